@@ -113,6 +113,10 @@ type ClusterController interface {
 	GetVolumeGetter(clusterID proto.ClusterID) (VolumeGetter, error)
 	// GetConfig get specified config of key from cluster manager
 	GetConfig(ctx context.Context, key string) (string, error)
+	// GetKV get value of key from cluster manager of the cluster
+	GetKV(ctx context.Context, clusterID proto.ClusterID, key string) ([]byte, error)
+	// SetKV set value of key to cluster manager of the cluster
+	SetKV(ctx context.Context, clusterID proto.ClusterID, key string, value []byte) error
 	// ChangeChooseAlg change alloc algorithm
 	ChangeChooseAlg(alg AlgChoose) error
 }
@@ -475,3 +479,31 @@ func (c *clusterControllerImpl) GetConfig(ctx context.Context, key string) (ret
 	}
 	return
 }
+
+func (c *clusterControllerImpl) getCluster(clusterID proto.ClusterID) (*cluster, error) {
+	allClusters := c.clusters.Load().(clusterMap)
+	if cluster, ok := allClusters[clusterID]; ok {
+		return cluster, nil
+	}
+	return nil, ErrNoSuchCluster
+}
+
+func (c *clusterControllerImpl) GetKV(ctx context.Context, clusterID proto.ClusterID, key string) ([]byte, error) {
+	cluster, err := c.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := cluster.client.GetKV(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Value, nil
+}
+
+func (c *clusterControllerImpl) SetKV(ctx context.Context, clusterID proto.ClusterID, key string, value []byte) error {
+	cluster, err := c.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	return cluster.client.SetKV(ctx, key, value)
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package access
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	"github.com/cubefs/cubefs/blobstore/common/kafka"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/util/defaulter"
+	"github.com/cubefs/cubefs/blobstore/util/log"
+)
+
+const (
+	defaultHeatBatchSize       = 100
+	defaultHeatFlushIntervalMs = 1000
+	defaultHeatQueueSize       = 10000
+)
+
+// HeatSampleConfig samples read events of blobs to kafka,
+// scheduler aggregates the events to volume heat, so that balancer and
+// cold migration can avoid moving hot volumes and choose cold ones first.
+type HeatSampleConfig struct {
+	// SampleRatio ratio of get requests to be sampled, in range (0, 1], 0 means disabled
+	SampleRatio     float64           `json:"sample_ratio"`
+	Kafka           kafka.ProducerCfg `json:"kafka"`
+	BatchSize       int               `json:"batch_size"`
+	FlushIntervalMs int               `json:"flush_interval_ms"`
+	// QueueSize events waiting to be sent, events are dropped if queue is full
+	QueueSize int `json:"queue_size"`
+}
+
+// heatSampler sends sampled read events asynchronously,
+// reading of users is never blocked by kafka.
+type heatSampler struct {
+	HeatSampleConfig
+	producer kafka.MsgProducer
+	queue    chan []byte
+	dropped  uint64
+}
+
+func newHeatSampler(cfg HeatSampleConfig, stopCh <-chan struct{}) (*heatSampler, error) {
+	producer, err := kafka.NewProducer(&cfg.Kafka)
+	if err != nil {
+		return nil, err
+	}
+	return startHeatSampler(cfg, producer, stopCh), nil
+}
+
+func startHeatSampler(cfg HeatSampleConfig, producer kafka.MsgProducer, stopCh <-chan struct{}) *heatSampler {
+	defaulter.LessOrEqual(&cfg.BatchSize, defaultHeatBatchSize)
+	defaulter.LessOrEqual(&cfg.FlushIntervalMs, defaultHeatFlushIntervalMs)
+	defaulter.LessOrEqual(&cfg.QueueSize, defaultHeatQueueSize)
+	s := &heatSampler{
+		HeatSampleConfig: cfg,
+		producer:         producer,
+		queue:            make(chan []byte, cfg.QueueSize),
+	}
+	go s.loop(stopCh)
+	return s
+}
+
+// sample emits one event of each blob in the read range of location.
+func (s *heatSampler) sample(loc *access.Location, readSize, offset uint64) {
+	if s == nil || readSize == 0 || rand.Float64() >= s.SampleRatio {
+		return
+	}
+
+	now := time.Now().Unix()
+	var blobOffset uint64
+	for _, blob := range loc.Spread() {
+		start, end := blobOffset, blobOffset+uint64(blob.Size)
+		blobOffset = end
+		if end <= offset {
+			continue
+		}
+		if start >= offset+readSize {
+			break
+		}
+		if start < offset {
+			start = offset
+		}
+		if end > offset+readSize {
+			end = offset + readSize
+		}
+
+		msg, err := json.Marshal(proto.BlobReadMsg{
+			ClusterID: loc.ClusterID,
+			Bid:       blob.Bid,
+			Vid:       blob.Vid,
+			Size:      end - start,
+			Time:      now,
+		})
+		if err != nil {
+			continue
+		}
+		select {
+		case s.queue <- msg:
+		default:
+			if atomic.AddUint64(&s.dropped, 1)%uint64(s.QueueSize) == 1 {
+				log.Warnf("heat sample queue is full, dropped %d events", atomic.LoadUint64(&s.dropped))
+			}
+		}
+	}
+}
+
+func (s *heatSampler) loop(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(time.Duration(s.FlushIntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.producer.SendMessages(s.Kafka.Topic, batch); err != nil {
+			log.Warnf("send %d heat sample events failed, err: %s", len(batch), err.Error())
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case msg := <-s.queue:
+			batch = append(batch, msg)
+			if len(batch) >= s.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-stopCh:
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+			}
+			flush()
+			return
+		}
+	}
+}
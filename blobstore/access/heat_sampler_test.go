@@ -0,0 +1,98 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package access
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+)
+
+type heatProducer struct {
+	mu    sync.Mutex
+	topic string
+	msgs  []proto.BlobReadMsg
+}
+
+func (p *heatProducer) SendMessage(topic string, msg []byte) error {
+	return p.SendMessages(topic, [][]byte{msg})
+}
+
+func (p *heatProducer) SendMessages(topic string, msgs [][]byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.topic = topic
+	for _, b := range msgs {
+		var msg proto.BlobReadMsg
+		if err := json.Unmarshal(b, &msg); err != nil {
+			return err
+		}
+		p.msgs = append(p.msgs, msg)
+	}
+	return nil
+}
+
+func (p *heatProducer) sent() []proto.BlobReadMsg {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]proto.BlobReadMsg(nil), p.msgs...)
+}
+
+func TestAccessHeatSampler(t *testing.T) {
+	var nilSampler *heatSampler
+	nilSampler.sample(&access.Location{}, 1, 0)
+
+	producer := &heatProducer{}
+	stopCh := make(chan struct{})
+	cfg := HeatSampleConfig{SampleRatio: 1, FlushIntervalMs: 10}
+	cfg.Kafka.Topic = "heat"
+	s := startHeatSampler(cfg, producer, stopCh)
+	require.Equal(t, defaultHeatBatchSize, s.BatchSize)
+
+	loc := &access.Location{
+		ClusterID: 1,
+		Size:      250,
+		BlobSize:  100,
+		Blobs: []access.SliceInfo{
+			{MinBid: 10, Vid: 1, Count: 2},
+			{MinBid: 20, Vid: 2, Count: 1},
+		},
+	}
+	s.sample(loc, 0, 0)
+	s.sample(loc, 120, 90)
+	require.Eventually(t, func() bool { return len(producer.sent()) == 3 }, time.Second, 10*time.Millisecond)
+	msgs := producer.sent()
+	require.Equal(t, "heat", producer.topic)
+	for idx, expected := range []proto.BlobReadMsg{
+		{ClusterID: 1, Bid: 10, Vid: 1, Size: 10},
+		{ClusterID: 1, Bid: 11, Vid: 1, Size: 100},
+		{ClusterID: 1, Bid: 20, Vid: 2, Size: 10},
+	} {
+		expected.Time = msgs[idx].Time
+		require.Equal(t, expected, msgs[idx])
+	}
+
+	s.SampleRatio = 0
+	s.sample(loc, 250, 0)
+	close(stopCh)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, producer.sent(), 3)
+}
@@ -0,0 +1,350 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package access
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/access/stream"
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/rpc/auth"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/util/defaulter"
+	"github.com/cubefs/cubefs/blobstore/util/errors"
+)
+
+const (
+	defaultHedgeMaxSize      uint64 = 4 << 20
+	defaultWatermarkReloadS         = 10
+	mirrorWatermarkKeyPrefix        = "access-mirror-watermark-"
+)
+
+// MirrorConfig read-only mirror cluster config.
+// The mirror cluster is replicated from primary cluster with the same volume and blob ids,
+// gets are served by mirror cluster when primary read failed or slower than ReadSLOMs.
+type MirrorConfig struct {
+	// primary cluster id => mirror cluster id
+	Clusters map[proto.ClusterID]proto.ClusterID `json:"clusters"`
+	// read from mirror if primary read not finished in time, 0 means only fallback when failed
+	ReadSLOMs int `json:"read_slo_ms"`
+	// reads not bigger than this size are buffered to race primary and mirror
+	HedgeMaxSize uint64 `json:"hedge_max_size"`
+	// mirror is considered stale if replication watermark not updated in time, 0 means never stale
+	MaxStalenessS int `json:"max_staleness_s"`
+	// interval of reloading watermarks from cluster manager of primary clusters
+	WatermarkReloadS int `json:"watermark_reload_s"`
+	// authentication of setting watermark, requests from loopback address need no authentication
+	Auth auth.Config `json:"auth"`
+
+	Stream stream.StreamConfig `json:"stream"`
+}
+
+// watermarkStore persists watermarks in cluster manager of primary clusters
+type watermarkStore interface {
+	GetKV(ctx context.Context, clusterID proto.ClusterID, key string) ([]byte, error)
+	SetKV(ctx context.Context, clusterID proto.ClusterID, key string, value []byte) error
+}
+
+func mirrorWatermarkKey(cid proto.ClusterID) string {
+	return fmt.Sprintf("%s%d", mirrorWatermarkKeyPrefix, cid)
+}
+
+// mirrorWatermark blobs not greater than Bid have been replicated to mirror cluster
+type mirrorWatermark struct {
+	ClusterID  proto.ClusterID `json:"cluster_id"`
+	Bid        proto.BlobID    `json:"bid"`
+	UpdateTime time.Time       `json:"update_time"`
+}
+
+type mirror struct {
+	MirrorConfig
+	handler stream.StreamHandler
+	store   watermarkStore
+	auth    *auth.AuthHandler
+
+	mu         sync.RWMutex
+	watermarks map[proto.ClusterID]mirrorWatermark // primary cluster id => watermark
+}
+
+func newMirror(cfg MirrorConfig, idc string, stopCh <-chan struct{}) (*mirror, error) {
+	if cfg.Stream.IDC == "" {
+		cfg.Stream.IDC = idc
+	}
+	defaulter.Equal(&cfg.HedgeMaxSize, defaultHedgeMaxSize)
+	defaulter.LessOrEqual(&cfg.WatermarkReloadS, defaultWatermarkReloadS)
+	h, err := stream.NewStreamHandler(&cfg.Stream, stopCh)
+	if err != nil {
+		return nil, err
+	}
+	return &mirror{
+		MirrorConfig: cfg,
+		handler:      h,
+		auth:         auth.NewAuthHandler(&cfg.Auth),
+		watermarks:   make(map[proto.ClusterID]mirrorWatermark),
+	}, nil
+}
+
+// run loads watermarks from store, and reloads them in background,
+// so watermarks set on any access are seen by others.
+func (m *mirror) run(store watermarkStore, stopCh <-chan struct{}) {
+	m.store = store
+	m.loadWatermarks()
+	go func() {
+		ticker := time.NewTicker(time.Duration(m.WatermarkReloadS) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.loadWatermarks()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+func (m *mirror) loadWatermarks() {
+	span, ctx := trace.StartSpanFromContext(context.Background(), "")
+	for cid := range m.Clusters {
+		value, err := m.store.GetKV(ctx, cid, mirrorWatermarkKey(cid))
+		if err != nil {
+			if rpc.DetectStatusCode(err) != http.StatusNotFound {
+				span.Warnf("load mirror watermark of cluster %d failed, err: %s", cid, err.Error())
+			}
+			continue
+		}
+		var wm mirrorWatermark
+		if err = json.Unmarshal(value, &wm); err != nil {
+			span.Warnf("invalid mirror watermark of cluster %d, err: %s", cid, err.Error())
+			continue
+		}
+		m.mu.Lock()
+		if old, ok := m.watermarks[cid]; !ok || old.UpdateTime.Before(wm.UpdateTime) {
+			m.watermarks[cid] = wm
+		}
+		m.mu.Unlock()
+	}
+}
+
+// setWatermark persists watermark into cluster manager of the primary cluster before it takes effect.
+func (m *mirror) setWatermark(ctx context.Context, cid proto.ClusterID, bid proto.BlobID) error {
+	if _, ok := m.Clusters[cid]; !ok {
+		return rpc.NewError(http.StatusBadRequest, "NoMirror", errors.Newf("cluster %d has no mirror", cid))
+	}
+	wm := mirrorWatermark{ClusterID: cid, Bid: bid, UpdateTime: time.Now()}
+	if m.store != nil {
+		value, err := json.Marshal(wm)
+		if err != nil {
+			return err
+		}
+		if err = m.store.SetKV(ctx, cid, mirrorWatermarkKey(cid), value); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	m.watermarks[cid] = wm
+	m.mu.Unlock()
+	return nil
+}
+
+// authorized returns true if the request is from loopback address or has valid token
+func (m *mirror) authorized(req *http.Request) bool {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+	return m.auth != nil && m.auth.Verify(req) == nil
+}
+
+func (m *mirror) getWatermarks() []mirrorWatermark {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	wms := make([]mirrorWatermark, 0, len(m.watermarks))
+	for _, wm := range m.watermarks {
+		wms = append(wms, wm)
+	}
+	return wms
+}
+
+// location returns location in mirror cluster,
+// false if no mirror or the blobs may not be replicated yet.
+func (m *mirror) location(loc *access.Location) (access.Location, bool) {
+	mirrorID, ok := m.Clusters[loc.ClusterID]
+	if !ok {
+		return access.Location{}, false
+	}
+
+	m.mu.RLock()
+	wm, ok := m.watermarks[loc.ClusterID]
+	m.mu.RUnlock()
+	if !ok {
+		return access.Location{}, false
+	}
+	if m.MaxStalenessS > 0 && time.Since(wm.UpdateTime) > time.Duration(m.MaxStalenessS)*time.Second {
+		return access.Location{}, false
+	}
+	for _, blob := range loc.Blobs {
+		if blob.Count > 0 && blob.MinBid+proto.BlobID(blob.Count-1) > wm.Bid {
+			return access.Location{}, false
+		}
+	}
+
+	mirrorLoc := loc.Copy()
+	mirrorLoc.ClusterID = mirrorID
+	return mirrorLoc, true
+}
+
+func (m *mirror) hedgeable(readSize uint64) bool {
+	return m.ReadSLOMs > 0 && readSize <= m.HedgeMaxSize
+}
+
+// hedgedGet reads from primary cluster, and reads from mirror cluster too
+// if primary failed or not finished in ReadSLOMs, returns the first success data.
+func (m *mirror) hedgedGet(ctx context.Context, primary stream.StreamHandler,
+	args *access.GetArgs, mirrorLoc access.Location) ([]byte, error) {
+	type result struct {
+		data     []byte
+		err      error
+		isMirror bool
+	}
+	ch := make(chan result, 2)
+	read := func(h stream.StreamHandler, loc access.Location, isMirror bool) {
+		buf := bytes.NewBuffer(make([]byte, 0, args.ReadSize))
+		transfer, err := h.Get(ctx, buf, loc, args.ReadSize, args.Offset)
+		if err == nil {
+			err = transfer()
+		}
+		ch <- result{data: buf.Bytes(), err: err, isMirror: isMirror}
+	}
+
+	pending := 1
+	mirrorStarted := false
+	startMirror := func() {
+		if !mirrorStarted {
+			mirrorStarted = true
+			pending++
+			go read(m.handler, mirrorLoc, true)
+		}
+	}
+
+	go read(primary, args.Location, false)
+	timer := time.NewTimer(time.Duration(m.ReadSLOMs) * time.Millisecond)
+	defer timer.Stop()
+
+	var firstErr error
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			stream.SteamReportDownload(args.Location.ClusterID, "Mirror", "slow")
+			startMirror()
+		case r := <-ch:
+			pending--
+			if r.err == nil {
+				if r.isMirror {
+					stream.SteamReportDownload(mirrorLoc.ClusterID, "Mirror", "-")
+				}
+				return r.data, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			startMirror()
+		}
+	}
+	stream.SteamReportDownload(mirrorLoc.ClusterID, "Mirror", "error")
+	return nil, firstErr
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// getWithMirror gets from primary cluster and falls back to mirror cluster.
+// Big reads are streamed and only fall back if nothing has been written to client.
+func (s *Service) getWithMirror(c *rpc.Context, w io.Writer, args *access.GetArgs, mirrorLoc access.Location) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContextSafe(ctx)
+
+	if s.mirror.hedgeable(args.ReadSize) {
+		data, err := s.mirror.hedgedGet(ctx, s.streamHandler, args, mirrorLoc)
+		if err != nil {
+			span.Error("stream get from primary and mirror failed", errors.Detail(err))
+			c.RespondError(httpError(err))
+			return
+		}
+		respondGetHeader(c, args)
+		if _, err = w.Write(data); err != nil {
+			span.Error("stream get write failed", errors.Detail(err))
+			return
+		}
+		span.Info("done /get request")
+		return
+	}
+
+	fromMirror := false
+	cw := &countWriter{w: w}
+	transfer, err := s.streamHandler.Get(ctx, cw, args.Location, args.ReadSize, args.Offset)
+	if err != nil {
+		span.Warn("stream get prepare failed, read from mirror", errors.Detail(err))
+		if transfer, err = s.mirror.handler.Get(ctx, w, mirrorLoc, args.ReadSize, args.Offset); err != nil {
+			span.Error("stream get mirror prepare failed", errors.Detail(err))
+			c.RespondError(httpError(err))
+			return
+		}
+		fromMirror = true
+	}
+
+	respondGetHeader(c, args)
+	err = transfer()
+	if err != nil && !fromMirror && cw.n == 0 {
+		span.Warn("stream get transfer failed, read from mirror", errors.Detail(err))
+		var mirrorTransfer func() error
+		if mirrorTransfer, err = s.mirror.handler.Get(ctx, w, mirrorLoc, args.ReadSize, args.Offset); err == nil {
+			err = mirrorTransfer()
+		}
+		fromMirror = true
+	}
+	if fromMirror {
+		reason := "-"
+		if err != nil {
+			reason = "error"
+		}
+		stream.SteamReportDownload(mirrorLoc.ClusterID, "Mirror", reason)
+	}
+	if err != nil {
+		stream.SteamReportDownload(args.Location.ClusterID, "StatusOKError", "-")
+		span.Error("stream get transfer failed", errors.Detail(err))
+		return
+	}
+	span.Info("done /get request")
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc/auth"
+	"github.com/cubefs/cubefs/blobstore/testing/mocks"
+)
+
+type memWatermarkStore struct {
+	mu  sync.Mutex
+	kvs map[string][]byte
+}
+
+func (s *memWatermarkStore) GetKV(ctx context.Context, cid proto.ClusterID, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.kvs[fmt.Sprintf("%d/%s", cid, key)]
+	if !ok {
+		return nil, errcode.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *memWatermarkStore) SetKV(ctx context.Context, cid proto.ClusterID, key string, value []byte) error {
+	s.mu.Lock()
+	s.kvs[fmt.Sprintf("%d/%s", cid, key)] = value
+	s.mu.Unlock()
+	return nil
+}
+
+func newMockMirror(t *testing.T) *mirror {
+	return &mirror{
+		store: &memWatermarkStore{kvs: make(map[string][]byte)},
+		MirrorConfig: MirrorConfig{
+			Clusters:      map[proto.ClusterID]proto.ClusterID{1: 2},
+			ReadSLOMs:     50,
+			HedgeMaxSize:  defaultHedgeMaxSize,
+			MaxStalenessS: 60,
+		},
+		handler:    mocks.NewMockStreamHandler(gomock.NewController(t)),
+		watermarks: make(map[proto.ClusterID]mirrorWatermark),
+	}
+}
+
+func mockGetter(data string, delay time.Duration, err error) func(context.Context, io.Writer,
+	access.Location, uint64, uint64) (func() error, error) {
+	return func(ctx context.Context, w io.Writer, loc access.Location, readSize, offset uint64) (func() error, error) {
+		return func() error {
+			time.Sleep(delay)
+			if err != nil {
+				return err
+			}
+			_, e := w.Write([]byte(data))
+			return e
+		}, nil
+	}
+}
+
+func TestAccessMirrorLocation(t *testing.T) {
+	ctx := context.Background()
+	m := newMockMirror(t)
+	loc := location.Copy()
+
+	_, ok := m.location(&loc)
+	require.False(t, ok)
+	require.Error(t, m.setWatermark(ctx, proto.ClusterID(3), 1000))
+
+	// blobs not replicated yet
+	require.NoError(t, m.setWatermark(ctx, loc.ClusterID, 111))
+	_, ok = m.location(&loc)
+	require.False(t, ok)
+
+	require.NoError(t, m.setWatermark(ctx, loc.ClusterID, 121))
+	mirrorLoc, ok := m.location(&loc)
+	require.True(t, ok)
+	require.Equal(t, proto.ClusterID(2), mirrorLoc.ClusterID)
+	require.Equal(t, loc.Blobs, mirrorLoc.Blobs)
+	require.Equal(t, proto.ClusterID(1), loc.ClusterID)
+	require.Len(t, m.getWatermarks(), 1)
+
+	// watermark is stale
+	m.watermarks[loc.ClusterID] = mirrorWatermark{Bid: 1000, UpdateTime: time.Now().Add(-time.Hour)}
+	_, ok = m.location(&loc)
+	require.False(t, ok)
+
+	// no mirror cluster
+	loc.ClusterID = 3
+	_, ok = m.location(&loc)
+	require.False(t, ok)
+}
+
+func TestAccessMirrorWatermarkStore(t *testing.T) {
+	ctx := context.Background()
+	m := newMockMirror(t)
+	require.NoError(t, m.setWatermark(ctx, 1, 121))
+
+	// watermark is loaded by another access
+	other := newMockMirror(t)
+	other.WatermarkReloadS = 1
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	other.run(m.store, stopCh)
+	wms := other.getWatermarks()
+	require.Len(t, wms, 1)
+	require.Equal(t, proto.BlobID(121), wms[0].Bid)
+	loc := location.Copy()
+	_, ok := other.location(&loc)
+	require.True(t, ok)
+
+	require.NoError(t, m.setWatermark(ctx, 1, 200))
+	require.Eventually(t, func() bool {
+		wms := other.getWatermarks()
+		return len(wms) == 1 && wms[0].Bid == 200
+	}, 3*time.Second, 100*time.Millisecond)
+}
+
+func TestAccessMirrorAuthorized(t *testing.T) {
+	m := newMockMirror(t)
+	req := httptest.NewRequest(http.MethodPost, "/access/mirror/watermark/1/100", nil)
+	require.False(t, m.authorized(req))
+	req.RemoteAddr = "127.0.0.1:9500"
+	require.True(t, m.authorized(req))
+
+	authCfg := auth.Config{EnableAuth: true, Secret: "testSecret"}
+	m.auth = auth.NewAuthHandler(&authCfg)
+	req = httptest.NewRequest(http.MethodPost, "/access/mirror/watermark/1/100", nil)
+	require.False(t, m.authorized(req))
+	// request signed by auth transport
+	var signed *http.Request
+	tr := auth.NewAuthTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		signed = r
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}), &authCfg)
+	_, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	require.True(t, m.authorized(signed))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestAccessMirrorHedgedGet(t *testing.T) {
+	ctx := context.Background()
+	m := newMockMirror(t)
+	primary := mocks.NewMockStreamHandler(gomock.NewController(t))
+	args := &access.GetArgs{Location: location.Copy(), ReadSize: 4}
+	mirrorLoc := args.Location.Copy()
+	mirrorLoc.ClusterID = 2
+
+	primaryGet := func() *gomock.Call {
+		return primary.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+	}
+	mirrorGet := func() *gomock.Call {
+		return m.handler.(*mocks.MockStreamHandler).EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+	}
+
+	require.True(t, m.hedgeable(args.ReadSize))
+	require.False(t, m.hedgeable(defaultHedgeMaxSize+1))
+
+	// primary is fast
+	primaryGet().DoAndReturn(mockGetter("prim", 0, nil))
+	data, err := m.hedgedGet(ctx, primary, args, mirrorLoc)
+	require.NoError(t, err)
+	require.Equal(t, "prim", string(data))
+
+	// primary failed
+	primaryGet().DoAndReturn(mockGetter("", 0, errors.New("mock primary")))
+	mirrorGet().DoAndReturn(mockGetter("mirr", 0, nil))
+	data, err = m.hedgedGet(ctx, primary, args, mirrorLoc)
+	require.NoError(t, err)
+	require.Equal(t, "mirr", string(data))
+
+	// primary is slower than slo
+	primaryGet().DoAndReturn(mockGetter("prim", 500*time.Millisecond, nil))
+	mirrorGet().DoAndReturn(mockGetter("mirr", 0, nil))
+	data, err = m.hedgedGet(ctx, primary, args, mirrorLoc)
+	require.NoError(t, err)
+	require.Equal(t, "mirr", string(data))
+
+	// mirror failed, wait for slow primary
+	primaryGet().DoAndReturn(mockGetter("prim", 200*time.Millisecond, nil))
+	mirrorGet().DoAndReturn(mockGetter("", 0, errors.New("mock mirror")))
+	data, err = m.hedgedGet(ctx, primary, args, mirrorLoc)
+	require.NoError(t, err)
+	require.Equal(t, "prim", string(data))
+
+	// both failed
+	primaryGet().DoAndReturn(mockGetter("", 0, errors.New("mock primary")))
+	mirrorGet().DoAndReturn(mockGetter("", 0, errors.New("mock mirror")))
+	_, err = m.hedgedGet(ctx, primary, args, mirrorLoc)
+	require.Error(t, err)
+}
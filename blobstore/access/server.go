@@ -82,12 +82,16 @@ type Config struct {
 	ServiceRegister consul.Config       `json:"service_register"`
 	Stream          stream.StreamConfig `json:"stream"`
 	Limit           stream.LimitConfig  `json:"limit"`
+	Mirror          MirrorConfig        `json:"mirror"`
+	HeatSample      HeatSampleConfig    `json:"heat_sample"`
 }
 
 // Service rpc service
 type Service struct {
 	config        Config
 	streamHandler stream.StreamHandler
+	mirror        *mirror
+	heatSampler   *heatSampler
 	limiter       stream.Limiter
 	closer        closer.Closer
 }
@@ -98,15 +102,40 @@ func New(cfg Config) *Service {
 	initWithRegionMagic(cfg.Stream.ClusterConfig.RegionMagic)
 
 	cl := closer.New()
+	// new mirror handler firstly, hystrix commands are configured by the primary at last
+	var m *mirror
+	if len(cfg.Mirror.Clusters) > 0 {
+		var err error
+		if m, err = newMirror(cfg.Mirror, cfg.Stream.IDC, cl.Done()); err != nil {
+			log.Fatalf("new mirror stream handler failed, err: %+v", err)
+		}
+	}
 	h, err := stream.NewStreamHandler(&cfg.Stream, cl.Done())
 	if err != nil {
 		log.Fatalf("new stream handler failed, err: %+v", err)
 	}
+	if m != nil {
+		if sa, ok := h.Admin().(*stream.StreamAdmin); ok {
+			m.run(sa.Controller, cl.Done())
+		}
+	}
+	lim, err := stream.NewLimiter(cfg.Limit)
+	if err != nil {
+		log.Fatalf("new limiter failed, err: %+v", err)
+	}
+	var hs *heatSampler
+	if cfg.HeatSample.SampleRatio > 0 {
+		if hs, err = newHeatSampler(cfg.HeatSample, cl.Done()); err != nil {
+			log.Fatalf("new heat sampler failed, err: %+v", err)
+		}
+	}
 
 	return &Service{
 		config:        cfg,
 		streamHandler: h,
-		limiter:       stream.NewLimiter(cfg.Limit),
+		mirror:        m,
+		heatSampler:   hs,
+		limiter:       lim,
 		closer:        cl,
 	}
 }
@@ -114,6 +143,22 @@ func New(cfg Config) *Service {
 // Close close server
 func (s *Service) Close() {
 	s.closer.Close()
+	s.limiter.Close()
+}
+
+// HealthCheck returns error if the service is closed or knows no cluster
+func (s *Service) HealthCheck() error {
+	select {
+	case <-s.closer.Done():
+		return errors.New("service is closed")
+	default:
+	}
+	if sa, ok := s.streamHandler.Admin().(*stream.StreamAdmin); ok && sa.Controller != nil {
+		if len(sa.Controller.All()) == 0 {
+			return errors.New("no available cluster")
+		}
+	}
+	return nil
 }
 
 // RegisterService register service to rpc
@@ -194,6 +239,35 @@ func (s *Service) RegisterAdminHandler() {
 
 		c.RespondStatus(http.StatusServiceUnavailable)
 	}, rpc.OptArgsURI())
+
+	if s.mirror == nil {
+		return
+	}
+	profile.HandleFunc(http.MethodGet, "/access/mirror/watermark", func(c *rpc.Context) {
+		c.RespondJSON(s.mirror.getWatermarks())
+	})
+	// replication service reports the max replicated bid of primary cluster
+	profile.HandleFunc(http.MethodPost, "/access/mirror/watermark/:cid/:bid", func(c *rpc.Context) {
+		if !s.mirror.authorized(c.Request) {
+			c.RespondStatus(http.StatusForbidden)
+			return
+		}
+		cid, err := strconv.ParseUint(c.Param.ByName("cid"), 10, 32)
+		if err != nil {
+			c.RespondWith(http.StatusBadRequest, "", []byte(err.Error()))
+			return
+		}
+		bid, err := strconv.ParseUint(c.Param.ByName("bid"), 10, 64)
+		if err != nil {
+			c.RespondWith(http.StatusBadRequest, "", []byte(err.Error()))
+			return
+		}
+		if err = s.mirror.setWatermark(c.Request.Context(), proto.ClusterID(cid), proto.BlobID(bid)); err != nil {
+			c.RespondError(err)
+			return
+		}
+		c.Respond()
+	}, rpc.OptArgsURI())
 }
 
 // Limit rps controller
@@ -253,7 +327,11 @@ func (s *Service) Put(c *rpc.Context) {
 	}
 
 	rc := s.limiter.Reader(ctx, c.Request.Body)
-	loc, err := s.streamHandler.Put(ctx, rc, args.Size, hasherMap)
+	put := s.streamHandler.Put
+	if args.Inline {
+		put = s.streamHandler.PutInline
+	}
+	loc, err := put(ctx, rc, args.Size, hasherMap)
 	if err != nil {
 		span.Error("stream put failed", errors.Detail(err))
 		c.RespondError(httpError(err))
@@ -388,8 +466,15 @@ func (s *Service) Get(c *rpc.Context) {
 		return
 	}
 
-	w := c.Writer
-	writer := s.limiter.Writer(ctx, w)
+	s.heatSampler.sample(&args.Location, args.ReadSize, args.Offset)
+	writer := s.limiter.Writer(ctx, c.Writer)
+	if s.mirror != nil {
+		if mirrorLoc, ok := s.mirror.location(&args.Location); ok {
+			s.getWithMirror(c, writer, args, mirrorLoc)
+			return
+		}
+	}
+
 	transfer, err := s.streamHandler.Get(ctx, writer, args.Location, args.ReadSize, args.Offset)
 	if err != nil {
 		span.Error("stream get prepare failed", errors.Detail(err))
@@ -397,6 +482,19 @@ func (s *Service) Get(c *rpc.Context) {
 		return
 	}
 
+	respondGetHeader(c, args)
+	err = transfer()
+	if err != nil {
+		stream.SteamReportDownload(args.Location.ClusterID, "StatusOKError", "-")
+		span.Error("stream get transfer failed", errors.Detail(err))
+		return
+	}
+	span.Info("done /get request")
+}
+
+// respondGetHeader responds status and flushes headers to client firstly
+func respondGetHeader(c *rpc.Context, args *access.GetArgs) {
+	w := c.Writer
 	w.Header().Set(rpc.HeaderContentType, rpc.MIMEStream)
 	w.Header().Set(rpc.HeaderContentLength, strconv.FormatInt(int64(args.ReadSize), 10))
 	if args.ReadSize > 0 && args.ReadSize != args.Location.Size {
@@ -406,17 +504,7 @@ func (s *Service) Get(c *rpc.Context) {
 	} else {
 		c.RespondStatus(http.StatusOK)
 	}
-
-	// flush headers to client firstly
 	c.Flush()
-
-	err = transfer()
-	if err != nil {
-		stream.SteamReportDownload(args.Location.ClusterID, "StatusOKError", "-")
-		span.Error("stream get transfer failed", errors.Detail(err))
-		return
-	}
-	span.Info("done /get request")
 }
 
 // Delete  all blobs in this location
@@ -464,6 +552,10 @@ func (s *Service) Delete(c *rpc.Context) {
 			err = errcode.ErrIllegalArguments
 			return
 		}
+		// nothing to delete of inline location
+		if loc.IsInline() {
+			continue
+		}
 		clusterBlobsN[loc.ClusterID] += len(loc.Blobs)
 	}
 
@@ -488,6 +580,9 @@ func (s *Service) Delete(c *rpc.Context) {
 		merged[id] = make([]access.SliceInfo, 0, n)
 	}
 	for _, loc := range args.Locations {
+		if loc.IsInline() {
+			continue
+		}
 		merged[loc.ClusterID] = append(merged[loc.ClusterID], loc.Blobs...)
 	}
 
@@ -500,7 +595,7 @@ func (s *Service) Delete(c *rpc.Context) {
 				resp.FailedLocations = make([]access.Location, 0, len(args.Locations))
 			}
 			for _, loc := range args.Locations {
-				if loc.ClusterID == id {
+				if loc.ClusterID == id && !loc.IsInline() {
 					resp.FailedLocations = append(resp.FailedLocations, loc)
 				}
 			}
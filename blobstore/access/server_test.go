@@ -104,6 +104,16 @@ func newService() *Service {
 			return &loc, nil
 		})
 
+	s.EXPECT().PutInline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, rc io.Reader, size int64, hasherMap access.HasherMap) (*access.Location, error) {
+			loc := location.Copy()
+			loc.Size = uint64(size)
+			loc.Blobs = nil
+			loc.Inline = []byte("inline")
+			stream.LocationCrcFill(&loc)
+			return &loc, nil
+		})
+
 	s.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
 		func(ctx context.Context, w io.Writer, location access.Location, readSize, offset uint64) (func() error, error) {
 			if readSize < 1024 {
@@ -121,15 +131,16 @@ func newService() *Service {
 			return nil
 		})
 
+	limiter, _ := stream.NewLimiter(stream.LimitConfig{
+		NameRps: map[string]int{
+			limitNameAlloc: 2,
+		},
+		ReaderMBps: 0,
+		WriterMBps: 0,
+	})
 	return &Service{
 		streamHandler: s,
-		limiter: stream.NewLimiter(stream.LimitConfig{
-			NameRps: map[string]int{
-				limitNameAlloc: 2,
-			},
-			ReaderMBps: 0,
-			WriterMBps: 0,
-		}),
+		limiter:       limiter,
 	}
 }
 
@@ -279,6 +290,15 @@ func TestAccessServicePut(t *testing.T) {
 			err := cli.DoWith(ctx, req, resp, rpc.WithCrcEncode())
 			require.NoError(t, err)
 			require.Equal(t, uint64(1024), resp.Location.Size)
+			require.False(t, resp.Location.IsInline())
+		}
+		{
+			args.Body = bytes.NewReader(make([]byte, 1024))
+			req, _ := http.NewRequest(method, url(1024, args.Hashes)+"&inline=true", args.Body)
+			resp := &access.PutResp{}
+			err := cli.DoWith(ctx, req, resp, rpc.WithCrcEncode())
+			require.NoError(t, err)
+			require.True(t, resp.Location.IsInline())
 		}
 	}
 }
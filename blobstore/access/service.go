@@ -28,10 +28,11 @@ var (
 
 func init() {
 	mod := &cmd.Module{
-		Name:       "ACCESS",
-		InitConfig: initConfig,
-		SetUp:      setUp,
-		TearDown:   tearDown,
+		Name:        "ACCESS",
+		InitConfig:  initConfig,
+		SetUp:       setUp,
+		TearDown:    tearDown,
+		HealthCheck: healthCheck,
 	}
 	cmd.RegisterGracefulModule(mod)
 }
@@ -56,6 +57,10 @@ func tearDown() {
 	gService.Close()
 }
 
+func healthCheck() error {
+	return gService.HealthCheck()
+}
+
 // NewHandler returns app server handler
 func NewHandler(service *Service) *rpc.Router {
 	rpc.RegisterArgsParser(&access.PutArgs{}, "json")
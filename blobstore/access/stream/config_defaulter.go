@@ -16,6 +16,7 @@ package stream
 
 const (
 	defaultMaxBlobSize uint32 = 1 << 22 // 4MB
+	maxInlineSize      int    = 1 << 16 // 64KB
 
 	defaultDiskPunishIntervalS    int = 60
 	defaultServicePunishIntervalS int = 60
@@ -95,6 +95,21 @@ func (mr *MockClusterControllerMockRecorder) GetConfig(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockClusterController)(nil).GetConfig), arg0, arg1)
 }
 
+// GetKV mocks base method.
+func (m *MockClusterController) GetKV(arg0 context.Context, arg1 proto.ClusterID, arg2 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKV", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKV indicates an expected call of GetKV.
+func (mr *MockClusterControllerMockRecorder) GetKV(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKV", reflect.TypeOf((*MockClusterController)(nil).GetKV), arg0, arg1, arg2)
+}
+
 // GetServiceController mocks base method.
 func (m *MockClusterController) GetServiceController(arg0 proto.ClusterID) (controller.ServiceController, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +154,20 @@ func (mr *MockClusterControllerMockRecorder) Region() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Region", reflect.TypeOf((*MockClusterController)(nil).Region))
 }
 
+// SetKV mocks base method.
+func (m *MockClusterController) SetKV(arg0 context.Context, arg1 proto.ClusterID, arg2 string, arg3 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKV", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKV indicates an expected call of SetKV.
+func (mr *MockClusterControllerMockRecorder) SetKV(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKV", reflect.TypeOf((*MockClusterController)(nil).SetKV), arg0, arg1, arg2, arg3)
+}
+
 // MockServiceController is a mock of ServiceController interface.
 type MockServiceController struct {
 	ctrl     *gomock.Controller
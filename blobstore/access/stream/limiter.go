@@ -17,11 +17,15 @@ package stream
 import (
 	"context"
 	"io"
+	"os"
 	"time"
 
 	"golang.org/x/time/rate"
 
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/util/errors"
 	"github.com/cubefs/cubefs/blobstore/util/limit"
 	"github.com/cubefs/cubefs/blobstore/util/limit/count"
 )
@@ -46,6 +50,8 @@ type Limiter interface {
 	// Status returns running status
 	// TODO: calculate rate limit wait concurrent
 	Status() Status
+	// Close stops the cluster quotas
+	Close()
 }
 
 // LimitConfig configuration of limiter
@@ -53,6 +59,13 @@ type LimitConfig struct {
 	NameRps    map[string]int `json:"name_rps"`    // request with name n/s
 	ReaderMBps int            `json:"reader_mbps"` // read with MB/s
 	WriterMBps int            `json:"writer_mbps"` // write with MB/s
+
+	// ReaderQuota and WriterQuota are bandwidth quotas with bytes per second,
+	// shared by all access with the same quota name in kv of QuotaClusterMgr,
+	// or limited in this access if name is empty.
+	ReaderQuota     ratelimit.ClusterConfig `json:"reader_quota"`
+	WriterQuota     ratelimit.ClusterConfig `json:"writer_quota"`
+	QuotaClusterMgr cmapi.Config            `json:"quota_clustermgr"`
 }
 
 // Status running status
@@ -154,14 +167,16 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 }
 
 type limiter struct {
-	config     LimitConfig
-	limiters   map[string]limit.Limiter
-	rateReader *rate.Limiter
-	rateWriter *rate.Limiter
+	config      LimitConfig
+	limiters    map[string]limit.Limiter
+	rateReader  *rate.Limiter
+	rateWriter  *rate.Limiter
+	quotaReader ratelimit.Limiter
+	quotaWriter ratelimit.Limiter
 }
 
-// NewLimiter returns a Limiter
-func NewLimiter(cfg LimitConfig) Limiter {
+// NewLimiter returns a Limiter, or error if the cluster quota is invalid
+func NewLimiter(cfg LimitConfig) (Limiter, error) {
 	mb := 1 << 20
 	lim := &limiter{
 		config:   cfg,
@@ -181,7 +196,33 @@ func NewLimiter(cfg LimitConfig) Limiter {
 		lim.rateWriter = rate.NewLimiter(rate.Limit(cfg.WriterMBps*mb), 2*cfg.WriterMBps*mb)
 	}
 
-	return lim
+	var err error
+	if lim.quotaReader, err = newQuota(cfg.ReaderQuota, &cfg.QuotaClusterMgr); err != nil {
+		return nil, err
+	}
+	if lim.quotaWriter, err = newQuota(cfg.WriterQuota, &cfg.QuotaClusterMgr); err != nil {
+		lim.Close()
+		return nil, err
+	}
+	return lim, nil
+}
+
+// newQuota returns nil if no quota, the total of cluster quota may be set in kv at runtime
+func newQuota(cfg ratelimit.ClusterConfig, cmConfig *cmapi.Config) (ratelimit.Limiter, error) {
+	if cfg.Name == "" {
+		if cfg.Rate <= 0 {
+			return nil, nil
+		}
+		return ratelimit.New(cfg.Config), nil
+	}
+	if cfg.Host == "" {
+		cfg.Host, _ = os.Hostname()
+	}
+	quota, err := ratelimit.NewClusterLimiter(cfg, cmapi.New(cmConfig))
+	if err != nil {
+		return nil, errors.Info(err, "new cluster quota", cfg.Name)
+	}
+	return quota, nil
 }
 
 func (lim *limiter) Acquire(name string) error {
@@ -198,6 +239,9 @@ func (lim *limiter) Release(name string) {
 }
 
 func (lim *limiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	if lim.quotaReader != nil {
+		r = ratelimit.Reader(ctx, lim.quotaReader, r)
+	}
 	if lim.rateReader != nil {
 		return &Reader{
 			ctx:        ctx,
@@ -209,6 +253,9 @@ func (lim *limiter) Reader(ctx context.Context, r io.Reader) io.Reader {
 }
 
 func (lim *limiter) Writer(ctx context.Context, w io.Writer) io.Writer {
+	if lim.quotaWriter != nil {
+		w = ratelimit.Writer(ctx, lim.quotaWriter, w)
+	}
 	if lim.rateWriter != nil {
 		return &Writer{
 			ctx:        ctx,
@@ -235,6 +282,14 @@ func (lim *limiter) Status() Status {
 	return st
 }
 
+func (lim *limiter) Close() {
+	for _, quota := range []ratelimit.Limiter{lim.quotaReader, lim.quotaWriter} {
+		if cl, ok := quota.(*ratelimit.ClusterLimiter); ok {
+			cl.Close()
+		}
+	}
+}
+
 // rateWait get duration of waiting half of limit
 func rateWait(r *rate.Limiter) int {
 	if r == nil {
@@ -15,6 +15,7 @@
 package stream
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"sync"
@@ -24,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
 
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 	"github.com/cubefs/cubefs/blobstore/util/limit"
 )
@@ -135,7 +137,8 @@ func TestAccessLimiterBase(t *testing.T) {
 		ReaderMBps: 1,
 		WriterMBps: 1,
 	}
-	l := NewLimiter(cfg)
+	l, err := NewLimiter(cfg)
+	require.NoError(t, err)
 
 	{
 		for range [100]struct{}{} {
@@ -229,14 +232,15 @@ func TestAccessLimiterBase(t *testing.T) {
 }
 
 func TestAccessLimiterNoop(t *testing.T) {
-	l := NewLimiter(LimitConfig{
+	l, err := NewLimiter(LimitConfig{
 		NameRps:    nil,
 		ReaderMBps: 0,
 		WriterMBps: 0,
 	})
+	require.NoError(t, err)
 
 	name := "noop"
-	err := l.Acquire(name)
+	err = l.Acquire(name)
 	require.NoError(t, err)
 	err = l.Acquire(name)
 	require.NoError(t, err)
@@ -264,11 +268,12 @@ func TestAccessLimiterNoop(t *testing.T) {
 
 func TestAccessLimiterStatus(t *testing.T) {
 	{
-		l := NewLimiter(LimitConfig{
+		l, err := NewLimiter(LimitConfig{
 			NameRps:    nil,
 			ReaderMBps: 0,
 			WriterMBps: 0,
 		})
+		require.NoError(t, err)
 		for range [100]struct{}{} {
 			l.Acquire("foo")
 		}
@@ -277,11 +282,12 @@ func TestAccessLimiterStatus(t *testing.T) {
 	{
 		ctx := ctxWithName("TestAccessLimiterStatus")()
 		name := "foo"
-		l := NewLimiter(LimitConfig{
+		l, err := NewLimiter(LimitConfig{
 			NameRps:    map[string]int{name: 10},
 			ReaderMBps: 4,
 			WriterMBps: 10,
 		})
+		require.NoError(t, err)
 
 		ch := make(chan struct{})
 		for range [7]struct{}{} {
@@ -324,3 +330,32 @@ func TestAccessLimiterStatus(t *testing.T) {
 		wg.Wait()
 	}
 }
+
+func TestAccessLimiterQuota(t *testing.T) {
+	l, err := NewLimiter(LimitConfig{
+		ReaderQuota: ratelimit.ClusterConfig{Config: ratelimit.Config{Rate: 1 << 20, Burst: 1 << 10}},
+		WriterQuota: ratelimit.ClusterConfig{Config: ratelimit.Config{Rate: 1 << 20, Burst: 1 << 10}},
+	})
+	require.NoError(t, err)
+	defer l.Close()
+
+	ctx := ctxWithName("TestAccessLimiterQuota")()
+	start := time.Now()
+	n, err := io.Copy(l.Writer(ctx, &limitWriter{}), l.Reader(ctx, bytes.NewReader(make([]byte, 50<<10))))
+	require.NoError(t, err)
+	require.Equal(t, int64(50<<10), n)
+	// both reader and writer wait for quota
+	require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	// no quota
+	l, err = NewLimiter(LimitConfig{})
+	require.NoError(t, err)
+	r := &limitReader{size: 1}
+	require.True(t, l.Reader(ctx, r) == io.Reader(r))
+
+	// invalid name of cluster quota
+	_, err = NewLimiter(LimitConfig{
+		ReaderQuota: ratelimit.ClusterConfig{Name: "access-reader", Config: ratelimit.Config{Rate: 1 << 20}},
+	})
+	require.ErrorIs(t, err, ratelimit.ErrInvalidName)
+}
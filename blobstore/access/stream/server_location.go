@@ -15,8 +15,16 @@
 package stream
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"hash/crc32"
+	"io"
 	"sync"
 	"time"
 
@@ -73,7 +81,11 @@ func initLocationSecret(b []byte) {
 func calcCrc(loc *access.Location) (uint32, error) {
 	crcWriter := crc32.New(_crcTable)
 
-	buf := bytespool.Alloc(1024)
+	size := 1024
+	if loc != nil && loc.IsInline() {
+		size += binary.MaxVarintLen64 + len(loc.Inline)
+	}
+	buf := bytespool.Alloc(size)
 	defer bytespool.Free(buf)
 
 	n := loc.Encode2(buf)
@@ -123,6 +135,14 @@ func signCrc(loc *access.Location, locs []access.Location) error {
 			return fmt.Errorf("not equal in crc %d", l.Crc)
 		}
 
+		// inline location can not be merged with others
+		if l.IsInline() || loc.IsInline() {
+			if len(locs) != 1 || len(loc.Blobs) > 0 || loc.Size != l.Size ||
+				!bytes.Equal(loc.Inline, l.Inline) {
+				return fmt.Errorf("not equal in inline")
+			}
+		}
+
 		// assert
 		if l.ClusterID != first.ClusterID ||
 			l.CodeMode != first.CodeMode ||
@@ -149,6 +169,52 @@ func signCrc(loc *access.Location, locs []access.Location) error {
 	return fillCrc(loc)
 }
 
+// inlineCipher returns aes-gcm aead with the key derived from the location secret
+func inlineCipher() (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, _crcMagicKey[:])
+	mac.Write([]byte("location-inline"))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// inlineAdditional binds the inline data to size of the location
+func inlineAdditional(size uint64) []byte {
+	ad := make([]byte, 8)
+	binary.BigEndian.PutUint64(ad, size)
+	return ad
+}
+
+// encryptInline seals inline data of the file with aes-gcm,
+// and the random nonce is ahead of the sealed data
+func encryptInline(data []byte, size uint64) ([]byte, error) {
+	aead, err := inlineCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, inlineAdditional(size)), nil
+}
+
+// decryptInline returns data of the inline sealed by encryptInline,
+// it fails if the inline or size has been modified
+func decryptInline(inline []byte, size uint64) ([]byte, error) {
+	aead, err := inlineCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(inline) < aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("bytes inline %d", len(inline))
+	}
+	nonce := inline[:aead.NonceSize()]
+	return aead.Open(nil, nonce, inline[aead.NonceSize():], inlineAdditional(size))
+}
+
 // genTokens generate tokens
 //  1. Returns 0 token if has no blobs.
 //  2. Returns 1 token if file size less than blobsize.
@@ -71,6 +71,11 @@ type StreamHandler interface {
 	//     optional: hasher map to calculate hash.Hash
 	Put(ctx context.Context, rc io.Reader, size int64, hasherMap access.HasherMap) (*access.Location, error)
 
+	// PutInline put one object, which is stored in location if not larger than inline size
+	//     required: size, file size
+	//     optional: hasher map to calculate hash.Hash
+	PutInline(ctx context.Context, rc io.Reader, size int64, hasherMap access.HasherMap) (*access.Location, error)
+
 	// Get read file
 	//     required: location, readSize
 	//     optional: offset(default is 0)
@@ -122,6 +127,9 @@ type StreamConfig struct {
 	EncoderConcurrency         int    `json:"encoder_concurrency"`
 	MinReadShardsX             int    `json:"min_read_shards_x"`
 	ShardCrcDisabled           bool   `json:"shard_crc_disabled"`
+	// InlineSize files not larger than it are stored encrypted in location instead of blobs
+	// if the put request asks for inline, 0 means disabled
+	InlineSize int `json:"inline_size"`
 
 	MemPoolSizeClasses map[int]int `json:"mem_pool_size_classes"`
 
@@ -203,6 +211,9 @@ func confCheck(cfg *StreamConfig) error {
 	}
 	defaulter.LessOrEqual(&cfg.EncoderConcurrency, defaultEncoderConcurrency)
 	defaulter.LessOrEqual(&cfg.MinReadShardsX, defaultMinReadShardsX)
+	if cfg.InlineSize < 0 || cfg.InlineSize > maxInlineSize {
+		return errors.Newf("invalid inline size(%d), should be in [0, %d]", cfg.InlineSize, maxInlineSize)
+	}
 
 	defaulter.LessOrEqual(&cfg.ClusterConfig.CMClientConfig.Config.ClientTimeoutMs, defaultTimeoutClusterMgr)
 	defaulter.LessOrEqual(&cfg.BlobnodeConfig.ClientTimeoutMs, defaultTimeoutBlobnode)
@@ -309,6 +320,9 @@ func NewStreamHandler(cfg *StreamConfig, stopCh <-chan struct{}) (h StreamHandle
 func (h *Handler) Delete(ctx context.Context, location *access.Location) error {
 	span := trace.SpanFromContextSafe(ctx)
 	span.Debugf("to delete %+v", location)
+	if location.IsInline() {
+		return nil
+	}
 	return h.clearGarbage(ctx, location)
 }
 
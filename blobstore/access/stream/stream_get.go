@@ -62,6 +62,12 @@ func (blob *blobGetArgs) ID() string {
 	return fmt.Sprintf("blob(cid:%d vid:%d bid:%d)", blob.Cid, blob.Vid, blob.Bid)
 }
 
+// dataShardsOnly returns true if data shards covering the range are enough,
+// reconstruct the whole blob is a waste if read a part of it
+func (blob *blobGetArgs) dataShardsOnly() bool {
+	return int(blob.BlobSize) <= blob.ShardSize || blob.ReadSize < blob.BlobSize
+}
+
 type shardData struct {
 	index  int
 	status bool
@@ -84,6 +90,7 @@ type pipeBuffer struct {
 	err    error
 	blob   blobGetArgs
 	shards [][]byte
+	ranged *ec.Buffer // data of range read from data shards only
 }
 
 // Get read file
@@ -93,7 +100,7 @@ type pipeBuffer struct {
 //
 //	first return value is data transfer to copy data after argument checking
 //
-//	Read data shards firstly, if blob size is small or read few bytes
+//	Read the data shards covering the range firstly, if blob size is small or read a range of blob,
 //	then ec reconstruct-read, try to reconstruct from N+X to N+M
 //	Just read essential bytes in each shard when reconstruct-read.
 //
@@ -114,6 +121,10 @@ func (h *Handler) Get(ctx context.Context, w io.Writer, location access.Location
 	span := trace.SpanFromContextSafe(ctx)
 	span.Debugf("get request cluster:%d size:%d offset:%d", location.ClusterID, readSize, offset)
 
+	if location.IsInline() {
+		return h.getInline(ctx, w, &location, readSize, offset)
+	}
+
 	blobs, err := genLocationBlobs(&location, readSize, offset)
 	if err != nil {
 		span.Info("illegal argument", err)
@@ -143,29 +154,6 @@ func (h *Handler) Get(ctx context.Context, w io.Writer, location access.Location
 			span.AppendRPCTrackLog([]string{getTime.String()})
 		}()
 
-		// try to read data shard only,
-		//   if blobsize is small: all data is in the first shard, cos shards aligned by MinShardSize.
-		//   read few bytes: read bytes less than quarter of blobsize, like Range:[0-1].
-		if len(blobs) == 1 {
-			blob := blobs[0]
-			if int(blob.BlobSize) <= blob.ShardSize || blob.ReadSize < blob.BlobSize/4 {
-				span.Debugf("read data shard only %s readsize:%d blobsize:%d shardsize:%d",
-					blob.ID(), blob.ReadSize, blob.BlobSize, blob.ShardSize)
-
-				err := h.getDataShardOnly(ctx, getTime, w, serviceController, blob)
-				if err != errNeedReconstructRead {
-					if err != nil {
-						span.Error("read data shard only", err)
-						reportDownload(clusterID, "Direct", "error")
-					} else {
-						reportDownload(clusterID, "Direct", "-")
-					}
-					return err
-				}
-				span.Info("read data shard only failed", err)
-			}
-		}
-
 		// data stream flow:
 		// client <--copy-- pipeline <--swap-- readBlob <--copy-- blobnode
 		//
@@ -189,7 +177,35 @@ func (h *Handler) Get(ctx context.Context, w io.Writer, location access.Location
 							ch <- pipeBuffer{err: err}
 							return
 						}
+						sortedVuids = nil
+					}
 
+					// try to read the data shards covering the range only,
+					//   if blobsize is small: all data is in the first shard, cos shards aligned by MinShardSize.
+					//   read a range of blob: like Range:[0-1], the other data shards are useless.
+					if blob.dataShardsOnly() {
+						span.Debugf("read data shard only %s offset:%d readsize:%d blobsize:%d shardsize:%d",
+							blob.ID(), blob.Offset, blob.ReadSize, blob.BlobSize, blob.ShardSize)
+
+						ranged, err := h.getDataShardOnly(ctx, getTime, serviceController, blobVolume, blob)
+						if err == nil {
+							select {
+							case <-closeCh:
+								ranged.Release()
+								return
+							case ch <- pipeBuffer{blob: blob, ranged: ranged}:
+							}
+							continue
+						}
+						if err != errNeedReconstructRead {
+							span.Error("read data shard only", blob.ID(), err)
+							ch <- pipeBuffer{err: err}
+							return
+						}
+						span.Info("read data shard only failed", blob.ID(), err)
+					}
+
+					if sortedVuids == nil {
 						// do not use local shards
 						sortedVuids = genSortedVuidByIDC(ctx, serviceController, h.IDC, blobVolume.Units[:tactic.N+tactic.M])
 						span.Debugf("to read %s with read-shard-x:%d active-shard-n:%d of data-n:%d party-n:%d",
@@ -235,6 +251,7 @@ func (h *Handler) Get(ctx context.Context, w io.Writer, location access.Location
 		}()
 
 		var err error
+		direct := true
 		for line := range pipeline {
 			if line.err != nil {
 				err = line.err
@@ -243,33 +260,21 @@ func (h *Handler) Get(ctx context.Context, w io.Writer, location access.Location
 
 			startWrite := time.Now()
 
-			idx := 0
-			off := line.blob.Offset
-			toReadSize := line.blob.ReadSize
-			for toReadSize > 0 {
-				buf := line.shards[idx]
-				l := uint64(len(buf))
-				if off >= l {
-					idx++
-					off -= l
-					continue
+			if line.ranged != nil {
+				if _, e := w.Write(line.ranged.DataBuf[:int(line.blob.ReadSize)]); e != nil {
+					err = errors.Info(e, "write to response")
 				}
-
-				toRead := minU64(toReadSize, l-off)
-				if _, e := w.Write(buf[off : off+toRead]); e != nil {
+			} else {
+				direct = false
+				if e := h.encoder[line.blob.CodeMode].JoinN(w, line.shards,
+					int(line.blob.Offset), int(line.blob.ReadSize)); e != nil {
 					err = errors.Info(e, "write to response")
-					break
 				}
-				idx++
-				off = 0
-				toReadSize -= toRead
 			}
 
 			getTime.IncW(time.Since(startWrite))
 
-			for _, buf := range line.shards {
-				h.memPool.Put(buf)
-			}
+			h.releasePipeBuffer(line)
 			if err != nil {
 				close(closeCh)
 				break
@@ -279,22 +284,31 @@ func (h *Handler) Get(ctx context.Context, w io.Writer, location access.Location
 		// release buffer in pipeline if fail to write client
 		go func() {
 			for line := range pipeline {
-				for _, buf := range line.shards {
-					h.memPool.Put(buf)
-				}
+				h.releasePipeBuffer(line)
 			}
 		}()
 
+		readMode := "EC"
+		if direct {
+			readMode = "Direct"
+		}
 		if err != nil {
-			reportDownload(clusterID, "EC", "error")
+			reportDownload(clusterID, readMode, "error")
 			span.Error("get request error", err)
 			return err
 		}
-		reportDownload(clusterID, "EC", "-")
+		reportDownload(clusterID, readMode, "-")
 		return nil
 	}, nil
 }
 
+func (h *Handler) releasePipeBuffer(line pipeBuffer) {
+	line.ranged.Release()
+	for _, buf := range line.shards {
+		h.memPool.Put(buf)
+	}
+}
+
 // 1. try to min-read shards bytes
 // 2. if failed try to read next shard to reconstruct
 // 3. write the the right offset bytes to writer
@@ -525,84 +539,82 @@ func (h *Handler) readOneShard(ctx context.Context, serviceController controller
 	return shardResult
 }
 
+// getDataShardOnly reads the segments of data shards covering the range concurrently,
+// returns errNeedReconstructRead if any of them failed.
 func (h *Handler) getDataShardOnly(ctx context.Context, getTime *timeReadWrite,
-	w io.Writer, serviceController controller.ServiceController, blob blobGetArgs) error {
+	serviceController controller.ServiceController, blobVolume *controller.VolumePhy,
+	blob blobGetArgs) (*ec.Buffer, error) {
 	span := trace.SpanFromContextSafe(ctx)
-	if blob.ReadSize == 0 {
-		return nil
-	}
-
-	blobVolume, err := h.getVolume(ctx, blob.Cid, blob.Vid, true)
-	if err != nil {
-		return err
-	}
 	tactic := blobVolume.CodeMode.Tactic()
 
 	from, to := int(blob.Offset), int(blob.Offset+blob.ReadSize)
 	buffer, err := ec.NewRangeBuffer(int(blob.BlobSize), from, to, tactic, h.memPool)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer buffer.Release()
 
 	shardSize := buffer.ShardSize
-	firstShardIdx := int(blob.Offset) / shardSize
-	shardOffset := int(blob.Offset) % shardSize
+	firstShardIdx, lastShardIdx := from/shardSize, (to-1)/shardSize
+	if lastShardIdx >= tactic.N {
+		buffer.Release()
+		return nil, fmt.Errorf("no enough data to read %d", to-tactic.N*shardSize)
+	}
 
 	startRead := time.Now()
-	remainSize := blob.ReadSize
-	bufOffset := 0
-	for i, shard := range blobVolume.Units[firstShardIdx:tactic.N] {
-		if remainSize <= 0 {
-			break
+	errCh := make(chan error, lastShardIdx-firstShardIdx+1)
+	for idx := firstShardIdx; idx <= lastShardIdx; idx++ {
+		shardFrom, shardTo := idx*shardSize, (idx+1)*shardSize
+		if shardFrom < from {
+			shardFrom = from
 		}
-
-		toReadSize := minU64(remainSize, uint64(shardSize-shardOffset))
-		args := blobnode.RangeGetShardArgs{
-			GetShardArgs: blobnode.GetShardArgs{
-				DiskID: shard.DiskID,
-				Vuid:   shard.Vuid,
-				Bid:    blob.Bid,
-			},
-			Offset: int64(shardOffset),
-			Size:   int64(toReadSize),
+		if shardTo > to {
+			shardTo = to
 		}
 
-		body, err := h.getOneShardFromHost(ctx, serviceController, shard.Host, shard.DiskID, args,
-			firstShardIdx+i, blob.Cid, blob.Vid, 1, nil)
-		if err != nil {
-			span.Warnf("read %s on blobnode(vuid:%d disk:%d host:%s) ecidx(%02d): %s", blob.ID(),
-				shard.Vuid, shard.DiskID, shard.Host, firstShardIdx+i, errors.Detail(err))
-			return errNeedReconstructRead
-		}
-		defer body.Close()
+		go func(idx, shardFrom, shardTo int) {
+			shard := blobVolume.Units[idx]
+			args := blobnode.RangeGetShardArgs{
+				GetShardArgs: blobnode.GetShardArgs{
+					DiskID: shard.DiskID,
+					Vuid:   shard.Vuid,
+					Bid:    blob.Bid,
+				},
+				Offset: int64(shardFrom - idx*shardSize),
+				Size:   int64(shardTo - shardFrom),
+			}
 
-		buf := buffer.DataBuf[bufOffset : bufOffset+int(toReadSize)]
-		_, err = io.ReadFull(body, buf)
-		if err != nil {
-			span.Warn(err)
-			return errNeedReconstructRead
-		}
+			body, err := h.getOneShardFromHost(ctx, serviceController, shard.Host, shard.DiskID, args,
+				idx, blob.Cid, blob.Vid, 1, nil)
+			if err != nil {
+				span.Warnf("read %s on blobnode(vuid:%d disk:%d host:%s) ecidx(%02d): %s", blob.ID(),
+					shard.Vuid, shard.DiskID, shard.Host, idx, errors.Detail(err))
+				errCh <- errNeedReconstructRead
+				return
+			}
+			defer body.Close()
 
-		// reset next shard offset
-		shardOffset = 0
-		remainSize -= toReadSize
-		bufOffset += int(toReadSize)
+			if _, err = io.ReadFull(body, buffer.DataBuf[shardFrom-from:shardTo-from]); err != nil {
+				span.Warn(err)
+				errCh <- errNeedReconstructRead
+				return
+			}
+			errCh <- nil
+		}(idx, shardFrom, shardTo)
 	}
-	getTime.IncR(time.Since(startRead))
 
-	if remainSize > 0 {
-		return fmt.Errorf("no enough data to read %d", remainSize)
+	// wait for all shards, the buffer is written concurrently
+	for idx := firstShardIdx; idx <= lastShardIdx; idx++ {
+		if e := <-errCh; e != nil {
+			err = e
+		}
 	}
+	getTime.IncR(time.Since(startRead))
 
-	startWrite := time.Now()
-	if _, err := w.Write(buffer.DataBuf[:int(blob.ReadSize)]); err != nil {
-		getTime.IncW(time.Since(startWrite))
-		return errors.Info(err, "write to response")
+	if err != nil {
+		buffer.Release()
+		return nil, err
 	}
-	getTime.IncW(time.Since(startWrite))
-
-	return nil
+	return buffer, nil
 }
 
 // getOneShardFromHost get body of one shard
@@ -636,6 +648,7 @@ func (h *Handler) getOneShardFromHost(ctx context.Context, serviceController con
 		// new child span to get from blobnode, we should finish it here.
 		spanChild, ctxChild := trace.StartSpanFromContextWithTraceID(
 			context.Background(), "GetFromBlobnode", span.TraceID())
+		ctxChild = rpc.ContextWithRequestID(ctxChild, rpc.RequestIDFromContext(ctx))
 		defer spanChild.Finish()
 
 		body, _, err := h.blobnodeClient.RangeGetShard(ctxChild, host, &args)
@@ -19,6 +19,7 @@ import (
 	"crypto/rand"
 	"math"
 	mrand "math/rand"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 	"github.com/cubefs/cubefs/blobstore/api/access"
 	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	"github.com/cubefs/cubefs/blobstore/common/ec"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
 )
 
@@ -197,6 +199,51 @@ func TestAccessStreamGetOffset(t *testing.T) {
 	dataShards.clean()
 }
 
+func TestAccessStreamGetRange(t *testing.T) {
+	ctx := ctxWithName("TestAccessStreamGetRange")
+	dataShards.clean()
+	defer dataShards.clean()
+
+	size := blobSize
+	data := make([]byte, size)
+	rand.Read(data)
+	loc, err := streamer.Put(ctx(), bytes.NewReader(data), int64(size), nil)
+	require.NoError(t, err)
+
+	sizes, err := ec.GetBufferSizes(size, loc.CodeMode.Tactic())
+	require.NoError(t, err)
+	shardSize := uint64(sizes.ShardSize)
+
+	// data shard of 1005 is broken
+	cases := []struct {
+		offset      uint64
+		readSize    uint64
+		reconstruct bool
+	}{
+		{0, 1, false},
+		{1, 100, false},
+		{shardSize - 10, 20, false},
+		{shardSize - 10, 2*shardSize + 20, false},
+		{4*shardSize + 1, 100, true},
+		{3*shardSize + 1, shardSize, true},
+	}
+	for _, cs := range cases {
+		atomic.StoreInt64(&rangeGetShardBytes, 0)
+		buff := bytes.NewBuffer(nil)
+		transfer, err := streamer.Get(ctx(), buff, *loc, cs.readSize, cs.offset)
+		require.NoError(t, err)
+		require.NoError(t, transfer())
+		require.True(t, dataEqual(data[cs.offset:cs.offset+cs.readSize], buff.Bytes()))
+
+		readBytes := atomic.LoadInt64(&rangeGetShardBytes)
+		if cs.reconstruct {
+			require.Less(t, int64(cs.readSize), readBytes)
+		} else {
+			require.Equal(t, int64(cs.readSize), readBytes)
+		}
+	}
+}
+
 func TestAccessStreamGetShardTimeout(t *testing.T) {
 	ctx := ctxWithName("TestAccessStreamGetShardTimeout")
 	dataShards.clean()
@@ -0,0 +1,99 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/util/errors"
+)
+
+// PutInline put one object, the object not larger than inline size is stored
+// in location instead of blobs, others are put as Put
+func (h *Handler) PutInline(ctx context.Context, rc io.Reader, size int64,
+	hasherMap access.HasherMap) (*access.Location, error) {
+	if size <= 0 || size > int64(h.InlineSize) {
+		return h.Put(ctx, rc, size, hasherMap)
+	}
+	span := trace.SpanFromContextSafe(ctx)
+	span.Debugf("put inline request size:%d hashes:b(%b)", size, hasherMap.ToHashAlgorithm())
+
+	if len(hasherMap) > 0 {
+		rc = io.TeeReader(rc, hasherMap.ToWriter())
+	}
+	return h.putInline(ctx, rc, size, h.allCodeModes.SelectCodeMode(size))
+}
+
+// putInline stores the small file in location instead of allocating blobs,
+// the cluster is chosen only to keep location cluster-routable
+func (h *Handler) putInline(ctx context.Context, rc io.Reader, size int64,
+	codeMode codemode.CodeMode) (*access.Location, error) {
+	span := trace.SpanFromContextSafe(ctx)
+
+	cluster, err := h.clusterController.ChooseOne()
+	if err != nil {
+		span.Error("choose cluster for inline failed", errors.Detail(err))
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	if n, err := io.ReadFull(rc, data); err != nil {
+		span.Infof("read inline data failed want:%d read:%d %s", size, n, err.Error())
+		return nil, errcode.ErrAccessReadRequestBody
+	}
+	inline, err := encryptInline(data, uint64(size))
+	if err != nil {
+		return nil, err
+	}
+
+	span.Debugf("put inline to cluster:%d size:%d", cluster.ClusterID, size)
+	return &access.Location{
+		ClusterID: cluster.ClusterID,
+		CodeMode:  codeMode,
+		Size:      uint64(size),
+		BlobSize:  atomic.LoadUint32(&h.MaxBlobSize),
+		Inline:    inline,
+	}, nil
+}
+
+// getInline writes the data decrypted from location
+func (h *Handler) getInline(ctx context.Context, w io.Writer, location *access.Location,
+	readSize, offset uint64) (func() error, error) {
+	span := trace.SpanFromContextSafe(ctx)
+
+	data, err := decryptInline(location.Inline, location.Size)
+	if err != nil || uint64(len(data)) != location.Size ||
+		offset > location.Size || readSize > location.Size-offset {
+		span.Infof("illegal inline location size:%d readsize:%d offset:%d err:%v",
+			location.Size, readSize, offset, err)
+		return func() error { return nil }, errcode.ErrIllegalArguments
+	}
+
+	return func() error {
+		_, err := w.Write(data[offset : offset+readSize])
+		if err != nil {
+			reportDownload(location.ClusterID, "Inline", "error")
+			return err
+		}
+		reportDownload(location.ClusterID, "Inline", "-")
+		return nil
+	}, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stream
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+)
+
+func TestAccessStreamInlineEncrypt(t *testing.T) {
+	data := make([]byte, 1024)
+	rand.Read(data)
+	size := uint64(len(data))
+	inline, err := encryptInline(data, size)
+	require.NoError(t, err)
+	require.NotContains(t, string(inline), string(data))
+
+	inline2, err := encryptInline(data, size)
+	require.NoError(t, err)
+	require.NotEqual(t, inline, inline2)
+
+	for _, in := range [][]byte{inline, inline2} {
+		datax, err := decryptInline(in, size)
+		require.NoError(t, err)
+		require.Equal(t, data, datax)
+	}
+	_, err = decryptInline(inline[:10], size)
+	require.Error(t, err)
+
+	// modified inline or size is detected
+	_, err = decryptInline(inline, size-1)
+	require.Error(t, err)
+	inline[len(inline)/2] ^= 0xff
+	_, err = decryptInline(inline, size)
+	require.Error(t, err)
+}
+
+func TestAccessStreamInline(t *testing.T) {
+	ctx := ctxWithName("TestAccessStreamInline")
+	streamer.InlineSize = 4 << 10
+	defer func() { streamer.InlineSize = 0 }()
+
+	size := 4 << 10
+	data := make([]byte, size)
+	rand.Read(data)
+	loc, err := streamer.PutInline(ctx(), bytes.NewReader(data), int64(size), nil)
+	require.NoError(t, err)
+	require.True(t, loc.IsInline())
+	require.Equal(t, 0, len(loc.Blobs))
+	require.Equal(t, uint64(size), loc.Size)
+	require.NoError(t, fillCrc(loc))
+	require.True(t, verifyCrc(loc))
+
+	buff := bytes.NewBuffer(nil)
+	transfer, err := streamer.Get(ctx(), buff, *loc, uint64(size), 0)
+	require.NoError(t, err)
+	require.NoError(t, transfer())
+	require.Equal(t, data, buff.Bytes())
+
+	buff.Reset()
+	transfer, err = streamer.Get(ctx(), buff, *loc, 100, 1000)
+	require.NoError(t, err)
+	require.NoError(t, transfer())
+	require.Equal(t, data[1000:1100], buff.Bytes())
+
+	_, err = streamer.Get(ctx(), buff, *loc, 2, uint64(size)-1)
+	require.ErrorIs(t, err, errcode.ErrIllegalArguments)
+	locx := loc.Copy()
+	locx.Inline = locx.Inline[:len(locx.Inline)-1]
+	_, err = streamer.Get(ctx(), buff, locx, 1, 0)
+	require.ErrorIs(t, err, errcode.ErrIllegalArguments)
+
+	require.NoError(t, streamer.Delete(ctx(), loc))
+
+	// inline location is signed only by itself
+	require.Error(t, signCrc(&locx, []access.Location{*loc}))
+	locx = loc.Copy()
+	require.NoError(t, signCrc(&locx, []access.Location{*loc}))
+	require.Error(t, signCrc(&locx, []access.Location{*loc, *loc}))
+	locx = loc.Copy()
+	locx.Inline[0] ^= 0xff
+	require.Error(t, signCrc(&locx, []access.Location{*loc}))
+	locx = loc.Copy()
+	locx.Inline = nil
+	require.Error(t, signCrc(&locx, []access.Location{*loc}))
+
+	// not inline without asking for
+	loc, err = streamer.Put(ctx(), bytes.NewReader(data), int64(size), nil)
+	require.NoError(t, err)
+	require.False(t, loc.IsInline())
+	require.NoError(t, streamer.Delete(ctx(), loc))
+
+	// larger file is stored in blobs
+	size++
+	loc, err = streamer.PutInline(ctx(), newReader(size), int64(size), nil)
+	require.NoError(t, err)
+	require.False(t, loc.IsInline())
+	require.Equal(t, 1, len(loc.Blobs))
+	require.NoError(t, streamer.Delete(ctx(), loc))
+	dataShards.clean()
+}
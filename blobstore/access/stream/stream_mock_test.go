@@ -28,6 +28,7 @@ import (
 	"math/rand"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -87,6 +88,8 @@ var (
 	dataShards  *shardsData
 
 	vuidController *vuidControl
+	// bytes read from blobnode
+	rangeGetShardBytes int64
 
 	putErrors = []errcode.Error{
 		errcode.ErrDiskBroken, errcode.ErrReadonlyVUID,
@@ -235,6 +238,7 @@ var storageAPIRangeGetShard = func(ctx context.Context, host string, args *blobn
 	}
 
 	buff = buff[int(args.Offset):int(args.Offset+args.Size)]
+	atomic.AddInt64(&rangeGetShardBytes, args.Size)
 	shardCrc = crc32.ChecksumIEEE(buff)
 	body = io.NopCloser(bytes.NewReader(buff))
 	return
@@ -256,6 +256,7 @@ func (h *Handler) writeToBlobnodes(ctx context.Context,
 			// new child span to write to blobnode, we should finish it here.
 			spanChild, ctxChild := trace.StartSpanFromContextWithTraceID(
 				context.Background(), "WriteToBlobnode", span.TraceID())
+			ctxChild = rpc.ContextWithRequestID(ctxChild, rpc.RequestIDFromContext(ctx))
 			defer spanChild.Finish()
 
 		RETRY:
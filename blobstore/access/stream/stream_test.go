@@ -49,6 +49,11 @@ func TestAccessStreamConfig(t *testing.T) {
 	require.Equal(t, idcOther, cfg.IDC)
 	require.Equal(t, map[int]int{1024: 1}, cfg.MemPoolSizeClasses)
 	require.Equal(t, defaultDiskPunishIntervalS, cfg.DiskPunishIntervalS)
+
+	cfg.InlineSize = maxInlineSize + 1
+	require.Error(t, confCheck(&cfg))
+	cfg.InlineSize = -1
+	require.Error(t, confCheck(&cfg))
 }
 
 func TestAccessStreamNew(t *testing.T) {
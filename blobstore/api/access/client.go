@@ -407,6 +407,9 @@ func (c *client) putObject(ctx context.Context, args *PutArgs) (location Locatio
 	rpcClient := c.rpcClient.Load().(rpc.Client)
 
 	urlStr := fmt.Sprintf("/put?size=%d&hashes=%d", args.Size, args.Hashes)
+	if args.Inline {
+		urlStr += "&inline=true"
+	}
 	req, err := http.NewRequest(http.MethodPut, urlStr, args.Body)
 	if err != nil {
 		return
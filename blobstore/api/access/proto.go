@@ -196,12 +196,13 @@ func (h HashSumMap) All() map[string]interface{} {
 	return m
 }
 
-// Location file location, 4 + 1 + 8 + 4 + 4 + len*16 bytes
+// Location file location, 4 + 1 + 8 + 4 + 4 + len*16 + len(Inline) bytes
 // |                                        |
 // |   ClusterID(4)    |    CodeMode(1)     |
 // |                Size(8)                 |
 // |   BlobSize(4)     |      Crc(4)        |
 // |           len*SliceInfo(16)            |
+// |          Inline(len(Inline))           |
 //
 // ClusterID which cluster file is in
 // CodeMode is ec encode mode, see defined in "common/lib/codemode"
@@ -209,6 +210,7 @@ func (h HashSumMap) All() map[string]interface{} {
 // BlobSize is every blob's size but the last one which's size=(Size mod BlobSize)
 // Crc is the checksum, change anything of the location, crc will mismatch
 // Blobs all blob information
+// Inline is the encrypted data of small file stored in location instead of blobs
 type Location struct {
 	_         [0]byte
 	ClusterID proto.ClusterID   `json:"cluster_id"`
@@ -217,6 +219,7 @@ type Location struct {
 	BlobSize  uint32            `json:"blob_size"`
 	Crc       uint32            `json:"crc"`
 	Blobs     []SliceInfo       `json:"blobs"`
+	Inline    []byte            `json:"inline,omitempty"`
 }
 
 // SliceInfo blobs info, 8 + 4 + 4 bytes
@@ -255,9 +258,18 @@ func (loc *Location) Copy() Location {
 		Blobs:     make([]SliceInfo, len(loc.Blobs)),
 	}
 	copy(dst.Blobs, loc.Blobs)
+	if len(loc.Inline) > 0 {
+		dst.Inline = make([]byte, len(loc.Inline))
+		copy(dst.Inline, loc.Inline)
+	}
 	return dst
 }
 
+// IsInline returns true if data of the file is stored in location
+func (loc *Location) IsInline() bool {
+	return len(loc.Inline) > 0
+}
+
 // Encode transfer Location to slice byte
 // Returns the buf created by me
 //
@@ -273,11 +285,16 @@ func (loc *Location) Copy() Location {
 //	- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
 //	| n-bytes |  (10)  | (5) |  (5)  | (20) | (20) |       ...         |
 //	- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+//	  optional (10){len(inline)} + len(Inline), only if inline is not empty
+//	- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
 func (loc *Location) Encode() []byte {
 	if loc == nil {
 		return nil
 	}
 	n := 25 + 5 + len(loc.Blobs)*20
+	if len(loc.Inline) > 0 {
+		n += 10 + len(loc.Inline)
+	}
 	buf := make([]byte, n)
 	n = loc.Encode2(buf)
 	return buf[:n]
@@ -307,6 +324,11 @@ func (loc *Location) Encode2(buf []byte) int {
 		n += binary.PutUvarint(buf[n:], uint64(blob.Count))
 	}
 
+	if len(loc.Inline) > 0 {
+		n += binary.PutUvarint(buf[n:], uint64(len(loc.Inline)))
+		n += copy(buf[n:], loc.Inline)
+	}
+
 	return n
 }
 
@@ -445,6 +467,21 @@ func DecodeLocation(buf []byte) (Location, int, error) {
 		loc.Blobs = append(loc.Blobs, blob)
 	}
 
+	// the location encoded without inline ends here
+	if len(buf) == 0 {
+		return loc, n, nil
+	}
+	if val, nn = next(); nn <= 0 {
+		return loc, n, fmt.Errorf("bytes length inline %d", nn)
+	}
+	if uint64(len(buf)) < val {
+		return loc, n, fmt.Errorf("bytes inline %d < %d", len(buf), val)
+	}
+	if val > 0 {
+		loc.Inline = make([]byte, val)
+		n += copy(loc.Inline, buf[:val])
+	}
+
 	return loc, n, nil
 }
 
@@ -487,7 +524,10 @@ func DecodeLocationFromBase64(s string) (Location, error) {
 type PutArgs struct {
 	Size   int64         `json:"size"`
 	Hashes HashAlgorithm `json:"hashes,omitempty"`
-	Body   io.Reader     `json:"-"`
+	// Inline the object not larger than inline size of access may be stored in location,
+	// set it only if the client keeps the whole location
+	Inline bool      `json:"inline,omitempty"`
+	Body   io.Reader `json:"-"`
 
 	// GetBody defines an optional func to return a new copy of Body.
 	// It is used for client requests when a redirect requires reading
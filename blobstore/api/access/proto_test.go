@@ -346,6 +346,40 @@ func TestLocationEncodeDecode(t *testing.T) {
 	}
 }
 
+func TestLocationEncodeDecodeInline(t *testing.T) {
+	loc := &access.Location{
+		ClusterID: 1,
+		CodeMode:  codemode.EC6P6,
+		Size:      3,
+		BlobSize:  1 << 22,
+		Crc:       mrand.Uint32(),
+		Inline:    []byte("inline-data"),
+	}
+	require.True(t, loc.IsInline())
+
+	buf := loc.Encode()
+	locx, n, err := access.DecodeLocation(buf)
+	require.NoError(t, err)
+	require.Equal(t, len(buf), n)
+	require.Equal(t, *loc, locx)
+	copied := loc.Copy()
+	require.Equal(t, loc.Inline, copied.Inline)
+
+	for _, n := range []int{len(buf) - 1, len(buf) - len(loc.Inline)} {
+		_, _, err = access.DecodeLocation(buf[:n])
+		require.Error(t, err)
+	}
+
+	// location without inline keeps the old encoding
+	loc.Inline = nil
+	require.False(t, loc.IsInline())
+	bufx := loc.Encode()
+	require.Equal(t, buf[:len(bufx)], bufx)
+	locx, _, err = access.DecodeLocation(bufx)
+	require.NoError(t, err)
+	require.Nil(t, locx.Inline)
+}
+
 func TestLocationDecodeError(t *testing.T) {
 	loc := &access.Location{
 		ClusterID: proto.ClusterID(math.MaxUint32),
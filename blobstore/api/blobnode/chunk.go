@@ -280,6 +280,40 @@ type CompactChunkArgs struct {
 	Vuid   proto.Vuid   `json:"vuid"`
 }
 
+type CompactStatArgs struct {
+	DiskID proto.DiskID `json:"diskid"`
+}
+
+// CompactProgress progress of the compacting chunk,
+// TotalSize is the physical size of source chunk when compaction started
+type CompactProgress struct {
+	Vuid         proto.Vuid `json:"vuid"`
+	ChunkID      ChunkId    `json:"chunk_id"`
+	StartTime    int64      `json:"start_time"` // unix nano
+	TotalSize    int64      `json:"total_size"`
+	CopiedSize   int64      `json:"copied_size"`
+	CopiedShards int64      `json:"copied_shards"`
+}
+
+// CompactStat chunks compacting on the disk
+type CompactStat struct {
+	DiskID      proto.DiskID       `json:"diskid"`
+	Concurrency int                `json:"concurrency"`
+	Running     []*CompactProgress `json:"running"`
+}
+
+func (c *client) CompactStat(ctx context.Context, host string, args *CompactStatArgs) (stat *CompactStat, err error) {
+	if !IsValidDiskID(args.DiskID) {
+		err = bloberr.ErrInvalidDiskId
+		return
+	}
+
+	urlStr := fmt.Sprintf("%v/chunk/compact/stat/diskid/%v", host, args.DiskID)
+	stat = new(CompactStat)
+	err = c.GetWith(ctx, urlStr, stat)
+	return
+}
+
 type DiskProbeArgs struct {
 	Path string `json:"path"`
 }
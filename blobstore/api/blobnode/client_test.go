@@ -85,6 +85,12 @@ func TestNewBlobNodeClient(t *testing.T) {
 	require.NoError(t, err)
 	span.Infof("chunks: %v\n", chunks)
 
+	compactStat, err := cli.CompactStat(ctx, mockServer.URL, &CompactStatArgs{DiskID: diskid})
+	require.NoError(t, err)
+	span.Infof("compact stat: %v\n", compactStat)
+	_, err = cli.CompactStat(ctx, mockServer.URL, &CompactStatArgs{})
+	require.Error(t, err)
+
 	databytes := []byte("test context")
 	putShardArgs := &PutShardArgs{
 		DiskID: diskid,
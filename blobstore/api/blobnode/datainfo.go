@@ -36,8 +36,10 @@ type DiskInfo struct {
 	Rack         string           `json:"rack"`
 	Host         string           `json:"host"`
 	Path         string           `json:"path"`
-	Status       proto.DiskStatus `json:"status"` // normal、broken、repairing、repaired、dropped
+	DiskSetID    proto.DiskSetID  `json:"disk_set_id"` // disks share the same backplane/HBA
+	Status       proto.DiskStatus `json:"status"`      // normal、broken、repairing、repaired、dropped
 	Readonly     bool             `json:"readonly"`
+	Cold         bool             `json:"cold"` // low-cost disk for sealed volumes
 	CreateAt     time.Time        `json:"create_time"`
 	LastUpdateAt time.Time        `json:"last_update_time"`
 	DiskHeartBeatInfo
@@ -98,6 +98,18 @@ type DiskAccessArgs struct {
 	Readonly bool         `json:"readonly"`
 }
 
+type WatchBrokenDiskArgs struct {
+	// Version the broken disk version which the watcher has seen
+	Version uint64 `json:"version"`
+	// TimeoutMs the longest time to wait for new broken disk
+	TimeoutMs int64 `json:"timeout_ms"`
+}
+
+type WatchBrokenDiskRet struct {
+	// Version the number of disks which have left normal status
+	Version uint64 `json:"version"`
+}
+
 // DiskIDAlloc alloc diskID from cluster manager
 func (c *Client) AllocDiskID(ctx context.Context) (proto.DiskID, error) {
 	ret := &DiskIDAllocRet{}
@@ -188,3 +200,11 @@ func (c *Client) SetReadonlyDisk(ctx context.Context, id proto.DiskID, readonly
 	err = c.PostWith(ctx, "/disk/access", nil, &DiskAccessArgs{DiskID: id, Readonly: readonly})
 	return
 }
+
+// WatchBrokenDisk waits until the broken disk version is greater than args.Version or timeout,
+// it returns the latest broken disk version
+func (c *Client) WatchBrokenDisk(ctx context.Context, args *WatchBrokenDiskArgs) (ret *WatchBrokenDiskRet, err error) {
+	ret = &WatchBrokenDiskRet{}
+	err = c.GetWith(ctx, fmt.Sprintf("/disk/broken/watch?version=%d&timeout_ms=%d", args.Version, args.TimeoutMs), ret)
+	return
+}
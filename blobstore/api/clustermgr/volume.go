@@ -111,6 +111,41 @@ func (c *Client) ListVolumeV2(ctx context.Context, args *ListVolumeV2Args) (ret
 	return
 }
 
+// VolumeHealth health of volume, Score is the number of units the volume can still lose,
+// the volume with lower score is more at risk and it may lose data if score is negative
+type VolumeHealth struct {
+	Vid            proto.Vid                  `json:"vid"`
+	CodeMode       codemode.CodeMode          `json:"code_mode"`
+	Status         proto.VolumeStatus         `json:"status"`
+	Score          int                        `json:"score"`
+	TotalUnits     int                        `json:"total_units"`
+	HealthyUnits   int                        `json:"healthy_units"`
+	PendingRepairs int                        `json:"pending_repairs"` // units on broken or repairing disks
+	LastInspect    *proto.VolumeInspectResult `json:"last_inspect,omitempty"`
+}
+
+type ListVolumeHealthArgs struct {
+	// list the count volumes with the lowest score
+	Count int `json:"count,omitempty"`
+}
+
+type ListVolumeHealthRet struct {
+	Volumes []*VolumeHealth `json:"volumes"`
+}
+
+// GetVolumeHealth returns health of the volume
+func (c *Client) GetVolumeHealth(ctx context.Context, args *GetVolumeArgs) (ret *VolumeHealth, err error) {
+	ret = &VolumeHealth{}
+	err = c.GetWith(ctx, "/volume/health/get?vid="+args.Vid.ToString(), ret)
+	return
+}
+
+// ListVolumeHealth returns the most at-risk volumes ordered by score ascending
+func (c *Client) ListVolumeHealth(ctx context.Context, args *ListVolumeHealthArgs) (ret ListVolumeHealthRet, err error) {
+	err = c.GetWith(ctx, fmt.Sprintf("/volume/health/list?count=%d", args.Count), &ret)
+	return
+}
+
 type AllocVolumeArgs struct {
 	IsInit   bool              `json:"is_init"`
 	CodeMode codemode.CodeMode `json:"code_mode"`
@@ -176,6 +211,11 @@ func (c *Client) UnlockVolume(ctx context.Context, args *UnlockVolumeArgs) (err
 
 type AllocVolumeUnitArgs struct {
 	Vuid proto.Vuid `json:"vuid"`
+	// Cold means alloc new volume unit on cold disk
+	Cold bool `json:"cold,omitempty"`
+	// ExcludeDisks the disks avoided in preference, they are still allocatable
+	// if no other disk is available
+	ExcludeDisks []proto.DiskID `json:"exclude_disks,omitempty"`
 }
 
 type AllocVolumeUnit struct {
@@ -48,6 +48,9 @@ type AllocVolsArgs struct {
 	BidCount uint64            `json:"bid_count"`
 	Excludes []proto.Vid       `json:"excludes"`
 	Discards []proto.Vid       `json:"discards"`
+	// volumes are chosen by consistent hash of client id,
+	// proxy fills it with the remote host of request if empty
+	ClientID string `json:"client_id,omitempty"`
 }
 
 type DiscardVolsArgs struct {
@@ -42,7 +42,14 @@ const (
 
 	PathTaskDetail    = "/task/detail"
 	PathTaskDetailURI = PathTaskDetail + "/:type/:id" // "/task/detail/:type/:id"
+	PathTaskList      = "/task/list"
 	PathUpdateVolume  = "/update/vol"
+
+	PathQueueParams    = "/queue/params"
+	PathQueueParamsSet = "/queue/params/set"
+
+	PathHostDrain     = "/host/drain"
+	PathHostDrainStat = "/host/drain/stat"
 )
 
 const defaultHostSyncIntervalMs = 3600000 // 1 hour
@@ -69,6 +76,7 @@ type IInspector interface {
 type ISchedulerStatus interface {
 	DetailMigrateTask(ctx context.Context, args *MigrateTaskDetailArgs) (detail MigrateTaskDetail, err error)
 	DiskMigratingStats(ctx context.Context, args *DiskMigratingStatsArgs) (ret *DiskMigratingStats, err error)
+	ListTasks(ctx context.Context, args *ListTasksArgs) (ret *ListTasksRet, err error)
 	Stats(ctx context.Context, host string) (ret TasksStat, err error)
 	LeaderStats(ctx context.Context) (ret TasksStat, err error)
 }
@@ -78,6 +86,18 @@ type IManualMigrator interface {
 	AddManualMigrateTask(ctx context.Context, args *AddManualMigrateArgs) (err error)
 }
 
+// IQueueParams queue parameters of migrate task.
+type IQueueParams interface {
+	GetQueueParams(ctx context.Context, args *QueueParamsArgs) (ret *QueueParams, err error)
+	SetQueueParams(ctx context.Context, args *QueueParamsSetArgs) (err error)
+}
+
+// IHostDrainer drain all disks of blobnode host.
+type IHostDrainer interface {
+	DrainHost(ctx context.Context, args *HostDrainArgs) (err error)
+	HostDrainStat(ctx context.Context, args *HostDrainStatArgs) (ret *HostDrainStat, err error)
+}
+
 // IVolumeUpdater volume updater.
 type IVolumeUpdater interface {
 	UpdateVolume(ctx context.Context, host string, vid proto.Vid) (err error)
@@ -89,6 +109,8 @@ type IScheduler interface {
 	IInspector
 	ISchedulerStatus
 	IManualMigrator
+	IQueueParams
+	IHostDrainer
 	IVolumeUpdater
 }
 
@@ -96,12 +118,17 @@ type IScheduler interface {
 type Config struct {
 	HostRetry          int   `json:"host_retry"`
 	HostSyncIntervalMs int64 `json:"host_sync_interval_ms"`
+	// transport of task acquire, renewal, complete and report, http or grpc
+	Transport string `json:"transport"`
+	// grpc port of scheduler if transport is grpc
+	GrpcPort int `json:"grpc_port"`
 	rpc.Config
 }
 
 type client struct {
 	hostRetry int
 	selector  selector.Selector
+	grpc      *grpcClient // nil if transport is http
 	rpc.Client
 }
 
@@ -131,11 +158,15 @@ func New(cfg *Config, service cmapi.APIService, clusterID proto.ClusterID) ISche
 	if cfg.HostRetry == 0 {
 		cfg.HostRetry = 1
 	}
-	return &client{
+	cli := &client{
 		hostRetry: cfg.HostRetry,
 		selector:  selector.MakeSelector(cfg.HostSyncIntervalMs, hostGetter),
 		Client:    rpc.NewClient(&cfg.Config),
 	}
+	if cfg.Transport == rpc.TransportGRPC {
+		cli.grpc = newGrpcClient(cfg.GrpcPort)
+	}
+	return cli
 }
 
 // NewVolumeUpdater returns volume updater client.
@@ -0,0 +1,302 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/cubefs/cubefs/blobstore/api/scheduler/schedulerpb"
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+)
+
+// grpcClient task client on grpc transport,
+// the grpc server listens on the same host of http server with port GrpcPort.
+type grpcClient struct {
+	port int
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn // grpc target => connection
+}
+
+func newGrpcClient(port int) *grpcClient {
+	return &grpcClient{port: port, conns: make(map[string]*grpc.ClientConn)}
+}
+
+// GrpcTarget returns grpc target of http host with grpc port.
+func GrpcTarget(host string, port int) (string, error) {
+	u, err := url.Parse(hostWithScheme(host))
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(u.Hostname(), strconv.Itoa(port)), nil
+}
+
+func (gc *grpcClient) client(ctx context.Context, host string) (pb.SchedulerTaskClient, error) {
+	target, err := GrpcTarget(host, gc.port)
+	if err != nil {
+		return nil, err
+	}
+
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	conn, ok := gc.conns[target]
+	if !ok {
+		if conn, err = rpc.DialGRPC(ctx, target); err != nil {
+			return nil, err
+		}
+		gc.conns[target] = conn
+	}
+	return pb.NewSchedulerTaskClient(conn), nil
+}
+
+func (c *client) grpcAcquireTask(ctx context.Context, args *AcquireArgs) (ret *proto.MigrateTask, err error) {
+	err = c.request(func(host string) error {
+		cli, err := c.grpc.client(ctx, host)
+		if err != nil {
+			return err
+		}
+		task, err := cli.AcquireTask(ctx, &pb.AcquireTaskRequest{Idc: args.IDC})
+		if err != nil {
+			return err
+		}
+		ret = MigrateTaskFromPB(task)
+		return nil
+	})
+	return
+}
+
+func (c *client) grpcRenewalTask(ctx context.Context, args *TaskRenewalArgs) (ret *TaskRenewalRet, err error) {
+	err = c.request(func(host string) error {
+		cli, err := c.grpc.client(ctx, host)
+		if err != nil {
+			return err
+		}
+		resp, err := cli.RenewalTask(ctx, TaskRenewalArgsToPB(args))
+		if err != nil {
+			return err
+		}
+		ret = TaskRenewalRetFromPB(resp)
+		return nil
+	})
+	return
+}
+
+func (c *client) grpcReportTask(ctx context.Context, args *TaskReportArgs) (err error) {
+	return c.request(func(host string) error {
+		cli, err := c.grpc.client(ctx, host)
+		if err != nil {
+			return err
+		}
+		_, err = cli.ReportTask(ctx, TaskReportArgsToPB(args))
+		return err
+	})
+}
+
+func (c *client) grpcCompleteTask(ctx context.Context, args *OperateTaskArgs) (err error) {
+	return c.request(func(host string) error {
+		cli, err := c.grpc.client(ctx, host)
+		if err != nil {
+			return err
+		}
+		_, err = cli.CompleteTask(ctx, OperateTaskArgsToPB(args))
+		return err
+	})
+}
+
+func vunitLocationToPB(loc proto.VunitLocation) *pb.VunitLocation {
+	return &pb.VunitLocation{Vuid: uint64(loc.Vuid), Host: loc.Host, DiskId: uint32(loc.DiskID)}
+}
+
+func vunitLocationFromPB(loc *pb.VunitLocation) proto.VunitLocation {
+	if loc == nil {
+		return proto.VunitLocation{}
+	}
+	return proto.VunitLocation{Vuid: proto.Vuid(loc.Vuid), Host: loc.Host, DiskID: proto.DiskID(loc.DiskId)}
+}
+
+func vunitLocationsToPB(locs []proto.VunitLocation) []*pb.VunitLocation {
+	if locs == nil {
+		return nil
+	}
+	ret := make([]*pb.VunitLocation, 0, len(locs))
+	for _, loc := range locs {
+		ret = append(ret, vunitLocationToPB(loc))
+	}
+	return ret
+}
+
+func vunitLocationsFromPB(locs []*pb.VunitLocation) []proto.VunitLocation {
+	if locs == nil {
+		return nil
+	}
+	ret := make([]proto.VunitLocation, 0, len(locs))
+	for _, loc := range locs {
+		ret = append(ret, vunitLocationFromPB(loc))
+	}
+	return ret
+}
+
+// MigrateTaskToPB converts migrate task to protobuf message.
+func MigrateTaskToPB(task *proto.MigrateTask) *pb.MigrateTask {
+	return &pb.MigrateTask{
+		TaskId:                  task.TaskID,
+		TaskType:                string(task.TaskType),
+		State:                   uint32(task.State),
+		SourceIdc:               task.SourceIDC,
+		SourceDiskId:            uint32(task.SourceDiskID),
+		SourceVuid:              uint64(task.SourceVuid),
+		Sources:                 vunitLocationsToPB(task.Sources),
+		CodeMode:                uint32(task.CodeMode),
+		Destination:             vunitLocationToPB(task.Destination),
+		Ctime:                   task.Ctime,
+		Mtime:                   task.MTime,
+		FinishAdvanceReason:     task.FinishAdvanceReason,
+		ForbiddenDirectDownload: task.ForbiddenDirectDownload,
+		WorkerRedoCnt:           uint32(task.WorkerRedoCnt),
+	}
+}
+
+// MigrateTaskFromPB converts protobuf message to migrate task.
+func MigrateTaskFromPB(task *pb.MigrateTask) *proto.MigrateTask {
+	return &proto.MigrateTask{
+		TaskID:                  task.TaskId,
+		TaskType:                proto.TaskType(task.TaskType),
+		State:                   proto.MigrateState(task.State),
+		SourceIDC:               task.SourceIdc,
+		SourceDiskID:            proto.DiskID(task.SourceDiskId),
+		SourceVuid:              proto.Vuid(task.SourceVuid),
+		Sources:                 vunitLocationsFromPB(task.Sources),
+		CodeMode:                codemode.CodeMode(task.CodeMode),
+		Destination:             vunitLocationFromPB(task.Destination),
+		Ctime:                   task.Ctime,
+		MTime:                   task.Mtime,
+		FinishAdvanceReason:     task.FinishAdvanceReason,
+		ForbiddenDirectDownload: task.ForbiddenDirectDownload,
+		WorkerRedoCnt:           uint8(task.WorkerRedoCnt),
+	}
+}
+
+// TaskRenewalArgsToPB converts renewal arguments to protobuf message.
+func TaskRenewalArgsToPB(args *TaskRenewalArgs) *pb.RenewalTaskRequest {
+	req := &pb.RenewalTaskRequest{Idc: args.IDC, Ids: make(map[string]*pb.TaskIDs, len(args.IDs))}
+	for typ, ids := range args.IDs {
+		req.Ids[string(typ)] = &pb.TaskIDs{Ids: ids}
+	}
+	return req
+}
+
+// TaskRenewalArgsFromPB converts protobuf message to renewal arguments.
+func TaskRenewalArgsFromPB(req *pb.RenewalTaskRequest) *TaskRenewalArgs {
+	args := &TaskRenewalArgs{IDC: req.Idc, IDs: make(map[proto.TaskType][]string, len(req.Ids))}
+	for typ, ids := range req.Ids {
+		args.IDs[proto.TaskType(typ)] = ids.GetIds()
+	}
+	return args
+}
+
+// TaskRenewalRetToPB converts renewal result to protobuf message.
+func TaskRenewalRetToPB(ret *TaskRenewalRet) *pb.RenewalTaskResponse {
+	resp := &pb.RenewalTaskResponse{}
+	if len(ret.Errors) == 0 {
+		return resp
+	}
+	resp.Errors = make(map[string]*pb.TaskErrors, len(ret.Errors))
+	for typ, errs := range ret.Errors {
+		resp.Errors[string(typ)] = &pb.TaskErrors{Errors: errs}
+	}
+	return resp
+}
+
+// TaskRenewalRetFromPB converts protobuf message to renewal result.
+func TaskRenewalRetFromPB(resp *pb.RenewalTaskResponse) *TaskRenewalRet {
+	ret := &TaskRenewalRet{}
+	if len(resp.Errors) == 0 {
+		return ret
+	}
+	ret.Errors = make(map[proto.TaskType]map[string]string, len(resp.Errors))
+	for typ, errs := range resp.Errors {
+		ret.Errors[proto.TaskType(typ)] = errs.GetErrors()
+	}
+	return ret
+}
+
+// TaskReportArgsToPB converts report arguments to protobuf message.
+func TaskReportArgsToPB(args *TaskReportArgs) *pb.ReportTaskRequest {
+	return &pb.ReportTaskRequest{
+		TaskType: string(args.TaskType),
+		TaskId:   args.TaskID,
+		TaskStats: &pb.TaskStatistics{
+			DoneSize:   args.TaskStats.DoneSize,
+			DoneCount:  args.TaskStats.DoneCount,
+			TotalSize:  args.TaskStats.TotalSize,
+			TotalCount: args.TaskStats.TotalCount,
+			Progress:   args.TaskStats.Progress,
+		},
+		IncreaseDataSizeByte: int64(args.IncreaseDataSizeByte),
+		IncreaseShardCnt:     int64(args.IncreaseShardCnt),
+	}
+}
+
+// TaskReportArgsFromPB converts protobuf message to report arguments.
+func TaskReportArgsFromPB(req *pb.ReportTaskRequest) *TaskReportArgs {
+	args := &TaskReportArgs{
+		TaskType:             proto.TaskType(req.TaskType),
+		TaskID:               req.TaskId,
+		IncreaseDataSizeByte: int(req.IncreaseDataSizeByte),
+		IncreaseShardCnt:     int(req.IncreaseShardCnt),
+	}
+	if stats := req.TaskStats; stats != nil {
+		args.TaskStats = proto.TaskStatistics{
+			DoneSize:   stats.DoneSize,
+			DoneCount:  stats.DoneCount,
+			TotalSize:  stats.TotalSize,
+			TotalCount: stats.TotalCount,
+			Progress:   stats.Progress,
+		}
+	}
+	return args
+}
+
+// OperateTaskArgsToPB converts operate arguments to protobuf message.
+func OperateTaskArgsToPB(args *OperateTaskArgs) *pb.OperateTaskRequest {
+	return &pb.OperateTaskRequest{
+		Idc:      args.IDC,
+		TaskId:   args.TaskID,
+		TaskType: string(args.TaskType),
+		Src:      vunitLocationsToPB(args.Src),
+		Dest:     vunitLocationToPB(args.Dest),
+		Reason:   args.Reason,
+	}
+}
+
+// OperateTaskArgsFromPB converts protobuf message to operate arguments.
+func OperateTaskArgsFromPB(req *pb.OperateTaskRequest) *OperateTaskArgs {
+	return &OperateTaskArgs{
+		IDC:      req.Idc,
+		TaskID:   req.TaskId,
+		TaskType: proto.TaskType(req.TaskType),
+		Src:      vunitLocationsFromPB(req.Src),
+		Dest:     vunitLocationFromPB(req.Dest),
+		Reason:   req.Reason,
+	}
+}
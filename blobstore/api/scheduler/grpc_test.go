@@ -0,0 +1,145 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/cubefs/cubefs/blobstore/api/scheduler/schedulerpb"
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/util/selector"
+)
+
+var testTask = &proto.MigrateTask{
+	TaskID:       "task-1",
+	TaskType:     proto.TaskTypeDiskRepair,
+	State:        proto.MigrateStatePrepared,
+	SourceIDC:    "z0",
+	SourceDiskID: 1,
+	SourceVuid:   2,
+	Sources: []proto.VunitLocation{
+		{Vuid: 2, Host: "127.0.0.1:8889", DiskID: 1},
+		{Vuid: 3, Host: "127.0.0.2:8889", DiskID: 3},
+	},
+	CodeMode:      codemode.EC6P6,
+	Destination:   proto.VunitLocation{Vuid: 4, Host: "127.0.0.3:8889", DiskID: 5},
+	Ctime:         "ctime",
+	MTime:         "mtime",
+	WorkerRedoCnt: 2,
+}
+
+type fakeTaskServer struct {
+	renewal  *TaskRenewalArgs
+	report   *TaskReportArgs
+	complete *OperateTaskArgs
+}
+
+func (s *fakeTaskServer) AcquireTask(ctx context.Context, req *pb.AcquireTaskRequest) (*pb.MigrateTask, error) {
+	if req.Idc != testTask.SourceIDC {
+		return nil, rpc.NewError(http.StatusNotFound, "NotFound", errors.New("no task"))
+	}
+	return MigrateTaskToPB(testTask), nil
+}
+
+func (s *fakeTaskServer) RenewalTask(ctx context.Context, req *pb.RenewalTaskRequest) (*pb.RenewalTaskResponse, error) {
+	s.renewal = TaskRenewalArgsFromPB(req)
+	ret := &TaskRenewalRet{Errors: map[proto.TaskType]map[string]string{
+		proto.TaskTypeDiskRepair: {"task-2": "no such task"},
+	}}
+	return TaskRenewalRetToPB(ret), nil
+}
+
+func (s *fakeTaskServer) CompleteTask(ctx context.Context, req *pb.OperateTaskRequest) (*pb.Empty, error) {
+	s.complete = OperateTaskArgsFromPB(req)
+	return &pb.Empty{}, nil
+}
+
+func (s *fakeTaskServer) ReportTask(ctx context.Context, req *pb.ReportTaskRequest) (*pb.Empty, error) {
+	s.report = TaskReportArgsFromPB(req)
+	return &pb.Empty{}, nil
+}
+
+func TestGrpcTarget(t *testing.T) {
+	for _, host := range []string{"127.0.0.1:9800", "http://127.0.0.1:9800", "127.0.0.1"} {
+		target, err := GrpcTarget(host, 9801)
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1:9801", target)
+	}
+	_, err := GrpcTarget("http://%zz", 9801)
+	require.Error(t, err)
+}
+
+func TestGrpcTaskClient(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	fake := &fakeTaskServer{}
+	server := rpc.NewGRPCServer()
+	pb.RegisterSchedulerTaskServer(server, fake)
+	go server.Serve(ln)
+	defer server.Stop()
+
+	cli := &client{
+		hostRetry: 1,
+		selector: selector.MakeSelector(60000, func() ([]string, error) {
+			return []string{"http://127.0.0.1:9800"}, nil
+		}),
+		grpc: newGrpcClient(ln.Addr().(*net.TCPAddr).Port),
+	}
+	ctx := context.Background()
+
+	task, err := cli.AcquireTask(ctx, &AcquireArgs{IDC: "z0"})
+	require.NoError(t, err)
+	require.Equal(t, testTask, task)
+	_, err = cli.AcquireTask(ctx, &AcquireArgs{IDC: "z1"})
+	require.Equal(t, http.StatusNotFound, rpc.DetectStatusCode(err))
+	require.Equal(t, "NotFound", rpc.DetectErrorCode(err))
+
+	renewalArgs := &TaskRenewalArgs{IDC: "z0", IDs: map[proto.TaskType][]string{
+		proto.TaskTypeDiskRepair: {"task-1", "task-2"},
+	}}
+	ret, err := cli.RenewalTask(ctx, renewalArgs)
+	require.NoError(t, err)
+	require.Equal(t, renewalArgs, fake.renewal)
+	require.Equal(t, "no such task", ret.Errors[proto.TaskTypeDiskRepair]["task-2"])
+
+	reportArgs := &TaskReportArgs{
+		TaskType:             proto.TaskTypeDiskRepair,
+		TaskID:               "task-1",
+		TaskStats:            proto.TaskStatistics{DoneSize: 1, DoneCount: 2, TotalSize: 3, TotalCount: 4, Progress: 5},
+		IncreaseDataSizeByte: 6,
+		IncreaseShardCnt:     7,
+	}
+	require.NoError(t, cli.ReportTask(ctx, reportArgs))
+	require.Equal(t, reportArgs, fake.report)
+
+	operateArgs := &OperateTaskArgs{
+		IDC:      "z0",
+		TaskID:   "task-1",
+		TaskType: proto.TaskTypeDiskRepair,
+		Src:      testTask.Sources,
+		Dest:     testTask.Destination,
+		Reason:   "reason",
+	}
+	require.NoError(t, cli.CompleteTask(ctx, operateArgs))
+	require.Equal(t, operateArgs, fake.complete)
+}
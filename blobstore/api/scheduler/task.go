@@ -28,6 +28,9 @@ type AcquireArgs struct {
 }
 
 func (c *client) AcquireTask(ctx context.Context, args *AcquireArgs) (ret *proto.MigrateTask, err error) {
+	if c.grpc != nil {
+		return c.grpcAcquireTask(ctx, args)
+	}
 	err = c.request(func(host string) error {
 		return c.GetWith(ctx, host+PathTaskAcquire+"?idc="+args.IDC, &ret)
 	})
@@ -44,6 +47,9 @@ type TaskRenewalRet struct {
 }
 
 func (c *client) RenewalTask(ctx context.Context, args *TaskRenewalArgs) (ret *TaskRenewalRet, err error) {
+	if c.grpc != nil {
+		return c.grpcRenewalTask(ctx, args)
+	}
 	err = c.request(func(host string) error {
 		return c.PostWith(ctx, host+PathTaskRenewal, &ret, args)
 	})
@@ -60,6 +66,9 @@ type TaskReportArgs struct {
 }
 
 func (c *client) ReportTask(ctx context.Context, args *TaskReportArgs) (err error) {
+	if c.grpc != nil {
+		return c.grpcReportTask(ctx, args)
+	}
 	return c.request(func(host string) error {
 		return c.PostWith(ctx, host+PathTaskReport, nil, args)
 	})
@@ -88,6 +97,9 @@ func (c *client) CancelTask(ctx context.Context, args *OperateTaskArgs) (err err
 }
 
 func (c *client) CompleteTask(ctx context.Context, args *OperateTaskArgs) (err error) {
+	if c.grpc != nil {
+		return c.grpcCompleteTask(ctx, args)
+	}
 	return c.request(func(host string) error {
 		return c.PostWith(ctx, host+PathTaskComplete, nil, args)
 	})
@@ -148,9 +160,15 @@ type DiskRepairTasksStat struct {
 }
 
 type MigrateTasksStat struct {
-	PreparingCnt   int         `json:"preparing_cnt"`
-	WorkerDoingCnt int         `json:"worker_doing_cnt"`
-	FinishingCnt   int         `json:"finishing_cnt"`
+	PreparingCnt   int      `json:"preparing_cnt"`
+	WorkerDoingCnt int      `json:"worker_doing_cnt"`
+	FinishingCnt   int      `json:"finishing_cnt"`
+	SlowCnt        int      `json:"slow_cnt"`
+	SlowTasks      []string `json:"slow_tasks,omitempty"`
+	// QuarantinedDisks destination disks avoided for repeatedly failed tasks
+	QuarantinedDisks []proto.DiskID `json:"quarantined_disks,omitempty"`
+	// CorruptedTasks tasks in db skipped when loading for corrupted state
+	CorruptedTasks []string    `json:"corrupted_tasks,omitempty"`
 	StatsPerMin    PerMinStats `json:"stats_per_min"`
 }
 
@@ -167,6 +185,11 @@ type BalanceTasksStat struct {
 	MigrateTasksStat
 }
 
+type ColdMigrateTasksStat struct {
+	Enable bool `json:"enable"`
+	MigrateTasksStat
+}
+
 type ManualMigrateTasksStat struct {
 	MigrateTasksStat
 }
@@ -191,6 +214,7 @@ type TasksStat struct {
 	DiskDrop      *DiskDropTasksStat      `json:"disk_drop,omitempty"`
 	Balance       *BalanceTasksStat       `json:"balance,omitempty"`
 	ManualMigrate *ManualMigrateTasksStat `json:"manual_migrate,omitempty"`
+	ColdMigrate   *ColdMigrateTasksStat   `json:"cold_migrate,omitempty"`
 	VolumeInspect *VolumeInspectTasksStat `json:"volume_inspect,omitempty"`
 	ShardRepair   *RunnerStat             `json:"shard_repair"`
 	BlobDelete    *RunnerStat             `json:"blob_delete"`
@@ -242,6 +266,151 @@ func (c *client) DiskMigratingStats(ctx context.Context, args *DiskMigratingStat
 	return
 }
 
+// ListTasksArgs list migrate tasks args, zero value of filter means not filtered.
+type ListTasksArgs struct {
+	TaskType proto.TaskType     `json:"task_type"`
+	State    proto.MigrateState `json:"state,omitempty"`
+	DiskID   proto.DiskID       `json:"disk_id,omitempty"`
+	Vid      proto.Vid          `json:"vid,omitempty"`
+	Marker   string             `json:"marker,omitempty"`
+	Count    int                `json:"count,omitempty"`
+}
+
+// ListTasksRet list migrate tasks result, the listing is over if marker is empty.
+type ListTasksRet struct {
+	Tasks  []*proto.MigrateTask `json:"tasks"`
+	Marker string               `json:"marker"`
+}
+
+func (c *client) ListTasks(ctx context.Context, args *ListTasksArgs) (ret *ListTasksRet, err error) {
+	if args == nil || !args.TaskType.Valid() {
+		err = errcode.ErrIllegalArguments
+		return
+	}
+	query := url.Values{}
+	query.Set("task_type", args.TaskType.String())
+	if args.State != 0 {
+		query.Set("state", fmt.Sprint(args.State))
+	}
+	if args.DiskID != proto.InvalidDiskID {
+		query.Set("disk_id", args.DiskID.ToString())
+	}
+	if args.Vid != proto.InvalidVid {
+		query.Set("vid", args.Vid.ToString())
+	}
+	if args.Marker != "" {
+		query.Set("marker", args.Marker)
+	}
+	if args.Count > 0 {
+		query.Set("count", fmt.Sprint(args.Count))
+	}
+	err = c.request(func(host string) error {
+		return c.GetWith(ctx, host+PathTaskList+"?"+query.Encode(), &ret)
+	})
+	return
+}
+
+type QueueParamsArgs struct {
+	TaskType proto.TaskType `json:"task_type"`
+}
+
+// QueueParams queue parameters of migrate task type which can be modified at runtime.
+type QueueParams struct {
+	TaskType                proto.TaskType `json:"task_type"`
+	LeaseExpiredS           int            `json:"lease_expired_s"`
+	PrepareQueueRetryDelayS int            `json:"prepare_queue_retry_delay_s"`
+	FinishQueueRetryDelayS  int            `json:"finish_queue_retry_delay_s"`
+	CancelPunishDurationS   int            `json:"cancel_punish_duration_s"`
+	WorkQueueSize           int            `json:"work_queue_size"`
+}
+
+// QueueParamsSetArgs queue parameters to modify, nil fields are not modified.
+type QueueParamsSetArgs struct {
+	TaskType                proto.TaskType `json:"task_type"`
+	LeaseExpiredS           *int           `json:"lease_expired_s,omitempty"`
+	PrepareQueueRetryDelayS *int           `json:"prepare_queue_retry_delay_s,omitempty"`
+	FinishQueueRetryDelayS  *int           `json:"finish_queue_retry_delay_s,omitempty"`
+	CancelPunishDurationS   *int           `json:"cancel_punish_duration_s,omitempty"`
+	WorkQueueSize           *int           `json:"work_queue_size,omitempty"`
+}
+
+func (c *client) GetQueueParams(ctx context.Context, args *QueueParamsArgs) (ret *QueueParams, err error) {
+	if args == nil || !args.TaskType.Valid() {
+		err = errcode.ErrIllegalArguments
+		return
+	}
+	err = c.request(func(host string) error {
+		return c.GetWith(ctx, host+PathQueueParams+"?task_type="+args.TaskType.String(), &ret)
+	})
+	return
+}
+
+func (c *client) SetQueueParams(ctx context.Context, args *QueueParamsSetArgs) (err error) {
+	if args == nil || !args.TaskType.Valid() {
+		return errcode.ErrIllegalArguments
+	}
+	return c.request(func(host string) error {
+		return c.PostWith(ctx, host+PathQueueParamsSet, nil, args)
+	})
+}
+
+// HostDrainArgs drain all disks of host, at most concurrency disks of host are dropping at the same time.
+type HostDrainArgs struct {
+	Host        string `json:"host"`
+	Concurrency int    `json:"concurrency,omitempty"`
+}
+
+type HostDrainStatArgs struct {
+	Host string `json:"host"`
+}
+
+// HostDrainDiskState state of disk in draining host
+type HostDrainDiskState string
+
+const (
+	HostDrainDiskPending  = HostDrainDiskState("pending")
+	HostDrainDiskDropping = HostDrainDiskState("dropping")
+	HostDrainDiskDropped  = HostDrainDiskState("dropped")
+)
+
+type HostDrainDisk struct {
+	DiskID         proto.DiskID       `json:"disk_id"`
+	State          HostDrainDiskState `json:"state"`
+	TotalChunks    int                `json:"total_chunks"`
+	MigratedChunks int                `json:"migrated_chunks"`
+}
+
+// HostDrainStat progress of draining host
+type HostDrainStat struct {
+	Host          string          `json:"host"`
+	Concurrency   int             `json:"concurrency"`
+	Ctime         string          `json:"ctime"`
+	PendingDisks  int             `json:"pending_disks"`
+	DroppingDisks int             `json:"dropping_disks"`
+	DroppedDisks  int             `json:"dropped_disks"`
+	Disks         []HostDrainDisk `json:"disks"`
+}
+
+func (c *client) DrainHost(ctx context.Context, args *HostDrainArgs) (err error) {
+	if args == nil || args.Host == "" {
+		return errcode.ErrIllegalArguments
+	}
+	return c.request(func(host string) error {
+		return c.PostWith(ctx, host+PathHostDrain, nil, args)
+	})
+}
+
+func (c *client) HostDrainStat(ctx context.Context, args *HostDrainStatArgs) (ret *HostDrainStat, err error) {
+	if args == nil || args.Host == "" {
+		err = errcode.ErrIllegalArguments
+		return
+	}
+	err = c.request(func(host string) error {
+		return c.GetWith(ctx, host+PathHostDrainStat+"?host="+url.QueryEscape(args.Host), &ret)
+	})
+	return
+}
+
 func (c *client) selectHost() ([]string, error) {
 	hosts := c.selector.GetRandomN(c.hostRetry)
 	if len(hosts) == 0 {
@@ -21,6 +21,7 @@ import (
 	api "github.com/cubefs/cubefs/blobstore/api/blobnode"
 	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 )
@@ -104,6 +105,30 @@ func (c *BlobNodeClient) GetShard(ctx context.Context, location proto.VunitLocat
 	return c.cli.GetShard(ctx, location.Host, &api.GetShardArgs{DiskID: location.DiskID, Vuid: location.Vuid, Bid: bid, Type: ioType})
 }
 
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
+
+type limitedBlobNode struct {
+	IBlobNode
+	lim ratelimit.Limiter
+}
+
+// NewLimitedBlobNodeClient returns blobnode client which limits bytes per second of getting shards
+func NewLimitedBlobNodeClient(cli IBlobNode, lim ratelimit.Limiter) IBlobNode {
+	return &limitedBlobNode{IBlobNode: cli, lim: lim}
+}
+
+// GetShard returns shard data limited by limiter
+func (c *limitedBlobNode) GetShard(ctx context.Context, location proto.VunitLocation, bid proto.BlobID, ioType api.IOType) (body io.ReadCloser, crc32 uint32, err error) {
+	body, crc32, err = c.IBlobNode.GetShard(ctx, location, bid, ioType)
+	if err != nil {
+		return
+	}
+	return &limitedBody{Reader: ratelimit.Reader(ctx, c.lim, body), Closer: body}, crc32, nil
+}
+
 // StatShard return shard stat
 func (c *BlobNodeClient) StatShard(ctx context.Context, location proto.VunitLocation, bid proto.BlobID) (si *ShardInfo, err error) {
 	pSpan := trace.SpanFromContextSafe(ctx)
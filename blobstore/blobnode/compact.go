@@ -68,3 +68,37 @@ func (s *Service) ChunkCompact(c *rpc.Context) {
 	ds.EnqueueCompact(ctx, args.Vuid)
 	span.Infof("compact enqueue vuid:%v success", args.Vuid)
 }
+
+/*
+ *  method:         GET
+ *  url:            /chunk/compact/stat/diskid/{diskid}
+ *  response body:  json.Marshal(CompactStat)
+ */
+func (s *Service) ChunkCompactStat(c *rpc.Context) {
+	args := new(bnapi.CompactStatArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	span := trace.SpanFromContextSafe(ctx)
+
+	if !bnapi.IsValidDiskID(args.DiskID) {
+		span.Debugf("args:%v", args)
+		c.RespondError(bloberr.ErrInvalidDiskId)
+		return
+	}
+
+	s.lock.RLock()
+	ds, exist := s.Disks[args.DiskID]
+	s.lock.RUnlock()
+	if !exist {
+		span.Errorf("diskid(%v) no such disk", args.DiskID)
+		c.RespondError(bloberr.ErrNoSuchDisk)
+		return
+	}
+
+	stat := ds.CompactStat(ctx)
+	c.RespondJSON(&stat)
+}
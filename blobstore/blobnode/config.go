@@ -110,6 +110,7 @@ func configInit(config *Config) {
 	}
 	defaulter.LessOrEqual(&config.InspectConf.IntervalSec, DefaultChunkInspectIntervalSec)
 	defaulter.LessOrEqual(&config.InspectConf.RateLimit, DefaultInspectRate)
+	defaulter.Empty(&config.WorkerConfig.RepairRateLimit.Host, config.Host)
 }
 
 func (s *Service) changeLimit(ctx context.Context, c Config) {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	bnapi "github.com/cubefs/cubefs/blobstore/api/blobnode"
@@ -39,6 +40,12 @@ var (
 type compactTask struct {
 	stopCh chan struct{}
 	once   sync.Once
+
+	// progress
+	startTime    int64
+	totalSize    int64
+	copiedSize   int64
+	copiedShards int64
 }
 
 func (cs *chunk) StartCompact(ctx context.Context) (newcs core.ChunkAPI, err error) {
@@ -63,6 +70,12 @@ func (cs *chunk) StartCompact(ctx context.Context) (newcs core.ChunkAPI, err err
 
 	stg := cs.getStg()
 
+	task := cs.compactTask.Load().(*compactTask)
+	atomic.StoreInt64(&task.startTime, now)
+	if stat, err := stg.Stat(ctx); err == nil {
+		atomic.StoreInt64(&task.totalSize, stat.PhySize)
+	}
+
 	// new dstChunkStorage
 	ncs, err := newChunkStorage(ctx, cs.Disk().GetDataPath(), vm, cs.readPool, cs.writePool, func(o *core.Option) {
 		o.Conf = cs.Disk().GetConfig()
@@ -76,7 +89,6 @@ func (cs *chunk) StartCompact(ctx context.Context) (newcs core.ChunkAPI, err err
 		return nil, err
 	}
 
-	task := cs.compactTask.Load().(*compactTask)
 	notify := func(err error) {
 		task.once.Do(func() {
 			close(task.stopCh)
@@ -191,6 +203,8 @@ func (cs *chunk) doCompact(ctx context.Context, ncs *chunk) (err error) {
 			span.Errorf("sync shard(%v) to chunk(%s) failed: %v", blobID, ncs.ID(), err)
 			return
 		}
+		atomic.AddInt64(&task.copiedShards, 1)
+		atomic.AddInt64(&task.copiedSize, int64(shard.Size))
 
 		// monitor task termination
 		select {
@@ -306,6 +320,26 @@ func (cs *chunk) StopCompact(ctx context.Context, ncs core.ChunkAPI) (err error)
 	return nil
 }
 
+// CompactProgress returns nil if the chunk is not compacting
+func (cs *chunk) CompactProgress() *bnapi.CompactProgress {
+	cs.lock.RLock()
+	compacting := cs.compacting
+	cs.lock.RUnlock()
+	if !compacting {
+		return nil
+	}
+
+	task := cs.compactTask.Load().(*compactTask)
+	return &bnapi.CompactProgress{
+		Vuid:         cs.vuid,
+		ChunkID:      cs.ID(),
+		StartTime:    atomic.LoadInt64(&task.startTime),
+		TotalSize:    atomic.LoadInt64(&task.totalSize),
+		CopiedSize:   atomic.LoadInt64(&task.copiedSize),
+		CopiedShards: atomic.LoadInt64(&task.copiedShards),
+	}
+}
+
 func (cs *chunk) NeedCompact(ctx context.Context) bool {
 	span := trace.SpanFromContextSafe(ctx)
 
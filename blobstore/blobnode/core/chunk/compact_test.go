@@ -121,6 +121,10 @@ func (mock *diskMock) EnqueueCompact(ctx context.Context, vuid proto.Vuid) {
 	// do nothing
 }
 
+func (mock *diskMock) CompactStat(ctx context.Context) (stat bnapi.CompactStat) {
+	return
+}
+
 func (mock *diskMock) GcRubbishChunk(ctx context.Context) (mayBeLost []bnapi.ChunkId, err error) {
 	return
 }
@@ -270,6 +274,13 @@ func TestChunk_StartCompact(t *testing.T) {
 	require.Equal(t, rawStg, replStg.RawStorage())
 	require.Equal(t, true, cs.compacting)
 
+	progress := cs.CompactProgress()
+	require.NotNil(t, progress)
+	require.Equal(t, vuid, progress.Vuid)
+	require.Equal(t, int64(shardCnt), progress.CopiedShards)
+	require.Equal(t, int64(shardCnt)*int64(shardSize), progress.CopiedSize)
+	require.NotZero(t, progress.StartTime)
+
 	// source list check
 	sis, _, err := cs.ListShards(ctx, 0, 4096, bnapi.ShardStatusNormal)
 	require.NoError(t, err)
@@ -300,6 +311,7 @@ func TestChunk_StartCompact(t *testing.T) {
 	err = cs.StopCompact(ctx, newcs)
 	require.NoError(t, err)
 	require.Equal(t, false, cs.compacting)
+	require.Nil(t, cs.CompactProgress())
 	require.Equal(t, rawStg, cs.getStg())
 	require.Equal(t, nil, cs.getStg().RawStorage())
 
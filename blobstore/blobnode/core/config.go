@@ -39,6 +39,7 @@ const (
 	DefaultDiskCleanTrashIntervalSec    = int64(60)             // 1 min
 	DefaultDiskTrashProtectionM         = int64(1440)           // 1 days
 	DefaultCompactBatchSize             = 1024                  // 1024 counts
+	DefaultCompactConcurrency           = 1                     // chunks compacting per disk
 	DefaultCompactMinSizeThreshold      = int64(16 * (1 << 30)) // 16 GiB
 	DefaultCompactTriggerThreshold      = int64(1 * (1 << 40))  // 1 TiB
 	DefaultMetricReportIntervalS        = int64(300)            // 300 Sec
@@ -51,10 +52,12 @@ const (
 
 // Config for disk
 type BaseConfig struct {
-	Path        string `json:"path"`
-	AutoFormat  bool   `json:"auto_format"`
-	MaxChunks   int32  `json:"max_chunks"`
-	DisableSync bool   `json:"disable_sync"`
+	Path        string          `json:"path"`
+	AutoFormat  bool            `json:"auto_format"`
+	MaxChunks   int32           `json:"max_chunks"`
+	DisableSync bool            `json:"disable_sync"`
+	DiskSetID   proto.DiskSetID `json:"disk_set_id"`
+	Cold        bool            `json:"cold"`
 }
 
 type RuntimeConfig struct {
@@ -79,6 +82,7 @@ type RuntimeConfig struct {
 	IOStatFileDryRun             bool    `json:"iostat_file_dryrun"`
 	SetDefaultSwitch             bool    `json:"set_default_switch"`
 	CompactBatchSize             int     `json:"compact_batch_size"`
+	CompactConcurrency           int     `json:"compact_concurrency"`
 	MetricReportIntervalS        int64   `json:"metric_report_interval_S"`
 	BlockBufferSize              int64   `json:"block_buffer_size"`
 	WriteThreadCnt               int     `json:"write_thread_cnt"`
@@ -131,6 +135,7 @@ func InitConfig(conf *Config) error {
 	defaulter.LessOrEqual(&conf.CompactMinSizeThreshold, DefaultCompactMinSizeThreshold)
 	defaulter.LessOrEqual(&conf.CompactEmptyRateThreshold, DefaultCompactEmptyRateThreshold)
 	defaulter.LessOrEqual(&conf.CompactBatchSize, DefaultCompactBatchSize)
+	defaulter.LessOrEqual(&conf.CompactConcurrency, DefaultCompactConcurrency)
 	defaulter.LessOrEqual(&conf.BlockBufferSize, DefaultBlockBufferSize)
 
 	defaulter.LessOrEqual(&conf.ChunkCleanIntervalSec, DefaultChunkCleanIntervalSec)
@@ -43,4 +43,11 @@ func TestInitConfig(t *testing.T) {
 	}
 	err = InitConfig(conf)
 	require.Error(t, err)
+
+	conf.AllocDiskID = func(ctx context.Context) (proto.DiskID, error) {
+		return 1, nil
+	}
+	err = InitConfig(conf)
+	require.NoError(t, err)
+	require.Equal(t, DefaultCompactConcurrency, conf.CompactConcurrency)
 }
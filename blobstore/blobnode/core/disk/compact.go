@@ -16,6 +16,7 @@ package disk
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	bnapi "github.com/cubefs/cubefs/blobstore/api/blobnode"
@@ -40,21 +41,23 @@ func (ds *DiskStorage) loopCompactFile() {
 
 	span.Infof("start compact executor.")
 
-	// consumer
-	ds.loopAttach(func() {
-		for {
-			select {
-			case <-ds.closeCh:
-				span.Warnf("loopCompact done...")
-				return
-			case vuid := <-ds.compactCh:
-				span.Debugf("recv compact message. vuid:[%d]", vuid)
-				if err := ds.ExecCompactChunk(vuid); err != nil {
-					span.Errorf("compact vuid: %d err:%v", vuid, err)
+	// consumers, limit the chunks compacting concurrently on the disk
+	for i := 0; i < ds.Conf.CompactConcurrency; i++ {
+		ds.loopAttach(func() {
+			for {
+				select {
+				case <-ds.closeCh:
+					span.Warnf("loopCompact done...")
+					return
+				case vuid := <-ds.compactCh:
+					span.Debugf("recv compact message. vuid:[%d]", vuid)
+					if err := ds.ExecCompactChunk(vuid); err != nil {
+						span.Errorf("compact vuid: %d err:%v", vuid, err)
+					}
 				}
 			}
-		}
-	})
+		})
+	}
 
 	span.Infof("start compact checker.")
 
@@ -84,15 +87,80 @@ func (ds *DiskStorage) runCompactFiles() {
 	}
 	ds.Lock.RUnlock()
 
+	// at most concurrency chunks in a round
+	n := ds.Conf.CompactConcurrency - ds.compactingCount()
 	for _, chunk := range chunks {
-		if !chunk.NeedCompact(ctx) {
+		if n <= 0 {
+			return
+		}
+		if ds.isCompacting(chunk.Vuid()) || !chunk.NeedCompact(ctx) {
 			continue
 		}
 		span.Infof("will compact vuid:<%d>", chunk.Vuid())
 		ds.EnqueueCompact(ctx, chunk.Vuid())
-		// Once in a round
-		return
+		n--
+	}
+}
+
+func (ds *DiskStorage) startCompacting(vuid proto.Vuid) bool {
+	ds.compactLock.Lock()
+	defer ds.compactLock.Unlock()
+	if _, ok := ds.compactings[vuid]; ok {
+		return false
+	}
+	if ds.compactings == nil {
+		ds.compactings = make(map[proto.Vuid]struct{})
+	}
+	ds.compactings[vuid] = struct{}{}
+	return true
+}
+
+func (ds *DiskStorage) finishCompacting(vuid proto.Vuid) {
+	ds.compactLock.Lock()
+	delete(ds.compactings, vuid)
+	ds.compactLock.Unlock()
+}
+
+func (ds *DiskStorage) isCompacting(vuid proto.Vuid) bool {
+	ds.compactLock.Lock()
+	defer ds.compactLock.Unlock()
+	_, ok := ds.compactings[vuid]
+	return ok
+}
+
+func (ds *DiskStorage) compactingCount() int {
+	ds.compactLock.Lock()
+	defer ds.compactLock.Unlock()
+	return len(ds.compactings)
+}
+
+// CompactStat returns progress of the chunks compacting on the disk
+func (ds *DiskStorage) CompactStat(ctx context.Context) (stat bnapi.CompactStat) {
+	stat.DiskID = ds.DiskID
+	stat.Concurrency = ds.Conf.CompactConcurrency
+	stat.Running = make([]*bnapi.CompactProgress, 0)
+
+	ds.compactLock.Lock()
+	vuids := make([]proto.Vuid, 0, len(ds.compactings))
+	for vuid := range ds.compactings {
+		vuids = append(vuids, vuid)
+	}
+	ds.compactLock.Unlock()
+	sort.Slice(vuids, func(i, j int) bool { return vuids[i] < vuids[j] })
+
+	for _, vuid := range vuids {
+		cs, found := ds.GetChunkStorage(vuid)
+		if !found {
+			continue
+		}
+		// not started or finished copying
+		progress := cs.CompactProgress()
+		if progress == nil {
+			progress = &bnapi.CompactProgress{Vuid: vuid, ChunkID: cs.ID()}
+		}
+		stat.Running = append(stat.Running, progress)
 	}
+	return
 }
 
 func (ds *DiskStorage) EnqueueCompact(ctx context.Context, vuid proto.Vuid) {
@@ -204,6 +272,12 @@ func (ds *DiskStorage) ExecCompactChunk(vuid proto.Vuid) (err error) {
 		return bloberr.ErrNoSuchVuid
 	}
 
+	if !ds.startCompacting(vuid) {
+		span.Warnf("vuid:%d is compacting, skip", vuid)
+		return bloberr.ErrChunkInCompact
+	}
+	defer ds.finishCompacting(vuid)
+
 	// Persistent compacting field
 	err = ds.UpdateChunkCompactState(ctx, vuid, true)
 	if err != nil {
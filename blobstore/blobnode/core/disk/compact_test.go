@@ -29,6 +29,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/blobnode/core"
 	"github.com/cubefs/cubefs/blobstore/blobnode/core/chunk"
 	db2 "github.com/cubefs/cubefs/blobstore/blobnode/db"
+	bloberr "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 	"github.com/cubefs/cubefs/blobstore/util/log"
@@ -195,3 +196,54 @@ func TestExecCompactChunk(t *testing.T) {
 	err = ds.UpdateChunkCompactState(ctx, proto.Vuid(2011), false)
 	require.Error(t, err)
 }
+
+func TestCompactStat(t *testing.T) {
+	testDir, err := os.MkdirTemp(os.TempDir(), "TestCompactStat")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	ctx := context.Background()
+
+	diskpath := filepath.Join(testDir, "DiskPath")
+
+	err = os.MkdirAll(diskpath, 0o755)
+	require.NoError(t, err)
+
+	diskConfig := core.Config{
+		BaseConfig: core.BaseConfig{
+			Path:       diskpath,
+			AutoFormat: true,
+		},
+		AllocDiskID:      getDiskIDFn,
+		NotifyCompacting: setChunkCompactFn,
+		HandleIOError:    handleIOErrorFn,
+	}
+	ds, err := NewDiskStorage(ctx, diskConfig)
+	require.NoError(t, err)
+	require.NotNil(t, ds)
+	defer ds.ResetChunks(ctx)
+
+	vuid := proto.Vuid(2001)
+	cs, err := ds.CreateChunk(context.TODO(), vuid, core.DefaultChunkSize)
+	require.NoError(t, err)
+	require.NotNil(t, cs)
+
+	stat := ds.CompactStat(ctx)
+	require.Equal(t, ds.DiskID, stat.DiskID)
+	require.Equal(t, core.DefaultCompactConcurrency, stat.Concurrency)
+	require.Equal(t, 0, len(stat.Running))
+
+	// the same chunk is not compacted concurrently
+	require.True(t, ds.startCompacting(vuid))
+	require.False(t, ds.startCompacting(vuid))
+	require.ErrorIs(t, ds.ExecCompactChunk(vuid), bloberr.ErrChunkInCompact)
+
+	stat = ds.CompactStat(ctx)
+	require.Equal(t, 1, len(stat.Running))
+	require.Equal(t, vuid, stat.Running[0].Vuid)
+	require.Equal(t, cs.ID(), stat.Running[0].ChunkID)
+
+	ds.finishCompacting(vuid)
+	require.False(t, ds.isCompacting(vuid))
+	require.Equal(t, 0, len(ds.CompactStat(ctx).Running))
+}
@@ -90,6 +90,9 @@ type DiskStorage struct {
 	compactCh chan proto.Vuid
 	closeCh   chan struct{}
 
+	compactLock sync.Mutex
+	compactings map[proto.Vuid]struct{}
+
 	// ctx is used for initiated requests that
 	// may need to be canceled on server shutdown.
 	wg  sync.WaitGroup
@@ -203,6 +206,8 @@ func (ds *DiskStorage) DiskInfo() (info bnapi.DiskInfo) {
 	info.Rack = hostInfo.Rack
 	info.Host = hostInfo.Host
 	info.Path = ds.Conf.Path
+	info.DiskSetID = ds.Conf.DiskSetID
+	info.Cold = ds.Conf.Cold
 
 	// status
 	info.Status = ds.status
@@ -510,6 +515,7 @@ func newDiskStorage(ctx context.Context, conf core.Config) (ds *DiskStorage, err
 		Conf:             &conf,
 		closeCh:          make(chan struct{}),
 		compactCh:        make(chan proto.Vuid),
+		compactings:      make(map[proto.Vuid]struct{}),
 		ctx:              ctx,
 		status:           dm.Status,
 		isMountPoint:     myos.IsMountPoint(conf.Path),
@@ -140,6 +140,7 @@ type ChunkAPI interface {
 	CommitCompact(ctx context.Context, ncs ChunkAPI) (err error)
 	StopCompact(ctx context.Context, ncs ChunkAPI) (err error)
 	NeedCompact(ctx context.Context) bool
+	CompactProgress() (progress *bnapi.CompactProgress)
 	IsDirty() bool
 	IsClosed() bool
 	AllowModify() (err error)
@@ -168,6 +169,7 @@ type DiskAPI interface {
 	UpdateChunkCompactState(ctx context.Context, vuid proto.Vuid, compacting bool) (err error)
 	ListChunks(ctx context.Context) (chunks []VuidMeta, err error)
 	EnqueueCompact(ctx context.Context, vuid proto.Vuid)
+	CompactStat(ctx context.Context) (stat bnapi.CompactStat)
 	GcRubbishChunk(ctx context.Context) (mayBeLost []bnapi.ChunkId, err error)
 	WalkChunksWithLock(ctx context.Context, fn func(cs ChunkAPI) error) (err error)
 	ResetChunks(ctx context.Context)
@@ -51,6 +51,20 @@ func (mr *MockDiskAPIMockRecorder) Close(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDiskAPI)(nil).Close), arg0)
 }
 
+// CompactStat mocks base method.
+func (m *MockDiskAPI) CompactStat(arg0 context.Context) blobnode.CompactStat {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactStat", arg0)
+	ret0, _ := ret[0].(blobnode.CompactStat)
+	return ret0
+}
+
+// CompactStat indicates an expected call of CompactStat.
+func (mr *MockDiskAPIMockRecorder) CompactStat(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactStat", reflect.TypeOf((*MockDiskAPI)(nil).CompactStat), arg0)
+}
+
 // CreateChunk mocks base method.
 func (m *MockDiskAPI) CreateChunk(arg0 context.Context, arg1 proto.Vuid, arg2 int64) (core.ChunkAPI, error) {
 	m.ctrl.T.Helper()
@@ -734,6 +748,20 @@ func (mr *MockChunkAPIMockRecorder) CommitCompact(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitCompact", reflect.TypeOf((*MockChunkAPI)(nil).CommitCompact), arg0, arg1)
 }
 
+// CompactProgress mocks base method.
+func (m *MockChunkAPI) CompactProgress() *blobnode.CompactProgress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactProgress")
+	ret0, _ := ret[0].(*blobnode.CompactProgress)
+	return ret0
+}
+
+// CompactProgress indicates an expected call of CompactProgress.
+func (mr *MockChunkAPIMockRecorder) CompactProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactProgress", reflect.TypeOf((*MockChunkAPI)(nil).CompactProgress))
+}
+
 // Delete mocks base method.
 func (m *MockChunkAPI) Delete(arg0 context.Context, arg1 proto.BlobID) error {
 	m.ctrl.T.Helper()
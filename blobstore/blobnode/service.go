@@ -35,14 +35,19 @@ var (
 
 func init() {
 	mod := &cmd.Module{
-		Name:       "BLOBNODE",
-		InitConfig: initConfig,
-		SetUp:      setUp,
-		TearDown:   tearDown,
+		Name:        "BLOBNODE",
+		InitConfig:  initConfig,
+		SetUp:       setUp,
+		TearDown:    tearDown,
+		HealthCheck: healthCheck,
 	}
 	cmd.RegisterModule(mod)
 }
 
+func healthCheck() error {
+	return gService.HealthCheck()
+}
+
 func initConfig(args []string) (cfg *cmd.Config, err error) {
 	config.Init("f", "", "blobnode.conf")
 
@@ -109,6 +114,7 @@ func NewHandler(service *Service) *rpc.Router {
 	rpc.RegisterArgsParser(&bnapi.ListChunkArgs{}, "json")
 	rpc.RegisterArgsParser(&bnapi.StatChunkArgs{}, "json")
 	rpc.RegisterArgsParser(&bnapi.CompactChunkArgs{}, "json")
+	rpc.RegisterArgsParser(&bnapi.CompactStatArgs{}, "json")
 	rpc.RegisterArgsParser(&bnapi.ChunkInspectArgs{}, "json")
 
 	rpc.RegisterArgsParser(&bnapi.GetShardArgs{}, "json")
@@ -133,6 +139,7 @@ func NewHandler(service *Service) *rpc.Router {
 	r.Handle(http.MethodGet, "/chunk/list/diskid/:diskid", service.ChunkList, rpc.OptArgsURI())
 	r.Handle(http.MethodGet, "/chunk/stat/diskid/:diskid/vuid/:vuid", service.ChunkStat, rpc.OptArgsURI())
 	r.Handle(http.MethodPost, "/chunk/compact/diskid/:diskid/vuid/:vuid", service.ChunkCompact, rpc.OptArgsURI())
+	r.Handle(http.MethodGet, "/chunk/compact/stat/diskid/:diskid", service.ChunkCompactStat, rpc.OptArgsURI())
 
 	r.Handle(http.MethodGet, "/shard/get/diskid/:diskid/vuid/:vuid/bid/:bid", service.ShardGet, rpc.OptArgsURI(), rpc.OptArgsQuery())
 	r.Handle(http.MethodGet, "/shard/list/diskid/:diskid/vuid/:vuid/startbid/:startbid/status/:status/count/:count", service.ShardList, rpc.OptArgsURI())
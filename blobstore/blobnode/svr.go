@@ -16,6 +16,7 @@ package blobnode
 
 import (
 	"context"
+	"errors"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -218,6 +219,21 @@ func (s *Service) DiskStat(c *rpc.Context) {
 	c.RespondJSON(&info)
 }
 
+// HealthCheck returns error if the service is closed or has no normal disk
+func (s *Service) HealthCheck() error {
+	select {
+	case <-s.closeCh:
+		return errors.New("service is closed")
+	default:
+	}
+	for _, ds := range s.copyDiskStorages(s.ctx) {
+		if ds.Status() == proto.DiskStatusNormal {
+			return nil
+		}
+	}
+	return errors.New("no normal disk")
+}
+
 func (s *Service) copyDiskStorages(ctx context.Context) []core.DiskAPI {
 	disks := make([]core.DiskAPI, 0)
 	s.lock.RLock()
@@ -174,6 +174,7 @@ type TaskRunner struct {
 	idc    string
 
 	taskletRunConcurrency int
+	pipelineBuffer        int
 	state                 taskState
 
 	ctx    context.Context
@@ -190,7 +191,7 @@ type TaskRunner struct {
 
 // NewTaskRunner return task runner
 func NewTaskRunner(ctx context.Context, taskID string, w ITaskWorker, idc string,
-	taskletRunConcurrency int, taskCounter *taskCounter, schedulerCli scheduler.IMigrator) *TaskRunner {
+	taskletRunConcurrency, pipelineBuffer int, taskCounter *taskCounter, schedulerCli scheduler.IMigrator) *TaskRunner {
 	span, ctx := trace.StartSpanFromContext(ctx, "taskRunner")
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -199,6 +200,7 @@ func NewTaskRunner(ctx context.Context, taskID string, w ITaskWorker, idc string
 		w:                     w,
 		idc:                   idc,
 		taskletRunConcurrency: taskletRunConcurrency,
+		pipelineBuffer:        pipelineBuffer,
 		ctx:                   ctx,
 		cancel:                cancel,
 		span:                  span,
@@ -232,22 +234,12 @@ func (r *TaskRunner) Run() {
 	r.stats.Do(migratedDataSize, migratedShardCnt)
 	r.statsAndReportTask(0, 0)
 
-	// all tasks are put into the task pool at one time to be executed
 	span.Infof("start exec task: taskID[%s], tasklets len[%d]", r.taskID, len(tasklets))
-	taskletsPool := taskpool.New(r.taskletRunConcurrency, len(tasklets))
-	wg := sync.WaitGroup{}
-	for i, t := range tasklets {
-		tasklet := t
-		_, ctx := trace.StartSpanFromContextWithTraceID(r.ctx, "execTaskletWrap", fmt.Sprintf("%s-%d", span.TraceID(), i))
-		wg.Add(1)
-
-		taskletsPool.Run(func() {
-			r.execTaskletWrap(ctx, tasklet)
-			wg.Done()
-		})
+	if w, ok := r.w.(IPipelineWorker); ok {
+		r.runPipeline(w, tasklets)
+	} else {
+		r.runTasklets(tasklets)
 	}
-	wg.Wait()
-	taskletsPool.Close()
 	r.cancel()
 	span.Infof("all tasklets has finished: taskID[%s]", r.taskID)
 
@@ -273,6 +265,24 @@ func (r *TaskRunner) Run() {
 	span.Infof("task Runner finish: taskID[%s]", r.taskID)
 }
 
+// runTasklets puts all tasklets into the task pool at one time to be executed
+func (r *TaskRunner) runTasklets(tasklets []Tasklet) {
+	taskletsPool := taskpool.New(r.taskletRunConcurrency, len(tasklets))
+	wg := sync.WaitGroup{}
+	for i, t := range tasklets {
+		tasklet := t
+		_, ctx := trace.StartSpanFromContextWithTraceID(r.ctx, "execTaskletWrap", fmt.Sprintf("%s-%d", r.span.TraceID(), i))
+		wg.Add(1)
+
+		taskletsPool.Run(func() {
+			r.execTaskletWrap(ctx, tasklet)
+			wg.Done()
+		})
+	}
+	wg.Wait()
+	taskletsPool.Close()
+}
+
 func (r *TaskRunner) execTaskletWrap(ctx context.Context, t Tasklet) {
 	select {
 	case <-r.ctx.Done():
@@ -61,6 +61,7 @@ func NewTaskRunnerMgr(idc string, meter WorkerConfigMeter, genWorker WorkerGener
 			proto.TaskTypeDiskDrop:      make(mapTaskRunner),
 			proto.TaskTypeDiskRepair:    make(mapTaskRunner),
 			proto.TaskTypeManualMigrate: make(mapTaskRunner),
+			proto.TaskTypeColdMigrate:   make(mapTaskRunner),
 		},
 
 		idc:          idc,
@@ -133,7 +134,7 @@ func (tm *TaskRunnerMgr) AddTask(ctx context.Context, task MigrateTaskEx) error
 
 	w := tm.genWorker(task)
 	concurrency := tm.meter.concurrencyByType(t.TaskType)
-	runner := NewTaskRunner(ctx, t.TaskID, w, t.SourceIDC, concurrency, &tm.taskCounter, tm.schedulerCli)
+	runner := NewTaskRunner(ctx, t.TaskID, w, t.SourceIDC, concurrency, tm.meter.TaskletPipelineBuffer, &tm.taskCounter, tm.schedulerCli)
 	if err := mgr.addTask(t.TaskID, runner); err != nil {
 		return err
 	}
@@ -17,6 +17,8 @@ package blobnode
 import (
 	"context"
 	"errors"
+	"hash/crc32"
+	"math/rand"
 	"sync"
 
 	"github.com/cubefs/cubefs/blobstore/api/scheduler"
@@ -31,6 +33,9 @@ import (
 // ErrNotReadyForMigrate not ready for migrate
 var ErrNotReadyForMigrate = errors.New("not ready for migrate")
 
+// max shards sampled to re-read from destination to verify crc
+const crcVerifySampleSize = 64
+
 type chunkState struct {
 	retErr    error
 	chunkInfo *client.ChunkInfo
@@ -44,6 +49,13 @@ type MigrateWorker struct {
 	benchmarkBids            []*ShardInfoSimple
 	downloadShardConcurrency int
 	forbiddenDirectDownload  bool
+	verifyRepairCrc          bool
+
+	// expected crc of sampled shards written to destination
+	crcMu         sync.Mutex
+	writtenCrcs   map[proto.BlobID]uint32
+	sampledBids   []proto.BlobID
+	writtenShards int64
 }
 
 // MigrateTaskEx migrate task execution machine
@@ -51,6 +63,7 @@ type MigrateTaskEx struct {
 	taskInfo *proto.MigrateTask
 
 	downloadShardConcurrency int
+	verifyRepairCrc          bool
 	blobNodeCli              client.IBlobNode
 }
 
@@ -61,6 +74,8 @@ func NewMigrateWorker(task MigrateTaskEx) ITaskWorker {
 		bolbNodeCli:              task.blobNodeCli,
 		downloadShardConcurrency: task.downloadShardConcurrency,
 		forbiddenDirectDownload:  task.taskInfo.ForbiddenDirectDownload,
+		verifyRepairCrc:          task.verifyRepairCrc,
+		writtenCrcs:              make(map[proto.BlobID]uint32, crcVerifySampleSize),
 	}
 }
 
@@ -104,23 +119,122 @@ func (w *MigrateWorker) GenTasklets(ctx context.Context) ([]Tasklet, *WorkError)
 
 // ExecTasklet execute migrate tasklet
 func (w *MigrateWorker) ExecTasklet(ctx context.Context, tasklet Tasklet) *WorkError {
-	replicas := w.t.Sources
-	mode := w.t.CodeMode
-	shardRecover := NewShardRecover(replicas, mode, tasklet.bids, w.bolbNodeCli, w.downloadShardConcurrency, w.t.TaskType)
-	defer shardRecover.ReleaseBuf()
+	t := w.NewStagedTasklet(tasklet)
+	defer t.Release()
+
+	for _, stage := range []func(context.Context) *WorkError{t.Download, t.Decode, t.Upload} {
+		if err := stage(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewStagedTasklet returns migrate tasklet executed in stages
+func (w *MigrateWorker) NewStagedTasklet(tasklet Tasklet) StagedTasklet {
+	return &migrateTasklet{
+		w:    w,
+		bids: tasklet.bids,
+		shardRecover: NewShardRecover(w.t.Sources, w.t.CodeMode, tasklet.bids, w.bolbNodeCli,
+			w.downloadShardConcurrency, w.t.TaskType),
+	}
+}
+
+// migrateTasklet downloads shards of bids, decodes the bids not downloaded directly
+// and uploads the shards to destination
+type migrateTasklet struct {
+	w            *MigrateWorker
+	bids         []*ShardInfoSimple
+	shardRecover *ShardRecover
+	decodeBids   []proto.BlobID
+}
+
+func (t *migrateTasklet) Download(ctx context.Context) *WorkError {
+	span := trace.SpanFromContextSafe(ctx)
+	span.Infof("download shards: len bids[%d]", len(t.bids))
+
+	bids, err := t.shardRecover.DownloadShards(ctx, []uint8{t.w.t.SourceVuid.Index()}, t.w.canDirectDownload())
+	if err != nil {
+		return SrcError(err)
+	}
+	t.decodeBids = bids
+	return nil
+}
+
+func (t *migrateTasklet) Decode(ctx context.Context) *WorkError {
+	if len(t.decodeBids) == 0 {
+		return nil
+	}
+	span := trace.SpanFromContextSafe(ctx)
+	span.Infof("decode shards: len bids[%d]", len(t.decodeBids))
 
-	return MigrateBids(ctx,
-		shardRecover,
-		w.t.SourceVuid.Index(),
-		w.t.Destination,
-		w.canDirectDownload(),
-		tasklet.bids,
-		w.bolbNodeCli)
+	if err := t.shardRecover.DecodeShards(ctx, []uint8{t.w.t.SourceVuid.Index()}, t.decodeBids); err != nil {
+		return SrcError(err)
+	}
+	return nil
+}
+
+func (t *migrateTasklet) Upload(ctx context.Context) *WorkError {
+	if err := PutShards(ctx, t.shardRecover, t.w.t.Destination, t.bids, t.w.bolbNodeCli); err != nil {
+		return err
+	}
+	t.w.recordWrittenCrcs(t.shardRecover, t.bids)
+	return nil
+}
+
+func (t *migrateTasklet) Release() {
+	t.shardRecover.ReleaseBuf()
+}
+
+// recordWrittenCrcs keeps crc of a uniform sample of written shards by reservoir sampling
+func (w *MigrateWorker) recordWrittenCrcs(shardRecover *ShardRecover, bids []*ShardInfoSimple) {
+	destIdx := w.t.Destination.Vuid.Index()
+	w.crcMu.Lock()
+	defer w.crcMu.Unlock()
+	for _, bid := range bids {
+		data, err := shardRecover.GetShard(destIdx, bid.Bid)
+		if err != nil {
+			continue
+		}
+		w.writtenShards++
+		if len(w.sampledBids) < crcVerifySampleSize {
+			w.sampledBids = append(w.sampledBids, bid.Bid)
+			w.writtenCrcs[bid.Bid] = crc32.ChecksumIEEE(data)
+			continue
+		}
+		if i := rand.Int63n(w.writtenShards); i < crcVerifySampleSize {
+			delete(w.writtenCrcs, w.sampledBids[i])
+			w.sampledBids[i] = bid.Bid
+			w.writtenCrcs[bid.Bid] = crc32.ChecksumIEEE(data)
+		}
+	}
 }
 
 // Check checks migrate task execute result
 func (w *MigrateWorker) Check(ctx context.Context) *WorkError {
-	return CheckVunit(ctx, w.benchmarkBids, w.t.Destination, w.bolbNodeCli)
+	span := trace.SpanFromContextSafe(ctx)
+
+	if err := CheckVunit(ctx, w.benchmarkBids, w.t.Destination, w.bolbNodeCli); err != nil {
+		return err
+	}
+
+	w.crcMu.Lock()
+	defer w.crcMu.Unlock()
+	if len(w.writtenCrcs) == 0 {
+		return nil
+	}
+	err := CheckVunitCrc(ctx, w.writtenCrcs, w.t.Destination, w.bolbNodeCli)
+	if err != nil && !w.crcVerifyMandatory() {
+		span.Warnf("check destination crc failed and ignore: taskID[%s], taskType[%s], err[%+v]",
+			w.t.TaskID, w.t.TaskType, err.err)
+		return nil
+	}
+	return err
+}
+
+// crcVerifyMandatory returns true if crc mismatch of destination shards should fail the task
+func (w *MigrateWorker) crcVerifyMandatory() bool {
+	return w.verifyRepairCrc && w.t.TaskType == proto.TaskTypeDiskRepair
 }
 
 // GetBenchmarkBids returns benchmark bids
@@ -300,6 +300,90 @@ func TestMigrateCheck(t *testing.T) {
 	}
 }
 
+func TestMigrateCheckCrc(t *testing.T) {
+	mode := codemode.EC6P6
+	replicas := genMockVol(100, codemode.CodeMode(mode))
+	badi := 3
+	repairTask := &proto.MigrateTask{
+		TaskID:      "mock_repair_task_id",
+		TaskType:    proto.TaskTypeDiskRepair,
+		CodeMode:    codemode.CodeMode(mode),
+		Sources:     replicas,
+		Destination: replicas[badi],
+		SourceVuid:  replicas[badi].Vuid,
+	}
+	bids := []proto.BlobID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	sizes := []int64{1024, 2048, 0, 512, 23, 65, 12, 50, 100, 2047}
+
+	workutils.TaskBufPool = workutils.NewBufPool(&workutils.BufConfig{
+		MigrateBufSize:     2 * 1024,
+		MigrateBufCapacity: 100,
+		RepairBufSize:      1,
+		RepairBufCapacity:  1,
+	})
+	getter := NewMockGetterWithBids(replicas, codemode.CodeMode(mode), bids, sizes)
+	shards, _ := getter.ListShards(context.Background(), replicas[badi])
+	for _, shard := range shards {
+		getter.Delete(context.Background(), replicas[badi].Vuid, shard.Bid)
+	}
+
+	w := NewMigrateWorker(MigrateTaskEx{taskInfo: repairTask, blobNodeCli: getter, downloadShardConcurrency: 1, verifyRepairCrc: true})
+	tasklets, werr := w.GenTasklets(context.Background())
+	require.Nil(t, werr)
+	for _, tasklet := range tasklets {
+		require.Nil(t, w.ExecTasklet(context.Background(), tasklet))
+	}
+	migrateWorker := w.(*MigrateWorker)
+	require.Equal(t, len(bids), len(migrateWorker.writtenCrcs))
+	require.Nil(t, w.Check(context.Background()))
+
+	// crc mismatch fails the repair task
+	migrateWorker.writtenCrcs[bids[0]]++
+	werr = w.Check(context.Background())
+	require.NotNil(t, werr)
+	require.Equal(t, DstErr, werr.errType)
+	require.ErrorIs(t, werr.err, ErrBidCrcNotMatch)
+
+	// crc mismatch is ignored if verification is not mandatory
+	migrateWorker.verifyRepairCrc = false
+	require.Nil(t, w.Check(context.Background()))
+	migrateWorker.verifyRepairCrc = true
+	migrateWorker.t.TaskType = proto.TaskTypeBalance
+	require.Nil(t, w.Check(context.Background()))
+
+	// data re-read from destination is verified
+	migrateWorker.t.TaskType = proto.TaskTypeDiskRepair
+	migrateWorker.writtenCrcs[bids[0]]--
+	require.Nil(t, w.Check(context.Background()))
+	getter.vunits[replicas[badi].Vuid].shards[bids[0]][0]++
+	werr = w.Check(context.Background())
+	require.NotNil(t, werr)
+	require.ErrorIs(t, werr.err, ErrBidCrcNotMatch)
+
+	// crc of a sample of shards is kept
+	bids, sizes = make([]proto.BlobID, 0, 200), make([]int64, 0, 200)
+	for i := 1; i <= 200; i++ {
+		bids = append(bids, proto.BlobID(i))
+		sizes = append(sizes, 10)
+	}
+	getter = NewMockGetterWithBids(replicas, codemode.CodeMode(mode), bids, sizes)
+	shards, _ = getter.ListShards(context.Background(), replicas[badi])
+	for _, shard := range shards {
+		getter.Delete(context.Background(), replicas[badi].Vuid, shard.Bid)
+	}
+	w = NewMigrateWorker(MigrateTaskEx{taskInfo: repairTask, blobNodeCli: getter, downloadShardConcurrency: 1, verifyRepairCrc: true})
+	tasklets, werr = w.GenTasklets(context.Background())
+	require.Nil(t, werr)
+	for _, tasklet := range tasklets {
+		require.Nil(t, w.ExecTasklet(context.Background(), tasklet))
+	}
+	migrateWorker = w.(*MigrateWorker)
+	require.Equal(t, int64(len(bids)), migrateWorker.writtenShards)
+	require.Len(t, migrateWorker.writtenCrcs, crcVerifySampleSize)
+	require.Len(t, migrateWorker.sampledBids, crcVerifySampleSize)
+	require.Nil(t, w.Check(context.Background()))
+}
+
 func TestMigrateArgs(t *testing.T) {
 	mode := codemode.EC15P12
 	replicas := genMockVol(100, mode)
@@ -0,0 +1,175 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package blobnode
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+)
+
+// tasklet pipeline stages
+const (
+	StageDownload = "download"
+	StageDecode   = "decode"
+	StageUpload   = "upload"
+)
+
+var (
+	taskletStageDurationMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "blobstore",
+			Subsystem: "blobnode",
+			Name:      "tasklet_stage_duration_ms",
+			Help:      "blobnode duration of tasklet pipeline stage",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{"task_type", "stage"},
+	)
+	taskletStageBytesMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "blobstore",
+			Subsystem: "blobnode",
+			Name:      "tasklet_stage_bytes",
+			Help:      "blobnode data size of tasklets passed pipeline stage",
+		},
+		[]string{"task_type", "stage"},
+	)
+	taskletStageErrorMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "blobstore",
+			Subsystem: "blobnode",
+			Name:      "tasklet_stage_error",
+			Help:      "blobnode failed count of tasklet pipeline stage",
+		},
+		[]string{"task_type", "stage"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(taskletStageDurationMetric, taskletStageBytesMetric, taskletStageErrorMetric)
+}
+
+// StagedTasklet tasklet executed by download, decode and upload stages,
+// the stages of different tasklets can run at the same time
+type StagedTasklet interface {
+	// download shards of tasklet from sources
+	Download(ctx context.Context) *WorkError
+	// recover the shards not downloaded
+	Decode(ctx context.Context) *WorkError
+	// put the shards to destination
+	Upload(ctx context.Context) *WorkError
+	// release buffers of tasklet, must be called at last whether stages failed or not
+	Release()
+}
+
+// IPipelineWorker task worker whose tasklets are executed in pipeline instead of one by one
+type IPipelineWorker interface {
+	ITaskWorker
+	NewStagedTasklet(t Tasklet) StagedTasklet
+}
+
+type taskletStage struct {
+	name string
+	exec func(t StagedTasklet, ctx context.Context) *WorkError
+}
+
+var taskletStages = []taskletStage{
+	{name: StageDownload, exec: StagedTasklet.Download},
+	{name: StageDecode, exec: StagedTasklet.Decode},
+	{name: StageUpload, exec: StagedTasklet.Upload},
+}
+
+type pipelineTasklet struct {
+	StagedTasklet
+	ctx     context.Context
+	tasklet Tasklet
+}
+
+// runPipeline runs every stage with taskletRunConcurrency goroutines, stages are connected by channels
+// buffering pipelineBuffer tasklets, so at most 3*taskletRunConcurrency+2*pipelineBuffer tasklets hold buffers
+func (r *TaskRunner) runPipeline(w IPipelineWorker, tasklets []Tasklet) {
+	input := make(chan *pipelineTasklet, r.pipelineBuffer)
+	go func() {
+		defer close(input)
+		for i, t := range tasklets {
+			_, ctx := trace.StartSpanFromContextWithTraceID(r.ctx, "execTaskletPipeline", fmt.Sprintf("%s-%d", r.span.TraceID(), i))
+			tasklet := &pipelineTasklet{StagedTasklet: w.NewStagedTasklet(t), ctx: ctx, tasklet: t}
+			select {
+			case <-r.ctx.Done():
+				r.span.Infof("tasklet canceled: taskID[%s]", r.taskID)
+				tasklet.Release()
+				return
+			case input <- tasklet:
+			}
+		}
+	}()
+
+	in := input
+	for _, stage := range taskletStages {
+		out := make(chan *pipelineTasklet, r.pipelineBuffer)
+		go r.runStage(stage, in, out)
+		in = out
+	}
+	for t := range in {
+		t.Release()
+		r.statsAndReportTask(t.tasklet.DataSizeByte(), uint64(len(t.tasklet.bids)))
+	}
+}
+
+func (r *TaskRunner) runStage(stage taskletStage, in <-chan *pipelineTasklet, out chan<- *pipelineTasklet) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < r.taskletRunConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range in {
+				if !r.execStage(stage, t) {
+					t.Release()
+					continue
+				}
+				out <- t
+			}
+		}()
+	}
+	wg.Wait()
+	close(out)
+}
+
+func (r *TaskRunner) execStage(stage taskletStage, t *pipelineTasklet) bool {
+	select {
+	case <-r.ctx.Done():
+		return false
+	default:
+	}
+
+	taskType := r.w.TaskType().String()
+	start := time.Now()
+	err := stage.exec(t.StagedTasklet, t.ctx)
+	taskletStageDurationMetric.WithLabelValues(taskType, stage.name).Observe(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		taskletStageErrorMetric.WithLabelValues(taskType, stage.name).Inc()
+		r.span.Errorf("tasklet stage failed: taskID[%s], stage[%s], err[%+v]", r.taskID, stage.name, err)
+		r.stopWithFail(err)
+		return false
+	}
+	taskletStageBytesMetric.WithLabelValues(taskType, stage.name).Add(float64(t.tasklet.DataSizeByte()))
+	return true
+}
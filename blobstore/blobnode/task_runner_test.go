@@ -121,7 +121,7 @@ func TestTaskRunner(t *testing.T) {
 	stats := &mockStats{}
 	cli := newMockSchedulerCli(t, stats)
 	run := func(worker ITaskWorker) {
-		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 3, &taskCounter{}, cli)
+		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 3, 1, &taskCounter{}, cli)
 		stats.step = ""
 		stats.wg.Add(1)
 		go runner.Run()
@@ -132,7 +132,7 @@ func TestTaskRunner(t *testing.T) {
 		log.Info("start test tasklet stop")
 		blocking := make(chan struct{})
 		worker := &mockWorker{blocking: blocking}
-		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 2, &taskCounter{}, cli)
+		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 2, 1, &taskCounter{}, cli)
 		stats.step = ""
 		stats.wg.Add(1)
 		go runner.Run()
@@ -150,7 +150,7 @@ func TestTaskRunner(t *testing.T) {
 		log.Info("start test tasklet fail")
 		blocking := make(chan struct{})
 		worker := &mockWorker{blocking: blocking, taskRetErr: errors.New("mock fail")}
-		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 3, &taskCounter{}, cli)
+		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 3, 1, &taskCounter{}, cli)
 		stats.step = ""
 		stats.wg.Add(1)
 		go runner.Run()
@@ -196,6 +196,95 @@ func TestTaskRunner(t *testing.T) {
 	}
 }
 
+type mockStagedTasklet struct {
+	w    *mockPipelineWorker
+	bid  proto.BlobID
+	done []string
+}
+
+func (t *mockStagedTasklet) exec(stage string) *WorkError {
+	t.done = append(t.done, stage)
+	if err, ok := t.w.stageErrs[stage]; ok && t.bid == t.w.failBid {
+		return err
+	}
+	return nil
+}
+
+func (t *mockStagedTasklet) Download(ctx context.Context) *WorkError { return t.exec(StageDownload) }
+func (t *mockStagedTasklet) Decode(ctx context.Context) *WorkError   { return t.exec(StageDecode) }
+func (t *mockStagedTasklet) Upload(ctx context.Context) *WorkError   { return t.exec(StageUpload) }
+func (t *mockStagedTasklet) Release() {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+	t.w.released = append(t.w.released, t)
+}
+
+type mockPipelineWorker struct {
+	mockWorker
+	failBid   proto.BlobID
+	stageErrs map[string]*WorkError
+
+	mu       sync.Mutex
+	created  int
+	released []*mockStagedTasklet
+}
+
+func (w *mockPipelineWorker) NewStagedTasklet(t Tasklet) StagedTasklet {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.created++
+	return &mockStagedTasklet{w: w, bid: t.bids[0].Bid}
+}
+
+func TestTaskRunnerPipeline(t *testing.T) {
+	taskID := "test_mock_task"
+	idc := "z0"
+	stats := &mockStats{}
+	cli := newMockSchedulerCli(t, stats)
+	run := func(worker *mockPipelineWorker) {
+		runner := NewTaskRunner(context.Background(), taskID, worker, idc, 2, 1, &taskCounter{}, cli)
+		stats.step = ""
+		stats.wg.Add(1)
+		go runner.Run()
+		stats.wg.Wait()
+		require.Equal(t, worker.created, len(worker.released))
+	}
+	// all tasklets complete
+	{
+		worker := &mockPipelineWorker{}
+		run(worker)
+		require.Equal(t, "Complete", stats.step)
+		require.Equal(t, 12, len(worker.released))
+		for _, tasklet := range worker.released {
+			require.Equal(t, []string{StageDownload, StageDecode, StageUpload}, tasklet.done)
+		}
+	}
+	// decode failed
+	{
+		worker := &mockPipelineWorker{failBid: 3, stageErrs: map[string]*WorkError{StageDecode: SrcError(errors.New("mock decode fail"))}}
+		run(worker)
+		require.Equal(t, "Cancel", stats.step)
+		for _, tasklet := range worker.released {
+			if tasklet.bid == 3 {
+				require.Equal(t, []string{StageDownload, StageDecode}, tasklet.done)
+			}
+		}
+	}
+	// upload failed
+	{
+		worker := &mockPipelineWorker{failBid: 1, stageErrs: map[string]*WorkError{StageUpload: DstError(errors.New("mock upload fail"))}}
+		run(worker)
+		require.Equal(t, "Reclaim", stats.step)
+	}
+	// check failed
+	{
+		worker := &mockPipelineWorker{mockWorker: mockWorker{checkRetErr: errors.New("mock check fail")}}
+		run(worker)
+		require.Equal(t, "Cancel", stats.step)
+		require.Equal(t, 12, len(worker.released))
+	}
+}
+
 func TestTaskState(t *testing.T) {
 	s := taskState{}
 	s.set(TaskRunning)
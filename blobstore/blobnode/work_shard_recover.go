@@ -20,6 +20,7 @@ import (
 	"hash/crc32"
 	"io"
 	"math/rand"
+	"sort"
 	"sync"
 	"unsafe"
 
@@ -103,7 +104,9 @@ func (locs Vunits) IntactGlobalSet(mode codemode.CodeMode, bad []uint8) Vunits {
 	return locs.Subset(idxes)
 }
 
-func (stripe *repairStripe) genDownloadPlans() []downloadPlan {
+// genDownloadPlans generates download plans of stripe, replicas are ordered by the random
+// download order of shard recover, so the plans of the same stripe are always the same
+func (r *ShardRecover) genDownloadPlans(stripe repairStripe) []downloadPlan {
 	badi := stripe.badIdxes
 	n := stripe.n
 	var downloadPlans []downloadPlan
@@ -111,8 +114,8 @@ func (stripe *repairStripe) genDownloadPlans() []downloadPlan {
 
 	stripeReplicas := make([]proto.VunitLocation, len(stripe.replicas))
 	copy(stripeReplicas, stripe.replicas)
-	rand.Shuffle(len(stripeReplicas), func(i, j int) {
-		stripeReplicas[i], stripeReplicas[j] = stripeReplicas[j], stripeReplicas[i]
+	sort.Slice(stripeReplicas, func(i, j int) bool {
+		return r.downloadOrder[stripeReplicas[i].Vuid.Index()] < r.downloadOrder[stripeReplicas[j].Vuid.Index()]
 	})
 
 	badMap := make(map[uint8]struct{})
@@ -394,6 +397,7 @@ type ShardRecover struct {
 	ioType                   blobnode.IOType
 	taskType                 proto.TaskType
 	ds                       *downloadStatus
+	downloadOrder            []int // random download order of replicas
 }
 
 // NewShardRecover returns shard recover
@@ -415,15 +419,30 @@ func NewShardRecover(replicas Vunits, mode codemode.CodeMode, bidInfos []*ShardI
 		taskType:                 taskType,
 		vunitShardGetConcurrency: vunitShardGetConcurrency,
 		ds:                       newDownloadStatus(),
+		downloadOrder:            rand.Perm(len(replicas)),
 	}
 	return &repair
 }
 
 // RecoverShards recover shards
 func (r *ShardRecover) RecoverShards(ctx context.Context, repairIdxs []uint8, direct bool) error {
+	repairBids, err := r.DownloadShards(ctx, repairIdxs, direct)
+	if err != nil {
+		return err
+	}
+	if len(repairBids) == 0 {
+		return nil
+	}
+	return r.DecodeShards(ctx, repairIdxs, repairBids)
+}
+
+// DownloadShards downloads shards of repairIdxs directly if direct is true,
+// then downloads the shards of first download plans which the bids not downloaded are recovered by,
+// returns the bids need to be recovered by DecodeShards
+func (r *ShardRecover) DownloadShards(ctx context.Context, repairIdxs []uint8, direct bool) ([]proto.BlobID, error) {
 	span := trace.SpanFromContextSafe(ctx)
 	if !r.replicas.IsValid() {
-		return errInvalidReplicas
+		return nil, errInvalidReplicas
 	}
 
 	// direct download shard
@@ -433,10 +452,10 @@ func (r *ShardRecover) RecoverShards(ctx context.Context, repairIdxs []uint8, di
 		span.Debugf("recover shards by direct: bids len[%d]", len(repairBids))
 		repairBids, allocBufErr = r.directGetShard(ctx, repairBids, repairIdxs)
 		if allocBufErr != nil {
-			return allocBufErr
+			return nil, allocBufErr
 		}
 		if len(repairBids) == 0 {
-			return nil
+			return nil, nil
 		}
 		span.Debugf("need recover shards by ec: bids len[%d]", len(repairBids))
 	}
@@ -447,6 +466,27 @@ func (r *ShardRecover) RecoverShards(ctx context.Context, repairIdxs []uint8, di
 		r.ds.forbiddenDownload(repairVuid)
 	}
 
+	// the replicas downloaded here will be skipped when decoding,
+	// and the other replicas are downloaded by the next plans if decoding failed
+	stripes := []repairStripe{r.globalStripe(repairIdxs)}
+	if localRepairable(repairIdxs, r.codeMode) {
+		stripes, _ = r.genLocalStripes(repairIdxs)
+	}
+	for _, stripe := range stripes {
+		if err := r.allocBuf(ctx, stripe.replicas.Indexes()); err != nil {
+			return nil, err
+		}
+		if plans := r.genDownloadPlans(stripe); len(plans) > 0 {
+			r.download(ctx, repairBids, plans[0].downloadReplicas)
+		}
+	}
+	span.Infof("end download shards: repairIdxs[%+v], len repairBids[%d]", repairIdxs, len(repairBids))
+	return repairBids, nil
+}
+
+// DecodeShards recovers the bids by local stripe or global stripe, must be called after DownloadShards
+func (r *ShardRecover) DecodeShards(ctx context.Context, repairIdxs []uint8, repairBids []proto.BlobID) error {
+	span := trace.SpanFromContextSafe(ctx)
 	span.Infof("start recover shards: repairIdxs[%+v], len repairBidInfos[%d]", repairIdxs, len(r.repairBidsReadOnly))
 
 	err := r.recoverReplicaShards(ctx, repairIdxs, repairBids)
@@ -549,12 +589,7 @@ func (r *ShardRecover) recoverByGlobalStripe(ctx context.Context, repairBids []p
 	span := trace.SpanFromContextSafe(ctx)
 	span.Infof("start recoverByGlobalStripe: repairIdxs[%+v]", repairIdxs)
 
-	stripe := repairStripe{
-		replicas: r.replicas,
-		n:        r.codeMode.T().N,
-		m:        r.codeMode.T().M,
-		badIdxes: repairIdxs,
-	}
+	stripe := r.globalStripe(repairIdxs)
 	idxs := stripe.replicas.Indexes()
 	err = r.allocBuf(ctx, idxs)
 	if err != nil {
@@ -572,7 +607,7 @@ func (r *ShardRecover) repairStripe(ctx context.Context, repairBids []proto.Blob
 	// step1:gen download plans for repair
 	span := trace.SpanFromContextSafe(ctx)
 
-	downloadPlans := stripe.genDownloadPlans()
+	downloadPlans := r.genDownloadPlans(stripe)
 	span.Infof("start repairStripe: downloadPlans len[%d], len(repairBids)[%d]", len(downloadPlans), len(repairBids))
 	failBids := repairBids
 	// step2:download data according download plans and repair data
@@ -774,6 +809,15 @@ func (r *ShardRecover) repair(ctx context.Context, repairBids []proto.BlobID, st
 	return nil
 }
 
+func (r *ShardRecover) globalStripe(repairIdxs []uint8) repairStripe {
+	return repairStripe{
+		replicas: r.replicas,
+		n:        r.codeMode.T().N,
+		m:        r.codeMode.T().M,
+		badIdxes: repairIdxs,
+	}
+}
+
 func (r *ShardRecover) genLocalStripes(repairIdxs []uint8) (stripes []repairStripe, err error) {
 	// generate local stripes list in same az with repairIdxs
 	repairIdxsInIdc := workutils.IdxSplitByLocalStripe(repairIdxs, r.codeMode)
@@ -840,7 +884,8 @@ func (r *ShardRecover) allocBuf(ctx context.Context, vunitIdxs []uint8) error {
 			switch r.taskType {
 			case proto.TaskTypeShardRepair:
 				buf, err = workutils.TaskBufPool.GetRepairBuf()
-			case proto.TaskTypeDiskRepair, proto.TaskTypeBalance, proto.TaskTypeManualMigrate, proto.TaskTypeDiskDrop:
+			case proto.TaskTypeDiskRepair, proto.TaskTypeBalance, proto.TaskTypeManualMigrate, proto.TaskTypeDiskDrop,
+				proto.TaskTypeColdMigrate:
 				buf, err = workutils.TaskBufPool.GetMigrateBuf()
 			default:
 				err = errors.New("unknown type")
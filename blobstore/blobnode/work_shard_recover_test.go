@@ -238,6 +238,29 @@ func TestRecoverShards(t *testing.T) {
 	testCheckData(t, repair4, getter4, badi4)
 }
 
+func TestDownloadAndDecodeShards(t *testing.T) {
+	ctx := context.Background()
+	for _, cs := range []struct {
+		mode  codemode.CodeMode
+		badis []uint8
+	}{
+		{codemode.EC6P6, []uint8{0}},
+		{codemode.EC6P10L2, []uint8{6}},
+	} {
+		repair, bidInfos, getter, replicas := InitMockRepair(cs.mode)
+		bids, err := repair.DownloadShards(ctx, cs.badis, false)
+		require.NoError(t, err)
+		require.Equal(t, GetBids(bidInfos), bids)
+
+		// shards downloaded by the first plan are decoded without downloading again
+		for _, replica := range replicas {
+			getter.setFail(replica.Vuid, errors.New("fake error"))
+		}
+		require.NoError(t, repair.DecodeShards(ctx, cs.badis, bids))
+		testCheckData(t, repair, getter, cs.badis)
+	}
+}
+
 func TestRecoverShards2(t *testing.T) {
 	// test without local :eg EC6p6
 	ctx := context.Background()
@@ -17,7 +17,10 @@ package blobnode
 import (
 	"bytes"
 	"context"
+	"hash/crc32"
+	"io"
 
+	"github.com/cubefs/cubefs/blobstore/api/blobnode"
 	"github.com/cubefs/cubefs/blobstore/blobnode/client"
 	"github.com/cubefs/cubefs/blobstore/common/codemode"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
@@ -31,6 +34,8 @@ var (
 	ErrBidMissing = errors.New("bid is missing")
 	// ErrBidNotMatch bid not match
 	ErrBidNotMatch = errors.New("bid not match")
+	// ErrBidCrcNotMatch crc of bid in destination not match
+	ErrBidCrcNotMatch = errors.New("bid crc not match")
 )
 
 // GenMigrateBids generates migrate blob ids
@@ -73,21 +78,12 @@ func GenMigrateBids(ctx context.Context, blobnodeCli client.IBlobNode, srcReplic
 	return migBids, benchmarkBids, nil
 }
 
-// MigrateBids migrate the bids data to destination
-func MigrateBids(ctx context.Context, shardRecover *ShardRecover, badIdx uint8, destLocation proto.VunitLocation,
-	direct bool, bids []*ShardInfoSimple, blobnodeCli client.IBlobNode) *WorkError {
+// PutShards puts the recovered shards of bids to destination
+func PutShards(ctx context.Context, shardRecover *ShardRecover, destLocation proto.VunitLocation,
+	bids []*ShardInfoSimple, blobnodeCli client.IBlobNode) *WorkError {
 	span := trace.SpanFromContextSafe(ctx)
 
-	// step1 recover shards
-	span.Infof("recover shard: len bids[%d]", len(bids))
-
-	err := shardRecover.RecoverShards(ctx, []uint8{badIdx}, direct)
-	if err != nil {
-		return SrcError(err)
-	}
-
-	// put shards to dest
-	span.Infof("put data to destination: dest[%+v]", destLocation)
+	span.Infof("put data to destination: dest[%+v], len bids[%d]", destLocation, len(bids))
 	destIdx := destLocation.Vuid.Index()
 	for _, bid := range bids {
 		data, err := shardRecover.GetShard(destIdx, bid.Bid)
@@ -134,3 +130,58 @@ func CheckVunit(ctx context.Context, expectBids []*ShardInfoSimple, dest proto.V
 	}
 	return nil
 }
+
+// CheckVunitCrc checks crc of shards in destination, both the crc recorded by destination
+// and the crc of data re-read from destination should be the expected one
+func CheckVunitCrc(ctx context.Context, expectCrcs map[proto.BlobID]uint32, dest proto.VunitLocation, blobnodeCli client.IBlobNode) *WorkError {
+	span := trace.SpanFromContextSafe(ctx)
+
+	destBids, err := GetSingleVunitNormalBids(ctx, blobnodeCli, dest)
+	if err != nil {
+		return DstError(err)
+	}
+
+	destCrcs := make(map[proto.BlobID]uint32, len(destBids))
+	for _, bid := range destBids {
+		destCrcs[bid.Bid] = bid.Crc32
+	}
+
+	for bid, expectCrc := range expectCrcs {
+		crc, ok := destCrcs[bid]
+		if !ok {
+			span.Errorf("repair check crc failed: dest[%+v], bid[%d], err[%+v]", dest, bid, ErrBidMissing)
+			return DstError(ErrBidMissing)
+		}
+		if crc != expectCrc {
+			span.Errorf("repair check crc failed: dest[%+v], bid[%d], expect crc[%d], actual crc[%d], err[%+v]",
+				dest, bid, expectCrc, crc, ErrBidCrcNotMatch)
+			return DstError(ErrBidCrcNotMatch)
+		}
+
+		dataCrc, err := readShardCrc(ctx, blobnodeCli, dest, bid)
+		if err != nil {
+			span.Errorf("repair check read shard failed: dest[%+v], bid[%d], err[%+v]", dest, bid, err)
+			return DstError(err)
+		}
+		if dataCrc != expectCrc {
+			span.Errorf("repair check data crc failed: dest[%+v], bid[%d], expect crc[%d], data crc[%d], err[%+v]",
+				dest, bid, expectCrc, dataCrc, ErrBidCrcNotMatch)
+			return DstError(ErrBidCrcNotMatch)
+		}
+	}
+	return nil
+}
+
+// readShardCrc reads shard data from location and returns its crc
+func readShardCrc(ctx context.Context, blobnodeCli client.IBlobNode, location proto.VunitLocation, bid proto.BlobID) (uint32, error) {
+	body, _, err := blobnodeCli.GetShard(ctx, location, bid, blobnode.BackgroundIO)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	crc := crc32.NewIEEE()
+	if _, err = io.Copy(crc, body); err != nil {
+		return 0, err
+	}
+	return crc.Sum32(), nil
+}
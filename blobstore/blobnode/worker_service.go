@@ -16,6 +16,7 @@ package blobnode
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	bnapi "github.com/cubefs/cubefs/blobstore/api/blobnode"
@@ -25,6 +26,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/blobnode/client"
 	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/recordlog"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
@@ -46,6 +48,8 @@ type WorkerConfigMeter struct {
 	DiskDropConcurrency int `json:"disk_drop_concurrency"`
 	// tasklet concurrency of single manual migrate task
 	ManualMigrateConcurrency int `json:"manual_migrate_concurrency"`
+	// tasklet concurrency of single cold migrate task
+	ColdMigrateConcurrency int `json:"cold_migrate_concurrency"`
 	// shard repair concurrency
 	ShardRepairConcurrency int `json:"shard_repair_concurrency"`
 	// volume inspect concurrency
@@ -53,6 +57,8 @@ type WorkerConfigMeter struct {
 
 	// batch download concurrency of single tasklet
 	DownloadShardConcurrency int `json:"download_shard_concurrency"`
+	// count of tasklets buffered between download, decode and upload stages of single task
+	TaskletPipelineBuffer int `json:"tasklet_pipeline_buffer"`
 }
 
 func (meter *WorkerConfigMeter) concurrencyByType(taskType proto.TaskType) int {
@@ -65,6 +71,8 @@ func (meter *WorkerConfigMeter) concurrencyByType(taskType proto.TaskType) int {
 		return meter.DiskDropConcurrency
 	case proto.TaskTypeManualMigrate:
 		return meter.ManualMigrateConcurrency
+	case proto.TaskTypeColdMigrate:
+		return meter.ColdMigrateConcurrency
 	default:
 		return 0
 	}
@@ -86,6 +94,14 @@ type WorkerConfig struct {
 	BlobNode bnapi.Config `json:"blobnode"`
 
 	DroppedBidRecord *recordlog.Config `json:"dropped_bid_record"`
+
+	// fail disk repair task when crc of repaired shards in destination not match,
+	// only log the mismatch if false
+	VerifyRepairCrc bool `json:"verify_repair_crc"`
+
+	// bytes per second of shards downloaded by migrate tasks and shard repair,
+	// shared by all blobnodes with the same name in clustermgr kv if name is set
+	RepairRateLimit ratelimit.ClusterConfig `json:"repair_rate_limit"`
 }
 
 // WorkerService worker worker_service
@@ -98,6 +114,7 @@ type WorkerService struct {
 
 	shardRepairLimit limit.Limiter
 	shardRepairer    *ShardRepairer
+	repairRateLimit  ratelimit.Limiter
 
 	schedulerCli scheduler.IScheduler
 	blobNodeCli  client.IBlobNode
@@ -110,9 +127,11 @@ func (cfg *WorkerConfig) checkAndFix() {
 	fixConfigItemInt(&cfg.BalanceConcurrency, 1)
 	fixConfigItemInt(&cfg.DiskDropConcurrency, 1)
 	fixConfigItemInt(&cfg.ManualMigrateConcurrency, 10)
+	fixConfigItemInt(&cfg.ColdMigrateConcurrency, 1)
 	fixConfigItemInt(&cfg.ShardRepairConcurrency, 1)
 	fixConfigItemInt(&cfg.InspectConcurrency, 1)
 	fixConfigItemInt(&cfg.DownloadShardConcurrency, 10)
+	fixConfigItemInt(&cfg.TaskletPipelineBuffer, 1)
 	fixConfigItemInt64(&cfg.Scheduler.ClientTimeoutMs, 1000)
 	fixConfigItemInt64(&cfg.Scheduler.HostSyncIntervalMs, 1000)
 	fixConfigItemInt64(&cfg.BlobNode.ClientTimeoutMs, 1000)
@@ -136,21 +155,27 @@ func NewWorkerService(cfg *WorkerConfig, service cmapi.APIService, clusterID pro
 
 	base.TaskBufPool = base.NewBufPool(&cfg.BufPoolConf)
 
+	repairRateLimit, err := newRepairRateLimit(cfg.RepairRateLimit, service)
+	if err != nil {
+		return nil, err
+	}
+
 	schedulerCli := scheduler.New(&cfg.Scheduler, service, clusterID)
-	blobNodeCli := client.NewBlobNodeClient(&cfg.BlobNode)
+	inspectBlobNodeCli := client.NewBlobNodeClient(&cfg.BlobNode)
+	blobNodeCli := client.NewLimitedBlobNodeClient(inspectBlobNodeCli, repairRateLimit)
 
 	renewalConfig := cfg.Scheduler
 	renewalConfig.ClientTimeoutMs = 1000 * proto.RenewalTimeoutS
 	renewalCli := scheduler.New(&renewalConfig, service, clusterID)
 	taskRunnerMgr := NewTaskRunnerMgr(idc, cfg.WorkerConfigMeter, NewMigrateWorker, renewalCli, schedulerCli)
-	inspectTaskMgr := NewInspectTaskMgr(cfg.InspectConcurrency, blobNodeCli, schedulerCli)
+	inspectTaskMgr := NewInspectTaskMgr(cfg.InspectConcurrency, inspectBlobNodeCli, schedulerCli)
 
 	shardRepairLimit := count.New(cfg.ShardRepairConcurrency)
 	shardRepairer := NewShardRepairer(blobNodeCli)
 
 	// init dropped bid record
 	bidRecord := base.DroppedBidRecorderInst()
-	err := bidRecord.Init(cfg.DroppedBidRecord, clusterID)
+	err = bidRecord.Init(cfg.DroppedBidRecord, clusterID)
 	if err != nil {
 		return nil, err
 	}
@@ -166,12 +191,32 @@ func NewWorkerService(cfg *WorkerConfig, service cmapi.APIService, clusterID pro
 
 		shardRepairLimit: shardRepairLimit,
 		shardRepairer:    shardRepairer,
+		repairRateLimit:  repairRateLimit,
 	}
 
 	go svr.Run()
 	return svr, nil
 }
 
+func newRepairRateLimit(cfg ratelimit.ClusterConfig, service cmapi.APIService) (ratelimit.Limiter, error) {
+	if cfg.Name == "" {
+		return ratelimit.New(cfg.Config), nil
+	}
+	kv, ok := service.(ratelimit.KVClient)
+	if !ok {
+		return nil, errors.New("clustermgr client without kv to coordinate repair rate limit")
+	}
+	return ratelimit.NewClusterLimiter(cfg, kv)
+}
+
+// Close stops the worker service
+func (s *WorkerService) Close() {
+	s.Closer.Close()
+	if cl, ok := s.repairRateLimit.(*ratelimit.ClusterLimiter); ok {
+		cl.Close()
+	}
+}
+
 // ShardRepair repair shard
 func (s *WorkerService) ShardRepair(c *rpc.Context) {
 	args := new(proto.ShardRepairTask)
@@ -265,6 +310,7 @@ func (s *WorkerService) acquireTask() {
 	err = s.taskRunnerMgr.AddTask(ctx, MigrateTaskEx{
 		taskInfo:                 t,
 		downloadShardConcurrency: s.DownloadShardConcurrency,
+		verifyRepairCrc:          s.VerifyRepairCrc,
 		blobNodeCli:              s.blobNodeCli,
 	})
 	if err != nil {
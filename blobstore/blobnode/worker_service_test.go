@@ -30,6 +30,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/blobnode/client"
 	"github.com/cubefs/cubefs/blobstore/common/codemode"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/testing/mocks"
 	"github.com/cubefs/cubefs/blobstore/util/closer"
@@ -192,6 +193,11 @@ func TestNewWorkService(t *testing.T) {
 	svr, err := NewWorkerService(&WorkerConfig{}, clusterMgr, 1, "z0")
 	require.NoError(t, err)
 	svr.Close()
+
+	// cluster repair rate limit needs kv of clustermgr
+	cfg := &WorkerConfig{RepairRateLimit: ratelimit.ClusterConfig{Name: "repair", Host: "host"}}
+	_, err = NewWorkerService(cfg, struct{ cmapi.APIService }{clusterMgr}, 1, "z0")
+	require.Error(t, err)
 }
 
 func TestFixConfigItem(t *testing.T) {
@@ -159,4 +159,13 @@ func Register(app *grumble.App) {
 			f.IntL("codemode", 0, "on special codemode")
 		},
 	})
+	accessCommand.AddCommand(&grumble.Command{
+		Name:     "codemode",
+		Help:     "explain codemode",
+		LongHelp: "show tactic and capability of codemode",
+		Run:      showCodeModes,
+		Flags: func(f *grumble.Flags) {
+			f.IntL("codemode", 0, "on special codemode")
+		},
+	})
 }
@@ -92,6 +92,32 @@ func showECbuffer(c *grumble.Context) error {
 	return nil
 }
 
+func showCodeModes(c *grumble.Context) error {
+	modes := codemode.GetAllCodeModes()
+	if mode := codemode.CodeMode(c.Flags.Int("codemode")); mode != 0 {
+		if !mode.IsValid() {
+			return fmt.Errorf("invalid codemode %d", mode)
+		}
+		modes = []codemode.CodeMode{mode}
+	}
+
+	for _, mode := range modes {
+		tactic := mode.Tactic()
+		fmt.Println(common.Readable(struct {
+			CodeMode   codemode.CodeMode   `json:"codemode"`
+			Name       string              `json:"name"`
+			Tactic     codemode.Tactic     `json:"tactic"`
+			Capability codemode.Capability `json:"capability"`
+		}{
+			CodeMode:   mode,
+			Name:       mode.String(),
+			Tactic:     tactic,
+			Capability: tactic.Capability(),
+		}))
+	}
+	return nil
+}
+
 func showEcbufferSize(size int, colorFmt *color.Color, modes []codemode.CodeMode) {
 	if size == -1 {
 		colorFmt.Printf("|%s|", center("blobsize"))
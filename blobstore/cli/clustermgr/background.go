@@ -26,6 +26,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/cli/common/fmt"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/taskswitch"
 )
 
 const notSet = "<not set>"
@@ -39,6 +40,7 @@ var (
 		string(proto.TaskTypeVolumeInspect),
 		string(proto.TaskTypeShardRepair),
 		string(proto.TaskTypeBlobDelete),
+		string(proto.TaskTypeColdMigrate),
 	}
 	BackgroundTaskTypeString = "[" + strings.Join(BackgroundTaskTypes, ", ") + "]"
 )
@@ -88,6 +90,67 @@ func addCmdBackground(cmd *grumble.Command) {
 			return cmdEnableDisableBackgroundTask(c, false)
 		},
 	})
+	backgroundCommand.AddCommand(&grumble.Command{
+		Name: "schedule",
+		Help: "set time windows of background task",
+		LongHelp: "Set time windows in which a background task runs, eg: \"00:00-06:00,22:00-24:00\", " +
+			"empty windows means all day, currently supported: " + BackgroundTaskTypeString,
+		Args: func(a *grumble.Args) {
+			a.String("task", "background task type to schedule")
+			a.String("windows", "time windows of a day in local time", grumble.Default(""))
+		},
+		Flags: clusterFlags,
+		Run:   cmdScheduleBackgroundTask,
+	})
+}
+
+func cmdScheduleBackgroundTask(c *grumble.Context) error {
+	key := c.Args.String("task")
+	if !isBackgroundTaskType(key) {
+		return fmt.Errorf("Unsupported background task type: %s", key)
+	}
+	schedule, err := taskswitch.ParseSchedule(c.Args.String("windows"))
+	if err != nil {
+		return err
+	}
+
+	cli := newCMClient(c.Flags)
+	ctx := common.CmdContext()
+	scheduleKey := taskswitch.ScheduleKey(key)
+	oldV, err := cli.GetConfig(ctx, scheduleKey)
+	if err != nil {
+		if rpc.DetectStatusCode(err) != http.StatusNotFound {
+			return err
+		}
+		oldV = notSet
+	}
+	value := schedule.String()
+	if value == "" {
+		value = notSet
+	}
+	if oldV == value {
+		fmt.Printf("Schedule of background task `%s` has already been `%s`.", key, value)
+		return nil
+	}
+
+	if !common.Confirm(fmt.Sprintf(
+		"To schedule background task `%s` from `%s` --> `%s` ?", common.Loaded.Sprint(key),
+		common.Danger.Sprint(oldV), common.Normal.Sprint(value))) {
+		return nil
+	}
+	if len(schedule) == 0 {
+		return cli.DeleteConfig(ctx, scheduleKey)
+	}
+	return cli.SetConfig(ctx, &clustermgr.ConfigSetArgs{Key: scheduleKey, Value: value})
+}
+
+func isBackgroundTaskType(key string) bool {
+	for _, kind := range BackgroundTaskTypes {
+		if kind == key {
+			return true
+		}
+	}
+	return false
 }
 
 func cmdEnableDisableBackgroundTask(c *grumble.Context, en bool) error {
@@ -175,5 +238,14 @@ func cmdListBackgroundStatus(c *grumble.Context) error {
 		return err
 	}
 	showConfig(key, value, verbose)
+
+	schedule, err := cli.GetConfig(ctx, taskswitch.ScheduleKey(key))
+	if err != nil {
+		if rpc.DetectStatusCode(err) != http.StatusNotFound {
+			return err
+		}
+		schedule = notSet
+	}
+	showConfig(taskswitch.ScheduleKey(key), schedule, verbose)
 	return nil
 }
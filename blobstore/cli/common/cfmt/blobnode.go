@@ -93,8 +93,8 @@ func DiskInfoF(info *blobnode.DiskInfo) []string {
 		[]string{
 			fmt.Sprintf("ClusterID : %-4d | Readonly: %-6v | IDC: %-12s | Rack: %s",
 				info.ClusterID, info.Readonly, info.Idc, info.Rack),
-			fmt.Sprintf("Status  : %-10s(%d) | Host: %-30s | Path: %s",
-				info.Status, info.Status, info.Host, info.Path),
+			fmt.Sprintf("Status  : %-10s(%d) | Host: %-30s | Path: %s | DiskSet: %d",
+				info.Status, info.Status, info.Host, info.Path, info.DiskSetID),
 			fmt.Sprintf("CreateAt: %s (%s) | LastUpdateAt: %s (%s)",
 				info.CreateAt.Format(time.RFC822), humanize.Time(info.CreateAt),
 				info.LastUpdateAt.Format(time.RFC822), humanize.Time(info.LastUpdateAt)),
@@ -124,6 +124,7 @@ func DiskInfoFV(info *blobnode.DiskInfo) []string {
 		fmt.Sprint("Rack     : ", info.Rack),
 		fmt.Sprint("Host     : ", info.Host),
 		fmt.Sprint("Path     : ", info.Path),
+		fmt.Sprint("DiskSet  : ", info.DiskSetID),
 		fmt.Sprintf("CreateAt : %s (%s)",
 			info.CreateAt.Format(time.RFC822), humanize.Time(info.CreateAt)),
 		fmt.Sprintf("UpdateAt : %s (%s)",
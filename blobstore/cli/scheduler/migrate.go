@@ -17,7 +17,6 @@ package scheduler
 import (
 	"github.com/desertbit/grumble"
 
-	"github.com/cubefs/cubefs/blobstore/api/clustermgr"
 	"github.com/cubefs/cubefs/blobstore/api/scheduler"
 	"github.com/cubefs/cubefs/blobstore/cli/common"
 	"github.com/cubefs/cubefs/blobstore/cli/common/fmt"
@@ -29,6 +28,8 @@ import (
 const (
 	_count          = "count"
 	_diskID         = "disk_id"
+	_state          = "state"
+	_vid            = "vid"
 	_directDownload = "direct_download"
 )
 
@@ -66,6 +67,8 @@ func addCmdMigrateTask(cmd *grumble.Command) {
 		Flags: func(f *grumble.Flags) {
 			migrateFlags(f)
 			f.Uint64L(_diskID, 0, "disk id for which disk to list")
+			f.UintL(_state, 0, "task state to list, 0 means all states")
+			f.Uint64L(_vid, 0, "volume id for which volume to list")
 			f.IntL(_count, 10, "the number you want to get")
 		},
 	})
@@ -142,32 +145,30 @@ func cmdListTask(c *grumble.Context) error {
 	if !taskType.Valid() {
 		return errcode.ErrIllegalTaskType
 	}
-	count := c.Flags.Int(_count)
-	diskID := proto.DiskID(c.Flags.Uint64(_diskID))
-	clusterMgrCli := newClusterMgrTaskClient(getClusterID(c.Flags))
-	prefix := client.GenMigrateTaskPrefix(taskType)
-	if diskID != proto.InvalidDiskID {
-		prefix = client.GenMigrateTaskPrefixByDiskID(taskType, diskID)
-	}
-	listKvArgs := &clustermgr.ListKvOpts{
-		Prefix: prefix,
-		Count:  count,
+	clusterID := getClusterID(c.Flags)
+	cli := scheduler.New(&scheduler.Config{}, newClusterMgrClient(clusterID), clusterID)
+	args := &scheduler.ListTasksArgs{
+		TaskType: taskType,
+		State:    proto.MigrateState(c.Flags.Uint(_state)),
+		DiskID:   proto.DiskID(c.Flags.Uint64(_diskID)),
+		Vid:      proto.Vid(c.Flags.Uint64(_vid)),
+		Count:    c.Flags.Int(_count),
 	}
 	for {
-		tasks, marker, err := clusterMgrCli.ListMigrateTasks(ctx, taskType, listKvArgs)
+		ret, err := cli.ListTasks(ctx, args)
 		if err != nil {
 			return err
 		}
-		for _, task := range tasks {
+		for _, task := range ret.Tasks {
 			printMigrateTask(task)
 		}
-		if marker == "" {
+		if ret.Marker == "" {
 			return nil
 		}
 		if !common.Confirm("for more?") {
 			return nil
 		}
-		listKvArgs.Marker = marker
+		args.Marker = ret.Marker
 	}
 }
 
@@ -16,6 +16,7 @@ package clustermgr
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/cubefs/cubefs/blobstore/api/blobnode"
 	"github.com/cubefs/cubefs/blobstore/api/clustermgr"
@@ -28,6 +29,11 @@ import (
 	"github.com/cubefs/cubefs/blobstore/util/errors"
 )
 
+const (
+	defaultWatchBrokenDiskTimeoutMs = 30000
+	maxWatchBrokenDiskTimeoutMs     = 60000
+)
+
 func (s *Service) DiskIdAlloc(c *rpc.Context) {
 	ctx := c.Request.Context()
 	span := trace.SpanFromContextSafe(ctx)
@@ -358,6 +364,39 @@ func (s *Service) DiskDroppingList(c *rpc.Context) {
 	c.RespondJSON(ret)
 }
 
+// DiskBrokenWatch responds the latest broken disk version when it is greater than args.Version,
+// or when any disk becomes broken or timeout
+func (s *Service) DiskBrokenWatch(c *rpc.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContextSafe(ctx)
+	args := new(clustermgr.WatchBrokenDiskArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	span.Debugf("accept DiskBrokenWatch request, args: %v", args)
+
+	if args.TimeoutMs <= 0 {
+		args.TimeoutMs = defaultWatchBrokenDiskTimeoutMs
+	}
+	if args.TimeoutMs > maxWatchBrokenDiskTimeoutMs {
+		args.TimeoutMs = maxWatchBrokenDiskTimeoutMs
+	}
+
+	version, brokenCh := s.DiskMgr.BrokenDiskVersion()
+	if version <= args.Version {
+		timer := time.NewTimer(time.Duration(args.TimeoutMs) * time.Millisecond)
+		select {
+		case <-brokenCh:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		timer.Stop()
+		version, _ = s.DiskMgr.BrokenDiskVersion()
+	}
+	c.RespondJSON(&clustermgr.WatchBrokenDiskRet{Version: version})
+}
+
 func (s *Service) DiskHeartbeat(c *rpc.Context) {
 	ctx := c.Request.Context()
 	span := trace.SpanFromContextSafe(ctx)
@@ -268,3 +268,54 @@ func TestDisk(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestDiskBrokenWatch(t *testing.T) {
+	testService, clean := initTestService(t)
+	defer clean()
+	testClusterClient := initTestClusterClient(testService)
+	ctx := newCtx()
+	insertDiskInfos(t, testClusterClient, 1, 2, testService.IDC[0])
+
+	// timeout without broken disk
+	ret, err := testClusterClient.WatchBrokenDisk(ctx, &clustermgr.WatchBrokenDiskArgs{TimeoutMs: 10})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), ret.Version)
+
+	type watchRet struct {
+		version uint64
+		err     error
+	}
+	watchCh := make(chan watchRet)
+	go func() {
+		ret, err := testClusterClient.WatchBrokenDisk(ctx, &clustermgr.WatchBrokenDiskArgs{TimeoutMs: 30000})
+		watchCh <- watchRet{version: ret.Version, err: err}
+	}()
+	err = testClusterClient.SetDisk(ctx, 1, proto.DiskStatusBroken)
+	require.NoError(t, err)
+	wret := <-watchCh
+	require.NoError(t, wret.err)
+	require.Equal(t, uint64(1), wret.version)
+
+	// return at once if the version is changed
+	ret, err = testClusterClient.WatchBrokenDisk(ctx, &clustermgr.WatchBrokenDiskArgs{TimeoutMs: 30000})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ret.Version)
+
+	// other status does not change the version
+	err = testClusterClient.SetDisk(ctx, 1, proto.DiskStatusRepairing)
+	require.NoError(t, err)
+	ret, err = testClusterClient.WatchBrokenDisk(ctx, &clustermgr.WatchBrokenDiskArgs{Version: 1, TimeoutMs: 10})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ret.Version)
+
+	// wait if the watcher has seen a greater version
+	ret, err = testClusterClient.WatchBrokenDisk(ctx, &clustermgr.WatchBrokenDiskArgs{Version: 5, TimeoutMs: 10})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ret.Version)
+
+	// version is rebuilt from the disk table
+	require.NoError(t, testService.DiskMgr.LoadData(ctx))
+	ret, err = testClusterClient.WatchBrokenDisk(ctx, &clustermgr.WatchBrokenDiskArgs{TimeoutMs: 10})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ret.Version)
+}
@@ -33,9 +33,10 @@ var defaultAllocTolerateBuff int64 = 50
 type idcStorage struct {
 	idc string
 	// freeChunk should always read and write by atomic
-	freeChunk int64
-	diffRack  bool
-	diffHost  bool
+	freeChunk   int64
+	diffRack    bool
+	diffHost    bool
+	diffDiskSet bool
 
 	rackStorages     map[string]*rackStorage
 	blobNodeStorages []*blobNodeStorage
@@ -58,8 +59,35 @@ type blobNodeStorage struct {
 	disks     []*diskItem
 }
 
+// allocFilter filter the disks which can not be chosen
+type allocFilter struct {
+	// cold means alloc from cold disks only, otherwise cold disks will be ignored
+	cold bool
+	// diskSets is the chosen disk sets, nil means no disk set limit
+	diskSets map[proto.DiskSetID]bool
+}
+
+// pass return true if disk can be chosen, it should be called with disk lock
+func (f *allocFilter) pass(disk *diskItem) bool {
+	if disk.info.Cold != f.cold {
+		return false
+	}
+	// ignore disk in the same fault domain with chosen disks
+	if f.diskSets != nil && disk.info.DiskSetID != proto.InvalidDiskSetID && f.diskSets[disk.info.DiskSetID] {
+		return false
+	}
+	return true
+}
+
+// choose record chosen disk, it should be called with disk lock
+func (f *allocFilter) choose(disk *diskItem) {
+	if f.diskSets != nil && disk.info.DiskSetID != proto.InvalidDiskSetID {
+		f.diskSets[disk.info.DiskSetID] = true
+	}
+}
+
 // allocDisk will choose disk by disk free chunk count weight
-func (d *blobNodeStorage) allocDisk(ctx context.Context, excludes map[proto.DiskID]*diskItem) (chosenDisk *diskItem) {
+func (d *blobNodeStorage) allocDisk(ctx context.Context, excludes map[proto.DiskID]*diskItem, filter *allocFilter) (chosenDisk *diskItem) {
 	span := trace.SpanFromContextSafe(ctx)
 	totalFreeChunk := atomic.LoadInt64(&d.freeChunk)
 	if totalFreeChunk <= 0 {
@@ -90,8 +118,14 @@ func (d *blobNodeStorage) allocDisk(ctx context.Context, excludes map[proto.Disk
 				return nil
 			}
 
+			if !filter.pass(disk) {
+				span.Debugf("disk %d is filtered, cold: %v, disk set: %d", disk.diskID, disk.info.Cold, disk.info.DiskSetID)
+				return nil
+			}
+
 			if _, ok := excludes[disk.diskID]; !ok {
 				span.Debugf("chosen disk: %#v", disk.info)
+				filter.choose(disk)
 				return disk
 			}
 			return nil
@@ -103,7 +137,7 @@ func (d *blobNodeStorage) allocDisk(ctx context.Context, excludes map[proto.Disk
 	return chosenDisk
 }
 
-func (s *idcStorage) alloc(ctx context.Context, count int, excludes map[proto.DiskID]*diskItem) ([]proto.DiskID, error) {
+func (s *idcStorage) alloc(ctx context.Context, count int, excludes map[proto.DiskID]*diskItem, cold bool) ([]proto.DiskID, error) {
 	span := trace.SpanFromContextSafe(ctx)
 	var chosenRacks map[string]int
 	var chosenDataStorages map[*blobNodeStorage]int
@@ -116,10 +150,24 @@ func (s *idcStorage) alloc(ctx context.Context, count int, excludes map[proto.Di
 		return nil, ErrNoEnoughSpace
 	}
 
-	if s.diffRack && s.diffHost {
-		chosenRacks, chosenDataStorages, chosenDisks = s.allocFromRack(ctx, count, excludes)
+	allocOnce := func(diskSets map[proto.DiskSetID]bool) {
+		filter := &allocFilter{cold: cold, diskSets: diskSets}
+		if s.diffRack && s.diffHost {
+			chosenRacks, chosenDataStorages, chosenDisks = s.allocFromRack(ctx, count, excludes, filter)
+		} else {
+			chosenDataStorages, chosenDisks = s.allocFromBlobNodeStorages(ctx, count, totalFreeChunk-defaultAllocTolerateBuff, s.blobNodeStorages, excludes, filter)
+		}
+	}
+
+	if s.diffDiskSet {
+		allocOnce(excludeDiskSets(excludes))
+		// disk set is a soft limit, retry without disk set limit when there is no enough disk sets
+		if len(chosenDisks) < count {
+			span.Warnf("can't find enough disk sets, chosen disks: %v, retry without disk set limit", chosenDisks)
+			allocOnce(nil)
+		}
 	} else {
-		chosenDataStorages, chosenDisks = s.allocFromBlobNodeStorages(ctx, count, totalFreeChunk-defaultAllocTolerateBuff, s.blobNodeStorages, excludes)
+		allocOnce(nil)
 	}
 
 	if len(chosenDisks) < count {
@@ -147,7 +195,7 @@ func (s *idcStorage) alloc(ctx context.Context, count int, excludes map[proto.Di
 // 1. alloc rack with free chunk weight
 // 2. alloc from rack's data node storage
 // 3. if can't meet the alloc count request, then retry with enable same rack
-func (s *idcStorage) allocFromRack(ctx context.Context, count int, excludes map[proto.DiskID]*diskItem) (chosenRacksRet map[string]int, chosenDataStorages map[*blobNodeStorage]int, chosenDisks map[proto.DiskID]*diskItem) {
+func (s *idcStorage) allocFromRack(ctx context.Context, count int, excludes map[proto.DiskID]*diskItem, filter *allocFilter) (chosenRacksRet map[string]int, chosenDataStorages map[*blobNodeStorage]int, chosenDisks map[proto.DiskID]*diskItem) {
 	span := trace.SpanFromContextSafe(ctx)
 	rackNum := len(s.rackStorages)
 	chosenRacksRet = make(map[string]int, count)
@@ -231,7 +279,7 @@ RETRY:
 		if num > _count {
 			num = _count
 		}
-		dataStorages, disks := s.allocFromBlobNodeStorages(ctx, num, atomic.LoadInt64(&s.rackStorages[rack].freeChunk), s.rackStorages[rack].blobNodeStorages, excludes)
+		dataStorages, disks := s.allocFromBlobNodeStorages(ctx, num, atomic.LoadInt64(&s.rackStorages[rack].freeChunk), s.rackStorages[rack].blobNodeStorages, excludes, filter)
 		for id := range disks {
 			chosenDisks[id] = disks[id]
 			chosenRacksRet[rack]++
@@ -251,7 +299,7 @@ RETRY:
 // 1. copy rack's blobNodeStorage pointer array
 // 2. alloc from blobNodeStorage array
 // 3. the alloc result length may not equal to count if there is no enough space or something else
-func (s *idcStorage) allocFromBlobNodeStorages(ctx context.Context, count int, totalFreeChunk int64, srcBlobNodeStorages []*blobNodeStorage, excludes map[proto.DiskID]*diskItem) (chosenDataStorages map[*blobNodeStorage]int, chosenDisks map[proto.DiskID]*diskItem) {
+func (s *idcStorage) allocFromBlobNodeStorages(ctx context.Context, count int, totalFreeChunk int64, srcBlobNodeStorages []*blobNodeStorage, excludes map[proto.DiskID]*diskItem, filter *allocFilter) (chosenDataStorages map[*blobNodeStorage]int, chosenDisks map[proto.DiskID]*diskItem) {
 	span := trace.SpanFromContextSafe(ctx)
 	excludeHosts := make(map[string]bool)
 	chosenDisks = make(map[proto.DiskID]*diskItem)
@@ -328,7 +376,7 @@ RETRY:
 			freeChunk := atomic.LoadInt64(&blobNodeStorages[i].freeChunk)
 			span.Debugf("total free chunk: %d, blobNode(%s) free chunk: %d, randNum: %d", _totalFreeChunk, blobNodeStorages[i].host, freeChunk, randNum)
 			if freeChunk >= randNum {
-				if selectedDisk := blobNodeStorages[i].allocDisk(ctx, chosenDisks); selectedDisk != nil {
+				if selectedDisk := blobNodeStorages[i].allocDisk(ctx, chosenDisks, filter); selectedDisk != nil {
 					chosenDisks[selectedDisk.diskID] = selectedDisk
 					chosenDataStorages[blobNodeStorages[i]] += 1
 					blobNodeStorages[chosenIdx], blobNodeStorages[i] = blobNodeStorages[i], blobNodeStorages[chosenIdx]
@@ -353,3 +401,20 @@ RETRY:
 	}
 	return
 }
+
+// excludeDiskSets return disk sets of the excludes disks,
+// new allocated disk should not be in these disk sets
+func excludeDiskSets(excludes map[proto.DiskID]*diskItem) map[proto.DiskSetID]bool {
+	diskSets := make(map[proto.DiskSetID]bool)
+	for _, disk := range excludes {
+		if disk == nil {
+			continue
+		}
+		disk.lock.RLock()
+		if disk.info.DiskSetID != proto.InvalidDiskSetID {
+			diskSets[disk.info.DiskSetID] = true
+		}
+		disk.lock.RUnlock()
+	}
+	return diskSets
+}
@@ -160,7 +160,7 @@ func TestAlloc(t *testing.T) {
 		// alloc from not enough space, alloc should return ErrNoEnoughSpace
 		for _, idc := range testIdcs {
 			allocator := testDiskMgr.allocators[idc].Load().(*idcStorage)
-			_, err := allocator.alloc(ctx, 9, nil)
+			_, err := allocator.alloc(ctx, 9, nil, false)
 			require.Equal(t, ErrNoEnoughSpace, err)
 		}
 
@@ -168,7 +168,7 @@ func TestAlloc(t *testing.T) {
 		testDiskMgr.RackAware = true
 		testDiskMgr.refresh(ctx)
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		_, err := allocator.alloc(ctx, 9, nil)
+		_, err := allocator.alloc(ctx, 9, nil, false)
 		require.Equal(t, ErrNoEnoughSpace, err)
 	}
 
@@ -180,7 +180,7 @@ func TestAlloc(t *testing.T) {
 		testDiskMgr.RackAware = false
 		testDiskMgr.refresh(ctx)
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		ret, err := allocator.alloc(ctx, 9, nil)
+		ret, err := allocator.alloc(ctx, 9, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 9, len(ret))
 	}
@@ -196,7 +196,7 @@ func TestAlloc(t *testing.T) {
 		testDiskMgr.refresh(ctx)
 		// alloc from enough space
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		ret, err := allocator.alloc(ctx, 9, nil)
+		ret, err := allocator.alloc(ctx, 9, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 9, len(ret))
 
@@ -205,7 +205,7 @@ func TestAlloc(t *testing.T) {
 		testDiskMgr.RackAware = true
 		testDiskMgr.refresh(ctx)
 		allocator = testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		ret, err = allocator.alloc(ctx, 9, nil)
+		ret, err = allocator.alloc(ctx, 9, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 9, len(ret))
 
@@ -272,7 +272,7 @@ func TestAllocWithSameHost(t *testing.T) {
 		// alloc from not enough space, alloc should return ErrNoEnoughSpace
 		for _, idc := range testIdcs {
 			allocator := testDiskMgr.allocators[idc].Load().(*idcStorage)
-			_, err := allocator.alloc(ctx, 11, nil)
+			_, err := allocator.alloc(ctx, 11, nil, false)
 			require.Equal(t, ErrNoEnoughSpace, err)
 		}
 	}
@@ -283,7 +283,7 @@ func TestAllocWithSameHost(t *testing.T) {
 		_, ctx = trace.StartSpanFromContext(context.Background(), "alloc-same-host-not-enough")
 		testDiskMgr.refresh(ctx)
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		ret, err := allocator.alloc(ctx, 12, nil)
+		ret, err := allocator.alloc(ctx, 12, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 12, len(ret))
 		t.Log(ret)
@@ -303,13 +303,13 @@ func TestAllocWithSameHost(t *testing.T) {
 		defaultAllocTolerateBuff = 0
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
 		for i := 1; i <= 10; i++ {
-			diskIDs, err := allocator.alloc(ctx, 12, nil)
+			diskIDs, err := allocator.alloc(ctx, 12, nil, false)
 			require.NoError(t, err)
 			require.Equal(t, 12, len(diskIDs))
 		}
 
 		// alloc exceed available free chunk, error should be return
-		_, err := allocator.alloc(ctx, 1, nil)
+		_, err := allocator.alloc(ctx, 1, nil, false)
 		require.Error(t, err)
 		require.Equal(t, ErrNoEnoughSpace, err)
 	}
@@ -335,7 +335,7 @@ func TestAllocWithSameHost(t *testing.T) {
 				3: testDiskMgr.allDisks[1],
 				4: testDiskMgr.allDisks[1],
 				5: testDiskMgr.allDisks[1],
-			})
+			}, false)
 			require.NoError(t, err)
 			require.Equal(t, 1, len(diskIDs))
 			require.Equal(t, proto.DiskID(6), diskIDs[0])
@@ -346,7 +346,7 @@ func TestAllocWithSameHost(t *testing.T) {
 			3: testDiskMgr.allDisks[1],
 			4: testDiskMgr.allDisks[1],
 			5: testDiskMgr.allDisks[1],
-		})
+		}, false)
 		require.Equal(t, ErrNoEnoughSpace, err)
 	}
 }
@@ -388,7 +388,7 @@ func TestAllocWithDiffRack(t *testing.T) {
 		testDiskMgr.refresh(ctx)
 		// alloc from not enough rack, but enough data node, it should be successful
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		diskIDs, err := allocator.alloc(ctx, 10, nil)
+		diskIDs, err := allocator.alloc(ctx, 10, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 10, len(diskIDs))
 
@@ -405,12 +405,12 @@ func TestAllocWithDiffRack(t *testing.T) {
 		defaultAllocTolerateBuff = 0
 		allocator = testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
 		for i := 1; i <= 10; i++ {
-			diskIDs, err := allocator.alloc(ctx, 10, nil)
+			diskIDs, err := allocator.alloc(ctx, 10, nil, false)
 			require.NoError(t, err)
 			require.Equal(t, 10, len(diskIDs))
 		}
 		// alloc exceed available free chunk, error should be return
-		_, err = allocator.alloc(ctx, 1, nil)
+		_, err = allocator.alloc(ctx, 1, nil, false)
 		require.Error(t, err)
 		require.Equal(t, ErrNoEnoughSpace, err)
 	}
@@ -444,7 +444,7 @@ func TestAllocWithDiffHost(t *testing.T) {
 		testDiskMgr.RackAware = false
 		testDiskMgr.refresh(ctx)
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		diskIDs, err := allocator.alloc(ctx, 10, nil)
+		diskIDs, err := allocator.alloc(ctx, 10, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 10, len(diskIDs))
 
@@ -461,12 +461,12 @@ func TestAllocWithDiffHost(t *testing.T) {
 		defaultAllocTolerateBuff = 0
 		allocator = testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
 		for i := 1; i <= 10; i++ {
-			diskIDs, err := allocator.alloc(ctx, 10, nil)
+			diskIDs, err := allocator.alloc(ctx, 10, nil, false)
 			require.NoError(t, err)
 			require.Equal(t, 10, len(diskIDs))
 		}
 		// alloc exceed available free chunk, error should be return
-		_, err = allocator.alloc(ctx, 1, nil)
+		_, err = allocator.alloc(ctx, 1, nil, false)
 		require.Error(t, err)
 		require.Equal(t, ErrNoEnoughSpace, err)
 	}
@@ -508,7 +508,7 @@ func TestAllocWithDiffRackAndSameHost(t *testing.T) {
 		testDiskMgr.RackAware = true
 		testDiskMgr.refresh(ctx)
 		allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
-		diskIDs, err := allocator.alloc(ctx, 10, nil)
+		diskIDs, err := allocator.alloc(ctx, 10, nil, false)
 		require.NoError(t, err)
 		require.Equal(t, 10, len(diskIDs))
 
@@ -525,12 +525,12 @@ func TestAllocWithDiffRackAndSameHost(t *testing.T) {
 		defaultAllocTolerateBuff = 0
 		allocator = testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
 		for i := 1; i <= 10; i++ {
-			diskIDs, err := allocator.alloc(ctx, 10, nil)
+			diskIDs, err := allocator.alloc(ctx, 10, nil, false)
 			require.NoError(t, err)
 			require.Equal(t, 10, len(diskIDs))
 		}
 		// alloc exceed available free chunk, error should be return
-		_, err = allocator.alloc(ctx, 1, nil)
+		_, err = allocator.alloc(ctx, 1, nil, false)
 		require.Error(t, err)
 		require.Equal(t, ErrNoEnoughSpace, err)
 	}
@@ -558,10 +558,134 @@ func TestAllocCost(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < totalTimes/concurrency; j++ {
-				allocator.alloc(ctx, 9, nil)
+				allocator.alloc(ctx, 9, nil, false)
 			}
 		}()
 	}
 	wg.Wait()
 	t.Log("op cost:", time.Since(start)/time.Duration(totalTimes))
 }
+
+func TestAllocWithDiskSet(t *testing.T) {
+	testDiskMgr, closeTestDiskMgr := initTestDiskMgr(t)
+	defer closeTestDiskMgr()
+	// disk never expire
+	testDiskMgr.HeartbeatExpireIntervalS = 6000
+
+	_, ctx := trace.StartSpanFromContext(context.Background(), "alloc-disk-set")
+	diskInfo := blobnode.DiskInfo{
+		DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{
+			Size:         14.5 * 1024 * 1024 * 1024 * 1024,
+			Free:         14.5 * 1024 * 1024 * 1024 * 1024,
+			MaxChunkCnt:  14.5 * 1024 / 16,
+			FreeChunkCnt: 14.5 * 1024 / 16,
+		},
+		ClusterID: proto.ClusterID(1),
+		Idc:       testIdcs[0],
+		Rack:      "0",
+		Host:      testIdcs[0] + hostPrefix + "0",
+		Status:    proto.DiskStatusNormal,
+	}
+	// one host with 3 disk sets
+	for i := 1; i <= 12; i++ {
+		diskInfo.DiskID = proto.DiskID(i)
+		diskInfo.DiskSetID = proto.DiskSetID(i%3 + 1)
+		diskInfo.Path = "/data" + strconv.Itoa(i)
+		require.NoError(t, testDiskMgr.addDisk(ctx, diskInfo))
+	}
+
+	testDiskMgr.HostAware = false
+	testDiskMgr.RackAware = false
+	testDiskMgr.DiskSetAware = true
+	testDiskMgr.refresh(ctx)
+	allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
+
+	diskSetOf := func(ids []proto.DiskID) map[proto.DiskSetID]int {
+		sets := make(map[proto.DiskSetID]int)
+		for _, id := range ids {
+			disk, _ := testDiskMgr.getDisk(id)
+			sets[disk.info.DiskSetID]++
+		}
+		return sets
+	}
+
+	// every allocated disk in different disk set
+	for i := 0; i < 10; i++ {
+		ret, err := allocator.alloc(ctx, 3, nil, false)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(diskSetOf(ret)))
+	}
+
+	// disk set of excludes disk should not be chosen
+	for i := 0; i < 10; i++ {
+		disk, _ := testDiskMgr.getDisk(proto.DiskID(3))
+		excludes := map[proto.DiskID]*diskItem{disk.diskID: disk}
+		ret, err := allocator.alloc(ctx, 2, excludes, false)
+		require.NoError(t, err)
+		sets := diskSetOf(ret)
+		require.Equal(t, 2, len(sets))
+		require.Equal(t, 0, sets[disk.info.DiskSetID])
+	}
+
+	// no enough disk sets, fallback to alloc without disk set limit
+	ret, err := allocator.alloc(ctx, 5, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(ret))
+}
+
+func TestAllocWithColdDisk(t *testing.T) {
+	testDiskMgr, closeTestDiskMgr := initTestDiskMgr(t)
+	defer closeTestDiskMgr()
+	// disk never expire
+	testDiskMgr.HeartbeatExpireIntervalS = 6000
+
+	_, ctx := trace.StartSpanFromContext(context.Background(), "alloc-cold-disk")
+	diskInfo := blobnode.DiskInfo{
+		DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{
+			Size:         14.5 * 1024 * 1024 * 1024 * 1024,
+			Free:         14.5 * 1024 * 1024 * 1024 * 1024,
+			MaxChunkCnt:  14.5 * 1024 / 16,
+			FreeChunkCnt: 14.5 * 1024 / 16,
+		},
+		ClusterID: proto.ClusterID(1),
+		Idc:       testIdcs[0],
+		Rack:      "0",
+		Host:      testIdcs[0] + hostPrefix + "0",
+		Status:    proto.DiskStatusNormal,
+	}
+	// disk 1-4 is cold disk
+	for i := 1; i <= 12; i++ {
+		diskInfo.DiskID = proto.DiskID(i)
+		diskInfo.Cold = i <= 4
+		diskInfo.Path = "/data" + strconv.Itoa(i)
+		require.NoError(t, testDiskMgr.addDisk(ctx, diskInfo))
+	}
+
+	testDiskMgr.HostAware = false
+	testDiskMgr.RackAware = false
+	testDiskMgr.refresh(ctx)
+	allocator := testDiskMgr.allocators[testIdcs[0]].Load().(*idcStorage)
+
+	isCold := func(id proto.DiskID) bool {
+		disk, _ := testDiskMgr.getDisk(id)
+		return disk.info.Cold
+	}
+
+	for i := 0; i < 10; i++ {
+		ret, err := allocator.alloc(ctx, 3, nil, true)
+		require.NoError(t, err)
+		for _, id := range ret {
+			require.True(t, isCold(id))
+		}
+
+		ret, err = allocator.alloc(ctx, 8, nil, false)
+		require.NoError(t, err)
+		for _, id := range ret {
+			require.False(t, isCold(id))
+		}
+	}
+
+	// no enough cold disk
+	_, err := allocator.alloc(ctx, 5, nil, true)
+	require.Equal(t, ErrNoEnoughSpace, err)
+}
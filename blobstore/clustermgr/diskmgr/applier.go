@@ -55,6 +55,7 @@ func (d *DiskMgr) LoadData(ctx context.Context) error {
 	}
 
 	allDisks := make(map[proto.DiskID]*diskItem)
+	brokenVersion := uint64(0)
 	for _, disk := range diskDBs {
 		info := diskInfoRecordToDiskInfo(disk)
 		di := &diskItem{
@@ -68,11 +69,21 @@ func (d *DiskMgr) LoadData(ctx context.Context) error {
 			di.dropping = true
 		}
 		allDisks[info.DiskID] = di
+		if info.Status != proto.DiskStatusNormal {
+			brokenVersion++
+		}
 		if di.needFilter() {
 			d.hostPathFilter.Store(di.genFilterKey(), 1)
 		}
 	}
 	d.allDisks = allDisks
+	d.brokenLock.Lock()
+	if brokenVersion > d.brokenVersion {
+		close(d.brokenCh)
+		d.brokenCh = make(chan struct{})
+	}
+	d.brokenVersion = brokenVersion
+	d.brokenLock.Unlock()
 	return nil
 }
 
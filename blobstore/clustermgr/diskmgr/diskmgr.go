@@ -89,6 +89,8 @@ type AllocPolicy struct {
 	Idc      string
 	Vuids    []proto.Vuid
 	Excludes []proto.DiskID
+	// Cold means alloc chunks from cold disks
+	Cold bool
 }
 
 type HeartbeatEvent struct {
@@ -100,6 +102,7 @@ type DiskMgrConfig struct {
 	RefreshIntervalS         int             `json:"refresh_interval_s"`
 	RackAware                bool            `json:"rack_aware"`
 	HostAware                bool            `json:"host_aware"`
+	DiskSetAware             bool            `json:"disk_set_aware"`
 	HeartbeatExpireIntervalS int             `json:"heartbeat_expire_interval_s"`
 	FlushIntervalS           int             `json:"flush_interval_s"`
 	ApplyConcurrency         uint32          `json:"apply_concurrency"`
@@ -128,6 +131,13 @@ type DiskMgr struct {
 	spaceStatInfo atomic.Value
 	metaLock      sync.RWMutex
 	closeCh       chan interface{}
+
+	// brokenVersion is the number of disks which have left normal status, it is
+	// derived from the disk table so all nodes agree on it and it survives restarts.
+	// brokenCh is closed and renewed to wake up the watchers when it increases
+	brokenLock    sync.Mutex
+	brokenVersion uint64
+	brokenCh      chan struct{}
 	DiskMgrConfig
 }
 
@@ -219,6 +229,7 @@ func New(scopeMgr scopemgr.ScopeMgrAPI, db *normaldb.NormalDB, cfg DiskMgrConfig
 		droppedDiskTbl: droppedDiskTbl,
 		blobNodeClient: blobnode.New(&cfg.BlobNodeConfig),
 		closeCh:        make(chan interface{}),
+		brokenCh:       make(chan struct{}),
 		DiskMgrConfig:  cfg,
 	}
 
@@ -376,14 +387,34 @@ func (d *DiskMgr) SetStatus(ctx context.Context, id proto.DiskID, status proto.D
 		span.Error(errors.Detail(err))
 		return err
 	}
+	before := diskInfo.info.Status
 	diskInfo.info.Status = status
 	if !diskInfo.needFilter() {
 		d.hostPathFilter.Delete(diskInfo.genFilterKey())
 	}
+	if before == proto.DiskStatusNormal {
+		d.notifyBroken()
+	}
 
 	return nil
 }
 
+// BrokenDiskVersion returns the version of broken disks, which is the number of
+// disks not in normal status, and a channel which is closed when it increases afterwards
+func (d *DiskMgr) BrokenDiskVersion() (uint64, <-chan struct{}) {
+	d.brokenLock.Lock()
+	defer d.brokenLock.Unlock()
+	return d.brokenVersion, d.brokenCh
+}
+
+func (d *DiskMgr) notifyBroken() {
+	d.brokenLock.Lock()
+	d.brokenVersion++
+	close(d.brokenCh)
+	d.brokenCh = make(chan struct{})
+	d.brokenLock.Unlock()
+}
+
 func (d *DiskMgr) IsDroppingDisk(ctx context.Context, id proto.DiskID) (bool, error) {
 	disk, ok := d.getDisk(id)
 	if !ok {
@@ -434,7 +465,7 @@ func (d *DiskMgr) AllocChunks(ctx context.Context, policy *AllocPolicy) (ret []p
 		}
 	}
 
-	ret, err = allocator.alloc(ctx, len(policy.Vuids), excludes)
+	ret, err = allocator.alloc(ctx, len(policy.Vuids), excludes, policy.Cold)
 	if err != nil {
 		return
 	}
@@ -749,6 +780,9 @@ func (d *DiskMgr) adminUpdateDisk(ctx context.Context, diskInfo *blobnode.DiskIn
 	}
 	disk.lock.Lock()
 	if diskInfo.Status.IsValid() {
+		if disk.info.Status == proto.DiskStatusNormal && diskInfo.Status != proto.DiskStatusNormal {
+			d.notifyBroken()
+		}
 		disk.info.Status = diskInfo.Status
 	}
 	if diskInfo.MaxChunkCnt > 0 {
@@ -772,6 +806,8 @@ func diskInfoToDiskInfoRecord(info *blobnode.DiskInfo) *normaldb.DiskInfoRecord
 		Rack:         info.Rack,
 		Host:         info.Host,
 		Path:         info.Path,
+		DiskSetID:    info.DiskSetID,
+		Cold:         info.Cold,
 		Status:       info.Status,
 		Readonly:     info.Readonly,
 		UsedChunkCnt: info.UsedChunkCnt,
@@ -792,6 +828,8 @@ func diskInfoRecordToDiskInfo(infoDB *normaldb.DiskInfoRecord) *blobnode.DiskInf
 		Rack:         infoDB.Rack,
 		Host:         infoDB.Host,
 		Path:         infoDB.Path,
+		DiskSetID:    infoDB.DiskSetID,
+		Cold:         infoDB.Cold,
 		Status:       infoDB.Status,
 		Readonly:     infoDB.Readonly,
 		CreateAt:     infoDB.CreateAt,
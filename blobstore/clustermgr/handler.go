@@ -33,6 +33,7 @@ func NewHandler(service *Service) *rpc.Router {
 	//==================disk==========================
 	rpc.RegisterArgsParser(&clustermgr.DiskInfoArgs{}, "json")
 	rpc.RegisterArgsParser(&clustermgr.ListOptionArgs{}, "json")
+	rpc.RegisterArgsParser(&clustermgr.WatchBrokenDiskArgs{}, "json")
 
 	rpc.POST("/diskid/alloc", service.DiskIdAlloc)
 
@@ -52,6 +53,8 @@ func NewHandler(service *Service) *rpc.Router {
 
 	rpc.GET("/disk/droppinglist", service.DiskDroppingList)
 
+	rpc.GET("/disk/broken/watch", service.DiskBrokenWatch, rpc.OptArgsQuery())
+
 	rpc.POST("/disk/access", service.DiskAccess, rpc.OptArgsBody())
 
 	rpc.POST("/admin/disk/update", service.AdminDiskUpdate, rpc.OptArgsBody())
@@ -75,6 +78,7 @@ func NewHandler(service *Service) *rpc.Router {
 	rpc.RegisterArgsParser(&clustermgr.ListVolumeV2Args{}, "json")
 	rpc.RegisterArgsParser(&clustermgr.ListVolumeUnitArgs{}, "json")
 	rpc.RegisterArgsParser(&clustermgr.ListAllocatedVolumeArgs{}, "json")
+	rpc.RegisterArgsParser(&clustermgr.ListVolumeHealthArgs{}, "json")
 
 	rpc.GET("/volume/get", service.VolumeGet, rpc.OptArgsQuery())
 
@@ -82,6 +86,10 @@ func NewHandler(service *Service) *rpc.Router {
 
 	rpc.GET("/v2/volume/list", service.V2VolumeList, rpc.OptArgsQuery())
 
+	rpc.GET("/volume/health/get", service.VolumeHealthGet, rpc.OptArgsQuery())
+
+	rpc.GET("/volume/health/list", service.VolumeHealthList, rpc.OptArgsQuery())
+
 	rpc.POST("/volume/alloc", service.VolumeAlloc, rpc.OptArgsBody())
 
 	rpc.POST("/volume/update", service.VolumeUpdate, rpc.OptArgsBody())
@@ -49,8 +49,10 @@ type DiskInfoRecord struct {
 	Rack         string           `json:"rack"`
 	Host         string           `json:"host"`
 	Path         string           `json:"path"`
+	DiskSetID    proto.DiskSetID  `json:"disk_set_id"`
 	Status       proto.DiskStatus `json:"status"`
 	Readonly     bool             `json:"readonly"`
+	Cold         bool             `json:"cold"`
 	MaxChunkCnt  int64            `json:"max_chunk_cnt"`
 	FreeChunkCnt int64            `json:"free_chunk_cnt"`
 	UsedChunkCnt int64            `json:"used_chunk_cnt"`
@@ -393,6 +393,15 @@ func (c *Config) checkAndFix() (err error) {
 	if len(c.CodeModePolicies) == 0 {
 		return errors.New("invalid code mode config")
 	}
+	for _, modePolicy := range c.CodeModePolicies {
+		if !modePolicy.ModeName.IsValid() {
+			return fmt.Errorf("invalid code mode name %s", modePolicy.ModeName)
+		}
+		tactic := modePolicy.ModeName.Tactic()
+		if err := tactic.Validate(); err != nil {
+			return fmt.Errorf("code mode %s: %s", modePolicy.ModeName, err.Error())
+		}
+	}
 	sort.Slice(c.CodeModePolicies, func(i, j int) bool {
 		return c.CodeModePolicies[i].MinSize < c.CodeModePolicies[j].MinSize
 	})
@@ -34,6 +34,7 @@ import (
 
 const (
 	maxReportChunkBodyLength = 1 << 23
+	defaultListKvCount       = 1000
 )
 
 func (s *Service) VolumeGet(c *rpc.Context) {
@@ -89,6 +90,101 @@ func (s *Service) VolumeList(c *rpc.Context) {
 	}
 }
 
+func (s *Service) VolumeHealthGet(c *rpc.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContextSafe(ctx)
+	args := new(clustermgr.GetVolumeArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	span.Debugf("accept VolumeHealthGet request, args: %v", args)
+
+	if err := s.raftNode.ReadIndex(ctx); err != nil {
+		span.Errorf("get read index error: %v", err)
+		c.RespondError(apierrors.ErrRaftReadIndex)
+		return
+	}
+
+	var inspect *proto.VolumeInspectResult
+	value, err := s.KvMgr.Get(proto.VolumeInspectResultKey(args.Vid))
+	if err != nil && err != kvstore.ErrNotFound {
+		span.Errorf("get volume inspect result error,vid is: %v, error:%v", args.Vid, err)
+		c.RespondError(apierrors.ErrCMUnexpect)
+		return
+	}
+	if err == nil {
+		inspect = new(proto.VolumeInspectResult)
+		if err = json.Unmarshal(value, inspect); err != nil {
+			span.Errorf("decode volume inspect result error,vid is: %v, error:%v", args.Vid, err)
+			c.RespondError(apierrors.ErrCMUnexpect)
+			return
+		}
+	}
+
+	ret, err := s.VolumeMgr.GetVolumeHealth(ctx, args.Vid, inspect)
+	if err != nil {
+		span.Errorf("get volume health error,vid is: %v, error:%v", args.Vid, err)
+		c.RespondError(err)
+		return
+	}
+	c.RespondJSON(ret)
+}
+
+func (s *Service) VolumeHealthList(c *rpc.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContextSafe(ctx)
+	args := new(clustermgr.ListVolumeHealthArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	span.Debugf("accept VolumeHealthList request, args: %v", args)
+
+	if err := s.raftNode.ReadIndex(ctx); err != nil {
+		span.Errorf("list read index error: %v", err)
+		c.RespondError(apierrors.ErrRaftReadIndex)
+		return
+	}
+
+	inspects, err := s.listVolumeInspectResults()
+	if err != nil {
+		span.Errorf("list volume inspect results error: %v", err)
+		c.RespondError(apierrors.ErrCMUnexpect)
+		return
+	}
+	healths, err := s.VolumeMgr.ListVolumeHealth(ctx, args.Count, inspects)
+	if err != nil {
+		span.Errorf("list volume health error,args is: %v, error:%v", args, err)
+		c.RespondError(err)
+		return
+	}
+	c.RespondJSON(&clustermgr.ListVolumeHealthRet{Volumes: healths})
+}
+
+// listVolumeInspectResults returns the last inspect results of volumes which missed shards, saved by scheduler
+func (s *Service) listVolumeInspectResults() (map[proto.Vid]*proto.VolumeInspectResult, error) {
+	inspects := make(map[proto.Vid]*proto.VolumeInspectResult)
+	opts := &clustermgr.ListKvOpts{Prefix: proto.VolumeInspectResultKeyPrefix, Count: defaultListKvCount}
+	for {
+		ret, err := s.KvMgr.List(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range ret.Kvs {
+			inspect := new(proto.VolumeInspectResult)
+			if err = json.Unmarshal(kv.Value, inspect); err != nil {
+				return nil, err
+			}
+			inspects[inspect.Vid] = inspect
+		}
+		if ret.Marker == "" {
+			return inspects, nil
+		}
+		opts.Marker = ret.Marker
+	}
+}
+
 // transport to primary and params check
 func (s *Service) VolumeAlloc(c *rpc.Context) {
 	ctx := c.Request.Context()
@@ -266,7 +362,7 @@ func (s *Service) VolumeUnitAlloc(c *rpc.Context) {
 	}
 	span.Debugf("accept VolumeUnitAlloc request, args: %v", args)
 
-	ret, err := s.VolumeMgr.AllocVolumeUnit(ctx, args.Vuid)
+	ret, err := s.VolumeMgr.AllocVolumeUnit(ctx, args)
 	if err != nil {
 		span.Error("alloc volumeUnit failed, err: ", errors.Detail(err))
 		c.RespondError(err)
@@ -16,6 +16,7 @@ package clustermgr
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -121,6 +122,29 @@ func TestService_VolumeInfo(t *testing.T) {
 		require.Equal(t, proto.Vid(5), list.Volumes[3].Vid)
 	}
 
+	// volume health
+	{
+		inspect := &proto.VolumeInspectResult{Vid: 2, MissedBlobs: 1, MaxMissedShards: 20}
+		value, err := json.Marshal(inspect)
+		require.NoError(t, err)
+		require.NoError(t, cmClient.SetKV(ctx, proto.VolumeInspectResultKey(inspect.Vid), value))
+
+		health, err := cmClient.GetVolumeHealth(ctx, &clustermgr.GetVolumeArgs{Vid: 2})
+		require.NoError(t, err)
+		require.Equal(t, inspect, health.LastInspect)
+		health, err = cmClient.GetVolumeHealth(ctx, &clustermgr.GetVolumeArgs{Vid: 1})
+		require.NoError(t, err)
+		require.Nil(t, health.LastInspect)
+		_, err = cmClient.GetVolumeHealth(ctx, &clustermgr.GetVolumeArgs{Vid: 100})
+		require.Error(t, err)
+
+		ret, err := cmClient.ListVolumeHealth(ctx, &clustermgr.ListVolumeHealthArgs{Count: 2})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(ret.Volumes))
+		require.Equal(t, proto.Vid(2), ret.Volumes[0].Vid)
+		require.LessOrEqual(t, ret.Volumes[0].Score, ret.Volumes[1].Score)
+	}
+
 	// list volume info v2
 	{
 		ret, err := cmClient.ListVolumeV2(ctx, &clustermgr.ListVolumeV2Args{Status: proto.VolumeStatusIdle})
@@ -0,0 +1,123 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package volumemgr
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cubefs/cubefs/blobstore/api/blobnode"
+	cm "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	apierrors "github.com/cubefs/cubefs/blobstore/common/errors"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+)
+
+const defaultListVolumeHealthCount = 100
+
+// GetVolumeHealth returns health of the volume, inspect is the last inspect result of volume which missed shards
+func (v *VolumeMgr) GetVolumeHealth(ctx context.Context, vid proto.Vid, inspect *proto.VolumeInspectResult) (*cm.VolumeHealth, error) {
+	vol := v.all.getVol(vid)
+	if vol == nil {
+		return nil, ErrVolumeNotExist
+	}
+	return v.volumeHealth(ctx, vol, inspect, make(map[proto.DiskID]*blobnode.DiskInfo))
+}
+
+// ListVolumeHealth returns the count volumes with the lowest health score,
+// inspects are the last inspect results of volumes which missed shards
+func (v *VolumeMgr) ListVolumeHealth(ctx context.Context, count int,
+	inspects map[proto.Vid]*proto.VolumeInspectResult) ([]*cm.VolumeHealth, error) {
+	if count <= 0 {
+		count = defaultListVolumeHealthCount
+	}
+	if count > defaultListVolumeMaxCount {
+		count = defaultListVolumeMaxCount
+	}
+
+	// do not compute health in rangeVol, it holds the read lock of shard
+	var vols []*volume
+	v.all.rangeVol(func(vol *volume) error {
+		vols = append(vols, vol)
+		return nil
+	})
+
+	disks := make(map[proto.DiskID]*blobnode.DiskInfo)
+	rets := make([]*cm.VolumeHealth, 0, len(vols))
+	for _, vol := range vols {
+		health, err := v.volumeHealth(ctx, vol, inspects[vol.vid], disks)
+		if err != nil {
+			return nil, err
+		}
+		rets = append(rets, health)
+	}
+	sort.Slice(rets, func(i, j int) bool {
+		if rets[i].Score != rets[j].Score {
+			return rets[i].Score < rets[j].Score
+		}
+		return rets[i].Vid < rets[j].Vid
+	})
+	if len(rets) > count {
+		rets = rets[:count]
+	}
+	return rets, nil
+}
+
+// volumeHealth counts the units on normal disks as healthy, units on broken or repairing disks are pending repairs,
+// the score is the parity units count minus the most lost units of volume and blob
+func (v *VolumeMgr) volumeHealth(ctx context.Context, vol *volume, inspect *proto.VolumeInspectResult,
+	disks map[proto.DiskID]*blobnode.DiskInfo) (*cm.VolumeHealth, error) {
+	vol.lock.RLock()
+	health := &cm.VolumeHealth{
+		Vid:         vol.vid,
+		CodeMode:    vol.volInfoBase.CodeMode,
+		Status:      vol.volInfoBase.Status,
+		TotalUnits:  len(vol.vUnits),
+		LastInspect: inspect,
+	}
+	diskIDs := make([]proto.DiskID, 0, len(vol.vUnits))
+	for _, vu := range vol.vUnits {
+		diskIDs = append(diskIDs, vu.vuInfo.DiskID)
+	}
+	vol.lock.RUnlock()
+
+	for _, diskID := range diskIDs {
+		disk, ok := disks[diskID]
+		if !ok {
+			var err error
+			disk, err = v.diskMgr.GetDiskInfo(ctx, diskID)
+			if err != nil && err != apierrors.ErrCMDiskNotFound {
+				return nil, err
+			}
+			disks[diskID] = disk
+		}
+		if disk == nil {
+			continue
+		}
+		switch disk.Status {
+		case proto.DiskStatusNormal:
+			health.HealthyUnits++
+		case proto.DiskStatusBroken, proto.DiskStatusRepairing:
+			health.PendingRepairs++
+		default:
+		}
+	}
+
+	lost := health.TotalUnits - health.HealthyUnits
+	if inspect != nil && inspect.MaxMissedShards > lost {
+		lost = inspect.MaxMissedShards
+	}
+	health.Score = health.CodeMode.Tactic().M - lost
+	return health, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package volumemgr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/api/blobnode"
+	apierrors "github.com/cubefs/cubefs/blobstore/common/errors"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+)
+
+func TestVolumeMgr_VolumeHealth(t *testing.T) {
+	mockVolumeMgr, clean := initMockVolumeMgr(t)
+	defer clean()
+
+	_, ctx := trace.StartSpanFromContext(context.Background(), "VolumeHealth")
+	// units of every volume are on disk [1,27], disk 1 is broken, disk 2 is repairing and disk 3 is not found
+	mockDiskMgr := NewMockDiskMgrAPI(gomock.NewController(t))
+	mockDiskMgr.EXPECT().GetDiskInfo(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id proto.DiskID) (*blobnode.DiskInfo, error) {
+			status := proto.DiskStatusNormal
+			switch id {
+			case 1:
+				status = proto.DiskStatusBroken
+			case 2:
+				status = proto.DiskStatusRepairing
+			case 3:
+				return nil, apierrors.ErrCMDiskNotFound
+			}
+			return &blobnode.DiskInfo{DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{DiskID: id}, Status: status}, nil
+		})
+	mockVolumeMgr.diskMgr = mockDiskMgr
+
+	health, err := mockVolumeMgr.GetVolumeHealth(ctx, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, proto.Vid(2), health.Vid)
+	require.Equal(t, 27, health.TotalUnits)
+	require.Equal(t, 24, health.HealthyUnits)
+	require.Equal(t, 2, health.PendingRepairs)
+	require.Equal(t, 9, health.Score)
+	require.Nil(t, health.LastInspect)
+
+	inspect := &proto.VolumeInspectResult{Vid: 5, MissedBlobs: 1, MaxMissedShards: 5}
+	health, err = mockVolumeMgr.GetVolumeHealth(ctx, 5, inspect)
+	require.NoError(t, err)
+	require.Equal(t, 7, health.Score)
+	require.Equal(t, inspect, health.LastInspect)
+
+	_, err = mockVolumeMgr.GetVolumeHealth(ctx, 31, nil)
+	require.Equal(t, ErrVolumeNotExist, err)
+
+	// list by score
+	inspects := map[proto.Vid]*proto.VolumeInspectResult{5: inspect}
+	healths, err := mockVolumeMgr.ListVolumeHealth(ctx, 2, inspects)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(healths))
+	require.Equal(t, proto.Vid(5), healths[0].Vid)
+	require.Equal(t, proto.Vid(0), healths[1].Vid)
+
+	healths, err = mockVolumeMgr.ListVolumeHealth(ctx, 0, inspects)
+	require.NoError(t, err)
+	require.Equal(t, volumeCount, len(healths))
+
+	// failed case
+	errMock := errors.New("mock error")
+	mockDiskMgr = NewMockDiskMgrAPI(gomock.NewController(t))
+	mockDiskMgr.EXPECT().GetDiskInfo(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, errMock)
+	mockVolumeMgr.diskMgr = mockDiskMgr
+	_, err = mockVolumeMgr.ListVolumeHealth(ctx, 10, nil)
+	require.True(t, errors.Is(err, errMock))
+}
@@ -104,7 +104,8 @@ type VolumeMgrAPI interface {
 	DiskWritableChange(ctx context.Context, diskID proto.DiskID) (err error)
 
 	// AllocVolumeUnit alloc a new chunk to volume unit, it will increase volumeUnit's nextEpoch in memory
-	AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*cm.AllocVolumeUnit, error)
+	// new chunk will be allocated on cold disk when cold is true or the volume unit is on cold disk already
+	AllocVolumeUnit(ctx context.Context, args *cm.AllocVolumeUnitArgs) (*cm.AllocVolumeUnit, error)
 
 	// ReleaseVolumeUnit release old volume unit's chunk
 	ReleaseVolumeUnit(ctx context.Context, vuid proto.Vuid, diskID proto.DiskID, force bool) (err error)
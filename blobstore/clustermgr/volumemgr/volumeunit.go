@@ -49,8 +49,9 @@ func (v *VolumeMgr) ListVolumeUnitInfo(ctx context.Context, args *cmapi.ListVolu
 	return ret, nil
 }
 
-func (v *VolumeMgr) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*cmapi.AllocVolumeUnit, error) {
+func (v *VolumeMgr) AllocVolumeUnit(ctx context.Context, args *cmapi.AllocVolumeUnitArgs) (*cmapi.AllocVolumeUnit, error) {
 	span := trace.SpanFromContextSafe(ctx)
+	vuid := args.Vuid
 	vid := vuid.Vid()
 	vol := v.all.getVol(vid)
 	if vol == nil {
@@ -64,8 +65,13 @@ func (v *VolumeMgr) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*cmap
 		return nil, ErrVolumeUnitNotExist
 	}
 	nextEpoch := vol.vUnits[index].nextEpoch + 1
+	targetDiskID := vol.vUnits[index].vuInfo.DiskID
 	vol.lock.RUnlock()
 
+	diskInfo, err := v.diskMgr.GetDiskInfo(ctx, targetDiskID)
+	if err != nil {
+		return nil, errors.Info(err, "get disk info failed").Detail(err)
+	}
 	pendingVuidKey := uuid.New().String()
 	v.pendingEntries.Store(pendingVuidKey, proto.Vuid(0))
 	// clear pending entry key
@@ -86,21 +92,27 @@ func (v *VolumeMgr) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*cmap
 	}
 
 	excludes := make([]proto.DiskID, 0)
-	targetDiskID := proto.DiskID(0)
 	vol.lock.RLock()
-	targetDiskID = vol.vUnits[vuid.Index()].vuInfo.DiskID
 	for _, vu := range vol.vUnits {
 		excludes = append(excludes, vu.vuInfo.DiskID)
 	}
 	vol.lock.RUnlock()
 
-	diskInfo, err := v.diskMgr.GetDiskInfo(ctx, targetDiskID)
-	if err != nil {
-		return nil, errors.Info(err, "get disk info failed").Detail(err)
+	// keep the volume unit in cold storage tier when repair or balance a cold volume unit
+	policy := &diskmgr.AllocPolicy{
+		Idc:      diskInfo.Idc,
+		Vuids:    []proto.Vuid{newVuid.(proto.Vuid)},
+		Excludes: excludes,
+		Cold:     args.Cold || diskInfo.Cold,
 	}
-
-	policy := &diskmgr.AllocPolicy{Idc: diskInfo.Idc, Vuids: []proto.Vuid{newVuid.(proto.Vuid)}, Excludes: excludes}
+	policy.Excludes = append(policy.Excludes, args.ExcludeDisks...)
 	allocDiskID, err := v.diskMgr.AllocChunks(ctx, policy)
+	if err != nil && len(args.ExcludeDisks) > 0 {
+		// the excluded disks are only avoided in preference
+		span.Warnf("alloc chunk excluding disks %v failed, retry without them: %v", args.ExcludeDisks, err)
+		policy.Excludes = excludes
+		allocDiskID, err = v.diskMgr.AllocChunks(ctx, policy)
+	}
 	if err != nil {
 		return nil, errors.Info(err, "alloc chunk failed").Detail(err)
 	}
@@ -75,19 +75,19 @@ func TestVolumeMgr_AllocVolumeUnit(t *testing.T) {
 		return nil
 	})
 	mockVolumeMgr.raftServer = mockRaftServer
-	ret, err := mockVolumeMgr.AllocVolumeUnit(ctx, proto.EncodeVuid(vuidPrefix, 1))
+	ret, err := mockVolumeMgr.AllocVolumeUnit(ctx, &clustermgr.AllocVolumeUnitArgs{Vuid: proto.EncodeVuid(vuidPrefix, 1)})
 	require.NoError(t, err)
 	require.Equal(t, ret.Vuid, proto.EncodeVuid(vuidPrefix, 3))
 	require.NotEqual(t, ret.DiskID, 0)
 
 	// failed case,raft propose error
 	mockRaftServer.EXPECT().Propose(gomock.Any(), gomock.Any()).Return(errors.New("error"))
-	ret, err = mockVolumeMgr.AllocVolumeUnit(ctx, proto.EncodeVuid(vuidPrefix, 1))
+	ret, err = mockVolumeMgr.AllocVolumeUnit(ctx, &clustermgr.AllocVolumeUnitArgs{Vuid: proto.EncodeVuid(vuidPrefix, 1)})
 	require.Error(t, err)
 	require.Nil(t, ret)
 
 	// failed case:vid not exist
-	ret, err = mockVolumeMgr.AllocVolumeUnit(ctx, proto.EncodeVuid(proto.EncodeVuidPrefix(44, 1), 1))
+	ret, err = mockVolumeMgr.AllocVolumeUnit(ctx, &clustermgr.AllocVolumeUnitArgs{Vuid: proto.EncodeVuid(proto.EncodeVuidPrefix(44, 1), 1)})
 	require.Error(t, err)
 	require.Nil(t, ret)
 
@@ -100,15 +100,58 @@ func TestVolumeMgr_AllocVolumeUnit(t *testing.T) {
 		})
 		return nil
 	})
-	ret, err = mockVolumeMgr.AllocVolumeUnit(ctx, proto.EncodeVuid(vuidPrefix, 1))
+	ret, err = mockVolumeMgr.AllocVolumeUnit(ctx, &clustermgr.AllocVolumeUnitArgs{Vuid: proto.EncodeVuid(vuidPrefix, 1)})
 	require.Error(t, err)
 	require.Nil(t, ret)
 
 	// failed case , index over
-	_, err = mockVolumeMgr.AllocVolumeUnit(ctx, proto.EncodeVuid(proto.EncodeVuidPrefix(1, 30), 1))
+	_, err = mockVolumeMgr.AllocVolumeUnit(ctx, &clustermgr.AllocVolumeUnitArgs{Vuid: proto.EncodeVuid(proto.EncodeVuidPrefix(1, 30), 1)})
 	require.Error(t, err)
 }
 
+func TestVolumeMgr_AllocVolumeUnitExcludeDisks(t *testing.T) {
+	mockVolumeMgr, clean := initMockVolumeMgr(t)
+	defer clean()
+
+	var vuidPrefix proto.VuidPrefix = 4294967296
+	ctr := gomock.NewController(t)
+	mockRaftServer := mocks.NewMockRaftServer(ctr)
+	mockRaftServer.EXPECT().Propose(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, data []byte) error {
+		mockVolumeMgr.pendingEntries.Range(func(key, value interface{}) bool {
+			mockVolumeMgr.pendingEntries.Store(key, proto.EncodeVuid(vuidPrefix, 3))
+			return true
+		})
+		return nil
+	})
+	mockVolumeMgr.raftServer = mockRaftServer
+
+	// only the excluded disk 100 is available
+	var allocExcludes [][]proto.DiskID
+	mockDiskMgr := NewMockDiskMgrAPI(ctr)
+	mockDiskMgr.EXPECT().AllocChunks(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, policy *diskmgr.AllocPolicy) ([]proto.DiskID, error) {
+		allocExcludes = append(allocExcludes, policy.Excludes)
+		for _, diskID := range policy.Excludes {
+			if diskID == 100 {
+				return nil, errors.New("no enough space")
+			}
+		}
+		return []proto.DiskID{100}, nil
+	})
+	mockDiskMgr.EXPECT().GetDiskInfo(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(mockGetDiskInfo)
+	mockVolumeMgr.diskMgr = mockDiskMgr
+
+	_, ctx := trace.StartSpanFromContext(context.Background(), "")
+	ret, err := mockVolumeMgr.AllocVolumeUnit(ctx, &clustermgr.AllocVolumeUnitArgs{
+		Vuid:         proto.EncodeVuid(vuidPrefix, 1),
+		ExcludeDisks: []proto.DiskID{100},
+	})
+	require.NoError(t, err)
+	require.Equal(t, proto.DiskID(100), ret.DiskID)
+	require.Equal(t, 2, len(allocExcludes))
+	require.Equal(t, len(allocExcludes[0]), len(allocExcludes[1])+1)
+	require.NotContains(t, allocExcludes[1], proto.DiskID(100))
+}
+
 func TestVolumeMgr_applyAllocVolumeUnit(t *testing.T) {
 	mockVolumeMgr, clean := initMockVolumeMgr(t)
 	defer clean()
@@ -30,6 +30,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/common/config"
 	"github.com/cubefs/cubefs/blobstore/common/profile"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/rpc/admin"
 	"github.com/cubefs/cubefs/blobstore/common/rpc/auditlog"
 	"github.com/cubefs/cubefs/blobstore/common/rpc/auth"
 	"github.com/cubefs/cubefs/blobstore/util/graceful"
@@ -59,6 +60,7 @@ type Config struct {
 
 	AuditLog auditlog.Config `json:"auditlog"`
 	Auth     auth.Config     `json:"auth"`
+	Admin    admin.Config    `json:"admin"`
 }
 
 type Module struct {
@@ -66,7 +68,9 @@ type Module struct {
 	InitConfig func(args []string) (*Config, error)
 	SetUp      func() (*rpc.Router, []rpc.ProgressHandler)
 	TearDown   func()
-	graceful   bool
+	// HealthCheck optional checker of health endpoint of admin server
+	HealthCheck admin.HealthChecker
+	graceful    bool
 }
 
 var mod *Module
@@ -151,6 +155,7 @@ func Main(args []string) {
 					log.Fatal("server exits:", err)
 				}
 			}()
+			adminServer := startAdminServer(&cfg.Admin)
 
 			// wait for signal
 			<-state.CloseCh
@@ -158,6 +163,9 @@ func Main(args []string) {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutS)*time.Second)
 			defer cancel()
 			httpServer.Shutdown(ctx)
+			if adminServer != nil {
+				adminServer.Shutdown(ctx)
+			}
 
 			if mod.TearDown != nil {
 				mod.TearDown()
@@ -184,6 +192,7 @@ func Main(args []string) {
 			log.Fatalf("Server exits, err: %v", err)
 		}
 	}()
+	adminServer := startAdminServer(&cfg.Admin)
 
 	// wait for signal
 	ch := make(chan os.Signal, 1)
@@ -193,12 +202,35 @@ func Main(args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutS)*time.Second)
 	defer cancel()
 	httpServer.Shutdown(ctx)
+	if adminServer != nil {
+		adminServer.Shutdown(ctx)
+	}
 
 	if mod.TearDown != nil {
 		mod.TearDown()
 	}
 }
 
+// startAdminServer serves admin mux if configured, admin server exits
+// with error logging only to keep the service running
+func startAdminServer(cfg *admin.Config) *http.Server {
+	if !cfg.Enabled() {
+		return nil
+	}
+	adminServer := &http.Server{
+		Addr:    cfg.BindAddr,
+		Handler: admin.NewHandler(cfg, mod.HealthCheck),
+	}
+
+	log.Info("admin server is running at", cfg.BindAddr)
+	go func() {
+		if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("admin server exits, err: %v", err)
+		}
+	}()
+	return adminServer
+}
+
 // reorderMiddleWareHandlers
 //
 //	the order of handlers in MiddlewareHandler has some constraints:
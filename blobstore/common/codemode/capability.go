@@ -0,0 +1,131 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package codemode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// max shards of one reed-solomon stripe
+const maxStripeShards = 256
+
+// ErrInvalidTactic tactic is not valid
+var ErrInvalidTactic = errors.New("invalid tactic")
+
+// Capability fault tolerance and reconstruction cost of tactic
+type Capability struct {
+	// MaxFailures max count of any lost shards in global stripe
+	MaxFailures int `json:"max_failures"`
+	// TolerableFailuresPerAZ min count of lost shards tolerable in every AZ at the same time,
+	// recovered by local parity or global parity
+	TolerableFailuresPerAZ int `json:"tolerable_failures_per_az"`
+	// TolerableAZFailures count of whole AZ down tolerable
+	TolerableAZFailures int `json:"tolerable_az_failures"`
+	// AZDownSafeQuorum data written with PutQuorum shards is still recoverable if one AZ was down
+	AZDownSafeQuorum bool `json:"az_down_safe_quorum"`
+
+	// GlobalReconstructCost shards read to reconstruct one shard by global stripe
+	GlobalReconstructCost int `json:"global_reconstruct_cost"`
+	// CrossAZReconstructCost min shards read from other AZs to reconstruct one shard by global stripe
+	CrossAZReconstructCost int `json:"cross_az_reconstruct_cost"`
+	// LocalReconstructCost shards read to reconstruct one shard in local stripe, 0 if no local parity
+	LocalReconstructCost int `json:"local_reconstruct_cost"`
+	// StorageOverhead ratio of all shards to data shards
+	StorageOverhead float64 `json:"storage_overhead"`
+}
+
+// Validate returns the reason why the tactic is invalid,
+// the shard counts must be divided by AZCount and local stripes must cover all shards exactly once
+func (c *Tactic) Validate() error {
+	if c.N <= 0 || c.M <= 0 || c.L < 0 || c.AZCount <= 0 {
+		return fmt.Errorf("%w: N(%d) M(%d) AZCount(%d) should be positive and L(%d) should not be negative",
+			ErrInvalidTactic, c.N, c.M, c.AZCount, c.L)
+	}
+	if c.N%c.AZCount != 0 || c.M%c.AZCount != 0 || c.L%c.AZCount != 0 {
+		return fmt.Errorf("%w: N(%d) M(%d) L(%d) should be divided by AZCount(%d)",
+			ErrInvalidTactic, c.N, c.M, c.L, c.AZCount)
+	}
+	if c.N+c.M > maxStripeShards {
+		return fmt.Errorf("%w: N+M(%d) should not be more than %d", ErrInvalidTactic, c.N+c.M, maxStripeShards)
+	}
+	if c.PutQuorum < c.N || c.PutQuorum > c.N+c.M {
+		return fmt.Errorf("%w: PutQuorum(%d) should be in [%d, %d]", ErrInvalidTactic, c.PutQuorum, c.N, c.N+c.M)
+	}
+	if c.GetQuorum < 0 || c.GetQuorum > c.N+c.M {
+		return fmt.Errorf("%w: GetQuorum(%d) should be in [0, %d]", ErrInvalidTactic, c.GetQuorum, c.N+c.M)
+	}
+	if c.MinShardSize < 0 {
+		return fmt.Errorf("%w: MinShardSize(%d) should not be negative", ErrInvalidTactic, c.MinShardSize)
+	}
+	if c.L == 0 {
+		return nil
+	}
+
+	stripes, n, m := c.AllLocalStripe()
+	if n+m > maxStripeShards {
+		return fmt.Errorf("%w: local stripe shards(%d) should not be more than %d", ErrInvalidTactic, n+m, maxStripeShards)
+	}
+	covered := make([]bool, c.N+c.M+c.L)
+	for _, stripe := range stripes {
+		if len(stripe) != n+m {
+			return fmt.Errorf("%w: local stripe %v should have %d shards", ErrInvalidTactic, stripe, n+m)
+		}
+		for _, idx := range stripe {
+			if idx < 0 || idx >= len(covered) || covered[idx] {
+				return fmt.Errorf("%w: shard %d is out of range or in more than one local stripe", ErrInvalidTactic, idx)
+			}
+			covered[idx] = true
+		}
+	}
+	for idx, ok := range covered {
+		if !ok {
+			return fmt.Errorf("%w: shard %d is not in any local stripe", ErrInvalidTactic, idx)
+		}
+	}
+	return nil
+}
+
+// Capability returns fault tolerance and reconstruction cost of the valid tactic
+func (c *Tactic) Capability() Capability {
+	n, m, l := c.N/c.AZCount, c.M/c.AZCount, c.L/c.AZCount
+	capability := Capability{
+		MaxFailures:            c.M,
+		TolerableFailuresPerAZ: m,
+		TolerableAZFailures:    c.M / (n + m),
+		GlobalReconstructCost:  c.N,
+		StorageOverhead:        float64(c.N+c.M+c.L) / float64(c.N),
+	}
+	// all lost shards of AZ are recovered by local parity if not more than local parity count
+	if l > capability.TolerableFailuresPerAZ {
+		capability.TolerableFailuresPerAZ = l
+	}
+	if c.AZCount > 1 {
+		capability.AZDownSafeQuorum = c.PutQuorum >= c.N+n+m
+		// the other global shards in the same AZ of lost one are read firstly
+		if cost := c.N - (n + m - 1); cost > 0 {
+			capability.CrossAZReconstructCost = cost
+		}
+	}
+	if c.L > 0 {
+		capability.LocalReconstructCost = n + m
+	}
+	return capability
+}
+
+// Capability returns fault tolerance and reconstruction cost of codemode
+func (c CodeMode) Capability() Capability {
+	return c.T().Capability()
+}
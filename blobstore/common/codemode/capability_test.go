@@ -0,0 +1,81 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package codemode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTacticValidate(t *testing.T) {
+	for _, cm := range append(GetAllCodeModes(), EC12P9) {
+		require.NoError(t, cm.T().Validate(), cm.String())
+	}
+
+	for _, tactic := range []Tactic{
+		{N: 0, M: 3, AZCount: 1, PutQuorum: 3},
+		{N: 3, M: 0, AZCount: 1, PutQuorum: 3},
+		{N: 3, M: 3, L: -1, AZCount: 1, PutQuorum: 3},
+		{N: 3, M: 3, AZCount: 0, PutQuorum: 3},
+		{N: 4, M: 3, AZCount: 2, PutQuorum: 6},
+		{N: 4, M: 4, L: 1, AZCount: 2, PutQuorum: 6},
+		{N: 200, M: 100, AZCount: 1, PutQuorum: 200},
+		{N: 6, M: 6, AZCount: 3, PutQuorum: 5},
+		{N: 6, M: 6, AZCount: 3, PutQuorum: 13},
+		{N: 6, M: 6, AZCount: 3, PutQuorum: 11, GetQuorum: -1},
+		{N: 6, M: 6, AZCount: 3, PutQuorum: 11, GetQuorum: 13},
+		{N: 6, M: 6, AZCount: 3, PutQuorum: 11, MinShardSize: -1},
+		{N: 100, M: 100, L: 100, AZCount: 1, PutQuorum: 200},
+	} {
+		err := tactic.Validate()
+		require.True(t, errors.Is(err, ErrInvalidTactic), "%+v", tactic)
+	}
+}
+
+func TestTacticCapability(t *testing.T) {
+	cases := []struct {
+		mode       CodeMode
+		capability Capability
+	}{
+		{EC6P6, Capability{
+			MaxFailures: 6, TolerableFailuresPerAZ: 2, TolerableAZFailures: 1, AZDownSafeQuorum: true,
+			GlobalReconstructCost: 6, CrossAZReconstructCost: 3, StorageOverhead: 2,
+		}},
+		{EC15P12, Capability{
+			MaxFailures: 12, TolerableFailuresPerAZ: 4, TolerableAZFailures: 1, AZDownSafeQuorum: true,
+			GlobalReconstructCost: 15, CrossAZReconstructCost: 7, StorageOverhead: 27.0 / 15,
+		}},
+		{EC6P10L2, Capability{
+			MaxFailures: 10, TolerableFailuresPerAZ: 5, TolerableAZFailures: 1, AZDownSafeQuorum: true,
+			GlobalReconstructCost: 6, LocalReconstructCost: 8, StorageOverhead: 3,
+		}},
+		{EC6P6L9, Capability{
+			MaxFailures: 6, TolerableFailuresPerAZ: 3, TolerableAZFailures: 1, AZDownSafeQuorum: true,
+			GlobalReconstructCost: 6, CrossAZReconstructCost: 3, LocalReconstructCost: 4, StorageOverhead: 21.0 / 6,
+		}},
+		{EC4P4L2, Capability{
+			MaxFailures: 4, TolerableFailuresPerAZ: 2, TolerableAZFailures: 1,
+			GlobalReconstructCost: 4, CrossAZReconstructCost: 1, LocalReconstructCost: 4, StorageOverhead: 2.5,
+		}},
+		{EC12P4, Capability{
+			MaxFailures: 4, TolerableFailuresPerAZ: 4, GlobalReconstructCost: 12, StorageOverhead: 16.0 / 12,
+		}},
+	}
+	for _, cs := range cases {
+		require.Equal(t, cs.capability, cs.mode.Capability(), cs.mode.String())
+	}
+}
@@ -35,6 +35,7 @@ var (
 	ErrInvalidCodeMode = errors.New("invalid code mode")
 	ErrVerify          = errors.New("shards verify failed")
 	ErrInvalidShards   = errors.New("invalid shards")
+	ErrInvalidRange    = errors.New("invalid range")
 )
 
 // Encoder normal ec encoder, implements all these functions
@@ -57,6 +58,9 @@ type Encoder interface {
 	GetShardsInIdc(shards [][]byte, idx int) [][]byte
 	// output source data into dst(io.Writer)
 	Join(dst io.Writer, shards [][]byte, outSize int) error
+	// output the range [offset, offset+length) of source data into dst(io.Writer),
+	// the data shards are written directly without buffering the whole source data
+	JoinN(dst io.Writer, shards [][]byte, offset, length int) error
 	// verify parity shards with data shards
 	Verify(shards [][]byte) (bool, error)
 }
@@ -179,6 +183,53 @@ func (e *encoder) Join(dst io.Writer, shards [][]byte, outSize int) error {
 	return e.engine.Join(dst, shards, outSize)
 }
 
+func (e *encoder) JoinN(dst io.Writer, shards [][]byte, offset, length int) error {
+	if len(shards) < e.CodeMode.N {
+		return ErrInvalidShards
+	}
+	return joinN(dst, shards[:e.CodeMode.N], offset, length)
+}
+
+// joinN writes the range of data shards into dst shard by shard
+func joinN(dst io.Writer, dataShards [][]byte, offset, length int) error {
+	if offset < 0 || length < 0 {
+		return ErrInvalidRange
+	}
+	size := 0
+	for _, shard := range dataShards {
+		size += len(shard)
+	}
+	if offset+length > size {
+		return ErrShortData
+	}
+
+	for _, shard := range dataShards {
+		if length == 0 {
+			break
+		}
+		l := len(shard)
+		if offset >= l {
+			offset -= l
+			continue
+		}
+
+		toWrite := l - offset
+		if toWrite > length {
+			toWrite = length
+		}
+		n, err := dst.Write(shard[offset : offset+toWrite])
+		if err != nil {
+			return err
+		}
+		if n != toWrite {
+			return io.ErrShortWrite
+		}
+		offset = 0
+		length -= toWrite
+	}
+	return nil
+}
+
 func initBadShards(shards [][]byte, badIdx []int) {
 	for _, i := range badIdx {
 		if shards[i] != nil && len(shards[i]) != 0 && cap(shards[i]) > 0 {
@@ -17,6 +17,7 @@ package ec
 import (
 	"bytes"
 	"crypto/rand"
+	"io"
 	mrand "math/rand"
 	"reflect"
 	"testing"
@@ -305,3 +306,57 @@ func testLrcReconstruct(t *testing.T, cm codemode.CodeMode) {
 		}
 	}
 }
+
+type shortWriter struct {
+	bytes.Buffer
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:len(p)-1]
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestEncoderJoinN(t *testing.T) {
+	data := make([]byte, 1<<20+1)
+	rand.Read(data)
+	for _, mode := range []codemode.CodeMode{codemode.EC6P6, codemode.EC6P10L2} {
+		encoder, err := NewEncoder(Config{CodeMode: mode.Tactic()})
+		require.NoError(t, err)
+		shards, err := encoder.Split(data)
+		require.NoError(t, err)
+		require.NoError(t, encoder.Encode(shards))
+
+		shardSize := len(shards[0])
+		for _, r := range [][2]int{
+			{0, 0},
+			{0, len(data)},
+			{0, 1},
+			{len(data) - 1, 1},
+			{shardSize - 1, 2},
+			{shardSize, shardSize},
+			{shardSize + 7, 3*shardSize + 11},
+			{mrand.Intn(len(data)), 0},
+		} {
+			offset, length := r[0], r[1]
+			if offset+length > len(data) {
+				length = len(data) - offset
+			}
+			wbuff := bytes.NewBuffer(make([]byte, 0))
+			require.NoError(t, encoder.JoinN(wbuff, shards, offset, length))
+			require.Equal(t, data[offset:offset+length], wbuff.Bytes())
+		}
+
+		// padding of data shards
+		wbuff := bytes.NewBuffer(make([]byte, 0))
+		require.NoError(t, encoder.JoinN(wbuff, shards, len(data), mode.Tactic().N*shardSize-len(data)))
+		require.Equal(t, make([]byte, mode.Tactic().N*shardSize-len(data)), wbuff.Bytes())
+
+		require.ErrorIs(t, encoder.JoinN(wbuff, shards, -1, 1), ErrInvalidRange)
+		require.ErrorIs(t, encoder.JoinN(wbuff, shards, 0, -1), ErrInvalidRange)
+		require.ErrorIs(t, encoder.JoinN(wbuff, shards, 1, mode.Tactic().N*shardSize), ErrShortData)
+		require.ErrorIs(t, encoder.JoinN(wbuff, shards[:1], 0, 1), ErrInvalidShards)
+		require.ErrorIs(t, encoder.JoinN(&shortWriter{}, shards, 0, 2), io.ErrShortWrite)
+	}
+}
@@ -245,3 +245,10 @@ func (e *lrcEncoder) GetShardsInIdc(shards [][]byte, idx int) [][]byte {
 func (e *lrcEncoder) Join(dst io.Writer, shards [][]byte, outSize int) error {
 	return e.engine.Join(dst, shards[:(e.CodeMode.N+e.CodeMode.M)], outSize)
 }
+
+func (e *lrcEncoder) JoinN(dst io.Writer, shards [][]byte, offset, length int) error {
+	if len(shards) < e.CodeMode.N {
+		return ErrInvalidShards
+	}
+	return joinN(dst, shards[:e.CodeMode.N], offset, length)
+}
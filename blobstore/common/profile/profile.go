@@ -213,6 +213,12 @@ func NewProfileHandler(addr string) rpc.ProgressHandler {
 	return &profileHandler{}
 }
 
+// Handler returns the profile serve multiplexer, to serve it on a separated port.
+func Handler() http.Handler {
+	routerOnce.Do(registerRouter)
+	return httpRouter
+}
+
 // handle path /debug/ , show usage
 func index(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, router.String())
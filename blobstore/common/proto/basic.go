@@ -24,6 +24,7 @@ import (
 // basic type for all module
 type (
 	DiskID    uint32
+	DiskSetID uint32
 	BlobID    uint64
 	Vid       uint32
 	ClusterID uint32
@@ -45,6 +46,10 @@ func (id DiskID) ToString() string {
 	return strconv.FormatUint(uint64(id), 10)
 }
 
+func (id DiskSetID) ToString() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
 func (vid Vid) ToString() string {
 	return strconv.FormatUint(uint64(vid), 10)
 }
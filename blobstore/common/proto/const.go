@@ -60,10 +60,12 @@ func (status DiskStatus) String() string {
 
 const (
 	InvalidDiskID = DiskID(0)
-	InValidBlobID = BlobID(0)
-	InvalidCrc32  = uint32(0)
-	InvalidVid    = Vid(0)
-	InvalidVuid   = Vuid(0)
+	// InvalidDiskSetID means disk not belongs to any disk set, no fault domain limit
+	InvalidDiskSetID = DiskSetID(0)
+	InValidBlobID    = BlobID(0)
+	InvalidCrc32     = uint32(0)
+	InvalidVid       = Vid(0)
+	InvalidVuid      = Vuid(0)
 )
 
 const (
@@ -0,0 +1,74 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proto
+
+import (
+	"fmt"
+
+	"github.com/cubefs/cubefs/blobstore/util/errors"
+)
+
+// ErrInvalidMigrateState state of repair or migrate task is unknown or the transition is not allowed
+var ErrInvalidMigrateState = errors.New("invalid migrate state")
+
+// migrateStateTransitions allowed transitions of repair and migrate task state,
+// task of work completed state is prepared again if destination should be reallocated,
+// finished states are terminal and the task is deleted from db
+var migrateStateTransitions = map[MigrateState][]MigrateState{
+	MigrateStateInited:            {MigrateStatePrepared, MigrateStateFinishedInAdvance},
+	MigrateStatePrepared:          {MigrateStateWorkCompleted},
+	MigrateStateWorkCompleted:     {MigrateStateFinished, MigrateStatePrepared},
+	MigrateStateFinished:          nil,
+	MigrateStateFinishedInAdvance: nil,
+}
+
+// Valid returns true if the state is known
+func (s MigrateState) Valid() bool {
+	_, ok := migrateStateTransitions[s]
+	return ok
+}
+
+// Finished returns true if the state is terminal
+func (s MigrateState) Finished() bool {
+	return s == MigrateStateFinished || s == MigrateStateFinishedInAdvance
+}
+
+// CheckTransition returns error if the state is not allowed to transit to next
+func (s MigrateState) CheckTransition(next MigrateState) error {
+	for _, state := range migrateStateTransitions[s] {
+		if state == next {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: can not transit from state[%d] to state[%d]", ErrInvalidMigrateState, s, next)
+}
+
+// TransitState sets state of task to next if the transition is allowed
+func (t *MigrateTask) TransitState(next MigrateState) error {
+	if err := t.State.CheckTransition(next); err != nil {
+		return fmt.Errorf("task_id[%s]: %w", t.TaskID, err)
+	}
+	t.State = next
+	return nil
+}
+
+// CheckStoredState returns error if the task loaded from db is corrupted,
+// only the unfinished task with known state is stored
+func (t *MigrateTask) CheckStoredState() error {
+	if !t.State.Valid() || t.State.Finished() {
+		return fmt.Errorf("%w: task_id[%s] should not be stored with state[%d]", ErrInvalidMigrateState, t.TaskID, t.State)
+	}
+	return nil
+}
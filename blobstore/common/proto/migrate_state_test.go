@@ -0,0 +1,74 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proto_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+)
+
+func TestMigrateStateTransition(t *testing.T) {
+	task := &proto.MigrateTask{TaskID: "task-1", State: proto.MigrateStateInited}
+	for _, next := range []proto.MigrateState{
+		proto.MigrateStatePrepared,
+		proto.MigrateStateWorkCompleted,
+		proto.MigrateStatePrepared,
+		proto.MigrateStateWorkCompleted,
+		proto.MigrateStateFinished,
+	} {
+		require.NoError(t, task.TransitState(next))
+		require.Equal(t, next, task.State)
+	}
+
+	for _, cs := range []struct {
+		from, to proto.MigrateState
+	}{
+		{proto.MigrateStateInited, proto.MigrateStateWorkCompleted},
+		{proto.MigrateStateInited, proto.MigrateStateFinished},
+		{proto.MigrateStatePrepared, proto.MigrateStateInited},
+		{proto.MigrateStatePrepared, proto.MigrateStateFinished},
+		{proto.MigrateStateWorkCompleted, proto.MigrateStateFinishedInAdvance},
+		{proto.MigrateStateFinished, proto.MigrateStatePrepared},
+		{proto.MigrateStateFinishedInAdvance, proto.MigrateStateInited},
+		{proto.MigrateState(0), proto.MigrateStateInited},
+		{proto.MigrateStateInited, proto.MigrateState(100)},
+	} {
+		task := &proto.MigrateTask{TaskID: "task-1", State: cs.from}
+		err := task.TransitState(cs.to)
+		require.True(t, errors.Is(err, proto.ErrInvalidMigrateState), "%d -> %d", cs.from, cs.to)
+		require.Equal(t, cs.from, task.State)
+	}
+}
+
+func TestMigrateStoredState(t *testing.T) {
+	for _, state := range []proto.MigrateState{
+		proto.MigrateStateInited, proto.MigrateStatePrepared, proto.MigrateStateWorkCompleted,
+	} {
+		task := &proto.MigrateTask{TaskID: "task-1", State: state}
+		require.NoError(t, task.CheckStoredState())
+	}
+	for _, state := range []proto.MigrateState{
+		0, proto.MigrateStateFinished, proto.MigrateStateFinishedInAdvance, 100,
+	} {
+		task := &proto.MigrateTask{TaskID: "task-1", State: state}
+		err := task.CheckStoredState()
+		require.True(t, errors.Is(err, proto.ErrInvalidMigrateState))
+		require.Contains(t, err.Error(), task.TaskID)
+	}
+}
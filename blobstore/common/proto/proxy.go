@@ -101,3 +101,22 @@ func (msg *ShardRepairMsg) IsValid() bool {
 	}
 	return true
 }
+
+// BlobReadMsg is sampled read event of blob, scheduler aggregates the events to volume heat
+type BlobReadMsg struct {
+	ClusterID ClusterID `json:"cluster_id"`
+	Bid       BlobID    `json:"bid"`
+	Vid       Vid       `json:"vid"`
+	Size      uint64    `json:"size"`
+	Time      int64     `json:"time"`
+}
+
+func (msg *BlobReadMsg) IsValid() bool {
+	if msg.Bid == InValidBlobID {
+		return false
+	}
+	if msg.Vid == InvalidVid {
+		return false
+	}
+	return true
+}
@@ -46,6 +46,17 @@ func TestDeleteMsg_IsValid(t *testing.T) {
 	require.Equal(t, false, msg.IsValid())
 }
 
+func TestBlobReadMsg_IsValid(t *testing.T) {
+	msg := BlobReadMsg{ClusterID: 1, Bid: 1, Vid: 1}
+	require.Equal(t, true, msg.IsValid())
+
+	msg = BlobReadMsg{ClusterID: 1, Vid: 1}
+	require.Equal(t, false, msg.IsValid())
+
+	msg = BlobReadMsg{ClusterID: 1, Bid: 1}
+	require.Equal(t, false, msg.IsValid())
+}
+
 func TestMsgMarshal(t *testing.T) {
 	stags := BlobDeleteStage{}
 	stags.SetStage(1, DeleteStageMarkDelete)
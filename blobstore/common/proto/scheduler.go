@@ -43,12 +43,13 @@ const (
 	TaskTypeVolumeInspect TaskType = "volume_inspect"
 	TaskTypeShardRepair   TaskType = "shard_repair"
 	TaskTypeBlobDelete    TaskType = "blob_delete"
+	TaskTypeColdMigrate   TaskType = "cold_migrate"
 )
 
 func (t TaskType) Valid() bool {
 	switch t {
 	case TaskTypeDiskRepair, TaskTypeBalance, TaskTypeDiskDrop, TaskTypeManualMigrate,
-		TaskTypeVolumeInspect, TaskTypeShardRepair, TaskTypeBlobDelete:
+		TaskTypeVolumeInspect, TaskTypeShardRepair, TaskTypeBlobDelete, TaskTypeColdMigrate:
 		return true
 	default:
 		return false
@@ -101,7 +102,8 @@ type MigrateTask struct {
 	Sources  []VunitLocation   `json:"sources"`   // source volume units location
 	CodeMode codemode.CodeMode `json:"code_mode"` // codemode
 
-	Destination VunitLocation `json:"destination"` // destination volume unit location
+	Destination    VunitLocation `json:"destination"`               // destination volume unit location
+	DestinationIDC string        `json:"destination_idc,omitempty"` // idc of destination disk
 
 	Ctime string `json:"ctime"` // create time
 	MTime string `json:"mtime"` // modify time
@@ -189,6 +191,23 @@ func (inspect *VolumeInspectRet) Err() error {
 	return errors.New(inspect.InspectErrStr)
 }
 
+// VolumeInspectResultKeyPrefix kv key prefix of the last inspect result of volume which missed shards,
+// the result is saved by scheduler and shown in volume health of clustermgr
+const VolumeInspectResultKeyPrefix = "volume_inspect-result-"
+
+// VolumeInspectResultKey returns kv key of the last inspect result of volume
+func VolumeInspectResultKey(vid Vid) string {
+	return VolumeInspectResultKeyPrefix + vid.ToString()
+}
+
+// VolumeInspectResult last inspect result of volume which missed shards
+type VolumeInspectResult struct {
+	Vid             Vid   `json:"vid"`
+	MissedBlobs     int   `json:"missed_blobs"`
+	MaxMissedShards int   `json:"max_missed_shards"` // the most missed shards of one blob
+	Ctime           int64 `json:"ctime"`             // unix seconds
+}
+
 type ShardRepairTask struct {
 	Bid      BlobID            `json:"bid"`
 	CodeMode codemode.CodeMode `json:"code_mode"`
@@ -0,0 +1,248 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/util/closer"
+	"github.com/cubefs/cubefs/blobstore/util/defaulter"
+)
+
+const (
+	defaultSyncIntervalS = 10
+	listKvCount          = 1000
+
+	// kv of bucket config:  ratelimit-{name}
+	// kv of alive instance: ratelimit_instance-{name}-{host}
+	kvConfigPrefix   = "ratelimit-"
+	kvInstancePrefix = "ratelimit_instance-"
+)
+
+// ErrInvalidName name of cluster limiter is empty or contains '-'
+var ErrInvalidName = errors.New("invalid ratelimit name")
+
+// KVClient kv of clustermgr
+type KVClient interface {
+	GetKV(ctx context.Context, key string) (cmapi.GetKvRet, error)
+	SetKV(ctx context.Context, key string, value []byte) error
+	DeleteKV(ctx context.Context, key string) error
+	ListKV(ctx context.Context, args *cmapi.ListKvOpts) (cmapi.ListKvRet, error)
+}
+
+var _ KVClient = (*cmapi.Client)(nil)
+
+// ClusterConfig configuration of cluster limiter.
+// Config of the bucket is the total of cluster, it is overwritten by
+// the config stored in clustermgr kv if exists, so that it can be changed at runtime.
+type ClusterConfig struct {
+	Config
+	// Name of the bucket, instances with the same name share the total rate
+	Name string `json:"name"`
+	// Host unique id of this instance
+	Host string `json:"host"`
+	// SyncIntervalS interval of refreshing config and alive instances
+	SyncIntervalS int `json:"sync_interval_s"`
+	// ExpireS instance is considered dead if not renewed, default is 10 times of interval,
+	// registration is renewed when it is going to expire in 2 intervals
+	ExpireS int `json:"expire_s"`
+}
+
+type instanceInfo struct {
+	Host     string `json:"host"`
+	ExpireAt int64  `json:"expire_at"`
+}
+
+// ClusterLimiter limiter which rate is the share of total rate in cluster
+type ClusterLimiter struct {
+	*localLimiter
+	closer.Closer
+
+	config ClusterConfig
+	client KVClient
+	total  Config
+	alive  int
+}
+
+// ConfigKey returns kv key of the bucket config in clustermgr
+func ConfigKey(name string) string {
+	return kvConfigPrefix + name
+}
+
+func instancePrefix(name string) string {
+	return kvInstancePrefix + name + "-"
+}
+
+// SetClusterConfig stores total config of the bucket to clustermgr kv,
+// instances apply it at next sync.
+func SetClusterConfig(ctx context.Context, client KVClient, name string, cfg Config) error {
+	if name == "" || strings.Contains(name, "-") {
+		return ErrInvalidName
+	}
+	val, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return client.SetKV(ctx, ConfigKey(name), val)
+}
+
+// NewClusterLimiter returns a cluster limiter, it syncs once before returning.
+func NewClusterLimiter(cfg ClusterConfig, client KVClient) (*ClusterLimiter, error) {
+	if cfg.Name == "" || strings.Contains(cfg.Name, "-") {
+		return nil, ErrInvalidName
+	}
+	defaulter.LessOrEqual(&cfg.SyncIntervalS, defaultSyncIntervalS)
+	defaulter.LessOrEqual(&cfg.ExpireS, 10*cfg.SyncIntervalS)
+
+	lim := &ClusterLimiter{
+		localLimiter: newLocal(Config{}),
+		Closer:       closer.New(),
+		config:       cfg,
+		client:       client,
+		total:        cfg.Config,
+		alive:        1,
+	}
+	lim.apply()
+	if err := lim.sync(context.Background()); err != nil {
+		return nil, err
+	}
+	go lim.loop()
+	return lim, nil
+}
+
+// Total returns total config of the bucket and number of alive instances
+func (lim *ClusterLimiter) Total() (Config, int) {
+	lim.mu.RLock()
+	defer lim.mu.RUnlock()
+	return lim.total, lim.alive
+}
+
+// Close stops syncing and unregisters this instance
+func (lim *ClusterLimiter) Close() {
+	lim.Closer.Close()
+	span, ctx := trace.StartSpanFromContext(context.Background(), "")
+	if err := lim.client.DeleteKV(ctx, instancePrefix(lim.config.Name)+lim.config.Host); err != nil {
+		span.Warnf("unregister ratelimit instance %s failed, err: %s", lim.config.Name, err.Error())
+	}
+}
+
+func (lim *ClusterLimiter) loop() {
+	ticker := time.NewTicker(time.Duration(lim.config.SyncIntervalS) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			span, ctx := trace.StartSpanFromContext(context.Background(), "")
+			if err := lim.sync(ctx); err != nil {
+				span.Warnf("sync ratelimit %s failed, keep share %+v, err: %s",
+					lim.config.Name, lim.Config(), err.Error())
+			}
+		case <-lim.Done():
+			return
+		}
+	}
+}
+
+// sync refreshes the total config and alive instances, registration of this
+// instance is written only if it is missing, changed or going to expire
+// before next sync, so that clustermgr kv is not rewritten every interval.
+func (lim *ClusterLimiter) sync(ctx context.Context) error {
+	now := time.Now()
+	total := lim.config.Config
+	ret, err := lim.client.GetKV(ctx, ConfigKey(lim.config.Name))
+	if err == nil {
+		if err = json.Unmarshal(ret.Value, &total); err != nil {
+			return err
+		}
+	} else if rpc.DetectStatusCode(err) != http.StatusNotFound {
+		return err
+	}
+
+	alive := 0
+	registered := false
+	prefix := instancePrefix(lim.config.Name)
+	key := prefix + lim.config.Host
+	renewAt := now.Add(2 * time.Duration(lim.config.SyncIntervalS) * time.Second).Unix()
+	opts := &cmapi.ListKvOpts{Prefix: prefix, Count: listKvCount}
+	for {
+		ret, err := lim.client.ListKV(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, kv := range ret.Kvs {
+			var instance instanceInfo
+			if err = json.Unmarshal(kv.Value, &instance); err != nil || instance.ExpireAt < now.Unix() {
+				// clean the dead instance, it registers again if alive
+				if kv.Key != key {
+					lim.client.DeleteKV(ctx, kv.Key) // nolint: errcheck
+				}
+				continue
+			}
+			if kv.Key == key {
+				registered = instance.ExpireAt > renewAt
+				continue
+			}
+			alive++
+		}
+		if ret.Marker == "" || len(ret.Kvs) == 0 {
+			break
+		}
+		opts.Marker = ret.Marker
+	}
+
+	if !registered {
+		info, err := json.Marshal(instanceInfo{
+			Host:     lim.config.Host,
+			ExpireAt: now.Add(time.Duration(lim.config.ExpireS) * time.Second).Unix(),
+		})
+		if err != nil {
+			return err
+		}
+		if err = lim.client.SetKV(ctx, key, info); err != nil {
+			return err
+		}
+	}
+	alive++
+
+	lim.mu.Lock()
+	lim.total, lim.alive = total, alive
+	lim.mu.Unlock()
+	lim.apply()
+	return nil
+}
+
+// apply sets the share of total to local bucket
+func (lim *ClusterLimiter) apply() {
+	total, alive := lim.Total()
+	if total.Rate <= 0 {
+		lim.setConfig(Config{})
+		return
+	}
+	share := Config{Rate: total.Rate / float64(alive)}
+	if total.Burst > 0 {
+		share.Burst = int(math.Ceil(float64(total.Burst) / float64(alive)))
+	}
+	lim.setConfig(share)
+}
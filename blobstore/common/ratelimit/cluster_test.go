@@ -0,0 +1,155 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+)
+
+type memKV struct {
+	mu   sync.Mutex
+	kvs  map[string][]byte
+	err  error
+	sets int
+}
+
+func newMemKV() *memKV {
+	return &memKV{kvs: make(map[string][]byte)}
+}
+
+func (m *memKV) GetKV(ctx context.Context, key string) (ret cmapi.GetKvRet, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return ret, m.err
+	}
+	val, ok := m.kvs[key]
+	if !ok {
+		return ret, errcode.ErrNotFound
+	}
+	ret.Value = val
+	return
+}
+
+func (m *memKV) SetKV(ctx context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.kvs[key] = value
+	m.sets++
+	return nil
+}
+
+func (m *memKV) DeleteKV(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.kvs, key)
+	return nil
+}
+
+// ListKV lists one key each time to test paging
+func (m *memKV) ListKV(ctx context.Context, args *cmapi.ListKvOpts) (ret cmapi.ListKvRet, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.kvs))
+	for key := range m.kvs {
+		if strings.HasPrefix(key, args.Prefix) && key > args.Marker {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		ret.Kvs = []*cmapi.KeyValue{{Key: keys[0], Value: m.kvs[keys[0]]}}
+		ret.Marker = keys[0]
+	}
+	return
+}
+
+func TestClusterLimiter(t *testing.T) {
+	kv := newMemKV()
+	_, err := NewClusterLimiter(ClusterConfig{Name: "a-b"}, kv)
+	require.ErrorIs(t, err, ErrInvalidName)
+	require.ErrorIs(t, SetClusterConfig(context.Background(), kv, "", Config{}), ErrInvalidName)
+
+	cfg := ClusterConfig{Config: Config{Rate: 100, Burst: 10}, Name: "repair", Host: "host1"}
+	lim1, err := NewClusterLimiter(cfg, kv)
+	require.NoError(t, err)
+	defer lim1.Close()
+	require.Equal(t, Config{Rate: 100, Burst: 10}, lim1.Config())
+
+	// the other instance and a dead one
+	dead, _ := json.Marshal(instanceInfo{Host: "host0", ExpireAt: time.Now().Add(-time.Second).Unix()})
+	kv.SetKV(context.Background(), instancePrefix("repair")+"host0", dead)
+	cfg.Host = "host2"
+	lim2, err := NewClusterLimiter(cfg, kv)
+	require.NoError(t, err)
+	require.Equal(t, Config{Rate: 50, Burst: 5}, lim2.Config())
+	_, err = kv.GetKV(context.Background(), instancePrefix("repair")+"host0")
+	require.ErrorIs(t, err, errcode.ErrNotFound)
+
+	require.NoError(t, lim1.sync(context.Background()))
+	total, alive := lim1.Total()
+	require.Equal(t, Config{Rate: 100, Burst: 10}, total)
+	require.Equal(t, 2, alive)
+	require.Equal(t, Config{Rate: 50, Burst: 5}, lim1.Config())
+
+	// registration is not rewritten if it is not going to expire
+	sets := kv.sets
+	require.NoError(t, lim1.sync(context.Background()))
+	require.Equal(t, sets, kv.sets)
+	// renew the registration going to expire
+	expiring, _ := json.Marshal(instanceInfo{Host: "host1", ExpireAt: time.Now().Add(time.Second).Unix()})
+	kv.SetKV(context.Background(), instancePrefix("repair")+"host1", expiring)
+	sets = kv.sets
+	require.NoError(t, lim1.sync(context.Background()))
+	require.Equal(t, sets+1, kv.sets)
+	require.Equal(t, 2, lim1.alive)
+	// register again if it was cleaned
+	kv.DeleteKV(context.Background(), instancePrefix("repair")+"host1")
+	require.NoError(t, lim1.sync(context.Background()))
+	require.Equal(t, sets+2, kv.sets)
+	require.Equal(t, 2, lim1.alive)
+
+	// change total config at runtime
+	require.NoError(t, SetClusterConfig(context.Background(), kv, "repair", Config{Rate: 30}))
+	require.NoError(t, lim1.sync(context.Background()))
+	require.Equal(t, Config{Rate: 15, Burst: 15}, lim1.Config())
+
+	// unregister
+	lim2.Close()
+	require.NoError(t, lim1.sync(context.Background()))
+	require.Equal(t, Config{Rate: 30, Burst: 30}, lim1.Config())
+
+	// keep share if failed
+	kv.err = errors.New("mock error")
+	require.Error(t, lim1.sync(context.Background()))
+	require.Equal(t, Config{Rate: 30, Burst: 30}, lim1.Config())
+	_, err = NewClusterLimiter(cfg, kv)
+	require.Error(t, err)
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package ratelimit provides token bucket limiters shared by services,
+// a limiter is local to the process, or coordinated in cluster that
+// the total rate is shared by all alive instances with the same name.
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Config configuration of token bucket
+type Config struct {
+	// Rate tokens per second, no limit if it is not positive
+	Rate float64 `json:"rate"`
+	// Burst max tokens of bucket, default is ceil of Rate
+	Burst int `json:"burst"`
+}
+
+// Limiter token bucket limiter
+type Limiter interface {
+	// Allow reports whether one token is available now
+	Allow() bool
+	// AllowN reports whether n tokens are available now
+	AllowN(n int) bool
+	// Wait blocks until one token is available or ctx is done
+	Wait(ctx context.Context) error
+	// WaitN blocks until n tokens are available or ctx is done,
+	// n is allowed to be greater than burst
+	WaitN(ctx context.Context, n int) error
+	// TakeN takes n tokens without waiting even if the bucket is not enough,
+	// requests after it wait until the debt is repaid, it is used when the cost
+	// is known after the work is done, AllowN(0) reports whether it is in debt
+	TakeN(n int)
+	// Config returns current configuration of the bucket
+	Config() Config
+}
+
+type localLimiter struct {
+	mu     sync.RWMutex
+	config Config
+	rate   *rate.Limiter
+}
+
+// New returns a local limiter
+func New(cfg Config) Limiter {
+	return newLocal(cfg)
+}
+
+func newLocal(cfg Config) *localLimiter {
+	cfg = fixConfig(cfg)
+	lim := &localLimiter{config: cfg, rate: rate.NewLimiter(rate.Inf, 0)}
+	if cfg.Rate > 0 {
+		// bucket is full at beginning
+		lim.rate = rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Burst)
+	}
+	return lim
+}
+
+func fixConfig(cfg Config) Config {
+	if cfg.Rate <= 0 {
+		return Config{}
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = int(math.Ceil(cfg.Rate))
+	}
+	return cfg
+}
+
+func (lim *localLimiter) setConfig(cfg Config) {
+	cfg = fixConfig(cfg)
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if cfg.Rate <= 0 {
+		lim.rate.SetLimit(rate.Inf)
+	} else {
+		lim.rate.SetLimit(rate.Limit(cfg.Rate))
+		lim.rate.SetBurst(cfg.Burst)
+	}
+	lim.config = cfg
+}
+
+func (lim *localLimiter) Config() Config {
+	lim.mu.RLock()
+	defer lim.mu.RUnlock()
+	return lim.config
+}
+
+func (lim *localLimiter) Allow() bool {
+	return lim.rate.Allow()
+}
+
+func (lim *localLimiter) AllowN(n int) bool {
+	return lim.rate.AllowN(time.Now(), n)
+}
+
+func (lim *localLimiter) Wait(ctx context.Context) error {
+	return lim.rate.Wait(ctx)
+}
+
+func (lim *localLimiter) WaitN(ctx context.Context, n int) error {
+	for n > 0 {
+		m := n
+		if burst := lim.rate.Burst(); lim.rate.Limit() != rate.Inf && m > burst {
+			m = burst
+		}
+		if err := lim.rate.WaitN(ctx, m); err != nil {
+			return err
+		}
+		n -= m
+	}
+	return nil
+}
+
+func (lim *localLimiter) TakeN(n int) {
+	if lim.rate.Limit() == rate.Inf {
+		return
+	}
+	now := time.Now()
+	for n > 0 {
+		m := n
+		if burst := lim.rate.Burst(); m > burst {
+			m = burst
+		}
+		lim.rate.ReserveN(now, m)
+		n -= m
+	}
+}
+
+type reader struct {
+	ctx        context.Context
+	lim        Limiter
+	underlying io.Reader
+}
+
+// Reader returns io.Reader limited bytes per second by limiter
+func Reader(ctx context.Context, lim Limiter, r io.Reader) io.Reader {
+	return &reader{ctx: ctx, lim: lim, underlying: r}
+}
+
+func (r *reader) Read(p []byte) (n int, err error) {
+	n, err = r.underlying.Read(p)
+	if n > 0 {
+		if werr := r.lim.WaitN(r.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return
+}
+
+type writer struct {
+	ctx        context.Context
+	lim        Limiter
+	underlying io.Writer
+}
+
+// Writer returns io.Writer limited bytes per second by limiter
+func Writer(ctx context.Context, lim Limiter, w io.Writer) io.Writer {
+	return &writer{ctx: ctx, lim: lim, underlying: w}
+}
+
+func (w *writer) Write(p []byte) (n int, err error) {
+	if err = w.lim.WaitN(w.ctx, len(p)); err != nil {
+		return
+	}
+	return w.underlying.Write(p)
+}
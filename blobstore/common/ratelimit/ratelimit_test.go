@@ -0,0 +1,74 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLimiter(t *testing.T) {
+	lim := New(Config{})
+	require.Equal(t, Config{}, lim.Config())
+	for i := 0; i < 100; i++ {
+		require.True(t, lim.AllowN(1<<20))
+	}
+
+	lim = New(Config{Rate: 10.5})
+	require.Equal(t, Config{Rate: 10.5, Burst: 11}, lim.Config())
+	require.True(t, lim.AllowN(11))
+	require.False(t, lim.Allow())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, lim.Wait(ctx))
+
+	lim = New(Config{Rate: 1000, Burst: 100})
+	start := time.Now()
+	require.NoError(t, lim.WaitN(context.Background(), 300))
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	// take more than burst and be in debt
+	lim = New(Config{Rate: 1000, Burst: 100})
+	require.True(t, lim.AllowN(0))
+	lim.TakeN(300)
+	require.False(t, lim.AllowN(0))
+	start = time.Now()
+	require.NoError(t, lim.Wait(context.Background()))
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	New(Config{}).TakeN(1 << 20)
+}
+
+func TestLimitedReaderWriter(t *testing.T) {
+	lim := New(Config{Rate: 1 << 20, Burst: 1 << 10})
+	data := make([]byte, 100<<10)
+
+	start := time.Now()
+	buff := new(bytes.Buffer)
+	n, err := io.Copy(Writer(context.Background(), lim, buff), Reader(context.Background(), New(Config{}), bytes.NewReader(data)))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), n)
+	require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = io.Copy(io.Discard, Reader(ctx, lim, bytes.NewReader(data)))
+	require.ErrorIs(t, err, context.Canceled)
+}
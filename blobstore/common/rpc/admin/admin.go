@@ -0,0 +1,161 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package admin serves the profile multiplexer, with gc stats and health
+// of the process, on a separated admin port.
+package admin
+
+import (
+	"net"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/common/profile"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/rpc/auth"
+)
+
+const (
+	PathIndex  = "/"
+	PathHealth = "/health"
+	PathGC     = "/debug/gc"
+	PathVars   = "/debug/vars"
+	PathPprof  = "/debug/pprof/"
+	PathMetric = "/metrics"
+
+	// count of recent gc pauses in gc stats
+	recentPauses = 16
+)
+
+var (
+	startTime    = time.Now()
+	registerOnce sync.Once
+	// healthChecker keeps the HealthChecker of the latest handler
+	healthChecker atomic.Value
+)
+
+// Config admin server config, requests from loopback address need no authentication
+type Config struct {
+	// BindAddr listen address of admin server, empty means disabled
+	BindAddr string      `json:"bind_addr"`
+	Auth     auth.Config `json:"auth"`
+}
+
+// Enabled returns true if admin server is configured
+func (cfg *Config) Enabled() bool {
+	return cfg.BindAddr != ""
+}
+
+// GCStats gc and memory stats of the process
+type GCStats struct {
+	NumGC        int64           `json:"num_gc"`
+	LastGC       time.Time       `json:"last_gc"`
+	PauseTotal   time.Duration   `json:"pause_total"`
+	RecentPauses []time.Duration `json:"recent_pauses"`
+
+	HeapAlloc    uint64 `json:"heap_alloc"`
+	HeapSys      uint64 `json:"heap_sys"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	NextGC       uint64 `json:"next_gc"`
+	NumGoroutine int    `json:"num_goroutine"`
+}
+
+// HealthStatus health of the process
+type HealthStatus struct {
+	Status     string `json:"status"`
+	UptimeS    int64  `json:"uptime_s"`
+	Goroutines int    `json:"goroutines"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HealthChecker returns error if the module is not healthy
+type HealthChecker func() error
+
+// NewHandler returns http handler of admin mux, checker may be nil.
+// Paths of gc stats and health are registered into the profile multiplexer,
+// which serves index, pprof, expvar and metrics.
+func NewHandler(cfg *Config, checker HealthChecker) http.Handler {
+	healthChecker.Store(checker)
+	registerOnce.Do(func() {
+		profile.HandleFunc(http.MethodGet, PathGC, gcStats)
+		profile.HandleFunc(http.MethodGet, PathHealth, health)
+	})
+
+	handler := profile.Handler()
+	if !cfg.Auth.EnableAuth {
+		return handler
+	}
+	authHandler := &loopbackAuthHandler{auth: auth.NewAuthHandler(&cfg.Auth)}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authHandler.Handler(w, req, handler.ServeHTTP)
+	})
+}
+
+// loopbackAuthHandler authenticates requests except from loopback address
+type loopbackAuthHandler struct {
+	auth *auth.AuthHandler
+}
+
+func (h *loopbackAuthHandler) Handler(w http.ResponseWriter, req *http.Request, f func(http.ResponseWriter, *http.Request)) {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			f(w, req)
+			return
+		}
+	}
+	h.auth.Handler(w, req, f)
+}
+
+func health(c *rpc.Context) {
+	status := HealthStatus{
+		Status:     "ok",
+		UptimeS:    int64(time.Since(startTime) / time.Second),
+		Goroutines: runtime.NumGoroutine(),
+	}
+	if checker, _ := healthChecker.Load().(HealthChecker); checker != nil {
+		if err := checker(); err != nil {
+			status.Status = "unhealthy"
+			status.Error = err.Error()
+			c.RespondStatusData(http.StatusServiceUnavailable, status)
+			return
+		}
+	}
+	c.RespondJSON(status)
+}
+
+func gcStats(c *rpc.Context) {
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	if len(gc.Pause) > recentPauses {
+		gc.Pause = gc.Pause[:recentPauses]
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	c.RespondJSON(GCStats{
+		NumGC:        gc.NumGC,
+		LastGC:       gc.LastGC,
+		PauseTotal:   gc.PauseTotal,
+		RecentPauses: gc.Pause,
+		HeapAlloc:    mem.HeapAlloc,
+		HeapSys:      mem.HeapSys,
+		HeapObjects:  mem.HeapObjects,
+		NextGC:       mem.NextGC,
+		NumGoroutine: runtime.NumGoroutine(),
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/rpc/auth"
+)
+
+func TestAdminHandler(t *testing.T) {
+	var unhealthy error
+	handler := NewHandler(&Config{}, func() error { return unhealthy })
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for _, path := range []string{PathIndex, PathVars, PathPprof, PathPprof + "goroutine", PathPprof + "cmdline", PathMetric} {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	// index of profile lists paths of admin
+	resp, err := http.Get(server.URL + PathIndex)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Contains(t, string(body), PathGC)
+	require.Contains(t, string(body), PathHealth)
+
+	resp, err = http.Get(server.URL + PathGC)
+	require.NoError(t, err)
+	var gc GCStats
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&gc))
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, gc.NumGoroutine > 0)
+	require.True(t, len(gc.RecentPauses) <= recentPauses)
+
+	resp, err = http.Get(server.URL + PathHealth)
+	require.NoError(t, err)
+	var status HealthStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "ok", status.Status)
+
+	unhealthy = errors.New("disk broken")
+	resp, err = http.Get(server.URL + PathHealth)
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "unhealthy", status.Status)
+	require.Equal(t, "disk broken", status.Error)
+}
+
+func TestAdminAuth(t *testing.T) {
+	authCfg := auth.Config{EnableAuth: true, Secret: "testSecret"}
+	handler := NewHandler(&Config{Auth: authCfg}, nil)
+
+	// request from remote address without token
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, PathHealth, nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// request from loopback address
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, PathHealth, nil)
+	req.RemoteAddr = "127.0.0.1:9500"
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
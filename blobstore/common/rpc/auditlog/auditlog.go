@@ -54,6 +54,8 @@ type jsonAuditlog struct {
 	metricSender MetricSender
 	logFile      LogCloser
 	logFilter    LogFilter
+	ruleFilter   LogFilter
+	redactor     *redactor
 
 	logPool  sync.Pool
 	bodyPool sync.Pool
@@ -65,6 +67,7 @@ type jsonAuditlog struct {
 type AuditLog struct {
 	ReqType    string `json:"req_type"`
 	Module     string `json:"module"`
+	RequestID  string `json:"request_id,omitempty"`
 	StartTime  int64  `json:"start_time"`
 	Method     string `json:"method"`
 	Path       string `json:"path"`
@@ -74,7 +77,10 @@ type AuditLog struct {
 	RespHeader M      `json:"resp_header"`
 	RespBody   string `json:"resp_body"`
 	RespLength int64  `json:"resp_length"`
-	Duration   int64  `json:"duration"`
+	Duration   int64  `json:"duration"` // latency in microseconds
+
+	// Tags custom tags of config and span tags, only output in json format
+	Tags M `json:"tags,omitempty"`
 }
 
 func (a *AuditLog) ToBytesWithTab(buf *bytes.Buffer) (b []byte) {
@@ -106,8 +112,18 @@ func (a *AuditLog) ToBytesWithTab(buf *bytes.Buffer) (b []byte) {
 }
 
 func (a *AuditLog) ToJson() (b []byte) {
-	b, _ = json.Marshal(a)
-	return
+	return a.ToJSONWithBuffer(new(bytes.Buffer))
+}
+
+// ToJSONWithBuffer encodes audit log into one json line with buf,
+// html characters in path and body are not escaped.
+func (a *AuditLog) ToJSONWithBuffer(buf *bytes.Buffer) []byte {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(a); err != nil {
+		return nil
+	}
+	return buf.Bytes()
 }
 
 func Open(module string, cfg *Config) (ph rpc.ProgressHandler, logFile LogCloser, err error) {
@@ -123,6 +139,12 @@ func Open(module string, cfg *Config) (ph rpc.ProgressHandler, logFile LogCloser
 		cfg.ChunkBits = defaultFileChunkBits
 	}
 
+	switch cfg.LogFormat {
+	case "", LogFormatText, LogFormatJSON:
+	default:
+		return nil, nil, errors.Newf("auditlog.Open: invalid log format %s", cfg.LogFormat)
+	}
+
 	largeLogConfig := largefile.Config{
 		Path:              cfg.LogDir,
 		FileChunkSizeBits: cfg.ChunkBits,
@@ -137,11 +159,21 @@ func Open(module string, cfg *Config) (ph rpc.ProgressHandler, logFile LogCloser
 			return nil, nil, errors.Info(err, "auditlog.Open: large file log open failed").Detail(err)
 		}
 	}
+	if cfg.Sink.Type != "" {
+		logFile, err = newSinkLogCloser(module, cfg.Sink, logFile)
+		if err != nil {
+			return nil, nil, errors.Info(err, "auditlog.Open: remote sink open failed").Detail(err)
+		}
+	}
 
 	logFilter, err := newLogFilter(cfg.Filters)
 	if err != nil {
 		return nil, nil, errors.Info(err, "new log filter").Detail(err)
 	}
+	ruleFilter, err := newRuleFilter(cfg.Rules)
+	if err != nil {
+		return nil, nil, errors.Info(err, "new rule filter").Detail(err)
+	}
 
 	return &jsonAuditlog{
 		module:       module,
@@ -149,6 +181,8 @@ func Open(module string, cfg *Config) (ph rpc.ProgressHandler, logFile LogCloser
 		metricSender: NewPrometheusSender(cfg.MetricConfig),
 		logFile:      logFile,
 		logFilter:    logFilter,
+		ruleFilter:   ruleFilter,
+		redactor:     newRedactor(cfg.Redact),
 
 		logPool: sync.Pool{
 			New: func() interface{} {
@@ -172,12 +206,21 @@ func (j *jsonAuditlog) Handler(w http.ResponseWriter, req *http.Request, f func(
 	startTime := time.Now().UnixNano()
 
 	ctx := req.Context()
+	requestID := req.Header.Get(rpc.HeaderRequestID)
 	span := trace.SpanFromContext(ctx)
 	if span == nil {
+		// trace with the propagated request id if no trace id
+		if requestID != "" && req.Header.Get(trace.RequestIDKey) == "" {
+			req.Header.Set(trace.RequestIDKey, requestID)
+		}
 		span, ctx = trace.StartSpanFromHTTPHeaderSafe(req, "")
 		defer span.Finish()
-		req = req.WithContext(ctx)
 	}
+	if requestID == "" {
+		requestID = span.TraceID()
+	}
+	req = req.WithContext(rpc.ContextWithRequestID(ctx, requestID))
+	w.Header().Set(rpc.HeaderRequestID, requestID)
 
 	_w := &responseWriter{
 		module:         j.module,
@@ -219,6 +262,7 @@ func (j *jsonAuditlog) Handler(w http.ResponseWriter, req *http.Request, f func(
 	auditLog := &AuditLog{
 		ReqType:   "REQ",
 		Module:    j.module,
+		RequestID: requestID,
 		StartTime: startTime / 100,
 		Method:    req.Method,
 		Path:      decodeReq.Path,
@@ -255,16 +299,19 @@ func (j *jsonAuditlog) Handler(w http.ResponseWriter, req *http.Request, f func(
 
 	auditLog.RespLength = _w.getBodyWritten()
 	auditLog.Duration = endTime - startTime/1000
+	j.redactor.Redact(auditLog)
 
 	j.metricSender.Send(auditLog.ToBytesWithTab(b))
 
-	if j.logFile == nil || j.logFilter.Filter(auditLog) {
+	if j.logFile == nil || j.ruleFilter.Filter(auditLog) || j.logFilter.Filter(auditLog) {
 		return
 	}
 
 	switch j.cfg.LogFormat {
 	case LogFormatJSON:
-		logBytes = auditLog.ToJson()
+		auditLog.Tags = j.tags(span)
+		b.Reset()
+		logBytes = auditLog.ToJSONWithBuffer(b)
 	default:
 		logBytes = b.Bytes() // *bytes.Buffer was filled with metricSender.Send
 	}
@@ -275,6 +322,22 @@ func (j *jsonAuditlog) Handler(w http.ResponseWriter, req *http.Request, f func(
 	}
 }
 
+// tags returns custom tags of config merged with span tags
+func (j *jsonAuditlog) tags(span trace.Span) M {
+	spanTags := span.Tags()
+	if len(j.cfg.Tags) == 0 && len(spanTags) == 0 {
+		return nil
+	}
+	tags := make(M, len(j.cfg.Tags)+len(spanTags))
+	for k, v := range spanTags {
+		tags[k] = v
+	}
+	for k, v := range j.cfg.Tags {
+		tags[k] = v
+	}
+	return tags
+}
+
 // ExtraHeader provides extra response header writes to the ResponseWriter.
 func ExtraHeader(w http.ResponseWriter) http.Header {
 	h := make(http.Header)
@@ -344,6 +344,91 @@ func TestBodylimit(t *testing.T) {
 	}
 }
 
+type bufferLogCloser struct {
+	lines [][]byte
+}
+
+func (b *bufferLogCloser) Log(p []byte) error {
+	b.lines = append(b.lines, append([]byte{}, p...))
+	return nil
+}
+
+func (b *bufferLogCloser) Close() error { return nil }
+
+func TestJSONFormat(t *testing.T) {
+	_, _, err := Open("testJSONFormat", &Config{LogFormat: "xml"})
+	require.Error(t, err)
+
+	ph, _, err := Open("testJSONFormat", &Config{
+		LogFormat:    LogFormatJSON,
+		Tags:         map[string]string{"cluster": "100"},
+		MetricConfig: PrometheusConfig{Idc: "testJSONFormat"},
+	})
+	require.NoError(t, err)
+	logger := &bufferLogCloser{}
+	ph.(*jsonAuditlog).logFile = logger
+
+	req := httptest.NewRequest(http.MethodPost, "/json/format?a=b", nil)
+	ph.Handler(httptest.NewRecorder(), req, func(w http.ResponseWriter, req *http.Request) {
+		trace.SpanFromContextSafe(req.Context()).SetTag("biz", "tag")
+		w.Header().Set("Content-Type", rpc.MIMEJSON)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":"<ok>"}`))
+	})
+	require.Len(t, logger.lines, 1)
+	line := logger.lines[0]
+	require.Equal(t, byte('\n'), line[len(line)-1])
+	require.Contains(t, string(line), `<ok>`)
+
+	var entry AuditLog
+	require.NoError(t, json.Unmarshal(line, &entry))
+	require.Equal(t, "REQ", entry.ReqType)
+	require.Equal(t, "testJSONFormat", entry.Module)
+	require.Equal(t, http.MethodPost, entry.Method)
+	require.Equal(t, "/json/format", entry.Path)
+	require.Equal(t, http.StatusCreated, entry.StatusCode)
+	require.Equal(t, int64(17), entry.RespLength)
+	require.GreaterOrEqual(t, entry.Duration, int64(0))
+	require.Equal(t, "100", entry.Tags["cluster"])
+	require.Equal(t, "tag", entry.Tags["biz"])
+}
+
+func TestRequestID(t *testing.T) {
+	ph, _, err := Open("testRequestID", &Config{
+		LogFormat:    LogFormatJSON,
+		MetricConfig: PrometheusConfig{Idc: "testRequestID"},
+	})
+	require.NoError(t, err)
+	logger := &bufferLogCloser{}
+	ph.(*jsonAuditlog).logFile = logger
+
+	handle := func(req *http.Request) (requestID string, entry AuditLog, header http.Header) {
+		w := httptest.NewRecorder()
+		ph.Handler(w, req, func(w http.ResponseWriter, req *http.Request) {
+			requestID = rpc.RequestIDFromContext(req.Context())
+			require.Equal(t, requestID, trace.SpanFromContextSafe(req.Context()).TraceID())
+			w.WriteHeader(http.StatusOK)
+		})
+		line := logger.lines[len(logger.lines)-1]
+		require.NoError(t, json.Unmarshal(line, &entry))
+		return requestID, entry, w.Header()
+	}
+
+	// generate request id
+	requestID, entry, header := handle(httptest.NewRequest(http.MethodGet, "/request/id", nil))
+	require.NotEmpty(t, requestID)
+	require.Equal(t, requestID, entry.RequestID)
+	require.Equal(t, requestID, header.Get(rpc.HeaderRequestID))
+
+	// propagate request id
+	req := httptest.NewRequest(http.MethodGet, "/request/id", nil)
+	req.Header.Set(rpc.HeaderRequestID, "test-request-id")
+	requestID, entry, header = handle(req)
+	require.Equal(t, "test-request-id", requestID)
+	require.Equal(t, "test-request-id", entry.RequestID)
+	require.Equal(t, "test-request-id", header.Get(rpc.HeaderRequestID))
+}
+
 func Benchmark_RowParser(b *testing.B) {
 	line := strings.Join([]string{
 		"REQ", "BENCH", "16866434380042975", "POST", "/bench/mark/test",
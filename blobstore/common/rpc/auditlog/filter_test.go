@@ -215,3 +215,50 @@ func Benchmark_FilterHeader(b *testing.B) {
 		f.Filter(&auditlog)
 	}
 }
+
+func TestRuleFilter(t *testing.T) {
+	for _, rules := range [][]FilterRule{
+		{{Action: "drop"}},
+		{{SampleRatio: -0.1}},
+		{{SampleRatio: 1.1}},
+		{{StatusClasses: []string{"6xx"}}},
+		{{StatusClasses: []string{"500"}}},
+	} {
+		_, err := newRuleFilter(rules)
+		require.Error(t, err)
+	}
+
+	log := auditlog
+	f, err := newRuleFilter(nil)
+	require.NoError(t, err)
+	require.False(t, f.Filter(&log))
+
+	f, err = newRuleFilter([]FilterRule{
+		{Action: RuleActionInclude, StatusClasses: []string{"5XX"}},
+		{PathPrefixes: []string{"/stat", "/service/"}, Methods: []string{"get"}},
+		{Action: RuleActionExclude, Methods: []string{"POST"}, SampleRatio: 1},
+	})
+	require.NoError(t, err)
+	require.True(t, f.Filter(&log))
+	log.StatusCode = 503
+	require.False(t, f.Filter(&log))
+	log.StatusCode = 200
+	log.Method = "HEAD"
+	require.False(t, f.Filter(&log))
+	log.Method = "POST"
+	require.False(t, f.Filter(&log))
+	log.Path = "/put"
+	require.False(t, f.Filter(&log))
+	log.Method = "GET"
+	require.False(t, f.Filter(&log))
+
+	f, err = newRuleFilter([]FilterRule{{Methods: []string{"GET"}, SampleRatio: 0.5}})
+	require.NoError(t, err)
+	dropped := 0
+	for i := 0; i < 1000; i++ {
+		if f.Filter(&log) {
+			dropped++
+		}
+	}
+	require.True(t, dropped > 0 && dropped < 1000)
+}
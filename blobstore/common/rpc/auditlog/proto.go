@@ -40,9 +40,17 @@ type Config struct {
 
 	// Filters are or relations
 	Filters []FilterConfig `json:"filters"`
+	// Rules include or exclude logs by path, method and status, checked before Filters
+	Rules []FilterRule `json:"rules"`
 
 	// LogFormat valid value is "text" or "json", default is "text"
 	LogFormat string `json:"log_format"`
+	// Tags custom tags added to each entry of json format, eg: cluster, idc
+	Tags map[string]string `json:"tags"`
+	// Redact masks headers and json fields of request params and response body
+	Redact RedactConfig `json:"redact"`
+	// Sink ships entries to remote sink, eg: kafka, syslog
+	Sink SinkConfig `json:"sink"`
 }
 
 // LogCloser a implemented audit logger should implements ProgressHandler
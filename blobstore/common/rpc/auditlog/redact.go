@@ -0,0 +1,175 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package auditlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultRedactMask = "***"
+
+// RedactConfig masks sensitive values before logging, so that request
+// and response bodies can be captured without leaking credentials.
+//
+// eg: headers ["Authorization"], fields ["password", "auth.secret_key"]
+type RedactConfig struct {
+	// Headers names of request and response headers, case insensitive
+	Headers []string `json:"headers"`
+	// Fields json field paths of request params and response body,
+	// nested fields are separated by '.', arrays are matched by each element
+	Fields []string `json:"fields"`
+	// Mask replaces the redacted values, default is "***"
+	Mask string `json:"mask"`
+}
+
+type redactor struct {
+	headers map[string]struct{}
+	fields  [][]string
+	mask    string
+}
+
+// newRedactor returns nil if nothing to redact.
+func newRedactor(cfg RedactConfig) *redactor {
+	if len(cfg.Headers) == 0 && len(cfg.Fields) == 0 {
+		return nil
+	}
+	r := &redactor{headers: make(map[string]struct{}, len(cfg.Headers)), mask: cfg.Mask}
+	if r.mask == "" {
+		r.mask = defaultRedactMask
+	}
+	for _, h := range cfg.Headers {
+		r.headers[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+	for _, f := range cfg.Fields {
+		if f = strings.Trim(f, "."); f != "" {
+			r.fields = append(r.fields, strings.Split(f, "."))
+		}
+	}
+	return r
+}
+
+// Redact masks headers and body fields of audit log in place.
+func (r *redactor) Redact(a *AuditLog) {
+	if r == nil {
+		return
+	}
+	r.redactHeader(a.ReqHeader)
+	r.redactHeader(a.RespHeader)
+	if len(r.fields) == 0 {
+		return
+	}
+	if rawQuery, ok := a.ReqHeader["RawQuery"].(string); ok {
+		a.ReqHeader["RawQuery"] = r.redactQuery(rawQuery)
+	}
+	if a.ReqParams != "" {
+		a.ReqParams = r.redactBody(a.ReqParams)
+	}
+	if a.RespBody != "" {
+		a.RespBody = r.redactBody(a.RespBody)
+	}
+}
+
+func (r *redactor) redactHeader(header M) {
+	for key := range header {
+		if _, ok := r.headers[http.CanonicalHeaderKey(key)]; ok {
+			header[key] = r.mask
+		}
+	}
+}
+
+// redactQuery masks values of form params whose names are the field paths,
+// the query is dropped if it's not parsable.
+func (r *redactor) redactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return r.mask
+	}
+	redacted := false
+	for _, path := range r.fields {
+		key := strings.Join(path, ".")
+		if vals, ok := values[key]; ok {
+			for i := range vals {
+				vals[i] = r.mask
+			}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
+// redactBody returns the body unchanged if nothing is redacted. Bodies which
+// are not json, eg: truncated by the body limit, are dropped since the fields
+// in them can't be found.
+func (r *redactor) redactBody(body string) string {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return r.mask
+	}
+	redacted := false
+	for _, path := range r.fields {
+		if r.redactField(v, path) {
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return r.mask
+	}
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func (r *redactor) redactField(v interface{}, path []string) (redacted bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		// form params are decoded into a flat object
+		if key := strings.Join(path, "."); len(path) > 1 {
+			if _, ok := val[key]; ok {
+				val[key] = r.mask
+				redacted = true
+			}
+		}
+		sub, ok := val[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			val[path[0]] = r.mask
+			return true
+		}
+		return r.redactField(sub, path[1:]) || redacted
+	case []interface{}:
+		for _, elem := range val {
+			if r.redactField(elem, path) {
+				redacted = true
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package auditlog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+)
+
+func TestRedactor(t *testing.T) {
+	require.Nil(t, newRedactor(RedactConfig{}))
+	var nilRedactor *redactor
+	nilRedactor.Redact(&AuditLog{})
+
+	r := newRedactor(RedactConfig{
+		Headers: []string{"authorization", "X-Secret"},
+		Fields:  []string{"password", "auth.secret_key", "items.token", "."},
+	})
+	a := &AuditLog{
+		ReqHeader:  M{"Authorization": "Bearer abc", "Host": "127.0.0.1"},
+		ReqParams:  `{"name":"a","password":"p","auth":{"secret_key":"s","id":1234567890123456789},"items":[{"token":"t"},{"x":1}]}`,
+		RespHeader: M{"X-Secret": "s"},
+		RespBody:   `{"result":"<ok>"}`,
+	}
+	r.Redact(a)
+	require.Equal(t, "***", a.ReqHeader["Authorization"])
+	require.Equal(t, "127.0.0.1", a.ReqHeader["Host"])
+	require.Equal(t, "***", a.RespHeader["X-Secret"])
+	require.Equal(t, `{"auth":{"id":1234567890123456789,"secret_key":"***"},"items":[{"token":"***"},{"x":1}],"name":"a","password":"***"}`, a.ReqParams)
+	require.Equal(t, `{"result":"<ok>"}`, a.RespBody)
+
+	r = newRedactor(RedactConfig{Fields: []string{"password"}, Mask: "-"})
+	a = &AuditLog{ReqParams: `not json password`, RespBody: `[{"password":1}]`}
+	r.Redact(a)
+	require.Equal(t, `-`, a.ReqParams)
+	require.Equal(t, `[{"password":"-"}]`, a.RespBody)
+
+	// body truncated by the limit is dropped
+	a = &AuditLog{RespBody: `{"name":"a","password":"p`}
+	r.Redact(a)
+	require.Equal(t, `-`, a.RespBody)
+
+	// form params in query and body
+	r = newRedactor(RedactConfig{Fields: []string{"password", "auth.secret_key"}})
+	a = &AuditLog{
+		ReqHeader: M{"RawQuery": "name=a&password=p&password=q&auth.secret_key=s"},
+		ReqParams: `{"auth.secret_key":"s","name":"a"}`,
+	}
+	r.Redact(a)
+	require.Equal(t, "auth.secret_key=%2A%2A%2A&name=a&password=%2A%2A%2A&password=%2A%2A%2A", a.ReqHeader["RawQuery"])
+	require.Equal(t, `{"auth.secret_key":"***","name":"a"}`, a.ReqParams)
+	a = &AuditLog{ReqHeader: M{"RawQuery": "name=a"}}
+	r.Redact(a)
+	require.Equal(t, "name=a", a.ReqHeader["RawQuery"])
+	a = &AuditLog{ReqHeader: M{"RawQuery": "password=%zz"}}
+	r.Redact(a)
+	require.Equal(t, "***", a.ReqHeader["RawQuery"])
+}
+
+func TestRedactHandler(t *testing.T) {
+	ph, _, err := Open("testRedact", &Config{
+		Redact:       RedactConfig{Headers: []string{"range"}, Fields: []string{"secret"}},
+		MetricConfig: PrometheusConfig{Idc: "testRedact"},
+	})
+	require.NoError(t, err)
+	logger := &bufferLogCloser{}
+	ph.(*jsonAuditlog).logFile = logger
+
+	req := httptest.NewRequest(http.MethodPost, "/redact", strings.NewReader(`{"user":"u","secret":"s"}`))
+	req.Header.Set("Content-Type", rpc.MIMEJSON)
+	req.Header.Set("Range", "bytes=0-1")
+	ph.Handler(httptest.NewRecorder(), req, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", rpc.MIMEJSON)
+		w.Write([]byte(`{"secret":"s"}`))
+	})
+	require.Len(t, logger.lines, 1)
+	line := string(logger.lines[0])
+	require.NotContains(t, line, `"s"`)
+	require.NotContains(t, line, "bytes=0-1")
+	require.Contains(t, line, `{"secret":"***","user":"u"}`)
+
+	req = httptest.NewRequest(http.MethodPost, "/redact?secret=q", strings.NewReader(`user=u&secret=s`))
+	req.Header.Set("Content-Type", rpc.MIMEPOSTForm)
+	ph.Handler(httptest.NewRecorder(), req, func(w http.ResponseWriter, req *http.Request) {})
+	require.Len(t, logger.lines, 2)
+	line = string(logger.lines[1])
+	require.NotContains(t, line, `"s"`)
+	require.NotContains(t, line, "secret=q")
+	require.Contains(t, line, `"secret":"***"`)
+}
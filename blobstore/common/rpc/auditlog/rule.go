@@ -0,0 +1,134 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package auditlog
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	RuleActionInclude = "include"
+	RuleActionExclude = "exclude"
+)
+
+// FilterRule include or exclude logs matched with path prefixes, methods and status classes.
+// Empty field matches all logs. Rules are checked in order, the first matched rule takes effect,
+// logs matched none of rules are logged.
+//
+// eg: include "5xx" first, then exclude "/stat" with sample ratio 0.01,
+// so errors are always logged and 1% of health checks are logged.
+type FilterRule struct {
+	// Action valid value is "include" or "exclude", default is "exclude"
+	Action        string   `json:"action"`
+	PathPrefixes  []string `json:"path_prefixes"`
+	Methods       []string `json:"methods"`
+	StatusClasses []string `json:"status_classes"` // "1xx" ~ "5xx"
+	// SampleRatio ratio of excluded logs still to be logged, in range [0, 1]
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
+type rule struct {
+	include      bool
+	pathPrefixes []string
+	methods      []string
+	classes      [6]bool
+	hasClass     bool
+	sampleRatio  float64
+}
+
+type ruleFilter struct {
+	rules []rule
+}
+
+func newRuleFilter(cfgs []FilterRule) (LogFilter, error) {
+	rf := &ruleFilter{rules: make([]rule, 0, len(cfgs))}
+	for _, cfg := range cfgs {
+		r := rule{pathPrefixes: cfg.PathPrefixes, sampleRatio: cfg.SampleRatio}
+		switch cfg.Action {
+		case RuleActionInclude:
+			r.include = true
+		case "", RuleActionExclude:
+		default:
+			return nil, fmt.Errorf("invalid rule action:%s", cfg.Action)
+		}
+		if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+			return nil, fmt.Errorf("invalid rule sample ratio:%v", cfg.SampleRatio)
+		}
+		for _, method := range cfg.Methods {
+			r.methods = append(r.methods, strings.ToUpper(method))
+		}
+		for _, class := range cfg.StatusClasses {
+			class = strings.ToLower(class)
+			if len(class) != 3 || class[1:] != "xx" || class[0] < '1' || class[0] > '5' {
+				return nil, fmt.Errorf("invalid rule status class:%s", class)
+			}
+			r.classes[class[0]-'0'] = true
+			r.hasClass = true
+		}
+		rf.rules = append(rf.rules, r)
+	}
+	return rf, nil
+}
+
+// Filter returns true if the log should be dropped.
+func (rf *ruleFilter) Filter(log *AuditLog) bool {
+	for idx := range rf.rules {
+		r := &rf.rules[idx]
+		if !r.match(log) {
+			continue
+		}
+		if r.include {
+			return false
+		}
+		return r.sampleRatio <= 0 || rand.Float64() >= r.sampleRatio
+	}
+	return false
+}
+
+func (r *rule) match(log *AuditLog) bool {
+	if len(r.pathPrefixes) > 0 {
+		matched := false
+		for _, prefix := range r.pathPrefixes {
+			if strings.HasPrefix(log.Path, prefix) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.methods) > 0 {
+		matched := false
+		for _, method := range r.methods {
+			if log.Method == method {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.hasClass {
+		class := log.StatusCode / 100
+		if class < 0 || class >= len(r.classes) || !r.classes[class] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package auditlog
+
+import (
+	"bytes"
+	"fmt"
+	"log/syslog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/common/kafka"
+	"github.com/cubefs/cubefs/blobstore/util/log"
+)
+
+const (
+	SinkTypeKafka  = "kafka"
+	SinkTypeSyslog = "syslog"
+)
+
+const (
+	defaultSinkBatchSize       = 100
+	defaultSinkFlushIntervalMs = 1000
+	defaultSinkQueueSize       = 10000
+)
+
+// SinkConfig remote sink of audit log, entries are shipped to the sink in batches.
+type SinkConfig struct {
+	// Type valid value is "kafka" or "syslog", empty means no remote sink
+	Type   string            `json:"type"`
+	Kafka  kafka.ProducerCfg `json:"kafka"`
+	Syslog SyslogConfig      `json:"syslog"`
+
+	BatchSize       int `json:"batch_size"`
+	FlushIntervalMs int `json:"flush_interval_ms"`
+	// QueueSize entries waiting to be shipped, new entries are not queued if the queue is full
+	QueueSize int `json:"queue_size"`
+	// Fallback writes entries to local log file only if failed to ship or the queue is full,
+	// otherwise entries are written to both local log file and remote sink, and dropped
+	// from remote sink if failed.
+	Fallback bool `json:"fallback"`
+}
+
+// SyslogConfig connects to local syslog daemon if Network is empty.
+type SyslogConfig struct {
+	Network string `json:"network"` // "udp" or "tcp"
+	Addr    string `json:"addr"`
+	Tag     string `json:"tag"` // default is module name
+}
+
+// remoteSink ships a batch of entries, entries are not retained after returned.
+type remoteSink interface {
+	Send(entries [][]byte) error
+	Close() error
+}
+
+type kafkaSink struct {
+	topic    string
+	producer *kafka.Producer
+}
+
+func newKafkaSink(cfg kafka.ProducerCfg) (*kafkaSink, error) {
+	producer, err := kafka.NewProducer(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSink{topic: cfg.Topic, producer: producer}, nil
+}
+
+func (s *kafkaSink) Send(entries [][]byte) error {
+	msgs := make([][]byte, len(entries))
+	for idx := range entries {
+		msgs[idx] = bytes.TrimRight(entries[idx], "\n")
+	}
+	return s.producer.SendMessages(s.topic, msgs)
+}
+
+func (s *kafkaSink) Close() error {
+	return s.producer.Close()
+}
+
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(module string, cfg SyslogConfig) (*syslogSink, error) {
+	tag := cfg.Tag
+	if tag == "" {
+		tag = module
+	}
+	writer, err := syslog.Dial(cfg.Network, cfg.Addr, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (s *syslogSink) Send(entries [][]byte) error {
+	for idx := range entries {
+		if err := s.writer.Info(string(bytes.TrimRight(entries[idx], "\n"))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
+
+// sinkLogCloser ships entries to remote sink in background,
+// local log file takes over entries in fallback mode.
+type sinkLogCloser struct {
+	sink     remoteSink
+	file     LogCloser
+	fallback bool
+
+	batchSize int
+	interval  time.Duration
+	queue     chan []byte
+	dropped   uint64
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	done      chan struct{}
+}
+
+var _ LogCloser = (*sinkLogCloser)(nil)
+
+func newSinkLogCloser(module string, cfg SinkConfig, file LogCloser) (LogCloser, error) {
+	var (
+		sink remoteSink
+		err  error
+	)
+	switch cfg.Type {
+	case SinkTypeKafka:
+		sink, err = newKafkaSink(cfg.Kafka)
+	case SinkTypeSyslog:
+		sink, err = newSyslogSink(module, cfg.Syslog)
+	default:
+		return nil, fmt.Errorf("invalid sink type:%s", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return startSinkLogCloser(sink, cfg, file), nil
+}
+
+func startSinkLogCloser(sink remoteSink, cfg SinkConfig, file LogCloser) *sinkLogCloser {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultSinkBatchSize
+	}
+	if cfg.FlushIntervalMs <= 0 {
+		cfg.FlushIntervalMs = defaultSinkFlushIntervalMs
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultSinkQueueSize
+	}
+	s := &sinkLogCloser{
+		sink:      sink,
+		file:      file,
+		fallback:  cfg.Fallback,
+		batchSize: cfg.BatchSize,
+		interval:  time.Duration(cfg.FlushIntervalMs) * time.Millisecond,
+		queue:     make(chan []byte, cfg.QueueSize),
+		closeCh:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// Log queues a copy of entry, the entry buffer is reused by caller.
+func (s *sinkLogCloser) Log(entry []byte) error {
+	if !s.fallback {
+		if err := s.file.Log(entry); err != nil {
+			return err
+		}
+	}
+	select {
+	case s.queue <- append([]byte(nil), entry...):
+		return nil
+	default:
+	}
+	if s.fallback {
+		return s.file.Log(entry)
+	}
+	atomic.AddUint64(&s.dropped, 1)
+	return nil
+}
+
+func (s *sinkLogCloser) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+		<-s.done
+	})
+	err := s.sink.Close()
+	if e := s.file.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+func (s *sinkLogCloser) loop() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.sink.Send(batch); err != nil {
+			log.Warnf("auditlog send %d entries to sink failed, fallback:%v err:%s", len(batch), s.fallback, err)
+			if s.fallback {
+				for idx := range batch {
+					s.file.Log(batch[idx])
+				}
+			} else {
+				atomic.AddUint64(&s.dropped, uint64(len(batch)))
+			}
+		}
+		for idx := range batch {
+			batch[idx] = nil
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if dropped := atomic.SwapUint64(&s.dropped, 0); dropped > 0 {
+				log.Warnf("auditlog dropped %d entries of sink", dropped)
+			}
+		case <-s.closeCh:
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+				if len(batch) >= s.batchSize {
+					flush()
+				}
+			}
+			flush()
+			close(s.done)
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package auditlog
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/kafka"
+)
+
+type syncLogCloser struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (s *syncLogCloser) Log(p []byte) error {
+	s.mu.Lock()
+	s.lines = append(s.lines, string(p))
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *syncLogCloser) Close() error { return nil }
+
+func (s *syncLogCloser) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.lines...)
+}
+
+type fakeSink struct {
+	mu      sync.Mutex
+	err     error
+	batches [][]string
+}
+
+func (s *fakeSink) Send(entries [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	batch := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		batch = append(batch, string(entry))
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *fakeSink) Close() error { return nil }
+
+func TestSinkLogCloser(t *testing.T) {
+	_, err := newSinkLogCloser("test", SinkConfig{Type: "http"}, noopLogCloser{})
+	require.Error(t, err)
+
+	// write both local file and remote sink
+	{
+		file, sink := &syncLogCloser{}, &fakeSink{}
+		s := startSinkLogCloser(sink, SinkConfig{BatchSize: 2, FlushIntervalMs: 10000}, file)
+		entry := []byte("a\n")
+		require.NoError(t, s.Log(entry))
+		entry[0] = 'b' // buffer is reused by caller
+		require.NoError(t, s.Log(entry))
+		require.NoError(t, s.Log([]byte("c\n")))
+		require.NoError(t, s.Close())
+		require.Equal(t, []string{"a\n", "b\n", "c\n"}, file.Lines())
+		require.Equal(t, [][]string{{"a\n", "b\n"}, {"c\n"}}, sink.batches)
+	}
+	// fallback to local file if failed to ship
+	{
+		file, sink := &syncLogCloser{}, &fakeSink{}
+		s := startSinkLogCloser(sink, SinkConfig{FlushIntervalMs: 10, Fallback: true}, file)
+		require.NoError(t, s.Log([]byte("a\n")))
+		require.Eventually(t, func() bool {
+			sink.mu.Lock()
+			defer sink.mu.Unlock()
+			return len(sink.batches) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Empty(t, file.Lines())
+
+		sink.mu.Lock()
+		sink.err = errors.New("unavailable")
+		sink.mu.Unlock()
+		require.NoError(t, s.Log([]byte("b\n")))
+		require.NoError(t, s.Close())
+		require.Equal(t, []string{"b\n"}, file.Lines())
+	}
+	// fallback to local file if queue is full
+	{
+		file := &syncLogCloser{}
+		s := &sinkLogCloser{sink: &fakeSink{}, file: file, fallback: true, queue: make(chan []byte, 1)}
+		require.NoError(t, s.Log([]byte("a\n")))
+		require.NoError(t, s.Log([]byte("b\n")))
+		require.Equal(t, []string{"b\n"}, file.Lines())
+		s.fallback = false
+		require.NoError(t, s.Log([]byte("c\n")))
+		require.Equal(t, uint64(1), s.dropped)
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	kafka.DefaultKafkaVersion = sarama.V0_9_0_0
+	seedBroker := sarama.NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := sarama.NewMockBroker(t, 2)
+	defer leader.Close()
+
+	topic := "auditlog"
+	metadataResponse := new(sarama.MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition(topic, 0, leader.BrokerID(), nil, nil, nil, sarama.ErrNoError)
+	seedBroker.Returns(metadataResponse)
+	prodSuccess := new(sarama.ProduceResponse)
+	prodSuccess.AddTopicPartition(topic, 0, sarama.ErrNoError)
+	leader.Returns(prodSuccess)
+
+	sink, err := newKafkaSink(kafka.ProducerCfg{BrokerList: []string{seedBroker.Addr()}, Topic: topic})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send([][]byte{[]byte("a\n")}))
+	require.NoError(t, sink.Close())
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := newSyslogSink("testSyslog", SyslogConfig{Network: "udp", Addr: conn.LocalAddr().String()})
+	require.NoError(t, err)
+	defer sink.Close()
+	require.NoError(t, sink.Send([][]byte{[]byte("REQ\tentry\n")}))
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+	require.True(t, strings.Contains(msg, "testSyslog"))
+	require.True(t, strings.HasSuffix(msg, "REQ\tentry\n"))
+}
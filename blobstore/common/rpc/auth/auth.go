@@ -31,7 +31,10 @@ const (
 	TokenHeaderKey = "BLOB-STORE-AUTH-TOKEN"
 )
 
-var errMismatchToken = errors.New("mismatch token")
+var (
+	errMismatchToken = errors.New("mismatch token")
+	errMissingToken  = errors.New("missing token")
+)
 
 type Config struct {
 	EnableAuth bool   `json:"enable_auth"`
@@ -35,23 +35,29 @@ func NewAuthHandler(cfg *Config) *AuthHandler {
 }
 
 func (self *AuthHandler) Handler(w http.ResponseWriter, req *http.Request, f func(http.ResponseWriter, *http.Request)) {
-	token := req.Header.Get(TokenHeaderKey)
-	if token == "" {
+	if err := self.Verify(req); err != nil {
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
+
+	f(w, req)
+}
+
+// Verify returns error if token of the request is missing or mismatched
+func (self *AuthHandler) Verify(req *http.Request) error {
+	token := req.Header.Get(TokenHeaderKey)
+	if token == "" {
+		return errMissingToken
+	}
 	info, err := decodeAuthInfo(token)
 	if err != nil {
-		w.WriteHeader(http.StatusForbidden)
-		return
+		return err
 	}
 	info.others = genEncodeStr(req)
 
 	err = verify(info, self.Secret)
 	if err != nil && err == errMismatchToken {
-		w.WriteHeader(http.StatusForbidden)
-		return
+		return err
 	}
-
-	f(w, req)
+	return nil
 }
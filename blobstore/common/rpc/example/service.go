@@ -78,8 +78,8 @@ func NewAppHandler(cfg AppConfig) *rpc.Router {
 
 	// args key/val in c.Param
 	rpc.PUT("/upload/:name/:size/:mode/:desc", app.Upload, rpc.OptArgsURI())
-	// parse args in handler by c.ArgsBody
-	rpc.POST("/update", app.Update)
+	// parse args in handler by c.ArgsBody, body larger than 1MB is rejected
+	rpc.POST("/update", app.Update, rpc.OptMaxBodySize(1<<20))
 	rpc.DELETE("/delete", app.Delete, rpc.OptArgsQuery())
 	rpc.POST("/download", app.Download, rpc.OptArgsForm())
 	rpc.GET("/stream", app.Stream)
@@ -87,8 +87,9 @@ func NewAppHandler(cfg AppConfig) *rpc.Router {
 	// but you can define you own rpc.Parser on args struct
 	rpc.HEAD("/exist/:name", app.Exist, rpc.OptArgsURI(), rpc.OptArgsQuery())
 	rpc.GET("/stat/:name", app.Stat, rpc.OptArgsURI(), rpc.OptArgsQuery())
-	// initialized 8 room for key/val map of c.Meta
-	rpc.GET("/list", app.List, rpc.OptMetaCapacity(8))
+	// initialized 8 room for key/val map of c.Meta,
+	// at most 16 lists are served at the same time, and context is canceled after 10s
+	rpc.GET("/list", app.List, rpc.OptMetaCapacity(8), rpc.OptMaxConcurrent(16), rpc.OptTimeout(10*time.Second))
 
 	// argument in uri with option
 	rpc.GET("/args/:require/:option", app.OptionalArgs, rpc.OptArgsURI())
@@ -0,0 +1,201 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+)
+
+// transport of service
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// grpc metadata keys of rpc.Error, they are sent in trailer.
+const (
+	grpcMetaStatus = "x-rpc-status"
+	grpcMetaCode   = "x-rpc-code"
+)
+
+// metadataCarrier propagates span context with grpc metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Set(key, val string) {
+	key = strings.ToLower(key)
+	c[key] = append(c[key], val)
+}
+
+func (c metadataCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vals := range c {
+		for _, v := range vals {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewGRPCServer returns grpc server with trace and error interceptor.
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.UnaryInterceptor(UnaryServerInterceptor))
+	return grpc.NewServer(opts...)
+}
+
+// DialGRPC dials grpc server without tls, with trace and error interceptor.
+func DialGRPC(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts, grpc.WithInsecure(), grpc.WithUnaryInterceptor(UnaryClientInterceptor))
+	return grpc.DialContext(ctx, target, opts...)
+}
+
+// UnaryServerInterceptor starts span from incoming metadata,
+// and converts returned error to grpc status.
+func UnaryServerInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	spanCtx, _ := trace.Extract(trace.TextMap, metadataCarrier(md))
+	var span trace.Span
+	if traceIDs := md.Get(trace.RequestIDKey); len(traceIDs) > 0 && traceIDs[0] != "" {
+		span, ctx = trace.StartSpanFromContextWithTraceID(ctx, info.FullMethod, traceIDs[0], ext.RPCServerOption(spanCtx))
+	} else {
+		span, ctx = trace.StartSpanFromContext(ctx, info.FullMethod, ext.RPCServerOption(spanCtx))
+	}
+	defer span.Finish()
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		span.Warnf("grpc %s failed: %s", info.FullMethod, err.Error())
+		statusCode, errCode, _ := DetectError(err)
+		grpc.SetTrailer(ctx, metadata.Pairs(
+			grpcMetaStatus, strconv.Itoa(statusCode),
+			grpcMetaCode, errCode))
+		return nil, status.Error(httpStatusToGRPCCode(statusCode), err.Error())
+	}
+	return resp, nil
+}
+
+// UnaryClientInterceptor injects span into outgoing metadata,
+// and converts grpc status to *Error.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	span := trace.SpanFromContextSafe(ctx)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	if err := span.Tracer().Inject(span.Context(), trace.TextMap, metadataCarrier(md)); err != nil {
+		span.Warnf("inject span into grpc metadata failed: %s", err.Error())
+	}
+	md.Set(trace.RequestIDKey, span.TraceID())
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	var trailer metadata.MD
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+	return GRPCErrorToError(err, trailer)
+}
+
+// GRPCErrorToError converts grpc error with trailer to *Error.
+func GRPCErrorToError(err error, trailer metadata.MD) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	statusCode := grpcCodeToHTTPStatus(st.Code())
+	if vals := trailer.Get(grpcMetaStatus); len(vals) > 0 {
+		if code, e := strconv.Atoi(vals[0]); e == nil {
+			statusCode = code
+		}
+	}
+	errCode := ""
+	if vals := trailer.Get(grpcMetaCode); len(vals) > 0 {
+		errCode = vals[0]
+	}
+	return NewError(statusCode, errCode, errors.New(st.Message()))
+}
+
+func httpStatusToGRPCCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if statusCode >= 400 && statusCode < 500 {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+func grpcCodeToHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
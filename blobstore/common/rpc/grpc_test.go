@@ -0,0 +1,88 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+)
+
+func TestGRPCErrorConvert(t *testing.T) {
+	require.NoError(t, GRPCErrorToError(nil, nil))
+
+	errNotGRPC := errors.New("not grpc error")
+	require.ErrorIs(t, GRPCErrorToError(errNotGRPC, nil), errNotGRPC)
+
+	err := GRPCErrorToError(status.Error(codes.NotFound, "not found"), nil)
+	require.Equal(t, http.StatusNotFound, DetectStatusCode(err))
+	require.Equal(t, "not found", err.Error())
+
+	trailer := metadata.Pairs(grpcMetaStatus, "801", grpcMetaCode, "Custom")
+	err = GRPCErrorToError(status.Error(codes.Internal, "custom"), trailer)
+	require.Equal(t, 801, DetectStatusCode(err))
+	require.Equal(t, "Custom", DetectErrorCode(err))
+
+	for _, code := range []int{
+		http.StatusOK, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden,
+		http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests, 499,
+		http.StatusNotImplemented, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		http.StatusInternalServerError,
+	} {
+		require.Equal(t, code, grpcCodeToHTTPStatus(httpStatusToGRPCCode(code)))
+	}
+	require.Equal(t, codes.FailedPrecondition, httpStatusToGRPCCode(http.StatusPreconditionFailed))
+	require.Equal(t, codes.Internal, httpStatusToGRPCCode(597))
+}
+
+func TestGRPCInterceptor(t *testing.T) {
+	span, ctx := trace.StartSpanFromContext(context.Background(), "TestGRPCInterceptor")
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+	err := UnaryClientInterceptor(ctx, "/test/Method", nil, nil, nil, invoker)
+	require.Equal(t, http.StatusServiceUnavailable, DetectStatusCode(err))
+	require.Equal(t, []string{span.TraceID()}, outgoing.Get(trace.RequestIDKey))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Method"}
+	incoming := metadata.NewIncomingContext(context.Background(), outgoing)
+	resp, err := UnaryServerInterceptor(incoming, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		require.Equal(t, span.TraceID(), trace.SpanFromContextSafe(ctx).TraceID())
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	_, err = UnaryServerInterceptor(incoming, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, NewError(http.StatusConflict, "Conflict", errors.New("conflict"))
+	})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.AlreadyExists, st.Code())
+	require.Equal(t, "conflict", st.Message())
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrorClass classification of a request result
+type ErrorClass int
+
+const (
+	// ErrorClassSuccess the host responded normally
+	ErrorClassSuccess ErrorClass = iota
+	// ErrorClassBusiness the host responded with a business error,
+	// retry on other hosts will get the same result
+	ErrorClassBusiness
+	// ErrorClassRetriable network error or the host is unavailable, may be retried on other hosts
+	ErrorClassRetriable
+)
+
+func (ec ErrorClass) String() string {
+	switch ec {
+	case ErrorClassSuccess:
+		return "success"
+	case ErrorClassBusiness:
+		return "business"
+	case ErrorClassRetriable:
+		return "retriable"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultClassifier classifies network errors as retriable except canceled by caller,
+// http status codes 2xx and 4xx are not retriable. Results of requests whose context is
+// done are not reported to host health, a deadline of the caller is not a host failure.
+func DefaultClassifier(code int, err error) ErrorClass {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return ErrorClassBusiness
+		}
+		if IsNetworkError(err) {
+			return ErrorClassRetriable
+		}
+		return ErrorClassBusiness
+	}
+	switch code / 100 {
+	case 2:
+		return ErrorClassSuccess
+	case 4:
+		return ErrorClassBusiness
+	default:
+		return ErrorClassRetriable
+	}
+}
+
+// IsNetworkError returns true if err is caused by connection broken, refused or timeout
+func IsNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+const (
+	defaultRetryBudgetMax   = 10
+	defaultRetryBudgetRatio = 0.1
+	defaultBreakerFailures  = 5
+	defaultBreakerOpenMs    = 10000
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// hostHealth adaptive retry budget and circuit breaker of a host.
+//
+// Retry budget: every retriable failure takes one token, and every success returns
+// ratio token, requests are not retried on the host if tokens not more than half of max,
+// so retries are throttled when the host keeps failing.
+//
+// Circuit breaker: the host is ejected after consecutive retriable failures,
+// and be half opened after open duration, the first result of the half opened
+// host decides it to be closed or opened again.
+type hostHealth struct {
+	budgetMax   float64
+	budgetRatio float64
+	maxFailures int
+	openTimeout time.Duration
+
+	mu        sync.Mutex
+	tokens    float64
+	failures  int
+	state     breakerState
+	openUntil time.Time
+}
+
+func newHostHealth(cfg *LbConfig) *hostHealth {
+	return &hostHealth{
+		budgetMax:   float64(cfg.RetryBudgetMax),
+		budgetRatio: cfg.RetryBudgetRatio,
+		maxFailures: cfg.BreakerFailures,
+		openTimeout: time.Duration(cfg.BreakerOpenMs) * time.Millisecond,
+		tokens:      float64(cfg.RetryBudgetMax),
+	}
+}
+
+// allowRetry returns true if the host has enough retry budget
+func (h *hostHealth) allowRetry() bool {
+	if h.budgetRatio <= 0 {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.tokens > h.budgetMax/2
+}
+
+// available returns false if the breaker of host is opened
+func (h *hostHealth) available(now time.Time) bool {
+	if h.maxFailures <= 0 {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.state == breakerOpen && !now.Before(h.openUntil) {
+		h.state = breakerHalfOpen
+	}
+	return h.state != breakerOpen
+}
+
+func (h *hostHealth) report(class ErrorClass) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if class != ErrorClassRetriable {
+		h.tokens += h.budgetRatio
+		if h.tokens > h.budgetMax {
+			h.tokens = h.budgetMax
+		}
+		h.failures = 0
+		h.state = breakerClosed
+		return
+	}
+
+	h.tokens--
+	if h.tokens < 0 {
+		h.tokens = 0
+	}
+	if h.maxFailures <= 0 {
+		return
+	}
+	h.failures++
+	if h.state == breakerHalfOpen || h.failures >= h.maxFailures {
+		h.state = breakerOpen
+		h.openUntil = time.Now().Add(h.openTimeout)
+	}
+}
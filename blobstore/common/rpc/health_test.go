@@ -0,0 +1,162 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorClassifier(t *testing.T) {
+	for _, cs := range []struct {
+		code  int
+		err   error
+		class ErrorClass
+	}{
+		{http.StatusOK, nil, ErrorClassSuccess},
+		{http.StatusPartialContent, nil, ErrorClassSuccess},
+		{http.StatusNotFound, nil, ErrorClassBusiness},
+		{http.StatusInternalServerError, nil, ErrorClassRetriable},
+		{http.StatusServiceUnavailable, nil, ErrorClassRetriable},
+		{0, context.Canceled, ErrorClassBusiness},
+		{0, &url.Error{Op: "Get", URL: "/", Err: context.Canceled}, ErrorClassBusiness},
+		{0, &url.Error{Op: "Get", URL: "/", Err: syscall.ECONNREFUSED}, ErrorClassRetriable},
+		{0, io.ErrUnexpectedEOF, ErrorClassRetriable},
+		{0, syscall.ECONNRESET, ErrorClassRetriable},
+		{0, errors.New("business"), ErrorClassBusiness},
+	} {
+		require.Equal(t, cs.class, DefaultClassifier(cs.code, cs.err), "code:%d err:%v", cs.code, cs.err)
+	}
+	require.False(t, IsNetworkError(nil))
+	require.Equal(t, "retriable", ErrorClassRetriable.String())
+}
+
+func TestHostHealthRetryBudget(t *testing.T) {
+	h := newHostHealth(&LbConfig{RetryBudgetRatio: 0.5, RetryBudgetMax: 4, BreakerFailures: -1})
+	require.True(t, h.allowRetry())
+	h.report(ErrorClassRetriable)
+	require.True(t, h.allowRetry())
+	h.report(ErrorClassRetriable)
+	require.False(t, h.allowRetry())
+	for i := 0; i < 10; i++ {
+		h.report(ErrorClassRetriable)
+	}
+	require.Equal(t, float64(0), h.tokens)
+
+	// business errors return tokens
+	for i := 0; i < 5; i++ {
+		h.report(ErrorClassBusiness)
+	}
+	require.True(t, h.allowRetry())
+	for i := 0; i < 10; i++ {
+		h.report(ErrorClassSuccess)
+	}
+	require.Equal(t, float64(4), h.tokens)
+	require.True(t, h.available(time.Now()))
+
+	h = newHostHealth(&LbConfig{RetryBudgetRatio: -1})
+	for i := 0; i < 10; i++ {
+		h.report(ErrorClassRetriable)
+	}
+	require.True(t, h.allowRetry())
+}
+
+func TestHostHealthBreaker(t *testing.T) {
+	h := newHostHealth(&LbConfig{RetryBudgetRatio: -1, BreakerFailures: 3, BreakerOpenMs: 100})
+	now := time.Now()
+	h.report(ErrorClassRetriable)
+	h.report(ErrorClassRetriable)
+	h.report(ErrorClassSuccess)
+	h.report(ErrorClassRetriable)
+	h.report(ErrorClassRetriable)
+	require.True(t, h.available(now))
+	h.report(ErrorClassRetriable)
+	require.False(t, h.available(time.Now()))
+
+	// half opened, failed again
+	require.True(t, h.available(time.Now().Add(time.Second)))
+	h.report(ErrorClassRetriable)
+	require.False(t, h.available(time.Now()))
+
+	// half opened, closed by success
+	require.True(t, h.available(time.Now().Add(time.Second)))
+	h.report(ErrorClassSuccess)
+	require.True(t, h.available(time.Now()))
+	require.Equal(t, breakerClosed, h.state)
+}
+
+func TestLbClient_BreakerEject(t *testing.T) {
+	cfg := newCfg([]string{refusedHosts[0], testServer.URL}, nil)
+	cfg.BreakerFailures = 1
+	cfg.BreakerOpenMs = 60000
+	client := NewLbClient(cfg, nil).(*lbClient)
+	defer client.Close()
+
+	for i := 0; i < 10; i++ {
+		resp, err := client.Head(context.Background(), "/get/name")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Equal(t, []string{testServer.URL}, client.availableHosts())
+
+	// all hosts are ejected
+	client.healthMap[testServer.URL].report(ErrorClassRetriable)
+	require.Len(t, client.availableHosts(), 2)
+}
+
+func TestLbClient_BreakerCallerDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+	cfg := newCfg([]string{server.URL}, nil)
+	cfg.BreakerFailures = 1
+	cfg.BreakerOpenMs = 60000
+	client := NewLbClient(cfg, nil).(*lbClient)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := client.Head(ctx, "/get/name")
+		cancel()
+		require.Error(t, err)
+	}
+	require.Equal(t, []string{server.URL}, client.availableHosts())
+	require.Equal(t, 0, client.healthMap[server.URL].failures)
+}
+
+func TestLbClient_RetryBudgetExhausted(t *testing.T) {
+	cfg := newCfg([]string{refusedHosts[0], refusedHosts[1]}, nil)
+	cfg.BreakerFailures = -1
+	cfg.RequestTryTimes = 10
+	client := NewLbClient(cfg, nil).(*lbClient)
+	defer client.Close()
+
+	for _, h := range client.healthMap {
+		h.tokens = h.budgetMax / 2
+	}
+	_, err := client.Head(context.Background(), "/get/name")
+	require.Error(t, err)
+	require.True(t, IsNetworkError(err))
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	urllib "net/url"
 	"strings"
+	"time"
 
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 )
@@ -46,8 +47,23 @@ type LbConfig struct {
 	// RequestTryTimes The maximum number of attempts for a request hosts.
 	RequestTryTimes int `json:"try_times"`
 
-	// should retry function
+	// RetryBudgetRatio adaptive retry budget of each host, every success returns
+	// the ratio token and every retriable failure takes one token,
+	// requests are not retried on the host if tokens not more than half of RetryBudgetMax.
+	// default value is 0.1, retry budget is disabled if RetryBudgetRatio < 0
+	RetryBudgetRatio float64 `json:"retry_budget_ratio"`
+	// RetryBudgetMax max tokens of retry budget, default value is 10
+	RetryBudgetMax int `json:"retry_budget_max"`
+	// BreakerFailures the host is ejected after consecutive retriable failures,
+	// default value is 5, circuit breaker is disabled if BreakerFailures < 0
+	BreakerFailures int `json:"breaker_failures"`
+	// BreakerOpenMs duration of the ejected host to be half opened, default value is 10000
+	BreakerOpenMs int `json:"breaker_open_ms"`
+
+	// should retry function, default retry if classified as retriable
 	ShouldRetry func(code int, err error) bool `json:"-"`
+	// classify result of host, only retriable errors count as failures of the host
+	Classifier func(code int, err error) ErrorClass `json:"-"`
 
 	// config for simple client
 	Config
@@ -57,6 +73,7 @@ type lbClient struct {
 	requestTryTimes int
 	// host for simple client
 	clientMap map[string]Client
+	healthMap map[string]*hostHealth
 
 	sel Selector
 	cfg *LbConfig
@@ -79,8 +96,26 @@ func NewLbClient(cfg *LbConfig, sel Selector) Client {
 	if cfg.RequestTryTimes == 0 {
 		cfg.RequestTryTimes = len(cfg.Hosts) + len(cfg.BackupHosts) + 1
 	}
+	if cfg.Classifier == nil {
+		cfg.Classifier = DefaultClassifier
+	}
 	if cfg.ShouldRetry == nil {
-		cfg.ShouldRetry = defaultShouldRetry
+		classifier := cfg.Classifier
+		cfg.ShouldRetry = func(code int, err error) bool {
+			return classifier(code, err) == ErrorClassRetriable
+		}
+	}
+	if cfg.RetryBudgetRatio == 0 {
+		cfg.RetryBudgetRatio = defaultRetryBudgetRatio
+	}
+	if cfg.RetryBudgetMax <= 0 {
+		cfg.RetryBudgetMax = defaultRetryBudgetMax
+	}
+	if cfg.BreakerFailures == 0 {
+		cfg.BreakerFailures = defaultBreakerFailures
+	}
+	if cfg.BreakerOpenMs <= 0 {
+		cfg.BreakerOpenMs = defaultBreakerOpenMs
 	}
 	if cfg.HostTryTimes > cfg.RequestTryTimes {
 		cfg.HostTryTimes = cfg.RequestTryTimes - 1
@@ -93,22 +128,45 @@ func NewLbClient(cfg *LbConfig, sel Selector) Client {
 	}
 	cl := &lbClient{sel: sel, cfg: cfg}
 	cl.clientMap = make(map[string]Client)
+	cl.healthMap = make(map[string]*hostHealth)
 	for _, host := range cfg.Hosts {
 		cl.clientMap[host] = NewClient(&cfg.Config)
+		cl.healthMap[host] = newHostHealth(cfg)
 	}
 	for _, host := range cfg.BackupHosts {
 		cl.clientMap[host] = NewClient(&cfg.Config)
+		cl.healthMap[host] = newHostHealth(cfg)
 	}
 
 	cl.requestTryTimes = cfg.RequestTryTimes
 	return cl
 }
 
-var defaultShouldRetry = func(code int, err error) bool {
-	if err != nil || (code/100 != 4 && code/100 != 2) {
-		return true
+// availableHosts returns hosts from selector, hosts with opened circuit breaker are ejected.
+// all hosts are returned if all of them are ejected.
+func (c *lbClient) availableHosts() []string {
+	hosts := c.sel.GetAvailableHosts()
+	now := time.Now()
+	available := hosts[:0:0]
+	for _, host := range hosts {
+		if h, ok := c.healthMap[host]; !ok || h.available(now) {
+			available = append(available, host)
+		}
+	}
+	if len(available) == 0 {
+		return hosts
+	}
+	return available
+}
+
+// nextRetryHost returns index of the next host which has retry budget, -1 if not found
+func (c *lbClient) nextRetryHost(hosts []string, index int) int {
+	for ; index < len(hosts); index++ {
+		if h, ok := c.healthMap[hosts[index]]; !ok || h.allowRetry() {
+			return index
+		}
 	}
-	return false
+	return -1
 }
 
 func (c *lbClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
@@ -262,6 +320,28 @@ func (c *lbClient) doCtx(ctx context.Context, r *http.Request) (resp *http.Respo
 	)
 
 	for i := 0; i < tryTimes; i++ {
+		// get the available hosts
+		if index == len(hosts) || hosts == nil {
+			hosts = c.availableHosts()
+			if len(hosts) < 1 {
+				if resp != nil && resp.Body != nil {
+					resp.Body.Close()
+				}
+				err = errNoHost
+				span.Errorf("lb.doCtx: get host failed: %s", err.Error())
+				return nil, err
+			}
+			index = 0
+		}
+		// the failed response is returned if no host has retry budget
+		if i > 0 {
+			next := c.nextRetryHost(hosts, index)
+			if next < 0 {
+				span.Warnf("lb.doCtx: retry budget exhausted, try times: %d, err: %v", i, err)
+				return
+			}
+			index = next
+		}
 		// close failed body
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
@@ -272,16 +352,6 @@ func (c *lbClient) doCtx(ctx context.Context, r *http.Request) (resp *http.Respo
 			return nil, ctx.Err()
 		default:
 		}
-		// get the available hosts
-		if index == len(hosts) || hosts == nil {
-			hosts = c.sel.GetAvailableHosts()
-			if len(hosts) < 1 {
-				err = errNoHost
-				span.Errorf("lb.doCtx: get host failed: %s", err.Error())
-				return
-			}
-			index = 0
-		}
 		host := hosts[index]
 		// get the real url
 		r.URL, err = urllib.Parse(host + reqURI)
@@ -291,15 +361,19 @@ func (c *lbClient) doCtx(ctx context.Context, r *http.Request) (resp *http.Respo
 		}
 		r.Host = r.URL.Host
 		resp, err = c.clientMap[host].Do(ctx, r)
+		code := 0
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		// errors caused by context of the caller are not counted against the host
+		if h, ok := c.healthMap[host]; ok && ctx.Err() == nil {
+			h.report(c.cfg.Classifier(code, err))
+		}
 		if i == tryTimes-1 {
 			span.Warnf("lb.doCtx: the last host of request, try times: %d, err: %v, host: %s",
 				i+1, err, host)
 			return
 		}
-		code := 0
-		if resp != nil {
-			code = resp.StatusCode
-		}
 		logInfo := fmt.Sprintf("try times: %d, code: %d, err: %v, host: %s", i+1, code, err, host)
 		if c.cfg.ShouldRetry(code, err) {
 			span.Info("lb.doCtx: retry host,", logInfo)
@@ -16,6 +16,7 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
@@ -40,6 +41,7 @@ const (
 	// trace
 	HeaderTraceLog  = "Trace-Log"
 	HeaderTraceTags = "Trace-Tags"
+	HeaderRequestID = "X-Request-Id"
 
 	// crc checker
 	HeaderCrcEncoded    = "X-Crc-Encoded"
@@ -131,6 +133,20 @@ type (
 	}
 )
 
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a new context holding the request id,
+// client propagates the request id in header to downstream services
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id of context, empty if not found
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
 // ProgressHandler http progress handler
 type ProgressHandler interface {
 	Handler(http.ResponseWriter, *http.Request, func(http.ResponseWriter, *http.Request))
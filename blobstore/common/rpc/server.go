@@ -15,11 +15,23 @@
 package rpc
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
+var (
+	errRouteTooManyRequests = NewError(http.StatusTooManyRequests, "TooManyRequests",
+		errors.New("too many concurrent requests of the route"))
+	errRouteBodyTooLarge = NewError(http.StatusRequestEntityTooLarge, "RequestEntityTooLarge",
+		errors.New("request body is too large"))
+	errRouteTimeout = NewError(http.StatusServiceUnavailable, "Timeout",
+		errors.New("request is not finished in time"))
+)
+
 type (
 	// HandlerFunc defines the handler of app function
 	HandlerFunc func(*Context)
@@ -38,6 +50,11 @@ type (
 		argsPostForm bool
 
 		metaCapacity int
+
+		// limits of the route, zero means no limit
+		timeout       time.Duration
+		maxBodySize   int64
+		maxConcurrent int
 	}
 	funcServerOption struct {
 		f func(*serverOptions)
@@ -53,6 +70,10 @@ func (so *serverOptions) copy() *serverOptions {
 		argsPostForm: so.argsPostForm,
 
 		metaCapacity: so.metaCapacity,
+
+		timeout:       so.timeout,
+		maxBodySize:   so.maxBodySize,
+		maxConcurrent: so.maxConcurrent,
 	}
 }
 
@@ -114,6 +135,35 @@ func OptMetaCapacity(capacity int) ServerOption {
 	})
 }
 
+// OptTimeout cancels context of the request after timeout,
+// handler should return in time when the context is done.
+func OptTimeout(timeout time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		if timeout >= 0 {
+			o.timeout = timeout
+		}
+	})
+}
+
+// OptMaxBodySize rejects request with body larger than size,
+// reading more than size of body without content length returns error.
+func OptMaxBodySize(size int64) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		if size >= 0 {
+			o.maxBodySize = size
+		}
+	})
+}
+
+// OptMaxConcurrent rejects request if there are n requests of the route being served.
+func OptMaxConcurrent(n int) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		if n >= 0 {
+			o.maxConcurrent = n
+		}
+	})
+}
+
 // makeHandler make handle of httprouter
 func makeHandler(handlers []HandlerFunc, opts ...ServerOption) httprouter.Handle {
 	opt := new(serverOptions)
@@ -121,7 +171,33 @@ func makeHandler(handlers []HandlerFunc, opts ...ServerOption) httprouter.Handle
 		o.apply(opt)
 	}
 
+	var sem chan struct{}
+	if opt.maxConcurrent > 0 {
+		sem = make(chan struct{}, opt.maxConcurrent)
+	}
+
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		var rejected error
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			default:
+				rejected = errRouteTooManyRequests
+			}
+		}
+		if opt.maxBodySize > 0 {
+			if r.ContentLength > opt.maxBodySize {
+				rejected = errRouteBodyTooLarge
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, opt.maxBodySize)
+		}
+		if opt.timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), opt.timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+
 		c := &Context{
 			opts:  opt,
 			Param: ps,
@@ -134,8 +210,17 @@ func makeHandler(handlers []HandlerFunc, opts ...ServerOption) httprouter.Handle
 			index:    -1,
 			handlers: handlers,
 		}
+		if rejected != nil {
+			c.RespondError(rejected)
+			return
+		}
+
 		c.Next()
 		if !c.wroteHeader {
+			if opt.timeout > 0 && r.Context().Err() == context.DeadlineExceeded {
+				c.RespondError(errRouteTimeout)
+				return
+			}
 			c.RespondStatus(http.StatusOK)
 		}
 	}
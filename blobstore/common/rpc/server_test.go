@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -313,6 +314,54 @@ func TestServerOptMetaCapacity(t *testing.T) {
 	}
 }
 
+func TestServerOptLimits(t *testing.T) {
+	router := New()
+	router.Handle(http.MethodGet, "/timeout", func(c *Context) {
+		<-c.Request.Context().Done()
+	}, OptTimeout(10*time.Millisecond))
+	router.Handle(http.MethodGet, "/timeout/respond", func(c *Context) {
+		<-c.Request.Context().Done()
+		c.RespondStatus(http.StatusAccepted)
+	}, OptTimeout(10*time.Millisecond))
+	router.Handle(http.MethodPut, "/body", func(c *Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.RespondError(NewError(http.StatusBadRequest, "", err))
+		}
+	}, OptMaxBodySize(4))
+
+	enter, leave := make(chan struct{}), make(chan struct{})
+	router.Handle(http.MethodGet, "/concurrent", func(c *Context) {
+		enter <- struct{}{}
+		<-leave
+	}, OptMaxConcurrent(1))
+
+	serve := func(method, path string, body io.Reader, contentLength int64) int {
+		w := new(mockResponseWriter)
+		req, _ := http.NewRequest(method, path, body)
+		req.ContentLength = contentLength
+		router.ServeHTTP(w, req)
+		return w.status
+	}
+
+	require.Equal(t, http.StatusServiceUnavailable, serve(http.MethodGet, "/timeout", nil, 0))
+	require.Equal(t, http.StatusAccepted, serve(http.MethodGet, "/timeout/respond", nil, 0))
+
+	require.Equal(t, http.StatusOK, serve(http.MethodPut, "/body", bytes.NewReader([]byte("1234")), 4))
+	require.Equal(t, http.StatusRequestEntityTooLarge, serve(http.MethodPut, "/body", bytes.NewReader([]byte("12345")), 5))
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/body", bytes.NewReader([]byte("12345")), -1))
+
+	done := make(chan int)
+	go func() { done <- serve(http.MethodGet, "/concurrent", nil, 0) }()
+	<-enter
+	require.Equal(t, http.StatusTooManyRequests, serve(http.MethodGet, "/concurrent", nil, 0))
+	leave <- struct{}{}
+	require.Equal(t, http.StatusOK, <-done)
+	go func() { done <- serve(http.MethodGet, "/concurrent", nil, 0) }()
+	<-enter
+	leave <- struct{}{}
+	require.Equal(t, http.StatusOK, <-done)
+}
+
 type marshalData struct {
 	I int
 	S string
@@ -257,6 +257,11 @@ func (c *client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 	if req.Header.Get(HeaderUA) == "" {
 		req.Header.Set(HeaderUA, UserAgent)
 	}
+	if req.Header.Get(HeaderRequestID) == "" {
+		if requestID := RequestIDFromContext(ctx); requestID != "" {
+			req.Header.Set(HeaderRequestID, requestID)
+		}
+	}
 	span := trace.SpanFromContextSafe(ctx)
 	err := trace.InjectWithHTTPHeader(ctx, req)
 	if err != nil {
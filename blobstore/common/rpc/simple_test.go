@@ -54,6 +54,9 @@ func (s *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		callWithJSON(w, r)
 	case "/timeout":
 		timeout(w, r)
+	case "/requestid":
+		marshal, _ := json.Marshal(ret{Name: r.Header.Get(HeaderRequestID)})
+		w.Write(marshal)
 	case "/notfound":
 		ReplyWith(w, 404, "", []byte("404 page not found"))
 	default:
@@ -173,6 +176,19 @@ func TestClient_GetWith(t *testing.T) {
 	require.NotNil(t, result)
 }
 
+func TestClient_RequestID(t *testing.T) {
+	result := &ret{}
+	err := simpleClient.GetWith(context.Background(), testServer.URL+"/requestid", result)
+	require.NoError(t, err)
+	require.Equal(t, "", result.Name)
+
+	ctx := ContextWithRequestID(context.Background(), "test-request-id")
+	require.Equal(t, "test-request-id", RequestIDFromContext(ctx))
+	err = simpleClient.GetWith(ctx, testServer.URL+"/requestid", result)
+	require.NoError(t, err)
+	require.Equal(t, "test-request-id", result.Name)
+}
+
 func TestClient_PostWithCrc(t *testing.T) {
 	ctx := context.Background()
 	result := &ret{}
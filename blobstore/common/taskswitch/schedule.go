@@ -0,0 +1,136 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package taskswitch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	minutesOfDay = 24 * 60
+
+	// ScheduleKeySuffix config key of switch schedule is switch name with the suffix,
+	// eg: balance_schedule: "00:00-06:00,22:00-24:00"
+	ScheduleKeySuffix = "_schedule"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ScheduleKey returns config key of switch schedule
+func ScheduleKey(switchName string) string {
+	return switchName + ScheduleKeySuffix
+}
+
+// Window time window of a day in local time, [From, To) minutes of day,
+// the window crosses midnight if From is greater than To.
+type Window struct {
+	From int
+	To   int
+}
+
+func (w Window) contains(minute int) bool {
+	if w.From <= w.To {
+		return minute >= w.From && minute < w.To
+	}
+	return minute >= w.From || minute < w.To
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.From/60, w.From%60, w.To/60, w.To%60)
+}
+
+// Schedule time windows in which the switch is enabled, empty means all day.
+type Schedule []Window
+
+// ParseSchedule parses windows separated by ',' eg: "00:00-06:00,22:00-24:00"
+func ParseSchedule(s string) (Schedule, error) {
+	var schedule Schedule
+	for _, str := range strings.Split(s, ",") {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+		times := strings.Split(str, "-")
+		if len(times) != 2 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSchedule, str)
+		}
+		from, err := parseMinute(times[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseMinute(times[1])
+		if err != nil {
+			return nil, err
+		}
+		if from == to {
+			return nil, fmt.Errorf("%w: empty window %s", ErrInvalidSchedule, str)
+		}
+		schedule = append(schedule, Window{From: from, To: to})
+	}
+	return schedule, nil
+}
+
+func parseMinute(s string) (int, error) {
+	var hour, minute int
+	if n, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &hour, &minute); err != nil || n != 2 {
+		return 0, fmt.Errorf("%w: time %s", ErrInvalidSchedule, s)
+	}
+	m := hour*60 + minute
+	if hour < 0 || minute < 0 || minute >= 60 || m > minutesOfDay {
+		return 0, fmt.Errorf("%w: time %s", ErrInvalidSchedule, s)
+	}
+	return m, nil
+}
+
+// Contains returns true if t is in any window of schedule
+func (s Schedule) Contains(t time.Time) bool {
+	if len(s) == 0 {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range s {
+		if w.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// Until returns the duration from t to the beginning of next window
+func (s Schedule) Until(t time.Time) time.Duration {
+	if s.Contains(t) {
+		return 0
+	}
+	minute := t.Hour()*60 + t.Minute()
+	next := minutesOfDay
+	for _, w := range s {
+		d := (w.From - minute + minutesOfDay) % minutesOfDay
+		if d < next {
+			next = d
+		}
+	}
+	return time.Duration(next)*time.Minute - time.Duration(t.Second())*time.Second
+}
+
+func (s Schedule) String() string {
+	windows := make([]string, 0, len(s))
+	for _, w := range s {
+		windows = append(windows, w.String())
+	}
+	return strings.Join(windows, ",")
+}
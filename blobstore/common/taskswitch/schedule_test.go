@@ -0,0 +1,89 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package taskswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, s := range []string{"0:00", "00:00-06:00-08:00", "a:00-06:00", "00:00-24:01", "06:60-07:00", "06:00-06:00"} {
+		_, err := ParseSchedule(s)
+		require.ErrorIs(t, err, ErrInvalidSchedule, s)
+	}
+
+	schedule, err := ParseSchedule("")
+	require.NoError(t, err)
+	require.Nil(t, schedule)
+
+	schedule, err = ParseSchedule(" 00:00-06:00, 22:30-1:00 ,")
+	require.NoError(t, err)
+	require.Equal(t, Schedule{{From: 0, To: 360}, {From: 1350, To: 60}}, schedule)
+	require.Equal(t, "00:00-06:00,22:30-01:00", schedule.String())
+}
+
+func TestScheduleContains(t *testing.T) {
+	day := time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	require.True(t, Schedule(nil).Contains(at(12, 0)))
+	require.Equal(t, time.Duration(0), Schedule(nil).Until(at(12, 0)))
+
+	schedule, err := ParseSchedule("02:00-06:00,22:00-01:00")
+	require.NoError(t, err)
+	for _, c := range []struct {
+		hour, minute int
+		contains     bool
+		until        time.Duration
+	}{
+		{0, 0, true, 0},
+		{0, 59, true, 0},
+		{1, 0, false, time.Hour},
+		{2, 0, true, 0},
+		{5, 59, true, 0},
+		{6, 0, false, 16 * time.Hour},
+		{21, 30, false, 30 * time.Minute},
+		{23, 59, true, 0},
+	} {
+		require.Equal(t, c.contains, schedule.Contains(at(c.hour, c.minute)), c)
+		require.Equal(t, c.until, schedule.Until(at(c.hour, c.minute)), c)
+	}
+}
+
+func TestTaskSwitchSchedule(t *testing.T) {
+	ts := NewEnabledTaskSwitch()
+	now := time.Now()
+	minute := now.Hour()*60 + now.Minute()
+	ts.SetSchedule(Schedule{{From: (minute + 1) % minutesOfDay, To: (minute + 2) % minutesOfDay}})
+	require.False(t, ts.Enabled())
+
+	done := make(chan struct{})
+	go func() {
+		ts.WaitEnable()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("should wait for schedule")
+	case <-time.After(10 * time.Millisecond):
+	}
+	ts.SetSchedule(nil)
+	require.True(t, ts.Enabled())
+}
@@ -17,9 +17,11 @@ package taskswitch
 import (
 	"context"
 	"errors"
+	"net/http"
 	"sync"
 	"time"
 
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 )
 
@@ -39,10 +41,13 @@ var (
 	ErrNoSuchSwitch   = errors.New("no such switch")
 )
 
+const maxScheduleWait = syncTaskStatusIntervalS * time.Second
+
 type TaskSwitch struct {
-	mu      sync.Mutex
-	enabled bool
-	wg      sync.WaitGroup
+	mu       sync.Mutex
+	enabled  bool
+	schedule Schedule
+	wg       sync.WaitGroup
 }
 
 func newTaskSwitch() *TaskSwitch {
@@ -79,14 +84,41 @@ func (s *TaskSwitch) Disable() {
 	s.wg.Add(1)
 }
 
+// SetSchedule sets time windows of the switch, empty means all day
+func (s *TaskSwitch) SetSchedule(schedule Schedule) {
+	s.mu.Lock()
+	s.schedule = schedule
+	s.mu.Unlock()
+}
+
+// Schedule returns time windows of the switch
+func (s *TaskSwitch) Schedule() Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedule
+}
+
+// Enabled returns true if the switch is enabled and now is in its schedule
 func (s *TaskSwitch) Enabled() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.enabled
+	return s.enabled && s.schedule.Contains(time.Now())
 }
 
+// WaitEnable blocks until the switch is enabled and now is in its schedule
 func (s *TaskSwitch) WaitEnable() {
-	s.wg.Wait()
+	for {
+		s.wg.Wait()
+		wait := s.Schedule().Until(time.Now())
+		if wait <= 0 {
+			return
+		}
+		// schedule may be changed while waiting
+		if wait > maxScheduleWait {
+			wait = maxScheduleWait
+		}
+		time.Sleep(wait)
+	}
 }
 
 type ConfigGetter interface {
@@ -128,6 +160,8 @@ func (sm *SwitchMgr) update() {
 			continue
 		}
 
+		sm.updateSchedule(ctx, switchName, taskSwitch)
+
 		if switchStatus(statusStr) {
 			taskSwitch.Enable()
 			continue
@@ -136,6 +170,26 @@ func (sm *SwitchMgr) update() {
 	}
 }
 
+// updateSchedule keeps the old schedule if failed, clears it if not set
+func (sm *SwitchMgr) updateSchedule(ctx context.Context, switchName string, taskSwitch *TaskSwitch) {
+	span := trace.SpanFromContextSafe(ctx)
+	scheduleStr, err := sm.cmCfgGetter.GetConfig(ctx, ScheduleKey(switchName))
+	if err != nil {
+		if rpc.DetectStatusCode(err) == http.StatusNotFound {
+			taskSwitch.SetSchedule(nil)
+			return
+		}
+		span.Errorf("Get Fail schedule of switchName %s err %v", switchName, err)
+		return
+	}
+	schedule, err := ParseSchedule(scheduleStr)
+	if err != nil {
+		span.Errorf("invalid schedule of switchName %s err %v", switchName, err)
+		return
+	}
+	taskSwitch.SetSchedule(schedule)
+}
+
 func (sm *SwitchMgr) AddSwitch(switchName string) (*TaskSwitch, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
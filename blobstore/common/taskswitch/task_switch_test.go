@@ -17,9 +17,13 @@ package taskswitch
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
 )
 
 func TestTaskSwitch(t *testing.T) {
@@ -39,6 +43,9 @@ func (cfgGetter *mockCfgGetter) GetConfig(ctx context.Context, key string) (val
 	if val, ok := cfgGetter.m[key]; ok {
 		return val, nil
 	}
+	if key == ScheduleKey("switch3") {
+		return "", rpc.NewError(http.StatusNotFound, "NotFound", errors.New("no such key"))
+	}
 	return "", errors.New("no such key")
 }
 
@@ -58,6 +65,34 @@ func TestSwitchMgr(t *testing.T) {
 	require.Equal(t, true, s1.Enabled())
 	require.Equal(t, false, s2.Enabled())
 
+	// schedule
+	now := time.Now()
+	window := Window{From: now.Hour()*60 + now.Minute(), To: (now.Hour()*60 + now.Minute() + 2) % minutesOfDay}
+	cfgGetter.m[ScheduleKey("switch1")] = window.String()
+	cfgGetter.m[ScheduleKey("switch2")] = "invalid"
+	cfgGetter.m["switch2"] = SwitchOpen
+	sm.update()
+	require.Equal(t, Schedule{window}, s1.Schedule())
+	require.Nil(t, s2.Schedule())
+	require.True(t, s2.Enabled())
+
+	outside := Window{From: window.To, To: (window.To + 1) % minutesOfDay}
+	cfgGetter.m[ScheduleKey("switch1")] = outside.String()
+	sm.update()
+	require.False(t, s1.Enabled())
+
+	// keep schedule if failed, clear it if not set
+	cfgGetter.m["switch3"] = SwitchOpen
+	s3, err := sm.AddSwitch("switch3")
+	require.NoError(t, err)
+	s3.SetSchedule(Schedule{outside})
+	delete(cfgGetter.m, ScheduleKey("switch1"))
+	sm.update()
+	require.Equal(t, Schedule{outside}, s1.Schedule())
+	require.Nil(t, s3.Schedule())
+	require.True(t, s3.Enabled())
+	require.NoError(t, sm.DelSwitch("switch3"))
+
 	sm.update()
 	err = sm.DelSwitch("switch1")
 	require.NoError(t, err)
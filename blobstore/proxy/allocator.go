@@ -15,6 +15,8 @@
 package proxy
 
 import (
+	"net"
+
 	"github.com/cubefs/cubefs/blobstore/api/proxy"
 	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
@@ -36,6 +38,11 @@ func (s *Service) Alloc(c *rpc.Context) {
 		c.RespondError(errcode.ErrIllegalArguments)
 		return
 	}
+	if args.ClientID == "" {
+		if host, _, err := net.SplitHostPort(c.Request.RemoteAddr); err == nil {
+			args.ClientID = host
+		}
+	}
 	span.Infof("accept Alloc request, args: %v", args)
 	resp, err := s.volumeMgr.Alloc(ctx, args)
 	if err != nil {
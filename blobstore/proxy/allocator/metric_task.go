@@ -75,6 +75,15 @@ func (v *volumeMgr) metricReport(ctx context.Context) {
 				"codemode": codeMode.String(),
 				"type":     "total_free_size",
 			}).Set(float64(info.TotalFree()))
+		proxyStatusMetric.With(
+			prometheus.Labels{
+				"service":  "PROXY",
+				"cluster":  v.ClusterID.ToString(),
+				"idc":      v.Idc,
+				"host":     v.Host,
+				"codemode": codeMode.String(),
+				"type":     "fill_ratio_skew",
+			}).Set(fillRatioSkew(info.List(false)))
 		span.Debugf("metric report total_free_size: %d, idc: %s, codemode: %s,",
 			info.TotalFree(), v.Idc, codeMode.String())
 	}
@@ -0,0 +1,141 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package allocator
+
+import (
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+)
+
+const (
+	defaultSelectCandidateNum = 2
+	// proxy does not see the end of writes, allocations in the window are taken as in-flight writes
+	inflightWindow = 5 * time.Second
+)
+
+type candidate struct {
+	vol   *volume
+	score uint64 // hash weight of the volume for the key
+	load  int64  // free space shared by in-flight writes, allocated size is taken off at allocation
+}
+
+// clientHashKey returns hash key of client id, the same client chooses the same candidates
+func clientHashKey(clientID string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(clientID))
+	return h.Sum64()
+}
+
+// hashWeight returns rendezvous hash weight of vid for key,
+// adding or removing a volume only remaps keys which choose the volume.
+func hashWeight(key uint64, vid proto.Vid) uint64 {
+	return mix64(mix64(key) ^ uint64(vid))
+}
+
+// mix64 is the finalizer of murmur3, consecutive keys are spread over the whole space
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// selectCandidates returns top num volumes with the highest hash weight of key,
+// and candidates are sorted by load, the least loaded volume first.
+func selectCandidates(key uint64, vols []*volume, num int) []candidate {
+	if num <= 0 {
+		num = 1
+	}
+	if num > len(vols) {
+		num = len(vols)
+	}
+
+	candidates := make([]candidate, 0, num+1)
+	for _, vol := range vols {
+		c := candidate{vol: vol, score: hashWeight(key, vol.Vid)}
+		if len(candidates) == num && c.score <= candidates[num-1].score {
+			continue
+		}
+		idx := sort.Search(len(candidates), func(i int) bool {
+			return candidates[i].score < c.score
+		})
+		candidates = append(candidates, candidate{})
+		copy(candidates[idx+1:], candidates[idx:])
+		candidates[idx] = c
+		if len(candidates) > num {
+			candidates = candidates[:num]
+		}
+	}
+
+	for i := range candidates {
+		candidates[i].load = candidates[i].vol.load()
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].load > candidates[j].load
+	})
+	return candidates
+}
+
+// load returns free space divided by in-flight writes of the volume, the larger the less loaded
+func (vol *volume) load() int64 {
+	vol.mu.RLock()
+	defer vol.mu.RUnlock()
+	return int64(vol.Free) / (1 + vol.inflight(time.Now()))
+}
+
+// inflight returns the count of allocations in the current window, the caller should hold mu
+func (vol *volume) inflight(now time.Time) int64 {
+	if now.Sub(vol.inflightStart) >= inflightWindow {
+		return 0
+	}
+	return vol.inflightCount
+}
+
+// addInflight counts an allocation as in-flight write, the caller should hold mu
+func (vol *volume) addInflight(now time.Time) {
+	if now.Sub(vol.inflightStart) >= inflightWindow {
+		vol.inflightStart, vol.inflightCount = now, 0
+	}
+	vol.inflightCount++
+}
+
+// fillRatioSkew returns the difference between the max and min used ratio of volumes
+func fillRatioSkew(vols []*volume) float64 {
+	if len(vols) == 0 {
+		return 0
+	}
+	min, max := float64(1), float64(0)
+	for _, vol := range vols {
+		vol.mu.RLock()
+		total := vol.Used + vol.Free
+		ratio := float64(0)
+		if total > 0 {
+			ratio = float64(vol.Used) / float64(total)
+		}
+		vol.mu.RUnlock()
+		if ratio < min {
+			min = ratio
+		}
+		if ratio > max {
+			max = ratio
+		}
+	}
+	return max - min
+}
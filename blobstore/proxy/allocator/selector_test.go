@@ -0,0 +1,136 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package allocator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/api/proxy"
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/proxy/mock"
+)
+
+func genSelectorVols(num int, free uint64) []*volume {
+	vols := make([]*volume, 0, num)
+	for i := 1; i <= num; i++ {
+		vols = append(vols, &volume{AllocVolumeInfo: clustermgr.AllocVolumeInfo{
+			VolumeInfo: clustermgr.VolumeInfo{
+				VolumeInfoBase: clustermgr.VolumeInfoBase{Vid: proto.Vid(i), Free: free},
+			},
+		}})
+	}
+	return vols
+}
+
+func TestSelectCandidates(t *testing.T) {
+	vols := genSelectorVols(20, 1<<20)
+
+	require.Len(t, selectCandidates(1, vols, 0), 1)
+	require.Len(t, selectCandidates(1, vols, 3), 3)
+	require.Len(t, selectCandidates(1, vols[:2], 3), 2)
+
+	// removing a volume only remaps keys which chose the volume
+	removed := vols[10]
+	remain := append(append([]*volume{}, vols[:10]...), vols[11:]...)
+	for key := uint64(0); key < 1000; key++ {
+		before := selectCandidates(key, vols, 1)[0].vol
+		after := selectCandidates(key, remain, 1)[0].vol
+		if before != removed {
+			require.Equal(t, before.Vid, after.Vid)
+		}
+	}
+
+	// the least loaded candidate first
+	for key := uint64(0); key < 100; key++ {
+		candidates := selectCandidates(key, vols, 2)
+		busy := candidates[0].vol
+		busy.Free -= 1 << 19
+		candidates = selectCandidates(key, vols, 2)
+		require.NotEqual(t, busy.Vid, candidates[0].vol.Vid)
+		require.Equal(t, busy.Vid, candidates[1].vol.Vid)
+		busy.Free += 1 << 19
+	}
+
+	// the candidate with more in-flight writes is more loaded
+	candidates := selectCandidates(1, vols, 2)
+	busy := candidates[0].vol
+	busy.addInflight(time.Now())
+	candidates = selectCandidates(1, vols, 2)
+	require.Equal(t, busy.Vid, candidates[1].vol.Vid)
+	require.Equal(t, int64(1<<19), candidates[1].load)
+	// in-flight writes expire after the window
+	busy.inflightStart = time.Now().Add(-inflightWindow)
+	require.Equal(t, int64(1<<20), busy.load())
+	busy.addInflight(time.Now())
+	require.Equal(t, int64(1), busy.inflightCount)
+}
+
+func TestFillRatioSkew(t *testing.T) {
+	require.Equal(t, float64(0), fillRatioSkew(nil))
+
+	vols := genSelectorVols(3, 100)
+	require.Equal(t, float64(0), fillRatioSkew(vols))
+	vols[0].Used, vols[0].Free = 50, 50
+	vols[1].Used, vols[1].Free = 25, 75
+	require.InDelta(t, 0.5, fillRatioSkew(vols), 1e-9)
+}
+
+func TestHashAlloc(t *testing.T) {
+	cmcli := mock.ProxyMockClusterMgrCli(t)
+	ctx := context.Background()
+	bidMgr, _ := NewBidMgr(ctx, BlobConfig{BidAllocNums: 100000}, cmcli)
+	vm := volumeMgr{clusterMgr: cmcli, BidMgr: bidMgr, VolConfig: VolConfig{SelectCandidateNum: 2}}
+
+	info := &modeInfo{
+		current:        &volumes{},
+		backup:         &volumes{},
+		totalThreshold: 1 << 20,
+	}
+	for _, vol := range genSelectorVols(10, 1<<30) {
+		info.Put(vol, false)
+	}
+	info.Put(genSelectorVols(20, 1<<30)[19], true)
+	vm.modeInfos = map[codemode.CodeMode]*modeInfo{codemode.EC6P6: info}
+
+	args := &proxy.AllocVolsArgs{
+		Fsize:    1 << 20,
+		CodeMode: codemode.EC6P6,
+		BidCount: 1,
+		Excludes: []proto.Vid{1},
+	}
+	for i := 0; i < 1000; i++ {
+		vid, err := vm.allocVid(ctx, args)
+		require.NoError(t, err)
+		require.NotEqual(t, proto.Vid(1), vid)
+	}
+	vols := info.List(false)
+	require.Less(t, fillRatioSkew(vols[1:]), 0.05)
+
+	// the same client chooses the same candidates
+	args.ClientID = "10.0.0.1"
+	chosen := make(map[proto.Vid]struct{})
+	for i := 0; i < 100; i++ {
+		vid, err := vm.allocVid(ctx, args)
+		require.NoError(t, err)
+		chosen[vid] = struct{}{}
+	}
+	require.LessOrEqual(t, len(chosen), vm.SelectCandidateNum)
+}
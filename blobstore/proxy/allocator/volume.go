@@ -18,6 +18,7 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cubefs/cubefs/blobstore/api/clustermgr"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
@@ -25,8 +26,10 @@ import (
 
 type volume struct {
 	clustermgr.AllocVolumeInfo
-	deleted bool
-	mu      sync.RWMutex
+	deleted       bool
+	inflightStart time.Time
+	inflightCount int64
+	mu            sync.RWMutex
 }
 
 type volumes struct {
@@ -56,6 +56,7 @@ type VolConfig struct {
 	MetricReportIntervalS int             `json:"metric_report_interval_s"`
 	RetainVolumeBatchNum  int             `json:"retain_volume_batch_num"`
 	RetainBatchIntervalS  int64           `json:"retain_batch_interval_s"`
+	SelectCandidateNum    int             `json:"select_candidate_num"`
 	VolumeReserveSize     int             `json:"-"`
 }
 
@@ -234,6 +235,7 @@ func volConfCheck(cfg *VolConfig) {
 	defaulter.Equal(&cfg.MetricReportIntervalS, defaultMetricIntervalS)
 	defaulter.Equal(&cfg.RetainVolumeBatchNum, defaultRetainVolumeNum)
 	defaulter.Equal(&cfg.RetainBatchIntervalS, defaultRetainBatchIntervalS)
+	defaulter.Equal(&cfg.SelectCandidateNum, defaultSelectCandidateNum)
 
 	need := int(cfg.TotalThresholdRatio*float64(cfg.InitVolumeNum)) + 1
 	if cfg.DefaultAllocVolsNum <= need {
@@ -397,8 +399,24 @@ func (v *volumeMgr) List(ctx context.Context, codeMode codemode.CodeMode) (vids
 	return
 }
 
+// getNextVid chooses candidates by consistent hash of client id and allocates the least loaded one
+// by free space and in-flight writes,
+// falls back to round-robin if none of the candidates are available.
 func (v *volumeMgr) getNextVid(ctx context.Context, vols []*volume, modeInfo *modeInfo, args *proxy.AllocVolsArgs) (proto.Vid, error) {
-	curIdx := int(atomic.AddUint64(&v.preIdx, uint64(1)) % uint64(len(vols)))
+	key := atomic.AddUint64(&v.preIdx, uint64(1))
+	if v.SelectCandidateNum > 0 {
+		hashKey := key
+		if args.ClientID != "" {
+			hashKey = clientHashKey(args.ClientID)
+		}
+		for _, c := range selectCandidates(hashKey, vols, v.SelectCandidateNum) {
+			if v.modifySpace(ctx, c.vol, modeInfo, args) {
+				return c.vol.Vid, nil
+			}
+		}
+	}
+
+	curIdx := int(key % uint64(len(vols)))
 	l := len(vols) + curIdx
 	for i := curIdx; i < l; i++ {
 		idx := i % len(vols)
@@ -424,6 +442,7 @@ func (v *volumeMgr) modifySpace(ctx context.Context, volInfo *volume, modeInfo *
 	}
 	volInfo.Free -= args.Fsize
 	volInfo.Used += args.Fsize
+	volInfo.addInflight(time.Now())
 	span.Debugf("selectVid: %v, this vid allocated Size: %v, freeSize: %v, reserve size: %v",
 		volInfo.Vid, volInfo.Used, volInfo.Free, v.VolumeReserveSize)
 	deleteFlag := false
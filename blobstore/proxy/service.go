@@ -23,6 +23,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/api/clustermgr"
 	"github.com/cubefs/cubefs/blobstore/api/proxy"
 	"github.com/cubefs/cubefs/blobstore/cmd"
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
 	"github.com/cubefs/cubefs/blobstore/common/config"
 	"github.com/cubefs/cubefs/blobstore/common/kafka"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
@@ -104,10 +105,11 @@ type Service struct {
 
 func init() {
 	mod := &cmd.Module{
-		Name:       proto.ServiceNameProxy,
-		InitConfig: initConfig,
-		SetUp:      setUp,
-		TearDown:   tearDown,
+		Name:        proto.ServiceNameProxy,
+		InitConfig:  initConfig,
+		SetUp:       setUp,
+		TearDown:    tearDown,
+		HealthCheck: healthCheck,
 	}
 	cmd.RegisterGracefulModule(mod)
 }
@@ -126,6 +128,10 @@ func setUp() (*rpc.Router, []rpc.ProgressHandler) {
 	return NewHandler(service), nil
 }
 
+func healthCheck() error {
+	return service.HealthCheck()
+}
+
 func tearDown() {
 	service.volumeMgr.Close()
 }
@@ -177,6 +183,16 @@ func New(cfg Config, cmcli clustermgr.APIProxy) *Service {
 	}
 }
 
+// HealthCheck returns error if no volume is allocated for any code mode
+func (s *Service) HealthCheck() error {
+	for _, mode := range codemode.GetAllCodeModes() {
+		if vids, _, err := s.volumeMgr.List(context.Background(), mode); err == nil && len(vids) > 0 {
+			return nil
+		}
+	}
+	return errors.New("no allocated volume")
+}
+
 func NewHandler(service *Service) *rpc.Router {
 	router := rpc.New()
 	rpc.RegisterArgsParser(&proxy.ListVolsArgs{}, "json")
@@ -407,6 +407,28 @@ func TestService_CacherErase(t *testing.T) {
 	}
 }
 
+func TestService_HealthCheck(t *testing.T) {
+	ctr := gomock.NewController(t)
+	volumeMgr := mock.NewMockVolumeMgr(ctr)
+	service := &Service{volumeMgr: volumeMgr}
+
+	volumeMgr.EXPECT().List(A, A).AnyTimes().Return(nil, nil, nil)
+	require.Error(t, service.HealthCheck())
+
+	ctr.Finish()
+	ctr = gomock.NewController(t)
+	volumeMgr = mock.NewMockVolumeMgr(ctr)
+	service.volumeMgr = volumeMgr
+	volumeMgr.EXPECT().List(A, A).AnyTimes().DoAndReturn(
+		func(ctx context.Context, codeMode codemode.CodeMode) ([]proto.Vid, []clustermgr.AllocVolumeInfo, error) {
+			if codeMode != codemode.EC6P6 {
+				return nil, nil, errCodeMode
+			}
+			return []proto.Vid{1}, nil, nil
+		})
+	require.NoError(t, service.HealthCheck())
+}
+
 func TestConfigFix(t *testing.T) {
 	testCases := []struct {
 		cfg *Config
@@ -55,16 +55,18 @@ type BalanceMgr struct {
 
 	clusterTopology IClusterTopology
 	clusterMgrCli   client.ClusterMgrAPI
+	volumeHeat      IVolumeHeat
 
 	cfg *BalanceMgrConfig
 }
 
 // NewBalanceMgr returns balance manager
 func NewBalanceMgr(clusterMgrCli client.ClusterMgrAPI, volumeUpdater client.IVolumeUpdater, taskSwitch taskswitch.ISwitcher,
-	clusterTopology IClusterTopology, taskLogger recordlog.Encoder, conf *BalanceMgrConfig) *BalanceMgr {
+	clusterTopology IClusterTopology, volumeHeat IVolumeHeat, taskLogger recordlog.Encoder, conf *BalanceMgrConfig) *BalanceMgr {
 	mgr := &BalanceMgr{
 		clusterTopology: clusterTopology,
 		clusterMgrCli:   clusterMgrCli,
+		volumeHeat:      volumeHeat,
 		cfg:             conf,
 	}
 	mgr.IMigrator = NewMigrateMgr(clusterMgrCli, volumeUpdater, taskSwitch, taskLogger,
@@ -195,11 +197,25 @@ func (mgr *BalanceMgr) selectBalanceVunit(ctx context.Context, diskID proto.Disk
 		return
 	}
 
+	// prefer cold volumes, then less used volumes
+	heats := make(map[proto.Vid]float64, len(vunits))
+	for i := range vunits {
+		heats[vunits[i].Vuid.Vid()] = mgr.volumeHeat.Heat(vunits[i].Vuid.Vid())
+	}
 	sort.Slice(vunits, func(i, j int) bool {
+		hi, hj := heats[vunits[i].Vuid.Vid()], heats[vunits[j].Vuid.Vid()]
+		if hi != hj {
+			return hi < hj
+		}
 		return vunits[i].Used < vunits[j].Used
 	})
 
+	inPeakHours := mgr.volumeHeat.InPeakHours(time.Now())
 	for i := range vunits {
+		if inPeakHours && mgr.volumeHeat.IsHot(vunits[i].Vuid.Vid()) {
+			span.Debugf("skip hot volume in peak hours: vid[%d]", vunits[i].Vuid.Vid())
+			continue
+		}
 		volInfo, err := mgr.clusterMgrCli.GetVolumeInfo(ctx, vunits[i].Vuid.Vid())
 		if err != nil {
 			span.Errorf("get volume info failed: vid[%d], err[%+v]", vunits[i].Vuid.Vid(), err)
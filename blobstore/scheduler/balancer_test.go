@@ -53,7 +53,7 @@ func newBalancer(t *testing.T) *BalanceMgr {
 	migrater.EXPECT().WaitEnable().AnyTimes().Return()
 	migrater.EXPECT().Enabled().AnyTimes().Return(true)
 
-	mgr := NewBalanceMgr(clusterMgr, volumeUpdater, taskSwitch, topologyMgr, taskLogger, conf)
+	mgr := NewBalanceMgr(clusterMgr, volumeUpdater, taskSwitch, topologyMgr, NewVolumeHeatMgr(nil, &VolumeHeatConfig{}), taskLogger, conf)
 	mgr.IMigrator = migrater
 	return mgr
 }
@@ -152,6 +152,13 @@ func TestBalanceCollectionTask(t *testing.T) {
 		err = mgr.collectionTask()
 		require.NoError(t, err)
 
+		// hot volume is not balanced in peak hours
+		mgr.volumeHeat = newHotVolumeHeat(volume.Vid)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListDiskVolumeUnits(any, any).Return(units, nil)
+		err = mgr.collectionTask()
+		require.True(t, errors.Is(err, ErrNoBalanceVunit))
+		mgr.volumeHeat = newHotVolumeHeat()
+
 		// select one task and gen task failed
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListDiskVolumeUnits(any, any).Return(nil, errMock)
 		err = mgr.collectionTask()
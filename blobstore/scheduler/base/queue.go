@@ -18,6 +18,7 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -51,6 +52,12 @@ type Queue struct {
 	doing *list.List
 	msgs  map[string]*list.Element
 
+	// msgs of todo are indexed by priority if priority is set, the highest priority first
+	priority   func(msg interface{}) int
+	todos      map[int]*list.List
+	priorities []int // priorities of todos in descending order
+	seq        uint64
+
 	msgTimeout time.Duration // default duration of task locking
 }
 
@@ -63,6 +70,7 @@ func NewQueue(msgTimeout time.Duration) *Queue {
 		todo:       new(list.List),
 		doing:      new(list.List),
 		msgs:       make(map[string]*list.Element),
+		todos:      make(map[int]*list.List),
 		msgTimeout: msgTimeout,
 	}
 	return q
@@ -71,7 +79,10 @@ func NewQueue(msgTimeout time.Duration) *Queue {
 type msgEx struct {
 	id       string
 	state    int
+	leased   bool // popped and not requeued, the msg is popped again if the lease expired
 	deadline time.Time
+	priority int
+	seq      uint64 // order of push, keeps fifo of msgs of the same priority when reindexed
 	msg      interface{}
 }
 
@@ -84,46 +95,148 @@ func (q *Queue) Push(id string, msg interface{}) error {
 		return errExistingMessageID
 	}
 
+	q.seq++
 	m := &msgEx{
 		id:    id,
 		state: msgStateTodo,
+		seq:   q.seq,
 		msg:   msg,
 	}
-	elem := q.todo.PushBack(m)
-	q.msgs[id] = elem
+	if q.priority != nil {
+		m.priority = q.priority(msg)
+	}
+	q.msgs[id] = q.todoList(m.priority).PushBack(m)
 
 	return nil
 }
 
+// SetPriority sets priority of msgs, msgs of the highest priority are popped first,
+// and msgs of the same priority are popped as fifo. Set it again once the priorities
+// of msgs changed, nil means fifo for all msgs.
+func (q *Queue) SetPriority(priority func(msg interface{}) int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	todo := make([]*msgEx, 0, len(q.msgs))
+	for _, elem := range q.msgs {
+		m := elem.Value.(*msgEx)
+		m.priority = 0
+		if priority != nil {
+			m.priority = priority(m.msg)
+		}
+		if m.state == msgStateTodo {
+			todo = append(todo, m)
+		}
+	}
+	sort.Slice(todo, func(i, j int) bool { return todo[i].seq < todo[j].seq })
+
+	q.priority = priority
+	q.todo = new(list.List)
+	q.todos = make(map[int]*list.List)
+	q.priorities = q.priorities[:0]
+	for _, m := range todo {
+		q.msgs[m.id] = q.todoList(m.priority).PushBack(m)
+	}
+}
+
+// todoList returns the todo list of msgs of priority
+func (q *Queue) todoList(priority int) *list.List {
+	if q.priority == nil {
+		return q.todo
+	}
+	l, ok := q.todos[priority]
+	if !ok {
+		l = new(list.List)
+		q.todos[priority] = l
+		idx := sort.Search(len(q.priorities), func(i int) bool { return q.priorities[i] < priority })
+		q.priorities = append(q.priorities, 0)
+		copy(q.priorities[idx+1:], q.priorities[idx:])
+		q.priorities[idx] = priority
+	}
+	return l
+}
+
+// todoFront returns the first todo msg of the highest priority
+func (q *Queue) todoFront() *list.Element {
+	if q.priority == nil {
+		return q.todo.Front()
+	}
+	if len(q.priorities) == 0 {
+		return nil
+	}
+	return q.todos[q.priorities[0]].Front()
+}
+
+// removeTodo removes the todo msg, and the empty list of its priority
+func (q *Queue) removeTodo(elem *list.Element) {
+	m := elem.Value.(*msgEx)
+	l := q.todoList(m.priority)
+	l.Remove(elem)
+	if q.priority == nil || l.Len() > 0 {
+		return
+	}
+	delete(q.todos, m.priority)
+	idx := sort.Search(len(q.priorities), func(i int) bool { return q.priorities[i] <= m.priority })
+	q.priorities = append(q.priorities[:idx], q.priorities[idx+1:]...)
+}
+
+func (q *Queue) todoLen() int {
+	if q.priority == nil {
+		return q.todo.Len()
+	}
+	n := 0
+	for _, l := range q.todos {
+		n += l.Len()
+	}
+	return n
+}
+
 // Pop  fetch a msg from queue。
 func (q *Queue) Pop() (string, interface{}, bool) {
+	id, msg, _, exist := q.PopLease()
+	return id, msg, exist
+}
+
+// PopLease fetch a msg from queue as Pop, expired is true if the lease of the msg popped last time expired。
+// The timeout msg in doing is fetched before the todo msg of the same or lower priority.
+func (q *Queue) PopLease() (id string, msg interface{}, expired, exist bool) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	now := time.Now()
+	elem := q.todoFront()
+	var timeout *msgEx
 	for ele := q.doing.Front(); ele != nil; ele = ele.Next() {
 		m := ele.Value.(*msgEx)
-		if m.deadline.Before(now) {
-			m.deadline = now.Add(q.msgTimeout)
-			return m.id, m.msg, true
+		if m.deadline.Before(now) && (timeout == nil || m.priority > timeout.priority) {
+			timeout = m
+			if q.priority == nil {
+				break
+			}
 		}
 	}
+	if timeout != nil && (elem == nil || timeout.priority >= elem.Value.(*msgEx).priority) {
+		expired = timeout.leased
+		timeout.leased = true
+		timeout.deadline = now.Add(q.msgTimeout)
+		return timeout.id, timeout.msg, expired, true
+	}
 
 	// no timeout msg in doing ,fetch from todo
-	if q.todo.Len() == 0 {
-		return "", nil, false
+	if elem == nil {
+		return "", nil, false, false
 	}
-	elem := q.todo.Front()
-	q.todo.Remove(elem)
+	q.removeTodo(elem)
 
 	m := elem.Value.(*msgEx)
 	m.state = msgStateDoing
+	m.leased = true
 	m.deadline = now.Add(q.msgTimeout)
 
 	elem = q.doing.PushFront(m)
 	q.msgs[m.id] = elem
 
-	return m.id, m.msg, true
+	return m.id, m.msg, false, true
 }
 
 // Get returns message by id
@@ -158,10 +271,37 @@ func (q *Queue) Requeue(id string, delay time.Duration) error {
 	}
 
 	// msg in doing queue。
+	m.leased = false
 	m.deadline = time.Now().Add(delay)
 	return nil
 }
 
+// Renew extends the lease of popped msg by the default duration of task locking
+func (q *Queue) Renew(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	elem, ok := q.msgs[id]
+	if !ok {
+		return ErrNoSuchMessageID
+	}
+	m := elem.Value.(*msgEx)
+	if m.state == msgStateTodo {
+		return nil
+	}
+	m.deadline = time.Now().Add(q.msgTimeout)
+	return nil
+}
+
+// IsDoing returns true if message is popped and not removed
+func (q *Queue) IsDoing(id string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	elem, ok := q.msgs[id]
+	return ok && elem.Value.(*msgEx).state == msgStateDoing
+}
+
 // Remove remove message by id
 func (q *Queue) Remove(id string) error {
 	q.mu.Lock()
@@ -174,7 +314,7 @@ func (q *Queue) Remove(id string) error {
 	m := elem.Value.(*msgEx)
 	switch m.state {
 	case msgStateTodo:
-		q.todo.Remove(elem)
+		q.removeTodo(elem)
 	case msgStateDoing:
 		q.doing.Remove(elem)
 	default:
@@ -184,12 +324,22 @@ func (q *Queue) Remove(id string) error {
 	return nil
 }
 
+// SetMsgTimeout set default duration of task locking
+func (q *Queue) SetMsgTimeout(msgTimeout time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if msgTimeout == 0 {
+		msgTimeout = neverTimeout
+	}
+	q.msgTimeout = msgTimeout
+}
+
 // Stats returns queue stats
 func (q *Queue) Stats() (todo, doing int) {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
-	return q.todo.Len(), q.doing.Len()
+	return q.todoLen(), q.doing.Len()
 }
 
 // WorkerTask define worker task interface
@@ -235,6 +385,20 @@ func (q *TaskQueue) PopTask() (string, WorkerTask, bool) {
 	return "", nil, false
 }
 
+// SetTaskPriority sets priority of tasks, the task of the highest priority is popped first,
+// set it again once the priorities of tasks changed, nil means fifo for all tasks
+func (q *TaskQueue) SetTaskPriority(priority func(task WorkerTask) int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if priority == nil {
+		q.queue.SetPriority(nil)
+		return
+	}
+	q.queue.SetPriority(func(msg interface{}) int {
+		return priority(msg.(WorkerTask))
+	})
+}
+
 // RemoveTask remove task by taskID
 func (q *TaskQueue) RemoveTask(taskID string) error {
 	q.mu.Lock()
@@ -253,6 +417,13 @@ func (q *TaskQueue) RetryTask(taskID string) {
 	}
 }
 
+// SetRetryDelay set punish duration of failed task
+func (q *TaskQueue) SetRetryDelay(retryDelay time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retryDelay = retryDelay
+}
+
 // Query find task by taskID
 func (q *TaskQueue) Query(taskID string) (WorkerTask, bool) {
 	q.mu.Lock()
@@ -281,9 +452,11 @@ type WorkerTaskQueue struct {
 }
 
 // NewWorkerTaskQueue return worker task queue
-func NewWorkerTaskQueue(cancelPunishDuration time.Duration) *WorkerTaskQueue {
+func NewWorkerTaskQueue(cancelPunishDuration, leaseExpiredS time.Duration) *WorkerTaskQueue {
 	// extended lock duration of task leasing
-	leaseExpiredS := proto.TaskLeaseExpiredS * time.Second
+	if leaseExpiredS <= 0 {
+		leaseExpiredS = proto.TaskLeaseExpiredS * time.Second
+	}
 
 	return &WorkerTaskQueue{
 		idcQueues:            make(map[string]*Queue),
@@ -310,19 +483,26 @@ func (q *WorkerTaskQueue) AddPreparedTask(idc, taskID string, wtask WorkerTask)
 
 // Acquire acquire task by idc
 func (q *WorkerTaskQueue) Acquire(idc string) (taskID string, wtask WorkerTask, exist bool) {
+	taskID, wtask, _, exist = q.AcquireLease(idc)
+	return
+}
+
+// AcquireLease acquire task by idc, leaseExpired is true if the task is acquired again
+// because the worker acquired it last time did not renew in time
+func (q *WorkerTaskQueue) AcquireLease(idc string) (taskID string, wtask WorkerTask, leaseExpired, exist bool) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	idcQueue, ok := q.idcQueues[idc]
 	if !ok {
-		return "", nil, false
+		return "", nil, false, false
 	}
 
-	taskID, task, exist := idcQueue.Pop()
+	taskID, task, leaseExpired, exist := idcQueue.PopLease()
 	if exist {
-		return taskID, task.(WorkerTask), exist
+		return taskID, task.(WorkerTask), leaseExpired, exist
 	}
-	return "", nil, false
+	return "", nil, false, false
 }
 
 // Cancel cancel task
@@ -378,7 +558,7 @@ func (q *WorkerTaskQueue) Renewal(idc, taskID string) error {
 	if !ok {
 		return errNoSuchIDCQueue
 	}
-	return idcQueue.Requeue(taskID, q.leaseExpiredS)
+	return idcQueue.Renew(taskID)
 }
 
 // Complete complete task
@@ -439,9 +619,33 @@ func (q *WorkerTaskQueue) Query(idc, taskID string) (WorkerTask, error) {
 	return wt.(WorkerTask), nil
 }
 
+// Acquired returns true if task is acquired by worker
+func (q *WorkerTaskQueue) Acquired(idc, taskID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	idcQueue, ok := q.idcQueues[idc]
+	if !ok {
+		return false
+	}
+	return idcQueue.IsDoing(taskID)
+}
+
 // SetLeaseExpiredS set lease expired time
 func (q *WorkerTaskQueue) SetLeaseExpiredS(dura time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	q.leaseExpiredS = dura
+	for _, idcQueue := range q.idcQueues {
+		idcQueue.SetMsgTimeout(dura)
+	}
+}
+
+// SetCancelPunishDuration set punish duration of canceled task
+func (q *WorkerTaskQueue) SetCancelPunishDuration(dura time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cancelPunishDuration = dura
 }
 
 func checkValid(task WorkerTask, src []proto.VunitLocation, dst proto.VunitLocation) error {
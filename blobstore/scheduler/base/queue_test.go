@@ -15,6 +15,7 @@
 package base
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -180,6 +181,69 @@ func newTestWorkerTaskQueue(cancelPunishDuration, renewDuration time.Duration) *
 	}
 }
 
+func TestTaskQueueSetTaskPriority(t *testing.T) {
+	q := NewTaskQueue(100 * time.Millisecond)
+	priorities := map[proto.Vuid]int{1: 0, 2: 2, 3: 1, 4: 2, 5: 1}
+	priority := func(task WorkerTask) int {
+		return priorities[task.GetDestination().Vuid]
+	}
+	q.SetTaskPriority(priority)
+	for _, vuid := range []proto.Vuid{1, 2, 3, 4} {
+		q.PushTask(fmt.Sprint(vuid), &mockWorkerTask{dst: vunit(vuid)})
+	}
+
+	// the highest priority first and fifo for the same priority
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, _, exist := q.PopTask()
+		require.True(t, exist)
+		ids = append(ids, id)
+	}
+	require.Equal(t, []string{"2", "4", "3"}, ids)
+
+	// the retried task is fetched after the delay
+	q.RetryTask("2")
+	id, _, exist := q.PopTask()
+	require.True(t, exist)
+	require.Equal(t, "1", id)
+	_, _, exist = q.PopTask()
+	require.False(t, exist)
+	time.Sleep(100 * time.Millisecond)
+	// the retried task is fetched before the todo task of lower priority
+	q.PushTask("5", &mockWorkerTask{dst: vunit(5)})
+	id, _, exist = q.PopTask()
+	require.True(t, exist)
+	require.Equal(t, "2", id)
+	id, _, exist = q.PopTask()
+	require.True(t, exist)
+	require.Equal(t, "5", id)
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		require.NoError(t, q.RemoveTask(id))
+	}
+	todo, doing := q.StatsTasks()
+	require.Equal(t, 0, todo)
+	require.Equal(t, 0, doing)
+
+	// reindexed once the priorities changed, and fifo without priority
+	for _, vuid := range []proto.Vuid{1, 2, 3, 4, 5} {
+		q.PushTask(fmt.Sprint(vuid), &mockWorkerTask{dst: vunit(vuid)})
+	}
+	priorities[1] = 3
+	q.SetTaskPriority(priority)
+	id, _, _ = q.PopTask()
+	require.Equal(t, "1", id)
+	q.SetTaskPriority(nil)
+	ids = ids[:0]
+	for i := 0; i < 4; i++ {
+		id, _, _ = q.PopTask()
+		ids = append(ids, id)
+	}
+	require.Equal(t, []string{"2", "3", "4", "5"}, ids)
+	todo, doing = q.StatsTasks()
+	require.Equal(t, 0, todo)
+	require.Equal(t, 5, doing)
+}
+
 func TestWorkerTaskQueue(t *testing.T) {
 	taskID1 := "task_id1"
 	idc := "z0"
@@ -191,10 +255,13 @@ func TestWorkerTaskQueue(t *testing.T) {
 	// test AddPreparedTask
 	wq := newTestWorkerTaskQueue(cancelPunishDuration, renewDuration)
 	wq.AddPreparedTask(idc, taskID1, &task1)
+	require.False(t, wq.Acquired(idc, taskID1))
 
 	// test acquire
 	id, wt, exist := wq.Acquire(idc)
 	require.Equal(t, true, exist)
+	require.True(t, wq.Acquired(idc, taskID1))
+	require.False(t, wq.Acquired("z1", taskID1))
 	require.Equal(t, id, taskID1)
 	require.Equal(t, wt.GetSources(), task1.GetSources())
 	require.Equal(t, wt.GetDestination(), task1.GetDestination())
@@ -250,7 +317,7 @@ func TestWorkerTaskQueue(t *testing.T) {
 	// test ErrUnmatchedVuids
 	taskID2 := "task_id2"
 	task2 := mockWorkerTask{src: vunits([]proto.Vuid{1, 2, 3}), dst: vunit(4)}
-	wq = NewWorkerTaskQueue(cancelPunishDuration)
+	wq = NewWorkerTaskQueue(cancelPunishDuration, 0)
 	wq.AddPreparedTask(idc, taskID2, &task2)
 
 	err = wq.Cancel(idc, taskID2, vunits([]proto.Vuid{4, 5, 6}), vunit(4))
@@ -260,3 +327,66 @@ func TestWorkerTaskQueue(t *testing.T) {
 	_, err = wq.Complete(idc, taskID2, vunits([]proto.Vuid{4, 5, 6}), vunit(4))
 	require.EqualError(t, err, ErrUnmatchedVuids.Error())
 }
+
+func TestQueueSetParams(t *testing.T) {
+	idc := "z0"
+	task1 := mockWorkerTask{src: vunits([]proto.Vuid{1, 2, 3}), dst: vunit(4)}
+
+	q := NewTaskQueue(time.Hour)
+	q.PushTask("task_id1", &task1)
+	_, _, exist := q.PopTask()
+	require.True(t, exist)
+	q.SetRetryDelay(0)
+	q.RetryTask("task_id1")
+	_, _, exist = q.PopTask()
+	require.True(t, exist)
+
+	wq := newTestWorkerTaskQueue(time.Hour, time.Hour)
+	wq.AddPreparedTask(idc, "task_id1", &task1)
+	_, _, exist = wq.Acquire(idc)
+	require.True(t, exist)
+	wq.SetCancelPunishDuration(0)
+	require.NoError(t, wq.Cancel(idc, "task_id1", task1.GetSources(), task1.GetDestination()))
+
+	// new lease takes effect on acquired task
+	wq.SetLeaseExpiredS(50 * time.Millisecond)
+	_, _, exist = wq.Acquire(idc)
+	require.True(t, exist)
+	_, _, exist = wq.Acquire(idc)
+	require.False(t, exist)
+	time.Sleep(50 * time.Millisecond)
+	_, _, exist = wq.Acquire(idc)
+	require.True(t, exist)
+	require.NoError(t, wq.Renewal(idc, "task_id1"))
+	_, _, exist = wq.Acquire(idc)
+	require.False(t, exist)
+}
+
+func TestWorkerTaskQueueLeaseExpired(t *testing.T) {
+	idc := "z0"
+	task1 := mockWorkerTask{src: vunits([]proto.Vuid{1, 2, 3}), dst: vunit(4)}
+
+	wq := NewWorkerTaskQueue(0, 50*time.Millisecond)
+	wq.AddPreparedTask(idc, "task_id1", &task1)
+	_, _, expired, exist := wq.AcquireLease(idc)
+	require.True(t, exist)
+	require.False(t, expired)
+
+	// renewed task is not expired
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, wq.Renewal(idc, "task_id1"))
+	time.Sleep(30 * time.Millisecond)
+	_, _, _, exist = wq.AcquireLease(idc)
+	require.False(t, exist)
+
+	time.Sleep(30 * time.Millisecond)
+	_, _, expired, exist = wq.AcquireLease(idc)
+	require.True(t, exist)
+	require.True(t, expired)
+
+	// canceled task is acquired again without lease expired
+	require.NoError(t, wq.Cancel(idc, "task_id1", task1.GetSources(), task1.GetDestination()))
+	_, _, expired, exist = wq.AcquireLease(idc)
+	require.True(t, exist)
+	require.False(t, expired)
+}
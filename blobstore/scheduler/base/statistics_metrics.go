@@ -104,8 +104,9 @@ type TaskStatsMgr struct {
 
 	taskCntGauge *prometheus.GaugeVec
 
-	reclaimCounter prometheus.Counter
-	cancelCounter  prometheus.Counter
+	reclaimCounter      prometheus.Counter
+	cancelCounter       prometheus.Counter
+	leaseExpiredCounter prometheus.Counter
 
 	taskCntStats TaskCntStats
 }
@@ -169,6 +170,15 @@ func NewTaskStatsMgr(clusterID proto.ClusterID, taskType proto.TaskType) *TaskSt
 			ConstLabels: labels,
 		})
 
+	leaseExpiredCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "task",
+			Name:        "lease_expired",
+			Help:        "task lease expired",
+			ConstLabels: labels,
+		})
+
 	if err := prometheus.Register(dataSizeProCounter); err != nil {
 		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
 			dataSizeProCounter = are.ExistingCollector.(prometheus.Counter)
@@ -204,6 +214,13 @@ func NewTaskStatsMgr(clusterID proto.ClusterID, taskType proto.TaskType) *TaskSt
 			panic(err)
 		}
 	}
+	if err := prometheus.Register(leaseExpiredCounter); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			leaseExpiredCounter = are.ExistingCollector.(prometheus.Counter)
+		} else {
+			panic(err)
+		}
+	}
 
 	mgr := &TaskStatsMgr{
 		TaskRunInfos:       make(map[string]TaskRunDetailInfo),
@@ -212,6 +229,8 @@ func NewTaskStatsMgr(clusterID proto.ClusterID, taskType proto.TaskType) *TaskSt
 		taskCntGauge:       taskCntGauge,
 		reclaimCounter:     reclaimCounter,
 		cancelCounter:      cancelCounter,
+
+		leaseExpiredCounter: leaseExpiredCounter,
 	}
 
 	return mgr
@@ -231,6 +250,13 @@ func (statsMgr *TaskStatsMgr) ReportTaskCntLoop() {
 	}
 }
 
+// ReportSlowTaskCnt report count of tasks exceeding the time budget
+func (statsMgr *TaskStatsMgr) ReportSlowTaskCnt(cnt int) {
+	statsMgr.mu.Lock()
+	statsMgr.taskCntGauge.WithLabelValues("slow").Set(float64(cnt))
+	statsMgr.mu.Unlock()
+}
+
 // ReportWorkerTaskStats report worker task stats
 func (statsMgr *TaskStatsMgr) ReportWorkerTaskStats(taskID string, s proto.TaskStatistics, increaseDataSize, increaseShardCnt int) {
 	statsMgr.mu.Lock()
@@ -267,6 +293,11 @@ func (statsMgr *TaskStatsMgr) CancelTask() {
 	statsMgr.cancelCounter.Inc()
 }
 
+// LeaseExpired task is acquired again after its lease expired
+func (statsMgr *TaskStatsMgr) LeaseExpired() {
+	statsMgr.leaseExpiredCounter.Inc()
+}
+
 // QueryTaskDetail find task detail info
 func (statsMgr *TaskStatsMgr) QueryTaskDetail(taskID string) (detail TaskRunDetailInfo, err error) {
 	statsMgr.mu.Lock()
@@ -50,6 +50,7 @@ func TestTaskStatisticsMgr(t *testing.T) {
 
 	mgr.ReclaimTask()
 	mgr.CancelTask()
+	mgr.LeaseExpired()
 }
 
 func TestNewClusterTopoStatisticsMgr(t *testing.T) {
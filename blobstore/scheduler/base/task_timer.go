@@ -0,0 +1,110 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package base
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SlowTask task which runs longer than the time budget
+type SlowTask struct {
+	TaskID  string
+	IDC     string
+	Elapsed time.Duration
+}
+
+type taskTime struct {
+	idc        string
+	start      time.Time
+	checkpoint time.Time // time of prepared or the last reclaim
+}
+
+// TaskTimer records the elapsed wall time of tasks from prepared to finished,
+// a zero budget disables the slow task flagging
+type TaskTimer struct {
+	mu     sync.Mutex
+	budget time.Duration
+	tasks  map[string]*taskTime
+	now    func() time.Time
+}
+
+// NewTaskTimer returns task timer
+func NewTaskTimer(budget time.Duration) *TaskTimer {
+	return &TaskTimer{
+		budget: budget,
+		tasks:  make(map[string]*taskTime),
+		now:    time.Now,
+	}
+}
+
+// Start starts timing of task, the start time is kept if task is redone
+func (t *TaskTimer) Start(idc, taskID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.tasks[taskID]; ok {
+		return
+	}
+	now := t.now()
+	t.tasks[taskID] = &taskTime{idc: idc, start: now, checkpoint: now}
+}
+
+// Finish stops timing of task and returns the elapsed time
+func (t *TaskTimer) Finish(taskID string) (elapsed time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tt, ok := t.tasks[taskID]
+	if !ok {
+		return 0, false
+	}
+	delete(t.tasks, taskID)
+	return t.now().Sub(tt.start), true
+}
+
+// Renew restarts the budget of task after it is reclaimed
+func (t *TaskTimer) Renew(taskID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tt, ok := t.tasks[taskID]; ok {
+		tt.checkpoint = t.now()
+	}
+}
+
+// SlowTasks returns tasks whose elapsed time since prepared exceeds the budget
+func (t *TaskTimer) SlowTasks() []SlowTask {
+	return t.overBudget(func(tt *taskTime) time.Time { return tt.start })
+}
+
+// ExpiredTasks returns tasks exceeding the budget since prepared or the last reclaim
+func (t *TaskTimer) ExpiredTasks() []SlowTask {
+	return t.overBudget(func(tt *taskTime) time.Time { return tt.checkpoint })
+}
+
+func (t *TaskTimer) overBudget(since func(tt *taskTime) time.Time) (tasks []SlowTask) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.budget <= 0 {
+		return nil
+	}
+	now := t.now()
+	for taskID, tt := range t.tasks {
+		if now.Sub(since(tt)) > t.budget {
+			tasks = append(tasks, SlowTask{TaskID: taskID, IDC: tt.idc, Elapsed: now.Sub(tt.start)})
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].TaskID < tasks[j].TaskID })
+	return tasks
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTimer(t *testing.T) {
+	now := time.Now()
+	timer := NewTaskTimer(time.Minute)
+	timer.now = func() time.Time { return now }
+
+	timer.Start("z0", "task1")
+	timer.Start("z1", "task2")
+	require.Empty(t, timer.SlowTasks())
+
+	// redo task keeps the start time
+	now = now.Add(30 * time.Second)
+	timer.Start("z0", "task1")
+	timer.Start("z0", "task3")
+
+	now = now.Add(31 * time.Second)
+	slow := timer.SlowTasks()
+	require.Equal(t, []SlowTask{
+		{TaskID: "task1", IDC: "z0", Elapsed: 61 * time.Second},
+		{TaskID: "task2", IDC: "z1", Elapsed: 61 * time.Second},
+	}, slow)
+	require.Equal(t, slow, timer.ExpiredTasks())
+
+	// reclaimed task is still slow but not expired
+	timer.Renew("task1")
+	require.Len(t, timer.SlowTasks(), 2)
+	expired := timer.ExpiredTasks()
+	require.Len(t, expired, 1)
+	require.Equal(t, "task2", expired[0].TaskID)
+
+	elapsed, ok := timer.Finish("task2")
+	require.True(t, ok)
+	require.Equal(t, 61*time.Second, elapsed)
+	_, ok = timer.Finish("task2")
+	require.False(t, ok)
+	require.Len(t, timer.SlowTasks(), 1)
+
+	// zero budget disables flagging
+	timer = NewTaskTimer(0)
+	timer.Start("z0", "task1")
+	require.Empty(t, timer.SlowTasks())
+	require.Empty(t, timer.ExpiredTasks())
+}
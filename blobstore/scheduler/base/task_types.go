@@ -46,13 +46,16 @@ var (
 
 // TaskCommonConfig task common config
 type TaskCommonConfig struct {
-	PrepareQueueRetryDelayS int `json:"prepare_queue_retry_delay_s"`
-	FinishQueueRetryDelayS  int `json:"finish_queue_retry_delay_s"`
-	CancelPunishDurationS   int `json:"cancel_punish_duration_s"`
-	WorkQueueSize           int `json:"work_queue_size"`
-	CollectTaskIntervalS    int `json:"collect_task_interval_s"`
-	CheckTaskIntervalS      int `json:"check_task_interval_s"`
-	DiskConcurrency         int `json:"disk_concurrency"`
+	PrepareQueueRetryDelayS int  `json:"prepare_queue_retry_delay_s"`
+	FinishQueueRetryDelayS  int  `json:"finish_queue_retry_delay_s"`
+	CancelPunishDurationS   int  `json:"cancel_punish_duration_s"`
+	WorkQueueSize           int  `json:"work_queue_size"`
+	CollectTaskIntervalS    int  `json:"collect_task_interval_s"`
+	CheckTaskIntervalS      int  `json:"check_task_interval_s"`
+	DiskConcurrency         int  `json:"disk_concurrency"`
+	TaskTimeBudgetS         int  `json:"task_time_budget_s"` // 0 disables the slow task flagging
+	ReclaimSlowTask         bool `json:"reclaim_slow_task"`
+	TaskLeaseExpiredS       int  `json:"task_lease_expired_s"`
 }
 
 // CheckAndFix check and fix task common config
@@ -64,4 +67,9 @@ func (conf *TaskCommonConfig) CheckAndFix() {
 	defaulter.LessOrEqual(&conf.CollectTaskIntervalS, defaultCollectIntervalS)
 	defaulter.LessOrEqual(&conf.CheckTaskIntervalS, defaultCheckTaskIntervalS)
 	defaulter.LessOrEqual(&conf.DiskConcurrency, defaultDiskConcurrency)
+	defaulter.LessOrEqual(&conf.TaskLeaseExpiredS, proto.TaskLeaseExpiredS)
+	// the lease should not expire before worker renews it
+	if conf.TaskLeaseExpiredS <= proto.TaskRenewalPeriodS+proto.RenewalTimeoutS {
+		conf.TaskLeaseExpiredS = proto.TaskRenewalPeriodS + proto.RenewalTimeoutS + 1
+	}
 }
@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
 )
 
 func TestCommonCheckAndFix(t *testing.T) {
@@ -29,4 +31,13 @@ func TestCommonCheckAndFix(t *testing.T) {
 	require.Equal(t, defaultWorkQueueSize, cfg.WorkQueueSize)
 	require.Equal(t, defaultCollectIntervalS, cfg.CollectTaskIntervalS)
 	require.Equal(t, defaultCheckTaskIntervalS, cfg.CheckTaskIntervalS)
+	require.Equal(t, proto.TaskLeaseExpiredS, cfg.TaskLeaseExpiredS)
+
+	// lease shorter than renewal is fixed
+	cfg.TaskLeaseExpiredS = proto.TaskRenewalPeriodS
+	cfg.CheckAndFix()
+	require.Equal(t, proto.TaskRenewalPeriodS+proto.RenewalTimeoutS+1, cfg.TaskLeaseExpiredS)
+	cfg.TaskLeaseExpiredS = 60
+	cfg.CheckAndFix()
+	require.Equal(t, 60, cfg.TaskLeaseExpiredS)
 }
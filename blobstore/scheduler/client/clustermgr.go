@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -26,9 +27,11 @@ import (
 
 	"github.com/cubefs/cubefs/blobstore/api/blobnode"
 	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
 	"github.com/cubefs/cubefs/blobstore/common/codemode"
 	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 	"github.com/cubefs/cubefs/blobstore/util/log"
 )
@@ -43,6 +46,8 @@ type ClusterMgrVolumeAPI interface {
 	UnlockVolume(ctx context.Context, Vid proto.Vid) (err error)
 	UpdateVolume(ctx context.Context, newVuid, oldVuid proto.Vuid, newDiskID proto.DiskID) (err error)
 	AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (ret *AllocVunitInfo, err error)
+	AllocColdVolumeUnit(ctx context.Context, vuid proto.Vuid) (ret *AllocVunitInfo, err error)
+	AllocVolumeUnitWithArgs(ctx context.Context, args *cmapi.AllocVolumeUnitArgs) (ret *AllocVunitInfo, err error)
 	ReleaseVolumeUnit(ctx context.Context, vuid proto.Vuid, diskID proto.DiskID) (err error)
 	ListDiskVolumeUnits(ctx context.Context, diskID proto.DiskID) (ret []*VunitInfoSimple, err error)
 	ListVolume(ctx context.Context, marker proto.Vid, count int) (volInfo []*VolumeInfoSimple, retVid proto.Vid, err error)
@@ -53,10 +58,13 @@ type ClusterMgrDiskAPI interface {
 	ListBrokenDisks(ctx context.Context) (disks []*DiskInfoSimple, err error)
 	ListRepairingDisks(ctx context.Context) (disks []*DiskInfoSimple, err error)
 	ListDropDisks(ctx context.Context) (disks []*DiskInfoSimple, err error)
+	ListHostDisks(ctx context.Context, host string) (disks []*DiskInfoSimple, err error)
+	DropDisk(ctx context.Context, diskID proto.DiskID) (err error)
 	SetDiskRepairing(ctx context.Context, diskID proto.DiskID) (err error)
 	SetDiskRepaired(ctx context.Context, diskID proto.DiskID) (err error)
 	SetDiskDropped(ctx context.Context, diskID proto.DiskID) (err error)
 	GetDiskInfo(ctx context.Context, diskID proto.DiskID) (ret *DiskInfoSimple, err error)
+	WatchBrokenDisks(ctx context.Context, version uint64) (newVersion uint64, err error)
 }
 
 type ClusterMgrServiceAPI interface {
@@ -78,8 +86,15 @@ type ClusterMgrTaskAPI interface {
 	ListMigratingDisks(ctx context.Context, taskType proto.TaskType) (disks []*MigratingDiskMeta, err error)
 	GetVolumeInspectCheckPoint(ctx context.Context) (ck *proto.VolumeInspectCheckPoint, err error)
 	SetVolumeInspectCheckPoint(ctx context.Context, startVid proto.Vid) (err error)
+	SetVolumeInspectResult(ctx context.Context, ret *proto.VolumeInspectResult) (err error)
+	DeleteVolumeInspectResult(ctx context.Context, vid proto.Vid) (err error)
+	ListVolumeInspectResults(ctx context.Context) (rets []*proto.VolumeInspectResult, err error)
 	GetConsumeOffset(taskType proto.TaskType, topic string, partition int32) (offset int64, err error)
 	SetConsumeOffset(taskType proto.TaskType, topic string, partition int32, offset int64) (err error)
+	GetQueueParams(ctx context.Context, taskType proto.TaskType) (params *api.QueueParams, err error)
+	SetQueueParams(ctx context.Context, params *api.QueueParams) (err error)
+	GetHostDrains(ctx context.Context) (drains []*HostDrainMeta, err error)
+	SetHostDrains(ctx context.Context, drains []*HostDrainMeta) (err error)
 }
 
 // ClusterMgrAPI define the interface of clustermgr used by scheduler
@@ -123,12 +138,35 @@ type ClusterMgrAPI interface {
 //	for example:
 //		blob_delete-consume_offset-blob_delete-1
 //		shard_repair-consume_offset-shard_repair-2
+//
+// queue params key
+//  - - - - - - - - - - - - - - - -
+//  | {task_type} | _queueParams |
+//  - - - - - - - - - - - - - - - -
+//	for example:
+//		disk_repair-queue_params
+//
+// volume inspect result key, see proto.VolumeInspectResultKey
+//  - - - - - - - - - - - - - - - - - -
+//  | {task_type} | result | {vid} |
+//  - - - - - - - - - - - - - - - - - -
+//	for example:
+//		volume_inspect-result-1
+//
+// host drains key, all draining hosts are saved in one value because host can not be a part of key
+//  - - - - - - - - - - - - - - -
+//  | {task_type} | _drainHosts |
+//  - - - - - - - - - - - - - - -
+//	for example:
+//		disk_drop-drain_hosts
 
 const (
 	_delimiter           = "-"
 	_migratingDiskPrefix = "migrating"
 	_checkPoint          = "checkpoint"
 	_consumeOffset       = "consume_offset"
+	_queueParams         = "queue_params"
+	_drainHosts          = "drain_hosts"
 )
 
 var (
@@ -136,6 +174,8 @@ var (
 	defaultListDiskMarker = proto.DiskID(0)
 	defaultListTaskNum    = 1000
 	defaultListTaskMarker = ""
+
+	defaultWatchBrokenDiskTimeoutMs = int64(30000)
 )
 
 type MigratingDiskMeta struct {
@@ -187,11 +227,29 @@ func genConsumerOffsetKey(taskType proto.TaskType, topic string, partition int32
 	return fmt.Sprintf("%s%s%s%s%s%s%d", taskType, _delimiter, _consumeOffset, _delimiter, topic, _delimiter, partition)
 }
 
+func genQueueParamsKey(taskType proto.TaskType) string {
+	return taskType.String() + _delimiter + _queueParams
+}
+
+func genHostDrainsKey() string {
+	return proto.TaskTypeDiskDrop.String() + _delimiter + _drainHosts
+}
+
+// HostDrainMeta draining host, the disks of host are dropped at most concurrency at the same time
+type HostDrainMeta struct {
+	Host        string         `json:"host"`
+	Concurrency int            `json:"concurrency"`
+	Disks       []proto.DiskID `json:"disks"`
+	Ctime       string         `json:"ctime"`
+}
+
 // VolumeInfoSimple volume info used by scheduler
 type VolumeInfoSimple struct {
 	Vid            proto.Vid             `json:"vid"`
 	CodeMode       codemode.CodeMode     `json:"code_mode"`
 	Status         proto.VolumeStatus    `json:"status"`
+	Total          uint64                `json:"total"`
+	Free           uint64                `json:"free"`
 	VunitLocations []proto.VunitLocation `json:"vunit_locations"`
 }
 
@@ -227,6 +285,8 @@ func (vol *VolumeInfoSimple) set(info *cmapi.VolumeInfo) {
 	vol.Vid = info.Vid
 	vol.CodeMode = info.CodeMode
 	vol.Status = info.Status
+	vol.Total = info.Total
+	vol.Free = info.Free
 	vol.VunitLocations = make([]proto.VunitLocation, len(info.Units))
 
 	// check volume info
@@ -256,6 +316,7 @@ func (vol *VolumeInfoSimple) set(info *cmapi.VolumeInfo) {
 // AllocVunitInfo volume unit info for alloc
 type AllocVunitInfo struct {
 	proto.VunitLocation
+	Idc string `json:"idc"`
 }
 
 // Location returns volume unit location
@@ -291,8 +352,10 @@ type DiskInfoSimple struct {
 	Idc          string           `json:"idc"`
 	Rack         string           `json:"rack"`
 	Host         string           `json:"host"`
+	DiskSetID    proto.DiskSetID  `json:"disk_set_id"`
 	Status       proto.DiskStatus `json:"status"`
 	Readonly     bool             `json:"readonly"`
+	Cold         bool             `json:"cold"`
 	UsedChunkCnt int64            `json:"used_chunk_cnt"`
 	MaxChunkCnt  int64            `json:"max_chunk_cnt"`
 	FreeChunkCnt int64            `json:"free_chunk_cnt"`
@@ -335,9 +398,11 @@ func (disk *DiskInfoSimple) set(info *blobnode.DiskInfo) {
 	disk.Idc = info.Idc
 	disk.Rack = info.Rack
 	disk.Host = info.Host
+	disk.DiskSetID = info.DiskSetID
 	disk.DiskID = info.DiskID
 	disk.Status = info.Status
 	disk.Readonly = info.Readonly
+	disk.Cold = info.Cold
 	disk.UsedChunkCnt = info.UsedChunkCnt
 	disk.MaxChunkCnt = info.MaxChunkCnt
 	disk.FreeChunkCnt = info.FreeChunkCnt
@@ -368,6 +433,8 @@ type IClusterManager interface {
 	ListDisk(ctx context.Context, args *cmapi.ListOptionArgs) (ret cmapi.ListDiskRet, err error)
 	ListDroppingDisk(ctx context.Context) (ret []*blobnode.DiskInfo, err error)
 	SetDisk(ctx context.Context, id proto.DiskID, status proto.DiskStatus) (err error)
+	SetReadonlyDisk(ctx context.Context, id proto.DiskID, readonly bool) (err error)
+	DropDisk(ctx context.Context, id proto.DiskID) (err error)
 	DiskInfo(ctx context.Context, id proto.DiskID) (ret *blobnode.DiskInfo, err error)
 	DroppedDisk(ctx context.Context, id proto.DiskID) (err error)
 	RegisterService(ctx context.Context, node cmapi.ServiceNode, tickInterval, heartbeatTicks, expiresTicks uint32) (err error)
@@ -376,21 +443,33 @@ type IClusterManager interface {
 	DeleteKV(ctx context.Context, key string) (err error)
 	SetKV(ctx context.Context, key string, value []byte) (err error)
 	ListKV(ctx context.Context, args *cmapi.ListKvOpts) (ret cmapi.ListKvRet, err error)
+	WatchBrokenDisk(ctx context.Context, args *cmapi.WatchBrokenDiskArgs) (ret *cmapi.WatchBrokenDiskRet, err error)
 }
 
 // clustermgrClient clustermgr client
 type clustermgrClient struct {
 	client IClusterManager
 	rwLock sync.RWMutex
+	// long poll time of watching broken disks
+	watchTimeoutMs int64
 }
 
 func NewClusterMgrClient(conf *cmapi.Config) ClusterMgrAPI {
 	return &clustermgrClient{
-		client: cmapi.New(conf),
-		rwLock: sync.RWMutex{},
+		client:         cmapi.New(conf),
+		rwLock:         sync.RWMutex{},
+		watchTimeoutMs: watchBrokenDiskTimeoutMs(conf),
 	}
 }
 
+// watchBrokenDiskTimeoutMs keeps the long poll shorter than the client timeout
+func watchBrokenDiskTimeoutMs(conf *cmapi.Config) int64 {
+	if timeout := conf.ClientTimeoutMs / 2; timeout > 0 && timeout < defaultWatchBrokenDiskTimeoutMs {
+		return timeout
+	}
+	return defaultWatchBrokenDiskTimeoutMs
+}
+
 // GetConfig returns config by config key
 func (c *clustermgrClient) GetConfig(ctx context.Context, key string) (val string, err error) {
 	c.rwLock.RLock()
@@ -463,14 +542,28 @@ func (c *clustermgrClient) UpdateVolume(ctx context.Context, newVuid, oldVuid pr
 
 // AllocVolumeUnit alloc volume unit
 func (c *clustermgrClient) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*AllocVunitInfo, error) {
+	return c.allocVolumeUnit(ctx, &cmapi.AllocVolumeUnitArgs{Vuid: vuid})
+}
+
+// AllocColdVolumeUnit alloc volume unit on cold disk
+func (c *clustermgrClient) AllocColdVolumeUnit(ctx context.Context, vuid proto.Vuid) (*AllocVunitInfo, error) {
+	return c.allocVolumeUnit(ctx, &cmapi.AllocVolumeUnitArgs{Vuid: vuid, Cold: true})
+}
+
+// AllocVolumeUnitWithArgs alloc volume unit with all args, such as the disks should be avoided
+func (c *clustermgrClient) AllocVolumeUnitWithArgs(ctx context.Context, args *cmapi.AllocVolumeUnitArgs) (*AllocVunitInfo, error) {
+	return c.allocVolumeUnit(ctx, args)
+}
+
+func (c *clustermgrClient) allocVolumeUnit(ctx context.Context, args *cmapi.AllocVolumeUnitArgs) (*AllocVunitInfo, error) {
 	c.rwLock.Lock()
 	defer c.rwLock.Unlock()
 
 	span := trace.SpanFromContextSafe(ctx)
 
-	span.Debugf("alloc volume unit: args vuid[%d]", vuid)
+	span.Debugf("alloc volume unit: args[%+v]", args)
 	ret := &AllocVunitInfo{}
-	info, err := c.client.AllocVolumeUnit(ctx, &cmapi.AllocVolumeUnitArgs{Vuid: vuid})
+	info, err := c.client.AllocVolumeUnit(ctx, args)
 	if err != nil {
 		span.Errorf("alloc volume unit failed: err[%+v]", err)
 		return nil, err
@@ -483,6 +576,7 @@ func (c *clustermgrClient) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid)
 	}
 
 	ret.set(info, diskInfo.Host)
+	ret.Idc = diskInfo.Idc
 	return ret, err
 }
 
@@ -565,6 +659,17 @@ func (c *clustermgrClient) ListRepairingDisks(ctx context.Context) (disks []*Dis
 	return c.listAllDisks(ctx, proto.DiskStatusRepairing)
 }
 
+// WatchBrokenDisks blocks until any disk becomes broken after the version or the long poll times out,
+// it returns the latest broken disk version of clustermgr.
+// Do not hold the rwLock during the long poll, or the writers would be blocked.
+func (c *clustermgrClient) WatchBrokenDisks(ctx context.Context, version uint64) (newVersion uint64, err error) {
+	ret, err := c.client.WatchBrokenDisk(ctx, &cmapi.WatchBrokenDiskArgs{Version: version, TimeoutMs: c.watchTimeoutMs})
+	if err != nil {
+		return version, err
+	}
+	return ret.Version, nil
+}
+
 func (c *clustermgrClient) listAllDisks(ctx context.Context, status proto.DiskStatus) (disks []*DiskInfoSimple, err error) {
 	span := trace.SpanFromContextSafe(ctx)
 	marker := defaultListDiskMarker
@@ -659,6 +764,58 @@ func (c *clustermgrClient) ListDropDisks(ctx context.Context) (disks []*DiskInfo
 	return disks, nil
 }
 
+// ListHostDisks list all disks of host
+func (c *clustermgrClient) ListHostDisks(ctx context.Context, host string) (disks []*DiskInfoSimple, err error) {
+	c.rwLock.RLock()
+	defer c.rwLock.RUnlock()
+
+	span := trace.SpanFromContextSafe(ctx)
+	marker := defaultListDiskMarker
+	for {
+		args := &cmapi.ListOptionArgs{
+			Host:   host,
+			Count:  defaultListDiskNum,
+			Marker: marker,
+		}
+		selectDisks, selectMarker, err := c.listDisk(ctx, args)
+		if err != nil {
+			span.Errorf("list host disks failed: host[%s], err[%+v]", host, err)
+			return nil, err
+		}
+
+		marker = selectMarker
+		disks = append(disks, selectDisks...)
+		if marker == defaultListDiskMarker {
+			break
+		}
+	}
+	return
+}
+
+// DropDisk adds the disk into dropping list of clustermgr, the disk is set readonly before dropping
+func (c *clustermgrClient) DropDisk(ctx context.Context, diskID proto.DiskID) (err error) {
+	c.rwLock.Lock()
+	defer c.rwLock.Unlock()
+	span := trace.SpanFromContextSafe(ctx)
+
+	info, err := c.client.DiskInfo(ctx, diskID)
+	if err != nil {
+		span.Errorf("drop disk, get disk info failed: disk_id[%d], err[%+v]", diskID, err)
+		return err
+	}
+	if !info.Readonly {
+		if err = c.client.SetReadonlyDisk(ctx, diskID, true); err != nil {
+			span.Errorf("drop disk, set disk readonly failed: disk_id[%d], err[%+v]", diskID, err)
+			return err
+		}
+	}
+
+	span.Debugf("drop disk: args disk_id[%d]", diskID)
+	err = c.client.DropDisk(ctx, diskID)
+	span.Debugf("drop disk ret: err[%+v]", err)
+	return
+}
+
 // SetDiskRepairing set disk repairing
 func (c *clustermgrClient) SetDiskRepairing(ctx context.Context, diskID proto.DiskID) (err error) {
 	c.rwLock.Lock()
@@ -953,6 +1110,48 @@ func (c *clustermgrClient) SetVolumeInspectCheckPoint(ctx context.Context, start
 	return c.client.SetKV(ctx, genVolumeInspectCheckpointKey(), checkPointBytes)
 }
 
+// SetVolumeInspectResult saves the last inspect result of volume which missed shards
+func (c *clustermgrClient) SetVolumeInspectResult(ctx context.Context, ret *proto.VolumeInspectResult) (err error) {
+	retBytes, err := json.Marshal(ret)
+	if err != nil {
+		return err
+	}
+	return c.client.SetKV(ctx, proto.VolumeInspectResultKey(ret.Vid), retBytes)
+}
+
+// DeleteVolumeInspectResult deletes the inspect result of volume which missed no shards now
+func (c *clustermgrClient) DeleteVolumeInspectResult(ctx context.Context, vid proto.Vid) (err error) {
+	return c.client.DeleteKV(ctx, proto.VolumeInspectResultKey(vid))
+}
+
+// ListVolumeInspectResults returns all saved inspect results of volumes
+func (c *clustermgrClient) ListVolumeInspectResults(ctx context.Context) (rets []*proto.VolumeInspectResult, err error) {
+	marker := defaultListTaskMarker
+	for {
+		args := &cmapi.ListKvOpts{
+			Prefix: proto.VolumeInspectResultKeyPrefix,
+			Count:  defaultListTaskNum,
+			Marker: marker,
+		}
+		ret, err := c.client.ListKV(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range ret.Kvs {
+			var inspectRet *proto.VolumeInspectResult
+			if err = json.Unmarshal(v.Value, &inspectRet); err != nil {
+				return nil, err
+			}
+			rets = append(rets, inspectRet)
+		}
+		marker = ret.Marker
+		if marker == defaultListTaskMarker {
+			break
+		}
+	}
+	return
+}
+
 func (c *clustermgrClient) GetConsumeOffset(taskType proto.TaskType, topic string, partition int32) (offset int64, err error) {
 	ret, err := c.client.GetKV(context.Background(), genConsumerOffsetKey(taskType, topic, partition))
 	if err != nil {
@@ -975,3 +1174,44 @@ func (c *clustermgrClient) SetConsumeOffset(taskType proto.TaskType, topic strin
 	}
 	return c.client.SetKV(context.Background(), genConsumerOffsetKey(taskType, topic, partition), consumeOffsetBytes)
 }
+
+// GetQueueParams returns persisted queue params of task type
+func (c *clustermgrClient) GetQueueParams(ctx context.Context, taskType proto.TaskType) (params *api.QueueParams, err error) {
+	ret, err := c.client.GetKV(ctx, genQueueParamsKey(taskType))
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(ret.Value, &params)
+	return
+}
+
+// SetQueueParams persists queue params of task type
+func (c *clustermgrClient) SetQueueParams(ctx context.Context, params *api.QueueParams) (err error) {
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.client.SetKV(ctx, genQueueParamsKey(params.TaskType), paramsBytes)
+}
+
+// GetHostDrains returns all persisted draining hosts
+func (c *clustermgrClient) GetHostDrains(ctx context.Context) (drains []*HostDrainMeta, err error) {
+	ret, err := c.client.GetKV(ctx, genHostDrainsKey())
+	if err != nil {
+		if rpc.DetectStatusCode(err) == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(ret.Value, &drains)
+	return
+}
+
+// SetHostDrains persists all draining hosts
+func (c *clustermgrClient) SetHostDrains(ctx context.Context, drains []*HostDrainMeta) (err error) {
+	drainsBytes, err := json.Marshal(drains)
+	if err != nil {
+		return err
+	}
+	return c.client.SetKV(ctx, genHostDrainsKey(), drainsBytes)
+}
@@ -0,0 +1,253 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+)
+
+const (
+	minCacheSweepSize = 1024
+	cacheLoadTimeout  = 10 * time.Second
+)
+
+// CacheConfig ttl of volume and disk info cached in scheduler, 0 means the default ttl
+// and negative ttl disables the cache, the concurrent lookups of the same key are
+// coalesced into one request even if the cache is disabled
+type CacheConfig struct {
+	VolumeTTLMs int64 `json:"volume_ttl_ms"`
+	DiskTTLMs   int64 `json:"disk_ttl_ms"`
+}
+
+type cacheEntry struct {
+	val      interface{}
+	expireAt time.Time
+	version  uint64 // increased by invalidation
+	loading  int
+}
+
+// lookupCache caches the result of lookups for a short ttl,
+// the result loaded before invalidation is dropped so that the caller never sees the stale one
+type lookupCache struct {
+	ttl   time.Duration
+	group singleflight.Group
+	now   func() time.Time
+
+	mu        sync.Mutex
+	entries   map[uint64]*cacheEntry
+	sweepSize int
+}
+
+func newLookupCache(ttl time.Duration) *lookupCache {
+	return &lookupCache{
+		ttl:       ttl,
+		now:       time.Now,
+		entries:   make(map[uint64]*cacheEntry),
+		sweepSize: minCacheSweepSize,
+	}
+}
+
+// get returns the cached value of key or loads it, the coalesced load runs with a context
+// detached from the callers so that one canceled caller does not fail the others
+func (c *lookupCache) get(ctx context.Context, key uint64, load func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && entry.val != nil && c.now().Before(entry.expireAt) {
+		c.mu.Unlock()
+		return entry.val, nil
+	}
+	c.mu.Unlock()
+
+	span := trace.SpanFromContextSafe(ctx)
+	ch := c.group.DoChan(strconv.FormatUint(key, 10), func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(trace.ContextWithSpan(context.Background(), span), cacheLoadTimeout)
+		defer cancel()
+		entry, version := c.startLoad(key)
+		val, err := load(loadCtx)
+		c.finishLoad(entry, version, val, err)
+		return val, err
+	})
+	select {
+	case ret := <-ch:
+		return ret.Val, ret.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *lookupCache) startLoad(key uint64) (*cacheEntry, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		c.sweep()
+		entry = &cacheEntry{}
+		c.entries[key] = entry
+	}
+	entry.loading++
+	return entry, entry.version
+}
+
+func (c *lookupCache) finishLoad(entry *cacheEntry, version uint64, val interface{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.loading--
+	if err != nil || c.ttl <= 0 || entry.version != version {
+		return
+	}
+	entry.val = val
+	entry.expireAt = c.now().Add(c.ttl)
+}
+
+// sweep removes the expired entries if the cache grows to twice the size after last sweep
+func (c *lookupCache) sweep() {
+	if len(c.entries) < c.sweepSize {
+		return
+	}
+	now := c.now()
+	for key, entry := range c.entries {
+		if entry.loading == 0 && !now.Before(entry.expireAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.sweepSize = 2 * len(c.entries)
+	if c.sweepSize < minCacheSweepSize {
+		c.sweepSize = minCacheSweepSize
+	}
+}
+
+func (c *lookupCache) invalidate(key uint64) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		entry.val = nil
+		entry.version++
+	}
+	c.mu.Unlock()
+	// the lookups after invalidation do not share the loading one
+	c.group.Forget(strconv.FormatUint(key, 10))
+}
+
+// cachedClusterMgrClient caches volume and disk info and invalidates them once modified by scheduler,
+// prepare loops look up the same volumes and disks repeatedly during mass failures
+type cachedClusterMgrClient struct {
+	ClusterMgrAPI
+	volumes *lookupCache
+	disks   *lookupCache
+}
+
+// NewCachedClusterMgrClient returns clustermgr client which caches and coalesces volume and disk info lookups
+func NewCachedClusterMgrClient(cli ClusterMgrAPI, conf *CacheConfig) ClusterMgrAPI {
+	return &cachedClusterMgrClient{
+		ClusterMgrAPI: cli,
+		volumes:       newLookupCache(time.Duration(conf.VolumeTTLMs) * time.Millisecond),
+		disks:         newLookupCache(time.Duration(conf.DiskTTLMs) * time.Millisecond),
+	}
+}
+
+// GetVolumeInfo returns a copy of the cached volume info
+func (c *cachedClusterMgrClient) GetVolumeInfo(ctx context.Context, vid proto.Vid) (*VolumeInfoSimple, error) {
+	val, err := c.volumes.get(ctx, uint64(vid), func(ctx context.Context) (interface{}, error) {
+		return c.ClusterMgrAPI.GetVolumeInfo(ctx, vid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cached, _ := val.(*VolumeInfoSimple)
+	if cached == nil {
+		return nil, nil
+	}
+	vol := *cached
+	vol.VunitLocations = append([]proto.VunitLocation(nil), cached.VunitLocations...)
+	return &vol, nil
+}
+
+func (c *cachedClusterMgrClient) LockVolume(ctx context.Context, vid proto.Vid) error {
+	defer c.volumes.invalidate(uint64(vid))
+	return c.ClusterMgrAPI.LockVolume(ctx, vid)
+}
+
+func (c *cachedClusterMgrClient) UnlockVolume(ctx context.Context, vid proto.Vid) error {
+	defer c.volumes.invalidate(uint64(vid))
+	return c.ClusterMgrAPI.UnlockVolume(ctx, vid)
+}
+
+func (c *cachedClusterMgrClient) UpdateVolume(ctx context.Context, newVuid, oldVuid proto.Vuid, newDiskID proto.DiskID) error {
+	defer c.volumes.invalidate(uint64(oldVuid.Vid()))
+	return c.ClusterMgrAPI.UpdateVolume(ctx, newVuid, oldVuid, newDiskID)
+}
+
+func (c *cachedClusterMgrClient) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*AllocVunitInfo, error) {
+	defer c.volumes.invalidate(uint64(vuid.Vid()))
+	return c.ClusterMgrAPI.AllocVolumeUnit(ctx, vuid)
+}
+
+func (c *cachedClusterMgrClient) AllocColdVolumeUnit(ctx context.Context, vuid proto.Vuid) (*AllocVunitInfo, error) {
+	defer c.volumes.invalidate(uint64(vuid.Vid()))
+	return c.ClusterMgrAPI.AllocColdVolumeUnit(ctx, vuid)
+}
+
+func (c *cachedClusterMgrClient) AllocVolumeUnitWithArgs(ctx context.Context, args *cmapi.AllocVolumeUnitArgs) (*AllocVunitInfo, error) {
+	defer c.volumes.invalidate(uint64(args.Vuid.Vid()))
+	return c.ClusterMgrAPI.AllocVolumeUnitWithArgs(ctx, args)
+}
+
+func (c *cachedClusterMgrClient) ReleaseVolumeUnit(ctx context.Context, vuid proto.Vuid, diskID proto.DiskID) error {
+	defer c.volumes.invalidate(uint64(vuid.Vid()))
+	return c.ClusterMgrAPI.ReleaseVolumeUnit(ctx, vuid, diskID)
+}
+
+// GetDiskInfo returns a copy of the cached disk info
+func (c *cachedClusterMgrClient) GetDiskInfo(ctx context.Context, diskID proto.DiskID) (*DiskInfoSimple, error) {
+	val, err := c.disks.get(ctx, uint64(diskID), func(ctx context.Context) (interface{}, error) {
+		return c.ClusterMgrAPI.GetDiskInfo(ctx, diskID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cached, _ := val.(*DiskInfoSimple)
+	if cached == nil {
+		return nil, nil
+	}
+	disk := *cached
+	return &disk, nil
+}
+
+func (c *cachedClusterMgrClient) SetDiskRepairing(ctx context.Context, diskID proto.DiskID) error {
+	defer c.disks.invalidate(uint64(diskID))
+	return c.ClusterMgrAPI.SetDiskRepairing(ctx, diskID)
+}
+
+func (c *cachedClusterMgrClient) SetDiskRepaired(ctx context.Context, diskID proto.DiskID) error {
+	defer c.disks.invalidate(uint64(diskID))
+	return c.ClusterMgrAPI.SetDiskRepaired(ctx, diskID)
+}
+
+func (c *cachedClusterMgrClient) SetDiskDropped(ctx context.Context, diskID proto.DiskID) error {
+	defer c.disks.invalidate(uint64(diskID))
+	return c.ClusterMgrAPI.SetDiskDropped(ctx, diskID)
+}
+
+func (c *cachedClusterMgrClient) DropDisk(ctx context.Context, diskID proto.DiskID) error {
+	defer c.disks.invalidate(uint64(diskID))
+	return c.ClusterMgrAPI.DropDisk(ctx, diskID)
+}
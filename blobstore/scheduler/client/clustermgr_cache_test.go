@@ -0,0 +1,232 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/api/blobnode"
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+)
+
+func TestLookupCache(t *testing.T) {
+	ctx := context.Background()
+	errMock := errors.New("fake error")
+	now := time.Now()
+	cache := newLookupCache(time.Second)
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func(context.Context) (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+	val, err := cache.get(ctx, 1, load)
+	require.NoError(t, err)
+	require.Equal(t, 1, val)
+	val, _ = cache.get(ctx, 1, load)
+	require.Equal(t, 1, val)
+
+	// expired
+	now = now.Add(time.Second)
+	val, _ = cache.get(ctx, 1, load)
+	require.Equal(t, 2, val)
+
+	// invalidated
+	cache.invalidate(1)
+	val, _ = cache.get(ctx, 1, load)
+	require.Equal(t, 3, val)
+
+	// failed load is not cached
+	_, err = cache.get(ctx, 2, func(context.Context) (interface{}, error) { return nil, errMock })
+	require.True(t, errors.Is(err, errMock))
+	val, _ = cache.get(ctx, 2, load)
+	require.Equal(t, 4, val)
+
+	// result loaded before invalidation is dropped
+	val, _ = cache.get(ctx, 3, func(context.Context) (interface{}, error) {
+		cache.invalidate(3)
+		return "stale", nil
+	})
+	require.Equal(t, "stale", val)
+	val, _ = cache.get(ctx, 3, load)
+	require.Equal(t, 5, val)
+
+	// not cached if ttl is negative
+	cache = newLookupCache(-time.Second)
+	loads = 0
+	cache.get(ctx, 1, load)
+	val, _ = cache.get(ctx, 1, load)
+	require.Equal(t, 2, val)
+}
+
+func TestLookupCacheSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cache := newLookupCache(time.Second)
+	cache.now = func() time.Time { return now }
+	load := func(context.Context) (interface{}, error) { return 1, nil }
+
+	for key := uint64(0); key < minCacheSweepSize; key++ {
+		cache.get(ctx, key, load)
+	}
+	require.Len(t, cache.entries, minCacheSweepSize)
+
+	now = now.Add(time.Second)
+	cache.get(ctx, minCacheSweepSize, load)
+	require.Len(t, cache.entries, 1)
+}
+
+func TestLookupCacheCoalesce(t *testing.T) {
+	ctx := context.Background()
+	cache := newLookupCache(time.Minute)
+	var (
+		mu    sync.Mutex
+		loads int
+		wg    sync.WaitGroup
+	)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(context.Context) (interface{}, error) {
+		mu.Lock()
+		loads++
+		mu.Unlock()
+		close(started)
+		<-release
+		return 1, nil
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cache.get(ctx, 1, load)
+	}()
+	<-started
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cache.get(ctx, 1, func(context.Context) (interface{}, error) { return 2, nil })
+			require.NoError(t, err)
+			require.Equal(t, 1, val)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, 1, loads)
+}
+
+func TestLookupCacheCanceled(t *testing.T) {
+	cache := newLookupCache(time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		return 1, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := cache.get(ctx, 1, load)
+		require.True(t, errors.Is(err, context.Canceled))
+	}()
+	<-started
+
+	// the waiter is not failed by the canceled caller who started the load
+	waited := make(chan struct{})
+	go func() {
+		defer close(waited)
+		val, err := cache.get(context.Background(), 1, load)
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+	close(release)
+	<-waited
+}
+
+func TestCachedClusterMgrClient(t *testing.T) {
+	ctx := context.Background()
+	any := gomock.Any()
+	errMock := errors.New("fake error")
+	cmCli := NewClusterMgrClient(&cmapi.Config{}).(*clustermgrClient)
+	mockCli := NewMockClusterManager(gomock.NewController(t))
+	cmCli.client = mockCli
+	cli := NewCachedClusterMgrClient(cmCli, &CacheConfig{VolumeTTLMs: 60000, DiskTTLMs: 60000})
+
+	// volume
+	{
+		volume := MockGenVolInfo(10, codemode.EC6P6, proto.VolumeStatusIdle)
+		mockCli.EXPECT().GetVolumeInfo(any, any).Return(volume, nil)
+		vol, err := cli.GetVolumeInfo(ctx, volume.Vid)
+		require.NoError(t, err)
+		vol.VunitLocations[0].DiskID = 0
+		vol2, err := cli.GetVolumeInfo(ctx, volume.Vid)
+		require.NoError(t, err)
+		require.Equal(t, volume.Units[0].DiskID, vol2.VunitLocations[0].DiskID)
+
+		mockCli.EXPECT().UpdateVolume(any, any).Return(errMock)
+		err = cli.UpdateVolume(ctx, vol.VunitLocations[0].Vuid+1, vol.VunitLocations[0].Vuid, 1)
+		require.True(t, errors.Is(err, errMock))
+		mockCli.EXPECT().GetVolumeInfo(any, any).Return(nil, errMock)
+		_, err = cli.GetVolumeInfo(ctx, volume.Vid)
+		require.True(t, errors.Is(err, errMock))
+
+		mockCli.EXPECT().GetVolumeInfo(any, any).Return(volume, nil)
+		_, err = cli.GetVolumeInfo(ctx, volume.Vid)
+		require.NoError(t, err)
+		mockCli.EXPECT().LockVolume(any, any).Return(nil)
+		require.NoError(t, cli.LockVolume(ctx, volume.Vid))
+		mockCli.EXPECT().GetVolumeInfo(any, any).Return(volume, nil)
+		_, err = cli.GetVolumeInfo(ctx, volume.Vid)
+		require.NoError(t, err)
+	}
+	// disk
+	{
+		mockCli.EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{
+			DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{DiskID: 1}, Status: proto.DiskStatusBroken,
+		}, nil)
+		disk, err := cli.GetDiskInfo(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, disk.IsBroken())
+		disk, err = cli.GetDiskInfo(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, disk.IsBroken())
+
+		mockCli.EXPECT().SetDisk(any, any, any).Return(nil)
+		require.NoError(t, cli.SetDiskRepairing(ctx, 1))
+		mockCli.EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{
+			DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{DiskID: 1}, Status: proto.DiskStatusRepairing,
+		}, nil)
+		disk, err = cli.GetDiskInfo(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, proto.DiskStatusRepairing, disk.Status)
+	}
+}
@@ -81,6 +81,20 @@ func (mr *MockClusterManagerMockRecorder) DiskInfo(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskInfo", reflect.TypeOf((*MockClusterManager)(nil).DiskInfo), arg0, arg1)
 }
 
+// DropDisk mocks base method.
+func (m *MockClusterManager) DropDisk(arg0 context.Context, arg1 proto.DiskID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropDisk", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropDisk indicates an expected call of DropDisk.
+func (mr *MockClusterManagerMockRecorder) DropDisk(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropDisk", reflect.TypeOf((*MockClusterManager)(nil).DropDisk), arg0, arg1)
+}
+
 // DroppedDisk mocks base method.
 func (m *MockClusterManager) DroppedDisk(arg0 context.Context, arg1 proto.DiskID) error {
 	m.ctrl.T.Helper()
@@ -300,6 +314,20 @@ func (mr *MockClusterManagerMockRecorder) SetKV(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKV", reflect.TypeOf((*MockClusterManager)(nil).SetKV), arg0, arg1, arg2)
 }
 
+// SetReadonlyDisk mocks base method.
+func (m *MockClusterManager) SetReadonlyDisk(arg0 context.Context, arg1 proto.DiskID, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReadonlyDisk", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReadonlyDisk indicates an expected call of SetReadonlyDisk.
+func (mr *MockClusterManagerMockRecorder) SetReadonlyDisk(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadonlyDisk", reflect.TypeOf((*MockClusterManager)(nil).SetReadonlyDisk), arg0, arg1, arg2)
+}
+
 // UnlockVolume mocks base method.
 func (m *MockClusterManager) UnlockVolume(arg0 context.Context, arg1 *clustermgr.UnlockVolumeArgs) error {
 	m.ctrl.T.Helper()
@@ -327,3 +355,18 @@ func (mr *MockClusterManagerMockRecorder) UpdateVolume(arg0, arg1 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolume", reflect.TypeOf((*MockClusterManager)(nil).UpdateVolume), arg0, arg1)
 }
+
+// WatchBrokenDisk mocks base method.
+func (m *MockClusterManager) WatchBrokenDisk(arg0 context.Context, arg1 *clustermgr.WatchBrokenDiskArgs) (*clustermgr.WatchBrokenDiskRet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchBrokenDisk", arg0, arg1)
+	ret0, _ := ret[0].(*clustermgr.WatchBrokenDiskRet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchBrokenDisk indicates an expected call of WatchBrokenDisk.
+func (mr *MockClusterManagerMockRecorder) WatchBrokenDisk(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchBrokenDisk", reflect.TypeOf((*MockClusterManager)(nil).WatchBrokenDisk), arg0, arg1)
+}
@@ -27,9 +27,11 @@ import (
 
 	"github.com/cubefs/cubefs/blobstore/api/blobnode"
 	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
 	"github.com/cubefs/cubefs/blobstore/common/codemode"
 	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/taskswitch"
 	_ "github.com/cubefs/cubefs/blobstore/testing/nolog"
 )
@@ -147,6 +149,33 @@ func TestClustermgrClient(t *testing.T) {
 		allocUnit, err := cli.AllocVolumeUnit(ctx, proto.Vuid(2))
 		require.NoError(t, err)
 		require.Equal(t, unit.Vuid, allocUnit.Location().Vuid)
+
+		cli.client.(*MockClusterManager).EXPECT().AllocVolumeUnit(any, any).DoAndReturn(
+			func(_ context.Context, args *cmapi.AllocVolumeUnitArgs) (*cmapi.AllocVolumeUnit, error) {
+				require.True(t, args.Cold)
+				return unit, nil
+			})
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{Host: "127.0.0.1:xxx"}, nil)
+		allocUnit, err = cli.AllocColdVolumeUnit(ctx, proto.Vuid(2))
+		require.NoError(t, err)
+		require.Equal(t, unit.Vuid, allocUnit.Location().Vuid)
+
+		// idc of the destination disk is returned
+		cli.client.(*MockClusterManager).EXPECT().AllocVolumeUnit(any, any).Return(unit, nil)
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{Host: "127.0.0.1:xxx", Idc: "z1"}, nil)
+		allocUnit, err = cli.AllocVolumeUnit(ctx, proto.Vuid(2))
+		require.NoError(t, err)
+		require.Equal(t, "z1", allocUnit.Idc)
+
+		cli.client.(*MockClusterManager).EXPECT().AllocVolumeUnit(any, any).DoAndReturn(
+			func(_ context.Context, args *cmapi.AllocVolumeUnitArgs) (*cmapi.AllocVolumeUnit, error) {
+				require.Equal(t, []proto.DiskID{3, 4}, args.ExcludeDisks)
+				return unit, nil
+			})
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{Host: "127.0.0.1:xxx"}, nil)
+		allocUnit, err = cli.AllocVolumeUnitWithArgs(ctx, &cmapi.AllocVolumeUnitArgs{Vuid: proto.Vuid(2), ExcludeDisks: []proto.DiskID{3, 4}})
+		require.NoError(t, err)
+		require.Equal(t, unit.Vuid, allocUnit.Location().Vuid)
 	}
 	{
 		// release volume unit
@@ -202,6 +231,26 @@ func TestClustermgrClient(t *testing.T) {
 		_, err := cli.ListBrokenDisks(ctx)
 		require.True(t, errors.Is(err, errMock))
 	}
+	{
+		// watch broken disk
+		require.Equal(t, defaultWatchBrokenDiskTimeoutMs, cli.watchTimeoutMs)
+		require.Equal(t, int64(500), watchBrokenDiskTimeoutMs(&cmapi.Config{LbConfig: rpc.LbConfig{Config: rpc.Config{ClientTimeoutMs: 1000}}}))
+
+		cli.client.(*MockClusterManager).EXPECT().WatchBrokenDisk(any, any).Return(nil, errMock)
+		version, err := cli.WatchBrokenDisks(ctx, 1)
+		require.True(t, errors.Is(err, errMock))
+		require.Equal(t, uint64(1), version)
+
+		cli.client.(*MockClusterManager).EXPECT().WatchBrokenDisk(any, any).DoAndReturn(
+			func(_ context.Context, args *cmapi.WatchBrokenDiskArgs) (*cmapi.WatchBrokenDiskRet, error) {
+				require.Equal(t, uint64(1), args.Version)
+				require.Equal(t, defaultWatchBrokenDiskTimeoutMs, args.TimeoutMs)
+				return &cmapi.WatchBrokenDiskRet{Version: 2}, nil
+			})
+		version, err = cli.WatchBrokenDisks(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), version)
+	}
 	{
 		// list repair disk
 		cli.client.(*MockClusterManager).EXPECT().ListDisk(any, any).Return(cmapi.ListDiskRet{}, errMock)
@@ -487,4 +536,104 @@ func TestClustermgrClient(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, offset, offset2)
 	}
+	{
+		// set queue params
+		params := &api.QueueParams{TaskType: proto.TaskTypeBalance, LeaseExpiredS: 20, WorkQueueSize: 10}
+		cli.client.(*MockClusterManager).EXPECT().SetKV(any, "balance-queue_params", any).Return(nil)
+		require.NoError(t, cli.SetQueueParams(ctx, params))
+
+		// get queue params
+		paramsBytes, _ := json.Marshal(params)
+		cli.client.(*MockClusterManager).EXPECT().GetKV(any, "balance-queue_params").Return(cmapi.GetKvRet{Value: paramsBytes}, nil)
+		params2, err := cli.GetQueueParams(ctx, proto.TaskTypeBalance)
+		require.NoError(t, err)
+		require.Equal(t, params, params2)
+
+		cli.client.(*MockClusterManager).EXPECT().GetKV(any, any).Return(cmapi.GetKvRet{}, errMock)
+		_, err = cli.GetQueueParams(ctx, proto.TaskTypeBalance)
+		require.True(t, errors.Is(err, errMock))
+	}
+	{
+		// set and delete volume inspect result
+		ret := &proto.VolumeInspectResult{Vid: 1, MissedBlobs: 2, MaxMissedShards: 1}
+		cli.client.(*MockClusterManager).EXPECT().SetKV(any, "volume_inspect-result-1", any).Return(nil)
+		require.NoError(t, cli.SetVolumeInspectResult(ctx, ret))
+		cli.client.(*MockClusterManager).EXPECT().DeleteKV(any, "volume_inspect-result-1").Return(nil)
+		require.NoError(t, cli.DeleteVolumeInspectResult(ctx, 1))
+
+		// list volume inspect results
+		retBytes, _ := json.Marshal(ret)
+		cli.client.(*MockClusterManager).EXPECT().ListKV(any, any).Return(cmapi.ListKvRet{
+			Kvs:    []*cmapi.KeyValue{{Key: proto.VolumeInspectResultKey(1), Value: retBytes}},
+			Marker: proto.VolumeInspectResultKey(1),
+		}, nil)
+		cli.client.(*MockClusterManager).EXPECT().ListKV(any, any).Return(cmapi.ListKvRet{}, nil)
+		rets, err := cli.ListVolumeInspectResults(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []*proto.VolumeInspectResult{ret}, rets)
+
+		cli.client.(*MockClusterManager).EXPECT().ListKV(any, any).Return(cmapi.ListKvRet{}, errMock)
+		_, err = cli.ListVolumeInspectResults(ctx)
+		require.True(t, errors.Is(err, errMock))
+	}
+	{
+		// list host disks
+		host := "http://127.0.0.1:8889"
+		cli.client.(*MockClusterManager).EXPECT().ListDisk(any, any).Return(cmapi.ListDiskRet{}, errMock)
+		_, err := cli.ListHostDisks(ctx, host)
+		require.True(t, errors.Is(err, errMock))
+
+		cli.client.(*MockClusterManager).EXPECT().ListDisk(any, any).DoAndReturn(
+			func(_ context.Context, args *cmapi.ListOptionArgs) (cmapi.ListDiskRet, error) {
+				require.Equal(t, host, args.Host)
+				require.Equal(t, proto.DiskStatus(0), args.Status)
+				return cmapi.ListDiskRet{Disks: []*blobnode.DiskInfo{{DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{DiskID: 1}, Host: host}}, Marker: 1}, nil
+			})
+		cli.client.(*MockClusterManager).EXPECT().ListDisk(any, any).Return(cmapi.ListDiskRet{
+			Disks: []*blobnode.DiskInfo{{DiskHeartBeatInfo: blobnode.DiskHeartBeatInfo{DiskID: 2}, Host: host}},
+		}, nil)
+		disks, err := cli.ListHostDisks(ctx, host)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(disks))
+		require.Equal(t, proto.DiskID(2), disks[1].DiskID)
+	}
+	{
+		// drop disk
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(nil, errMock)
+		require.True(t, errors.Is(cli.DropDisk(ctx, 1), errMock))
+
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{}, nil)
+		cli.client.(*MockClusterManager).EXPECT().SetReadonlyDisk(any, proto.DiskID(1), true).Return(errMock)
+		require.True(t, errors.Is(cli.DropDisk(ctx, 1), errMock))
+
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{}, nil)
+		cli.client.(*MockClusterManager).EXPECT().SetReadonlyDisk(any, proto.DiskID(1), true).Return(nil)
+		cli.client.(*MockClusterManager).EXPECT().DropDisk(any, proto.DiskID(1)).Return(nil)
+		require.NoError(t, cli.DropDisk(ctx, 1))
+
+		cli.client.(*MockClusterManager).EXPECT().DiskInfo(any, any).Return(&blobnode.DiskInfo{Readonly: true}, nil)
+		cli.client.(*MockClusterManager).EXPECT().DropDisk(any, proto.DiskID(1)).Return(errMock)
+		require.True(t, errors.Is(cli.DropDisk(ctx, 1), errMock))
+	}
+	{
+		// get and set host drains
+		cli.client.(*MockClusterManager).EXPECT().GetKV(any, "disk_drop-drain_hosts").Return(cmapi.GetKvRet{}, errcode.ErrNotFound)
+		drains, err := cli.GetHostDrains(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(drains))
+
+		cli.client.(*MockClusterManager).EXPECT().GetKV(any, any).Return(cmapi.GetKvRet{}, errMock)
+		_, err = cli.GetHostDrains(ctx)
+		require.True(t, errors.Is(err, errMock))
+
+		drains = []*HostDrainMeta{{Host: "http://127.0.0.1:8889", Concurrency: 2, Disks: []proto.DiskID{1, 2}}}
+		drainsBytes, _ := json.Marshal(drains)
+		cli.client.(*MockClusterManager).EXPECT().SetKV(any, "disk_drop-drain_hosts", drainsBytes).Return(nil)
+		require.NoError(t, cli.SetHostDrains(ctx, drains))
+
+		cli.client.(*MockClusterManager).EXPECT().GetKV(any, any).Return(cmapi.GetKvRet{Value: drainsBytes}, nil)
+		drains2, err := cli.GetHostDrains(ctx)
+		require.NoError(t, err)
+		require.Equal(t, drains, drains2)
+	}
 }
@@ -20,6 +20,7 @@ import (
 	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
 	api "github.com/cubefs/cubefs/blobstore/api/proxy"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 )
 
@@ -42,7 +43,9 @@ func NewProxyClient(cfg *api.LbConfig, clusterMgr *cmapi.Client, clusterID proto
 // SendShardRepairMsg send shard repair message
 func (c *proxyClient) SendShardRepairMsg(ctx context.Context, vid proto.Vid, bid proto.BlobID, badIdx []uint8) error {
 	pSpan := trace.SpanFromContextSafe(ctx)
+	requestID := rpc.RequestIDFromContext(ctx)
 	span, ctx := trace.StartSpanFromContextWithTraceID(context.Background(), "SendShardRepairMsg", pSpan.TraceID())
+	ctx = rpc.ContextWithRequestID(ctx, requestID)
 	span.Debugf("send shard repair msg vid %d bid %d badIdx %+v", vid, bid, badIdx)
 
 	err := c.client.SendShardRepairMsg(ctx, &api.ShardRepairArgs{
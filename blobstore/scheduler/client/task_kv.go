@@ -0,0 +1,193 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+)
+
+const (
+	defaultListKvCount = 10
+	maxListKvCount     = 1000
+)
+
+// TaskKV storage of migrate tasks, consume offsets, checkpoints and queue params,
+// which are stored in kv of clustermgr by default.
+type TaskKV interface {
+	GetKV(ctx context.Context, key string) (ret cmapi.GetKvRet, err error)
+	DeleteKV(ctx context.Context, key string) (err error)
+	SetKV(ctx context.Context, key string, value []byte) (err error)
+	ListKV(ctx context.Context, args *cmapi.ListKvOpts) (ret cmapi.ListKvRet, err error)
+}
+
+// MemTaskKV in-memory TaskKV with the same semantics of clustermgr kv,
+// so that a full scheduler can run in tests without storing tasks in clustermgr.
+// All kvs are written to the file after each change if path is not empty,
+// and loaded from the file when creating, to test restarting of scheduler.
+type MemTaskKV struct {
+	mu   sync.RWMutex
+	path string
+	kvs  map[string][]byte
+}
+
+// NewMemTaskKV returns in-memory TaskKV, persisted in path if not empty
+func NewMemTaskKV(path string) (*MemTaskKV, error) {
+	m := &MemTaskKV{path: path, kvs: make(map[string][]byte)}
+	if path == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &m.kvs); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetKV returns ErrNotFound if the key does not exist
+func (m *MemTaskKV) GetKV(ctx context.Context, key string) (ret cmapi.GetKvRet, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	val, ok := m.kvs[key]
+	if !ok {
+		return ret, errcode.ErrNotFound
+	}
+	ret.Value = append([]byte(nil), val...)
+	return ret, nil
+}
+
+// DeleteKV deletes the key, it is ok if the key does not exist
+func (m *MemTaskKV) DeleteKV(ctx context.Context, key string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.kvs[key]; !ok {
+		return nil
+	}
+	delete(m.kvs, key)
+	return m.persist()
+}
+
+// SetKV sets value of the key
+func (m *MemTaskKV) SetKV(ctx context.Context, key string, value []byte) (err error) {
+	if key == "" || value == nil {
+		return errcode.ErrIllegalArguments
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.kvs[key] = append([]byte(nil), value...)
+	return m.persist()
+}
+
+// ListKV lists kvs with prefix after marker in order of key,
+// the returned marker is empty if there is no more kvs.
+func (m *MemTaskKV) ListKV(ctx context.Context, args *cmapi.ListKvOpts) (ret cmapi.ListKvRet, err error) {
+	count := args.Count
+	if count <= 0 {
+		count = defaultListKvCount
+	}
+	if count > maxListKvCount {
+		count = maxListKvCount
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]string, 0)
+	for key := range m.kvs {
+		if strings.HasPrefix(key, args.Prefix) && key > args.Marker {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > count {
+		keys = keys[:count]
+	}
+
+	ret.Kvs = make([]*cmapi.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		ret.Kvs = append(ret.Kvs, &cmapi.KeyValue{Key: key, Value: append([]byte(nil), m.kvs[key]...)})
+	}
+	if len(keys) == count {
+		ret.Marker = keys[len(keys)-1]
+	}
+	return ret, nil
+}
+
+// persist writes all kvs to a temporary file and renames it to path
+func (m *MemTaskKV) persist() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(m.kvs)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// taskKVClusterMgr routes kv requests to TaskKV
+type taskKVClusterMgr struct {
+	IClusterManager
+	kv TaskKV
+}
+
+func (c *taskKVClusterMgr) GetKV(ctx context.Context, key string) (cmapi.GetKvRet, error) {
+	return c.kv.GetKV(ctx, key)
+}
+
+func (c *taskKVClusterMgr) DeleteKV(ctx context.Context, key string) error {
+	return c.kv.DeleteKV(ctx, key)
+}
+
+func (c *taskKVClusterMgr) SetKV(ctx context.Context, key string, value []byte) error {
+	return c.kv.SetKV(ctx, key, value)
+}
+
+func (c *taskKVClusterMgr) ListKV(ctx context.Context, args *cmapi.ListKvOpts) (cmapi.ListKvRet, error) {
+	return c.kv.ListKV(ctx, args)
+}
+
+// NewClusterMgrClientWithTaskKV returns clustermgr client which stores tasks in kv
+func NewClusterMgrClientWithTaskKV(conf *cmapi.Config, kv TaskKV) ClusterMgrAPI {
+	return &clustermgrClient{
+		client:         &taskKVClusterMgr{IClusterManager: cmapi.New(conf), kv: kv},
+		watchTimeoutMs: watchBrokenDiskTimeoutMs(conf),
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+)
+
+func TestMemTaskKV(t *testing.T) {
+	ctx := context.Background()
+	dir, err := os.MkdirTemp(os.TempDir(), "scheduler-task-kv")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tasks.json")
+
+	kv, err := NewMemTaskKV(path)
+	require.NoError(t, err)
+	cli := &clustermgrClient{client: &taskKVClusterMgr{kv: kv}}
+
+	_, err = cli.GetMigrateTask(ctx, proto.TaskTypeBalance, "not_exist")
+	require.Equal(t, http.StatusNotFound, rpc.DetectStatusCode(err))
+	require.NoError(t, cli.DeleteMigrateTask(ctx, "not_exist"))
+	require.Error(t, kv.SetKV(ctx, "", []byte("v")))
+
+	for i := 1; i <= 15; i++ {
+		task := &proto.MigrateTask{
+			TaskID:       GenMigrateTaskID(proto.TaskTypeBalance, proto.DiskID(i%2+1), proto.Vid(i)),
+			TaskType:     proto.TaskTypeBalance,
+			SourceDiskID: proto.DiskID(i%2 + 1),
+		}
+		require.NoError(t, cli.AddMigrateTask(ctx, task))
+	}
+	task := &proto.MigrateTask{TaskID: GenMigrateTaskID(proto.TaskTypeDiskRepair, 1, 1), TaskType: proto.TaskTypeDiskRepair}
+	require.NoError(t, cli.AddMigrateTask(ctx, task))
+	require.NoError(t, cli.SetConsumeOffset(proto.TaskTypeShardRepair, "topic", 1, 100))
+
+	tasks, err := cli.ListAllMigrateTasks(ctx, proto.TaskTypeBalance)
+	require.NoError(t, err)
+	require.Len(t, tasks, 15)
+	tasks, err = cli.ListAllMigrateTasksByDiskID(ctx, proto.TaskTypeBalance, 1)
+	require.NoError(t, err)
+	require.Len(t, tasks, 7)
+	tasks, marker, err := cli.ListMigrateTasks(ctx, proto.TaskTypeBalance, &cmapi.ListKvOpts{Prefix: GenMigrateTaskPrefix(proto.TaskTypeBalance), Count: 10})
+	require.NoError(t, err)
+	require.Len(t, tasks, 10)
+	require.NotEmpty(t, marker)
+	tasks, marker, err = cli.ListMigrateTasks(ctx, proto.TaskTypeBalance, &cmapi.ListKvOpts{Prefix: GenMigrateTaskPrefix(proto.TaskTypeBalance), Marker: marker, Count: 10})
+	require.NoError(t, err)
+	require.Len(t, tasks, 5)
+	require.Empty(t, marker)
+
+	// restart
+	kv, err = NewMemTaskKV(path)
+	require.NoError(t, err)
+	cli = &clustermgrClient{client: &taskKVClusterMgr{kv: kv}}
+	task2, err := cli.GetMigrateTask(ctx, task.TaskType, task.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, task.TaskID, task2.TaskID)
+	offset, err := cli.GetConsumeOffset(proto.TaskTypeShardRepair, "topic", 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), offset)
+	require.NoError(t, cli.DeleteMigrateTask(ctx, task.TaskID))
+
+	kv, err = NewMemTaskKV(path)
+	require.NoError(t, err)
+	_, err = kv.GetKV(ctx, task.TaskID)
+	require.Equal(t, http.StatusNotFound, rpc.DetectStatusCode(err))
+
+	// broken file
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = NewMemTaskKV(path)
+	require.Error(t, err)
+}
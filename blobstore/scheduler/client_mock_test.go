@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	clustermgr "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	scheduler "github.com/cubefs/cubefs/blobstore/api/scheduler"
 	proto "github.com/cubefs/cubefs/blobstore/common/proto"
 	client "github.com/cubefs/cubefs/blobstore/scheduler/client"
 	gomock "github.com/golang/mock/gomock"
@@ -65,6 +66,21 @@ func (mr *MockClusterMgrAPIMockRecorder) AddMigratingDisk(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMigratingDisk", reflect.TypeOf((*MockClusterMgrAPI)(nil).AddMigratingDisk), arg0, arg1)
 }
 
+// AllocColdVolumeUnit mocks base method.
+func (m *MockClusterMgrAPI) AllocColdVolumeUnit(arg0 context.Context, arg1 proto.Vuid) (*client.AllocVunitInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllocColdVolumeUnit", arg0, arg1)
+	ret0, _ := ret[0].(*client.AllocVunitInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllocColdVolumeUnit indicates an expected call of AllocColdVolumeUnit.
+func (mr *MockClusterMgrAPIMockRecorder) AllocColdVolumeUnit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocColdVolumeUnit", reflect.TypeOf((*MockClusterMgrAPI)(nil).AllocColdVolumeUnit), arg0, arg1)
+}
+
 // AllocVolumeUnit mocks base method.
 func (m *MockClusterMgrAPI) AllocVolumeUnit(arg0 context.Context, arg1 proto.Vuid) (*client.AllocVunitInfo, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +96,21 @@ func (mr *MockClusterMgrAPIMockRecorder) AllocVolumeUnit(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocVolumeUnit", reflect.TypeOf((*MockClusterMgrAPI)(nil).AllocVolumeUnit), arg0, arg1)
 }
 
+// AllocVolumeUnitWithArgs mocks base method.
+func (m *MockClusterMgrAPI) AllocVolumeUnitWithArgs(arg0 context.Context, arg1 *clustermgr.AllocVolumeUnitArgs) (*client.AllocVunitInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllocVolumeUnitWithArgs", arg0, arg1)
+	ret0, _ := ret[0].(*client.AllocVunitInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllocVolumeUnitWithArgs indicates an expected call of AllocVolumeUnitWithArgs.
+func (mr *MockClusterMgrAPIMockRecorder) AllocVolumeUnitWithArgs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocVolumeUnitWithArgs", reflect.TypeOf((*MockClusterMgrAPI)(nil).AllocVolumeUnitWithArgs), arg0, arg1)
+}
+
 // DeleteMigrateTask mocks base method.
 func (m *MockClusterMgrAPI) DeleteMigrateTask(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -108,6 +139,34 @@ func (mr *MockClusterMgrAPIMockRecorder) DeleteMigratingDisk(arg0, arg1, arg2 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMigratingDisk", reflect.TypeOf((*MockClusterMgrAPI)(nil).DeleteMigratingDisk), arg0, arg1, arg2)
 }
 
+// DeleteVolumeInspectResult mocks base method.
+func (m *MockClusterMgrAPI) DeleteVolumeInspectResult(arg0 context.Context, arg1 proto.Vid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolumeInspectResult", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVolumeInspectResult indicates an expected call of DeleteVolumeInspectResult.
+func (mr *MockClusterMgrAPIMockRecorder) DeleteVolumeInspectResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolumeInspectResult", reflect.TypeOf((*MockClusterMgrAPI)(nil).DeleteVolumeInspectResult), arg0, arg1)
+}
+
+// DropDisk mocks base method.
+func (m *MockClusterMgrAPI) DropDisk(arg0 context.Context, arg1 proto.DiskID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropDisk", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropDisk indicates an expected call of DropDisk.
+func (mr *MockClusterMgrAPIMockRecorder) DropDisk(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropDisk", reflect.TypeOf((*MockClusterMgrAPI)(nil).DropDisk), arg0, arg1)
+}
+
 // GetConfig mocks base method.
 func (m *MockClusterMgrAPI) GetConfig(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +212,21 @@ func (mr *MockClusterMgrAPIMockRecorder) GetDiskInfo(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiskInfo", reflect.TypeOf((*MockClusterMgrAPI)(nil).GetDiskInfo), arg0, arg1)
 }
 
+// GetHostDrains mocks base method.
+func (m *MockClusterMgrAPI) GetHostDrains(arg0 context.Context) ([]*client.HostDrainMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHostDrains", arg0)
+	ret0, _ := ret[0].([]*client.HostDrainMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHostDrains indicates an expected call of GetHostDrains.
+func (mr *MockClusterMgrAPIMockRecorder) GetHostDrains(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHostDrains", reflect.TypeOf((*MockClusterMgrAPI)(nil).GetHostDrains), arg0)
+}
+
 // GetMigrateTask mocks base method.
 func (m *MockClusterMgrAPI) GetMigrateTask(arg0 context.Context, arg1 proto.TaskType, arg2 string) (*proto.MigrateTask, error) {
 	m.ctrl.T.Helper()
@@ -183,6 +257,21 @@ func (mr *MockClusterMgrAPIMockRecorder) GetMigratingDisk(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigratingDisk", reflect.TypeOf((*MockClusterMgrAPI)(nil).GetMigratingDisk), arg0, arg1, arg2)
 }
 
+// GetQueueParams mocks base method.
+func (m *MockClusterMgrAPI) GetQueueParams(arg0 context.Context, arg1 proto.TaskType) (*scheduler.QueueParams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueParams", arg0, arg1)
+	ret0, _ := ret[0].(*scheduler.QueueParams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueParams indicates an expected call of GetQueueParams.
+func (mr *MockClusterMgrAPIMockRecorder) GetQueueParams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueParams", reflect.TypeOf((*MockClusterMgrAPI)(nil).GetQueueParams), arg0, arg1)
+}
+
 // GetService mocks base method.
 func (m *MockClusterMgrAPI) GetService(arg0 context.Context, arg1 string, arg2 proto.ClusterID) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -318,6 +407,21 @@ func (mr *MockClusterMgrAPIMockRecorder) ListDropDisks(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDropDisks", reflect.TypeOf((*MockClusterMgrAPI)(nil).ListDropDisks), arg0)
 }
 
+// ListHostDisks mocks base method.
+func (m *MockClusterMgrAPI) ListHostDisks(arg0 context.Context, arg1 string) ([]*client.DiskInfoSimple, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHostDisks", arg0, arg1)
+	ret0, _ := ret[0].([]*client.DiskInfoSimple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHostDisks indicates an expected call of ListHostDisks.
+func (mr *MockClusterMgrAPIMockRecorder) ListHostDisks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostDisks", reflect.TypeOf((*MockClusterMgrAPI)(nil).ListHostDisks), arg0, arg1)
+}
+
 // ListMigrateTasks mocks base method.
 func (m *MockClusterMgrAPI) ListMigrateTasks(arg0 context.Context, arg1 proto.TaskType, arg2 *clustermgr.ListKvOpts) ([]*proto.MigrateTask, string, error) {
 	m.ctrl.T.Helper()
@@ -380,6 +484,21 @@ func (mr *MockClusterMgrAPIMockRecorder) ListVolume(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolume", reflect.TypeOf((*MockClusterMgrAPI)(nil).ListVolume), arg0, arg1, arg2)
 }
 
+// ListVolumeInspectResults mocks base method.
+func (m *MockClusterMgrAPI) ListVolumeInspectResults(arg0 context.Context) ([]*proto.VolumeInspectResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumeInspectResults", arg0)
+	ret0, _ := ret[0].([]*proto.VolumeInspectResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumeInspectResults indicates an expected call of ListVolumeInspectResults.
+func (mr *MockClusterMgrAPIMockRecorder) ListVolumeInspectResults(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumeInspectResults", reflect.TypeOf((*MockClusterMgrAPI)(nil).ListVolumeInspectResults), arg0)
+}
+
 // LockVolume mocks base method.
 func (m *MockClusterMgrAPI) LockVolume(arg0 context.Context, arg1 proto.Vid) error {
 	m.ctrl.T.Helper()
@@ -478,6 +597,34 @@ func (mr *MockClusterMgrAPIMockRecorder) SetDiskRepairing(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDiskRepairing", reflect.TypeOf((*MockClusterMgrAPI)(nil).SetDiskRepairing), arg0, arg1)
 }
 
+// SetHostDrains mocks base method.
+func (m *MockClusterMgrAPI) SetHostDrains(arg0 context.Context, arg1 []*client.HostDrainMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHostDrains", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHostDrains indicates an expected call of SetHostDrains.
+func (mr *MockClusterMgrAPIMockRecorder) SetHostDrains(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHostDrains", reflect.TypeOf((*MockClusterMgrAPI)(nil).SetHostDrains), arg0, arg1)
+}
+
+// SetQueueParams mocks base method.
+func (m *MockClusterMgrAPI) SetQueueParams(arg0 context.Context, arg1 *scheduler.QueueParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQueueParams", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetQueueParams indicates an expected call of SetQueueParams.
+func (mr *MockClusterMgrAPIMockRecorder) SetQueueParams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQueueParams", reflect.TypeOf((*MockClusterMgrAPI)(nil).SetQueueParams), arg0, arg1)
+}
+
 // SetVolumeInspectCheckPoint mocks base method.
 func (m *MockClusterMgrAPI) SetVolumeInspectCheckPoint(arg0 context.Context, arg1 proto.Vid) error {
 	m.ctrl.T.Helper()
@@ -492,6 +639,20 @@ func (mr *MockClusterMgrAPIMockRecorder) SetVolumeInspectCheckPoint(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVolumeInspectCheckPoint", reflect.TypeOf((*MockClusterMgrAPI)(nil).SetVolumeInspectCheckPoint), arg0, arg1)
 }
 
+// SetVolumeInspectResult mocks base method.
+func (m *MockClusterMgrAPI) SetVolumeInspectResult(arg0 context.Context, arg1 *proto.VolumeInspectResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVolumeInspectResult", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVolumeInspectResult indicates an expected call of SetVolumeInspectResult.
+func (mr *MockClusterMgrAPIMockRecorder) SetVolumeInspectResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVolumeInspectResult", reflect.TypeOf((*MockClusterMgrAPI)(nil).SetVolumeInspectResult), arg0, arg1)
+}
+
 // UnlockVolume mocks base method.
 func (m *MockClusterMgrAPI) UnlockVolume(arg0 context.Context, arg1 proto.Vid) error {
 	m.ctrl.T.Helper()
@@ -534,6 +695,21 @@ func (mr *MockClusterMgrAPIMockRecorder) UpdateVolume(arg0, arg1, arg2, arg3 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolume", reflect.TypeOf((*MockClusterMgrAPI)(nil).UpdateVolume), arg0, arg1, arg2, arg3)
 }
 
+// WatchBrokenDisks mocks base method.
+func (m *MockClusterMgrAPI) WatchBrokenDisks(arg0 context.Context, arg1 uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchBrokenDisks", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchBrokenDisks indicates an expected call of WatchBrokenDisks.
+func (mr *MockClusterMgrAPIMockRecorder) WatchBrokenDisks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchBrokenDisks", reflect.TypeOf((*MockClusterMgrAPI)(nil).WatchBrokenDisks), arg0, arg1)
+}
+
 // MockBlobnodeAPI is a mock of BlobnodeAPI interface.
 type MockBlobnodeAPI struct {
 	ctrl     *gomock.Controller
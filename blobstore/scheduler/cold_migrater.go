@@ -0,0 +1,286 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/recordlog"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/taskswitch"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/scheduler/base"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+	"github.com/cubefs/cubefs/blobstore/util/log"
+)
+
+const (
+	collectColdTaskPauseS = 5
+)
+
+var (
+	// ErrNoColdDisk no cold disk in cluster
+	ErrNoColdDisk = errors.New("no cold disk in cluster")
+	// ErrTooManyColdMigratingTasks too many cold migrating tasks
+	ErrTooManyColdMigratingTasks = errors.New("too many cold migrating tasks")
+
+	errColdScanFinished = errors.New("scan all volumes finished")
+)
+
+// ColdMigrateConfig cold migrate task manager config
+type ColdMigrateConfig struct {
+	// volume is sealed when it is idle and its free space ratio is not greater than MaxFreeRatio
+	MaxFreeRatio float64 `json:"max_free_ratio"`
+	ListVolStep  int     `json:"list_vol_step"`
+	// pause between two rounds of scanning all volumes
+	ScanIntervalS int `json:"scan_interval_s"`
+	MigrateConfig
+}
+
+// ColdMigrateMgr moves volume units of sealed volumes from normal disks to cold disks.
+// it reuses the migrate procedure, so the volume mapping in clustermgr is updated
+// when task finished and the reading of volume is transparent to users.
+type ColdMigrateMgr struct {
+	IMigrator
+
+	clusterTopology IClusterTopology
+	clusterMgrCli   client.ClusterMgrAPI
+	volumeHeat      IVolumeHeat
+
+	// next vid to scan
+	marker proto.Vid
+	cfg    *ColdMigrateConfig
+}
+
+// NewColdMigrateMgr returns cold migrate manager
+func NewColdMigrateMgr(clusterMgrCli client.ClusterMgrAPI, volumeUpdater client.IVolumeUpdater, taskSwitch taskswitch.ISwitcher,
+	clusterTopology IClusterTopology, volumeHeat IVolumeHeat, taskLogger recordlog.Encoder, conf *ColdMigrateConfig) *ColdMigrateMgr {
+	mgr := &ColdMigrateMgr{
+		clusterTopology: clusterTopology,
+		clusterMgrCli:   clusterMgrCli,
+		volumeHeat:      volumeHeat,
+		cfg:             conf,
+	}
+	mgr.IMigrator = NewMigrateMgr(clusterMgrCli, volumeUpdater, taskSwitch, taskLogger,
+		&conf.MigrateConfig, proto.TaskTypeColdMigrate)
+	return mgr
+}
+
+// Run run cold migrate task manager
+func (mgr *ColdMigrateMgr) Run() {
+	go mgr.collectTaskLoop()
+	mgr.IMigrator.Run()
+	go mgr.checkAndClearJunkTasksLoop()
+}
+
+func (mgr *ColdMigrateMgr) collectTaskLoop() {
+	t := time.NewTicker(time.Duration(mgr.cfg.CollectTaskIntervalS) * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			mgr.IMigrator.WaitEnable()
+			err := mgr.collectionTask()
+			switch err {
+			case ErrTooManyColdMigratingTasks:
+				log.Debugf("no task to collect and sleep: sleep second[%d], err[%+v]", collectColdTaskPauseS, err)
+				time.Sleep(time.Duration(collectColdTaskPauseS) * time.Second)
+			case errColdScanFinished, ErrNoColdDisk:
+				log.Debugf("wait next round of scanning: sleep second[%d], err[%+v]", mgr.cfg.ScanIntervalS, err)
+				time.Sleep(time.Duration(mgr.cfg.ScanIntervalS) * time.Second)
+			}
+		case <-mgr.IMigrator.Done():
+			return
+		}
+	}
+}
+
+func (mgr *ColdMigrateMgr) collectionTask() (err error) {
+	span, ctx := trace.StartSpanFromContext(context.Background(), "cold_migrate_collectionTask")
+	defer span.Finish()
+
+	needMigrateCnt := mgr.cfg.DiskConcurrency - mgr.IMigrator.GetMigratingDiskNum()
+	if needMigrateCnt <= 0 {
+		span.Warnf("the number of cold migrating disk is greater than config: current[%d], conf[%d]",
+			mgr.IMigrator.GetMigratingDiskNum(), mgr.cfg.DiskConcurrency)
+		return ErrTooManyColdMigratingTasks
+	}
+
+	disks, coldIDCs := mgr.clusterDisks()
+	if len(coldIDCs) == 0 {
+		return ErrNoColdDisk
+	}
+
+	vols, nextVid, err := mgr.clusterMgrCli.ListVolume(ctx, mgr.marker, mgr.cfg.ListVolStep)
+	if err != nil {
+		span.Errorf("list volume failed: marker[%d], err[%+v]", mgr.marker, err)
+		return
+	}
+	if len(vols) == 0 {
+		span.Infof("scan all volumes finished: marker[%d]", mgr.marker)
+		mgr.marker = proto.Vid(0)
+		return errColdScanFinished
+	}
+
+	// choose cold volumes first
+	heats := make(map[proto.Vid]float64, len(vols))
+	for _, vol := range vols {
+		heats[vol.Vid] = mgr.volumeHeat.Heat(vol.Vid)
+	}
+	sort.SliceStable(vols, func(i, j int) bool {
+		return heats[vols[i].Vid] < heats[vols[j].Vid]
+	})
+
+	migrateCnt := 0
+	for _, vol := range vols {
+		if !mgr.isSealed(vol) {
+			continue
+		}
+		disk, vuid, ok := mgr.selectHotVunit(vol, disks, coldIDCs)
+		if !ok {
+			continue
+		}
+		mgr.genOneColdMigrateTask(ctx, disk, vuid)
+
+		migrateCnt++
+		// scan the same volumes next time, the volume may still have units on normal disk
+		if migrateCnt >= needMigrateCnt {
+			return nil
+		}
+	}
+	mgr.marker = nextVid
+	return nil
+}
+
+// clusterDisks returns normal disks and idcs which has cold disks
+func (mgr *ColdMigrateMgr) clusterDisks() (map[proto.DiskID]*client.DiskInfoSimple, map[string]bool) {
+	disks := make(map[proto.DiskID]*client.DiskInfoSimple)
+	coldIDCs := make(map[string]bool)
+	for idcName := range mgr.clusterTopology.GetIDCs() {
+		for _, disk := range mgr.clusterTopology.GetIDCDisks(idcName) {
+			disks[disk.DiskID] = disk
+			if disk.Cold && disk.IsHealth() {
+				coldIDCs[disk.Idc] = true
+			}
+		}
+	}
+	return disks, coldIDCs
+}
+
+// isSealed returns true if the volume is no longer written and not hot,
+// hot volumes are kept on normal disks even if they are sealed.
+func (mgr *ColdMigrateMgr) isSealed(vol *client.VolumeInfoSimple) bool {
+	if !vol.IsIdle() || vol.Total == 0 {
+		return false
+	}
+	if mgr.volumeHeat.IsHot(vol.Vid) {
+		return false
+	}
+	return float64(vol.Free)/float64(vol.Total) <= mgr.cfg.MaxFreeRatio
+}
+
+func (mgr *ColdMigrateMgr) selectHotVunit(vol *client.VolumeInfoSimple, disks map[proto.DiskID]*client.DiskInfoSimple,
+	coldIDCs map[string]bool) (*client.DiskInfoSimple, proto.Vuid, bool) {
+	for _, location := range vol.VunitLocations {
+		disk, ok := disks[location.DiskID]
+		if !ok || disk.Cold || !disk.IsHealth() || !coldIDCs[disk.Idc] {
+			continue
+		}
+		if mgr.IMigrator.IsMigratingDisk(disk.DiskID) {
+			continue
+		}
+		return disk, location.Vuid, true
+	}
+	return nil, proto.InvalidVuid, false
+}
+
+func (mgr *ColdMigrateMgr) genOneColdMigrateTask(ctx context.Context, diskInfo *client.DiskInfoSimple, vuid proto.Vuid) {
+	span := trace.SpanFromContextSafe(ctx)
+
+	span.Debugf("select cold migrate volume unit; vuid[%d], volume_id[%v]", vuid, vuid.Vid())
+	task := &proto.MigrateTask{
+		TaskID:       client.GenMigrateTaskID(proto.TaskTypeColdMigrate, diskInfo.DiskID, vuid.Vid()),
+		TaskType:     proto.TaskTypeColdMigrate,
+		State:        proto.MigrateStateInited,
+		SourceIDC:    diskInfo.Idc,
+		SourceDiskID: diskInfo.DiskID,
+		SourceVuid:   vuid,
+	}
+	mgr.IMigrator.AddTask(ctx, task)
+}
+
+// checkAndClearJunkTasksLoop due to network timeout, it may still have some junk migrate tasks in clustermgr,
+// and we need to clear those tasks later
+func (mgr *ColdMigrateMgr) checkAndClearJunkTasksLoop() {
+	t := time.NewTicker(clearJunkMigrationTaskInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			mgr.checkAndClearJunkTasks()
+		case <-mgr.IMigrator.Done():
+			return
+		}
+	}
+}
+
+func (mgr *ColdMigrateMgr) checkAndClearJunkTasks() {
+	span, ctx := trace.StartSpanFromContext(context.Background(), "cold_migrate.clearJunkTasks")
+
+	for _, task := range mgr.DeletedTasks() {
+		if time.Since(task.DeletedTime) < junkMigrationTaskProtectionWindow {
+			continue
+		}
+		_, err := mgr.clusterMgrCli.GetMigrateTask(ctx, proto.TaskTypeColdMigrate, task.TaskID)
+		if err != nil {
+			if rpc.DetectStatusCode(err) != http.StatusNotFound {
+				span.Errorf("get cold migrate task from clustermanager failed: err[%+v]", err)
+				continue
+			}
+		} else {
+			span.Warnf("delete junk task: task_id[%s]", task.TaskID)
+			base.InsistOn(ctx, "delete junk task", func() error {
+				return mgr.clusterMgrCli.DeleteMigrateTask(ctx, task.TaskID)
+			})
+		}
+
+		mgr.ClearDeletedTaskByID(task.DiskID, task.TaskID)
+	}
+}
+
+// coldVunitAllocator alloc volume unit on cold disk
+type coldVunitAllocator struct {
+	cli client.ClusterMgrAPI
+}
+
+func (a *coldVunitAllocator) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*client.AllocVunitInfo, error) {
+	return a.cli.AllocColdVolumeUnit(ctx, vuid)
+}
+
+// newVunitAllocator returns volume unit allocator of the task type,
+// destination of cold migrate task should always be on cold disk
+func newVunitAllocator(cli client.ClusterMgrAPI, taskType proto.TaskType) base.IAllocVunit {
+	if taskType == proto.TaskTypeColdMigrate {
+		return &coldVunitAllocator{cli: cli}
+	}
+	return cli
+}
@@ -0,0 +1,219 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/codemode"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/scheduler/base"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+	"github.com/cubefs/cubefs/blobstore/testing/mocks"
+	"github.com/cubefs/cubefs/blobstore/util/closer"
+)
+
+func newColdMigrater(t *testing.T) *ColdMigrateMgr {
+	ctr := gomock.NewController(t)
+	clusterMgr := NewMockClusterMgrAPI(ctr)
+	volumeUpdater := NewMockVolumeUpdater(ctr)
+	taskSwitch := mocks.NewMockSwitcher(ctr)
+	topologyMgr := NewMockClusterTopology(ctr)
+	taskLogger := mocks.NewMockRecordLogEncoder(ctr)
+	migrater := NewMockMigrater(ctr)
+	conf := &ColdMigrateConfig{MaxFreeRatio: 0.1, ListVolStep: 10}
+	c := closer.New()
+
+	migrater.EXPECT().StatQueueTaskCnt().AnyTimes().Return(0, 0, 0)
+	migrater.EXPECT().Close().AnyTimes().DoAndReturn(c.Close)
+	migrater.EXPECT().Done().AnyTimes().Return(c.Done())
+	migrater.EXPECT().WaitEnable().AnyTimes().Return()
+	migrater.EXPECT().Enabled().AnyTimes().Return(true)
+
+	mgr := NewColdMigrateMgr(clusterMgr, volumeUpdater, taskSwitch, topologyMgr, NewVolumeHeatMgr(nil, &VolumeHeatConfig{}), taskLogger, conf)
+	mgr.IMigrator = migrater
+	return mgr
+}
+
+func mockColdTopology(vol *client.VolumeInfoSimple, coldCnt int) *ClusterTopologyMgr {
+	var disks []*client.DiskInfoSimple
+	for i, location := range vol.VunitLocations {
+		disks = append(disks, &client.DiskInfoSimple{
+			ClusterID:    1,
+			Idc:          "z0",
+			Rack:         "rack1",
+			Host:         location.Host,
+			Status:       proto.DiskStatusNormal,
+			DiskID:       location.DiskID,
+			FreeChunkCnt: 10,
+			MaxChunkCnt:  700,
+			Cold:         i < coldCnt,
+		})
+	}
+	clusterTopMgr := &ClusterTopologyMgr{
+		taskStatsMgr: base.NewClusterTopologyStatisticsMgr(1, []float64{}),
+	}
+	clusterTopMgr.buildClusterTopology(disks, 1)
+	return clusterTopMgr
+}
+
+func TestColdMigrateLoad(t *testing.T) {
+	mgr := newColdMigrater(t)
+	mgr.IMigrator.(*MockMigrater).EXPECT().Load().Return(nil)
+	err := mgr.Load()
+	require.NoError(t, err)
+}
+
+func TestColdMigrateRun(t *testing.T) {
+	mgr := newColdMigrater(t)
+	defer mgr.Close()
+
+	mgr.IMigrator.(*MockMigrater).EXPECT().Run().Return()
+	mgr.IMigrator.(*MockMigrater).EXPECT().GetMigratingDiskNum().AnyTimes().Return(1)
+	mgr.cfg.CollectTaskIntervalS = 1
+	mgr.cfg.CheckTaskIntervalS = 1
+	require.True(t, mgr.Enabled())
+	mgr.Run()
+
+	time.Sleep(1 * time.Second)
+}
+
+func TestColdMigrateCollectionTask(t *testing.T) {
+	ctx := context.Background()
+	{
+		mgr := newColdMigrater(t)
+		mgr.IMigrator.(*MockMigrater).EXPECT().GetMigratingDiskNum().AnyTimes().Return(1)
+
+		err := mgr.collectionTask()
+		require.True(t, errors.Is(err, ErrTooManyColdMigratingTasks))
+	}
+	{
+		mgr := newColdMigrater(t)
+		mgr.cfg.DiskConcurrency = 2
+		mgr.IMigrator.(*MockMigrater).EXPECT().GetMigratingDiskNum().AnyTimes().Return(0)
+		mgr.IMigrator.(*MockMigrater).EXPECT().IsMigratingDisk(any).AnyTimes().Return(false)
+
+		volume := MockGenVolInfo(10000, codemode.EC6P6, proto.VolumeStatusIdle)
+		volume.Total, volume.Free = 100, 5
+
+		// no cold disk
+		mgr.clusterTopology = mockColdTopology(volume, 0)
+		err := mgr.collectionTask()
+		require.True(t, errors.Is(err, ErrNoColdDisk))
+
+		// list volume failed
+		mgr.clusterTopology = mockColdTopology(volume, 2)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolume(any, any, any).Return(nil, proto.Vid(0), errMock)
+		err = mgr.collectionTask()
+		require.True(t, errors.Is(err, errMock))
+
+		// volume is not sealed
+		active := MockGenVolInfo(10001, codemode.EC6P6, proto.VolumeStatusActive)
+		notFull := MockGenVolInfo(10002, codemode.EC6P6, proto.VolumeStatusIdle)
+		notFull.Total, notFull.Free = 100, 50
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolume(any, any, any).Return(
+			[]*client.VolumeInfoSimple{active, notFull}, proto.Vid(10002), nil)
+		err = mgr.collectionTask()
+		require.NoError(t, err)
+		require.Equal(t, proto.Vid(10002), mgr.marker)
+
+		// hot volume is kept on normal disks
+		mgr.volumeHeat = newHotVolumeHeat(volume.Vid)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolume(any, any, any).Return(
+			[]*client.VolumeInfoSimple{volume}, proto.Vid(10000), nil)
+		err = mgr.collectionTask()
+		require.NoError(t, err)
+		mgr.volumeHeat = newHotVolumeHeat()
+
+		// select the first unit not on cold disk
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolume(any, any, any).Return(
+			[]*client.VolumeInfoSimple{volume}, proto.Vid(10000), nil)
+		mgr.IMigrator.(*MockMigrater).EXPECT().AddTask(any, any).DoAndReturn(func(_ context.Context, task *proto.MigrateTask) {
+			require.Equal(t, proto.TaskTypeColdMigrate, task.TaskType)
+			require.Equal(t, volume.VunitLocations[2].Vuid, task.SourceVuid)
+			require.Equal(t, volume.VunitLocations[2].DiskID, task.SourceDiskID)
+		})
+		err = mgr.collectionTask()
+		require.NoError(t, err)
+		require.Equal(t, proto.Vid(10000), mgr.marker)
+
+		// scan finished
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolume(any, any, any).Return(nil, proto.Vid(0), nil)
+		err = mgr.collectionTask()
+		require.True(t, errors.Is(err, errColdScanFinished))
+		require.Equal(t, proto.Vid(0), mgr.marker)
+	}
+	{
+		// all units on cold disk
+		mgr := newColdMigrater(t)
+		mgr.cfg.DiskConcurrency = 1
+		mgr.IMigrator.(*MockMigrater).EXPECT().GetMigratingDiskNum().AnyTimes().Return(0)
+		mgr.IMigrator.(*MockMigrater).EXPECT().IsMigratingDisk(any).AnyTimes().Return(false)
+
+		volume := MockGenVolInfo(10000, codemode.EC6P6, proto.VolumeStatusIdle)
+		volume.Total, volume.Free = 100, 0
+		mgr.clusterTopology = mockColdTopology(volume, len(volume.VunitLocations))
+		disks, coldIDCs := mgr.clusterDisks()
+		_, _, ok := mgr.selectHotVunit(volume, disks, coldIDCs)
+		require.False(t, ok)
+	}
+	{
+		// cold migrate task should alloc volume unit on cold disk
+		mgr := newColdMigrater(t)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().AllocColdVolumeUnit(any, any).Return(&client.AllocVunitInfo{}, nil)
+		_, err := newVunitAllocator(mgr.clusterMgrCli, proto.TaskTypeColdMigrate).AllocVolumeUnit(ctx, proto.Vuid(1))
+		require.NoError(t, err)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().AllocVolumeUnit(any, any).Return(&client.AllocVunitInfo{}, nil)
+		_, err = newVunitAllocator(mgr.clusterMgrCli, proto.TaskTypeBalance).AllocVolumeUnit(ctx, proto.Vuid(1))
+		require.NoError(t, err)
+	}
+}
+
+func TestColdMigrateCheckAndClearJunkTasks(t *testing.T) {
+	{
+		mgr := newColdMigrater(t)
+		mgr.IMigrator.(*MockMigrater).EXPECT().DeletedTasks().Return([]DeletedTask{
+			{DiskID: proto.DiskID(1), TaskID: xid.New().String(), DeletedTime: time.Now()},
+		})
+		mgr.checkAndClearJunkTasks()
+	}
+	{
+		mgr := newColdMigrater(t)
+		mgr.IMigrator.(*MockMigrater).EXPECT().DeletedTasks().Return([]DeletedTask{
+			{DiskID: proto.DiskID(1), TaskID: xid.New().String(), DeletedTime: time.Now().Add(-junkMigrationTaskProtectionWindow)},
+		})
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().GetMigrateTask(any, any, any).Return(nil, errcode.ErrNotFound)
+		mgr.IMigrator.(*MockMigrater).EXPECT().ClearDeletedTaskByID(any, any).Return()
+		mgr.checkAndClearJunkTasks()
+	}
+	{
+		mgr := newColdMigrater(t)
+		mgr.IMigrator.(*MockMigrater).EXPECT().DeletedTasks().Return([]DeletedTask{
+			{DiskID: proto.DiskID(1), TaskID: xid.New().String(), DeletedTime: time.Now().Add(-junkMigrationTaskProtectionWindow)},
+		})
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().GetMigrateTask(any, any, any).Return(&proto.MigrateTask{}, nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().DeleteMigrateTask(any, any).Return(nil)
+		mgr.IMigrator.(*MockMigrater).EXPECT().ClearDeletedTaskByID(any, any).Return()
+		mgr.checkAndClearJunkTasks()
+	}
+}
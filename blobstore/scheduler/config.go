@@ -24,7 +24,9 @@ import (
 	"github.com/cubefs/cubefs/blobstore/cmd"
 	"github.com/cubefs/cubefs/blobstore/common/kafka"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/recordlog"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
 	"github.com/cubefs/cubefs/blobstore/util/defaulter"
 )
 
@@ -38,6 +40,10 @@ const (
 	defaultMaxDiskFreeChunkCnt = int64(1024)
 	defaultMinDiskFreeChunkCnt = int64(20)
 
+	defaultColdMaxFreeRatio  = 0.05
+	defaultColdListVolStep   = 100
+	defaultColdScanIntervalS = 3600
+
 	defaultInspectIntervalS  = 1
 	defaultListVolIntervalMs = 10
 	defaultListVolStep       = 100
@@ -67,6 +73,18 @@ const (
 
 	defaultBlobDeleteNormalTopic = "blob_delete"
 	defaultBlobDeleteFailedTopic = "blob_delete_failed"
+
+	defaultVolumeCacheTTLMs = int64(1000)
+	defaultDiskCacheTTLMs   = int64(3000)
+
+	defaultHeatHalfLifeS         = 3600
+	defaultHeatHotThreshold      = 100.0
+	defaultHeatSnapshotIntervalS = 60
+
+	defaultQuarantineFailureWindowS = 600
+	defaultQuarantineS              = 1800
+
+	defaultTaskChunkSizeMB = 16 << 10
 )
 
 // Config service config
@@ -75,28 +93,48 @@ type Config struct {
 
 	ClusterID proto.ClusterID `json:"cluster_id"`
 	Services  Services        `json:"services"`
+	// grpc port of task protocol with worker, grpc server is disabled if 0
+	GrpcPort int `json:"grpc_port"`
 
 	TopologyUpdateIntervalMin  int       `json:"topology_update_interval_min"`
 	VolumeCacheUpdateIntervalS int       `json:"volume_cache_update_interval_s"`
 	FreeChunkCounterBuckets    []float64 `json:"free_chunk_counter_buckets"`
 
-	ClusterMgr clustermgr.Config `json:"clustermgr"`
-	Proxy      proxy.LbConfig    `json:"proxy"`
-	Blobnode   blobnode.Config   `json:"blobnode"`
-	Scheduler  scheduler.Config  `json:"scheduler"`
+	ClusterMgr      clustermgr.Config  `json:"clustermgr"`
+	ClusterMgrCache client.CacheConfig `json:"clustermgr_cache"`
+	Proxy           proxy.LbConfig     `json:"proxy"`
+	Blobnode        blobnode.Config    `json:"blobnode"`
+	Scheduler       scheduler.Config   `json:"scheduler"`
 
 	Balance       BalanceMgrConfig    `json:"balance"`
 	DiskDrop      DropMgrConfig       `json:"disk_drop"`
 	DiskRepair    MigrateConfig       `json:"disk_repair"`
 	ManualMigrate MigrateConfig       `json:"manual_migrate"`
+	ColdMigrate   ColdMigrateConfig   `json:"cold_migrate"`
 	VolumeInspect VolumeInspectMgrCfg `json:"volume_inspect"`
 	TaskLog       recordlog.Config    `json:"task_log"`
+	// DestQuarantine quarantine of destination disks shared by all migrate tasks
+	DestQuarantine DestQuarantineConfig `json:"dest_quarantine"`
+	// TaskBandwidth caps bandwidth of migrate tasks dispatched to workers, except disk repair
+	TaskBandwidth TaskBandwidthConfig `json:"task_bandwidth"`
 
 	Kafka       KafkaConfig       `json:"kafka"`
 	ShardRepair ShardRepairConfig `json:"shard_repair"`
 	BlobDelete  BlobDeleteConfig  `json:"blob_delete"`
+	VolumeHeat  VolumeHeatConfig  `json:"volume_heat"`
 
 	ServiceRegister ServiceRegisterConfig `json:"service_register"`
+
+	// TaskKVPath tasks are stored in the local file instead of clustermgr kv if not empty,
+	// only for testing a standalone scheduler
+	TaskKVPath string `json:"task_kv_path"`
+}
+
+// TaskBandwidthConfig bandwidth cap of dispatching migrate tasks, rate and burst are in MB/s,
+// one task costs the chunk size, no limit if rate is 0
+type TaskBandwidthConfig struct {
+	ratelimit.Config
+	ChunkSizeMB int `json:"chunk_size_mb"`
 }
 
 // ServiceRegisterConfig is service register info
@@ -129,6 +167,8 @@ type Topics struct {
 	ShardRepairFailed string   `json:"shard_repair_failed"`
 	BlobDelete        string   `json:"blob_delete"`
 	BlobDeleteFailed  string   `json:"blob_delete_failed"`
+	// VolumeHeat topic of read events sampled by access, empty means volume heat is disabled
+	VolumeHeat string `json:"volume_heat"`
 }
 
 // KafkaConfig kafka config
@@ -194,11 +234,17 @@ func (c *Config) fixConfig() (err error) {
 	c.fixDiskDropConfig()
 	c.fixDiskRepairConfig()
 	c.fixManualMigrateConfig()
+	c.fixColdMigrateConfig()
+	c.fixDestQuarantineConfig()
+	defaulter.LessOrEqual(&c.TaskBandwidth.ChunkSizeMB, defaultTaskChunkSizeMB)
 	c.fixInspectConfig()
 	c.fixShardRepairConfig()
 	if err := c.fixBlobDeleteConfig(); err != nil {
 		return err
 	}
+	if err := c.fixVolumeHeatConfig(); err != nil {
+		return err
+	}
 	c.fixRegisterConfig()
 	return nil
 }
@@ -210,6 +256,8 @@ func (c *Config) fixClientConfig() {
 	defaulter.LessOrEqual(&c.Blobnode.ClientTimeoutMs, defaultClientTimeoutMs)
 	defaulter.LessOrEqual(&c.Scheduler.ClientTimeoutMs, defaultClientTimeoutMs)
 	defaulter.LessOrEqual(&c.Scheduler.HostRetry, defaultRetryHostsCnt)
+	defaulter.Equal(&c.ClusterMgrCache.VolumeTTLMs, defaultVolumeCacheTTLMs)
+	defaulter.Equal(&c.ClusterMgrCache.DiskTTLMs, defaultDiskCacheTTLMs)
 }
 
 func (c *Config) fixKafkaConfig() (err error) {
@@ -254,6 +302,19 @@ func (c *Config) fixManualMigrateConfig() {
 	c.ManualMigrate.CheckAndFix()
 }
 
+func (c *Config) fixColdMigrateConfig() {
+	c.ColdMigrate.ClusterID = c.ClusterID
+	defaulter.LessOrEqual(&c.ColdMigrate.MaxFreeRatio, defaultColdMaxFreeRatio)
+	defaulter.LessOrEqual(&c.ColdMigrate.ListVolStep, defaultColdListVolStep)
+	defaulter.LessOrEqual(&c.ColdMigrate.ScanIntervalS, defaultColdScanIntervalS)
+	c.ColdMigrate.CheckAndFix()
+}
+
+func (c *Config) fixDestQuarantineConfig() {
+	defaulter.LessOrEqual(&c.DestQuarantine.FailureWindowS, defaultQuarantineFailureWindowS)
+	defaulter.LessOrEqual(&c.DestQuarantine.QuarantineS, defaultQuarantineS)
+}
+
 func (c *Config) fixInspectConfig() {
 	defaulter.LessOrEqual(&c.VolumeInspect.TimeoutMs, defaultInspectTimeoutMs)
 	defaulter.LessOrEqual(&c.VolumeInspect.ListVolStep, defaultListVolStep)
@@ -301,6 +362,20 @@ func (c *Config) fixBlobDeleteConfig() error {
 	return nil
 }
 
+func (c *Config) fixVolumeHeatConfig() error {
+	if !c.VolumeHeat.PeakHours.Valid() {
+		return errInvalidHourRange
+	}
+	defaulter.LessOrEqual(&c.VolumeHeat.HalfLifeS, defaultHeatHalfLifeS)
+	defaulter.LessOrEqual(&c.VolumeHeat.HotThreshold, defaultHeatHotThreshold)
+	defaulter.LessOrEqual(&c.VolumeHeat.MaxBatchSize, defaultMaxBatchSize)
+	defaulter.LessOrEqual(&c.VolumeHeat.BatchIntervalS, defaultBatchIntervalSec)
+	defaulter.LessOrEqual(&c.VolumeHeat.SnapshotIntervalS, defaultHeatSnapshotIntervalS)
+	c.VolumeHeat.Topic = c.Kafka.Topics.VolumeHeat
+	c.VolumeHeat.ClusterID = c.ClusterID
+	return nil
+}
+
 func (c *Config) fixRegisterConfig() {
 	defaulter.LessOrEqual(&c.ServiceRegister.TickInterval, defaultTickInterval)
 	defaulter.LessOrEqual(&c.ServiceRegister.HeartbeatTicks, defaultHeartbeatTicks)
@@ -57,6 +57,8 @@ func TestConfigCheckAndFix(t *testing.T) {
 	require.Equal(t, "127.0.0.1:9800", cfg.Leader())
 	require.Nil(t, cfg.Follower())
 	require.Equal(t, defaultDeleteDelayH, cfg.BlobDelete.SafeDelayTimeH)
+	require.Equal(t, defaultVolumeCacheTTLMs, cfg.ClusterMgrCache.VolumeTTLMs)
+	require.Equal(t, defaultDiskCacheTTLMs, cfg.ClusterMgrCache.DiskTTLMs)
 	require.Equal(t, sarama.V2_1_0_0, kafka.DefaultKafkaVersion)
 	cfg.Services.Members[2] = "127.0.0.1:9880"
 	require.Equal(t, "127.0.0.1:9880", cfg.Follower()[0])
@@ -68,6 +70,12 @@ func TestConfigCheckAndFix(t *testing.T) {
 	require.Equal(t, defaultDeleteNoDelay, cfg.BlobDelete.SafeDelayTimeH)
 	require.Equal(t, defaultDeleteHourRangeTo, cfg.BlobDelete.DeleteHourRange.To)
 
+	// negative ttl disables the cache
+	cfg.ClusterMgrCache.VolumeTTLMs = -1
+	err = cfg.fixConfig()
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), cfg.ClusterMgrCache.VolumeTTLMs)
+
 	testCases := []struct {
 		hourRange HourRange
 		err       error
@@ -94,4 +102,12 @@ func TestConfigCheckAndFix(t *testing.T) {
 		err = cfg.fixConfig()
 		require.True(t, errors.Is(err, test.err))
 	}
+
+	cfg.BlobDelete.DeleteHourRange = HourRange{}
+	cfg.Kafka.Topics.VolumeHeat = "blob_read"
+	require.NoError(t, cfg.fixConfig())
+	require.Equal(t, "blob_read", cfg.VolumeHeat.Topic)
+	require.Equal(t, defaultHeatHalfLifeS, cfg.VolumeHeat.HalfLifeS)
+	cfg.VolumeHeat.PeakHours = HourRange{From: 20, To: 8}
+	require.True(t, errors.Is(cfg.fixConfig(), errInvalidHourRange))
 }
@@ -0,0 +1,152 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/scheduler/base"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+// DestQuarantineConfig destination disk is quarantined for QuarantineS seconds
+// if its tasks failed FailureThreshold times within FailureWindowS seconds
+type DestQuarantineConfig struct {
+	// 0 disables the quarantine
+	FailureThreshold int `json:"failure_threshold"`
+	FailureWindowS   int `json:"failure_window_s"`
+	QuarantineS      int `json:"quarantine_s"`
+}
+
+// destQuarantine tracks the destination disks whose tasks are reclaimed or redone,
+// the quarantined disks are avoided when alloc volume unit for tasks of all types,
+// nil destQuarantine means the quarantine is disabled
+type destQuarantine struct {
+	sync.Mutex
+
+	threshold  int
+	window     time.Duration
+	duration   time.Duration
+	failures   map[proto.DiskID][]time.Time
+	quarantine map[proto.DiskID]time.Time // disk id to the time quarantine is lifted
+}
+
+func newDestQuarantine(cfg *DestQuarantineConfig) *destQuarantine {
+	if cfg.FailureThreshold <= 0 {
+		return nil
+	}
+	return &destQuarantine{
+		threshold:  cfg.FailureThreshold,
+		window:     time.Duration(cfg.FailureWindowS) * time.Second,
+		duration:   time.Duration(cfg.QuarantineS) * time.Second,
+		failures:   make(map[proto.DiskID][]time.Time),
+		quarantine: make(map[proto.DiskID]time.Time),
+	}
+}
+
+// fail records a failed task on the destination disk
+func (q *destQuarantine) fail(ctx context.Context, diskID proto.DiskID) {
+	if q == nil || diskID == proto.InvalidDiskID {
+		return
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	now := time.Now()
+	if until, ok := q.quarantine[diskID]; ok && now.Before(until) {
+		return
+	}
+
+	failures := q.failures[diskID][:0]
+	for _, t := range q.failures[diskID] {
+		if now.Sub(t) < q.window {
+			failures = append(failures, t)
+		}
+	}
+	failures = append(failures, now)
+	if len(failures) < q.threshold {
+		q.failures[diskID] = failures
+		return
+	}
+
+	delete(q.failures, diskID)
+	q.quarantine[diskID] = now.Add(q.duration)
+	span := trace.SpanFromContextSafe(ctx)
+	span.Warnf("quarantine destination disk: disk_id[%d], failures[%d], duration[%s]", diskID, len(failures), q.duration)
+}
+
+// disks returns the disks in quarantine
+func (q *destQuarantine) disks() []proto.DiskID {
+	if q == nil {
+		return nil
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	now := time.Now()
+	disks := make([]proto.DiskID, 0, len(q.quarantine))
+	for diskID, until := range q.quarantine {
+		if !now.Before(until) {
+			delete(q.quarantine, diskID)
+			continue
+		}
+		disks = append(disks, diskID)
+	}
+	if len(disks) == 0 {
+		return nil
+	}
+	sort.Slice(disks, func(i, j int) bool { return disks[i] < disks[j] })
+	return disks
+}
+
+// allocator returns volume unit allocator avoiding the quarantined disks and the excludes,
+// the allocator is returned as it is if the quarantine is disabled
+func (q *destQuarantine) allocator(cli client.ClusterMgrAPI, allocator base.IAllocVunit, excludes ...proto.DiskID) base.IAllocVunit {
+	if q == nil {
+		return allocator
+	}
+	a := &excludingVunitAllocator{IAllocVunit: allocator, cli: cli, excludes: excludes, quarantine: q}
+	if _, ok := allocator.(*coldVunitAllocator); ok {
+		a.args.Cold = true
+	}
+	return a
+}
+
+// excludingVunitAllocator alloc volume unit avoiding the disks in preference,
+// the allocator is used directly if there is no disk to avoid
+type excludingVunitAllocator struct {
+	base.IAllocVunit
+	cli        client.ClusterMgrAPI
+	args       cmapi.AllocVolumeUnitArgs
+	excludes   []proto.DiskID
+	quarantine *destQuarantine
+}
+
+func (a *excludingVunitAllocator) AllocVolumeUnit(ctx context.Context, vuid proto.Vuid) (*client.AllocVunitInfo, error) {
+	excludes := append(a.quarantine.disks(), a.excludes...)
+	if len(excludes) == 0 {
+		return a.IAllocVunit.AllocVolumeUnit(ctx, vuid)
+	}
+	args := a.args
+	args.Vuid = vuid
+	args.ExcludeDisks = excludes
+	return a.cli.AllocVolumeUnitWithArgs(ctx, &args)
+}
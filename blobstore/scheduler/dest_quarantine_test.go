@@ -0,0 +1,100 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+func TestDestQuarantine(t *testing.T) {
+	ctx := context.Background()
+
+	// disabled
+	var q *destQuarantine
+	require.Nil(t, newDestQuarantine(&DestQuarantineConfig{}))
+	q.fail(ctx, 1)
+	require.Nil(t, q.disks())
+
+	q = newDestQuarantine(&DestQuarantineConfig{FailureThreshold: 2, FailureWindowS: 60, QuarantineS: 60})
+	q.fail(ctx, proto.InvalidDiskID)
+	q.fail(ctx, proto.InvalidDiskID)
+	q.fail(ctx, 3)
+	q.fail(ctx, 2)
+	require.Nil(t, q.disks())
+	q.fail(ctx, 3)
+	q.fail(ctx, 2)
+	require.Equal(t, []proto.DiskID{2, 3}, q.disks())
+
+	// failures out of window
+	q.window = 0
+	q.fail(ctx, 4)
+	q.fail(ctx, 4)
+	require.Equal(t, []proto.DiskID{2, 3}, q.disks())
+
+	// quarantine lifted
+	q.window = time.Minute
+	q.duration = 10 * time.Millisecond
+	q.fail(ctx, 5)
+	q.fail(ctx, 5)
+	require.Equal(t, []proto.DiskID{2, 3, 5}, q.disks())
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, []proto.DiskID{2, 3}, q.disks())
+}
+
+func TestDestQuarantineAllocator(t *testing.T) {
+	ctx := context.Background()
+	ctr := gomock.NewController(t)
+	cli := NewMockClusterMgrAPI(ctr)
+	vuid := proto.Vuid(100)
+
+	// quarantine disabled
+	var q *destQuarantine
+	allocator := newVunitAllocator(cli, proto.TaskTypeBalance)
+	require.Equal(t, allocator, q.allocator(cli, allocator, 1))
+
+	// no disk to avoid
+	q = newDestQuarantine(&DestQuarantineConfig{FailureThreshold: 1, FailureWindowS: 60, QuarantineS: 60})
+	cli.EXPECT().AllocVolumeUnit(any, any).Return(&client.AllocVunitInfo{}, nil)
+	_, err := q.allocator(cli, allocator).AllocVolumeUnit(ctx, vuid)
+	require.NoError(t, err)
+
+	q.fail(ctx, 2)
+	cli.EXPECT().AllocVolumeUnitWithArgs(any, any).DoAndReturn(
+		func(_ context.Context, args *cmapi.AllocVolumeUnitArgs) (*client.AllocVunitInfo, error) {
+			require.Equal(t, cmapi.AllocVolumeUnitArgs{Vuid: vuid, ExcludeDisks: []proto.DiskID{2, 1}}, *args)
+			return &client.AllocVunitInfo{}, nil
+		})
+	_, err = q.allocator(cli, allocator, 1).AllocVolumeUnit(ctx, vuid)
+	require.NoError(t, err)
+
+	// keep args of the allocator
+	cli.EXPECT().AllocVolumeUnitWithArgs(any, any).DoAndReturn(
+		func(_ context.Context, args *cmapi.AllocVolumeUnitArgs) (*client.AllocVunitInfo, error) {
+			require.True(t, args.Cold)
+			require.Equal(t, []proto.DiskID{2}, args.ExcludeDisks)
+			return &client.AllocVunitInfo{}, nil
+		})
+	_, err = q.allocator(cli, newVunitAllocator(cli, proto.TaskTypeColdMigrate)).AllocVolumeUnit(ctx, vuid)
+	require.NoError(t, err)
+}
@@ -151,6 +151,10 @@ type DiskDropMgr struct {
 	clusterMgrCli client.ClusterMgrAPI
 	topologyMgr   IClusterTopology
 
+	// draining hosts
+	drainsMu sync.Mutex
+	drains   map[string]*client.HostDrainMeta
+
 	cfg *DropMgrConfig
 }
 
@@ -164,6 +168,7 @@ func NewDiskDropMgr(clusterMgrCli client.ClusterMgrAPI, volumeUpdater client.IVo
 		allDisks:       newDropDiskMap(),
 		droppedDisks:   newMigratedDisks(),
 		collectedDisks: newDropDiskMap(),
+		drains:         make(map[string]*client.HostDrainMeta),
 
 		totalTaskLimit:   count.NewBlockingCount(conf.TotalTaskLimit),
 		taskLimitPerDisk: keycount.NewBlockingKeyCountLimit(conf.TaskLimitPerDisk),
@@ -212,6 +217,9 @@ func (mgr *DiskDropMgr) Load() (err error) {
 		}
 	}
 
+	if err = mgr.loadHostDrains(ctx); err != nil {
+		return err
+	}
 	return mgr.IMigrator.Load()
 }
 
@@ -296,6 +304,8 @@ func (mgr *DiskDropMgr) collectTask() {
 			dDisk.setCollecting(false)
 		})
 	}
+
+	mgr.drainHosts(ctx)
 }
 
 func (mgr *DiskDropMgr) releaseTaskLimit(diskID proto.DiskID) {
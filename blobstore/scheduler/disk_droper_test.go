@@ -73,6 +73,7 @@ func TestDiskDropLoad(t *testing.T) {
 		volume.VunitLocations[0].Vuid = 0
 		mgr.topologyMgr.(*MockClusterTopology).EXPECT().GetVolume(any).Times(1).Return(volume, nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().DeleteMigrateTask(any, t1.TaskID).Times(1).Return(nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().GetHostDrains(any).Return(nil, nil)
 		mgr.IMigrator.(*MockMigrater).EXPECT().Load().Return(nil)
 		err := mgr.Load()
 		require.NoError(t, err)
@@ -36,6 +36,7 @@ import (
 // DiskRepairMgr repair task manager
 type DiskRepairMgr struct {
 	closer.Closer
+	*queueParams
 
 	prepareQueue   *base.TaskQueue
 	workQueue      *base.WorkerTaskQueue
@@ -43,6 +44,9 @@ type DiskRepairMgr struct {
 	deletedTasks   *diskMigratedTasks
 	repairedDisks  *migratedDisks
 	repairingDisks *migratingDisks
+	lostShards     *lostShardsRanker
+	// notified by clustermgr when any disk becomes broken
+	brokenNotify chan struct{}
 
 	clusterMgrCli client.ClusterMgrAPI
 
@@ -51,6 +55,8 @@ type DiskRepairMgr struct {
 	// for stats
 	finishTaskCounter counter.Counter
 	taskStatsMgr      *base.TaskStatsMgr
+	slowTasks         *slowTaskChecker
+	corruptedTasks    []string // corrupted tasks skipped when loading, kept in db for checking
 
 	hasRevised bool
 	taskLogger recordlog.Encoder
@@ -62,11 +68,13 @@ func NewDiskRepairMgr(clusterMgrCli client.ClusterMgrAPI, taskSwitch taskswitch.
 	mgr := &DiskRepairMgr{
 		Closer:         closer.New(),
 		prepareQueue:   base.NewTaskQueue(time.Duration(cfg.PrepareQueueRetryDelayS) * time.Second),
-		workQueue:      base.NewWorkerTaskQueue(time.Duration(cfg.CancelPunishDurationS) * time.Second),
+		workQueue:      base.NewWorkerTaskQueue(time.Duration(cfg.CancelPunishDurationS)*time.Second, time.Duration(cfg.TaskLeaseExpiredS)*time.Second),
 		finishQueue:    base.NewTaskQueue(time.Duration(cfg.FinishQueueRetryDelayS) * time.Second),
 		deletedTasks:   newDiskMigratedTasks(),
 		repairedDisks:  newMigratedDisks(),
 		repairingDisks: newMigratingDisks(),
+		lostShards:     newLostShardsRanker(),
+		brokenNotify:   make(chan struct{}, 1),
 
 		clusterMgrCli: clusterMgrCli,
 		taskSwitch:    taskSwitch,
@@ -75,7 +83,10 @@ func NewDiskRepairMgr(clusterMgrCli client.ClusterMgrAPI, taskSwitch taskswitch.
 
 		hasRevised: false,
 	}
+	mgr.queueParams = newQueueParams(proto.TaskTypeDiskRepair, &cfg.TaskCommonConfig, mgr.prepareQueue, mgr.workQueue, mgr.finishQueue)
 	mgr.taskStatsMgr = base.NewTaskStatsMgrAndRun(cfg.ClusterID, proto.TaskTypeDiskRepair, mgr)
+	mgr.slowTasks = newSlowTaskChecker(proto.TaskTypeDiskRepair, &cfg.TaskCommonConfig, mgr.workQueue, mgr.taskStatsMgr,
+		cfg.quarantine.allocator(clusterMgrCli, clusterMgrCli), mgr)
 	return mgr
 }
 
@@ -101,11 +112,17 @@ func (mgr *DiskRepairMgr) Load() error {
 	}
 
 	var junkTasks []*proto.MigrateTask
+	loadTasks := tasks[:0]
 	for _, t := range tasks {
 		if _, ok := mgr.repairingDisks.get(t.SourceDiskID); !ok {
 			junkTasks = append(junkTasks, t)
 			continue
 		}
+		loadTasks = append(loadTasks, t)
+	}
+	loadTasks, mgr.corruptedTasks = skipCorruptedTasks(ctx, loadTasks)
+
+	for _, t := range loadTasks {
 		if t.Running() {
 			err = base.VolTaskLockerInst().TryLock(ctx, t.Vid())
 			if err != nil {
@@ -120,12 +137,10 @@ func (mgr *DiskRepairMgr) Load() error {
 			mgr.prepareQueue.PushTask(t.TaskID, t)
 		case proto.MigrateStatePrepared:
 			mgr.workQueue.AddPreparedTask(t.SourceIDC, t.TaskID, t)
+			mgr.slowTasks.Start(t.SourceIDC, t.TaskID)
 		case proto.MigrateStateWorkCompleted:
 			mgr.finishQueue.PushTask(t.TaskID, t)
-		case proto.MigrateStateFinished, proto.MigrateStateFinishedInAdvance:
-			return fmt.Errorf("task should be deleted from db: task[%+v]", t)
-		default:
-			return fmt.Errorf("unexpect migrate state: task[%+v]", t)
+			mgr.slowTasks.Start(t.SourceIDC, t.TaskID)
 		}
 	}
 
@@ -159,10 +174,12 @@ func (mgr *DiskRepairMgr) clearJunkTasksWhenLoading(ctx context.Context, tasks [
 // Run run repair task includes collect/prepare/finish/check phase
 func (mgr *DiskRepairMgr) Run() {
 	go mgr.collectTaskLoop()
+	go mgr.watchBrokenDiskLoop()
 	go mgr.prepareTaskLoop()
 	go mgr.finishTaskLoop()
 	go mgr.checkRepairedAndClearLoop()
 	go mgr.checkAndClearJunkTasksLoop()
+	go mgr.slowTasks.checkLoop(mgr.Closer.Done())
 }
 
 func (mgr *DiskRepairMgr) Enabled() bool {
@@ -182,12 +199,57 @@ func (mgr *DiskRepairMgr) collectTaskLoop() {
 		case <-t.C:
 			mgr.WaitEnable()
 			mgr.collectTask()
+		case <-mgr.brokenNotify:
+			mgr.WaitEnable()
+			mgr.collectTask()
 		case <-mgr.Closer.Done():
 			return
 		}
 	}
 }
 
+// watchBrokenDiskLoop long polls clustermgr and collects task as soon as any disk becomes broken,
+// the periodic collecting still works if clustermgr does not support watching
+func (mgr *DiskRepairMgr) watchBrokenDiskLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-mgr.Closer.Done()
+		cancel()
+	}()
+
+	var version uint64
+	for {
+		select {
+		case <-mgr.Closer.Done():
+			return
+		default:
+		}
+
+		span, spanCtx := trace.StartSpanFromContext(ctx, "disk_repair.watchBrokenDisk")
+		newVersion, err := mgr.clusterMgrCli.WatchBrokenDisks(spanCtx, version)
+		if err != nil {
+			span.Warnf("watch broken disk failed: version[%d], err[%+v]", version, err)
+			span.Finish()
+			select {
+			case <-time.After(time.Duration(mgr.cfg.CollectTaskIntervalS) * time.Second):
+				continue
+			case <-mgr.Closer.Done():
+				return
+			}
+		}
+		// a smaller version is only adopted, which may come from a clustermgr node lagging behind
+		if newVersion > version {
+			span.Infof("broken disk changed: version[%d], new version[%d]", version, newVersion)
+			select {
+			case mgr.brokenNotify <- struct{}{}:
+			default:
+			}
+		}
+		version = newVersion
+		span.Finish()
+	}
+}
+
 func (mgr *DiskRepairMgr) collectTask() {
 	span, ctx := trace.StartSpanFromContext(context.Background(), "disk_repair.collectTask")
 	defer span.Finish()
@@ -200,16 +262,21 @@ func (mgr *DiskRepairMgr) collectTask() {
 		mgr.hasRevised = true
 	}
 
-	if mgr.repairingDisks.size() >= mgr.cfg.DiskConcurrency {
+	brokenDisks, err := mgr.clusterMgrCli.ListBrokenDisks(ctx)
+	if err != nil {
+		span.Info("acquire broken disk failed: err[%+v]", err)
 		return
 	}
+	// rank volumes by lost shards on all broken disks, including the ones waiting to be repaired
+	if err = mgr.refreshLostShards(ctx, append(mgr.repairingDisks.list(), brokenDisks...)); err != nil {
+		span.Warnf("rank volumes by lost shards failed: err[%+v]", err)
+	}
 
-	brokenDisk, err := mgr.acquireBrokenDisk(ctx)
-	if err != nil {
-		span.Info("acquire broken disk failed: err[%+v]", err)
+	if mgr.repairingDisks.size() >= mgr.cfg.DiskConcurrency {
 		return
 	}
 
+	brokenDisk := mgr.getUnRepairingDisk(brokenDisks)
 	if brokenDisk == nil {
 		return
 	}
@@ -262,24 +329,18 @@ func (mgr *DiskRepairMgr) reviseRepairDisk(ctx context.Context, diskID proto.Dis
 	return nil
 }
 
-func (mgr *DiskRepairMgr) acquireBrokenDisk(ctx context.Context) (*client.DiskInfoSimple, error) {
-	repairingDisks, err := mgr.clusterMgrCli.ListBrokenDisks(ctx)
-	if err != nil {
-		return nil, err
-	}
-	if len(repairingDisks) == 0 {
-		return nil, nil
-	}
-	return mgr.getUnRepairingDisk(repairingDisks), nil
-}
-
-func (mgr *DiskRepairMgr) getUnRepairingDisk(disks []*client.DiskInfoSimple) *client.DiskInfoSimple {
+// getUnRepairingDisk returns the disk holding the volume lost the most shards, or the first one
+func (mgr *DiskRepairMgr) getUnRepairingDisk(disks []*client.DiskInfoSimple) (disk *client.DiskInfoSimple) {
+	priority := 0
 	for _, v := range disks {
-		if _, ok := mgr.repairingDisks.get(v.DiskID); !ok {
-			return v
+		if _, ok := mgr.repairingDisks.get(v.DiskID); ok {
+			continue
+		}
+		if p := mgr.lostShards.diskPriority(v.DiskID); disk == nil || p > priority {
+			disk, priority = v, p
 		}
 	}
-	return nil
+	return disk
 }
 
 func (mgr *DiskRepairMgr) genDiskRepairTasks(ctx context.Context, disk *client.DiskInfoSimple, newRepairDisk bool) error {
@@ -363,7 +424,7 @@ func (mgr *DiskRepairMgr) prepareTaskLoop() {
 	for {
 		mgr.WaitEnable()
 		todo, doing := mgr.workQueue.StatsTasks()
-		if mgr.repairingDisks.size() == 0 || todo+doing >= mgr.cfg.WorkQueueSize {
+		if mgr.repairingDisks.size() == 0 || todo+doing >= mgr.workQueueSize() {
 			time.Sleep(time.Second)
 			continue
 		}
@@ -418,6 +479,23 @@ func (mgr *DiskRepairMgr) popTaskAndPrepare() error {
 	return nil
 }
 
+// refreshLostShards ranks volumes by lost shards on the disks, and reindexes the prepare queue
+// once the ranks changed so that the task of volume lost the most shards is popped first
+func (mgr *DiskRepairMgr) refreshLostShards(ctx context.Context, disks []*client.DiskInfoSimple) error {
+	changed, err := mgr.lostShards.refresh(ctx, mgr.clusterMgrCli, disks)
+	if err != nil || !changed {
+		return err
+	}
+	if !mgr.lostShards.ranked() {
+		mgr.prepareQueue.SetTaskPriority(nil)
+		return nil
+	}
+	mgr.prepareQueue.SetTaskPriority(func(task base.WorkerTask) int {
+		return mgr.lostShards.volumePriority(task.(*proto.MigrateTask).Vid())
+	})
+	return nil
+}
+
 func (mgr *DiskRepairMgr) prepareTask(t *proto.MigrateTask) error {
 	span, ctx := trace.StartSpanFromContext(
 		context.Background(),
@@ -436,12 +514,12 @@ func (mgr *DiskRepairMgr) prepareTask(t *proto.MigrateTask) error {
 	badVuid := t.SourceVuid
 	if volInfo.VunitLocations[badVuid.Index()].Vuid != badVuid {
 		span.Infof("repair task finish in advance: task_id[%s]", t.TaskID)
-		mgr.finishTaskInAdvance(ctx, t, "volume has migrated")
-		return nil
+		return mgr.finishTaskInAdvance(ctx, t, "volume has migrated")
 	}
 
 	// 2.generate src and destination for task & task persist
-	allocDstVunit, err := base.AllocVunitSafe(ctx, mgr.clusterMgrCli, badVuid, t.Sources)
+	allocator := mgr.cfg.dstAllocator(mgr.clusterMgrCli, proto.TaskTypeDiskRepair)
+	allocDstVunit, err := base.AllocVunitSafe(ctx, allocator, badVuid, t.Sources)
 	if err != nil {
 		span.Errorf("repair alloc volume unit failed: err[%+v]", err)
 		return err
@@ -450,7 +528,11 @@ func (mgr *DiskRepairMgr) prepareTask(t *proto.MigrateTask) error {
 	t.CodeMode = volInfo.CodeMode
 	t.Sources = volInfo.VunitLocations
 	t.Destination = allocDstVunit.Location()
-	t.State = proto.MigrateStatePrepared
+	t.DestinationIDC = allocDstVunit.Idc
+	if err = t.TransitState(proto.MigrateStatePrepared); err != nil {
+		span.Errorf("prepare repair task failed: err[%+v]", err)
+		return err
+	}
 	base.InsistOn(ctx, "repair prepare task update task tbl", func() error {
 		return mgr.clusterMgrCli.UpdateMigrateTask(ctx, t)
 	})
@@ -461,11 +543,15 @@ func (mgr *DiskRepairMgr) prepareTask(t *proto.MigrateTask) error {
 
 func (mgr *DiskRepairMgr) sendToWorkQueue(t *proto.MigrateTask) {
 	mgr.workQueue.AddPreparedTask(t.SourceIDC, t.TaskID, t)
+	mgr.slowTasks.Start(t.SourceIDC, t.TaskID)
 	mgr.prepareQueue.RemoveTask(t.TaskID)
 }
 
-func (mgr *DiskRepairMgr) finishTaskInAdvance(ctx context.Context, task *proto.MigrateTask, reason string) {
-	task.State = proto.MigrateStateFinishedInAdvance
+func (mgr *DiskRepairMgr) finishTaskInAdvance(ctx context.Context, task *proto.MigrateTask, reason string) error {
+	if err := task.TransitState(proto.MigrateStateFinishedInAdvance); err != nil {
+		trace.SpanFromContextSafe(ctx).Errorf("finish task in advance failed: err[%+v]", err)
+		return err
+	}
 	task.FinishAdvanceReason = reason
 	base.InsistOn(ctx, "repair finish task in advance update task tbl", func() error {
 		return mgr.clusterMgrCli.DeleteMigrateTask(ctx, task.TaskID)
@@ -477,8 +563,10 @@ func (mgr *DiskRepairMgr) finishTaskInAdvance(ctx context.Context, task *proto.M
 
 	mgr.finishTaskCounter.Add()
 	mgr.prepareQueue.RemoveTask(task.TaskID)
+	mgr.slowTasks.Finish(task.TaskID)
 	mgr.deletedTasks.add(task.SourceDiskID, task.TaskID)
 	base.VolTaskLockerInst().Unlock(ctx, task.Vid())
+	return nil
 }
 
 func (mgr *DiskRepairMgr) finishTaskLoop() {
@@ -519,8 +607,9 @@ func (mgr *DiskRepairMgr) finishTask(ctx context.Context, task *proto.MigrateTas
 		}
 	}()
 
-	if task.State != proto.MigrateStateWorkCompleted {
-		span.Panicf("task state not expect: task_id[%s], expect state[%d], actual state[%d]", proto.MigrateStateWorkCompleted, task.State)
+	if err := task.State.CheckTransition(proto.MigrateStateFinished); err != nil {
+		span.Errorf("finish task failed: task_id[%s], err[%+v]", task.TaskID, err)
+		return err
 	}
 	// complete stage can not make sure to save task info to db,
 	// finish stage make sure to save task info to db
@@ -539,7 +628,10 @@ func (mgr *DiskRepairMgr) finishTask(ctx context.Context, task *proto.MigrateTas
 		return mgr.handleUpdateVolMappingFail(ctx, task, err)
 	}
 
-	task.State = proto.MigrateStateFinished
+	if err = task.TransitState(proto.MigrateStateFinished); err != nil {
+		span.Errorf("finish task failed: task_id[%s], err[%+v]", task.TaskID, err)
+		return err
+	}
 	base.InsistOn(ctx, "repair finish task update task state finished", func() error {
 		return mgr.clusterMgrCli.DeleteMigrateTask(ctx, task.TaskID)
 	})
@@ -552,6 +644,9 @@ func (mgr *DiskRepairMgr) finishTask(ctx context.Context, task *proto.MigrateTas
 	// 1.remove task in memory
 	// 2.release lock of volume task
 	mgr.finishQueue.RemoveTask(task.TaskID)
+	if elapsed, ok := mgr.slowTasks.Finish(task.TaskID); ok {
+		span.Infof("repair task finished: task_id[%s], elapsed[%s]", task.TaskID, elapsed)
+	}
 
 	// add delete task and check it again
 	mgr.deletedTasks.add(task.SourceDiskID, task.TaskID)
@@ -573,13 +668,19 @@ func (mgr *DiskRepairMgr) handleUpdateVolMappingFail(ctx context.Context, task *
 	if base.ShouldAllocAndRedo(code) {
 		span.Infof("realloc vunit and redo: task_id[%s]", task.TaskID)
 
-		newVunit, err := base.AllocVunitSafe(ctx, mgr.clusterMgrCli, task.SourceVuid, task.Sources)
+		mgr.cfg.quarantine.fail(ctx, task.Destination.DiskID)
+		allocator := mgr.cfg.dstAllocator(mgr.clusterMgrCli, proto.TaskTypeDiskRepair)
+		newVunit, err := base.AllocVunitSafe(ctx, allocator, task.SourceVuid, task.Sources)
 		if err != nil {
 			span.Errorf("realloc failed: vuid[%d], err[%+v]", task.SourceVuid, err)
 			return err
 		}
 		task.SetDestination(newVunit.Location())
-		task.State = proto.MigrateStatePrepared
+		task.DestinationIDC = newVunit.Idc
+		if err = task.TransitState(proto.MigrateStatePrepared); err != nil {
+			span.Errorf("redo task failed: err[%+v]", err)
+			return err
+		}
 		task.WorkerRedoCnt++
 
 		base.InsistOn(ctx, "repair redo task update task tbl", func() error {
@@ -733,9 +834,13 @@ func (mgr *DiskRepairMgr) AcquireTask(ctx context.Context, idc string) (task pro
 		return task, proto.ErrTaskPaused
 	}
 
-	_, repairTask, _ := mgr.workQueue.Acquire(idc)
+	_, repairTask, leaseExpired, _ := mgr.workQueue.AcquireLease(idc)
 	if repairTask != nil {
 		task = *repairTask.(*proto.MigrateTask)
+		if leaseExpired {
+			mgr.taskStatsMgr.LeaseExpired()
+			trace.SpanFromContextSafe(ctx).Warnf("acquire repair task of expired lease: task_id[%s]", task.TaskID)
+		}
 		return task, nil
 	}
 	return task, proto.ErrTaskEmpty
@@ -769,6 +874,7 @@ func (mgr *DiskRepairMgr) ReclaimTask(ctx context.Context,
 		span.Errorf("reclaim repair task failed: task_id[%s], err[%+v]", taskID, err)
 		return err
 	}
+	mgr.cfg.quarantine.fail(ctx, oldDst.DiskID)
 
 	task, err := mgr.workQueue.Query(idc, taskID)
 	if err != nil {
@@ -796,7 +902,10 @@ func (mgr *DiskRepairMgr) CompleteTask(ctx context.Context, args *api.OperateTas
 	}
 
 	t := completeTask.(*proto.MigrateTask)
-	t.State = proto.MigrateStateWorkCompleted
+	if err = t.TransitState(proto.MigrateStateWorkCompleted); err != nil {
+		span.Errorf("complete repair task failed: err[%+v]", err)
+		return err
+	}
 
 	mgr.finishQueue.PushTask(args.TaskID, t)
 	// as complete func is face to svr api, so can not loop save task
@@ -861,10 +970,15 @@ func (mgr *DiskRepairMgr) Stats() api.MigrateTasksStat {
 	preparing, workerDoing, finishing := mgr.StatQueueTaskCnt()
 	finishedCnt := mgr.finishTaskCounter.Show()
 	increaseDataSize, increaseShardCnt := mgr.taskStatsMgr.Counters()
+	slowTasks := mgr.slowTasks.slowTaskIDs()
 	return api.MigrateTasksStat{
-		PreparingCnt:   preparing,
-		WorkerDoingCnt: workerDoing,
-		FinishingCnt:   finishing,
+		PreparingCnt:     preparing,
+		WorkerDoingCnt:   workerDoing,
+		FinishingCnt:     finishing,
+		SlowCnt:          len(slowTasks),
+		SlowTasks:        slowTasks,
+		QuarantinedDisks: mgr.cfg.quarantine.disks(),
+		CorruptedTasks:   mgr.corruptedTasks,
 		StatsPerMin: api.PerMinStats{
 			FinishedCnt:    fmt.Sprint(finishedCnt),
 			DataAmountByte: base.DataMountFormat(increaseDataSize),
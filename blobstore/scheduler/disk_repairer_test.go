@@ -135,16 +135,16 @@ func TestDiskRepairerLoad(t *testing.T) {
 	{
 		mgr := newDiskRepairer(t)
 		t1 := mockGenMigrateTask(proto.TaskTypeDiskRepair, "z0", 1, 1, proto.MigrateStateFinishedInAdvance, newMockVolInfoMap())
+		t2 := mockGenMigrateTask(proto.TaskTypeDiskRepair, "z0", 1, 2, proto.MigrateState(0), newMockVolInfoMap())
+		t3 := mockGenMigrateTask(proto.TaskTypeDiskRepair, "z0", 1, 3, proto.MigrateStateInited, newMockVolInfoMap())
+		// corrupted tasks are skipped and shown in stats
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListMigratingDisks(any, any).Return([]*client.MigratingDiskMeta{{Disk: &client.DiskInfoSimple{DiskID: proto.DiskID(1)}}}, nil)
-		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t1}, nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t1, t2, t3}, nil)
 		err := mgr.Load()
-		require.Error(t, err)
-
-		t2 := mockGenMigrateTask(proto.TaskTypeDiskRepair, "z0", 1, 1, proto.MigrateStateFinished, newMockVolInfoMap())
-		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListMigratingDisks(any, any).Return([]*client.MigratingDiskMeta{{Disk: &client.DiskInfoSimple{DiskID: proto.DiskID(1)}}}, nil)
-		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t2}, nil)
-		err = mgr.Load()
-		require.Error(t, err)
+		require.NoError(t, err)
+		require.Equal(t, []string{t1.TaskID, t2.TaskID}, mgr.Stats().CorruptedTasks)
+		_, ok := mgr.prepareQueue.Query(t3.TaskID)
+		require.True(t, ok)
 	}
 	{
 		mgr := newDiskRepairer(t)
@@ -172,7 +172,8 @@ func TestDiskRepairerLoad(t *testing.T) {
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListMigratingDisks(any, any).Return([]*client.MigratingDiskMeta{{Disk: &client.DiskInfoSimple{DiskID: proto.DiskID(1)}}}, nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t1}, nil)
 		err := mgr.Load()
-		require.Error(t, err)
+		require.NoError(t, err)
+		require.Equal(t, []string{t1.TaskID}, mgr.Stats().CorruptedTasks)
 	}
 }
 
@@ -184,6 +185,12 @@ func TestDiskRepairerRun(t *testing.T) {
 	mgr.taskSwitch.(*mocks.MockSwitcher).EXPECT().Enabled().AnyTimes().Return(true)
 
 	mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasksByDiskID(any, any, any).AnyTimes().Return(nil, errMock)
+	mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListBrokenDisks(any).AnyTimes().Return(nil, nil)
+	mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().WatchBrokenDisks(any, any).AnyTimes().DoAndReturn(
+		func(ctx context.Context, version uint64) (uint64, error) {
+			<-ctx.Done()
+			return version, ctx.Err()
+		})
 	require.True(t, mgr.Enabled())
 	mgr.hasRevised = true
 	mgr.repairingDisks.add(testDisk1.DiskID, testDisk1)
@@ -192,6 +199,38 @@ func TestDiskRepairerRun(t *testing.T) {
 	time.Sleep(1 * time.Second)
 }
 
+func TestDiskRepairerWatchBrokenDisk(t *testing.T) {
+	mgr := newDiskRepairer(t)
+	mgr.cfg.CollectTaskIntervalS = 0
+
+	gomock.InOrder(
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().WatchBrokenDisks(any, uint64(0)).Return(uint64(0), errMock),
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().WatchBrokenDisks(any, uint64(0)).Return(uint64(0), nil),
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().WatchBrokenDisks(any, uint64(0)).Return(uint64(1), nil),
+		// smaller version from a lagging clustermgr is adopted without notify
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().WatchBrokenDisks(any, uint64(1)).Return(uint64(0), nil),
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().WatchBrokenDisks(any, uint64(0)).DoAndReturn(
+			func(ctx context.Context, version uint64) (uint64, error) {
+				<-ctx.Done()
+				return version, ctx.Err()
+			}),
+	)
+	done := make(chan struct{})
+	go func() {
+		mgr.watchBrokenDiskLoop()
+		close(done)
+	}()
+
+	select {
+	case <-mgr.brokenNotify:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notify of broken disk")
+	}
+	mgr.Close()
+	<-done
+	require.Len(t, mgr.brokenNotify, 0)
+}
+
 func TestDiskRepairerCollectTask(t *testing.T) {
 	{
 		mgr := newDiskRepairer(t)
@@ -235,6 +274,7 @@ func TestDiskRepairerCollectTask(t *testing.T) {
 			&client.DiskInfoSimple{DiskID: testDisk1.DiskID, Status: proto.DiskStatusBroken}, nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListDiskVolumeUnits(any, any).Return(units, nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().SetDiskRepairing(any, any).Return(nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListBrokenDisks(any).Return(nil, nil)
 		mgr.collectTask()
 		require.True(t, mgr.hasRevised)
 		todo, doing := mgr.prepareQueue.StatsTasks()
@@ -340,7 +380,8 @@ func TestDiskRepairerCollectTask(t *testing.T) {
 			units = append(units, &ele)
 		}
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasksByDiskID(any, any, any).Return(nil, nil)
-		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListDiskVolumeUnits(any, any).Return(units, nil)
+		// list units of both broken disks to rank volumes and of the new repairing disk to generate tasks
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListDiskVolumeUnits(any, any).Times(3).Return(units, nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListBrokenDisks(any).Return([]*client.DiskInfoSimple{testDisk1, testDisk2}, nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().AddMigratingDisk(any, any).Return(nil)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().SetDiskRepairing(any, any).Return(nil)
@@ -350,6 +391,61 @@ func TestDiskRepairerCollectTask(t *testing.T) {
 	}
 }
 
+func TestDiskRepairerLostShardsPriority(t *testing.T) {
+	ctx := context.Background()
+	mgr := newDiskRepairer(t)
+	disk3 := &client.DiskInfoSimple{DiskID: proto.DiskID(3), Idc: "z0", Status: proto.DiskStatusBroken}
+	diskVids := map[proto.DiskID][]proto.Vid{
+		testDisk1.DiskID: {10, 11},
+		testDisk2.DiskID: {11, 12},
+		disk3.DiskID:     {13},
+	}
+	mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListDiskVolumeUnits(any, any).Times(3).DoAndReturn(
+		func(_ context.Context, diskID proto.DiskID) ([]*client.VunitInfoSimple, error) {
+			var units []*client.VunitInfoSimple
+			for idx, vid := range diskVids[diskID] {
+				vuid, _ := proto.NewVuid(vid, uint8(idx), 1)
+				units = append(units, &client.VunitInfoSimple{Vuid: vuid, DiskID: diskID})
+			}
+			return units, nil
+		})
+
+	// only one broken disk
+	require.NoError(t, mgr.refreshLostShards(ctx, []*client.DiskInfoSimple{testDisk1}))
+	require.False(t, mgr.lostShards.ranked())
+
+	disks := []*client.DiskInfoSimple{disk3, testDisk1, testDisk2}
+	require.NoError(t, mgr.refreshLostShards(ctx, disks))
+	require.True(t, mgr.lostShards.ranked())
+	require.Equal(t, 2, mgr.lostShards.volumePriority(11))
+	require.Equal(t, 1, mgr.lostShards.volumePriority(10))
+	require.Equal(t, 2, mgr.lostShards.diskPriority(testDisk2.DiskID))
+	require.Equal(t, 1, mgr.lostShards.diskPriority(disk3.DiskID))
+	// no recount if the broken disks are not changed
+	changed, err := mgr.lostShards.refresh(ctx, mgr.clusterMgrCli, []*client.DiskInfoSimple{testDisk2, disk3, testDisk1, testDisk1})
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// the disk holding the volume lost the most shards is repaired first
+	require.Equal(t, testDisk1.DiskID, mgr.getUnRepairingDisk(disks).DiskID)
+	mgr.repairingDisks.add(testDisk1.DiskID, testDisk1)
+	require.Equal(t, testDisk2.DiskID, mgr.getUnRepairingDisk(disks).DiskID)
+
+	// the task of volume lost the most shards is prepared first
+	for _, vid := range diskVids[testDisk1.DiskID] {
+		vuid, _ := proto.NewVuid(vid, 0, 1)
+		task := &proto.MigrateTask{
+			TaskID:     client.GenMigrateTaskID(proto.TaskTypeDiskRepair, testDisk1.DiskID, vid),
+			TaskType:   proto.TaskTypeDiskRepair,
+			SourceVuid: vuid,
+		}
+		mgr.prepareQueue.PushTask(task.TaskID, task)
+	}
+	_, task, exist := mgr.prepareQueue.PopTask()
+	require.True(t, exist)
+	require.Equal(t, proto.Vid(11), task.(*proto.MigrateTask).Vid())
+}
+
 func TestDiskRepairerPopTaskAndPrepare(t *testing.T) {
 	{
 		mgr := newDiskRepairer(t)
@@ -446,9 +542,8 @@ func TestDiskRepairerPopTaskAndFinish(t *testing.T) {
 		mgr := newDiskRepairer(t)
 		t1 := mockGenMigrateTask(proto.TaskTypeDiskRepair, "z0", 1, 1, proto.MigrateStateFinished, newMockVolInfoMap())
 		mgr.finishQueue.PushTask(t1.TaskID, t1)
-		require.Panics(t, func() {
-			mgr.popTaskAndFinish()
-		})
+		err := mgr.popTaskAndFinish()
+		require.True(t, errors.Is(err, proto.ErrInvalidMigrateState))
 	}
 	{
 		mgr := newDiskRepairer(t)
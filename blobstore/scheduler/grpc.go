@@ -0,0 +1,113 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
+	pb "github.com/cubefs/cubefs/blobstore/api/scheduler/schedulerpb"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/util/log"
+)
+
+// taskServer serves task protocol of worker on grpc transport,
+// requests to follower are forwarded to leader like http requests.
+type taskServer struct {
+	svr    *Service
+	leader pb.SchedulerTaskClient // nil if service is leader
+}
+
+var _ pb.SchedulerTaskServer = (*taskServer)(nil)
+
+// startGrpcServer listens on grpc port, returns nil server if grpc port is not configured.
+func (svr *Service) startGrpcServer(port int) (*grpc.Server, error) {
+	if port <= 0 {
+		return nil, nil
+	}
+
+	ts := &taskServer{svr: svr}
+	if !svr.leader {
+		target, err := api.GrpcTarget(svr.leaderHost, port)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := rpc.DialGRPC(context.Background(), target)
+		if err != nil {
+			return nil, err
+		}
+		ts.leader = pb.NewSchedulerTaskClient(conn)
+	}
+
+	ln, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return nil, err
+	}
+	server := rpc.NewGRPCServer()
+	pb.RegisterSchedulerTaskServer(server, ts)
+	go func() {
+		if err := server.Serve(ln); err != nil {
+			log.Errorf("grpc server exit: err[%+v]", err)
+		}
+	}()
+	log.Infof("grpc server is running at port %d", port)
+	return server, nil
+}
+
+func (ts *taskServer) AcquireTask(ctx context.Context, req *pb.AcquireTaskRequest) (*pb.MigrateTask, error) {
+	if ts.leader != nil {
+		return ts.leader.AcquireTask(ctx, req)
+	}
+	task, err := ts.svr.acquireTask(ctx, &api.AcquireArgs{IDC: req.Idc})
+	if err != nil {
+		return nil, err
+	}
+	return api.MigrateTaskToPB(task), nil
+}
+
+func (ts *taskServer) RenewalTask(ctx context.Context, req *pb.RenewalTaskRequest) (*pb.RenewalTaskResponse, error) {
+	if ts.leader != nil {
+		return ts.leader.RenewalTask(ctx, req)
+	}
+	ret, err := ts.svr.renewalTask(ctx, api.TaskRenewalArgsFromPB(req))
+	if err != nil {
+		return nil, err
+	}
+	return api.TaskRenewalRetToPB(ret), nil
+}
+
+func (ts *taskServer) CompleteTask(ctx context.Context, req *pb.OperateTaskRequest) (*pb.Empty, error) {
+	if ts.leader != nil {
+		return ts.leader.CompleteTask(ctx, req)
+	}
+	if err := ts.svr.completeTask(ctx, api.OperateTaskArgsFromPB(req)); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (ts *taskServer) ReportTask(ctx context.Context, req *pb.ReportTaskRequest) (*pb.Empty, error) {
+	if ts.leader != nil {
+		return ts.leader.ReportTask(ctx, req)
+	}
+	if err := ts.svr.reportTask(api.TaskReportArgsFromPB(req)); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
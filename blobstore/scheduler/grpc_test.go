@@ -0,0 +1,116 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
+	pb "github.com/cubefs/cubefs/blobstore/api/scheduler/schedulerpb"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+func freeGrpcPort(t *testing.T) int {
+	ln, err := net.Listen("tcp", localHost+":0")
+	require.NoError(t, err)
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestServiceGrpc(t *testing.T) {
+	ctr := gomock.NewController(t)
+	diskRepairMgr := NewMockMigrater(ctr)
+	emptyMgr := NewMockMigrater(ctr)
+	emptyMgr.EXPECT().AcquireTask(any, any).AnyTimes().Return(proto.MigrateTask{}, errMock)
+
+	svr := &Service{
+		leader:        true,
+		diskRepairMgr: diskRepairMgr,
+		manualMigMgr:  emptyMgr,
+		diskDropMgr:   emptyMgr,
+		balanceMgr:    emptyMgr,
+		coldMigMgr:    emptyMgr,
+	}
+	server, err := svr.startGrpcServer(0)
+	require.NoError(t, err)
+	require.Nil(t, server)
+
+	port := freeGrpcPort(t)
+	server, err = svr.startGrpcServer(port)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	ctx := context.Background()
+	conn, err := rpc.DialGRPC(ctx, localHost+":"+strconv.Itoa(port))
+	require.NoError(t, err)
+	defer conn.Close()
+	cli := pb.NewSchedulerTaskClient(conn)
+
+	taskID := client.GenMigrateTaskID(proto.TaskTypeDiskRepair, 1, 1)
+	task := proto.MigrateTask{TaskID: taskID, TaskType: proto.TaskTypeDiskRepair, SourceIDC: "z0"}
+	diskRepairMgr.EXPECT().AcquireTask(any, "z0").Return(task, nil)
+	ret, err := cli.AcquireTask(ctx, &pb.AcquireTaskRequest{Idc: "z0"})
+	require.NoError(t, err)
+	require.Equal(t, &task, api.MigrateTaskFromPB(ret))
+
+	diskRepairMgr.EXPECT().AcquireTask(any, "z1").Return(proto.MigrateTask{}, errMock)
+	_, err = cli.AcquireTask(ctx, &pb.AcquireTaskRequest{Idc: "z1"})
+	require.Equal(t, errcode.CodeNotingTodo, rpc.DetectStatusCode(err))
+
+	diskRepairMgr.EXPECT().RenewalTask(any, "z0", taskID).Return(errMock)
+	renewal, err := cli.RenewalTask(ctx, api.TaskRenewalArgsToPB(&api.TaskRenewalArgs{
+		IDC: "z0",
+		IDs: map[proto.TaskType][]string{proto.TaskTypeDiskRepair: {taskID, "illegal"}},
+	}))
+	require.NoError(t, err)
+	errs := api.TaskRenewalRetFromPB(renewal).Errors[proto.TaskTypeDiskRepair]
+	require.Equal(t, errMock.Error(), errs[taskID])
+	require.Equal(t, errcode.ErrIllegalArguments.Error(), errs["illegal"])
+
+	diskRepairMgr.EXPECT().CompleteTask(any, any).Return(nil)
+	_, err = cli.CompleteTask(ctx, api.OperateTaskArgsToPB(&api.OperateTaskArgs{
+		IDC: "z0", TaskID: taskID, TaskType: proto.TaskTypeDiskRepair,
+	}))
+	require.NoError(t, err)
+	_, err = cli.CompleteTask(ctx, api.OperateTaskArgsToPB(&api.OperateTaskArgs{
+		IDC: "z0", TaskID: taskID, TaskType: "illegal",
+	}))
+	require.Equal(t, http.StatusBadRequest, rpc.DetectStatusCode(err))
+
+	diskRepairMgr.EXPECT().ReportWorkerTaskStats(any).Return()
+	_, err = cli.ReportTask(ctx, api.TaskReportArgsToPB(&api.TaskReportArgs{
+		TaskID: taskID, TaskType: proto.TaskTypeDiskRepair,
+	}))
+	require.NoError(t, err)
+
+	// follower forwards requests to leader
+	follower := &taskServer{svr: &Service{}, leader: cli}
+	diskRepairMgr.EXPECT().AcquireTask(any, "z0").Return(task, nil)
+	ret, err = follower.AcquireTask(ctx, &pb.AcquireTaskRequest{Idc: "z0"})
+	require.NoError(t, err)
+	require.Equal(t, taskID, ret.TaskId)
+	_, err = follower.ReportTask(ctx, api.TaskReportArgsToPB(&api.TaskReportArgs{TaskID: "illegal"}))
+	require.Equal(t, http.StatusBadRequest, rpc.DetectStatusCode(err))
+}
@@ -0,0 +1,356 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+const (
+	defaultHostDrainConcurrency = 1
+	maxHostDrainConcurrency     = 16
+)
+
+var (
+	errIllegalHostDrain = errors.New("illegal host drain")
+	errHostNotDraining  = errors.New("host is not draining")
+)
+
+// IHostDrainer drains all disks of blobnode host
+type IHostDrainer interface {
+	DrainHost(ctx context.Context, host string, concurrency int) error
+	HostDrainStat(ctx context.Context, host string) (*api.HostDrainStat, error)
+}
+
+func (mgr *DiskDropMgr) loadHostDrains(ctx context.Context) error {
+	drains, err := mgr.clusterMgrCli.GetHostDrains(ctx)
+	if err != nil {
+		return err
+	}
+	mgr.drainsMu.Lock()
+	for _, drain := range drains {
+		mgr.drains[drain.Host] = drain
+	}
+	mgr.drainsMu.Unlock()
+	return nil
+}
+
+// DrainHost drops all undropped disks of host, at most concurrency disks of host are dropping at the same time,
+// drain the host again to modify the concurrency or pick up the new disks of host
+func (mgr *DiskDropMgr) DrainHost(ctx context.Context, host string, concurrency int) error {
+	span := trace.SpanFromContextSafe(ctx)
+	if host == "" {
+		return fmt.Errorf("%w: empty host", errIllegalHostDrain)
+	}
+	if concurrency == 0 {
+		concurrency = defaultHostDrainConcurrency
+	}
+	if concurrency < 0 || concurrency > maxHostDrainConcurrency {
+		return fmt.Errorf("%w: concurrency should be in [1, %d]", errIllegalHostDrain, maxHostDrainConcurrency)
+	}
+
+	disks, err := mgr.clusterMgrCli.ListHostDisks(ctx, host)
+	if err != nil {
+		span.Errorf("list host disks failed: host[%s], err[%+v]", host, err)
+		return err
+	}
+	diskIDs := make([]proto.DiskID, 0, len(disks))
+	for _, disk := range disks {
+		if disk.IsDropped() || disk.IsRepaired() {
+			continue
+		}
+		diskIDs = append(diskIDs, disk.DiskID)
+	}
+	if len(diskIDs) == 0 {
+		return fmt.Errorf("%w: no disk to drain of host %s", errIllegalHostDrain, host)
+	}
+	diskIDs = mergeDiskIDs(nil, diskIDs)
+
+	mgr.drainsMu.Lock()
+	defer mgr.drainsMu.Unlock()
+	drain := &client.HostDrainMeta{
+		Host:        host,
+		Concurrency: concurrency,
+		Disks:       diskIDs,
+		Ctime:       time.Now().String(),
+	}
+	if old, ok := mgr.drains[host]; ok {
+		// keep the dropped disks in progress view
+		drain.Ctime = old.Ctime
+		drain.Disks = mergeDiskIDs(old.Disks, diskIDs)
+	}
+	if err = mgr.saveHostDrain(ctx, host, drain); err != nil {
+		span.Errorf("persist host drain failed: host[%s], err[%+v]", host, err)
+		return err
+	}
+	span.Infof("drain host success: host[%s], concurrency[%d], disks[%v]", host, concurrency, diskIDs)
+	return nil
+}
+
+// HostDrainStat returns the progress of draining host, the host is not draining any more once all disks are dropped
+func (mgr *DiskDropMgr) HostDrainStat(ctx context.Context, host string) (*api.HostDrainStat, error) {
+	mgr.drainsMu.Lock()
+	drain, ok := mgr.drains[host]
+	mgr.drainsMu.Unlock()
+	if !ok {
+		return nil, errHostNotDraining
+	}
+
+	disks, err := mgr.listHostDrainDisks(ctx, drain)
+	if err != nil {
+		return nil, err
+	}
+	stat := &api.HostDrainStat{
+		Host:        drain.Host,
+		Concurrency: drain.Concurrency,
+		Ctime:       drain.Ctime,
+		Disks:       make([]api.HostDrainDisk, 0, len(drain.Disks)),
+	}
+	for _, diskID := range drain.Disks {
+		state := mgr.hostDrainDiskState(disks[diskID])
+		disk := api.HostDrainDisk{DiskID: diskID, State: state}
+		if dDisk := mgr.allDisks.get(diskID); dDisk != nil {
+			disk.TotalChunks = int(dDisk.UsedChunkCnt)
+			switch {
+			case state == api.HostDrainDiskDropped:
+				disk.MigratedChunks = disk.TotalChunks
+			case dDisk.isCollecting():
+				disk.MigratedChunks = int(dDisk.UsedChunkCnt - dDisk.getUndoneCnt())
+			}
+		} else if info := disks[diskID]; info != nil {
+			disk.TotalChunks = int(info.UsedChunkCnt)
+		}
+
+		switch state {
+		case api.HostDrainDiskPending:
+			stat.PendingDisks++
+		case api.HostDrainDiskDropping:
+			stat.DroppingDisks++
+		case api.HostDrainDiskDropped:
+			stat.DroppedDisks++
+		}
+		stat.Disks = append(stat.Disks, disk)
+	}
+	return stat, nil
+}
+
+// drainHosts drops the pending disks of draining hosts until the concurrency of host is reached,
+// and removes the host whose disks are all dropped. The draining hosts are snapshot so that
+// clustermgr is not called with drainsMu held, except persisting the removed hosts
+func (mgr *DiskDropMgr) drainHosts(ctx context.Context) {
+	span := trace.SpanFromContextSafe(ctx)
+
+	mgr.drainsMu.Lock()
+	drains := make([]*client.HostDrainMeta, 0, len(mgr.drains))
+	for _, drain := range mgr.drains {
+		drains = append(drains, drain)
+	}
+	mgr.drainsMu.Unlock()
+	sort.Slice(drains, func(i, j int) bool { return drains[i].Host < drains[j].Host })
+
+	var drained []*client.HostDrainMeta
+	for _, drain := range drains {
+		host := drain.Host
+		disks, err := mgr.listHostDrainDisks(ctx, drain)
+		if err != nil {
+			span.Errorf("list host disks failed: host[%s], err[%+v]", host, err)
+			continue
+		}
+
+		undone, dropping := 0, 0
+		var pending []*client.DiskInfoSimple
+		for _, diskID := range drain.Disks {
+			disk := disks[diskID]
+			switch mgr.hostDrainDiskState(disk) {
+			case api.HostDrainDiskPending:
+				undone++
+				// broken disk is dropped after repaired
+				if disk.IsHealth() {
+					pending = append(pending, disk)
+				}
+			case api.HostDrainDiskDropping:
+				undone++
+				dropping++
+			}
+		}
+
+		if undone == 0 {
+			drained = append(drained, drain)
+			continue
+		}
+
+		for _, disk := range pending {
+			if dropping >= drain.Concurrency {
+				break
+			}
+			if err = mgr.clusterMgrCli.DropDisk(ctx, disk.DiskID); err != nil {
+				span.Errorf("drop disk of draining host failed: host[%s], disk_id[%d], err[%+v]", host, disk.DiskID, err)
+				continue
+			}
+			span.Infof("drop disk of draining host: host[%s], disk_id[%d]", host, disk.DiskID)
+			dropping++
+		}
+	}
+	mgr.removeDrainedHosts(ctx, drained)
+}
+
+// removeDrainedHosts removes the drained hosts which are not drained again meanwhile
+func (mgr *DiskDropMgr) removeDrainedHosts(ctx context.Context, drained []*client.HostDrainMeta) {
+	if len(drained) == 0 {
+		return
+	}
+	span := trace.SpanFromContextSafe(ctx)
+
+	mgr.drainsMu.Lock()
+	defer mgr.drainsMu.Unlock()
+	removed := make(map[string]*client.HostDrainMeta, len(drained))
+	for _, drain := range drained {
+		if mgr.drains[drain.Host] == drain {
+			removed[drain.Host] = nil
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	if err := mgr.saveHostDrains(ctx, removed); err != nil {
+		span.Errorf("remove drained hosts failed: hosts[%d], err[%+v]", len(removed), err)
+		return
+	}
+	for _, drain := range drained {
+		if _, ok := removed[drain.Host]; ok {
+			span.Infof("host drained: host[%s], disks[%v]", drain.Host, drain.Disks)
+		}
+	}
+}
+
+func mergeDiskIDs(a, b []proto.DiskID) []proto.DiskID {
+	set := make(map[proto.DiskID]struct{}, len(a)+len(b))
+	merged := make([]proto.DiskID, 0, len(a)+len(b))
+	for _, ids := range [][]proto.DiskID{a, b} {
+		for _, id := range ids {
+			if _, ok := set[id]; !ok {
+				set[id] = struct{}{}
+				merged = append(merged, id)
+			}
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i] < merged[j] })
+	return merged
+}
+
+func (mgr *DiskDropMgr) listHostDrainDisks(ctx context.Context, drain *client.HostDrainMeta) (map[proto.DiskID]*client.DiskInfoSimple, error) {
+	disks, err := mgr.clusterMgrCli.ListHostDisks(ctx, drain.Host)
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[proto.DiskID]*client.DiskInfoSimple, len(disks))
+	for _, disk := range disks {
+		infos[disk.DiskID] = disk
+	}
+	return infos, nil
+}
+
+// hostDrainDiskState the disk is dropping once collected by disk drop manager,
+// the disk removed from host or repaired has nothing to drop
+func (mgr *DiskDropMgr) hostDrainDiskState(disk *client.DiskInfoSimple) api.HostDrainDiskState {
+	if disk == nil || disk.IsDropped() || disk.IsRepaired() {
+		return api.HostDrainDiskDropped
+	}
+	if mgr.allDisks.get(disk.DiskID) != nil {
+		return api.HostDrainDiskDropping
+	}
+	return api.HostDrainDiskPending
+}
+
+// saveHostDrain persists all draining hosts with the drain of host, nil drain means removing the host,
+// the caller should hold drainsMu
+func (mgr *DiskDropMgr) saveHostDrain(ctx context.Context, host string, drain *client.HostDrainMeta) error {
+	return mgr.saveHostDrains(ctx, map[string]*client.HostDrainMeta{host: drain})
+}
+
+// saveHostDrains persists all draining hosts with the updated drains of hosts, the caller should hold drainsMu
+func (mgr *DiskDropMgr) saveHostDrains(ctx context.Context, updates map[string]*client.HostDrainMeta) error {
+	drains := make([]*client.HostDrainMeta, 0, len(mgr.drains)+len(updates))
+	for h, d := range mgr.drains {
+		if _, ok := updates[h]; !ok {
+			drains = append(drains, d)
+		}
+	}
+	for _, d := range updates {
+		if d != nil {
+			drains = append(drains, d)
+		}
+	}
+	sort.Slice(drains, func(i, j int) bool { return drains[i].Host < drains[j].Host })
+	if err := mgr.clusterMgrCli.SetHostDrains(ctx, drains); err != nil {
+		return err
+	}
+
+	for h, d := range updates {
+		if d != nil {
+			mgr.drains[h] = d
+		} else {
+			delete(mgr.drains, h)
+		}
+	}
+	return nil
+}
+
+// HTTPHostDrain drains all disks of blobnode host
+func (svr *Service) HTTPHostDrain(c *rpc.Context) {
+	args := new(api.HostDrainArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	err := svr.hostDrainer.DrainHost(c.Request.Context(), args.Host, args.Concurrency)
+	if err != nil {
+		if errors.Is(err, errIllegalHostDrain) {
+			err = rpc.NewError(http.StatusBadRequest, "illegal_host_drain", err)
+		}
+		c.RespondError(err)
+		return
+	}
+	c.Respond()
+}
+
+// HTTPHostDrainStat returns progress of draining host
+func (svr *Service) HTTPHostDrainStat(c *rpc.Context) {
+	args := new(api.HostDrainStatArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	stat, err := svr.hostDrainer.HostDrainStat(c.Request.Context(), args.Host)
+	if err != nil {
+		if errors.Is(err, errHostNotDraining) {
+			err = rpc.NewError(http.StatusNotFound, "host_not_draining", err)
+		}
+		c.RespondError(err)
+		return
+	}
+	c.RespondJSON(stat)
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+func newHostDrainDisks(host string, statuses ...proto.DiskStatus) []*client.DiskInfoSimple {
+	disks := make([]*client.DiskInfoSimple, 0, len(statuses))
+	for idx, status := range statuses {
+		disks = append(disks, &client.DiskInfoSimple{
+			DiskID:       proto.DiskID(idx + 1),
+			Host:         host,
+			Status:       status,
+			UsedChunkCnt: 10,
+		})
+	}
+	return disks
+}
+
+func TestDiskDropLoadHostDrains(t *testing.T) {
+	ctx := context.Background()
+	mgr := newDiskDroper(t)
+	cmCli := mgr.clusterMgrCli.(*MockClusterMgrAPI)
+
+	cmCli.EXPECT().GetHostDrains(any).Return(nil, errMock)
+	require.True(t, errors.Is(mgr.loadHostDrains(ctx), errMock))
+
+	drain := &client.HostDrainMeta{Host: testDrainHost, Concurrency: 2, Disks: []proto.DiskID{1, 2}}
+	cmCli.EXPECT().GetHostDrains(any).Return([]*client.HostDrainMeta{drain}, nil)
+	require.NoError(t, mgr.loadHostDrains(ctx))
+	require.Equal(t, drain, mgr.drains[testDrainHost])
+}
+
+func TestDiskDropDrainHost(t *testing.T) {
+	ctx := context.Background()
+	mgr := newDiskDroper(t)
+	cmCli := mgr.clusterMgrCli.(*MockClusterMgrAPI)
+
+	// illegal args
+	for _, args := range []struct {
+		host        string
+		concurrency int
+	}{
+		{"", 1},
+		{testDrainHost, -1},
+		{testDrainHost, maxHostDrainConcurrency + 1},
+	} {
+		err := mgr.DrainHost(ctx, args.host, args.concurrency)
+		require.True(t, errors.Is(err, errIllegalHostDrain))
+	}
+
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(nil, errMock)
+	require.True(t, errors.Is(mgr.DrainHost(ctx, testDrainHost, 0), errMock))
+
+	// no disk to drain
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(
+		newHostDrainDisks(testDrainHost, proto.DiskStatusDropped, proto.DiskStatusRepaired), nil)
+	require.True(t, errors.Is(mgr.DrainHost(ctx, testDrainHost, 0), errIllegalHostDrain))
+
+	disks := newHostDrainDisks(testDrainHost, proto.DiskStatusNormal, proto.DiskStatusDropped, proto.DiskStatusBroken)
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Times(2).Return(disks, nil)
+	cmCli.EXPECT().SetHostDrains(any, any).Return(errMock)
+	require.True(t, errors.Is(mgr.DrainHost(ctx, testDrainHost, 0), errMock))
+	require.Equal(t, 0, len(mgr.drains))
+
+	cmCli.EXPECT().SetHostDrains(any, any).DoAndReturn(func(_ context.Context, drains []*client.HostDrainMeta) error {
+		require.Equal(t, 1, len(drains))
+		require.Equal(t, testDrainHost, drains[0].Host)
+		require.Equal(t, defaultHostDrainConcurrency, drains[0].Concurrency)
+		require.Equal(t, []proto.DiskID{1, 3}, drains[0].Disks)
+		return nil
+	})
+	require.NoError(t, mgr.DrainHost(ctx, testDrainHost, 0))
+	ctime := mgr.drains[testDrainHost].Ctime
+
+	// drain again with the new disk and concurrency, disk 1 has been dropped
+	disks = newHostDrainDisks(testDrainHost, proto.DiskStatusDropped, proto.DiskStatusDropped,
+		proto.DiskStatusNormal, proto.DiskStatusNormal)
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(disks, nil)
+	cmCli.EXPECT().SetHostDrains(any, any).Return(nil)
+	require.NoError(t, mgr.DrainHost(ctx, testDrainHost, 2))
+	drain := mgr.drains[testDrainHost]
+	require.Equal(t, 2, drain.Concurrency)
+	require.Equal(t, []proto.DiskID{1, 3, 4}, drain.Disks)
+	require.Equal(t, ctime, drain.Ctime)
+}
+
+func TestDiskDropDrainHosts(t *testing.T) {
+	ctx := context.Background()
+	mgr := newDiskDroper(t)
+	cmCli := mgr.clusterMgrCli.(*MockClusterMgrAPI)
+	otherHost := "http://127.0.0.1:8890"
+	mgr.drains[testDrainHost] = &client.HostDrainMeta{Host: testDrainHost, Concurrency: 2, Disks: []proto.DiskID{1, 2, 3, 4, 5}}
+	mgr.drains[otherHost] = &client.HostDrainMeta{Host: otherHost, Concurrency: 1, Disks: []proto.DiskID{1}}
+
+	// disk 1 is dropping, disk 2 is broken, disk 3 dropped failed and disk 4 is dropped
+	disks := newHostDrainDisks(testDrainHost, proto.DiskStatusNormal, proto.DiskStatusBroken,
+		proto.DiskStatusNormal, proto.DiskStatusNormal, proto.DiskStatusNormal)
+	mgr.allDisks.add(&dropDisk{DiskInfoSimple: disks[0]})
+	cmCli.EXPECT().ListHostDisks(any, otherHost).Return(nil, errMock)
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(disks, nil)
+	cmCli.EXPECT().DropDisk(any, proto.DiskID(3)).Return(errMock)
+	cmCli.EXPECT().DropDisk(any, proto.DiskID(4)).Return(nil)
+	mgr.drainHosts(ctx)
+	require.Equal(t, 2, len(mgr.drains))
+
+	// disk 1 and 2 are dropped, disk 3 and 4 are dropping
+	disks = newHostDrainDisks(testDrainHost, proto.DiskStatusDropped, proto.DiskStatusRepaired,
+		proto.DiskStatusNormal, proto.DiskStatusNormal, proto.DiskStatusNormal)
+	mgr.allDisks.add(&dropDisk{DiskInfoSimple: disks[2]})
+	mgr.allDisks.add(&dropDisk{DiskInfoSimple: disks[3]})
+	cmCli.EXPECT().ListHostDisks(any, otherHost).Return(nil, nil)
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(disks, nil)
+	cmCli.EXPECT().SetHostDrains(any, any).DoAndReturn(func(_ context.Context, drains []*client.HostDrainMeta) error {
+		require.Equal(t, 1, len(drains))
+		require.Equal(t, testDrainHost, drains[0].Host)
+		return nil
+	})
+	mgr.drainHosts(ctx)
+	require.Equal(t, 1, len(mgr.drains))
+
+	// all disks are dropped
+	disks = newHostDrainDisks(testDrainHost, proto.DiskStatusDropped, proto.DiskStatusDropped,
+		proto.DiskStatusDropped, proto.DiskStatusDropped)
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Times(2).Return(disks, nil)
+	cmCli.EXPECT().SetHostDrains(any, any).Return(errMock)
+	mgr.drainHosts(ctx)
+	require.Equal(t, 1, len(mgr.drains))
+	cmCli.EXPECT().SetHostDrains(any, any).Return(nil)
+	mgr.drainHosts(ctx)
+	require.Equal(t, 0, len(mgr.drains))
+
+	// the host drained again meanwhile is kept, drainsMu is not held while listing disks
+	drain := &client.HostDrainMeta{Host: testDrainHost, Concurrency: 1, Disks: []proto.DiskID{1}}
+	mgr.drains[testDrainHost] = drain
+	again := &client.HostDrainMeta{Host: testDrainHost, Concurrency: 1, Disks: []proto.DiskID{1, 5}}
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).DoAndReturn(
+		func(context.Context, string) ([]*client.DiskInfoSimple, error) {
+			mgr.drainsMu.Lock()
+			mgr.drains[testDrainHost] = again
+			mgr.drainsMu.Unlock()
+			return disks, nil
+		})
+	mgr.drainHosts(ctx)
+	require.Equal(t, again, mgr.drains[testDrainHost])
+}
+
+func TestDiskDropHostDrainStat(t *testing.T) {
+	ctx := context.Background()
+	mgr := newDiskDroper(t)
+	cmCli := mgr.clusterMgrCli.(*MockClusterMgrAPI)
+
+	_, err := mgr.HostDrainStat(ctx, testDrainHost)
+	require.True(t, errors.Is(err, errHostNotDraining))
+
+	mgr.drains[testDrainHost] = &client.HostDrainMeta{Host: testDrainHost, Concurrency: 1, Disks: []proto.DiskID{1, 2, 3, 4}, Ctime: "ctime"}
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(nil, errMock)
+	_, err = mgr.HostDrainStat(ctx, testDrainHost)
+	require.True(t, errors.Is(err, errMock))
+
+	// disk 1 is dropped, disk 2 is dropping, disk 3 is pending and disk 4 is removed
+	disks := newHostDrainDisks(testDrainHost, proto.DiskStatusDropped, proto.DiskStatusNormal, proto.DiskStatusNormal)
+	mgr.allDisks.add(&dropDisk{DiskInfoSimple: disks[0]})
+	dropping := &dropDisk{DiskInfoSimple: disks[1]}
+	dropping.setCollecting(true)
+	dropping.addUndoneCnt(4)
+	mgr.allDisks.add(dropping)
+	cmCli.EXPECT().ListHostDisks(any, testDrainHost).Return(disks, nil)
+	stat, err := mgr.HostDrainStat(ctx, testDrainHost)
+	require.NoError(t, err)
+	require.Equal(t, &api.HostDrainStat{
+		Host:          testDrainHost,
+		Concurrency:   1,
+		Ctime:         "ctime",
+		PendingDisks:  1,
+		DroppingDisks: 1,
+		DroppedDisks:  2,
+		Disks: []api.HostDrainDisk{
+			{DiskID: 1, State: api.HostDrainDiskDropped, TotalChunks: 10, MigratedChunks: 10},
+			{DiskID: 2, State: api.HostDrainDiskDropping, TotalChunks: 10, MigratedChunks: 6},
+			{DiskID: 3, State: api.HostDrainDiskPending, TotalChunks: 10},
+			{DiskID: 4, State: api.HostDrainDiskDropped},
+		},
+	}, stat)
+}
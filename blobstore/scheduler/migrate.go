@@ -62,6 +62,9 @@ type Migrator interface {
 	ReportWorkerTaskStats(st *api.TaskReportArgs)
 	StatQueueTaskCnt() (inited, prepared, completed int)
 	Stats() api.MigrateTasksStat
+	// queue params
+	QueueParams() api.QueueParams
+	SetQueueParams(params api.QueueParams)
 	// control
 	taskswitch.ISwitcher
 	closer.Closer
@@ -306,6 +309,8 @@ func (m *migratedDisks) list() (disks []*migratedDisk) {
 type MigrateConfig struct {
 	ClusterID proto.ClusterID `json:"-"` // fill in config.go
 	base.TaskCommonConfig
+	// shared by all types of tasks, fill in startup.go
+	quarantine *destQuarantine
 
 	lockFailHandleFunc lockFailFunc
 	// clear junk tasks
@@ -316,6 +321,11 @@ type MigrateConfig struct {
 	loadTaskCallback taskLimitFunc
 }
 
+// dstAllocator returns volume unit allocator of destination of the task type
+func (conf *MigrateConfig) dstAllocator(cli client.ClusterMgrAPI, taskType proto.TaskType) base.IAllocVunit {
+	return conf.quarantine.allocator(cli, newVunitAllocator(cli, taskType))
+}
+
 type clearJunkTasksFunc func(ctx context.Context, tasks []*proto.MigrateTask) error
 
 var defaultClearJunkTasksFunc = func(ctx context.Context, tasks []*proto.MigrateTask) error {
@@ -333,6 +343,7 @@ var defaultDiskTaskLimitFunc = func(diskId proto.DiskID) {
 // MigrateMgr migrate manager
 type MigrateMgr struct {
 	closer.Closer
+	*queueParams
 
 	taskType           proto.TaskType
 	diskMigratingVuids *diskMigratingVuids
@@ -349,6 +360,8 @@ type MigrateMgr struct {
 
 	finishTaskCounter counter.Counter
 	taskStatsMgr      *base.TaskStatsMgr
+	slowTasks         *slowTaskChecker
+	corruptedTasks    []string // corrupted tasks skipped when loading, kept in db for checking
 
 	cfg *MigrateConfig
 
@@ -381,7 +394,7 @@ func NewMigrateMgr(
 		volumeUpdater: volumeUpdater,
 
 		prepareQueue: base.NewTaskQueue(time.Duration(conf.PrepareQueueRetryDelayS) * time.Second),
-		workQueue:    base.NewWorkerTaskQueue(time.Duration(conf.CancelPunishDurationS) * time.Second),
+		workQueue:    base.NewWorkerTaskQueue(time.Duration(conf.CancelPunishDurationS)*time.Second, time.Duration(conf.TaskLeaseExpiredS)*time.Second),
 		finishQueue:  base.NewTaskQueue(time.Duration(conf.FinishQueueRetryDelayS) * time.Second),
 		deletedTasks: newDiskMigratedTasks(),
 
@@ -398,7 +411,10 @@ func NewMigrateMgr(
 	if mgr.lockVolFailHandleFunc == nil {
 		mgr.lockVolFailHandleFunc = mgr.handleLockVolFail
 	}
+	mgr.queueParams = newQueueParams(taskType, &conf.TaskCommonConfig, mgr.prepareQueue, mgr.workQueue, mgr.finishQueue)
 	mgr.taskStatsMgr = base.NewTaskStatsMgrAndRun(conf.ClusterID, taskType, mgr)
+	mgr.slowTasks = newSlowTaskChecker(taskType, &conf.TaskCommonConfig, mgr.workQueue, mgr.taskStatsMgr,
+		conf.quarantine.allocator(clusterMgrCli, newVunitAllocator(clusterMgrCli, taskType)), mgr)
 	return mgr
 }
 
@@ -425,11 +441,16 @@ func (mgr *MigrateMgr) Load() (err error) {
 		return
 	}
 	var junkTasks []*proto.MigrateTask
+	loadTasks := tasks[:0]
 	for i := range tasks {
 		if mgr.isJunkTask(disks, tasks[i]) {
 			junkTasks = append(junkTasks, tasks[i])
 			continue
 		}
+		loadTasks = append(loadTasks, tasks[i])
+	}
+	tasks, mgr.corruptedTasks = skipCorruptedTasks(ctx, loadTasks)
+	for i := range tasks {
 		if tasks[i].Running() {
 			err = base.VolTaskLockerInst().TryLock(ctx, tasks[i].SourceVuid.Vid())
 			if err != nil {
@@ -447,17 +468,31 @@ func (mgr *MigrateMgr) Load() (err error) {
 			mgr.prepareQueue.PushTask(tasks[i].TaskID, tasks[i])
 		case proto.MigrateStatePrepared:
 			mgr.workQueue.AddPreparedTask(tasks[i].SourceIDC, tasks[i].TaskID, tasks[i])
+			mgr.slowTasks.Start(tasks[i].SourceIDC, tasks[i].TaskID)
 		case proto.MigrateStateWorkCompleted:
 			mgr.finishQueue.PushTask(tasks[i].TaskID, tasks[i])
-		case proto.MigrateStateFinished, proto.MigrateStateFinishedInAdvance:
-			return fmt.Errorf("task should be deleted from db: task[%+v]", tasks[i])
-		default:
-			return fmt.Errorf("unexpect migrate state: task[%+v]", tasks[i])
+			mgr.slowTasks.Start(tasks[i].SourceIDC, tasks[i].TaskID)
 		}
 	}
 	return mgr.clearJunkTasksCallBack(ctx, junkTasks)
 }
 
+// skipCorruptedTasks returns the tasks to load and the ids of corrupted ones,
+// the corrupted tasks are neither loaded nor deleted so that they can be checked manually
+func skipCorruptedTasks(ctx context.Context, tasks []*proto.MigrateTask) (loadTasks []*proto.MigrateTask, corrupted []string) {
+	span := trace.SpanFromContextSafe(ctx)
+	loadTasks = tasks[:0]
+	for _, task := range tasks {
+		if err := task.CheckStoredState(); err != nil {
+			span.Errorf("skip corrupted task in db: task_type[%s], err[%+v]", task.TaskType, err)
+			corrupted = append(corrupted, task.TaskID)
+			continue
+		}
+		loadTasks = append(loadTasks, task)
+	}
+	return
+}
+
 func (mgr *MigrateMgr) isJunkTask(disks *migratingDisks, task *proto.MigrateTask) bool {
 	switch mgr.taskType {
 	case proto.TaskTypeDiskDrop:
@@ -491,13 +526,14 @@ func (mgr *MigrateMgr) listMigratingDisks(ctx context.Context) (*migratingDisks,
 func (mgr *MigrateMgr) Run() {
 	go mgr.prepareTaskLoop()
 	go mgr.finishTaskLoop()
+	go mgr.slowTasks.checkLoop(mgr.Closer.Done())
 }
 
 func (mgr *MigrateMgr) prepareTaskLoop() {
 	for {
 		mgr.taskSwitch.WaitEnable()
 		todo, doing := mgr.workQueue.StatsTasks()
-		if todo+doing >= mgr.cfg.WorkQueueSize {
+		if todo+doing >= mgr.workQueueSize() {
 			time.Sleep(prepareTaskPause)
 			continue
 		}
@@ -562,8 +598,7 @@ func (mgr *MigrateMgr) prepareTask() (err error) {
 			return err
 		}
 
-		mgr.finishTaskInAdvance(ctx, migTask, "volume has migrated")
-		return nil
+		return mgr.finishTaskInAdvance(ctx, migTask, "volume has migrated")
 	}
 
 	// lock volume
@@ -578,7 +613,8 @@ func (mgr *MigrateMgr) prepareTask() (err error) {
 	}
 
 	// alloc volume unit
-	ret, err := base.AllocVunitSafe(ctx, mgr.clusterMgrCli, migTask.SourceVuid, migTask.Sources)
+	allocator := mgr.cfg.dstAllocator(mgr.clusterMgrCli, mgr.taskType)
+	ret, err := base.AllocVunitSafe(ctx, allocator, migTask.SourceVuid, migTask.Sources)
 	if err != nil {
 		span.Errorf("alloc volume unit failed: err[%+v]", err)
 		return
@@ -587,7 +623,11 @@ func (mgr *MigrateMgr) prepareTask() (err error) {
 	migTask.CodeMode = volInfo.CodeMode
 	migTask.Sources = volInfo.VunitLocations
 	migTask.SetDestination(ret.Location())
-	migTask.State = proto.MigrateStatePrepared
+	migTask.DestinationIDC = ret.Idc
+	if err = migTask.TransitState(proto.MigrateStatePrepared); err != nil {
+		span.Errorf("prepare task failed: err[%+v]", err)
+		return
+	}
 
 	// update db
 	base.InsistOn(ctx, "migrate prepare task update task tbl", func() error {
@@ -596,6 +636,7 @@ func (mgr *MigrateMgr) prepareTask() (err error) {
 
 	// send task to worker queue and remove task in prepareQueue
 	mgr.workQueue.AddPreparedTask(migTask.SourceIDC, migTask.TaskID, migTask)
+	mgr.slowTasks.Start(migTask.SourceIDC, migTask.TaskID)
 	_ = mgr.prepareQueue.RemoveTask(migTask.TaskID)
 
 	span.Infof("prepare task success: task_id[%s], state[%v]", migTask.TaskID, migTask.State)
@@ -629,8 +670,9 @@ func (mgr *MigrateMgr) finishTask() (err error) {
 	migrateTask := task.(*proto.MigrateTask).Copy()
 	span.Infof("finish task phase: task_id[%s], state[%v]", migrateTask.TaskID, migrateTask.State)
 
-	if migrateTask.State != proto.MigrateStateWorkCompleted {
-		span.Panicf("unexpect task state: task_id[%s], expect state[%d], actual state[%d]", proto.MigrateStateWorkCompleted, migrateTask.State)
+	if err = migrateTask.State.CheckTransition(proto.MigrateStateFinished); err != nil {
+		span.Errorf("finish task failed: task_id[%s], err[%+v]", migrateTask.TaskID, err)
+		return
 	}
 
 	// because competed task did not persisted to the database, so in finish phase need to do it
@@ -674,7 +716,10 @@ func (mgr *MigrateMgr) finishTask() (err error) {
 		return
 	}
 	// remove task from clustermgr
-	migrateTask.State = proto.MigrateStateFinished
+	if err = migrateTask.TransitState(proto.MigrateStateFinished); err != nil {
+		span.Errorf("finish task failed: task_id[%s], err[%+v]", migrateTask.TaskID, err)
+		return
+	}
 	base.InsistOn(ctx, "migrate finish task update task tbl", func() error {
 		return mgr.clusterMgrCli.DeleteMigrateTask(ctx, migrateTask.TaskID)
 	})
@@ -689,12 +734,13 @@ func (mgr *MigrateMgr) finishTask() (err error) {
 	mgr.deleteMigratingVuid(migrateTask.SourceDiskID, migrateTask.SourceVuid)
 
 	mgr.finishTaskCounter.Add()
+	elapsed, _ := mgr.slowTasks.Finish(migrateTask.TaskID)
 
 	// add delete task and check it again
 	mgr.addDeletedTask(migrateTask)
 	mgr.finishTaskCallback(migrateTask.SourceDiskID)
 
-	span.Infof("finish task phase success: task_id[%s], state[%v]", migrateTask.TaskID, migrateTask.State)
+	span.Infof("finish task phase success: task_id[%s], state[%v], elapsed[%s]", migrateTask.TaskID, migrateTask.State, elapsed)
 	return
 }
 
@@ -718,15 +764,17 @@ func (mgr *MigrateMgr) AddTask(ctx context.Context, task *proto.MigrateTask) {
 }
 
 func (mgr *MigrateMgr) handleLockVolFail(ctx context.Context, task *proto.MigrateTask) error {
-	mgr.finishTaskInAdvance(ctx, task, "lock volume fail")
-	return nil
+	return mgr.finishTaskInAdvance(ctx, task, "lock volume fail")
 }
 
-func (mgr *MigrateMgr) finishTaskInAdvance(ctx context.Context, task *proto.MigrateTask, reason string) {
+func (mgr *MigrateMgr) finishTaskInAdvance(ctx context.Context, task *proto.MigrateTask, reason string) error {
 	span := trace.SpanFromContextSafe(ctx)
 	span.Infof("finish task in advance: task_id[%s], task[%+v]", task.TaskID, task)
 
-	task.State = proto.MigrateStateFinishedInAdvance
+	if err := task.TransitState(proto.MigrateStateFinishedInAdvance); err != nil {
+		span.Errorf("finish task in advance failed: err[%+v]", err)
+		return err
+	}
 	task.FinishAdvanceReason = reason
 
 	base.InsistOn(ctx, "migrate finish task in advance update tbl", func() error {
@@ -738,11 +786,13 @@ func (mgr *MigrateMgr) finishTaskInAdvance(ctx context.Context, task *proto.Migr
 
 	mgr.finishTaskCounter.Add()
 	_ = mgr.prepareQueue.RemoveTask(task.TaskID)
+	mgr.slowTasks.Finish(task.TaskID)
 	mgr.addDeletedTask(task)
 
 	mgr.finishTaskCallback(task.SourceDiskID)
 
 	base.VolTaskLockerInst().Unlock(ctx, task.SourceVuid.Vid())
+	return nil
 }
 
 func (mgr *MigrateMgr) handleUpdateVolMappingFail(ctx context.Context, task *proto.MigrateTask, err error) error {
@@ -756,13 +806,19 @@ func (mgr *MigrateMgr) handleUpdateVolMappingFail(ctx context.Context, task *pro
 
 	if base.ShouldAllocAndRedo(code) {
 		span.Infof("realloc vunit and redo: task_id[%s]", task.TaskID)
-		newVunit, err := base.AllocVunitSafe(ctx, mgr.clusterMgrCli, task.SourceVuid, task.Sources)
+		mgr.cfg.quarantine.fail(ctx, task.Destination.DiskID)
+		allocator := mgr.cfg.dstAllocator(mgr.clusterMgrCli, mgr.taskType)
+		newVunit, err := base.AllocVunitSafe(ctx, allocator, task.SourceVuid, task.Sources)
 		if err != nil {
 			span.Errorf("realloc failed: vuid[%d], err[%+v]", task.SourceVuid, err)
 			return err
 		}
 		task.SetDestination(newVunit.Location())
-		task.State = proto.MigrateStatePrepared
+		task.DestinationIDC = newVunit.Idc
+		if err = task.TransitState(proto.MigrateStatePrepared); err != nil {
+			span.Errorf("redo task failed: err[%+v]", err)
+			return err
+		}
 		task.WorkerRedoCnt++
 
 		base.InsistOn(ctx, "migrate redo task update task tbl", func() error {
@@ -791,7 +847,7 @@ func (mgr *MigrateMgr) ClearDeletedTasks(diskID proto.DiskID) {
 // ClearDeletedTaskByID clear migrated task
 func (mgr *MigrateMgr) ClearDeletedTaskByID(diskID proto.DiskID, taskID string) {
 	switch mgr.taskType {
-	case proto.TaskTypeBalance: // only balance task need to clear by id
+	case proto.TaskTypeBalance, proto.TaskTypeColdMigrate: // only balance and cold migrate task need to clear by id
 		mgr.deletedTasks.deleteByID(diskID, taskID)
 	default:
 	}
@@ -809,7 +865,7 @@ func (mgr *MigrateMgr) DeletedTasks() []DeletedTask {
 
 func (mgr *MigrateMgr) addMigratingVuid(diskID proto.DiskID, vuid proto.Vuid, taskID string) {
 	switch mgr.taskType {
-	case proto.TaskTypeBalance: // only balance task need to add
+	case proto.TaskTypeBalance, proto.TaskTypeColdMigrate: // only balance and cold migrate task need to add
 		mgr.diskMigratingVuids.addMigratingVuid(diskID, vuid, taskID)
 	default:
 	}
@@ -817,7 +873,7 @@ func (mgr *MigrateMgr) addMigratingVuid(diskID proto.DiskID, vuid proto.Vuid, ta
 
 func (mgr *MigrateMgr) deleteMigratingVuid(diskID proto.DiskID, vuid proto.Vuid) {
 	switch mgr.taskType {
-	case proto.TaskTypeBalance: // only balance task need to add
+	case proto.TaskTypeBalance, proto.TaskTypeColdMigrate: // only balance and cold migrate task need to add
 		mgr.diskMigratingVuids.deleteMigratingVuid(diskID, vuid)
 	default:
 	}
@@ -825,7 +881,7 @@ func (mgr *MigrateMgr) deleteMigratingVuid(diskID proto.DiskID, vuid proto.Vuid)
 
 func (mgr *MigrateMgr) addDeletedTask(task *proto.MigrateTask) {
 	switch mgr.taskType {
-	case proto.TaskTypeDiskDrop, proto.TaskTypeBalance: // only disk drop and balance task need to add
+	case proto.TaskTypeDiskDrop, proto.TaskTypeBalance, proto.TaskTypeColdMigrate: // only disk drop, balance and cold migrate task need to add
 		mgr.deletedTasks.add(task.SourceDiskID, task.TaskID)
 	default:
 	}
@@ -849,10 +905,15 @@ func (mgr *MigrateMgr) Stats() api.MigrateTasksStat {
 	preparing, workerDoing, finishing := mgr.StatQueueTaskCnt()
 	finishedCnt := mgr.finishTaskCounter.Show()
 	increaseDataSize, increaseShardCnt := mgr.taskStatsMgr.Counters()
+	slowTasks := mgr.slowTasks.slowTaskIDs()
 	return api.MigrateTasksStat{
-		PreparingCnt:   preparing,
-		WorkerDoingCnt: workerDoing,
-		FinishingCnt:   finishing,
+		PreparingCnt:     preparing,
+		WorkerDoingCnt:   workerDoing,
+		FinishingCnt:     finishing,
+		SlowCnt:          len(slowTasks),
+		SlowTasks:        slowTasks,
+		QuarantinedDisks: mgr.cfg.quarantine.disks(),
+		CorruptedTasks:   mgr.corruptedTasks,
 		StatsPerMin: api.PerMinStats{
 			FinishedCnt:    fmt.Sprint(finishedCnt),
 			DataAmountByte: base.DataMountFormat(increaseDataSize),
@@ -869,9 +930,13 @@ func (mgr *MigrateMgr) AcquireTask(ctx context.Context, idc string) (task proto.
 		return task, proto.ErrTaskPaused
 	}
 
-	_, migTask, _ := mgr.workQueue.Acquire(idc)
+	_, migTask, leaseExpired, _ := mgr.workQueue.AcquireLease(idc)
 	if migTask != nil {
 		task = *migTask.(*proto.MigrateTask)
+		if leaseExpired {
+			mgr.taskStatsMgr.LeaseExpired()
+			span.Warnf("acquire %s task of expired lease: task_id[%s]", mgr.taskType, task.TaskID)
+		}
 		span.Infof("acquire %s taskId: %s", mgr.taskType, task.TaskID)
 		return task, nil
 	}
@@ -901,6 +966,7 @@ func (mgr *MigrateMgr) ReclaimTask(ctx context.Context, idc, taskID string,
 		span.Errorf("reclaim migrate task failed: task_type:[%s],task_id[%s], err[%+v]", mgr.taskType, taskID, err)
 		return err
 	}
+	mgr.cfg.quarantine.fail(ctx, oldDst.DiskID)
 
 	task, err := mgr.workQueue.Query(idc, taskID)
 	if err != nil {
@@ -926,7 +992,10 @@ func (mgr *MigrateMgr) CompleteTask(ctx context.Context, args *api.OperateTaskAr
 	}
 
 	t := completeTask.(*proto.MigrateTask)
-	t.State = proto.MigrateStateWorkCompleted
+	if err = t.TransitState(proto.MigrateStateWorkCompleted); err != nil {
+		span.Errorf("complete migrate task failed: err[%+v]", err)
+		return err
+	}
 
 	err = mgr.clusterMgrCli.UpdateMigrateTask(ctx, t)
 	if err != nil {
@@ -97,13 +97,16 @@ func TestMigrateMigrateLoad(t *testing.T) {
 	{
 		t1 := mockGenMigrateTask(proto.TaskTypeManualMigrate, "z2", 8, 104, proto.MigrateStateFinished, MockMigrateVolInfoMap)
 		t2 := mockGenMigrateTask(proto.TaskTypeManualMigrate, "z0", 4, 105, proto.MigrateStateFinishedInAdvance, MockMigrateVolInfoMap)
-		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t1}, nil)
+		t3 := mockGenMigrateTask(proto.TaskTypeManualMigrate, "z0", 4, 100, proto.MigrateStateInited, MockMigrateVolInfoMap)
+		// corrupted tasks are skipped and shown in stats
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t1, t2, t3}, nil)
 		err := mgr.Load()
-		require.Error(t, err)
-
-		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t2}, nil)
-		err = mgr.Load()
-		require.Error(t, err)
+		require.NoError(t, err)
+		require.Equal(t, []string{t1.TaskID, t2.TaskID}, mgr.Stats().CorruptedTasks)
+		_, ok := mgr.prepareQueue.Query(t1.TaskID)
+		require.False(t, ok)
+		_, ok = mgr.prepareQueue.Query(t3.TaskID)
+		require.True(t, ok)
 	}
 	{
 		t2 := mockGenMigrateTask(proto.TaskTypeManualMigrate, "z0", 5, 101, proto.MigrateStatePrepared, MockMigrateVolInfoMap)
@@ -115,7 +118,8 @@ func TestMigrateMigrateLoad(t *testing.T) {
 		t4 := mockGenMigrateTask(proto.TaskTypeManualMigrate, "z2", 7, 103, 100, MockMigrateVolInfoMap)
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListAllMigrateTasks(any, any).Return([]*proto.MigrateTask{t4}, nil)
 		err = mgr.Load()
-		require.Error(t, err)
+		require.NoError(t, err)
+		require.Equal(t, []string{t4.TaskID}, mgr.Stats().CorruptedTasks)
 	}
 	{
 		mgr := newMigrateMgr(t)
@@ -243,13 +247,12 @@ func TestFinishMigrateTask(t *testing.T) {
 		require.True(t, errors.Is(err, base.ErrNoTaskInQueue))
 	}
 	{
-		// panic :status not eql proto.MigrateStateWorkCompleted
+		// status not eql proto.MigrateStateWorkCompleted
 		mgr := newMigrateMgr(t)
 		t1 := mockGenMigrateTask(proto.TaskTypeManualMigrate, "z0", 4, 100, proto.MigrateStatePrepared, MockMigrateVolInfoMap)
 		mgr.finishQueue.PushTask(t1.TaskID, t1)
-		require.Panics(t, func() {
-			mgr.finishTask()
-		})
+		err := mgr.finishTask()
+		require.True(t, errors.Is(err, proto.ErrInvalidMigrateState))
 	}
 	{
 		{
@@ -359,6 +362,20 @@ func TestAcquireMigrateTask(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, t1.TaskID, task.TaskID)
 	}
+	{
+		// task of expired lease is acquired again
+		mgr := newMigrateMgr(t)
+		mgr.workQueue.SetLeaseExpiredS(time.Millisecond)
+		mgr.taskSwitch.(*mocks.MockSwitcher).EXPECT().Enabled().Times(2).Return(true)
+		t1 := mockGenMigrateTask(proto.TaskTypeManualMigrate, idc, 4, 100, proto.MigrateStatePrepared, MockMigrateVolInfoMap)
+		mgr.workQueue.AddPreparedTask(idc, t1.TaskID, t1)
+		_, err := mgr.AcquireTask(ctx, idc)
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+		task, err := mgr.AcquireTask(ctx, idc)
+		require.NoError(t, err)
+		require.Equal(t, t1.TaskID, task.TaskID)
+	}
 }
 
 func TestCancelMigrateTask(t *testing.T) {
@@ -406,7 +423,60 @@ func TestReclaimMigrateTask(t *testing.T) {
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().UpdateMigrateTask(any, any).Return(nil)
 		err = mgr.ReclaimTask(ctx, idc, t1.TaskID, t1.Sources, t1.Destination, &client.AllocVunitInfo{})
 		require.NoError(t, err)
+		require.Nil(t, mgr.Stats().QuarantinedDisks)
 	}
+	{
+		// destination is quarantined
+		mgr := newMigrateMgr(t)
+		mgr.cfg.quarantine = newDestQuarantine(&DestQuarantineConfig{FailureThreshold: 1, FailureWindowS: 60, QuarantineS: 60})
+		t1 := mockGenMigrateTask(proto.TaskTypeManualMigrate, idc, 4, 100, proto.MigrateStatePrepared, MockMigrateVolInfoMap)
+		mgr.workQueue.AddPreparedTask(idc, t1.TaskID, t1)
+		oldDst := t1.Destination
+
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().UpdateMigrateTask(any, any).Return(nil)
+		err := mgr.ReclaimTask(ctx, idc, t1.TaskID, t1.Sources, oldDst, &client.AllocVunitInfo{VunitLocation: proto.VunitLocation{DiskID: 1000}})
+		require.NoError(t, err)
+		require.Equal(t, []proto.DiskID{oldDst.DiskID}, mgr.Stats().QuarantinedDisks)
+	}
+}
+
+func TestMigrateSlowTask(t *testing.T) {
+	idc := "z0"
+	mgr := newMigrateMgr(t)
+	mgr.slowTasks.TaskTimer = base.NewTaskTimer(time.Millisecond)
+	mgr.slowTasks.reclaim = true
+
+	t1 := mockGenMigrateTask(proto.TaskTypeBalance, idc, 4, 100, proto.MigrateStatePrepared, MockMigrateVolInfoMap)
+	t2 := mockGenMigrateTask(proto.TaskTypeBalance, idc, 5, 101, proto.MigrateStatePrepared, MockMigrateVolInfoMap)
+	for _, task := range []*proto.MigrateTask{t1, t2} {
+		mgr.workQueue.AddPreparedTask(idc, task.TaskID, task)
+		mgr.slowTasks.Start(idc, task.TaskID)
+	}
+	_, _, exist := mgr.workQueue.Acquire(idc)
+	require.True(t, exist)
+	time.Sleep(2 * time.Millisecond)
+
+	stats := mgr.Stats()
+	require.Equal(t, 2, stats.SlowCnt)
+	require.ElementsMatch(t, []string{t1.TaskID, t2.TaskID}, stats.SlowTasks)
+
+	// only the acquired task is reclaimed
+	oldDst := t1.Destination
+	newDst := &client.AllocVunitInfo{VunitLocation: proto.VunitLocation{Vuid: oldDst.Vuid, DiskID: 9999}}
+	mgr.taskSwitch.(*mocks.MockSwitcher).EXPECT().Enabled().Return(true)
+	mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().AllocVolumeUnit(any, any).Return(newDst, nil)
+	mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().UpdateMigrateTask(any, any).Return(nil)
+	mgr.slowTasks.check()
+	task, err := mgr.workQueue.Query(idc, t1.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, newDst.Location(), task.GetDestination())
+	// the old worker can not complete the task any more
+	_, err = mgr.workQueue.Complete(idc, t1.TaskID, t1.Sources, oldDst)
+	require.ErrorIs(t, err, base.ErrUnmatchedVuids)
+
+	_, ok := mgr.slowTasks.Finish(t1.TaskID)
+	require.True(t, ok)
+	require.Equal(t, 1, mgr.Stats().SlowCnt)
 }
 
 func TestCompleteMigrateTask(t *testing.T) {
@@ -0,0 +1,207 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/scheduler/base"
+)
+
+const (
+	minQueueLeaseExpiredS = proto.TaskRenewalPeriodS + proto.RenewalTimeoutS + 1
+	maxQueueLeaseExpiredS = 600
+	maxQueueDelayS        = 3600
+	maxWorkQueueSize      = 10000
+)
+
+var errIllegalQueueParams = errors.New("illegal queue params")
+
+// queueParams queue parameters of migrate manager which can be adjusted at runtime
+type queueParams struct {
+	mu     sync.RWMutex
+	params api.QueueParams
+
+	prepareQueue *base.TaskQueue
+	workQueue    *base.WorkerTaskQueue
+	finishQueue  *base.TaskQueue
+}
+
+func newQueueParams(taskType proto.TaskType, cfg *base.TaskCommonConfig,
+	prepareQueue *base.TaskQueue, workQueue *base.WorkerTaskQueue, finishQueue *base.TaskQueue) *queueParams {
+	leaseExpiredS := cfg.TaskLeaseExpiredS
+	if leaseExpiredS <= 0 {
+		leaseExpiredS = proto.TaskLeaseExpiredS
+	}
+	return &queueParams{
+		params: api.QueueParams{
+			TaskType:                taskType,
+			LeaseExpiredS:           leaseExpiredS,
+			PrepareQueueRetryDelayS: cfg.PrepareQueueRetryDelayS,
+			FinishQueueRetryDelayS:  cfg.FinishQueueRetryDelayS,
+			CancelPunishDurationS:   cfg.CancelPunishDurationS,
+			WorkQueueSize:           cfg.WorkQueueSize,
+		},
+		prepareQueue: prepareQueue,
+		workQueue:    workQueue,
+		finishQueue:  finishQueue,
+	}
+}
+
+func (q *queueParams) workQueueSize() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.params.WorkQueueSize
+}
+
+// QueueParams returns current queue params
+func (q *queueParams) QueueParams() api.QueueParams {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.params
+}
+
+// SetQueueParams applies the checked queue params to queues
+func (q *queueParams) SetQueueParams(params api.QueueParams) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	params.TaskType = q.params.TaskType
+	q.params = params
+	q.prepareQueue.SetRetryDelay(time.Duration(params.PrepareQueueRetryDelayS) * time.Second)
+	q.finishQueue.SetRetryDelay(time.Duration(params.FinishQueueRetryDelayS) * time.Second)
+	q.workQueue.SetCancelPunishDuration(time.Duration(params.CancelPunishDurationS) * time.Second)
+	q.workQueue.SetLeaseExpiredS(time.Duration(params.LeaseExpiredS) * time.Second)
+}
+
+// mergeQueueParams modifies current params with the non nil fields of args and checks them
+func mergeQueueParams(current api.QueueParams, args *api.QueueParamsSetArgs) (api.QueueParams, error) {
+	merged := current
+	for _, field := range []struct {
+		name     string
+		val      *int
+		min, max int
+		dst      *int
+	}{
+		{"lease_expired_s", args.LeaseExpiredS, minQueueLeaseExpiredS, maxQueueLeaseExpiredS, &merged.LeaseExpiredS},
+		{"prepare_queue_retry_delay_s", args.PrepareQueueRetryDelayS, 0, maxQueueDelayS, &merged.PrepareQueueRetryDelayS},
+		{"finish_queue_retry_delay_s", args.FinishQueueRetryDelayS, 0, maxQueueDelayS, &merged.FinishQueueRetryDelayS},
+		{"cancel_punish_duration_s", args.CancelPunishDurationS, 0, maxQueueDelayS, &merged.CancelPunishDurationS},
+		{"work_queue_size", args.WorkQueueSize, 1, maxWorkQueueSize, &merged.WorkQueueSize},
+	} {
+		if field.val == nil {
+			continue
+		}
+		if *field.val < field.min || *field.val > field.max {
+			return current, fmt.Errorf("%w: %s should be in [%d, %d]", errIllegalQueueParams, field.name, field.min, field.max)
+		}
+		*field.dst = *field.val
+	}
+	return merged, nil
+}
+
+// queueParamsSetArgs returns args setting all fields of params
+func queueParamsSetArgs(params *api.QueueParams) *api.QueueParamsSetArgs {
+	return &api.QueueParamsSetArgs{
+		TaskType:                params.TaskType,
+		LeaseExpiredS:           &params.LeaseExpiredS,
+		PrepareQueueRetryDelayS: &params.PrepareQueueRetryDelayS,
+		FinishQueueRetryDelayS:  &params.FinishQueueRetryDelayS,
+		CancelPunishDurationS:   &params.CancelPunishDurationS,
+		WorkQueueSize:           &params.WorkQueueSize,
+	}
+}
+
+// HTTPQueueParams returns queue params of migrate task type
+func (svr *Service) HTTPQueueParams(c *rpc.Context) {
+	args := new(api.QueueParamsArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	mgr, err := svr.mgrByType(args.TaskType)
+	if err != nil {
+		c.RespondError(err)
+		return
+	}
+	params := mgr.QueueParams()
+	c.RespondJSON(&params)
+}
+
+// HTTPQueueParamsSet modifies and persists queue params of migrate task type
+func (svr *Service) HTTPQueueParamsSet(c *rpc.Context) {
+	args := new(api.QueueParamsSetArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	mgr, err := svr.mgrByType(args.TaskType)
+	if err != nil {
+		c.RespondError(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	span := trace.SpanFromContextSafe(ctx)
+	params, err := mergeQueueParams(mgr.QueueParams(), args)
+	if err != nil {
+		c.RespondError(rpc.NewError(http.StatusBadRequest, "illegal_queue_params", err))
+		return
+	}
+	if err = svr.clusterMgrCli.SetQueueParams(ctx, &params); err != nil {
+		span.Errorf("persist queue params failed: params[%+v], err[%+v]", params, err)
+		c.RespondError(err)
+		return
+	}
+	mgr.SetQueueParams(params)
+	span.Infof("set queue params success: params[%+v]", params)
+	c.Respond()
+}
+
+// loadQueueParams applies persisted queue params to migrate managers
+func (svr *Service) loadQueueParams() {
+	span, ctx := trace.StartSpanFromContext(context.Background(), "loadQueueParams")
+	for _, taskType := range []proto.TaskType{
+		proto.TaskTypeDiskRepair, proto.TaskTypeBalance, proto.TaskTypeDiskDrop,
+		proto.TaskTypeManualMigrate, proto.TaskTypeColdMigrate,
+	} {
+		mgr, err := svr.mgrByType(taskType)
+		if err != nil {
+			continue
+		}
+		persisted, err := svr.clusterMgrCli.GetQueueParams(ctx, taskType)
+		if err != nil {
+			if rpc.DetectStatusCode(err) != http.StatusNotFound {
+				span.Warnf("get queue params failed: task_type[%s], err[%+v]", taskType, err)
+			}
+			continue
+		}
+		params, err := mergeQueueParams(mgr.QueueParams(), queueParamsSetArgs(persisted))
+		if err != nil {
+			span.Warnf("ignore persisted queue params: task_type[%s], err[%+v]", taskType, err)
+			continue
+		}
+		mgr.SetQueueParams(params)
+		span.Infof("load queue params success: params[%+v]", params)
+	}
+}
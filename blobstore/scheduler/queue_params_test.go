@@ -0,0 +1,125 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+)
+
+func TestMergeQueueParams(t *testing.T) {
+	current := api.QueueParams{
+		TaskType:                proto.TaskTypeBalance,
+		LeaseExpiredS:           10,
+		PrepareQueueRetryDelayS: 10,
+		FinishQueueRetryDelayS:  10,
+		CancelPunishDurationS:   20,
+		WorkQueueSize:           20,
+	}
+
+	merged, err := mergeQueueParams(current, &api.QueueParamsSetArgs{})
+	require.NoError(t, err)
+	require.Equal(t, current, merged)
+
+	merged, err = mergeQueueParams(current, &api.QueueParamsSetArgs{LeaseExpiredS: intPtr(30), WorkQueueSize: intPtr(5)})
+	require.NoError(t, err)
+	require.Equal(t, 30, merged.LeaseExpiredS)
+	require.Equal(t, 5, merged.WorkQueueSize)
+	require.Equal(t, current.CancelPunishDurationS, merged.CancelPunishDurationS)
+
+	// set to zero
+	merged, err = mergeQueueParams(current, &api.QueueParamsSetArgs{PrepareQueueRetryDelayS: intPtr(0), CancelPunishDurationS: intPtr(0)})
+	require.NoError(t, err)
+	require.Equal(t, 0, merged.PrepareQueueRetryDelayS)
+	require.Equal(t, 0, merged.CancelPunishDurationS)
+	require.Equal(t, current.FinishQueueRetryDelayS, merged.FinishQueueRetryDelayS)
+
+	for _, args := range []api.QueueParamsSetArgs{
+		{LeaseExpiredS: intPtr(proto.TaskRenewalPeriodS)},
+		{LeaseExpiredS: intPtr(maxQueueLeaseExpiredS + 1)},
+		{PrepareQueueRetryDelayS: intPtr(-1)},
+		{FinishQueueRetryDelayS: intPtr(maxQueueDelayS + 1)},
+		{CancelPunishDurationS: intPtr(-1)},
+		{WorkQueueSize: intPtr(0)},
+		{WorkQueueSize: intPtr(maxWorkQueueSize + 1)},
+	} {
+		_, err = mergeQueueParams(current, &args)
+		require.True(t, errors.Is(err, errIllegalQueueParams))
+	}
+
+	// all fields of persisted params are applied
+	persisted := api.QueueParams{TaskType: proto.TaskTypeBalance, LeaseExpiredS: 30, WorkQueueSize: 5}
+	merged, err = mergeQueueParams(current, queueParamsSetArgs(&persisted))
+	require.NoError(t, err)
+	require.Equal(t, persisted, merged)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestMigrateMgrQueueParams(t *testing.T) {
+	mgr := newMigrateMgr(t)
+	params := mgr.QueueParams()
+	require.Equal(t, proto.TaskTypeBalance, params.TaskType)
+	require.Equal(t, proto.TaskLeaseExpiredS, params.LeaseExpiredS)
+	require.Equal(t, 3, mgr.workQueueSize())
+
+	params.WorkQueueSize = 10
+	params.TaskType = proto.TaskTypeDiskDrop
+	mgr.SetQueueParams(params)
+	require.Equal(t, 10, mgr.workQueueSize())
+	require.Equal(t, proto.TaskTypeBalance, mgr.QueueParams().TaskType)
+
+	repairMgr := newDiskRepairer(t)
+	require.Equal(t, proto.TaskTypeDiskRepair, repairMgr.QueueParams().TaskType)
+}
+
+func TestServiceLoadQueueParams(t *testing.T) {
+	ctr := gomock.NewController(t)
+	clusterMgrCli := NewMockClusterMgrAPI(ctr)
+	svr := &Service{
+		balanceMgr:    NewMockMigrater(ctr),
+		diskDropMgr:   NewMockMigrater(ctr),
+		diskRepairMgr: NewMockMigrater(ctr),
+		manualMigMgr:  NewMockMigrater(ctr),
+		coldMigMgr:    NewMockMigrater(ctr),
+		clusterMgrCli: clusterMgrCli,
+	}
+	current := api.QueueParams{LeaseExpiredS: 10, WorkQueueSize: 20}
+	notFound := rpc.NewError(http.StatusNotFound, "NotFound", errMock)
+
+	// disk repair loaded
+	clusterMgrCli.EXPECT().GetQueueParams(any, proto.TaskTypeDiskRepair).Return(&api.QueueParams{LeaseExpiredS: 10, WorkQueueSize: 5}, nil)
+	svr.diskRepairMgr.(*MockMigrater).EXPECT().QueueParams().Return(current)
+	svr.diskRepairMgr.(*MockMigrater).EXPECT().SetQueueParams(api.QueueParams{LeaseExpiredS: 10, WorkQueueSize: 5})
+	// balance illegal
+	clusterMgrCli.EXPECT().GetQueueParams(any, proto.TaskTypeBalance).Return(&api.QueueParams{LeaseExpiredS: 1}, nil)
+	svr.balanceMgr.(*MockMigrater).EXPECT().QueueParams().Return(current)
+	// others not found or failed
+	clusterMgrCli.EXPECT().GetQueueParams(any, proto.TaskTypeDiskDrop).Return(nil, notFound)
+	clusterMgrCli.EXPECT().GetQueueParams(any, proto.TaskTypeManualMigrate).Return(nil, errMock)
+	clusterMgrCli.EXPECT().GetQueueParams(any, proto.TaskTypeColdMigrate).Return(nil, notFound)
+
+	svr.loadQueueParams()
+}
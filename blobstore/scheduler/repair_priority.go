@@ -0,0 +1,130 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+type listDiskVolumeUnits interface {
+	ListDiskVolumeUnits(ctx context.Context, diskID proto.DiskID) ([]*client.VunitInfoSimple, error)
+}
+
+// lostShardsRanker counts the volume units of each volume on the broken and repairing disks,
+// the volumes lost more shards have lower remaining redundancy and are repaired first
+type lostShardsRanker struct {
+	mu       sync.RWMutex
+	disks    []proto.DiskID
+	vols     map[proto.Vid]int
+	diskVols map[proto.DiskID]int // the most lost shards of volumes on the disk
+}
+
+func newLostShardsRanker() *lostShardsRanker {
+	return &lostShardsRanker{
+		vols:     make(map[proto.Vid]int),
+		diskVols: make(map[proto.DiskID]int),
+	}
+}
+
+// refresh recounts the lost shards if the broken disks changed, and returns true if recounted,
+// all volumes lost the same shards if there is only one broken disk
+func (r *lostShardsRanker) refresh(ctx context.Context, cli listDiskVolumeUnits, disks []*client.DiskInfoSimple) (bool, error) {
+	diskIDs := make([]proto.DiskID, 0, len(disks))
+	seen := make(map[proto.DiskID]struct{}, len(disks))
+	for _, disk := range disks {
+		if _, ok := seen[disk.DiskID]; ok {
+			continue
+		}
+		seen[disk.DiskID] = struct{}{}
+		diskIDs = append(diskIDs, disk.DiskID)
+	}
+	sort.Slice(diskIDs, func(i, j int) bool { return diskIDs[i] < diskIDs[j] })
+
+	r.mu.RLock()
+	unchanged := equalDiskIDs(r.disks, diskIDs)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	vols := make(map[proto.Vid]int)
+	diskVols := make(map[proto.DiskID]int)
+	if len(diskIDs) > 1 {
+		diskVids := make(map[proto.DiskID][]proto.Vid, len(diskIDs))
+		for _, diskID := range diskIDs {
+			vunits, err := cli.ListDiskVolumeUnits(ctx, diskID)
+			if err != nil {
+				return false, err
+			}
+			for _, vunit := range vunits {
+				vid := vunit.Vuid.Vid()
+				vols[vid]++
+				diskVids[diskID] = append(diskVids[diskID], vid)
+			}
+		}
+		for diskID, vids := range diskVids {
+			for _, vid := range vids {
+				if vols[vid] > diskVols[diskID] {
+					diskVols[diskID] = vols[vid]
+				}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.disks = diskIDs
+	r.vols = vols
+	r.diskVols = diskVols
+	r.mu.Unlock()
+	return true, nil
+}
+
+// ranked returns false if the volumes have no difference in priority
+func (r *lostShardsRanker) ranked() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.vols) > 0
+}
+
+// volumePriority returns the lost shards of volume on the broken disks
+func (r *lostShardsRanker) volumePriority(vid proto.Vid) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.vols[vid]
+}
+
+// diskPriority returns the most lost shards of volumes on the disk
+func (r *lostShardsRanker) diskPriority(diskID proto.DiskID) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.diskVols[diskID]
+}
+
+func equalDiskIDs(a, b []proto.DiskID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -489,6 +489,20 @@ func (mr *MockMigraterMockRecorder) QueryTask(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTask", reflect.TypeOf((*MockMigrater)(nil).QueryTask), arg0, arg1)
 }
 
+// QueueParams mocks base method.
+func (m *MockMigrater) QueueParams() scheduler.QueueParams {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueParams")
+	ret0, _ := ret[0].(scheduler.QueueParams)
+	return ret0
+}
+
+// QueueParams indicates an expected call of QueueParams.
+func (mr *MockMigraterMockRecorder) QueueParams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueParams", reflect.TypeOf((*MockMigrater)(nil).QueueParams))
+}
+
 // ReclaimTask mocks base method.
 func (m *MockMigrater) ReclaimTask(arg0 context.Context, arg1, arg2 string, arg3 []proto.VunitLocation, arg4 proto.VunitLocation, arg5 *client.AllocVunitInfo) error {
 	m.ctrl.T.Helper()
@@ -541,6 +555,18 @@ func (mr *MockMigraterMockRecorder) Run() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockMigrater)(nil).Run))
 }
 
+// SetQueueParams mocks base method.
+func (m *MockMigrater) SetQueueParams(arg0 scheduler.QueueParams) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetQueueParams", arg0)
+}
+
+// SetQueueParams indicates an expected call of SetQueueParams.
+func (mr *MockMigraterMockRecorder) SetQueueParams(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQueueParams", reflect.TypeOf((*MockMigrater)(nil).SetQueueParams), arg0)
+}
+
 // StatQueueTaskCnt mocks base method.
 func (m *MockMigrater) StatQueueTaskCnt() (int, int, int) {
 	m.ctrl.T.Helper()
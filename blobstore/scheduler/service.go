@@ -15,13 +15,18 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net/http"
 
+	"google.golang.org/grpc"
+
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
 	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
 	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/trace"
 	"github.com/cubefs/cubefs/blobstore/scheduler/base"
@@ -31,6 +36,13 @@ import (
 
 var errIllegalTaskType = rpc.NewError(http.StatusBadRequest, "illegal_type", errcode.ErrIllegalTaskType)
 
+const (
+	defaultListTasksCount = 10
+	maxListTasksCount     = 1000
+	// max tasks scanned in one listing, the rest is left to next listing by marker
+	maxListTasksScan = 10000
+)
+
 // Service rpc service
 type Service struct {
 	ClusterID     proto.ClusterID
@@ -41,16 +53,23 @@ type Service struct {
 	balanceMgr    Migrator
 	diskDropMgr   IDisKMigrator
 	diskRepairMgr IDisKMigrator
+	hostDrainer   IHostDrainer
 	manualMigMgr  IManualMigrator
+	coldMigMgr    Migrator
 	inspectMgr    IVolumeInspector
+	volumeHeatMgr *VolumeHeatMgr
 
 	shardRepairMgr  ITaskRunner
 	blobDeleteMgr   ITaskRunner
 	clusterTopology IClusterTopology
 	volumeUpdater   client.IVolumeUpdater
 	kafkaMonitors   []*base.KafkaTopicMonitor
+	grpcServer      *grpc.Server
 
 	clusterMgrCli client.ClusterMgrAPI
+	quarantine    *destQuarantine
+	taskBandwidth ratelimit.Limiter
+	taskCostMB    int
 }
 
 func (svr *Service) mgrByType(typ proto.TaskType) (Migrator, error) {
@@ -63,6 +82,8 @@ func (svr *Service) mgrByType(typ proto.TaskType) (Migrator, error) {
 		return svr.diskDropMgr, nil
 	case proto.TaskTypeManualMigrate:
 		return svr.manualMigMgr, nil
+	case proto.TaskTypeColdMigrate:
+		return svr.coldMigMgr, nil
 	default:
 		return nil, errIllegalTaskType
 	}
@@ -87,20 +108,35 @@ func (svr *Service) HTTPTaskAcquire(c *rpc.Context) {
 		return
 	}
 
-	// acquire task ordered: returns disk repair task first and other random
-	ctx := c.Request.Context()
-	migrators := []Migrator{svr.diskRepairMgr, svr.manualMigMgr, svr.diskDropMgr, svr.balanceMgr}
+	migrateTask, err := svr.acquireTask(c.Request.Context(), args)
+	if err != nil {
+		c.RespondError(err)
+		return
+	}
+	c.RespondJSON(migrateTask)
+}
+
+// acquireTask acquire task ordered: returns disk repair task first and other random,
+// other tasks are not returned if bandwidth of dispatched tasks is in debt
+func (svr *Service) acquireTask(ctx context.Context, args *api.AcquireArgs) (*proto.MigrateTask, error) {
+	migrators := []Migrator{svr.diskRepairMgr, svr.manualMigMgr, svr.diskDropMgr, svr.balanceMgr, svr.coldMigMgr}
 	shuffledMigrators := migrators[1:]
 	rand.Shuffle(len(shuffledMigrators), func(i, j int) {
 		shuffledMigrators[i], shuffledMigrators[j] = shuffledMigrators[j], shuffledMigrators[i]
 	})
-	for _, acquire := range migrators {
+	for idx, acquire := range migrators {
+		capped := idx > 0 && svr.taskBandwidth != nil
+		if capped && !svr.taskBandwidth.AllowN(0) {
+			break
+		}
 		if migrateTask, err := acquire.AcquireTask(ctx, args.IDC); err == nil {
-			c.RespondJSON(migrateTask)
-			return
+			if capped {
+				svr.taskBandwidth.TakeN(svr.taskCostMB)
+			}
+			return &migrateTask, nil
 		}
 	}
-	c.RespondError(errcode.ErrNothingTodo)
+	return nil, errcode.ErrNothingTodo
 }
 
 // HTTPTaskReclaim reclaim task
@@ -121,7 +157,9 @@ func (svr *Service) HTTPTaskReclaim(c *rpc.Context) {
 		return
 	}
 
-	newDst, err := base.AllocVunitSafe(ctx, svr.clusterMgrCli, args.Dest.Vuid, args.Src)
+	// the failed destination is avoided no matter it is quarantined or not
+	allocator := svr.quarantine.allocator(svr.clusterMgrCli, newVunitAllocator(svr.clusterMgrCli, args.TaskType), args.Dest.DiskID)
+	newDst, err := base.AllocVunitSafe(ctx, allocator, args.Dest.Vuid, args.Src)
 	if err != nil {
 		c.RespondError(err)
 		return
@@ -156,17 +194,18 @@ func (svr *Service) HTTPTaskComplete(c *rpc.Context) {
 		c.RespondError(err)
 		return
 	}
+	c.RespondError(svr.completeTask(c.Request.Context(), args))
+}
+
+func (svr *Service) completeTask(ctx context.Context, args *api.OperateTaskArgs) error {
 	if !client.ValidMigrateTask(args.TaskType, args.TaskID) {
-		c.RespondError(errcode.ErrIllegalArguments)
-		return
+		return errcode.ErrIllegalArguments
 	}
-	ctx := c.Request.Context()
 	completer, err := svr.mgrByType(args.TaskType)
 	if err != nil {
-		c.RespondError(err)
-		return
+		return err
 	}
-	c.RespondError(completer.CompleteTask(ctx, args))
+	return completer.CompleteTask(ctx, args)
 }
 
 // HTTPInspectAcquire acquire inspect task
@@ -202,13 +241,20 @@ func (svr *Service) HTTPTaskRenewal(c *rpc.Context) {
 		return
 	}
 
-	ctx := c.Request.Context()
+	ret, err := svr.renewalTask(c.Request.Context(), args)
+	if err != nil {
+		c.RespondError(err)
+		return
+	}
+	c.RespondJSON(ret)
+}
+
+func (svr *Service) renewalTask(ctx context.Context, args *api.TaskRenewalArgs) (*api.TaskRenewalRet, error) {
 	typeErrors := make(map[proto.TaskType]map[string]string)
 	for typ, ids := range args.IDs {
 		renewaler, err := svr.mgrByType(typ)
 		if err != nil {
-			c.RespondError(err)
-			return
+			return nil, err
 		}
 
 		errors := make(map[string]string)
@@ -226,7 +272,7 @@ func (svr *Service) HTTPTaskRenewal(c *rpc.Context) {
 			typeErrors[typ] = errors
 		}
 	}
-	c.RespondJSON(api.TaskRenewalRet{Errors: typeErrors})
+	return &api.TaskRenewalRet{Errors: typeErrors}, nil
 }
 
 // HTTPTaskReport reports task stats
@@ -236,17 +282,19 @@ func (svr *Service) HTTPTaskReport(c *rpc.Context) {
 		c.RespondError(err)
 		return
 	}
+	c.RespondError(svr.reportTask(args))
+}
+
+func (svr *Service) reportTask(args *api.TaskReportArgs) error {
 	if !client.ValidMigrateTask(args.TaskType, args.TaskID) {
-		c.RespondError(errcode.ErrIllegalArguments)
-		return
+		return errcode.ErrIllegalArguments
 	}
 	reporter, err := svr.mgrByType(args.TaskType)
 	if err != nil {
-		c.RespondError(err)
-		return
+		return err
 	}
 	reporter.ReportWorkerTaskStats(args)
-	c.Respond()
+	return nil
 }
 
 // HTTPMigrateTaskDetail returns migrate task detail.
@@ -274,6 +322,65 @@ func (svr *Service) HTTPMigrateTaskDetail(c *rpc.Context) {
 	c.RespondJSON(detail)
 }
 
+// HTTPListTasks lists migrate tasks of task type with filters
+func (svr *Service) HTTPListTasks(c *rpc.Context) {
+	args := new(api.ListTasksArgs)
+	if err := c.ParseArgs(args); err != nil {
+		c.RespondError(err)
+		return
+	}
+	if _, err := svr.mgrByType(args.TaskType); err != nil {
+		c.RespondError(err)
+		return
+	}
+	if args.Count <= 0 {
+		args.Count = defaultListTasksCount
+	}
+	if args.Count > maxListTasksCount {
+		args.Count = maxListTasksCount
+	}
+
+	ret, err := svr.listTasks(c.Request.Context(), args)
+	if err != nil {
+		c.RespondError(err)
+		return
+	}
+	c.RespondJSON(ret)
+}
+
+// listTasks scans tasks page by page from marker until count tasks matched,
+// the page size never exceeds the remaining count so that no matched task is skipped by the returned marker
+func (svr *Service) listTasks(ctx context.Context, args *api.ListTasksArgs) (*api.ListTasksRet, error) {
+	prefix := client.GenMigrateTaskPrefix(args.TaskType)
+	if args.DiskID != proto.InvalidDiskID {
+		prefix = client.GenMigrateTaskPrefixByDiskID(args.TaskType, args.DiskID)
+	}
+	ret := &api.ListTasksRet{Tasks: make([]*proto.MigrateTask, 0), Marker: args.Marker}
+	for scanned := 0; scanned < maxListTasksScan; {
+		count := args.Count - len(ret.Tasks)
+		tasks, marker, err := svr.clusterMgrCli.ListMigrateTasks(ctx, args.TaskType,
+			&cmapi.ListKvOpts{Prefix: prefix, Marker: ret.Marker, Count: count})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			if args.State != 0 && task.State != args.State {
+				continue
+			}
+			if args.Vid != proto.InvalidVid && task.Vid() != args.Vid {
+				continue
+			}
+			ret.Tasks = append(ret.Tasks, task)
+		}
+		scanned += count
+		ret.Marker = marker
+		if marker == "" || len(ret.Tasks) >= args.Count {
+			break
+		}
+	}
+	return ret, nil
+}
+
 // HTTPDiskMigratingStats returns disk migrating stats
 func (svr *Service) HTTPDiskMigratingStats(c *rpc.Context) {
 	args := new(api.DiskMigratingStatsArgs)
@@ -352,6 +459,12 @@ func (svr *Service) HTTPStats(c *rpc.Context) {
 		MigrateTasksStat: svr.balanceMgr.Stats(),
 	}
 
+	// stats cold migrate tasks
+	taskStats.ColdMigrate = &api.ColdMigrateTasksStat{
+		Enable:           svr.coldMigMgr.Enabled(),
+		MigrateTasksStat: svr.coldMigMgr.Stats(),
+	}
+
 	// stats manual migrate tasks
 	taskStats.ManualMigrate = &api.ManualMigrateTasksStat{
 		MigrateTasksStat: svr.manualMigMgr.Stats(),
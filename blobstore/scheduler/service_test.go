@@ -26,7 +26,9 @@ import (
 	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
 	api "github.com/cubefs/cubefs/blobstore/api/scheduler"
 	"github.com/cubefs/cubefs/blobstore/common/counter"
+	errcode "github.com/cubefs/cubefs/blobstore/common/errors"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/scheduler/client"
 	"github.com/cubefs/cubefs/blobstore/testing/mocks"
@@ -37,6 +39,8 @@ var (
 	once            sync.Once
 )
 
+const testDrainHost = "http://127.0.0.1:8889"
+
 func runMockService(s *Service) string {
 	once.Do(func() {
 		schedulerServer = httptest.NewServer(NewHandler(s))
@@ -54,6 +58,7 @@ func newMockService(t *testing.T) *Service {
 	diskRepairMgr := NewMockMigrater(ctr)
 	manualMgr := NewMockMigrater(ctr)
 	balanceMgr := NewMockMigrater(ctr)
+	coldMigMgr := NewMockMigrater(ctr)
 	inspectorMgr := NewMockVolumeInspector(ctr)
 	clusterTopology := NewMockClusterTopology(ctr)
 
@@ -72,6 +77,9 @@ func newMockService(t *testing.T) *Service {
 	// reclaim manual migrate task
 	manualMgr.EXPECT().ReclaimTask(any, any, any, any, any, any).Return(nil)
 	clusterMgrCli.EXPECT().AllocVolumeUnit(any, any).Return(&client.AllocVunitInfo{}, nil)
+	// reclaim cold migrate task
+	coldMigMgr.EXPECT().ReclaimTask(any, any, any, any, any, any).Return(nil)
+	clusterMgrCli.EXPECT().AllocColdVolumeUnit(any, any).Return(&client.AllocVunitInfo{}, nil)
 
 	// cancel repair task
 	diskRepairMgr.EXPECT().CancelTask(any, any).Return(nil)
@@ -81,6 +89,8 @@ func newMockService(t *testing.T) *Service {
 	diskDropMgr.EXPECT().CancelTask(any, any).Return(nil)
 	// cancel manual migrate task
 	manualMgr.EXPECT().CancelTask(any, any).Return(nil)
+	// cancel cold migrate task
+	coldMigMgr.EXPECT().CancelTask(any, any).Return(nil)
 
 	// complete repair task
 	diskRepairMgr.EXPECT().CompleteTask(any, any).Return(nil)
@@ -90,6 +100,8 @@ func newMockService(t *testing.T) *Service {
 	diskDropMgr.EXPECT().CompleteTask(any, any).Return(nil)
 	// complete manual migrate task
 	manualMgr.EXPECT().CompleteTask(any, any).Return(nil)
+	// complete cold migrate task
+	coldMigMgr.EXPECT().CompleteTask(any, any).Return(nil)
 
 	// renewal repair task
 	diskRepairMgr.EXPECT().RenewalTask(any, any, any).Times(3).Return(nil)
@@ -108,6 +120,8 @@ func newMockService(t *testing.T) *Service {
 	diskDropMgr.EXPECT().ReportWorkerTaskStats(any).Return()
 	// report manual migrate task
 	manualMgr.EXPECT().ReportWorkerTaskStats(any).Return()
+	// report cold migrate task
+	coldMigMgr.EXPECT().ReportWorkerTaskStats(any).Return()
 
 	// add manual migrate task
 	manualMgr.EXPECT().AddManualTask(any, any, any).Return(nil)
@@ -138,6 +152,8 @@ func newMockService(t *testing.T) *Service {
 	balanceMgr.EXPECT().Stats().Return(api.MigrateTasksStat{})
 	balanceMgr.EXPECT().Enabled().Return(true)
 	manualMgr.EXPECT().Stats().Return(api.MigrateTasksStat{})
+	coldMigMgr.EXPECT().Stats().Return(api.MigrateTasksStat{})
+	coldMigMgr.EXPECT().Enabled().Return(true)
 	inspectorMgr.EXPECT().GetTaskStats().Return([counter.SLOT]int{}, [counter.SLOT]int{})
 	inspectorMgr.EXPECT().Enabled().Return(true)
 
@@ -146,10 +162,12 @@ func newMockService(t *testing.T) *Service {
 	diskDropMgr.EXPECT().QueryTask(any, any).Return(nil, nil)
 	diskRepairMgr.EXPECT().QueryTask(any, any).Return(nil, nil)
 	manualMgr.EXPECT().QueryTask(any, any).Return(nil, nil)
+	coldMigMgr.EXPECT().QueryTask(any, any).Return(nil, nil)
 	balanceMgr.EXPECT().QueryTask(any, any).Return(nil, errMock)
 	diskDropMgr.EXPECT().QueryTask(any, any).Return(nil, errMock)
 	diskRepairMgr.EXPECT().QueryTask(any, any).Return(nil, errMock)
 	manualMgr.EXPECT().QueryTask(any, any).Return(nil, errMock)
+	coldMigMgr.EXPECT().QueryTask(any, any).Return(nil, errMock)
 
 	// disk stats
 	diskRepairMgr.EXPECT().DiskProgress(any, any).Return(nil, errMock)
@@ -157,6 +175,41 @@ func newMockService(t *testing.T) *Service {
 	diskRepairMgr.EXPECT().DiskProgress(any, any).Return(&api.DiskMigratingStats{TotalTasksCnt: int(testDisk1.UsedChunkCnt), MigratedTasksCnt: 1}, nil)
 	diskDropMgr.EXPECT().DiskProgress(any, any).Return(&api.DiskMigratingStats{TotalTasksCnt: int(testDisk1.UsedChunkCnt), MigratedTasksCnt: 1}, nil)
 
+	// list tasks
+	clusterMgrCli.EXPECT().ListMigrateTasks(any, any, any).Times(2).DoAndReturn(
+		func(_ context.Context, taskType proto.TaskType, args *cmapi.ListKvOpts) ([]*proto.MigrateTask, string, error) {
+			if args.Prefix != client.GenMigrateTaskPrefixByDiskID(taskType, testDisk1.DiskID) {
+				return nil, "", errMock
+			}
+			if args.Marker == "" && args.Count == 2 {
+				return []*proto.MigrateTask{
+					{TaskID: "task1", TaskType: taskType, State: proto.MigrateStatePrepared},
+					{TaskID: "task2", TaskType: taskType, State: proto.MigrateStateInited},
+				}, "task2", nil
+			}
+			if args.Marker == "task2" && args.Count == 1 {
+				return []*proto.MigrateTask{
+					{TaskID: "task3", TaskType: taskType, State: proto.MigrateStatePrepared},
+				}, "task3", nil
+			}
+			return nil, "", errMock
+		})
+
+	// queue params
+	balanceMgr.EXPECT().QueueParams().Times(3).Return(api.QueueParams{TaskType: proto.TaskTypeBalance, LeaseExpiredS: 10, WorkQueueSize: 20})
+	balanceMgr.EXPECT().SetQueueParams(any).Return()
+	clusterMgrCli.EXPECT().SetQueueParams(any, any).Return(nil)
+
+	// host drain
+	hostDrainer := &DiskDropMgr{
+		clusterMgrCli: clusterMgrCli,
+		allDisks:      newDropDiskMap(),
+		drains:        make(map[string]*client.HostDrainMeta),
+	}
+	clusterMgrCli.EXPECT().ListHostDisks(any, testDrainHost).Times(2).Return(
+		[]*client.DiskInfoSimple{{DiskID: 1, Host: testDrainHost, Status: proto.DiskStatusNormal, UsedChunkCnt: 10}}, nil)
+	clusterMgrCli.EXPECT().SetHostDrains(any, any).Return(nil)
+
 	service := &Service{
 		ClusterID:     1,
 		leader:        true,
@@ -164,7 +217,9 @@ func newMockService(t *testing.T) *Service {
 		balanceMgr:    balanceMgr,
 		diskDropMgr:   diskDropMgr,
 		manualMigMgr:  manualMgr,
+		coldMigMgr:    coldMigMgr,
 		diskRepairMgr: diskRepairMgr,
+		hostDrainer:   hostDrainer,
 		inspectMgr:    inspectorMgr,
 
 		shardRepairMgr:  shardRepairMgr,
@@ -191,6 +246,7 @@ func TestServiceAPI(t *testing.T) {
 	taskTypes := []proto.TaskType{
 		proto.TaskTypeBalance, proto.TaskTypeDiskDrop,
 		proto.TaskTypeDiskRepair, proto.TaskTypeManualMigrate,
+		proto.TaskTypeColdMigrate,
 	}
 	// acquire task
 	task, err := cli.AcquireTask(ctx, &api.AcquireArgs{IDC: idc})
@@ -275,6 +331,53 @@ func TestServiceAPI(t *testing.T) {
 		_, err = cli.DetailMigrateTask(ctx, &api.MigrateTaskDetailArgs{Type: taskType, ID: client.GenMigrateTaskID(taskType, diskID, volumeID)})
 		require.Error(t, err)
 	}
+	// list tasks
+	{
+		_, err = cli.ListTasks(ctx, nil)
+		require.Error(t, err)
+		_, err = cli.ListTasks(ctx, &api.ListTasksArgs{TaskType: proto.TaskTypeShardRepair})
+		require.Equal(t, 400, rpc.DetectStatusCode(err))
+		ret, err := cli.ListTasks(ctx, &api.ListTasksArgs{
+			TaskType: proto.TaskTypeBalance,
+			State:    proto.MigrateStatePrepared,
+			DiskID:   testDisk1.DiskID,
+			Count:    2,
+		})
+		require.NoError(t, err)
+		require.Len(t, ret.Tasks, 2)
+		require.Equal(t, "task1", ret.Tasks[0].TaskID)
+		require.Equal(t, "task3", ret.Tasks[1].TaskID)
+		require.Equal(t, "task3", ret.Marker)
+	}
+	// queue params
+	{
+		_, err = cli.GetQueueParams(ctx, &api.QueueParamsArgs{TaskType: "xxxxx"})
+		require.Error(t, err)
+		params, err := cli.GetQueueParams(ctx, &api.QueueParamsArgs{TaskType: proto.TaskTypeBalance})
+		require.NoError(t, err)
+		require.Equal(t, 20, params.WorkQueueSize)
+
+		err = cli.SetQueueParams(ctx, &api.QueueParamsSetArgs{TaskType: proto.TaskTypeBalance, LeaseExpiredS: intPtr(1)})
+		require.Equal(t, 400, rpc.DetectStatusCode(err))
+		err = cli.SetQueueParams(ctx, &api.QueueParamsSetArgs{TaskType: proto.TaskTypeBalance, WorkQueueSize: intPtr(100)})
+		require.NoError(t, err)
+	}
+	// host drain
+	{
+		err = cli.DrainHost(ctx, &api.HostDrainArgs{Host: testDrainHost, Concurrency: maxHostDrainConcurrency + 1})
+		require.Equal(t, 400, rpc.DetectStatusCode(err))
+		_, err = cli.HostDrainStat(ctx, &api.HostDrainStatArgs{Host: testDrainHost})
+		require.Equal(t, 404, rpc.DetectStatusCode(err))
+
+		err = cli.DrainHost(ctx, &api.HostDrainArgs{Host: testDrainHost})
+		require.NoError(t, err)
+		stat, err := cli.HostDrainStat(ctx, &api.HostDrainStatArgs{Host: testDrainHost})
+		require.NoError(t, err)
+		require.Equal(t, testDrainHost, stat.Host)
+		require.Equal(t, defaultHostDrainConcurrency, stat.Concurrency)
+		require.Equal(t, 1, stat.PendingDisks)
+		require.Equal(t, []api.HostDrainDisk{{DiskID: 1, State: api.HostDrainDiskPending, TotalChunks: 10}}, stat.Disks)
+	}
 	// disk migrating stats
 	diskMigrateTypes := []proto.TaskType{proto.TaskTypeDiskRepair, proto.TaskTypeDiskDrop}
 	for _, taskType := range diskMigrateTypes {
@@ -286,3 +389,89 @@ func TestServiceAPI(t *testing.T) {
 		require.Equal(t, 1, stats.MigratedTasksCnt)
 	}
 }
+
+func TestServiceListTasks(t *testing.T) {
+	ctr := gomock.NewController(t)
+	clusterMgrCli := NewMockClusterMgrAPI(ctr)
+	svr := &Service{clusterMgrCli: clusterMgrCli}
+	ctx := context.Background()
+	tasks := []*proto.MigrateTask{
+		{TaskID: "task1", TaskType: proto.TaskTypeDiskRepair, State: proto.MigrateStatePrepared, SourceVuid: proto.EncodeVuid(proto.EncodeVuidPrefix(1, 0), 1)},
+		{TaskID: "task2", TaskType: proto.TaskTypeDiskRepair, State: proto.MigrateStatePrepared, SourceVuid: proto.EncodeVuid(proto.EncodeVuidPrefix(2, 0), 1)},
+		{TaskID: "task3", TaskType: proto.TaskTypeDiskRepair, State: proto.MigrateStateInited, SourceVuid: proto.EncodeVuid(proto.EncodeVuidPrefix(1, 1), 1)},
+	}
+	listKv := func(_ context.Context, _ proto.TaskType, args *cmapi.ListKvOpts) ([]*proto.MigrateTask, string, error) {
+		require.Equal(t, client.GenMigrateTaskPrefix(proto.TaskTypeDiskRepair), args.Prefix)
+		start := 0
+		for i, task := range tasks {
+			if task.TaskID == args.Marker {
+				start = i + 1
+			}
+		}
+		end := start + args.Count
+		if end >= len(tasks) {
+			return tasks[start:], "", nil
+		}
+		return tasks[start:end], tasks[end-1].TaskID, nil
+	}
+
+	// filter by vid until the end of tasks
+	clusterMgrCli.EXPECT().ListMigrateTasks(any, any, any).DoAndReturn(listKv)
+	ret, err := svr.listTasks(ctx, &api.ListTasksArgs{TaskType: proto.TaskTypeDiskRepair, Vid: 1, Count: 3})
+	require.NoError(t, err)
+	require.Len(t, ret.Tasks, 2)
+	require.Equal(t, "task1", ret.Tasks[0].TaskID)
+	require.Equal(t, "task3", ret.Tasks[1].TaskID)
+	require.Empty(t, ret.Marker)
+
+	// paginate by marker
+	clusterMgrCli.EXPECT().ListMigrateTasks(any, any, any).DoAndReturn(listKv)
+	ret, err = svr.listTasks(ctx, &api.ListTasksArgs{TaskType: proto.TaskTypeDiskRepair, State: proto.MigrateStatePrepared, Count: 2})
+	require.NoError(t, err)
+	require.Len(t, ret.Tasks, 2)
+	require.Equal(t, "task2", ret.Marker)
+	clusterMgrCli.EXPECT().ListMigrateTasks(any, any, any).DoAndReturn(listKv)
+	ret, err = svr.listTasks(ctx, &api.ListTasksArgs{TaskType: proto.TaskTypeDiskRepair, State: proto.MigrateStatePrepared, Marker: ret.Marker, Count: 2})
+	require.NoError(t, err)
+	require.Empty(t, ret.Tasks)
+	require.Empty(t, ret.Marker)
+
+	clusterMgrCli.EXPECT().ListMigrateTasks(any, any, any).Return(nil, "", errMock)
+	_, err = svr.listTasks(ctx, &api.ListTasksArgs{TaskType: proto.TaskTypeDiskRepair, Count: 2})
+	require.ErrorIs(t, err, errMock)
+}
+
+func TestServiceAcquireTaskBandwidth(t *testing.T) {
+	ctr := gomock.NewController(t)
+	diskRepairMgr := NewMockMigrater(ctr)
+	balanceMgr := NewMockMigrater(ctr)
+	emptyMgr := NewMockMigrater(ctr)
+	emptyMgr.EXPECT().AcquireTask(any, any).AnyTimes().Return(proto.MigrateTask{}, errMock)
+
+	svr := &Service{
+		diskRepairMgr: diskRepairMgr,
+		manualMigMgr:  emptyMgr,
+		diskDropMgr:   emptyMgr,
+		balanceMgr:    balanceMgr,
+		coldMigMgr:    emptyMgr,
+		taskBandwidth: ratelimit.New(ratelimit.Config{Rate: 100}),
+		taskCostMB:    1000,
+	}
+	ctx := context.Background()
+	args := &api.AcquireArgs{IDC: "z0"}
+
+	// the first task is dispatched, then the bandwidth is in debt
+	diskRepairMgr.EXPECT().AcquireTask(any, any).Times(2).Return(proto.MigrateTask{}, errMock)
+	balanceMgr.EXPECT().AcquireTask(any, any).Return(proto.MigrateTask{TaskType: proto.TaskTypeBalance}, nil)
+	task, err := svr.acquireTask(ctx, args)
+	require.NoError(t, err)
+	require.Equal(t, proto.TaskTypeBalance, task.TaskType)
+	_, err = svr.acquireTask(ctx, args)
+	require.ErrorIs(t, err, errcode.ErrNothingTodo)
+
+	// disk repair is not capped and costs nothing
+	diskRepairMgr.EXPECT().AcquireTask(any, any).Return(proto.MigrateTask{TaskType: proto.TaskTypeDiskRepair}, nil)
+	task, err = svr.acquireTask(ctx, args)
+	require.NoError(t, err)
+	require.Equal(t, proto.TaskTypeDiskRepair, task.TaskType)
+}
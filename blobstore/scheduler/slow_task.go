@@ -0,0 +1,116 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/trace"
+	"github.com/cubefs/cubefs/blobstore/scheduler/base"
+	"github.com/cubefs/cubefs/blobstore/scheduler/client"
+)
+
+type slowTaskReclaimer interface {
+	Enabled() bool
+	ReclaimTask(ctx context.Context, idc, taskID string,
+		src []proto.VunitLocation, oldDst proto.VunitLocation, newDst *client.AllocVunitInfo) error
+}
+
+// slowTaskChecker flags the tasks exceeding the time budget from prepared to finished,
+// and reclaims the acquired ones to a new destination so that other workers can redo them
+type slowTaskChecker struct {
+	*base.TaskTimer
+
+	taskType     proto.TaskType
+	budget       time.Duration
+	reclaim      bool
+	interval     time.Duration
+	workQueue    *base.WorkerTaskQueue
+	taskStatsMgr *base.TaskStatsMgr
+	allocator    base.IAllocVunit
+	reclaimer    slowTaskReclaimer
+}
+
+func newSlowTaskChecker(taskType proto.TaskType, cfg *base.TaskCommonConfig, workQueue *base.WorkerTaskQueue,
+	taskStatsMgr *base.TaskStatsMgr, allocator base.IAllocVunit, reclaimer slowTaskReclaimer) *slowTaskChecker {
+	return &slowTaskChecker{
+		TaskTimer:    base.NewTaskTimer(time.Duration(cfg.TaskTimeBudgetS) * time.Second),
+		taskType:     taskType,
+		budget:       time.Duration(cfg.TaskTimeBudgetS) * time.Second,
+		reclaim:      cfg.ReclaimSlowTask,
+		interval:     time.Duration(cfg.CheckTaskIntervalS) * time.Second,
+		workQueue:    workQueue,
+		taskStatsMgr: taskStatsMgr,
+		allocator:    allocator,
+		reclaimer:    reclaimer,
+	}
+}
+
+func (c *slowTaskChecker) checkLoop(done <-chan struct{}) {
+	if c.budget <= 0 {
+		return
+	}
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			c.check()
+		case <-done:
+			return
+		}
+	}
+}
+
+func (c *slowTaskChecker) check() {
+	c.taskStatsMgr.ReportSlowTaskCnt(len(c.SlowTasks()))
+	if !c.reclaim || !c.reclaimer.Enabled() {
+		return
+	}
+
+	span, ctx := trace.StartSpanFromContext(context.Background(), "slow_task.check")
+	for _, slow := range c.ExpiredTasks() {
+		// the task waiting in queue or being finished has no worker to be replaced
+		if !c.workQueue.Acquired(slow.IDC, slow.TaskID) {
+			continue
+		}
+		wtask, err := c.workQueue.Query(slow.IDC, slow.TaskID)
+		if err != nil {
+			continue
+		}
+		task := wtask.(*proto.MigrateTask).Copy()
+		newDst, err := base.AllocVunitSafe(ctx, c.allocator, task.Destination.Vuid, task.Sources)
+		if err != nil {
+			span.Errorf("alloc vunit for slow task failed: task_type[%s], task_id[%s], err[%+v]", c.taskType, slow.TaskID, err)
+			continue
+		}
+		if err = c.reclaimer.ReclaimTask(ctx, slow.IDC, slow.TaskID, task.Sources, task.Destination, newDst); err != nil {
+			continue
+		}
+		c.Renew(slow.TaskID)
+		span.Warnf("reclaim slow task: task_type[%s], task_id[%s], elapsed[%s]", c.taskType, slow.TaskID, slow.Elapsed)
+	}
+}
+
+// slowTaskIDs returns ids of the tasks exceeding the time budget
+func (c *slowTaskChecker) slowTaskIDs() (ids []string) {
+	for _, slow := range c.SlowTasks() {
+		ids = append(ids, slow.TaskID)
+	}
+	return ids
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cubefs/cubefs/blobstore/cmd"
 	"github.com/cubefs/cubefs/blobstore/common/config"
 	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/ratelimit"
 	"github.com/cubefs/cubefs/blobstore/common/recordlog"
 	"github.com/cubefs/cubefs/blobstore/common/rpc"
 	"github.com/cubefs/cubefs/blobstore/common/taskswitch"
@@ -57,10 +58,11 @@ var (
 
 func init() {
 	mod := &cmd.Module{
-		Name:       proto.ServiceNameScheduler,
-		InitConfig: initConfig,
-		SetUp:      setUp,
-		TearDown:   tearDown,
+		Name:        proto.ServiceNameScheduler,
+		InitConfig:  initConfig,
+		SetUp:       setUp,
+		TearDown:    tearDown,
+		HealthCheck: healthCheck,
 	}
 	cmd.RegisterModule(mod)
 }
@@ -84,6 +86,10 @@ func setUp() (*rpc.Router, []rpc.ProgressHandler) {
 	return NewHandler(service), []rpc.ProgressHandler{service}
 }
 
+func healthCheck() error {
+	return service.HealthCheck()
+}
+
 func tearDown() {
 	// close record file safety
 	service.Close()
@@ -105,6 +111,14 @@ func NewService(conf *Config) (svr *Service, err error) {
 	}
 
 	clusterMgrCli := client.NewClusterMgrClient(&conf.ClusterMgr)
+	if conf.TaskKVPath != "" {
+		taskKV, err := client.NewMemTaskKV(conf.TaskKVPath)
+		if err != nil {
+			log.Errorf("new task kv failed: path[%s], err[%+v]", conf.TaskKVPath, err)
+			return nil, err
+		}
+		clusterMgrCli = client.NewClusterMgrClientWithTaskKV(&conf.ClusterMgr, taskKV)
+	}
 
 	blobnodeCli := client.NewBlobnodeClient(&conf.Blobnode)
 	switchMgr := taskswitch.NewSwitchMgr(clusterMgrCli)
@@ -146,6 +160,11 @@ func NewService(conf *Config) (svr *Service, err error) {
 		return nil, err
 	}
 
+	if svr.grpcServer, err = svr.startGrpcServer(conf.GrpcPort); err != nil {
+		log.Errorf("start grpc server failed: port[%d], err[%+v]", conf.GrpcPort, err)
+		return nil, err
+	}
+
 	if !svr.leader {
 		return
 	}
@@ -157,6 +176,16 @@ func NewService(conf *Config) (svr *Service, err error) {
 	}
 
 	// all migrate manager
+	migrateClusterMgrCli := client.NewCachedClusterMgrClient(clusterMgrCli, &conf.ClusterMgrCache)
+	svr.quarantine = newDestQuarantine(&conf.DestQuarantine)
+	svr.taskBandwidth = ratelimit.New(conf.TaskBandwidth.Config)
+	svr.taskCostMB = conf.TaskBandwidth.ChunkSizeMB
+	for _, cfg := range []*MigrateConfig{
+		&conf.Balance.MigrateConfig, &conf.DiskDrop.MigrateConfig, &conf.DiskRepair,
+		&conf.ManualMigrate, &conf.ColdMigrate.MigrateConfig,
+	} {
+		cfg.quarantine = svr.quarantine
+	}
 	taskLogger, err := recordlog.NewEncoder(&conf.TaskLog)
 	if err != nil {
 		return nil, err
@@ -166,13 +195,14 @@ func NewService(conf *Config) (svr *Service, err error) {
 	if err != nil {
 		return nil, err
 	}
-	balanceMgr := NewBalanceMgr(clusterMgrCli, volumeUpdater, balanceTaskSwitch, topologyMgr, taskLogger, &conf.Balance)
+	volumeHeatMgr := NewVolumeHeatMgr(kafkaClient, &conf.VolumeHeat)
+	balanceMgr := NewBalanceMgr(migrateClusterMgrCli, volumeUpdater, balanceTaskSwitch, topologyMgr, volumeHeatMgr, taskLogger, &conf.Balance)
 
 	diskDropTaskSwitch, err := switchMgr.AddSwitch(proto.TaskTypeDiskDrop.String())
 	if err != nil {
 		return nil, err
 	}
-	diskDropMgr := NewDiskDropMgr(clusterMgrCli, volumeUpdater, diskDropTaskSwitch, taskLogger, &conf.DiskDrop, topologyMgr)
+	diskDropMgr := NewDiskDropMgr(migrateClusterMgrCli, volumeUpdater, diskDropTaskSwitch, taskLogger, &conf.DiskDrop, topologyMgr)
 
 	// new disk repair manager
 	diskRepairTaskSwitch, err := switchMgr.AddSwitch(proto.TaskTypeDiskRepair.String())
@@ -180,9 +210,15 @@ func NewService(conf *Config) (svr *Service, err error) {
 		return nil, err
 	}
 
-	diskRepairMgr := NewDiskRepairMgr(clusterMgrCli, diskRepairTaskSwitch, taskLogger, &conf.DiskRepair)
+	diskRepairMgr := NewDiskRepairMgr(migrateClusterMgrCli, diskRepairTaskSwitch, taskLogger, &conf.DiskRepair)
+
+	manualMigMgr := NewManualMigrateMgr(migrateClusterMgrCli, volumeUpdater, taskLogger, &conf.ManualMigrate)
 
-	manualMigMgr := NewManualMigrateMgr(clusterMgrCli, volumeUpdater, taskLogger, &conf.ManualMigrate)
+	coldMigTaskSwitch, err := switchMgr.AddSwitch(proto.TaskTypeColdMigrate.String())
+	if err != nil {
+		return nil, err
+	}
+	coldMigMgr := NewColdMigrateMgr(migrateClusterMgrCli, volumeUpdater, coldMigTaskSwitch, topologyMgr, volumeHeatMgr, taskLogger, &conf.ColdMigrate)
 
 	mqProxy := client.NewProxyClient(&conf.Proxy, cmapi.New(&conf.ClusterMgr), conf.ClusterID)
 	inspectorTaskSwitch, err := switchMgr.AddSwitch(proto.TaskTypeVolumeInspect.String())
@@ -193,9 +229,17 @@ func NewService(conf *Config) (svr *Service, err error) {
 
 	svr.balanceMgr = balanceMgr
 	svr.diskDropMgr = diskDropMgr
+	svr.hostDrainer = diskDropMgr
 	svr.manualMigMgr = manualMigMgr
+	svr.coldMigMgr = coldMigMgr
 	svr.diskRepairMgr = diskRepairMgr
 	svr.inspectMgr = inspectMgr
+	svr.volumeHeatMgr = volumeHeatMgr
+
+	if err = volumeHeatMgr.Run(); err != nil {
+		log.Errorf("run volume heat mgr failed: err[%+v]", err)
+		return nil, err
+	}
 
 	err = svr.waitAndLoad()
 	if err != nil {
@@ -212,6 +256,7 @@ func (svr *Service) waitAndLoad() error {
 	//so there will not a task run on multiple worker
 	log.Infof("start waitAndLoad")
 	time.Sleep(proto.TaskLeaseExpiredS * time.Second)
+	svr.loadQueueParams()
 	return svr.load()
 }
 
@@ -228,6 +273,9 @@ func (svr *Service) load() (err error) {
 	if err = svr.manualMigMgr.Load(); err != nil {
 		return
 	}
+	if err = svr.coldMigMgr.Load(); err != nil {
+		return
+	}
 
 	return
 }
@@ -245,12 +293,21 @@ func (svr *Service) register(cfg ServiceRegisterConfig) error {
 	return svr.clusterMgrCli.Register(context.Background(), info)
 }
 
+// HealthCheck returns error if the cluster topology knows no idc
+func (svr *Service) HealthCheck() error {
+	if len(svr.clusterTopology.GetIDCs()) == 0 {
+		return errors.New("no idc in cluster topology")
+	}
+	return nil
+}
+
 // Run run task
 func (svr *Service) Run() {
 	svr.diskRepairMgr.Run()
 	svr.balanceMgr.Run()
 	svr.diskDropMgr.Run()
 	svr.manualMigMgr.Run()
+	svr.coldMigMgr.Run()
 	svr.inspectMgr.Run()
 }
 
@@ -342,6 +399,9 @@ func (svr *Service) Close() {
 	log.Infof("stop scheduler service")
 	svr.blobDeleteMgr.Close()
 	svr.shardRepairMgr.Close()
+	if svr.grpcServer != nil {
+		svr.grpcServer.GracefulStop()
+	}
 	if !svr.leader {
 		return
 	}
@@ -350,7 +410,9 @@ func (svr *Service) Close() {
 	svr.diskRepairMgr.Close()
 	svr.diskDropMgr.Close()
 	svr.manualMigMgr.Close()
+	svr.coldMigMgr.Close()
 	svr.inspectMgr.Close()
+	svr.volumeHeatMgr.Close()
 }
 
 // NewHandler returns app server handler
@@ -358,6 +420,9 @@ func NewHandler(service *Service) *rpc.Router {
 	rpc.RegisterArgsParser(&api.AcquireArgs{}, "json")
 	rpc.RegisterArgsParser(&api.DiskMigratingStatsArgs{}, "json")
 	rpc.RegisterArgsParser(&api.MigrateTaskDetailArgs{}, "json")
+	rpc.RegisterArgsParser(&api.QueueParamsArgs{}, "json")
+	rpc.RegisterArgsParser(&api.ListTasksArgs{}, "json")
+	rpc.RegisterArgsParser(&api.HostDrainStatArgs{}, "json")
 
 	// rpc http svr interface
 	rpc.GET(api.PathTaskAcquire, service.HTTPTaskAcquire, rpc.OptArgsQuery())
@@ -373,11 +438,18 @@ func NewHandler(service *Service) *rpc.Router {
 	rpc.POST(api.PathTaskRenewal, service.HTTPTaskRenewal, rpc.OptArgsBody())
 
 	rpc.GET(api.PathTaskDetailURI, service.HTTPMigrateTaskDetail, rpc.OptArgsURI())
+	rpc.GET(api.PathTaskList, service.HTTPListTasks, rpc.OptArgsQuery())
 	rpc.GET(api.PathStats, service.HTTPStats, rpc.OptArgsQuery())
 	rpc.GET(api.PathStatsLeader, service.HTTPStats, rpc.OptArgsQuery())
 	rpc.GET(api.PathStatsDiskMigrating, service.HTTPDiskMigratingStats, rpc.OptArgsQuery())
 
 	rpc.POST(api.PathUpdateVolume, service.HTTPUpdateVolume, rpc.OptArgsBody())
 
+	rpc.GET(api.PathQueueParams, service.HTTPQueueParams, rpc.OptArgsQuery())
+	rpc.POST(api.PathQueueParamsSet, service.HTTPQueueParamsSet, rpc.OptArgsBody())
+
+	rpc.POST(api.PathHostDrain, service.HTTPHostDrain, rpc.OptArgsBody())
+	rpc.GET(api.PathHostDrainStat, service.HTTPHostDrainStat, rpc.OptArgsQuery())
+
 	return rpc.DefaultRouter
 }
@@ -100,17 +100,20 @@ func newMockServiceWithOpts(ctr *gomock.Controller, isLeader bool) *Service {
 	diskRepairMgr := NewMockMigrater(ctr)
 	manualMgr := NewMockMigrater(ctr)
 	balanceMgr := NewMockMigrater(ctr)
+	coldMigMgr := NewMockMigrater(ctr)
 	inspecterMgr := NewMockVolumeInspector(ctr)
 	clusterTopology := NewMockClusterTopology(ctr)
 	volumeUpdater := NewMockVolumeUpdater(ctr)
 
 	balanceMgr.EXPECT().Close().AnyTimes().Return()
+	coldMigMgr.EXPECT().Close().AnyTimes().Return()
 	diskRepairMgr.EXPECT().Close().AnyTimes().Return()
 	diskDropMgr.EXPECT().Close().AnyTimes().Return()
 	manualMgr.EXPECT().Close().AnyTimes().Return()
 	inspecterMgr.EXPECT().Close().AnyTimes().Return()
 
 	balanceMgr.EXPECT().Run().AnyTimes().Return()
+	coldMigMgr.EXPECT().Run().AnyTimes().Return()
 	diskDropMgr.EXPECT().Run().AnyTimes().Return()
 	diskRepairMgr.EXPECT().Run().AnyTimes().Return()
 	inspecterMgr.EXPECT().Run().AnyTimes().Return()
@@ -123,6 +126,7 @@ func newMockServiceWithOpts(ctr *gomock.Controller, isLeader bool) *Service {
 	blobDeleteMgr.EXPECT().Close().AnyTimes().Return()
 
 	balanceMgr.EXPECT().Load().AnyTimes().Return(nil)
+	coldMigMgr.EXPECT().Load().AnyTimes().Return(nil)
 	diskRepairMgr.EXPECT().Load().AnyTimes().Return(nil)
 	diskDropMgr.EXPECT().Load().AnyTimes().Return(nil)
 	manualMgr.EXPECT().Load().AnyTimes().Return(nil)
@@ -141,6 +145,8 @@ func newMockServiceWithOpts(ctr *gomock.Controller, isLeader bool) *Service {
 	diskDropMgr.EXPECT().Enabled().AnyTimes().Return(true)
 	balanceMgr.EXPECT().Stats().AnyTimes().Return(api.MigrateTasksStat{})
 	balanceMgr.EXPECT().Enabled().AnyTimes().Return(true)
+	coldMigMgr.EXPECT().Stats().AnyTimes().Return(api.MigrateTasksStat{})
+	coldMigMgr.EXPECT().Enabled().AnyTimes().Return(true)
 	manualMgr.EXPECT().Stats().AnyTimes().Return(api.MigrateTasksStat{})
 	inspecterMgr.EXPECT().GetTaskStats().AnyTimes().Return([counter.SLOT]int{}, [counter.SLOT]int{})
 	inspecterMgr.EXPECT().Enabled().AnyTimes().Return(true)
@@ -152,6 +158,7 @@ func newMockServiceWithOpts(ctr *gomock.Controller, isLeader bool) *Service {
 	diskRepairMgr.EXPECT().AcquireTask(any, any).AnyTimes().Return(proto.MigrateTask{}, errMock)
 	diskDropMgr.EXPECT().AcquireTask(any, any).AnyTimes().Return(proto.MigrateTask{}, errMock)
 	balanceMgr.EXPECT().AcquireTask(any, any).AnyTimes().Return(proto.MigrateTask{}, errMock)
+	coldMigMgr.EXPECT().AcquireTask(any, any).AnyTimes().Return(proto.MigrateTask{}, errMock)
 
 	clusterTopology.EXPECT().UpdateVolume(any).AnyTimes().Return(&client.VolumeInfoSimple{}, nil)
 	clusterMgrCli.EXPECT().GetConfig(any, any).AnyTimes().Return("", errMock)
@@ -162,8 +169,10 @@ func newMockServiceWithOpts(ctr *gomock.Controller, isLeader bool) *Service {
 		balanceMgr:      balanceMgr,
 		diskDropMgr:     diskDropMgr,
 		manualMigMgr:    manualMgr,
+		coldMigMgr:      coldMigMgr,
 		diskRepairMgr:   diskRepairMgr,
 		inspectMgr:      inspecterMgr,
+		volumeHeatMgr:   NewVolumeHeatMgr(nil, &VolumeHeatConfig{}),
 		shardRepairMgr:  shardRepairMgr,
 		blobDeleteMgr:   blobDeleteMgr,
 		clusterTopology: clusterTopology,
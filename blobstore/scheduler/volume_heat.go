@@ -0,0 +1,305 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/scheduler/base"
+	"github.com/cubefs/cubefs/blobstore/util/closer"
+	"github.com/cubefs/cubefs/blobstore/util/log"
+)
+
+const (
+	// minVolumeHeat heat less than it is considered to be zero and cleaned
+	minVolumeHeat = 0.01
+	// heatUnitBytes read size of one heat, event without size is counted as one heat
+	heatUnitBytes = 1 << 20
+)
+
+// VolumeHeatConfig volume heat config, heat is aggregated from read events sampled by access.
+type VolumeHeatConfig struct {
+	Topic     string          `json:"-"`
+	ClusterID proto.ClusterID `json:"-"`
+	// heat of volume halves after HalfLifeS without reading
+	HalfLifeS int `json:"half_life_s"`
+	// volume is hot if its heat is not less than HotThreshold
+	HotThreshold float64 `json:"hot_threshold"`
+	// hot volumes are not balanced in peak hours, empty range means no peak hours
+	PeakHours      HourRange `json:"peak_hours"`
+	MaxBatchSize   int       `json:"max_batch_size"`
+	BatchIntervalS int       `json:"batch_interval_s"`
+	// heat is saved to the local file periodically and loaded at startup, empty means not saved
+	SnapshotPath      string `json:"snapshot_path"`
+	SnapshotIntervalS int    `json:"snapshot_interval_s"`
+}
+
+// IVolumeHeat read heat of volumes
+type IVolumeHeat interface {
+	Heat(vid proto.Vid) float64
+	IsHot(vid proto.Vid) bool
+	InPeakHours(now time.Time) bool
+}
+
+type volumeHeat struct {
+	value      float64
+	updateTime time.Time
+}
+
+// volumeHeatSnapshot heat of volume saved in snapshot file
+type volumeHeatSnapshot struct {
+	Value      float64 `json:"value"`
+	UpdateTime int64   `json:"update_time"`
+}
+
+// VolumeHeatMgr aggregates sampled read events of blobs to heat of volumes,
+// each event weighs its read size and decays from the read time, so recent reads weigh more.
+// Heat is kept in memory and saved to the snapshot file if configured.
+type VolumeHeatMgr struct {
+	closer.Closer
+	cfg         *VolumeHeatConfig
+	kafkaClient base.KafkaConsumer
+	consumer    base.GroupConsumer
+
+	mu    sync.RWMutex
+	heats map[proto.Vid]*volumeHeat
+}
+
+// NewVolumeHeatMgr returns volume heat manager, heat of all volumes is zero if topic is not configured.
+func NewVolumeHeatMgr(kafkaClient base.KafkaConsumer, cfg *VolumeHeatConfig) *VolumeHeatMgr {
+	return &VolumeHeatMgr{
+		Closer:      closer.New(),
+		cfg:         cfg,
+		kafkaClient: kafkaClient,
+		heats:       make(map[proto.Vid]*volumeHeat),
+	}
+}
+
+// Run starts consuming read events
+func (mgr *VolumeHeatMgr) Run() error {
+	if mgr.cfg.Topic == "" {
+		return nil
+	}
+	if err := mgr.loadSnapshot(); err != nil {
+		return err
+	}
+	consumer, err := mgr.kafkaClient.StartKafkaConsumer(base.KafkaConsumerCfg{
+		Topic:        mgr.cfg.Topic,
+		MaxBatchSize: mgr.cfg.MaxBatchSize,
+		MaxWaitTimeS: mgr.cfg.BatchIntervalS,
+	}, mgr.Consume)
+	if err != nil {
+		return err
+	}
+	mgr.consumer = consumer
+	go mgr.cleanLoop()
+	return nil
+}
+
+// Close stops consuming read events
+func (mgr *VolumeHeatMgr) Close() {
+	mgr.Closer.Close()
+	if mgr.consumer != nil {
+		mgr.consumer.Stop()
+		if err := mgr.saveSnapshot(); err != nil {
+			log.Errorf("save volume heat snapshot failed: err[%+v]", err)
+		}
+	}
+}
+
+// Consume consumes read events, invalid events and events of other clusters are skipped
+func (mgr *VolumeHeatMgr) Consume(msgs []*sarama.ConsumerMessage, consumerPause base.ConsumerPause) bool {
+	now := time.Now()
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	for _, m := range msgs {
+		var msg proto.BlobReadMsg
+		if err := json.Unmarshal(m.Value, &msg); err != nil || !msg.IsValid() {
+			log.Warnf("skip invalid read event: value[%s], err[%+v]", string(m.Value), err)
+			continue
+		}
+		if msg.ClusterID != mgr.cfg.ClusterID {
+			log.Debugf("skip read event of other cluster: cluster_id[%d]", msg.ClusterID)
+			continue
+		}
+		mgr.add(msg.Vid, eventHeat(msg.Size), eventTime(msg.Time, now))
+	}
+	return true
+}
+
+// add adds heat read at t to volume, the event older than the last one is decayed to the update time,
+// so that the events replayed from backlog do not count as fresh reads
+func (mgr *VolumeHeatMgr) add(vid proto.Vid, value float64, t time.Time) {
+	heat, ok := mgr.heats[vid]
+	if !ok {
+		heat = &volumeHeat{updateTime: t}
+		mgr.heats[vid] = heat
+	}
+	if t.After(heat.updateTime) {
+		heat.value = mgr.decay(heat, t) + value
+		heat.updateTime = t
+		return
+	}
+	heat.value += value * mgr.decayFactor(heat.updateTime.Sub(t))
+}
+
+// eventHeat returns heat of read event weighted by read size
+func eventHeat(size uint64) float64 {
+	if size == 0 {
+		return 1
+	}
+	return float64(size) / heatUnitBytes
+}
+
+// eventTime returns read time of event, now is used if it is not set or in the future
+func eventTime(unix int64, now time.Time) time.Time {
+	if unix <= 0 || unix > now.Unix() {
+		return now
+	}
+	return time.Unix(unix, 0)
+}
+
+// Heat returns current heat of volume
+func (mgr *VolumeHeatMgr) Heat(vid proto.Vid) float64 {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	heat, ok := mgr.heats[vid]
+	if !ok {
+		return 0
+	}
+	return mgr.decay(heat, time.Now())
+}
+
+// IsHot returns true if heat of volume reaches the threshold
+func (mgr *VolumeHeatMgr) IsHot(vid proto.Vid) bool {
+	return mgr.cfg.HotThreshold > 0 && mgr.Heat(vid) >= mgr.cfg.HotThreshold
+}
+
+// InPeakHours returns true if now is in [from, to) of peak hours
+func (mgr *VolumeHeatMgr) InPeakHours(now time.Time) bool {
+	hour := now.Hour()
+	return hour >= mgr.cfg.PeakHours.From && hour < mgr.cfg.PeakHours.To
+}
+
+func (mgr *VolumeHeatMgr) decay(heat *volumeHeat, now time.Time) float64 {
+	return heat.value * mgr.decayFactor(now.Sub(heat.updateTime))
+}
+
+func (mgr *VolumeHeatMgr) decayFactor(elapsed time.Duration) float64 {
+	if mgr.cfg.HalfLifeS <= 0 || elapsed <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, elapsed.Seconds()/float64(mgr.cfg.HalfLifeS))
+}
+
+// cleanLoop removes volumes which are not read for a long time and saves snapshot periodically
+func (mgr *VolumeHeatMgr) cleanLoop() {
+	t := time.NewTicker(time.Duration(mgr.cfg.HalfLifeS) * time.Second)
+	defer t.Stop()
+	var snapshotC <-chan time.Time
+	if mgr.cfg.SnapshotPath != "" && mgr.cfg.SnapshotIntervalS > 0 {
+		snapshot := time.NewTicker(time.Duration(mgr.cfg.SnapshotIntervalS) * time.Second)
+		defer snapshot.Stop()
+		snapshotC = snapshot.C
+	}
+
+	for {
+		select {
+		case <-t.C:
+			mgr.clean(time.Now())
+		case <-snapshotC:
+			if err := mgr.saveSnapshot(); err != nil {
+				log.Errorf("save volume heat snapshot failed: err[%+v]", err)
+			}
+		case <-mgr.Done():
+			return
+		}
+	}
+}
+
+func (mgr *VolumeHeatMgr) clean(now time.Time) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	for vid, heat := range mgr.heats {
+		if mgr.decay(heat, now) < minVolumeHeat {
+			delete(mgr.heats, vid)
+		}
+	}
+}
+
+// saveSnapshot writes heat of all volumes to a temporary file and renames it to snapshot path
+func (mgr *VolumeHeatMgr) saveSnapshot() error {
+	if mgr.cfg.SnapshotPath == "" {
+		return nil
+	}
+	mgr.mu.RLock()
+	heats := make(map[proto.Vid]volumeHeatSnapshot, len(mgr.heats))
+	for vid, heat := range mgr.heats {
+		heats[vid] = volumeHeatSnapshot{Value: heat.value, UpdateTime: heat.updateTime.UnixNano()}
+	}
+	mgr.mu.RUnlock()
+
+	data, err := json.Marshal(heats)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(mgr.cfg.SnapshotPath), filepath.Base(mgr.cfg.SnapshotPath)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), mgr.cfg.SnapshotPath)
+}
+
+// loadSnapshot loads heat of volumes saved before restart
+func (mgr *VolumeHeatMgr) loadSnapshot() error {
+	if mgr.cfg.SnapshotPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(mgr.cfg.SnapshotPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	heats := make(map[proto.Vid]volumeHeatSnapshot)
+	if err = json.Unmarshal(data, &heats); err != nil {
+		return err
+	}
+
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	for vid, heat := range heats {
+		mgr.heats[vid] = &volumeHeat{value: heat.Value, updateTime: time.Unix(0, heat.UpdateTime)}
+	}
+	log.Infof("load volume heat snapshot: volumes[%d]", len(heats))
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/common/proto"
+)
+
+func newHotVolumeHeat(hotVids ...proto.Vid) *VolumeHeatMgr {
+	mgr := NewVolumeHeatMgr(nil, &VolumeHeatConfig{HalfLifeS: 3600, HotThreshold: 1, PeakHours: HourRange{From: 0, To: 24}})
+	for _, vid := range hotVids {
+		mgr.heats[vid] = &volumeHeat{value: 10, updateTime: time.Now()}
+	}
+	return mgr
+}
+
+func readMsg(t *testing.T, vid proto.Vid) *sarama.ConsumerMessage {
+	return readMsgOf(t, proto.BlobReadMsg{ClusterID: 1, Bid: 1, Vid: vid})
+}
+
+func readMsgOf(t *testing.T, msg proto.BlobReadMsg) *sarama.ConsumerMessage {
+	b, err := json.Marshal(msg)
+	require.NoError(t, err)
+	return &sarama.ConsumerMessage{Value: b}
+}
+
+func TestVolumeHeatMgr(t *testing.T) {
+	ctr := gomock.NewController(t)
+	kafkaClient := NewMockKafkaConsumer(ctr)
+	consumer := NewMockGroupConsumer(ctr)
+
+	// disabled without topic
+	mgr := NewVolumeHeatMgr(kafkaClient, &VolumeHeatConfig{})
+	require.NoError(t, mgr.Run())
+	require.Zero(t, mgr.Heat(1))
+	require.False(t, mgr.IsHot(1))
+	require.False(t, mgr.InPeakHours(time.Now()))
+	mgr.Close()
+
+	cfg := &VolumeHeatConfig{
+		Topic:        "blob_read",
+		ClusterID:    1,
+		HalfLifeS:    60,
+		HotThreshold: 1.5,
+		PeakHours:    HourRange{From: 8, To: 20},
+	}
+	mgr = NewVolumeHeatMgr(kafkaClient, cfg)
+	kafkaClient.EXPECT().StartKafkaConsumer(any, any).Return(nil, errMock)
+	require.ErrorIs(t, mgr.Run(), errMock)
+	kafkaClient.EXPECT().StartKafkaConsumer(any, any).Return(consumer, nil)
+	require.NoError(t, mgr.Run())
+	defer func() {
+		consumer.EXPECT().Stop().Return()
+		mgr.Close()
+	}()
+
+	require.True(t, mgr.Consume([]*sarama.ConsumerMessage{
+		readMsg(t, 1), readMsg(t, 1), readMsg(t, 2), readMsg(t, proto.InvalidVid), {Value: []byte("x")},
+	}, nil))
+	require.InDelta(t, 2, mgr.Heat(1), 0.01)
+	require.InDelta(t, 1, mgr.Heat(2), 0.01)
+	require.True(t, mgr.IsHot(1))
+	require.False(t, mgr.IsHot(2))
+	require.Len(t, mgr.heats, 2)
+
+	// heat halves after half life
+	mgr.heats[1].updateTime = time.Now().Add(-time.Minute)
+	require.InDelta(t, 1, mgr.Heat(1), 0.01)
+	require.False(t, mgr.IsHot(1))
+
+	mgr.heats[2].updateTime = time.Now().Add(-time.Hour)
+	mgr.clean(time.Now())
+	require.Len(t, mgr.heats, 1)
+	require.Zero(t, mgr.Heat(2))
+
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	require.False(t, mgr.InPeakHours(day.Add(7*time.Hour)))
+	require.True(t, mgr.InPeakHours(day.Add(8*time.Hour)))
+	require.False(t, mgr.InPeakHours(day.Add(20*time.Hour)))
+}
+
+func TestVolumeHeatMgrEvents(t *testing.T) {
+	mgr := NewVolumeHeatMgr(nil, &VolumeHeatConfig{ClusterID: 1, HalfLifeS: 60})
+	now := time.Now()
+
+	// events of other clusters are skipped
+	mgr.Consume([]*sarama.ConsumerMessage{readMsgOf(t, proto.BlobReadMsg{ClusterID: 2, Bid: 1, Vid: 1})}, nil)
+	require.Zero(t, mgr.Heat(1))
+
+	// weighted by read size
+	mgr.Consume([]*sarama.ConsumerMessage{
+		readMsgOf(t, proto.BlobReadMsg{ClusterID: 1, Bid: 1, Vid: 1, Size: 4 * heatUnitBytes, Time: now.Unix()}),
+		readMsgOf(t, proto.BlobReadMsg{ClusterID: 1, Bid: 1, Vid: 2, Size: heatUnitBytes / 2, Time: now.Unix()}),
+	}, nil)
+	require.InDelta(t, 4, mgr.Heat(1), 0.1)
+	require.InDelta(t, 0.5, mgr.Heat(2), 0.1)
+
+	// replayed events decay from the read time
+	mgr.Consume([]*sarama.ConsumerMessage{
+		readMsgOf(t, proto.BlobReadMsg{ClusterID: 1, Bid: 1, Vid: 3, Size: 4 * heatUnitBytes, Time: now.Add(-2 * time.Minute).Unix()}),
+		readMsgOf(t, proto.BlobReadMsg{ClusterID: 1, Bid: 1, Vid: 1, Size: 4 * heatUnitBytes, Time: now.Add(-time.Minute).Unix()}),
+	}, nil)
+	require.InDelta(t, 1, mgr.Heat(3), 0.1)
+	require.InDelta(t, 6, mgr.Heat(1), 0.1)
+
+	// events in the future are read now
+	require.Equal(t, now, eventTime(now.Add(time.Hour).Unix(), now))
+	require.Equal(t, now, eventTime(0, now))
+}
+
+func TestVolumeHeatMgrSnapshot(t *testing.T) {
+	ctr := gomock.NewController(t)
+	kafkaClient := NewMockKafkaConsumer(ctr)
+	consumer := NewMockGroupConsumer(ctr)
+	dir, err := os.MkdirTemp("", "volume_heat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := &VolumeHeatConfig{Topic: "blob_read", ClusterID: 1, HalfLifeS: 3600, SnapshotPath: filepath.Join(dir, "heat")}
+	mgr := NewVolumeHeatMgr(kafkaClient, cfg)
+	kafkaClient.EXPECT().StartKafkaConsumer(any, any).Times(2).Return(consumer, nil)
+	require.NoError(t, mgr.Run())
+	mgr.Consume([]*sarama.ConsumerMessage{readMsg(t, 1), readMsg(t, 1)}, nil)
+	consumer.EXPECT().Stop().Times(2).Return()
+	mgr.Close()
+
+	// heat is loaded after restart
+	mgr = NewVolumeHeatMgr(kafkaClient, cfg)
+	require.NoError(t, mgr.Run())
+	defer mgr.Close()
+	require.InDelta(t, 2, mgr.Heat(1), 0.01)
+
+	require.NoError(t, os.WriteFile(cfg.SnapshotPath, []byte("x"), 0o644))
+	require.Error(t, NewVolumeHeatMgr(kafkaClient, cfg).Run())
+}
@@ -180,6 +180,9 @@ type VolumeInspectMgr struct {
 	repairShardSender client.ProxyAPI
 	sendDeduplicator  *badShardDeduplicator
 
+	// volumes which have saved inspect result, loaded from clustermgr lazily
+	missedVols map[proto.Vid]struct{}
+
 	completeTaskCounter counter.Counter
 	timeoutCounter      counter.Counter
 
@@ -414,11 +417,15 @@ func (mgr *VolumeInspectMgr) finish(ctx context.Context) {
 
 	// collect missed bids
 	var missedShards [][]*proto.MissedShard
+	var cleanVids []proto.Vid
 	for _, task := range mgr.tasks {
 		if task.hasMissedShard() {
 			missedShards = append(missedShards, task.ret.MissedShards)
 			continue
 		}
+		if task.completed() && task.ret.Err() == nil && len(task.t.Replicas) > 0 {
+			cleanVids = append(cleanVids, task.t.Replicas[0].Vuid.Vid())
+		}
 	}
 
 	// clear & stats tasks
@@ -462,7 +469,9 @@ func (mgr *VolumeInspectMgr) finish(ctx context.Context) {
 				return mgr.trySendShardRepairMsg(ctx, vid, bid, bads)
 			})
 		}
+		mgr.saveInspectResult(ctx, vid, bidsBads)
 	}
+	mgr.clearInspectResults(ctx, cleanVids)
 
 	err := retry.Timed(3, 200).On(func() error {
 		return mgr.clusterMgrCli.SetVolumeInspectCheckPoint(ctx, mgr.nextVid)
@@ -472,6 +481,57 @@ func (mgr *VolumeInspectMgr) finish(ctx context.Context) {
 	}
 }
 
+// saveInspectResult saves the inspect result of volume which missed shards, it shows in volume health of clustermgr
+func (mgr *VolumeInspectMgr) saveInspectResult(ctx context.Context, vid proto.Vid, bidsBads map[proto.BlobID][]uint8) {
+	span := trace.SpanFromContextSafe(ctx)
+	ret := &proto.VolumeInspectResult{
+		Vid:         vid,
+		MissedBlobs: len(bidsBads),
+		Ctime:       time.Now().Unix(),
+	}
+	for _, bads := range bidsBads {
+		if len(bads) > ret.MaxMissedShards {
+			ret.MaxMissedShards = len(bads)
+		}
+	}
+	if err := mgr.clusterMgrCli.SetVolumeInspectResult(ctx, ret); err != nil {
+		span.Warnf("save inspect result failed: vid[%d], err[%+v]", vid, err)
+		return
+	}
+	if mgr.missedVols != nil {
+		mgr.missedVols[vid] = struct{}{}
+	}
+}
+
+// clearInspectResults deletes the saved inspect results of volumes which missed no shards now
+func (mgr *VolumeInspectMgr) clearInspectResults(ctx context.Context, vids []proto.Vid) {
+	if len(vids) == 0 {
+		return
+	}
+	span := trace.SpanFromContextSafe(ctx)
+	if mgr.missedVols == nil {
+		rets, err := mgr.clusterMgrCli.ListVolumeInspectResults(ctx)
+		if err != nil {
+			span.Warnf("list inspect results failed: err[%+v]", err)
+			return
+		}
+		mgr.missedVols = make(map[proto.Vid]struct{}, len(rets))
+		for _, ret := range rets {
+			mgr.missedVols[ret.Vid] = struct{}{}
+		}
+	}
+	for _, vid := range vids {
+		if _, ok := mgr.missedVols[vid]; !ok {
+			continue
+		}
+		if err := mgr.clusterMgrCli.DeleteVolumeInspectResult(ctx, vid); err != nil {
+			span.Warnf("delete inspect result failed: vid[%d], err[%+v]", vid, err)
+			continue
+		}
+		delete(mgr.missedVols, vid)
+	}
+}
+
 func (mgr *VolumeInspectMgr) collectVolInspectBads(
 	ctx context.Context,
 	volMissedShards []*proto.MissedShard) (bidsMissed map[proto.BlobID][]uint8, err error,
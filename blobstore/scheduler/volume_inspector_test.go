@@ -218,11 +218,44 @@ func TestInspectorFinish(t *testing.T) {
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().GetVolumeInfo(any, any).Return(volume, nil)
 		mgr.repairShardSender.(*MockMqProxyAPI).EXPECT().SendShardRepairMsg(any, any, any, any).Return(errMock)
 		mgr.repairShardSender.(*MockMqProxyAPI).EXPECT().SendShardRepairMsg(any, any, any, any).AnyTimes().Return(nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().SetVolumeInspectResult(any, any).DoAndReturn(
+			func(_ context.Context, ret *proto.VolumeInspectResult) error {
+				require.Equal(t, proto.Vid(100012), ret.Vid)
+				require.Equal(t, 2, ret.MissedBlobs)
+				require.Equal(t, 1, ret.MaxMissedShards)
+				return nil
+			})
 		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().SetVolumeInspectCheckPoint(any, any).Return(nil)
 
 		mgr.finish(ctx)
 		require.Equal(t, 0, len(mgr.tasks))
 	}
+	{
+		mgr := newInspector(t)
+
+		mgr.cfg.InspectBatch = 2
+		mgr.cfg.ListVolStep = 2
+
+		volume1 := MockGenVolInfo(100012, codemode.EC6P6, proto.VolumeStatusIdle)
+		volume2 := MockGenVolInfo(100013, codemode.EC6P6, proto.VolumeStatusIdle)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().GetVolumeInspectCheckPoint(any).AnyTimes().Return(&proto.VolumeInspectCheckPoint{}, nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolume(any, any, any).Return([]*client.VolumeInfoSimple{volume1, volume2}, proto.Vid(0), nil)
+
+		mgr.prepare(ctx)
+		require.Equal(t, 2, len(mgr.tasks))
+
+		// inspect results of volumes which missed no shards now are deleted
+		for _, task := range mgr.tasks {
+			task.ret = &proto.VolumeInspectRet{TaskID: task.t.TaskID}
+		}
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().ListVolumeInspectResults(any).Return(
+			[]*proto.VolumeInspectResult{{Vid: 100013, MissedBlobs: 1, MaxMissedShards: 1}}, nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().DeleteVolumeInspectResult(any, proto.Vid(100013)).Return(nil)
+		mgr.clusterMgrCli.(*MockClusterMgrAPI).EXPECT().SetVolumeInspectCheckPoint(any, any).Return(nil)
+		mgr.finish(ctx)
+		require.Equal(t, 0, len(mgr.tasks))
+		require.Equal(t, 0, len(mgr.missedVols))
+	}
 }
 
 func TestInspectorAcquire(t *testing.T) {
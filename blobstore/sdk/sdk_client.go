@@ -82,11 +82,16 @@ func New(conf *Config) (acapi.API, error) {
 	if err != nil {
 		return nil, err
 	}
+	lim, err := stream.NewLimiter(conf.Limit)
+	if err != nil {
+		cl.Close()
+		return nil, err
+	}
 
 	return &sdkHandler{
 		conf:    *conf,
 		handler: h,
-		limiter: stream.NewLimiter(conf.Limit),
+		limiter: lim,
 		closer:  cl,
 	}, nil
 }
@@ -22,7 +22,7 @@ import (
 func newSdkHandler() *sdkHandler {
 	ctr := gomock.NewController(&testing.T{})
 	h := mocks.NewMockStreamHandler(ctr)
-	l := stream.NewLimiter(stream.LimitConfig{
+	l, _ := stream.NewLimiter(stream.LimitConfig{
 		NameRps: map[string]int{"alloc": 2},
 	})
 
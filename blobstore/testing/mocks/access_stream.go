@@ -124,3 +124,18 @@ func (mr *MockStreamHandlerMockRecorder) PutAt(arg0, arg1, arg2, arg3, arg4, arg
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAt", reflect.TypeOf((*MockStreamHandler)(nil).PutAt), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
+
+// PutInline mocks base method.
+func (m *MockStreamHandler) PutInline(arg0 context.Context, arg1 io.Reader, arg2 int64, arg3 access.HasherMap) (*access.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutInline", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*access.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutInline indicates an expected call of PutInline.
+func (mr *MockStreamHandlerMockRecorder) PutInline(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInline", reflect.TypeOf((*MockStreamHandler)(nil).PutInline), arg0, arg1, arg2, arg3)
+}
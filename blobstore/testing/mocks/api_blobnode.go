@@ -51,6 +51,21 @@ func (mr *MockStorageAPIMockRecorder) Close(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStorageAPI)(nil).Close), arg0, arg1)
 }
 
+// CompactStat mocks base method.
+func (m *MockStorageAPI) CompactStat(arg0 context.Context, arg1 string, arg2 *blobnode.CompactStatArgs) (*blobnode.CompactStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactStat", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*blobnode.CompactStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactStat indicates an expected call of CompactStat.
+func (mr *MockStorageAPIMockRecorder) CompactStat(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactStat", reflect.TypeOf((*MockStorageAPI)(nil).CompactStat), arg0, arg1, arg2)
+}
+
 // CreateChunk mocks base method.
 func (m *MockStorageAPI) CreateChunk(arg0 context.Context, arg1 string, arg2 *blobnode.CreateChunkArgs) error {
 	m.ctrl.T.Helper()
@@ -152,6 +152,50 @@ func (mr *MockISchedulerMockRecorder) DiskMigratingStats(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskMigratingStats", reflect.TypeOf((*MockIScheduler)(nil).DiskMigratingStats), arg0, arg1)
 }
 
+// DrainHost mocks base method.
+func (m *MockIScheduler) DrainHost(arg0 context.Context, arg1 *scheduler.HostDrainArgs) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainHost", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DrainHost indicates an expected call of DrainHost.
+func (mr *MockISchedulerMockRecorder) DrainHost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainHost", reflect.TypeOf((*MockIScheduler)(nil).DrainHost), arg0, arg1)
+}
+
+// GetQueueParams mocks base method.
+func (m *MockIScheduler) GetQueueParams(arg0 context.Context, arg1 *scheduler.QueueParamsArgs) (*scheduler.QueueParams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueParams", arg0, arg1)
+	ret0, _ := ret[0].(*scheduler.QueueParams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueParams indicates an expected call of GetQueueParams.
+func (mr *MockISchedulerMockRecorder) GetQueueParams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueParams", reflect.TypeOf((*MockIScheduler)(nil).GetQueueParams), arg0, arg1)
+}
+
+// HostDrainStat mocks base method.
+func (m *MockIScheduler) HostDrainStat(arg0 context.Context, arg1 *scheduler.HostDrainStatArgs) (*scheduler.HostDrainStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HostDrainStat", arg0, arg1)
+	ret0, _ := ret[0].(*scheduler.HostDrainStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HostDrainStat indicates an expected call of HostDrainStat.
+func (mr *MockISchedulerMockRecorder) HostDrainStat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HostDrainStat", reflect.TypeOf((*MockIScheduler)(nil).HostDrainStat), arg0, arg1)
+}
+
 // LeaderStats mocks base method.
 func (m *MockIScheduler) LeaderStats(arg0 context.Context) (scheduler.TasksStat, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +211,21 @@ func (mr *MockISchedulerMockRecorder) LeaderStats(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaderStats", reflect.TypeOf((*MockIScheduler)(nil).LeaderStats), arg0)
 }
 
+// ListTasks mocks base method.
+func (m *MockIScheduler) ListTasks(arg0 context.Context, arg1 *scheduler.ListTasksArgs) (*scheduler.ListTasksRet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", arg0, arg1)
+	ret0, _ := ret[0].(*scheduler.ListTasksRet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockISchedulerMockRecorder) ListTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockIScheduler)(nil).ListTasks), arg0, arg1)
+}
+
 // ReclaimTask mocks base method.
 func (m *MockIScheduler) ReclaimTask(arg0 context.Context, arg1 *scheduler.OperateTaskArgs) error {
 	m.ctrl.T.Helper()
@@ -210,6 +269,20 @@ func (mr *MockISchedulerMockRecorder) ReportTask(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportTask", reflect.TypeOf((*MockIScheduler)(nil).ReportTask), arg0, arg1)
 }
 
+// SetQueueParams mocks base method.
+func (m *MockIScheduler) SetQueueParams(arg0 context.Context, arg1 *scheduler.QueueParamsSetArgs) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQueueParams", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetQueueParams indicates an expected call of SetQueueParams.
+func (mr *MockISchedulerMockRecorder) SetQueueParams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQueueParams", reflect.TypeOf((*MockIScheduler)(nil).SetQueueParams), arg0, arg1)
+}
+
 // Stats mocks base method.
 func (m *MockIScheduler) Stats(arg0 context.Context, arg1 string) (scheduler.TasksStat, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,185 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package bytespool
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Config of tiered pool.
+type Config struct {
+	// SizeClasses sizes of buffer classes, oversize buffer is not pooled.
+	SizeClasses []int `json:"size_classes"`
+	// MaxPooledBytes total bytes of idle buffers kept in pool,
+	// buffers are dropped to gc when exceeding. 0 means no limit,
+	// the idle buffers are kept in sync.Pool and released by gc.
+	MaxPooledBytes int64 `json:"max_pooled_bytes"`
+}
+
+// ClassStats statistics of one size class, size 0 is the class of oversize.
+type ClassStats struct {
+	Size    int    `json:"size"`
+	Alloc   uint64 `json:"alloc"`   // number of Alloc
+	Hit     uint64 `json:"hit"`     // reused from pool
+	Miss    uint64 `json:"miss"`    // made a new buffer
+	Dropped uint64 `json:"dropped"` // freed but not pooled
+	Idle    int    `json:"idle"`    // idle buffers in pool, -1 if unknown
+}
+
+// Stats statistics of tiered pool.
+type Stats struct {
+	PooledBytes    int64        `json:"pooled_bytes"`
+	MaxPooledBytes int64        `json:"max_pooled_bytes"`
+	Classes        []ClassStats `json:"classes"`
+}
+
+type sizeClass struct {
+	size    int
+	alloc   uint64
+	hit     uint64
+	miss    uint64
+	dropped uint64
+
+	pool sync.Pool   // no limit of pooled bytes
+	free chan []byte // limited pooled bytes
+}
+
+// Pool tiered bytes pool with multiple size classes.
+type Pool struct {
+	classes  []*sizeClass
+	oversize sizeClass
+
+	maxPooled int64
+	pooled    int64
+}
+
+// NewPool returns a tiered bytes pool.
+func NewPool(cfg Config) *Pool {
+	sizes := make([]int, 0, len(cfg.SizeClasses))
+	for _, size := range cfg.SizeClasses {
+		if size > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Ints(sizes)
+
+	p := &Pool{maxPooled: cfg.MaxPooledBytes}
+	for idx, size := range sizes {
+		if idx > 0 && size == sizes[idx-1] {
+			continue
+		}
+		class := &sizeClass{size: size}
+		if p.maxPooled > 0 {
+			class.free = make(chan []byte, p.maxPooled/int64(size))
+		}
+		p.classes = append(p.classes, class)
+	}
+	return p
+}
+
+// Alloc returns a buffer with length of size, oversize buffer is made directly.
+func (p *Pool) Alloc(size int) []byte {
+	for _, class := range p.classes {
+		if size <= class.size {
+			atomic.AddUint64(&class.alloc, 1)
+			return p.get(class)[:size]
+		}
+	}
+	atomic.AddUint64(&p.oversize.alloc, 1)
+	atomic.AddUint64(&p.oversize.miss, 1)
+	return make([]byte, size)
+}
+
+func (p *Pool) get(class *sizeClass) []byte {
+	if class.free == nil {
+		if b, ok := class.pool.Get().([]byte); ok {
+			atomic.AddUint64(&class.hit, 1)
+			return b
+		}
+	} else {
+		select {
+		case b := <-class.free:
+			atomic.AddInt64(&p.pooled, -int64(class.size))
+			atomic.AddUint64(&class.hit, 1)
+			return b
+		default:
+		}
+	}
+	atomic.AddUint64(&class.miss, 1)
+	return make([]byte, class.size)
+}
+
+// Free puts the buffer back to the largest class not greater than its capacity.
+func (p *Pool) Free(b []byte) {
+	size := cap(b)
+	for ii := len(p.classes) - 1; ii >= 0; ii-- {
+		class := p.classes[ii]
+		if size < class.size {
+			continue
+		}
+		if ii == len(p.classes)-1 && size > class.size {
+			break
+		}
+		p.put(class, b[:class.size])
+		return
+	}
+	atomic.AddUint64(&p.oversize.dropped, 1)
+}
+
+func (p *Pool) put(class *sizeClass, b []byte) {
+	if class.free == nil {
+		class.pool.Put(b) // nolint: staticcheck
+		return
+	}
+	if atomic.AddInt64(&p.pooled, int64(class.size)) > p.maxPooled {
+		atomic.AddInt64(&p.pooled, -int64(class.size))
+		atomic.AddUint64(&class.dropped, 1)
+		return
+	}
+	select {
+	case class.free <- b:
+	default:
+		atomic.AddInt64(&p.pooled, -int64(class.size))
+		atomic.AddUint64(&class.dropped, 1)
+	}
+}
+
+// Stats returns statistics of the pool, the last class is oversize.
+func (p *Pool) Stats() Stats {
+	st := Stats{
+		PooledBytes:    atomic.LoadInt64(&p.pooled),
+		MaxPooledBytes: p.maxPooled,
+		Classes:        make([]ClassStats, 0, len(p.classes)+1),
+	}
+	classes := make([]*sizeClass, 0, len(p.classes)+1)
+	classes = append(classes, p.classes...)
+	for _, class := range append(classes, &p.oversize) {
+		idle := -1
+		if class.free != nil {
+			idle = len(class.free)
+		}
+		st.Classes = append(st.Classes, ClassStats{
+			Size:    class.size,
+			Alloc:   atomic.LoadUint64(&class.alloc),
+			Hit:     atomic.LoadUint64(&class.hit),
+			Miss:    atomic.LoadUint64(&class.miss),
+			Dropped: atomic.LoadUint64(&class.dropped),
+			Idle:    idle,
+		})
+	}
+	return st
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package bytespool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/util/bytespool"
+)
+
+func TestTieredPoolLimited(t *testing.T) {
+	pool := bytespool.NewPool(bytespool.Config{
+		SizeClasses:    []int{1 << 20, 1 << 10, 0, 1 << 10},
+		MaxPooledBytes: 1<<20 + 1<<10,
+	})
+
+	b1 := pool.Alloc(100)
+	require.Len(t, b1, 100)
+	require.Equal(t, 1<<10, cap(b1))
+	b2 := pool.Alloc(1 << 19)
+	b3 := pool.Alloc(1 << 20)
+	b4 := pool.Alloc(1<<20 + 1)
+	require.Equal(t, 1<<20+1, len(b4))
+
+	pool.Free(b1)
+	pool.Free(b2)
+	pool.Free(b3)                  // exceeds max pooled bytes
+	pool.Free(b4)                  // oversize
+	pool.Free(make([]byte, 1<<11)) // exceeds max pooled bytes
+	require.Equal(t, int64(1<<20+1<<10), pool.Stats().PooledBytes)
+	require.Equal(t, 1<<10, cap(pool.Alloc(1)))
+	require.Equal(t, 1<<20, cap(pool.Alloc(1<<20)))
+
+	st := pool.Stats()
+	require.Equal(t, int64(0), st.PooledBytes)
+	require.Equal(t, int64(1<<20+1<<10), st.MaxPooledBytes)
+	require.Equal(t, []bytespool.ClassStats{
+		{Size: 1 << 10, Alloc: 2, Hit: 1, Miss: 1, Dropped: 1, Idle: 0},
+		{Size: 1 << 20, Alloc: 3, Hit: 1, Miss: 2, Dropped: 1, Idle: 0},
+		{Size: 0, Alloc: 1, Hit: 0, Miss: 1, Dropped: 1, Idle: -1},
+	}, st.Classes)
+}
+
+func TestTieredPoolUnlimited(t *testing.T) {
+	pool := bytespool.NewPool(bytespool.Config{SizeClasses: []int{1 << 10, 1 << 12}})
+	for _, size := range []int{0, 1, 1 << 10, 1<<10 + 1, 1 << 12} {
+		b := pool.Alloc(size)
+		require.Len(t, b, size)
+		pool.Free(b)
+	}
+	pool.Free(make([]byte, 1<<13))
+
+	st := pool.Stats()
+	require.Equal(t, int64(0), st.PooledBytes)
+	require.Len(t, st.Classes, 3)
+	require.Equal(t, uint64(3), st.Classes[0].Alloc)
+	require.Equal(t, uint64(2), st.Classes[1].Alloc)
+	require.Equal(t, st.Classes[1].Alloc, st.Classes[1].Hit+st.Classes[1].Miss)
+	require.Equal(t, -1, st.Classes[1].Idle)
+	require.Equal(t, uint64(1), st.Classes[2].Dropped)
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package taskpool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// PriorityTaskPool limited pool with priority lanes, lane 0 is the highest priority.
+// Workers dequeue lanes by weights, so latency-critical tasks are not queued
+// behind bulk background tasks, and low priority tasks are not starved.
+type PriorityTaskPool struct {
+	lanes    []chan func()
+	ready    chan struct{} // one token of each task in lanes
+	schedule []int         // weighted round robin sequence of lane index
+	wg       *sync.WaitGroup
+	doing    *uint32
+}
+
+// NewPriority returns priority task pool with workerCount, poolSize of each lane,
+// and weights of lanes, the number of lanes is len(weights).
+// Weight less than 1 is considered as 1.
+func NewPriority(workerCount, poolSize int, weights []int) PriorityTaskPool {
+	if len(weights) == 0 {
+		weights = []int{1}
+	}
+	// lane must be buffered, the token is sent after the task is queued
+	if poolSize < 1 {
+		poolSize = 1
+	}
+
+	tp := PriorityTaskPool{
+		lanes: make([]chan func(), len(weights)),
+		ready: make(chan struct{}, poolSize*len(weights)),
+		wg:    &sync.WaitGroup{},
+		doing: new(uint32),
+	}
+	for idx, weight := range weights {
+		tp.lanes[idx] = make(chan func(), poolSize)
+		if weight < 1 {
+			weight = 1
+		}
+		for i := 0; i < weight; i++ {
+			tp.schedule = append(tp.schedule, idx)
+		}
+	}
+
+	tp.wg.Add(workerCount)
+	for i := 0; i < workerCount; i++ {
+		go func(cursor int) {
+			defer tp.wg.Done()
+			for range tp.ready {
+				var task func()
+				task, cursor = tp.dequeue(cursor)
+				atomic.AddUint32(tp.doing, 1)
+				task()
+				atomic.AddUint32(tp.doing, ^uint32(0))
+			}
+		}(i % len(tp.schedule))
+	}
+	return tp
+}
+
+// dequeue picks a task by weighted round robin from cursor of schedule,
+// there must be a task in lanes after the worker got a token.
+func (tp PriorityTaskPool) dequeue(cursor int) (func(), int) {
+	for {
+		for i := 0; i < len(tp.schedule); i++ {
+			lane := tp.schedule[cursor]
+			cursor = (cursor + 1) % len(tp.schedule)
+			select {
+			case task := <-tp.lanes[lane]:
+				return task, cursor
+			default:
+			}
+		}
+	}
+}
+
+func (tp PriorityTaskPool) lane(priority int) chan func() {
+	if priority < 0 {
+		priority = 0
+	}
+	if priority >= len(tp.lanes) {
+		priority = len(tp.lanes) - 1
+	}
+	return tp.lanes[priority]
+}
+
+// Run add task to lane of priority, block if the lane is full,
+// priority out of range is clamped to the highest or lowest lane.
+func (tp PriorityTaskPool) Run(priority int, task func()) {
+	tp.lane(priority) <- task
+	tp.ready <- struct{}{}
+}
+
+// TryRun try to add task to lane of priority, return immediately
+func (tp PriorityTaskPool) TryRun(priority int, task func()) bool {
+	select {
+	case tp.lane(priority) <- task:
+		tp.ready <- struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// Running returns the number of running tasks
+func (tp PriorityTaskPool) Running() uint32 {
+	return atomic.LoadUint32(tp.doing)
+}
+
+// Close the pool after queued tasks are done, the function is concurrent unsafe
+func (tp PriorityTaskPool) Close() {
+	close(tp.ready)
+	tp.wg.Wait()
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package taskpool_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cubefs/cubefs/blobstore/util/taskpool"
+)
+
+func TestPriorityTaskPool(t *testing.T) {
+	runner := taskpool.NewPriority(1, 4, []int{2, 1})
+
+	block := make(chan struct{})
+	runner.Run(0, func() { <-block })
+	require.Eventually(t, func() bool { return runner.Running() == 1 }, time.Second, time.Millisecond)
+
+	var mu sync.Mutex
+	var order []int
+	for i := 0; i < 3; i++ {
+		runner.Run(1, func() {
+			mu.Lock()
+			order = append(order, 1)
+			mu.Unlock()
+		})
+	}
+	for i := 0; i < 4; i++ {
+		runner.Run(-1, func() {
+			mu.Lock()
+			order = append(order, 0)
+			mu.Unlock()
+		})
+	}
+	require.False(t, runner.TryRun(0, func() {}))
+	require.True(t, runner.TryRun(100, func() {}))
+	require.False(t, runner.TryRun(1, func() {}))
+
+	close(block)
+	runner.Close()
+	// schedule of weights is [0, 0, 1], the cursor is 1 after the blocking task
+	require.Equal(t, []int{0, 1, 0, 0, 1, 0, 1}, order)
+	require.Equal(t, uint32(0), runner.Running())
+}
@@ -16,8 +16,13 @@
 package taskpool
 
 import (
+	"context"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/util/log"
 )
 
 // TaskPool limited pool
@@ -25,6 +30,25 @@ type TaskPool struct {
 	pool  chan func()
 	wg    *sync.WaitGroup
 	doing *uint32
+	ctl   *poolControl
+}
+
+// poolControl controls the number of workers
+type poolControl struct {
+	sync.Mutex
+	workers  int
+	quit     chan struct{}
+	closed   chan struct{}
+	executed uint64
+}
+
+// Stats statistics of task pool
+type Stats struct {
+	Workers    int    `json:"workers"`     // number of workers after resizing
+	Running    uint32 `json:"running"`     // number of busy workers
+	QueueDepth int    `json:"queue_depth"` // number of tasks waiting in queue
+	QueueSize  int    `json:"queue_size"`
+	Executed   uint64 `json:"executed"` // number of executed tasks
 }
 
 // New returns task pool with workerCount and poolSize
@@ -32,24 +56,79 @@ func New(workerCount, poolSize int) TaskPool {
 	pool := make(chan func(), poolSize)
 	wg := &sync.WaitGroup{}
 	doing := uint32(0)
-	tp := TaskPool{pool: pool, wg: wg, doing: &doing}
+	ctl := &poolControl{quit: make(chan struct{}), closed: make(chan struct{})}
+	tp := TaskPool{pool: pool, wg: wg, doing: &doing, ctl: ctl}
 
-	wg.Add(workerCount)
-	for i := 0; i < workerCount; i++ {
+	tp.startWorkers(workerCount)
+	ctl.workers = workerCount
+	return tp
+}
+
+func (tp TaskPool) startWorkers(n int) {
+	tp.wg.Add(n)
+	for i := 0; i < n; i++ {
 		go func() {
-			defer wg.Done()
+			defer tp.wg.Done()
 			for {
-				task, ok := <-pool
-				if !ok {
-					break
+				select {
+				case task, ok := <-tp.pool:
+					if !ok {
+						return
+					}
+					atomic.AddUint32(tp.doing, 1)
+					task()
+					atomic.AddUint32(tp.doing, ^uint32(0))
+					atomic.AddUint64(&tp.ctl.executed, 1)
+				case <-tp.ctl.quit:
+					return
 				}
-				atomic.AddUint32(tp.doing, 1)
-				task()
-				atomic.AddUint32(tp.doing, ^uint32(0))
 			}
 		}()
 	}
-	return tp
+}
+
+// Resize grows or shrinks workers to workerCount at runtime,
+// surplus workers exit after finishing their running tasks.
+func (tp TaskPool) Resize(workerCount int) {
+	if workerCount < 0 {
+		workerCount = 0
+	}
+	tp.ctl.Lock()
+	defer tp.ctl.Unlock()
+	select {
+	case <-tp.ctl.closed:
+		return
+	default:
+	}
+
+	if n := workerCount - tp.ctl.workers; n > 0 {
+		tp.startWorkers(n)
+	} else if n < 0 {
+		go func() {
+			for i := 0; i < -n; i++ {
+				select {
+				case tp.ctl.quit <- struct{}{}:
+				case <-tp.ctl.closed:
+					return
+				}
+			}
+		}()
+	}
+	tp.ctl.workers = workerCount
+}
+
+// Stats returns statistics of the pool
+func (tp TaskPool) Stats() Stats {
+	tp.ctl.Lock()
+	workers := tp.ctl.workers
+	tp.ctl.Unlock()
+	return Stats{
+		Workers:    workers,
+		Running:    tp.Running(),
+		QueueDepth: len(tp.pool),
+		QueueSize:  cap(tp.pool),
+		Executed:   atomic.LoadUint64(&tp.ctl.executed),
+	}
 }
 
 // Run add task to pool, block if pool is full
@@ -67,12 +146,55 @@ func (tp TaskPool) TryRun(task func()) bool {
 	}
 }
 
+// RunWithContext add task to pool, block if pool is full until ctx is done.
+// Returns error of ctx if the task is not added,
+// the task is abandoned if ctx is done before it is executed.
+func (tp TaskPool) RunWithContext(ctx context.Context, task func(context.Context)) error {
+	return tp.RunWithTimeout(ctx, 0, task)
+}
+
+// RunWithTimeout is same as RunWithContext, ctx of the task is
+// canceled after timeout since it begins executing, 0 means no timeout.
+// The task should return in time when its ctx is done, panic of the task is recovered.
+func (tp TaskPool) RunWithTimeout(ctx context.Context, timeout time.Duration, task func(context.Context)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fn := func() {
+		if ctx.Err() != nil {
+			return
+		}
+		runCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				log.Errorf("taskpool: panic fired in task: %v\n%s", p, debug.Stack())
+			}
+		}()
+		task(runCtx)
+	}
+
+	select {
+	case tp.pool <- fn:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (tp TaskPool) Running() uint32 {
 	return atomic.LoadUint32(tp.doing)
 }
 
 // Close the pool, the function is concurrent unsafe
 func (tp TaskPool) Close() {
+	tp.ctl.Lock()
+	close(tp.ctl.closed)
+	tp.ctl.Unlock()
 	close(tp.pool)
 	tp.wg.Wait()
 }
@@ -15,6 +15,7 @@
 package taskpool_test
 
 import (
+	"context"
 	"math"
 	"sync"
 	"testing"
@@ -44,6 +45,96 @@ func TestTaskpoolBase(t *testing.T) {
 	runner.Close()
 }
 
+func TestTaskpoolRunWithContext(t *testing.T) {
+	runner := taskpool.New(1, 1)
+	defer runner.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, runner.RunWithContext(ctx, func(context.Context) {
+		t.Fatal("can not be here")
+	}), context.Canceled)
+
+	// the worker is blocked, the queued task is abandoned after cancel
+	block := make(chan struct{})
+	require.NoError(t, runner.RunWithContext(context.Background(), func(context.Context) { <-block }))
+	ctx, cancel = context.WithCancel(context.Background())
+	require.NoError(t, runner.RunWithContext(ctx, func(context.Context) {
+		t.Fatal("can not be here")
+	}))
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+	require.ErrorIs(t, runner.RunWithContext(ctx2, func(context.Context) {}), context.DeadlineExceeded)
+	cancel()
+	close(block)
+
+	// timeout of executing
+	done := make(chan error)
+	require.NoError(t, runner.RunWithTimeout(context.Background(), 10*time.Millisecond, func(ctx context.Context) {
+		<-ctx.Done()
+		done <- ctx.Err()
+	}))
+	require.ErrorIs(t, <-done, context.DeadlineExceeded)
+
+	// panic is recovered, the worker is still running
+	require.NoError(t, runner.RunWithContext(context.Background(), func(context.Context) {
+		panic("task panic")
+	}))
+	require.NoError(t, runner.RunWithContext(context.Background(), func(context.Context) {
+		done <- nil
+	}))
+	require.NoError(t, <-done)
+}
+
+func TestTaskpoolResize(t *testing.T) {
+	runner := taskpool.New(1, 10)
+	defer runner.Close()
+	stats := runner.Stats()
+	require.Equal(t, 1, stats.Workers)
+	require.Equal(t, 10, stats.QueueSize)
+
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for i := 0; i < 4; i++ {
+		runner.Run(func() {
+			<-block
+			wg.Done()
+		})
+	}
+	require.Eventually(t, func() bool { return runner.Running() == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, 3, runner.Stats().QueueDepth)
+
+	runner.Resize(4)
+	require.Eventually(t, func() bool { return runner.Running() == 4 }, time.Second, time.Millisecond)
+	stats = runner.Stats()
+	require.Equal(t, 4, stats.Workers)
+	require.Equal(t, 0, stats.QueueDepth)
+	close(block)
+	wg.Wait()
+	require.Eventually(t, func() bool { return runner.Stats().Executed == 4 }, time.Second, time.Millisecond)
+
+	runner.Resize(1)
+	require.Equal(t, 1, runner.Stats().Workers)
+	time.Sleep(20 * time.Millisecond)
+	block = make(chan struct{})
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		runner.Run(func() {
+			<-block
+			wg.Done()
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, uint32(1), runner.Running())
+	require.Equal(t, 1, runner.Stats().QueueDepth)
+	close(block)
+	wg.Wait()
+
+	runner.Resize(-1)
+	require.Equal(t, 0, runner.Stats().Workers)
+}
+
 func BenchmarkGoroutine(b *testing.B) {
 	var wg sync.WaitGroup
 	for i := 0; i < b.N; i++ {
@@ -0,0 +1,160 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+// DataNodeDisk disk reported by http api of datanode
+type DataNodeDisk struct {
+	Path         string            `json:"path"`
+	Total        uint64            `json:"total"`
+	Used         uint64            `json:"used"`
+	Available    uint64            `json:"available"`
+	Unallocated  uint64            `json:"unallocated"`
+	Allocated    uint64            `json:"allocated"`
+	Status       int               `json:"status"`
+	Partitions   int               `json:"partitions"`
+	Decommission bool              `json:"decommission"`
+	ReadErrCnt   uint64            `json:"readErrCnt"`
+	WriteErrCnt  uint64            `json:"writeErrCnt"`
+	Health       *proto.DiskHealth `json:"health,omitempty"`
+}
+
+// DataNodePartition data partition reported by http api of datanode
+type DataNodePartition struct {
+	ID       uint64   `json:"id"`
+	Size     int      `json:"size"`
+	Used     int      `json:"used"`
+	Status   int      `json:"status"`
+	Path     string   `json:"path"`
+	Replicas []string `json:"replicas"`
+}
+
+// NodeHttpClient client of http apis of datanode and metanode
+type NodeHttpClient struct {
+	host   string
+	client *http.Client
+}
+
+// NewNodeHttpClient returns client of node with http address host
+func NewNodeHttpClient(host string) *NodeHttpClient {
+	return &NodeHttpClient{host: host, client: &http.Client{Timeout: requestTimeout}}
+}
+
+func (c *NodeHttpClient) request(method, path string, params url.Values, result interface{}) (err error) {
+	reqURL := fmt.Sprintf("http://%v%v", c.host, path)
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, reqURL, nil)
+	if err != nil {
+		return
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	body := new(proto.HTTPReplyRaw)
+	if err = body.Unmarshal(data); err != nil {
+		return fmt.Errorf("%v %v status(%v) body(%v)", method, path, resp.StatusCode,
+			strings.TrimSpace(string(data)))
+	}
+	if resp.StatusCode != http.StatusOK || body.Code != http.StatusOK {
+		return fmt.Errorf("%v %v code(%v) msg(%v)", method, path, body.Code, body.Msg)
+	}
+	if result == nil {
+		return
+	}
+	return json.Unmarshal(body.Data, result)
+}
+
+// GetConfig returns runtime configs of node
+func (c *NodeHttpClient) GetConfig() (configs map[string]string, err error) {
+	configs = make(map[string]string)
+	err = c.request(http.MethodGet, "/getConfig", nil, &configs)
+	return
+}
+
+// SetConfig sets runtime configs of node, returns configs after setting
+func (c *NodeHttpClient) SetConfig(configs map[string]string) (result map[string]string, err error) {
+	params := make(url.Values)
+	for key, value := range configs {
+		params.Set(key, value)
+	}
+	result = make(map[string]string)
+	err = c.request(http.MethodPost, "/setConfig", params, &result)
+	return
+}
+
+// GetQuotaUsage returns used files and bytes of quotas on a meta partition of metanode
+func (c *NodeHttpClient) GetQuotaUsage(pid uint64) (infos []*proto.QuotaReportInfo, err error) {
+	params := make(url.Values)
+	params.Set("pid", strconv.FormatUint(pid, 10))
+	err = c.request(http.MethodGet, "/getQuotaUsage", params, &infos)
+	return
+}
+
+// GetInodeCount returns count of files and directories of a meta partition in the snapshot
+// of verSeq, the latest is returned if verSeq is 0
+func (c *NodeHttpClient) GetInodeCount(pid, verSeq uint64) (files, dirs uint64, err error) {
+	params := make(url.Values)
+	params.Set("pid", strconv.FormatUint(pid, 10))
+	if verSeq != 0 {
+		params.Set("verSeq", strconv.FormatUint(verSeq, 10))
+	}
+	result := make(map[string]uint64)
+	if err = c.request(http.MethodGet, "/getInodeCount", params, &result); err != nil {
+		return
+	}
+	return result["files"], result["dirs"], nil
+}
+
+// GetDisks returns disks of datanode
+func (c *NodeHttpClient) GetDisks() (disks []*DataNodeDisk, err error) {
+	result := &struct {
+		Disks []*DataNodeDisk `json:"disks"`
+	}{}
+	if err = c.request(http.MethodGet, "/disks", nil, result); err != nil {
+		return
+	}
+	return result.Disks, nil
+}
+
+// GetPartitions returns data partitions of datanode
+func (c *NodeHttpClient) GetPartitions() (partitions []*DataNodePartition, err error) {
+	result := &struct {
+		Partitions []*DataNodePartition `json:"partitions"`
+	}{}
+	if err = c.request(http.MethodGet, "/partitions", nil, result); err != nil {
+		return
+	}
+	return result.Partitions, nil
+}
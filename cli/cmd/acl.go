@@ -41,9 +41,10 @@ func newAclCmd(client *master.MasterClient) *cobra.Command {
 func newAclAddCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     CliAclAdd,
-		Short:   cmdAclAddShort,
-		Aliases: []string{"add"},
+		Use:               CliAclAdd,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdAclAddShort,
+		Aliases:           []string{"add"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) <= 1 {
 				stdout("example:cfs-cli acl aclAdd volName 192.168.0.1\n")
@@ -67,9 +68,10 @@ func newAclAddCmd(client *master.MasterClient) *cobra.Command {
 func newAclListCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     cliAclListShort,
-		Short:   cmdAclListShort,
-		Aliases: []string{"list"},
+		Use:               cliAclListShort,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdAclListShort,
+		Aliases:           []string{"list"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
 				stdout("need volume name\n")
@@ -85,10 +87,12 @@ func newAclListCmd(client *master.MasterClient) *cobra.Command {
 				stdout("AclOperation return \n")
 				return
 			}
-			stdout("%v\n", volumeAclTableHeader)
-			for _, info := range aclInfo.List {
-				stdout("%v\n", formatAclInfoTableRow(info))
-			}
+			err = render(aclInfo.List, func() {
+				stdout("%v\n", volumeAclTableHeader)
+				for _, info := range aclInfo.List {
+					stdout("%v\n", formatAclInfoTableRow(info))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optKeyword, "keyword", "", "Specify keyword of volume name to filter")
@@ -98,9 +102,10 @@ func newAclListCmd(client *master.MasterClient) *cobra.Command {
 func newAclDelCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     CliAclDel,
-		Short:   cmdAclDelShort,
-		Aliases: []string{"del"},
+		Use:               CliAclDel,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdAclDelShort,
+		Aliases:           []string{"del"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) <= 1 {
 				stdout("USAGE:./cfs-cli acl aclDel volName ipAddr\n")
@@ -125,9 +130,10 @@ func newAclDelCmd(client *master.MasterClient) *cobra.Command {
 func newAclCheckCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     CliAclCheck,
-		Short:   cmdAclCheckShort,
-		Aliases: []string{"check"},
+		Use:               CliAclCheck,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdAclCheckShort,
+		Aliases:           []string{"check"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) <= 1 {
 				stdout("USAGE:./cfs-cli acl aclCheck volName ipAddr\n")
@@ -0,0 +1,372 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/data/stream"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/sdk/meta"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdBenchUse       = "bench [COMMAND]"
+	cmdBenchShort     = "Run micro-benchmarks against a volume"
+	cmdBenchMetaShort = "Benchmark metadata operations: create, stat and delete"
+	cmdBenchDataShort = "Benchmark data operations: sequential and random write and read"
+
+	benchDirPrefix = "cfs-cli-bench-"
+)
+
+func newBenchCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdBenchUse,
+		Short: cmdBenchShort,
+		Args:  cobra.MinimumNArgs(0),
+	}
+	proto.InitBufferPool(32768)
+	cmd.AddCommand(
+		newBenchMetaCmd(client),
+		newBenchDataCmd(client),
+	)
+	return cmd
+}
+
+// benchStat collects latency of one kind of operation.
+type benchStat struct {
+	op        string
+	mu        sync.Mutex
+	latencies []time.Duration
+	bytes     int64
+	errors    int
+	elapsed   time.Duration
+}
+
+func (s *benchStat) record(start time.Time, n int, err error) {
+	cost := time.Since(start)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.errors++
+		return
+	}
+	s.latencies = append(s.latencies, cost)
+	s.bytes += int64(n)
+}
+
+// percentile returns the p-th (0 < p <= 100) percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p/100+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func (s *benchStat) row() []interface{} {
+	sorted := make([]time.Duration, len(s.latencies))
+	copy(sorted, s.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var opsPerSec, mbPerSec float64
+	if secs := s.elapsed.Seconds(); secs > 0 {
+		opsPerSec = float64(len(sorted)) / secs
+		mbPerSec = float64(s.bytes) / secs / 1024 / 1024
+	}
+	var max time.Duration
+	if len(sorted) > 0 {
+		max = sorted[len(sorted)-1]
+	}
+	return arow(s.op, len(sorted), s.errors, fmt.Sprintf("%.1f", opsPerSec), fmt.Sprintf("%.2f", mbPerSec),
+		percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99), max)
+}
+
+var benchTableHeader = arow("OP", "OPS", "ERRORS", "OPS/S", "MB/S", "P50", "P90", "P99", "MAX")
+
+func formatBenchStats(stats ...*benchStat) string {
+	rows := table{benchTableHeader}
+	for _, s := range stats {
+		rows = rows.append(s.row())
+	}
+	return alignTable(rows...)
+}
+
+// runBench runs fn(worker, i) for i in [0, count) with concurrency workers.
+func runBench(stat *benchStat, concurrency, count int, fn func(worker, i int)) {
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := worker; i < count; i += concurrency {
+				fn(worker, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	stat.elapsed = time.Since(start)
+}
+
+func newBenchMetaWrapper(client *master.MasterClient, volName string) (*meta.MetaWrapper, error) {
+	return meta.NewMetaWrapper(&meta.MetaConfig{
+		Volume:  volName,
+		Masters: client.Nodes(),
+	})
+}
+
+func benchFileMode() uint32 {
+	return proto.Mode(os.FileMode(0o644))
+}
+
+func createBenchDir(mw *meta.MetaWrapper) (ino uint64, name string, err error) {
+	name = fmt.Sprintf("%s%d", benchDirPrefix, time.Now().UnixNano())
+	info, err := mw.Create_ll(proto.RootIno, name, proto.Mode(os.ModeDir|0o755), 0, 0, nil, "/"+name)
+	if err != nil {
+		return 0, "", fmt.Errorf("create bench dir %v failed: %v", name, err)
+	}
+	return info.Inode, name, nil
+}
+
+func removeBenchDir(mw *meta.MetaWrapper, name string) {
+	if info, err := mw.Delete_ll(proto.RootIno, name, true, "/"+name); err == nil && info != nil {
+		mw.Evict(info.Inode, "/"+name)
+	}
+}
+
+func newBenchMetaCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optCount       int
+		optConcurrency int
+	)
+	cmd := &cobra.Command{
+		Use:               "meta [VOLUME]",
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdBenchMetaShort,
+		Args:              cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			if optCount <= 0 || optConcurrency <= 0 {
+				err = fmt.Errorf("count and concurrency should be greater than 0")
+				return
+			}
+			mw, err := newBenchMetaWrapper(client, args[0])
+			if err != nil {
+				return
+			}
+			defer mw.Close()
+
+			dirIno, dirName, err := createBenchDir(mw)
+			if err != nil {
+				return
+			}
+			defer removeBenchDir(mw, dirName)
+
+			inodes := make([]uint64, optCount)
+			fileName := func(i int) string { return fmt.Sprintf("f%d", i) }
+			filePath := func(i int) string { return fmt.Sprintf("/%s/f%d", dirName, i) }
+
+			create := &benchStat{op: "create"}
+			runBench(create, optConcurrency, optCount, func(_, i int) {
+				start := time.Now()
+				info, e := mw.Create_ll(dirIno, fileName(i), benchFileMode(), 0, 0, nil, filePath(i))
+				if e == nil {
+					inodes[i] = info.Inode
+				}
+				create.record(start, 0, e)
+			})
+
+			stat := &benchStat{op: "stat"}
+			runBench(stat, optConcurrency, optCount, func(_, i int) {
+				if inodes[i] == 0 {
+					return
+				}
+				start := time.Now()
+				_, e := mw.InodeGet_ll(inodes[i])
+				stat.record(start, 0, e)
+			})
+
+			del := &benchStat{op: "delete"}
+			runBench(del, optConcurrency, optCount, func(_, i int) {
+				if inodes[i] == 0 {
+					return
+				}
+				start := time.Now()
+				_, e := mw.Delete_ll(dirIno, fileName(i), false, filePath(i))
+				if e == nil {
+					e = mw.Evict(inodes[i], filePath(i))
+				}
+				del.record(start, 0, e)
+			})
+
+			stdout("Volume %v, files %v, concurrency %v\n", args[0], optCount, optConcurrency)
+			stdout("%v", formatBenchStats(create, stat, del))
+		},
+	}
+	cmd.Flags().IntVar(&optCount, "count", 1000, "Number of files to create, stat and delete")
+	cmd.Flags().IntVar(&optConcurrency, "concurrency", 8, "Number of concurrent workers")
+	return cmd
+}
+
+func newBenchDataCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optFileSizeMB  int
+		optBlockSizeKB int
+		optConcurrency int
+	)
+	cmd := &cobra.Command{
+		Use:               "data [VOLUME]",
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdBenchDataShort,
+		Args:              cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			if optFileSizeMB <= 0 || optBlockSizeKB <= 0 || optConcurrency <= 0 {
+				err = fmt.Errorf("size, block and concurrency should be greater than 0")
+				return
+			}
+			fileSize := optFileSizeMB * 1024 * 1024
+			blockSize := optBlockSizeKB * 1024
+			if blockSize > fileSize {
+				err = fmt.Errorf("block size %vKB is greater than file size %vMB", optBlockSizeKB, optFileSizeMB)
+				return
+			}
+			blocks := fileSize / blockSize
+
+			view, err := client.AdminAPI().GetVolumeSimpleInfo(args[0])
+			if err != nil {
+				return
+			}
+			if view.VolType != proto.VolumeTypeHot {
+				err = fmt.Errorf("data bench only supports hot volume")
+				return
+			}
+
+			mw, err := newBenchMetaWrapper(client, args[0])
+			if err != nil {
+				return
+			}
+			defer mw.Close()
+			ec, err := stream.NewExtentClient(&stream.ExtentConfig{
+				Volume:            args[0],
+				VolumeType:        view.VolType,
+				Masters:           client.Nodes(),
+				OnAppendExtentKey: mw.AppendExtentKey,
+				OnSplitExtentKey:  mw.SplitExtentKey,
+				OnGetExtents:      mw.GetExtents,
+				OnTruncate:        mw.Truncate,
+			})
+			if err != nil {
+				return
+			}
+			defer ec.Close()
+
+			dirIno, dirName, err := createBenchDir(mw)
+			if err != nil {
+				return
+			}
+			defer removeBenchDir(mw, dirName)
+
+			inodes := make([]uint64, optConcurrency)
+			for i := range inodes {
+				name := fmt.Sprintf("f%d", i)
+				info, e := mw.Create_ll(dirIno, name, benchFileMode(), 0, 0, nil, fmt.Sprintf("/%s/%s", dirName, name))
+				if e != nil {
+					err = fmt.Errorf("create bench file %v failed: %v", name, e)
+					return
+				}
+				inodes[i] = info.Inode
+				if err = ec.OpenStream(info.Inode); err != nil {
+					return
+				}
+			}
+			defer func() {
+				for i, ino := range inodes {
+					name := fmt.Sprintf("f%d", i)
+					ec.CloseStream(ino)
+					ec.EvictStream(ino)
+					mw.Delete_ll(dirIno, name, false, fmt.Sprintf("/%s/%s", dirName, name))
+					mw.Evict(ino, fmt.Sprintf("/%s/%s", dirName, name))
+				}
+			}()
+
+			buf := make([]byte, blockSize)
+			rand.Read(buf)
+			readBufs := make([][]byte, optConcurrency)
+			for i := range readBufs {
+				readBufs[i] = make([]byte, blockSize)
+			}
+			total := blocks * optConcurrency
+			// each worker owns a file, block i of the whole run is block i/concurrency of the file.
+			seqOffset := func(i int) int { return (i / optConcurrency) * blockSize }
+			randOffset := func(int) int { return rand.Intn(blocks) * blockSize }
+
+			write := func(stat *benchStat, offset func(int) int) {
+				runBench(stat, optConcurrency, total, func(worker, i int) {
+					start := time.Now()
+					n, e := ec.Write(inodes[worker], offset(i), buf, 0, nil)
+					stat.record(start, n, e)
+				})
+				for _, ino := range inodes {
+					ec.Flush(ino)
+				}
+			}
+			read := func(stat *benchStat, offset func(int) int) {
+				runBench(stat, optConcurrency, total, func(worker, i int) {
+					start := time.Now()
+					n, e := ec.Read(inodes[worker], readBufs[worker], offset(i), blockSize)
+					stat.record(start, n, e)
+				})
+			}
+
+			seqWrite := &benchStat{op: "seq-write"}
+			write(seqWrite, seqOffset)
+			seqRead := &benchStat{op: "seq-read"}
+			read(seqRead, seqOffset)
+			randWrite := &benchStat{op: "rand-write"}
+			write(randWrite, randOffset)
+			randRead := &benchStat{op: "rand-read"}
+			read(randRead, randOffset)
+
+			stdout("Volume %v, files %v, file size %vMB, block size %vKB\n",
+				args[0], optConcurrency, optFileSizeMB, optBlockSizeKB)
+			stdout("%v", formatBenchStats(seqWrite, seqRead, randWrite, randRead))
+		},
+	}
+	cmd.Flags().IntVar(&optFileSizeMB, "size", 64, "Size of each file in MB")
+	cmd.Flags().IntVar(&optBlockSizeKB, "block", 128, "Size of each read and write in KB")
+	cmd.Flags().IntVar(&optConcurrency, "concurrency", 4, "Number of concurrent workers, each worker owns a file")
+	return cmd
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	require.Equal(t, time.Duration(0), percentile(nil, 50))
+
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	require.Equal(t, time.Millisecond, percentile(sorted, 0.1))
+	require.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	require.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	require.Equal(t, 100*time.Millisecond, percentile(sorted, 100))
+}
+
+func TestBenchStat(t *testing.T) {
+	stat := &benchStat{op: "write"}
+	runBench(stat, 3, 10, func(_, i int) {
+		var err error
+		if i == 0 {
+			err = errors.New("mock error")
+		}
+		stat.record(time.Now(), 1024, err)
+	})
+	require.Equal(t, 9, len(stat.latencies))
+	require.Equal(t, 1, stat.errors)
+	require.Equal(t, int64(9*1024), stat.bytes)
+	require.Contains(t, formatBenchStats(stat), "write")
+}
@@ -0,0 +1,472 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cubefs/cubefs/blobstore/api/blobnode"
+	cmapi "github.com/cubefs/cubefs/blobstore/api/clustermgr"
+	"github.com/cubefs/cubefs/blobstore/api/scheduler"
+	ebsproto "github.com/cubefs/cubefs/blobstore/common/proto"
+	"github.com/cubefs/cubefs/blobstore/common/rpc"
+	"github.com/cubefs/cubefs/blobstore/common/rpc/auth"
+)
+
+const (
+	cmdBlobstoreUse   = "blobstore [COMMAND]"
+	cmdBlobstoreShort = "Manage blobstore cluster"
+
+	cliFlagBlobstoreClusterID = "cluster-id"
+	cliFlagBlobstoreCmAddr    = "cm-addr"
+	cliFlagBlobstoreCmSecret  = "cm-secret"
+)
+
+// blobstoreClient clients of blobstore, addresses of clustermgr are read from
+// flags, or blobstore section of config file if flags are not set.
+type blobstoreClient struct {
+	clusterID ebsproto.ClusterID
+	cmAddr    string
+	cmSecret  string
+}
+
+func (c *blobstoreClient) clusterMgr() (*cmapi.Client, error) {
+	var hosts []string
+	if c.cmAddr != "" {
+		hosts = strings.Split(c.cmAddr, ",")
+	}
+	secret := c.cmSecret
+	if len(hosts) == 0 || c.clusterID == 0 {
+		config, err := LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+		if len(hosts) == 0 {
+			hosts = config.Blobstore.ClusterMgrAddr
+		}
+		if c.clusterID == 0 {
+			c.clusterID = ebsproto.ClusterID(config.Blobstore.ClusterID)
+		}
+		if secret == "" {
+			secret = config.Blobstore.ClusterMgrSecret
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("clustermgr address of blobstore is not set, use --%s or config file", cliFlagBlobstoreCmAddr)
+	}
+	for idx := range hosts {
+		if !strings.HasPrefix(hosts[idx], "http") {
+			hosts[idx] = "http://" + hosts[idx]
+		}
+	}
+	return cmapi.New(&cmapi.Config{
+		LbConfig: rpc.LbConfig{
+			Hosts: hosts,
+			Config: rpc.Config{
+				Tc: rpc.TransportConfig{
+					Auth: auth.Config{EnableAuth: secret != "", Secret: secret},
+				},
+			},
+		},
+	}), nil
+}
+
+func (c *blobstoreClient) scheduler() (scheduler.IScheduler, error) {
+	cmClient, err := c.clusterMgr()
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.New(&scheduler.Config{}, cmClient, c.clusterID), nil
+}
+
+func blobstoreContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Duration(defaultConfigTimeout)*time.Second)
+}
+
+func newBlobstoreCmd() *cobra.Command {
+	client := &blobstoreClient{}
+	var clusterID uint32
+	cmd := &cobra.Command{
+		Use:   cmdBlobstoreUse,
+		Short: cmdBlobstoreShort,
+		Args:  cobra.MinimumNArgs(0),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			client.clusterID = ebsproto.ClusterID(clusterID)
+		},
+	}
+	cmd.PersistentFlags().Uint32Var(&clusterID, cliFlagBlobstoreClusterID, 0, "Specify blobstore cluster id")
+	cmd.PersistentFlags().StringVar(&client.cmAddr, cliFlagBlobstoreCmAddr, "",
+		"Specify clustermgr address {HOST}:{PORT}[,{HOST}:{PORT}]")
+	cmd.PersistentFlags().StringVar(&client.cmSecret, cliFlagBlobstoreCmSecret, "", "Specify clustermgr auth secret")
+	cmd.AddCommand(
+		newBlobstoreDiskCmd(client),
+		newBlobstoreVolumeCmd(client),
+		newBlobstoreTaskCmd(client),
+		newBlobstoreMigrateCmd(client),
+		newBlobstoreRepairCmd(client),
+	)
+	return cmd
+}
+
+const (
+	cmdBlobstoreDiskShort     = "Manage blobstore disks"
+	cmdBlobstoreDiskListShort = "List blobstore disks"
+)
+
+func newBlobstoreDiskCmd(client *blobstoreClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   CliResourceDisk,
+		Short: cmdBlobstoreDiskShort,
+	}
+	cmd.AddCommand(newBlobstoreDiskListCmd(client))
+	return cmd
+}
+
+func newBlobstoreDiskListCmd(client *blobstoreClient) *cobra.Command {
+	var (
+		optHost   string
+		optIdc    string
+		optRack   string
+		optStatus string
+	)
+	cmd := &cobra.Command{
+		Use:   CliOpList,
+		Short: cmdBlobstoreDiskListShort,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			opt := &cmapi.ListOptionArgs{Host: optHost, Idc: optIdc, Rack: optRack, Count: 200}
+			if optStatus != "" {
+				if opt.Status, err = parseBlobstoreDiskStatus(optStatus); err != nil {
+					return
+				}
+			}
+			if optHost != "" && !strings.HasPrefix(optHost, "http") {
+				opt.Host = "http://" + optHost
+			}
+			var cmClient *cmapi.Client
+			if cmClient, err = client.clusterMgr(); err != nil {
+				return
+			}
+			ctx, cancel := blobstoreContext()
+			defer cancel()
+
+			var disks []*blobnode.DiskInfo
+			for {
+				var ret cmapi.ListDiskRet
+				if ret, err = cmClient.ListDisk(ctx, opt); err != nil {
+					return
+				}
+				disks = append(disks, ret.Disks...)
+				if len(ret.Disks) == 0 || ret.Marker == ebsproto.InvalidDiskID {
+					break
+				}
+				opt.Marker = ret.Marker
+			}
+			err = render(disks, func() {
+				stdout("%v\n", formatBlobstoreDiskList(disks))
+			})
+		},
+	}
+	cmd.Flags().StringVar(&optHost, "host", "", "Filter disks by host")
+	cmd.Flags().StringVar(&optIdc, "idc", "", "Filter disks by idc")
+	cmd.Flags().StringVar(&optRack, "rack", "", "Filter disks by rack")
+	cmd.Flags().StringVar(&optStatus, "status", "", "Filter disks by status [normal, broken, repairing, repaired, dropped]")
+	return cmd
+}
+
+func parseBlobstoreDiskStatus(status string) (ebsproto.DiskStatus, error) {
+	for s := ebsproto.DiskStatusNormal; s < ebsproto.DiskStatusMax; s++ {
+		if s.String() == status {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid disk status %s", status)
+}
+
+const (
+	cmdBlobstoreVolumeShort     = "Manage blobstore volumes"
+	cmdBlobstoreVolumeInfoShort = "Show blobstore volume information"
+)
+
+func newBlobstoreVolumeCmd(client *blobstoreClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volume",
+		Short: cmdBlobstoreVolumeShort,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   CliOpInfo + " [VID]",
+		Short: cmdBlobstoreVolumeInfoShort,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			var vid uint64
+			if vid, err = strconv.ParseUint(args[0], 10, 32); err != nil {
+				return
+			}
+			var cmClient *cmapi.Client
+			if cmClient, err = client.clusterMgr(); err != nil {
+				return
+			}
+			ctx, cancel := blobstoreContext()
+			defer cancel()
+			var volume *cmapi.VolumeInfo
+			if volume, err = cmClient.GetVolumeInfo(ctx, &cmapi.GetVolumeArgs{Vid: ebsproto.Vid(vid)}); err != nil {
+				return
+			}
+			err = render(volume, func() {
+				stdout("%v", formatBlobstoreVolume(volume))
+			})
+		},
+	})
+	return cmd
+}
+
+const (
+	cmdBlobstoreTaskShort     = "Manage blobstore migrate tasks"
+	cmdBlobstoreTaskInfoShort = "Show migrate task information"
+)
+
+func newBlobstoreTaskCmd(client *blobstoreClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: cmdBlobstoreTaskShort,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   CliOpInfo + " [TASK_TYPE] [TASK_ID]",
+		Short: cmdBlobstoreTaskInfoShort,
+		Long:  "Show migrate task information, TASK_TYPE is one of [disk_repair, disk_drop, balance, manual_migrate, cold_migrate]",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			taskType := ebsproto.TaskType(args[0])
+			if !taskType.Valid() {
+				err = fmt.Errorf("invalid task type %s", args[0])
+				return
+			}
+			var cli scheduler.IScheduler
+			if cli, err = client.scheduler(); err != nil {
+				return
+			}
+			ctx, cancel := blobstoreContext()
+			defer cancel()
+			var detail scheduler.MigrateTaskDetail
+			if detail, err = cli.DetailMigrateTask(ctx, &scheduler.MigrateTaskDetailArgs{Type: taskType, ID: args[1]}); err != nil {
+				return
+			}
+			err = render(detail, func() {
+				stdout("%v", formatBlobstoreTask(&detail))
+			})
+		},
+	})
+	return cmd
+}
+
+const (
+	cmdBlobstoreMigrateShort = "Add manual migrate task of volume unit"
+)
+
+func newBlobstoreMigrateCmd(client *blobstoreClient) *cobra.Command {
+	var (
+		optDirectDownload bool
+		optYes            bool
+	)
+	cmd := &cobra.Command{
+		Use:   CliOpMigrate + " [VUID]",
+		Short: cmdBlobstoreMigrateShort,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			var id uint64
+			if id, err = strconv.ParseUint(args[0], 10, 64); err != nil {
+				return
+			}
+			vuid := ebsproto.Vuid(id)
+			if !vuid.IsValid() {
+				err = fmt.Errorf("invalid vuid %s", args[0])
+				return
+			}
+			if !optYes {
+				stdout("Add manual migrate task of vid[%d] vuid[%d]? ", vuid.Vid(), vuid)
+				var userConfirm string
+				_, _ = fmt.Scanln(&userConfirm)
+				if userConfirm != "yes" {
+					stdout("Abort by user.\n")
+					return
+				}
+			}
+			var cli scheduler.IScheduler
+			if cli, err = client.scheduler(); err != nil {
+				return
+			}
+			ctx, cancel := blobstoreContext()
+			defer cancel()
+			if err = cli.AddManualMigrateTask(ctx, &scheduler.AddManualMigrateArgs{
+				Vuid:           vuid,
+				DirectDownload: optDirectDownload,
+			}); err != nil {
+				return
+			}
+			stdout("Add manual migrate task of vuid[%d] successfully\n", vuid)
+		},
+	}
+	cmd.Flags().BoolVar(&optDirectDownload, "direct-download", false, "Download shards directly instead of recovering")
+	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
+	return cmd
+}
+
+const (
+	cmdBlobstoreRepairShort     = "Manage blobstore repair"
+	cmdBlobstoreRepairStatShort = "Show repair progress of cluster or disk"
+)
+
+func newBlobstoreRepairCmd(client *blobstoreClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: cmdBlobstoreRepairShort,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   CliOpStatus + " [DISK_ID]",
+		Short: cmdBlobstoreRepairStatShort,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			var cli scheduler.IScheduler
+			if cli, err = client.scheduler(); err != nil {
+				return
+			}
+			ctx, cancel := blobstoreContext()
+			defer cancel()
+
+			if len(args) > 0 {
+				var diskID uint64
+				if diskID, err = strconv.ParseUint(args[0], 10, 32); err != nil {
+					return
+				}
+				var stats *scheduler.DiskMigratingStats
+				if stats, err = cli.DiskMigratingStats(ctx, &scheduler.DiskMigratingStatsArgs{
+					TaskType: ebsproto.TaskTypeDiskRepair,
+					DiskID:   ebsproto.DiskID(diskID),
+				}); err != nil {
+					return
+				}
+				err = render(stats, func() {
+					stdout("  Disk          : %v\n", diskID)
+					stdout("  Repaired tasks: %v/%v\n", stats.MigratedTasksCnt, stats.TotalTasksCnt)
+				})
+				return
+			}
+
+			var stats scheduler.TasksStat
+			if stats, err = cli.LeaderStats(ctx); err != nil {
+				return
+			}
+			err = render(stats, func() {
+				stdout("%v", formatBlobstoreRepairStat(&stats))
+			})
+		},
+	})
+	return cmd
+}
+
+func formatBlobstoreDiskList(disks []*blobnode.DiskInfo) string {
+	if len(disks) == 0 {
+		return ""
+	}
+	diskRows := table{
+		arow("DiskID", "Host", "Path", "Idc", "Rack", "Status", "Readonly", "Used", "Size", "FreeChunk"),
+	}
+	for _, d := range disks {
+		diskRows = diskRows.append(arow(d.DiskID, d.Host, d.Path, d.Idc, d.Rack, d.Status, d.Readonly,
+			formatSize(uint64(d.Used)), formatSize(uint64(d.Size)), d.FreeChunkCnt))
+	}
+	return alignTable(diskRows...)
+}
+
+func formatBlobstoreVolume(volume *cmapi.VolumeInfo) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("  Vid         : %v\n", volume.Vid))
+	sb.WriteString(fmt.Sprintf("  CodeMode    : %v\n", volume.CodeMode))
+	sb.WriteString(fmt.Sprintf("  Status      : %v\n", volume.Status))
+	sb.WriteString(fmt.Sprintf("  HealthScore : %v\n", volume.HealthScore))
+	sb.WriteString(fmt.Sprintf("  Total       : %v\n", formatSize(volume.Total)))
+	sb.WriteString(fmt.Sprintf("  Used        : %v\n", formatSize(volume.Used)))
+	sb.WriteString(fmt.Sprintf("  Free        : %v\n", formatSize(volume.Free)))
+	sb.WriteString("  Units       :\n")
+	unitRows := table{arow("Index", "Vuid", "DiskID", "Host")}
+	for idx, unit := range volume.Units {
+		unitRows = unitRows.append(arow(idx, unit.Vuid, unit.DiskID, unit.Host))
+	}
+	sb.WriteString(alignTable(unitRows...))
+	return sb.String()
+}
+
+func formatBlobstoreTask(detail *scheduler.MigrateTaskDetail) string {
+	task := detail.Task
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("  TaskID      : %v\n", task.TaskID))
+	sb.WriteString(fmt.Sprintf("  TaskType    : %v\n", task.TaskType))
+	sb.WriteString(fmt.Sprintf("  State       : %v\n", task.State))
+	sb.WriteString(fmt.Sprintf("  Vid         : %v\n", task.Vid()))
+	sb.WriteString(fmt.Sprintf("  Source      : disk[%v] vuid[%v]\n", task.SourceDiskID, task.SourceVuid))
+	sb.WriteString(fmt.Sprintf("  Destination : disk[%v] vuid[%v] host[%v]\n", task.Destination.DiskID, task.Destination.Vuid, task.Destination.Host))
+	sb.WriteString(fmt.Sprintf("  CreateTime  : %v\n", task.Ctime))
+	sb.WriteString(fmt.Sprintf("  UpdateTime  : %v\n", task.MTime))
+	sb.WriteString(fmt.Sprintf("  Progress    : %v%%\n", detail.Stat.Progress))
+	return sb.String()
+}
+
+func formatBlobstoreRepairStat(stats *scheduler.TasksStat) string {
+	sb := strings.Builder{}
+	if repair := stats.DiskRepair; repair != nil {
+		sb.WriteString("Disk repair:\n")
+		sb.WriteString(fmt.Sprintf("  Enable         : %v\n", repair.Enable))
+		sb.WriteString(fmt.Sprintf("  RepairingDisks : %v\n", repair.RepairingDisks))
+		sb.WriteString(fmt.Sprintf("  RepairedTasks  : %v/%v\n", repair.RepairedTasksCnt, repair.TotalTasksCnt))
+		sb.WriteString(fmt.Sprintf("  Preparing      : %v\n", repair.PreparingCnt))
+		sb.WriteString(fmt.Sprintf("  WorkerDoing    : %v\n", repair.WorkerDoingCnt))
+		sb.WriteString(fmt.Sprintf("  Finishing      : %v\n", repair.FinishingCnt))
+		sb.WriteString(fmt.Sprintf("  FinishedPerMin : %v\n", repair.StatsPerMin.FinishedCnt))
+		sb.WriteString(fmt.Sprintf("  DataPerMin     : %v\n", repair.StatsPerMin.DataAmountByte))
+	}
+	if repair := stats.ShardRepair; repair != nil {
+		sb.WriteString("Shard repair:\n")
+		sb.WriteString(fmt.Sprintf("  Enable         : %v\n", repair.Enable))
+		sb.WriteString(fmt.Sprintf("  SuccessPerMin  : %v\n", repair.SuccessPerMin))
+		sb.WriteString(fmt.Sprintf("  FailedPerMin   : %v\n", repair.FailedPerMin))
+		sb.WriteString(fmt.Sprintf("  TotalErrCnt    : %v\n", repair.TotalErrCnt))
+	}
+	return sb.String()
+}
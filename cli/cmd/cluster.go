@@ -36,11 +36,13 @@ func newClusterCmd(client *master.MasterClient) *cobra.Command {
 	clusterCmd.AddCommand(
 		newClusterInfoCmd(client),
 		newClusterStatCmd(client),
+		newClusterStatHistoryCmd(client),
 		newClusterFreezeCmd(client),
 		newClusterSetThresholdCmd(client),
 		newClusterSetParasCmd(client),
 		newClusterDisableMpDecommissionCmd(client),
 		newClusterSetVolDeletionDelayTimeCmd(client),
+		newClusterHealthCmd(client),
 	)
 	return clusterCmd
 }
@@ -79,18 +81,26 @@ func newClusterInfoCmd(client *master.MasterClient) *cobra.Command {
 			if cp, err = client.AdminAPI().GetClusterIP(); err != nil {
 				errout(err)
 			}
-			stdout("[Cluster]\n")
-			stdout("%v", formatClusterView(cv, cn, cp))
 			if clusterPara, err = client.AdminAPI().GetClusterParas(); err != nil {
 				errout(err)
 			}
-
-			stdout(fmt.Sprintf("  BatchCount         : %v\n", clusterPara[nodeDeleteBatchCountKey]))
-			stdout(fmt.Sprintf("  MarkDeleteRate     : %v\n", clusterPara[nodeMarkDeleteRateKey]))
-			stdout(fmt.Sprintf("  DeleteWorkerSleepMs: %v\n", clusterPara[nodeDeleteWorkerSleepMs]))
-			stdout(fmt.Sprintf("  AutoRepairRate     : %v\n", clusterPara[nodeAutoRepairRateKey]))
-			stdout(fmt.Sprintf("  MaxDpCntLimit      : %v\n", clusterPara[nodeMaxDpCntLimit]))
-			stdout("\n")
+			data := struct {
+				*proto.ClusterView
+				*proto.ClusterNodeInfo
+				*proto.ClusterIP
+				Parameters map[string]string
+			}{cv, cn, cp, clusterPara}
+			err = render(data, func() {
+				stdout("[Cluster]\n")
+				stdout("%v", formatClusterView(cv, cn, cp))
+				stdout(fmt.Sprintf("  BatchCount         : %v\n", clusterPara[nodeDeleteBatchCountKey]))
+				stdout(fmt.Sprintf("  MarkDeleteRate     : %v\n", clusterPara[nodeMarkDeleteRateKey]))
+				stdout(fmt.Sprintf("  DeleteWorkerSleepMs: %v\n", clusterPara[nodeDeleteWorkerSleepMs]))
+				stdout(fmt.Sprintf("  AutoRepairRate     : %v\n", clusterPara[nodeAutoRepairRateKey]))
+				stdout(fmt.Sprintf("  MaxDpCntLimit      : %v\n", clusterPara[nodeMaxDpCntLimit]))
+				stdout("\n")
+			})
+			errout(err)
 		},
 	}
 	return cmd
@@ -114,9 +124,11 @@ func newClusterStatCmd(client *master.MasterClient) *cobra.Command {
 				err = fmt.Errorf("Get cluster info fail:\n%v\n", err)
 				return
 			}
-			stdout("[Cluster Status]\n")
-			stdout("%v", formatClusterStat(cs))
-			stdout("\n")
+			err = render(cs, func() {
+				stdout("[Cluster Status]\n")
+				stdout("%v", formatClusterStat(cs))
+				stdout("\n")
+			})
 		},
 	}
 	return cmd
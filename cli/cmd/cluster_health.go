@@ -0,0 +1,308 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdClusterHealthShort = "Show cluster health report"
+
+	healthStatusHealthy  = "healthy"
+	healthStatusWarning  = "warning"
+	healthStatusCritical = "critical"
+
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+type nodeHealth struct {
+	Total    int      `json:"total"`
+	Inactive []string `json:"inactive"`
+}
+
+type partitionHealth struct {
+	Corrupt       []uint64 `json:"corrupt"`
+	LackReplica   []uint64 `json:"lack_replica"`
+	BadReplica    []uint64 `json:"bad_replica"`
+	ExcessReplica []uint64 `json:"excess_replica"`
+	Bad           []uint64 `json:"bad"`
+}
+
+type diskHealth struct {
+	Address       string `json:"address"`
+	Path          string `json:"path"`
+	Status        string `json:"status"`
+	ErrPartitions int    `json:"err_partitions"`
+}
+
+type clusterHealth struct {
+	Status         string          `json:"status"`
+	Cluster        string          `json:"cluster"`
+	Leader         string          `json:"leader"`
+	MasterNodes    nodeHealth      `json:"master_nodes"`
+	MetaNodes      nodeHealth      `json:"meta_nodes"`
+	DataNodes      nodeHealth      `json:"data_nodes"`
+	MetaPartitions partitionHealth `json:"meta_partitions"`
+	DataPartitions partitionHealth `json:"data_partitions"`
+	BadDisks       []diskHealth    `json:"bad_disks"`
+	Problems       []string        `json:"problems"`
+}
+
+func newClusterHealthCmd(client *master.MasterClient) *cobra.Command {
+	var optJSON bool
+	cmd := &cobra.Command{
+		Use:   CliOpHealth,
+		Short: cmdClusterHealthShort,
+		Long: `Aggregate master topology, inactive nodes, bad partitions and replicas,
+and disk errors into a single report.
+The status is 'critical' if there is no master leader or any partition is corrupt,
+'warning' if there is any other problem, otherwise 'healthy'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err    error
+				health *clusterHealth
+			)
+			defer func() {
+				errout(err)
+			}()
+			if health, err = getClusterHealth(client); err != nil {
+				return
+			}
+			if optJSON {
+				optOutput = outputJSON
+			}
+			err = render(health, func() {
+				stdout("%v", formatClusterHealth(health))
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&optJSON, CliFlagJSON, false, "Output in json format, same as --output json")
+	return cmd
+}
+
+func getClusterHealth(client *master.MasterClient) (health *clusterHealth, err error) {
+	var (
+		cv        *proto.ClusterView
+		dpDiag    *proto.DataPartitionDiagnosis
+		mpDiag    *proto.MetaPartitionDiagnosis
+		diskInfos *proto.DiskInfos
+	)
+	if cv, err = client.AdminAPI().GetCluster(); err != nil {
+		return
+	}
+	if dpDiag, err = client.AdminAPI().DiagnoseDataPartition(true); err != nil {
+		return
+	}
+	if mpDiag, err = client.AdminAPI().DiagnoseMetaPartition(); err != nil {
+		return
+	}
+	if diskInfos, err = client.AdminAPI().QueryBadDisks(); err != nil {
+		return
+	}
+	return buildClusterHealth(cv, dpDiag, mpDiag, diskInfos), nil
+}
+
+func buildClusterHealth(cv *proto.ClusterView, dpDiag *proto.DataPartitionDiagnosis,
+	mpDiag *proto.MetaPartitionDiagnosis, diskInfos *proto.DiskInfos,
+) *clusterHealth {
+	health := &clusterHealth{
+		Cluster: cv.Name,
+		Leader:  cv.LeaderAddr,
+		MasterNodes: nodeHealth{
+			Total:    len(cv.MasterNodes),
+			Inactive: inactiveNodes(cv.MasterNodes),
+		},
+		MetaNodes: nodeHealth{Total: len(cv.MetaNodes), Inactive: mpDiag.InactiveMetaNodes},
+		DataNodes: nodeHealth{Total: len(cv.DataNodes), Inactive: dpDiag.InactiveDataNodes},
+		MetaPartitions: partitionHealth{
+			Corrupt:       mpDiag.CorruptMetaPartitionIDs,
+			LackReplica:   mpDiag.LackReplicaMetaPartitionIDs,
+			BadReplica:    mpDiag.BadReplicaMetaPartitionIDs,
+			ExcessReplica: mpDiag.ExcessReplicaMetaPartitionIDs,
+		},
+		DataPartitions: partitionHealth{
+			Corrupt:       dpDiag.CorruptDataPartitionIDs,
+			LackReplica:   dpDiag.LackReplicaDataPartitionIDs,
+			BadReplica:    dpDiag.BadReplicaDataPartitionIDs,
+			ExcessReplica: dpDiag.ExcessReplicaDpIDs,
+		},
+		BadDisks: make([]diskHealth, 0),
+		Problems: make([]string, 0),
+	}
+	for _, view := range mpDiag.BadMetaPartitionIDs {
+		health.MetaPartitions.Bad = append(health.MetaPartitions.Bad, view.PartitionIDs...)
+	}
+	for _, view := range dpDiag.BadDataPartitionInfos {
+		for _, info := range view.PartitionInfos {
+			health.DataPartitions.Bad = append(health.DataPartitions.Bad, info.PartitionID)
+		}
+	}
+	if diskInfos != nil {
+		for _, disk := range diskInfos.Disks {
+			health.BadDisks = append(health.BadDisks, diskHealth{
+				Address:       disk.Address,
+				Path:          disk.Path,
+				Status:        disk.Status,
+				ErrPartitions: len(disk.DiskErrPartitionList),
+			})
+		}
+	}
+
+	critical := false
+	if health.Leader == "" {
+		critical = true
+		health.Problems = append(health.Problems, "no master leader")
+	}
+	if n := len(health.MetaPartitions.Corrupt) + len(health.DataPartitions.Corrupt); n > 0 {
+		critical = true
+		health.Problems = append(health.Problems, fmt.Sprintf("%v corrupt partitions", n))
+	}
+	warnings := []struct {
+		count int
+		what  string
+	}{
+		{len(health.MasterNodes.Inactive), "inactive master nodes"},
+		{len(health.MetaNodes.Inactive), "inactive meta nodes"},
+		{len(health.DataNodes.Inactive), "inactive data nodes"},
+		{len(health.MetaPartitions.LackReplica) + len(health.DataPartitions.LackReplica), "partitions lack of replica"},
+		{len(health.MetaPartitions.BadReplica) + len(health.DataPartitions.BadReplica), "partitions with bad replica"},
+		{len(health.MetaPartitions.ExcessReplica) + len(health.DataPartitions.ExcessReplica), "partitions with excess replica"},
+		{len(health.MetaPartitions.Bad) + len(health.DataPartitions.Bad), "bad partitions"},
+		{len(health.BadDisks), "bad disks"},
+	}
+	for _, w := range warnings {
+		if w.count > 0 {
+			health.Problems = append(health.Problems, fmt.Sprintf("%v %v", w.count, w.what))
+		}
+	}
+
+	switch {
+	case critical:
+		health.Status = healthStatusCritical
+	case len(health.Problems) > 0:
+		health.Status = healthStatusWarning
+	default:
+		health.Status = healthStatusHealthy
+	}
+	return health
+}
+
+func inactiveNodes(nodes []proto.NodeView) []string {
+	inactive := make([]string, 0)
+	for _, node := range nodes {
+		if !node.IsActive {
+			inactive = append(inactive, node.Addr)
+		}
+	}
+	return inactive
+}
+
+func healthColor(ok bool, warn bool) string {
+	if ok {
+		return colorGreen
+	}
+	if warn {
+		return colorYellow
+	}
+	return colorRed
+}
+
+func colorize(color, s string) string {
+	return color + s + colorReset
+}
+
+func formatClusterHealth(health *clusterHealth) string {
+	sb := strings.Builder{}
+	statusColor := colorGreen
+	switch health.Status {
+	case healthStatusWarning:
+		statusColor = colorYellow
+	case healthStatusCritical:
+		statusColor = colorRed
+	}
+	sb.WriteString(fmt.Sprintf("  Cluster     : %v\n", health.Cluster))
+	sb.WriteString(fmt.Sprintf("  Status      : %v\n", colorize(statusColor, strings.ToUpper(health.Status))))
+	leader := health.Leader
+	if leader == "" {
+		leader = "N/A"
+	}
+	sb.WriteString(fmt.Sprintf("  Leader      : %v\n", colorize(healthColor(health.Leader != "", false), leader)))
+
+	sb.WriteString("\n[Nodes]\n")
+	for _, n := range []struct {
+		name string
+		node nodeHealth
+	}{
+		{"Master", health.MasterNodes},
+		{"MetaNode", health.MetaNodes},
+		{"DataNode", health.DataNodes},
+	} {
+		line := fmt.Sprintf("%v/%v active", n.node.Total-len(n.node.Inactive), n.node.Total)
+		sb.WriteString(fmt.Sprintf("  %-12v: %v\n", n.name, colorize(healthColor(len(n.node.Inactive) == 0, true), line)))
+		if len(n.node.Inactive) > 0 {
+			sb.WriteString(fmt.Sprintf("  %-12v  inactive: %v\n", "", strings.Join(n.node.Inactive, ", ")))
+		}
+	}
+
+	for _, p := range []struct {
+		name      string
+		partition partitionHealth
+	}{
+		{"MetaPartitions", health.MetaPartitions},
+		{"DataPartitions", health.DataPartitions},
+	} {
+		sb.WriteString(fmt.Sprintf("\n[%v]\n", p.name))
+		sb.WriteString(formatPartitionHealthLine("Corrupt", p.partition.Corrupt, false))
+		sb.WriteString(formatPartitionHealthLine("LackReplica", p.partition.LackReplica, true))
+		sb.WriteString(formatPartitionHealthLine("BadReplica", p.partition.BadReplica, true))
+		sb.WriteString(formatPartitionHealthLine("ExcessReplica", p.partition.ExcessReplica, true))
+		sb.WriteString(formatPartitionHealthLine("Bad", p.partition.Bad, true))
+	}
+
+	sb.WriteString("\n[BadDisks]\n")
+	if len(health.BadDisks) == 0 {
+		sb.WriteString(fmt.Sprintf("  %v\n", colorize(colorGreen, "none")))
+	}
+	for _, disk := range health.BadDisks {
+		sb.WriteString(colorize(colorYellow, fmt.Sprintf("  %v %v status(%v) error partitions(%v)",
+			disk.Address, disk.Path, disk.Status, disk.ErrPartitions)))
+		sb.WriteString("\n")
+	}
+
+	if len(health.Problems) > 0 {
+		sb.WriteString("\n[Problems]\n")
+		for _, problem := range health.Problems {
+			sb.WriteString(fmt.Sprintf("  - %v\n", colorize(statusColor, problem)))
+		}
+	}
+	return sb.String()
+}
+
+func formatPartitionHealthLine(name string, ids []uint64, warn bool) string {
+	line := fmt.Sprintf("%v", len(ids))
+	if len(ids) > 0 {
+		line = fmt.Sprintf("%v %v", len(ids), ids)
+	}
+	return fmt.Sprintf("  %-14v: %v\n", name, colorize(healthColor(len(ids) == 0, warn), line))
+}
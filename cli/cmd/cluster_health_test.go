@@ -0,0 +1,60 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliClusterHealth(t *testing.T) {
+	cv := &proto.ClusterView{
+		Name:        "test",
+		LeaderAddr:  "127.0.0.1:17010",
+		MasterNodes: []proto.NodeView{{Addr: "127.0.0.1:17010", IsActive: true}},
+		MetaNodes:   []proto.NodeView{{Addr: "m1", IsActive: true}, {Addr: "m2", IsActive: true}},
+		DataNodes:   []proto.NodeView{{Addr: "d1", IsActive: true}, {Addr: "d2", IsActive: true}},
+	}
+	dpDiag := &proto.DataPartitionDiagnosis{}
+	mpDiag := &proto.MetaPartitionDiagnosis{}
+
+	health := buildClusterHealth(cv, dpDiag, mpDiag, &proto.DiskInfos{})
+	require.Equal(t, healthStatusHealthy, health.Status)
+	require.Empty(t, health.Problems)
+	t.Log("\n" + formatClusterHealth(health))
+
+	dpDiag.InactiveDataNodes = []string{"d2"}
+	dpDiag.BadDataPartitionInfos = []proto.BadPartitionRepairView{
+		{Path: "/data0", PartitionInfos: []proto.DpRepairInfo{{PartitionID: 10}, {PartitionID: 11}}},
+	}
+	mpDiag.LackReplicaMetaPartitionIDs = []uint64{1}
+	disks := &proto.DiskInfos{Disks: []proto.DiskInfo{{Address: "d1", Path: "/data1", DiskErrPartitionList: []uint64{12}}}}
+	health = buildClusterHealth(cv, dpDiag, mpDiag, disks)
+	require.Equal(t, healthStatusWarning, health.Status)
+	require.Equal(t, []string{"d2"}, health.DataNodes.Inactive)
+	require.Equal(t, []uint64{10, 11}, health.DataPartitions.Bad)
+	require.Equal(t, 1, health.BadDisks[0].ErrPartitions)
+	require.Len(t, health.Problems, 4)
+	t.Log("\n" + formatClusterHealth(health))
+
+	cv.LeaderAddr = ""
+	mpDiag.CorruptMetaPartitionIDs = []uint64{2}
+	health = buildClusterHealth(cv, dpDiag, mpDiag, disks)
+	require.Equal(t, healthStatusCritical, health.Status)
+	require.Equal(t, "no master leader", health.Problems[0])
+	t.Log("\n" + formatClusterHealth(health))
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const cmdClusterStatHistoryShort = "Show trends of cluster status from snapshots kept by master"
+
+var (
+	statHistoryTablePattern = "%-19v    %-17v    %-17v    %-9v    %-9v    %-6v    %-14v    %-14v\n"
+	statHistoryTableHeader  = fmt.Sprintf(statHistoryTablePattern, "TIME", "DATA USED/TOTAL", "META USED/TOTAL",
+		"DATANODES", "METANODES", "VOLS", "DPS(UNAVAIL)", "MPS(UNAVAIL)")
+)
+
+func newClusterStatHistoryCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optSince time.Duration
+		optUntil time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "stat-history",
+		Short: cmdClusterStatHistoryShort,
+		Long: `Show trends of cluster status, such as capacity, node counts and partition health,
+from snapshots which master takes periodically and keeps for a retention time.
+Capacities are in GB, inactive nodes are shown in parentheses after node counts.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err     error
+				history *proto.ClusterStatHistory
+			)
+			defer func() {
+				errout(err)
+			}()
+			now := time.Now()
+			if history, err = client.AdminAPI().GetClusterStatHistory(now.Add(-optSince).Unix(),
+				now.Add(-optUntil).Unix()); err != nil {
+				return
+			}
+			err = render(history, func() {
+				stdout("%v", formatClusterStatHistory(history))
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&optSince, "since", 24*time.Hour, "Show snapshots taken since the duration ago")
+	cmd.Flags().DurationVar(&optUntil, "until", 0, "Show snapshots taken until the duration ago")
+	return cmd
+}
+
+func formatClusterStatHistory(history *proto.ClusterStatHistory) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Snapshot interval: %v, retention: %vh\n",
+		time.Duration(history.IntervalSec)*time.Second, history.RetentionHour))
+	sb.WriteString(statHistoryTableHeader)
+	for _, s := range history.Snapshots {
+		sb.WriteString(fmt.Sprintf(statHistoryTablePattern,
+			time.Unix(s.Time, 0).Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%v/%v", s.DataUsedGB, s.DataTotalGB),
+			fmt.Sprintf("%v/%v", s.MetaUsedGB, s.MetaTotalGB),
+			fmt.Sprintf("%v(%v)", s.DataNodeCount, s.InactiveDataNodeCount),
+			fmt.Sprintf("%v(%v)", s.MetaNodeCount, s.InactiveMetaNodeCount),
+			s.VolCount,
+			fmt.Sprintf("%v(%v)", s.DataPartitionCount, s.UnavailableDataPartitions),
+			fmt.Sprintf("%v(%v)", s.MetaPartitionCount, s.UnavailableMetaPartitions)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliFormatClusterStatHistory(t *testing.T) {
+	history := &proto.ClusterStatHistory{
+		IntervalSec:   600,
+		RetentionHour: 168,
+		Snapshots: []*proto.ClusterStatSnapshot{
+			{Time: 1700000000, DataUsedGB: 10, DataTotalGB: 100, DataNodeCount: 3, InactiveDataNodeCount: 1, DataPartitionCount: 20, UnavailableDataPartitions: 2},
+			{Time: 1700000600, DataUsedGB: 12, DataTotalGB: 100, DataNodeCount: 3},
+		},
+	}
+	lines := strings.Split(strings.TrimSpace(formatClusterStatHistory(history)), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "10m0s")
+	require.Contains(t, lines[0], "168h")
+	require.Contains(t, lines[2], "10/100")
+	require.Contains(t, lines[2], "3(1)")
+	require.Contains(t, lines[2], "20(2)")
+	require.Contains(t, lines[3], "12/100")
+}
@@ -45,9 +45,20 @@ var (
 )
 
 type Config struct {
-	MasterAddr  []string `json:"masterAddr"`
-	Timeout     uint16   `json:"timeout"`
-	ClientIDKey string   `json:"clientIDKey"`
+	MasterAddr  []string        `json:"masterAddr"`
+	Timeout     uint16          `json:"timeout"`
+	ClientIDKey string          `json:"clientIDKey"`
+	AccessKey   string          `json:"accessKey"`
+	SecretKey   string          `json:"secretKey"`
+	Blobstore   BlobstoreConfig `json:"blobstore"`
+	Safety      SafetyConfig    `json:"safety"`
+}
+
+// BlobstoreConfig default cluster of blobstore commands
+type BlobstoreConfig struct {
+	ClusterID        uint32   `json:"clusterID"`
+	ClusterMgrAddr   []string `json:"clusterMgrAddr"`
+	ClusterMgrSecret string   `json:"clusterMgrSecret"`
 }
 
 func newConfigCmd() *cobra.Command {
@@ -57,6 +68,7 @@ func newConfigCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newConfigSetCmd())
 	cmd.AddCommand(newConfigInfoCmd())
+	cmd.AddCommand(newConfigNodeCmd())
 	return cmd
 }
 
@@ -114,7 +126,14 @@ func newConfigInfoCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			config, err := LoadConfig()
 			errout(err)
-			printConfigInfo(config)
+			// secrets in config are not printed
+			data := struct {
+				MasterAddr []string `json:"masterAddr"`
+				Timeout    uint16   `json:"timeout"`
+			}{config.MasterAddr, config.Timeout}
+			errout(render(data, func() {
+				printConfigInfo(config)
+			}))
 		},
 	}
 	cmd.Flags().StringVar(&optFilterWritable, "filter-writable", "", "Filter node writable status")
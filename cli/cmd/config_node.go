@@ -0,0 +1,284 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cubefs/cubefs/cli/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdConfigNodeShort     = "Manage runtime config of data nodes and meta nodes"
+	cmdConfigNodeGetShort  = "Show runtime config of a node"
+	cmdConfigNodeDiffShort = "Diff runtime config of nodes against a desired config file"
+	cmdConfigNodePushShort = "Push changes of a desired config file to nodes"
+)
+
+func newConfigNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: cmdConfigNodeShort,
+		Long: `Manage runtime config of data nodes and meta nodes by their http address,
+which is {HOST}:{PROF PORT}. Desired config file is a json object of config keys and
+values, such as the config file of node, keys which are not runtime configs are ignored.
+Runtime configs of meta nodes are synced from master and can not be pushed, set them
+by 'cfs-cli cluster set' instead.`,
+	}
+	cmd.AddCommand(
+		newConfigNodeGetCmd(),
+		newConfigNodeDiffCmd(),
+		newConfigNodePushCmd(),
+	)
+	return cmd
+}
+
+func newConfigNodeGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   CliOpGet + " [{HOST}:{PORT}]",
+		Short: cmdConfigNodeGetShort,
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err     error
+				configs map[string]string
+			)
+			defer func() {
+				errout(err)
+			}()
+			if configs, err = api.NewNodeHttpClient(args[0]).GetConfig(); err != nil {
+				return
+			}
+			err = render(configs, func() {
+				keys := make([]string, 0, len(configs))
+				for key := range configs {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				rows := table{arow("KEY", "VALUE")}
+				for _, key := range keys {
+					rows = rows.append(arow(key, configs[key]))
+				}
+				stdout("%v", alignTable(rows...))
+			})
+		},
+	}
+	return cmd
+}
+
+func newConfigNodeDiffCmd() *cobra.Command {
+	var optFile, optBatch string
+	cmd := &cobra.Command{
+		Use:   "diff [{HOST}:{PORT}]...",
+		Short: cmdConfigNodeDiffShort,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err     error
+				nodes   []string
+				desired map[string]string
+			)
+			defer func() {
+				errout(err)
+			}()
+			if nodes, desired, err = loadConfigNodeArgs(args, optBatch, optFile); err != nil {
+				return
+			}
+			drifts := collectConfigDrifts(nodes, desired)
+			stdout("%v", formatConfigDrifts(drifts))
+			if n := countDriftNodes(drifts); n > 0 {
+				err = fmt.Errorf("config drift found on %v of %v nodes", n, len(nodes))
+			}
+		},
+	}
+	cmd.Flags().StringVar(&optFile, "file", "", "Desired config file in json")
+	cmd.Flags().StringVar(&optBatch, "batch", "", "File of node addresses, one per line")
+	return cmd
+}
+
+func newConfigNodePushCmd() *cobra.Command {
+	var (
+		optFile, optBatch string
+		optYes            bool
+	)
+	cmd := &cobra.Command{
+		Use:   "push [{HOST}:{PORT}]...",
+		Short: cmdConfigNodePushShort,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err     error
+				nodes   []string
+				desired map[string]string
+			)
+			defer func() {
+				errout(err)
+			}()
+			if nodes, desired, err = loadConfigNodeArgs(args, optBatch, optFile); err != nil {
+				return
+			}
+			drifts := collectConfigDrifts(nodes, desired)
+			stdout("%v", formatConfigDrifts(drifts))
+			if countDriftNodes(drifts) == 0 {
+				stdout("All nodes are in sync.\n")
+				return
+			}
+			if !optYes {
+				stdout("Push changes to nodes (yes/no)[no]:")
+				var userConfirm string
+				_, _ = fmt.Scanln(&userConfirm)
+				if userConfirm != "yes" {
+					err = fmt.Errorf("Abort by user.\n")
+					return
+				}
+			}
+
+			failed := 0
+			for _, drift := range drifts {
+				if drift.err != nil || len(drift.changes) == 0 {
+					continue
+				}
+				changes := make(map[string]string, len(drift.changes))
+				for _, change := range drift.changes {
+					changes[change.key] = change.desired
+				}
+				if _, e := api.NewNodeHttpClient(drift.node).SetConfig(changes); e != nil {
+					failed++
+					stdout("%v push failed: %v\n", drift.node, e)
+					continue
+				}
+				stdout("%v pushed %v changes\n", drift.node, len(changes))
+			}
+			if failed > 0 {
+				err = fmt.Errorf("push failed on %v nodes", failed)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&optFile, "file", "", "Desired config file in json")
+	cmd.Flags().StringVar(&optBatch, "batch", "", "File of node addresses, one per line")
+	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
+	return cmd
+}
+
+func loadConfigNodeArgs(args []string, batch, file string) (nodes []string, desired map[string]string, err error) {
+	nodes = args
+	if batch != "" {
+		var batchNodes []string
+		if batchNodes, err = readNodeList(batch); err != nil {
+			return
+		}
+		nodes = append(nodes, batchNodes...)
+	}
+	if len(nodes) == 0 {
+		err = fmt.Errorf("requires node addresses or --batch file")
+		return
+	}
+	if file == "" {
+		err = fmt.Errorf("requires desired config --file")
+		return
+	}
+	desired, err = loadDesiredConfig(file)
+	return
+}
+
+// loadDesiredConfig loads scalar values of json object in file as strings
+func loadDesiredConfig(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode config file %v: %v", file, err)
+	}
+	desired := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch val := value.(type) {
+		case string, bool, json.Number:
+			desired[key] = fmt.Sprintf("%v", val)
+		}
+	}
+	return desired, nil
+}
+
+type configChange struct {
+	key     string
+	current string
+	desired string
+}
+
+type configDrift struct {
+	node    string
+	changes []configChange
+	err     error
+}
+
+func diffConfig(current, desired map[string]string) []configChange {
+	changes := make([]configChange, 0)
+	for key, value := range desired {
+		if cur, ok := current[key]; ok && cur != value {
+			changes = append(changes, configChange{key: key, current: cur, desired: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key < changes[j].key })
+	return changes
+}
+
+func collectConfigDrifts(nodes []string, desired map[string]string) []*configDrift {
+	drifts := make([]*configDrift, 0, len(nodes))
+	for _, node := range nodes {
+		drift := &configDrift{node: node}
+		drifts = append(drifts, drift)
+		current, err := api.NewNodeHttpClient(node).GetConfig()
+		if err != nil {
+			drift.err = err
+			continue
+		}
+		drift.changes = diffConfig(current, desired)
+	}
+	return drifts
+}
+
+func countDriftNodes(drifts []*configDrift) (n int) {
+	for _, drift := range drifts {
+		if drift.err != nil || len(drift.changes) > 0 {
+			n++
+		}
+	}
+	return
+}
+
+func formatConfigDrifts(drifts []*configDrift) string {
+	rows := table{arow("NODE", "KEY", "CURRENT", "DESIRED")}
+	for _, drift := range drifts {
+		if drift.err != nil {
+			rows = rows.append(arow(drift.node, "-", "error: "+drift.err.Error(), "-"))
+			continue
+		}
+		if len(drift.changes) == 0 {
+			rows = rows.append(arow(drift.node, "-", "in sync", "-"))
+			continue
+		}
+		for _, change := range drift.changes {
+			rows = rows.append(arow(drift.node, change.key, change.current, change.desired))
+		}
+	}
+	return alignTable(rows...)
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliConfigNodeDiff(t *testing.T) {
+	file := path.Join(t.TempDir(), "desired.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"autoRepair": true, "diskReadIops": 1000,
+		"diskWriteFlow": 10485760, "listen": "17310", "masterAddr": ["a", "b"]}`), 0o600))
+	desired, err := loadDesiredConfig(file)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"autoRepair":    "true",
+		"diskReadIops":  "1000",
+		"diskWriteFlow": "10485760",
+		"listen":        "17310",
+	}, desired)
+
+	current := map[string]string{
+		"autoRepair":    "false",
+		"diskReadIops":  "1000",
+		"diskWriteFlow": "0",
+	}
+	changes := diffConfig(current, desired)
+	require.Equal(t, []configChange{
+		{key: "autoRepair", current: "false", desired: "true"},
+		{key: "diskWriteFlow", current: "0", desired: "10485760"},
+	}, changes)
+
+	drifts := []*configDrift{{node: "1.1.1.1:17320", changes: changes}, {node: "1.1.1.2:17320"}}
+	require.Equal(t, 1, countDriftNodes(drifts))
+
+	require.NoError(t, os.WriteFile(file, []byte(`[1, 2]`), 0o600))
+	_, err = loadDesiredConfig(file)
+	require.Error(t, err)
+}
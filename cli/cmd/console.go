@@ -0,0 +1,240 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/cubefs/cubefs/sdk/master"
+	shlex "github.com/desertbit/go-shlex"
+	"github.com/desertbit/readline"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdConsoleUse   = "console"
+	cmdConsoleShort = "Open an interactive shell"
+	cmdUseShort     = "Show or change master address and client id key of the console"
+	cmdExitShort    = "Exit the console"
+
+	consoleHistoryName  = ".cfs-cli-history"
+	consoleHistoryLimit = 1000
+)
+
+var consoleHistoryPath = path.Join(defaultHomeDir, consoleHistoryName)
+
+// consoleExit is raised by errout instead of exiting the process in console
+type consoleExit int
+
+// errConsoleQuit is returned by the exit command to stop the console
+var errConsoleQuit = fmt.Errorf("quit console")
+
+// console runs commands on a shared master client, so master address and
+// client id key changed by 'use' are kept until the console exits.
+type console struct {
+	client  *master.MasterClient
+	timeout uint16
+}
+
+func newConsoleCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdConsoleUse,
+		Short: cmdConsoleShort,
+		Long: `Open an interactive shell to run commands without the program name,
+such as 'vol list'. Commands are kept in ~/` + consoleHistoryName + `, and can be
+completed by tab. Use 'use' to change master address and client id key for the
+rest of the console, and 'exit' or Ctrl-D to quit.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			c := &console{client: client, timeout: defaultConfigTimeout}
+			if config, e := LoadConfig(); e == nil {
+				c.timeout = config.Timeout
+			}
+			err = c.run()
+		},
+	}
+	return cmd
+}
+
+func (c *console) run() (err error) {
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          c.prompt(),
+		HistoryFile:     consoleHistoryPath,
+		HistoryLimit:    consoleHistoryLimit,
+		AutoComplete:    &consoleCompleter{newRoot: c.newRoot},
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return
+	}
+	defer rl.Close()
+
+	for {
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		args, err := shlex.Split(line, true)
+		if err != nil {
+			stdoutln("Error:", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if err = c.execute(args); err == errConsoleQuit {
+			return nil
+		}
+		if err != nil {
+			stdoutln("Error:", err)
+		}
+		rl.SetPrompt(c.prompt())
+	}
+}
+
+func (c *console) prompt() string {
+	return fmt.Sprintf("cfs-cli [%v]> ", strings.Join(c.client.Nodes(), ","))
+}
+
+// execute runs args on a new command tree, so flags of last command are not kept
+func (c *console) execute(args []string) (err error) {
+	old := exit
+	exit = func(code int) { panic(consoleExit(code)) }
+	defer func() {
+		exit = old
+		if r := recover(); r != nil {
+			if _, ok := r.(consoleExit); !ok {
+				panic(r)
+			}
+		}
+	}()
+	root := c.newRoot()
+	root.SetArgs(args)
+	return root.Execute()
+}
+
+func (c *console) newRoot() *cobra.Command {
+	root := NewRootCmd(c.client).CFSCmd
+	if sub, _, err := root.Find([]string{cmdConsoleUse}); err == nil && sub != root {
+		root.RemoveCommand(sub)
+	}
+	root.AddCommand(c.newUseCmd(), newExitCmd())
+	return root
+}
+
+func (c *console) newUseCmd() *cobra.Command {
+	var (
+		optAddr        string
+		optClientIDKey string
+		optTimeout     uint16
+	)
+	cmd := &cobra.Command{
+		Use:   "use",
+		Short: cmdUseShort,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmd.Flags().Changed("addr") {
+				c.client.ReplaceMasterAddresses(strings.Split(optAddr, ","))
+			}
+			if cmd.Flags().Changed(CliFlagClientIDKey) {
+				c.client.SetClientIDKey(optClientIDKey)
+			}
+			if cmd.Flags().Changed("timeout") && optTimeout > 0 {
+				c.timeout = optTimeout
+				c.client.SetTimeout(optTimeout)
+			}
+			stdout("  Master  Address    : %v\n", c.client.Nodes())
+			stdout("  Request Timeout [s]: %v\n", c.timeout)
+			clientIDKey := ""
+			if c.client.ClientIDKey() != "" {
+				clientIDKey = "******"
+			}
+			stdout("  Client ID Key      : %v\n", clientIDKey)
+		},
+	}
+	cmd.Flags().StringVar(&optAddr, "addr", "", "Specify master address {HOST}:{PORT}[,{HOST}:{PORT}]")
+	cmd.Flags().StringVar(&optClientIDKey, CliFlagClientIDKey, "", CliUsageClientIDKey)
+	cmd.Flags().Uint16Var(&optTimeout, "timeout", 0, "Specify timeout for requests [Unit: s]")
+	return cmd
+}
+
+func newExitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "exit",
+		Aliases: []string{"quit"},
+		Short:   cmdExitShort,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errConsoleQuit
+		},
+	}
+}
+
+// consoleCompleter completes a line by the hidden completion command of cobra,
+// which completes sub commands, flags and arguments of ValidArgsFunction.
+type consoleCompleter struct {
+	newRoot func() *cobra.Command
+}
+
+func (cc *consoleCompleter) Do(line []rune, pos int) (newLine [][]rune, length int) {
+	input := string(line[:pos])
+	args, err := shlex.Split(input, true)
+	if err != nil {
+		return nil, 0
+	}
+	toComplete := ""
+	if len(args) > 0 && !strings.HasSuffix(input, " ") {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	out := new(bytes.Buffer)
+	root := cc.newRoot()
+	root.SetOut(out)
+	root.SetErr(io.Discard)
+	root.SetArgs(append([]string{cobra.ShellCompRequestCmd}, append(args, toComplete)...))
+	if err = root.Execute(); err != nil {
+		return nil, 0
+	}
+
+	for _, candidate := range strings.Split(out.String(), "\n") {
+		// the last line is the directive, descriptions follow a tab,
+		// flags are completed as both '--flag' and '--flag='
+		candidate = strings.SplitN(candidate, "\t", 2)[0]
+		if candidate == "" || strings.HasPrefix(candidate, ":") || strings.HasSuffix(candidate, "=") {
+			continue
+		}
+		if !strings.HasPrefix(candidate, toComplete) {
+			continue
+		}
+		newLine = append(newLine, []rune(candidate[len(toComplete):]+" "))
+	}
+	return newLine, len([]rune(toComplete))
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliConsoleExecute(t *testing.T) {
+	client := master.NewMasterClient([]string{"127.0.0.1:17010"}, false)
+	c := &console{client: client, timeout: defaultConfigTimeout}
+
+	require.NoError(t, c.execute([]string{"use", "--addr", "127.0.0.2:17010,127.0.0.3:17010", "--clientIDKey", "key"}))
+	require.Equal(t, []string{"127.0.0.2:17010", "127.0.0.3:17010"}, client.Nodes())
+	require.Equal(t, "key", client.ClientIDKey())
+
+	// failed command does not exit the console
+	require.NoError(t, c.execute([]string{"no-such-command"}))
+	require.Error(t, c.execute([]string{"use", "--no-such-flag"}))
+	require.Equal(t, errConsoleQuit, c.execute([]string{"quit"}))
+}
+
+func TestCliConsoleComplete(t *testing.T) {
+	client := master.NewMasterClient([]string{"127.0.0.1:17010"}, false)
+	c := &console{client: client, timeout: defaultConfigTimeout}
+	completer := &consoleCompleter{newRoot: c.newRoot}
+
+	complete := func(line string) ([]string, int) {
+		candidates, length := completer.Do([]rune(line), len(line))
+		result := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			result = append(result, string(candidate))
+		}
+		sort.Strings(result)
+		return result, length
+	}
+
+	candidates, length := complete("us")
+	require.Equal(t, []string{"e ", "er "}, candidates)
+	require.Equal(t, 2, length)
+
+	candidates, length = complete("use --ad")
+	require.Equal(t, []string{"dr "}, candidates)
+	require.Equal(t, 4, length)
+
+	candidates, _ = complete("exit ")
+	require.Empty(t, candidates)
+
+	candidates, _ = complete("console")
+	require.Empty(t, candidates)
+}
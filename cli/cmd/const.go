@@ -44,6 +44,7 @@ const (
 	CliOpGetDiscard              = "get-discard"
 	CliOpSetDiscard              = "set-discard"
 	CliOpForbidMpDecommission    = "forbid-mp-decommission"
+	CliOpHealth                  = "health"
 
 	// Shorthand format of operation name
 	CliOpDecommissionShortHand = "dec"
@@ -120,7 +121,11 @@ const (
 	CliFlagForceInode          = "forceInode"
 	CliFlagEnableQuota         = "enableQuota"
 	CliFlagDeleteLockTime      = "delete-lock-time"
+	CliFlagInlineDirThreshold  = "inline-dir-threshold"
+	CliFlagClientQpsLimit      = "client-qps-limit"
 	CliFlagClientIDKey         = "clientIDKey"
+	CliFlagJSON                = "json"
+	CliFlagOutput              = "output"
 
 	// CliFlagSetDataPartitionCount	= "count" use dp-count instead
 
@@ -15,9 +15,12 @@
 package cmd
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/sdk/master"
 	"github.com/spf13/cobra"
 )
@@ -38,6 +41,7 @@ func newDataNodeCmd(client *master.MasterClient) *cobra.Command {
 		newDataNodeInfoCmd(client),
 		newDataNodeDecommissionCmd(client),
 		newDataNodeMigrateCmd(client),
+		newDataNodeDiskCmd(client),
 	)
 	return cmd
 }
@@ -63,8 +67,7 @@ func newDataNodeListCmd(client *master.MasterClient) *cobra.Command {
 			sort.SliceStable(view.DataNodes, func(i, j int) bool {
 				return view.DataNodes[i].ID < view.DataNodes[j].ID
 			})
-			stdoutln("[Data nodes]")
-			stdoutln(formatNodeViewTableHeader())
+			nodes := make([]proto.NodeView, 0, len(view.DataNodes))
 			for _, node := range view.DataNodes {
 				if optFilterStatus != "" &&
 					!strings.Contains(formatNodeStatus(node.IsActive), optFilterStatus) {
@@ -74,9 +77,15 @@ func newDataNodeListCmd(client *master.MasterClient) *cobra.Command {
 					!strings.Contains(formatYesNo(node.IsWritable), optFilterWritable) {
 					continue
 				}
-				stdoutln(formatNodeView(&node, true))
+				nodes = append(nodes, node)
 			}
-			return nil
+			return render(nodes, func() {
+				stdoutln("[Data nodes]")
+				stdoutln(formatNodeViewTableHeader())
+				for i := range nodes {
+					stdoutln(formatNodeView(&nodes[i], true))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optFilterWritable, "filter-writable", "", "Filter node writable status")
@@ -94,49 +103,79 @@ func newDataNodeInfoCmd(client *master.MasterClient) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			stdoutln("[Data node info]")
-			stdoutln(formatDataNodeDetail(datanodeInfo, false))
-			return nil
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validDataNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+			return render(datanodeInfo, func() {
+				stdoutln("[Data node info]")
+				stdoutln(formatDataNodeDetail(datanodeInfo, false))
+			})
 		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
 	}
 	return cmd
 }
 
 func newDataNodeDecommissionCmd(client *master.MasterClient) *cobra.Command {
 	var (
-		optCount    int
-		clientIDKey string
+		optCount         int
+		clientIDKey      string
+		optBatch         string
+		optWatch         bool
+		optMaxConcurrent int
+		optInterval      time.Duration
 	)
 	cmd := &cobra.Command{
 		Use:   CliOpDecommission + " [{HOST}:{PORT}]",
 		Short: cmdDataNodeDecommissionInfoShort,
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Decommission a data node, or a batch of data nodes listed in file with --batch.
+Nodes in batch are decommissioned with at most --max-concurrent nodes at the same time,
+and the progress is printed as a live table with --watch until all nodes are done.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if optBatch == "" && len(args) < 1 {
+				return fmt.Errorf("requires a data node address or --batch file")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if optCount < 0 {
 				stdoutln("Migrate dp count should >= 0")
 				return nil
 			}
-			if err := client.NodeAPI().DataNodeDecommission(args[0], optCount, clientIDKey); err != nil {
-				return err
+			decommission := func(addr string) error {
+				return client.NodeAPI().DataNodeDecommission(addr, optCount, clientIDKey)
 			}
-			stdoutln("Decommission data node successfully")
-			return nil
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
+			if optBatch == "" && !optWatch {
+				if err := runDangerousOp(dangerousOpDataNodeDecommission, args[0], false, false, func() error {
+					return decommission(args[0])
+				}); err != nil {
+					return err
+				}
+				stdoutln("Decommission data node successfully")
+				return nil
 			}
-			return validDataNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+
+			var (
+				nodes []string
+				err   error
+			)
+			if optBatch != "" {
+				if nodes, err = readNodeList(optBatch); err != nil {
+					return err
+				}
+			} else {
+				nodes = args[:1]
+			}
+			return runDangerousOp(dangerousOpDataNodeDecommission, strings.Join(nodes, ","), false, false, func() error {
+				return newBatchDecommission(nodes, optMaxConcurrent, optInterval, optWatch,
+					decommission, client.NodeAPI().QueryDataNodeDecommissionProgress).run()
+			})
 		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
 	}
 	cmd.Flags().IntVar(&optCount, CliFlagCount, 0, "DataNode delete mp count")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
+	cmd.Flags().StringVar(&optBatch, "batch", "", "File of data node addresses to decommission, one per line")
+	cmd.Flags().BoolVar(&optWatch, "watch", false, "Watch decommission progress until all nodes are done")
+	cmd.Flags().IntVar(&optMaxConcurrent, "max-concurrent", 1, "Max number of nodes decommissioned at the same time")
+	cmd.Flags().DurationVar(&optInterval, "interval", 5*time.Second, "Interval of querying decommission progress")
 	return cmd
 }
 
@@ -160,12 +199,7 @@ func newDataNodeMigrateCmd(client *master.MasterClient) *cobra.Command {
 			stdoutln("Migrate data node successfully")
 			return nil
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes, validDataNodes),
 	}
 	cmd.Flags().IntVar(&optCount, CliFlagCount, dpMigrateMax, "Migrate dp count,default 15")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -0,0 +1,149 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cubefs/cubefs/cli/api"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdDataNodeDiskUse   = "disk [{HOST}:{PORT}] [DISK_PATH]"
+	cmdDataNodeDiskShort = "Show disks of a data node with their partitions"
+
+	defaultDataNodeProfPort = 17320
+)
+
+// dataNodeDisk disk of data node with the data partitions on it
+type dataNodeDisk struct {
+	*api.DataNodeDisk
+	DataPartitions []*api.DataNodePartition `json:"dataPartitions"`
+}
+
+func newDataNodeDiskCmd(client *master.MasterClient) *cobra.Command {
+	var optProfPort uint16
+
+	cmd := &cobra.Command{
+		Use:   cmdDataNodeDiskUse,
+		Short: cmdDataNodeDiskShort,
+		Long: `Show usage, io error counters, decommission status and data partitions of
+disks of a data node, or only the disk of DISK_PATH. They are fetched from the
+http port of the data node, which is specified by --prof-port.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err        error
+				disks      []*api.DataNodeDisk
+				partitions []*api.DataNodePartition
+				result     []*dataNodeDisk
+			)
+			defer func() {
+				errout(err)
+			}()
+			nodeClient := api.NewNodeHttpClient(nodeProfAddr(args[0], optProfPort))
+			if disks, err = nodeClient.GetDisks(); err != nil {
+				return
+			}
+			if partitions, err = nodeClient.GetPartitions(); err != nil {
+				return
+			}
+			diskPath := ""
+			if len(args) > 1 {
+				diskPath = args[1]
+			}
+			if result, err = groupDiskPartitions(disks, partitions, diskPath); err != nil {
+				return
+			}
+			err = render(result, func() {
+				for _, disk := range result {
+					stdout("%v", formatDataNodeDisk(disk))
+				}
+			})
+		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+	}
+	cmd.Flags().Uint16Var(&optProfPort, "prof-port", defaultDataNodeProfPort, "Specify http port of data node")
+	return cmd
+}
+
+// groupDiskPartitions attaches data partitions to their disks sorted by path,
+// only the disk of diskPath is returned if it is not empty
+func groupDiskPartitions(disks []*api.DataNodeDisk, partitions []*api.DataNodePartition,
+	diskPath string) ([]*dataNodeDisk, error) {
+	result := make([]*dataNodeDisk, 0, len(disks))
+	byPath := make(map[string]*dataNodeDisk, len(disks))
+	for _, disk := range disks {
+		if diskPath != "" && filepath.Clean(disk.Path) != filepath.Clean(diskPath) {
+			continue
+		}
+		d := &dataNodeDisk{DataNodeDisk: disk, DataPartitions: make([]*api.DataNodePartition, 0)}
+		byPath[filepath.Clean(disk.Path)] = d
+		result = append(result, d)
+	}
+	if diskPath != "" && len(result) == 0 {
+		return nil, fmt.Errorf("disk %v not found", diskPath)
+	}
+
+	for _, dp := range partitions {
+		if d, ok := byPath[filepath.Dir(dp.Path)]; ok {
+			d.DataPartitions = append(d.DataPartitions, dp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	for _, d := range result {
+		sort.Slice(d.DataPartitions, func(i, j int) bool { return d.DataPartitions[i].ID < d.DataPartitions[j].ID })
+	}
+	return result, nil
+}
+
+var (
+	dataNodeDiskPartitionTablePattern = "    %-10v %-12v %-12v %-12v %v"
+	dataNodeDiskPartitionTableHeader  = fmt.Sprintf(dataNodeDiskPartitionTablePattern,
+		"DpID", "Status", "Size", "Used", "Replicas")
+)
+
+func formatDataNodeDisk(disk *dataNodeDisk) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("[Disk %v]\n", disk.Path))
+	sb.WriteString(fmt.Sprintf("  Status              : %v\n", formatDataPartitionStatus(int8(disk.Status))))
+	sb.WriteString(fmt.Sprintf("  Decommission        : %v\n", formatYesNo(disk.Decommission)))
+	sb.WriteString(fmt.Sprintf("  Total               : %v\n", formatSize(disk.Total)))
+	sb.WriteString(fmt.Sprintf("  Used                : %v\n", formatSize(disk.Used)))
+	sb.WriteString(fmt.Sprintf("  Available           : %v\n", formatSize(disk.Available)))
+	sb.WriteString(fmt.Sprintf("  Allocated           : %v\n", formatSize(disk.Allocated)))
+	sb.WriteString(fmt.Sprintf("  Unallocated         : %v\n", formatSize(disk.Unallocated)))
+	sb.WriteString(fmt.Sprintf("  ReadErrCnt          : %v\n", disk.ReadErrCnt))
+	sb.WriteString(fmt.Sprintf("  WriteErrCnt         : %v\n", disk.WriteErrCnt))
+	if h := disk.Health; h != nil && h.SmartAvailable {
+		sb.WriteString(fmt.Sprintf("  Device              : %v\n", h.Device))
+		sb.WriteString(fmt.Sprintf("  SmartPassed         : %v\n", h.SmartPassed))
+	}
+	sb.WriteString(fmt.Sprintf("  DataPartitionCnt    : %v\n", len(disk.DataPartitions)))
+	if len(disk.DataPartitions) > 0 {
+		sb.WriteString(dataNodeDiskPartitionTableHeader + "\n")
+		for _, dp := range disk.DataPartitions {
+			sb.WriteString(fmt.Sprintf(dataNodeDiskPartitionTablePattern+"\n",
+				dp.ID, formatDataPartitionStatus(int8(dp.Status)), formatSize(uint64(dp.Size)),
+				formatSize(uint64(dp.Used)), strings.Join(dp.Replicas, ",")))
+		}
+	}
+	return sb.String()
+}
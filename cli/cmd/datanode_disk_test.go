@@ -0,0 +1,57 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/cli/api"
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliGroupDiskPartitions(t *testing.T) {
+	disks := []*api.DataNodeDisk{
+		{Path: "/data2", Status: proto.ReadWrite},
+		{Path: "/data1", Status: proto.Unavailable, Decommission: true, ReadErrCnt: 3},
+	}
+	partitions := []*api.DataNodePartition{
+		{ID: 3, Path: "/data1/datapartition_3_128849018880", Status: proto.ReadOnly, Replicas: []string{"a", "b"}},
+		{ID: 2, Path: "/data2/datapartition_2_128849018880"},
+		{ID: 1, Path: "/data1/datapartition_1_128849018880"},
+		{ID: 4, Path: "/data3/datapartition_4_128849018880"},
+	}
+
+	result, err := groupDiskPartitions(disks, partitions, "")
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "/data1", result[0].Path)
+	require.Len(t, result[0].DataPartitions, 2)
+	require.Equal(t, uint64(1), result[0].DataPartitions[0].ID)
+	require.Equal(t, uint64(3), result[0].DataPartitions[1].ID)
+	require.Len(t, result[1].DataPartitions, 1)
+
+	result, err = groupDiskPartitions(disks, partitions, "/data1/")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	out := formatDataNodeDisk(result[0])
+	require.Contains(t, out, "[Disk /data1]")
+	require.Contains(t, out, "Unavailable")
+	require.Contains(t, out, "ReadErrCnt          : 3")
+	require.Contains(t, out, "a,b")
+
+	_, err = groupDiskPartitions(disks, partitions, "/data3")
+	require.Error(t, err)
+}
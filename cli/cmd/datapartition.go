@@ -43,6 +43,7 @@ func newDataPartitionCmd(client *master.MasterClient) *cobra.Command {
 		newDataPartitionGetDiscardCmd(client),
 		newDataPartitionSetDiscardCmd(client),
 		newDataPartitionQueryDecommissionProgress(client),
+		newDataPartitionCheckCrcCmd(client),
 	)
 	return cmd
 }
@@ -60,9 +61,10 @@ const (
 
 func newDataPartitionGetCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpInfo + " [DATA PARTITION ID]",
-		Short: cmdDataPartitionGetShort,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               CliOpInfo + " [DATA PARTITION ID]",
+		ValidArgsFunction: validArgsFunc(client, validDataPartitions),
+		Short:             cmdDataPartitionGetShort,
+		Args:              cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var (
 				err         error
@@ -78,7 +80,9 @@ func newDataPartitionGetCmd(client *master.MasterClient) *cobra.Command {
 			if partition, err = client.AdminAPI().GetDataPartition("", partitionID); err != nil {
 				return
 			}
-			stdoutf("%v", formatDataPartitionInfo(partition))
+			err = render(partition, func() {
+				stdoutf("%v", formatDataPartitionInfo(partition))
+			})
 		},
 	}
 	return cmd
@@ -188,6 +192,23 @@ The "reset" command will be released in next version`,
 				}
 			}
 
+			stdoutln()
+			stdoutln("[Partition with extent crc mismatch]:")
+			stdoutln(scrubErrorPartitionInfoTableHeader)
+			sort.SliceStable(diagnosis.ScrubErrorDataPartitionIDs, func(i, j int) bool {
+				return diagnosis.ScrubErrorDataPartitionIDs[i] < diagnosis.ScrubErrorDataPartitionIDs[j]
+			})
+			for _, dpId := range diagnosis.ScrubErrorDataPartitionIDs {
+				var partition *proto.DataPartitionInfo
+				if partition, err = client.AdminAPI().GetDataPartition("", dpId); err != nil {
+					err = fmt.Errorf("Partition not found, err:[%v] ", err)
+					return
+				}
+				if partition != nil {
+					stdout("%v", formatScrubErrorDpInfoRows(partition))
+				}
+			}
+
 			if diff {
 				stdoutln()
 				stdoutln("[Partition with replica file count differ significantly]:")
@@ -282,12 +303,7 @@ func newDataPartitionDecommissionCmd(client *master.MasterClient) *cobra.Command
 			}
 			stdoutln("Decommission data partition successfully")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validDataNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes, validDataPartitions),
 	}
 	cmd.Flags().BoolVarP(&raftForceDel, "raftForceDel", "r", false, "true for raftForceDel")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -317,12 +333,7 @@ func newDataPartitionReplicateCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdoutln("Add replication successfully")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validDataNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes, validDataPartitions),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -351,12 +362,7 @@ func newDataPartitionDeleteReplicaCmd(client *master.MasterClient) *cobra.Comman
 			}
 			stdoutln("Delete replication successfully")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validDataNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validDataNodes, validDataPartitions),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -396,9 +402,10 @@ func newDataPartitionGetDiscardCmd(client *master.MasterClient) *cobra.Command {
 
 func newDataPartitionSetDiscardCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpSetDiscard + " [DATA PARTITION ID] [DISCARD]",
-		Short: cmdDataPartitionSetDiscardShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               CliOpSetDiscard + " [DATA PARTITION ID] [DISCARD]",
+		ValidArgsFunction: validArgsFunc(client, validDataPartitions),
+		Short:             cmdDataPartitionSetDiscardShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var (
 				err     error
@@ -429,9 +436,10 @@ func newDataPartitionSetDiscardCmd(client *master.MasterClient) *cobra.Command {
 
 func newDataPartitionQueryDecommissionProgress(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpQueryProgress + "[DATA PARTITION ID]",
-		Short: cmdDataPartitionQueryDecommissionProgressShort,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               CliOpQueryProgress + "[DATA PARTITION ID]",
+		ValidArgsFunction: validArgsFunc(client, validDataPartitions),
+		Short:             cmdDataPartitionQueryDecommissionProgressShort,
+		Args:              cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var (
 				err  error
@@ -452,7 +460,9 @@ func newDataPartitionQueryDecommissionProgress(client *master.MasterClient) *cob
 				return
 			}
 
-			stdout("%v", formatDataPartitionDecommissionProgress(info))
+			err = render(info, func() {
+				stdout("%v", formatDataPartitionDecommissionProgress(info))
+			})
 		},
 	}
 	return cmd
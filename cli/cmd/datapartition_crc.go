@@ -0,0 +1,370 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdDataPartitionCheckCrcUse   = "check-crc [DATA PARTITION ID]"
+	cmdDataPartitionCheckCrcShort = "Compare extent crc of data partition replicas"
+
+	// same as the master, crc of empty extent and extents modified recently are not compared
+	extentEmptyCrc         uint32 = 4045511210
+	extentCrcCheckInterval        = 20 * time.Minute
+
+	dpCrcStatusOK       = "OK"
+	dpCrcStatusMismatch = "Mismatch"
+	dpCrcStatusRecover  = "Recovering"
+	dpCrcStatusTimeout  = "LoadTimeout"
+	dpCrcStatusError    = "Error"
+)
+
+// extentReplicaCrc crc of an extent on a replica
+type extentReplicaCrc struct {
+	Addr    string `json:"addr"`
+	Size    uint32 `json:"size"`
+	Crc     uint32 `json:"crc"`
+	ApplyID uint64 `json:"applyID"`
+	Bad     bool   `json:"bad"`
+}
+
+// extentCrcMismatch an extent whose replicas have different crc or size
+type extentCrcMismatch struct {
+	Extent     string              `json:"extent"`
+	Replicas   []*extentReplicaCrc `json:"replicas"`
+	Repairable bool                `json:"repairable"`
+}
+
+type dpCrcResult struct {
+	PartitionID uint64               `json:"partitionID"`
+	VolName     string               `json:"volName"`
+	Status      string               `json:"status"`
+	Message     string               `json:"message,omitempty"`
+	Mismatches  []*extentCrcMismatch `json:"mismatches,omitempty"`
+	// BadReplica the only replica with bad crc, which can be repaired by rebuilding it
+	BadReplica string `json:"badReplica,omitempty"`
+}
+
+func newDataPartitionCheckCrcCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optAll         bool
+		optRepair      bool
+		optYes         bool
+		optTimeout     time.Duration
+		optConcurrency int
+		clientIDKey    string
+	)
+	cmd := &cobra.Command{
+		Use:   cmdDataPartitionCheckCrcUse,
+		Short: cmdDataPartitionCheckCrcShort,
+		Long: `Trigger master to load extent crc from all replicas of data partitions, and
+report extents whose replicas have different crc or size. Extents modified in the last
+` + extentCrcCheckInterval.String() + ` are not compared.
+With --repair, a replica which is the only one with bad crc in a partition is decommissioned,
+so that it is rebuilt from the healthy replicas.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if !optAll && len(args) < 1 {
+				return fmt.Errorf("requires a data partition id or --all")
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err error
+				ids []uint64
+			)
+			defer func() {
+				errout(err)
+			}()
+			if optAll {
+				if ids, err = listAllDataPartitionIDs(client); err != nil {
+					return
+				}
+			} else {
+				var id uint64
+				if id, err = strconv.ParseUint(args[0], 10, 64); err != nil {
+					return
+				}
+				ids = append(ids, id)
+			}
+			if optConcurrency <= 0 {
+				optConcurrency = 1
+			}
+
+			results := make([]*dpCrcResult, len(ids))
+			idCh := make(chan int)
+			wg := sync.WaitGroup{}
+			for i := 0; i < optConcurrency; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for idx := range idCh {
+						results[idx] = checkDataPartitionCrc(client, ids[idx], clientIDKey, optTimeout)
+					}
+				}()
+			}
+			for idx := range ids {
+				idCh <- idx
+			}
+			close(idCh)
+			wg.Wait()
+
+			if err = render(results, func() {
+				stdout("%v", formatDataPartitionCrcResults(results))
+			}); err != nil {
+				return
+			}
+			if !optRepair {
+				return
+			}
+			err = repairDataPartitionCrc(client, results, optYes, clientIDKey)
+		},
+		ValidArgsFunction: validArgsFunc(client, validDataPartitions),
+	}
+	cmd.Flags().BoolVar(&optAll, "all", false, "Check all data partitions of all volumes")
+	cmd.Flags().BoolVar(&optRepair, "repair", false, "Rebuild the bad replica from the healthy replicas")
+	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
+	cmd.Flags().DurationVar(&optTimeout, "timeout", 2*time.Minute, "Time to wait for replicas to report extent crc")
+	cmd.Flags().IntVar(&optConcurrency, "concurrency", 8, "Number of data partitions checked at the same time")
+	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
+	return cmd
+}
+
+func listAllDataPartitionIDs(client *master.MasterClient) (ids []uint64, err error) {
+	var (
+		vols []*proto.VolInfo
+		view *proto.DataPartitionsView
+	)
+	if vols, err = client.AdminAPI().ListVols(""); err != nil {
+		return
+	}
+	for _, vol := range vols {
+		if view, err = client.ClientAPI().EncodingGzip().GetDataPartitions(vol.Name); err != nil {
+			return
+		}
+		for _, dp := range view.DataPartitions {
+			ids = append(ids, dp.PartitionID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return
+}
+
+// checkDataPartitionCrc loads extent crc of the partition by master and compares them
+func checkDataPartitionCrc(client *master.MasterClient, id uint64, clientIDKey string, timeout time.Duration) *dpCrcResult {
+	result := &dpCrcResult{PartitionID: id}
+	partition, err := client.AdminAPI().GetDataPartition("", id)
+	if err != nil {
+		result.Status, result.Message = dpCrcStatusError, err.Error()
+		return result
+	}
+	result.VolName = partition.VolName
+	// master does not load recovering partitions
+	if partition.IsRecover {
+		result.Status = dpCrcStatusRecover
+		return result
+	}
+	if err = client.AdminAPI().LoadDataPartition(partition.VolName, id, clientIDKey); err != nil {
+		result.Status, result.Message = dpCrcStatusError, err.Error()
+		return result
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		time.Sleep(time.Second)
+		if partition, err = client.AdminAPI().GetDataPartition(partition.VolName, id); err != nil {
+			result.Status, result.Message = dpCrcStatusError, err.Error()
+			return result
+		}
+		if dataPartitionLoaded(partition) {
+			break
+		}
+		if time.Now().After(deadline) {
+			result.Status = dpCrcStatusTimeout
+			return result
+		}
+	}
+
+	result.Mismatches = compareExtentCrc(partition, time.Now())
+	result.Status = dpCrcStatusOK
+	if len(result.Mismatches) > 0 {
+		result.Status = dpCrcStatusMismatch
+		result.BadReplica = onlyBadReplica(result.Mismatches)
+	}
+	return result
+}
+
+func dataPartitionLoaded(partition *proto.DataPartitionInfo) bool {
+	if len(partition.Replicas) < len(partition.Hosts) {
+		return false
+	}
+	for _, replica := range partition.Replicas {
+		if !replica.HasLoadResponse {
+			return false
+		}
+	}
+	return true
+}
+
+// compareExtentCrc returns extents whose replicas with the same apply id have different crc or size,
+// replicas out of the most common crc are marked bad.
+func compareExtentCrc(partition *proto.DataPartitionInfo, now time.Time) []*extentCrcMismatch {
+	mismatches := make([]*extentCrcMismatch, 0)
+	for name, fc := range partition.FileInCoreMap {
+		if len(fc.MetadataArray) < 2 || now.Unix()-fc.LastModify <= int64(extentCrcCheckInterval/time.Second) {
+			continue
+		}
+		base := fc.MetadataArray[0]
+		skip, mismatch := false, false
+		for _, fm := range fc.MetadataArray {
+			if fm.Crc == 0 || fm.Crc == extentEmptyCrc || fm.ApplyID != base.ApplyID {
+				skip = true
+				break
+			}
+			if fm.Crc != base.Crc || fm.Size != base.Size {
+				mismatch = true
+			}
+		}
+		if skip || !mismatch {
+			continue
+		}
+
+		counts := make(map[string]int)
+		key := func(fm *proto.FileMetadata) string { return fmt.Sprintf("%v/%v", fm.Size, fm.Crc) }
+		for _, fm := range fc.MetadataArray {
+			counts[key(fm)]++
+		}
+		major, majorCount := "", 0
+		for k, count := range counts {
+			if count > majorCount || (count == majorCount && k < major) {
+				major, majorCount = k, count
+			}
+		}
+
+		m := &extentCrcMismatch{Extent: name, Repairable: majorCount*2 > len(fc.MetadataArray)}
+		for _, fm := range fc.MetadataArray {
+			m.Replicas = append(m.Replicas, &extentReplicaCrc{
+				Addr: fm.LocAddr, Size: fm.Size, Crc: fm.Crc, ApplyID: fm.ApplyID,
+				Bad: m.Repairable && key(fm) != major,
+			})
+		}
+		mismatches = append(mismatches, m)
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		a, _ := strconv.ParseUint(mismatches[i].Extent, 10, 64)
+		b, _ := strconv.ParseUint(mismatches[j].Extent, 10, 64)
+		return a < b
+	})
+	return mismatches
+}
+
+// onlyBadReplica returns the replica if all mismatched extents are repairable and bad on it only
+func onlyBadReplica(mismatches []*extentCrcMismatch) string {
+	bad := ""
+	for _, m := range mismatches {
+		if !m.Repairable {
+			return ""
+		}
+		for _, replica := range m.Replicas {
+			if !replica.Bad {
+				continue
+			}
+			if bad != "" && bad != replica.Addr {
+				return ""
+			}
+			bad = replica.Addr
+		}
+	}
+	return bad
+}
+
+func formatDataPartitionCrcResults(results []*dpCrcResult) string {
+	counts := make(map[string]int)
+	rows := table{arow("PARTITION", "VOLUME", "STATUS", "EXTENT", "REPLICA", "SIZE", "CRC", "APPLY ID", "BAD")}
+	for _, result := range results {
+		counts[result.Status]++
+		if result.Status == dpCrcStatusOK {
+			continue
+		}
+		if len(result.Mismatches) == 0 {
+			rows = rows.append(arow(result.PartitionID, result.VolName, result.Status, result.Message, "", "", "", "", ""))
+			continue
+		}
+		for _, m := range result.Mismatches {
+			for _, replica := range m.Replicas {
+				rows = rows.append(arow(result.PartitionID, result.VolName, result.Status, m.Extent,
+					replica.Addr, replica.Size, replica.Crc, replica.ApplyID, formatYesNo(replica.Bad)))
+			}
+		}
+	}
+	summary := fmt.Sprintf("Checked %v data partitions: %v ok, %v mismatch, %v recovering, %v load timeout, %v error\n",
+		len(results), counts[dpCrcStatusOK], counts[dpCrcStatusMismatch], counts[dpCrcStatusRecover],
+		counts[dpCrcStatusTimeout], counts[dpCrcStatusError])
+	if len(rows) == 1 {
+		return summary
+	}
+	return alignTable(rows...) + summary
+}
+
+// repairDataPartitionCrc decommissions the only bad replica of partitions,
+// partitions with more than one bad replica or without majority crc are left to be handled manually.
+func repairDataPartitionCrc(client *master.MasterClient, results []*dpCrcResult, yes bool, clientIDKey string) (err error) {
+	repairs := make([]*dpCrcResult, 0)
+	for _, result := range results {
+		if result.Status != dpCrcStatusMismatch {
+			continue
+		}
+		if result.BadReplica == "" {
+			stdout("Data partition %v can not be repaired automatically, no single bad replica\n", result.PartitionID)
+			continue
+		}
+		repairs = append(repairs, result)
+	}
+	if len(repairs) == 0 {
+		stdout("Nothing to repair.\n")
+		return
+	}
+	if !yes {
+		stdout("Decommission bad replicas of %v data partitions to rebuild them from healthy replicas (yes/no)[no]:", len(repairs))
+		var userConfirm string
+		_, _ = fmt.Scanln(&userConfirm)
+		if userConfirm != "yes" {
+			return fmt.Errorf("Abort by user.\n")
+		}
+	}
+	failed := 0
+	for _, result := range repairs {
+		if e := client.AdminAPI().DecommissionDataPartition(result.PartitionID, result.BadReplica, false, clientIDKey); e != nil {
+			failed++
+			stdout("Data partition %v decommission replica %v failed: %v\n", result.PartitionID, result.BadReplica, e)
+			continue
+		}
+		stdout("Data partition %v decommission replica %v started\n", result.PartitionID, result.BadReplica)
+	}
+	if failed > 0 {
+		return fmt.Errorf("repair failed on %v data partitions", failed)
+	}
+	return
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliCompareExtentCrc(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour).Unix()
+	fm := func(addr string, size, crc uint32) *proto.FileMetadata {
+		return &proto.FileMetadata{LocAddr: addr, Size: size, Crc: crc, ApplyID: 10}
+	}
+	partition := &proto.DataPartitionInfo{FileInCoreMap: map[string]*proto.FileInCore{
+		// same crc
+		"1025": {Name: "1025", LastModify: old, MetadataArray: []*proto.FileMetadata{fm("a", 4, 1), fm("b", 4, 1), fm("c", 4, 1)}},
+		// bad crc on c
+		"1026": {Name: "1026", LastModify: old, MetadataArray: []*proto.FileMetadata{fm("a", 4, 1), fm("b", 4, 1), fm("c", 4, 2)}},
+		// modified recently
+		"1027": {Name: "1027", LastModify: now.Unix(), MetadataArray: []*proto.FileMetadata{fm("a", 4, 1), fm("b", 4, 1), fm("c", 4, 2)}},
+		// empty crc
+		"1028": {Name: "1028", LastModify: old, MetadataArray: []*proto.FileMetadata{fm("a", 4, 1), fm("b", 4, extentEmptyCrc), fm("c", 4, 2)}},
+		// bad size on c
+		"1029": {Name: "1029", LastModify: old, MetadataArray: []*proto.FileMetadata{fm("a", 4, 1), fm("b", 4, 1), fm("c", 8, 1)}},
+	}}
+	mismatches := compareExtentCrc(partition, now)
+	require.Len(t, mismatches, 2)
+	require.Equal(t, "1026", mismatches[0].Extent)
+	require.Equal(t, "1029", mismatches[1].Extent)
+	for _, m := range mismatches {
+		require.True(t, m.Repairable)
+		require.False(t, m.Replicas[0].Bad)
+		require.False(t, m.Replicas[1].Bad)
+		require.True(t, m.Replicas[2].Bad)
+	}
+	require.Equal(t, "c", onlyBadReplica(mismatches))
+
+	// all replicas differ, no majority
+	partition.FileInCoreMap["1030"] = &proto.FileInCore{Name: "1030", LastModify: old,
+		MetadataArray: []*proto.FileMetadata{fm("a", 4, 1), fm("b", 4, 2), fm("c", 4, 3)}}
+	mismatches = compareExtentCrc(partition, now)
+	require.Len(t, mismatches, 3)
+	require.False(t, mismatches[2].Repairable)
+	require.Equal(t, "", onlyBadReplica(mismatches))
+}
@@ -25,6 +25,7 @@ func newDiskCmd(client *master.MasterClient) *cobra.Command {
 		newListDisksCmd(client),
 		newDiskDetailCmd(client),
 		newListBadDiskCmd(client),
+		newListPredictedFailureDisksCmd(client),
 		newDecommissionDiskCmd(client),
 		newRecommissionDiskCmd(client),
 		newQueryDecommissionDiskCmd(client),
@@ -39,9 +40,10 @@ const (
 func newDiskDetailCmd(client *master.MasterClient) *cobra.Command {
 	var optDpDetail bool
 	cmd := &cobra.Command{
-		Use:   CliOpInfo + " [DATANODE_IP:PORT] [DISK_PATH]",
-		Short: cmdDiskDetailShort,
-		Args:  cobra.ExactArgs(2),
+		Use:               CliOpInfo + " [DATANODE_IP:PORT] [DISK_PATH]",
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+		Short:             cmdDiskDetailShort,
+		Args:              cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var (
 				detail *proto.DiskInfo
@@ -53,24 +55,33 @@ func newDiskDetailCmd(client *master.MasterClient) *cobra.Command {
 			if detail, err = client.AdminAPI().DiskDetail(args[0], args[1]); err != nil {
 				return
 			}
-			stdout("Summary:\n%s\n", formatDiskDetailSummary(detail))
+			data := struct {
+				*proto.DiskInfo
+				DataPartitions []*proto.DataPartitionReport `json:",omitempty"`
+			}{DiskInfo: detail}
 
-			// print data partition detail
+			// data partition detail
 			if optDpDetail {
 				var view *proto.DiskDataPartitionsView
 				if view, err = client.ClientAPI().GetDiskDataPartitions(args[0], args[1]); err != nil {
 					err = fmt.Errorf("Get disk data detail information failed:\n%v\n", err)
 					return
 				}
-				stdout("Data partitions:\n")
-				stdout("%v\n", diskDataPartitionTableHeader)
 				sort.SliceStable(view.DataPartitions, func(i, j int) bool {
 					return view.DataPartitions[i].PartitionID < view.DataPartitions[j].PartitionID
 				})
-				for _, dp := range view.DataPartitions {
-					stdout("%v\n", formatDiskDataPartitionTableRow(dp))
-				}
+				data.DataPartitions = view.DataPartitions
 			}
+			err = render(data, func() {
+				stdout("Summary:\n%s\n", formatDiskDetailSummary(detail))
+				if optDpDetail {
+					stdout("Data partitions:\n")
+					stdout("%v\n", diskDataPartitionTableHeader)
+					for _, dp := range data.DataPartitions {
+						stdout("%v\n", formatDiskDataPartitionTableRow(dp))
+					}
+				}
+			})
 		},
 	}
 	cmd.Flags().BoolVarP(&optDpDetail, "data-partition", "d", false, "Display data partitions on the disk")
@@ -83,9 +94,10 @@ const (
 
 func newListDisksCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpList + " [DATANODE_IP:PORT]",
-		Short: cmdListDisksShort,
-		Args:  cobra.MaximumNArgs(1),
+		Use:               CliOpList + " [DATANODE_IP:PORT]",
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+		Short:             cmdListDisksShort,
+		Args:              cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var (
 				infos *proto.DiskInfos
@@ -104,7 +116,9 @@ func newListDisksCmd(client *master.MasterClient) *cobra.Command {
 			sort.SliceStable(infos.Disks, func(i, j int) bool {
 				return infos.Disks[i].Address < infos.Disks[j].Address
 			})
-			stdout("%v\n", formatDiskList(infos.Disks))
+			err = render(infos.Disks, func() {
+				stdout("%v\n", formatDiskList(infos.Disks))
+			})
 		},
 	}
 	return cmd
@@ -139,15 +153,53 @@ func newListBadDiskCmd(client *master.MasterClient) *cobra.Command {
 	return cmd
 }
 
+const (
+	cmdDiskPredictUse   = "predict [DATANODE_IP:PORT]"
+	cmdDiskPredictShort = "List disks predicted to fail by the SMART data"
+)
+
+func newListPredictedFailureDisksCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               cmdDiskPredictUse,
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+		Short:             cmdDiskPredictShort,
+		Args:              cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				infos *proto.DiskInfos
+				err   error
+			)
+			defer func() {
+				errout(err)
+			}()
+			addr := ""
+			if len(args) > 0 {
+				addr = args[0]
+			}
+			if infos, err = client.AdminAPI().QueryPredictedFailureDisks(addr); err != nil {
+				return
+			}
+			sort.SliceStable(infos.Disks, func(i, j int) bool {
+				return infos.Disks[i].Address < infos.Disks[j].Address
+			})
+			err = render(infos.Disks, func() {
+				stdout("%v\n", formatPredictedFailureDisks(infos.Disks))
+			})
+		},
+	}
+	return cmd
+}
+
 const (
 	cmdDecommissionDisksShort = "Decommission disk on datanode"
 )
 
 func newDecommissionDiskCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpDecommission + " [DATA NODE ADDR] [DISK]",
-		Short: cmdDecommissionDisksShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               CliOpDecommission + " [DATA NODE ADDR] [DISK]",
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+		Short:             cmdDecommissionDisksShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			defer func() {
@@ -168,9 +220,10 @@ const (
 
 func newRecommissionDiskCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpRecommission + " [DATA NODE ADDR] [DISK]",
-		Short: cmdRecommissionDisksShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               CliOpRecommission + " [DATA NODE ADDR] [DISK]",
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+		Short:             cmdRecommissionDisksShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			defer func() {
@@ -191,9 +244,10 @@ const (
 
 func newQueryDecommissionDiskCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   CliOpQueryProgress + " [DATA NODE ADDR] [DISK]",
-		Short: cmdQueryDecommissionDiskProgressShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               CliOpQueryProgress + " [DATA NODE ADDR] [DISK]",
+		ValidArgsFunction: validArgsFunc(client, validDataNodes),
+		Short:             cmdQueryDecommissionDiskProgressShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			defer func() {
@@ -203,7 +257,9 @@ func newQueryDecommissionDiskCmd(client *master.MasterClient) *cobra.Command {
 			if err != nil {
 				return
 			}
-			stdout("%v", formatDecommissionProgress(progress))
+			err = render(progress, func() {
+				stdout("%v", formatDecommissionProgress(progress))
+			})
 		},
 	}
 	return cmd
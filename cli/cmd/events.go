@@ -0,0 +1,149 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdEventsUse       = "events [COMMAND]"
+	cmdEventsShort     = "Show events of the cluster"
+	cmdEventsTailShort = "Show the latest events of the cluster and follow new events"
+
+	// eventsPollWait is the time master waits for new events in a poll
+	eventsPollWait = 20 * time.Second
+	// eventsRetryInterval is the interval to poll again after a failed poll
+	eventsRetryInterval = 3 * time.Second
+)
+
+func newEventsCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdEventsUse,
+		Short: cmdEventsShort,
+		Args:  cobra.MinimumNArgs(0),
+	}
+	cmd.AddCommand(
+		newEventsTailCmd(client),
+	)
+	return cmd
+}
+
+func newEventsTailCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optSeverity string
+		optModule   string
+		optLines    int
+		optFollow   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: cmdEventsTailShort,
+		Long: `Show the latest events of the cluster, such as nodes down, partitions offline and
+repairs of partitions, and follow new events by long polling the master leader.
+Events are kept in memory of the master leader, so events before the leader
+changes are lost. Modules of events are datanode, metanode, datapartition,
+metapartition and volume.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err  error
+				view *proto.ClusterEventsView
+			)
+			defer func() {
+				errout(err)
+			}()
+			if err = checkEventSeverity(optSeverity); err != nil {
+				return
+			}
+			if view, err = client.AdminAPI().GetClusterEvents(0, 0, optSeverity, optModule); err != nil {
+				return
+			}
+			if err = printClusterEvents(lastClusterEvents(view.Events, optLines)); err != nil {
+				return
+			}
+			seq := view.NextSeq
+			for optFollow {
+				if view, err = client.AdminAPI().GetClusterEvents(seq, eventsPollWait, optSeverity, optModule); err != nil {
+					fmt.Fprintf(os.Stderr, "Get events failed: %v\n", err)
+					time.Sleep(eventsRetryInterval)
+					continue
+				}
+				if err = printClusterEvents(view.Events); err != nil {
+					return
+				}
+				seq = nextClusterEventSeq(seq, view)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&optSeverity, "severity", "",
+		fmt.Sprintf("Show events not less severe than it, one of %v, %v, %v",
+			proto.EventSeverityInfo, proto.EventSeverityWarning, proto.EventSeverityError))
+	cmd.Flags().StringVar(&optModule, "module", "", "Show events of the modules only, separated by comma")
+	cmd.Flags().IntVarP(&optLines, "lines", "n", 10, "Number of the latest events to show, all if it is negative")
+	cmd.Flags().BoolVarP(&optFollow, "follow", "f", true, "Follow new events")
+	_ = cmd.RegisterFlagCompletionFunc("severity", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{proto.EventSeverityInfo, proto.EventSeverityWarning, proto.EventSeverityError}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func checkEventSeverity(severity string) error {
+	switch severity {
+	case "", proto.EventSeverityInfo, proto.EventSeverityWarning, proto.EventSeverityError:
+		return nil
+	default:
+		return fmt.Errorf("invalid severity %v, should be one of %v, %v, %v", severity,
+			proto.EventSeverityInfo, proto.EventSeverityWarning, proto.EventSeverityError)
+	}
+}
+
+// lastClusterEvents returns the last n events, all events if n is negative
+func lastClusterEvents(events []*proto.ClusterEvent, n int) []*proto.ClusterEvent {
+	if n < 0 || len(events) <= n {
+		return events
+	}
+	return events[len(events)-n:]
+}
+
+// nextClusterEventSeq returns sequence of the next poll. Sequence of master restarts from 1 when
+// the leader changes, so events are polled from the beginning if the sequence goes back.
+func nextClusterEventSeq(seq uint64, view *proto.ClusterEventsView) uint64 {
+	if view.NextSeq < seq {
+		return 0
+	}
+	return view.NextSeq
+}
+
+func printClusterEvents(events []*proto.ClusterEvent) error {
+	for _, event := range events {
+		if err := render(event, func() { stdout("%v\n", formatClusterEvent(event)) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatClusterEvent(event *proto.ClusterEvent) string {
+	return fmt.Sprintf("%v %-7v %-13v %v", time.Unix(event.Time, 0).Format(proto.TimeFormat),
+		strings.ToUpper(event.Severity), event.Module, event.Message)
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliLastClusterEvents(t *testing.T) {
+	events := []*proto.ClusterEvent{{Seq: 1}, {Seq: 2}, {Seq: 3}}
+	require.Len(t, lastClusterEvents(events, -1), 3)
+	require.Len(t, lastClusterEvents(events, 5), 3)
+	require.Len(t, lastClusterEvents(events, 0), 0)
+	last := lastClusterEvents(events, 2)
+	require.Equal(t, uint64(2), last[0].Seq)
+	require.Equal(t, uint64(3), last[1].Seq)
+}
+
+func TestCliNextClusterEventSeq(t *testing.T) {
+	require.Equal(t, uint64(12), nextClusterEventSeq(10, &proto.ClusterEventsView{NextSeq: 12}))
+	require.Equal(t, uint64(10), nextClusterEventSeq(10, &proto.ClusterEventsView{NextSeq: 10}))
+	// leader changed
+	require.Equal(t, uint64(0), nextClusterEventSeq(10, &proto.ClusterEventsView{NextSeq: 3}))
+}
+
+func TestCliFormatClusterEvent(t *testing.T) {
+	out := formatClusterEvent(&proto.ClusterEvent{
+		Seq: 1, Time: 1700000000, Severity: proto.EventSeverityWarning,
+		Module: "datanode", Message: "datanode 192.168.0.1:17310 is down",
+	})
+	require.True(t, strings.Contains(out, "WARNING datanode"))
+	require.True(t, strings.HasSuffix(out, "datanode 192.168.0.1:17310 is down"))
+	require.NoError(t, checkEventSeverity(""))
+	require.NoError(t, checkEventSeverity(proto.EventSeverityError))
+	require.Error(t, checkEventSeverity("fatal"))
+}
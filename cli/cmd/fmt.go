@@ -165,6 +165,8 @@ func formatSimpleVolView(svv *proto.SimpleVolView) string {
 	sb.WriteString(fmt.Sprintf("  Tx conflict retry num           : %v\n", svv.TxConflictRetryNum))
 	sb.WriteString(fmt.Sprintf("  Tx conflict retry interval(ms)  : %v\n", svv.TxConflictRetryInterval))
 	sb.WriteString(fmt.Sprintf("  Tx limit interval(s)            : %v\n", svv.TxOpLimit))
+	sb.WriteString(fmt.Sprintf("  Inline dir threshold            : %v\n", svv.InlineDirThreshold))
+	sb.WriteString(fmt.Sprintf("  Client qps limit                : %v\n", svv.ClientQpsLimit))
 	sb.WriteString(fmt.Sprintf("  Forbidden                       : %v\n", svv.Forbidden))
 	sb.WriteString(fmt.Sprintf("  EnableAuditLog                  : %v\n", svv.EnableAuditLog))
 	sb.WriteString(fmt.Sprintf("  Quota                           : %v\n", formatEnabledDisabled(svv.EnableQuota)))
@@ -262,6 +264,10 @@ var (
 	RepFileCountDifferInfoTableHeader           = fmt.Sprintf(repFileCountDifferPartitionInfoTablePattern,
 		"DP_ID", "VOLUME", "REPLICAS", "DP_STATUS", "MEMBERS(fileCount)")
 
+	scrubErrorPartitionInfoTablePattern = "%-8v    %-8v    %-12v    %-24v    %-8v    %v"
+	scrubErrorPartitionInfoTableHeader  = fmt.Sprintf(scrubErrorPartitionInfoTablePattern,
+		"DP_ID", "VOLUME", "EXTENT_ID", "REPLICA", "REPAIRED", "REASON")
+
 	repUsedSizeDifferPartitionInfoTablePattern = "%-8v    %-8v    %-8v    %-8v    %-24v"
 	RepUsedSizeDifferInfoTableHeader           = fmt.Sprintf(repUsedSizeDifferPartitionInfoTablePattern,
 		"DP_ID", "VOLUME", "REPLICAS", "DP_STATUS", "MEMBERS(usedSize)")
@@ -301,6 +307,17 @@ func formatBadReplicaDpInfoRow(partition *proto.DataPartitionInfo) string {
 		formatDataPartitionStatus(partition.Status), "["+strings.Join(partition.Hosts, ", ")+"]", sb.String())
 }
 
+func formatScrubErrorDpInfoRows(partition *proto.DataPartitionInfo) string {
+	sb := strings.Builder{}
+	for _, replica := range partition.Replicas {
+		for _, scrubErr := range replica.ScrubErrors {
+			sb.WriteString(fmt.Sprintf(scrubErrorPartitionInfoTablePattern+"\n", partition.PartitionID, partition.VolName,
+				scrubErr.ExtentID, scrubErr.Addr, formatYesNo(scrubErr.Repaired), scrubErr.Reason))
+		}
+	}
+	return sb.String()
+}
+
 func formatReplicaFileCountDiffDpInfoRow(partition *proto.DataPartitionInfo) string {
 	sb := strings.Builder{}
 	sb.WriteString("[")
@@ -432,6 +449,11 @@ func formatDataPartitionInfo(partition *proto.DataPartitionInfo) string {
 		sb.WriteString(fmt.Sprintf("%v\n", formatDataReplica(idx, replica, true)))
 	}
 
+	sb.WriteString("\n")
+	sb.WriteString("Scrub errors : \n")
+	sb.WriteString(fmt.Sprintf("%v\n", scrubErrorPartitionInfoTableHeader))
+	sb.WriteString(formatScrubErrorDpInfoRows(partition))
+
 	sb.WriteString("\n")
 	sb.WriteString("FileInCoreMap : \n")
 	sb.WriteString(fmt.Sprintf("%v\n", formatDataFileInCoreTableHeader()))
@@ -484,6 +506,7 @@ func formatMetaPartitionInfo(partition *proto.MetaPartitionInfo) string {
 	sb.WriteString(fmt.Sprintf("Start         : %v\n", partition.Start))
 	sb.WriteString(fmt.Sprintf("End           : %v\n", partition.End))
 	sb.WriteString(fmt.Sprintf("MaxInodeID    : %v\n", partition.MaxInodeID))
+	sb.WriteString(fmt.Sprintf("Qps           : %v\n", partition.Qps))
 	sb.WriteString(fmt.Sprintf("Forbidden     : %v\n", partition.Forbidden))
 	sb.WriteString("\n")
 	sb.WriteString("Replicas : \n")
@@ -922,10 +945,47 @@ func formatDiskDetailSummary(detail *proto.DiskInfo) string {
 	sb.WriteString(fmt.Sprintf("  IOUtil              : %v\n", fmt.Sprintf("%.1f%%", detail.IOUtil)))
 	sb.WriteString(fmt.Sprintf("  DataPartitionCnt    : %v\n", detail.TotalPartitionCnt))
 	sb.WriteString(fmt.Sprintf("  ErrDataPartitions   : %v\n", errDataPartitions))
+	if h := detail.Health; h != nil {
+		sb.WriteString(fmt.Sprintf("  Device              : %v\n", h.Device))
+		if h.SmartAvailable {
+			sb.WriteString(fmt.Sprintf("  SmartPassed         : %v\n", h.SmartPassed))
+			sb.WriteString(fmt.Sprintf("  ReallocatedSectors  : %v\n", h.ReallocatedSectors))
+			sb.WriteString(fmt.Sprintf("  PendingSectors      : %v\n", h.PendingSectors))
+			sb.WriteString(fmt.Sprintf("  UncorrectableSectors: %v\n", h.UncorrectableSectors))
+			sb.WriteString(fmt.Sprintf("  MediaErrors         : %v\n", h.MediaErrors))
+			sb.WriteString(fmt.Sprintf("  PercentageUsed      : %v%%\n", h.PercentageUsed))
+			sb.WriteString(fmt.Sprintf("  Temperature         : %v\n", h.Temperature))
+			sb.WriteString(fmt.Sprintf("  PowerOnHours        : %v\n", h.PowerOnHours))
+		} else {
+			sb.WriteString("  SmartPassed         : N/A\n")
+		}
+		sb.WriteString(fmt.Sprintf("  IoLatency           : read %.1fms write %.1fms\n", h.ReadLatencyMs, h.WriteLatencyMs))
+		sb.WriteString(fmt.Sprintf("  HealthUpdateTime    : %v\n", formatTime(h.UpdateTime)))
+	}
+	if len(detail.FailureReasons) > 0 {
+		sb.WriteString(fmt.Sprintf("  PredictedFailure    : %v\n", strings.Join(detail.FailureReasons, ", ")))
+	}
 
 	return sb.String()
 }
 
+func formatPredictedFailureDisks(disks []proto.DiskInfo) string {
+	if len(disks) == 0 {
+		return ""
+	}
+	diskRows := table{
+		arow("NodeId", "Address", "Path", "Status", "Device", "Reasons"),
+	}
+	for _, d := range disks {
+		device := ""
+		if d.Health != nil {
+			device = d.Health.Device
+		}
+		diskRows = diskRows.append(arow(d.NodeId, d.Address, d.Path, d.Status, device, strings.Join(d.FailureReasons, ", ")))
+	}
+	return alignTable(diskRows...)
+}
+
 var (
 	diskDataPartitionTablePattern = "%-8v    %-8v    %-8v    %-8v    %-8v    %-8v    %-8v    %-8v    %-8v"
 	diskDataPartitionTableHeader  = fmt.Sprintf(diskDataPartitionTablePattern,
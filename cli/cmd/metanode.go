@@ -39,6 +39,7 @@ func newMetaNodeCmd(client *master.MasterClient) *cobra.Command {
 		newMetaNodeInfoCmd(client),
 		newMetaNodeDecommissionCmd(client),
 		newMetaNodeMigrateCmd(client),
+		newMetaNodeBalanceCmd(client),
 	)
 	return cmd
 }
@@ -69,8 +70,7 @@ func newMetaNodeListCmd(client *master.MasterClient) *cobra.Command {
 			sort.SliceStable(view.MetaNodes, func(i, j int) bool {
 				return view.MetaNodes[i].ID < view.MetaNodes[j].ID
 			})
-			stdout("[Meta nodes]\n")
-			stdout("%v\n", formatNodeViewTableHeader())
+			nodes := make([]proto.NodeView, 0, len(view.MetaNodes))
 			for _, node := range view.MetaNodes {
 				if optFilterStatus != "" &&
 					!strings.Contains(formatNodeStatus(node.IsActive), optFilterStatus) {
@@ -80,8 +80,15 @@ func newMetaNodeListCmd(client *master.MasterClient) *cobra.Command {
 					!strings.Contains(formatYesNo(node.IsWritable), optFilterWritable) {
 					continue
 				}
-				stdout("%v\n", formatNodeView(&node, true))
+				nodes = append(nodes, node)
 			}
+			err = render(nodes, func() {
+				stdout("[Meta nodes]\n")
+				stdout("%v\n", formatNodeViewTableHeader())
+				for i := range nodes {
+					stdout("%v\n", formatNodeView(&nodes[i], true))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optFilterWritable, "filter-writable", "", "Filter node writable status")
@@ -105,15 +112,12 @@ func newMetaNodeInfoCmd(client *master.MasterClient) *cobra.Command {
 			if metanodeInfo, err = client.NodeAPI().GetMetaNode(nodeAddr); err != nil {
 				return
 			}
-			stdout("[Meta node info]\n")
-			stdout("%v", formatMetaNodeDetail(metanodeInfo, false))
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+			err = render(metanodeInfo, func() {
+				stdout("[Meta node info]\n")
+				stdout("%v", formatMetaNodeDetail(metanodeInfo, false))
+			})
 		},
+		ValidArgsFunction: validArgsFunc(client, validMetaNodes),
 	}
 	return cmd
 }
@@ -138,17 +142,14 @@ func newMetaNodeDecommissionCmd(client *master.MasterClient) *cobra.Command {
 				stdout("Migrate mp count should >= 0\n")
 				return
 			}
-			if err = client.NodeAPI().MetaNodeDecommission(nodeAddr, optCount, clientIDKey); err != nil {
+			if err = runDangerousOp(dangerousOpMetaNodeDecommission, nodeAddr, false, false, func() error {
+				return client.NodeAPI().MetaNodeDecommission(nodeAddr, optCount, clientIDKey)
+			}); err != nil {
 				return
 			}
 			stdout("Decommission meta node successfully\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validMetaNodes),
 	}
 	cmd.Flags().IntVar(&optCount, CliFlagCount, 0, "MetaNode delete mp count")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -181,12 +182,7 @@ func newMetaNodeMigrateCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdout("Migrate meta node successfully\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validMetaNodes, validMetaNodes),
 	}
 	cmd.Flags().IntVar(&optCount, CliFlagCount, mpMigrateMax, "Migrate mp count")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -0,0 +1,280 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdMetaNodeBalanceShort = "Show meta partition skew of meta nodes in a zone and migrate partitions to balance it"
+)
+
+// metaNodeLoad meta partitions on a meta node, Projected is the count after the planned moves
+type metaNodeLoad struct {
+	Addr       string   `json:"addr"`
+	Ratio      float64  `json:"ratio"`
+	Partitions []uint64 `json:"-"`
+	Count      int      `json:"count"`
+	Projected  int      `json:"projected"`
+}
+
+// metaPartitionMove a meta partition planned to move off a meta node, the target is chosen by master
+type metaPartitionMove struct {
+	PartitionID uint64 `json:"partitionID"`
+	From        string `json:"from"`
+	// Items count of inodes and dentries of the partition
+	Items uint64 `json:"items"`
+}
+
+type metaNodeBalancePlan struct {
+	Zone  string               `json:"zone"`
+	Nodes []*metaNodeLoad      `json:"nodes"`
+	Moves []*metaPartitionMove `json:"moves"`
+}
+
+func newMetaNodeBalanceCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optZone      string
+		optThreshold float64
+		optMaxMoves  int
+		optDryRun    bool
+		optYes       bool
+		clientIDKey  string
+	)
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: cmdMetaNodeBalanceShort,
+		Long: `Show the count of meta partitions on active meta nodes of the zone, and plan to move
+the largest meta partitions off the most loaded nodes until no node has more partitions
+than the average by --threshold. Planned partitions are decommissioned from the loaded
+nodes, and master chooses the new nodes of them. Use --dry-run to show the plan only.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err  error
+				plan *metaNodeBalancePlan
+			)
+			defer func() {
+				errout(err)
+			}()
+			if optZone == "" {
+				err = fmt.Errorf("requires --zone")
+				return
+			}
+			if plan, err = newMetaNodeBalancePlan(client, optZone, optThreshold, optMaxMoves); err != nil {
+				return
+			}
+			if err = render(plan, func() {
+				stdout("%v", formatMetaNodeBalancePlan(plan))
+			}); err != nil {
+				return
+			}
+			if optDryRun || len(plan.Moves) == 0 {
+				return
+			}
+			if !optYes {
+				stdout("Decommission %v meta partitions to balance zone %v (yes/no)[no]:", len(plan.Moves), optZone)
+				var userConfirm string
+				_, _ = fmt.Scanln(&userConfirm)
+				if userConfirm != "yes" {
+					err = fmt.Errorf("Abort by user.\n")
+					return
+				}
+			}
+			failed := 0
+			for _, move := range plan.Moves {
+				if e := client.AdminAPI().DecommissionMetaPartition(move.PartitionID, move.From, clientIDKey); e != nil {
+					failed++
+					stdout("Meta partition %v decommission from %v failed: %v\n", move.PartitionID, move.From, e)
+					continue
+				}
+				stdout("Meta partition %v decommission from %v started\n", move.PartitionID, move.From)
+			}
+			if failed > 0 {
+				err = fmt.Errorf("decommission failed on %v meta partitions", failed)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&optZone, "zone", "", "Specify zone of meta nodes")
+	cmd.Flags().Float64Var(&optThreshold, "threshold", 0.1, "Allowed ratio of partitions above the average of a node")
+	cmd.Flags().IntVar(&optMaxMoves, "max-moves", 10, "Max number of meta partitions to move")
+	cmd.Flags().BoolVar(&optDryRun, "dry-run", false, "Show the plan without migrating")
+	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
+	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
+	_ = cmd.RegisterFlagCompletionFunc("zone", validArgsFunc(client, validZones))
+	return cmd
+}
+
+func newMetaNodeBalancePlan(client *master.MasterClient, zoneName string, threshold float64, maxMoves int) (plan *metaNodeBalancePlan, err error) {
+	var zones []*proto.ZoneView
+	if zones, err = client.AdminAPI().ListZones(); err != nil {
+		return
+	}
+	var zone *proto.ZoneView
+	for _, z := range zones {
+		if z.Name == zoneName {
+			zone = z
+		}
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("zone %v not found", zoneName)
+	}
+
+	plan = &metaNodeBalancePlan{Zone: zoneName}
+	for _, nodeSet := range zone.NodeSet {
+		for _, view := range nodeSet.MetaNodes {
+			if !view.IsActive {
+				continue
+			}
+			var info *proto.MetaNodeInfo
+			if info, err = client.NodeAPI().GetMetaNode(view.Addr); err != nil {
+				return
+			}
+			plan.Nodes = append(plan.Nodes, &metaNodeLoad{
+				Addr:       info.Addr,
+				Ratio:      info.Ratio,
+				Partitions: info.PersistenceMetaPartitions,
+				Count:      len(info.PersistenceMetaPartitions),
+			})
+		}
+	}
+	if len(plan.Nodes) == 0 {
+		return nil, fmt.Errorf("no active meta node in zone %v", zoneName)
+	}
+
+	// only partitions on nodes above the average may be moved
+	items := make(map[uint64]uint64)
+	avg := averageMetaPartitionCount(plan.Nodes)
+	for _, node := range plan.Nodes {
+		if float64(node.Count) <= avg {
+			continue
+		}
+		for _, id := range node.Partitions {
+			mp, e := client.ClientAPI().GetMetaPartition(id)
+			if e != nil || mp.IsRecover || len(mp.Hosts) < int(mp.ReplicaNum) {
+				continue
+			}
+			items[id] = mp.InodeCount + mp.DentryCount
+		}
+	}
+	plan.Moves = planMetaNodeBalance(plan.Nodes, items, threshold, maxMoves)
+	return
+}
+
+func averageMetaPartitionCount(nodes []*metaNodeLoad) float64 {
+	total := 0
+	for _, node := range nodes {
+		total += node.Count
+	}
+	return float64(total) / float64(len(nodes))
+}
+
+// planMetaNodeBalance moves the largest movable partition off the most loaded node to the least
+// loaded node, until the most loaded node is within threshold above the average. Only partitions
+// in items are movable, and a partition is not moved to a node which already has it.
+func planMetaNodeBalance(nodes []*metaNodeLoad, items map[uint64]uint64, threshold float64, maxMoves int) []*metaPartitionMove {
+	moves := make([]*metaPartitionMove, 0)
+	if len(nodes) < 2 {
+		return moves
+	}
+	avg := averageMetaPartitionCount(nodes)
+	hosted := make(map[string]map[uint64]bool, len(nodes))
+	for _, node := range nodes {
+		node.Projected = node.Count
+		hosted[node.Addr] = make(map[uint64]bool, len(node.Partitions))
+		for _, id := range node.Partitions {
+			hosted[node.Addr][id] = true
+		}
+	}
+	moved := make(map[uint64]bool)
+	exhausted := make(map[string]bool)
+
+	for len(moves) < maxMoves {
+		var src, dst *metaNodeLoad
+		for _, node := range nodes {
+			if !exhausted[node.Addr] && (src == nil || node.Projected > src.Projected) {
+				src = node
+			}
+			if dst == nil || node.Projected < dst.Projected {
+				dst = node
+			}
+		}
+		if src == nil || src.Projected-dst.Projected <= 1 || float64(src.Projected) <= avg*(1+threshold) {
+			break
+		}
+		var (
+			best      uint64
+			bestItems uint64
+			found     bool
+		)
+		for id := range hosted[src.Addr] {
+			n, ok := items[id]
+			if !ok || moved[id] || hosted[dst.Addr][id] {
+				continue
+			}
+			if !found || n > bestItems || (n == bestItems && id < best) {
+				best, bestItems, found = id, n, true
+			}
+		}
+		if !found {
+			exhausted[src.Addr] = true
+			continue
+		}
+		moved[best] = true
+		delete(hosted[src.Addr], best)
+		hosted[dst.Addr][best] = true
+		src.Projected--
+		dst.Projected++
+		moves = append(moves, &metaPartitionMove{PartitionID: best, From: src.Addr, Items: bestItems})
+	}
+	return moves
+}
+
+func formatMetaNodeBalancePlan(plan *metaNodeBalancePlan) string {
+	nodes := make([]*metaNodeLoad, len(plan.Nodes))
+	copy(nodes, plan.Nodes)
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Count != nodes[j].Count {
+			return nodes[i].Count > nodes[j].Count
+		}
+		return nodes[i].Addr < nodes[j].Addr
+	})
+	avg := averageMetaPartitionCount(nodes)
+	rows := table{arow("NODE", "MEM RATIO", "PARTITIONS", "SKEW", "PROJECTED")}
+	for _, node := range nodes {
+		skew := 0.0
+		if avg > 0 {
+			skew = (float64(node.Count) - avg) * 100 / avg
+		}
+		rows = rows.append(arow(node.Addr, fmt.Sprintf("%.2f%%", node.Ratio*100), node.Count,
+			fmt.Sprintf("%+.2f%%", skew), node.Projected))
+	}
+	out := alignTable(rows...)
+	out += fmt.Sprintf("Zone %v has %v active meta nodes, %.2f meta partitions per node\n", plan.Zone, len(nodes), avg)
+	if len(plan.Moves) == 0 {
+		return out + "No meta partition needs to be moved.\n"
+	}
+	rows = table{arow("PARTITION", "FROM", "INODES+DENTRIES")}
+	for _, move := range plan.Moves {
+		rows = rows.append(arow(move.PartitionID, move.From, move.Items))
+	}
+	return out + alignTable(rows...)
+}
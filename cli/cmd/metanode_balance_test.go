@@ -0,0 +1,50 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliPlanMetaNodeBalance(t *testing.T) {
+	nodes := []*metaNodeLoad{
+		{Addr: "a", Partitions: []uint64{1, 2, 3, 4, 5, 6}, Count: 6},
+		{Addr: "b", Partitions: []uint64{1, 7}, Count: 2},
+		{Addr: "c", Partitions: []uint64{2, 8}, Count: 2},
+	}
+	items := map[uint64]uint64{1: 500, 2: 300, 3: 100, 4: 400, 5: 200, 6: 50}
+	moves := planMetaNodeBalance(nodes, items, 0.1, 10)
+	require.Len(t, moves, 2)
+	// partition 1 is the largest, but it is on b which is the target
+	require.Equal(t, uint64(4), moves[0].PartitionID)
+	require.Equal(t, "a", moves[0].From)
+	require.Equal(t, uint64(1), moves[1].PartitionID)
+	require.Equal(t, 4, nodes[0].Projected)
+	require.Equal(t, 3, nodes[1].Projected)
+	require.Equal(t, 3, nodes[2].Projected)
+
+	// limited by max moves
+	moves = planMetaNodeBalance(nodes, items, 0.1, 1)
+	require.Len(t, moves, 1)
+
+	// no movable partition
+	moves = planMetaNodeBalance(nodes, map[uint64]uint64{}, 0.1, 10)
+	require.Len(t, moves, 0)
+	require.Equal(t, 6, nodes[0].Projected)
+
+	t.Log("\n" + formatMetaNodeBalancePlan(&metaNodeBalancePlan{Zone: "z", Nodes: nodes, Moves: moves}))
+}
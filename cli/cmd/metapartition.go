@@ -40,6 +40,7 @@ func newMetaPartitionCmd(client *master.MasterClient) *cobra.Command {
 		newMetaPartitionDecommissionCmd(client),
 		newMetaPartitionReplicateCmd(client),
 		newMetaPartitionDeleteReplicaCmd(client),
+		newMetaPartitionSplitCmd(client),
 	)
 	return cmd
 }
@@ -50,6 +51,7 @@ const (
 	cmdMetaPartitionDecommissionShort  = "Decommission a replication of the meta partition to a new address"
 	cmdMetaPartitionReplicateShort     = "Add a replication of the meta partition on a new address"
 	cmdMetaPartitionDeleteReplicaShort = "Delete a replication of the meta partition on a fixed address"
+	cmdMetaPartitionSplitShort         = "Split the unallocated inode range of a meta partition into a new meta partition"
 )
 
 func newMetaPartitionGetCmd(client *master.MasterClient) *cobra.Command {
@@ -72,7 +74,9 @@ func newMetaPartitionGetCmd(client *master.MasterClient) *cobra.Command {
 			if partition, err = client.ClientAPI().GetMetaPartition(partitionID); err != nil {
 				return
 			}
-			stdout(formatMetaPartitionInfo(partition))
+			err = render(partition, func() {
+				stdout(formatMetaPartitionInfo(partition))
+			})
 		},
 	}
 	return cmd
@@ -275,12 +279,7 @@ func newMetaPartitionDecommissionCmd(client *master.MasterClient) *cobra.Command
 			}
 			stdout("Decommission meta partition successfully\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validMetaNodes),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -307,12 +306,7 @@ func newMetaPartitionReplicateCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdout("Add replication successfully\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validMetaNodes),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -342,13 +336,42 @@ func newMetaPartitionDeleteReplicaCmd(client *master.MasterClient) *cobra.Comman
 			}
 			stdout("Delete replication successfully\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
+		ValidArgsFunction: validArgsFunc(client, validMetaNodes),
+	}
+	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
+	return cmd
+}
+
+func newMetaPartitionSplitCmd(client *master.MasterClient) *cobra.Command {
+	var optEnd uint64
+	cmd := &cobra.Command{
+		Use:   "split [VOLUME] [META PARTITION ID]",
+		Short: cmdMetaPartitionSplitShort,
+		Long: `Split the inode range of a meta partition of the volume after the end into a new meta
+partition, so that new inodes in the range are created in the new partition. The end must be
+above the allocated inodes, existing inodes and dentries are not moved, and the partition keeps
+serving during the split. If the end is not set, the partition keeps a quarter of the inode step
+for the inodes being allocated.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err         error
+				partitionID uint64
+				msg         string
+			)
+			defer func() {
+				errout(err)
+			}()
+			if partitionID, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+				return
 			}
-			return validMetaNodes(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+			if msg, err = client.AdminAPI().SplitMetaPartition(args[0], partitionID, optEnd); err != nil {
+				return
+			}
+			stdout("%v\n", msg)
 		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
 	}
-	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
+	cmd.Flags().Uint64Var(&optEnd, "end", 0, "The new end of the inode range of the meta partition")
 	return cmd
 }
@@ -0,0 +1,215 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+const (
+	// status messages of decommission progress returned by master
+	decommissionStatusSuccess = "Success"
+	decommissionStatusFailed  = "Failed"
+
+	decommissionStatusWaiting = "Waiting"
+	decommissionStatusError   = "Error"
+
+	clearScreen = "\033[H\033[2J"
+)
+
+// readNodeList reads node addresses from file, one per line,
+// blank lines and lines starting with '#' are ignored.
+func readNodeList(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, ok := seen[line]; ok {
+			continue
+		}
+		seen[line] = struct{}{}
+		nodes = append(nodes, line)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no node in file %v", file)
+	}
+	return nodes, nil
+}
+
+type nodeDecommissionState struct {
+	addr      string
+	status    string
+	progress  string
+	failedDps []uint64
+	err       error
+	startTime time.Time
+	endTime   time.Time
+}
+
+func (s *nodeDecommissionState) done() bool {
+	return !s.endTime.IsZero()
+}
+
+// batchDecommission decommissions nodes with at most maxConcurrent nodes running,
+// and polls progress of running nodes every interval until all nodes are done.
+type batchDecommission struct {
+	decommission  func(addr string) error
+	query         func(addr string) (*proto.DecommissionProgress, error)
+	maxConcurrent int
+	interval      time.Duration
+	watch         bool
+
+	nodes []*nodeDecommissionState
+}
+
+func newBatchDecommission(addrs []string, maxConcurrent int, interval time.Duration, watch bool,
+	decommission func(addr string) error, query func(addr string) (*proto.DecommissionProgress, error),
+) *batchDecommission {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	b := &batchDecommission{
+		decommission:  decommission,
+		query:         query,
+		maxConcurrent: maxConcurrent,
+		interval:      interval,
+		watch:         watch,
+	}
+	for _, addr := range addrs {
+		b.nodes = append(b.nodes, &nodeDecommissionState{addr: addr, status: decommissionStatusWaiting})
+	}
+	return b
+}
+
+// run returns an error if any node failed to decommission
+func (b *batchDecommission) run() error {
+	for {
+		b.poll()
+		if b.watch {
+			stdout("%v%v", clearScreen, b.formatProgress())
+		}
+		if b.finished() {
+			break
+		}
+		time.Sleep(b.interval)
+	}
+	if !b.watch {
+		stdout("%v", b.formatProgress())
+	}
+
+	failed := make([]string, 0)
+	for _, node := range b.nodes {
+		if node.err != nil || node.status == decommissionStatusFailed {
+			failed = append(failed, node.addr)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("decommission failed nodes: %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// poll refreshes progress of running nodes, then starts waiting nodes up to the limit
+func (b *batchDecommission) poll() {
+	running := 0
+	for _, node := range b.nodes {
+		if node.startTime.IsZero() || node.done() {
+			continue
+		}
+		progress, err := b.query(node.addr)
+		if err != nil {
+			// keep last progress, query again next time
+			running++
+			continue
+		}
+		node.status, node.progress, node.failedDps = progress.StatusMessage, progress.Progress, progress.FailedDps
+		if node.status == decommissionStatusSuccess || node.status == decommissionStatusFailed {
+			node.endTime = time.Now()
+			if !b.watch {
+				stdout("%v decommission %v, cost %v\n", node.addr, node.status, node.endTime.Sub(node.startTime).Truncate(time.Second))
+			}
+			continue
+		}
+		running++
+	}
+
+	for _, node := range b.nodes {
+		if running >= b.maxConcurrent {
+			break
+		}
+		if !node.startTime.IsZero() {
+			continue
+		}
+		node.startTime = time.Now()
+		if node.err = b.decommission(node.addr); node.err != nil {
+			node.status = decommissionStatusError
+			node.endTime = node.startTime
+			if !b.watch {
+				stdout("%v decommission error: %v\n", node.addr, node.err)
+			}
+			continue
+		}
+		if !b.watch {
+			stdout("%v decommission started\n", node.addr)
+		}
+		running++
+	}
+}
+
+func (b *batchDecommission) finished() bool {
+	for _, node := range b.nodes {
+		if !node.done() {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *batchDecommission) formatProgress() string {
+	done := 0
+	rows := table{arow("NODE", "STATUS", "PROGRESS", "FAILED DPS", "ELAPSED", "MESSAGE")}
+	for _, node := range b.nodes {
+		if node.done() {
+			done++
+		}
+		elapsed := ""
+		if !node.startTime.IsZero() {
+			end := node.endTime
+			if end.IsZero() {
+				end = time.Now()
+			}
+			elapsed = end.Sub(node.startTime).Truncate(time.Second).String()
+		}
+		message := ""
+		if node.err != nil {
+			message = node.err.Error()
+		}
+		rows = rows.append(arow(node.addr, node.status, node.progress, len(node.failedDps), elapsed, message))
+	}
+	return fmt.Sprintf("Decommission %v/%v nodes done, max concurrent nodes %v\n%v",
+		done, len(b.nodes), b.maxConcurrent, alignTable(rows...))
+}
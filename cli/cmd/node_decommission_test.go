@@ -0,0 +1,79 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliReadNodeList(t *testing.T) {
+	file := path.Join(t.TempDir(), "nodes.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# nodes\n1.1.1.1:17310\n\n 1.1.1.2:17310 \n1.1.1.1:17310\n"), 0o600))
+	nodes, err := readNodeList(file)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.1.1.1:17310", "1.1.1.2:17310"}, nodes)
+
+	require.NoError(t, os.WriteFile(file, []byte("# nodes\n"), 0o600))
+	_, err = readNodeList(file)
+	require.Error(t, err)
+}
+
+func TestCliBatchDecommission(t *testing.T) {
+	polls := make(map[string]int)
+	running, maxRunning := 0, 0
+	decommission := func(addr string) error {
+		if addr == "bad" {
+			return errors.New("node not exists")
+		}
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		return nil
+	}
+	query := func(addr string) (*proto.DecommissionProgress, error) {
+		polls[addr]++
+		if polls[addr] < 2 {
+			return &proto.DecommissionProgress{StatusMessage: "Running", Progress: "50.00%"}, nil
+		}
+		running--
+		if addr == "n3" {
+			return &proto.DecommissionProgress{StatusMessage: decommissionStatusFailed, FailedDps: []uint64{1}}, nil
+		}
+		return &proto.DecommissionProgress{StatusMessage: decommissionStatusSuccess, Progress: "100.00%"}, nil
+	}
+
+	b := newBatchDecommission([]string{"n1", "n2", "bad", "n3"}, 2, time.Millisecond, false, decommission, query)
+	err := b.run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bad")
+	require.Contains(t, err.Error(), "n3")
+	require.Equal(t, 2, maxRunning)
+	for _, node := range b.nodes {
+		require.True(t, node.done())
+	}
+	require.Equal(t, decommissionStatusSuccess, b.nodes[0].status)
+	require.Equal(t, decommissionStatusError, b.nodes[2].status)
+
+	b = newBatchDecommission([]string{"n1"}, 0, time.Millisecond, false, decommission, query)
+	require.NoError(t, b.run())
+}
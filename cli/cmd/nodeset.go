@@ -59,12 +59,14 @@ func newNodeSetListCmd(client *master.MasterClient) *cobra.Command {
 			if nodeSetStats, err = client.AdminAPI().ListNodeSets(zoneName); err != nil {
 				return
 			}
-			zoneTablePattern := "%-6v %-6v %-12v %-10v %-10v\n"
-			stdout(zoneTablePattern, "ID", "Cap", "Zone", "MetaNum", "DataNum")
-			zoneDataPattern := "%-6v %-6v %-12v %-10v %-10v\n"
-			for _, nodeSet := range nodeSetStats {
-				stdout(zoneDataPattern, nodeSet.ID, nodeSet.Capacity, nodeSet.Zone, nodeSet.MetaNodeNum, nodeSet.DataNodeNum)
-			}
+			err = render(nodeSetStats, func() {
+				zoneTablePattern := "%-6v %-6v %-12v %-10v %-10v\n"
+				stdout(zoneTablePattern, "ID", "Cap", "Zone", "MetaNum", "DataNum")
+				zoneDataPattern := "%-6v %-6v %-12v %-10v %-10v\n"
+				for _, nodeSet := range nodeSetStats {
+					stdout(zoneDataPattern, nodeSet.ID, nodeSet.Capacity, nodeSet.Zone, nodeSet.MetaNodeNum, nodeSet.DataNodeNum)
+				}
+			})
 		},
 	}
 
@@ -89,7 +91,9 @@ func newNodeSetInfoCmd(client *master.MasterClient) *cobra.Command {
 			if nodeSetStatInfo, err = client.AdminAPI().GetNodeSet(nodeSetId); err != nil {
 				return
 			}
-			stdout("%v", formatNodeSetView(nodeSetStatInfo))
+			err = render(nodeSetStatInfo, func() {
+				stdout("%v", formatNodeSetView(nodeSetStatInfo))
+			})
 		},
 	}
 	return cmd
@@ -0,0 +1,112 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// optOutput is the global output format set by --output
+var optOutput = outputTable
+
+// render prints data in the output format, the human readable table is
+// printed by printTable. Data is always encoded by its json tags,
+// so json and yaml outputs have the same field names.
+func render(data interface{}, printTable func()) error {
+	switch optOutput {
+	case outputTable, "":
+		printTable()
+		return nil
+	case outputJSON:
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		stdout("%s\n", out)
+		return nil
+	case outputYAML:
+		out, err := marshalYAML(data)
+		if err != nil {
+			return err
+		}
+		stdout("%s", out)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, should be one of %v, %v, %v",
+			optOutput, outputTable, outputJSON, outputYAML)
+	}
+}
+
+func marshalYAML(data interface{}) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err = dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(yamlValue(value))
+}
+
+// yamlValue converts numbers decoded from json to integers or float64,
+// which are encoded by yaml as numbers instead of strings.
+func yamlValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return u
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		m := make(yaml.MapSlice, 0, len(val))
+		for _, key := range keys {
+			m = append(m, yaml.MapItem{Key: key, Value: yamlValue(val[key])})
+		}
+		return m
+	case []interface{}:
+		for i := range val {
+			val[i] = yamlValue(val[i])
+		}
+		return val
+	default:
+		return val
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliRender(t *testing.T) {
+	type item struct {
+		Name  string  `json:"name"`
+		Size  uint64  `json:"size"`
+		Ratio float64 `json:"ratio"`
+		Tags  []string
+	}
+	data := []item{{Name: "a", Size: math.MaxUint64, Ratio: 0.5, Tags: []string{"x"}}}
+
+	out := new(strings.Builder)
+	old := stdout
+	stdout = func(format string, a ...interface{}) { fmt.Fprintf(out, format, a...) }
+	defer func() {
+		stdout = old
+		optOutput = outputTable
+	}()
+
+	printed := false
+	optOutput = outputTable
+	require.NoError(t, render(data, func() { printed = true }))
+	require.True(t, printed)
+	require.Empty(t, out.String())
+
+	optOutput = outputJSON
+	require.NoError(t, render(data, func() { t.Fatal("table printed") }))
+	require.Equal(t, `[
+  {
+    "name": "a",
+    "size": 18446744073709551615,
+    "ratio": 0.5,
+    "Tags": [
+      "x"
+    ]
+  }
+]
+`, out.String())
+
+	out.Reset()
+	optOutput = outputYAML
+	require.NoError(t, render(data, func() { t.Fatal("table printed") }))
+	require.Equal(t, "- Tags:\n  - x\n  name: a\n  ratio: 0.5\n  size: 18446744073709551615\n", out.String())
+
+	optOutput = "xml"
+	require.Error(t, render(data, func() {}))
+}
@@ -70,6 +70,8 @@ func newQuotaCmd(client *master.MasterClient) *cobra.Command {
 		newQuotaListAllCmd(client),
 		newQuotaApplyCmd(client),
 		newQuotaRevokeCmd(client),
+		newQuotaSetCmd(client),
+		newQuotaReportCmd(client),
 	)
 	return cmd
 }
@@ -79,68 +81,20 @@ func newQuotaCreateCmd(client *master.MasterClient) *cobra.Command {
 	var maxBytes uint64
 
 	cmd := &cobra.Command{
-		Use:   cmdQuotaCreateUse,
-		Short: cmdQuotaCreateShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdQuotaCreateUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaCreateShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
-			var err error
 			volName := args[0]
 			fullPath := args[1]
 
-			metaConfig := &meta.MetaConfig{
-				Volume:  volName,
-				Masters: client.Nodes(),
-			}
-			metaWrapper, err := meta.NewMetaWrapper(metaConfig)
-			if err != nil {
-				stdout("NewMetaWrapper failed: %v\n", err)
-				return
-			}
 			fullPaths := strings.Split(fullPath, ",")
-			err = checkNestedDirectories(fullPaths)
+			quotaPathInofs, err := lookupQuotaPathInfos(client, volName, fullPaths)
 			if err != nil {
 				stdout("create quota failed, fullPaths %v error %v.\n", fullPaths, err)
 				return
 			}
-			if len(fullPaths) > 5 {
-				stdout("create quota failed, fullPath %v has more than 5 path.\n", fullPaths)
-				return
-			}
-			quotaPathInofs := make([]proto.QuotaPathInfo, 0)
-			for _, path := range fullPaths {
-				var quotaPathInfo proto.QuotaPathInfo
-				quotaPathInfo.FullPath = path
-
-				if !strings.HasPrefix(path, "/") {
-					stdout("create quota failed, path %v does not start with / \n", path)
-					return
-				}
-
-				inodeId, err := metaWrapper.LookupPath(path)
-				if err != nil {
-					stdout("get inode by fullPath %v fail %v\n", path, err)
-					return
-				}
-				quotaPathInfo.RootInode = inodeId
-				inodeInfo, err := metaWrapper.InodeGet_ll(inodeId)
-				if err != nil {
-					stdout("get inode %v info fail %v\n", inodeId, err)
-					return
-				}
-
-				if !proto.IsDir(inodeInfo.Mode) {
-					stdout("create quota failed, inode [%v] is not dir\n", inodeId)
-					return
-				}
-
-				mp := metaWrapper.GetPartitionByInodeId_ll(inodeId)
-				if mp == nil {
-					stdout("can not find mp by inodeId: %v\n", inodeId)
-					return
-				}
-				quotaPathInfo.PartitionId = mp.PartitionID
-				quotaPathInofs = append(quotaPathInofs, quotaPathInfo)
-			}
 			var quotaId uint32
 			if quotaId, err = client.AdminAPI().CreateQuota(volName, quotaPathInofs, maxFiles, maxBytes); err != nil {
 				stdout("volName %v path %v quota create failed(%v)\n", volName, fullPath, err)
@@ -155,11 +109,62 @@ func newQuotaCreateCmd(client *master.MasterClient) *cobra.Command {
 	return cmd
 }
 
+// lookupQuotaPathInfos checks the full paths are directories which can be limited by a quota,
+// and returns their root inodes and meta partitions.
+func lookupQuotaPathInfos(client *master.MasterClient, volName string, fullPaths []string) (quotaPathInofs []proto.QuotaPathInfo, err error) {
+	metaConfig := &meta.MetaConfig{
+		Volume:  volName,
+		Masters: client.Nodes(),
+	}
+	metaWrapper, err := meta.NewMetaWrapper(metaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("NewMetaWrapper failed: %v", err)
+	}
+	if err = checkNestedDirectories(fullPaths); err != nil {
+		return
+	}
+	if len(fullPaths) > 5 {
+		return nil, fmt.Errorf("fullPath %v has more than 5 path", fullPaths)
+	}
+	quotaPathInofs = make([]proto.QuotaPathInfo, 0)
+	for _, path := range fullPaths {
+		var quotaPathInfo proto.QuotaPathInfo
+		quotaPathInfo.FullPath = path
+
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("path %v does not start with /", path)
+		}
+
+		inodeId, err := metaWrapper.LookupPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("get inode by fullPath %v fail %v", path, err)
+		}
+		quotaPathInfo.RootInode = inodeId
+		inodeInfo, err := metaWrapper.InodeGet_ll(inodeId)
+		if err != nil {
+			return nil, fmt.Errorf("get inode %v info fail %v", inodeId, err)
+		}
+
+		if !proto.IsDir(inodeInfo.Mode) {
+			return nil, fmt.Errorf("inode [%v] is not dir", inodeId)
+		}
+
+		mp := metaWrapper.GetPartitionByInodeId_ll(inodeId)
+		if mp == nil {
+			return nil, fmt.Errorf("can not find mp by inodeId: %v", inodeId)
+		}
+		quotaPathInfo.PartitionId = mp.PartitionID
+		quotaPathInofs = append(quotaPathInofs, quotaPathInfo)
+	}
+	return
+}
+
 func newQuotaListCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   cmdQuotaListUse,
-		Short: cmdQuotaListShort,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               cmdQuotaListUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaListShort,
+		Args:              cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var quotas []*proto.QuotaInfo
 			var err error
@@ -171,11 +176,13 @@ func newQuotaListCmd(client *master.MasterClient) *cobra.Command {
 			sort.Slice(quotas, func(i, j int) bool {
 				return quotas[i].QuotaId < quotas[j].QuotaId
 			})
-			stdout("[quotas]\n")
-			stdout("%v\n", formatQuotaTableHeader())
-			for _, quotaInfo := range quotas {
-				stdout("%v\n", formatQuotaInfo(quotaInfo))
-			}
+			errout(render(quotas, func() {
+				stdout("[quotas]\n")
+				stdout("%v\n", formatQuotaTableHeader())
+				for _, quotaInfo := range quotas {
+					stdout("%v\n", formatQuotaInfo(quotaInfo))
+				}
+			}))
 		},
 	}
 	return cmd
@@ -194,10 +201,12 @@ func newQuotaListAllCmd(client *master.MasterClient) *cobra.Command {
 				stdout("quota list all failed(%v)\n", err)
 				return
 			}
-			stdout("%v\n", volumeInfoTableHeader)
-			for _, vol := range vols {
-				stdout("%v\n", formatVolInfoTableRow(vol))
-			}
+			errout(render(vols, func() {
+				stdout("%v\n", volumeInfoTableHeader)
+				for _, vol := range vols {
+					stdout("%v\n", formatVolInfoTableRow(vol))
+				}
+			}))
 		},
 	}
 
@@ -209,9 +218,10 @@ func newQuotaUpdateCmd(client *master.MasterClient) *cobra.Command {
 	var maxBytes uint64
 
 	cmd := &cobra.Command{
-		Use:   cmdQuotaUpdateUse,
-		Short: cmdQuotaUpdateShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdQuotaUpdateUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaUpdateShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			volName := args[0]
 			quotaId := args[1]
@@ -243,9 +253,10 @@ func newQuotaUpdateCmd(client *master.MasterClient) *cobra.Command {
 func newQuotaDelete(client *master.MasterClient) *cobra.Command {
 	var optYes bool
 	cmd := &cobra.Command{
-		Use:   cmdQuotaDeleteUse,
-		Short: cmdQUotaDeleteShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdQuotaDeleteUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQUotaDeleteShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			volName := args[0]
 			quotaId := args[1]
@@ -274,9 +285,10 @@ func newQuotaDelete(client *master.MasterClient) *cobra.Command {
 
 func newQuotaGetInode(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   cmdQuotaGetInodeUse,
-		Short: cmdQuotaGetInodeShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdQuotaGetInodeUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaGetInodeShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			volName := args[0]
 			inodeId, err := strconv.ParseUint(args[1], 10, 64)
@@ -300,9 +312,11 @@ func newQuotaGetInode(client *master.MasterClient) *cobra.Command {
 				stdout("get indoe quota failed %v\n", err)
 				return
 			}
-			for quotaId, quotaInfo := range quotaInfos {
-				stdout("quotaId [%v] quotaInfo [%v] \n", quotaId, quotaInfo)
-			}
+			errout(render(quotaInfos, func() {
+				for quotaId, quotaInfo := range quotaInfos {
+					stdout("quotaId [%v] quotaInfo [%v] \n", quotaId, quotaInfo)
+				}
+			}))
 		},
 	}
 	return cmd
@@ -311,9 +325,10 @@ func newQuotaGetInode(client *master.MasterClient) *cobra.Command {
 func newQuotaApplyCmd(client *master.MasterClient) *cobra.Command {
 	var maxConcurrencyInode uint64
 	cmd := &cobra.Command{
-		Use:   cmdQuotaApplyUse,
-		Short: cmdQuotaApplyShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdQuotaApplyUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaApplyShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			volName := args[0]
 			quotaId := args[1]
@@ -362,9 +377,10 @@ func newQuotaRevokeCmd(client *master.MasterClient) *cobra.Command {
 	var maxConcurrencyInode uint64
 	var forceInode uint64
 	cmd := &cobra.Command{
-		Use:   cmdQuotaRevokeUse,
-		Short: cmdQuotaRevokeShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdQuotaRevokeUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaRevokeShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			volName := args[0]
 			quotaId := args[1]
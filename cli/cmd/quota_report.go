@@ -0,0 +1,282 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cubefs/cubefs/cli/api"
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdQuotaSetUse      = "set [volname] [fullpath]"
+	cmdQuotaSetShort    = "set quota of a path, create it if the path has no quota"
+	cmdQuotaReportUse   = "report [volname]"
+	cmdQuotaReportShort = "report used files and bytes of quotas against limits"
+
+	defaultMetaNodeProfPort = 17220
+)
+
+func newQuotaSetCmd(client *master.MasterClient) *cobra.Command {
+	var maxFiles uint64
+	var maxBytes uint64
+
+	cmd := &cobra.Command{
+		Use:               cmdQuotaSetUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaSetShort,
+		Long: `Set max files and max bytes of the quota which limits the path. If no quota
+limits the path, a new quota is created and unset limits are unlimited, otherwise
+unset limits of the quota are not changed.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err    error
+				quotas []*proto.QuotaInfo
+			)
+			defer func() {
+				errout(err)
+			}()
+			volName := args[0]
+			fullPath := args[1]
+
+			if quotas, err = client.AdminAPI().ListQuota(volName); err != nil {
+				return
+			}
+			if quota := findPathQuota(quotas, fullPath); quota != nil {
+				if maxFiles == 0 {
+					maxFiles = quota.MaxFiles
+				}
+				if maxBytes == 0 {
+					maxBytes = quota.MaxBytes
+				}
+				quotaId := strconv.FormatUint(uint64(quota.QuotaId), 10)
+				if err = client.AdminAPI().UpdateQuota(volName, quotaId, maxFiles, maxBytes); err != nil {
+					return
+				}
+				stdout("updateQuota: volName %v quotaId %v paths %v maxFiles %v maxBytes %v success.\n",
+					volName, quotaId, quotaPaths(quota), maxFiles, maxBytes)
+				return
+			}
+
+			if maxFiles == 0 {
+				maxFiles = cmdQuotaDefaultMaxFiles
+			}
+			if maxBytes == 0 {
+				maxBytes = cmdQuotaDefaultMaxBytes
+			}
+			var (
+				quotaPathInfos []proto.QuotaPathInfo
+				quotaId        uint32
+			)
+			if quotaPathInfos, err = lookupQuotaPathInfos(client, volName, []string{fullPath}); err != nil {
+				return
+			}
+			if quotaId, err = client.AdminAPI().CreateQuota(volName, quotaPathInfos, maxFiles, maxBytes); err != nil {
+				return
+			}
+			stdout("createQuota: volName %v path %v maxFiles %v maxBytes %v quotaId %v success.\n",
+				volName, fullPath, maxFiles, maxBytes, quotaId)
+		},
+	}
+	cmd.Flags().Uint64Var(&maxFiles, CliFlagMaxFiles, 0, "Specify quota max files")
+	cmd.Flags().Uint64Var(&maxBytes, CliFlagMaxBytes, 0, "Specify quota max bytes")
+	return cmd
+}
+
+func findPathQuota(quotas []*proto.QuotaInfo, fullPath string) *proto.QuotaInfo {
+	for _, quota := range quotas {
+		for _, pathInfo := range quota.PathInfos {
+			if pathInfo.FullPath == fullPath {
+				return quota
+			}
+		}
+	}
+	return nil
+}
+
+func quotaPaths(quota *proto.QuotaInfo) []string {
+	paths := make([]string, 0, len(quota.PathInfos))
+	for _, pathInfo := range quota.PathInfos {
+		paths = append(paths, pathInfo.FullPath)
+	}
+	return paths
+}
+
+// quotaUsage used files and bytes of a quota summed from all meta partitions
+type quotaUsage struct {
+	QuotaId      uint32   `json:"quotaId"`
+	Paths        []string `json:"paths"`
+	UsedFiles    int64    `json:"usedFiles"`
+	MaxFiles     uint64   `json:"maxFiles"`
+	UsedBytes    int64    `json:"usedBytes"`
+	MaxBytes     uint64   `json:"maxBytes"`
+	LimitedFiles bool     `json:"limitedFiles"`
+	LimitedBytes bool     `json:"limitedBytes"`
+}
+
+type quotaReport struct {
+	VolName string        `json:"volName"`
+	Quotas  []*quotaUsage `json:"quotas"`
+	// FailedPartitions meta partitions whose usage can not be fetched, the usage is less than actual
+	FailedPartitions []uint64 `json:"failedPartitions,omitempty"`
+}
+
+func newQuotaReportCmd(client *master.MasterClient) *cobra.Command {
+	var optProfPort uint16
+
+	cmd := &cobra.Command{
+		Use:               cmdQuotaReportUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdQuotaReportShort,
+		Long: `Report used files and bytes of quotas of the volume against their limits.
+Usage is fetched from leaders of all meta partitions of the volume by the http
+port of meta nodes, which is specified by --prof-port.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err    error
+				quotas []*proto.QuotaInfo
+				views  []*proto.MetaPartitionView
+			)
+			defer func() {
+				errout(err)
+			}()
+			volName := args[0]
+
+			if quotas, err = client.AdminAPI().ListQuota(volName); err != nil {
+				return
+			}
+			if views, err = client.ClientAPI().GetMetaPartitions(volName); err != nil {
+				return
+			}
+			report := &quotaReport{VolName: volName}
+			partUsages := make([][]*proto.QuotaReportInfo, 0, len(views))
+			for _, view := range views {
+				if view.LeaderAddr == "" {
+					report.FailedPartitions = append(report.FailedPartitions, view.PartitionID)
+					continue
+				}
+				addr := nodeProfAddr(view.LeaderAddr, optProfPort)
+				infos, e := api.NewNodeHttpClient(addr).GetQuotaUsage(view.PartitionID)
+				if e != nil {
+					report.FailedPartitions = append(report.FailedPartitions, view.PartitionID)
+					continue
+				}
+				partUsages = append(partUsages, infos)
+			}
+			report.Quotas = sumQuotaUsage(quotas, partUsages)
+
+			err = render(report, func() {
+				stdout("%v", formatQuotaReport(report))
+			})
+			if err == nil && len(report.FailedPartitions) > 0 {
+				err = fmt.Errorf("failed to fetch usage of %v meta partitions %v", len(report.FailedPartitions),
+					report.FailedPartitions)
+			}
+		},
+	}
+	cmd.Flags().Uint16Var(&optProfPort, "prof-port", defaultMetaNodeProfPort, "Specify http port of meta nodes")
+	return cmd
+}
+
+// nodeProfAddr replaces the port of data node or meta node address by its http port
+func nodeProfAddr(addr string, profPort uint16) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(profPort)))
+}
+
+// sumQuotaUsage sums used info of quotas reported by meta partitions, sorted by quota id
+func sumQuotaUsage(quotas []*proto.QuotaInfo, partUsages [][]*proto.QuotaReportInfo) []*quotaUsage {
+	usages := make(map[uint32]*quotaUsage, len(quotas))
+	result := make([]*quotaUsage, 0, len(quotas))
+	for _, quota := range quotas {
+		usage := &quotaUsage{
+			QuotaId:      quota.QuotaId,
+			Paths:        quotaPaths(quota),
+			MaxFiles:     quota.MaxFiles,
+			MaxBytes:     quota.MaxBytes,
+			LimitedFiles: quota.LimitedInfo.LimitedFiles,
+			LimitedBytes: quota.LimitedInfo.LimitedBytes,
+		}
+		usages[quota.QuotaId] = usage
+		result = append(result, usage)
+	}
+	for _, infos := range partUsages {
+		for _, info := range infos {
+			usage, ok := usages[info.QuotaId]
+			if !ok {
+				continue
+			}
+			usage.UsedFiles += info.UsedInfo.UsedFiles
+			usage.UsedBytes += info.UsedInfo.UsedBytes
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].QuotaId < result[j].QuotaId })
+	return result
+}
+
+func formatQuotaLimit(limit uint64, format func(uint64) string) string {
+	if limit == math.MaxUint64 {
+		return "unlimited"
+	}
+	return format(limit)
+}
+
+func formatQuotaPercent(used int64, limit uint64) string {
+	if limit == 0 || limit == math.MaxUint64 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", float64(used)*100/float64(limit))
+}
+
+func formatQuotaReport(report *quotaReport) string {
+	count := func(n uint64) string { return strconv.FormatUint(n, 10) }
+	rows := table{arow("ID", "PATH", "USED FILES/MAX FILES", "FILES%", "USED BYTES/MAX BYTES", "BYTES%", "LIMITED")}
+	for _, usage := range report.Quotas {
+		limited := make([]string, 0, 2)
+		if usage.LimitedFiles {
+			limited = append(limited, "files")
+		}
+		if usage.LimitedBytes {
+			limited = append(limited, "bytes")
+		}
+		if len(limited) == 0 {
+			limited = append(limited, "no")
+		}
+		rows = rows.append(arow(usage.QuotaId, strings.Join(usage.Paths, ","),
+			fmt.Sprintf("%v/%v", usage.UsedFiles, formatQuotaLimit(usage.MaxFiles, count)),
+			formatQuotaPercent(usage.UsedFiles, usage.MaxFiles),
+			fmt.Sprintf("%v/%v", formatSize(uint64(usage.UsedBytes)), formatQuotaLimit(usage.MaxBytes, formatSize)),
+			formatQuotaPercent(usage.UsedBytes, usage.MaxBytes),
+			strings.Join(limited, ",")))
+	}
+	summary := fmt.Sprintf("Volume %v has %v quotas", report.VolName, len(report.Quotas))
+	if len(report.FailedPartitions) > 0 {
+		summary += fmt.Sprintf(", usage of %v meta partitions is missing", len(report.FailedPartitions))
+	}
+	return alignTable(rows...) + summary + "\n"
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliSumQuotaUsage(t *testing.T) {
+	quotas := []*proto.QuotaInfo{
+		{QuotaId: 2, PathInfos: []proto.QuotaPathInfo{{FullPath: "/b"}}, MaxFiles: 100, MaxBytes: math.MaxUint64},
+		{QuotaId: 1, PathInfos: []proto.QuotaPathInfo{{FullPath: "/a"}, {FullPath: "/c"}}, MaxFiles: 10, MaxBytes: 1024,
+			LimitedInfo: proto.QuotaLimitedInfo{LimitedFiles: true}},
+	}
+	partUsages := [][]*proto.QuotaReportInfo{
+		{{QuotaId: 1, UsedInfo: proto.QuotaUsedInfo{UsedFiles: 6, UsedBytes: 256}}},
+		{
+			{QuotaId: 1, UsedInfo: proto.QuotaUsedInfo{UsedFiles: 4, UsedBytes: 256}},
+			{QuotaId: 2, UsedInfo: proto.QuotaUsedInfo{UsedFiles: 50, UsedBytes: 4096}},
+			// deleted quota
+			{QuotaId: 3, UsedInfo: proto.QuotaUsedInfo{UsedFiles: 1, UsedBytes: 1}},
+		},
+	}
+	usages := sumQuotaUsage(quotas, partUsages)
+	require.Len(t, usages, 2)
+	require.Equal(t, uint32(1), usages[0].QuotaId)
+	require.Equal(t, []string{"/a", "/c"}, usages[0].Paths)
+	require.Equal(t, int64(10), usages[0].UsedFiles)
+	require.Equal(t, int64(512), usages[0].UsedBytes)
+	require.True(t, usages[0].LimitedFiles)
+	require.Equal(t, int64(50), usages[1].UsedFiles)
+
+	out := formatQuotaReport(&quotaReport{VolName: "vol", Quotas: usages, FailedPartitions: []uint64{3}})
+	require.Contains(t, out, "100.00%")
+	require.Contains(t, out, "50.00%")
+	require.Contains(t, out, "unlimited")
+	require.True(t, strings.HasSuffix(out, "usage of 1 meta partitions is missing\n"))
+}
+
+func TestCliFindPathQuota(t *testing.T) {
+	quotas := []*proto.QuotaInfo{
+		{QuotaId: 1, PathInfos: []proto.QuotaPathInfo{{FullPath: "/a"}, {FullPath: "/c"}}},
+	}
+	require.Equal(t, uint32(1), findPathQuota(quotas, "/c").QuotaId)
+	require.Nil(t, findPathQuota(quotas, "/b"))
+	require.Equal(t, "192.168.0.1:17220", nodeProfAddr("192.168.0.1:17210", 17220))
+}
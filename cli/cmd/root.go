@@ -59,6 +59,8 @@ func NewRootCmd(client *master.MasterClient) *CubeFSCmd {
 		},
 	}
 	cmd.CFSCmd.Flags().BoolVarP(&optShowVersion, "version", "v", false, "Show version information")
+	cmd.CFSCmd.PersistentFlags().StringVar(&optOutput, CliFlagOutput, outputTable,
+		fmt.Sprintf("Output format of list and info commands [%v|%v|%v]", outputTable, outputJSON, outputYAML))
 
 	// TODO: delete compatibility cmd at 49e62e794d7c1000c9fb09bd75565112ecd5c5e1.
 	// add back into Commands later ?
@@ -80,12 +82,20 @@ func NewRootCmd(client *master.MasterClient) *CubeFSCmd {
 		newQuotaCmd(client),
 		newDiskCmd(client),
 		newVersionCmd(client),
+		newSnapshotCmd(client),
+		newEventsCmd(client),
+		newBenchCmd(client),
+		newBlobstoreCmd(),
+		newConsoleCmd(client),
 	)
 	return cmd
 }
 
 var stdout = stdoutf
 
+// exit is replaced in console to keep the console running after a command fails
+var exit = os.Exit
+
 func stdoutln(a ...interface{}) {
 	fmt.Fprintln(os.Stdout, a...)
 }
@@ -106,5 +116,5 @@ func errout(err error) {
 	fmt.Fprintln(os.Stderr, "Error:", err)
 	log.LogError("Error:", err)
 	log.LogFlush()
-	os.Exit(1)
+	exit(1)
 }
@@ -0,0 +1,157 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"time"
+)
+
+// dangerous operations which are confirmed by token and audited
+const (
+	dangerousOpVolDelete            = "vol delete"
+	dangerousOpDataNodeDecommission = "datanode decommission"
+	dangerousOpMetaNodeDecommission = "metanode decommission"
+	dangerousOpZoneDisable          = "zone disable"
+)
+
+const (
+	defaultAuditName = ".cfs-cli-audit.log"
+	confirmTokenLen  = 3
+
+	auditResultSuccess = "success"
+	auditResultAborted = "aborted"
+	auditResultFailed  = "failed"
+)
+
+var defaultAuditPath = path.Join(defaultHomeDir, defaultAuditName)
+
+// confirmInput is read for confirmation tokens
+var confirmInput io.Reader = os.Stdin
+
+// SafetyConfig safeguards of dangerous operations
+type SafetyConfig struct {
+	// DisableConfirmToken dangerous operations are confirmed by yes/no instead of a token
+	DisableConfirmToken bool `json:"disableConfirmToken"`
+	// AuditFile records of dangerous operations, ~/.cfs-cli-audit.log by default
+	AuditFile string `json:"auditFile"`
+}
+
+type auditRecord struct {
+	Time   string `json:"time"`
+	User   string `json:"user"`
+	Op     string `json:"op"`
+	Target string `json:"target"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runDangerousOp runs op on target after it is confirmed by a generated token, and records it
+// to the audit file. Confirmation is skipped if yes, which is set by -y of the command. If token
+// is disabled by config, it is confirmed by yes/no when askYesNo.
+func runDangerousOp(op, target string, yes, askYesNo bool, run func() error) (err error) {
+	safety := SafetyConfig{}
+	if config, e := LoadConfig(); e == nil {
+		safety = config.Safety
+	}
+	record := &auditRecord{Op: op, Target: target}
+	defer func() {
+		if e := writeAuditRecord(safety.AuditFile, record); e != nil {
+			stdout("Write audit record failed: %v\n", e)
+		}
+	}()
+
+	if yes {
+		stdout("Confirmed by -y: %v [%v]\n", op, target)
+	} else if !safety.DisableConfirmToken {
+		err = confirmByToken(confirmInput, op, target, newConfirmToken())
+	} else if askYesNo {
+		err = confirmByYesNo(confirmInput, op, target)
+	}
+	if err != nil {
+		record.Result = auditResultAborted
+		return
+	}
+	if err = run(); err != nil {
+		record.Result, record.Error = auditResultFailed, err.Error()
+		return
+	}
+	record.Result = auditResultSuccess
+	return
+}
+
+func newConfirmToken() string {
+	buf := make([]byte, confirmTokenLen)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%06x", time.Now().UnixNano()&0xffffff)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func confirmByToken(in io.Reader, op, target, token string) error {
+	stdout("This is a dangerous operation: %v [%v]\n", op, target)
+	stdout("Type the token %v to confirm:", token)
+	var input string
+	_, _ = fmt.Fscanln(in, &input)
+	if input != token {
+		return fmt.Errorf("Abort by user, token mismatch.\n")
+	}
+	return nil
+}
+
+func confirmByYesNo(in io.Reader, op, target string) error {
+	stdout("%v [%v] (yes/no)[no]:", op, target)
+	var input string
+	_, _ = fmt.Fscanln(in, &input)
+	if input != "yes" {
+		return fmt.Errorf("Abort by user.\n")
+	}
+	return nil
+}
+
+func writeAuditRecord(file string, record *auditRecord) (err error) {
+	if file == "" {
+		file = defaultAuditPath
+	}
+	record.Time = time.Now().Format(time.RFC3339)
+	record.User = currentUserName()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return
+	}
+	return f.Close()
+}
+
+func currentUserName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliConfirmByToken(t *testing.T) {
+	token := newConfirmToken()
+	require.Len(t, token, confirmTokenLen*2)
+	require.NotEqual(t, token, newConfirmToken())
+
+	require.NoError(t, confirmByToken(strings.NewReader(token+"\n"), dangerousOpVolDelete, "vol", token))
+	require.Error(t, confirmByToken(strings.NewReader("yes\n"), dangerousOpVolDelete, "vol", token))
+	require.Error(t, confirmByToken(strings.NewReader(""), dangerousOpVolDelete, "vol", token))
+
+	require.NoError(t, confirmByYesNo(strings.NewReader("yes\n"), dangerousOpVolDelete, "vol"))
+	require.Error(t, confirmByYesNo(strings.NewReader("no\n"), dangerousOpVolDelete, "vol"))
+}
+
+func TestCliRunDangerousOp(t *testing.T) {
+	auditPath, input := defaultAuditPath, confirmInput
+	defer func() { defaultAuditPath, confirmInput = auditPath, input }()
+	defaultAuditPath = path.Join(t.TempDir(), "audit.log")
+	confirmInput = strings.NewReader("")
+
+	// confirmation is skipped by -y
+	ran := false
+	require.NoError(t, runDangerousOp(dangerousOpVolDelete, "vol1", true, true, func() error {
+		ran = true
+		return nil
+	}))
+	require.True(t, ran)
+
+	ran = false
+	require.Error(t, runDangerousOp(dangerousOpVolDelete, "vol1", false, true, func() error {
+		ran = true
+		return nil
+	}))
+	require.False(t, ran)
+
+	data, err := os.ReadFile(defaultAuditPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], auditResultSuccess)
+	require.Contains(t, lines[1], auditResultAborted)
+}
+
+func TestCliWriteAuditRecord(t *testing.T) {
+	file := path.Join(t.TempDir(), "audit.log")
+	require.NoError(t, writeAuditRecord(file, &auditRecord{Op: dangerousOpVolDelete, Target: "vol1", Result: auditResultSuccess}))
+	require.NoError(t, writeAuditRecord(file, &auditRecord{Op: dangerousOpZoneDisable, Target: "zone1", Result: auditResultAborted}))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	record := &auditRecord{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), record))
+	require.Equal(t, dangerousOpZoneDisable, record.Op)
+	require.Equal(t, "zone1", record.Target)
+	require.Equal(t, auditResultAborted, record.Result)
+	require.NotEmpty(t, record.Time)
+}
@@ -0,0 +1,318 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cubefs/cubefs/cli/api"
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdSnapshotUse         = "snapshot [COMMAND]"
+	cmdSnapshotShort       = "Manage snapshots of volumes"
+	cmdSnapshotCreateShort = "Create a snapshot of the volume"
+	cmdSnapshotListShort   = "List snapshots of the volume"
+	cmdSnapshotDeleteShort = "Delete a snapshot of the volume"
+	cmdSnapshotDiffShort   = "Diff count of files and directories between snapshots"
+
+	snapshotLatest = "latest"
+)
+
+func newSnapshotCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdSnapshotUse,
+		Short: cmdSnapshotShort,
+		Long: `Manage snapshots of volumes, which are versions of metadata of the volume.
+Snapshots are read by mounting the volume with the version of the snapshot.`,
+		Args: cobra.MinimumNArgs(0),
+	}
+	cmd.AddCommand(
+		newSnapshotCreateCmd(client),
+		newSnapshotListCmd(client),
+		newSnapshotDeleteCmd(client),
+		newSnapshotDiffCmd(client),
+	)
+	return cmd
+}
+
+func newSnapshotCreateCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   CliOpCreate + " [VOLUME]",
+		Short: cmdSnapshotCreateShort,
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err error
+				ver *proto.VolVersionInfo
+			)
+			defer func() {
+				errout(err)
+			}()
+			if ver, err = client.AdminAPI().CreateVersion(args[0]); err != nil {
+				return
+			}
+			err = render(ver, func() {
+				stdout("Snapshot %v of volume %v is created at %v.\n", ver.Ver, args[0], formatSnapshotTime(ver.Ver))
+			})
+		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+	}
+	return cmd
+}
+
+func newSnapshotListCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   CliOpList + " [VOLUME]",
+		Short: cmdSnapshotListShort,
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err   error
+				snaps []*proto.VolVersionInfo
+			)
+			defer func() {
+				errout(err)
+			}()
+			if snaps, err = listSnapshots(client, args[0]); err != nil {
+				return
+			}
+			err = render(snaps, func() {
+				rows := table{arow("SNAPSHOT", "CTIME", "STATUS")}
+				for _, snap := range snaps {
+					rows = rows.append(arow(snap.Ver, formatSnapshotTime(snap.Ver), formatSnapshotStatus(snap.Status)))
+				}
+				stdout("%v", alignTable(rows...))
+			})
+		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+	}
+	return cmd
+}
+
+func newSnapshotDeleteCmd(client *master.MasterClient) *cobra.Command {
+	var optYes bool
+	cmd := &cobra.Command{
+		Use:   CliOpDelete + " [VOLUME] [SNAPSHOT]",
+		Short: cmdSnapshotDeleteShort,
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err   error
+				snaps []*proto.VolVersionInfo
+				ver   uint64
+			)
+			defer func() {
+				errout(err)
+			}()
+			volName := args[0]
+			if snaps, err = listSnapshots(client, volName); err != nil {
+				return
+			}
+			if ver, err = parseSnapshotVer(snaps, args[1], false); err != nil {
+				return
+			}
+			if !optYes {
+				stdout("Delete snapshot %v of volume %v created at %v, files in it can not be recovered (yes/no)[no]:",
+					ver, volName, formatSnapshotTime(ver))
+				var userConfirm string
+				_, _ = fmt.Scanln(&userConfirm)
+				if userConfirm != "yes" {
+					err = fmt.Errorf("Abort by user.\n")
+					return
+				}
+			}
+			if err = client.AdminAPI().DeleteVersion(volName, strconv.FormatUint(ver, 10)); err != nil {
+				return
+			}
+			stdout("Snapshot %v of volume %v is being deleted.\n", ver, volName)
+		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+	}
+	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
+	return cmd
+}
+
+// snapshotCount count of files and directories in a snapshot summed from all meta partitions
+type snapshotCount struct {
+	Snapshot string `json:"snapshot"`
+	Files    uint64 `json:"files"`
+	Dirs     uint64 `json:"dirs"`
+	// FailedPartitions meta partitions whose count can not be fetched, the count is less than actual
+	FailedPartitions []uint64 `json:"failedPartitions,omitempty"`
+}
+
+func newSnapshotDiffCmd(client *master.MasterClient) *cobra.Command {
+	var optProfPort uint16
+	cmd := &cobra.Command{
+		Use:   "diff [VOLUME] [SNAPSHOT] [SNAPSHOT]",
+		Short: cmdSnapshotDiffShort,
+		Long: `Diff count of files and directories between two snapshots of the volume, the
+second snapshot is the latest metadata if it is omitted or '` + snapshotLatest + `'. Counts are
+fetched from leaders of all meta partitions of the volume by the http port of meta
+nodes, which is specified by --prof-port.`,
+		Args: cobra.RangeArgs(2, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err      error
+				snaps    []*proto.VolVersionInfo
+				views    []*proto.MetaPartitionView
+				from, to uint64
+			)
+			defer func() {
+				errout(err)
+			}()
+			volName := args[0]
+			if snaps, err = listSnapshots(client, volName); err != nil {
+				return
+			}
+			if from, err = parseSnapshotVer(snaps, args[1], true); err != nil {
+				return
+			}
+			if len(args) > 2 {
+				if to, err = parseSnapshotVer(snaps, args[2], true); err != nil {
+					return
+				}
+			}
+			if views, err = client.ClientAPI().GetMetaPartitions(volName); err != nil {
+				return
+			}
+			counts := []*snapshotCount{
+				countSnapshotInodes(views, from, optProfPort),
+				countSnapshotInodes(views, to, optProfPort),
+			}
+			err = render(counts, func() {
+				stdout("%v", formatSnapshotDiff(counts[0], counts[1]))
+			})
+			if err == nil && (len(counts[0].FailedPartitions) > 0 || len(counts[1].FailedPartitions) > 0) {
+				err = fmt.Errorf("failed to fetch count of meta partitions %v %v",
+					counts[0].FailedPartitions, counts[1].FailedPartitions)
+			}
+		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+	}
+	cmd.Flags().Uint16Var(&optProfPort, "prof-port", defaultMetaNodeProfPort, "Specify http port of meta nodes")
+	return cmd
+}
+
+// listSnapshots returns snapshots of the volume, the version being written is not a snapshot
+func listSnapshots(client *master.MasterClient, volName string) (snaps []*proto.VolVersionInfo, err error) {
+	var verList *proto.VolVersionInfoList
+	if verList, err = client.AdminAPI().GetVerList(volName); err != nil {
+		return
+	}
+	snaps = verList.VerList
+	if len(snaps) > 0 {
+		snaps = snaps[:len(snaps)-1]
+	}
+	return
+}
+
+// parseSnapshotVer returns version of snapshot, which is 0 for the latest if allowed
+func parseSnapshotVer(snaps []*proto.VolVersionInfo, arg string, allowLatest bool) (uint64, error) {
+	if allowLatest && arg == snapshotLatest {
+		return 0, nil
+	}
+	ver, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid snapshot %v: %v", arg, err)
+	}
+	for _, snap := range snaps {
+		if snap.Ver != ver {
+			continue
+		}
+		if snap.Status != proto.VersionNormal {
+			return 0, fmt.Errorf("snapshot %v is %v", ver, formatSnapshotStatus(snap.Status))
+		}
+		return ver, nil
+	}
+	return 0, fmt.Errorf("snapshot %v not found", ver)
+}
+
+func countSnapshotInodes(views []*proto.MetaPartitionView, ver uint64, profPort uint16) *snapshotCount {
+	count := &snapshotCount{Snapshot: formatSnapshotName(ver)}
+	for _, view := range views {
+		if view.LeaderAddr == "" {
+			count.FailedPartitions = append(count.FailedPartitions, view.PartitionID)
+			continue
+		}
+		files, dirs, err := api.NewNodeHttpClient(nodeProfAddr(view.LeaderAddr, profPort)).
+			GetInodeCount(view.PartitionID, ver)
+		if err != nil {
+			count.FailedPartitions = append(count.FailedPartitions, view.PartitionID)
+			continue
+		}
+		count.Files += files
+		count.Dirs += dirs
+	}
+	return count
+}
+
+func formatSnapshotName(ver uint64) string {
+	if ver == 0 {
+		return snapshotLatest
+	}
+	return strconv.FormatUint(ver, 10)
+}
+
+// formatSnapshotTime returns create time of snapshot, which is the unix micro seconds of version
+func formatSnapshotTime(ver uint64) string {
+	return time.UnixMicro(int64(ver)).Local().Format(time.RFC1123)
+}
+
+func formatSnapshotStatus(status uint8) string {
+	switch status {
+	case proto.VersionNormal:
+		return "Normal"
+	case proto.VersionDeleted:
+		return "Deleted"
+	case proto.VersionDeleting:
+		return "Deleting"
+	case proto.VersionDeleteAbnormal:
+		return "DeleteAbnormal"
+	case proto.VersionPrepare:
+		return "Prepare"
+	default:
+		return fmt.Sprintf("Unknown(%v)", status)
+	}
+}
+
+func formatCountDelta(from, to uint64) string {
+	if to >= from {
+		return fmt.Sprintf("+%v", to-from)
+	}
+	return fmt.Sprintf("-%v", from-to)
+}
+
+func formatSnapshotDiff(from, to *snapshotCount) string {
+	rows := table{
+		arow("SNAPSHOT", "FILES", "DIRS"),
+		arow(from.Snapshot, from.Files, from.Dirs),
+		arow(to.Snapshot, to.Files, to.Dirs),
+		arow("DIFF", formatCountDelta(from.Files, to.Files), formatCountDelta(from.Dirs, to.Dirs)),
+	}
+	out := alignTable(rows...)
+	for _, count := range []*snapshotCount{from, to} {
+		if len(count.FailedPartitions) > 0 {
+			out += fmt.Sprintf("Count of %v meta partitions is missing in %v\n", len(count.FailedPartitions), count.Snapshot)
+		}
+	}
+	return out
+}
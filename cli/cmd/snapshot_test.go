@@ -0,0 +1,54 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliParseSnapshotVer(t *testing.T) {
+	snaps := []*proto.VolVersionInfo{
+		{Ver: 100, Status: proto.VersionNormal},
+		{Ver: 200, Status: proto.VersionDeleting},
+	}
+	ver, err := parseSnapshotVer(snaps, "100", false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), ver)
+
+	ver, err = parseSnapshotVer(snaps, snapshotLatest, true)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), ver)
+
+	_, err = parseSnapshotVer(snaps, snapshotLatest, false)
+	require.Error(t, err)
+	_, err = parseSnapshotVer(snaps, "200", false)
+	require.Error(t, err)
+	_, err = parseSnapshotVer(snaps, "300", false)
+	require.Error(t, err)
+}
+
+func TestCliFormatSnapshotDiff(t *testing.T) {
+	from := &snapshotCount{Snapshot: "100", Files: 10, Dirs: 5}
+	to := &snapshotCount{Snapshot: snapshotLatest, Files: 15, Dirs: 3, FailedPartitions: []uint64{2}}
+	out := formatSnapshotDiff(from, to)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 5)
+	require.Equal(t, []string{"DIFF", "|", "+5", "|", "-2"}, strings.Fields(lines[3]))
+	require.Contains(t, lines[4], "1 meta partitions is missing in latest")
+}
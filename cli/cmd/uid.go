@@ -44,9 +44,10 @@ func newUidCmd(client *master.MasterClient) *cobra.Command {
 func newUidAddCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     CliUidAdd,
-		Short:   cmdUidAddShort,
-		Aliases: []string{"add"},
+		Use:               CliUidAdd,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdUidAddShort,
+		Aliases:           []string{"add"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) < 3 {
 				stdout("example:cfs-cli uid add volName uid size\n")
@@ -73,9 +74,10 @@ func newUidListCmd(client *master.MasterClient) *cobra.Command {
 		uidListAll bool
 	)
 	cmd := &cobra.Command{
-		Use:     cliUidListShort,
-		Short:   cmdUidListShort,
-		Aliases: []string{"list"},
+		Use:               cliUidListShort,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdUidListShort,
+		Aliases:           []string{"list"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
 				stdout("need volume name\n")
@@ -96,13 +98,19 @@ func newUidListCmd(client *master.MasterClient) *cobra.Command {
 				stdout("UidOperation return \n")
 				return
 			}
-			stdout("%v\n", volumeUidTableHeader)
+			uids := make([]*proto.UidSpaceInfo, 0, len(uidInfo.UidSpaceArr))
 			for _, info := range uidInfo.UidSpaceArr {
 				if !uidListAll && !info.Enabled {
 					continue
 				}
-				stdout("%v\n", formatUidInfoTableRow(info))
+				uids = append(uids, info)
 			}
+			err = render(uids, func() {
+				stdout("%v\n", volumeUidTableHeader)
+				for _, info := range uids {
+					stdout("%v\n", formatUidInfoTableRow(info))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optKeyword, "keyword", "", "Specify keyword of volume name to filter")
@@ -112,9 +120,10 @@ func newUidListCmd(client *master.MasterClient) *cobra.Command {
 func newUidDelCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     CliUidDel,
-		Short:   cmdUidDelShort,
-		Aliases: []string{"del"},
+		Use:               CliUidDel,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdUidDelShort,
+		Aliases:           []string{"del"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) <= 1 {
 				stdout("USAGE:./cfs-cli uid uidDel volName\n")
@@ -139,9 +148,10 @@ func newUidDelCmd(client *master.MasterClient) *cobra.Command {
 func newUidCheckCmd(client *master.MasterClient) *cobra.Command {
 	var optKeyword string
 	cmd := &cobra.Command{
-		Use:     CliUidCheck,
-		Short:   cmdUidCheckShort,
-		Aliases: []string{"check"},
+		Use:               CliUidCheck,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdUidCheckShort,
+		Aliases:           []string{"check"},
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) <= 1 {
 				stdout("USAGE:./cfs-cli uid uidCheck volName\n")
@@ -146,9 +146,10 @@ func newUserUpdateCmd(client *master.MasterClient) *cobra.Command {
 	var clientIDKey string
 	var optYes bool
 	cmd := &cobra.Command{
-		Use:   cmdUserUpdateUse,
-		Short: cmdUserUpdateShort,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               cmdUserUpdateUse,
+		ValidArgsFunction: validArgsFunc(client, validUsers),
+		Short:             cmdUserUpdateShort,
+		Args:              cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			userID := args[0]
@@ -254,12 +255,7 @@ func newUserDeleteCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdout("Delete user success.\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validUsers(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validUsers),
 	}
 	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -288,14 +284,11 @@ func newUserInfoCmd(client *master.MasterClient) *cobra.Command {
 				err = fmt.Errorf("Get user info failed: %v\n", err)
 				return
 			}
-			printUserInfo(userInfo)
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validUsers(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+			err = render(userInfo, func() {
+				printUserInfo(userInfo)
+			})
 		},
+		ValidArgsFunction: validArgsFunc(client, validUsers),
 	}
 
 	return cmd
@@ -369,12 +362,7 @@ func newUserPermCmd(client *master.MasterClient) *cobra.Command {
 			}
 			printUserInfo(userInfo)
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validUsers(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, validUsers, completeVols),
 	}
 	cmd.Flags().StringVar(&subdir, "subdir", "", "Subdir")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -400,10 +388,12 @@ func newUserListCmd(client *master.MasterClient) *cobra.Command {
 			if users, err = client.UserAPI().ListUsers(optKeyword); err != nil {
 				return
 			}
-			stdout("%v\n", userInfoTableHeader)
-			for _, user := range users {
-				stdout("%v\n", formatUserInfoTableRow(user))
-			}
+			err = render(users, func() {
+				stdout("%v\n", userInfoTableHeader)
+				for _, user := range users {
+					stdout("%v\n", formatUserInfoTableRow(user))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optKeyword, "keyword", "", "Specify keyword of user name to filter")
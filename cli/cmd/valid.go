@@ -15,84 +15,188 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/cubefs/cubefs/proto"
 	sdk "github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+)
+
+const (
+	completionCacheName = ".cfs-cli-completion-cache.json"
+	completionCacheTTL  = 30 * time.Second
+
+	completionVols           = "vols"
+	completionDataNodes      = "datanodes"
+	completionMetaNodes      = "metanodes"
+	completionUsers          = "users"
+	completionZones          = "zones"
+	completionDataPartitions = "datapartitions"
 )
 
+var completionCachePath = path.Join(defaultHomeDir, completionCacheName)
+
+// completeFunc returns completion candidates of a positional argument
+type completeFunc func(client *sdk.MasterClient, toComplete string) []string
+
+// validArgsFunc returns a cobra ValidArgsFunction which completes
+// the positional arguments in order, nil completes nothing.
+func validArgsFunc(client *sdk.MasterClient, completes ...completeFunc) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(completes) || completes[len(args)] == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completes[len(args)](client, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+type completionCacheEntry struct {
+	Time   int64    `json:"time"`
+	Values []string `json:"values"`
+}
+
+// completionCandidates returns candidates with prefix toComplete.
+// Every completion runs in a new process, so candidates are cached in file
+// for a short time to keep tab-completion fast on big clusters.
+func completionCandidates(client *sdk.MasterClient, kind, toComplete string, fetch func() ([]string, error)) []string {
+	key := strings.Join(client.Nodes(), ",") + "/" + kind
+	cache := make(map[string]completionCacheEntry)
+	if data, err := os.ReadFile(completionCachePath); err == nil {
+		if err = json.Unmarshal(data, &cache); err != nil {
+			cache = make(map[string]completionCacheEntry)
+		}
+	}
+
+	now := time.Now()
+	entry, ok := cache[key]
+	if !ok || now.Sub(time.Unix(entry.Time, 0)) > completionCacheTTL {
+		values, err := fetch()
+		if err != nil {
+			return nil
+		}
+		entry = completionCacheEntry{Time: now.Unix(), Values: values}
+		cache[key] = entry
+		for k, e := range cache {
+			if now.Sub(time.Unix(e.Time, 0)) > completionCacheTTL {
+				delete(cache, k)
+			}
+		}
+		if data, err := json.Marshal(cache); err == nil {
+			os.WriteFile(completionCachePath, data, 0o600) // nolint: errcheck
+		}
+	}
+
+	candidates := make([]string, 0, len(entry.Values))
+	for _, value := range entry.Values {
+		if strings.HasPrefix(value, toComplete) {
+			candidates = append(candidates, value)
+		}
+	}
+	return candidates
+}
+
 func validVols(client, complete interface{}) []string {
-	var (
-		validVols []string
-		vols      []*proto.VolInfo
-		err       error
-	)
 	clientSdk := client.(*sdk.MasterClient)
 	completeStr := complete.(string)
-	if vols, err = clientSdk.AdminAPI().ListVols(completeStr); err != nil {
-		errout(err)
-	}
-	for _, vol := range vols {
-		validVols = append(validVols, vol.Name)
-	}
-	return validVols
+	return completionCandidates(clientSdk, completionVols, completeStr, func() ([]string, error) {
+		vols, err := clientSdk.AdminAPI().ListVols("")
+		if err != nil {
+			return nil, err
+		}
+		validVols := make([]string, 0, len(vols))
+		for _, vol := range vols {
+			validVols = append(validVols, vol.Name)
+		}
+		return validVols, nil
+	})
 }
 
 func validDataNodes(client *sdk.MasterClient, toComplete string) []string {
-	var (
-		validDataNodes []string
-		clusterView    *proto.ClusterView
-
-		err error
-	)
-	if clusterView, err = client.AdminAPI().GetCluster(); err != nil {
-		errout(err)
-	}
-	for _, dn := range clusterView.DataNodes {
-		validDataNodes = append(validDataNodes, dn.Addr)
-	}
-	return validDataNodes
+	return completionCandidates(client, completionDataNodes, toComplete, func() ([]string, error) {
+		clusterView, err := client.AdminAPI().GetCluster()
+		if err != nil {
+			return nil, err
+		}
+		validDataNodes := make([]string, 0, len(clusterView.DataNodes))
+		for _, dn := range clusterView.DataNodes {
+			validDataNodes = append(validDataNodes, dn.Addr)
+		}
+		return validDataNodes, nil
+	})
 }
 
 func validMetaNodes(client *sdk.MasterClient, toComplete string) []string {
-	var (
-		validMetaNodes []string
-		clusterView    *proto.ClusterView
-		err            error
-	)
-	if clusterView, err = client.AdminAPI().GetCluster(); err != nil {
-		errout(err)
-	}
-	for _, mn := range clusterView.MetaNodes {
-		validMetaNodes = append(validMetaNodes, mn.Addr)
-	}
-	return validMetaNodes
+	return completionCandidates(client, completionMetaNodes, toComplete, func() ([]string, error) {
+		clusterView, err := client.AdminAPI().GetCluster()
+		if err != nil {
+			return nil, err
+		}
+		validMetaNodes := make([]string, 0, len(clusterView.MetaNodes))
+		for _, mn := range clusterView.MetaNodes {
+			validMetaNodes = append(validMetaNodes, mn.Addr)
+		}
+		return validMetaNodes, nil
+	})
 }
 
 func validUsers(client *sdk.MasterClient, toComplete string) []string {
-	var (
-		validUsers []string
-		users      []*proto.UserInfo
-		err        error
-	)
-	if users, err = client.UserAPI().ListUsers(toComplete); err != nil {
-		errout(err)
-	}
-	for _, user := range users {
-		validUsers = append(validUsers, user.UserID)
-	}
-	return validUsers
+	return completionCandidates(client, completionUsers, toComplete, func() ([]string, error) {
+		users, err := client.UserAPI().ListUsers("")
+		if err != nil {
+			return nil, err
+		}
+		validUsers := make([]string, 0, len(users))
+		for _, user := range users {
+			validUsers = append(validUsers, user.UserID)
+		}
+		return validUsers, nil
+	})
 }
 
 func validZones(client *sdk.MasterClient, toComplete string) []string {
-	var (
-		validZones []string
-		zones      []*proto.ZoneView
-		err        error
-	)
-	if zones, err = client.AdminAPI().ListZones(); err != nil {
-		errout(err)
-	}
-	for _, zone := range zones {
-		validZones = append(validZones, zone.Name)
-	}
-	return validZones
+	return completionCandidates(client, completionZones, toComplete, func() ([]string, error) {
+		zones, err := client.AdminAPI().ListZones()
+		if err != nil {
+			return nil, err
+		}
+		validZones := make([]string, 0, len(zones))
+		for _, zone := range zones {
+			validZones = append(validZones, zone.Name)
+		}
+		return validZones, nil
+	})
+}
+
+// validDataPartitions returns data partition ids of all volumes
+func validDataPartitions(client *sdk.MasterClient, toComplete string) []string {
+	return completionCandidates(client, completionDataPartitions, toComplete, func() ([]string, error) {
+		var (
+			vols []*proto.VolInfo
+			view *proto.DataPartitionsView
+			err  error
+		)
+		if vols, err = client.AdminAPI().ListVols(""); err != nil {
+			return nil, err
+		}
+		validDataPartitions := make([]string, 0)
+		for _, vol := range vols {
+			if view, err = client.ClientAPI().EncodingGzip().GetDataPartitions(vol.Name); err != nil {
+				return nil, err
+			}
+			for _, dp := range view.DataPartitions {
+				validDataPartitions = append(validDataPartitions, strconv.FormatUint(dp.PartitionID, 10))
+			}
+		}
+		return validDataPartitions, nil
+	})
+}
+
+// completeVols completes volumes as a positional argument
+func completeVols(client *sdk.MasterClient, toComplete string) []string {
+	return validVols(client, toComplete)
 }
@@ -0,0 +1,62 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	sdk "github.com/cubefs/cubefs/sdk/master"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliCompletionCandidates(t *testing.T) {
+	completionCachePath = path.Join(t.TempDir(), completionCacheName)
+	client := sdk.NewMasterClient([]string{"127.0.0.1:17010"}, false)
+
+	fetched := 0
+	fetch := func() ([]string, error) {
+		fetched++
+		return []string{"vol1", "vol2", "abc"}, nil
+	}
+	require.Equal(t, []string{"vol1", "vol2"}, completionCandidates(client, completionVols, "vol", fetch))
+	require.Equal(t, []string{"abc"}, completionCandidates(client, completionVols, "a", fetch))
+	require.Equal(t, 1, fetched)
+
+	failed := func() ([]string, error) { return nil, errors.New("failed") }
+	require.Empty(t, completionCandidates(client, completionUsers, "", failed))
+
+	other := sdk.NewMasterClient([]string{"127.0.0.2:17010"}, false)
+	require.Len(t, completionCandidates(other, completionVols, "", fetch), 3)
+	require.Equal(t, 2, fetched)
+}
+
+func TestCliValidArgsFunc(t *testing.T) {
+	first := func(client *sdk.MasterClient, toComplete string) []string { return []string{"first"} }
+	second := func(client *sdk.MasterClient, toComplete string) []string { return []string{"second"} }
+	fn := validArgsFunc(nil, first, nil, second)
+
+	values, directive := fn(nil, nil, "")
+	require.Equal(t, []string{"first"}, values)
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	values, _ = fn(nil, []string{"a"}, "")
+	require.Nil(t, values)
+	values, _ = fn(nil, []string{"a", "b"}, "")
+	require.Equal(t, []string{"second"}, values)
+	values, _ = fn(nil, []string{"a", "b", "c"}, "")
+	require.Nil(t, values)
+}
@@ -90,13 +90,17 @@ func newVersionListCmd(client *master.MasterClient) *cobra.Command {
 			if verList, err = client.AdminAPI().GetVerList(volumeName); err != nil {
 				return
 			}
-			stdout("%v\n", volumeVersionTableHeader)
-			for id, ver := range verList.VerList {
-				if id == len(verList.VerList)-1 {
-					break
-				}
-				stdout("%v\n", formatVerInfoTableRow(ver))
+			// the last one is the current version being written
+			vers := verList.VerList
+			if len(vers) > 0 {
+				vers = vers[:len(vers)-1]
 			}
+			err = render(vers, func() {
+				stdout("%v\n", volumeVersionTableHeader)
+				for _, ver := range vers {
+					stdout("%v\n", formatVerInfoTableRow(ver))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optKeyword, "keyword", "", "Specify keyword of volume name to filter")
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/sdk/meta"
 	"github.com/cubefs/cubefs/util"
 	"github.com/spf13/cobra"
 )
@@ -51,6 +52,10 @@ func newVolCmd(client *master.MasterClient) *cobra.Command {
 		newVolAddMPCmd(client),
 		newVolSetForbiddenCmd(client),
 		newVolSetAuditLogCmd(client),
+		newVolSetQosLimitCmd(client),
+		newVolDuCmd(client),
+		newVolPlanCmd(client),
+		newVolTrashCmd(client),
 	)
 	return cmd
 }
@@ -74,10 +79,12 @@ func newVolListCmd(client *master.MasterClient) *cobra.Command {
 			if vols, err = client.AdminAPI().ListVols(optKeyword); err != nil {
 				return
 			}
-			stdout("%v\n", volumeInfoTableHeader)
-			for _, vol := range vols {
-				stdout("%v\n", formatVolInfoTableRow(vol))
-			}
+			err = render(vols, func() {
+				stdout("%v\n", volumeInfoTableHeader)
+				for _, vol := range vols {
+					stdout("%v\n", formatVolInfoTableRow(vol))
+				}
+			})
 		},
 	}
 	cmd.Flags().StringVar(&optKeyword, "keyword", "", "Specify keyword of volume name to filter")
@@ -138,9 +145,10 @@ func newVolCreateCmd(client *master.MasterClient) *cobra.Command {
 	var clientIDKey string
 	var optYes bool
 	cmd := &cobra.Command{
-		Use:   cmdVolCreateUse,
-		Short: cmdVolCreateShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdVolCreateUse,
+		ValidArgsFunction: validArgsFunc(client, nil, validUsers),
+		Short:             cmdVolCreateShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			volumeName := args[0]
@@ -287,6 +295,8 @@ func newVolUpdateCmd(client *master.MasterClient) *cobra.Command {
 	var optReplicaNum string
 	var optDeleteLockTime int64
 	var optEnableQuota string
+	var optInlineDirThreshold int
+	var optClientQpsLimit int
 	confirmString := strings.Builder{}
 	var vv *proto.SimpleVolView
 	cmd := &cobra.Command{
@@ -405,6 +415,22 @@ func newVolUpdateCmd(client *master.MasterClient) *cobra.Command {
 				confirmString.WriteString(fmt.Sprintf("  DeleteLockTime            : %v h\n", vv.DeleteLockTime))
 			}
 
+			if optInlineDirThreshold >= 0 && optInlineDirThreshold != vv.InlineDirThreshold {
+				isChange = true
+				confirmString.WriteString(fmt.Sprintf("  InlineDirThreshold        : %v -> %v\n", vv.InlineDirThreshold, optInlineDirThreshold))
+				vv.InlineDirThreshold = optInlineDirThreshold
+			} else {
+				confirmString.WriteString(fmt.Sprintf("  InlineDirThreshold        : %v\n", vv.InlineDirThreshold))
+			}
+
+			if optClientQpsLimit >= 0 && optClientQpsLimit != vv.ClientQpsLimit {
+				isChange = true
+				confirmString.WriteString(fmt.Sprintf("  ClientQpsLimit            : %v -> %v\n", vv.ClientQpsLimit, optClientQpsLimit))
+				vv.ClientQpsLimit = optClientQpsLimit
+			} else {
+				confirmString.WriteString(fmt.Sprintf("  ClientQpsLimit            : %v\n", vv.ClientQpsLimit))
+			}
+
 			// var maskStr string
 			if optTxMask != "" {
 				var oldMask, newMask proto.TxOpMask
@@ -596,12 +622,7 @@ func newVolUpdateCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdout("Volume configuration has been update successfully.\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validVols(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
 	}
 	cmd.Flags().StringVar(&optDescription, CliFlagDescription, "", "The description of volume")
 	cmd.Flags().StringVar(&optZoneName, CliFlagZoneName, "", "Specify volume zone name")
@@ -627,6 +648,8 @@ func newVolUpdateCmd(client *master.MasterClient) *cobra.Command {
 	cmd.Flags().StringVar(&optReplicaNum, CliFlagReplicaNum, "", "Specify data partition replicas number(default 3 for normal volume,1 for low volume)")
 	cmd.Flags().StringVar(&optEnableQuota, CliFlagEnableQuota, "", "Enable quota")
 	cmd.Flags().Int64Var(&optDeleteLockTime, CliFlagDeleteLockTime, -1, "Specify delete lock time[Unit: hour] for volume")
+	cmd.Flags().IntVar(&optInlineDirThreshold, CliFlagInlineDirThreshold, -1, "Specify max children of a directory stored inline in meta nodes, 0 disables it")
+	cmd.Flags().IntVar(&optClientQpsLimit, CliFlagClientQpsLimit, -1, "Specify max qps of each client on a meta node, 0 means no limit")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 
 	return cmd
@@ -658,49 +681,62 @@ func newVolInfoCmd(client *master.MasterClient) *cobra.Command {
 				err = fmt.Errorf("Get volume info failed:\n%v\n", err)
 				return
 			}
-			// print summary info
-			stdout("Summary:\n%s\n", formatSimpleVolView(svv))
+			data := struct {
+				*proto.SimpleVolView
+				MetaPartitions []*proto.MetaPartitionView     `json:",omitempty"`
+				DataPartitions []*proto.DataPartitionResponse `json:",omitempty"`
+			}{SimpleVolView: svv}
 
-			// print metadata detail
+			// metadata detail
 			if optMetaDetail {
 				var views []*proto.MetaPartitionView
 				if views, err = client.ClientAPI().GetMetaPartitions(volumeName); err != nil {
 					err = fmt.Errorf("Get volume metadata detail information failed:\n%v\n", err)
 					return
 				}
-				stdout("Meta partitions:\n")
-				stdout("%v\n", metaPartitionTableHeader)
 				sort.SliceStable(views, func(i, j int) bool {
 					return views[i].PartitionID < views[j].PartitionID
 				})
-				for _, view := range views {
-					stdout("%v\n", formatMetaPartitionTableRow(view))
-				}
+				data.MetaPartitions = views
 			}
 
-			// print data detail
+			// data detail
 			if optDataDetail {
 				var view *proto.DataPartitionsView
 				if view, err = client.ClientAPI().EncodingGzip().GetDataPartitions(volumeName); err != nil {
 					err = fmt.Errorf("Get volume data detail information failed:\n%v\n", err)
 					return
 				}
-				stdout("Data partitions:\n")
-				stdout("%v\n", dataPartitionTableHeader)
 				sort.SliceStable(view.DataPartitions, func(i, j int) bool {
 					return view.DataPartitions[i].PartitionID < view.DataPartitions[j].PartitionID
 				})
-				for _, dp := range view.DataPartitions {
-					stdout("%v\n", formatDataPartitionTableRow(dp))
-				}
-			}
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
+				data.DataPartitions = view.DataPartitions
 			}
-			return validVols(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+
+			err = render(data, func() {
+				// print summary info
+				stdout("Summary:\n%s\n", formatSimpleVolView(svv))
+
+				// print metadata detail
+				if optMetaDetail {
+					stdout("Meta partitions:\n")
+					stdout("%v\n", metaPartitionTableHeader)
+					for _, view := range data.MetaPartitions {
+						stdout("%v\n", formatMetaPartitionTableRow(view))
+					}
+				}
+
+				// print data detail
+				if optDataDetail {
+					stdout("Data partitions:\n")
+					stdout("%v\n", dataPartitionTableHeader)
+					for _, dp := range data.DataPartitions {
+						stdout("%v\n", formatDataPartitionTableRow(dp))
+					}
+				}
+			})
 		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
 	}
 	cmd.Flags().BoolVarP(&optMetaDetail, "meta-partition", "m", false, "Display meta partition detail information")
 	cmd.Flags().BoolVarP(&optDataDetail, "data-partition", "d", false, "Display data partition detail information")
@@ -728,57 +764,49 @@ func newVolDeleteCmd(client *master.MasterClient) *cobra.Command {
 			defer func() {
 				errout(err)
 			}()
-			// ask user for confirm
-			if !optYes {
-				if status {
-					stdout("Delete volume [%v] (yes/no)[no]:", volumeName)
-					var userConfirm string
-					_, _ = fmt.Scanln(&userConfirm)
-					if userConfirm != "yes" {
-						err = fmt.Errorf("Abort by user.\n")
-						return
+			// deleting is confirmed by token and audited
+			if status {
+				err = runDangerousOp(dangerousOpVolDelete, volumeName, optYes, true, func() error {
+					svv, err := client.AdminAPI().GetVolumeSimpleInfo(volumeName)
+					if err != nil {
+						return fmt.Errorf("Delete volume failed:\n%v\n", err)
 					}
-				} else {
-					stdout("UnDelete volume [%v] (yes/no)[no]:", volumeName)
-					var userConfirm string
-					_, _ = fmt.Scanln(&userConfirm)
-					if userConfirm != "yes" {
-						err = fmt.Errorf("Abort by user.\n")
-						return
+					if err = client.AdminAPI().DeleteVolumeWithAuthNode(volumeName, util.CalcAuthKey(svv.Owner), clientIDKey); err != nil {
+						return fmt.Errorf("Delete volume failed:\n%v\n", err)
 					}
-				}
-			}
-
-			var svv *proto.SimpleVolView
-			svv, err = client.AdminAPI().GetVolumeSimpleInfo(volumeName)
-			if status {
+					return nil
+				})
 				if err != nil {
-					err = fmt.Errorf("Delete volume failed:\n%v\n", err)
-					return
-				}
-				if err = client.AdminAPI().DeleteVolumeWithAuthNode(volumeName, util.CalcAuthKey(svv.Owner), clientIDKey); err != nil {
-					err = fmt.Errorf("Delete volume failed:\n%v\n", err)
 					return
 				}
 				stdout("Volume has been deleted successfully.\n")
-			} else {
-				if err != nil {
-					err = fmt.Errorf("UnDelete volume failed:\n%v\n", err)
-					return
-				}
-				if err = client.AdminAPI().UnDeleteVolume(volumeName, util.CalcAuthKey(svv.Owner), status); err != nil {
-					err = fmt.Errorf("UnDelete volume failed:\n%v\n", err)
+				return
+			}
+
+			// ask user for confirm
+			if !optYes {
+				stdout("UnDelete volume [%v] (yes/no)[no]:", volumeName)
+				var userConfirm string
+				_, _ = fmt.Scanln(&userConfirm)
+				if userConfirm != "yes" {
+					err = fmt.Errorf("Abort by user.\n")
 					return
 				}
-				stdout("Volume has been undeleted successfully.\n")
 			}
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
+
+			var svv *proto.SimpleVolView
+			svv, err = client.AdminAPI().GetVolumeSimpleInfo(volumeName)
+			if err != nil {
+				err = fmt.Errorf("UnDelete volume failed:\n%v\n", err)
+				return
+			}
+			if err = client.AdminAPI().UnDeleteVolume(volumeName, util.CalcAuthKey(svv.Owner), status); err != nil {
+				err = fmt.Errorf("UnDelete volume failed:\n%v\n", err)
+				return
 			}
-			return validVols(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+			stdout("Volume has been undeleted successfully.\n")
 		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
 	}
 	cmd.Flags().BoolVarP(&optYes, "yes", "y", false, "Answer yes for all questions")
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
@@ -796,10 +824,11 @@ func newVolTransferCmd(client *master.MasterClient) *cobra.Command {
 	var optForce bool
 	var clientIDKey string
 	cmd := &cobra.Command{
-		Use:     cmdVolTransferUse,
-		Short:   cmdVolTransferShort,
-		Aliases: []string{"trans"},
-		Args:    cobra.MinimumNArgs(2),
+		Use:               cmdVolTransferUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols, validUsers),
+		Short:             cmdVolTransferShort,
+		Aliases:           []string{"trans"},
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			volume := args[0]
@@ -882,12 +911,7 @@ func newVolAddDPCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdout("Add dp successfully.\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validVols(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -924,12 +948,7 @@ func newVolAddMPCmd(client *master.MasterClient) *cobra.Command {
 			}
 			stdout("Add mp successfully.\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validVols(client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -974,13 +993,7 @@ func newVolSetCapacityCmd(use, short string, r clientHandler) *cobra.Command {
 			}
 			stdout("Volume capacity has been set successfully.\n")
 		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			volume := r.(*volumeClient)
-			return validVols(volume.client, toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: validArgsFunc(r.(*volumeClient).client, completeVols),
 	}
 	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, r.(*volumeClient).client.ClientIDKey(), CliUsageClientIDKey)
 	return cmd
@@ -993,9 +1006,10 @@ var (
 
 func newVolSetForbiddenCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   cmdVolSetForbiddenUse,
-		Short: cmdVolSetForbiddenShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdVolSetForbiddenUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolSetForbiddenShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			name := args[0]
 			settingStr := args[1]
@@ -1023,9 +1037,10 @@ var (
 
 func newVolSetAuditLogCmd(client *master.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   cmdVolSetAuditLogUse,
-		Short: cmdVolSetAuditLogShort,
-		Args:  cobra.MinimumNArgs(2),
+		Use:               cmdVolSetAuditLogUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolSetAuditLogShort,
+		Args:              cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			name := args[0]
 			settingStr := args[1]
@@ -1045,3 +1060,96 @@ func newVolSetAuditLogCmd(client *master.MasterClient) *cobra.Command {
 	}
 	return cmd
 }
+
+var (
+	cmdVolSetQosLimitUse   = "set-qos [VOLUME]"
+	cmdVolSetQosLimitShort = "Set read/write iops and flow limits of volume, 0 means no limit"
+)
+
+func newVolSetQosLimitCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optIopsRLimit uint64
+		optIopsWLimit uint64
+		optFlowRLimit uint64
+		optFlowWLimit uint64
+	)
+	cmd := &cobra.Command{
+		Use:               cmdVolSetQosLimitUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolSetQosLimitShort,
+		Args:              cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			// limits not specified are kept unchanged
+			iopsRLimit, iopsWLimit, flowRLimit, flowWLimit := int64(-1), int64(-1), int64(-1), int64(-1)
+			if cmd.Flags().Changed("iops-read") {
+				iopsRLimit = int64(optIopsRLimit)
+			}
+			if cmd.Flags().Changed("iops-write") {
+				iopsWLimit = int64(optIopsWLimit)
+			}
+			if cmd.Flags().Changed("flow-read") {
+				flowRLimit = int64(optFlowRLimit)
+			}
+			if cmd.Flags().Changed("flow-write") {
+				flowWLimit = int64(optFlowWLimit)
+			}
+			if err = client.AdminAPI().SetVolumeQosLimit(name, iopsRLimit, iopsWLimit, flowRLimit, flowWLimit); err != nil {
+				return
+			}
+			stdout("Volume qos limit has been set successfully, please wait few minutes for the settings to take effect.\n")
+		},
+	}
+	cmd.Flags().Uint64Var(&optIopsRLimit, "iops-read", 0, "Specify read iops limit of volume")
+	cmd.Flags().Uint64Var(&optIopsWLimit, "iops-write", 0, "Specify write iops limit of volume")
+	cmd.Flags().Uint64Var(&optFlowRLimit, "flow-read", 0, "Specify read flow limit[Unit: MB/s] of volume")
+	cmd.Flags().Uint64Var(&optFlowWLimit, "flow-write", 0, "Specify write flow limit[Unit: MB/s] of volume")
+	return cmd
+}
+
+var (
+	cmdVolDuUse   = "du [VOLUME] [INODE]"
+	cmdVolDuShort = "Show files, subdirectories and bytes under a directory recursively"
+)
+
+func newVolDuCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               cmdVolDuUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolDuShort,
+		Long: `Show files, subdirectories and bytes under the directory recursively, which are counted
+incrementally in the recursive summary of each directory by clients with summary enabled,
+the directory tree is not walked.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			ino, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return
+			}
+			mw, err := meta.NewMetaWrapper(&meta.MetaConfig{
+				Volume:  args[0],
+				Masters: client.Nodes(),
+			})
+			if err != nil {
+				return
+			}
+			summaryInfo, err := mw.GetDirStat_ll(ino)
+			if err != nil {
+				return
+			}
+			err = render(summaryInfo, func() {
+				stdout("inode [%v] files [%v] dirs [%v] bytes [%v]\n", ino, summaryInfo.Files, summaryInfo.Subdirs,
+					formatSize(uint64(summaryInfo.Fbytes)))
+			})
+		},
+	}
+	return cmd
+}
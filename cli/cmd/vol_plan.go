@@ -0,0 +1,246 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdVolPlanUse   = "plan [VOLUME]"
+	cmdVolPlanShort = "Analyze usage growth of a volume and suggest capacity and partition adjustments"
+
+	volHistoryName       = ".cfs-cli-vol-history.json"
+	volHistoryMaxSamples = 1024
+	volHistoryMaxAge     = 90 * 24 * time.Hour
+)
+
+var volHistoryPath = path.Join(defaultHomeDir, volHistoryName)
+
+// volUsageSample used size of volume at unix time
+type volUsageSample struct {
+	Time     int64  `json:"time"`
+	UsedSize uint64 `json:"used"`
+}
+
+// volPlan suggestion of capacity and partitions of volume
+type volPlan struct {
+	Capacity        uint64  // GB
+	UsedSize        uint64  // bytes
+	GrowthPerDay    float64 // bytes
+	SampleDuration  time.Duration
+	DaysToFull      float64 // negative if the volume never gets full
+	SuggestCapacity uint64  // GB
+	RwDpCnt         int
+	SuggestAddDp    int
+}
+
+func newVolPlanCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optInterval    time.Duration
+		optDays        int
+		optTargetRatio float64
+		optApply       bool
+		clientIDKey    string
+	)
+	cmd := &cobra.Command{
+		Use:   cmdVolPlanUse,
+		Short: cmdVolPlanShort,
+		Long: `Analyze the growth rate of used size of a volume and suggest capacity and
+data partition adjustments. Master keeps no history of volume stats, so the used
+size is recorded in ~/` + volHistoryName + ` every time this command runs, the
+growth rate is computed from the oldest recorded sample. The volume is sampled
+again after --interval if there is no earlier sample.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				err  error
+				svv  *proto.SimpleVolView
+				stat *proto.VolStatInfo
+			)
+			defer func() {
+				errout(err)
+			}()
+			volumeName := args[0]
+			if optDays <= 0 {
+				err = fmt.Errorf("days must be larger than 0")
+				return
+			}
+			if optTargetRatio <= 0 || optTargetRatio > 1 {
+				err = fmt.Errorf("target ratio must be in (0, 1]")
+				return
+			}
+			if svv, err = client.AdminAPI().GetVolumeSimpleInfo(volumeName); err != nil {
+				return
+			}
+			if stat, err = client.ClientAPI().GetVolumeStat(volumeName); err != nil {
+				return
+			}
+
+			history := loadVolHistory()
+			samples := appendVolSample(history[volumeName], volUsageSample{Time: time.Now().Unix(), UsedSize: stat.UsedSize})
+			if len(samples) < 2 && optInterval > 0 {
+				stdout("No usage history of volume %v, sampling again after %v ...\n", volumeName, optInterval)
+				time.Sleep(optInterval)
+				if stat, err = client.ClientAPI().GetVolumeStat(volumeName); err != nil {
+					return
+				}
+				samples = appendVolSample(samples, volUsageSample{Time: time.Now().Unix(), UsedSize: stat.UsedSize})
+			}
+			history[volumeName] = samples
+			if err = saveVolHistory(history); err != nil {
+				return
+			}
+
+			plan := computeVolPlan(svv, samples, optDays, optTargetRatio)
+			stdout("%v", formatVolPlan(volumeName, plan, optDays, optTargetRatio))
+			if !optApply {
+				return
+			}
+
+			if plan.SuggestCapacity > plan.Capacity {
+				if err = client.AdminAPI().VolExpand(volumeName, plan.SuggestCapacity,
+					util.CalcAuthKey(svv.Owner), clientIDKey); err != nil {
+					return
+				}
+				stdout("Volume capacity has been expanded to %v GB.\n", plan.SuggestCapacity)
+			}
+			if plan.SuggestAddDp > 0 {
+				if err = client.AdminAPI().CreateDataPartition(volumeName, plan.SuggestAddDp, clientIDKey); err != nil {
+					return
+				}
+				stdout("Add %v dp successfully.\n", plan.SuggestAddDp)
+			}
+			if plan.SuggestCapacity <= plan.Capacity && plan.SuggestAddDp <= 0 {
+				stdout("Nothing to apply.\n")
+			}
+		},
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+	}
+	cmd.Flags().DurationVar(&optInterval, "interval", time.Minute, "Sampling interval if there is no usage history, 0 to skip sampling")
+	cmd.Flags().IntVar(&optDays, "days", 30, "Number of days the capacity should last")
+	cmd.Flags().Float64Var(&optTargetRatio, "target-ratio", 0.8, "Expected used ratio of capacity after the days")
+	cmd.Flags().BoolVar(&optApply, "apply", false, "Apply the suggested capacity expansion and data partitions")
+	cmd.Flags().StringVar(&clientIDKey, CliFlagClientIDKey, client.ClientIDKey(), CliUsageClientIDKey)
+	return cmd
+}
+
+func loadVolHistory() map[string][]volUsageSample {
+	history := make(map[string][]volUsageSample)
+	data, err := os.ReadFile(volHistoryPath)
+	if err != nil {
+		return history
+	}
+	if err = json.Unmarshal(data, &history); err != nil {
+		return make(map[string][]volUsageSample)
+	}
+	return history
+}
+
+func saveVolHistory(history map[string][]volUsageSample) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(volHistoryPath, data, 0o600)
+}
+
+// appendVolSample appends the sample and drops the expired and excess samples
+func appendVolSample(samples []volUsageSample, sample volUsageSample) []volUsageSample {
+	samples = append(samples, sample)
+	expired := sample.Time - int64(volHistoryMaxAge/time.Second)
+	idx := 0
+	for idx < len(samples)-1 && samples[idx].Time < expired {
+		idx++
+	}
+	if len(samples)-idx > volHistoryMaxSamples {
+		idx = len(samples) - volHistoryMaxSamples
+	}
+	return samples[idx:]
+}
+
+func computeVolPlan(svv *proto.SimpleVolView, samples []volUsageSample, days int, targetRatio float64) *volPlan {
+	plan := &volPlan{
+		Capacity:        svv.Capacity,
+		RwDpCnt:         svv.RwDpCnt,
+		DaysToFull:      -1,
+		SuggestCapacity: svv.Capacity,
+	}
+	if len(samples) == 0 {
+		return plan
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	plan.UsedSize = last.UsedSize
+	plan.SampleDuration = time.Duration(last.Time-first.Time) * time.Second
+	if plan.SampleDuration > 0 && last.UsedSize > first.UsedSize {
+		plan.GrowthPerDay = float64(last.UsedSize-first.UsedSize) / plan.SampleDuration.Hours() * 24
+	}
+
+	capacity := float64(svv.Capacity * util.GB)
+	if plan.GrowthPerDay > 0 {
+		plan.DaysToFull = math.Max(capacity-float64(plan.UsedSize), 0) / plan.GrowthPerDay
+	}
+
+	expected := (float64(plan.UsedSize) + plan.GrowthPerDay*float64(days)) / targetRatio
+	if suggest := uint64(math.Ceil(expected / util.GB)); suggest > svv.Capacity {
+		plan.SuggestCapacity = suggest
+	}
+
+	// keep enough writable data partitions for the growth of one day
+	needRwDp := int(math.Ceil(plan.GrowthPerDay / util.DefaultDataPartitionSize))
+	if needRwDp > svv.RwDpCnt {
+		plan.SuggestAddDp = needRwDp - svv.RwDpCnt
+	}
+	return plan
+}
+
+func formatVolPlan(name string, plan *volPlan, days int, targetRatio float64) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("  Volume              : %v\n", name))
+	sb.WriteString(fmt.Sprintf("  Capacity            : %v GB\n", plan.Capacity))
+	sb.WriteString(fmt.Sprintf("  Used size           : %v\n", formatSize(plan.UsedSize)))
+	sb.WriteString(fmt.Sprintf("  Sample duration     : %v\n", plan.SampleDuration))
+	sb.WriteString(fmt.Sprintf("  Growth per day      : %v\n", formatSize(uint64(plan.GrowthPerDay))))
+	if plan.DaysToFull < 0 {
+		sb.WriteString("  Days to full        : N/A\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("  Days to full        : %.1f\n", plan.DaysToFull))
+	}
+	sb.WriteString(fmt.Sprintf("  Writable dp count   : %v\n", plan.RwDpCnt))
+	sb.WriteString("Suggestion:\n")
+	if plan.SuggestCapacity > plan.Capacity {
+		sb.WriteString(fmt.Sprintf("  Expand capacity to %v GB to last %v days under used ratio %v\n",
+			plan.SuggestCapacity, days, targetRatio))
+	} else {
+		sb.WriteString(fmt.Sprintf("  Capacity is enough for %v days under used ratio %v\n", days, targetRatio))
+	}
+	if plan.SuggestAddDp > 0 {
+		sb.WriteString(fmt.Sprintf("  Add %v data partitions for the growth of one day\n", plan.SuggestAddDp))
+	} else {
+		sb.WriteString("  Writable data partitions are enough\n")
+	}
+	return sb.String()
+}
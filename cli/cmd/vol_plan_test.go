@@ -0,0 +1,60 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliVolPlan(t *testing.T) {
+	svv := &proto.SimpleVolView{Capacity: 1000, RwDpCnt: 1}
+	day := int64(24 * time.Hour / time.Second)
+
+	// no growth
+	plan := computeVolPlan(svv, []volUsageSample{{Time: 0, UsedSize: 100 * util.GB}}, 30, 0.8)
+	require.Equal(t, float64(-1), plan.DaysToFull)
+	require.Equal(t, uint64(1000), plan.SuggestCapacity)
+	require.Equal(t, 0, plan.SuggestAddDp)
+
+	// 300GB per day
+	samples := []volUsageSample{{Time: 0, UsedSize: 100 * util.GB}, {Time: day, UsedSize: 400 * util.GB}}
+	plan = computeVolPlan(svv, samples, 10, 0.8)
+	require.Equal(t, float64(300*util.GB), plan.GrowthPerDay)
+	require.Equal(t, 2.0, plan.DaysToFull)
+	require.Equal(t, uint64((400+300*10)*10/8), plan.SuggestCapacity)
+	require.Equal(t, 2, plan.SuggestAddDp)
+	t.Log("\n" + formatVolPlan("vol", plan, 10, 0.8))
+}
+
+func TestCliVolPlanSamples(t *testing.T) {
+	var samples []volUsageSample
+	samples = appendVolSample(samples, volUsageSample{Time: 0})
+	samples = appendVolSample(samples, volUsageSample{Time: 1})
+	require.Len(t, samples, 2)
+
+	samples = appendVolSample(samples, volUsageSample{Time: int64(volHistoryMaxAge/time.Second) + 1})
+	require.Len(t, samples, 2)
+	require.Equal(t, int64(1), samples[0].Time)
+
+	for i := 0; i < volHistoryMaxSamples; i++ {
+		samples = appendVolSample(samples, volUsageSample{Time: samples[len(samples)-1].Time})
+	}
+	require.Len(t, samples, volHistoryMaxSamples)
+}
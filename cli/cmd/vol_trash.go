@@ -0,0 +1,161 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/sdk/meta"
+	"github.com/cubefs/cubefs/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cmdVolTrashUse          = "trash [COMMAND]"
+	cmdVolTrashShort        = "Manage trash of volume"
+	cmdVolTrashSetUse       = "set [VOLUME] [MINUTES]"
+	cmdVolTrashSetShort     = "Set retention of files in trash, 0 disables trash"
+	cmdVolTrashListUse      = "list [VOLUME] [PARENT INODE]"
+	cmdVolTrashListShort    = "List files in trash deleted from a directory"
+	cmdVolTrashRestoreUse   = "restore [VOLUME] [PARENT INODE] [KEY]"
+	cmdVolTrashRestoreShort = "Restore a file in trash to the directory it was deleted from"
+)
+
+func newVolTrashCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdVolTrashUse,
+		Short: cmdVolTrashShort,
+	}
+	cmd.AddCommand(
+		newVolTrashSetCmd(client),
+		newVolTrashListCmd(client),
+		newVolTrashRestoreCmd(client),
+	)
+	return cmd
+}
+
+func newVolTrashSetCmd(client *master.MasterClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               cmdVolTrashSetUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolTrashSetShort,
+		Args:              cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			name := args[0]
+			interval, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return
+			}
+			svv, err := client.AdminAPI().GetVolumeSimpleInfo(name)
+			if err != nil {
+				return
+			}
+			if err = client.AdminAPI().SetVolumeTrashInterval(name, util.CalcAuthKey(svv.Owner), interval); err != nil {
+				return
+			}
+			stdout("Volume trash has been set successfully, please wait few minutes for the settings to take effect.\n")
+		},
+	}
+	return cmd
+}
+
+func newTrashMetaWrapper(client *master.MasterClient, volName string) (*meta.MetaWrapper, error) {
+	return meta.NewMetaWrapper(&meta.MetaConfig{
+		Volume:  volName,
+		Masters: client.Nodes(),
+	})
+}
+
+func newVolTrashListCmd(client *master.MasterClient) *cobra.Command {
+	var (
+		optMarker string
+		optLimit  int
+	)
+	cmd := &cobra.Command{
+		Use:               cmdVolTrashListUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolTrashListShort,
+		Args:              cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			parentID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return
+			}
+			mw, err := newTrashMetaWrapper(client, args[0])
+			if err != nil {
+				return
+			}
+			items, nextMarker, err := mw.ListTrash_ll(parentID, optMarker, optLimit)
+			if err != nil {
+				return
+			}
+			err = render(items, func() {
+				stdout("%-12v  %-20v  %-32v  %v\n", "INODE", "DELETE TIME", "NAME", "KEY")
+				for _, item := range items {
+					stdout("%-12v  %-20v  %-32v  %v\n", item.Inode, formatTime(item.DeleteTime), item.Name, item.Key)
+				}
+				if nextMarker != "" {
+					stdout("\nmore files in trash, list them with --marker %v\n", nextMarker)
+				}
+			})
+		},
+	}
+	cmd.Flags().StringVar(&optMarker, "marker", "", "List files after the key")
+	cmd.Flags().IntVar(&optLimit, "limit", 1000, "Max number of files to list")
+	return cmd
+}
+
+func newVolTrashRestoreCmd(client *master.MasterClient) *cobra.Command {
+	var optName string
+	cmd := &cobra.Command{
+		Use:               cmdVolTrashRestoreUse,
+		ValidArgsFunction: validArgsFunc(client, completeVols),
+		Short:             cmdVolTrashRestoreShort,
+		Long: `Restore a file in trash to the directory it was deleted from, with its original
+name or the one given by --name. It fails if the name is taken or the directory is deleted.`,
+		Args: cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			defer func() {
+				errout(err)
+			}()
+			parentID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return
+			}
+			mw, err := newTrashMetaWrapper(client, args[0])
+			if err != nil {
+				return
+			}
+			item := &proto.TrashItem{Key: args[2], ParentID: parentID}
+			if err = mw.RestoreTrash_ll(item, optName); err != nil {
+				return
+			}
+			stdout("File %v has been restored successfully.\n", args[2])
+		},
+	}
+	cmd.Flags().StringVar(&optName, "name", "", "Restore the file with a new name")
+	return cmd
+}
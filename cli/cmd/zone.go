@@ -16,6 +16,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/cubefs/cubefs/proto"
 	sdk "github.com/cubefs/cubefs/sdk/master"
@@ -48,6 +50,7 @@ const (
 )
 
 func newZoneListCmd(client *sdk.MasterClient) *cobra.Command {
+	var optSort string
 	cmd := &cobra.Command{
 		Use:     CliOpList,
 		Short:   cmdZoneListShort,
@@ -58,19 +61,98 @@ func newZoneListCmd(client *sdk.MasterClient) *cobra.Command {
 			defer func() {
 				errout(err)
 			}()
-			if zones, err = client.AdminAPI().ListZones(); err != nil {
+			if zones, err = client.AdminAPI().ListZonesWithUsage(); err != nil {
 				return
 			}
-			zoneTablePattern := "%-8v    %-10v\n"
-			stdout(zoneTablePattern, "ZONE", "STATUS")
-			for _, zone := range zones {
-				stdout(zoneTablePattern, zone.Name, zone.Status)
+			if err = sortZones(zones, optSort); err != nil {
+				return
 			}
+			err = render(zones, func() {
+				stdout("%v", formatZoneList(zones))
+			})
 		},
 	}
+	cmd.Flags().StringVar(&optSort, "sort", zoneSortName,
+		fmt.Sprintf("Sort zones by [%v]", strings.Join(zoneSortKeys, "|")))
 	return cmd
 }
 
+const (
+	zoneSortName      = "name"
+	zoneSortDataNodes = "datanodes"
+	zoneSortMetaNodes = "metanodes"
+	zoneSortDataUsed  = "data-used"
+	zoneSortMetaUsed  = "meta-used"
+	zoneSortDataRatio = "data-ratio"
+	zoneSortMetaRatio = "meta-ratio"
+	zoneSortRwDp      = "rw-dp"
+	zoneSortRwMp      = "rw-mp"
+)
+
+var zoneSortKeys = []string{
+	zoneSortName, zoneSortDataNodes, zoneSortMetaNodes, zoneSortDataUsed, zoneSortMetaUsed,
+	zoneSortDataRatio, zoneSortMetaRatio, zoneSortRwDp, zoneSortRwMp,
+}
+
+func zoneUsage(zone *proto.ZoneView) *proto.ZoneUsageView {
+	if zone.Usage == nil {
+		return &proto.ZoneUsageView{}
+	}
+	return zone.Usage
+}
+
+func usedRatio(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
+
+// sortZones sorts zones by name in ascending order, or by others in descending order
+func sortZones(zones []*proto.ZoneView, key string) error {
+	var value func(u *proto.ZoneUsageView) float64
+	switch key {
+	case zoneSortName:
+		sort.SliceStable(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+		return nil
+	case zoneSortDataNodes:
+		value = func(u *proto.ZoneUsageView) float64 { return float64(u.DataNodeCount) }
+	case zoneSortMetaNodes:
+		value = func(u *proto.ZoneUsageView) float64 { return float64(u.MetaNodeCount) }
+	case zoneSortDataUsed:
+		value = func(u *proto.ZoneUsageView) float64 { return float64(u.DataUsed) }
+	case zoneSortMetaUsed:
+		value = func(u *proto.ZoneUsageView) float64 { return float64(u.MetaUsed) }
+	case zoneSortDataRatio:
+		value = func(u *proto.ZoneUsageView) float64 { return usedRatio(u.DataUsed, u.DataTotal) }
+	case zoneSortMetaRatio:
+		value = func(u *proto.ZoneUsageView) float64 { return usedRatio(u.MetaUsed, u.MetaTotal) }
+	case zoneSortRwDp:
+		value = func(u *proto.ZoneUsageView) float64 { return float64(u.WritableDataPartitions) }
+	case zoneSortRwMp:
+		value = func(u *proto.ZoneUsageView) float64 { return float64(u.WritableMetaPartitions) }
+	default:
+		return fmt.Errorf("invalid sort key %v, should be one of [%v]", key, strings.Join(zoneSortKeys, "|"))
+	}
+	sort.SliceStable(zones, func(i, j int) bool {
+		return value(zoneUsage(zones[i])) > value(zoneUsage(zones[j]))
+	})
+	return nil
+}
+
+func formatZoneList(zones []*proto.ZoneView) string {
+	rows := table{arow("ZONE", "STATUS", "DATANODES", "METANODES", "DATA USED/TOTAL", "DATA RATIO",
+		"META USED/TOTAL", "META RATIO", "RW DPS", "RW MPS")}
+	for _, zone := range zones {
+		u := zoneUsage(zone)
+		rows = rows.append(arow(zone.Name, zone.Status, u.DataNodeCount, u.MetaNodeCount,
+			formatSize(u.DataUsed)+"/"+formatSize(u.DataTotal), fmt.Sprintf("%.2f", usedRatio(u.DataUsed, u.DataTotal)),
+			formatSize(u.MetaUsed)+"/"+formatSize(u.MetaTotal), fmt.Sprintf("%.2f", usedRatio(u.MetaUsed, u.MetaTotal)),
+			u.WritableDataPartitions, u.WritableMetaPartitions))
+	}
+	return alignTable(rows...)
+}
+
 func newZoneInfoCmd(client *sdk.MasterClient) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   CliOpInfo + " [NAME]",
@@ -100,14 +182,11 @@ func newZoneInfoCmd(client *sdk.MasterClient) *cobra.Command {
 				err = fmt.Errorf("Zone[%v] not exists in cluster\n ", zoneName)
 				return
 			}
-			stdout("%v", formatZoneView(zoneView))
-		},
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			if len(args) != 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-			return validZones(client, toComplete), cobra.ShellCompDirectiveNoFileComp
+			err = render(zoneView, func() {
+				stdout("%v", formatZoneView(zoneView))
+			})
 		},
+		ValidArgsFunction: validArgsFunc(client, validZones),
 	}
 	return cmd
 }
@@ -119,16 +198,26 @@ func newZoneUpdateCmd(client *sdk.MasterClient) *cobra.Command {
 	dataNodeSelector := ""
 	metaNodeSelector := ""
 	cmd := &cobra.Command{
-		Use:   CliOpUpdate + " [NAME]",
-		Short: cmdZoneUpdateShort,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               CliOpUpdate + " [NAME]",
+		ValidArgsFunction: validArgsFunc(client, validZones),
+		Short:             cmdZoneUpdateShort,
+		Args:              cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var err error
 			defer func() {
 				errout(err)
 			}()
 			zoneName := args[0]
-			if err = client.AdminAPI().UpdateZone(zoneName, enable, dataNodesetSelector, metaNodesetSelector, dataNodeSelector, metaNodeSelector); err != nil {
+			update := func() error {
+				return client.AdminAPI().UpdateZone(zoneName, enable, dataNodesetSelector, metaNodesetSelector, dataNodeSelector, metaNodeSelector)
+			}
+			// disabling a zone is confirmed by token and audited
+			if !enable {
+				err = runDangerousOp(dangerousOpZoneDisable, zoneName, false, false, update)
+			} else {
+				err = update()
+			}
+			if err != nil {
 				return
 			}
 			stdout(fmt.Sprintf("Zone %v has been update successfully!\n", zoneName))
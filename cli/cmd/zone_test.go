@@ -0,0 +1,47 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliSortZones(t *testing.T) {
+	zones := []*proto.ZoneView{
+		{Name: "b", Usage: &proto.ZoneUsageView{DataNodeCount: 3, DataTotal: 100, DataUsed: 10, WritableDataPartitions: 5}},
+		{Name: "c"},
+		{Name: "a", Usage: &proto.ZoneUsageView{DataNodeCount: 1, DataTotal: 100, DataUsed: 90, WritableDataPartitions: 9}},
+	}
+	names := func() (ns []string) {
+		for _, zone := range zones {
+			ns = append(ns, zone.Name)
+		}
+		return
+	}
+
+	require.NoError(t, sortZones(zones, zoneSortName))
+	require.Equal(t, []string{"a", "b", "c"}, names())
+	require.NoError(t, sortZones(zones, zoneSortDataNodes))
+	require.Equal(t, []string{"b", "a", "c"}, names())
+	require.NoError(t, sortZones(zones, zoneSortDataRatio))
+	require.Equal(t, []string{"a", "b", "c"}, names())
+	require.NoError(t, sortZones(zones, zoneSortRwDp))
+	require.Equal(t, []string{"a", "b", "c"}, names())
+	require.Error(t, sortZones(zones, "unknown"))
+	t.Log("\n" + formatZoneList(zones))
+}
@@ -15,6 +15,7 @@
 package fs
 
 import (
+	"context"
 	"syscall"
 	"time"
 
@@ -67,6 +68,10 @@ var (
 
 // ParseError returns the error type.
 func ParseError(err error) fuse.Errno {
+	// the request is interrupted by the kernel
+	if err == context.Canceled {
+		return fuse.EINTR
+	}
 	switch v := err.(type) {
 	case syscall.Errno:
 		return fuse.Errno(v)
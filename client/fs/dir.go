@@ -134,7 +134,7 @@ func (d *Dir) Attr(ctx context.Context, a *fuse.Attr) error {
 	}()
 
 	ino := d.info.Inode
-	info, err := d.super.InodeGet(ino)
+	info, err := d.super.InodeGetContext(ctx, ino)
 	if err != nil {
 		log.LogErrorf("Attr: ino(%v) err(%v)", ino, err)
 		return ParseError(err)
@@ -298,6 +298,11 @@ func (d *Dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 		stat.EndStat("Lookup", err, bgTime, 1)
 	}()
 
+	metric := exporter.NewTPCnt("lookup")
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: d.super.volname})
+	}()
+
 	log.LogDebugf("TRACE Lookup: parent(%v) req(%v)", d.info.Inode, req)
 	log.LogDebugf("TRACE Lookup: parent(%v) path(%v) d.super.bcacheDir(%v)", d.info.Inode, d.getCwd(), d.super.bcacheDir)
 
@@ -312,7 +317,7 @@ func (d *Dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 		if dentryInfo == nil {
 			lookupMetric := exporter.NewCounter("lookupDcacheMiss")
 			lookupMetric.AddWithLabels(1, map[string]string{exporter.Vol: d.super.volname})
-			ino, _, err = d.super.mw.Lookup_ll(d.info.Inode, req.Name)
+			ino, _, err = d.super.mw.LookupContext_ll(ctx, d.info.Inode, req.Name)
 			if err != nil {
 				if err != syscall.ENOENT {
 					log.LogErrorf("Lookup: parent(%v) name(%v) err(%v)", d.info.Inode, req.Name, err)
@@ -331,8 +336,13 @@ func (d *Dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 		}
 	} else {
 		cino, ok := d.dcache.Get(req.Name)
-		if !ok {
-			cino, _, err = d.super.mw.Lookup_ll(d.info.Inode, req.Name)
+		if ok {
+			lookupMetric := exporter.NewCounter("lookupDcacheHit")
+			lookupMetric.AddWithLabels(1, map[string]string{exporter.Vol: d.super.volname})
+		} else {
+			lookupMetric := exporter.NewCounter("lookupDcacheMiss")
+			lookupMetric.AddWithLabels(1, map[string]string{exporter.Vol: d.super.volname})
+			cino, _, err = d.super.mw.LookupContext_ll(ctx, d.info.Inode, req.Name)
 			if err != nil {
 				if err != syscall.ENOENT {
 					log.LogErrorf("Lookup: parent(%v) name(%v) err(%v)", d.info.Inode, req.Name, err)
@@ -343,7 +353,10 @@ func (d *Dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.Lo
 		ino = cino
 	}
 
-	info, err := d.super.InodeGet(ino)
+	info, err := d.super.InodeGetContext(ctx, ino)
+	if err == fuse.EINTR {
+		return nil, err
+	}
 	if err != nil {
 		log.LogErrorf("Lookup: parent(%v) name(%v) ino(%v) err(%v)", d.info.Inode, req.Name, ino, err)
 		dummyInodeInfo := &proto.InodeInfo{Inode: ino}
@@ -391,7 +404,7 @@ func (d *Dir) ReadDir(ctx context.Context, req *fuse.ReadRequest, resp *fuse.Rea
 	} else {
 		dirCtx = DirContext{}
 	}
-	children, err := d.super.mw.ReadDirLimit_ll(d.info.Inode, dirCtx.Name, limit)
+	children, err := d.super.mw.ReadDirLimitContext_ll(ctx, d.info.Inode, dirCtx.Name, limit)
 	if err != nil {
 		log.LogErrorf("readdirlimit: Readdir: ino(%v) err(%v) offset %v", d.info.Inode, err, req.Offset)
 		return make([]fuse.Dirent, 0), ParseError(err)
@@ -503,7 +516,7 @@ func (d *Dir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
 	from := ""
 	var children []proto.Dentry
 	for !noMore {
-		batches, err := d.super.mw.ReadDirLimit_ll(d.info.Inode, from, DefaultReaddirLimit)
+		batches, err := d.super.mw.ReadDirLimitContext_ll(ctx, d.info.Inode, from, DefaultReaddirLimit)
 		if err != nil {
 			log.LogErrorf("Readdir: ino(%v) err(%v) from(%v)", d.info.Inode, err, from)
 			return make([]fuse.Dirent, 0), ParseError(err)
@@ -822,6 +835,18 @@ func (d *Dir) Getxattr(ctx context.Context, req *fuse.GetxattrRequest, resp *fus
 			"Bytes:" + strconv.FormatInt(int64(fbytes), 10)
 		value = []byte(summaryStr)
 
+	} else if name == meta.RecursiveSummaryKey {
+		if !d.super.mw.EnableSummary {
+			return fuse.ENOSYS
+		}
+		var summaryInfo meta.SummaryInfo
+		if summaryInfo, err = d.super.mw.GetDirStat_ll(ino); err != nil {
+			log.LogErrorf("GetXattr: ino(%v) name(%v) err(%v)", ino, name, err)
+			return ParseError(err)
+		}
+		value = []byte("Files:" + strconv.FormatInt(summaryInfo.Files, 10) + "," +
+			"Dirs:" + strconv.FormatInt(summaryInfo.Subdirs, 10) + "," +
+			"Bytes:" + strconv.FormatInt(summaryInfo.Fbytes, 10))
 	} else {
 		info, err = d.super.mw.XAttrGet_ll(ino, name)
 		if err != nil {
@@ -885,8 +910,8 @@ func (d *Dir) Setxattr(ctx context.Context, req *fuse.SetxattrRequest) error {
 	ino := d.info.Inode
 	name := req.Name
 	value := req.Xattr
-	if name == meta.SummaryKey {
-		log.LogErrorf("Set 'DirStat' is not supported.")
+	if name == meta.SummaryKey || name == meta.RecursiveSummaryKey || name == meta.SummaryParentKey {
+		log.LogErrorf("Set '%v' is not supported.", name)
 		return fuse.ENOSYS
 	}
 	// TODO： implement flag to improve compatible (Mofei Zhang)
@@ -912,8 +937,8 @@ func (d *Dir) Removexattr(ctx context.Context, req *fuse.RemovexattrRequest) err
 
 	ino := d.info.Inode
 	name := req.Name
-	if name == meta.SummaryKey {
-		log.LogErrorf("Remove 'DirStat' is not supported.")
+	if name == meta.SummaryKey || name == meta.RecursiveSummaryKey || name == meta.SummaryParentKey {
+		log.LogErrorf("Remove '%v' is not supported.", name)
 		return fuse.ENOSYS
 	}
 	if err = d.super.mw.XAttrDel_ll(ino, name); err != nil {
@@ -15,11 +15,13 @@
 package fs
 
 import (
+	"context"
 	"time"
 
 	"github.com/cubefs/cubefs/depends/bazil.org/fuse"
 
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/exporter"
 	"github.com/cubefs/cubefs/util/log"
 )
 
@@ -28,12 +30,21 @@ const (
 )
 
 func (s *Super) InodeGet(ino uint64) (*proto.InodeInfo, error) {
+	return s.InodeGetContext(context.Background(), ino)
+}
+
+// InodeGetContext is InodeGet which is interrupted once ctx is done.
+func (s *Super) InodeGetContext(ctx context.Context, ino uint64) (*proto.InodeInfo, error) {
 	info := s.ic.Get(ino)
 	if info != nil {
+		icacheMetric := exporter.NewCounter("icacheHit")
+		icacheMetric.AddWithLabels(1, map[string]string{exporter.Vol: s.volname})
 		return info, nil
 	}
+	icacheMetric := exporter.NewCounter("icacheMiss")
+	icacheMetric.AddWithLabels(1, map[string]string{exporter.Vol: s.volname})
 
-	info, err := s.mw.InodeGet_ll(ino)
+	info, err := s.mw.InodeGetContext_ll(ctx, ino)
 	if err != nil || info == nil {
 		log.LogErrorf("InodeGet: ino(%v) err(%v) info(%v)", ino, err, info)
 		if err != nil {
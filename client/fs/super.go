@@ -108,14 +108,15 @@ func NewSuper(opt *proto.MountOptions) (s *Super, err error) {
 	s = new(Super)
 	masters := strings.Split(opt.Master, meta.HostsSeparator)
 	metaConfig := &meta.MetaConfig{
-		Volume:          opt.Volname,
-		Owner:           opt.Owner,
-		Masters:         masters,
-		Authenticate:    opt.Authenticate,
-		TicketMess:      opt.TicketMess,
-		ValidateOwner:   opt.Authenticate || opt.AccessKey == "",
-		EnableSummary:   opt.EnableSummary && opt.EnableXattr,
-		MetaSendTimeout: opt.MetaSendTimeout,
+		Volume:           opt.Volname,
+		Owner:            opt.Owner,
+		Masters:          masters,
+		Authenticate:     opt.Authenticate,
+		TicketMess:       opt.TicketMess,
+		ValidateOwner:    opt.Authenticate || opt.AccessKey == "",
+		EnableSummary:    opt.EnableSummary && opt.EnableXattr,
+		MetaSendTimeout:  opt.MetaSendTimeout,
+		ReportAccessTime: opt.ReportAccessTime,
 	}
 	s.mw, err = meta.NewMetaWrapper(metaConfig)
 	if err != nil {
@@ -218,15 +219,20 @@ func NewSuper(opt *proto.MountOptions) (s *Super, err error) {
 		NearRead:          opt.NearRead,
 		ReadRate:          opt.ReadRate,
 		WriteRate:         opt.WriteRate,
+		ReadBandwidth:     opt.ReadBandwidth,
+		WriteBandwidth:    opt.WriteBandwidth,
 		VolumeType:        opt.VolType,
 		BcacheEnable:      opt.EnableBcache,
 		BcacheDir:         opt.BcacheDir,
+		ReadCacheDir:      opt.ReadCacheDir,
+		ReadCacheSize:     opt.ReadCacheSize,
 		MaxStreamerLimit:  opt.MaxStreamerLimit,
 		VerReadSeq:        opt.VerReadSeq,
 		OnAppendExtentKey: s.mw.AppendExtentKey,
 		OnSplitExtentKey:  s.mw.SplitExtentKey,
 		OnGetExtents:      s.mw.GetExtents,
 		OnTruncate:        s.mw.Truncate,
+		OnSetModifyTime:   s.mw.SetModifyTime,
 		OnEvictIcache:     s.ic.Delete,
 		OnLoadBcache:      s.bc.Get,
 		OnCacheBcache:     s.bc.Put,
@@ -394,6 +400,26 @@ func (s *Super) SetRate(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte(fmt.Sprintf("Set write rate to %v successfully\n", msg)))
 		}
 	}
+
+	if bandwidth := r.FormValue("readBandwidth"); bandwidth != "" {
+		val, err := strconv.Atoi(bandwidth)
+		if err != nil {
+			w.Write([]byte("Set read bandwidth failed\n"))
+		} else {
+			msg := s.ec.SetReadBandwidth(val)
+			w.Write([]byte(fmt.Sprintf("Set read bandwidth to %v successfully\n", msg)))
+		}
+	}
+
+	if bandwidth := r.FormValue("writeBandwidth"); bandwidth != "" {
+		val, err := strconv.Atoi(bandwidth)
+		if err != nil {
+			w.Write([]byte("Set write bandwidth failed\n"))
+		} else {
+			msg := s.ec.SetWriteBandwidth(val)
+			w.Write([]byte(fmt.Sprintf("Set write bandwidth to %v successfully\n", msg)))
+		}
+	}
 }
 
 func (s *Super) umpKey(act string) string {
@@ -753,6 +753,8 @@ func parseMountOption(cfg *config.Config) (*proto.MountOptions, error) {
 	opt.AttrValid = GlobalMountOptions[proto.AttrValid].GetInt64()
 	opt.ReadRate = GlobalMountOptions[proto.ReadRate].GetInt64()
 	opt.WriteRate = GlobalMountOptions[proto.WriteRate].GetInt64()
+	opt.ReadBandwidth = GlobalMountOptions[proto.ReadBandwidth].GetInt64()
+	opt.WriteBandwidth = GlobalMountOptions[proto.WriteBandwidth].GetInt64()
 	opt.EnSyncWrite = GlobalMountOptions[proto.EnSyncWrite].GetInt64()
 	opt.AutoInvalData = GlobalMountOptions[proto.AutoInvalData].GetInt64()
 	opt.UmpDatadir = GlobalMountOptions[proto.WarnLogDir].GetString()
@@ -810,6 +812,9 @@ func parseMountOption(cfg *config.Config) (*proto.MountOptions, error) {
 	opt.MetaSendTimeout = GlobalMountOptions[proto.MetaSendTimeout].GetInt64()
 	opt.MaxStreamerLimit = GlobalMountOptions[proto.MaxStreamerLimit].GetInt64()
 	opt.EnableAudit = GlobalMountOptions[proto.EnableAudit].GetBool()
+	opt.ReportAccessTime = GlobalMountOptions[proto.ReportAccessTime].GetBool()
+	opt.ReadCacheDir = GlobalMountOptions[proto.ReadCacheDir].GetString()
+	opt.ReadCacheSize = GlobalMountOptions[proto.ReadCacheSize].GetInt64()
 	opt.RequestTimeout = GlobalMountOptions[proto.RequestTimeout].GetInt64()
 	opt.MinWriteAbleDataPartitionCnt = int(GlobalMountOptions[proto.MinWriteAbleDataPartitionCnt].GetInt64())
 	opt.FileSystemName = GlobalMountOptions[proto.FileSystemName].GetString()
@@ -21,6 +21,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -510,7 +511,9 @@ func (dp *DataPartition) ExtentWithHoleRepairRead(request repl.PacketInterface,
 			reply.SetData(make([]byte, currReadSize))
 		}
 		reply.SetExtentOffset(offset)
-		crc, err = dp.extentStore.Read(reply.GetExtentID(), offset, int64(currReadSize), reply.GetData(), false)
+		dp.disk.limitRepairRead.Run(int(currReadSize), func() {
+			crc, err = dp.extentStore.Read(reply.GetExtentID(), offset, int64(currReadSize), reply.GetData(), false)
+		})
 		if err != nil {
 			return
 		}
@@ -559,7 +562,23 @@ func (dp *DataPartition) NormalExtentRepairRead(p repl.PacketInterface, connect
 		reply := makeRspPacket(p.GetReqID(), p.GetPartitionID(), p.GetExtentID())
 		reply.SetStartT(p.GetStartT())
 		currReadSize := uint32(util.Min(int(needReplySize), util.ReadBlockSize))
-		if currReadSize == util.ReadBlockSize {
+		// whole blocks with crc are sent from the extent file directly
+		var (
+			blockFile *os.File
+			blockCrc  uint32
+		)
+		if dp.dataNode.zeroCopyRead && currReadSize == util.BlockSize && offset%util.BlockSize == 0 && canSendFile(connect) {
+			var ok bool
+			if blockFile, blockCrc, ok, err = store.BlockFile(reply.GetExtentID(), int(offset/util.BlockSize)); !ok {
+				blockFile = nil
+			}
+			if err != nil {
+				return
+			}
+		}
+		if blockFile != nil {
+			reply.SetData(nil)
+		} else if currReadSize == util.ReadBlockSize {
 			data, _ := proto.Buffers.Get(util.ReadBlockSize)
 			reply.SetData(data)
 		} else {
@@ -573,10 +592,25 @@ func (dp *DataPartition) NormalExtentRepairRead(p repl.PacketInterface, connect
 		p.SetSize(currReadSize)
 		p.SetExtentOffset(offset)
 
-		dp.Disk().allocCheckLimit(proto.IopsReadType, 1)
-		dp.Disk().allocCheckLimit(proto.FlowReadType, currReadSize)
-
-		dp.disk.limitRead.Run(int(currReadSize), func() {
+		limiter := dp.disk.limitRead
+		if isRepairRead {
+			limiter = dp.disk.limitRepairRead
+		} else {
+			dp.Disk().allocCheckLimit(proto.IopsReadType, 1)
+			dp.Disk().allocCheckLimit(proto.FlowReadType, currReadSize)
+		}
+
+		sent := false
+		limiter.Run(int(currReadSize), func() {
+			if blockFile != nil {
+				reply.SetCRC(blockCrc)
+				reply.SetSize(currReadSize)
+				reply.SetResultCode(proto.OpOk)
+				reply.SetOpCode(p.GetOpcode())
+				err = dp.sendBlockFile(connect, reply, blockFile, offset)
+				sent = true
+				return
+			}
 			var crc uint32
 			crc, err = store.Read(reply.GetExtentID(), offset, int64(currReadSize), reply.GetData(), isRepairRead)
 			reply.SetCRC(crc)
@@ -596,12 +630,14 @@ func (dp *DataPartition) NormalExtentRepairRead(p repl.PacketInterface, connect
 		reply.SetResultCode(proto.OpOk)
 		reply.SetOpCode(p.GetOpcode())
 		p.SetResultCode(proto.OpOk)
-		if err = reply.WriteToConn(connect); err != nil {
-			return
+		if !sent {
+			if err = reply.WriteToConn(connect); err != nil {
+				return
+			}
 		}
 		needReplySize -= currReadSize
 		offset += int64(currReadSize)
-		if currReadSize == util.ReadBlockSize {
+		if currReadSize == util.ReadBlockSize && !sent {
 			proto.Buffers.Put(reply.GetData())
 		}
 		if connect.RemoteAddr() != nil {
@@ -613,6 +649,26 @@ func (dp *DataPartition) NormalExtentRepairRead(p repl.PacketInterface, connect
 	return
 }
 
+// sendBlockFile writes the header of the reply and sends the block from the extent file by
+// sendfile. The connection is broken if the block is changed during the sending, so that the
+// reader retries.
+func (dp *DataPartition) sendBlockFile(conn net.Conn, reply repl.PacketInterface, file *os.File, offset int64) (err error) {
+	if err = reply.WriteToConn(conn); err != nil {
+		return
+	}
+	if err = sendFile(conn, file, offset, util.BlockSize); err == nil {
+		var crc uint32
+		crc, err = dp.ExtentStore().BlockCrc(reply.GetExtentID(), int(offset/util.BlockSize))
+		if err == nil && crc != reply.GetCRC() {
+			err = fmt.Errorf("extent(%v) block(%v) is changed during sendfile", reply.GetExtentID(), offset/util.BlockSize)
+		}
+	}
+	if err != nil {
+		conn.Close()
+	}
+	return
+}
+
 // NotifyExtentRepair notifies the followers to repair.
 func (dp *DataPartition) NotifyExtentRepair(members []*DataPartitionRepairTask) (err error) {
 	wg := new(sync.WaitGroup)
@@ -811,8 +867,15 @@ func (dp *DataPartition) streamRepairExtent(remoteExtentInfo *storage.ExtentInfo
 			}
 			log.LogDebugf("streamRepairExtent dp[%v] extent[%v] localExtentInfo[%v] remote info(remoteAvaliSize[%v],isEmptyResponse[%v],currRecoverySize[%v]",
 				dp.partitionID, localExtentInfo, remoteExtentInfo, remoteAvaliSize, isEmptyResponse, currRecoverySize)
+			// holes are punched without data
+			writeSize := int(reply.GetSize())
+			if isEmptyResponse {
+				writeSize = 0
+			}
 			if storage.IsTinyExtent(localExtentInfo.FileID) {
-				err = store.TinyExtentRecover(uint64(localExtentInfo.FileID), int64(currFixOffset), int64(currRecoverySize), reply.GetData(), reply.GetCRC(), isEmptyResponse)
+				dp.disk.limitRepairWrite.Run(writeSize, func() {
+					err = store.TinyExtentRecover(uint64(localExtentInfo.FileID), int64(currFixOffset), int64(currRecoverySize), reply.GetData(), reply.GetCRC(), isEmptyResponse)
+				})
 				if hasRecoverySize+currRecoverySize >= remoteAvaliSize {
 					log.LogInfof("streamRepairTinyExtent(%v) recover fininsh,remoteAvaliSize(%v) "+
 						"hasRecoverySize(%v) currRecoverySize(%v)", dp.applyRepairKey(int(localExtentInfo.FileID)),
@@ -821,7 +884,9 @@ func (dp *DataPartition) streamRepairExtent(remoteExtentInfo *storage.ExtentInfo
 				}
 			} else {
 				log.LogDebugf("streamRepairExtent reply size %v, currFixoffset %v, reply %v ", reply.GetSize(), currFixOffset, reply)
-				_, err = store.Write(uint64(localExtentInfo.FileID), int64(currFixOffset), int64(reply.GetSize()), reply.GetData(), reply.GetCRC(), wType, BufferWrite, isEmptyResponse)
+				dp.disk.limitRepairWrite.Run(writeSize, func() {
+					_, err = store.Write(uint64(localExtentInfo.FileID), int64(currFixOffset), int64(reply.GetSize()), reply.GetData(), reply.GetCRC(), wType, BufferWrite, isEmptyResponse)
+				})
 			}
 			// log.LogDebugf("streamRepairExtent reply size %v, currFixoffset %v, reply %v err %v", reply.Size, currFixOffset, reply, err)
 			// write to the local extent file
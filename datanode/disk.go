@@ -31,6 +31,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/storage"
 	"github.com/cubefs/cubefs/util/exporter"
 	"github.com/cubefs/cubefs/util/loadutil"
 	"github.com/cubefs/cubefs/util/log"
@@ -81,6 +82,9 @@ type Disk struct {
 	limitFactor map[uint32]*rate.Limiter
 	limitRead   *ioLimiter
 	limitWrite  *ioLimiter
+	// repair and decommission IO is limited apart from the client IO
+	limitRepairRead  *ioLimiter
+	limitRepairWrite *ioLimiter
 
 	// diskPartition info
 	diskPartition               *disk.PartitionStat
@@ -89,6 +93,9 @@ type Disk struct {
 	extentRepairReadLimit       chan struct{}
 	enableExtentRepairReadLimit bool
 	extentRepairReadDp          uint64
+	writeCache                  *storage.WriteCache // write back cache on a fast disk, nil if not configured
+	health                      atomic.Value        // *proto.DiskHealth, collected by the disk health check
+	lastIoStat                  *diskIoStat
 }
 
 const (
@@ -135,6 +142,8 @@ func NewDisk(path string, reservedSpace, diskRdonlySpace uint64, maxErrCnt int,
 	d.limitFactor[proto.IopsWriteType] = rate.NewLimiter(rate.Limit(proto.QosDefaultDiskMaxIoLimit), defaultIOLimitBurst)
 	d.limitRead = newIOLimiter(space.dataNode.diskReadFlow, space.dataNode.diskReadIocc)
 	d.limitWrite = newIOLimiter(space.dataNode.diskWriteFlow, space.dataNode.diskWriteIocc)
+	d.limitRepairRead = newIOLimiter(space.dataNode.diskRepairReadFlow, space.dataNode.diskRepairReadIocc)
+	d.limitRepairWrite = newIOLimiter(space.dataNode.diskRepairWriteFlow, space.dataNode.diskRepairWriteIocc)
 
 	d.DiskErrPartitionSet = make(map[uint64]struct{})
 
@@ -147,6 +156,12 @@ func NewDisk(path string, reservedSpace, diskRdonlySpace uint64, maxErrCnt int,
 	d.extentRepairReadLimit = make(chan struct{}, MaxExtentRepairReadLimit)
 	d.extentRepairReadLimit <- struct{}{}
 	d.enableExtentRepairReadLimit = diskEnableReadRepairExtentLimit
+	if cacheConfig, ok := space.dataNode.writeCacheConfigs[path]; ok {
+		if d.writeCache, err = storage.NewWriteCache(cacheConfig); err != nil {
+			log.LogErrorf("action[NewDisk]: disk(%v) failed to create write cache: %v", path, err)
+			return nil, err
+		}
+	}
 	return
 }
 
@@ -217,6 +232,12 @@ func (d *Disk) updateQosLimiter() {
 	d.limitRead.ResetFlow(d.dataNode.diskReadFlow)
 	d.limitWrite.ResetIO(d.dataNode.diskWriteIocc)
 	d.limitWrite.ResetFlow(d.dataNode.diskWriteFlow)
+	log.LogInfof("action[updateQosLimiter] repair read(iocc:%d flow:%d) repair write(iocc:%d flow:%d)",
+		d.dataNode.diskRepairReadIocc, d.dataNode.diskRepairReadFlow, d.dataNode.diskRepairWriteIocc, d.dataNode.diskRepairWriteFlow)
+	d.limitRepairRead.ResetIO(d.dataNode.diskRepairReadIocc)
+	d.limitRepairRead.ResetFlow(d.dataNode.diskRepairReadFlow)
+	d.limitRepairWrite.ResetIO(d.dataNode.diskRepairWriteIocc)
+	d.limitRepairWrite.ResetFlow(d.dataNode.diskRepairWriteFlow)
 }
 
 func (d *Disk) allocCheckLimit(factorType uint32, used uint32) error {
@@ -563,6 +584,21 @@ func (d *Disk) isExpiredPartitionDir(filename string) (isExpiredPartitionDir boo
 	return
 }
 
+// removeWriteCacheJournals removes the journals of the expired partition from the write cache,
+// they are never replayed since the partition is not loaded.
+func (d *Disk) removeWriteCacheJournals(filename string) {
+	if d.writeCache == nil {
+		return
+	}
+	partitionID, _, err := unmarshalPartitionName(filename)
+	if err != nil {
+		return
+	}
+	if err = d.writeCache.RemoveJournals(partitionID); err != nil {
+		log.LogWarnf("action[removeWriteCacheJournals] disk(%v) dp(%v) err(%v)", d.Path, partitionID, err)
+	}
+}
+
 // RestorePartition reads the files stored on the local disk and restores the data partitions.
 func (d *Disk) RestorePartition(visitor PartitionVisitor) (err error) {
 	convert := func(node *proto.DataNodeInfo) *DataNodeInfo {
@@ -607,6 +643,7 @@ func (d *Disk) RestorePartition(visitor PartitionVisitor) (err error) {
 			if d.isExpiredPartitionDir(filename) {
 				name := path.Join(d.Path, filename)
 				toDeleteExpiredPartitionNames = append(toDeleteExpiredPartitionNames, name)
+				d.removeWriteCacheJournals(strings.TrimPrefix(filename, ExpiredPartitionPrefix))
 				log.LogInfof("action[RestorePartition] find expired partition on path(%s)", name)
 			}
 			continue
@@ -627,6 +664,7 @@ func (d *Disk) RestorePartition(visitor PartitionVisitor) (err error) {
 			newName := path.Join(d.Path, ExpiredPartitionPrefix+filename)
 			os.Rename(oldName, newName)
 			toDeleteExpiredPartitionNames = append(toDeleteExpiredPartitionNames, newName)
+			d.removeWriteCacheJournals(filename)
 			continue
 		}
 
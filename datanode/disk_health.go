@@ -0,0 +1,239 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	DefaultDiskHealthCheckInterval = 10 * time.Minute
+	DefaultSmartctlPath            = "smartctl"
+
+	smartctlTimeout = 30 * time.Second
+	procDiskStats   = "/proc/diskstats"
+)
+
+// ATA SMART attributes which indicate the disk is going to fail
+const (
+	smartAttrReallocatedSectors   = 5
+	smartAttrPendingSectors       = 197
+	smartAttrUncorrectableSectors = 198
+)
+
+type smartctlOutput struct {
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	AtaSmartAttributes struct {
+		Table []struct {
+			ID  int `json:"id"`
+			Raw struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NvmeHealthLog *struct {
+		CriticalWarning uint64 `json:"critical_warning"`
+		PercentageUsed  uint64 `json:"percentage_used"`
+		MediaErrors     uint64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+	Temperature struct {
+		Current uint64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+}
+
+// parseSmartctlOutput fills the health with the output of "smartctl -a -j".
+func parseSmartctlOutput(data []byte, health *proto.DiskHealth) (err error) {
+	out := new(smartctlOutput)
+	if err = json.Unmarshal(data, out); err != nil {
+		return
+	}
+	if out.SmartStatus == nil {
+		return fmt.Errorf("no smart status")
+	}
+	health.SmartAvailable = true
+	health.SmartPassed = out.SmartStatus.Passed
+	for _, attr := range out.AtaSmartAttributes.Table {
+		switch attr.ID {
+		case smartAttrReallocatedSectors:
+			health.ReallocatedSectors = attr.Raw.Value
+		case smartAttrPendingSectors:
+			health.PendingSectors = attr.Raw.Value
+		case smartAttrUncorrectableSectors:
+			health.UncorrectableSectors = attr.Raw.Value
+		}
+	}
+	if out.NvmeHealthLog != nil {
+		health.CriticalWarning = out.NvmeHealthLog.CriticalWarning
+		health.PercentageUsed = out.NvmeHealthLog.PercentageUsed
+		health.MediaErrors = out.NvmeHealthLog.MediaErrors
+	}
+	health.Temperature = out.Temperature.Current
+	health.PowerOnHours = out.PowerOnTime.Hours
+	return
+}
+
+// readSmart runs smartctl on the device, the exit code of smartctl is a bit mask
+// which is not zero on a failing disk, so the output is parsed anyway.
+func readSmart(smartctl, device string, health *proto.DiskHealth) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), smartctlTimeout)
+	defer cancel()
+	data, runErr := exec.CommandContext(ctx, smartctl, "-a", "-j", device).Output()
+	if len(data) == 0 {
+		return fmt.Errorf("run %v on %v: %v", smartctl, device, runErr)
+	}
+	return parseSmartctlOutput(data, health)
+}
+
+// wholeDevice returns the device of the disk which the partition belongs to,
+// e.g. /dev/sdb1 -> /dev/sdb, /dev/nvme0n1p1 -> /dev/nvme0n1.
+func wholeDevice(device string) string {
+	name := filepath.Base(device)
+	if strings.HasPrefix(name, "nvme") || strings.HasPrefix(name, "mmcblk") {
+		if i := strings.LastIndexByte(name, 'p'); i > 0 && i < len(name)-1 && isDigits(name[i+1:]) && isDigits(name[i-1:i]) {
+			name = name[:i]
+		}
+	} else if strings.HasPrefix(name, "sd") || strings.HasPrefix(name, "vd") || strings.HasPrefix(name, "hd") ||
+		strings.HasPrefix(name, "xvd") {
+		name = strings.TrimRight(name, "0123456789")
+	}
+	return filepath.Join(filepath.Dir(device), name)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+type diskIoStat struct {
+	reads      uint64
+	readTicks  uint64 // ms
+	writes     uint64
+	writeTicks uint64 // ms
+}
+
+// parseDiskStats parses the io counters of each device in the format of /proc/diskstats.
+func parseDiskStats(r io.Reader) (stats map[string]diskIoStat) {
+	stats = make(map[string]diskIoStat)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 11 {
+			continue
+		}
+		var values [4]uint64
+		var err error
+		for i, idx := range []int{3, 6, 7, 10} {
+			if values[i], err = strconv.ParseUint(fields[idx], 10, 64); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			continue
+		}
+		stats[fields[2]] = diskIoStat{reads: values[0], readTicks: values[1], writes: values[2], writeTicks: values[3]}
+	}
+	return
+}
+
+func avgLatency(ticks, lastTicks, ios, lastIos uint64) float64 {
+	if ios <= lastIos || ticks < lastTicks {
+		return 0
+	}
+	return float64(ticks-lastTicks) / float64(ios-lastIos)
+}
+
+func (d *Disk) getHealth() *proto.DiskHealth {
+	health, _ := d.health.Load().(*proto.DiskHealth)
+	return health
+}
+
+func (d *Disk) collectHealth(smartctl string, ioStats map[string]diskIoStat) {
+	health := &proto.DiskHealth{
+		ReadErrCnt:  d.getReadErrCnt(),
+		WriteErrCnt: d.getWriteErrCnt(),
+		UpdateTime:  time.Now().Unix(),
+	}
+	if d.diskPartition == nil {
+		d.health.Store(health)
+		return
+	}
+	health.Device = wholeDevice(d.diskPartition.Device)
+	if err := readSmart(smartctl, health.Device, health); err != nil {
+		log.LogWarnf("action[collectHealth] disk(%v) read smart err(%v)", d.Path, err)
+	}
+	if stat, ok := ioStats[filepath.Base(d.diskPartition.Device)]; ok {
+		if d.lastIoStat != nil {
+			health.ReadLatencyMs = avgLatency(stat.readTicks, d.lastIoStat.readTicks, stat.reads, d.lastIoStat.reads)
+			health.WriteLatencyMs = avgLatency(stat.writeTicks, d.lastIoStat.writeTicks, stat.writes, d.lastIoStat.writes)
+		}
+		d.lastIoStat = &stat
+	}
+	d.health.Store(health)
+}
+
+func (manager *SpaceManager) collectDisksHealth() {
+	smartctl := manager.dataNode.smartctlPath
+	ioStats := make(map[string]diskIoStat)
+	if f, err := os.Open(procDiskStats); err != nil {
+		log.LogWarnf("action[collectDisksHealth] open %v err(%v)", procDiskStats, err)
+	} else {
+		ioStats = parseDiskStats(f)
+		f.Close()
+	}
+	for _, d := range manager.GetDisks() {
+		d.collectHealth(smartctl, ioStats)
+	}
+}
+
+// StartDiskHealthCheck collects the health indicators of the disks periodically,
+// they are reported to the master by the heartbeat to predict the disk failures.
+func (manager *SpaceManager) StartDiskHealthCheck() {
+	interval := manager.dataNode.diskHealthCheckInterval
+	go func() {
+		manager.collectDisksHealth()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-manager.stopC:
+				return
+			case <-ticker.C:
+				manager.collectDisksHealth()
+			}
+		}
+	}()
+}
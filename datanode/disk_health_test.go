@@ -0,0 +1,94 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSmartctlOutput(t *testing.T) {
+	ata := `{
+		"smart_status": {"passed": false},
+		"ata_smart_attributes": {"table": [
+			{"id": 5, "name": "Reallocated_Sector_Ct", "raw": {"value": 120, "string": "120"}},
+			{"id": 9, "name": "Power_On_Hours", "raw": {"value": 30000, "string": "30000"}},
+			{"id": 197, "name": "Current_Pending_Sector", "raw": {"value": 8, "string": "8"}},
+			{"id": 198, "name": "Offline_Uncorrectable", "raw": {"value": 2, "string": "2"}}
+		]},
+		"temperature": {"current": 41},
+		"power_on_time": {"hours": 30000}
+	}`
+	health := &proto.DiskHealth{}
+	require.NoError(t, parseSmartctlOutput([]byte(ata), health))
+	require.Equal(t, proto.DiskHealth{
+		SmartAvailable:       true,
+		SmartPassed:          false,
+		ReallocatedSectors:   120,
+		PendingSectors:       8,
+		UncorrectableSectors: 2,
+		Temperature:          41,
+		PowerOnHours:         30000,
+	}, *health)
+
+	nvme := `{
+		"smart_status": {"passed": true},
+		"nvme_smart_health_information_log": {"critical_warning": 4, "temperature": 35, "percentage_used": 97, "media_errors": 3},
+		"temperature": {"current": 35},
+		"power_on_time": {"hours": 100}
+	}`
+	health = &proto.DiskHealth{}
+	require.NoError(t, parseSmartctlOutput([]byte(nvme), health))
+	require.True(t, health.SmartPassed)
+	require.EqualValues(t, 4, health.CriticalWarning)
+	require.EqualValues(t, 97, health.PercentageUsed)
+	require.EqualValues(t, 3, health.MediaErrors)
+
+	// smartctl fails to open the device
+	health = &proto.DiskHealth{}
+	require.Error(t, parseSmartctlOutput([]byte(`{"smartctl": {"exit_status": 2}}`), health))
+	require.False(t, health.SmartAvailable)
+}
+
+func TestWholeDevice(t *testing.T) {
+	for device, expected := range map[string]string{
+		"/dev/sdb1":          "/dev/sdb",
+		"/dev/sdb":           "/dev/sdb",
+		"/dev/vdaa12":        "/dev/vdaa",
+		"/dev/nvme0n1p2":     "/dev/nvme0n1",
+		"/dev/nvme0n1":       "/dev/nvme0n1",
+		"/dev/mmcblk0p1":     "/dev/mmcblk0",
+		"/dev/mapper/data-1": "/dev/mapper/data-1",
+	} {
+		require.Equal(t, expected, wholeDevice(device), device)
+	}
+}
+
+func TestParseDiskStats(t *testing.T) {
+	data := `   8       0 sda 100 0 800 50 200 0 1600 300 0 350 350
+   8      16 sdb1 10 0 80 5 bad 0 160 30 0 35 35
+ 259       0 nvme0n1 4000 10 32000 1000 2000 20 16000 6000 0 7000 7000 0 0 0 0
+`
+	stats := parseDiskStats(strings.NewReader(data))
+	require.Len(t, stats, 2)
+	require.Equal(t, diskIoStat{reads: 100, readTicks: 50, writes: 200, writeTicks: 300}, stats["sda"])
+	require.Equal(t, diskIoStat{reads: 4000, readTicks: 1000, writes: 2000, writeTicks: 6000}, stats["nvme0n1"])
+
+	require.Equal(t, 2.0, avgLatency(250, 50, 200, 100))
+	require.Zero(t, avgLatency(50, 50, 100, 100))
+}
@@ -0,0 +1,355 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/repl"
+	"github.com/cubefs/cubefs/storage"
+	"github.com/cubefs/cubefs/util"
+	"github.com/cubefs/cubefs/util/log"
+	"golang.org/x/time/rate"
+)
+
+const (
+	extentScrubIdleInterval  = time.Minute
+	extentScrubRoundInterval = time.Hour
+	maxExtentScrubErrors     = 64
+)
+
+var errExtentScrubStopped = errors.New("extent scrub stopped")
+
+// startExtentScrub verifies the normal extents of all the partitions round by round at the rate
+// of extentScrubRate, the data of each block is checked against its crc in the extent header,
+// and the leader of a partition also compares the extent crcs with the other replicas.
+func (s *DataNode) startExtentScrub() {
+	limiter := rate.NewLimiter(rate.Inf, util.BlockSize)
+	wait := func(n int) error {
+		select {
+		case <-s.stopC:
+			return errExtentScrubStopped
+		default:
+		}
+		scrubRate := atomic.LoadInt64(&s.extentScrubRate)
+		if scrubRate <= 0 {
+			return errExtentScrubStopped
+		}
+		if limit := rate.Limit(scrubRate * util.MB); limiter.Limit() != limit {
+			limiter.SetLimit(limit)
+		}
+		return limiter.WaitN(context.Background(), n)
+	}
+	sleep := func(d time.Duration) bool {
+		select {
+		case <-s.stopC:
+			return false
+		case <-time.After(d):
+			return true
+		}
+	}
+
+	for {
+		if atomic.LoadInt64(&s.extentScrubRate) <= 0 {
+			if !sleep(extentScrubIdleInterval) {
+				return
+			}
+			continue
+		}
+		partitions := make([]*DataPartition, 0)
+		s.space.RangePartitions(func(dp *DataPartition) bool {
+			partitions = append(partitions, dp)
+			return true
+		})
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i].partitionID < partitions[j].partitionID })
+		log.LogInfof("action[startExtentScrub] start to scrub %v partitions", len(partitions))
+		for _, dp := range partitions {
+			if err := dp.scrubExtents(wait, s.extentScrubAutoRepair); err == errExtentScrubStopped {
+				break
+			}
+		}
+		if !sleep(extentScrubRoundInterval) {
+			return
+		}
+	}
+}
+
+func (dp *DataPartition) scrubResult() (lastScrubTime int64, scrubErrors []*proto.ExtentScrubError) {
+	dp.scrubLock.RLock()
+	defer dp.scrubLock.RUnlock()
+	return dp.lastScrubTime, dp.scrubErrors
+}
+
+// scrubExtents scrubs the normal extents of the partition whose crc has been computed, that is,
+// which are not written recently.
+func (dp *DataPartition) scrubExtents(wait func(n int) error, autoRepair bool) (err error) {
+	if !dp.isNormalType() || dp.dataNode.space.Partition(dp.partitionID) == nil {
+		return
+	}
+	store := dp.ExtentStore()
+	extents, _, err := store.GetAllWatermarks(storage.NormalExtentFilter())
+	if err != nil {
+		log.LogWarnf("action[scrubExtents] dp(%v) get extents failed: %v", dp.partitionID, err)
+		return
+	}
+	sort.Sort(storage.ExtentInfoArr(extents))
+
+	start := time.Now()
+	localAddr := dp.dataNode.localServerAddr
+	scrubErrors := make([]*proto.ExtentScrubError, 0)
+	addScrubError := func(scrubErr *proto.ExtentScrubError) {
+		log.LogErrorf("action[scrubExtents] dp(%v) extent(%v) replica(%v) %v, repaired(%v)",
+			dp.partitionID, scrubErr.ExtentID, scrubErr.Addr, scrubErr.Reason, scrubErr.Repaired)
+		if len(scrubErrors) < maxExtentScrubErrors {
+			scrubErrors = append(scrubErrors, scrubErr)
+		}
+	}
+	buf := make([]byte, util.BlockSize)
+	for _, ei := range extents {
+		if dp.dataNode.space.Partition(dp.partitionID) == nil {
+			return
+		}
+		if ei.Crc == 0 || ei.Size == 0 || ei.SnapshotDataOff > util.ExtentSize {
+			continue
+		}
+		var badBlocks []int
+		if badBlocks, err = dp.scrubExtent(ei, buf, wait); err != nil {
+			if err == errExtentScrubStopped {
+				return
+			}
+			log.LogWarnf("action[scrubExtents] dp(%v) scrub extent(%v) failed: %v", dp.partitionID, ei.FileID, err)
+			continue
+		}
+		if len(badBlocks) == 0 {
+			continue
+		}
+		scrubErr := &proto.ExtentScrubError{
+			ExtentID: ei.FileID,
+			Addr:     localAddr,
+			Reason:   fmt.Sprintf("crc mismatch of blocks %v", badBlocks),
+			Time:     time.Now().Unix(),
+		}
+		if autoRepair {
+			if err = dp.repairScrubBlocks(ei.FileID, badBlocks); err != nil {
+				log.LogWarnf("action[scrubExtents] dp(%v) repair extent(%v) failed: %v", dp.partitionID, ei.FileID, err)
+			} else {
+				scrubErr.Repaired = true
+			}
+		}
+		addScrubError(scrubErr)
+	}
+	err = nil
+	if _, isLeader := dp.IsRaftLeader(); isLeader {
+		for _, scrubErr := range dp.compareExtentCrcs(extents) {
+			addScrubError(scrubErr)
+		}
+	}
+
+	dp.scrubLock.Lock()
+	dp.lastScrubTime = time.Now().Unix()
+	dp.scrubErrors = scrubErrors
+	dp.scrubLock.Unlock()
+	log.LogInfof("action[scrubExtents] dp(%v) scrub %v extents with %v errors, cost %v",
+		dp.partitionID, len(extents), len(scrubErrors), time.Since(start))
+	return
+}
+
+// scrubExtent returns the blocks of the extent whose data does not match the crc.
+func (dp *DataPartition) scrubExtent(ei *storage.ExtentInfo, buf []byte, wait func(n int) error) (badBlocks []int, err error) {
+	blockCnt := int((ei.Size + util.BlockSize - 1) / util.BlockSize)
+	for blockNo := 0; blockNo < blockCnt; blockNo++ {
+		if err = wait(util.BlockSize); err != nil {
+			return
+		}
+		var ok bool
+		if ok, err = dp.ExtentStore().VerifyBlock(ei.FileID, blockNo, buf); err != nil {
+			return
+		}
+		if !ok {
+			badBlocks = append(badBlocks, blockNo)
+		}
+	}
+	return
+}
+
+// repairScrubBlocks overwrites the corrupted blocks with the data of another replica,
+// which is checked against the crc of the block in the local extent header. The blocks
+// written after the scrub are skipped, and the writes are throttled by the repair io limiter.
+func (dp *DataPartition) repairScrubBlocks(extentID uint64, badBlocks []int) (err error) {
+	store := dp.ExtentStore()
+	for _, blockNo := range badBlocks {
+		var (
+			ei       *storage.ExtentInfo
+			expected uint32
+			data     []byte
+		)
+		if ei, err = store.Watermark(extentID); err != nil {
+			return
+		}
+		if expected, err = store.BlockCrc(extentID, blockNo); err != nil {
+			return
+		}
+		offset := int64(blockNo) * util.BlockSize
+		size := int64(ei.Size) - offset
+		if size > util.BlockSize {
+			size = util.BlockSize
+		}
+		if expected == 0 || size <= 0 {
+			continue
+		}
+		err = fmt.Errorf("no replica has the data of block %v", blockNo)
+		for _, addr := range dp.getReplicaCopy() {
+			if addr == dp.dataNode.localServerAddr {
+				continue
+			}
+			var readErr error
+			if data, readErr = dp.readRemoteBlock(addr, extentID, offset, size); readErr != nil {
+				log.LogWarnf("action[repairScrubBlocks] dp(%v) extent(%v) read block(%v) from %v failed: %v",
+					dp.partitionID, extentID, blockNo, addr, readErr)
+				continue
+			}
+			if crc32.ChecksumIEEE(data) != expected {
+				log.LogWarnf("action[repairScrubBlocks] dp(%v) extent(%v) block(%v) from %v mismatches crc",
+					dp.partitionID, extentID, blockNo, addr)
+				continue
+			}
+			err = nil
+			break
+		}
+		if err != nil {
+			return
+		}
+		var repaired bool
+		dp.disk.limitRepairWrite.Run(int(size), func() {
+			repaired, err = store.RepairBlock(extentID, blockNo, data, expected)
+		})
+		if err != nil {
+			return
+		}
+		if !repaired {
+			log.LogInfof("action[repairScrubBlocks] dp(%v) extent(%v) block(%v) is changed, skip repair",
+				dp.partitionID, extentID, blockNo)
+			continue
+		}
+		log.LogWarnf("action[repairScrubBlocks] dp(%v) extent(%v) block(%v) is repaired", dp.partitionID, extentID, blockNo)
+	}
+	return
+}
+
+func (dp *DataPartition) readRemoteBlock(addr string, extentID uint64, offset, size int64) (data []byte, err error) {
+	p := repl.NewExtentRepairReadPacket(dp.partitionID, extentID, int(offset), int(size)).(*repl.Packet)
+	p.Opcode = proto.OpStreamFollowerRead
+	conn, err := gConnPool.GetConnect(addr)
+	if err != nil {
+		return
+	}
+	// the remote replies the end of the stream after the data, the connection is not reused
+	defer gConnPool.PutConnect(conn, true)
+	if err = p.WriteToConn(conn); err != nil {
+		return
+	}
+	reply := repl.NewPacket()
+	if err = reply.ReadFromConnWithVer(conn, proto.ReadDeadlineTime); err != nil {
+		return
+	}
+	if reply.ResultCode != proto.OpOk {
+		return nil, fmt.Errorf("read failed: %v", reply.GetResultMsg())
+	}
+	if int64(reply.Size) != size || reply.CRC != crc32.ChecksumIEEE(reply.Data[:reply.Size]) {
+		return nil, fmt.Errorf("invalid reply size(%v) crc(%v)", reply.Size, reply.CRC)
+	}
+	return reply.Data[:reply.Size], nil
+}
+
+// compareExtentCrcs compares the crcs of the extents with the other replicas,
+// and returns the replicas whose crc differs from the majority.
+func (dp *DataPartition) compareExtentCrcs(extents []*storage.ExtentInfo) (scrubErrors []*proto.ExtentScrubError) {
+	localAddr := dp.dataNode.localServerAddr
+	remotes := make(map[string]map[uint64]*storage.ExtentInfo)
+	for _, addr := range dp.getReplicaCopy() {
+		if addr == localAddr {
+			continue
+		}
+		remoteExtents, err := dp.getRemoteExtentInfo(proto.NormalExtentType, nil, addr)
+		if err != nil {
+			log.LogWarnf("action[compareExtentCrcs] dp(%v) get extents from %v failed: %v", dp.partitionID, addr, err)
+			continue
+		}
+		remotes[addr] = make(map[uint64]*storage.ExtentInfo, len(remoteExtents))
+		for _, ei := range remoteExtents {
+			remotes[addr][ei.FileID] = ei
+		}
+	}
+	if len(remotes) == 0 {
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, ei := range extents {
+		if ei.Crc == 0 || ei.SnapshotDataOff > util.ExtentSize {
+			continue
+		}
+		crcs := map[string]uint32{localAddr: ei.Crc}
+		for addr, remoteExtents := range remotes {
+			// crcs of the extents with different sizes are left to the repair
+			if remote, ok := remoteExtents[ei.FileID]; ok && remote.Crc != 0 && remote.Size == ei.Size &&
+				remote.SnapshotDataOff <= util.ExtentSize {
+				crcs[addr] = remote.Crc
+			}
+		}
+		for _, addr := range crcMismatchReplicas(crcs) {
+			scrubErrors = append(scrubErrors, &proto.ExtentScrubError{
+				ExtentID: ei.FileID,
+				Addr:     addr,
+				Reason:   fmt.Sprintf("extent crc(%v) differs from the other replicas", crcs[addr]),
+				Time:     now,
+			})
+		}
+	}
+	return
+}
+
+// crcMismatchReplicas returns the replicas whose crc differs from the majority,
+// all the replicas are returned if there is no majority.
+func crcMismatchReplicas(crcs map[string]uint32) (addrs []string) {
+	counts := make(map[uint32]int)
+	for _, crc := range crcs {
+		counts[crc]++
+	}
+	if len(counts) <= 1 {
+		return
+	}
+	var majority uint32
+	hasMajority := false
+	for crc, count := range counts {
+		if count*2 > len(crcs) {
+			majority, hasMajority = crc, true
+		}
+	}
+	for addr, crc := range crcs {
+		if !hasMajority || crc != majority {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Strings(addrs)
+	return
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrcMismatchReplicas(t *testing.T) {
+	require.Empty(t, crcMismatchReplicas(map[string]uint32{"a": 1}))
+	require.Empty(t, crcMismatchReplicas(map[string]uint32{"a": 1, "b": 1, "c": 1}))
+	require.Equal(t, []string{"c"}, crcMismatchReplicas(map[string]uint32{"a": 1, "b": 1, "c": 2}))
+	// no majority
+	require.Equal(t, []string{"a", "b"}, crcMismatchReplicas(map[string]uint32{"a": 1, "b": 2}))
+	require.Equal(t, []string{"a", "b", "c"}, crcMismatchReplicas(map[string]uint32{"a": 1, "b": 2, "c": 3}))
+}
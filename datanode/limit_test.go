@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cubefs/cubefs/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
@@ -121,3 +122,33 @@ func TestSetLimiter(t *testing.T) {
 	setLimiter(limiter, 0)
 	assert.Equal(t, rate.Inf, limiter.Limit())
 }
+
+func TestUpdateVolQosLimits(t *testing.T) {
+	s := &DataNode{}
+	require.Nil(t, s.getVolQosLimiter("vol1"))
+
+	s.updateVolQosLimits(map[string]*proto.VolQosLimit{
+		"vol1": {IopsReadLimit: 100, FlowWriteLimit: 1024},
+	})
+	limiter := s.getVolQosLimiter("vol1")
+	require.NotNil(t, limiter)
+	require.Equal(t, rate.Limit(100), limiter.limitFactor[proto.IopsReadType].Limit())
+	require.Equal(t, rate.Limit(1024), limiter.limitFactor[proto.FlowWriteType].Limit())
+	require.Equal(t, rate.Inf, limiter.limitFactor[proto.IopsWriteType].Limit())
+
+	s.updateVolQosLimits(nil)
+	require.Nil(t, s.getVolQosLimiter("vol1"))
+	// volumes without limiter are not limited
+	s.volAllocCheckLimit("vol1", proto.FlowReadType, 1)
+}
+
+func TestVolQosLimiterWaitOverBurst(t *testing.T) {
+	l := newVolQosLimiter()
+	limiter := l.limitFactor[proto.FlowReadType]
+	limiter.SetBurst(10)
+	setLimiter(limiter, 100)
+	// the burst is consumed at once, the rest 20 tokens take 200ms
+	start := time.Now()
+	l.wait(proto.FlowReadType, 30)
+	require.True(t, time.Since(start) >= 150*time.Millisecond)
+}
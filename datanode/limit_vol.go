@@ -0,0 +1,117 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+	"golang.org/x/time/rate"
+)
+
+// volQosLimiter limits the read/write iops and flow of a volume on this data node
+type volQosLimiter struct {
+	limit       proto.VolQosLimit
+	limitFactor map[uint32]*rate.Limiter
+}
+
+func newVolQosLimiter() *volQosLimiter {
+	l := &volQosLimiter{limitFactor: make(map[uint32]*rate.Limiter)}
+	l.limitFactor[proto.FlowReadType] = rate.NewLimiter(rate.Inf, proto.QosDefaultBurst)
+	l.limitFactor[proto.FlowWriteType] = rate.NewLimiter(rate.Inf, proto.QosDefaultBurst)
+	l.limitFactor[proto.IopsReadType] = rate.NewLimiter(rate.Inf, defaultIOLimitBurst)
+	l.limitFactor[proto.IopsWriteType] = rate.NewLimiter(rate.Inf, defaultIOLimitBurst)
+	return l
+}
+
+func (l *volQosLimiter) update(limit proto.VolQosLimit) {
+	if l.limit == limit {
+		return
+	}
+	l.limit = limit
+	setLimiter(l.limitFactor[proto.FlowReadType], limit.FlowReadLimit)
+	setLimiter(l.limitFactor[proto.FlowWriteType], limit.FlowWriteLimit)
+	setLimiter(l.limitFactor[proto.IopsReadType], limit.IopsReadLimit)
+	setLimiter(l.limitFactor[proto.IopsWriteType], limit.IopsWriteLimit)
+}
+
+func (l *volQosLimiter) wait(factorType uint32, used uint32) {
+	limiter := l.limitFactor[factorType]
+	if limiter.Limit() == rate.Inf {
+		return
+	}
+	// the request larger than the burst is waited in pieces of the burst
+	n := int(used)
+	for n > 0 {
+		piece := n
+		if burst := limiter.Burst(); burst > 0 && piece > burst {
+			piece = burst
+		}
+		limiter.WaitN(context.Background(), piece)
+		n -= piece
+	}
+}
+
+// volQosLimiters holds the qos limiters of volumes pushed by master through heartbeat
+type volQosLimiters struct {
+	sync.RWMutex
+	limiters map[string]*volQosLimiter
+}
+
+func (s *DataNode) getVolQosLimiter(volName string) *volQosLimiter {
+	s.volQosLimiters.RLock()
+	defer s.volQosLimiters.RUnlock()
+	return s.volQosLimiters.limiters[volName]
+}
+
+// updateVolQosLimits applies the qos limits of volumes from master, volumes not in limits are not limited any more
+func (s *DataNode) updateVolQosLimits(limits map[string]*proto.VolQosLimit) {
+	s.volQosLimiters.Lock()
+	defer s.volQosLimiters.Unlock()
+	if s.volQosLimiters.limiters == nil {
+		s.volQosLimiters.limiters = make(map[string]*volQosLimiter)
+	}
+	for volName := range s.volQosLimiters.limiters {
+		if _, ok := limits[volName]; !ok {
+			delete(s.volQosLimiters.limiters, volName)
+			log.LogInfof("action[updateVolQosLimits] vol[%v] qos limit removed", volName)
+		}
+	}
+	for volName, limit := range limits {
+		if limit == nil {
+			continue
+		}
+		limiter, ok := s.volQosLimiters.limiters[volName]
+		if !ok {
+			limiter = newVolQosLimiter()
+			s.volQosLimiters.limiters[volName] = limiter
+		}
+		if limiter.limit != *limit {
+			log.LogInfof("action[updateVolQosLimits] vol[%v] qos limit %+v", volName, *limit)
+		}
+		limiter.update(*limit)
+	}
+}
+
+// volAllocCheckLimit waits until the volume qos limit of factorType allows the request
+func (s *DataNode) volAllocCheckLimit(volName string, factorType uint32, used uint32) {
+	limiter := s.getVolQosLimiter(volName)
+	if limiter == nil {
+		return
+	}
+	limiter.wait(factorType, used)
+}
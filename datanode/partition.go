@@ -138,6 +138,10 @@ type DataPartition struct {
 	recoverErrCnt              uint64 // donot reset, if reach max err cnt, delete this dp
 
 	diskErrCnt uint64 // number of disk io errors while reading or writing
+
+	scrubLock     sync.RWMutex
+	lastScrubTime int64
+	scrubErrors   []*proto.ExtentScrubError // found in the last scrub
 }
 
 func (dp *DataPartition) IsForbidden() bool {
@@ -330,6 +334,12 @@ func newDataPartition(dpCfg *dataPartitionCfg, disk *Disk, isCreate bool) (dp *D
 		log.LogWarnf("action[newDataPartition] dp %v NewExtentStore failed %v", partitionID, err.Error())
 		return
 	}
+	if disk.writeCache != nil {
+		if err = partition.extentStore.SetWriteCache(disk.writeCache); err != nil {
+			log.LogErrorf("action[newDataPartition] dp %v SetWriteCache failed %v", partitionID, err)
+			return
+		}
+	}
 	// store applyid
 	if err = partition.storeAppliedID(partition.appliedID); err != nil {
 		log.LogErrorf("action[newDataPartition] dp %v initial Apply [%v] failed: %v",
@@ -0,0 +1,73 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+func canSendFile(conn net.Conn) bool {
+	_, ok := conn.(*net.TCPConn)
+	return ok
+}
+
+// sendFile sends the range of the file to the tcp connection by sendfile, the data is not
+// copied to user space. The write deadline of the connection is respected. The descriptor of
+// the file is referenced during the sending, so it's not closed by the eviction of the extent.
+func sendFile(conn net.Conn, file *os.File, offset, size int64) (err error) {
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		return
+	}
+	rawFile, err := file.SyscallConn()
+	if err != nil {
+		return
+	}
+	var werr error
+	cerr := rawFile.Control(func(src uintptr) {
+		werr = rawConn.Write(func(fd uintptr) bool {
+			for size > 0 {
+				n, e := syscall.Sendfile(int(fd), int(src), &offset, int(size))
+				if n > 0 {
+					size -= int64(n)
+				}
+				switch {
+				case e == syscall.EAGAIN:
+					// wait until the connection is writable
+					return false
+				case e == syscall.EINTR:
+					continue
+				case e != nil:
+					err = os.NewSyscallError("sendfile", e)
+					return true
+				case n == 0:
+					err = io.ErrUnexpectedEOF
+					return true
+				}
+			}
+			return true
+		})
+	})
+	if err == nil {
+		err = werr
+	}
+	if err == nil {
+		err = cerr
+	}
+	return
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package datanode
+
+import (
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendFile(t *testing.T) {
+	data := make([]byte, 4<<20)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	name := filepath.Join(t.TempDir(), "extent")
+	require.NoError(t, os.WriteFile(name, data, 0o644))
+	file, err := os.Open(name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		buf, _ := io.ReadAll(conn)
+		received <- buf
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	require.True(t, canSendFile(conn))
+	offset, size := int64(1000), int64(3<<20)
+	require.NoError(t, sendFile(conn, file, offset, size))
+	// beyond the end of the file
+	require.Error(t, sendFile(conn, file, int64(len(data))-10, 100))
+	// the descriptor of a closed file is not used
+	closed, err := os.Open(name)
+	require.NoError(t, err)
+	require.NoError(t, closed.Close())
+	require.Error(t, sendFile(conn, closed, 0, size))
+	conn.Close()
+	buf := <-received
+	require.Equal(t, data[offset:offset+size], buf[:size])
+	require.Equal(t, data[len(data)-10:], buf[size:])
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	require.False(t, canSendFile(c1))
+}
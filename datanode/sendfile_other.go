@@ -0,0 +1,32 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !linux
+
+package datanode
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// sendfile is only used on linux, the reads fall back to the buffered path on other systems.
+func canSendFile(conn net.Conn) bool {
+	return false
+}
+
+func sendFile(conn net.Conn, file *os.File, offset, size int64) error {
+	return syscall.ENOSYS
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/cubefs/cubefs/raftstore"
 	"github.com/cubefs/cubefs/repl"
 	masterSDK "github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/storage"
 	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/atomicutil"
 	"github.com/cubefs/cubefs/util/config"
@@ -117,12 +118,33 @@ const (
 	ConfigDiskWriteIops = "diskWriteIops" // int
 	ConfigDiskWriteFlow = "diskWriteFlow" // int
 
+	// limits of the repair and decommission IO of each disk, separated from the client IO
+	ConfigDiskRepairReadIocc  = "diskRepairReadIocc"  // int
+	ConfigDiskRepairReadFlow  = "diskRepairReadFlow"  // int
+	ConfigDiskRepairWriteIocc = "diskRepairWriteIocc" // int
+	ConfigDiskRepairWriteFlow = "diskRepairWriteFlow" // int
+
 	ConfigServiceIDKey = "serviceIDKey"
 
 	// disk status becomes unavailable if disk error partition count reaches this value
 	ConfigKeyDiskUnavailablePartitionErrorCount = "diskUnavailablePartitionErrorCount"
 	// disk read extent limit
 	ConfigEnableDiskReadExtentLimit = "enableDiskReadRepairExtentLimit" // bool
+
+	// extent scrub
+	ConfigExtentScrubRate       = "extentScrubRate"       // int, MB/s, 0 disables the scrub
+	ConfigExtentScrubAutoRepair = "extentScrubAutoRepair" // bool
+
+	// write cache on fast disks, format "DISK_PATH:CACHE_PATH:CAPACITY[:FLUSH_INTERVAL[:JOURNAL_SYNC]]"
+	ConfigKeyDiskWriteCache         = "diskWriteCache"         // array
+	ConfigKeyWriteCacheMaxWriteSize = "writeCacheMaxWriteSize" // int, bytes
+
+	// send whole blocks of the stream reads by sendfile, false falls back to the buffered path
+	ConfigZeroCopyRead = "zeroCopyRead" // bool
+
+	// health check of the disks by SMART data, reported to the master to predict disk failures
+	ConfigSmartctlPath            = "smartctlPath"            // string
+	ConfigDiskHealthCheckInterval = "diskHealthCheckInterval" // int, seconds
 )
 
 const cpuSampleDuration = 1 * time.Second
@@ -173,6 +195,11 @@ type DataNode struct {
 	diskWriteIocc           int
 	diskWriteIops           int
 	diskWriteFlow           int
+	diskRepairReadIocc      int
+	diskRepairReadFlow      int
+	diskRepairWriteIocc     int
+	diskRepairWriteFlow     int
+	volQosLimiters          volQosLimiters
 	dpMaxRepairErrCnt       uint64
 	clusterUuid             string
 	clusterUuidEnable       bool
@@ -182,6 +209,15 @@ type DataNode struct {
 	// dpRepairTimeOut         uint64
 
 	diskUnavailablePartitionErrorCount uint64 // disk status becomes unavailable when disk error partition count reaches this value
+
+	extentScrubRate       int64 // MB/s
+	extentScrubAutoRepair bool
+
+	writeCacheConfigs map[string]storage.WriteCacheConfig // disk path -> write cache
+	zeroCopyRead      bool
+
+	smartctlPath            string
+	diskHealthCheckInterval time.Duration
 }
 
 type verOp2Phase struct {
@@ -358,6 +394,22 @@ func (s *DataNode) parseConfig(cfg *config.Config) (err error) {
 	s.diskUnavailablePartitionErrorCount = uint64(diskUnavailablePartitionErrorCount)
 	log.LogDebugf("action[parseConfig] load diskUnavailablePartitionErrorCount(%v)", s.diskUnavailablePartitionErrorCount)
 
+	if s.extentScrubRate = cfg.GetInt64(ConfigExtentScrubRate); s.extentScrubRate < 0 {
+		return fmt.Errorf("invalid %v(%v)", ConfigExtentScrubRate, s.extentScrubRate)
+	}
+	s.extentScrubAutoRepair = cfg.GetBool(ConfigExtentScrubAutoRepair)
+	s.zeroCopyRead = cfg.GetBoolWithDefault(ConfigZeroCopyRead, true)
+	log.LogDebugf("action[parseConfig] load zeroCopyRead(%v)", s.zeroCopyRead)
+	if s.smartctlPath = cfg.GetString(ConfigSmartctlPath); s.smartctlPath == "" {
+		s.smartctlPath = DefaultSmartctlPath
+	}
+	s.diskHealthCheckInterval = DefaultDiskHealthCheckInterval
+	if interval := cfg.GetInt64(ConfigDiskHealthCheckInterval); interval > 0 {
+		s.diskHealthCheckInterval = time.Duration(interval) * time.Second
+	}
+	log.LogDebugf("action[parseConfig] load smartctlPath(%v) diskHealthCheckInterval(%v)", s.smartctlPath, s.diskHealthCheckInterval)
+	log.LogDebugf("action[parseConfig] load extentScrubRate(%v) extentScrubAutoRepair(%v)", s.extentScrubRate, s.extentScrubAutoRepair)
+
 	log.LogDebugf("action[parseConfig] load masterAddrs(%v).", MasterClient.Nodes())
 	log.LogDebugf("action[parseConfig] load port(%v).", s.port)
 	log.LogDebugf("action[parseConfig] load zoneName(%v).", s.zoneName)
@@ -373,8 +425,14 @@ func (s *DataNode) initQosLimit(cfg *config.Config) {
 	dn.diskWriteIocc = cfg.GetInt(ConfigDiskWriteIocc)
 	dn.diskWriteIops = cfg.GetInt(ConfigDiskWriteIops)
 	dn.diskWriteFlow = cfg.GetInt(ConfigDiskWriteFlow)
+	dn.diskRepairReadIocc = cfg.GetInt(ConfigDiskRepairReadIocc)
+	dn.diskRepairReadFlow = cfg.GetInt(ConfigDiskRepairReadFlow)
+	dn.diskRepairWriteIocc = cfg.GetInt(ConfigDiskRepairWriteIocc)
+	dn.diskRepairWriteFlow = cfg.GetInt(ConfigDiskRepairWriteFlow)
 	log.LogWarnf("action[initQosLimit] set qos [%v], read(iocc:%d iops:%d flow:%d) write(iocc:%d iops:%d flow:%d)",
 		dn.diskQosEnable, dn.diskReadIocc, dn.diskReadIops, dn.diskReadFlow, dn.diskWriteIocc, dn.diskWriteIops, dn.diskWriteFlow)
+	log.LogWarnf("action[initQosLimit] set repair qos, read(iocc:%d flow:%d) write(iocc:%d flow:%d)",
+		dn.diskRepairReadIocc, dn.diskRepairReadFlow, dn.diskRepairWriteIocc, dn.diskRepairWriteFlow)
 }
 
 func (s *DataNode) updateQosLimit() {
@@ -417,6 +475,10 @@ func (s *DataNode) startSpaceManager(cfg *config.Config) (err error) {
 		}
 	}
 
+	if s.writeCacheConfigs, err = parseWriteCacheConfigs(cfg); err != nil {
+		return err
+	}
+
 	var wg sync.WaitGroup
 	for _, d := range paths {
 		log.LogDebugf("action[startSpaceManager] load disk raw config(%v).", d)
@@ -460,11 +522,45 @@ func (s *DataNode) startSpaceManager(cfg *config.Config) (err error) {
 	wg.Wait()
 	// start async sample
 	s.space.StartDiskSample()
+	s.space.StartDiskHealthCheck()
 	s.updateQosLimit() // load from config
 	s.markAllDiskLoaded()
 	return nil
 }
 
+func parseWriteCacheConfigs(cfg *config.Config) (configs map[string]storage.WriteCacheConfig, err error) {
+	configs = make(map[string]storage.WriteCacheConfig)
+	maxWriteSize := cfg.GetInt64(ConfigKeyWriteCacheMaxWriteSize)
+	if maxWriteSize < 0 || maxWriteSize > util.BlockSize {
+		return nil, fmt.Errorf("invalid %v(%v)", ConfigKeyWriteCacheMaxWriteSize, maxWriteSize)
+	}
+	for _, item := range cfg.GetSlice(ConfigKeyDiskWriteCache) {
+		arr := strings.Split(item.(string), ":")
+		if len(arr) < 3 || len(arr) > 5 {
+			return nil, fmt.Errorf("Invalid disk write cache configuration. Example: DISK_PATH:CACHE_PATH:CAPACITY[:FLUSH_INTERVAL[:JOURNAL_SYNC]]")
+		}
+		wcc := storage.WriteCacheConfig{Path: arr[1], MaxWriteSize: maxWriteSize}
+		if wcc.Capacity, err = strconv.ParseInt(arr[2], 10, 64); err != nil || wcc.Capacity <= 0 {
+			return nil, fmt.Errorf("Invalid write cache capacity of disk %v: %v", arr[0], arr[2])
+		}
+		if len(arr) > 3 {
+			var interval int64
+			if interval, err = strconv.ParseInt(arr[3], 10, 64); err != nil || interval <= 0 {
+				return nil, fmt.Errorf("Invalid write cache flush interval of disk %v: %v", arr[0], arr[3])
+			}
+			wcc.FlushInterval = time.Duration(interval) * time.Second
+		}
+		if len(arr) > 4 {
+			if wcc.SyncJournal, err = strconv.ParseBool(arr[4]); err != nil {
+				return nil, fmt.Errorf("Invalid write cache journal sync of disk %v: %v", arr[0], arr[4])
+			}
+		}
+		configs[arr[0]] = wcc
+		log.LogInfof("action[parseWriteCacheConfigs] disk(%v) write cache(%+v)", arr[0], wcc)
+	}
+	return
+}
+
 func (s *DataNode) markAllDiskLoaded() {
 	s.space.diskMutex.Lock()
 	defer s.space.diskMutex.Unlock()
@@ -649,6 +745,8 @@ func (s *DataNode) registerHandler() {
 	http.HandleFunc("/setDiskBad", s.setDiskBadAPI)
 	http.HandleFunc("/setDiskQos", s.setDiskQos)
 	http.HandleFunc("/getDiskQos", s.getDiskQos)
+	http.HandleFunc("/getConfig", s.getConfig)
+	http.HandleFunc("/setConfig", s.setConfig)
 	http.HandleFunc("/reloadDataPartition", s.reloadDataPartition)
 	http.HandleFunc("/setDiskExtentReadLimitStatus", s.setDiskExtentReadLimitStatus)
 	http.HandleFunc("/queryDiskExtentReadLimitStatus", s.queryDiskExtentReadLimitStatus)
@@ -882,6 +980,7 @@ func (s *DataNode) shallDegrade() bool {
 func (s *DataNode) scheduleTask() {
 	go s.startUpdateNodeInfo()
 	s.scheduleToCheckLackPartitions()
+	go s.startExtentScrub()
 }
 
 func (s *DataNode) startCpuSample() {
@@ -32,21 +32,31 @@ import (
 
 var AutoRepairStatus = true
 
+type writeCacheInfo struct {
+	Path     string `json:"path"`
+	Capacity int64  `json:"capacity"`
+	Used     int64  `json:"used"`
+}
+
 func (s *DataNode) getDiskAPI(w http.ResponseWriter, r *http.Request) {
 	disks := make([]interface{}, 0)
 	for _, diskItem := range s.space.GetDisks() {
 		disk := &struct {
-			Path         string `json:"path"`
-			Total        uint64 `json:"total"`
-			Used         uint64 `json:"used"`
-			Available    uint64 `json:"available"`
-			Unallocated  uint64 `json:"unallocated"`
-			Allocated    uint64 `json:"allocated"`
-			Status       int    `json:"status"`
-			RestSize     uint64 `json:"restSize"`
-			DiskRdoSize  uint64 `json:"diskRdoSize"`
-			Partitions   int    `json:"partitions"`
-			Decommission bool   `json:"decommission"`
+			Path         string            `json:"path"`
+			Total        uint64            `json:"total"`
+			Used         uint64            `json:"used"`
+			Available    uint64            `json:"available"`
+			Unallocated  uint64            `json:"unallocated"`
+			Allocated    uint64            `json:"allocated"`
+			Status       int               `json:"status"`
+			RestSize     uint64            `json:"restSize"`
+			DiskRdoSize  uint64            `json:"diskRdoSize"`
+			Partitions   int               `json:"partitions"`
+			Decommission bool              `json:"decommission"`
+			ReadErrCnt   uint64            `json:"readErrCnt"`
+			WriteErrCnt  uint64            `json:"writeErrCnt"`
+			WriteCache   *writeCacheInfo   `json:"writeCache,omitempty"`
+			Health       *proto.DiskHealth `json:"health,omitempty"`
 		}{
 			Path:         diskItem.Path,
 			Total:        diskItem.Total,
@@ -59,6 +69,16 @@ func (s *DataNode) getDiskAPI(w http.ResponseWriter, r *http.Request) {
 			DiskRdoSize:  diskItem.DiskRdonlySpace,
 			Partitions:   diskItem.PartitionCount(),
 			Decommission: diskItem.GetDecommissionStatus(),
+			ReadErrCnt:   diskItem.getReadErrCnt(),
+			WriteErrCnt:  diskItem.getWriteErrCnt(),
+			Health:       diskItem.getHealth(),
+		}
+		if diskItem.writeCache != nil {
+			disk.WriteCache = &writeCacheInfo{
+				Path:     diskItem.writeCache.Config().Path,
+				Capacity: diskItem.writeCache.Config().Capacity,
+				Used:     diskItem.writeCache.Used(),
+			}
 		}
 		disks = append(disks, disk)
 	}
@@ -379,6 +399,11 @@ func (s *DataNode) setDiskQos(w http.ResponseWriter, r *http.Request) {
 		ConfigDiskWriteIocc: &s.diskWriteIocc,
 		ConfigDiskWriteIops: &s.diskWriteIops,
 		ConfigDiskWriteFlow: &s.diskWriteFlow,
+
+		ConfigDiskRepairReadIocc:  &s.diskRepairReadIocc,
+		ConfigDiskRepairReadFlow:  &s.diskRepairReadFlow,
+		ConfigDiskRepairWriteIocc: &s.diskRepairWriteIocc,
+		ConfigDiskRepairWriteFlow: &s.diskRepairWriteFlow,
 	} {
 		val, err, has := parser(key)
 		if err != nil {
@@ -401,13 +426,17 @@ func (s *DataNode) getDiskQos(w http.ResponseWriter, r *http.Request) {
 	disks := make([]interface{}, 0)
 	for _, diskItem := range s.space.GetDisks() {
 		disk := &struct {
-			Path  string        `json:"path"`
-			Read  LimiterStatus `json:"read"`
-			Write LimiterStatus `json:"write"`
+			Path        string        `json:"path"`
+			Read        LimiterStatus `json:"read"`
+			Write       LimiterStatus `json:"write"`
+			RepairRead  LimiterStatus `json:"repairRead"`
+			RepairWrite LimiterStatus `json:"repairWrite"`
 		}{
-			Path:  diskItem.Path,
-			Read:  diskItem.limitRead.Status(),
-			Write: diskItem.limitWrite.Status(),
+			Path:        diskItem.Path,
+			Read:        diskItem.limitRead.Status(),
+			Write:       diskItem.limitWrite.Status(),
+			RepairRead:  diskItem.limitRepairRead.Status(),
+			RepairWrite: diskItem.limitRepairWrite.Status(),
 		}
 		disks = append(disks, disk)
 	}
@@ -421,6 +450,118 @@ func (s *DataNode) getDiskQos(w http.ResponseWriter, r *http.Request) {
 	s.buildSuccessResp(w, diskStatus)
 }
 
+const configAutoRepair = "autoRepair" // bool
+
+// runtimeConfig returns configs which can be changed at runtime by setConfig,
+// keys are the same as the keys in config file.
+func (s *DataNode) runtimeConfig() map[string]string {
+	return map[string]string{
+		configAutoRepair:    strconv.FormatBool(AutoRepairStatus),
+		CfgMetricsDegrade:   strconv.FormatInt(atomic.LoadInt64(&s.metricsDegrade), 10),
+		ConfigDiskQosEnable: strconv.FormatBool(s.diskQosEnable),
+		ConfigDiskReadIocc:  strconv.Itoa(s.diskReadIocc),
+		ConfigDiskReadIops:  strconv.Itoa(s.diskReadIops),
+		ConfigDiskReadFlow:  strconv.Itoa(s.diskReadFlow),
+		ConfigDiskWriteIocc: strconv.Itoa(s.diskWriteIocc),
+		ConfigDiskWriteIops: strconv.Itoa(s.diskWriteIops),
+		ConfigDiskWriteFlow: strconv.Itoa(s.diskWriteFlow),
+
+		ConfigDiskRepairReadIocc:  strconv.Itoa(s.diskRepairReadIocc),
+		ConfigDiskRepairReadFlow:  strconv.Itoa(s.diskRepairReadFlow),
+		ConfigDiskRepairWriteIocc: strconv.Itoa(s.diskRepairWriteIocc),
+		ConfigDiskRepairWriteFlow: strconv.Itoa(s.diskRepairWriteFlow),
+
+		ConfigExtentScrubRate:       strconv.FormatInt(atomic.LoadInt64(&s.extentScrubRate), 10),
+		ConfigExtentScrubAutoRepair: strconv.FormatBool(s.extentScrubAutoRepair),
+		ConfigZeroCopyRead:          strconv.FormatBool(s.zeroCopyRead),
+	}
+}
+
+func (s *DataNode) getConfig(w http.ResponseWriter, r *http.Request) {
+	s.buildSuccessResp(w, s.runtimeConfig())
+}
+
+// setConfig sets runtime configs in form, nothing is changed if any config is invalid.
+func (s *DataNode) setConfig(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.buildFailureResp(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bools := map[string]*bool{
+		configAutoRepair:            &AutoRepairStatus,
+		ConfigDiskQosEnable:         &s.diskQosEnable,
+		ConfigExtentScrubAutoRepair: &s.extentScrubAutoRepair,
+		ConfigZeroCopyRead:          &s.zeroCopyRead,
+	}
+	ints := map[string]*int{
+		ConfigDiskReadIocc:  &s.diskReadIocc,
+		ConfigDiskReadIops:  &s.diskReadIops,
+		ConfigDiskReadFlow:  &s.diskReadFlow,
+		ConfigDiskWriteIocc: &s.diskWriteIocc,
+		ConfigDiskWriteIops: &s.diskWriteIops,
+		ConfigDiskWriteFlow: &s.diskWriteFlow,
+
+		ConfigDiskRepairReadIocc:  &s.diskRepairReadIocc,
+		ConfigDiskRepairReadFlow:  &s.diskRepairReadFlow,
+		ConfigDiskRepairWriteIocc: &s.diskRepairWriteIocc,
+		ConfigDiskRepairWriteFlow: &s.diskRepairWriteFlow,
+	}
+
+	updates := make([]func(), 0, len(r.Form))
+	qosUpdated := false
+	for key := range r.Form {
+		value := r.FormValue(key)
+		if pVal, ok := bools[key]; ok {
+			val, err := strconv.ParseBool(value)
+			if err != nil {
+				s.buildFailureResp(w, http.StatusBadRequest, fmt.Sprintf("parse %v fail: %v", key, err))
+				return
+			}
+			updates = append(updates, func() { *pVal = val })
+			continue
+		}
+		if pVal, ok := ints[key]; ok {
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				s.buildFailureResp(w, http.StatusBadRequest, fmt.Sprintf("parse %v fail: %v", key, err))
+				return
+			}
+			updates = append(updates, func() { *pVal = val })
+			qosUpdated = true
+			continue
+		}
+		if key == CfgMetricsDegrade {
+			val, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				s.buildFailureResp(w, http.StatusBadRequest, fmt.Sprintf("parse %v fail: %v", key, err))
+				return
+			}
+			updates = append(updates, func() { atomic.StoreInt64(&s.metricsDegrade, val) })
+			continue
+		}
+		if key == ConfigExtentScrubRate {
+			val, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || val < 0 {
+				s.buildFailureResp(w, http.StatusBadRequest, fmt.Sprintf("invalid %v: %v", key, value))
+				return
+			}
+			updates = append(updates, func() { atomic.StoreInt64(&s.extentScrubRate, val) })
+			continue
+		}
+		s.buildFailureResp(w, http.StatusBadRequest, fmt.Sprintf("config %v can not be changed at runtime", key))
+		return
+	}
+
+	for _, update := range updates {
+		update()
+	}
+	if qosUpdated {
+		s.updateQosLimit()
+	}
+	log.LogInfof("action[setConfig] set runtime config %v", r.Form)
+	s.buildSuccessResp(w, s.runtimeConfig())
+}
+
 func (s *DataNode) getSmuxPoolStat() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if !s.enableSmuxConnPool {
@@ -477,6 +477,7 @@ func (s *DataNode) buildHeartBeatResponse(response *proto.DataNodeHeartbeatRespo
 			NeedCompare:                true,
 			DecommissionRepairProgress: partition.decommissionRepairProgress,
 		}
+		vr.LastScrubTime, vr.ScrubErrors = partition.scrubResult()
 		log.LogDebugf("action[Heartbeats] dpid(%v), status(%v) total(%v) used(%v) leader(%v) isLeader(%v).", vr.PartitionID, vr.PartitionStatus, vr.Total, vr.Used, leaderAddr, vr.IsLeader)
 		response.PartitionReports = append(response.PartitionReports, vr)
 		return true
@@ -498,6 +499,7 @@ func (s *DataNode) buildHeartBeatResponse(response *proto.DataNodeHeartbeatRespo
 			TotalPartitionCnt: d.PartitionCount(),
 
 			DiskErrPartitionList: d.GetDiskErrPartitionList(),
+			Health:               d.getHealth(),
 		}
 		response.DiskStats = append(response.DiskStats, bds)
 	}
@@ -134,7 +134,7 @@ func (s *DataNode) OperatePacket(p *repl.Packet, c net.Conn) (err error) {
 	case proto.OpStreamRead:
 		s.handleStreamReadPacket(p, c, StreamRead)
 	case proto.OpStreamFollowerRead:
-		s.extentRepairReadPacket(p, c, StreamRead)
+		s.handleStreamFollowerReadPacket(p, c, StreamRead)
 	case proto.OpExtentRepairRead:
 		s.handleExtentRepairReadPacket(p, c, RepairRead)
 	case proto.OpTinyExtentRepairRead:
@@ -531,6 +531,8 @@ func (s *DataNode) handleHeartbeatPacket(p *repl.Packet) {
 
 			// set volume forbidden
 			s.checkVolumeForbidden(request.ForbiddenVols)
+			// set volume qos limits
+			s.updateVolQosLimits(request.VolQosLimits)
 			// set decommission disks
 			s.checkDecommissionDisks(request.DecommissionDisks)
 			s.diskQosEnableFromMaster = request.EnableDiskQos
@@ -939,6 +941,17 @@ func (s *DataNode) handleStreamReadPacket(p *repl.Packet, connect net.Conn, isRe
 	if err = partition.CheckLeader(p, connect); err != nil {
 		return
 	}
+	s.volAllocCheckLimit(partition.volumeID, proto.FlowReadType, p.Size)
+	s.volAllocCheckLimit(partition.volumeID, proto.IopsReadType, 1)
+	s.extentRepairReadPacket(p, connect, isRepairRead)
+}
+
+// handleStreamFollowerReadPacket serves reads of clients from any replica, which are limited by
+// volume qos as the reads from the leader.
+func (s *DataNode) handleStreamFollowerReadPacket(p *repl.Packet, connect net.Conn, isRepairRead bool) {
+	partition := p.Object.(*DataPartition)
+	s.volAllocCheckLimit(partition.volumeID, proto.FlowReadType, p.Size)
+	s.volAllocCheckLimit(partition.volumeID, proto.IopsReadType, 1)
 	s.extentRepairReadPacket(p, connect, isRepairRead)
 }
 
@@ -101,6 +101,10 @@ func (s *DataNode) checkPartition(p *repl.Packet) (err error) {
 		dp.disk.allocCheckLimit(proto.FlowWriteType, uint32(p.Size))
 		dp.disk.allocCheckLimit(proto.IopsWriteType, 1)
 	}
+	if (p.IsNormalWriteOperation() && p.IsLeaderPacket()) || p.IsRandomWrite() {
+		s.volAllocCheckLimit(dp.volumeID, proto.FlowWriteType, p.Size)
+		s.volAllocCheckLimit(dp.volumeID, proto.IopsWriteType, 1)
+	}
 	return
 }
 
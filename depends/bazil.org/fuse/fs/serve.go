@@ -1488,6 +1488,7 @@ func (c *Server) serveWithTimeOut(r fuse.Request, requestTimeout int64) {
 				}
 				done(err)
 				r.RespondError(err)
+				doneChan <- nil
 				return
 			}
 		}()
@@ -1513,7 +1514,15 @@ func (c *Server) serveWithTimeOut(r fuse.Request, requestTimeout int64) {
 		err := ctx.Err()
 		if err != nil {
 			if err.Error() == "context canceled" {
-				// Context is finished, ignore
+				// The request is interrupted, the handler returns soon as the context is
+				// cancelled, and the kernel waits for the reply of the request.
+				if handleErr := <-doneChan; handleErr != nil {
+					if handleErr == context.Canceled {
+						handleErr = fuse.EINTR
+					}
+					done(handleErr)
+					r.RespondError(handleErr)
+				}
 			} else if err.Error() == "context deadline exceeded" {
 				log.Printf("request timeout, err: [%v], req: [%v], conn: [%v], pid: [%v]", ctx.Err(), r, r.Hdr().Conn, r.Hdr().Pid)
 				done(fuse.ETIME)
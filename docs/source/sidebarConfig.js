@@ -47,6 +47,10 @@ module.exports = [
                     'user-guide/cli/user.md',
                     'user-guide/cli/nodeset.md',
                     'user-guide/cli/quota.md',
+                    'user-guide/cli/snapshot.md',
+                    'user-guide/cli/events.md',
+                    'user-guide/cli/bench.md',
+                    'user-guide/cli/blobstore.md',
                     'user-guide/cli/blobstore-cli.md',
                 ]
             },
@@ -146,6 +150,7 @@ module.exports = [
                     'dev-guide/admin-api/master/data-partition.md',
                     'dev-guide/admin-api/master/management.md',
                     'dev-guide/admin-api/master/user.md',
+                    'dev-guide/admin-api/master/tenant.md',
                     'dev-guide/admin-api/master/failureDomain.md',
                     'dev-guide/admin-api/metanode/partition.md',
                     'dev-guide/admin-api/metanode/inode.md',
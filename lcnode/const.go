@@ -18,6 +18,7 @@ import (
 	"regexp"
 
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
 	"golang.org/x/time/rate"
 )
 
@@ -51,6 +52,7 @@ const (
 	defaultUnboundedChanInitCapacity = 10000
 	defaultLcNodeTaskCountLimit      = 1
 	maxLcNodeTaskCountLimit          = 20
+	defaultTransitionBlockSize       = 8 * util.MB
 )
 
 var (
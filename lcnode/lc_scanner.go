@@ -50,6 +50,7 @@ type LcScanner struct {
 	limiter       *rate.Limiter
 	now           time.Time
 	stopC         chan bool
+	transitioner  *Transitioner
 }
 
 func NewS3Scanner(adminTask *proto.AdminTask, l *LcNode) (*LcScanner, error) {
@@ -69,6 +70,15 @@ func NewS3Scanner(adminTask *proto.AdminTask, l *LcNode) (*LcScanner, error) {
 		return nil, err
 	}
 
+	var transitioner *Transitioner
+	if len(scanTask.Rule.Transitions) > 0 {
+		if transitioner, err = NewTransitioner(l, scanTask.VolName, metaWrapper); err != nil {
+			log.LogErrorf("NewS3Scanner: volume(%v) rule(%v) transitions are skipped: err(%v)",
+				scanTask.VolName, scanTask.Rule.ID, err)
+			err = nil
+		}
+	}
+
 	scanner := &LcScanner{
 		ID:            scanTask.Id,
 		Volume:        scanTask.VolName,
@@ -85,6 +95,7 @@ func NewS3Scanner(adminTask *proto.AdminTask, l *LcNode) (*LcScanner, error) {
 		limiter:       rate.NewLimiter(lcScanLimitPerSecond, defaultLcScanLimitBurst),
 		now:           time.Now(),
 		stopC:         make(chan bool),
+		transitioner:  transitioner,
 	}
 
 	return scanner, nil
@@ -141,6 +152,22 @@ func (s *LcScanner) Start() (err error) {
 
 	go s.scan()
 
+	if s.rule.AbortIncompleteMultipartUpload != nil {
+		// the running job keeps the task from being done until the uploads are aborted
+		if _, err = s.fileRPoll.Submit(s.abortIncompleteMultipartUploads); err != nil {
+			log.LogErrorf("startScan: submit abort multipart uploads err(%v): volume(%v), rule id(%v)",
+				err, s.Volume, s.rule.ID)
+			err = nil
+		}
+		// the tree is not scanned if there are no actions on the objects
+		if s.rule.Expire == nil && len(s.rule.Transitions) == 0 {
+			t := time.Now()
+			response.StartTime = &t
+			go s.checkScanning()
+			return
+		}
+	}
+
 	var currentPath string
 	if len(prefixDirs) > 0 {
 		currentPath = strings.Join(prefixDirs, pathSep)
@@ -319,6 +346,8 @@ func (s *LcScanner) batchHandleFile() {
 	dentries, inodes := s.batchDentries.BatchGetAndClear()
 
 	var expiredDentries []*proto.ScanDentry
+	var transitionInodes []*proto.InodeInfo
+	var transitionClasses []string
 	inodesInfo := s.mw.BatchInodeGet(inodes)
 	for _, info := range inodesInfo {
 		if s.inodeExpired(info, s.rule.Expire) {
@@ -326,6 +355,13 @@ func (s *LcScanner) batchHandleFile() {
 			if d != nil {
 				expiredDentries = append(expiredDentries, d)
 			}
+			continue
+		}
+		if s.transitioner != nil && proto.IsRegular(info.Mode) {
+			if t := s.inodeTransition(info, s.rule.Transitions); t != nil {
+				transitionInodes = append(transitionInodes, info)
+				transitionClasses = append(transitionClasses, t.StorageClass)
+			}
 		}
 	}
 
@@ -350,6 +386,94 @@ func (s *LcScanner) batchHandleFile() {
 		}
 	}
 	atomic.AddInt64(&s.currentStat.ExpiredNum, int64(len(expiredDentries)))
+
+	s.transitionFiles(transitionInodes, transitionClasses)
+}
+
+// abortIncompleteMultipartUploads removes the parts and the sessions of the multipart uploads
+// under the prefix of the rule which are initiated before the days of the rule.
+func (s *LcScanner) abortIncompleteMultipartUploads() {
+	var prefix string
+	if s.rule.Filter != nil {
+		prefix = s.rule.Filter.Prefix
+	}
+	days := s.rule.AbortIncompleteMultipartUpload.DaysAfterInitiation
+	if days <= 0 {
+		return
+	}
+	infos, err := s.mw.BatchGetExpiredMultipart(prefix, days)
+	if err != nil && err != syscall.ENOENT {
+		log.LogWarnf("abortIncompleteMultipartUploads BatchGetExpiredMultipart err: %v, volume: %v, prefix: %v",
+			err, s.Volume, prefix)
+		return
+	}
+	log.LogInfof("abortIncompleteMultipartUploads volume: %v, prefix: %v, days: %v, expired num: %v",
+		s.Volume, prefix, days, len(infos))
+
+	for _, info := range infos {
+		select {
+		case <-s.stopC:
+			return
+		default:
+		}
+		s.limiter.Wait(context.Background())
+		if err = s.mw.AbortExpiredMultipart_ll(info); err != nil {
+			log.LogWarnf("abortIncompleteMultipartUploads AbortExpiredMultipart_ll err: %v, path: %v, multipartID: %v, skip it",
+				err, info.Path, info.MultipartId)
+			atomic.AddInt64(&s.currentStat.ErrorSkippedNum, 1)
+			continue
+		}
+		atomic.AddInt64(&s.currentStat.AbortedMultipartNum, 1)
+	}
+}
+
+// inodeTransition returns the transition of the rule which the inode is due to.
+func (s *LcScanner) inodeTransition(inode *proto.InodeInfo, transitions []*proto.TransitionConfig) *proto.TransitionConfig {
+	for _, t := range transitions {
+		if t.Days <= 0 && t.Date == nil {
+			continue
+		}
+		if s.inodeExpired(inode, &proto.ExpirationConfig{Date: t.Date, Days: t.Days}) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *LcScanner) transitionFiles(inodes []*proto.InodeInfo, storageClasses []string) {
+	if len(inodes) == 0 {
+		return
+	}
+	ids := make([]uint64, 0, len(inodes))
+	for _, info := range inodes {
+		ids = append(ids, info.Inode)
+	}
+	// the storage class is set by the meta node once the inode is transitioned
+	xattrs, err := s.mw.BatchGetXAttr(ids, []string{proto.XAttrKeyStorageClass})
+	if err != nil {
+		log.LogWarnf("transitionFiles BatchGetXAttr err: %v, skip %v inodes", err, len(inodes))
+		atomic.AddInt64(&s.currentStat.ErrorSkippedNum, int64(len(inodes)))
+		return
+	}
+	transitioned := make(map[uint64]bool, len(xattrs))
+	for _, xattr := range xattrs {
+		if len(xattr.Get(proto.XAttrKeyStorageClass)) > 0 {
+			transitioned[xattr.Inode] = true
+		}
+	}
+
+	for i, info := range inodes {
+		if transitioned[info.Inode] {
+			continue
+		}
+		s.limiter.Wait(context.Background())
+		if err = s.transitioner.Transition(info, storageClasses[i]); err != nil {
+			log.LogWarnf("transitionFiles ino(%v) to %v err: %v, skip it", info.Inode, storageClasses[i], err)
+			atomic.AddInt64(&s.currentStat.ErrorSkippedNum, 1)
+			continue
+		}
+		atomic.AddInt64(&s.currentStat.TransitionedNum, 1)
+	}
 }
 
 func (s *LcScanner) inodeExpired(inode *proto.InodeInfo, cond *proto.ExpirationConfig) bool {
@@ -525,6 +649,8 @@ func (s *LcScanner) checkScanning() {
 					response.Volume = s.Volume
 					response.RuleId = s.rule.ID
 					response.ExpiredNum = s.currentStat.ExpiredNum
+					response.TransitionedNum = s.currentStat.TransitionedNum
+					response.AbortedMultipartNum = s.currentStat.AbortedMultipartNum
 					response.FileScannedNum = s.currentStat.FileScannedNum
 					response.DirScannedNum = s.currentStat.DirScannedNum
 					response.TotalInodeScannedNum = s.currentStat.TotalInodeScannedNum
@@ -556,6 +682,9 @@ func (s *LcScanner) Stop() {
 	s.dirRPoll.WaitAndClose()
 	close(s.dirChan.In)
 	close(s.fileChan.In)
+	if s.transitioner != nil {
+		s.transitioner.Close()
+	}
 	s.mw.Close()
 	log.LogInfof("scanner(%v) stopped", s.ID)
 }
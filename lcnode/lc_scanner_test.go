@@ -15,6 +15,7 @@
 package lcnode
 
 import (
+	"syscall"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/cubefs/cubefs/util/routinepool"
 	"github.com/cubefs/cubefs/util/unboundedchan"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 func TestLcScanner(t *testing.T) {
@@ -52,3 +54,69 @@ func TestLcScanner(t *testing.T) {
 	time.Sleep(time.Second * 5)
 	require.Equal(t, true, scanner.DoneScanning())
 }
+
+func TestInodeTransition(t *testing.T) {
+	now := time.Now()
+	scanner := &LcScanner{now: now}
+	inode := &proto.InodeInfo{
+		Inode:      1,
+		CreateTime: now.Add(-10 * 24 * time.Hour),
+	}
+
+	date := now.Add(time.Hour)
+	transitions := []*proto.TransitionConfig{
+		{Days: 30, StorageClass: proto.StorageClassStandardIA},
+		{Date: &date, StorageClass: proto.StorageClassStandardIA},
+	}
+	require.Nil(t, scanner.inodeTransition(inode, transitions))
+	require.Nil(t, scanner.inodeTransition(inode, []*proto.TransitionConfig{{StorageClass: proto.StorageClassStandardIA}}))
+
+	transitions[0].Days = 7
+	require.Equal(t, transitions[0], scanner.inodeTransition(inode, transitions))
+
+	transitions[0].Days = 30
+	date = now.Add(-time.Hour)
+	require.Equal(t, transitions[1], scanner.inodeTransition(inode, transitions))
+}
+
+type multipartMetaWrapper struct {
+	MockMetaWrapper
+	expired []*proto.ExpiredMultipartInfo
+	aborted []string
+}
+
+func (m *multipartMetaWrapper) BatchGetExpiredMultipart(prefix string, days int) ([]*proto.ExpiredMultipartInfo, error) {
+	return m.expired, nil
+}
+
+func (m *multipartMetaWrapper) AbortExpiredMultipart_ll(info *proto.ExpiredMultipartInfo) error {
+	if info.MultipartId == "broken" {
+		return syscall.EIO
+	}
+	m.aborted = append(m.aborted, info.MultipartId)
+	return nil
+}
+
+func TestAbortIncompleteMultipartUploads(t *testing.T) {
+	mw := &multipartMetaWrapper{
+		expired: []*proto.ExpiredMultipartInfo{
+			{Path: "uploads/a", MultipartId: "m1", Inodes: []uint64{10, 11}},
+			{Path: "uploads/b", MultipartId: "broken"},
+			{Path: "uploads/c", MultipartId: "m3", Inodes: []uint64{12}},
+		},
+	}
+	scanner := &LcScanner{
+		mw: mw,
+		rule: &proto.Rule{
+			Filter:                         &proto.FilterConfig{Prefix: "uploads/"},
+			AbortIncompleteMultipartUpload: &proto.AbortIncompleteMultipartUploadConfig{DaysAfterInitiation: 7},
+		},
+		currentStat: &proto.LcNodeRuleTaskStatistics{},
+		limiter:     rate.NewLimiter(rate.Inf, 1),
+		stopC:       make(chan bool),
+	}
+	scanner.abortIncompleteMultipartUploads()
+	require.Equal(t, []string{"m1", "m3"}, mw.aborted)
+	require.Equal(t, int64(2), scanner.currentStat.AbortedMultipartNum)
+	require.Equal(t, int64(1), scanner.currentStat.ErrorSkippedNum)
+}
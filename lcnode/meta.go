@@ -24,5 +24,8 @@ type MetaWrapper interface {
 	DeleteWithCond_ll(parentID, cond uint64, name string, isDir bool, fullPath string) (inode *proto.InodeInfo, err error)
 	Evict(inode uint64, fullPath string) error
 	ReadDirLimit_ll(parentID uint64, from string, limit uint64) ([]proto.Dentry, error)
+	BatchGetXAttr(inodes []uint64, keys []string) ([]*proto.XAttrInfo, error)
+	BatchGetExpiredMultipart(prefix string, days int) ([]*proto.ExpiredMultipartInfo, error)
+	AbortExpiredMultipart_ll(info *proto.ExpiredMultipartInfo) error
 	Close() error
 }
@@ -50,6 +50,18 @@ func (*MockMetaWrapper) ReadDirLimit_ll(parentID uint64, from string, limit uint
 	return nil, nil
 }
 
+func (*MockMetaWrapper) BatchGetXAttr(inodes []uint64, keys []string) ([]*proto.XAttrInfo, error) {
+	return nil, nil
+}
+
+func (*MockMetaWrapper) BatchGetExpiredMultipart(prefix string, days int) ([]*proto.ExpiredMultipartInfo, error) {
+	return nil, nil
+}
+
+func (*MockMetaWrapper) AbortExpiredMultipart_ll(info *proto.ExpiredMultipartInfo) error {
+	return nil
+}
+
 func (*MockMetaWrapper) Close() error {
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 
 	"github.com/cubefs/cubefs/cmd/common"
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/data/blobstore"
 	"github.com/cubefs/cubefs/sdk/master"
 	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/config"
@@ -48,6 +49,7 @@ type LcNode struct {
 	control          common.Control
 	lcScanners       map[string]*LcScanner
 	snapshotScanners map[string]*SnapshotScanner
+	ebsClient        *blobstore.BlobStoreClient
 }
 
 func NewServer() *LcNode {
@@ -249,6 +251,11 @@ func (l *LcNode) register() {
 			}
 			l.nodeID = nodeID
 			log.LogInfof("register: register LcNode: nodeID(%v)", l.nodeID)
+			if ci.EbsAddr != "" && l.ebsClient == nil {
+				if err = l.newEbsClient(ci.EbsAddr); err != nil {
+					log.LogErrorf("register: new ebs client err(%v), transition is not available", err)
+				}
+			}
 			return
 		case <-l.stopC:
 			timer.Stop()
@@ -0,0 +1,164 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package lcnode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/data/blobstore"
+	"github.com/cubefs/cubefs/sdk/data/stream"
+	"github.com/cubefs/cubefs/sdk/meta"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// Transitioner copies the data of the files from the data nodes to the blobstore,
+// then replaces the extents of the inodes with the obj extents on the meta nodes.
+type Transitioner struct {
+	volume    string
+	blockSize int
+	mw        *meta.MetaWrapper
+	ec        *stream.ExtentClient
+	ebsc      *blobstore.BlobStoreClient
+}
+
+func (l *LcNode) newEbsClient(ebsAddr string) (err error) {
+	l.ebsClient, err = blobstore.NewEbsClient(access.Config{
+		ConnMode: access.NoLimitConnMode,
+		Consul: access.ConsulConfig{
+			Address: ebsAddr,
+		},
+		MaxSizePutOnce: int64(defaultTransitionBlockSize),
+		Logger: &access.Logger{
+			Filename: path.Join(log.LogDir, "ebs.log"),
+		},
+	})
+	return
+}
+
+func NewTransitioner(l *LcNode, volume string, mw *meta.MetaWrapper) (t *Transitioner, err error) {
+	if l.ebsClient == nil {
+		return nil, fmt.Errorf("no blobstore of the cluster to transition")
+	}
+	var volView *proto.SimpleVolView
+	if volView, err = l.mc.AdminAPI().GetVolumeSimpleInfo(volume); err != nil {
+		return
+	}
+	if proto.IsCold(volView.VolType) {
+		return nil, fmt.Errorf("transition of cold volume(%v) is not supported", volume)
+	}
+
+	var ec *stream.ExtentClient
+	if ec, err = stream.NewExtentClient(&stream.ExtentConfig{
+		Volume:            volume,
+		VolumeType:        volView.VolType,
+		Masters:           l.masters,
+		FollowerRead:      true,
+		OnAppendExtentKey: mw.AppendExtentKey,
+		OnSplitExtentKey:  mw.SplitExtentKey,
+		OnGetExtents:      mw.GetExtents,
+		OnTruncate:        mw.Truncate,
+	}); err != nil {
+		return
+	}
+	t = &Transitioner{
+		volume:    volume,
+		blockSize: defaultTransitionBlockSize,
+		mw:        mw,
+		ec:        ec,
+		ebsc:      l.ebsClient,
+	}
+	if volView.ObjBlockSize > 0 {
+		t.blockSize = volView.ObjBlockSize
+	}
+	return
+}
+
+// Transition moves the data of the file to the blobstore, it fails if the file is
+// modified in the meantime, and the data written to the blobstore is deleted.
+func (t *Transitioner) Transition(inode *proto.InodeInfo, storageClass string) (err error) {
+	if inode.Size == 0 {
+		return
+	}
+	if err = t.ec.OpenStream(inode.Inode); err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := t.ec.CloseStream(inode.Inode); closeErr != nil {
+			log.LogWarnf("Transition: close stream ino(%v) err(%v)", inode.Inode, closeErr)
+		}
+	}()
+
+	ctx := context.Background()
+	oeks := make([]proto.ObjExtentKey, 0, inode.Size/uint64(t.blockSize)+1)
+	defer func() {
+		if err != nil && len(oeks) > 0 {
+			if delErr := t.ebsc.Delete(oeks); delErr != nil {
+				log.LogErrorf("Transition: delete written blobs of ino(%v) err(%v)", inode.Inode, delErr)
+			}
+		}
+	}()
+
+	buf := make([]byte, t.blockSize)
+	for offset := uint64(0); offset < inode.Size; {
+		size := t.blockSize
+		if rest := inode.Size - offset; rest < uint64(size) {
+			size = int(rest)
+		}
+		var n int
+		if n, err = t.ec.Read(inode.Inode, buf[:size], int(offset), size); err != nil && err != io.EOF {
+			return
+		}
+		if n != size {
+			return fmt.Errorf("read ino(%v) offset(%v) size(%v): short read %v", inode.Inode, offset, size, n)
+		}
+		var location access.Location
+		if location, err = t.ebsc.Write(ctx, t.volume, buf[:n], uint32(n)); err != nil {
+			return
+		}
+		blobs := make([]proto.Blob, 0, len(location.Blobs))
+		for _, info := range location.Blobs {
+			blobs = append(blobs, proto.Blob{
+				MinBid: uint64(info.MinBid),
+				Count:  uint64(info.Count),
+				Vid:    uint64(info.Vid),
+			})
+		}
+		oeks = append(oeks, proto.ObjExtentKey{
+			Cid:        uint64(location.ClusterID),
+			CodeMode:   uint8(location.CodeMode),
+			Size:       location.Size,
+			BlobSize:   location.BlobSize,
+			Blobs:      blobs,
+			BlobsLen:   uint32(len(blobs)),
+			FileOffset: offset,
+			Crc:        location.Crc,
+		})
+		offset += uint64(n)
+	}
+
+	err = t.mw.InodeTransition(inode.Inode, inode.Size, inode.Generation, oeks, storageClass)
+	return
+}
+
+func (t *Transitioner) Close() {
+	if err := t.ec.Close(); err != nil {
+		log.LogWarnf("Transitioner: close extent client of volume(%v) err(%v)", t.volume, err)
+	}
+}
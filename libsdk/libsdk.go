@@ -1225,6 +1225,7 @@ func (c *client) start() (err error) {
 		OnSplitExtentKey:  mw.SplitExtentKey,
 		OnGetExtents:      mw.GetExtents,
 		OnTruncate:        mw.Truncate,
+		OnSetModifyTime:   mw.SetModifyTime,
 		BcacheEnable:      c.enableBcache,
 		OnLoadBcache:      c.bc.Get,
 		OnCacheBcache:     c.bc.Put,
@@ -388,6 +388,8 @@ type updateVolReq struct {
 	txConflictRetryNum      int64
 	txConflictRetryInterval int64
 	txOpLimit               int
+	inlineDirThreshold      int
+	clientQpsLimit          int
 	zoneName                string
 	description             string
 	dpSelectorName          string
@@ -516,6 +518,18 @@ func parseVolUpdateReq(r *http.Request, vol *Vol, req *updateVolReq) (err error)
 		return
 	}
 
+	if req.inlineDirThreshold, err = extractUintWithDefault(r, inlineDirThresholdKey, vol.inlineDirThreshold); err != nil {
+		return
+	}
+	if req.inlineDirThreshold > proto.MaxInlineDirThreshold {
+		err = fmt.Errorf("%v(%v) should not be larger than %v", inlineDirThresholdKey, req.inlineDirThreshold, proto.MaxInlineDirThreshold)
+		return
+	}
+
+	if req.clientQpsLimit, err = extractUintWithDefault(r, clientQpsLimitKey, vol.clientQpsLimit); err != nil {
+		return
+	}
+
 	if req.authenticate, err = extractBoolWithDefault(r, authenticateKey, vol.authenticate); err != nil {
 		return
 	}
@@ -666,6 +680,7 @@ type createVolReq struct {
 	txConflictRetryInterval              int64
 	qosLimitArgs                         *qosArgs
 	clientReqPeriod, clientHitTriggerCnt uint32
+	tenant                               string
 	// cold vol args
 	coldArgs coldVolArgs
 }
@@ -797,6 +812,7 @@ func parseRequestToCreateVol(r *http.Request, req *createVolReq) (err error) {
 	}
 	req.zoneName = extractStr(r, zoneNameKey)
 	req.description = extractStr(r, descriptionKey)
+	req.tenant = extractStr(r, tenantKey)
 	req.domainId, err = extractUint64WithDefault(r, domainIdKey, 0)
 	if err != nil {
 		return
@@ -1104,6 +1120,238 @@ func parseAndExtractThreshold(r *http.Request) (threshold float64, err error) {
 	return
 }
 
+func parseRequestToGetClusterEvents(r *http.Request) (seq uint64, wait time.Duration, filter *clusterEventFilter, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if seq, err = extractUint64WithDefault(r, eventSeqKey, 0); err != nil {
+		return
+	}
+	var waitSec uint64
+	if waitSec, err = extractUint64WithDefault(r, eventWaitKey, 0); err != nil {
+		return
+	}
+	wait = time.Duration(waitSec) * time.Second
+	filter = &clusterEventFilter{severity: r.FormValue(eventSeverityKey), modules: make(map[string]bool)}
+	if _, ok := eventSeverityLevel[filter.severity]; filter.severity != "" && !ok {
+		err = unmatchedKey(eventSeverityKey)
+		return
+	}
+	for _, module := range strings.Split(r.FormValue(eventModuleKey), ",") {
+		if module = strings.TrimSpace(module); module != "" {
+			filter.modules[module] = true
+		}
+	}
+	return
+}
+
+func parseRequestToGetTopologyEvents(r *http.Request) (seq uint64, wait time.Duration, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if seq, err = extractUint64WithDefault(r, eventSeqKey, 0); err != nil {
+		return
+	}
+	var waitSec uint64
+	if waitSec, err = extractUint64WithDefault(r, eventWaitKey, 0); err != nil {
+		return
+	}
+	wait = time.Duration(waitSec) * time.Second
+	return
+}
+
+// parseRequestToSetVolAutoScale overwrites the policy by parameters in the request
+func parseRequestToSetVolAutoScale(r *http.Request, policy *proto.VolAutoScalePolicy) (err error) {
+	if policy.Enable, err = extractBoolWithDefault(r, enableKey, policy.Enable); err != nil {
+		return
+	}
+	if policy.MinWritableSpace, err = extractUint64WithDefault(r, minWritableSpaceKey, policy.MinWritableSpace); err != nil {
+		return
+	}
+	if policy.MinWritableDp, err = extractUintWithDefault(r, minWritableDpKey, policy.MinWritableDp); err != nil {
+		return
+	}
+	if policy.ExpandCount, err = extractUintWithDefault(r, expandCountKey, policy.ExpandCount); err != nil {
+		return
+	}
+	if policy.CooldownSec, err = extractInt64WithDefault(r, cooldownKey, policy.CooldownSec); err != nil {
+		return
+	}
+	return checkVolAutoScalePolicy(policy)
+}
+
+// parseRequestToSetVolPlacement overwrites the placement by parameters in the request
+func parseRequestToSetVolPlacement(r *http.Request, placement *proto.VolPlacement) (err error) {
+	if placement.MinZones, err = extractUintWithDefault(r, minZonesKey, placement.MinZones); err != nil {
+		return
+	}
+	if _, ok := r.Form[excludeNodeSetsKey]; !ok {
+		return
+	}
+	placement.ExcludeNodeSets = make([]uint64, 0)
+	for _, value := range strings.Split(r.FormValue(excludeNodeSetsKey), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		var id uint64
+		if id, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return fmt.Errorf("parse [%v] is not valid nodeset id [%v], err %v", excludeNodeSetsKey, value, err)
+		}
+		placement.ExcludeNodeSets = append(placement.ExcludeNodeSets, id)
+	}
+	return
+}
+
+// parseRequestToSetVolQosLimit overwrites the qos limit by parameters in the request, flow limits take MB as unit
+func parseRequestToSetVolQosLimit(r *http.Request, limit *proto.VolQosLimit) (err error) {
+	if limit.IopsReadLimit, err = extractUint64WithDefault(r, iopsReadLimitKey, limit.IopsReadLimit); err != nil {
+		return
+	}
+	if limit.IopsWriteLimit, err = extractUint64WithDefault(r, iopsWriteLimitKey, limit.IopsWriteLimit); err != nil {
+		return
+	}
+	var flow uint64
+	if flow, err = extractUint64WithDefault(r, flowReadLimitKey, limit.FlowReadLimit/util.MB); err != nil {
+		return
+	}
+	limit.FlowReadLimit = flow * util.MB
+	if flow, err = extractUint64WithDefault(r, flowWriteLimitKey, limit.FlowWriteLimit/util.MB); err != nil {
+		return
+	}
+	limit.FlowWriteLimit = flow * util.MB
+	return
+}
+
+// parseRequestToGetStatHistory returns the time range of the query, it ends now and covers
+// defaultStatHistoryRange seconds by default.
+func parseRequestToGetStatHistory(r *http.Request) (start, end int64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if end, err = extractInt64WithDefault(r, endKey, time.Now().Unix()); err != nil {
+		return
+	}
+	if start, err = extractInt64WithDefault(r, startKey, end-defaultStatHistoryRange); err != nil {
+		return
+	}
+	if start > end {
+		err = fmt.Errorf("start[%v] is later than end[%v]", start, end)
+	}
+	return
+}
+
+// parseRequestToSetReplicaRepair returns nil for arguments not set
+func parseRequestToSetReplicaRepair(r *http.Request) (enable *bool, limitPerZone *uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if value := r.FormValue(enableKey); value != "" {
+		var enabled bool
+		if enabled, err = strconv.ParseBool(value); err != nil {
+			err = fmt.Errorf("parse [%v] is not valid bool, err %v", enableKey, err)
+			return
+		}
+		enable = &enabled
+	}
+	if value := r.FormValue(limitPerZoneKey); value != "" {
+		var limit uint64
+		if limit, err = strconv.ParseUint(value, 10, 64); err != nil {
+			err = fmt.Errorf("parse [%v] is not valid uint, err %v", limitPerZoneKey, err)
+			return
+		}
+		limitPerZone = &limit
+	}
+	if enable == nil && limitPerZone == nil {
+		err = fmt.Errorf("at least one of [%v] and [%v] should be set", enableKey, limitPerZoneKey)
+	}
+	return
+}
+
+// parseRequestToSetNodeSetRebalance returns nil for arguments not set
+func parseRequestToSetNodeSetRebalance(r *http.Request) (enable *bool, rate *uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if value := r.FormValue(enableKey); value != "" {
+		var enabled bool
+		if enabled, err = strconv.ParseBool(value); err != nil {
+			err = fmt.Errorf("parse [%v] is not valid bool, err %v", enableKey, err)
+			return
+		}
+		enable = &enabled
+	}
+	if value := r.FormValue(rateKey); value != "" {
+		var limit uint64
+		if limit, err = strconv.ParseUint(value, 10, 64); err != nil {
+			err = fmt.Errorf("parse [%v] is not valid uint, err %v", rateKey, err)
+			return
+		}
+		rate = &limit
+	}
+	if enable == nil && rate == nil {
+		err = fmt.Errorf("at least one of [%v] and [%v] should be set", enableKey, rateKey)
+	}
+	return
+}
+
+// parseRequestToSetZoneReservation returns nil ratios if they are not set
+func parseRequestToSetZoneReservation(r *http.Request) (name string, dataRatio, metaRatio *float64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name = r.FormValue(nameKey); name == "" {
+		err = keyNotFound(nameKey)
+		return
+	}
+	parseRatio := func(key string) (*float64, error) {
+		value := r.FormValue(key)
+		if value == "" {
+			return nil, nil
+		}
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio >= 1 {
+			return nil, fmt.Errorf("%v should be in [0, 1), but is %v", key, value)
+		}
+		return &ratio, nil
+	}
+	if dataRatio, err = parseRatio(dataReservedRatioKey); err != nil {
+		return
+	}
+	if metaRatio, err = parseRatio(metaReservedRatioKey); err != nil {
+		return
+	}
+	if dataRatio == nil && metaRatio == nil {
+		err = keyNotFound(dataReservedRatioKey + " or " + metaReservedRatioKey)
+	}
+	return
+}
+
+func parseRequestToNodeMaintenance(r *http.Request) (addr string, nodeType uint32, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if addr = r.FormValue(addrKey); addr == "" {
+		err = keyNotFound(addrKey)
+		return
+	}
+	nodeType, err = parseNodeType(r)
+	return
+}
+
+// parseRequestToEnterNodeMaintenance returns ttl of the maintenance in seconds
+func parseRequestToEnterNodeMaintenance(r *http.Request) (addr string, nodeType uint32, ttl int64, err error) {
+	if addr, nodeType, err = parseRequestToNodeMaintenance(r); err != nil {
+		return
+	}
+	if ttl, err = extractInt64WithDefault(r, ttlKey, defaultNodeMaintenanceTTL); err != nil {
+		return
+	}
+	if ttl <= 0 || ttl > maxNodeMaintenanceTTL {
+		err = fmt.Errorf("%v should be in [1, %v] seconds, but is %v", ttlKey, maxNodeMaintenanceTTL, ttl)
+	}
+	return
+}
+
 func parseAndExtractVolDeletionDelayTime(r *http.Request) (volDeletionDelayTimeHour int, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
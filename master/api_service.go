@@ -22,6 +22,7 @@ import (
 	"math"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -101,6 +102,7 @@ type ZoneView struct {
 	DataNodesetSelector string
 	MetaNodesetSelector string
 	NodeSet             map[uint64]*NodeSetView
+	Usage               *proto.ZoneUsageView `json:",omitempty"`
 }
 
 func newZoneView(name string) *ZoneView {
@@ -316,6 +318,266 @@ func (m *Server) setEnableAuditLogForVolume(w http.ResponseWriter, r *http.Reque
 	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set volume audit log to (%v) success", status)))
 }
 
+func (m *Server) setVolTrashInterval(w http.ResponseWriter, r *http.Request) {
+	var (
+		name     string
+		authKey  string
+		interval int64
+		err      error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolSetTrashInterval))
+	defer func() {
+		doStatAndMetric(proto.AdminVolSetTrashInterval, metric, err, map[string]string{exporter.Vol: name})
+		if err != nil {
+			log.LogErrorf("set volume trash interval failed, error: %v", err)
+		} else {
+			log.LogInfof("set volume [%v] trash interval to (%v) success", name, interval)
+		}
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if authKey, err = extractAuthKey(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if interval, err = extractInt64WithDefault(r, trashIntervalKey, -1); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if interval < 0 {
+		err = fmt.Errorf("%v should be specified and not negative", trashIntervalKey)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	vol, err := m.cluster.getVol(name)
+	if err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	if !matchKey(vol.Owner, authKey) {
+		err = proto.ErrVolAuthKeyNotMatch
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	oldInterval := vol.TrashInterval
+	vol.TrashInterval = interval
+	defer func() {
+		if err != nil {
+			vol.TrashInterval = oldInterval
+		}
+	}()
+	if err = m.cluster.syncUpdateVol(vol); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set volume trash interval to (%v) success", interval)))
+}
+
+func (m *Server) setVolAutoScale(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		vol    *Vol
+		policy *proto.VolAutoScalePolicy
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolSetAutoScale))
+	defer func() {
+		doStatAndMetric(proto.AdminVolSetAutoScale, metric, err, nil)
+		if err != nil {
+			log.LogErrorf("set volume auto-scaling failed, error: %v", err)
+		} else {
+			log.LogInfof("set volume [%v] auto-scaling to (%+v) success", name, policy)
+		}
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	policy = vol.getAutoScalePolicy()
+	if err = parseRequestToSetVolAutoScale(r, policy); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	oldPolicy := vol.autoScalePolicy
+	vol.setAutoScalePolicy(policy)
+	if err = m.cluster.syncUpdateVol(vol); err != nil {
+		vol.setAutoScalePolicy(oldPolicy)
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set volume auto-scaling to (%+v) success", *policy)))
+}
+
+func (m *Server) getVolAutoScale(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		vol  *Vol
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolGetAutoScale))
+	defer func() {
+		doStatAndMetric(proto.AdminVolGetAutoScale, metric, err, nil)
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	view := &proto.VolAutoScaleView{
+		Name:    vol.Name,
+		Policy:  vol.getAutoScalePolicy(),
+		Records: vol.autoScaler.getRecords(),
+	}
+	view.WritableSpace, view.WritableDp = vol.writableSpace()
+	sendOkReply(w, r, newSuccessHTTPReply(view))
+}
+
+func (m *Server) setVolPlacement(w http.ResponseWriter, r *http.Request) {
+	var (
+		name      string
+		vol       *Vol
+		placement *proto.VolPlacement
+		err       error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolSetPlacement))
+	defer func() {
+		doStatAndMetric(proto.AdminVolSetPlacement, metric, err, nil)
+		if err != nil {
+			log.LogErrorf("set volume placement failed, error: %v", err)
+		} else {
+			log.LogInfof("set volume [%v] placement to (%+v) success", name, placement)
+		}
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	placement = vol.getPlacement()
+	if err = parseRequestToSetVolPlacement(r, placement); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.checkVolPlacement(vol, placement); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	oldPlacement := vol.getPlacement()
+	vol.setPlacement(placement)
+	if err = m.cluster.syncUpdateVol(vol); err != nil {
+		vol.setPlacement(oldPlacement)
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set volume placement to (%+v) success", *placement)))
+}
+
+// getVolPlacement returns placement constraints of the volume and the schema of them
+func (m *Server) getVolPlacement(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		vol  *Vol
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolGetPlacement))
+	defer func() {
+		doStatAndMetric(proto.AdminVolGetPlacement, metric, err, nil)
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(&proto.VolPlacementView{
+		Name:      vol.Name,
+		Placement: vol.getPlacement(),
+		Schema:    proto.VolPlacementSchema,
+	}))
+}
+
+func (m *Server) setVolQosLimit(w http.ResponseWriter, r *http.Request) {
+	var (
+		name  string
+		vol   *Vol
+		limit *proto.VolQosLimit
+		err   error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolSetQosLimit))
+	defer func() {
+		doStatAndMetric(proto.AdminVolSetQosLimit, metric, err, nil)
+		if err != nil {
+			log.LogErrorf("set volume qos limit failed, error: %v", err)
+		} else {
+			log.LogInfof("set volume [%v] qos limit to (%+v) success", name, limit)
+		}
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	oldLimit := vol.getVolQosLimit()
+	limit = &proto.VolQosLimit{}
+	if oldLimit != nil {
+		*limit = *oldLimit
+	}
+	if err = parseRequestToSetVolQosLimit(r, limit); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	vol.setVolQosLimit(limit)
+	if err = m.cluster.syncUpdateVol(vol); err != nil {
+		vol.setVolQosLimit(oldLimit)
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set volume qos limit to (%+v) success", *limit)))
+}
+
+// getVolQosLimit returns the qos limit of the volume, limits are 0 if the volume is not limited
+func (m *Server) getVolQosLimit(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		vol  *Vol
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolGetQosLimit))
+	defer func() {
+		doStatAndMetric(proto.AdminVolGetQosLimit, metric, err, nil)
+	}()
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
+		return
+	}
+	limit := vol.getVolQosLimit()
+	if limit == nil {
+		limit = &proto.VolQosLimit{}
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(limit))
+}
+
 func (m *Server) setupForbidMetaPartitionDecommission(w http.ResponseWriter, r *http.Request) {
 	var (
 		status bool
@@ -431,12 +693,89 @@ func (m *Server) updateZone(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("update zone status to [%v] successfully", status)))
 }
 
+func (m *Server) setZoneReservation(w http.ResponseWriter, r *http.Request) {
+	var (
+		name      string
+		dataRatio *float64
+		metaRatio *float64
+		zone      *Zone
+		err       error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.SetZoneReservation))
+	defer func() {
+		doStatAndMetric(proto.SetZoneReservation, metric, err, nil)
+	}()
+
+	if name, dataRatio, metaRatio, err = parseRequestToSetZoneReservation(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if zone, err = m.cluster.t.getZone(name); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeZoneNotExists, Msg: err.Error()})
+		return
+	}
+	if err = zone.updateReservedRatio(m.cluster, dataRatio, metaRatio); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	dataReserved, metaReserved := zone.getReservedRatio()
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set reservation of zone [%v] to data [%v] meta [%v] successfully",
+		name, dataReserved, metaReserved)))
+}
+
+// getZoneReservation returns reservation of the zone, or all zones if name is not specified
+func (m *Server) getZoneReservation(w http.ResponseWriter, r *http.Request) {
+	var (
+		zones []*Zone
+		err   error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.GetZoneReservation))
+	defer func() {
+		doStatAndMetric(proto.GetZoneReservation, metric, err, nil)
+	}()
+
+	if name := r.FormValue(nameKey); name != "" {
+		var zone *Zone
+		if zone, err = m.cluster.t.getZone(name); err != nil {
+			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeZoneNotExists, Msg: err.Error()})
+			return
+		}
+		zones = append(zones, zone)
+	} else {
+		zones = m.cluster.t.getAllZones()
+	}
+	views := make([]*proto.ZoneReservationView, 0, len(zones))
+	for _, zone := range zones {
+		view := &proto.ZoneReservationView{
+			Name:       zone.name,
+			DataUsedUp: zone.isReservationUsedUp(TypeDataPartition),
+			MetaUsedUp: zone.isReservationUsedUp(TypeMetaPartition),
+		}
+		view.DataReservedRatio, view.MetaReservedRatio = zone.getReservedRatio()
+		view.DataUsed, view.DataTotal = zone.getDataUsed()
+		view.MetaUsed, view.MetaTotal = zone.getMetaUsed()
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	sendOkReply(w, r, newSuccessHTTPReply(views))
+}
+
 func (m *Server) listZone(w http.ResponseWriter, r *http.Request) {
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.GetAllZones))
 	defer func() {
 		doStatAndMetric(proto.GetAllZones, metric, nil, nil)
 	}()
 
+	withUsage, err := extractBoolWithDefault(r, zoneUsageKey, false)
+	if err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	var usages map[string]*proto.ZoneUsageView
+	if withUsage {
+		usages = m.cluster.getZoneUsages()
+	}
+
 	zones := m.cluster.t.getAllZones()
 	zoneViews := make([]*ZoneView, 0)
 	for _, zone := range zones {
@@ -444,6 +783,9 @@ func (m *Server) listZone(w http.ResponseWriter, r *http.Request) {
 		cv.Status = zone.getStatusToString()
 		cv.DataNodesetSelector = zone.GetDataNodesetSelector()
 		cv.MetaNodesetSelector = zone.GetMetaNodesetSelector()
+		if withUsage {
+			cv.Usage = usages[zone.name]
+		}
 		zoneViews = append(zoneViews, cv)
 	}
 	sendOkReply(w, r, newSuccessHTTPReply(zoneViews))
@@ -614,6 +956,28 @@ func (m *Server) clusterStat(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(cs))
 }
 
+// clusterStatHistory returns persisted snapshots of cluster stats in the time range
+func (m *Server) clusterStatHistory(w http.ResponseWriter, r *http.Request) {
+	var (
+		start, end int64
+		history    *proto.ClusterStatHistory
+		err        error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminClusterStatHistory))
+	defer func() {
+		doStatAndMetric(proto.AdminClusterStatHistory, metric, err, nil)
+	}()
+	if start, end, err = parseRequestToGetStatHistory(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if history, err = m.cluster.getStatHistory(start, end); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(history))
+}
+
 func (m *Server) UidOperate(w http.ResponseWriter, r *http.Request) {
 	var (
 		uid     uint32
@@ -813,6 +1177,47 @@ func (m *Server) getCluster(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(cv))
 }
 
+// getClusterEvents returns cluster events since seq, it waits at most wait seconds for new events
+// if there is no event matched.
+func (m *Server) getClusterEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		seq    uint64
+		wait   time.Duration
+		filter *clusterEventFilter
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminGetClusterEvents))
+	defer func() {
+		doStatAndMetric(proto.AdminGetClusterEvents, metric, err, nil)
+	}()
+
+	if seq, wait, filter, err = parseRequestToGetClusterEvents(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.eventLog.wait(seq, filter, wait, r.Context().Done())))
+}
+
+// getTopologyEvents returns topology events since seq, it waits at most wait seconds for new events
+// if there is no event.
+func (m *Server) getTopologyEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		seq  uint64
+		wait time.Duration
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.ClientTopologyEvents))
+	defer func() {
+		doStatAndMetric(proto.ClientTopologyEvents, metric, err, nil)
+	}()
+
+	if seq, wait, err = parseRequestToGetTopologyEvents(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.topologyEventLog.wait(seq, wait, r.Context().Done())))
+}
+
 func (m *Server) getApiList(w http.ResponseWriter, r *http.Request) {
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminGetMasterApiList))
 	defer func() {
@@ -1930,6 +2335,7 @@ func (m *Server) diagnoseDataPartition(w http.ResponseWriter, r *http.Request) {
 		repUsedSizeDifferDpIDs  []uint64
 		excessReplicaDpIDs      []uint64
 		badDataPartitionInfos   []proto.BadPartitionRepairView
+		scrubErrorDpIDs         []uint64
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminDiagnoseDataPartition))
 	defer func() {
@@ -1976,6 +2382,10 @@ func (m *Server) diagnoseDataPartition(w http.ResponseWriter, r *http.Request) {
 	for _, dp := range excessReplicaDPs {
 		excessReplicaDpIDs = append(excessReplicaDpIDs, dp.PartitionID)
 	}
+	scrubErrorDpIDs = make([]uint64, 0)
+	for _, dp := range m.cluster.checkScrubErrorsOfDataPartitions() {
+		scrubErrorDpIDs = append(scrubErrorDpIDs, dp.PartitionID)
+	}
 
 	// badDataPartitions = m.cluster.getBadDataPartitionsView()
 	badDataPartitionInfos = m.cluster.getBadDataPartitionsRepairView()
@@ -1988,13 +2398,14 @@ func (m *Server) diagnoseDataPartition(w http.ResponseWriter, r *http.Request) {
 		RepFileCountDifferDpIDs:     repFileCountDifferDpIDs,
 		RepUsedSizeDifferDpIDs:      repUsedSizeDifferDpIDs,
 		ExcessReplicaDpIDs:          excessReplicaDpIDs,
+		ScrubErrorDataPartitionIDs:  scrubErrorDpIDs,
 	}
 	log.LogInfof("diagnose dataPartition[%v] inactiveNodes:[%v], corruptDpIDs:[%v], "+
 		"lackReplicaDpIDs:[%v], BadReplicaDataPartitionIDs[%v], "+
-		"repFileCountDifferDpIDs:[%v], RepUsedSizeDifferDpIDs[%v], excessReplicaDpIDs[%v]",
+		"repFileCountDifferDpIDs:[%v], RepUsedSizeDifferDpIDs[%v], excessReplicaDpIDs[%v], scrubErrorDpIDs[%v]",
 		m.cluster.Name, inactiveNodes, corruptDpIDs,
 		lackReplicaDpIDs, badReplicaDpIDs,
-		repFileCountDifferDpIDs, repUsedSizeDifferDpIDs, excessReplicaDpIDs)
+		repFileCountDifferDpIDs, repUsedSizeDifferDpIDs, excessReplicaDpIDs, scrubErrorDpIDs)
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
@@ -2244,9 +2655,10 @@ func (m *Server) checkReplicaNum(r *http.Request, vol *Vol, req *updateVolReq) (
 
 func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 	var (
-		req = &updateVolReq{}
-		vol *Vol
-		err error
+		req    = &updateVolReq{}
+		vol    *Vol
+		err    error
+		dryRun bool
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminUpdateVol))
 	defer func() {
@@ -2276,6 +2688,10 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
 	newArgs := getVolVarargs(vol)
 
@@ -2293,6 +2709,8 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 	newArgs.txConflictRetryNum = req.txConflictRetryNum
 	newArgs.txConflictRetryInterval = req.txConflictRetryInterval
 	newArgs.txOpLimit = req.txOpLimit
+	newArgs.inlineDirThreshold = req.inlineDirThreshold
+	newArgs.clientQpsLimit = req.clientQpsLimit
 	newArgs.enableQuota = req.enableQuota
 	if req.coldArgs != nil {
 		newArgs.coldArgs = req.coldArgs
@@ -2301,6 +2719,16 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 	newArgs.dpReplicaNum = uint8(req.replicaNum)
 	newArgs.dpReadOnlyWhenVolFull = req.dpReadOnlyWhenVolFull
 
+	if dryRun {
+		var plan *proto.DryRunPlan
+		if plan, err = m.cluster.planUpdateVol(proto.AdminUpdateVol, vol, req.authKey, newArgs); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(plan))
+		return
+	}
+
 	log.LogWarnf("[updateVolOut] name [%s], z1 [%s], z2[%s] replicaNum[%v]", req.name, req.zoneName, vol.Name, req.replicaNum)
 	if err = m.cluster.updateVol(req.name, req.authKey, newArgs); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
@@ -2325,6 +2753,7 @@ func (m *Server) volExpand(w http.ResponseWriter, r *http.Request) {
 		msg      string
 		capacity int
 		vol      *Vol
+		dryRun   bool
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolExpand))
 	defer func() {
@@ -2335,6 +2764,10 @@ func (m *Server) volExpand(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
 	if vol, err = m.cluster.getVol(name); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
@@ -2350,6 +2783,16 @@ func (m *Server) volExpand(w http.ResponseWriter, r *http.Request) {
 	newArgs := getVolVarargs(vol)
 	newArgs.capacity = uint64(capacity)
 
+	if dryRun {
+		var plan *proto.DryRunPlan
+		if plan, err = m.cluster.planUpdateVol(proto.AdminVolExpand, vol, authKey, newArgs); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(plan))
+		return
+	}
+
 	if err = m.cluster.updateVol(name, authKey, newArgs); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -2367,6 +2810,7 @@ func (m *Server) volShrink(w http.ResponseWriter, r *http.Request) {
 		msg      string
 		capacity int
 		vol      *Vol
+		dryRun   bool
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminVolShrink))
 	defer func() {
@@ -2377,6 +2821,10 @@ func (m *Server) volShrink(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
 	if vol, err = m.cluster.getVol(name); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
@@ -2392,6 +2840,16 @@ func (m *Server) volShrink(w http.ResponseWriter, r *http.Request) {
 	newArgs := getVolVarargs(vol)
 	newArgs.capacity = uint64(capacity)
 
+	if dryRun {
+		var plan *proto.DryRunPlan
+		if plan, err = m.cluster.planUpdateVol(proto.AdminVolShrink, vol, authKey, newArgs); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(plan))
+		return
+	}
+
 	if err = m.cluster.updateVol(name, authKey, newArgs); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -2521,8 +2979,14 @@ func (m *Server) createVol(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+
 	var vol *Vol
-	if vol, err = m.cluster.createVol(req); err != nil {
+	if req.tenant != "" {
+		vol, err = m.cluster.createTenantVol(req.tenant, req)
+	} else {
+		vol, err = m.cluster.createVol(req)
+	}
+	if err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
@@ -2649,6 +3113,8 @@ func newSimpleView(vol *Vol) (view *proto.SimpleVolView) {
 		TxConflictRetryNum:      vol.txConflictRetryNum,
 		TxConflictRetryInterval: vol.txConflictRetryInterval,
 		TxOpLimit:               vol.txOpLimit,
+		InlineDirThreshold:      vol.inlineDirThreshold,
+		ClientQpsLimit:          vol.clientQpsLimit,
 		NeedToLowerReplica:      vol.NeedToLowerReplica,
 		Authenticate:            vol.authenticate,
 		CrossZone:               vol.crossZone,
@@ -2659,6 +3125,7 @@ func newSimpleView(vol *Vol) (view *proto.SimpleVolView) {
 		DpCnt:                   len(vol.dataPartitions.partitionMap),
 		CreateTime:              time.Unix(vol.createTime, 0).Format(proto.TimeFormat),
 		DeleteLockTime:          vol.DeleteLockTime,
+		TrashInterval:           vol.TrashInterval,
 		Description:             vol.description,
 		DpSelectorName:          vol.dpSelectorName,
 		DpSelectorParm:          vol.dpSelectorParm,
@@ -2678,6 +3145,7 @@ func newSimpleView(vol *Vol) (view *proto.SimpleVolView) {
 		Forbidden:               vol.Forbidden,
 		EnableAuditLog:          vol.EnableAuditLog,
 		DeleteExecTime:          vol.DeleteExecTime,
+		QosLimit:                vol.getVolQosLimit(),
 	}
 
 	vol.uidSpaceManager.RLock()
@@ -2796,6 +3264,7 @@ func (m *Server) getDataNode(w http.ResponseWriter, r *http.Request) {
 		PersistenceDataPartitions: dataNode.PersistenceDataPartitions,
 		BadDisks:                  dataNode.BadDisks,
 		RdOnly:                    dataNode.RdOnly,
+		MaintenanceExpireTime:     dataNode.MaintenanceExpireTime,
 		MaxDpCntLimit:             dataNode.GetDpCntLimit(),
 		CpuUtil:                   dataNode.CpuUtil.Load(),
 		IoUtils:                   dataNode.GetIoUtils(),
@@ -2811,6 +3280,8 @@ func (m *Server) decommissionDataNode(w http.ResponseWriter, r *http.Request) {
 		offLineAddr string
 		raftForce   bool
 		limit       int
+		dryRun      bool
+		node        *DataNode
 		err         error
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.DecommissionDataNode))
@@ -2827,12 +3298,26 @@ func (m *Server) decommissionDataNode(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
-	if _, err = m.cluster.dataNode(offLineAddr); err != nil {
+	if node, err = m.cluster.dataNode(offLineAddr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
 		return
 	}
 
+	if dryRun {
+		var plan *proto.DryRunPlan
+		if plan, err = m.cluster.planDataNodeDecommission(node, limit); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(plan))
+		return
+	}
+
 	if err = m.cluster.migrateDataNode(offLineAddr, "", raftForce, limit); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -2968,6 +3453,35 @@ func (m *Server) cancelDecommissionDataNode(w http.ResponseWriter, r *http.Reque
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
+// Resume the paused decommission of a data node.
+func (m *Server) resumeDecommissionDataNode(w http.ResponseWriter, r *http.Request) {
+	var (
+		node        *DataNode
+		offLineAddr string
+		err         error
+	)
+
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.ResumeDecommissionDataNode))
+	defer func() {
+		doStatAndMetric(proto.ResumeDecommissionDataNode, metric, err, nil)
+	}()
+
+	if offLineAddr, err = parseAndExtractNodeAddr(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if node, err = m.cluster.dataNode(offLineAddr); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
+		return
+	}
+	if err = m.cluster.resumeDecommissionDataNode(node); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("resume decommission data node [%v] success", offLineAddr)))
+}
+
 func (m *Server) setNodeInfoHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		params map[string]interface{}
@@ -3550,6 +4064,59 @@ func (m *Server) setNodeRdOnlyHandler(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("[setNodeRdOnlyHandler] set node %s to rdOnly(%v) success", addr, rdOnly)))
 }
 
+func (m *Server) enterNodeMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr     string
+		nodeType uint32
+		ttl      int64
+		err      error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminEnterNodeMaintenance))
+	defer func() {
+		doStatAndMetric(proto.AdminEnterNodeMaintenance, metric, err, nil)
+	}()
+
+	if addr, nodeType, ttl, err = parseRequestToEnterNodeMaintenance(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	expireTime := time.Now().Unix() + ttl
+	if err = m.cluster.setNodeMaintenance(addr, nodeType, expireTime); err != nil {
+		log.LogErrorf("[enterNodeMaintenanceHandler] node %s enters maintenance, err (%s)", addr, err.Error())
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("node %s enters maintenance until %v success", addr,
+		time.Unix(expireTime, 0).Format(proto.TimeFormat))))
+}
+
+func (m *Server) exitNodeMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr     string
+		nodeType uint32
+		err      error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminExitNodeMaintenance))
+	defer func() {
+		doStatAndMetric(proto.AdminExitNodeMaintenance, metric, err, nil)
+	}()
+
+	if addr, nodeType, err = parseRequestToNodeMaintenance(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if err = m.cluster.setNodeMaintenance(addr, nodeType, 0); err != nil {
+		log.LogErrorf("[exitNodeMaintenanceHandler] node %s exits maintenance, err (%s)", addr, err.Error())
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("node %s exits maintenance success", addr)))
+}
+
 func (m *Server) setDpRdOnlyHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		dpId   uint64
@@ -3968,6 +4535,7 @@ func (m *Server) decommissionDisk(w http.ResponseWriter, r *http.Request) {
 		raftForce             bool
 		limit                 int
 		decommissionType      int
+		dryRun                bool
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.DecommissionDisk))
 	defer func() {
@@ -3983,6 +4551,26 @@ func (m *Server) decommissionDisk(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if dryRun {
+		var (
+			node *DataNode
+			plan *proto.DryRunPlan
+		)
+		if node, err = m.cluster.dataNode(offLineAddr); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
+			return
+		}
+		if plan, err = m.cluster.planDiskDecommission(node, diskPath, limit); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(plan))
+		return
+	}
 	if err = m.cluster.migrateDisk(offLineAddr, diskPath, "", raftForce, limit, diskDisable, uint32(decommissionType)); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -4226,6 +4814,63 @@ func (m *Server) pauseDecommissionDisk(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
+func (m *Server) resumeDecommissionDisk(w http.ResponseWriter, r *http.Request) {
+	var (
+		offLineAddr, diskPath string
+		err                   error
+	)
+
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.ResumeDecommissionDisk))
+	defer func() {
+		doStatAndMetric(proto.ResumeDecommissionDisk, metric, err, nil)
+	}()
+
+	if offLineAddr, diskPath, _, _, _, err = parseReqToDecoDisk(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	key := fmt.Sprintf("%s_%s", offLineAddr, diskPath)
+	value, ok := m.cluster.DecommissionDisks.Load(key)
+	if !ok {
+		err = fmt.Errorf("action[resumeDecommissionDisk]cannot found decommission task for node[%v] disk[%v]",
+			offLineAddr, diskPath)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.resumeDecommissionDisk(value.(*DecommissionDisk)); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("resume decommission data node [%s] disk[%s] success",
+		offLineAddr, diskPath)))
+}
+
+func (m *Server) queryDiskDecoDetail(w http.ResponseWriter, r *http.Request) {
+	var (
+		offLineAddr, diskPath string
+		err                   error
+	)
+
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.QueryDiskDecoDetail))
+	defer func() {
+		doStatAndMetric(proto.QueryDiskDecoDetail, metric, err, nil)
+	}()
+
+	if offLineAddr, diskPath, _, _, _, err = parseReqToDecoDisk(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	key := fmt.Sprintf("%s_%s", offLineAddr, diskPath)
+	value, ok := m.cluster.DecommissionDisks.Load(key)
+	if !ok {
+		err = fmt.Errorf("action[queryDiskDecoDetail]cannot found decommission task for node[%v] disk[%v], "+
+			"may be already offline", offLineAddr, diskPath)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getDiskDecommissionDetail(value.(*DecommissionDisk))))
+}
+
 // handle tasks such as heartbeat，loadDataPartition，deleteDataPartition, etc.
 func (m *Server) handleDataNodeTaskResponse(w http.ResponseWriter, r *http.Request) {
 	var (
@@ -4378,6 +5023,8 @@ func (m *Server) getMetaNode(w http.ResponseWriter, r *http.Request) {
 		MetaPartitionCount:        metaNode.MetaPartitionCount,
 		NodeSetID:                 metaNode.NodeSetID,
 		PersistenceMetaPartitions: metaNode.PersistenceMetaPartitions,
+		RdOnly:                    metaNode.RdOnly,
+		MaintenanceExpireTime:     metaNode.MaintenanceExpireTime,
 		CpuUtil:                   metaNode.CpuUtil.Load(),
 	}
 	sendOkReply(w, r, newSuccessHTTPReply(metaNodeInfo))
@@ -4492,6 +5139,52 @@ func (m *Server) loadMetaPartition(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
+// splitMetaPartition splits the unallocated inode range of a meta partition into a new partition.
+func (m *Server) splitMetaPartition(w http.ResponseWriter, r *http.Request) {
+	var (
+		volName     string
+		partitionID uint64
+		end         uint64
+		vol         *Vol
+		mp          *MetaPartition
+		nextMp      *MetaPartition
+		err         error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminSplitMetaPartition))
+	defer func() {
+		doStatAndMetric(proto.AdminSplitMetaPartition, metric, err, map[string]string{exporter.Vol: volName})
+	}()
+
+	if volName, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if partitionID, err = extractMetaPartitionID(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if end, err = extractUint64(r, endKey); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(volName); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+		return
+	}
+	if mp, err = vol.metaPartition(partitionID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrMetaPartitionNotExists))
+		return
+	}
+	if nextMp, err = vol.splitMetaPartitionOnline(m.cluster, mp, end); err != nil {
+		log.LogErrorf("action[splitMetaPartition] vol[%v] mp[%v] end[%v] err[%v]", volName, partitionID, end, err)
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	msg := fmt.Sprintf("split meta partition[%v] successfully, new partition[%v] range[%v,%v]",
+		partitionID, nextMp.PartitionID, nextMp.Start, nextMp.End)
+	sendOkReply(w, r, newSuccessHTTPReply(msg))
+}
+
 func (m *Server) migrateMetaNodeHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		srcAddr    string
@@ -4554,6 +5247,8 @@ func (m *Server) decommissionMetaNode(w http.ResponseWriter, r *http.Request) {
 		rstMsg      string
 		offLineAddr string
 		limit       int
+		dryRun      bool
+		node        *MetaNode
 		err         error
 	)
 	metric := exporter.NewTPCnt(apiToMetricsName(proto.DecommissionMetaNode))
@@ -4565,11 +5260,24 @@ func (m *Server) decommissionMetaNode(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
-	if _, err = m.cluster.metaNode(offLineAddr); err != nil {
+	if node, err = m.cluster.metaNode(offLineAddr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrMetaNodeNotExists))
 		return
 	}
+	if dryRun {
+		var plan *proto.DryRunPlan
+		if plan, err = m.cluster.planMetaNodeDecommission(node, limit); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(plan))
+		return
+	}
 	if err = m.cluster.migrateMetaNode(offLineAddr, "", limit); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -5079,6 +5787,7 @@ func (m *Server) getMetaPartition(w http.ResponseWriter, r *http.Request) {
 				InodeCount:  mp.Replicas[i].InodeCount,
 				DentryCount: mp.Replicas[i].DentryCount,
 				MaxInode:    mp.Replicas[i].MaxInodeID,
+				Qps:         mp.Replicas[i].Qps,
 			}
 		}
 		forbidden := true
@@ -5108,6 +5817,7 @@ func (m *Server) getMetaPartition(w http.ResponseWriter, r *http.Request) {
 			OfflinePeerID: mp.OfflinePeerID,
 			LoadResponse:  mp.LoadResponse,
 			Forbidden:     forbidden,
+			Qps:           mp.Qps,
 		}
 		return mpInfo
 	}
@@ -5575,6 +6285,29 @@ func (m *Server) queryDataNodeDecoProgress(w http.ResponseWriter, r *http.Reques
 	sendOkReply(w, r, newSuccessHTTPReply(resp))
 }
 
+func (m *Server) queryDataNodeDecoDetail(w http.ResponseWriter, r *http.Request) {
+	var (
+		offLineAddr string
+		err         error
+		dn          *DataNode
+	)
+
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.QueryDataNodeDecoDetail))
+	defer func() {
+		doStatAndMetric(proto.QueryDataNodeDecoDetail, metric, err, nil)
+	}()
+
+	if offLineAddr, err = parseReqToDecoDataNodeProgress(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if dn, err = m.cluster.dataNode(offLineAddr); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getDataNodeDecommissionDetail(dn)))
+}
+
 func (m *Server) queryDataNodeDecoFailedDps(w http.ResponseWriter, r *http.Request) {
 	var (
 		offLineAddr string
@@ -5719,6 +6452,101 @@ func (m *Server) setCheckDataReplicasEnable(w http.ResponseWriter, r *http.Reque
 		"set checkDataReplicasEnable to [%v] successfully", enable)))
 }
 
+func (m *Server) setReplicaRepair(w http.ResponseWriter, r *http.Request) {
+	var (
+		enable       *bool
+		limitPerZone *uint64
+		err          error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminSetReplicaRepair))
+	defer func() {
+		doStatAndMetric(proto.AdminSetReplicaRepair, metric, err, nil)
+	}()
+
+	if enable, limitPerZone, err = parseRequestToSetReplicaRepair(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	oldEnable, oldLimit := m.cluster.replicaRepairEnable, m.cluster.replicaRepairLimitPerZone
+	if enable != nil {
+		m.cluster.replicaRepairEnable = *enable
+	}
+	if limitPerZone != nil {
+		m.cluster.replicaRepairLimitPerZone = *limitPerZone
+	}
+	if err = m.cluster.syncPutCluster(); err != nil {
+		m.cluster.replicaRepairEnable, m.cluster.replicaRepairLimitPerZone = oldEnable, oldLimit
+		log.LogErrorf("action[setReplicaRepair] syncPutCluster failed %v", err)
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrPersistenceByRaft))
+		return
+	}
+
+	log.LogInfof("action[setReplicaRepair] enable[%v] limitPerZone[%v]",
+		m.cluster.replicaRepairEnable, m.cluster.getReplicaRepairLimitPerZone())
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set replica repair enable[%v] limitPerZone[%v] successfully",
+		m.cluster.replicaRepairEnable, m.cluster.getReplicaRepairLimitPerZone())))
+}
+
+func (m *Server) getReplicaRepairBacklog(w http.ResponseWriter, r *http.Request) {
+	var err error
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.AdminGetReplicaRepairBacklog))
+	defer func() {
+		doStatAndMetric(proto.AdminGetReplicaRepairBacklog, metric, err, nil)
+	}()
+
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getReplicaRepairBacklog()))
+}
+
+func (m *Server) setNodeSetRebalance(w http.ResponseWriter, r *http.Request) {
+	var (
+		enable *bool
+		rate   *uint64
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.SetNodeSetRebalance))
+	defer func() {
+		doStatAndMetric(proto.SetNodeSetRebalance, metric, err, nil)
+	}()
+
+	if enable, rate, err = parseRequestToSetNodeSetRebalance(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	oldEnable, oldRate := m.cluster.nodeSetRebalanceEnable, m.cluster.nodeSetRebalanceRate
+	if enable != nil {
+		m.cluster.nodeSetRebalanceEnable = *enable
+	}
+	if rate != nil {
+		m.cluster.nodeSetRebalanceRate = *rate
+	}
+	if err = m.cluster.syncPutCluster(); err != nil {
+		m.cluster.nodeSetRebalanceEnable, m.cluster.nodeSetRebalanceRate = oldEnable, oldRate
+		log.LogErrorf("action[setNodeSetRebalance] syncPutCluster failed %v", err)
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrPersistenceByRaft))
+		return
+	}
+	if !oldEnable && m.cluster.nodeSetRebalanceEnable {
+		m.cluster.nodeSetRebalanceMgr.start()
+	}
+
+	log.LogInfof("action[setNodeSetRebalance] enable[%v] rate[%v]",
+		m.cluster.nodeSetRebalanceEnable, m.cluster.getNodeSetRebalanceRate())
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set nodeset rebalance enable[%v] rate[%v] successfully",
+		m.cluster.nodeSetRebalanceEnable, m.cluster.getNodeSetRebalanceRate())))
+}
+
+func (m *Server) getNodeSetRebalance(w http.ResponseWriter, r *http.Request) {
+	var err error
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.GetNodeSetRebalance))
+	defer func() {
+		doStatAndMetric(proto.GetNodeSetRebalance, metric, err, nil)
+	}()
+
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getNodeSetRebalanceStatus()))
+}
+
 func (m *Server) setFileStats(w http.ResponseWriter, r *http.Request) {
 	var (
 		err    error
@@ -6280,6 +7108,30 @@ func (m *Server) queryDisks(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(infos))
 }
 
+func (m *Server) queryPredictedFailureDisks(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		nodeAddr string
+		infos    proto.DiskInfos
+	)
+
+	metric := exporter.NewTPCnt("req_queryPredictedFailureDisks")
+	defer func() {
+		metric.Set(err)
+	}()
+
+	nodeAddr = r.FormValue(addrKey)
+	if len(nodeAddr) > 0 {
+		if !checkIp(nodeAddr) {
+			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: fmt.Errorf("addr not legal").Error()})
+			return
+		}
+	}
+
+	infos.Disks = m.cluster.getPredictedFailureDisks(nodeAddr)
+	sendOkReply(w, r, newSuccessHTTPReply(infos))
+}
+
 func (m *Server) queryDiskDetail(w http.ResponseWriter, r *http.Request) {
 	var (
 		err        error
@@ -6331,6 +7183,9 @@ func (m *Server) queryDiskDetail(w http.ResponseWriter, r *http.Request) {
 
 		TotalPartitionCnt:    targetDisk.TotalPartitionCnt,
 		DiskErrPartitionList: targetDisk.DiskErrPartitionList,
+
+		Health:         targetDisk.Health,
+		FailureReasons: predictDiskFailure(targetDisk.Health),
 	}
 
 	sendOkReply(w, r, newSuccessHTTPReply(diskDetail))
@@ -0,0 +1,226 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/exporter"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+func (m *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	var (
+		role *proto.RoleInfo
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleCreate))
+	defer func() {
+		doStatAndMetric(proto.RoleCreate, metric, err, nil)
+	}()
+
+	if role, err = parseRequestToCreateRole(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.createRole(role); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(role))
+}
+
+func (m *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleDelete))
+	defer func() {
+		doStatAndMetric(proto.RoleDelete, metric, err, nil)
+	}()
+
+	if name, err = parseRole(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.deleteRole(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	msg := fmt.Sprintf("delete role[%v] successfully", name)
+	log.LogWarn(msg)
+	sendOkReply(w, r, newSuccessHTTPReply(msg))
+}
+
+func (m *Server) updateRole(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		role *proto.RoleInfo
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleUpdate))
+	defer func() {
+		doStatAndMetric(proto.RoleUpdate, metric, err, nil)
+	}()
+
+	if name, err = parseRole(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	role, err = m.cluster.updateRole(name, func(role *proto.RoleInfo) error {
+		if actions := extractActions(r); len(actions) > 0 {
+			if err := checkAdminActions(actions); err != nil {
+				return err
+			}
+			role.Actions = actions
+		}
+		role.Description = extractStrWithDefault(r, descriptionKey, role.Description)
+		return nil
+	})
+	if err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(role))
+}
+
+func (m *Server) getRole(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		role *proto.RoleInfo
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleGet))
+	defer func() {
+		doStatAndMetric(proto.RoleGet, metric, err, nil)
+	}()
+
+	if name, err = parseRole(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if role, err = m.cluster.roleMgr.getRole(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(role))
+}
+
+func (m *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	var err error
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleList))
+	defer func() {
+		doStatAndMetric(proto.RoleList, metric, err, nil)
+	}()
+
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.roleMgr.listRoles()))
+}
+
+func (m *Server) grantRole(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		userID string
+		role   *proto.RoleInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleGrant))
+	defer func() {
+		doStatAndMetric(proto.RoleGrant, metric, err, nil)
+	}()
+
+	if name, userID, err = parseRoleAndUser(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if _, err = m.user.getUserInfo(userID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if role, err = m.cluster.grantRole(name, userID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(role))
+}
+
+func (m *Server) revokeRole(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		userID string
+		role   *proto.RoleInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.RoleRevoke))
+	defer func() {
+		doStatAndMetric(proto.RoleRevoke, metric, err, nil)
+	}()
+
+	if name, userID, err = parseRoleAndUser(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if role, err = m.cluster.revokeRole(name, userID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(role))
+}
+
+func parseRole(r *http.Request) (name string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name = r.FormValue(roleKey); name == "" {
+		err = keyNotFound(roleKey)
+		return
+	}
+	if !volNameRegexp.MatchString(name) {
+		err = fmt.Errorf("invalid role name [%v]", name)
+	}
+	return
+}
+
+func parseRoleAndUser(r *http.Request) (name, userID string, err error) {
+	if name, err = parseRole(r); err != nil {
+		return
+	}
+	userID, err = extractUser(r)
+	return
+}
+
+// extractActions returns actions separated by comma
+func extractActions(r *http.Request) (actions []string) {
+	actions = make([]string, 0)
+	for _, action := range strings.Split(r.FormValue(actionsKey), commaSplit) {
+		if action = strings.TrimSpace(action); action != "" {
+			actions = append(actions, action)
+		}
+	}
+	return
+}
+
+func parseRequestToCreateRole(r *http.Request) (role *proto.RoleInfo, err error) {
+	role = &proto.RoleInfo{Users: make([]string, 0)}
+	if role.Name, err = parseRole(r); err != nil {
+		return
+	}
+	role.Actions = extractActions(r)
+	role.Description = extractStr(r, descriptionKey)
+	return
+}
@@ -0,0 +1,288 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/exporter"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+func (m *Server) createTenant(w http.ResponseWriter, r *http.Request) {
+	var (
+		tenant *proto.TenantInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantCreate))
+	defer func() {
+		doStatAndMetric(proto.TenantCreate, metric, err, nil)
+	}()
+
+	if tenant, err = parseRequestToCreateTenant(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.createTenant(tenant); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func (m *Server) deleteTenant(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		err  error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantDelete))
+	defer func() {
+		doStatAndMetric(proto.TenantDelete, metric, err, nil)
+	}()
+
+	if name, err = parseTenant(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.deleteTenant(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	msg := fmt.Sprintf("delete tenant[%v] successfully", name)
+	log.LogWarn(msg)
+	sendOkReply(w, r, newSuccessHTTPReply(msg))
+}
+
+func (m *Server) updateTenant(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		tenant *proto.TenantInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantUpdate))
+	defer func() {
+		doStatAndMetric(proto.TenantUpdate, metric, err, nil)
+	}()
+
+	if name, err = parseTenant(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	tenant, err = m.cluster.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		if err := parseTenantLimits(r, tenant); err != nil {
+			return err
+		}
+		return m.cluster.checkTenantLimits(tenant)
+	})
+	if err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func (m *Server) getTenant(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		tenant *proto.TenantInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantGet))
+	defer func() {
+		doStatAndMetric(proto.TenantGet, metric, err, nil)
+	}()
+
+	if name, err = parseTenant(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if tenant, err = m.cluster.tenantMgr.getTenant(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func (m *Server) listTenants(w http.ResponseWriter, r *http.Request) {
+	var err error
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantList))
+	defer func() {
+		doStatAndMetric(proto.TenantList, metric, err, nil)
+	}()
+
+	tenants := m.cluster.tenantMgr.listTenants()
+	views := make([]*proto.TenantView, 0, len(tenants))
+	for _, tenant := range tenants {
+		views = append(views, m.cluster.newTenantView(tenant))
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(views))
+}
+
+func (m *Server) addTenantUser(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		userID string
+		tenant *proto.TenantInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantAddUser))
+	defer func() {
+		doStatAndMetric(proto.TenantAddUser, metric, err, nil)
+	}()
+
+	if name, userID, err = parseTenantAndUser(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if _, err = m.user.getUserInfo(userID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if tenant, err = m.cluster.addTenantUser(name, userID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func (m *Server) removeTenantUser(w http.ResponseWriter, r *http.Request) {
+	var (
+		name   string
+		userID string
+		tenant *proto.TenantInfo
+		err    error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantRemoveUser))
+	defer func() {
+		doStatAndMetric(proto.TenantRemoveUser, metric, err, nil)
+	}()
+
+	if name, userID, err = parseTenantAndUser(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if tenant, err = m.cluster.removeTenantUser(name, userID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func (m *Server) addTenantVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    string
+		volName string
+		tenant  *proto.TenantInfo
+		err     error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantAddVol))
+	defer func() {
+		doStatAndMetric(proto.TenantAddVol, metric, err, map[string]string{exporter.Vol: volName})
+	}()
+
+	if name, volName, err = parseTenantAndVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if tenant, err = m.cluster.addTenantVol(name, volName); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func (m *Server) removeTenantVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    string
+		volName string
+		tenant  *proto.TenantInfo
+		err     error
+	)
+	metric := exporter.NewTPCnt(apiToMetricsName(proto.TenantRemoveVol))
+	defer func() {
+		doStatAndMetric(proto.TenantRemoveVol, metric, err, map[string]string{exporter.Vol: volName})
+	}()
+
+	if name, volName, err = parseTenantAndVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if tenant, err = m.cluster.removeTenantVol(name, volName); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.newTenantView(tenant)))
+}
+
+func parseTenant(r *http.Request) (name string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	return extractTenant(r)
+}
+
+func extractTenant(r *http.Request) (name string, err error) {
+	if name = r.FormValue(tenantKey); name == "" {
+		err = keyNotFound(tenantKey)
+		return
+	}
+	if !volNameRegexp.MatchString(name) {
+		err = fmt.Errorf("invalid tenant name [%v]", name)
+	}
+	return
+}
+
+func parseTenantAndUser(r *http.Request) (name, userID string, err error) {
+	if name, err = parseTenant(r); err != nil {
+		return
+	}
+	userID, err = extractUser(r)
+	return
+}
+
+func parseTenantAndVol(r *http.Request) (name, volName string, err error) {
+	if name, err = parseTenant(r); err != nil {
+		return
+	}
+	volName, err = extractName(r)
+	return
+}
+
+// parseTenantLimits sets the limits and description of the tenant from the request, limits not in the
+// request are kept.
+func parseTenantLimits(r *http.Request, tenant *proto.TenantInfo) (err error) {
+	if tenant.CapacityLimit, err = extractUint64WithDefault(r, capacityLimitKey, tenant.CapacityLimit); err != nil {
+		return
+	}
+	if tenant.VolCountLimit, err = extractUintWithDefault(r, volCountLimitKey, tenant.VolCountLimit); err != nil {
+		return
+	}
+	if tenant.UserCountLimit, err = extractUintWithDefault(r, userCountLimitKey, tenant.UserCountLimit); err != nil {
+		return
+	}
+	tenant.Description = extractStrWithDefault(r, descriptionKey, tenant.Description)
+	return
+}
+
+func parseRequestToCreateTenant(r *http.Request) (tenant *proto.TenantInfo, err error) {
+	tenant = &proto.TenantInfo{Vols: make([]string, 0), Users: make([]string, 0)}
+	if tenant.Name, err = parseTenant(r); err != nil {
+		return
+	}
+	err = parseTenantLimits(r, tenant)
+	return
+}
@@ -65,6 +65,7 @@ func (m *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
+	m.cluster.revokeUserRoles(userID)
 	msg := fmt.Sprintf("delete user[%v] successfully", userID)
 	log.LogWarn(msg)
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
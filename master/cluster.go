@@ -83,12 +83,19 @@ type Cluster struct {
 	QosAcceptLimit               *rate.Limiter
 	apiLimiter                   *ApiLimiter
 	DecommissionDisks            sync.Map
+	diskFailureTracker           *diskFailureTracker
 	DecommissionLimit            uint64
 	EnableAutoDecommissionDisk   bool
 	AutoDecommissionDiskMux      sync.Mutex
 	checkAutoCreateDataPartition bool
 	masterClient                 *masterSDK.MasterClient
 	checkDataReplicasEnable      bool
+	replicaRepairEnable          bool
+	replicaRepairLimitPerZone    uint64
+	replicaRepairMgr             *replicaRepairManager
+	nodeSetRebalanceEnable       bool
+	nodeSetRebalanceRate         uint64
+	nodeSetRebalanceMgr          *nodeSetRebalanceManager
 	fileStatsEnable              bool
 	clusterUuid                  string
 	clusterUuidEnable            bool
@@ -100,6 +107,10 @@ type Cluster struct {
 	lcNodes                      sync.Map
 	lcMgr                        *lifecycleManager
 	snapshotMgr                  *snapshotDelManager
+	eventLog                     *clusterEventLog
+	topologyEventLog             *topologyEventLog
+	tenantMgr                    *tenantManager
+	roleMgr                      *roleManager
 	DecommissionDiskFactor       float64
 	S3ApiQosQuota                *sync.Map // (api,uid,limtType) -> limitQuota
 }
@@ -351,6 +362,7 @@ func newCluster(name string, leaderInfo *LeaderInfo, fsm *MetadataFsm, partition
 	c.QosAcceptLimit = rate.NewLimiter(rate.Limit(c.cfg.QosMasterAcceptLimit), proto.QosDefaultBurst)
 	c.apiLimiter = newApiLimiter()
 	c.DecommissionLimit = defaultDecommissionParallelLimit
+	c.diskFailureTracker = newDiskFailureTracker()
 	c.checkAutoCreateDataPartition = false
 	c.masterClient = masterSDK.NewMasterClient(nil, false)
 	c.inodeCountNotEqualMP = new(sync.Map)
@@ -361,6 +373,12 @@ func newCluster(name string, leaderInfo *LeaderInfo, fsm *MetadataFsm, partition
 	c.snapshotMgr = newSnapshotManager()
 	c.snapshotMgr.cluster = c
 	c.S3ApiQosQuota = new(sync.Map)
+	c.eventLog = newClusterEventLog(defaultClusterEventCapacity)
+	c.topologyEventLog = newTopologyEventLog(defaultTopologyEventCapacity)
+	c.tenantMgr = newTenantManager()
+	c.roleMgr = newRoleManager()
+	c.replicaRepairMgr = newReplicaRepairManager()
+	c.nodeSetRebalanceMgr = newNodeSetRebalanceManager()
 	return
 }
 
@@ -372,6 +390,7 @@ func (c *Cluster) scheduleTask() {
 	c.scheduleToCheckHeartbeat()
 	c.scheduleToCheckMetaPartitions()
 	c.scheduleToUpdateStatInfo()
+	c.scheduleToSnapshotStat()
 	c.scheduleToManageDp()
 	c.scheduleToCheckVolStatus()
 	c.scheduleToCheckVolQos()
@@ -384,6 +403,8 @@ func (c *Cluster) scheduleTask() {
 	c.scheduleToCheckDecommissionDataNode()
 	c.scheduleToCheckDecommissionDisk()
 	c.scheduleToCheckDataReplicas()
+	c.scheduleToRepairReplicas()
+	c.scheduleToRebalanceNodeSets()
 	c.scheduleToLcScan()
 	c.scheduleToSnapshotDelVerScan()
 	c.scheduleToBadDisk()
@@ -622,6 +643,7 @@ func (c *Cluster) checkDataPartitions() {
 		if c.checkAutoCreateDataPartition {
 			vol.checkAutoDataPartitionCreation(c)
 		}
+		vol.checkAutoScale(c)
 	}
 }
 
@@ -708,11 +730,17 @@ func (c *Cluster) checkLeaderAddr() {
 
 func (c *Cluster) checkDataNodeHeartbeat() {
 	tasks := make([]*proto.AdminTask, 0)
+	volQosLimits := c.getVolQosLimitShares()
 	c.dataNodes.Range(func(addr, dataNode interface{}) bool {
 		node := dataNode.(*DataNode)
-		node.checkLiveness()
+		if node.checkLiveness() {
+			c.recordEvent(proto.EventSeverityWarning, eventModuleDataNode, "datanode %v is down, last report at %v",
+				node.Addr, node.ReportTime.Format(proto.TimeFormat))
+		}
+		c.checkDataNodeMaintenance(node)
 		task := node.createHeartbeatTask(c.masterAddr(), c.diskQosEnable)
 		hbReq := task.Request.(*proto.HeartBeatRequest)
+		hbReq.VolQosLimits = volQosLimits
 		c.volMutex.RLock()
 		defer c.volMutex.RUnlock()
 		for _, vol := range c.vols {
@@ -733,7 +761,11 @@ func (c *Cluster) checkMetaNodeHeartbeat() {
 
 	c.metaNodes.Range(func(addr, metaNode interface{}) bool {
 		node := metaNode.(*MetaNode)
-		node.checkHeartbeat()
+		if node.checkHeartbeat() {
+			c.recordEvent(proto.EventSeverityWarning, eventModuleMetaNode, "metanode %v is down, last report at %v",
+				node.Addr, node.ReportTime.Format(proto.TimeFormat))
+		}
+		c.checkMetaNodeMaintenance(node)
 		task := node.createHeartbeatTask(c.masterAddr(), c.fileStatsEnable)
 		hbReq := task.Request.(*proto.HeartBeatRequest)
 
@@ -1062,6 +1094,7 @@ func (c *Cluster) addMetaNode(nodeAddr, zoneName string, nodesetId uint64) (id u
 
 	c.addNodeSetGrp(ns, false)
 	c.metaNodes.Store(nodeAddr, metaNode)
+	c.recordTopologyEvent(proto.TopologyEventMetaNodeAdded, nodeAddr, zoneName)
 	log.LogInfof("action[addMetaNode],clusterID[%v] metaNodeAddr:%v,nodeSetId[%v],capacity[%v]",
 		c.Name, nodeAddr, ns.ID, ns.Capacity)
 	return
@@ -1126,6 +1159,7 @@ func (c *Cluster) addDataNode(nodeAddr, zoneName string, nodesetId uint64) (id u
 	c.addNodeSetGrp(ns, false)
 
 	c.dataNodes.Store(nodeAddr, dataNode)
+	c.recordTopologyEvent(proto.TopologyEventDataNodeAdded, nodeAddr, zoneName)
 	log.LogInfof("action[addDataNode],clusterID[%v] dataNodeAddr:%v,nodeSetId[%v],capacity[%v]",
 		c.Name, nodeAddr, ns.ID, ns.Capacity)
 	return
@@ -1271,6 +1305,21 @@ func (c *Cluster) checkReplicaOfDataPartitions(ignoreDiscardDp bool) (
 	return
 }
 
+// checkScrubErrorsOfDataPartitions returns the data partitions with extent crc mismatches
+// reported by the scrubber of data nodes and not repaired.
+func (c *Cluster) checkScrubErrorsOfDataPartitions() (scrubErrorDps []*DataPartition) {
+	scrubErrorDps = make([]*DataPartition, 0)
+	vols := c.copyVols()
+	for _, vol := range vols {
+		for _, dp := range vol.dataPartitions.clonePartitions() {
+			if dp.hasScrubErrors() {
+				scrubErrorDps = append(scrubErrorDps, dp)
+			}
+		}
+	}
+	return
+}
+
 func (c *Cluster) getDataPartitionByID(partitionID uint64) (dp *DataPartition, err error) {
 	vols := c.copyVols()
 
@@ -1561,11 +1610,22 @@ func (c *Cluster) createDataPartition(volName string, preload *DataPartitionPreL
 			goto errHandler
 		}
 	} else {
+		var excludeZones []string
+		if excludeZones, err = c.getReservationUsedUpZones(TypeDataPartition, zoneName); err != nil {
+			goto errHandler
+		}
+		placement := vol.getPlacement()
 		zoneNum := c.decideZoneNum(vol.crossZone)
-		if targetHosts, targetPeers, err = c.getHostFromNormalZone(TypeDataPartition, nil, nil, nil,
+		if placement.MinZones > zoneNum {
+			zoneNum = placement.MinZones
+		}
+		if targetHosts, targetPeers, err = c.getHostFromNormalZone(TypeDataPartition, excludeZones, placement.ExcludeNodeSets, nil,
 			int(dpReplicaNum), zoneNum, zoneName); err != nil {
 			goto errHandler
 		}
+		if err = c.checkHostsZones(TypeDataPartition, targetHosts, placement.MinZones); err != nil {
+			goto errHandler
+		}
 	}
 
 	if partitionID, err = c.idAlloc.allocateDataPartitionID(); err != nil {
@@ -1781,6 +1841,29 @@ func (c *Cluster) chooseZoneNormal(zones []*Zone, excludeNodeSets []uint64, excl
 	return
 }
 
+// getReservationUsedUpZones returns zones which have used up the capacity except the reserved, new partitions of
+// volumes are not allocated in them. It fails if one of the specified zones or the only zone is used up.
+func (c *Cluster) getReservationUsedUpZones(nodeType uint32, specifiedZone string) (zones []string, err error) {
+	for _, zone := range c.t.getAllZones() {
+		if zone.isReservationUsedUp(nodeType) {
+			zones = append(zones, zone.name)
+		}
+	}
+	if len(zones) == 0 {
+		return
+	}
+	if specifiedZone != "" {
+		for _, name := range strings.Split(specifiedZone, ",") {
+			if contains(zones, name) {
+				return nil, fmt.Errorf("zone[%v] has used up the capacity except the reserved", name)
+			}
+		}
+	} else if c.t.isSingleZone() {
+		return nil, fmt.Errorf("zone%v has used up the capacity except the reserved", zones)
+	}
+	return
+}
+
 func (c *Cluster) getHostFromNormalZone(nodeType uint32, excludeZones []string, excludeNodeSets []uint64,
 	excludeHosts []string, replicaNum int,
 	zoneNum int, specifiedZone string) (hosts []string, peers []proto.Peer, err error,
@@ -2066,6 +2149,7 @@ func (c *Cluster) decommissionDataNode(dataNode *DataNode, force bool) (err erro
 func (c *Cluster) delDataNodeFromCache(dataNode *DataNode) {
 	c.dataNodes.Delete(dataNode.Addr)
 	c.t.deleteDataNode(dataNode)
+	c.recordTopologyEvent(proto.TopologyEventDataNodeRemoved, dataNode.Addr, dataNode.ZoneName)
 	go dataNode.clean()
 }
 
@@ -2376,7 +2460,7 @@ func (c *Cluster) migrateDataPartition(srcAddr, targetAddr string, dp *DataParti
 
 	if targetAddr != "" {
 		targetHosts = []string{targetAddr}
-	} else if targetHosts, _, err = ns.getAvailDataNodeHosts(dp.Hosts, 1); err != nil {
+	} else if targetHosts, _, err = c.getAvailHostsFromNodeSet(ns, TypeDataPartition, dp.VolName, dp.Hosts); err != nil {
 		if _, ok := c.vols[dp.VolName]; !ok {
 			log.LogWarnf("clusterID[%v] partitionID:%v  on node:%v offline failed,PersistenceHosts:[%v]",
 				c.Name, dp.PartitionID, srcAddr, dp.Hosts)
@@ -2388,16 +2472,13 @@ func (c *Cluster) migrateDataPartition(srcAddr, targetAddr string, dp *DataParti
 			goto errHandler
 		}
 		// select data nodes from the other node set in same zone
+		placement := c.getVolPlacement(dp.VolName)
 		excludeNodeSets = append(excludeNodeSets, ns.ID)
+		excludeNodeSets = append(excludeNodeSets, placement.ExcludeNodeSets...)
 		if targetHosts, _, err = zone.getAvailNodeHosts(TypeDataPartition, excludeNodeSets, dp.Hosts, 1); err != nil {
 			// select data nodes from the other zone
 			zones = dp.getLiveZones(srcAddr)
-			var excludeZone []string
-			if len(zones) == 0 {
-				excludeZone = append(excludeZone, zone.name)
-			} else {
-				excludeZone = append(excludeZone, zones[0])
-			}
+			excludeZone := getPlacementExcludeZones(placement, zones, zone.name)
 			if targetHosts, _, err = c.getHostFromNormalZone(TypeDataPartition, excludeZone, excludeNodeSets, dp.Hosts, 1, 1, ""); err != nil {
 				goto errHandler
 			}
@@ -2445,6 +2526,8 @@ func (c *Cluster) migrateDataPartition(srcAddr, targetAddr string, dp *DataParti
 
 	log.LogWarnf("[migrateDataPartition] clusterID[%v] partitionID:%v  on node:%v offline success,newHost[%v],PersistenceHosts:[%v]",
 		c.Name, dp.PartitionID, srcAddr, newAddr, dp.Hosts)
+	c.recordEvent(proto.EventSeverityInfo, eventModuleDataPartition, "datapartition %v is offline on %v, repair started on %v",
+		dp.PartitionID, srcAddr, newAddr)
 	dp.SetSpecialReplicaDecommissionStep(SpecialDecommissionInitial)
 	return
 
@@ -2460,6 +2543,8 @@ errHandler:
 
 	if err != nil {
 		Warn(c.Name, msg)
+		c.recordEvent(proto.EventSeverityError, eventModuleDataPartition, "datapartition %v offline on %v failed: %v",
+			dp.PartitionID, srcAddr, err)
 		err = fmt.Errorf("vol[%v],partition[%v],err[%v]", dp.VolName, dp.PartitionID, err)
 		log.LogErrorf("actin[decommissionDataPartition] err %v", err)
 	}
@@ -3086,6 +3171,7 @@ func (c *Cluster) decommissionMetaNode(metaNode *MetaNode) (err error) {
 func (c *Cluster) deleteMetaNodeFromCache(metaNode *MetaNode) {
 	c.metaNodes.Delete(metaNode.Addr)
 	c.t.deleteMetaNode(metaNode)
+	c.recordTopologyEvent(proto.TopologyEventMetaNodeRemoved, metaNode.Addr, metaNode.ZoneName)
 	go metaNode.clean()
 }
 
@@ -3093,7 +3179,6 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 	var (
 		vol           *Vol
 		serverAuthKey string
-		volUsedSpace  uint64
 		oldArgs       *VolVarargs
 	)
 
@@ -3117,20 +3202,13 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 		return proto.ErrVolAuthKeyNotMatch
 	}
 
-	volUsedSpace = vol.totalUsedSpace()
-	if float64(newArgs.capacity*util.GB) < float64(volUsedSpace)*1.01 && newArgs.capacity != vol.Capacity {
-		err = fmt.Errorf("capacity[%v] has to be 1 percent larger than the used space[%v]", newArgs.capacity,
-			volUsedSpace/util.GB)
+	if err = c.checkUpdateVolArgs(vol, newArgs); err != nil {
 		goto errHandler
 	}
 
-	log.LogInfof("[checkZoneName] name [%s], zone [%s]", name, newArgs.zoneName)
-	if newArgs.zoneName, err = c.checkZoneName(name, vol.crossZone, vol.defaultPriority, newArgs.zoneName, vol.domainId); err != nil {
-		goto errHandler
-	}
-
-	if newArgs.coldArgs.cacheCap >= newArgs.capacity {
-		err = fmt.Errorf("capacity must be large than cache capacity, newCap(%d), newCacheCap(%d)", newArgs.capacity, newArgs.coldArgs.cacheCap)
+	c.tenantMgr.volLock.Lock()
+	defer c.tenantMgr.volLock.Unlock()
+	if err = c.checkTenantVolCapacity(vol, newArgs.capacity); err != nil {
 		goto errHandler
 	}
 
@@ -3152,6 +3230,25 @@ errHandler:
 	return
 }
 
+// checkUpdateVolArgs checks if the volume can be updated to the new args, the zone name of the args is normalized.
+func (c *Cluster) checkUpdateVolArgs(vol *Vol, newArgs *VolVarargs) (err error) {
+	volUsedSpace := vol.totalUsedSpace()
+	if float64(newArgs.capacity*util.GB) < float64(volUsedSpace)*1.01 && newArgs.capacity != vol.Capacity {
+		return fmt.Errorf("capacity[%v] has to be 1 percent larger than the used space[%v]", newArgs.capacity,
+			volUsedSpace/util.GB)
+	}
+
+	log.LogInfof("[checkZoneName] name [%s], zone [%s]", vol.Name, newArgs.zoneName)
+	if newArgs.zoneName, err = c.checkZoneName(vol.Name, vol.crossZone, vol.defaultPriority, newArgs.zoneName, vol.domainId); err != nil {
+		return
+	}
+
+	if newArgs.coldArgs.cacheCap >= newArgs.capacity {
+		return fmt.Errorf("capacity must be large than cache capacity, newCap(%d), newCacheCap(%d)", newArgs.capacity, newArgs.coldArgs.cacheCap)
+	}
+	return
+}
+
 func (c *Cluster) checkNormalZoneName(zoneName string) (err error) {
 	var zones []string
 	if c.needFaultDomain {
@@ -3421,7 +3518,7 @@ func (c *Cluster) updateInodeIDRange(volName string, start uint64) (err error) {
 		return proto.ErrVolNotExists
 	}
 
-	maxPartitionID = vol.maxPartitionID()
+	maxPartitionID = vol.lastPartitionID()
 	if partition, err = vol.metaPartition(maxPartitionID); err != nil {
 		log.LogErrorf("action[updateInodeIDRange]  mp[%v] not found", maxPartitionID)
 		return proto.ErrMetaPartitionNotExists
@@ -4199,19 +4296,65 @@ func (c *Cluster) scheduleToBadDisk() {
 	}()
 }
 
+// checkBadDisk marks the disks predicted to fail in consecutive health samples for auto decommission,
+// so that their data partitions are migrated before the disks break. The disks marked in one check and
+// the disks being decommissioned of a data node are limited, in case of a wrong prediction of many disks.
 func (c *Cluster) checkBadDisk() {
-	c.dataNodes.Range(func(addr, node interface{}) bool {
-		//TODO add to auto decommission disk
-		//dataNode, ok := node.(*DataNode)
-		//if !ok {
-		//	return true
-		//}
-		//for _, badDisk := range dataNode.BadDisks {
-		//
-		//}
+	if !c.metaReady || !c.AutoDecommissionDiskIsEnabled() {
+		return
+	}
+	var disks []proto.DiskInfo
+	for _, info := range c.getPredictedFailureDisks("") {
+		if c.diskFailureTracker.observe(fmt.Sprintf("%s_%s", info.Address, info.Path), info.Health) {
+			disks = append(disks, info)
+		}
+	}
+	c.diskFailureTracker.prune()
+	if len(disks) == 0 {
+		return
+	}
+
+	decommissioning := c.getDecommissioningDiskCount()
+	marked := 0
+	for _, info := range disks {
+		key := fmt.Sprintf("%s_%s", info.Address, info.Path)
+		if _, ok := c.DecommissionDisks.Load(key); ok {
+			continue
+		}
+		if marked >= maxPredictedFailureDisksPerCheck {
+			log.LogWarnf("action[checkBadDisk] disk %v is predicted to fail, %v disks are marked in this check",
+				key, marked)
+			continue
+		}
+		if decommissioning[info.Address] >= maxPredictedFailureDisksPerNode {
+			log.LogWarnf("action[checkBadDisk] disk %v is predicted to fail, %v disks of the node are being decommissioned",
+				key, decommissioning[info.Address])
+			continue
+		}
+		if err := c.migrateDisk(info.Address, info.Path, "", false, 0, true, AutoDecommission); err != nil {
+			log.LogWarnf("action[checkBadDisk] mark disk %v for auto decommission failed: %v", key, err)
+			continue
+		}
+		decommissioning[info.Address]++
+		marked++
+		msg := fmt.Sprintf("action[checkBadDisk] clusterID[%v] disk %v is predicted to fail %v, marked for auto decommission",
+			c.Name, key, info.FailureReasons)
+		Warn(c.Name, msg)
+	}
+}
 
+// getDecommissioningDiskCount returns the number of disks being decommissioned of each data node.
+func (c *Cluster) getDecommissioningDiskCount() map[string]int {
+	count := make(map[string]int)
+	c.DecommissionDisks.Range(func(key, value interface{}) bool {
+		disk := value.(*DecommissionDisk)
+		status := disk.GetDecommissionStatus()
+		if status != DecommissionSuccess && status != DecommissionFail {
+			count[disk.SrcAddr]++
+		}
 		return true
 	})
+	return count
 }
 
 func (c *Cluster) TryDecommissionDisk(disk *DecommissionDisk) {
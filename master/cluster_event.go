@@ -0,0 +1,145 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+const (
+	defaultClusterEventCapacity = 4096
+	maxClusterEventsWait        = 60 * time.Second
+)
+
+// modules of cluster events
+const (
+	eventModuleDataNode      = "datanode"
+	eventModuleMetaNode      = "metanode"
+	eventModuleDataPartition = "datapartition"
+	eventModuleMetaPartition = "metapartition"
+	eventModuleVolume        = "volume"
+)
+
+var eventSeverityLevel = map[string]int{
+	proto.EventSeverityInfo:    0,
+	proto.EventSeverityWarning: 1,
+	proto.EventSeverityError:   2,
+}
+
+// clusterEventFilter selects events not less severe than severity, and of modules if it is not empty
+type clusterEventFilter struct {
+	severity string
+	modules  map[string]bool
+}
+
+func (f *clusterEventFilter) match(event *proto.ClusterEvent) bool {
+	if f.severity != "" && eventSeverityLevel[event.Severity] < eventSeverityLevel[f.severity] {
+		return false
+	}
+	return len(f.modules) == 0 || f.modules[event.Module]
+}
+
+// clusterEventLog keeps the latest events of the cluster in memory, events are
+// not persisted and are lost when the leader of master changes.
+type clusterEventLog struct {
+	sync.RWMutex
+	events   []*proto.ClusterEvent // ring buffer
+	capacity int
+	nextSeq  uint64
+	// notify is closed and replaced when an event is recorded
+	notify chan struct{}
+}
+
+func newClusterEventLog(capacity int) *clusterEventLog {
+	return &clusterEventLog{
+		events:   make([]*proto.ClusterEvent, 0, capacity),
+		capacity: capacity,
+		nextSeq:  1,
+		notify:   make(chan struct{}),
+	}
+}
+
+func (l *clusterEventLog) record(severity, module, msg string) {
+	l.Lock()
+	defer l.Unlock()
+	event := &proto.ClusterEvent{
+		Seq:      l.nextSeq,
+		Time:     time.Now().Unix(),
+		Severity: severity,
+		Module:   module,
+		Message:  msg,
+	}
+	l.nextSeq++
+	if len(l.events) < l.capacity {
+		l.events = append(l.events, event)
+	} else {
+		l.events[(event.Seq-1)%uint64(l.capacity)] = event
+	}
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// since returns events with sequence not less than seq, events dropped from the buffer are skipped
+func (l *clusterEventLog) since(seq uint64, filter *clusterEventFilter) (view *proto.ClusterEventsView, notify chan struct{}) {
+	l.RLock()
+	defer l.RUnlock()
+	view = &proto.ClusterEventsView{Events: make([]*proto.ClusterEvent, 0), NextSeq: l.nextSeq}
+	oldest := l.nextSeq - uint64(len(l.events))
+	if seq < oldest {
+		seq = oldest
+	}
+	for ; seq < l.nextSeq; seq++ {
+		event := l.events[(seq-1)%uint64(l.capacity)]
+		if filter.match(event) {
+			view.Events = append(view.Events, event)
+		}
+	}
+	return view, l.notify
+}
+
+// wait returns events since seq, it waits until a matched event is recorded or timeout
+// if there is no matched event.
+func (l *clusterEventLog) wait(seq uint64, filter *clusterEventFilter, timeout time.Duration, done <-chan struct{}) *proto.ClusterEventsView {
+	if timeout > maxClusterEventsWait {
+		timeout = maxClusterEventsWait
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		view, notify := l.since(seq, filter)
+		if len(view.Events) > 0 || timeout <= 0 {
+			return view
+		}
+		seq = view.NextSeq
+		select {
+		case <-notify:
+		case <-timer.C:
+			return view
+		case <-done:
+			return view
+		}
+	}
+}
+
+func (c *Cluster) recordEvent(severity, module, format string, args ...interface{}) {
+	if c.eventLog == nil {
+		return
+	}
+	c.eventLog.record(severity, module, fmt.Sprintf(format, args...))
+}
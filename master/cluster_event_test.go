@@ -0,0 +1,72 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterEventLogRing(t *testing.T) {
+	l := newClusterEventLog(3)
+	for i := 0; i < 5; i++ {
+		l.record(proto.EventSeverityInfo, eventModuleDataNode, "event")
+	}
+	view, _ := l.since(0, &clusterEventFilter{})
+	require.Equal(t, uint64(6), view.NextSeq)
+	require.Len(t, view.Events, 3)
+	for i, event := range view.Events {
+		require.Equal(t, uint64(i+3), event.Seq)
+	}
+	view, _ = l.since(5, &clusterEventFilter{})
+	require.Len(t, view.Events, 1)
+	require.Equal(t, uint64(5), view.Events[0].Seq)
+}
+
+func TestClusterEventLogFilter(t *testing.T) {
+	l := newClusterEventLog(10)
+	l.record(proto.EventSeverityInfo, eventModuleDataNode, "info")
+	l.record(proto.EventSeverityWarning, eventModuleMetaNode, "warning")
+	l.record(proto.EventSeverityError, eventModuleDataPartition, "error")
+
+	view, _ := l.since(0, &clusterEventFilter{severity: proto.EventSeverityWarning})
+	require.Len(t, view.Events, 2)
+	view, _ = l.since(0, &clusterEventFilter{modules: map[string]bool{eventModuleDataNode: true, eventModuleDataPartition: true}})
+	require.Len(t, view.Events, 2)
+	view, _ = l.since(0, &clusterEventFilter{severity: proto.EventSeverityError, modules: map[string]bool{eventModuleMetaNode: true}})
+	require.Len(t, view.Events, 0)
+	require.Equal(t, uint64(4), view.NextSeq)
+}
+
+func TestClusterEventLogWait(t *testing.T) {
+	l := newClusterEventLog(10)
+	filter := &clusterEventFilter{severity: proto.EventSeverityWarning}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l.record(proto.EventSeverityInfo, eventModuleDataNode, "info")
+		l.record(proto.EventSeverityError, eventModuleDataNode, "error")
+	}()
+	view := l.wait(1, filter, 5*time.Second, nil)
+	require.Len(t, view.Events, 1)
+	require.Equal(t, "error", view.Events[0].Message)
+
+	start := time.Now()
+	view = l.wait(view.NextSeq, filter, 100*time.Millisecond, nil)
+	require.Len(t, view.Events, 0)
+	require.True(t, time.Since(start) >= 100*time.Millisecond)
+}
@@ -107,6 +107,86 @@ func (c *Cluster) updateZoneStatInfo() {
 	}
 }
 
+// getZoneUsages returns node counts, capacity and writable partitions of all zones,
+// a partition is counted in every zone holding one of its replicas.
+func (c *Cluster) getZoneUsages() map[string]*proto.ZoneUsageView {
+	usages := make(map[string]*proto.ZoneUsageView)
+	for _, zone := range c.t.getAllZones() {
+		usage := &proto.ZoneUsageView{}
+		usages[zone.name] = usage
+		zone.dataNodes.Range(func(key, value interface{}) bool {
+			node := value.(*DataNode)
+			usage.DataNodeCount++
+			usage.DataTotal += node.Total
+			usage.DataUsed += node.Used
+			return true
+		})
+		zone.metaNodes.Range(func(key, value interface{}) bool {
+			node := value.(*MetaNode)
+			usage.MetaNodeCount++
+			usage.MetaTotal += node.Total
+			usage.MetaUsed += node.Used
+			return true
+		})
+	}
+
+	hostsZones := func(hosts []string, getZone func(addr string) (string, bool)) map[string]struct{} {
+		zones := make(map[string]struct{})
+		for _, host := range hosts {
+			if zone, ok := getZone(host); ok {
+				zones[zone] = struct{}{}
+			}
+		}
+		return zones
+	}
+	dataNodeZone := func(addr string) (string, bool) {
+		node, err := c.dataNode(addr)
+		if err != nil {
+			return "", false
+		}
+		return node.ZoneName, true
+	}
+	metaNodeZone := func(addr string) (string, bool) {
+		node, err := c.metaNode(addr)
+		if err != nil {
+			return "", false
+		}
+		return node.ZoneName, true
+	}
+
+	for _, vol := range c.allVols() {
+		for _, dp := range vol.dataPartitions.clonePartitions() {
+			dp.RLock()
+			writable := dp.Status == proto.ReadWrite
+			hosts := dp.Hosts
+			dp.RUnlock()
+			if !writable {
+				continue
+			}
+			for zone := range hostsZones(hosts, dataNodeZone) {
+				if usage, ok := usages[zone]; ok {
+					usage.WritableDataPartitions++
+				}
+			}
+		}
+		for _, mp := range vol.cloneMetaPartitionMap() {
+			mp.RLock()
+			writable := mp.Status == proto.ReadWrite
+			hosts := mp.Hosts
+			mp.RUnlock()
+			if !writable {
+				continue
+			}
+			for zone := range hostsZones(hosts, metaNodeZone) {
+				if usage, ok := usages[zone]; ok {
+					usage.WritableMetaPartitions++
+				}
+			}
+		}
+	}
+	return usages
+}
+
 func fixedPoint(x float64, scale int) float64 {
 	decimal := math.Pow10(scale)
 	return float64(int(math.Round(x*decimal))) / decimal
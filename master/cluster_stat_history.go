@@ -0,0 +1,181 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// defaultStatHistoryRange is the time range of snapshots returned if the query does not specify the start time
+const defaultStatHistoryRange = 24 * 3600
+
+// statSnapshotKey keys of snapshots are ordered by the time of them
+func statSnapshotKey(snapshotTime int64) string {
+	return statSnapshotPrefix + fmt.Sprintf("%020d", snapshotTime)
+}
+
+func parseStatSnapshotTime(key string) (int64, error) {
+	return strconv.ParseInt(strings.TrimPrefix(key, statSnapshotPrefix), 10, 64)
+}
+
+func sumBadPartitions(badPartitionIds *sync.Map) (count int) {
+	badPartitionIds.Range(func(key, value interface{}) bool {
+		count += len(value.([]uint64))
+		return true
+	})
+	return
+}
+
+// takeStatSnapshot collects the current capacity, node counts and partition health of the cluster
+func (c *Cluster) takeStatSnapshot() *proto.ClusterStatSnapshot {
+	snapshot := &proto.ClusterStatSnapshot{Time: time.Now().Unix()}
+	if c.dataNodeStatInfo != nil {
+		snapshot.DataTotalGB, snapshot.DataUsedGB = c.dataNodeStatInfo.TotalGB, c.dataNodeStatInfo.UsedGB
+	}
+	if c.metaNodeStatInfo != nil {
+		snapshot.MetaTotalGB, snapshot.MetaUsedGB = c.metaNodeStatInfo.TotalGB, c.metaNodeStatInfo.UsedGB
+	}
+	c.dataNodes.Range(func(addr, node interface{}) bool {
+		snapshot.DataNodeCount++
+		if !node.(*DataNode).isActive {
+			snapshot.InactiveDataNodeCount++
+		}
+		return true
+	})
+	c.metaNodes.Range(func(addr, node interface{}) bool {
+		snapshot.MetaNodeCount++
+		if !node.(*MetaNode).IsActive {
+			snapshot.InactiveMetaNodeCount++
+		}
+		return true
+	})
+	vols := c.copyVols()
+	snapshot.VolCount = len(vols)
+	for _, vol := range vols {
+		for _, dp := range vol.dataPartitions.clonePartitions() {
+			snapshot.DataPartitionCount++
+			if dp.Status == proto.Unavailable {
+				snapshot.UnavailableDataPartitions++
+			}
+		}
+		vol.mpsLock.RLock()
+		for _, mp := range vol.MetaPartitions {
+			snapshot.MetaPartitionCount++
+			if mp.Status == proto.Unavailable {
+				snapshot.UnavailableMetaPartitions++
+			}
+		}
+		vol.mpsLock.RUnlock()
+	}
+	c.badPartitionMutex.RLock()
+	snapshot.BadDataPartitions = sumBadPartitions(c.BadDataPartitionIds)
+	snapshot.BadMetaPartitions = sumBadPartitions(c.BadMetaPartitionIds)
+	c.badPartitionMutex.RUnlock()
+	return snapshot
+}
+
+func (c *Cluster) syncAddStatSnapshot(snapshot *proto.ClusterStatSnapshot) (err error) {
+	metadata := new(RaftCmd)
+	metadata.Op = opSyncAddStatSnapshot
+	metadata.K = statSnapshotKey(snapshot.Time)
+	if metadata.V, err = json.Marshal(snapshot); err != nil {
+		return
+	}
+	return c.submit(metadata)
+}
+
+func (c *Cluster) syncDeleteStatSnapshot(key string) (err error) {
+	metadata := new(RaftCmd)
+	metadata.Op = opSyncDeleteStatSnapshot
+	metadata.K = key
+	return c.submit(metadata)
+}
+
+// loadStatSnapshots returns persisted snapshots taken in [start, end] in ascending order of time
+func (c *Cluster) loadStatSnapshots(start, end int64) (snapshots []*proto.ClusterStatSnapshot, err error) {
+	result, err := c.fsm.store.SeekForPrefix([]byte(statSnapshotPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("action[loadStatSnapshots] seek failed, err[%v]", err)
+	}
+	snapshots = make([]*proto.ClusterStatSnapshot, 0)
+	for key, value := range result {
+		snapshotTime, err := parseStatSnapshotTime(key)
+		if err != nil || snapshotTime < start || snapshotTime > end {
+			continue
+		}
+		snapshot := &proto.ClusterStatSnapshot{}
+		if err = json.Unmarshal(value, snapshot); err != nil {
+			log.LogWarnf("action[loadStatSnapshots] unmarshal snapshot[%v] failed, err[%v]", key, err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time < snapshots[j].Time })
+	return
+}
+
+func (c *Cluster) getStatHistory(start, end int64) (history *proto.ClusterStatHistory, err error) {
+	history = &proto.ClusterStatHistory{
+		IntervalSec:   c.cfg.StatSnapshotIntervalSec,
+		RetentionHour: c.cfg.StatSnapshotRetentionHour,
+	}
+	history.Snapshots, err = c.loadStatSnapshots(start, end)
+	return
+}
+
+// deleteExpiredStatSnapshots deletes snapshots older than the retention
+func (c *Cluster) deleteExpiredStatSnapshots() {
+	expireTime := time.Now().Unix() - c.cfg.StatSnapshotRetentionHour*3600
+	expired, err := c.loadStatSnapshots(0, expireTime-1)
+	if err != nil {
+		log.LogWarnf("action[deleteExpiredStatSnapshots] err[%v]", err)
+		return
+	}
+	for _, snapshot := range expired {
+		if err = c.syncDeleteStatSnapshot(statSnapshotKey(snapshot.Time)); err != nil {
+			log.LogWarnf("action[deleteExpiredStatSnapshots] delete snapshot[%v] failed, err[%v]", snapshot.Time, err)
+			return
+		}
+	}
+}
+
+func (c *Cluster) snapshotStat() {
+	snapshot := c.takeStatSnapshot()
+	if err := c.syncAddStatSnapshot(snapshot); err != nil {
+		log.LogWarnf("action[snapshotStat] persist snapshot failed, err[%v]", err)
+		return
+	}
+	c.deleteExpiredStatSnapshots()
+}
+
+func (c *Cluster) scheduleToSnapshotStat() {
+	go func() {
+		for {
+			time.Sleep(time.Duration(c.cfg.StatSnapshotIntervalSec) * time.Second)
+			if c.partition != nil && c.partition.IsRaftLeader() {
+				c.snapshotStat()
+			}
+		}
+	}()
+}
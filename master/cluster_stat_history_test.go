@@ -0,0 +1,59 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatSnapshotKey(t *testing.T) {
+	require.Less(t, statSnapshotKey(999), statSnapshotKey(1000))
+	snapshotTime, err := parseStatSnapshotTime(statSnapshotKey(1700000000))
+	require.NoError(t, err)
+	require.EqualValues(t, 1700000000, snapshotTime)
+}
+
+func TestParseRequestToGetStatHistory(t *testing.T) {
+	r := httptest.NewRequest("GET", "/cluster/statHistory", nil)
+	start, end, err := parseRequestToGetStatHistory(r)
+	require.NoError(t, err)
+	require.InDelta(t, time.Now().Unix(), end, 5)
+	require.EqualValues(t, defaultStatHistoryRange, end-start)
+
+	r = httptest.NewRequest("GET", "/cluster/statHistory?start=100&end=200", nil)
+	start, end, err = parseRequestToGetStatHistory(r)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, start)
+	require.EqualValues(t, 200, end)
+
+	r = httptest.NewRequest("GET", "/cluster/statHistory?start=300&end=200", nil)
+	_, _, err = parseRequestToGetStatHistory(r)
+	require.Error(t, err)
+}
+
+func TestClusterStatHistory(t *testing.T) {
+	c := server.cluster
+	c.snapshotStat()
+	history, err := c.getStatHistory(time.Now().Unix()-60, time.Now().Unix())
+	require.NoError(t, err)
+	require.NotEmpty(t, history.Snapshots)
+	snapshot := history.Snapshots[len(history.Snapshots)-1]
+	require.NotZero(t, snapshot.DataNodeCount)
+	require.NotZero(t, snapshot.VolCount)
+}
@@ -97,13 +97,8 @@ func (c *Cluster) loadDataPartition(dp *DataPartition) {
 
 func (c *Cluster) migrateMetaPartition(srcAddr, targetAddr string, mp *MetaPartition) (err error) {
 	var (
-		newPeers        []proto.Peer
-		metaNode        *MetaNode
-		zone            *Zone
-		ns              *nodeSet
-		excludeNodeSets []uint64
-		oldHosts        []string
-		zones           []string
+		newPeers []proto.Peer
+		oldHosts []string
 	)
 
 	log.LogWarnf("action[migrateMetaPartition],volName[%v], migrate from src[%s] to target[%s],partitionID[%v] begin",
@@ -123,48 +118,12 @@ func (c *Cluster) migrateMetaPartition(srcAddr, targetAddr string, mp *MetaParti
 		goto errHandler
 	}
 
-	if metaNode, err = c.metaNode(srcAddr); err != nil {
-		goto errHandler
-	}
-
-	if zone, err = c.t.getZone(metaNode.ZoneName); err != nil {
-		goto errHandler
-	}
-
-	if ns, err = zone.getNodeSet(metaNode.NodeSetID); err != nil {
-		goto errHandler
-	}
-
 	if targetAddr != "" {
 		newPeers = []proto.Peer{{
 			Addr: targetAddr,
 		}}
-	} else if _, newPeers, err = ns.getAvailMetaNodeHosts(oldHosts, 1); err != nil {
-		if _, ok := c.vols[mp.volName]; !ok {
-			log.LogWarnf("[migrateMetaPartition] clusterID[%v] partitionID:%v  on node:[%v]",
-				c.Name, mp.PartitionID, mp.Hosts)
-			return
-		}
-		if c.isFaultDomain(c.vols[mp.volName]) {
-			log.LogWarnf("[migrateMetaPartition] clusterID[%v] partitionID:%v  on node:[%v]",
-				c.Name, mp.PartitionID, mp.Hosts)
-			return
-		}
-		// choose a meta node in other node set in the same zone
-		excludeNodeSets = append(excludeNodeSets, ns.ID)
-		if _, newPeers, err = zone.getAvailNodeHosts(TypeMetaPartition, excludeNodeSets, oldHosts, 1); err != nil {
-			zones = mp.getLiveZones(srcAddr)
-			var excludeZone []string
-			if len(zones) == 0 {
-				excludeZone = append(excludeZone, zone.name)
-			} else {
-				excludeZone = append(excludeZone, zones[0])
-			}
-			// choose a meta node in other zone
-			if _, newPeers, err = c.getHostFromNormalZone(TypeMetaPartition, excludeZone, excludeNodeSets, oldHosts, 1, 1, ""); err != nil {
-				goto errHandler
-			}
-		}
+	} else if newPeers, err = c.chooseMetaPartitionTarget(mp, srcAddr, oldHosts); err != nil {
+		goto errHandler
 	}
 
 	if err = c.deleteMetaReplica(mp, srcAddr, false, false); err != nil {
@@ -184,12 +143,16 @@ func (c *Cluster) migrateMetaPartition(srcAddr, targetAddr string, mp *MetaParti
 
 	Warn(c.Name, fmt.Sprintf("action[migrateMetaPartition] clusterID[%v] vol[%v] meta partition[%v] "+
 		"migrate addr[%v] success,new addr[%v]", c.Name, mp.volName, mp.PartitionID, srcAddr, newPeers[0].Addr))
+	c.recordEvent(proto.EventSeverityInfo, eventModuleMetaPartition, "metapartition %v is offline on %v, repair started on %v",
+		mp.PartitionID, srcAddr, newPeers[0].Addr)
 	return
 
 errHandler:
 	msg := fmt.Sprintf("action[migrateMetaPartition],volName: %v,partitionID: %v,err: %v", mp.volName, mp.PartitionID, errors.Stack(err))
 	log.LogError(msg)
 	Warn(c.Name, msg)
+	c.recordEvent(proto.EventSeverityError, eventModuleMetaPartition, "metapartition %v offline on %v failed: %v",
+		mp.PartitionID, srcAddr, err)
 
 	if err != nil {
 		err = fmt.Errorf("action[migrateMetaPartition] vol[%v],partition[%v],err[%v]", mp.volName, mp.PartitionID, err)
@@ -197,6 +160,51 @@ errHandler:
 	return
 }
 
+// chooseMetaPartitionTarget chooses the meta node to receive the replica of the partition on srcAddr, the node set
+// of srcAddr is preferred, then the other node sets in the same zone and the other zones at last.
+func (c *Cluster) chooseMetaPartitionTarget(mp *MetaPartition, srcAddr string, oldHosts []string) (newPeers []proto.Peer, err error) {
+	var (
+		metaNode        *MetaNode
+		zone            *Zone
+		ns              *nodeSet
+		excludeNodeSets []uint64
+		zones           []string
+	)
+	if metaNode, err = c.metaNode(srcAddr); err != nil {
+		return
+	}
+	if zone, err = c.t.getZone(metaNode.ZoneName); err != nil {
+		return
+	}
+	if ns, err = zone.getNodeSet(metaNode.NodeSetID); err != nil {
+		return
+	}
+	if _, newPeers, err = c.getAvailHostsFromNodeSet(ns, TypeMetaPartition, mp.volName, oldHosts); err == nil {
+		return
+	}
+	if _, ok := c.vols[mp.volName]; !ok {
+		log.LogWarnf("[chooseMetaPartitionTarget] clusterID[%v] partitionID:%v  on node:[%v]",
+			c.Name, mp.PartitionID, mp.Hosts)
+		return
+	}
+	if c.isFaultDomain(c.vols[mp.volName]) {
+		log.LogWarnf("[chooseMetaPartitionTarget] clusterID[%v] partitionID:%v  on node:[%v]",
+			c.Name, mp.PartitionID, mp.Hosts)
+		return
+	}
+	// choose a meta node in other node set in the same zone
+	placement := c.getVolPlacement(mp.volName)
+	excludeNodeSets = append(excludeNodeSets, ns.ID)
+	excludeNodeSets = append(excludeNodeSets, placement.ExcludeNodeSets...)
+	if _, newPeers, err = zone.getAvailNodeHosts(TypeMetaPartition, excludeNodeSets, oldHosts, 1); err != nil {
+		zones = mp.getLiveZones(srcAddr)
+		excludeZone := getPlacementExcludeZones(placement, zones, zone.name)
+		// choose a meta node in other zone
+		_, newPeers, err = c.getHostFromNormalZone(TypeMetaPartition, excludeZone, excludeNodeSets, oldHosts, 1, 1, "")
+	}
+	return
+}
+
 // taking the given mata partition offline.
 // 1. checking if the meta partition can be offline.
 // There are two cases where the partition is not allowed to be offline:
@@ -1132,6 +1140,10 @@ func (c *Cluster) updateMetaNode(metaNode *MetaNode, metaPartitions []*proto.Met
 		}
 
 		mp.updateMetaPartition(mr, metaNode)
+		if mr.IsLeader && contains(mp.Hosts, metaNode.Addr) {
+			c.recordPartitionLeader(proto.TopologyEventMetaPartitionLeaderChanged, mp.volName, mp.PartitionID,
+				metaNode.Addr, metaNode.ZoneName)
+		}
 		vol.uidSpaceManager.volUidUpdate(mr)
 		vol.quotaManager.quotaUpdate(mr)
 		c.updateInodeIDUpperBound(mp, mr, threshold, metaNode)
@@ -1148,8 +1160,7 @@ func (c *Cluster) updateInodeIDUpperBound(mp *MetaPartition, mr *proto.MetaParti
 		return
 	}
 
-	maxPartitionID := vol.maxPartitionID()
-	if mr.PartitionID < maxPartitionID {
+	if mr.PartitionID != vol.lastPartitionID() {
 		return
 	}
 	var end uint64
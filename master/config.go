@@ -49,6 +49,11 @@ const (
 	disableAutoCreate                   = "disableAutoCreate"
 	cfgMonitorPushAddr                  = "monitorPushAddr"
 	intervalToScanS3Expiration          = "intervalToScanS3Expiration"
+	cfgStatSnapshotIntervalSec          = "statSnapshotIntervalSec"
+	cfgStatSnapshotRetentionHour        = "statSnapshotRetentionHour"
+	cfgEnableRBAC                       = "enableRBAC"
+	cfgMetaPartitionSplitQps            = "metaPartitionSplitQps"
+	cfgMetaPartitionSplitInodeCount     = "metaPartitionSplitInodeCount"
 
 	cfgVolForceDeletion           = "volForceDeletion"
 	cfgVolDeletionDentryThreshold = "volDeletionDentryThreshold"
@@ -90,6 +95,8 @@ const (
 	defaultMaxDpCntLimit                               = 3000
 	defaultIntervalToScanS3Expiration                  = 12 * 3600
 	defaultMaxConcurrentLcNodes                        = 3
+	defaultStatSnapshotIntervalSec                     = 10 * 60
+	defaultStatSnapshotRetentionHour                   = 7 * 24
 	metaPartitionInodeUsageThreshold           float64 = 0.75 // inode usage threshold on a meta partition
 	lowerLimitRWMetaPartition                          = 3    // lower limit of RW meta partition, equal defaultReplicaNum
 	// defaultIntervalToCheckDelVerTaskExpiration         = 3
@@ -140,6 +147,11 @@ type clusterConfig struct {
 	MonitorPushAddr                     string
 	IntervalToScanS3Expiration          int64
 	MaxConcurrentLcNodes                uint64
+	StatSnapshotIntervalSec             int64  // interval to take snapshots of cluster stats
+	StatSnapshotRetentionHour           int64  // how long snapshots of cluster stats are kept
+	EnableRBAC                          bool   // check roles of users before handling admin apis
+	MetaPartitionSplitQps               uint64 // split a meta partition if its qps reaches it, 0 disables it
+	MetaPartitionSplitInodeCount        uint64 // split a meta partition if its inode count reaches it, 0 disables it
 
 	volForceDeletion           bool   // when delete a volume, ignore it's dentry count or not
 	volDeletionDentryThreshold uint64 // in case of volForceDeletion is set to false, define the dentry count threshold to allow volume deletion
@@ -173,6 +185,8 @@ func newClusterConfig() (cfg *clusterConfig) {
 	cfg.IntervalToScanS3Expiration = defaultIntervalToScanS3Expiration
 	cfg.MaxConcurrentLcNodes = defaultMaxConcurrentLcNodes
 	cfg.volDelayDeleteTimeHour = defaultVolDelayDeleteTimeHour
+	cfg.StatSnapshotIntervalSec = defaultStatSnapshotIntervalSec
+	cfg.StatSnapshotRetentionHour = defaultStatSnapshotRetentionHour
 	return
 }
 
@@ -69,6 +69,7 @@ const (
 	akKey                      = "ak"
 	keywordsKey                = "keywords"
 	zoneNameKey                = "zoneName"
+	zoneUsageKey               = "usage"
 	nodesetIdKey               = "nodesetId"
 	crossZoneKey               = "crossZone"
 	normalZonesFirstKey        = "normalZonesFirst"
@@ -99,6 +100,9 @@ const (
 	txConflictRetryNumKey      = "txConflictRetryNum"
 	txConflictRetryIntervalKey = "txConflictRetryInterval"
 	txOpLimitKey               = "txOpLimit"
+	inlineDirThresholdKey      = "inlineDirThreshold"
+	clientQpsLimitKey          = "clientQpsLimit"
+	trashIntervalKey           = "trashInterval"
 	txForceResetKey            = "txForceReset"
 	QosEnableKey               = "qosEnable"
 	DiskEnableKey              = "diskenable"
@@ -134,6 +138,33 @@ const (
 	Periodic                   = "periodic"
 	DecommissionType           = "decommissionType"
 	decommissionDiskFactor     = "decommissionDiskFactor"
+	eventSeqKey                = "seq"
+	eventWaitKey               = "wait"
+	eventSeverityKey           = "severity"
+	eventModuleKey             = "module"
+	dataReservedRatioKey       = "dataReservedRatio"
+	metaReservedRatioKey       = "metaReservedRatio"
+	minWritableSpaceKey        = "minWritableSpace"
+	minWritableDpKey           = "minWritableDp"
+	expandCountKey             = "expandCount"
+	cooldownKey                = "cooldown"
+	minZonesKey                = "minZones"
+	excludeNodeSetsKey         = "excludeNodeSets"
+	iopsReadLimitKey           = "iopsReadLimit"
+	iopsWriteLimitKey          = "iopsWriteLimit"
+	flowReadLimitKey           = "flowReadLimit"
+	flowWriteLimitKey          = "flowWriteLimit"
+	ttlKey                     = "ttl"
+	endKey                     = "end"
+	tenantKey                  = "tenant"
+	capacityLimitKey           = "capacityLimit"
+	volCountLimitKey           = "volCountLimit"
+	userCountLimitKey          = "userCountLimit"
+	limitPerZoneKey            = "limitPerZone"
+	roleKey                    = "role"
+	actionsKey                 = "actions"
+	dryRunKey                  = "dryrun"
+	rateKey                    = "rate"
 )
 
 const (
@@ -288,6 +319,17 @@ const (
 
 	opSyncS3QosSet    uint32 = 0x60
 	opSyncS3QosDelete uint32 = 0x61
+
+	opSyncAddStatSnapshot    uint32 = 0x70
+	opSyncDeleteStatSnapshot uint32 = 0x71
+
+	opSyncAddTenant    uint32 = 0x72
+	opSyncUpdateTenant uint32 = 0x73
+	opSyncDeleteTenant uint32 = 0x74
+
+	opSyncAddRole    uint32 = 0x75
+	opSyncUpdateRole uint32 = 0x76
+	opSyncDeleteRole uint32 = 0x77
 )
 
 const (
@@ -341,6 +383,11 @@ const (
 	lcNodePrefix     = keySeparator + lcNodeAcronym + keySeparator
 	lcConfPrefix     = keySeparator + lcConfigurationAcronym + keySeparator
 	S3QoSPrefix      = keySeparator + S3QoS + keySeparator
+
+	statSnapshotPrefix = keySeparator + "statSnapshot" + keySeparator
+
+	tenantPrefix = keySeparator + "tenant" + keySeparator
+	rolePrefix   = keySeparator + "role" + keySeparator
 )
 
 // selector enum
@@ -64,11 +64,14 @@ type DataNode struct {
 	DecommissionRetry         uint8
 	DecommissionLimit         int
 	DecommissionCompleteTime  int64
+	DecommissionStartTime     int64
 	DpCntLimit                DpCountLimiter     `json:"-"` // max count of data partition in a data node
 	CpuUtil                   atomicutil.Float64 `json:"-"`
 	ioUtils                   atomic.Value       `json:"-"`
 	DecommissionDiskList      []string
 	DecommissionDpTotal       int
+	MaintenanceExpireTime     int64
+	maintenanceTransferTime   time.Time
 }
 
 func newDataNode(addr, zoneName, clusterID string) (dataNode *DataNode) {
@@ -93,14 +96,17 @@ func (dataNode *DataNode) SetIoUtils(used map[string]float64) {
 	dataNode.ioUtils.Store(used)
 }
 
-func (dataNode *DataNode) checkLiveness() {
+// checkLiveness returns true if the data node turns inactive
+func (dataNode *DataNode) checkLiveness() (down bool) {
 	dataNode.Lock()
 	defer dataNode.Unlock()
 	log.LogInfof("action[checkLiveness] datanode[%v] report time[%v],since report time[%v], need gap [%v]",
 		dataNode.Addr, dataNode.ReportTime, time.Since(dataNode.ReportTime), time.Second*time.Duration(defaultNodeTimeOutSec))
 	if time.Since(dataNode.ReportTime) > time.Second*time.Duration(defaultNodeTimeOutSec) {
+		down = dataNode.isActive
 		dataNode.isActive = false
 	}
+	return
 }
 
 func (dataNode *DataNode) badPartitions(diskPath string, c *Cluster) (partitions []*DataPartition) {
@@ -179,7 +185,8 @@ func (dataNode *DataNode) isWriteAble() (ok bool) {
 	dataNode.RLock()
 	defer dataNode.RUnlock()
 
-	if dataNode.isActive && dataNode.AvailableSpace > 10*util.GB && !dataNode.RdOnly {
+	if dataNode.isActive && dataNode.AvailableSpace > 10*util.GB && !dataNode.RdOnly &&
+		!inMaintenance(dataNode.MaintenanceExpireTime) {
 		ok = true
 	}
 
@@ -215,7 +222,7 @@ func (dataNode *DataNode) isWriteAbleWithSize(size uint64) (ok bool) {
 	dataNode.RLock()
 	defer dataNode.RUnlock()
 
-	if dataNode.isActive && dataNode.AvailableSpace > size {
+	if dataNode.isActive && dataNode.AvailableSpace > size && !inMaintenance(dataNode.MaintenanceExpireTime) {
 		ok = true
 	}
 
@@ -473,6 +480,7 @@ func (dataNode *DataNode) markDecommission(targetAddr string, raftForce bool, li
 	dataNode.DecommissionRetry = 0
 	dataNode.DecommissionLimit = limit
 	dataNode.DecommissionDiskList = make([]string, 0)
+	dataNode.DecommissionStartTime = time.Now().Unix()
 }
 
 func (dataNode *DataNode) canMarkDecommission() bool {
@@ -579,6 +579,20 @@ func (partition *DataPartition) hasHost(addr string) (ok bool) {
 	return
 }
 
+// hasScrubErrors returns whether any replica reports extent crc mismatches which are not repaired.
+func (partition *DataPartition) hasScrubErrors() bool {
+	partition.RLock()
+	defer partition.RUnlock()
+	for _, replica := range partition.Replicas {
+		for _, scrubErr := range replica.ScrubErrors {
+			if !scrubErr.Repaired {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (partition *DataPartition) liveReplicas(timeOutSec int64) (replicas []*DataReplica) {
 	replicas = make([]*DataReplica, 0)
 	for i := 0; i < len(partition.Replicas); i++ {
@@ -714,9 +728,13 @@ func (partition *DataPartition) updateMetric(vr *proto.DataPartitionReport, data
 	replica.IsLeader = vr.IsLeader
 	if replica.IsLeader {
 		partition.LeaderReportTime = time.Now().Unix()
+		c.recordPartitionLeader(proto.TopologyEventDataPartitionLeaderChanged, partition.VolName, partition.PartitionID,
+			dataNode.Addr, dataNode.ZoneName)
 	}
 	replica.NeedsToCompare = vr.NeedCompare
 	replica.DecommissionRepairProgress = vr.DecommissionRepairProgress
+	replica.LastScrubTime = vr.LastScrubTime
+	replica.ScrubErrors = vr.ScrubErrors
 	if replica.DiskPath != vr.DiskPath && vr.DiskPath != "" {
 		oldDiskPath := replica.DiskPath
 		replica.DiskPath = vr.DiskPath
@@ -1013,6 +1031,8 @@ func GetDecommissionStatusMessage(status uint32) string {
 		return "Marked"
 	case DecommissionPause:
 		return "Paused"
+	case DecommissionPrepare:
+		return "Prepare"
 	case DecommissionRunning:
 		return "Running"
 	case DecommissionSuccess:
@@ -1497,12 +1517,9 @@ func (partition *DataPartition) createTaskToStopDataPartitionRepair(addr string,
 
 func (partition *DataPartition) TryAcquireDecommissionToken(c *Cluster) bool {
 	var (
-		zone            *Zone
-		ns              *nodeSet
-		err             error
-		targetHosts     []string
-		excludeNodeSets []uint64
-		zones           []string
+		ns          *nodeSet
+		err         error
+		targetHosts []string
 	)
 	const MaxRetryDecommissionWait = 60
 	defer c.syncUpdateDataPartition(partition)
@@ -1519,53 +1536,9 @@ func (partition *DataPartition) TryAcquireDecommissionToken(c *Cluster) bool {
 
 	// the first time for dst addr not specify
 	if !partition.DecommissionDstAddrSpecify && partition.DecommissionDstAddr == "" {
-		// try to find available data node in src nodeset
-		ns, zone, err = getTargetNodeset(partition.DecommissionSrcAddr, c)
-		if err != nil {
-			log.LogWarnf("action[TryAcquireDecommissionToken] dp %v find src nodeset failed:%v",
-				partition.PartitionID, err.Error())
+		if targetHosts, ns, err = c.chooseDecommissionTarget(partition, partition.DecommissionSrcAddr); err != nil {
 			goto errHandler
 		}
-		targetHosts, _, err = ns.getAvailDataNodeHosts(partition.Hosts, 1)
-		if err != nil {
-			log.LogWarnf("action[TryAcquireDecommissionToken] dp %v choose from src nodeset failed:%v",
-				partition.PartitionID, err.Error())
-			if _, ok := c.vols[partition.VolName]; !ok {
-				log.LogWarnf("action[TryAcquireDecommissionToken] dp %v cannot find vol:%v",
-					partition.PartitionID, err.Error())
-				goto errHandler
-			}
-
-			if c.isFaultDomain(c.vols[partition.VolName]) {
-				log.LogWarnf("action[TryAcquireDecommissionToken] dp %v is fault domain",
-					partition.PartitionID)
-				goto errHandler
-			}
-			excludeNodeSets = append(excludeNodeSets, ns.ID)
-			if targetHosts, _, err = zone.getAvailNodeHosts(TypeDataPartition, excludeNodeSets, partition.Hosts, 1); err != nil {
-				// select data nodes from the other zone
-				zones = partition.getLiveZones(partition.DecommissionSrcAddr)
-				var excludeZone []string
-				if len(zones) == 0 {
-					excludeZone = append(excludeZone, zone.name)
-				} else {
-					excludeZone = append(excludeZone, zones[0])
-				}
-				if targetHosts, _, err = c.getHostFromNormalZone(TypeDataPartition, excludeZone, excludeNodeSets, partition.Hosts, 1, 1, ""); err != nil {
-					log.LogWarnf("action[TryAcquireDecommissionToken] dp %v getHostFromNormalZone failed:%v",
-						partition.PartitionID, err.Error())
-					goto errHandler
-				}
-			}
-			// get nodeset for target host
-			newAddr := targetHosts[0]
-			ns, _, err = getTargetNodeset(newAddr, c)
-			if err != nil {
-				log.LogWarnf("action[TryAcquireDecommissionToken] dp %v find new nodeset failed:%v",
-					partition.PartitionID, err.Error())
-				goto errHandler
-			}
-		}
 		// only persist DecommissionDstAddr when get token
 		if ns.AcquireDecommissionToken(partition.PartitionID) {
 			partition.DecommissionDstAddr = targetHosts[0]
@@ -1606,6 +1579,59 @@ errHandler:
 	return false
 }
 
+// chooseDecommissionTarget chooses the data node to receive the replica of the partition on srcAddr, the node set
+// of srcAddr is preferred, then the other node sets in the same zone and the other zones at last.
+func (c *Cluster) chooseDecommissionTarget(partition *DataPartition, srcAddr string) (targetHosts []string, ns *nodeSet, err error) {
+	var (
+		zone            *Zone
+		excludeNodeSets []uint64
+		zones           []string
+	)
+	// try to find available data node in src nodeset
+	ns, zone, err = getTargetNodeset(srcAddr, c)
+	if err != nil {
+		log.LogWarnf("action[chooseDecommissionTarget] dp %v find src nodeset failed:%v",
+			partition.PartitionID, err.Error())
+		return
+	}
+	targetHosts, _, err = c.getAvailHostsFromNodeSet(ns, TypeDataPartition, partition.VolName, partition.Hosts)
+	if err == nil {
+		return
+	}
+	log.LogWarnf("action[chooseDecommissionTarget] dp %v choose from src nodeset failed:%v",
+		partition.PartitionID, err.Error())
+	if _, ok := c.vols[partition.VolName]; !ok {
+		log.LogWarnf("action[chooseDecommissionTarget] dp %v cannot find vol:%v",
+			partition.PartitionID, err.Error())
+		return
+	}
+
+	if c.isFaultDomain(c.vols[partition.VolName]) {
+		log.LogWarnf("action[chooseDecommissionTarget] dp %v is fault domain",
+			partition.PartitionID)
+		return
+	}
+	placement := c.getVolPlacement(partition.VolName)
+	excludeNodeSets = append(excludeNodeSets, ns.ID)
+	excludeNodeSets = append(excludeNodeSets, placement.ExcludeNodeSets...)
+	if targetHosts, _, err = zone.getAvailNodeHosts(TypeDataPartition, excludeNodeSets, partition.Hosts, 1); err != nil {
+		// select data nodes from the other zone
+		zones = partition.getLiveZones(srcAddr)
+		excludeZone := getPlacementExcludeZones(placement, zones, zone.name)
+		if targetHosts, _, err = c.getHostFromNormalZone(TypeDataPartition, excludeZone, excludeNodeSets, partition.Hosts, 1, 1, ""); err != nil {
+			log.LogWarnf("action[chooseDecommissionTarget] dp %v getHostFromNormalZone failed:%v",
+				partition.PartitionID, err.Error())
+			return
+		}
+	}
+	// get nodeset for target host
+	if ns, _, err = getTargetNodeset(targetHosts[0], c); err != nil {
+		log.LogWarnf("action[chooseDecommissionTarget] dp %v find new nodeset failed:%v",
+			partition.PartitionID, err.Error())
+	}
+	return
+}
+
 func (partition *DataPartition) ReleaseDecommissionToken(c *Cluster) {
 	if partition.DecommissionDstAddr == "" {
 		return
@@ -0,0 +1,137 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// estimateDecommissionCompleteTime estimates when a decommission completes by its average speed since it started,
+// it returns 0 if the decommission is not running or has no progress yet.
+func estimateDecommissionCompleteTime(status uint32, startTime int64, progress float64, now time.Time) int64 {
+	if status != DecommissionRunning || startTime == 0 || progress <= 0 || progress >= 1 {
+		return 0
+	}
+	elapsed := now.Unix() - startTime
+	if elapsed <= 0 {
+		return 0
+	}
+	return now.Unix() + int64(float64(elapsed)*(1-progress)/progress)
+}
+
+func (partition *DataPartition) getDecommissionDetail() *proto.DecommissionDpDetail {
+	partition.RLock()
+	defer partition.RUnlock()
+	detail := &proto.DecommissionDpDetail{
+		PartitionID:  partition.PartitionID,
+		Status:       GetDecommissionStatusMessage(partition.GetDecommissionStatus()),
+		SrcAddr:      partition.DecommissionSrcAddr,
+		DstAddr:      partition.DecommissionDstAddr,
+		Retry:        partition.DecommissionRetry,
+		ErrorMessage: partition.DecommissionErrorMessage,
+	}
+	if partition.IsDecommissionSuccess() {
+		detail.RepairProgress = 1
+		return detail
+	}
+	for _, replica := range partition.Replicas {
+		if partition.DecommissionDstAddr != "" && replica.Addr == partition.DecommissionDstAddr {
+			detail.RepairProgress = replica.DecommissionRepairProgress
+			break
+		}
+	}
+	return detail
+}
+
+func newDecommissionDetail(status uint32, progress float64, startTime int64, partitions []*DataPartition) *proto.DecommissionDetail {
+	progress, _ = FormatFloatFloor(progress, 4)
+	detail := &proto.DecommissionDetail{
+		DecommissionProgress: proto.DecommissionProgress{
+			Status:        status,
+			Progress:      fmt.Sprintf("%.2f%%", progress*float64(100)),
+			StatusMessage: GetDecommissionStatusMessage(status),
+		},
+		StartTime:             startTime,
+		EstimatedCompleteTime: estimateDecommissionCompleteTime(status, startTime, progress, time.Now()),
+		Partitions:            make([]*proto.DecommissionDpDetail, 0, len(partitions)),
+	}
+	for _, dp := range partitions {
+		if dp.IsDecommissionFailed() {
+			detail.FailedDps = append(detail.FailedDps, dp.PartitionID)
+		}
+		detail.Partitions = append(detail.Partitions, dp.getDecommissionDetail())
+	}
+	return detail
+}
+
+func (c *Cluster) getDataNodeDecommissionDetail(dataNode *DataNode) *proto.DecommissionDetail {
+	status, progress := dataNode.updateDecommissionStatus(c, false)
+	return newDecommissionDetail(status, progress, dataNode.DecommissionStartTime,
+		dataNode.GetLatestDecommissionDataPartition(c))
+}
+
+func (c *Cluster) getDiskDecommissionDetail(disk *DecommissionDisk) *proto.DecommissionDetail {
+	status, progress := disk.updateDecommissionStatus(c, false)
+	return newDecommissionDetail(status, progress, disk.DecommissionStartTime, disk.GetLatestDecommissionDP(c))
+}
+
+// resumeDecommissionDisk continues the paused decommission of the disk with its original parameters
+func (c *Cluster) resumeDecommissionDisk(disk *DecommissionDisk) (err error) {
+	if status := disk.GetDecommissionStatus(); status != DecommissionPause {
+		return fmt.Errorf("decommission of dataNode[%v] disk[%v] is %v, not paused", disk.SrcAddr, disk.DiskPath,
+			GetDecommissionStatusMessage(status))
+	}
+	if err = c.migrateDisk(disk.SrcAddr, disk.DiskPath, disk.DstAddr, disk.DecommissionRaftForce, disk.DecommissionDpCount,
+		disk.DiskDisable, disk.Type); err != nil {
+		return
+	}
+	log.LogInfof("action[resumeDecommissionDisk] dataNode[%v] disk[%v] resume decommission", disk.SrcAddr, disk.DiskPath)
+	return
+}
+
+// resumeDecommissionDataNode continues the paused decommission of the data node with its original parameters,
+// paused disks of the decommission are resumed as well.
+func (c *Cluster) resumeDecommissionDataNode(dataNode *DataNode) (err error) {
+	if status := dataNode.GetDecommissionStatus(); status != DecommissionPause {
+		return fmt.Errorf("decommission of dataNode[%v] is %v, not paused", dataNode.Addr,
+			GetDecommissionStatusMessage(status))
+	}
+	for _, diskPath := range dataNode.DecommissionDiskList {
+		value, ok := c.DecommissionDisks.Load(fmt.Sprintf("%s_%s", dataNode.Addr, diskPath))
+		if !ok {
+			continue
+		}
+		disk := value.(*DecommissionDisk)
+		if disk.GetDecommissionStatus() != DecommissionPause {
+			continue
+		}
+		if err = c.resumeDecommissionDisk(disk); err != nil {
+			return
+		}
+	}
+	dataNode.DecommissionRetry = 0
+	dataNode.SetDecommissionStatus(markDecommission)
+	if err = c.syncUpdateDataNode(dataNode); err != nil {
+		dataNode.SetDecommissionStatus(DecommissionPause)
+		return
+	}
+	log.LogInfof("action[resumeDecommissionDataNode] dataNode[%v] resume decommission with disks %v",
+		dataNode.Addr, dataNode.DecommissionDiskList)
+	return
+}
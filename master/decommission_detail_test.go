@@ -0,0 +1,59 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateDecommissionCompleteTime(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-10 * time.Minute).Unix()
+	require.Equal(t, now.Unix()+int64(30*60), estimateDecommissionCompleteTime(DecommissionRunning, start, 0.25, now))
+	require.Zero(t, estimateDecommissionCompleteTime(DecommissionRunning, start, 0, now))
+	require.Zero(t, estimateDecommissionCompleteTime(DecommissionRunning, 0, 0.5, now))
+	require.Zero(t, estimateDecommissionCompleteTime(DecommissionPause, start, 0.5, now))
+	require.Zero(t, estimateDecommissionCompleteTime(DecommissionSuccess, start, 1, now))
+}
+
+func TestNewDecommissionDetail(t *testing.T) {
+	running := newDataPartition(1, 3, "vol", 1, proto.PartitionTypeNormal, 0)
+	running.SetDecommissionStatus(DecommissionRunning)
+	running.DecommissionSrcAddr = "127.0.0.1:17310"
+	running.DecommissionDstAddr = "127.0.0.1:17320"
+	replica := &DataReplica{}
+	replica.Addr = running.DecommissionDstAddr
+	replica.DecommissionRepairProgress = 0.5
+	running.Replicas = append(running.Replicas, replica)
+
+	failed := newDataPartition(2, 3, "vol", 1, proto.PartitionTypeNormal, 0)
+	failed.SetDecommissionStatus(DecommissionFail)
+	failed.DecommissionErrorMessage = "no node to add replica"
+
+	detail := newDecommissionDetail(DecommissionRunning, 0.5, time.Now().Add(-time.Minute).Unix(),
+		[]*DataPartition{running, failed})
+	require.Equal(t, "50.00%", detail.Progress)
+	require.Equal(t, []uint64{2}, detail.FailedDps)
+	require.NotZero(t, detail.EstimatedCompleteTime)
+	require.Len(t, detail.Partitions, 2)
+	require.Equal(t, "Running", detail.Partitions[0].Status)
+	require.Equal(t, 0.5, detail.Partitions[0].RepairProgress)
+	require.Equal(t, "Failed", detail.Partitions[1].Status)
+	require.Equal(t, failed.DecommissionErrorMessage, detail.Partitions[1].ErrorMessage)
+}
@@ -128,10 +128,13 @@ func (c *Cluster) checkDiskRecoveryProgress() {
 					partition.SetDecommissionStatus(DecommissionFail)
 					partition.DecommissionErrorMessage = fmt.Sprintf("Decommission target node %v disk unavailable", partition.DecommissionDstAddr)
 					Warn(c.Name, fmt.Sprintf("action[checkDiskRecoveryProgress]clusterID[%v],partitionID[%v] has recovered failed", c.Name, partitionID))
+					c.recordEvent(proto.EventSeverityError, eventModuleDataPartition, "datapartition %v repair failed on %v",
+						partitionID, partition.DecommissionDstAddr)
 				} else {
 					partition.DecommissionErrorMessage = ""
 					partition.SetDecommissionStatus(DecommissionSuccess) // can be readonly or readwrite
 					Warn(c.Name, fmt.Sprintf("action[checkDiskRecoveryProgress]clusterID[%v],partitionID[%v] has recovered success", c.Name, partitionID))
+					c.recordEvent(proto.EventSeverityInfo, eventModuleDataPartition, "datapartition %v repair finished", partitionID)
 				}
 				partition.RLock()
 				err = c.syncUpdateDataPartition(partition)
@@ -215,6 +218,7 @@ type DecommissionDisk struct {
 	DiskDisable              bool
 	Type                     uint32
 	DecommissionCompleteTime int64
+	DecommissionStartTime    int64
 }
 
 func (dd *DecommissionDisk) GenerateKey() string {
@@ -362,6 +366,7 @@ func (dd *DecommissionDisk) markDecommission(dstPath string, raftForce bool, lim
 		dd.DecommissionRaftForce = raftForce
 		dd.DstAddr = dstPath
 		dd.DecommissionRetry = 0
+		dd.DecommissionStartTime = time.Now().Unix()
 	}
 	dd.DecommissionTerm = dd.DecommissionTerm + 1
 	dd.SetDecommissionStatus(markDecommission)
@@ -397,3 +402,129 @@ func (dd *DecommissionDisk) CanBePaused() bool {
 	}
 	return false
 }
+
+// thresholds of the disk health indicators to predict the disk failures
+const (
+	diskFailureReallocatedSectors   = 100
+	diskFailurePendingSectors       = 10
+	diskFailureUncorrectableSectors = 1
+	diskFailureMediaErrors          = 1
+	diskFailurePercentageUsed       = 95
+	diskFailureLatencyMs            = 1000
+)
+
+const (
+	diskFailureConfirmSamples        = 3 // consecutive health samples of a disk predicted to fail before it's decommissioned
+	maxPredictedFailureDisksPerCheck = 2 // disks predicted to fail marked for auto decommission in one check
+	maxPredictedFailureDisksPerNode  = 1 // disks being decommissioned of a data node to mark more disks predicted to fail
+)
+
+// diskFailureTracker counts the consecutive health samples of the disks predicted to fail, so that
+// the disks are not decommissioned by a transient spike, e.g. io latency. It's only used by checkBadDisk.
+type diskFailureTracker struct {
+	samples map[string]*diskFailureSample
+}
+
+type diskFailureSample struct {
+	updateTime int64 // update time of the last health sample counted
+	count      int
+	seen       bool // observed since the last prune
+}
+
+func newDiskFailureTracker() *diskFailureTracker {
+	return &diskFailureTracker{samples: make(map[string]*diskFailureSample)}
+}
+
+// observe counts the health sample of the disk predicted to fail, and returns whether the disk is
+// predicted to fail in enough consecutive samples. A sample is counted once however many times it's observed.
+func (t *diskFailureTracker) observe(key string, health *proto.DiskHealth) bool {
+	s, ok := t.samples[key]
+	if !ok {
+		s = &diskFailureSample{}
+		t.samples[key] = s
+	}
+	s.seen = true
+	if health != nil && health.UpdateTime != s.updateTime {
+		s.updateTime = health.UpdateTime
+		s.count++
+	}
+	return s.count >= diskFailureConfirmSamples
+}
+
+// prune resets the disks not observed since the last prune, which are healthy again or removed.
+func (t *diskFailureTracker) prune() {
+	for key, s := range t.samples {
+		if !s.seen {
+			delete(t.samples, key)
+			continue
+		}
+		s.seen = false
+	}
+}
+
+// predictDiskFailure returns the reasons why the disk is predicted to fail, empty if the disk is healthy.
+func predictDiskFailure(health *proto.DiskHealth) (reasons []string) {
+	if health == nil {
+		return
+	}
+	if health.SmartAvailable && !health.SmartPassed {
+		reasons = append(reasons, "smart self-assessment failed")
+	}
+	if health.ReallocatedSectors >= diskFailureReallocatedSectors {
+		reasons = append(reasons, fmt.Sprintf("reallocated sectors %v", health.ReallocatedSectors))
+	}
+	if health.PendingSectors >= diskFailurePendingSectors {
+		reasons = append(reasons, fmt.Sprintf("pending sectors %v", health.PendingSectors))
+	}
+	if health.UncorrectableSectors >= diskFailureUncorrectableSectors {
+		reasons = append(reasons, fmt.Sprintf("uncorrectable sectors %v", health.UncorrectableSectors))
+	}
+	if health.MediaErrors >= diskFailureMediaErrors {
+		reasons = append(reasons, fmt.Sprintf("media errors %v", health.MediaErrors))
+	}
+	if health.CriticalWarning != 0 {
+		reasons = append(reasons, fmt.Sprintf("critical warning %#x", health.CriticalWarning))
+	}
+	if health.PercentageUsed >= diskFailurePercentageUsed {
+		reasons = append(reasons, fmt.Sprintf("percentage used %v%%", health.PercentageUsed))
+	}
+	if health.ReadLatencyMs >= diskFailureLatencyMs || health.WriteLatencyMs >= diskFailureLatencyMs {
+		reasons = append(reasons, fmt.Sprintf("io latency read %.1fms write %.1fms", health.ReadLatencyMs, health.WriteLatencyMs))
+	}
+	return
+}
+
+// getPredictedFailureDisks returns the disks predicted to fail of the data node, or of all the data nodes if nodeAddr is empty.
+func (c *Cluster) getPredictedFailureDisks(nodeAddr string) (infos []proto.DiskInfo) {
+	c.dataNodes.Range(func(addr, node interface{}) bool {
+		dataNode, ok := node.(*DataNode)
+		if !ok {
+			return true
+		}
+		if len(nodeAddr) > 0 && nodeAddr != dataNode.Addr {
+			return true
+		}
+		for _, ds := range dataNode.DiskStats {
+			reasons := predictDiskFailure(ds.Health)
+			if len(reasons) == 0 {
+				continue
+			}
+			infos = append(infos, proto.DiskInfo{
+				NodeId:               dataNode.ID,
+				Address:              dataNode.Addr,
+				Path:                 ds.DiskPath,
+				Status:               proto.DiskStatusMap[ds.Status],
+				Total:                ds.Total,
+				Used:                 ds.Used,
+				Available:            ds.Available,
+				IOUtil:               ds.IOUtil,
+				TotalPartitionCnt:    ds.TotalPartitionCnt,
+				DiskErrPartitionList: ds.DiskErrPartitionList,
+				Health:               ds.Health,
+				FailureReasons:       reasons,
+			})
+		}
+		return true
+	})
+	return
+}
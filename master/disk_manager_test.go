@@ -0,0 +1,48 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskFailureTracker(t *testing.T) {
+	tracker := newDiskFailureTracker()
+	observe := func(key string, updateTime int64) bool {
+		confirmed := tracker.observe(key, &proto.DiskHealth{UpdateTime: updateTime})
+		tracker.prune()
+		return confirmed
+	}
+
+	for i := int64(1); i < diskFailureConfirmSamples; i++ {
+		require.False(t, observe("a", i))
+	}
+	// the same sample is counted once
+	require.False(t, observe("a", diskFailureConfirmSamples-1))
+	require.True(t, observe("a", diskFailureConfirmSamples))
+
+	// reset if the disk is not predicted to fail in a check
+	require.False(t, observe("b", 1))
+	tracker.prune()
+	for i := int64(1); i < diskFailureConfirmSamples; i++ {
+		require.False(t, observe("b", i))
+	}
+	require.True(t, observe("b", diskFailureConfirmSamples))
+	_, ok := tracker.samples["a"]
+	require.False(t, ok)
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+const (
+	dryRunDataPartition = "data"
+	dryRunMetaPartition = "meta"
+)
+
+// parseDryRun parses the dryrun parameter, with dryrun=true a cluster-changing admin API replies the computed plan
+// instead of applying it.
+func parseDryRun(r *http.Request) (bool, error) {
+	return extractBoolWithDefault(r, dryRunKey, false)
+}
+
+func newDryRunPlan(operation, target string) *proto.DryRunPlan {
+	return &proto.DryRunPlan{
+		Operation:  operation,
+		Target:     target,
+		Changes:    make(map[string]string),
+		Partitions: make([]*proto.DryRunPartition, 0),
+	}
+}
+
+func addDryRunChange(plan *proto.DryRunPlan, key string, oldValue, newValue interface{}) {
+	if oldStr, newStr := fmt.Sprint(oldValue), fmt.Sprint(newValue); oldStr != newStr {
+		plan.Changes[key] = fmt.Sprintf("%v -> %v", oldStr, newStr)
+	}
+}
+
+func sortDryRunPartitions(plan *proto.DryRunPlan) {
+	sort.Slice(plan.Partitions, func(i, j int) bool {
+		if plan.Partitions[i].PartitionType != plan.Partitions[j].PartitionType {
+			return plan.Partitions[i].PartitionType < plan.Partitions[j].PartitionType
+		}
+		return plan.Partitions[i].PartitionID < plan.Partitions[j].PartitionID
+	})
+}
+
+// planUpdateVol checks the new args of the volume as updateVol does and returns the changed settings,
+// replicas out of the new zones of the volume are reported as affected partitions.
+func (c *Cluster) planUpdateVol(operation string, vol *Vol, authKey string, newArgs *VolVarargs) (plan *proto.DryRunPlan, err error) {
+	if vol.status() == proto.VolStatusMarkDelete {
+		return nil, proto.ErrVolNotExists
+	}
+	if !matchKey(vol.Owner, authKey) {
+		return nil, proto.ErrVolAuthKeyNotMatch
+	}
+	if err = c.checkUpdateVolArgs(vol, newArgs); err != nil {
+		return
+	}
+
+	oldArgs := getVolVarargs(vol)
+	plan = newDryRunPlan(operation, vol.Name)
+	addDryRunChange(plan, volCapacityKey, oldArgs.capacity, newArgs.capacity)
+	addDryRunChange(plan, zoneNameKey, oldArgs.zoneName, newArgs.zoneName)
+	addDryRunChange(plan, descriptionKey, oldArgs.description, newArgs.description)
+	addDryRunChange(plan, replicaNumKey, oldArgs.dpReplicaNum, newArgs.dpReplicaNum)
+	addDryRunChange(plan, volDeleteLockTimeKey, oldArgs.deleteLockTime, newArgs.deleteLockTime)
+	addDryRunChange(plan, followerReadKey, oldArgs.followerRead, newArgs.followerRead)
+	addDryRunChange(plan, authenticateKey, oldArgs.authenticate, newArgs.authenticate)
+	addDryRunChange(plan, dpSelectorNameKey, oldArgs.dpSelectorName, newArgs.dpSelectorName)
+	addDryRunChange(plan, dpSelectorParmKey, oldArgs.dpSelectorParm, newArgs.dpSelectorParm)
+	addDryRunChange(plan, enablePosixAclKey, oldArgs.enablePosixAcl, newArgs.enablePosixAcl)
+	addDryRunChange(plan, enableQuota, oldArgs.enableQuota, newArgs.enableQuota)
+	addDryRunChange(plan, dpReadOnlyWhenVolFull, oldArgs.dpReadOnlyWhenVolFull, newArgs.dpReadOnlyWhenVolFull)
+	addDryRunChange(plan, enableTxMaskKey, proto.GetMaskString(oldArgs.enableTransaction),
+		proto.GetMaskString(newArgs.enableTransaction))
+	addDryRunChange(plan, txTimeoutKey, oldArgs.txTimeout, newArgs.txTimeout)
+	addDryRunChange(plan, inlineDirThresholdKey, oldArgs.inlineDirThreshold, newArgs.inlineDirThreshold)
+	addDryRunChange(plan, clientQpsLimitKey, oldArgs.clientQpsLimit, newArgs.clientQpsLimit)
+
+	if newArgs.zoneName == "" || newArgs.zoneName == oldArgs.zoneName {
+		return
+	}
+	zones := make(map[string]bool)
+	for _, zone := range strings.Split(newArgs.zoneName, ",") {
+		zones[zone] = true
+	}
+	for _, dp := range vol.dataPartitions.clonePartitions() {
+		dp.RLock()
+		hosts := append([]string{}, dp.Hosts...)
+		dp.RUnlock()
+		for _, host := range hosts {
+			if dataNode, err := c.dataNode(host); err == nil && !zones[dataNode.ZoneName] {
+				plan.Partitions = append(plan.Partitions, &proto.DryRunPartition{
+					PartitionID:   dp.PartitionID,
+					PartitionType: dryRunDataPartition,
+					VolName:       vol.Name,
+					SrcAddr:       host,
+				})
+			}
+		}
+	}
+	for _, mp := range vol.cloneMetaPartitionMap() {
+		mp.RLock()
+		hosts := append([]string{}, mp.Hosts...)
+		mp.RUnlock()
+		for _, host := range hosts {
+			if metaNode, err := c.metaNode(host); err == nil && !zones[metaNode.ZoneName] {
+				plan.Partitions = append(plan.Partitions, &proto.DryRunPartition{
+					PartitionID:   mp.PartitionID,
+					PartitionType: dryRunMetaPartition,
+					VolName:       vol.Name,
+					SrcAddr:       host,
+				})
+			}
+		}
+	}
+	sortDryRunPartitions(plan)
+	return
+}
+
+// planDataPartitionsDecommission chooses a target for each partition to be decommissioned from srcAddr, the
+// targets are chosen one by one with the current load of data nodes, so they may differ from the real decommission.
+func (c *Cluster) planDataPartitionsDecommission(plan *proto.DryRunPlan, srcAddr string, partitions []*DataPartition, limit int) {
+	if limit > 0 && limit < len(partitions) {
+		partitions = partitions[:limit]
+	}
+	for _, dp := range partitions {
+		partition := &proto.DryRunPartition{
+			PartitionID:   dp.PartitionID,
+			PartitionType: dryRunDataPartition,
+			VolName:       dp.VolName,
+			SrcAddr:       srcAddr,
+		}
+		if targetHosts, _, err := c.chooseDecommissionTarget(dp, srcAddr); err != nil {
+			partition.ErrorMessage = err.Error()
+		} else {
+			partition.DstAddr = targetHosts[0]
+		}
+		plan.Partitions = append(plan.Partitions, partition)
+	}
+}
+
+func (c *Cluster) planDataNodeDecommission(dataNode *DataNode, limit int) (plan *proto.DryRunPlan, err error) {
+	if !dataNode.canMarkDecommission() {
+		return nil, fmt.Errorf("migrate src(%v) is still on working, please wait,check or cancel if abnormal:%v",
+			dataNode.Addr, dataNode.GetDecommissionStatus())
+	}
+	plan = newDryRunPlan(proto.DecommissionDataNode, dataNode.Addr)
+	c.planDataPartitionsDecommission(plan, dataNode.Addr, c.getAllDataPartitionByDataNode(dataNode.Addr), limit)
+	return
+}
+
+func (c *Cluster) planDiskDecommission(dataNode *DataNode, diskPath string, limit int) (plan *proto.DryRunPlan, err error) {
+	if value, ok := c.DecommissionDisks.Load(fmt.Sprintf("%s_%s", dataNode.Addr, diskPath)); ok {
+		if status := value.(*DecommissionDisk).GetDecommissionStatus(); status == markDecommission || status == DecommissionRunning {
+			return nil, fmt.Errorf("migrate src(%v) diskPath(%v)s still on working, please wait,check or cancel if abnormal",
+				dataNode.Addr, diskPath)
+		}
+	}
+	plan = newDryRunPlan(proto.DecommissionDisk, fmt.Sprintf("%s:%s", dataNode.Addr, diskPath))
+	c.planDataPartitionsDecommission(plan, dataNode.Addr, dataNode.badPartitions(diskPath, c), limit)
+	return
+}
+
+func (c *Cluster) planMetaNodeDecommission(metaNode *MetaNode, limit int) (plan *proto.DryRunPlan, err error) {
+	if c.ForbidMpDecommission {
+		return nil, fmt.Errorf("cluster mataPartition decommission switch is disabled")
+	}
+	partitions := c.getAllMetaPartitionByMetaNode(metaNode.Addr)
+	if limit <= 0 {
+		limit = defaultMigrateMpCnt
+	}
+	if limit < len(partitions) {
+		partitions = partitions[:limit]
+	}
+	plan = newDryRunPlan(proto.DecommissionMetaNode, metaNode.Addr)
+	for _, mp := range partitions {
+		partition := &proto.DryRunPartition{
+			PartitionID:   mp.PartitionID,
+			PartitionType: dryRunMetaPartition,
+			VolName:       mp.volName,
+			SrcAddr:       metaNode.Addr,
+		}
+		mp.RLock()
+		hosts := append([]string{}, mp.Hosts...)
+		mp.RUnlock()
+		if newPeers, err := c.chooseMetaPartitionTarget(mp, metaNode.Addr, hosts); err != nil {
+			partition.ErrorMessage = err.Error()
+		} else {
+			partition.DstAddr = newPeers[0].Addr
+		}
+		plan.Partitions = append(plan.Partitions, partition)
+	}
+	return
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func processDryRun(reqURL string, t *testing.T) *proto.DryRunPlan {
+	reply := process(reqURL, t)
+	require.NotNil(t, reply)
+	data, err := json.Marshal(reply.Data)
+	require.NoError(t, err)
+	plan := &proto.DryRunPlan{}
+	require.NoError(t, json.Unmarshal(data, plan))
+	return plan
+}
+
+func TestAddDryRunChange(t *testing.T) {
+	plan := newDryRunPlan(proto.AdminUpdateVol, "vol")
+	addDryRunChange(plan, volCapacityKey, uint64(10), uint64(20))
+	addDryRunChange(plan, zoneNameKey, "zone1", "zone1")
+	require.Equal(t, map[string]string{volCapacityKey: "10 -> 20"}, plan.Changes)
+}
+
+func TestVolExpandDryRun(t *testing.T) {
+	capacity := commonVol.Capacity
+	reqURL := fmt.Sprintf("%v%v?name=%v&authKey=%v&capacity=%v&dryrun=true", hostAddr, proto.AdminVolExpand,
+		commonVol.Name, buildAuthKey(testOwner), capacity+100)
+	plan := processDryRun(reqURL, t)
+	require.Equal(t, proto.AdminVolExpand, plan.Operation)
+	require.Equal(t, fmt.Sprintf("%v -> %v", capacity, capacity+100), plan.Changes[volCapacityKey])
+	require.Equal(t, capacity, commonVol.Capacity)
+}
+
+func TestDecommissionDataNodeDryRun(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?addr=%v&dryrun=true", hostAddr, proto.DecommissionDataNode, mds1Addr)
+	plan := processDryRun(reqURL, t)
+	require.Equal(t, mds1Addr, plan.Target)
+	require.Len(t, plan.Partitions, len(server.cluster.getAllDataPartitionByDataNode(mds1Addr)))
+	for _, partition := range plan.Partitions {
+		require.Equal(t, mds1Addr, partition.SrcAddr)
+		require.NotEqual(t, mds1Addr, partition.DstAddr)
+	}
+	dataNode, err := server.cluster.dataNode(mds1Addr)
+	require.NoError(t, err)
+	require.Equal(t, uint32(DecommissionInitial), dataNode.GetDecommissionStatus())
+}
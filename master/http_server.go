@@ -41,6 +41,9 @@ func (m *Server) startHTTPService(modulename string, cfg *config.Config) {
 	if m.cluster.authenticate {
 		m.registerAuthenticationMiddleware(router)
 	}
+	if m.config.EnableRBAC {
+		m.registerRBACMiddleware(router)
+	}
 	exporter.InitWithRouter(modulename, cfg, router, m.port)
 	addr := fmt.Sprintf(":%s", m.port)
 	if m.bindIp {
@@ -213,7 +216,8 @@ var AuthenticationUri2MsgTypeMap = map[string]proto.MsgType{
 	proto.UserTransferVol:     proto.MsgMasterUserTransferVolReq,
 
 	// Master API zone management
-	proto.UpdateZone: proto.MsgMasterUpdateZoneReq,
+	proto.UpdateZone:         proto.MsgMasterUpdateZoneReq,
+	proto.SetZoneReservation: proto.MsgMasterUpdateZoneReq,
 }
 
 func (m *Server) registerAuthenticationMiddleware(router *mux.Router) {
@@ -237,6 +241,24 @@ func (m *Server) registerAuthenticationMiddleware(router *mux.Router) {
 	router.Use(authenticationInterceptor)
 }
 
+func (m *Server) registerRBACMiddleware(router *mux.Router) {
+	rbacInterceptor := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if action, checked := rbacAction(r.URL.Path); checked {
+					if err := m.checkAdminAction(r, action); err != nil {
+						log.LogWarnf("action[rbacInterceptor] check action[%v] failed, RequestURI[%v], err[%v]",
+							action, r.RequestURI, err)
+						sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeNoPermission, Msg: err.Error()})
+						return
+					}
+				}
+				next.ServeHTTP(w, r)
+			})
+	}
+	router.Use(rbacInterceptor)
+}
+
 func (m *Server) registerAPIRoutes(router *mux.Router) {
 	// graphql api for cluster
 	cs := &ClusterService{user: m.user, cluster: m.cluster, conf: m.config, leaderInfo: m.leaderInfo}
@@ -270,6 +292,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.AdminGetCluster).
 		HandlerFunc(m.getCluster)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetClusterEvents).
+		HandlerFunc(m.getClusterEvents)
 	router.NewRoute().Name(proto.AdminACL).
 		Methods(http.MethodGet).
 		Path(proto.AdminACL).
@@ -293,6 +318,27 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminVolEnableAuditLog).
 		HandlerFunc(m.setEnableAuditLogForVolume)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminVolSetTrashInterval).
+		HandlerFunc(m.setVolTrashInterval)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminVolSetAutoScale).
+		HandlerFunc(m.setVolAutoScale)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminVolGetAutoScale).
+		HandlerFunc(m.getVolAutoScale)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminVolSetPlacement).
+		HandlerFunc(m.setVolPlacement)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminVolGetPlacement).
+		HandlerFunc(m.getVolPlacement)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminVolSetQosLimit).
+		HandlerFunc(m.setVolQosLimit)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminVolGetQosLimit).
+		HandlerFunc(m.getVolQosLimit)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminClusterForbidMpDecommission).
 		HandlerFunc(m.setupForbidMetaPartitionDecommission)
@@ -306,9 +352,16 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 		Path(proto.RaftStatus).
 		HandlerFunc(m.getRaftStatus)
 	router.NewRoute().Methods(http.MethodGet).Path(proto.AdminClusterStat).HandlerFunc(m.clusterStat)
+	router.NewRoute().Methods(http.MethodGet).Path(proto.AdminClusterStatHistory).HandlerFunc(m.clusterStatHistory)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminSetCheckDataReplicasEnable).
 		HandlerFunc(m.setCheckDataReplicasEnable)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetReplicaRepair).
+		HandlerFunc(m.setReplicaRepair)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetReplicaRepairBacklog).
+		HandlerFunc(m.getReplicaRepairBacklog)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminSetConfig).
 		HandlerFunc(m.setConfigHandler)
@@ -449,6 +502,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminDecommissionMetaPartition).
 		HandlerFunc(m.decommissionMetaPartition)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSplitMetaPartition).
+		HandlerFunc(m.splitMetaPartition)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminChangeMetaPartitionLeader).
 		HandlerFunc(m.changeMetaPartitionLeader)
@@ -529,6 +585,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.ClientDiskDataPartitions).
 		HandlerFunc(m.getDiskDataPartitions)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.ClientTopologyEvents).
+		HandlerFunc(m.getTopologyEvents)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminResetDataPartitionDecommissionStatus).
 		HandlerFunc(m.resetDataPartitionDecommissionStatus)
@@ -591,6 +650,12 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.PauseDecommissionDataNode).
 		HandlerFunc(m.pauseDecommissionDataNode)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.ResumeDecommissionDataNode).
+		HandlerFunc(m.resumeDecommissionDataNode)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.QueryDataNodeDecoDetail).
+		HandlerFunc(m.queryDataNodeDecoDetail)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.CancelDecommissionDataNode).
 		HandlerFunc(m.cancelDecommissionDataNode)
@@ -619,6 +684,12 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.PauseDecommissionDisk).
 		HandlerFunc(m.pauseDecommissionDisk)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.ResumeDecommissionDisk).
+		HandlerFunc(m.resumeDecommissionDisk)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.QueryDiskDecoDetail).
+		HandlerFunc(m.queryDiskDecoDetail)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.QueryDecommissionDiskDecoFailedDps).
 		HandlerFunc(m.queryDecommissionDiskDecoFailedDps)
@@ -631,6 +702,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.QueryDiskDetail).
 		HandlerFunc(m.queryDiskDetail)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.QueryPredictedFailureDisks).
+		HandlerFunc(m.queryPredictedFailureDisks)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.QueryAllDecommissionDisk).
 		HandlerFunc(m.queryAllDecommissionDisk)
@@ -671,6 +745,12 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminSetNodeRdOnly).
 		HandlerFunc(m.setNodeRdOnlyHandler)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminEnterNodeMaintenance).
+		HandlerFunc(m.enterNodeMaintenanceHandler)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminExitNodeMaintenance).
+		HandlerFunc(m.exitNodeMaintenanceHandler)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminSetDpRdOnly).
 		HandlerFunc(m.setDpRdOnlyHandler)
@@ -716,6 +796,58 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 		Path(proto.UsersOfVol).
 		HandlerFunc(m.getUsersOfVol)
 
+	// tenant management APIs
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantCreate).
+		HandlerFunc(m.createTenant)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantDelete).
+		HandlerFunc(m.deleteTenant)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantUpdate).
+		HandlerFunc(m.updateTenant)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.TenantGet).
+		HandlerFunc(m.getTenant)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.TenantList).
+		HandlerFunc(m.listTenants)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantAddUser).
+		HandlerFunc(m.addTenantUser)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantRemoveUser).
+		HandlerFunc(m.removeTenantUser)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantAddVol).
+		HandlerFunc(m.addTenantVol)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TenantRemoveVol).
+		HandlerFunc(m.removeTenantVol)
+
+	// role management APIs
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.RoleCreate).
+		HandlerFunc(m.createRole)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.RoleDelete).
+		HandlerFunc(m.deleteRole)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.RoleUpdate).
+		HandlerFunc(m.updateRole)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.RoleGet).
+		HandlerFunc(m.getRole)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.RoleList).
+		HandlerFunc(m.listRoles)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.RoleGrant).
+		HandlerFunc(m.grantRole)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.RoleRevoke).
+		HandlerFunc(m.revokeRole)
+
 	// zone management APIs
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.UpdateZone).
@@ -723,6 +855,12 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.GetAllZones).
 		HandlerFunc(m.listZone)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.SetZoneReservation).
+		HandlerFunc(m.setZoneReservation)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.GetZoneReservation).
+		HandlerFunc(m.getZoneReservation)
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.GetAllNodeSets).
 		HandlerFunc(m.listNodeSets)
@@ -732,6 +870,12 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.UpdateNodeSet).
 		HandlerFunc(m.updateNodeSet)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.SetNodeSetRebalance).
+		HandlerFunc(m.setNodeSetRebalance)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.GetNodeSetRebalance).
+		HandlerFunc(m.getNodeSetRebalance)
 
 	// Quota
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
@@ -237,6 +237,18 @@ func (m *Server) loadMetadata() {
 		panic(err)
 	}
 	log.LogInfo("action[loadS3QoSInfo] end")
+
+	log.LogInfo("action[loadTenants] begin")
+	if err = m.cluster.loadTenants(); err != nil {
+		panic(err)
+	}
+	log.LogInfo("action[loadTenants] end")
+
+	log.LogInfo("action[loadRoles] begin")
+	if err = m.cluster.loadRoles(); err != nil {
+		panic(err)
+	}
+	log.LogInfo("action[loadRoles] end")
 }
 
 func (m *Server) clearMetadata() {
@@ -245,6 +257,11 @@ func (m *Server) clearMetadata() {
 	m.cluster.clearMetaNodes()
 	m.cluster.clearLcNodes()
 	m.cluster.clearVols()
+	m.cluster.tenantMgr.clear()
+	m.cluster.roleMgr.clear()
+	m.cluster.replicaRepairMgr.clear()
+	m.cluster.nodeSetRebalanceMgr.clear()
+	m.cluster.topologyEventLog.clear()
 
 	if m.user != nil {
 		// leader change event may be before m.user initialization
@@ -48,6 +48,8 @@ type MetaNode struct {
 	RdOnly                    bool
 	MigrateLock               sync.RWMutex
 	CpuUtil                   atomicutil.Float64 `json:"-"`
+	MaintenanceExpireTime     int64
+	maintenanceTransferTime   time.Time
 }
 
 func newMetaNode(addr, zoneName, clusterID string) (node *MetaNode) {
@@ -88,7 +90,7 @@ func (metaNode *MetaNode) isWritable() (ok bool) {
 	defer metaNode.RUnlock()
 	if metaNode.IsActive && metaNode.MaxMemAvailWeight > gConfig.metaNodeReservedMem &&
 		!metaNode.reachesThreshold() && metaNode.MetaPartitionCount < defaultMaxMetaPartitionCountOnEachNode &&
-		!metaNode.RdOnly {
+		!metaNode.RdOnly && !inMaintenance(metaNode.MaintenanceExpireTime) {
 		ok = true
 	}
 	return
@@ -154,12 +156,15 @@ func (metaNode *MetaNode) createVersionTask(volume string, version uint64, op ui
 	return
 }
 
-func (metaNode *MetaNode) checkHeartbeat() {
+// checkHeartbeat returns true if the meta node turns inactive
+func (metaNode *MetaNode) checkHeartbeat() (down bool) {
 	metaNode.Lock()
 	defer metaNode.Unlock()
 	if time.Since(metaNode.ReportTime) > time.Second*time.Duration(defaultNodeTimeOutSec) {
+		down = metaNode.IsActive
 		metaNode.IsActive = false
 	}
+	return
 }
 
 // LeaderMetaNode define the leader metaPartitions in meta node
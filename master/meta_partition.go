@@ -40,6 +40,7 @@ type MetaReplica struct {
 	TxRbInoCnt  uint64
 	TxRbDenCnt  uint64
 	FreeListLen uint64
+	Qps         uint64
 	ReportTime  int64
 	Status      int8 // unavailable, readOnly, readWrite
 	IsLeader    bool
@@ -58,6 +59,7 @@ type MetaPartition struct {
 	TxCnt            uint64
 	TxRbInoCnt       uint64
 	TxRbDenCnt       uint64
+	Qps              uint64 // requests per second served by the leader
 	Replicas         []*MetaReplica
 	LeaderReportTime int64
 	ReplicaNum       uint8
@@ -207,8 +209,8 @@ func (mp *MetaPartition) dataSize() uint64 {
 	return maxSize
 }
 
-func (mp *MetaPartition) checkEnd(c *Cluster, maxPartitionID uint64) {
-	if mp.PartitionID < maxPartitionID {
+func (mp *MetaPartition) checkEnd(c *Cluster, lastPartitionID uint64) {
+	if mp.PartitionID != lastPartitionID {
 		return
 	}
 	vol, err := c.getVol(mp.volName)
@@ -220,9 +222,9 @@ func (mp *MetaPartition) checkEnd(c *Cluster, maxPartitionID uint64) {
 	vol.createMpMutex.Lock()
 	defer vol.createMpMutex.Unlock()
 
-	curMaxPartitionID := vol.maxPartitionID()
-	if mp.PartitionID != curMaxPartitionID {
-		log.LogWarnf("action[checkEnd] partition[%v] not max partition[%v]", mp.PartitionID, curMaxPartitionID)
+	curLastPartitionID := vol.lastPartitionID()
+	if mp.PartitionID != curLastPartitionID {
+		log.LogWarnf("action[checkEnd] partition[%v] not last partition[%v]", mp.PartitionID, curLastPartitionID)
 		return
 	}
 
@@ -289,7 +291,7 @@ func (mp *MetaPartition) checkLeader(clusterID string) {
 	}
 }
 
-func (mp *MetaPartition) checkStatus(clusterID string, writeLog bool, replicaNum int, maxPartitionID uint64, metaPartitionInodeIdStep uint64, forbiddenVol bool) (doSplit bool) {
+func (mp *MetaPartition) checkStatus(clusterID string, writeLog bool, replicaNum int, lastPartitionID uint64, metaPartitionInodeIdStep uint64, forbiddenVol bool) (doSplit bool) {
 	mp.Lock()
 	defer mp.Unlock()
 
@@ -323,7 +325,7 @@ func (mp *MetaPartition) checkStatus(clusterID string, writeLog bool, replicaNum
 				continue
 			}
 
-			if mp.PartitionID == maxPartitionID {
+			if mp.PartitionID == lastPartitionID {
 				log.LogInfof("split[checkStatus] need split,id:%v,status:%v,replicaNum:%v,InodeCount:%v", mp.PartitionID, mp.Status, mp.ReplicaNum, mp.InodeCount)
 				doSplit = true
 			} else {
@@ -336,7 +338,7 @@ func (mp *MetaPartition) checkStatus(clusterID string, writeLog bool, replicaNum
 		}
 	}
 
-	if mp.PartitionID >= maxPartitionID && mp.Status == proto.ReadOnly && !forbiddenVol {
+	if mp.PartitionID == lastPartitionID && mp.Status == proto.ReadOnly && !forbiddenVol {
 		mp.Status = proto.ReadWrite
 	}
 
@@ -413,6 +415,7 @@ func (mp *MetaPartition) updateMetaPartition(mgr *proto.MetaPartitionReport, met
 	mr.updateMetric(mgr)
 	if mr.IsLeader {
 		mp.LeaderReportTime = time.Now().Unix()
+		mp.Qps = mr.Qps
 	}
 	mp.setMaxInodeID()
 	mp.setInodeCount()
@@ -743,6 +746,7 @@ func (mr *MetaReplica) updateMetric(mgr *proto.MetaPartitionReport) {
 	mr.TxRbInoCnt = mgr.TxRbInoCnt
 	mr.TxRbDenCnt = mgr.TxRbDenCnt
 	mr.FreeListLen = mgr.FreeListLen
+	mr.Qps = mgr.Qps
 	mr.dataSize = mgr.Size
 	mr.setLastReportTime()
 
@@ -1,7 +1,9 @@
 package master
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -20,6 +22,7 @@ func TestMetaPartition(t *testing.T) {
 		return
 	}
 	createMetaPartition(commonVol, t)
+	splitMetaPartitionOnlineTest(commonVol, t)
 	maxPartitionID := commonVol.maxPartitionID()
 	getMetaPartition(commonVol.Name, maxPartitionID, t)
 	loadMetaPartitionTest(commonVol, maxPartitionID, t)
@@ -104,3 +107,70 @@ func decommissionMetaPartition(vol *Vol, id uint64, t *testing.T) {
 		return
 	}
 }
+
+func splitMetaPartitionOnlineTest(vol *Vol, t *testing.T) {
+	step := gConfig.MetaPartitionInodeIdStep
+	maxPartitionID := vol.maxPartitionID()
+	mp, err := vol.metaPartition(maxPartitionID)
+	if err != nil {
+		t.Errorf("splitMetaPartitionOnline, last meta partition not found, err[%v]", err)
+		return
+	}
+	end := mp.MaxInodeID + step/4
+	vol.mpsLock.RLock()
+	oldPartitionCount := len(vol.MetaPartitions)
+	vol.mpsLock.RUnlock()
+
+	reqURL := fmt.Sprintf("%v%v?name=%v&id=%v&end=%v",
+		hostAddr, proto.AdminSplitMetaPartition, vol.Name, mp.PartitionID, end)
+	process(reqURL, t)
+
+	vol.mpsLock.RLock()
+	newPartitionCount := len(vol.MetaPartitions)
+	vol.mpsLock.RUnlock()
+	assert.Equal(t, oldPartitionCount+1, newPartitionCount)
+	assert.Equal(t, end, mp.End)
+
+	nextMp, err := vol.metaPartition(vol.maxPartitionID())
+	if err != nil {
+		t.Errorf("splitMetaPartitionOnline, next meta partition of [%v] not found, err[%v]", mp.PartitionID, err)
+		return
+	}
+	assert.True(t, nextMp.PartitionID > maxPartitionID)
+	assert.Equal(t, end+1, nextMp.Start)
+	assert.Equal(t, defaultMaxMetaPartitionInodeID, nextMp.End)
+
+	assert.Equal(t, nextMp.PartitionID, vol.lastPartitionID())
+
+	// the partition in the middle of the inode range hands over the rest of its range
+	midEnd := mp.MaxInodeID + step/8
+	reqURL = fmt.Sprintf("%v%v?name=%v&id=%v&end=%v",
+		hostAddr, proto.AdminSplitMetaPartition, vol.Name, mp.PartitionID, midEnd)
+	process(reqURL, t)
+
+	midMp, err := vol.metaPartition(vol.maxPartitionID())
+	if err != nil {
+		t.Errorf("splitMetaPartitionOnline, next meta partition of [%v] not found, err[%v]", mp.PartitionID, err)
+		return
+	}
+	assert.Equal(t, midEnd, mp.End)
+	assert.Equal(t, midEnd+1, midMp.Start)
+	assert.Equal(t, end, midMp.End)
+	assert.Equal(t, nextMp.PartitionID, vol.lastPartitionID())
+
+	// the split point must be inside the range of the partition
+	reqURL = fmt.Sprintf("%v%v?name=%v&id=%v&end=%v",
+		hostAddr, proto.AdminSplitMetaPartition, vol.Name, midMp.PartitionID, end+1)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	reply := &proto.HTTPReply{}
+	if err = json.NewDecoder(resp.Body).Decode(reply); err != nil {
+		t.Error(err)
+		return
+	}
+	assert.NotEqual(t, proto.ErrCodeSuccess, reply.Code)
+}
@@ -62,6 +62,10 @@ type clusterValue struct {
 	EnableAutoDecommissionDisk  bool
 	DecommissionDiskFactor      float64
 	VolDeletionDelayTimeHour    int64
+	ReplicaRepairEnable         bool
+	ReplicaRepairLimitPerZone   uint64
+	NodeSetRebalanceEnable      bool
+	NodeSetRebalanceRate        uint64
 }
 
 func newClusterValue(c *Cluster) (cv *clusterValue) {
@@ -93,6 +97,10 @@ func newClusterValue(c *Cluster) (cv *clusterValue) {
 		EnableAutoDecommissionDisk:  c.EnableAutoDecommissionDisk,
 		DecommissionDiskFactor:      c.DecommissionDiskFactor,
 		VolDeletionDelayTimeHour:    c.cfg.volDelayDeleteTimeHour,
+		ReplicaRepairEnable:         c.replicaRepairEnable,
+		ReplicaRepairLimitPerZone:   c.replicaRepairLimitPerZone,
+		NodeSetRebalanceEnable:      c.nodeSetRebalanceEnable,
+		NodeSetRebalanceRate:        c.nodeSetRebalanceRate,
 	}
 	return cv
 }
@@ -263,6 +271,7 @@ type volValue struct {
 	OSSSecretKey    string
 	CreateTime      int64
 	DeleteLockTime  int64
+	TrashInterval   int64
 	Description     string
 	DpSelectorName  string
 	DpSelectorParm  string
@@ -288,6 +297,8 @@ type volValue struct {
 	TxConflictRetryNum      int64
 	TxConflictRetryInterval int64
 	TxOpLimit               int
+	InlineDirThreshold      int
+	ClientQpsLimit          int
 
 	VolQosEnable                                           bool
 	DiskQosEnable                                          bool
@@ -296,6 +307,10 @@ type volValue struct {
 	ClientReqPeriod, ClientHitTriggerCnt                   uint32
 	Forbidden                                              bool
 	EnableAuditLog                                         bool
+	AutoScalePolicy                                        *bsProto.VolAutoScalePolicy
+	AutoScaleRecords                                       []*bsProto.VolAutoScaleRecord
+	Placement                                              *bsProto.VolPlacement
+	QosLimit                                               *bsProto.VolQosLimit
 }
 
 func (v *volValue) Bytes() (raw []byte, err error) {
@@ -322,6 +337,7 @@ func newVolValue(vol *Vol) (vv *volValue) {
 		OSSSecretKey:            vol.OSSSecretKey,
 		CreateTime:              vol.createTime,
 		DeleteLockTime:          vol.DeleteLockTime,
+		TrashInterval:           vol.TrashInterval,
 		Description:             vol.description,
 		DpSelectorName:          vol.dpSelectorName,
 		DpSelectorParm:          vol.dpSelectorParm,
@@ -333,6 +349,8 @@ func newVolValue(vol *Vol) (vv *volValue) {
 		TxConflictRetryNum:      vol.txConflictRetryNum,
 		TxConflictRetryInterval: vol.txConflictRetryInterval,
 		TxOpLimit:               vol.txOpLimit,
+		InlineDirThreshold:      vol.inlineDirThreshold,
+		ClientQpsLimit:          vol.clientQpsLimit,
 
 		VolType:             vol.VolType,
 		EbsBlkSize:          vol.EbsBlkSize,
@@ -359,6 +377,10 @@ func newVolValue(vol *Vol) (vv *volValue) {
 		DpReadOnlyWhenVolFull: vol.DpReadOnlyWhenVolFull,
 		Forbidden:             vol.Forbidden,
 		EnableAuditLog:        vol.EnableAuditLog,
+		AutoScalePolicy:       vol.autoScalePolicy,
+		AutoScaleRecords:      vol.autoScaler.getRecords(),
+		Placement:             vol.placement,
+		QosLimit:              vol.qosLimit,
 		AuthKey:               vol.authKey,
 		DeleteExecTime:        vol.DeleteExecTime,
 		User:                  vol.user,
@@ -388,9 +410,11 @@ type dataNodeValue struct {
 	DecommissionLimit        int
 	DecommissionRetry        uint8
 	DecommissionCompleteTime int64
+	DecommissionStartTime    int64
 	ToBeOffline              bool
 	DecommissionDiskList     []string
 	DecommissionDpTotal      int
+	MaintenanceExpireTime    int64
 }
 
 func newDataNodeValue(dataNode *DataNode) *dataNodeValue {
@@ -407,27 +431,31 @@ func newDataNodeValue(dataNode *DataNode) *dataNodeValue {
 		DecommissionLimit:        dataNode.DecommissionLimit,
 		DecommissionRetry:        dataNode.DecommissionRetry,
 		DecommissionCompleteTime: dataNode.DecommissionCompleteTime,
+		DecommissionStartTime:    dataNode.DecommissionStartTime,
 		ToBeOffline:              dataNode.ToBeOffline,
 		DecommissionDiskList:     dataNode.DecommissionDiskList,
 		DecommissionDpTotal:      dataNode.DecommissionDpTotal,
+		MaintenanceExpireTime:    dataNode.MaintenanceExpireTime,
 	}
 }
 
 type metaNodeValue struct {
-	ID        uint64
-	NodeSetID uint64
-	Addr      string
-	ZoneName  string
-	RdOnly    bool
+	ID                    uint64
+	NodeSetID             uint64
+	Addr                  string
+	ZoneName              string
+	RdOnly                bool
+	MaintenanceExpireTime int64
 }
 
 func newMetaNodeValue(metaNode *MetaNode) *metaNodeValue {
 	return &metaNodeValue{
-		ID:        metaNode.ID,
-		NodeSetID: metaNode.NodeSetID,
-		Addr:      metaNode.Addr,
-		ZoneName:  metaNode.ZoneName,
-		RdOnly:    metaNode.RdOnly,
+		ID:                    metaNode.ID,
+		NodeSetID:             metaNode.NodeSetID,
+		Addr:                  metaNode.Addr,
+		ZoneName:              metaNode.ZoneName,
+		RdOnly:                metaNode.RdOnly,
+		MaintenanceExpireTime: metaNode.MaintenanceExpireTime,
 	}
 }
 
@@ -1031,6 +1059,8 @@ func (c *Cluster) loadZoneValue() (err error) {
 		zone.QosIopsWLimit = cv.QosIopsWLimit
 		zone.QosFlowWLimit = cv.QosFlowWLimit
 		zone.QosIopsRLimit = cv.QosIopsRLimit
+		zone.DataReservedRatio = cv.DataReservedRatio
+		zone.MetaReservedRatio = cv.MetaReservedRatio
 		if zone.GetDataNodesetSelector() != cv.DataNodesetSelector {
 			zone.dataNodesetSelector = NewNodesetSelector(cv.DataNodesetSelector, DataNodeType)
 		}
@@ -1149,6 +1179,10 @@ func (c *Cluster) loadClusterValue() (err error) {
 		log.LogInfof("action[loadClusterValue], metaNodeThreshold[%v]", cv.Threshold)
 
 		c.checkDataReplicasEnable = cv.CheckDataReplicasEnable
+		c.replicaRepairEnable = cv.ReplicaRepairEnable
+		c.replicaRepairLimitPerZone = cv.ReplicaRepairLimitPerZone
+		c.nodeSetRebalanceEnable = cv.NodeSetRebalanceEnable
+		c.nodeSetRebalanceRate = cv.NodeSetRebalanceRate
 	}
 	return
 }
@@ -1371,9 +1405,11 @@ func (c *Cluster) loadDataNodes() (err error) {
 		dataNode.DecommissionLimit = dnv.DecommissionLimit
 		dataNode.DecommissionRetry = dnv.DecommissionRetry
 		dataNode.DecommissionCompleteTime = dnv.DecommissionCompleteTime
+		dataNode.DecommissionStartTime = dnv.DecommissionStartTime
 		dataNode.ToBeOffline = dnv.ToBeOffline
 		dataNode.DecommissionDiskList = dnv.DecommissionDiskList
 		dataNode.DecommissionDpTotal = dnv.DecommissionDpTotal
+		dataNode.MaintenanceExpireTime = dnv.MaintenanceExpireTime
 		olddn, ok := c.dataNodes.Load(dataNode.Addr)
 		if ok {
 			if olddn.(*DataNode).ID <= dataNode.ID {
@@ -1412,6 +1448,7 @@ func (c *Cluster) loadMetaNodes() (err error) {
 		metaNode.ID = mnv.ID
 		metaNode.NodeSetID = mnv.NodeSetID
 		metaNode.RdOnly = mnv.RdOnly
+		metaNode.MaintenanceExpireTime = mnv.MaintenanceExpireTime
 
 		oldmn, ok := c.metaNodes.Load(metaNode.Addr)
 		if ok {
@@ -1640,6 +1677,7 @@ type decommissionDiskValue struct {
 	Type                     uint32
 	DecommissionCompleteTime int64
 	DecommissionLimit        int
+	DecommissionStartTime    int64
 }
 
 func newDecommissionDiskValue(disk *DecommissionDisk) *decommissionDiskValue {
@@ -1655,6 +1693,7 @@ func newDecommissionDiskValue(disk *DecommissionDisk) *decommissionDiskValue {
 		Type:                     disk.Type,
 		DecommissionCompleteTime: disk.DecommissionCompleteTime,
 		DecommissionLimit:        disk.DecommissionDpCount,
+		DecommissionStartTime:    disk.DecommissionStartTime,
 	}
 }
 
@@ -1671,6 +1710,7 @@ func (ddv *decommissionDiskValue) Restore() *DecommissionDisk {
 		Type:                     ddv.Type,
 		DecommissionCompleteTime: ddv.DecommissionCompleteTime,
 		DecommissionDpCount:      ddv.DecommissionLimit,
+		DecommissionStartTime:    ddv.DecommissionStartTime,
 	}
 }
 
@@ -88,6 +88,8 @@ const (
 	MetricLcTotalFileScanned         = "lc_total_file_scanned"
 	MetricLcTotalDirScanned          = "lc_total_dirs_scanned"
 	MetricLcTotalExpired             = "lc_total_expired"
+	MetricLcTotalTransitioned        = "lc_total_transitioned"
+	MetricLcTotalAbortedMultipart    = "lc_total_aborted_multipart"
 )
 
 var WarnMetrics *warningMetrics
@@ -143,12 +145,14 @@ type monitorMetrics struct {
 	inconsistentMps               map[string]string
 	nodesetIds                    map[uint64]string
 
-	lcNodesCount       *exporter.Gauge
-	lcVolNames         map[string]struct{}
-	lcTotalScanned     *exporter.GaugeVec
-	lcTotalFileScanned *exporter.GaugeVec
-	lcTotalDirScanned  *exporter.GaugeVec
-	lcTotalExpired     *exporter.GaugeVec
+	lcNodesCount        *exporter.Gauge
+	lcVolNames          map[string]struct{}
+	lcTotalScanned      *exporter.GaugeVec
+	lcTotalFileScanned  *exporter.GaugeVec
+	lcTotalDirScanned   *exporter.GaugeVec
+	lcTotalExpired      *exporter.GaugeVec
+	lcTotalTransitioned *exporter.GaugeVec
+	lcTotalAborted      *exporter.GaugeVec
 }
 
 func newMonitorMetrics(c *Cluster) *monitorMetrics {
@@ -477,6 +481,8 @@ func (mm *monitorMetrics) start() {
 	mm.lcTotalFileScanned = exporter.NewGaugeVec(MetricLcTotalFileScanned, "", []string{"volName", "type"})
 	mm.lcTotalDirScanned = exporter.NewGaugeVec(MetricLcTotalDirScanned, "", []string{"volName", "type"})
 	mm.lcTotalExpired = exporter.NewGaugeVec(MetricLcTotalExpired, "", []string{"volName", "type"})
+	mm.lcTotalTransitioned = exporter.NewGaugeVec(MetricLcTotalTransitioned, "", []string{"volName", "type"})
+	mm.lcTotalAborted = exporter.NewGaugeVec(MetricLcTotalAbortedMultipart, "", []string{"volName", "type"})
 	go mm.statMetrics()
 }
 
@@ -897,6 +903,8 @@ func (mm *monitorMetrics) deleteS3LcVolMetric(volName string) {
 	mm.lcTotalFileScanned.DeleteLabelValues(volName, "file")
 	mm.lcTotalDirScanned.DeleteLabelValues(volName, "dir")
 	mm.lcTotalExpired.DeleteLabelValues(volName, "expired")
+	mm.lcTotalTransitioned.DeleteLabelValues(volName, "transitioned")
+	mm.lcTotalAborted.DeleteLabelValues(volName, "aborted")
 }
 
 func (mm *monitorMetrics) setLcMetrics() {
@@ -918,6 +926,8 @@ func (mm *monitorMetrics) setLcMetrics() {
 		mm.lcTotalFileScanned.SetWithLabelValues(float64(stat.FileScannedNum), key, "file")
 		mm.lcTotalDirScanned.SetWithLabelValues(float64(stat.DirScannedNum), key, "dir")
 		mm.lcTotalExpired.SetWithLabelValues(float64(stat.ExpiredNum), key, "expired")
+		mm.lcTotalTransitioned.SetWithLabelValues(float64(stat.TransitionedNum), key, "transitioned")
+		mm.lcTotalAborted.SetWithLabelValues(float64(stat.AbortedMultipartNum), key, "aborted")
 	}
 }
 
@@ -0,0 +1,276 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	defaultNodeMaintenanceTTL = 3600
+	maxNodeMaintenanceTTL     = 7 * 24 * 3600
+	// maintenanceTransferInterval is the interval to transfer leaders away from a node in maintenance again,
+	// leaders may come back to the node after it restarts.
+	maintenanceTransferInterval = time.Minute
+)
+
+// inMaintenance returns true if the maintenance which expires at expireTime is not over
+func inMaintenance(expireTime int64) bool {
+	return expireTime > time.Now().Unix()
+}
+
+func (dataNode *DataNode) isInMaintenance() bool {
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	return inMaintenance(dataNode.MaintenanceExpireTime)
+}
+
+func (metaNode *MetaNode) isInMaintenance() bool {
+	metaNode.RLock()
+	defer metaNode.RUnlock()
+	return inMaintenance(metaNode.MaintenanceExpireTime)
+}
+
+// setNodeMaintenance puts the node into maintenance until expireTime, the node leaves maintenance if expireTime is 0.
+// Leaders of partitions on the node are transferred to other replicas when it enters maintenance.
+func (c *Cluster) setNodeMaintenance(addr string, nodeType uint32, expireTime int64) (err error) {
+	if nodeType == TypeDataPartition {
+		var dataNode *DataNode
+		if dataNode, err = c.setDataNodeMaintenance(addr, expireTime); err != nil {
+			return
+		}
+		if expireTime > 0 {
+			c.recordEvent(proto.EventSeverityInfo, eventModuleDataNode, "datanode %v enters maintenance until %v",
+				addr, time.Unix(expireTime, 0).Format(proto.TimeFormat))
+			go c.transferDataNodeLeaders(dataNode)
+			return
+		}
+		c.recordEvent(proto.EventSeverityInfo, eventModuleDataNode, "datanode %v exits maintenance", addr)
+		return
+	}
+
+	var metaNode *MetaNode
+	if metaNode, err = c.setMetaNodeMaintenance(addr, expireTime); err != nil {
+		return
+	}
+	if expireTime > 0 {
+		c.recordEvent(proto.EventSeverityInfo, eventModuleMetaNode, "metanode %v enters maintenance until %v",
+			addr, time.Unix(expireTime, 0).Format(proto.TimeFormat))
+		go c.transferMetaNodeLeaders(metaNode)
+		return
+	}
+	c.recordEvent(proto.EventSeverityInfo, eventModuleMetaNode, "metanode %v exits maintenance", addr)
+	return
+}
+
+func (c *Cluster) setDataNodeMaintenance(addr string, expireTime int64) (dataNode *DataNode, err error) {
+	c.dnMutex.Lock()
+	defer c.dnMutex.Unlock()
+	if dataNode, err = c.dataNode(addr); err != nil {
+		return
+	}
+	dataNode.Lock()
+	oldExpireTime := dataNode.MaintenanceExpireTime
+	dataNode.MaintenanceExpireTime = expireTime
+	if expireTime > 0 {
+		dataNode.maintenanceTransferTime = time.Now()
+	}
+	dataNode.Unlock()
+	if err = c.syncUpdateDataNode(dataNode); err != nil {
+		dataNode.Lock()
+		dataNode.MaintenanceExpireTime = oldExpireTime
+		dataNode.Unlock()
+		return nil, fmt.Errorf("[setDataNodeMaintenance] syncUpdateDataNode err(%v)", err)
+	}
+	log.LogInfof("action[setDataNodeMaintenance] datanode[%v] maintenance expire time[%v]", addr, expireTime)
+	return
+}
+
+func (c *Cluster) setMetaNodeMaintenance(addr string, expireTime int64) (metaNode *MetaNode, err error) {
+	c.mnMutex.Lock()
+	defer c.mnMutex.Unlock()
+	if metaNode, err = c.metaNode(addr); err != nil {
+		return
+	}
+	metaNode.Lock()
+	oldExpireTime := metaNode.MaintenanceExpireTime
+	metaNode.MaintenanceExpireTime = expireTime
+	if expireTime > 0 {
+		metaNode.maintenanceTransferTime = time.Now()
+	}
+	metaNode.Unlock()
+	if err = c.syncUpdateMetaNode(metaNode); err != nil {
+		metaNode.Lock()
+		metaNode.MaintenanceExpireTime = oldExpireTime
+		metaNode.Unlock()
+		return nil, fmt.Errorf("[setMetaNodeMaintenance] syncUpdateMetaNode err(%v)", err)
+	}
+	log.LogInfof("action[setMetaNodeMaintenance] metanode[%v] maintenance expire time[%v]", addr, expireTime)
+	return
+}
+
+// checkDataNodeMaintenance takes the node out of maintenance when it expires, or transfers leaders which
+// came back to the node while it is still in maintenance.
+func (c *Cluster) checkDataNodeMaintenance(dataNode *DataNode) {
+	dataNode.Lock()
+	expireTime := dataNode.MaintenanceExpireTime
+	transfer := inMaintenance(expireTime) && time.Since(dataNode.maintenanceTransferTime) > maintenanceTransferInterval
+	if transfer {
+		dataNode.maintenanceTransferTime = time.Now()
+	}
+	dataNode.Unlock()
+	if expireTime == 0 {
+		return
+	}
+	if transfer {
+		go c.transferDataNodeLeaders(dataNode)
+		return
+	}
+	if inMaintenance(expireTime) {
+		return
+	}
+	if _, err := c.setDataNodeMaintenance(dataNode.Addr, 0); err != nil {
+		log.LogWarnf("action[checkDataNodeMaintenance] datanode[%v] exits maintenance failed, err[%v]", dataNode.Addr, err)
+		return
+	}
+	c.recordEvent(proto.EventSeverityInfo, eventModuleDataNode, "datanode %v exits maintenance as it expires", dataNode.Addr)
+}
+
+// checkMetaNodeMaintenance is the same as checkDataNodeMaintenance for meta nodes
+func (c *Cluster) checkMetaNodeMaintenance(metaNode *MetaNode) {
+	metaNode.Lock()
+	expireTime := metaNode.MaintenanceExpireTime
+	transfer := inMaintenance(expireTime) && time.Since(metaNode.maintenanceTransferTime) > maintenanceTransferInterval
+	if transfer {
+		metaNode.maintenanceTransferTime = time.Now()
+	}
+	metaNode.Unlock()
+	if expireTime == 0 {
+		return
+	}
+	if transfer {
+		go c.transferMetaNodeLeaders(metaNode)
+		return
+	}
+	if inMaintenance(expireTime) {
+		return
+	}
+	if _, err := c.setMetaNodeMaintenance(metaNode.Addr, 0); err != nil {
+		log.LogWarnf("action[checkMetaNodeMaintenance] metanode[%v] exits maintenance failed, err[%v]", metaNode.Addr, err)
+		return
+	}
+	c.recordEvent(proto.EventSeverityInfo, eventModuleMetaNode, "metanode %v exits maintenance as it expires", metaNode.Addr)
+}
+
+// getMaintenanceLeaderCandidate returns a live replica not in maintenance to be the new leader of the partition
+func (partition *DataPartition) getMaintenanceLeaderCandidate(addr string) *DataNode {
+	partition.RLock()
+	defer partition.RUnlock()
+	for _, replica := range partition.Replicas {
+		if replica.Addr == addr || replica.dataNode == nil {
+			continue
+		}
+		if replica.isLive(defaultDataPartitionTimeOutSec) && !replica.dataNode.isInMaintenance() {
+			return replica.dataNode
+		}
+	}
+	return nil
+}
+
+func (mp *MetaPartition) getMaintenanceLeaderCandidate(addr string) *MetaNode {
+	mp.RLock()
+	defer mp.RUnlock()
+	for _, mr := range mp.Replicas {
+		if mr.Addr == addr || mr.metaNode == nil {
+			continue
+		}
+		if mr.isActive() && !mr.metaNode.isInMaintenance() {
+			return mr.metaNode
+		}
+	}
+	return nil
+}
+
+func (mp *MetaPartition) getLeaderAddrWithLock() string {
+	mp.RLock()
+	defer mp.RUnlock()
+	mr, err := mp.getMetaReplicaLeader()
+	if err != nil {
+		return ""
+	}
+	return mr.Addr
+}
+
+// transferDataNodeLeaders tries to transfer leaders of data partitions on the node to other replicas
+func (c *Cluster) transferDataNodeLeaders(dataNode *DataNode) {
+	var transferred, failed int
+	for _, dp := range c.getAllDataPartitionByDataNode(dataNode.Addr) {
+		if dp.getLeaderAddrWithLock() != dataNode.Addr {
+			continue
+		}
+		target := dp.getMaintenanceLeaderCandidate(dataNode.Addr)
+		if target == nil {
+			failed++
+			log.LogWarnf("action[transferDataNodeLeaders] dp[%v] has no replica to be leader instead of datanode[%v]",
+				dp.PartitionID, dataNode.Addr)
+			continue
+		}
+		if err := dp.tryToChangeLeader(c, target); err != nil {
+			failed++
+			log.LogWarnf("action[transferDataNodeLeaders] dp[%v] change leader from [%v] to [%v] failed, err[%v]",
+				dp.PartitionID, dataNode.Addr, target.Addr, err)
+			continue
+		}
+		transferred++
+	}
+	log.LogInfof("action[transferDataNodeLeaders] datanode[%v] transferred[%v] failed[%v]", dataNode.Addr, transferred, failed)
+	if failed > 0 {
+		c.recordEvent(proto.EventSeverityWarning, eventModuleDataNode, "datanode %v in maintenance failed to transfer %v leaders",
+			dataNode.Addr, failed)
+	}
+}
+
+// transferMetaNodeLeaders tries to transfer leaders of meta partitions on the node to other replicas
+func (c *Cluster) transferMetaNodeLeaders(metaNode *MetaNode) {
+	var transferred, failed int
+	for _, mp := range c.getAllMetaPartitionByMetaNode(metaNode.Addr) {
+		if mp.getLeaderAddrWithLock() != metaNode.Addr {
+			continue
+		}
+		target := mp.getMaintenanceLeaderCandidate(metaNode.Addr)
+		if target == nil {
+			failed++
+			log.LogWarnf("action[transferMetaNodeLeaders] mp[%v] has no replica to be leader instead of metanode[%v]",
+				mp.PartitionID, metaNode.Addr)
+			continue
+		}
+		if err := mp.tryToChangeLeader(c, target); err != nil {
+			failed++
+			log.LogWarnf("action[transferMetaNodeLeaders] mp[%v] change leader from [%v] to [%v] failed, err[%v]",
+				mp.PartitionID, metaNode.Addr, target.Addr, err)
+			continue
+		}
+		transferred++
+	}
+	log.LogInfof("action[transferMetaNodeLeaders] metanode[%v] transferred[%v] failed[%v]", metaNode.Addr, transferred, failed)
+	if failed > 0 {
+		c.recordEvent(proto.EventSeverityWarning, eventModuleMetaNode, "metanode %v in maintenance failed to transfer %v leaders",
+			metaNode.Addr, failed)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequestToEnterNodeMaintenance(t *testing.T) {
+	r := httptest.NewRequest("GET", "/admin/enterNodeMaintenance?addr=127.0.0.1:17310&nodeType=2", nil)
+	addr, nodeType, ttl, err := parseRequestToEnterNodeMaintenance(r)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:17310", addr)
+	require.Equal(t, TypeDataPartition, nodeType)
+	require.EqualValues(t, defaultNodeMaintenanceTTL, ttl)
+
+	r = httptest.NewRequest("GET", "/admin/enterNodeMaintenance?addr=127.0.0.1:17210&nodeType=1&ttl=60", nil)
+	_, nodeType, ttl, err = parseRequestToEnterNodeMaintenance(r)
+	require.NoError(t, err)
+	require.Equal(t, TypeMetaPartition, nodeType)
+	require.EqualValues(t, 60, ttl)
+
+	r = httptest.NewRequest("GET", "/admin/enterNodeMaintenance?addr=127.0.0.1:17310&nodeType=2&ttl=0", nil)
+	_, _, _, err = parseRequestToEnterNodeMaintenance(r)
+	require.Error(t, err)
+
+	r = httptest.NewRequest("GET", "/admin/enterNodeMaintenance?nodeType=2", nil)
+	_, _, _, err = parseRequestToEnterNodeMaintenance(r)
+	require.Error(t, err)
+}
+
+func TestNodeInMaintenanceNotWritable(t *testing.T) {
+	dataNode := newDataNode("127.0.0.1:17310", testZone1, "cluster")
+	dataNode.isActive = true
+	dataNode.AvailableSpace = 100 * util.GB
+	require.True(t, dataNode.isWriteAble())
+
+	dataNode.MaintenanceExpireTime = time.Now().Add(time.Hour).Unix()
+	require.True(t, dataNode.isInMaintenance())
+	require.False(t, dataNode.isWriteAble())
+	require.False(t, dataNode.isWriteAbleWithSize(util.GB))
+
+	dataNode.MaintenanceExpireTime = time.Now().Add(-time.Second).Unix()
+	require.False(t, dataNode.isInMaintenance())
+	require.True(t, dataNode.isWriteAble())
+}
@@ -0,0 +1,541 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	nodeSetRebalanceRunning = "running"
+	nodeSetRebalanceDone    = "done"
+	nodeSetRebalanceFailed  = "failed"
+
+	rebalanceDataPartition = "data"
+	rebalanceMetaPartition = "meta"
+
+	defaultNodeSetRebalanceRate = 5
+	nodeSetRebalanceInterval    = time.Minute
+)
+
+// rebalanceNodeLoad is the load of a data node or meta node in the rebalance of its nodeset
+type rebalanceNodeLoad struct {
+	addr       string
+	total      uint64
+	canAlloc   bool
+	partitions []uint64
+	count      int
+	expected   float64
+}
+
+// surplus returns how many replicas the node holds more than it is expected to, it is balanced if the
+// surplus is less than 1.
+func (load *rebalanceNodeLoad) surplus() float64 {
+	return float64(load.count) - load.expected
+}
+
+// setExpectedLoads sets the expected replica count of each node proportional to its total space, and returns
+// the count of replicas to move to balance the nodes.
+func setExpectedLoads(loads []*rebalanceNodeLoad) (moves int) {
+	var total, weights uint64
+	for _, load := range loads {
+		total += uint64(load.count)
+		weights += load.total
+	}
+	for _, load := range loads {
+		if weights == 0 {
+			load.expected = float64(total) / float64(len(loads))
+		} else {
+			load.expected = float64(total) * float64(load.total) / float64(weights)
+		}
+		if over := load.count - int(math.Ceil(load.expected)); over > 0 {
+			moves += over
+		}
+	}
+	return
+}
+
+// rebalanceNodeSetLoads is the loads of nodes of a nodeset holding one type of partitions. Replicas are only
+// migrated among nodes of the same nodeset, so that all replicas of a partition in a zone stay in one nodeset.
+type rebalanceNodeSetLoads struct {
+	zoneName      string
+	nodeSetID     uint64
+	partitionType string
+	loads         []*rebalanceNodeLoad
+}
+
+func rebalanceNodeSetKey(zoneName string, nodeSetID uint64, partitionType string) string {
+	return fmt.Sprintf("%s_%d_%s", zoneName, nodeSetID, partitionType)
+}
+
+func (set *rebalanceNodeSetLoads) key() string {
+	return rebalanceNodeSetKey(set.zoneName, set.nodeSetID, set.partitionType)
+}
+
+func rebalanceMoveKey(partitionType string, partitionID uint64) string {
+	return fmt.Sprintf("%s_%d", partitionType, partitionID)
+}
+
+// nodeSetRebalanceManager holds the progress of the rebalance since it was enabled
+type nodeSetRebalanceManager struct {
+	sync.RWMutex
+	startTime int64
+	moves     map[string]*proto.NodeSetRebalanceMove
+	nodeSets  map[string]*proto.NodeSetRebalanceNodeSet
+}
+
+func newNodeSetRebalanceManager() *nodeSetRebalanceManager {
+	return &nodeSetRebalanceManager{
+		moves:    make(map[string]*proto.NodeSetRebalanceMove),
+		nodeSets: make(map[string]*proto.NodeSetRebalanceNodeSet),
+	}
+}
+
+func (mgr *nodeSetRebalanceManager) reset(startTime int64) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.startTime = startTime
+	mgr.moves = make(map[string]*proto.NodeSetRebalanceMove)
+	mgr.nodeSets = make(map[string]*proto.NodeSetRebalanceNodeSet)
+}
+
+func (mgr *nodeSetRebalanceManager) clear() {
+	mgr.reset(0)
+}
+
+// start resets the progress, moves scheduled before are not tracked any more
+func (mgr *nodeSetRebalanceManager) start() {
+	mgr.reset(time.Now().Unix())
+}
+
+func (mgr *nodeSetRebalanceManager) getNodeSet(zoneName string, nodeSetID uint64,
+	partitionType string,
+) *proto.NodeSetRebalanceNodeSet {
+	key := rebalanceNodeSetKey(zoneName, nodeSetID, partitionType)
+	ns, ok := mgr.nodeSets[key]
+	if !ok {
+		ns = &proto.NodeSetRebalanceNodeSet{ZoneName: zoneName, NodeSetID: nodeSetID, PartitionType: partitionType}
+		mgr.nodeSets[key] = ns
+	}
+	return ns
+}
+
+func (mgr *nodeSetRebalanceManager) addMove(move *proto.NodeSetRebalanceMove) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.moves[rebalanceMoveKey(move.PartitionType, move.PartitionID)] = move
+	mgr.getNodeSet(move.ZoneName, move.NodeSetID, move.PartitionType).RunningMoves++
+}
+
+func (mgr *nodeSetRebalanceManager) isMoving(partitionType string, partitionID uint64) bool {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	_, ok := mgr.moves[rebalanceMoveKey(partitionType, partitionID)]
+	return ok
+}
+
+// finishMove removes the move from the running ones and counts it as done or failed
+func (mgr *nodeSetRebalanceManager) finishMove(move *proto.NodeSetRebalanceMove, status string) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	delete(mgr.moves, rebalanceMoveKey(move.PartitionType, move.PartitionID))
+	ns := mgr.getNodeSet(move.ZoneName, move.NodeSetID, move.PartitionType)
+	ns.RunningMoves--
+	if status == nodeSetRebalanceDone {
+		ns.DoneMoves++
+	} else {
+		ns.FailedMoves++
+	}
+}
+
+func (mgr *nodeSetRebalanceManager) runningMoves(key string) int {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	if ns, ok := mgr.nodeSets[key]; ok {
+		return ns.RunningMoves
+	}
+	return 0
+}
+
+func (mgr *nodeSetRebalanceManager) listMoves() []*proto.NodeSetRebalanceMove {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	moves := make([]*proto.NodeSetRebalanceMove, 0, len(mgr.moves))
+	for _, move := range mgr.moves {
+		moves = append(moves, move)
+	}
+	return moves
+}
+
+// updateNodeSet updates the loads of the nodeset found in a round
+func (mgr *nodeSetRebalanceManager) updateNodeSet(set *rebalanceNodeSetLoads, pendingMoves int) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	ns := mgr.getNodeSet(set.zoneName, set.nodeSetID, set.partitionType)
+	ns.NodeCount = len(set.loads)
+	ns.PendingMoves = pendingMoves
+	ns.MinCount, ns.MaxCount = 0, 0
+	for i, load := range set.loads {
+		if i == 0 || load.count < ns.MinCount {
+			ns.MinCount = load.count
+		}
+		if load.count > ns.MaxCount {
+			ns.MaxCount = load.count
+		}
+	}
+}
+
+func (mgr *nodeSetRebalanceManager) getStatus() (startTime int64, nodeSets []*proto.NodeSetRebalanceNodeSet,
+	moves []*proto.NodeSetRebalanceMove,
+) {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	nodeSets = make([]*proto.NodeSetRebalanceNodeSet, 0, len(mgr.nodeSets))
+	for _, ns := range mgr.nodeSets {
+		dup := *ns
+		total := dup.DoneMoves + uint64(dup.RunningMoves) + uint64(dup.PendingMoves)
+		progress := float64(1)
+		if total > 0 {
+			progress = float64(dup.DoneMoves) / float64(total)
+		}
+		dup.Progress = fmt.Sprintf("%.2f%%", progress*float64(100))
+		nodeSets = append(nodeSets, &dup)
+	}
+	sort.Slice(nodeSets, func(i, j int) bool {
+		if nodeSets[i].ZoneName != nodeSets[j].ZoneName {
+			return nodeSets[i].ZoneName < nodeSets[j].ZoneName
+		}
+		if nodeSets[i].NodeSetID != nodeSets[j].NodeSetID {
+			return nodeSets[i].NodeSetID < nodeSets[j].NodeSetID
+		}
+		return nodeSets[i].PartitionType < nodeSets[j].PartitionType
+	})
+	moves = make([]*proto.NodeSetRebalanceMove, 0, len(mgr.moves))
+	for _, move := range mgr.moves {
+		dup := *move
+		moves = append(moves, &dup)
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].PartitionType != moves[j].PartitionType {
+			return moves[i].PartitionType < moves[j].PartitionType
+		}
+		return moves[i].PartitionID < moves[j].PartitionID
+	})
+	return mgr.startTime, nodeSets, moves
+}
+
+func (c *Cluster) getNodeSetRebalanceRate() uint64 {
+	if c.nodeSetRebalanceRate == 0 {
+		return defaultNodeSetRebalanceRate
+	}
+	return c.nodeSetRebalanceRate
+}
+
+// checkRebalanceMoves counts the moves whose decommission or recovery finished as done or failed
+func (c *Cluster) checkRebalanceMoves() {
+	for _, move := range c.nodeSetRebalanceMgr.listMoves() {
+		var hosts []string
+		if move.PartitionType == rebalanceMetaPartition {
+			mp, err := c.getMetaPartitionByID(move.PartitionID)
+			if err != nil {
+				c.nodeSetRebalanceMgr.finishMove(move, nodeSetRebalanceFailed)
+				continue
+			}
+			mp.RLock()
+			recovering := mp.IsRecover
+			hosts = mp.Hosts
+			mp.RUnlock()
+			if recovering {
+				continue
+			}
+		} else {
+			dp, err := c.getDataPartitionByID(move.PartitionID)
+			if err != nil || dp.IsDecommissionFailed() {
+				c.nodeSetRebalanceMgr.finishMove(move, nodeSetRebalanceFailed)
+				continue
+			}
+			if dp.IsDoingDecommission() {
+				continue
+			}
+			dp.RLock()
+			hosts = dp.Hosts
+			dp.RUnlock()
+		}
+		if contains(hosts, move.DstAddr) && !contains(hosts, move.SrcAddr) {
+			c.nodeSetRebalanceMgr.finishMove(move, nodeSetRebalanceDone)
+		} else {
+			c.nodeSetRebalanceMgr.finishMove(move, nodeSetRebalanceFailed)
+		}
+	}
+}
+
+// canRebalanceVol returns true if partitions of the vol could be rebalanced
+func (c *Cluster) canRebalanceVol(volName string) bool {
+	vol, err := c.getVol(volName)
+	return err == nil && vol.Status != proto.VolStatusMarkDelete && !c.isFaultDomain(vol)
+}
+
+// canRebalanceDataPartition returns true if a replica of the partition can be migrated to dstAddr
+func (c *Cluster) canRebalanceDataPartition(dp *DataPartition, dstAddr string) bool {
+	if !c.canRebalanceVol(dp.VolName) {
+		return false
+	}
+	if dp.IsDiscard || !proto.IsNormalDp(dp.PartitionType) || dp.IsDoingDecommission() ||
+		c.nodeSetRebalanceMgr.isMoving(rebalanceDataPartition, dp.PartitionID) {
+		return false
+	}
+	dp.RLock()
+	defer dp.RUnlock()
+	return !dp.isRecover && len(dp.Hosts) == int(dp.ReplicaNum) && len(dp.Replicas) == len(dp.Hosts) &&
+		!dp.hasHost(dstAddr)
+}
+
+// canRebalanceMetaPartition returns true if a replica of the partition can be migrated to dstAddr
+func (c *Cluster) canRebalanceMetaPartition(mp *MetaPartition, dstAddr string) bool {
+	if c.ForbidMpDecommission || !c.canRebalanceVol(mp.volName) ||
+		c.nodeSetRebalanceMgr.isMoving(rebalanceMetaPartition, mp.PartitionID) {
+		return false
+	}
+	mp.RLock()
+	defer mp.RUnlock()
+	return !mp.IsRecover && len(mp.Hosts) == int(mp.ReplicaNum) && !contains(mp.Hosts, dstAddr)
+}
+
+// migrateDataReplica marks the replica of the partition on srcAddr to be decommissioned to dstAddr, the target
+// is chosen by decommission if dstAddr is empty.
+func (c *Cluster) migrateDataReplica(dp *DataPartition, srcAddr, dstAddr string) (err error) {
+	replica, err := dp.getReplica(srcAddr)
+	if err != nil {
+		return
+	}
+	node, err := c.dataNode(srcAddr)
+	if err != nil {
+		return
+	}
+	zone, err := c.t.getZone(node.ZoneName)
+	if err != nil {
+		return
+	}
+	ns, err := zone.getNodeSet(node.NodeSetID)
+	if err != nil {
+		return
+	}
+	if !dp.MarkDecommissionStatus(srcAddr, dstAddr, replica.DiskPath, false, 0, c) {
+		return fmt.Errorf("mark decommission failed")
+	}
+	if err = c.syncUpdateDataPartition(dp); err != nil {
+		return
+	}
+	ns.AddToDecommissionDataPartitionList(dp, c)
+	return
+}
+
+// rebalanceReplica migrates a replica of the partition from srcAddr to dstAddr if it can be moved, it returns
+// the vol of the partition and whether the migration is started.
+func (c *Cluster) rebalanceReplica(partitionType string, partitionID uint64, srcAddr, dstAddr string) (string, bool) {
+	if partitionType == rebalanceMetaPartition {
+		mp, err := c.getMetaPartitionByID(partitionID)
+		if err != nil || !c.canRebalanceMetaPartition(mp, dstAddr) {
+			return "", false
+		}
+		if err = c.migrateMetaPartition(srcAddr, dstAddr, mp); err != nil {
+			log.LogWarnf("action[rebalanceReplica] migrate mp[%v] from [%v] to [%v] failed, err[%v]",
+				partitionID, srcAddr, dstAddr, err)
+			return "", false
+		}
+		return mp.volName, true
+	}
+	dp, err := c.getDataPartitionByID(partitionID)
+	if err != nil || !c.canRebalanceDataPartition(dp, dstAddr) {
+		return "", false
+	}
+	if err = c.migrateDataReplica(dp, srcAddr, dstAddr); err != nil {
+		log.LogWarnf("action[rebalanceReplica] migrate dp[%v] from [%v] to [%v] failed, err[%v]",
+			partitionID, srcAddr, dstAddr, err)
+		return "", false
+	}
+	return dp.VolName, true
+}
+
+// scheduleNodeSetRebalance moves replicas from the most loaded nodes of the nodeset to the least loaded ones,
+// until the nodeset is balanced or limit moves are scheduled.
+func (c *Cluster) scheduleNodeSetRebalance(set *rebalanceNodeSetLoads, limit int) {
+	loads := set.loads
+	for scheduled := 0; scheduled < limit; {
+		sort.Slice(loads, func(i, j int) bool { return loads[i].surplus() > loads[j].surplus() })
+		moved := false
+		for _, src := range loads {
+			if src.count <= int(math.Ceil(src.expected)) {
+				break
+			}
+			if moved = c.moveOneReplica(set, src); moved {
+				break
+			}
+		}
+		if !moved {
+			return
+		}
+		scheduled++
+	}
+}
+
+func (c *Cluster) moveOneReplica(set *rebalanceNodeSetLoads, src *rebalanceNodeLoad) bool {
+	for i := len(set.loads) - 1; i >= 0; i-- {
+		dst := set.loads[i]
+		if dst.surplus() >= 0 {
+			return false
+		}
+		if !dst.canAlloc {
+			continue
+		}
+		for idx, id := range src.partitions {
+			volName, ok := c.rebalanceReplica(set.partitionType, id, src.addr, dst.addr)
+			if !ok {
+				continue
+			}
+			log.LogInfof("action[moveOneReplica] zone[%v] nodeSet[%v] migrate %v partition[%v] from [%v] to [%v]",
+				set.zoneName, set.nodeSetID, set.partitionType, id, src.addr, dst.addr)
+			c.nodeSetRebalanceMgr.addMove(&proto.NodeSetRebalanceMove{
+				PartitionID:   id,
+				PartitionType: set.partitionType,
+				VolName:       volName,
+				ZoneName:      set.zoneName,
+				NodeSetID:     set.nodeSetID,
+				SrcAddr:       src.addr,
+				DstAddr:       dst.addr,
+				Status:        nodeSetRebalanceRunning,
+				ScheduleTime:  time.Now().Unix(),
+			})
+			src.partitions = append(src.partitions[:idx], src.partitions[idx+1:]...)
+			src.count--
+			dst.count++
+			return true
+		}
+	}
+	return false
+}
+
+// getNodeSetLoads returns loads of active data nodes and meta nodes of each nodeset
+func (c *Cluster) getNodeSetLoads() []*rebalanceNodeSetLoads {
+	dps := make(map[string][]uint64)
+	mps := make(map[string][]uint64)
+	for _, vol := range c.copyVols() {
+		for _, dp := range vol.dataPartitions.clonePartitions() {
+			dp.RLock()
+			for _, host := range dp.Hosts {
+				dps[host] = append(dps[host], dp.PartitionID)
+			}
+			dp.RUnlock()
+		}
+		for _, mp := range vol.cloneMetaPartitionMap() {
+			mp.RLock()
+			for _, host := range mp.Hosts {
+				mps[host] = append(mps[host], mp.PartitionID)
+			}
+			mp.RUnlock()
+		}
+	}
+
+	sets := make(map[string]*rebalanceNodeSetLoads)
+	addLoad := func(zoneName string, nodeSetID uint64, partitionType string, load *rebalanceNodeLoad) {
+		key := rebalanceNodeSetKey(zoneName, nodeSetID, partitionType)
+		set, ok := sets[key]
+		if !ok {
+			set = &rebalanceNodeSetLoads{zoneName: zoneName, nodeSetID: nodeSetID, partitionType: partitionType}
+			sets[key] = set
+		}
+		load.count = len(load.partitions)
+		set.loads = append(set.loads, load)
+	}
+	for _, zone := range c.t.getAllZones() {
+		zone.dataNodes.Range(func(addr, value interface{}) bool {
+			node := value.(*DataNode)
+			if node.isActive && !node.ToBeOffline {
+				addLoad(zone.name, node.NodeSetID, rebalanceDataPartition, &rebalanceNodeLoad{
+					addr:       node.Addr,
+					total:      node.Total,
+					canAlloc:   node.canAllocDp(),
+					partitions: dps[node.Addr],
+				})
+			}
+			return true
+		})
+		zone.metaNodes.Range(func(addr, value interface{}) bool {
+			node := value.(*MetaNode)
+			if node.IsActive && !node.ToBeOffline {
+				addLoad(zone.name, node.NodeSetID, rebalanceMetaPartition, &rebalanceNodeLoad{
+					addr:       node.Addr,
+					total:      node.Total,
+					canAlloc:   node.isWritable(),
+					partitions: mps[node.Addr],
+				})
+			}
+			return true
+		})
+	}
+
+	result := make([]*rebalanceNodeSetLoads, 0, len(sets))
+	for _, set := range sets {
+		result = append(result, set)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].key() < result[j].key() })
+	return result
+}
+
+// rebalanceNodeSets updates the rebalance progress of each nodeset, and schedules moves if the rebalance is
+// enabled, at most rate replicas of each type are migrating in each nodeset at the same time.
+func (c *Cluster) rebalanceNodeSets() {
+	c.checkRebalanceMoves()
+	rate := int(c.getNodeSetRebalanceRate())
+	for _, set := range c.getNodeSetLoads() {
+		pending := setExpectedLoads(set.loads)
+		if c.nodeSetRebalanceEnable {
+			if limit := rate - c.nodeSetRebalanceMgr.runningMoves(set.key()); limit > 0 && pending > 0 {
+				c.scheduleNodeSetRebalance(set, limit)
+				pending = setExpectedLoads(set.loads)
+			}
+		}
+		c.nodeSetRebalanceMgr.updateNodeSet(set, pending)
+	}
+}
+
+func (c *Cluster) getNodeSetRebalanceStatus() *proto.NodeSetRebalanceStatus {
+	status := &proto.NodeSetRebalanceStatus{
+		Enable: c.nodeSetRebalanceEnable,
+		Rate:   c.getNodeSetRebalanceRate(),
+	}
+	status.StartTime, status.NodeSets, status.Moves = c.nodeSetRebalanceMgr.getStatus()
+	return status
+}
+
+// scheduleToRebalanceNodeSets keeps the progress up to date even if the rebalance is disabled, so that
+// imbalance of nodesets could be checked before enabling it.
+func (c *Cluster) scheduleToRebalanceNodeSets() {
+	go func() {
+		for {
+			time.Sleep(nodeSetRebalanceInterval)
+			if c.partition != nil && c.partition.IsRaftLeader() {
+				c.rebalanceNodeSets()
+			}
+		}
+	}()
+}
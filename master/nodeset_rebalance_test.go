@@ -0,0 +1,143 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRebalanceLoad(addr string, total uint64, count int) *rebalanceNodeLoad {
+	return &rebalanceNodeLoad{addr: addr, total: total, count: count}
+}
+
+func TestSetExpectedLoads(t *testing.T) {
+	// a new node with the same space as the old ones is expected to hold a third of replicas
+	loads := []*rebalanceNodeLoad{
+		newTestRebalanceLoad("a", 100, 30),
+		newTestRebalanceLoad("b", 100, 30),
+		newTestRebalanceLoad("c", 100, 0),
+	}
+	require.Equal(t, 20, setExpectedLoads(loads))
+	require.Equal(t, float64(20), loads[2].expected)
+
+	// replicas are expected proportional to space of nodes
+	loads = []*rebalanceNodeLoad{
+		newTestRebalanceLoad("a", 100, 10),
+		newTestRebalanceLoad("b", 300, 30),
+	}
+	require.Zero(t, setExpectedLoads(loads))
+	require.Equal(t, float64(10), loads[0].expected)
+
+	// a node exceeding its expected count by less than 1 is balanced
+	loads = []*rebalanceNodeLoad{
+		newTestRebalanceLoad("a", 100, 2),
+		newTestRebalanceLoad("b", 100, 1),
+	}
+	require.Zero(t, setExpectedLoads(loads))
+}
+
+func TestNodeSetRebalanceManagerProgress(t *testing.T) {
+	mgr := newNodeSetRebalanceManager()
+	mgr.start()
+	newMove := func(partitionType string, id uint64, src string) *proto.NodeSetRebalanceMove {
+		return &proto.NodeSetRebalanceMove{
+			PartitionID: id, PartitionType: partitionType, ZoneName: testZone1, NodeSetID: 1, SrcAddr: src, DstAddr: "c",
+		}
+	}
+	done := newMove(rebalanceDataPartition, 1, "a")
+	running := newMove(rebalanceDataPartition, 2, "b")
+	// meta partition with the same id as a data partition is tracked in its own nodeset
+	meta := newMove(rebalanceMetaPartition, 1, "a")
+	mgr.addMove(done)
+	mgr.addMove(running)
+	mgr.addMove(meta)
+	require.True(t, mgr.isMoving(rebalanceDataPartition, 1))
+	require.True(t, mgr.isMoving(rebalanceMetaPartition, 1))
+	mgr.finishMove(done, nodeSetRebalanceDone)
+	require.False(t, mgr.isMoving(rebalanceDataPartition, 1))
+	require.True(t, mgr.isMoving(rebalanceMetaPartition, 1))
+	set := &rebalanceNodeSetLoads{
+		zoneName:      testZone1,
+		nodeSetID:     1,
+		partitionType: rebalanceDataPartition,
+		loads: []*rebalanceNodeLoad{
+			newTestRebalanceLoad("a", 100, 3),
+			newTestRebalanceLoad("b", 100, 4),
+			newTestRebalanceLoad("c", 100, 1),
+		},
+	}
+	require.Equal(t, 1, mgr.runningMoves(set.key()))
+	mgr.updateNodeSet(set, 2)
+
+	startTime, nodeSets, moves := mgr.getStatus()
+	require.NotZero(t, startTime)
+	require.Len(t, nodeSets, 2)
+	require.Equal(t, rebalanceDataPartition, nodeSets[0].PartitionType)
+	require.Equal(t, 3, nodeSets[0].NodeCount)
+	require.Equal(t, 1, nodeSets[0].MinCount)
+	require.Equal(t, 4, nodeSets[0].MaxCount)
+	require.EqualValues(t, 1, nodeSets[0].DoneMoves)
+	require.Equal(t, "25.00%", nodeSets[0].Progress)
+	require.Equal(t, rebalanceMetaPartition, nodeSets[1].PartitionType)
+	require.Equal(t, 1, nodeSets[1].RunningMoves)
+	require.Len(t, moves, 2)
+	require.EqualValues(t, 2, moves[0].PartitionID)
+	require.Equal(t, rebalanceMetaPartition, moves[1].PartitionType)
+}
+
+func TestParseRequestToSetNodeSetRebalance(t *testing.T) {
+	r := httptest.NewRequest("GET", "/nodeSet/rebalance/set?enable=true&rate=3", nil)
+	enable, rate, err := parseRequestToSetNodeSetRebalance(r)
+	require.NoError(t, err)
+	require.True(t, *enable)
+	require.EqualValues(t, 3, *rate)
+
+	r = httptest.NewRequest("GET", "/nodeSet/rebalance/set?rate=-1", nil)
+	_, _, err = parseRequestToSetNodeSetRebalance(r)
+	require.Error(t, err)
+
+	r = httptest.NewRequest("GET", "/nodeSet/rebalance/set", nil)
+	_, _, err = parseRequestToSetNodeSetRebalance(r)
+	require.Error(t, err)
+}
+
+func TestGetNodeSetLoads(t *testing.T) {
+	c := server.cluster
+	types := make(map[string]bool)
+	for _, set := range c.getNodeSetLoads() {
+		types[set.partitionType] = true
+		// nodes are grouped by nodeset, so that replicas are only moved within their nodeset
+		for _, load := range set.loads {
+			if set.partitionType == rebalanceMetaPartition {
+				node, err := c.metaNode(load.addr)
+				require.NoError(t, err)
+				require.Equal(t, set.nodeSetID, node.NodeSetID)
+				require.Equal(t, set.zoneName, node.ZoneName)
+			} else {
+				node, err := c.dataNode(load.addr)
+				require.NoError(t, err)
+				require.Equal(t, set.nodeSetID, node.NodeSetID)
+				require.Equal(t, set.zoneName, node.ZoneName)
+			}
+			require.Equal(t, len(load.partitions), load.count)
+		}
+	}
+	require.True(t, types[rebalanceDataPartition])
+	require.True(t, types[rebalanceMetaPartition])
+}
@@ -0,0 +1,523 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/exporter"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// RBACUri2ActionMap defines the admin action required by each api checked by RBAC, all actions are required
+// by the apis neither in the map nor in rbacUncheckedUris.
+var RBACUri2ActionMap = map[string]string{
+	proto.AdminCreateVol: proto.AdminActionCreateVolume,
+
+	proto.AdminDeleteVol: proto.AdminActionDeleteVolume,
+
+	proto.AdminUpdateVol:                  proto.AdminActionUpdateVolume,
+	proto.AdminVolShrink:                  proto.AdminActionUpdateVolume,
+	proto.AdminVolExpand:                  proto.AdminActionUpdateVolume,
+	proto.AdminVolSetQosLimit:             proto.AdminActionUpdateVolume,
+	proto.AdminVolSetAutoScale:            proto.AdminActionUpdateVolume,
+	proto.AdminVolSetPlacement:            proto.AdminActionUpdateVolume,
+	proto.AdminVolSetTrashInterval:        proto.AdminActionUpdateVolume,
+	proto.AdminVolEnableAuditLog:          proto.AdminActionUpdateVolume,
+	proto.AdminVolForbidden:               proto.AdminActionUpdateVolume,
+	proto.AdminSetVerStrategy:             proto.AdminActionUpdateVolume,
+	proto.AdminCreateVersion:              proto.AdminActionUpdateVolume,
+	proto.AdminDelVersion:                 proto.AdminActionUpdateVolume,
+	proto.AdminSplitMetaPartition:         proto.AdminActionUpdateVolume,
+	proto.AdminCreateDataPartition:        proto.AdminActionUpdateVolume,
+	proto.AdminCreateMetaPartition:        proto.AdminActionUpdateVolume,
+	proto.AdminCreatePreLoadDataPartition: proto.AdminActionUpdateVolume,
+	proto.AdminPutDataPartitions:          proto.AdminActionUpdateVolume,
+	proto.AdminACL:                        proto.AdminActionUpdateVolume,
+	proto.AdminUid:                        proto.AdminActionUpdateVolume,
+	proto.QuotaCreate:                     proto.AdminActionUpdateVolume,
+	proto.QuotaUpdate:                     proto.AdminActionUpdateVolume,
+	proto.QuotaDelete:                     proto.AdminActionUpdateVolume,
+	proto.SetBucketLifecycle:              proto.AdminActionUpdateVolume,
+	proto.DeleteBucketLifecycle:           proto.AdminActionUpdateVolume,
+
+	proto.AdminDecommissionDataPartition:            proto.AdminActionDecommission,
+	proto.AdminDecommissionMetaPartition:            proto.AdminActionDecommission,
+	proto.AdminResetDataPartitionDecommissionStatus: proto.AdminActionDecommission,
+	proto.AdminAddDataReplica:                       proto.AdminActionDecommission,
+	proto.AdminDeleteDataReplica:                    proto.AdminActionDecommission,
+	proto.AdminAddMetaReplica:                       proto.AdminActionDecommission,
+	proto.AdminDeleteMetaReplica:                    proto.AdminActionDecommission,
+	proto.AdminLoadDataPartition:                    proto.AdminActionDecommission,
+	proto.AdminLoadMetaPartition:                    proto.AdminActionDecommission,
+	proto.AdminDataPartitionChangeLeader:            proto.AdminActionDecommission,
+	proto.AdminChangeMetaPartitionLeader:            proto.AdminActionDecommission,
+	proto.AdminBalanceMetaPartitionLeader:           proto.AdminActionDecommission,
+	proto.AdminSetDpDiscard:                         proto.AdminActionDecommission,
+	proto.AdminSetDpRdOnly:                          proto.AdminActionDecommission,
+	proto.AdminSetNodeRdOnly:                        proto.AdminActionDecommission,
+	proto.AdminEnterNodeMaintenance:                 proto.AdminActionDecommission,
+	proto.AdminExitNodeMaintenance:                  proto.AdminActionDecommission,
+	proto.DecommissionDataNode:                      proto.AdminActionDecommission,
+	proto.CancelDecommissionDataNode:                proto.AdminActionDecommission,
+	proto.PauseDecommissionDataNode:                 proto.AdminActionDecommission,
+	proto.ResumeDecommissionDataNode:                proto.AdminActionDecommission,
+	proto.DecommissionMetaNode:                      proto.AdminActionDecommission,
+	proto.DecommissionDisk:                          proto.AdminActionDecommission,
+	proto.PauseDecommissionDisk:                     proto.AdminActionDecommission,
+	proto.ResumeDecommissionDisk:                    proto.AdminActionDecommission,
+	proto.RecommissionDisk:                          proto.AdminActionDecommission,
+	proto.MarkDecoDiskFixed:                         proto.AdminActionDecommission,
+	proto.RestoreStoppedAutoDecommissionDisk:        proto.AdminActionDecommission,
+	proto.MigrateDataNode:                           proto.AdminActionDecommission,
+	proto.MigrateMetaNode:                           proto.AdminActionDecommission,
+
+	proto.AdminClusterFreeze:                 proto.AdminActionUpdateConfig,
+	proto.AdminClusterForbidMpDecommission:   proto.AdminActionUpdateConfig,
+	proto.AdminSetCheckDataReplicasEnable:    proto.AdminActionUpdateConfig,
+	proto.AdminEnableAutoDecommissionDisk:    proto.AdminActionUpdateConfig,
+	proto.AdminSetClusterInfo:                proto.AdminActionUpdateConfig,
+	proto.AdminSetNodeInfo:                   proto.AdminActionUpdateConfig,
+	proto.AdminSetConfig:                     proto.AdminActionUpdateConfig,
+	proto.AdminSetMetaNodeThreshold:          proto.AdminActionUpdateConfig,
+	proto.AdminSetReplicaRepair:              proto.AdminActionUpdateConfig,
+	proto.AdminSetApiQpsLimit:                proto.AdminActionUpdateConfig,
+	proto.AdminRemoveApiQpsLimit:             proto.AdminActionUpdateConfig,
+	proto.AdminSetFileStats:                  proto.AdminActionUpdateConfig,
+	proto.AdminSetMasterVolDeletionDelayTime: proto.AdminActionUpdateConfig,
+	proto.AdminGenerateClusterUuid:           proto.AdminActionUpdateConfig,
+	proto.AdminSetClusterUuidEnable:          proto.AdminActionUpdateConfig,
+	proto.AdminUpdateDecommissionLimit:       proto.AdminActionUpdateConfig,
+	proto.AdminUpdateDecommissionDiskFactor:  proto.AdminActionUpdateConfig,
+	proto.AdminUpdateDomainDataUseRatio:      proto.AdminActionUpdateConfig,
+	proto.AdminUpdateZoneExcludeRatio:        proto.AdminActionUpdateConfig,
+	proto.AdminUpdateNodeSetCapcity:          proto.AdminActionUpdateConfig,
+	proto.AdminUpdateNodeSetId:               proto.AdminActionUpdateConfig,
+	proto.AdminUpdateNodeSetNodeSelector:     proto.AdminActionUpdateConfig,
+	proto.AdminUpdateDataNode:                proto.AdminActionUpdateConfig,
+	proto.AdminUpdateMetaNode:                proto.AdminActionUpdateConfig,
+	proto.AdminOpFollowerPartitionsRead:      proto.AdminActionUpdateConfig,
+	proto.AdminChangeMasterLeader:            proto.AdminActionUpdateConfig,
+	proto.AdminLcNode:                        proto.AdminActionUpdateConfig,
+	proto.AddRaftNode:                        proto.AdminActionUpdateConfig,
+	proto.RemoveRaftNode:                     proto.AdminActionUpdateConfig,
+	proto.UpdateZone:                         proto.AdminActionUpdateConfig,
+	proto.SetZoneReservation:                 proto.AdminActionUpdateConfig,
+	proto.UpdateNodeSet:                      proto.AdminActionUpdateConfig,
+	proto.SetNodeSetRebalance:                proto.AdminActionUpdateConfig,
+	proto.QosUpdate:                          proto.AdminActionUpdateConfig,
+	proto.QosUpdateClientParam:               proto.AdminActionUpdateConfig,
+	proto.QosUpdateMagnify:                   proto.AdminActionUpdateConfig,
+	proto.QosUpdateMasterLimit:               proto.AdminActionUpdateConfig,
+	proto.QosUpdateZoneLimit:                 proto.AdminActionUpdateConfig,
+	proto.S3QoSSet:                           proto.AdminActionUpdateConfig,
+	proto.S3QoSDelete:                        proto.AdminActionUpdateConfig,
+
+	proto.UserCreate:          proto.AdminActionManageUser,
+	proto.UserDelete:          proto.AdminActionManageUser,
+	proto.UserUpdate:          proto.AdminActionManageUser,
+	proto.UserUpdatePolicy:    proto.AdminActionManageUser,
+	proto.UserRemovePolicy:    proto.AdminActionManageUser,
+	proto.UserDeleteVolPolicy: proto.AdminActionManageUser,
+	proto.UserTransferVol:     proto.AdminActionManageUser,
+	proto.TenantCreate:        proto.AdminActionManageUser,
+	proto.TenantUpdate:        proto.AdminActionManageUser,
+	proto.TenantDelete:        proto.AdminActionManageUser,
+	proto.TenantAddUser:       proto.AdminActionManageUser,
+	proto.TenantRemoveUser:    proto.AdminActionManageUser,
+	proto.TenantAddVol:        proto.AdminActionManageUser,
+	proto.TenantRemoveVol:     proto.AdminActionManageUser,
+
+	proto.RoleCreate: proto.AdminActionManageRole,
+	proto.RoleDelete: proto.AdminActionManageRole,
+	proto.RoleUpdate: proto.AdminActionManageRole,
+	proto.RoleGrant:  proto.AdminActionManageRole,
+	proto.RoleRevoke: proto.AdminActionManageRole,
+}
+
+// rbacUncheckedUris are the read only apis and the apis called by the nodes, which are not checked by RBAC.
+var rbacUncheckedUris = map[string]bool{
+	exporter.PromHandlerPattern: true,
+
+	proto.AddDataNode:             true,
+	proto.AddMetaNode:             true,
+	proto.AddLcNode:               true,
+	proto.GetDataNodeTaskResponse: true,
+	proto.GetMetaNodeTaskResponse: true,
+	proto.GetLcNodeTaskResponse:   true,
+	proto.QosUpload:               true,
+
+	proto.ClientVol:                true,
+	proto.ClientVolStat:            true,
+	proto.ClientDataPartitions:     true,
+	proto.ClientDiskDataPartitions: true,
+	proto.ClientMetaPartition:      true,
+	proto.ClientMetaPartitions:     true,
+	proto.ClientTopologyEvents:     true,
+
+	proto.AdminGetMasterApiList:     true,
+	proto.AdminGetIP:                true,
+	proto.AdminGetCluster:           true,
+	proto.AdminGetClusterEvents:     true,
+	proto.AdminGetClusterUuid:       true,
+	proto.AdminGetClusterValue:      true,
+	proto.AdminGetConfig:            true,
+	proto.AdminGetNodeInfo:          true,
+	proto.AdminGetIsDomainOn:        true,
+	proto.AdminGetAllNodeSetGrpInfo: true,
+	proto.AdminGetNodeSetGrpInfo:    true,
+	proto.AdminGetMonitorPushAddr:   true,
+	proto.AdminGetApiQpsLimit:       true,
+	proto.AdminGetFileStats:         true,
+	proto.AdminGetInvalidNodes:      true,
+	proto.AdminClusterStat:          true,
+	proto.AdminClusterStatHistory:   true,
+	proto.RaftStatus:                true,
+	proto.GetTopologyView:           true,
+	proto.GetAllZones:               true,
+	proto.GetZoneReservation:        true,
+	proto.GetAllNodeSets:            true,
+	proto.GetNodeSet:                true,
+	proto.GetNodeSetRebalance:       true,
+
+	proto.AdminGetVol:            true,
+	proto.AdminGetVolVer:         true,
+	proto.AdminListVols:          true,
+	proto.AdminVolGetQosLimit:    true,
+	proto.AdminVolGetAutoScale:   true,
+	proto.AdminVolGetPlacement:   true,
+	proto.AdminGetVersionInfo:    true,
+	proto.AdminGetAllVersionInfo: true,
+	proto.QuotaGet:               true,
+	proto.QuotaList:              true,
+	proto.QuotaListAll:           true,
+	proto.GetBucketLifecycle:     true,
+	proto.S3QoSGet:               true,
+
+	proto.AdminGetDataPartition:                     true,
+	proto.AdminDiagnoseDataPartition:                true,
+	proto.AdminDiagnoseMetaPartition:                true,
+	proto.AdminGetDiscardDp:                         true,
+	proto.AdminGetReplicaRepairBacklog:              true,
+	proto.AdminQueryDataPartitionDecommissionStatus: true,
+
+	proto.GetDataNode:                        true,
+	proto.GetMetaNode:                        true,
+	proto.QueryDataNodeDecoProgress:          true,
+	proto.QueryDataNodeDecoDetail:            true,
+	proto.QueryDataNodeDecoFailedDps:         true,
+	proto.QueryDisableDisk:                   true,
+	proto.QueryDisks:                         true,
+	proto.QueryDiskDetail:                    true,
+	proto.QueryBadDisks:                      true,
+	proto.QueryPredictedFailureDisks:         true,
+	proto.QueryAllDecommissionDisk:           true,
+	proto.QueryDiskDecoProgress:              true,
+	proto.QueryDiskDecoDetail:                true,
+	proto.QueryDecommissionDiskDecoFailedDps: true,
+	proto.AdminQueryDecommissionLimit:        true,
+	proto.AdminQueryDecommissionDiskLimit:    true,
+	proto.AdminQueryDecommissionToken:        true,
+	proto.AdminQueryAutoDecommissionDisk:     true,
+
+	proto.QosGetStatus:           true,
+	proto.QosGetClientsLimitInfo: true,
+	proto.QosGetZoneLimitInfo:    true,
+
+	proto.UserGetInfo:   true,
+	proto.UserGetAKInfo: true,
+	proto.UserList:      true,
+	proto.UsersOfVol:    true,
+	proto.TenantGet:     true,
+	proto.TenantList:    true,
+	proto.RoleGet:       true,
+	proto.RoleList:      true,
+}
+
+// rbacAction returns the action required by the api, false if the api is not checked.
+func rbacAction(uri string) (action string, checked bool) {
+	if action, checked = RBACUri2ActionMap[uri]; checked {
+		return
+	}
+	if rbacUncheckedUris[uri] {
+		return "", false
+	}
+	return proto.AdminActionAll, true
+}
+
+// roleManager holds roles in memory, all changes are persisted by raft before applied to it
+type roleManager struct {
+	sync.RWMutex
+	roles map[string]*proto.RoleInfo
+}
+
+func newRoleManager() *roleManager {
+	return &roleManager{roles: make(map[string]*proto.RoleInfo)}
+}
+
+func (mgr *roleManager) clear() {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.roles = make(map[string]*proto.RoleInfo)
+}
+
+func (mgr *roleManager) put(role *proto.RoleInfo) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.roles[role.Name] = role
+}
+
+func copyRole(role *proto.RoleInfo) *proto.RoleInfo {
+	dup := *role
+	dup.Actions = append([]string{}, role.Actions...)
+	dup.Users = append([]string{}, role.Users...)
+	return &dup
+}
+
+func (mgr *roleManager) getRole(name string) (*proto.RoleInfo, error) {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	role, ok := mgr.roles[name]
+	if !ok {
+		return nil, proto.ErrRoleNotExists
+	}
+	return copyRole(role), nil
+}
+
+func (mgr *roleManager) listRoles() []*proto.RoleInfo {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	roles := make([]*proto.RoleInfo, 0, len(mgr.roles))
+	for _, role := range mgr.roles {
+		roles = append(roles, copyRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+// userHasAction returns true if any role granted to the user allows the action
+func (mgr *roleManager) userHasAction(userID, action string) bool {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	for _, role := range mgr.roles {
+		if contains(role.Users, userID) && role.HasAction(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// getUserRoles returns names of roles granted to the user
+func (mgr *roleManager) getUserRoles(userID string) (names []string) {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	names = make([]string, 0)
+	for name, role := range mgr.roles {
+		if contains(role.Users, userID) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+func checkAdminActions(actions []string) error {
+	if len(actions) == 0 {
+		return fmt.Errorf("no action is specified")
+	}
+	for _, action := range actions {
+		if !proto.IsValidAdminAction(action) {
+			return fmt.Errorf("invalid action [%v], valid actions are %v", action, proto.AdminActions)
+		}
+	}
+	return nil
+}
+
+func (c *Cluster) syncPutRole(opType uint32, role *proto.RoleInfo) (err error) {
+	metadata := new(RaftCmd)
+	metadata.Op = opType
+	metadata.K = rolePrefix + role.Name
+	if metadata.V, err = json.Marshal(role); err != nil {
+		return
+	}
+	return c.submit(metadata)
+}
+
+func (c *Cluster) syncAddRole(role *proto.RoleInfo) error {
+	return c.syncPutRole(opSyncAddRole, role)
+}
+
+func (c *Cluster) syncUpdateRole(role *proto.RoleInfo) error {
+	return c.syncPutRole(opSyncUpdateRole, role)
+}
+
+func (c *Cluster) syncDeleteRole(role *proto.RoleInfo) error {
+	return c.syncPutRole(opSyncDeleteRole, role)
+}
+
+func (c *Cluster) loadRoles() (err error) {
+	result, err := c.fsm.store.SeekForPrefix([]byte(rolePrefix))
+	if err != nil {
+		return fmt.Errorf("action[loadRoles],err:%v", err.Error())
+	}
+	for _, value := range result {
+		role := &proto.RoleInfo{}
+		if err = json.Unmarshal(value, role); err != nil {
+			return fmt.Errorf("action[loadRoles],value:%v,unmarshal err:%v", string(value), err)
+		}
+		c.roleMgr.put(role)
+		log.LogInfof("action[loadRoles],role[%v]", role.Name)
+	}
+	return
+}
+
+func (c *Cluster) createRole(role *proto.RoleInfo) (err error) {
+	if err = checkAdminActions(role.Actions); err != nil {
+		return
+	}
+	c.roleMgr.Lock()
+	defer c.roleMgr.Unlock()
+	if _, ok := c.roleMgr.roles[role.Name]; ok {
+		return proto.ErrDuplicateRole
+	}
+	role.CreateTime = time.Unix(time.Now().Unix(), 0).Format(proto.TimeFormat)
+	if err = c.syncAddRole(role); err != nil {
+		return
+	}
+	c.roleMgr.roles[role.Name] = role
+	log.LogInfof("action[createRole] role[%v] created, actions%v", role.Name, role.Actions)
+	return
+}
+
+// updateRole applies update to a copy of the role and persists the result
+func (c *Cluster) updateRole(name string, update func(role *proto.RoleInfo) error) (role *proto.RoleInfo, err error) {
+	c.roleMgr.Lock()
+	defer c.roleMgr.Unlock()
+	old, ok := c.roleMgr.roles[name]
+	if !ok {
+		return nil, proto.ErrRoleNotExists
+	}
+	role = copyRole(old)
+	if err = update(role); err != nil {
+		return nil, err
+	}
+	if err = c.syncUpdateRole(role); err != nil {
+		return nil, err
+	}
+	c.roleMgr.roles[name] = role
+	return copyRole(role), nil
+}
+
+func (c *Cluster) deleteRole(name string) (err error) {
+	c.roleMgr.Lock()
+	defer c.roleMgr.Unlock()
+	role, ok := c.roleMgr.roles[name]
+	if !ok {
+		return proto.ErrRoleNotExists
+	}
+	if err = c.syncDeleteRole(role); err != nil {
+		return
+	}
+	delete(c.roleMgr.roles, name)
+	log.LogInfof("action[deleteRole] role[%v] deleted", name)
+	return
+}
+
+func (c *Cluster) grantRole(name, userID string) (*proto.RoleInfo, error) {
+	return c.updateRole(name, func(role *proto.RoleInfo) error {
+		if contains(role.Users, userID) {
+			return fmt.Errorf("role[%v] is already granted to user[%v]", name, userID)
+		}
+		role.Users = append(role.Users, userID)
+		return nil
+	})
+}
+
+func (c *Cluster) revokeRole(name, userID string) (*proto.RoleInfo, error) {
+	return c.updateRole(name, func(role *proto.RoleInfo) error {
+		var ok bool
+		if role.Users, ok = removeString(role.Users, userID); !ok {
+			return fmt.Errorf("role[%v] is not granted to user[%v]", name, userID)
+		}
+		return nil
+	})
+}
+
+// revokeUserRoles revokes all roles of a deleted user
+func (c *Cluster) revokeUserRoles(userID string) {
+	for _, name := range c.roleMgr.getUserRoles(userID) {
+		if _, err := c.revokeRole(name, userID); err != nil {
+			log.LogWarnf("action[revokeUserRoles] revoke role[%v] of user[%v] failed, err[%v]", name, userID, err)
+		}
+	}
+}
+
+// checkRBACSignature checks the request is signed by the secret key of the user in time.
+func checkRBACSignature(r *http.Request, secretKey string) (err error) {
+	date := r.Header.Get(proto.RBACDateHeader)
+	ts, err := strconv.ParseInt(date, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%v: invalid header [%v]", proto.ErrNoPermission, proto.RBACDateHeader)
+	}
+	if elapsed := time.Since(time.Unix(ts, 0)); elapsed > proto.RBACSignatureExpiration || elapsed < -proto.RBACSignatureExpiration {
+		return fmt.Errorf("%v: signature is expired", proto.ErrNoPermission)
+	}
+	var body []byte
+	if r.Body != nil {
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	expected := proto.RBACSignature(secretKey, r.Method, r.URL.Path, r.URL.RawQuery, date, body)
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(proto.RBACSignatureHeader))) {
+		return fmt.Errorf("%v: signature not match", proto.ErrNoPermission)
+	}
+	return nil
+}
+
+// checkAdminAction checks the user signing the request is allowed to do the action, root and admin users
+// are allowed to do all actions.
+func (m *Server) checkAdminAction(r *http.Request, action string) (err error) {
+	accessKey := r.Header.Get(proto.RBACAccessKeyHeader)
+	if accessKey == "" {
+		return fmt.Errorf("%v: header [%v] not found", proto.ErrNoPermission, proto.RBACAccessKeyHeader)
+	}
+	userInfo, err := m.user.getKeyInfo(accessKey)
+	if err != nil {
+		return
+	}
+	if err = checkRBACSignature(r, userInfo.SecretKey); err != nil {
+		return
+	}
+	if userInfo.UserType == proto.UserTypeRoot || userInfo.UserType == proto.UserTypeAdmin {
+		return nil
+	}
+	if !m.cluster.roleMgr.userHasAction(userInfo.UserID, action) {
+		return fmt.Errorf("%v: user[%v] is not allowed to %v", proto.ErrNoPermission, userInfo.UserID, action)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequestToCreateRole(t *testing.T) {
+	r := httptest.NewRequest("GET", "/role/create?role=r1&actions=createVolume,%20deleteVolume,", nil)
+	role, err := parseRequestToCreateRole(r)
+	require.NoError(t, err)
+	require.Equal(t, "r1", role.Name)
+	require.Equal(t, []string{proto.AdminActionCreateVolume, proto.AdminActionDeleteVolume}, role.Actions)
+	require.NoError(t, checkAdminActions(role.Actions))
+
+	require.Error(t, checkAdminActions(nil))
+	require.Error(t, checkAdminActions([]string{"dropCluster"}))
+
+	r = httptest.NewRequest("GET", "/role/create?actions=createVolume", nil)
+	_, err = parseRequestToCreateRole(r)
+	require.Error(t, err)
+}
+
+func TestRBACUri2ActionMap(t *testing.T) {
+	for uri, action := range RBACUri2ActionMap {
+		require.True(t, proto.IsValidAdminAction(action), uri)
+		require.False(t, rbacUncheckedUris[uri], uri)
+	}
+
+	// every api is either checked by its action or left unchecked explicitly, the graphql apis require all actions
+	router := mux.NewRouter()
+	server.registerAPIRoutes(router)
+	graphql := map[string]bool{proto.AdminClusterAPI: true, proto.AdminUserAPI: true, proto.AdminVolumeAPI: true}
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		uri, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		_, checked := RBACUri2ActionMap[uri]
+		require.True(t, checked || rbacUncheckedUris[uri] || graphql[uri], uri)
+		return nil
+	})
+	require.NoError(t, err)
+
+	action, checked := rbacAction("/unknown/api")
+	require.True(t, checked)
+	require.Equal(t, proto.AdminActionAll, action)
+	_, checked = rbacAction(proto.AdminGetVol)
+	require.False(t, checked)
+}
+
+func TestRoleGrantAndRevoke(t *testing.T) {
+	c := server.cluster
+	name := "volumeAdmin"
+	userID := "rbacUser"
+	require.NoError(t, c.createRole(&proto.RoleInfo{Name: name, Actions: []string{proto.AdminActionCreateVolume}}))
+	defer c.deleteRole(name)
+	require.Equal(t, proto.ErrDuplicateRole, c.createRole(&proto.RoleInfo{Name: name, Actions: []string{proto.AdminActionAll}}))
+
+	require.False(t, c.roleMgr.userHasAction(userID, proto.AdminActionCreateVolume))
+	_, err := c.grantRole(name, userID)
+	require.NoError(t, err)
+	_, err = c.grantRole(name, userID)
+	require.Error(t, err)
+	require.True(t, c.roleMgr.userHasAction(userID, proto.AdminActionCreateVolume))
+	require.False(t, c.roleMgr.userHasAction(userID, proto.AdminActionDeleteVolume))
+	require.Equal(t, []string{name}, c.roleMgr.getUserRoles(userID))
+
+	c.revokeUserRoles(userID)
+	require.False(t, c.roleMgr.userHasAction(userID, proto.AdminActionCreateVolume))
+	_, err = c.revokeRole(name, userID)
+	require.Error(t, err)
+}
+
+func TestCheckAdminAction(t *testing.T) {
+	userID := "rbacChecked"
+	userInfo, err := server.user.createKey(&proto.UserCreateParam{ID: userID, Password: "rbacPassword", Type: proto.UserTypeNormal})
+	require.NoError(t, err)
+	defer server.user.deleteKey(userID)
+
+	uri := proto.AdminDeleteVol + "?name=vol&authKey=key"
+	sign := func(secretKey, uri string, ts int64) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, uri, strings.NewReader("body"))
+		date := strconv.FormatInt(ts, 10)
+		r.Header.Set(proto.RBACAccessKeyHeader, userInfo.AccessKey)
+		r.Header.Set(proto.RBACDateHeader, date)
+		r.Header.Set(proto.RBACSignatureHeader, proto.RBACSignature(secretKey, r.Method, r.URL.Path, r.URL.RawQuery, date, []byte("body")))
+		return r
+	}
+	now := time.Now().Unix()
+	require.Error(t, server.checkAdminAction(httptest.NewRequest(http.MethodPost, uri, nil), proto.AdminActionDeleteVolume))
+	require.Error(t, server.checkAdminAction(sign("wrong", uri, now), proto.AdminActionDeleteVolume))
+	require.Error(t, server.checkAdminAction(sign(userInfo.SecretKey, uri, now-int64(proto.RBACSignatureExpiration/time.Second)-60), proto.AdminActionDeleteVolume))
+	// the params are signed
+	r := sign(userInfo.SecretKey, uri, now)
+	r.URL.RawQuery = "name=other&authKey=key"
+	require.Error(t, server.checkAdminAction(r, proto.AdminActionDeleteVolume))
+	require.Error(t, server.checkAdminAction(sign(userInfo.SecretKey, uri, now), proto.AdminActionDeleteVolume))
+
+	name := "volumeDeleter"
+	require.NoError(t, server.cluster.createRole(&proto.RoleInfo{Name: name, Actions: []string{proto.AdminActionDeleteVolume}}))
+	defer server.cluster.deleteRole(name)
+	_, err = server.cluster.grantRole(name, userID)
+	require.NoError(t, err)
+	r = sign(userInfo.SecretKey, uri, now)
+	require.NoError(t, server.checkAdminAction(r, proto.AdminActionDeleteVolume))
+	// the body is kept for the handler
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	require.Equal(t, "body", string(body))
+	require.Error(t, server.checkAdminAction(sign(userInfo.SecretKey, uri, now), proto.AdminActionAll))
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	replicaRepairReasonMissing = "missing"
+	replicaRepairReasonCorrupt = "corrupt"
+
+	replicaRepairPending   = "pending"
+	replicaRepairRepairing = "repairing"
+	replicaRepairFailed    = "failed"
+
+	defaultReplicaRepairLimitPerZone = 10
+	replicaRepairInterval            = time.Minute
+)
+
+// replicaRepairManager holds the backlog of bad replicas found by the repair planner
+type replicaRepairManager struct {
+	sync.RWMutex
+	tasks map[string]*proto.ReplicaRepairTask
+}
+
+func newReplicaRepairManager() *replicaRepairManager {
+	return &replicaRepairManager{tasks: make(map[string]*proto.ReplicaRepairTask)}
+}
+
+func replicaRepairTaskKey(task *proto.ReplicaRepairTask) string {
+	return fmt.Sprintf("%v_%v", task.PartitionID, task.Addr)
+}
+
+// update replaces the backlog by the detected tasks, tasks already in the backlog keep their states
+func (mgr *replicaRepairManager) update(detected []*proto.ReplicaRepairTask) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	tasks := make(map[string]*proto.ReplicaRepairTask, len(detected))
+	for _, task := range detected {
+		key := replicaRepairTaskKey(task)
+		if old, ok := mgr.tasks[key]; ok {
+			task = old
+		}
+		tasks[key] = task
+	}
+	mgr.tasks = tasks
+}
+
+func (mgr *replicaRepairManager) setTaskStatus(task *proto.ReplicaRepairTask, status string, err error) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	task.Status = status
+	task.ErrMsg = ""
+	if err != nil {
+		task.ErrMsg = err.Error()
+	}
+	if status == replicaRepairRepairing && task.ScheduleTime == 0 {
+		task.ScheduleTime = time.Now().Unix()
+	}
+}
+
+func sortReplicaRepairTasks(tasks []*proto.ReplicaRepairTask) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].CreateTime != tasks[j].CreateTime {
+			return tasks[i].CreateTime < tasks[j].CreateTime
+		}
+		return tasks[i].PartitionID < tasks[j].PartitionID
+	})
+}
+
+// getTasks returns tasks in the backlog ordered by the time they were found, only states of them
+// could be changed, by setTaskStatus.
+func (mgr *replicaRepairManager) getTasks() []*proto.ReplicaRepairTask {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	tasks := make([]*proto.ReplicaRepairTask, 0, len(mgr.tasks))
+	for _, task := range mgr.tasks {
+		tasks = append(tasks, task)
+	}
+	sortReplicaRepairTasks(tasks)
+	return tasks
+}
+
+// listTasks returns copies of tasks ordered by the time they were found
+func (mgr *replicaRepairManager) listTasks() []*proto.ReplicaRepairTask {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	tasks := make([]*proto.ReplicaRepairTask, 0, len(mgr.tasks))
+	for _, task := range mgr.tasks {
+		dup := *task
+		tasks = append(tasks, &dup)
+	}
+	sortReplicaRepairTasks(tasks)
+	return tasks
+}
+
+func (mgr *replicaRepairManager) clear() {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.tasks = make(map[string]*proto.ReplicaRepairTask)
+}
+
+func (c *Cluster) getReplicaRepairLimitPerZone() uint64 {
+	if c.replicaRepairLimitPerZone == 0 {
+		return defaultReplicaRepairLimitPerZone
+	}
+	return c.replicaRepairLimitPerZone
+}
+
+func (c *Cluster) getDataNodeZone(addr string) string {
+	node, err := c.dataNode(addr)
+	if err != nil {
+		return ""
+	}
+	return node.ZoneName
+}
+
+// detectBadReplicas finds replicas missing from data partitions and replicas reported unavailable by
+// active data nodes, which means the disk or the data of the replica is broken.
+func (c *Cluster) detectBadReplicas() (tasks []*proto.ReplicaRepairTask) {
+	now := time.Now().Unix()
+	tasks = make([]*proto.ReplicaRepairTask, 0)
+	for _, vol := range c.copyVols() {
+		if vol.Status == proto.VolStatusMarkDelete {
+			continue
+		}
+		for _, dp := range vol.dataPartitions.clonePartitions() {
+			if dp.IsDiscard || !proto.IsNormalDp(dp.PartitionType) {
+				continue
+			}
+			dp.RLock()
+			if dp.ReplicaNum > uint8(len(dp.Hosts)) && len(dp.Hosts) == len(dp.Replicas) && len(dp.Hosts) > 0 {
+				tasks = append(tasks, &proto.ReplicaRepairTask{
+					PartitionID: dp.PartitionID,
+					VolName:     dp.VolName,
+					ZoneName:    c.getDataNodeZone(dp.Hosts[0]),
+					Reason:      replicaRepairReasonMissing,
+					Status:      replicaRepairPending,
+					CreateTime:  now,
+				})
+			}
+			for _, replica := range dp.Replicas {
+				if replica.Status != proto.Unavailable {
+					continue
+				}
+				if node, err := c.dataNode(replica.Addr); err != nil || !node.isActive {
+					continue
+				}
+				tasks = append(tasks, &proto.ReplicaRepairTask{
+					PartitionID: dp.PartitionID,
+					VolName:     dp.VolName,
+					Addr:        replica.Addr,
+					ZoneName:    c.getDataNodeZone(replica.Addr),
+					Reason:      replicaRepairReasonCorrupt,
+					Status:      replicaRepairPending,
+					CreateTime:  now,
+				})
+			}
+			dp.RUnlock()
+		}
+	}
+	return
+}
+
+// repairCorruptReplica migrates the corrupt replica to another data node by decommission
+func (c *Cluster) repairCorruptReplica(dp *DataPartition, addr string) (err error) {
+	return c.migrateDataReplica(dp, addr, "")
+}
+
+func (c *Cluster) repairReplica(dp *DataPartition, task *proto.ReplicaRepairTask) (err error) {
+	if task.Reason == replicaRepairReasonCorrupt {
+		return c.repairCorruptReplica(dp, task.Addr)
+	}
+	success, err := c.autoAddDataReplica(dp)
+	if err == nil && !success {
+		err = fmt.Errorf("no data node to add replica")
+	}
+	return
+}
+
+// scheduleReplicaRepair schedules tasks of the backlog in order, at most limitPerZone tasks of each zone
+// are scheduled in a round. Tasks of partitions under decommission are not scheduled again.
+func (c *Cluster) scheduleReplicaRepair() {
+	limit := c.getReplicaRepairLimitPerZone()
+	scheduled := make(map[string]uint64)
+	for _, task := range c.replicaRepairMgr.getTasks() {
+		dp, err := c.getDataPartitionByID(task.PartitionID)
+		if err != nil {
+			continue
+		}
+		if dp.IsDoingDecommission() {
+			c.replicaRepairMgr.setTaskStatus(task, replicaRepairRepairing, nil)
+			continue
+		}
+		if scheduled[task.ZoneName] >= limit {
+			continue
+		}
+		scheduled[task.ZoneName]++
+		if err = c.repairReplica(dp, task); err != nil {
+			log.LogWarnf("action[scheduleReplicaRepair] repair dp[%v] replica[%v] reason[%v] failed, err[%v]",
+				task.PartitionID, task.Addr, task.Reason, err)
+			c.replicaRepairMgr.setTaskStatus(task, replicaRepairFailed, err)
+			continue
+		}
+		log.LogInfof("action[scheduleReplicaRepair] repair dp[%v] replica[%v] reason[%v] zone[%v] scheduled",
+			task.PartitionID, task.Addr, task.Reason, task.ZoneName)
+		c.replicaRepairMgr.setTaskStatus(task, replicaRepairRepairing, nil)
+	}
+}
+
+func (c *Cluster) planReplicaRepair() {
+	c.replicaRepairMgr.update(c.detectBadReplicas())
+	if c.replicaRepairEnable {
+		c.scheduleReplicaRepair()
+	}
+}
+
+func (c *Cluster) getReplicaRepairBacklog() *proto.ReplicaRepairBacklog {
+	return &proto.ReplicaRepairBacklog{
+		Enable:       c.replicaRepairEnable,
+		LimitPerZone: c.getReplicaRepairLimitPerZone(),
+		Tasks:        c.replicaRepairMgr.listTasks(),
+	}
+}
+
+// scheduleToRepairReplicas keeps the backlog up to date even if the repair is disabled, so that the bad
+// replicas could be checked before enabling it.
+func (c *Cluster) scheduleToRepairReplicas() {
+	go func() {
+		for {
+			time.Sleep(replicaRepairInterval)
+			if c.partition != nil && c.partition.IsRaftLeader() {
+				c.planReplicaRepair()
+			}
+		}
+	}()
+}
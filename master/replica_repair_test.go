@@ -0,0 +1,97 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaRepairManagerUpdate(t *testing.T) {
+	mgr := newReplicaRepairManager()
+	missing := &proto.ReplicaRepairTask{PartitionID: 2, Reason: replicaRepairReasonMissing, Status: replicaRepairPending, CreateTime: 20}
+	corrupt := &proto.ReplicaRepairTask{PartitionID: 1, Addr: "127.0.0.1:17310", Reason: replicaRepairReasonCorrupt, Status: replicaRepairPending, CreateTime: 10}
+	mgr.update([]*proto.ReplicaRepairTask{missing, corrupt})
+	mgr.setTaskStatus(corrupt, replicaRepairFailed, errors.New("no node"))
+
+	tasks := mgr.listTasks()
+	require.Len(t, tasks, 2)
+	require.EqualValues(t, 1, tasks[0].PartitionID)
+	require.Equal(t, replicaRepairFailed, tasks[0].Status)
+	require.Equal(t, "no node", tasks[0].ErrMsg)
+
+	// a task detected again keeps its state
+	again := &proto.ReplicaRepairTask{PartitionID: 1, Addr: "127.0.0.1:17310", Reason: replicaRepairReasonCorrupt, Status: replicaRepairPending, CreateTime: 30}
+	mgr.update([]*proto.ReplicaRepairTask{again})
+	tasks = mgr.listTasks()
+	require.Len(t, tasks, 1)
+	require.Equal(t, replicaRepairFailed, tasks[0].Status)
+	require.EqualValues(t, 10, tasks[0].CreateTime)
+
+	mgr.setTaskStatus(corrupt, replicaRepairRepairing, nil)
+	tasks = mgr.listTasks()
+	require.Empty(t, tasks[0].ErrMsg)
+	require.NotZero(t, tasks[0].ScheduleTime)
+}
+
+func TestParseRequestToSetReplicaRepair(t *testing.T) {
+	r := httptest.NewRequest("GET", "/dataPartition/replicaRepair/set?enable=true", nil)
+	enable, limit, err := parseRequestToSetReplicaRepair(r)
+	require.NoError(t, err)
+	require.True(t, *enable)
+	require.Nil(t, limit)
+
+	r = httptest.NewRequest("GET", "/dataPartition/replicaRepair/set?limitPerZone=3", nil)
+	enable, limit, err = parseRequestToSetReplicaRepair(r)
+	require.NoError(t, err)
+	require.Nil(t, enable)
+	require.EqualValues(t, 3, *limit)
+
+	r = httptest.NewRequest("GET", "/dataPartition/replicaRepair/set", nil)
+	_, _, err = parseRequestToSetReplicaRepair(r)
+	require.Error(t, err)
+}
+
+func TestDetectCorruptReplica(t *testing.T) {
+	c := server.cluster
+	dps := commonVol.dataPartitions.clonePartitions()
+	require.NotEmpty(t, dps)
+	dp := dps[0]
+
+	dp.Lock()
+	replica := dp.Replicas[0]
+	status := replica.Status
+	replica.Status = proto.Unavailable
+	dp.Unlock()
+	defer func() {
+		dp.Lock()
+		replica.Status = status
+		dp.Unlock()
+	}()
+
+	found := false
+	for _, task := range c.detectBadReplicas() {
+		if task.PartitionID == dp.PartitionID && task.Addr == replica.Addr {
+			require.Equal(t, replicaRepairReasonCorrupt, task.Reason)
+			require.NotEmpty(t, task.ZoneName)
+			found = true
+		}
+	}
+	require.True(t, found)
+}
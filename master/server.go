@@ -360,6 +360,28 @@ func (m *Server) checkConfig(cfg *config.Config) (err error) {
 
 	m.config.MonitorPushAddr = cfg.GetString(cfgMonitorPushAddr)
 
+	m.config.StatSnapshotIntervalSec = cfg.GetInt64WithDefault(cfgStatSnapshotIntervalSec, defaultStatSnapshotIntervalSec)
+	if m.config.StatSnapshotIntervalSec <= 0 {
+		return fmt.Errorf("%v,err:%v must be positive", proto.ErrInvalidCfg, cfgStatSnapshotIntervalSec)
+	}
+	m.config.StatSnapshotRetentionHour = cfg.GetInt64WithDefault(cfgStatSnapshotRetentionHour, defaultStatSnapshotRetentionHour)
+	if m.config.StatSnapshotRetentionHour <= 0 {
+		return fmt.Errorf("%v,err:%v must be positive", proto.ErrInvalidCfg, cfgStatSnapshotRetentionHour)
+	}
+
+	m.config.EnableRBAC = cfg.GetBoolWithDefault(cfgEnableRBAC, false)
+
+	splitQps := cfg.GetInt64WithDefault(cfgMetaPartitionSplitQps, 0)
+	if splitQps < 0 {
+		return fmt.Errorf("%v,err:%v can't be negative", proto.ErrInvalidCfg, cfgMetaPartitionSplitQps)
+	}
+	m.config.MetaPartitionSplitQps = uint64(splitQps)
+	splitInodeCount := cfg.GetInt64WithDefault(cfgMetaPartitionSplitInodeCount, 0)
+	if splitInodeCount < 0 {
+		return fmt.Errorf("%v,err:%v can't be negative", proto.ErrInvalidCfg, cfgMetaPartitionSplitInodeCount)
+	}
+	m.config.MetaPartitionSplitInodeCount = uint64(splitInodeCount)
+
 	m.config.volForceDeletion = cfg.GetBoolWithDefault(cfgVolForceDeletion, true)
 
 	threshold := cfg.GetInt64WithDefault(cfgVolDeletionDentryThreshold, 0)
@@ -0,0 +1,334 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// tenantManager holds tenants in memory, all changes are persisted by raft before applied to it
+type tenantManager struct {
+	sync.RWMutex
+	tenants map[string]*proto.TenantInfo
+	// volLock serializes the quota checks with the changes of volumes and capacities of tenants,
+	// so that concurrent requests can't exceed the quota
+	volLock sync.Mutex
+}
+
+func newTenantManager() *tenantManager {
+	return &tenantManager{tenants: make(map[string]*proto.TenantInfo)}
+}
+
+func (mgr *tenantManager) clear() {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.tenants = make(map[string]*proto.TenantInfo)
+}
+
+func (mgr *tenantManager) put(tenant *proto.TenantInfo) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	mgr.tenants[tenant.Name] = tenant
+}
+
+func copyTenant(tenant *proto.TenantInfo) *proto.TenantInfo {
+	dup := *tenant
+	dup.Vols = append([]string{}, tenant.Vols...)
+	dup.Users = append([]string{}, tenant.Users...)
+	return &dup
+}
+
+// getTenant returns a copy of the tenant, it is safe to modify the copy
+func (mgr *tenantManager) getTenant(name string) (*proto.TenantInfo, error) {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	tenant, ok := mgr.tenants[name]
+	if !ok {
+		return nil, proto.ErrTenantNotExists
+	}
+	return copyTenant(tenant), nil
+}
+
+func (mgr *tenantManager) listTenants() []*proto.TenantInfo {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	tenants := make([]*proto.TenantInfo, 0, len(mgr.tenants))
+	for _, tenant := range mgr.tenants {
+		tenants = append(tenants, copyTenant(tenant))
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants
+}
+
+// getVolTenant returns the name of the tenant the volume belongs to, empty if it belongs to none
+func (mgr *tenantManager) getVolTenant(volName string) string {
+	mgr.RLock()
+	defer mgr.RUnlock()
+	for name, tenant := range mgr.tenants {
+		if contains(tenant.Vols, volName) {
+			return name
+		}
+	}
+	return ""
+}
+
+func (c *Cluster) syncPutTenant(opType uint32, tenant *proto.TenantInfo) (err error) {
+	metadata := new(RaftCmd)
+	metadata.Op = opType
+	metadata.K = tenantPrefix + tenant.Name
+	if metadata.V, err = json.Marshal(tenant); err != nil {
+		return
+	}
+	return c.submit(metadata)
+}
+
+func (c *Cluster) syncAddTenant(tenant *proto.TenantInfo) error {
+	return c.syncPutTenant(opSyncAddTenant, tenant)
+}
+
+func (c *Cluster) syncUpdateTenant(tenant *proto.TenantInfo) error {
+	return c.syncPutTenant(opSyncUpdateTenant, tenant)
+}
+
+func (c *Cluster) syncDeleteTenant(tenant *proto.TenantInfo) error {
+	return c.syncPutTenant(opSyncDeleteTenant, tenant)
+}
+
+func (c *Cluster) loadTenants() (err error) {
+	result, err := c.fsm.store.SeekForPrefix([]byte(tenantPrefix))
+	if err != nil {
+		return fmt.Errorf("action[loadTenants],err:%v", err.Error())
+	}
+	for _, value := range result {
+		tenant := &proto.TenantInfo{}
+		if err = json.Unmarshal(value, tenant); err != nil {
+			return fmt.Errorf("action[loadTenants],value:%v,unmarshal err:%v", string(value), err)
+		}
+		c.tenantMgr.put(tenant)
+		log.LogInfof("action[loadTenants],tenant[%v]", tenant.Name)
+	}
+	return
+}
+
+// getTenantUsedCapacity returns the total capacity of volumes of the tenant in GB
+func (c *Cluster) getTenantUsedCapacity(tenant *proto.TenantInfo) (used uint64) {
+	for _, volName := range tenant.Vols {
+		if vol, err := c.getVol(volName); err == nil {
+			used += vol.Capacity
+		}
+	}
+	return
+}
+
+func (c *Cluster) newTenantView(tenant *proto.TenantInfo) *proto.TenantView {
+	return &proto.TenantView{TenantInfo: *tenant, UsedCapacity: c.getTenantUsedCapacity(tenant)}
+}
+
+// checkTenantLimits checks the limits of the tenant are not exceeded by its volumes and users
+func (c *Cluster) checkTenantLimits(tenant *proto.TenantInfo) error {
+	if tenant.VolCountLimit > 0 && len(tenant.Vols) > tenant.VolCountLimit {
+		return fmt.Errorf("tenant[%v] volume count %v exceeds limit %v", tenant.Name, len(tenant.Vols), tenant.VolCountLimit)
+	}
+	if tenant.UserCountLimit > 0 && len(tenant.Users) > tenant.UserCountLimit {
+		return fmt.Errorf("tenant[%v] user count %v exceeds limit %v", tenant.Name, len(tenant.Users), tenant.UserCountLimit)
+	}
+	if tenant.CapacityLimit > 0 {
+		if used := c.getTenantUsedCapacity(tenant); used > tenant.CapacityLimit {
+			return fmt.Errorf("tenant[%v] capacity %vGB exceeds limit %vGB", tenant.Name, used, tenant.CapacityLimit)
+		}
+	}
+	return nil
+}
+
+// checkTenantVolQuota checks a new volume of capacity GB could be created in the tenant
+func (c *Cluster) checkTenantVolQuota(name string, capacity uint64) (err error) {
+	tenant, err := c.tenantMgr.getTenant(name)
+	if err != nil {
+		return
+	}
+	if tenant.VolCountLimit > 0 && len(tenant.Vols) >= tenant.VolCountLimit {
+		return fmt.Errorf("tenant[%v] volume count reaches limit %v", name, tenant.VolCountLimit)
+	}
+	if tenant.CapacityLimit > 0 {
+		if used := c.getTenantUsedCapacity(tenant); used+capacity > tenant.CapacityLimit {
+			return fmt.Errorf("tenant[%v] capacity limit %vGB, used %vGB, no room for %vGB", name, tenant.CapacityLimit, used, capacity)
+		}
+	}
+	return
+}
+
+// checkTenantVolCapacity checks the volume could be resized to capacity GB within the capacity limit
+// of its tenant, the caller must hold volLock.
+func (c *Cluster) checkTenantVolCapacity(vol *Vol, capacity uint64) (err error) {
+	if capacity <= vol.Capacity {
+		return
+	}
+	name := c.tenantMgr.getVolTenant(vol.Name)
+	if name == "" {
+		return
+	}
+	tenant, err := c.tenantMgr.getTenant(name)
+	if err != nil || tenant.CapacityLimit == 0 {
+		return
+	}
+	if used := c.getTenantUsedCapacity(tenant) - vol.Capacity; used+capacity > tenant.CapacityLimit {
+		return fmt.Errorf("tenant[%v] capacity limit %vGB, used by other volumes %vGB, no room for %vGB",
+			name, tenant.CapacityLimit, used, capacity)
+	}
+	return
+}
+
+// createTenantVol creates the volume in the tenant. The volume is added to the tenant before it's created,
+// so it's never left out of the tenant, and it's removed from the tenant if the creation fails.
+func (c *Cluster) createTenantVol(name string, req *createVolReq) (vol *Vol, err error) {
+	c.tenantMgr.volLock.Lock()
+	defer c.tenantMgr.volLock.Unlock()
+	if err = c.checkTenantVolQuota(name, uint64(req.capacity)); err != nil {
+		return
+	}
+	if owner := c.tenantMgr.getVolTenant(req.name); owner != "" {
+		return nil, fmt.Errorf("vol[%v] already belongs to tenant[%v]", req.name, owner)
+	}
+	if _, err = c.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		tenant.Vols = append(tenant.Vols, req.name)
+		return nil
+	}); err != nil {
+		return
+	}
+	if vol, err = c.createVol(req); err != nil {
+		if _, rbErr := c.removeTenantVol(name, req.name); rbErr != nil {
+			log.LogErrorf("action[createTenantVol] remove vol[%v] from tenant[%v] failed, err[%v]", req.name, name, rbErr)
+		}
+		return
+	}
+	return
+}
+
+func (c *Cluster) createTenant(tenant *proto.TenantInfo) (err error) {
+	c.tenantMgr.Lock()
+	defer c.tenantMgr.Unlock()
+	if _, ok := c.tenantMgr.tenants[tenant.Name]; ok {
+		return proto.ErrDuplicateTenant
+	}
+	tenant.CreateTime = time.Unix(time.Now().Unix(), 0).Format(proto.TimeFormat)
+	if err = c.syncAddTenant(tenant); err != nil {
+		return
+	}
+	c.tenantMgr.tenants[tenant.Name] = tenant
+	log.LogInfof("action[createTenant] tenant[%v] created, %+v", tenant.Name, tenant)
+	return
+}
+
+// updateTenant applies update to a copy of the tenant and persists the result
+func (c *Cluster) updateTenant(name string, update func(tenant *proto.TenantInfo) error) (tenant *proto.TenantInfo, err error) {
+	c.tenantMgr.Lock()
+	defer c.tenantMgr.Unlock()
+	old, ok := c.tenantMgr.tenants[name]
+	if !ok {
+		return nil, proto.ErrTenantNotExists
+	}
+	tenant = copyTenant(old)
+	if err = update(tenant); err != nil {
+		return nil, err
+	}
+	if err = c.syncUpdateTenant(tenant); err != nil {
+		return nil, err
+	}
+	c.tenantMgr.tenants[name] = tenant
+	return copyTenant(tenant), nil
+}
+
+// deleteTenant deletes a tenant without volumes
+func (c *Cluster) deleteTenant(name string) (err error) {
+	c.tenantMgr.Lock()
+	defer c.tenantMgr.Unlock()
+	tenant, ok := c.tenantMgr.tenants[name]
+	if !ok {
+		return proto.ErrTenantNotExists
+	}
+	if len(tenant.Vols) > 0 {
+		return fmt.Errorf("tenant[%v] still has volumes %v", name, tenant.Vols)
+	}
+	if err = c.syncDeleteTenant(tenant); err != nil {
+		return
+	}
+	delete(c.tenantMgr.tenants, name)
+	log.LogInfof("action[deleteTenant] tenant[%v] deleted", name)
+	return
+}
+
+func (c *Cluster) addTenantUser(name, userID string) (*proto.TenantInfo, error) {
+	return c.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		if contains(tenant.Users, userID) {
+			return fmt.Errorf("user[%v] already in tenant[%v]", userID, name)
+		}
+		tenant.Users = append(tenant.Users, userID)
+		return c.checkTenantLimits(tenant)
+	})
+}
+
+func (c *Cluster) removeTenantUser(name, userID string) (*proto.TenantInfo, error) {
+	return c.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		var ok bool
+		if tenant.Users, ok = removeString(tenant.Users, userID); !ok {
+			return fmt.Errorf("user[%v] not in tenant[%v]", userID, name)
+		}
+		return nil
+	})
+}
+
+// addTenantVol adds an existing volume to the tenant, a volume belongs to one tenant at most
+func (c *Cluster) addTenantVol(name, volName string) (*proto.TenantInfo, error) {
+	c.tenantMgr.volLock.Lock()
+	defer c.tenantMgr.volLock.Unlock()
+	if _, err := c.getVol(volName); err != nil {
+		return nil, proto.ErrVolNotExists
+	}
+	if owner := c.tenantMgr.getVolTenant(volName); owner != "" {
+		return nil, fmt.Errorf("vol[%v] already belongs to tenant[%v]", volName, owner)
+	}
+	return c.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		tenant.Vols = append(tenant.Vols, volName)
+		return c.checkTenantLimits(tenant)
+	})
+}
+
+func (c *Cluster) removeTenantVol(name, volName string) (*proto.TenantInfo, error) {
+	return c.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		var ok bool
+		if tenant.Vols, ok = removeString(tenant.Vols, volName); !ok {
+			return fmt.Errorf("vol[%v] not in tenant[%v]", volName, name)
+		}
+		return nil
+	})
+}
+
+// removeVolFromTenant removes a deleted volume from its tenant
+func (c *Cluster) removeVolFromTenant(volName string) {
+	name := c.tenantMgr.getVolTenant(volName)
+	if name == "" {
+		return
+	}
+	if _, err := c.removeTenantVol(name, volName); err != nil {
+		log.LogWarnf("action[removeVolFromTenant] remove vol[%v] from tenant[%v] failed, err[%v]", volName, name, err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequestToCreateTenant(t *testing.T) {
+	r := httptest.NewRequest("GET", "/tenant/create?tenant=t1&capacityLimit=100&volCountLimit=2", nil)
+	tenant, err := parseRequestToCreateTenant(r)
+	require.NoError(t, err)
+	require.Equal(t, "t1", tenant.Name)
+	require.EqualValues(t, 100, tenant.CapacityLimit)
+	require.Equal(t, 2, tenant.VolCountLimit)
+	require.Zero(t, tenant.UserCountLimit)
+
+	r = httptest.NewRequest("GET", "/tenant/update?tenant=t1&userCountLimit=3", nil)
+	require.NoError(t, parseTenantLimits(r, tenant))
+	require.EqualValues(t, 100, tenant.CapacityLimit)
+	require.Equal(t, 3, tenant.UserCountLimit)
+
+	r = httptest.NewRequest("GET", "/tenant/create", nil)
+	_, err = parseRequestToCreateTenant(r)
+	require.Error(t, err)
+}
+
+func TestTenantLimits(t *testing.T) {
+	c := server.cluster
+	name := "tenantLimits"
+	require.NoError(t, c.createTenant(&proto.TenantInfo{Name: name, VolCountLimit: 1, CapacityLimit: commonVol.Capacity}))
+	require.Equal(t, proto.ErrDuplicateTenant, c.createTenant(&proto.TenantInfo{Name: name}))
+
+	require.NoError(t, c.checkTenantVolQuota(name, commonVol.Capacity))
+	require.Error(t, c.checkTenantVolQuota(name, commonVol.Capacity+1))
+
+	tenant, err := c.addTenantVol(name, commonVolName)
+	require.NoError(t, err)
+	require.Equal(t, []string{commonVolName}, tenant.Vols)
+	require.Equal(t, commonVol.Capacity, c.newTenantView(tenant).UsedCapacity)
+	require.Equal(t, name, c.tenantMgr.getVolTenant(commonVolName))
+	require.Error(t, c.checkTenantVolQuota(name, 1))
+	require.Error(t, c.deleteTenant(name))
+
+	// the volume can't be expanded beyond the capacity limit of the tenant
+	args := getVolVarargs(commonVol)
+	args.capacity = commonVol.Capacity + 1
+	require.Error(t, c.updateVol(commonVolName, buildAuthKey(testOwner), args))
+	require.NoError(t, c.checkTenantVolCapacity(commonVol, commonVol.Capacity))
+
+	c.removeVolFromTenant(commonVolName)
+	require.Empty(t, c.tenantMgr.getVolTenant(commonVolName))
+	require.NoError(t, c.deleteTenant(name))
+	_, err = c.tenantMgr.getTenant(name)
+	require.Equal(t, proto.ErrTenantNotExists, err)
+}
+
+func TestTenantUsers(t *testing.T) {
+	c := server.cluster
+	name := "tenantUsers"
+	require.NoError(t, c.createTenant(&proto.TenantInfo{Name: name, UserCountLimit: 1}))
+	defer c.deleteTenant(name)
+
+	tenant, err := c.addTenantUser(name, "user1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1"}, tenant.Users)
+	_, err = c.addTenantUser(name, "user2")
+	require.Error(t, err)
+
+	tenant, err = c.removeTenantUser(name, "user1")
+	require.NoError(t, err)
+	require.Empty(t, tenant.Users)
+	_, err = c.removeTenantUser(name, "user1")
+	require.Error(t, err)
+}
+
+func TestCreateVolInTenant(t *testing.T) {
+	name := "tenantVols"
+	c := server.cluster
+	require.NoError(t, c.createTenant(&proto.TenantInfo{Name: name, VolCountLimit: 1}))
+	_, err := c.addTenantVol(name, commonVolName)
+	require.NoError(t, err)
+	defer func() {
+		c.removeVolFromTenant(commonVolName)
+		c.deleteTenant(name)
+	}()
+
+	req := map[string]interface{}{
+		nameKey:        "tenantVol",
+		volOwnerKey:    testOwner,
+		volCapacityKey: 10,
+		tenantKey:      name,
+	}
+	processWithFatalV2(proto.AdminCreateVol, false, req, t)
+	_, err = c.getVol("tenantVol")
+	require.Error(t, err)
+
+	// the volume is removed from the tenant if it can't be created
+	tenant, err := c.updateTenant(name, func(tenant *proto.TenantInfo) error {
+		tenant.VolCountLimit = 0
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = c.createTenantVol(name, &createVolReq{name: "tenantVol", owner: testOwner, capacity: 10, zoneName: "noSuchZone"})
+	require.Error(t, err)
+	tenant, err = c.tenantMgr.getTenant(name)
+	require.NoError(t, err)
+	require.Equal(t, []string{commonVolName}, tenant.Vols)
+}
@@ -1407,6 +1407,9 @@ type Zone struct {
 	QosIopsWLimit           uint64
 	QosFlowRLimit           uint64
 	QosFlowWLimit           uint64
+	// ratio of capacity reserved for repairs and emergency expansion, which new partitions of volumes can not use
+	DataReservedRatio float64
+	MetaReservedRatio float64
 	sync.RWMutex
 }
 
@@ -1418,6 +1421,8 @@ type zoneValue struct {
 	QosFlowWLimit       uint64
 	DataNodesetSelector string
 	MetaNodesetSelector string
+	DataReservedRatio   float64
+	MetaReservedRatio   float64
 }
 
 func newZone(name string) (zone *Zone) {
@@ -1477,6 +1482,8 @@ func (zone *Zone) getFsmValue() *zoneValue {
 		QosFlowWLimit:       zone.QosFlowWLimit,
 		DataNodesetSelector: zone.GetDataNodesetSelector(),
 		MetaNodesetSelector: zone.GetMetaNodesetSelector(),
+		DataReservedRatio:   zone.DataReservedRatio,
+		MetaReservedRatio:   zone.MetaReservedRatio,
 	}
 }
 
@@ -1808,6 +1815,50 @@ func (zone *Zone) getSpaceLeft(dataType uint32) (spaceLeft uint64) {
 	}
 }
 
+func (zone *Zone) getReservedRatio() (dataRatio, metaRatio float64) {
+	zone.RLock()
+	defer zone.RUnlock()
+	return zone.DataReservedRatio, zone.MetaReservedRatio
+}
+
+// isReservationUsedUp returns true if the used capacity of the zone reaches the capacity except the reserved
+func (zone *Zone) isReservationUsedUp(nodeType uint32) bool {
+	var (
+		used  uint64
+		total uint64
+	)
+	dataRatio, metaRatio := zone.getReservedRatio()
+	ratio := dataRatio
+	if nodeType == TypeDataPartition {
+		used, total = zone.getDataUsed()
+	} else {
+		ratio = metaRatio
+		used, total = zone.getMetaUsed()
+	}
+	if ratio <= 0 || total == 0 {
+		return false
+	}
+	return float64(used) >= float64(total)*(1-ratio)
+}
+
+func (zone *Zone) updateReservedRatio(cluster *Cluster, dataRatio, metaRatio *float64) (err error) {
+	zone.Lock()
+	oldData, oldMeta := zone.DataReservedRatio, zone.MetaReservedRatio
+	if dataRatio != nil {
+		zone.DataReservedRatio = *dataRatio
+	}
+	if metaRatio != nil {
+		zone.MetaReservedRatio = *metaRatio
+	}
+	zone.Unlock()
+	if err = cluster.sycnPutZoneInfo(zone); err != nil {
+		zone.Lock()
+		zone.DataReservedRatio, zone.MetaReservedRatio = oldData, oldMeta
+		zone.Unlock()
+	}
+	return
+}
+
 func (zone *Zone) canWriteForMetaNode(replicaNum uint8) (can bool) {
 	zone.RLock()
 	defer zone.RUnlock()
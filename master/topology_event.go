@@ -0,0 +1,158 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+const defaultTopologyEventCapacity = 4096
+
+// topologyEventLog keeps the latest topology changes in memory for subscribers, it is
+// reset with a new epoch when the leader of master changes.
+type topologyEventLog struct {
+	sync.RWMutex
+	epoch    int64
+	events   []*proto.TopologyEvent // ring buffer
+	capacity int
+	nextSeq  uint64
+	// leaders is the last reported leader of partitions, keyed by event type and partition id
+	leaders map[string]map[uint64]string
+	// notify is closed and replaced when an event is recorded
+	notify chan struct{}
+}
+
+func newTopologyEventLog(capacity int) *topologyEventLog {
+	l := &topologyEventLog{capacity: capacity}
+	l.clear()
+	return l
+}
+
+func (l *topologyEventLog) clear() {
+	l.Lock()
+	defer l.Unlock()
+	l.epoch = time.Now().UnixNano()
+	l.events = make([]*proto.TopologyEvent, 0, l.capacity)
+	l.nextSeq = 1
+	l.leaders = make(map[string]map[uint64]string)
+	if l.notify != nil {
+		close(l.notify)
+	}
+	l.notify = make(chan struct{})
+}
+
+func (l *topologyEventLog) record(event *proto.TopologyEvent) {
+	l.Lock()
+	defer l.Unlock()
+	l.recordLocked(event)
+}
+
+func (l *topologyEventLog) recordLocked(event *proto.TopologyEvent) {
+	event.Seq = l.nextSeq
+	event.Time = time.Now().Unix()
+	l.nextSeq++
+	if len(l.events) < l.capacity {
+		l.events = append(l.events, event)
+	} else {
+		l.events[(event.Seq-1)%uint64(l.capacity)] = event
+	}
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// recordLeader records an event if the leader differs from the last reported one, the first
+// report of a partition is not an event since the leader before it is unknown.
+func (l *topologyEventLog) recordLeader(event *proto.TopologyEvent) {
+	l.Lock()
+	defer l.Unlock()
+	leaders, ok := l.leaders[event.Type]
+	if !ok {
+		leaders = make(map[uint64]string)
+		l.leaders[event.Type] = leaders
+	}
+	last, ok := leaders[event.PartitionID]
+	leaders[event.PartitionID] = event.Addr
+	if ok && last != event.Addr {
+		l.recordLocked(event)
+	}
+}
+
+// since returns events with sequence not less than seq, the view is truncated without events
+// if some of them have been dropped from the buffer or seq is from another epoch.
+func (l *topologyEventLog) since(seq uint64) (view *proto.TopologyEventsView, notify chan struct{}) {
+	l.RLock()
+	defer l.RUnlock()
+	view = &proto.TopologyEventsView{Epoch: l.epoch, Events: make([]*proto.TopologyEvent, 0), NextSeq: l.nextSeq}
+	oldest := l.nextSeq - uint64(len(l.events))
+	if seq < oldest || seq > l.nextSeq {
+		view.Truncated = true
+		return view, l.notify
+	}
+	for ; seq < l.nextSeq; seq++ {
+		view.Events = append(view.Events, l.events[(seq-1)%uint64(l.capacity)])
+	}
+	return view, l.notify
+}
+
+// wait returns events since seq, it waits until an event is recorded or timeout if there is no event.
+// A zero seq returns the next sequence only, subscribers start from it.
+func (l *topologyEventLog) wait(seq uint64, timeout time.Duration, done <-chan struct{}) *proto.TopologyEventsView {
+	if seq == 0 {
+		view, _ := l.since(0)
+		view.Truncated = false
+		return view
+	}
+	if timeout > maxClusterEventsWait {
+		timeout = maxClusterEventsWait
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		view, notify := l.since(seq)
+		if len(view.Events) > 0 || view.Truncated || timeout <= 0 {
+			return view
+		}
+		select {
+		case <-notify:
+		case <-timer.C:
+			return view
+		case <-done:
+			return view
+		}
+	}
+}
+
+func (c *Cluster) recordTopologyEvent(eventType, addr, zoneName string) {
+	if c.topologyEventLog == nil {
+		return
+	}
+	c.topologyEventLog.record(&proto.TopologyEvent{Type: eventType, Addr: addr, ZoneName: zoneName})
+}
+
+func (c *Cluster) recordPartitionLeader(eventType, volName string, partitionID uint64, leaderAddr, zoneName string) {
+	if c.topologyEventLog == nil {
+		return
+	}
+	c.topologyEventLog.recordLeader(&proto.TopologyEvent{
+		Type:        eventType,
+		Addr:        leaderAddr,
+		ZoneName:    zoneName,
+		VolName:     volName,
+		PartitionID: partitionID,
+	})
+}
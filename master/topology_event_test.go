@@ -0,0 +1,84 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyEventLogTruncated(t *testing.T) {
+	l := newTopologyEventLog(3)
+	for i := 0; i < 5; i++ {
+		l.record(&proto.TopologyEvent{Type: proto.TopologyEventDataNodeAdded, Addr: "node"})
+	}
+	view, _ := l.since(3)
+	require.False(t, view.Truncated)
+	require.Len(t, view.Events, 3)
+	require.Equal(t, uint64(6), view.NextSeq)
+
+	view, _ = l.since(2)
+	require.True(t, view.Truncated)
+	require.Len(t, view.Events, 0)
+
+	// sequence of the epoch before clear
+	epoch := view.Epoch
+	l.clear()
+	view, _ = l.since(6)
+	require.True(t, view.Truncated)
+	require.NotEqual(t, epoch, view.Epoch)
+	require.Equal(t, uint64(1), view.NextSeq)
+}
+
+func TestTopologyEventLogLeader(t *testing.T) {
+	l := newTopologyEventLog(10)
+	leader := func(id uint64, addr string) {
+		l.recordLeader(&proto.TopologyEvent{Type: proto.TopologyEventDataPartitionLeaderChanged, PartitionID: id, Addr: addr})
+	}
+	leader(1, "a")
+	leader(1, "a")
+	leader(2, "b")
+	view, _ := l.since(1)
+	require.Len(t, view.Events, 0)
+
+	leader(1, "c")
+	l.recordLeader(&proto.TopologyEvent{Type: proto.TopologyEventMetaPartitionLeaderChanged, PartitionID: 1, Addr: "d"})
+	view, _ = l.since(1)
+	require.Len(t, view.Events, 1)
+	require.Equal(t, uint64(1), view.Events[0].PartitionID)
+	require.Equal(t, "c", view.Events[0].Addr)
+}
+
+func TestTopologyEventLogWait(t *testing.T) {
+	l := newTopologyEventLog(10)
+	view := l.wait(0, time.Minute, nil)
+	require.Equal(t, uint64(1), view.NextSeq)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l.record(&proto.TopologyEvent{Type: proto.TopologyEventMetaNodeRemoved, Addr: "node"})
+	}()
+	view = l.wait(view.NextSeq, 5*time.Second, nil)
+	require.Len(t, view.Events, 1)
+	require.Equal(t, proto.TopologyEventMetaNodeRemoved, view.Events[0].Type)
+
+	start := time.Now()
+	view = l.wait(view.NextSeq, 100*time.Millisecond, nil)
+	require.Len(t, view.Events, 0)
+	require.True(t, time.Since(start) >= 100*time.Millisecond)
+}
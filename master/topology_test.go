@@ -150,3 +150,45 @@ func TestAllocZones(t *testing.T) {
 		}
 	}
 }
+
+func TestZoneReservation(t *testing.T) {
+	topo := newTopology()
+	zoneName1 := "zone1"
+	zoneName2 := "zone2"
+	zone1 := newZone(zoneName1)
+	zone2 := newZone(zoneName2)
+	topo.putZone(zone1)
+	topo.putZone(zone2)
+	c := new(Cluster)
+	c.t = topo
+	nodeSet1 := newNodeSet(c, 1, 6, zoneName1)
+	nodeSet2 := newNodeSet(c, 2, 6, zoneName2)
+	zone1.putNodeSet(nodeSet1)
+	zone2.putNodeSet(nodeSet2)
+	// used 10GB of 1024GB
+	topo.putDataNode(createDataNodeForTopo(mds1Addr, zoneName1, nodeSet1))
+	topo.putDataNode(createDataNodeForTopo(mds2Addr, zoneName2, nodeSet2))
+
+	zone1.DataReservedRatio = 0.99
+	zone2.DataReservedRatio = 0.5
+	if !zone1.isReservationUsedUp(TypeDataPartition) {
+		t.Errorf("zone [%v] should be used up", zoneName1)
+	}
+	if zone2.isReservationUsedUp(TypeDataPartition) {
+		t.Errorf("zone [%v] should not be used up", zoneName2)
+	}
+	if zone1.isReservationUsedUp(TypeMetaPartition) {
+		t.Errorf("meta of zone [%v] should not be used up", zoneName1)
+	}
+
+	zones, err := c.getReservationUsedUpZones(TypeDataPartition, "")
+	if err != nil || len(zones) != 1 || zones[0] != zoneName1 {
+		t.Errorf("used up zones should be [%v], but is %v, err %v", zoneName1, zones, err)
+	}
+	if _, err = c.getReservationUsedUpZones(TypeDataPartition, zoneName2); err != nil {
+		t.Errorf("specified zone [%v] should not be used up, err %v", zoneName2, err)
+	}
+	if _, err = c.getReservationUsedUpZones(TypeDataPartition, zoneName1+","+zoneName2); err == nil {
+		t.Errorf("specified zone [%v] should be used up", zoneName1)
+	}
+}
@@ -49,6 +49,8 @@ type VolVarargs struct {
 	txConflictRetryNum      int64
 	txConflictRetryInterval int64
 	txOpLimit               int
+	inlineDirThreshold      int
+	clientQpsLimit          int
 }
 
 // Vol represents a set of meta partitionMap and data partitionMap
@@ -89,6 +91,8 @@ type Vol struct {
 	txConflictRetryNum      int64
 	txConflictRetryInterval int64
 	txOpLimit               int
+	inlineDirThreshold      int // max number of children of a directory stored inline in meta nodes
+	clientQpsLimit          int // max qps of each client on a meta node, 0 means no limit
 	zoneName                string
 	MetaPartitions          map[uint64]*MetaPartition `graphql:"-"`
 	dataPartitions          *DataPartitionMap
@@ -98,6 +102,7 @@ type Vol struct {
 	createMpMutex           sync.RWMutex
 	createTime              int64
 	DeleteLockTime          int64
+	TrashInterval           int64 // retention of trash in minutes, 0 disables the trash
 	description             string
 	dpSelectorName          string
 	dpSelectorParm          string
@@ -117,10 +122,15 @@ type Vol struct {
 	authKey                 string
 	DeleteExecTime          time.Time
 	user                    *User
+	autoScalePolicy         *proto.VolAutoScalePolicy
+	placement               *proto.VolPlacement
+	qosLimit                *proto.VolQosLimit
+	autoScaler              *volAutoScaler
 }
 
 func newVol(vv volValue) (vol *Vol) {
 	vol = &Vol{ID: vv.ID, Name: vv.Name, MetaPartitions: make(map[uint64]*MetaPartition)}
+	vol.autoScaler = newVolAutoScaler()
 	if vol.threshold <= 0 {
 		vol.threshold = defaultMetaPartitionMemUsageThreshold
 	}
@@ -141,6 +151,7 @@ func newVol(vv volValue) (vol *Vol) {
 	vol.mpsCache = make([]byte, 0)
 	vol.createTime = vv.CreateTime
 	vol.DeleteLockTime = vv.DeleteLockTime
+	vol.TrashInterval = vv.TrashInterval
 	vol.description = vv.Description
 	vol.defaultPriority = vv.DefaultPriority
 	vol.domainId = vv.DomainId
@@ -151,6 +162,8 @@ func newVol(vv volValue) (vol *Vol) {
 	vol.txConflictRetryNum = vv.TxConflictRetryNum
 	vol.txConflictRetryInterval = vv.TxConflictRetryInterval
 	vol.txOpLimit = vv.TxOpLimit
+	vol.inlineDirThreshold = vv.InlineDirThreshold
+	vol.clientQpsLimit = vv.ClientQpsLimit
 
 	vol.VolType = vv.VolType
 	vol.EbsBlkSize = vv.EbsBlkSize
@@ -210,6 +223,10 @@ func newVolFromVolValue(vv *volValue) (vol *Vol) {
 	vol.authKey = vv.AuthKey
 	vol.DeleteExecTime = vv.DeleteExecTime
 	vol.user = vv.User
+	vol.autoScalePolicy = vv.AutoScalePolicy
+	vol.autoScaler.restore(vv.AutoScaleRecords)
+	vol.placement = vv.Placement
+	vol.qosLimit = vv.QosLimit
 	return vol
 }
 
@@ -442,6 +459,21 @@ func (vol *Vol) maxPartitionID() (maxPartitionID uint64) {
 	return
 }
 
+// lastPartitionID returns the id of the meta partition which serves the inode range up to
+// defaultMaxMetaPartitionInodeID. It's the partition with the max id, unless a partition in
+// the middle of the inode range has been split after it was created.
+func (vol *Vol) lastPartitionID() (lastPartitionID uint64) {
+	vol.mpsLock.RLock()
+	defer vol.mpsLock.RUnlock()
+	var lastStart uint64
+	for id, mp := range vol.MetaPartitions {
+		if lastPartitionID == 0 || mp.Start > lastStart {
+			lastPartitionID, lastStart = id, mp.Start
+		}
+	}
+	return
+}
+
 func (vol *Vol) getRWMetaPartitionNum() (num uint64, isHeartBeatDone bool) {
 	if time.Now().Unix()-vol.createTime <= defaultMetaPartitionTimeOutSec {
 		log.LogInfof("The vol[%v] is being created.", vol.Name)
@@ -485,9 +517,8 @@ func (vol *Vol) addMetaPartitions(c *Cluster, count int) (err error) {
 	vol.createMpMutex.Lock()
 	defer vol.createMpMutex.Unlock()
 
-	// update End of the maxMetaPartition range
-	maxPartitionId := vol.maxPartitionID()
-	rearMetaPartition := vol.MetaPartitions[maxPartitionId]
+	// update End of the last meta partition range
+	rearMetaPartition := vol.MetaPartitions[vol.lastPartitionID()]
 	oldEnd := rearMetaPartition.End
 	end = rearMetaPartition.MaxInodeID + gConfig.MetaPartitionInodeIdStep
 
@@ -733,14 +764,14 @@ func (vol *Vol) checkReplicaNum(c *Cluster) {
 func (vol *Vol) checkMetaPartitions(c *Cluster) {
 	var tasks []*proto.AdminTask
 	metaPartitionInodeIdStep := gConfig.MetaPartitionInodeIdStep
-	maxPartitionID := vol.maxPartitionID()
+	lastPartitionID := vol.lastPartitionID()
 	mps := vol.cloneMetaPartitionMap()
 	var (
 		doSplit bool
 		err     error
 	)
 	for _, mp := range mps {
-		doSplit = mp.checkStatus(c.Name, true, int(vol.mpReplicaNum), maxPartitionID, metaPartitionInodeIdStep, vol.Forbidden)
+		doSplit = mp.checkStatus(c.Name, true, int(vol.mpReplicaNum), lastPartitionID, metaPartitionInodeIdStep, vol.Forbidden)
 		if doSplit && !c.cfg.DisableAutoCreate {
 			nextStart := mp.MaxInodeID + metaPartitionInodeIdStep
 			log.LogInfof(c.Name, fmt.Sprintf("cluster[%v],vol[%v],meta partition[%v] splits start[%v] maxinodeid:[%v] default step:[%v],nextStart[%v]",
@@ -750,9 +781,13 @@ func (vol *Vol) checkMetaPartitions(c *Cluster) {
 			}
 		}
 
+		if !c.cfg.DisableAutoCreate {
+			vol.checkSplitHotMetaPartition(c, mp)
+		}
+
 		mp.checkLeader(c.Name)
 		mp.checkReplicaNum(c, vol.Name, vol.mpReplicaNum)
-		mp.checkEnd(c, maxPartitionID)
+		mp.checkEnd(c, lastPartitionID)
 		mp.reportMissingReplicas(c.Name, c.leaderInfo.addr, defaultMetaPartitionTimeOutSec, defaultIntervalToAlarmMissingMetaPartition)
 		tasks = append(tasks, mp.replicaCreationTasks(c.Name, vol.Name)...)
 	}
@@ -761,8 +796,7 @@ func (vol *Vol) checkMetaPartitions(c *Cluster) {
 }
 
 func (vol *Vol) checkSplitMetaPartition(c *Cluster, metaPartitionInodeStep uint64) {
-	maxPartitionID := vol.maxPartitionID()
-	maxMP, err := vol.metaPartition(maxPartitionID)
+	maxMP, err := vol.metaPartition(vol.lastPartitionID())
 	if err != nil {
 		return
 	}
@@ -788,7 +822,7 @@ func (vol *Vol) checkSplitMetaPartition(c *Cluster, metaPartitionInodeStep uint6
 			Warn(c.Name, msg)
 		}
 		log.LogInfof("volume[%v] split MaxMP[%v], MaxInodeID[%d] Start[%d] RWMPNum[%d] maxMPInodeUsedRatio[%.2f]",
-			vol.Name, maxPartitionID, maxMP.MaxInodeID, maxMP.Start, RWMPNum, maxMPInodeUsedRatio)
+			vol.Name, maxMP.PartitionID, maxMP.MaxInodeID, maxMP.Start, RWMPNum, maxMPInodeUsedRatio)
 	}
 }
 
@@ -1073,6 +1107,7 @@ func (vol *Vol) updateViewCache(c *Cluster) {
 	view := proto.NewVolView(vol.Name, vol.Status, vol.FollowerRead, vol.createTime, vol.CacheTTL, vol.VolType, vol.DeleteLockTime)
 	view.SetOwner(vol.Owner)
 	view.SetOSSSecure(vol.OSSAccessKey, vol.OSSSecretKey)
+	view.TrashInterval = vol.TrashInterval
 	mpViews := vol.getMetaPartitionsView()
 	view.MetaPartitions = mpViews
 	mpViewsReply := newSuccessHTTPReply(mpViews)
@@ -1288,6 +1323,7 @@ func (vol *Vol) deleteVolFromStore(c *Cluster) (err error) {
 	// then delete the volume
 	c.deleteVol(vol.Name)
 	c.volStatInfo.Delete(vol.Name)
+	c.removeVolFromTenant(vol.Name)
 
 	c.DelBucketLifecycle(vol.Name)
 	return
@@ -1349,13 +1385,18 @@ func (vol *Vol) String() string {
 		vol.Name, vol.dpReplicaNum, vol.mpReplicaNum, vol.Capacity, vol.Status)
 }
 
-func (vol *Vol) doSplitMetaPartition(c *Cluster, mp *MetaPartition, end uint64, metaPartitionInodeIdStep uint64, ignoreNoLeader bool) (nextMp *MetaPartition, err error) {
+// doSplitMetaPartition sets the end of mp to end, and creates a new meta partition for the inode range (end, nextEnd].
+func (vol *Vol) doSplitMetaPartition(c *Cluster, mp *MetaPartition, end, nextEnd uint64, metaPartitionInodeIdStep uint64, ignoreNoLeader bool) (nextMp *MetaPartition, err error) {
 	mp.Lock()
 	defer mp.Unlock()
 
 	if err = mp.canSplit(end, metaPartitionInodeIdStep, ignoreNoLeader); err != nil {
 		return
 	}
+	if end >= nextEnd {
+		err = fmt.Errorf("end[%v] must be less than %v", end, nextEnd)
+		return
+	}
 
 	log.LogWarnf("action[splitMetaPartition],partition[%v],start[%v],end[%v],new end[%v]", mp.PartitionID, mp.Start, mp.End, end)
 	cmdMap := make(map[string]*RaftCmd)
@@ -1368,7 +1409,7 @@ func (vol *Vol) doSplitMetaPartition(c *Cluster, mp *MetaPartition, end uint64,
 	}
 
 	cmdMap[updateMpRaftCmd.K] = updateMpRaftCmd
-	if nextMp, err = vol.doCreateMetaPartition(c, mp.End+1, defaultMaxMetaPartitionInodeID); err != nil {
+	if nextMp, err = vol.doCreateMetaPartition(c, mp.End+1, nextEnd); err != nil {
 		Warn(c.Name, fmt.Sprintf("action[updateEnd] clusterID[%v] partitionID[%v] create meta partition err[%v]",
 			c.Name, mp.PartitionID, err))
 		log.LogErrorf("action[updateEnd] partitionID[%v] err[%v]", mp.PartitionID, err)
@@ -1404,13 +1445,13 @@ func (vol *Vol) splitMetaPartition(c *Cluster, mp *MetaPartition, end uint64, me
 	vol.createMpMutex.Lock()
 	defer vol.createMpMutex.Unlock()
 
-	maxPartitionID := vol.maxPartitionID()
-	if maxPartitionID != mp.PartitionID {
-		err = fmt.Errorf("mp[%v] is not the last meta partition[%v]", mp.PartitionID, maxPartitionID)
+	lastPartitionID := vol.lastPartitionID()
+	if lastPartitionID != mp.PartitionID {
+		err = fmt.Errorf("mp[%v] is not the last meta partition[%v]", mp.PartitionID, lastPartitionID)
 		return
 	}
 
-	nextMp, err := vol.doSplitMetaPartition(c, mp, end, metaPartitionInodeIdStep, ignoreNoLeader)
+	nextMp, err := vol.doSplitMetaPartition(c, mp, end, defaultMaxMetaPartitionInodeID, metaPartitionInodeIdStep, ignoreNoLeader)
 	if err != nil {
 		return
 	}
@@ -1420,6 +1461,75 @@ func (vol *Vol) splitMetaPartition(c *Cluster, mp *MetaPartition, end uint64, me
 	return
 }
 
+// splitMetaPartitionOnline divides the inode range of a meta partition at end, the inodes after
+// end are served by a new meta partition with its own raft group, so that new inodes are created
+// there. Any partition can be split, the partition in the middle of the inode range hands over
+// the rest of its range, and the last one hands over the range up to defaultMaxMetaPartitionInodeID.
+// The split point must be above the inodes allocated by the partition, so no inode or dentry is
+// migrated and the partition keeps serving during the split.
+// If end is 0, the partition keeps a quarter of the inode step for the inodes being allocated.
+func (vol *Vol) splitMetaPartitionOnline(c *Cluster, mp *MetaPartition, end uint64) (nextMp *MetaPartition, err error) {
+	if c.DisableAutoAllocate {
+		err = errors.NewErrorf("cluster auto allocate is disable")
+		return
+	}
+	if vol.Forbidden {
+		err = errors.NewErrorf("volume %v is forbidden", vol.Name)
+		return
+	}
+
+	vol.createMpMutex.Lock()
+	defer vol.createMpMutex.Unlock()
+
+	metaPartitionInodeIdStep := gConfig.MetaPartitionInodeIdStep
+	mp.RLock()
+	nextEnd := mp.End
+	if end == 0 {
+		end = mp.MaxInodeID + metaPartitionInodeIdStep/4
+	}
+	mp.RUnlock()
+	if mp.PartitionID == vol.lastPartitionID() {
+		nextEnd = defaultMaxMetaPartitionInodeID
+	}
+
+	if nextMp, err = vol.doSplitMetaPartition(c, mp, end, nextEnd, metaPartitionInodeIdStep, false); err != nil {
+		return
+	}
+
+	vol.addMetaPartition(nextMp)
+	log.LogWarnf("action[splitMetaPartitionOnline],partition[%v] split at [%v],next partition[%v],start[%v],end[%v]",
+		mp.PartitionID, end, nextMp.PartitionID, nextMp.Start, nextMp.End)
+	return
+}
+
+// checkSplitHotMetaPartition splits the meta partition whose qps or inode count exceeds the
+// threshold, so that new inodes are created in a new partition. A partition is split only if
+// more than half of the inode step is unallocated, so it's split once, since it keeps a quarter
+// of the step after the split.
+func (vol *Vol) checkSplitHotMetaPartition(c *Cluster, mp *MetaPartition) {
+	qpsThreshold := c.cfg.MetaPartitionSplitQps
+	inodeThreshold := c.cfg.MetaPartitionSplitInodeCount
+	if qpsThreshold == 0 && inodeThreshold == 0 {
+		return
+	}
+	mp.RLock()
+	hot := mp.Status == proto.ReadWrite && mp.End-mp.MaxInodeID > gConfig.MetaPartitionInodeIdStep/2 &&
+		((qpsThreshold > 0 && mp.Qps >= qpsThreshold) || (inodeThreshold > 0 && mp.InodeCount >= inodeThreshold))
+	qps, inodeCount := mp.Qps, mp.InodeCount
+	mp.RUnlock()
+	if !hot {
+		return
+	}
+	nextMp, err := vol.splitMetaPartitionOnline(c, mp, 0)
+	if err != nil {
+		Warn(c.Name, fmt.Sprintf("cluster[%v],vol[%v],hot meta partition[%v] qps[%v] inodes[%v] splits failed,err[%v]",
+			c.Name, vol.Name, mp.PartitionID, qps, inodeCount, err))
+		return
+	}
+	log.LogWarnf("action[checkSplitHotMetaPartition] vol[%v] hot meta partition[%v] qps[%v] inodes[%v] split to partition[%v]",
+		vol.Name, mp.PartitionID, qps, inodeCount, nextMp.PartitionID)
+}
+
 func (vol *Vol) createMetaPartition(c *Cluster, start, end uint64) (err error) {
 	var mp *MetaPartition
 	if mp, err = vol.doCreateMetaPartition(c, start, end); err != nil {
@@ -1449,12 +1559,24 @@ func (vol *Vol) doCreateMetaPartition(c *Cluster, start, end uint64) (mp *MetaPa
 		}
 	} else {
 		var excludeZone []string
+		if excludeZone, err = c.getReservationUsedUpZones(TypeMetaPartition, vol.zoneName); err != nil {
+			log.LogErrorf("action[doCreateMetaPartition] getReservationUsedUpZones err[%v]", err)
+			return nil, errors.NewError(err)
+		}
+		placement := vol.getPlacement()
 		zoneNum := c.decideZoneNum(vol.crossZone)
+		if placement.MinZones > zoneNum {
+			zoneNum = placement.MinZones
+		}
 
-		if hosts, peers, err = c.getHostFromNormalZone(TypeMetaPartition, excludeZone, nil, nil, int(vol.mpReplicaNum), zoneNum, vol.zoneName); err != nil {
+		if hosts, peers, err = c.getHostFromNormalZone(TypeMetaPartition, excludeZone, placement.ExcludeNodeSets, nil, int(vol.mpReplicaNum), zoneNum, vol.zoneName); err != nil {
 			log.LogErrorf("action[doCreateMetaPartition] getHostFromNormalZone err[%v]", err)
 			return nil, errors.NewError(err)
 		}
+		if err = c.checkHostsZones(TypeMetaPartition, hosts, placement.MinZones); err != nil {
+			log.LogErrorf("action[doCreateMetaPartition] checkHostsZones err[%v]", err)
+			return nil, errors.NewError(err)
+		}
 
 	}
 
@@ -1528,6 +1650,8 @@ func setVolFromArgs(args *VolVarargs, vol *Vol) {
 	vol.txConflictRetryNum = args.txConflictRetryNum
 	vol.txConflictRetryInterval = args.txConflictRetryInterval
 	vol.txOpLimit = args.txOpLimit
+	vol.inlineDirThreshold = args.inlineDirThreshold
+	vol.clientQpsLimit = args.clientQpsLimit
 	vol.dpReplicaNum = args.dpReplicaNum
 
 	if proto.IsCold(vol.VolType) {
@@ -1579,6 +1703,8 @@ func getVolVarargs(vol *Vol) *VolVarargs {
 		txConflictRetryNum:      vol.txConflictRetryNum,
 		txConflictRetryInterval: vol.txConflictRetryInterval,
 		txOpLimit:               vol.txOpLimit,
+		inlineDirThreshold:      vol.inlineDirThreshold,
+		clientQpsLimit:          vol.clientQpsLimit,
 		coldArgs:                args,
 		dpReadOnlyWhenVolFull:   vol.DpReadOnlyWhenVolFull,
 	}
@@ -0,0 +1,189 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	defaultAutoScaleExpandCount = minNumOfRWDataPartitions
+	defaultAutoScaleCooldownSec = 300
+	minAutoScaleCooldownSec     = 60
+	maxAutoScaleRecords         = 32
+)
+
+// volAutoScaler keeps the state of automatic expansions of a volume, the records are persisted
+// with the volume and restored by the new leader
+type volAutoScaler struct {
+	sync.RWMutex
+	lastScaleTime time.Time
+	records       []*proto.VolAutoScaleRecord
+}
+
+func newVolAutoScaler() *volAutoScaler {
+	return &volAutoScaler{records: make([]*proto.VolAutoScaleRecord, 0)}
+}
+
+func (s *volAutoScaler) inCooldown(cooldownSec int64) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return time.Since(s.lastScaleTime) < time.Duration(cooldownSec)*time.Second
+}
+
+func (s *volAutoScaler) addRecord(record *proto.VolAutoScaleRecord) {
+	s.Lock()
+	defer s.Unlock()
+	s.lastScaleTime = time.Unix(record.Time, 0)
+	s.records = append(s.records, record)
+	if len(s.records) > maxAutoScaleRecords {
+		s.records = s.records[len(s.records)-maxAutoScaleRecords:]
+	}
+}
+
+// restore the records persisted with the volume, the cooldown continues from the last record
+func (s *volAutoScaler) restore(records []*proto.VolAutoScaleRecord) {
+	if len(records) == 0 {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.records = append(s.records[:0], records...)
+	s.lastScaleTime = time.Unix(records[len(records)-1].Time, 0)
+}
+
+func (s *volAutoScaler) getRecords() []*proto.VolAutoScaleRecord {
+	s.RLock()
+	defer s.RUnlock()
+	records := make([]*proto.VolAutoScaleRecord, len(s.records))
+	copy(records, s.records)
+	return records
+}
+
+func newDefaultVolAutoScalePolicy() *proto.VolAutoScalePolicy {
+	return &proto.VolAutoScalePolicy{
+		ExpandCount: defaultAutoScaleExpandCount,
+		CooldownSec: defaultAutoScaleCooldownSec,
+	}
+}
+
+func checkVolAutoScalePolicy(policy *proto.VolAutoScalePolicy) error {
+	if policy.ExpandCount <= 0 || policy.ExpandCount > maxNumberOfDataPartitionsForExpansion {
+		return fmt.Errorf("expandCount should be in [1, %v]", maxNumberOfDataPartitionsForExpansion)
+	}
+	if policy.CooldownSec < minAutoScaleCooldownSec {
+		return fmt.Errorf("cooldown should not be less than %v seconds", minAutoScaleCooldownSec)
+	}
+	if policy.MinWritableDp < 0 {
+		return fmt.Errorf("minWritableDp should not be negative")
+	}
+	if policy.Enable && policy.MinWritableSpace == 0 && policy.MinWritableDp == 0 {
+		return fmt.Errorf("minWritableSpace or minWritableDp is required to enable auto-scaling")
+	}
+	return nil
+}
+
+func (vol *Vol) getAutoScalePolicy() *proto.VolAutoScalePolicy {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	if vol.autoScalePolicy == nil {
+		return newDefaultVolAutoScalePolicy()
+	}
+	policy := *vol.autoScalePolicy
+	return &policy
+}
+
+func (vol *Vol) setAutoScalePolicy(policy *proto.VolAutoScalePolicy) {
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	vol.autoScalePolicy = policy
+}
+
+// writableSpace returns the writable space in GB and count of read write data partitions of the volume
+func (vol *Vol) writableSpace() (space uint64, count int) {
+	for _, dp := range vol.dataPartitions.clonePartitions() {
+		if dp.Status != proto.ReadWrite {
+			continue
+		}
+		count++
+		if used := dp.getMaxUsedSpace(); dp.total > used {
+			space += dp.total - used
+		}
+	}
+	return space / util.GB, count
+}
+
+// autoScaleReason returns why the volume should be expanded by the policy, empty if it should not
+func autoScaleReason(policy *proto.VolAutoScalePolicy, writableSpace uint64, writableDp int) string {
+	reasons := make([]string, 0, 2)
+	if policy.MinWritableSpace > 0 && writableSpace < policy.MinWritableSpace {
+		reasons = append(reasons, fmt.Sprintf("writable space %vGB is less than %vGB", writableSpace, policy.MinWritableSpace))
+	}
+	if policy.MinWritableDp > 0 && writableDp < policy.MinWritableDp {
+		reasons = append(reasons, fmt.Sprintf("writable data partitions %v is less than %v", writableDp, policy.MinWritableDp))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// checkAutoScale expands data partitions of the volume if its policy is enabled and triggered
+func (vol *Vol) checkAutoScale(c *Cluster) {
+	policy := vol.getAutoScalePolicy()
+	if !policy.Enable || !proto.IsHot(vol.VolType) || vol.status() == proto.VolStatusMarkDelete {
+		return
+	}
+	if c.DisableAutoAllocate || vol.Forbidden || vol.autoScaler.inCooldown(policy.CooldownSec) {
+		return
+	}
+	writableSpace, writableDp := vol.writableSpace()
+	reason := autoScaleReason(policy, writableSpace, writableDp)
+	if reason == "" {
+		return
+	}
+
+	record := &proto.VolAutoScaleRecord{
+		Time:          time.Now().Unix(),
+		Reason:        reason,
+		WritableSpace: writableSpace,
+		WritableDp:    writableDp,
+		ExpandCount:   policy.ExpandCount,
+	}
+	before := len(vol.dataPartitions.clonePartitions())
+	if err := c.batchCreateDataPartition(vol, policy.ExpandCount, false); err != nil {
+		record.Error = err.Error()
+	}
+	record.Created = len(vol.dataPartitions.clonePartitions()) - before
+	vol.autoScaler.addRecord(record)
+	if err := c.syncUpdateVol(vol); err != nil {
+		log.LogWarnf("action[checkAutoScale] vol %v persist auto-scaling record failed: %v", vol.Name, err)
+	}
+
+	msg := fmt.Sprintf("vol %v auto-scaling created %v of %v data partitions, %v", vol.Name, record.Created,
+		record.ExpandCount, reason)
+	if record.Error != "" {
+		msg += ", err: " + record.Error
+		log.LogWarnf("action[checkAutoScale] %v", msg)
+		c.recordEvent(proto.EventSeverityWarning, eventModuleVolume, "%v", msg)
+		return
+	}
+	log.LogInfof("action[checkAutoScale] %v", msg)
+	c.recordEvent(proto.EventSeverityInfo, eventModuleVolume, "%v", msg)
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckVolAutoScalePolicy(t *testing.T) {
+	policy := newDefaultVolAutoScalePolicy()
+	require.NoError(t, checkVolAutoScalePolicy(policy))
+
+	policy.Enable = true
+	require.Error(t, checkVolAutoScalePolicy(policy))
+	policy.MinWritableDp = 20
+	require.NoError(t, checkVolAutoScalePolicy(policy))
+
+	policy.ExpandCount = maxNumberOfDataPartitionsForExpansion + 1
+	require.Error(t, checkVolAutoScalePolicy(policy))
+	policy.ExpandCount = defaultAutoScaleExpandCount
+	policy.CooldownSec = minAutoScaleCooldownSec - 1
+	require.Error(t, checkVolAutoScalePolicy(policy))
+}
+
+func TestAutoScaleReason(t *testing.T) {
+	policy := &proto.VolAutoScalePolicy{Enable: true, MinWritableSpace: 100, MinWritableDp: 10}
+	require.Empty(t, autoScaleReason(policy, 100, 10))
+	require.Contains(t, autoScaleReason(policy, 99, 10), "writable space")
+	require.Contains(t, autoScaleReason(policy, 100, 9), "writable data partitions")
+
+	// zero threshold is not checked
+	policy.MinWritableSpace = 0
+	require.Empty(t, autoScaleReason(policy, 0, 10))
+}
+
+func TestVolAutoScalerRecords(t *testing.T) {
+	s := newVolAutoScaler()
+	require.False(t, s.inCooldown(defaultAutoScaleCooldownSec))
+	for i := 0; i < maxAutoScaleRecords+2; i++ {
+		s.addRecord(&proto.VolAutoScaleRecord{Time: time.Now().Unix(), ExpandCount: i})
+	}
+	require.True(t, s.inCooldown(defaultAutoScaleCooldownSec))
+	records := s.getRecords()
+	require.Len(t, records, maxAutoScaleRecords)
+	require.Equal(t, 2, records[0].ExpandCount)
+
+	// the records and cooldown are restored with the volume
+	vol := newVol(volValue{ID: 1, Name: "autoScale"})
+	vol.autoScaler = s
+	restored := newVolFromVolValue(newVolValue(vol))
+	require.Equal(t, records, restored.autoScaler.getRecords())
+	require.True(t, restored.autoScaler.inCooldown(defaultAutoScaleCooldownSec))
+}
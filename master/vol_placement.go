@@ -0,0 +1,120 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+func (vol *Vol) getPlacement() *proto.VolPlacement {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	placement := &proto.VolPlacement{}
+	if vol.placement != nil {
+		placement.MinZones = vol.placement.MinZones
+		placement.ExcludeNodeSets = append(placement.ExcludeNodeSets, vol.placement.ExcludeNodeSets...)
+	}
+	return placement
+}
+
+func (vol *Vol) setPlacement(placement *proto.VolPlacement) {
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	vol.placement = placement
+}
+
+// checkVolPlacement checks if the placement can be satisfied by replicas and zones of the volume
+func (c *Cluster) checkVolPlacement(vol *Vol, placement *proto.VolPlacement) error {
+	if c.isFaultDomain(vol) {
+		return fmt.Errorf("vol[%v] in fault domain does not support placement constraints", vol.Name)
+	}
+	if placement.MinZones < 0 {
+		return fmt.Errorf("minZones should not be negative")
+	}
+	if placement.MinZones > int(vol.dpReplicaNum) || placement.MinZones > int(vol.mpReplicaNum) {
+		return fmt.Errorf("minZones[%v] is more than replicas of vol[%v]", placement.MinZones, vol.Name)
+	}
+	if vol.zoneName != "" && placement.MinZones > len(strings.Split(vol.zoneName, ",")) {
+		return fmt.Errorf("minZones[%v] is more than zones[%v] of vol[%v]", placement.MinZones, vol.zoneName, vol.Name)
+	}
+	for _, id := range placement.ExcludeNodeSets {
+		if _, err := c.t.getNodeSetByNodeSetId(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cluster) getVolPlacement(volName string) *proto.VolPlacement {
+	vol, err := c.getVol(volName)
+	if err != nil {
+		return &proto.VolPlacement{}
+	}
+	return vol.getPlacement()
+}
+
+// getAvailHostsFromNodeSet chooses a node in the node set for a new replica of the volume, it fails if the
+// node set is excluded by placement of the volume.
+func (c *Cluster) getAvailHostsFromNodeSet(ns *nodeSet, nodeType uint32, volName string, excludeHosts []string) (
+	hosts []string, peers []proto.Peer, err error,
+) {
+	for _, id := range c.getVolPlacement(volName).ExcludeNodeSets {
+		if id == ns.ID {
+			return nil, nil, fmt.Errorf("nodeset[%v] is excluded by placement of vol[%v]", ns.ID, volName)
+		}
+	}
+	if nodeType == TypeDataPartition {
+		return ns.getAvailDataNodeHosts(excludeHosts, 1)
+	}
+	return ns.getAvailMetaNodeHosts(excludeHosts, 1)
+}
+
+// getPlacementExcludeZones returns zones excluded when a new replica is chosen from other zones, zones of
+// all live replicas are excluded if the volume requires replicas to span zones.
+func getPlacementExcludeZones(placement *proto.VolPlacement, liveZones []string, srcZone string) (excludeZones []string) {
+	if placement.MinZones > 1 {
+		return append(liveZones, srcZone)
+	}
+	if len(liveZones) == 0 {
+		return []string{srcZone}
+	}
+	return []string{liveZones[0]}
+}
+
+// checkHostsZones checks if hosts of a new partition span at least minZones zones
+func (c *Cluster) checkHostsZones(nodeType uint32, hosts []string, minZones int) error {
+	if minZones <= 1 {
+		return nil
+	}
+	zones := make(map[string]bool)
+	for _, host := range hosts {
+		if nodeType == TypeDataPartition {
+			if dataNode, err := c.dataNode(host); err == nil {
+				zones[dataNode.ZoneName] = true
+			}
+			continue
+		}
+		if metaNode, err := c.metaNode(host); err == nil {
+			zones[metaNode.ZoneName] = true
+		}
+	}
+	if len(zones) < minZones {
+		return fmt.Errorf("hosts%v span %v zones, less than minZones[%v] of placement", hosts, len(zones), minZones)
+	}
+	return nil
+}
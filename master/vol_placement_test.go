@@ -0,0 +1,51 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequestToSetVolPlacement(t *testing.T) {
+	placement := &proto.VolPlacement{MinZones: 2, ExcludeNodeSets: []uint64{1}}
+	r := httptest.NewRequest("GET", "/vol/placement/set?name=vol&excludeNodeSets=3,4", nil)
+	require.NoError(t, r.ParseForm())
+	require.NoError(t, parseRequestToSetVolPlacement(r, placement))
+	require.Equal(t, 2, placement.MinZones)
+	require.Equal(t, []uint64{3, 4}, placement.ExcludeNodeSets)
+
+	r = httptest.NewRequest("GET", "/vol/placement/set?name=vol&minZones=3&excludeNodeSets=", nil)
+	require.NoError(t, r.ParseForm())
+	require.NoError(t, parseRequestToSetVolPlacement(r, placement))
+	require.Equal(t, 3, placement.MinZones)
+	require.Empty(t, placement.ExcludeNodeSets)
+
+	r = httptest.NewRequest("GET", "/vol/placement/set?name=vol&excludeNodeSets=a", nil)
+	require.NoError(t, r.ParseForm())
+	require.Error(t, parseRequestToSetVolPlacement(r, placement))
+}
+
+func TestGetPlacementExcludeZones(t *testing.T) {
+	placement := &proto.VolPlacement{}
+	require.Equal(t, []string{"zone1"}, getPlacementExcludeZones(placement, nil, "zone1"))
+	require.Equal(t, []string{"zone2"}, getPlacementExcludeZones(placement, []string{"zone2", "zone3"}, "zone1"))
+
+	placement.MinZones = 3
+	require.Equal(t, []string{"zone2", "zone3", "zone1"}, getPlacementExcludeZones(placement, []string{"zone2", "zone3"}, "zone1"))
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"github.com/cubefs/cubefs/proto"
+)
+
+// getVolQosLimit returns a copy of the qos limit of the volume, nil if the volume is not limited
+func (vol *Vol) getVolQosLimit() *proto.VolQosLimit {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	if vol.qosLimit.IsEmpty() {
+		return nil
+	}
+	limit := *vol.qosLimit
+	return &limit
+}
+
+func (vol *Vol) setVolQosLimit(limit *proto.VolQosLimit) {
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	if limit.IsEmpty() {
+		vol.qosLimit = nil
+		return
+	}
+	vol.qosLimit = limit
+}
+
+// getDataNodeCount returns the number of data nodes which host data partitions of the volume
+func (vol *Vol) getDataNodeCount() int {
+	hosts := make(map[string]struct{})
+	for _, dp := range vol.dataPartitions.clonePartitions() {
+		dp.RLock()
+		for _, host := range dp.Hosts {
+			hosts[host] = struct{}{}
+		}
+		dp.RUnlock()
+	}
+	return len(hosts)
+}
+
+// shareLimit returns the share of each node of the limit, a limited node is never unlimited by its share
+func shareLimit(limit uint64, nodeCount int) uint64 {
+	if limit == 0 || nodeCount <= 1 {
+		return limit
+	}
+	share := limit / uint64(nodeCount)
+	if share == 0 {
+		share = 1
+	}
+	return share
+}
+
+func shareVolQosLimit(limit *proto.VolQosLimit, nodeCount int) *proto.VolQosLimit {
+	return &proto.VolQosLimit{
+		IopsReadLimit:  shareLimit(limit.IopsReadLimit, nodeCount),
+		IopsWriteLimit: shareLimit(limit.IopsWriteLimit, nodeCount),
+		FlowReadLimit:  shareLimit(limit.FlowReadLimit, nodeCount),
+		FlowWriteLimit: shareLimit(limit.FlowWriteLimit, nodeCount),
+	}
+}
+
+// getVolQosLimitShares returns the share of each data node of qos limits of volumes, data nodes of a volume
+// share its limits evenly.
+func (c *Cluster) getVolQosLimitShares() map[string]*proto.VolQosLimit {
+	shares := make(map[string]*proto.VolQosLimit)
+	for _, vol := range c.allVols() {
+		limit := vol.getVolQosLimit()
+		if limit == nil {
+			continue
+		}
+		shares[vol.Name] = shareVolQosLimit(limit, vol.getDataNodeCount())
+	}
+	return shares
+}
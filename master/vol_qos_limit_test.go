@@ -0,0 +1,56 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareVolQosLimit(t *testing.T) {
+	limit := &proto.VolQosLimit{IopsReadLimit: 1000, IopsWriteLimit: 2, FlowReadLimit: 300 * util.MB}
+	share := shareVolQosLimit(limit, 3)
+	require.EqualValues(t, 333, share.IopsReadLimit)
+	require.EqualValues(t, 1, share.IopsWriteLimit)
+	require.EqualValues(t, 100*util.MB, share.FlowReadLimit)
+	require.Zero(t, share.FlowWriteLimit)
+
+	require.Equal(t, *limit, *shareVolQosLimit(limit, 0))
+}
+
+func TestParseRequestToSetVolQosLimit(t *testing.T) {
+	limit := &proto.VolQosLimit{IopsReadLimit: 100, FlowWriteLimit: 10 * util.MB}
+	r := httptest.NewRequest("GET", "/vol/qosLimit/set?name=vol&iopsWriteLimit=200&flowReadLimit=50", nil)
+	require.NoError(t, parseRequestToSetVolQosLimit(r, limit))
+	require.EqualValues(t, 100, limit.IopsReadLimit)
+	require.EqualValues(t, 200, limit.IopsWriteLimit)
+	require.EqualValues(t, 50*util.MB, limit.FlowReadLimit)
+	require.EqualValues(t, 10*util.MB, limit.FlowWriteLimit)
+
+	r = httptest.NewRequest("GET", "/vol/qosLimit/set?name=vol&iopsReadLimit=-1", nil)
+	require.Error(t, parseRequestToSetVolQosLimit(r, limit))
+}
+
+func TestVolQosLimitEmpty(t *testing.T) {
+	vol := &Vol{}
+	vol.setVolQosLimit(&proto.VolQosLimit{})
+	require.Nil(t, vol.getVolQosLimit())
+	vol.setVolQosLimit(&proto.VolQosLimit{FlowReadLimit: util.MB})
+	require.EqualValues(t, util.MB, vol.getVolQosLimit().FlowReadLimit)
+}
@@ -24,6 +24,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/util/config"
@@ -62,12 +63,15 @@ func (m *MetaNode) registerAPIHandler() (err error) {
 	http.HandleFunc("/getEbsExtentsByInode", m.getEbsExtentsByInodeHandler)
 	// get all inodes of the partitionID
 	http.HandleFunc("/getAllInodes", m.getAllInodesHandler)
+	http.HandleFunc("/getInodeCount", m.getInodeCountHandler)
 	// get dentry information
 	http.HandleFunc("/getDentry", m.getDentryHandler)
 	http.HandleFunc("/getDirectory", m.getDirectoryHandler)
 	http.HandleFunc("/getAllDentry", m.getAllDentriesHandler)
 	http.HandleFunc("/getAllTxInfo", m.getAllTxHandler)
 	http.HandleFunc("/getParams", m.getParamsHandler)
+	http.HandleFunc("/getConfig", m.getConfigHandler)
+	http.HandleFunc("/getQuotaUsage", m.getQuotaUsageHandler)
 	http.HandleFunc("/getSmuxStat", m.getSmuxStatHandler)
 	http.HandleFunc("/getRaftStatus", m.getRaftStatusHandler)
 	http.HandleFunc("/genClusterVersionFile", m.genClusterVersionFileHandler)
@@ -75,6 +79,12 @@ func (m *MetaNode) registerAPIHandler() (err error) {
 	http.HandleFunc("/getDentrySnapshot", m.getDentrySnapshotHandler)
 	// get tx information
 	http.HandleFunc("/getTx", m.getTxHandler)
+	// get namespace change events of the partitionID
+	http.HandleFunc("/getChangeEvents", m.getChangeEventsHandler)
+	// export and import snapshot of partition
+	http.HandleFunc("/exportSnapshot", m.exportSnapshotHandler)
+	http.HandleFunc("/importSnapshot", m.importSnapshotHandler)
+	http.HandleFunc("/getImportSnapshotStatus", m.getImportSnapshotStatusHandler)
 	return
 }
 
@@ -89,6 +99,47 @@ func (m *MetaNode) getParamsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// getConfigHandler returns runtime configs, which are synced from master
+// and can be changed by setting cluster parameters of master.
+func (m *MetaNode) getConfigHandler(w http.ResponseWriter, r *http.Request) {
+	resp := NewAPIResponse(http.StatusOK, http.StatusText(http.StatusOK))
+	resp.Data = map[string]string{
+		metaNodeDeleteBatchCountKey:    strconv.FormatUint(DeleteBatchCount(), 10),
+		metaNodeDeleteWorkerSleepMsKey: strconv.FormatUint(atomic.LoadUint64(&deleteWorkerSleepMs), 10),
+		metaNodeDirChildrenLimitKey:    strconv.FormatUint(uint64(atomic.LoadUint32(&dirChildrenNumLimit)), 10),
+	}
+	data, _ := resp.Marshal()
+	if _, err := w.Write(data); err != nil {
+		log.LogErrorf("[getConfigHandler] response %s", err)
+	}
+}
+
+// getQuotaUsageHandler returns used files and bytes of quotas on the partition
+func (m *MetaNode) getQuotaUsageHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	resp := NewAPIResponse(http.StatusBadRequest, "")
+	defer func() {
+		data, _ := resp.Marshal()
+		if _, err := w.Write(data); err != nil {
+			log.LogErrorf("[getQuotaUsageHandler] response %s", err)
+		}
+	}()
+	pid, err := strconv.ParseUint(r.FormValue("pid"), 10, 64)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	mp, err := m.metadataManager.GetPartition(pid)
+	if err != nil {
+		resp.Code = http.StatusNotFound
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Data = mp.getQuotaUsedInfos()
+	resp.Code = http.StatusOK
+	resp.Msg = http.StatusText(http.StatusOK)
+}
+
 func (m *MetaNode) getSmuxStatHandler(w http.ResponseWriter, r *http.Request) {
 	resp := NewAPIResponse(http.StatusOK, http.StatusText(http.StatusOK))
 	resp.Data = smuxPool.GetStat()
@@ -226,6 +277,54 @@ func (m *MetaNode) getAllInodesHandler(w http.ResponseWriter, r *http.Request) {
 	mp.GetInodeTree().Ascend(f)
 }
 
+// getInodeCountHandler returns count of files and directories of the partition
+// which are visible in the snapshot of verSeq, or the latest if verSeq is not set.
+func (m *MetaNode) getInodeCountHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	resp := NewAPIResponse(http.StatusBadRequest, "")
+	defer func() {
+		data, _ := resp.Marshal()
+		if _, err := w.Write(data); err != nil {
+			log.LogErrorf("[getInodeCountHandler] response %s", err)
+		}
+	}()
+	pid, err := strconv.ParseUint(r.FormValue("pid"), 10, 64)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	mp, err := m.metadataManager.GetPartition(pid)
+	if err != nil {
+		resp.Code = http.StatusNotFound
+		resp.Msg = err.Error()
+		return
+	}
+	verSeq, err := m.getRealVerSeq(w, r)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	var files, dirs uint64
+	mp.GetInodeTree().Ascend(func(i BtreeItem) bool {
+		inode, _ := i.(*Inode).getInoByVer(verSeq, false)
+		if inode == nil || inode.ShouldDelete() || inode.GetNLink() == 0 {
+			return true
+		}
+		if proto.IsDir(inode.Type) {
+			dirs++
+		} else {
+			files++
+		}
+		return true
+	})
+	resp.Data = map[string]uint64{
+		"files": files,
+		"dirs":  dirs,
+	}
+	resp.Code = http.StatusOK
+	resp.Msg = http.StatusText(http.StatusOK)
+}
+
 func (m *MetaNode) getSplitKeyHandler(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	log.LogDebugf("getSplitKeyHandler")
@@ -854,3 +953,141 @@ func (m *MetaNode) getSnapshotHandler(w http.ResponseWriter, r *http.Request, fi
 		return
 	}
 }
+
+func (m *MetaNode) getChangeEventsHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	resp := NewAPIResponse(http.StatusBadRequest, "")
+	defer func() {
+		data, _ := resp.Marshal()
+		if _, err := w.Write(data); err != nil {
+			log.LogErrorf("[getChangeEventsHandler] response %s", err)
+		}
+	}()
+	pid, err := strconv.ParseUint(r.FormValue("pid"), 10, 64)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	var from uint64
+	if value := r.FormValue("from"); value != "" {
+		if from, err = strconv.ParseUint(value, 10, 64); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+	}
+	var limit int
+	if value := r.FormValue("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+	}
+	mp, err := m.metadataManager.GetPartition(pid)
+	if err != nil {
+		resp.Code = http.StatusNotFound
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Code = http.StatusOK
+	resp.Msg = http.StatusText(http.StatusOK)
+	resp.Data = mp.GetChangeEvents(from, limit)
+}
+
+// exportSnapshotHandler uploads the latest snapshot of the partition to path,
+// e.g. s3://bucket/prefix or file:///dir.
+func (m *MetaNode) exportSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	resp := NewAPIResponse(http.StatusBadRequest, "")
+	defer func() {
+		data, _ := resp.Marshal()
+		if _, err := w.Write(data); err != nil {
+			log.LogErrorf("[exportSnapshotHandler] response %s", err)
+		}
+	}()
+	pid, err := strconv.ParseUint(r.FormValue("pid"), 10, 64)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	target := r.FormValue("path")
+	if target == "" {
+		resp.Msg = "path is required"
+		return
+	}
+	mp, err := m.metadataManager.GetPartition(pid)
+	if err != nil {
+		resp.Code = http.StatusNotFound
+		resp.Msg = err.Error()
+		return
+	}
+	manifest, err := mp.ExportSnapshot(target)
+	if err != nil {
+		resp.Code = http.StatusInternalServerError
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Code = http.StatusOK
+	resp.Msg = http.StatusText(http.StatusOK)
+	resp.Data = manifest
+}
+
+// importSnapshotHandler starts importing an exported snapshot into the empty partition,
+// it should be requested on the leader of the partition.
+func (m *MetaNode) importSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	resp := NewAPIResponse(http.StatusBadRequest, "")
+	defer func() {
+		data, _ := resp.Marshal()
+		if _, err := w.Write(data); err != nil {
+			log.LogErrorf("[importSnapshotHandler] response %s", err)
+		}
+	}()
+	pid, err := strconv.ParseUint(r.FormValue("pid"), 10, 64)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	source := r.FormValue("path")
+	if source == "" {
+		resp.Msg = "path is required"
+		return
+	}
+	mp, err := m.metadataManager.GetPartition(pid)
+	if err != nil {
+		resp.Code = http.StatusNotFound
+		resp.Msg = err.Error()
+		return
+	}
+	if err = mp.ImportSnapshot(source); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Code = http.StatusOK
+	resp.Msg = http.StatusText(http.StatusOK)
+	resp.Data = mp.GetSnapshotImportStatus()
+}
+
+func (m *MetaNode) getImportSnapshotStatusHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	resp := NewAPIResponse(http.StatusBadRequest, "")
+	defer func() {
+		data, _ := resp.Marshal()
+		if _, err := w.Write(data); err != nil {
+			log.LogErrorf("[getImportSnapshotStatusHandler] response %s", err)
+		}
+	}()
+	pid, err := strconv.ParseUint(r.FormValue("pid"), 10, 64)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	mp, err := m.metadataManager.GetPartition(pid)
+	if err != nil {
+		resp.Code = http.StatusNotFound
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Code = http.StatusOK
+	resp.Msg = http.StatusText(http.StatusOK)
+	resp.Data = mp.GetSnapshotImportStatus()
+}
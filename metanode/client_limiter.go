@@ -0,0 +1,92 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiters of clients not accessed within the time are released
+const clientLimiterIdleTime = 10 * time.Minute
+
+type clientLimiter struct {
+	limiter    *rate.Limiter
+	lastAccess int64 // unix nano
+}
+
+// clientLimiters throttles the requests of each client of a volume on the meta node,
+// so that one client cannot starve the meta node for the other clients.
+type clientLimiters struct {
+	limiters  sync.Map // volume/client ip -> *clientLimiter
+	lastEvict int64    // unix nano
+}
+
+// allow reports whether a request of the client is allowed under the qps limit, 0 means no limit.
+func (l *clientLimiters) allow(volName, clientIP string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return true
+	}
+	l.evictIdle(now)
+	key := volName + "/" + clientIP
+	val, ok := l.limiters.Load(key)
+	if !ok {
+		val, _ = l.limiters.LoadOrStore(key, &clientLimiter{limiter: rate.NewLimiter(rate.Limit(limit), limit)})
+	}
+	cl := val.(*clientLimiter)
+	atomic.StoreInt64(&cl.lastAccess, now.UnixNano())
+	if cl.limiter.Limit() != rate.Limit(limit) {
+		cl.limiter.SetLimitAt(now, rate.Limit(limit))
+		cl.limiter.SetBurstAt(now, limit)
+	}
+	return cl.limiter.AllowN(now, 1)
+}
+
+func (l *clientLimiters) evictIdle(now time.Time) {
+	last := atomic.LoadInt64(&l.lastEvict)
+	if now.UnixNano()-last < int64(clientLimiterIdleTime) || !atomic.CompareAndSwapInt64(&l.lastEvict, last, now.UnixNano()) {
+		return
+	}
+	l.limiters.Range(func(key, val interface{}) bool {
+		if now.UnixNano()-atomic.LoadInt64(&val.(*clientLimiter).lastAccess) > int64(clientLimiterIdleTime) {
+			l.limiters.Delete(key)
+		}
+		return true
+	})
+}
+
+// allowClientRequest checks the request against the qps limit of its client, the requests
+// proxied by the other replicas of the partition have been checked on them.
+func (m *metadataManager) allowClientRequest(conn net.Conn, mp MetaPartition) bool {
+	limit := mp.ClientQpsLimit()
+	if limit <= 0 || conn == nil || conn.RemoteAddr() == nil {
+		return true
+	}
+	clientIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return true
+	}
+	conf := mp.GetBaseConfig()
+	for _, peer := range conf.Peers {
+		if peerIP, _, err := net.SplitHostPort(peer.Addr); err == nil && peerIP == clientIP {
+			return true
+		}
+	}
+	return m.clientLimiters.allow(conf.VolName, clientIP, limit, time.Now())
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLimiters(t *testing.T) {
+	l := &clientLimiters{}
+	now := time.Now()
+
+	for i := 0; i < 100; i++ {
+		require.True(t, l.allow("vol", "192.168.0.1", 0, now))
+	}
+
+	for i := 0; i < 10; i++ {
+		require.True(t, l.allow("vol", "192.168.0.1", 10, now))
+	}
+	require.False(t, l.allow("vol", "192.168.0.1", 10, now))
+	// other clients and volumes are not affected
+	require.True(t, l.allow("vol", "192.168.0.2", 10, now))
+	require.True(t, l.allow("vol1", "192.168.0.1", 10, now))
+	// tokens are refilled over time
+	require.True(t, l.allow("vol", "192.168.0.1", 10, now.Add(200*time.Millisecond)))
+
+	// a raised limit takes effect
+	now = now.Add(time.Second)
+	require.True(t, l.allow("vol", "192.168.0.1", 20, now))
+	now = now.Add(time.Second)
+	for i := 0; i < 20; i++ {
+		require.True(t, l.allow("vol", "192.168.0.1", 20, now))
+	}
+	require.False(t, l.allow("vol", "192.168.0.1", 20, now))
+
+	// idle limiters are released
+	now = now.Add(2 * clientLimiterIdleTime)
+	require.True(t, l.allow("vol", "192.168.0.2", 10, now))
+	_, ok := l.limiters.Load("vol/192.168.0.1")
+	require.False(t, ok)
+	_, ok = l.limiters.Load("vol/192.168.0.2")
+	require.True(t, ok)
+}
@@ -187,11 +187,21 @@ const (
 	opFSMStoreTickV1  = 72
 
 	opFSMVerListSnapShot = 73
+
+	opFSMImportSnapshotBatch   = 74
+	opFSMUpdateAccessTimeBatch = 75
+	opFSMTrashDentry           = 76
+	opFSMRestoreTrashDentry    = 77
+	opFSMInodeTransition       = 78
+	opFSMMarkTrashPurge        = 79
+	opFSMPurgeTrash            = 80
 )
 
 var (
 	ErrNoLeader   = errors.New("no leader")
 	ErrNotALeader = errors.New("not a leader")
+
+	ErrClientThrottled = errors.New("client requests exceed qps limit of volume, try again later")
 )
 
 // Default configuration
@@ -220,9 +230,17 @@ const (
 	cfgRetainLogs                = "retainLogs"                // string, raft RetainLogs
 	cfgRaftSyncSnapFormatVersion = "raftSyncSnapFormatVersion" // int, format version of snapshot that raft leader sent to follower
 	cfgServiceIDKey              = "serviceIDKey"
+	cfgEnableXAttrIndex          = "enableXAttrIndex"       // bool, index user xattrs for searching
+	cfgSnapshotStoreEndpoint     = "snapshotStoreEndpoint"  // string, object store of s3:// snapshot paths
+	cfgSnapshotStoreRegion       = "snapshotStoreRegion"    // string
+	cfgSnapshotStoreAccessKey    = "snapshotStoreAccessKey" // string
+	cfgSnapshotStoreSecretKey    = "snapshotStoreSecretKey" // string
+	cfgAtimeGranularity          = "atimeGranularity"       // int, seconds, granularity of access time tracking, 0 disables it
 
-	metaNodeDeleteBatchCountKey = "batchCount"
-	configNameResolveInterval   = "nameResolveInterval" // int
+	metaNodeDeleteBatchCountKey    = "batchCount"
+	metaNodeDeleteWorkerSleepMsKey = "deleteWorkerSleepMs"
+	metaNodeDirChildrenLimitKey    = "dirChildrenNumLimit"
+	configNameResolveInterval      = "nameResolveInterval" // int
 )
 
 const (
@@ -51,6 +51,12 @@ type Vol struct {
 	sync.RWMutex
 	dataPartitionView map[uint64]*DataPartition
 	volDeleteLockTime int64
+	// max number of children of a directory stored inline, 0 disables it
+	inlineDirThreshold int
+	// retention of trash in minutes, 0 disables the trash
+	trashInterval int64
+	// max qps of each client on the meta node, 0 means no limit
+	clientQpsLimit int
 }
 
 // NewVol returns a new volume instance.
@@ -0,0 +1,386 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cubefs/cubefs/util/btree"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	inlineDentryFixedSize = 2 + 8 + 4
+	// number of dentries scanned in each round of compaction
+	inlineDirCompactBatch = 100000
+)
+
+// inlineDir is a small directory whose dentries are packed into one btree item,
+// which takes much less memory than a btree item for each dentry.
+type inlineDir struct {
+	parentId uint64
+	count    uint32
+	// dentries ordered by name, each one is encoded as nameLen(uint16) | name | inode(uint64) | type(uint32)
+	data []byte
+}
+
+func newInlineDir(parentId uint64, dentries []*Dentry) *inlineDir {
+	size := 0
+	for _, den := range dentries {
+		size += inlineDentryFixedSize + len(den.Name)
+	}
+	dir := &inlineDir{parentId: parentId, count: uint32(len(dentries)), data: make([]byte, 0, size)}
+	for _, den := range dentries {
+		dir.data = binary.BigEndian.AppendUint16(dir.data, uint16(len(den.Name)))
+		dir.data = append(dir.data, den.Name...)
+		dir.data = binary.BigEndian.AppendUint64(dir.data, den.Inode)
+		dir.data = binary.BigEndian.AppendUint32(dir.data, den.Type)
+	}
+	return dir
+}
+
+func (d *inlineDir) Less(than BtreeItem) bool {
+	dir, ok := than.(*inlineDir)
+	return ok && d.parentId < dir.parentId
+}
+
+func (d *inlineDir) Copy() BtreeItem {
+	dir := *d
+	return &dir
+}
+
+// rangeDentries decodes the dentries in order of name, the data is never modified once packed.
+func (d *inlineDir) rangeDentries(fn func(den *Dentry) bool) {
+	for off := 0; off < len(d.data); {
+		nameLen := int(binary.BigEndian.Uint16(d.data[off:]))
+		off += 2
+		den := &Dentry{ParentId: d.parentId, Name: string(d.data[off : off+nameLen])}
+		off += nameLen
+		den.Inode = binary.BigEndian.Uint64(d.data[off:])
+		den.Type = binary.BigEndian.Uint32(d.data[off+8:])
+		off += 12
+		if !fn(den) {
+			return
+		}
+	}
+}
+
+func (d *inlineDir) get(name string) (found *Dentry) {
+	d.rangeDentries(func(den *Dentry) bool {
+		if den.Name == name {
+			found = den
+		}
+		return found == nil && den.Name < name
+	})
+	return
+}
+
+// DentryTree is the btree of dentries, the dentries of small directories may be packed inline
+// by compaction. Lookups and scans decode the inline directories transparently, while
+// a directory is expanded to regular dentries before any of its dentries are modified.
+type DentryTree struct {
+	sync.RWMutex   // makes packing and expanding atomic to snapshots of the tree
+	dentries       *BTree
+	inline         *BTree
+	inlineDirs     int64
+	inlineDentries int64
+	compactCursor  uint64 // parent id to continue compaction from, only accessed by apply
+}
+
+// NewDentryTree creates a new dentry tree.
+func NewDentryTree() *DentryTree {
+	return &DentryTree{
+		dentries: NewBtree(),
+		inline:   NewBtree(),
+	}
+}
+
+func (t *DentryTree) getInline(parentId uint64) *inlineDir {
+	if atomic.LoadInt64(&t.inlineDirs) == 0 {
+		return nil
+	}
+	if item := t.inline.Get(&inlineDir{parentId: parentId}); item != nil {
+		return item.(*inlineDir)
+	}
+	return nil
+}
+
+// Get returns the dentry of the given key, dentries of inline directories are decoded
+// copies and must not be modified.
+func (t *DentryTree) Get(key BtreeItem) BtreeItem {
+	den := key.(*Dentry)
+	if dir := t.getInline(den.ParentId); dir != nil {
+		if found := dir.get(den.Name); found != nil {
+			return found
+		}
+		return nil
+	}
+	return t.dentries.Get(key)
+}
+
+func (t *DentryTree) CopyGet(key BtreeItem) BtreeItem {
+	t.expand(key.(*Dentry).ParentId)
+	return t.dentries.CopyGet(key)
+}
+
+func (t *DentryTree) CopyFind(key BtreeItem, fn func(i BtreeItem)) {
+	t.expand(key.(*Dentry).ParentId)
+	t.dentries.CopyFind(key, fn)
+}
+
+func (t *DentryTree) Delete(key BtreeItem) BtreeItem {
+	t.expand(key.(*Dentry).ParentId)
+	return t.dentries.Delete(key)
+}
+
+func (t *DentryTree) ReplaceOrInsert(key BtreeItem, replace bool) (BtreeItem, bool) {
+	t.expand(key.(*Dentry).ParentId)
+	return t.dentries.ReplaceOrInsert(key, replace)
+}
+
+// Execute runs fn on the regular dentries, the directory must be expanded in advance.
+func (t *DentryTree) Execute(fn func(tree *btree.BTree) interface{}) interface{} {
+	return t.dentries.Execute(fn)
+}
+
+// Ascend scans all the dentries in order.
+func (t *DentryTree) Ascend(fn func(i BtreeItem) bool) {
+	t.ascend(nil, nil, fn)
+}
+
+// AscendRange scans the dentries in [greaterOrEqual, lessThan) in order.
+func (t *DentryTree) AscendRange(greaterOrEqual, lessThan BtreeItem, fn func(i BtreeItem) bool) {
+	t.ascend(greaterOrEqual.(*Dentry), lessThan.(*Dentry), fn)
+}
+
+// ascend merges the regular dentries and the inline directories in order.
+func (t *DentryTree) ascend(ge, lt *Dentry, fn func(i BtreeItem) bool) {
+	if atomic.LoadInt64(&t.inlineDirs) == 0 {
+		if ge == nil {
+			t.dentries.Ascend(fn)
+		} else {
+			t.dentries.AscendRange(ge, lt, fn)
+		}
+		return
+	}
+	var (
+		nextInline uint64 // parent id to look for the next inline directory from
+		exhausted  bool
+		lastInline uint64
+		visited    bool
+		stopped    bool
+	)
+	if ge != nil {
+		nextInline = ge.ParentId
+	}
+	inRange := func(den *Dentry) bool {
+		return (ge == nil || !den.Less(ge)) && (lt == nil || den.Less(lt))
+	}
+	// visit the inline directories with parent id not larger than upTo
+	visitInline := func(upTo uint64) bool {
+		if exhausted || nextInline > upTo {
+			return true
+		}
+		exhausted = true
+		t.inline.AscendGreaterOrEqual(&inlineDir{parentId: nextInline}, func(i BtreeItem) bool {
+			dir := i.(*inlineDir)
+			if dir.parentId > upTo {
+				nextInline, exhausted = dir.parentId, false
+				return false
+			}
+			lastInline, visited = dir.parentId, true
+			dir.rangeDentries(func(den *Dentry) bool {
+				stopped = inRange(den) && !fn(den)
+				return !stopped
+			})
+			return !stopped
+		})
+		return !stopped
+	}
+	iterator := func(i BtreeItem) bool {
+		den := i.(*Dentry)
+		if !visitInline(den.ParentId) {
+			return false
+		}
+		if visited && lastInline == den.ParentId {
+			// the directory is being packed or expanded, it has been visited inline
+			return true
+		}
+		return fn(i)
+	}
+	if ge == nil {
+		t.dentries.Ascend(iterator)
+	} else {
+		t.dentries.AscendRange(ge, lt, iterator)
+	}
+	if stopped {
+		return
+	}
+	upTo := uint64(math.MaxUint64)
+	if lt != nil {
+		upTo = lt.ParentId
+	}
+	visitInline(upTo)
+}
+
+// GetTree returns the snapshot of the dentry tree.
+func (t *DentryTree) GetTree() *DentryTree {
+	t.RLock()
+	defer t.RUnlock()
+	return &DentryTree{
+		dentries:       t.dentries.GetTree(),
+		inline:         t.inline.GetTree(),
+		inlineDirs:     atomic.LoadInt64(&t.inlineDirs),
+		inlineDentries: atomic.LoadInt64(&t.inlineDentries),
+	}
+}
+
+// Reset resets the dentry tree.
+func (t *DentryTree) Reset() {
+	t.Lock()
+	defer t.Unlock()
+	t.dentries.Reset()
+	t.inline.Reset()
+	atomic.StoreInt64(&t.inlineDirs, 0)
+	atomic.StoreInt64(&t.inlineDentries, 0)
+}
+
+// Len returns the number of dentries, including the ones of inline directories.
+func (t *DentryTree) Len() int {
+	t.RLock()
+	defer t.RUnlock()
+	return t.dentries.Len() + int(atomic.LoadInt64(&t.inlineDentries))
+}
+
+// InlineLen returns the number of inline directories and the dentries in them.
+func (t *DentryTree) InlineLen() (dirs, dentries int) {
+	return int(atomic.LoadInt64(&t.inlineDirs)), int(atomic.LoadInt64(&t.inlineDentries))
+}
+
+// pack moves the dentries of the directory inline, the regular dentries are removed
+// only after the inline directory is visible, so readers always see the whole directory.
+func (t *DentryTree) pack(parentId uint64, dentries []*Dentry) {
+	dir := newInlineDir(parentId, dentries)
+	t.Lock()
+	defer t.Unlock()
+	t.inline.ReplaceOrInsert(dir, true)
+	atomic.AddInt64(&t.inlineDirs, 1)
+	atomic.AddInt64(&t.inlineDentries, int64(dir.count))
+	for _, den := range dentries {
+		t.dentries.Delete(den)
+	}
+}
+
+// expand moves the dentries of an inline directory back to regular ones.
+func (t *DentryTree) expand(parentId uint64) {
+	dir := t.getInline(parentId)
+	if dir == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	dir.rangeDentries(func(den *Dentry) bool {
+		t.dentries.ReplaceOrInsert(den, true)
+		return true
+	})
+	t.inline.Delete(dir)
+	atomic.AddInt64(&t.inlineDirs, -1)
+	atomic.AddInt64(&t.inlineDentries, -int64(dir.count))
+}
+
+// compact packs the directories with at most threshold dentries, it scans a batch of
+// dentries in each round and continues from where the last round stops.
+func (t *DentryTree) compact(threshold int) (packed int) {
+	var (
+		candidates [][]*Dentry
+		children   []*Dentry
+		parentId   uint64
+		packable   bool
+		scanned    int
+		stopped    bool
+	)
+	flush := func() {
+		if packable && len(children) > 0 {
+			candidates = append(candidates, children)
+		}
+	}
+	t.dentries.AscendGreaterOrEqual(&Dentry{ParentId: t.compactCursor}, func(i BtreeItem) bool {
+		den := i.(*Dentry)
+		if scanned == 0 || den.ParentId != parentId {
+			flush()
+			if scanned >= inlineDirCompactBatch {
+				t.compactCursor, stopped = den.ParentId, true
+				return false
+			}
+			parentId, children, packable = den.ParentId, nil, true
+		}
+		scanned++
+		if !packable {
+			return true
+		}
+		// dentries with snapshot versions are never packed
+		if den.multiSnap != nil || len(children) >= threshold {
+			children, packable = nil, false
+			return true
+		}
+		children = append(children, den)
+		return true
+	})
+	if !stopped {
+		flush()
+		t.compactCursor = 0
+	}
+	for _, dentries := range candidates {
+		t.pack(dentries[0].ParentId, dentries)
+	}
+	return len(candidates)
+}
+
+// expandBatch expands at most limit inline directories.
+func (t *DentryTree) expandBatch(limit int) (expanded int) {
+	if atomic.LoadInt64(&t.inlineDirs) == 0 {
+		return
+	}
+	parentIds := make([]uint64, 0)
+	t.inline.Ascend(func(i BtreeItem) bool {
+		parentIds = append(parentIds, i.(*inlineDir).parentId)
+		return len(parentIds) < limit
+	})
+	for _, parentId := range parentIds {
+		t.expand(parentId)
+	}
+	return len(parentIds)
+}
+
+// compactInlineDirs packs small directories according to the threshold of the volume,
+// or expands them if it is disabled. It runs in apply, so that it never races with
+// the modification of dentries.
+func (mp *metaPartition) compactInlineDirs() {
+	threshold := mp.vol.inlineDirThreshold
+	if threshold <= 0 || mp.verSeq > 0 {
+		if expanded := mp.dentryTree.expandBatch(inlineDirCompactBatch); expanded > 0 {
+			log.LogInfof("[compactInlineDirs] mp(%v) expand %v inline dirs", mp.config.PartitionId, expanded)
+		}
+		return
+	}
+	if packed := mp.dentryTree.compact(threshold); packed > 0 {
+		dirs, dentries := mp.dentryTree.InlineLen()
+		log.LogInfof("[compactInlineDirs] mp(%v) pack %v dirs, inline dirs(%v) dentries(%v)",
+			mp.config.PartitionId, packed, dirs, dentries)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectDentries(t *DentryTree, ge, lt *Dentry) (names []string) {
+	names = make([]string, 0)
+	fn := func(i BtreeItem) bool {
+		den := i.(*Dentry)
+		names = append(names, fmt.Sprintf("%v/%v", den.ParentId, den.Name))
+		return true
+	}
+	if ge == nil {
+		t.Ascend(fn)
+	} else {
+		t.AscendRange(ge, lt, fn)
+	}
+	return
+}
+
+func TestDentryTreeInlineDirs(t *testing.T) {
+	tree := NewDentryTree()
+	// parent 1 and 3 are small, parent 2 is large
+	for parent, count := range map[uint64]int{1: 2, 2: 4, 3: 1} {
+		for i := 0; i < count; i++ {
+			den := &Dentry{ParentId: parent, Name: fmt.Sprintf("f%v", i), Inode: parent*100 + uint64(i), Type: 0o644}
+			_, ok := tree.ReplaceOrInsert(den, false)
+			require.True(t, ok)
+		}
+	}
+	all := collectDentries(tree, nil, nil)
+	require.Len(t, all, 7)
+
+	require.Equal(t, 2, tree.compact(3))
+	dirs, dentries := tree.InlineLen()
+	require.Equal(t, 2, dirs)
+	require.Equal(t, 3, dentries)
+	require.Equal(t, 7, tree.Len())
+	require.Equal(t, 4, tree.dentries.Len())
+
+	// scans and lookups are not affected
+	require.Equal(t, all, collectDentries(tree, nil, nil))
+	require.Equal(t, []string{"1/f1", "2/f0", "2/f1"},
+		collectDentries(tree, &Dentry{ParentId: 1, Name: "f1"}, &Dentry{ParentId: 2, Name: "f2"}))
+	require.Equal(t, []string{"3/f0"}, collectDentries(tree, &Dentry{ParentId: 3}, &Dentry{ParentId: 4}))
+	den := tree.Get(&Dentry{ParentId: 1, Name: "f1"})
+	require.NotNil(t, den)
+	require.Equal(t, uint64(101), den.(*Dentry).Inode)
+	require.Nil(t, tree.Get(&Dentry{ParentId: 1, Name: "f2"}))
+
+	// snapshots keep the inline dirs
+	snap := tree.GetTree()
+	require.Equal(t, all, collectDentries(snap, nil, nil))
+
+	// a directory is expanded once modified
+	_, ok := tree.ReplaceOrInsert(&Dentry{ParentId: 1, Name: "f2", Inode: 102}, false)
+	require.True(t, ok)
+	dirs, dentries = tree.InlineLen()
+	require.Equal(t, 1, dirs)
+	require.Equal(t, 1, dentries)
+	require.Equal(t, 8, tree.Len())
+	require.Equal(t, 7, snap.Len())
+	require.NotNil(t, tree.Delete(&Dentry{ParentId: 3, Name: "f0"}))
+	require.Equal(t, []string{"1/f0", "1/f1", "1/f2", "2/f0", "2/f1", "2/f2", "2/f3"}, collectDentries(tree, nil, nil))
+
+	// dentries with snapshot versions are never packed
+	tree.compactCursor = 0
+	den = tree.Get(&Dentry{ParentId: 1, Name: "f0"})
+	den.(*Dentry).addVersion(1)
+	require.Equal(t, 0, tree.compact(3))
+
+	require.Equal(t, 2, snap.expandBatch(10))
+	require.Equal(t, 7, snap.dentries.Len())
+	require.Equal(t, all, collectDentries(snap, nil, nil))
+}
@@ -1823,6 +1823,8 @@ func (i *Inode) SetAttr(req *SetattrRequest) {
 	}
 	if req.Valid&proto.AttrModifyTime != 0 {
 		i.ModifyTime = req.ModifyTime
+		// the extents may be overwritten in place, which is invisible to the meta node
+		i.Generation++
 	}
 
 	i.Unlock()
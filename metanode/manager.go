@@ -90,6 +90,7 @@ type metadataManager struct {
 	stopC                chan struct{}
 	volUpdating          *sync.Map // map[string]*verOp2Phase
 	verUpdateChan        chan string
+	clientLimiters       clientLimiters
 }
 
 func (m *metadataManager) getPacketLabels(p *Packet) (labels map[string]string) {
@@ -155,12 +156,20 @@ func (m *metadataManager) HandleMetadataOperation(conn net.Conn, p *Packet, remo
 		err = m.opMetaEvictInode(conn, p, remoteAddr)
 	case proto.OpMetaBatchEvictInode:
 		err = m.opBatchMetaEvictInode(conn, p, remoteAddr)
+	case proto.OpMetaBatchRecordAccess:
+		err = m.opMetaBatchRecordAccess(conn, p, remoteAddr)
 	case proto.OpMetaSetattr:
 		err = m.opSetAttr(conn, p, remoteAddr)
 	case proto.OpMetaCreateDentry:
 		err = m.opCreateDentry(conn, p, remoteAddr)
 	case proto.OpMetaDeleteDentry:
 		err = m.opDeleteDentry(conn, p, remoteAddr)
+	case proto.OpMetaListTrash:
+		err = m.opMetaListTrash(conn, p, remoteAddr)
+	case proto.OpMetaRestoreTrash:
+		err = m.opMetaRestoreTrash(conn, p, remoteAddr)
+	case proto.OpMetaInodeTransition:
+		err = m.opMetaInodeTransition(conn, p, remoteAddr)
 	case proto.OpMetaBatchDeleteDentry:
 		err = m.opBatchDeleteDentry(conn, p, remoteAddr)
 	case proto.OpMetaUpdateDentry:
@@ -275,6 +284,10 @@ func (m *metadataManager) HandleMetadataOperation(conn net.Conn, p *Packet, remo
 		err = m.opMetaBatchDeleteInodeQuota(conn, p, remoteAddr)
 	case proto.OpMetaGetInodeQuota:
 		err = m.opMetaGetInodeQuota(conn, p, remoteAddr)
+	case proto.OpMetaGetChangeEvents:
+		err = m.opMetaGetChangeEvents(conn, p, remoteAddr)
+	case proto.OpMetaSearchXAttr:
+		err = m.opMetaSearchXAttr(conn, p, remoteAddr)
 	case proto.OpQuotaCreateInode:
 		err = m.opQuotaCreateInode(conn, p, remoteAddr)
 	case proto.OpQuotaCreateDentry:
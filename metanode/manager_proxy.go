@@ -54,6 +54,7 @@ func (m *metadataManager) IsForbiddenOp(mp MetaPartition, reqOp uint8) bool {
 		proto.OpMetaExtentAddWithCheck,
 		proto.OpMetaObjExtentAdd,
 		proto.OpMetaBatchObjExtentsAdd,
+		proto.OpMetaInodeTransition,
 		proto.OpMetaBatchExtentsAdd,
 		proto.OpMetaExtentsDel,
 		// inode
@@ -105,7 +106,14 @@ func (m *metadataManager) serveProxy(conn net.Conn, mp MetaPartition,
 		return false
 	}
 
+	if !m.allowClientRequest(conn, mp) {
+		p.PacketErrorWithBody(proto.OpThrottledErr, []byte(ErrClientThrottled.Error()))
+		m.respondToClient(conn, p)
+		return false
+	}
+
 	if leaderAddr, ok = mp.IsLeader(); ok {
+		mp.RecordRequest()
 		return
 	}
 	if leaderAddr == "" {
@@ -237,6 +237,38 @@ func (mqMgr *MetaQuotaManager) getQuotaReportInfos() (infos []*proto.QuotaReport
 	return
 }
 
+// getQuotaUsedInfos returns used info of quotas limited on the partition,
+// statistics are merged without being flushed, unlike getQuotaReportInfos.
+func (mqMgr *MetaQuotaManager) getQuotaUsedInfos() (infos []*proto.QuotaReportInfo) {
+	mqMgr.rwlock.RLock()
+	defer mqMgr.rwlock.RUnlock()
+	usedInfos := make(map[uint32]proto.QuotaUsedInfo)
+	mqMgr.statisticBase.Range(func(key, value interface{}) bool {
+		usedInfos[key.(uint32)] = value.(proto.QuotaUsedInfo)
+		return true
+	})
+	mqMgr.statisticTemp.Range(func(key, value interface{}) bool {
+		usedInfo := usedInfos[key.(uint32)]
+		tempInfo := value.(proto.QuotaUsedInfo)
+		usedInfo.Add(&tempInfo)
+		usedInfos[key.(uint32)] = usedInfo
+		return true
+	})
+	for quotaId, usedInfo := range usedInfos {
+		if _, ok := mqMgr.limitedMap.Load(quotaId); !ok {
+			continue
+		}
+		if usedInfo.UsedFiles < 0 {
+			usedInfo.UsedFiles = 0
+		}
+		if usedInfo.UsedBytes < 0 {
+			usedInfo.UsedBytes = 0
+		}
+		infos = append(infos, &proto.QuotaReportInfo{QuotaId: quotaId, UsedInfo: usedInfo})
+	}
+	return
+}
+
 func (mqMgr *MetaQuotaManager) statisticRebuildStart() bool {
 	mqMgr.rwlock.Lock()
 	defer mqMgr.rwlock.Unlock()
@@ -45,6 +45,8 @@ var (
 	smuxPortShift  int
 	smuxPool       *util.SmuxConnectPool
 	smuxPoolCfg    = util.DefaultSmuxConnPoolConfig()
+	// enableXAttrIndex indexes user xattrs of meta partitions for searching
+	enableXAttrIndex bool
 )
 
 // The MetaNode manages the dentry and inode information of the meta partitions on a meta node.
@@ -215,6 +217,19 @@ func (m *MetaNode) parseConfig(cfg *config.Config) (err error) {
 	}
 
 	m.serviceIDKey = cfg.GetString(cfgServiceIDKey)
+	enableXAttrIndex = cfg.GetBool(cfgEnableXAttrIndex)
+	if atimeGranularity = cfg.GetInt64(cfgAtimeGranularity); atimeGranularity < 0 {
+		return fmt.Errorf("%v, invalid %v(%v)", proto.ErrInvalidCfg, cfgAtimeGranularity, atimeGranularity)
+	}
+	snapshotStoreCfg = snapshotStoreConfig{
+		endpoint:  cfg.GetString(cfgSnapshotStoreEndpoint),
+		region:    cfg.GetString(cfgSnapshotStoreRegion),
+		accessKey: cfg.GetString(cfgSnapshotStoreAccessKey),
+		secretKey: cfg.GetString(cfgSnapshotStoreSecretKey),
+	}
+	if snapshotStoreCfg.region == "" {
+		snapshotStoreCfg.region = "default"
+	}
 
 	total, _, err := util.GetMemInfo()
 	if err != nil {
@@ -67,7 +67,7 @@ var tlog *testing.T
 func newPartition(conf *MetaPartitionConfig, manager *metadataManager) (mp *metaPartition) {
 	mp = &metaPartition{
 		config:        conf,
-		dentryTree:    NewBtree(),
+		dentryTree:    NewDentryTree(),
 		inodeTree:     NewBtree(),
 		extendTree:    NewBtree(),
 		multipartTree: NewBtree(),
@@ -130,6 +130,7 @@ type OpInode interface {
 	UnlinkInode(req *UnlinkInoReq, p *Packet, remoteAddr string) (err error)
 	UnlinkInodeBatch(req *BatchUnlinkInoReq, p *Packet, remoteAddr string) (err error)
 	InodeGet(req *InodeGetReq, p *Packet) (err error)
+	RecordAccess(req *proto.BatchRecordAccessRequest, p *Packet) (err error)
 	InodeGetSplitEk(req *InodeGetSplitReq, p *Packet) (err error)
 	InodeGetBatch(req *InodeGetReqBatch, p *Packet) (err error)
 	CreateInodeLink(req *LinkInodeReq, p *Packet, remoteAddr string) (err error)
@@ -156,6 +157,7 @@ type OpExtend interface {
 	RemoveXAttr(req *proto.RemoveXAttrRequest, p *Packet) (err error)
 	ListXAttr(req *proto.ListXAttrRequest, p *Packet) (err error)
 	UpdateXAttr(req *proto.UpdateXAttrRequest, p *Packet) (err error)
+	SearchXAttr(req *proto.SearchXAttrRequest, p *Packet) (err error)
 }
 
 // OpDentry defines the interface for the dentry operations.
@@ -168,8 +170,11 @@ type OpDentry interface {
 	ReadDirLimit(req *ReadDirLimitReq, p *Packet) (err error)
 	ReadDirOnly(req *ReadDirOnlyReq, p *Packet) (err error)
 	Lookup(req *LookupReq, p *Packet) (err error)
-	GetDentryTree() *BTree
+	GetDentryTree() *DentryTree
 	GetDentryTreeLen() int
+	ListTrash(req *proto.ListTrashRequest, p *Packet) (err error)
+	RestoreTrash(req *proto.RestoreTrashRequest, p *Packet) (err error)
+	InodeTransition(req *proto.InodeTransitionRequest, p *Packet) (err error)
 	TxCreateDentry(req *proto.TxCreateDentryRequest, p *Packet, remoteAddr string) (err error)
 	TxDeleteDentry(req *proto.TxDeleteDentryRequest, p *Packet, remoteAddr string) (err error)
 	TxUpdateDentry(req *proto.TxUpdateDentryRequest, p *Packet, remoteAddr string) (err error)
@@ -260,6 +265,10 @@ type OpPartition interface {
 	CanRemoveRaftMember(peer proto.Peer) error
 	IsEquareCreateMetaPartitionRequst(request *proto.CreateMetaPartitionRequest) (err error)
 	GetUniqID(p *Packet, num uint32) (err error)
+	GetChangeEvents(from uint64, limit int) *proto.ChangeEvents
+	ExportSnapshot(path string) (manifest *SnapshotManifest, err error)
+	ImportSnapshot(path string) (err error)
+	GetSnapshotImportStatus() *SnapshotImportStatus
 }
 
 // MetaPartition defines the interface for the meta partition operations.
@@ -268,6 +277,9 @@ type MetaPartition interface {
 	Stop()
 	DataSize() uint64
 	GetFreeListLen() int
+	RecordRequest()
+	RequestQps() uint64
+	ClientQpsLimit() int
 	OpMeta
 	LoadSnapshot(path string) error
 	ForceSetMetaPartitionToLoadding()
@@ -462,6 +474,7 @@ func (uMgr *UidManager) accumInoUidSize(ino *Inode, accum *sync.Map) {
 type OpQuota interface {
 	setQuotaHbInfo(infos []*proto.QuotaHeartBeatInfo)
 	getQuotaReportInfos() (infos []*proto.QuotaReportInfo)
+	getQuotaUsedInfos() (infos []*proto.QuotaReportInfo)
 	batchSetInodeQuota(req *proto.BatchSetMetaserverQuotaReuqest,
 		resp *proto.BatchSetMetaserverQuotaResponse) (err error)
 	batchDeleteInodeQuota(req *proto.BatchDeleteMetaserverQuotaReuqest,
@@ -481,9 +494,12 @@ type metaPartition struct {
 	size                   uint64                // For partition all file size
 	applyID                uint64                // Inode/Dentry max applyID, this index will be update after restoring from the dumped data.
 	storedApplyId          uint64                // update after store snapshot to disk
-	dentryTree             *BTree                // btree for dentries
+	dentryTree             *DentryTree           // btree for dentries
 	inodeTree              *BTree                // btree for inodes
 	extendTree             *BTree                // btree for inode extend (XAttr) management
+	xattrIndex             *xattrIndex           // secondary index of user xattrs, nil if disabled
+	atimeTracker           *accessTimeTracker    // access time reported by clients, persisted lazily
+	reqStat                requestStat           // requests served by the leader
 	multipartTree          *BTree                // collection for multipart management
 	txProcessor            *TransactionProcessor // transction processor
 	raftPartition          raftstore.Partition
@@ -510,6 +526,10 @@ type metaPartition struct {
 	multiVersionList       *proto.VolVersionInfoList
 	verUpdateChan          chan []byte
 	enableAuditLog         bool
+	changeLog              *changeLog   // namespace mutations for change data capture
+	snapshotLock           sync.RWMutex // protects the snapshot directory from being replaced when exporting
+	snapshotImportLock     sync.Mutex
+	snapshotImport         *SnapshotImportStatus
 }
 
 func (mp *metaPartition) IsForbidden() bool {
@@ -611,6 +631,18 @@ func (mp *metaPartition) GetFreeListLen() int {
 	return mp.freeList.Len()
 }
 
+func (mp *metaPartition) RecordRequest() {
+	mp.reqStat.add()
+}
+
+func (mp *metaPartition) RequestQps() uint64 {
+	return mp.reqStat.Qps(time.Now())
+}
+
+func (mp *metaPartition) ClientQpsLimit() int {
+	return mp.vol.clientQpsLimit
+}
+
 // Start starts a meta partition.
 func (mp *metaPartition) Start(isCreate bool) (err error) {
 	if atomic.CompareAndSwapUint32(&mp.state, common.StateStandby, common.StateStart) {
@@ -646,6 +678,7 @@ func (mp *metaPartition) Stop() {
 			mp.config.BeforeStop()
 		}
 		mp.onStop()
+		mp.changeLog.close()
 		if mp.config.AfterStop != nil {
 			mp.config.AfterStop()
 			log.LogDebugf("[AfterStop]: partition id=%d execute ok.",
@@ -717,6 +750,9 @@ func (mp *metaPartition) onStart(isCreate bool) (err error) {
 	}
 
 	mp.vol.volDeleteLockTime = volumeInfo.DeleteLockTime
+	mp.vol.inlineDirThreshold = volumeInfo.InlineDirThreshold
+	mp.vol.clientQpsLimit = volumeInfo.ClientQpsLimit
+	mp.vol.trashInterval = volumeInfo.TrashInterval
 
 	go mp.runVersionOp()
 
@@ -776,6 +812,8 @@ func (mp *metaPartition) onStart(isCreate bool) (err error) {
 func (mp *metaPartition) startScheduleTask() {
 	mp.startSchedule(mp.applyID)
 	mp.startFileStats()
+	mp.startAccessTimeFlush()
+	mp.startTrashPurge()
 }
 
 func (mp *metaPartition) onStop() {
@@ -858,7 +896,7 @@ func (mp *metaPartition) getRaftPort() (heartbeat, replica int, err error) {
 func NewMetaPartition(conf *MetaPartitionConfig, manager *metadataManager) MetaPartition {
 	mp := &metaPartition{
 		config:        conf,
-		dentryTree:    NewBtree(),
+		dentryTree:    NewDentryTree(),
 		inodeTree:     NewBtree(),
 		extendTree:    NewBtree(),
 		multipartTree: NewBtree(),
@@ -875,6 +913,11 @@ func NewMetaPartition(conf *MetaPartitionConfig, manager *metadataManager) MetaP
 			TemporaryVerMap: make(map[uint64]*proto.VolVersionInfo),
 		},
 		enableAuditLog: true,
+		changeLog:      newChangeLog(defaultChangeLogCapacity),
+		atimeTracker:   newAccessTimeTracker(),
+	}
+	if enableXAttrIndex {
+		mp.xattrIndex = newXAttrIndex()
 	}
 	mp.txProcessor = NewTransactionProcessor(mp)
 	return mp
@@ -1109,18 +1152,22 @@ func (mp *metaPartition) load(isCreate bool) (err error) {
 		if err = mp.storeSnapshotFiles(); err != nil {
 			err = errors.NewErrorf("[onStart] storeSnapshotFiles for partition id=%d: %s",
 				mp.config.PartitionId, err.Error())
+			return
 		}
-		return
+		return mp.openChangeLog()
 	}
 
 	snapshotPath := path.Join(mp.config.RootDir, snapshotDir)
 	if _, err = os.Stat(snapshotPath); err != nil {
 		log.LogErrorf("load snapshot failed, err: %s", err.Error())
-		return nil
+		return mp.openChangeLog()
 
 	}
 
-	return mp.LoadSnapshot(snapshotPath)
+	if err = mp.LoadSnapshot(snapshotPath); err != nil {
+		return
+	}
+	return mp.openChangeLog()
 }
 
 func (mp *metaPartition) store(sm *storeMsg) (err error) {
@@ -1172,11 +1219,17 @@ func (mp *metaPartition) store(sm *storeMsg) (err error) {
 	if err = mp.storeUniqID(tmpDir, sm); err != nil {
 		return
 	}
+	// events before the snapshot are not rebuilt by raft log replay
+	if err = mp.changeLog.sync(); err != nil {
+		return
+	}
 
 	// write crc to file
 	if err = os.WriteFile(path.Join(tmpDir, SnapshotSign), crcBuffer.Bytes(), 0o775); err != nil {
 		return
 	}
+	mp.snapshotLock.Lock()
+	defer mp.snapshotLock.Unlock()
 	snapshotDir := path.Join(mp.config.RootDir, snapshotDir)
 	// check snapshot backup
 	backupDir := path.Join(mp.config.RootDir, snapshotBackup)
@@ -1686,7 +1739,7 @@ func (mp *metaPartition) storeSnapshotFiles() (err error) {
 		applyIndex:     mp.applyID,
 		txId:           mp.txProcessor.txManager.txIdAlloc.getTransactionID(),
 		inodeTree:      NewBtree(),
-		dentryTree:     NewBtree(),
+		dentryTree:     NewDentryTree(),
 		extendTree:     NewBtree(),
 		multipartTree:  NewBtree(),
 		txTree:         NewBtree(),
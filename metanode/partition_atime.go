@@ -0,0 +1,171 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	accessTimeEntrySize     = 16
+	accessTimeBatchMaxCount = 4096
+)
+
+// atimeGranularity is the granularity of access time tracking in seconds, 0 disables it.
+// The access time of an inode is persisted at most once per granularity.
+var atimeGranularity int64
+
+// accessTimeTracker collects access time of inodes reported by clients on the leader,
+// they are persisted lazily in batches by flushAccessTime.
+type accessTimeTracker struct {
+	sync.Mutex
+	pending map[uint64]int64
+}
+
+func newAccessTimeTracker() *accessTimeTracker {
+	return &accessTimeTracker{pending: make(map[uint64]int64)}
+}
+
+func (t *accessTimeTracker) record(ino uint64, atime int64) {
+	t.Lock()
+	if atime > t.pending[ino] {
+		t.pending[ino] = atime
+	}
+	t.Unlock()
+}
+
+func (t *accessTimeTracker) swap() (pending map[uint64]int64) {
+	t.Lock()
+	pending = t.pending
+	t.pending = make(map[uint64]int64)
+	t.Unlock()
+	return
+}
+
+// RecordAccess records access time of the inodes in memory, it is ignored if tracking is disabled.
+func (mp *metaPartition) RecordAccess(req *proto.BatchRecordAccessRequest, p *Packet) (err error) {
+	if atimeGranularity <= 0 {
+		p.PacketOkReply()
+		return
+	}
+	now := time.Now().Unix()
+	atime := req.AccessTime
+	if atime <= 0 || atime > now {
+		atime = now
+	}
+	for _, ino := range req.Inodes {
+		if ino < mp.config.Start || ino > mp.config.End {
+			continue
+		}
+		mp.atimeTracker.record(ino, atime)
+	}
+	p.PacketOkReply()
+	return
+}
+
+func (mp *metaPartition) startAccessTimeFlush() {
+	if atimeGranularity <= 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(atimeGranularity) * time.Second)
+	go func(stopC chan bool) {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopC:
+				return
+			case <-ticker.C:
+				if err := mp.flushAccessTime(); err != nil {
+					log.LogWarnf("[flushAccessTime] partition(%v) flush access time failed: %v",
+						mp.config.PartitionId, err)
+				}
+			}
+		}
+	}(mp.stopC)
+}
+
+// flushAccessTime submits access time of inodes which are stale for at least the granularity,
+// so that an inode is written at most once per granularity no matter how often it is accessed.
+func (mp *metaPartition) flushAccessTime() (err error) {
+	pending := mp.atimeTracker.swap()
+	if len(pending) == 0 {
+		return
+	}
+	if _, ok := mp.IsLeader(); !ok {
+		// followers drop the records, clients report to the new leader
+		return
+	}
+	data := make([]byte, 0, accessTimeEntrySize*len(pending))
+	count := 0
+	submit := func() error {
+		if count == 0 {
+			return nil
+		}
+		_, submitErr := mp.submit(opFSMUpdateAccessTimeBatch, data)
+		data, count = data[:0], 0
+		return submitErr
+	}
+	entry := make([]byte, accessTimeEntrySize)
+	for ino, atime := range pending {
+		item := mp.inodeTree.Get(NewInode(ino, 0))
+		if item == nil {
+			continue
+		}
+		inode := item.(*Inode)
+		inode.RLock()
+		stale := atime-inode.AccessTime >= atimeGranularity
+		inode.RUnlock()
+		if !stale {
+			continue
+		}
+		binary.BigEndian.PutUint64(entry[:8], ino)
+		binary.BigEndian.PutUint64(entry[8:], uint64(atime))
+		data = append(data, entry...)
+		count++
+		if count >= accessTimeBatchMaxCount {
+			if err = submit(); err != nil {
+				return
+			}
+		}
+	}
+	return submit()
+}
+
+func (mp *metaPartition) fsmUpdateAccessTimeBatch(data []byte) (err error) {
+	if len(data)%accessTimeEntrySize != 0 {
+		return fmt.Errorf("invalid length(%v) of access time batch", len(data))
+	}
+	for offset := 0; offset < len(data); offset += accessTimeEntrySize {
+		ino := binary.BigEndian.Uint64(data[offset : offset+8])
+		atime := int64(binary.BigEndian.Uint64(data[offset+8 : offset+accessTimeEntrySize]))
+		item := mp.inodeTree.CopyGet(NewInode(ino, 0))
+		if item == nil {
+			continue
+		}
+		inode := item.(*Inode)
+		inode.Lock()
+		if atime > inode.AccessTime {
+			inode.AccessTime = atime
+		}
+		inode.Unlock()
+	}
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAccessTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mp := mockPartitionRaftForQuotaTest(ctrl)
+	mp.uidManager = NewUidMgr(VolNameForTest, PartitionIdForTest)
+	mp.config.NodeId = 1
+	mp.config.Start, mp.config.End = 1, 1000
+	mp.atimeTracker = newAccessTimeTracker()
+
+	now := time.Now().Unix()
+	for ino := uint64(1); ino <= 2; ino++ {
+		inode := NewInode(ino, 0o644)
+		inode.AccessTime = now - 10
+		require.Equal(t, proto.OpOk, mp.fsmCreateInode(inode))
+	}
+	getAccessTime := func(ino uint64) int64 {
+		return mp.inodeTree.Get(NewInode(ino, 0)).(*Inode).AccessTime
+	}
+
+	// ignored if tracking is disabled
+	p := &Packet{}
+	require.NoError(t, mp.RecordAccess(&proto.BatchRecordAccessRequest{Inodes: []uint64{1}, AccessTime: now}, p))
+	require.Equal(t, proto.OpOk, p.ResultCode)
+	require.Len(t, mp.atimeTracker.pending, 0)
+
+	defer func(old int64) { atimeGranularity = old }(atimeGranularity)
+	atimeGranularity = 60
+	p = &Packet{}
+	req := &proto.BatchRecordAccessRequest{Inodes: []uint64{1, 2, 2000}, AccessTime: now + 3600}
+	require.NoError(t, mp.RecordAccess(req, p))
+	require.Equal(t, proto.OpOk, p.ResultCode)
+	require.Len(t, mp.atimeTracker.pending, 2)
+	require.LessOrEqual(t, mp.atimeTracker.pending[1], time.Now().Unix())
+
+	// inodes accessed within the granularity are not persisted
+	require.NoError(t, mp.flushAccessTime())
+	require.Equal(t, now-10, getAccessTime(1))
+	require.Len(t, mp.atimeTracker.pending, 0)
+
+	mp.atimeTracker.record(1, now+100)
+	mp.atimeTracker.record(1, now+50)
+	require.NoError(t, mp.flushAccessTime())
+	require.Equal(t, now+100, getAccessTime(1))
+	require.Equal(t, now-10, getAccessTime(2))
+}
@@ -0,0 +1,642 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	defaultChangeLogCapacity = 10000
+	defaultChangeLogSegments = 32
+	defaultChangeEventsLimit = 1000
+
+	changeLogDir           = "changeLog"
+	changeLogMetaFile      = "META"
+	changeLogSegmentPrefix = "segment."
+)
+
+// changeLog keeps change events of meta partition in segment files under
+// the partition directory, the recent ones are cached in a ring buffer.
+// A segment holds about capacity events and the oldest one is removed if
+// there are more than maxSegments segments. Events of one seq are never
+// split into two segments.
+// Events are synced before the snapshot is stored, the ones after the
+// snapshot are rebuilt by raft log replay after restart, so seq goes on
+// across restarts. Events are lost only when the oldest segment is removed
+// or the snapshot is received from the leader.
+// The change log is in memory only if it's not opened.
+type changeLog struct {
+	sync.RWMutex
+	events       []proto.ChangeEvent
+	head         int    // index of the oldest cached event
+	cachedSeq    uint64 // events not greater than cachedSeq are not cached
+	lostSeq      uint64 // events not greater than lostSeq may be lost
+	lastSeq      uint64 // seq of the latest event
+	committedSeq uint64 // events not greater than committedSeq are readable
+	capacity     int
+
+	dir         string
+	maxSegments int
+	segments    []*changeLogSegment
+	active      *os.File // file of the last segment
+	loadedSeq   uint64   // events not greater than loadedSeq are loaded from segments
+}
+
+// changeLogSegment is a file of change events in json lines, named by
+// the seq of its first event.
+type changeLogSegment struct {
+	firstSeq uint64
+	lastSeq  uint64
+	count    int
+	size     int64
+}
+
+func (seg *changeLogSegment) name() string {
+	return fmt.Sprintf("%s%020d", changeLogSegmentPrefix, seg.firstSeq)
+}
+
+type changeLogMeta struct {
+	LostSeq uint64 `json:"lostSeq"`
+}
+
+func newChangeLog(capacity int) *changeLog {
+	if capacity <= 0 {
+		capacity = defaultChangeLogCapacity
+	}
+	return &changeLog{capacity: capacity, maxSegments: defaultChangeLogSegments}
+}
+
+// open loads the segments in dir. Events of the latest seq are dropped
+// if it's greater than applyID, they are rebuilt by raft log replay.
+// If dir doesn't exist, the partition is new or upgraded from the version
+// without change log files, events not greater than applyID are unknown.
+func (l *changeLog) open(dir string, applyID uint64) (err error) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	if _, err = os.Stat(dir); os.IsNotExist(err) {
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return
+		}
+		l.dir = dir
+		if err = l.storeMeta(applyID); err != nil {
+			return
+		}
+		l.events, l.head, l.segments = nil, 0, nil
+		l.lostSeq, l.cachedSeq, l.lastSeq, l.committedSeq, l.loadedSeq = applyID, applyID, applyID, applyID, applyID
+		return
+	} else if err != nil {
+		return
+	}
+
+	l.dir = dir
+	meta := &changeLogMeta{LostSeq: applyID}
+	data, err := os.ReadFile(path.Join(dir, changeLogMetaFile))
+	if os.IsNotExist(err) {
+		// crashed before the meta file is stored
+		err = l.storeMeta(applyID)
+	} else if err == nil {
+		err = json.Unmarshal(data, meta)
+	}
+	if err != nil {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	l.segments = make([]*changeLogSegment, 0)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), changeLogSegmentPrefix) {
+			continue
+		}
+		seq, parseErr := strconv.ParseUint(strings.TrimPrefix(entry.Name(), changeLogSegmentPrefix), 10, 64)
+		if parseErr != nil {
+			log.LogWarnf("changeLog: dir(%v) skip unknown file %v", dir, entry.Name())
+			continue
+		}
+		l.segments = append(l.segments, &changeLogSegment{firstSeq: seq})
+	}
+	sort.Slice(l.segments, func(i, j int) bool { return l.segments[i].firstSeq < l.segments[j].firstSeq })
+
+	l.lostSeq, l.lastSeq = meta.LostSeq, meta.LostSeq
+	if len(l.segments) > 0 {
+		if err = l.loadLastSegment(applyID); err != nil {
+			return
+		}
+	}
+	l.events, l.head = nil, 0
+	l.cachedSeq, l.committedSeq, l.loadedSeq = l.lastSeq, l.lastSeq, l.lastSeq
+	return
+}
+
+// loadLastSegment scans the last segment and truncates the broken tail
+// and the events to be replayed, the segment is opened for appending.
+func (l *changeLog) loadLastSegment(applyID uint64) (err error) {
+	seg := l.segments[len(l.segments)-1]
+	filename := path.Join(l.dir, seg.name())
+	var (
+		lastSeq     uint64
+		count       int
+		groupOffset int64
+		groupCount  int
+		prevSeq     uint64
+	)
+	size, err := readChangeLogSegment(filename, func(event *proto.ChangeEvent, offset int64) bool {
+		if event.Seq != lastSeq {
+			prevSeq, lastSeq = lastSeq, event.Seq
+			groupOffset, groupCount = offset, count
+		}
+		count++
+		return true
+	})
+	if err != nil {
+		return
+	}
+	if lastSeq > applyID {
+		size, count, lastSeq = groupOffset, groupCount, prevSeq
+	}
+	if l.active, err = os.OpenFile(filename, os.O_RDWR|os.O_APPEND, 0o644); err != nil {
+		return
+	}
+	if err = l.active.Truncate(size); err != nil {
+		return
+	}
+	seg.count, seg.size = count, size
+	seg.lastSeq = lastSeq
+	if lastSeq == 0 {
+		// empty segment
+		seg.lastSeq = seg.firstSeq - 1
+	}
+	if seg.lastSeq > l.lastSeq {
+		l.lastSeq = seg.lastSeq
+	}
+	return
+}
+
+// readChangeLogSegment calls fn with events of the segment file in order
+// until it returns false, the broken tail of the file is skipped. It
+// returns the end offset of the last event read.
+func readChangeLogSegment(filename string, fn func(event *proto.ChangeEvent, offset int64) bool) (size int64, err error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer fp.Close()
+	reader := bufio.NewReader(fp)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil {
+			// EOF or the event being written
+			return
+		}
+		event := &proto.ChangeEvent{}
+		if json.Unmarshal(line, event) != nil {
+			log.LogWarnf("readChangeLogSegment: file(%v) broken at offset(%v)", filename, size)
+			return
+		}
+		offset := size
+		size += int64(len(line))
+		if !fn(event, offset) {
+			return
+		}
+	}
+}
+
+func (l *changeLog) storeMeta(lostSeq uint64) (err error) {
+	data, err := json.Marshal(&changeLogMeta{LostSeq: lostSeq})
+	if err != nil {
+		return
+	}
+	filename := path.Join(l.dir, changeLogMetaFile)
+	tmpFile := filename + ".tmp"
+	fp, err := os.OpenFile(tmpFile, os.O_RDWR|os.O_TRUNC|os.O_CREATE, 0o644)
+	if err != nil {
+		return
+	}
+	if _, err = fp.Write(data); err == nil {
+		err = fp.Sync()
+	}
+	fp.Close()
+	if err != nil {
+		return
+	}
+	return os.Rename(tmpFile, filename)
+}
+
+func (l *changeLog) append(event proto.ChangeEvent) {
+	if l == nil {
+		return
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+	l.Lock()
+	defer l.Unlock()
+	if event.Seq <= l.loadedSeq {
+		// replayed from raft log after restart
+		return
+	}
+	if len(l.events) < l.capacity {
+		l.events = append(l.events, event)
+	} else {
+		l.cachedSeq = l.events[l.head].Seq
+		l.events[l.head] = event
+		l.head = (l.head + 1) % l.capacity
+	}
+	l.lastSeq = event.Seq
+	if l.dir == "" {
+		l.lostSeq = l.cachedSeq
+		return
+	}
+	if err := l.persist(event); err != nil {
+		log.LogErrorf("changeLog: dir(%v) persist event(%v) err(%v)", l.dir, event.Seq, err)
+		if l.lostSeq < event.Seq {
+			l.lostSeq = event.Seq
+			if err = l.storeMeta(l.lostSeq); err != nil {
+				log.LogErrorf("changeLog: dir(%v) store lostSeq(%v) err(%v)", l.dir, l.lostSeq, err)
+			}
+		}
+	}
+}
+
+func (l *changeLog) persist(event proto.ChangeEvent) (err error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	var seg *changeLogSegment
+	if len(l.segments) > 0 {
+		seg = l.segments[len(l.segments)-1]
+	}
+	if l.active == nil || (seg.count >= l.capacity && seg.lastSeq != event.Seq) {
+		if seg, err = l.rotate(event.Seq); err != nil {
+			return
+		}
+	}
+	if _, err = l.active.Write(data); err != nil {
+		// drop the partial event, or the events after it are unreadable
+		if truncErr := l.active.Truncate(seg.size); truncErr != nil {
+			log.LogErrorf("changeLog: dir(%v) truncate segment(%v) err(%v)", l.dir, seg.name(), truncErr)
+		}
+		return
+	}
+	seg.count++
+	seg.size += int64(len(data))
+	seg.lastSeq = event.Seq
+	return
+}
+
+// rotate syncs the active segment and creates a new one starting at seq,
+// the oldest segments are removed if there are too many.
+func (l *changeLog) rotate(seq uint64) (seg *changeLogSegment, err error) {
+	if l.active != nil {
+		if err = l.active.Sync(); err != nil {
+			return
+		}
+		l.active.Close()
+		l.active = nil
+	}
+	seg = &changeLogSegment{firstSeq: seq, lastSeq: seq - 1}
+	if l.active, err = os.OpenFile(path.Join(l.dir, seg.name()), os.O_RDWR|os.O_TRUNC|os.O_CREATE|os.O_APPEND, 0o644); err != nil {
+		return
+	}
+	l.segments = append(l.segments, seg)
+	for len(l.segments) > l.maxSegments {
+		// events of the oldest segment are all before the next one
+		lostSeq := l.segments[1].firstSeq - 1
+		if err = l.storeMeta(lostSeq); err != nil {
+			return
+		}
+		l.lostSeq = lostSeq
+		if err = os.Remove(path.Join(l.dir, l.segments[0].name())); err != nil && !os.IsNotExist(err) {
+			return
+		}
+		l.segments = l.segments[1:]
+	}
+	return
+}
+
+// commit makes events not greater than seq readable, it's called after
+// the mutation is applied so that events of one seq are read together.
+func (l *changeLog) commit(seq uint64) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	if seq > l.committedSeq {
+		l.committedSeq = seq
+	}
+	l.Unlock()
+}
+
+// sync flushes the active segment, it's called before the snapshot is
+// stored, the events before the snapshot are not rebuilt after restart.
+func (l *changeLog) sync() (err error) {
+	if l == nil {
+		return
+	}
+	l.RLock()
+	defer l.RUnlock()
+	if l.active != nil {
+		err = l.active.Sync()
+	}
+	return
+}
+
+func (l *changeLog) close() {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	if l.active != nil {
+		l.active.Sync()
+		l.active.Close()
+		l.active = nil
+	}
+	// the partition may be removed, events are kept in memory only
+	l.dir, l.segments = "", nil
+}
+
+// reset drops all events, called when snapshot is applied.
+func (l *changeLog) reset(seq uint64) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.events, l.head = nil, 0
+	l.lostSeq, l.cachedSeq, l.lastSeq, l.committedSeq, l.loadedSeq = seq, seq, seq, seq, seq
+	if l.dir == "" {
+		return
+	}
+	if err := l.storeMeta(seq); err != nil {
+		log.LogErrorf("changeLog: dir(%v) store lostSeq(%v) err(%v)", l.dir, seq, err)
+	}
+	if l.active != nil {
+		l.active.Close()
+		l.active = nil
+	}
+	for _, seg := range l.segments {
+		if err := os.Remove(path.Join(l.dir, seg.name())); err != nil && !os.IsNotExist(err) {
+			log.LogErrorf("changeLog: dir(%v) remove segment(%v) err(%v)", l.dir, seg.name(), err)
+		}
+	}
+	l.segments = nil
+}
+
+// read returns at most limit events with seq greater than from,
+// events with the same seq are never split into two reads.
+func (l *changeLog) read(from uint64, limit int) (events []proto.ChangeEvent, nextSeq uint64, truncated bool) {
+	if limit <= 0 || limit > defaultChangeEventsLimit {
+		limit = defaultChangeEventsLimit
+	}
+	if l == nil {
+		return nil, from, false
+	}
+	l.RLock()
+	if from >= l.cachedSeq || l.dir == "" {
+		defer l.RUnlock()
+		events, nextSeq = l.readCached(from, limit)
+		return events, l.skipLost(from, nextSeq, len(events)), from < l.lostSeq
+	}
+	segments := make([]changeLogSegment, 0, len(l.segments))
+	for _, seg := range l.segments {
+		segments = append(segments, *seg)
+	}
+	committedSeq := l.committedSeq
+	l.RUnlock()
+
+	// segments are read without lock, the lost ones are checked after read
+	events, nextSeq = l.readSegments(segments, from, committedSeq, limit)
+	l.RLock()
+	truncated = from < l.lostSeq
+	nextSeq = l.skipLost(from, nextSeq, len(events))
+	l.RUnlock()
+	return
+}
+
+// skipLost moves nextSeq over the lost events if nothing is read, so the
+// consumer is told about the lost events only once.
+func (l *changeLog) skipLost(from, nextSeq uint64, count int) uint64 {
+	if count == 0 && from < l.lostSeq {
+		return l.lostSeq
+	}
+	return nextSeq
+}
+
+func (l *changeLog) readCached(from uint64, limit int) (events []proto.ChangeEvent, nextSeq uint64) {
+	events = make([]proto.ChangeEvent, 0)
+	nextSeq = from
+	for i := 0; i < len(l.events); i++ {
+		event := l.events[(l.head+i)%len(l.events)]
+		if event.Seq <= from {
+			continue
+		}
+		if event.Seq > l.committedSeq || (len(events) >= limit && event.Seq != nextSeq) {
+			break
+		}
+		events = append(events, event)
+		nextSeq = event.Seq
+	}
+	return
+}
+
+func (l *changeLog) readSegments(segments []changeLogSegment, from, committedSeq uint64, limit int) (events []proto.ChangeEvent, nextSeq uint64) {
+	events = make([]proto.ChangeEvent, 0)
+	nextSeq = from
+	done := false
+	for i := 0; i < len(segments) && !done; i++ {
+		if i+1 < len(segments) && segments[i+1].firstSeq <= from+1 {
+			// all events of the segment are not greater than from
+			continue
+		}
+		filename := path.Join(l.dir, segments[i].name())
+		_, err := readChangeLogSegment(filename, func(event *proto.ChangeEvent, offset int64) bool {
+			if event.Seq <= from {
+				return true
+			}
+			if event.Seq > committedSeq || (len(events) >= limit && event.Seq != nextSeq) {
+				done = true
+				return false
+			}
+			events = append(events, *event)
+			nextSeq = event.Seq
+			return true
+		})
+		if err != nil && !os.IsNotExist(err) {
+			log.LogWarnf("changeLog: read segment(%v) err(%v)", filename, err)
+			break
+		}
+	}
+	return
+}
+
+// GetChangeEvents returns change events with seq greater than from.
+func (mp *metaPartition) GetChangeEvents(from uint64, limit int) *proto.ChangeEvents {
+	events, nextSeq, truncated := mp.changeLog.read(from, limit)
+	return &proto.ChangeEvents{
+		PartitionID: mp.config.PartitionId,
+		Events:      events,
+		NextSeq:     nextSeq,
+		Truncated:   truncated,
+	}
+}
+
+func (mp *metaPartition) openChangeLog() error {
+	return mp.changeLog.open(path.Join(mp.config.RootDir, changeLogDir), mp.applyID)
+}
+
+func (mp *metaPartition) inodeChangeInfo(ino uint64) *proto.InodeInfo {
+	item := mp.inodeTree.Get(NewInode(ino, 0))
+	if item == nil {
+		return nil
+	}
+	info := &proto.InodeInfo{}
+	replyInfoNoCheck(info, item.(*Inode))
+	return info
+}
+
+func (mp *metaPartition) recordDentryChange(seq uint64, typ string, den *Dentry, oldIno uint64) {
+	if mp.changeLog == nil {
+		return
+	}
+	mp.changeLog.append(proto.ChangeEvent{
+		Seq:      seq,
+		Type:     typ,
+		ParentID: den.ParentId,
+		Name:     den.Name,
+		Inode:    den.Inode,
+		OldInode: oldIno,
+		Info:     &proto.InodeInfo{Inode: den.Inode, Mode: den.Type},
+	})
+}
+
+func (mp *metaPartition) recordInodeChange(seq uint64, typ string, ino uint64) {
+	if mp.changeLog == nil {
+		return
+	}
+	mp.changeLog.append(proto.ChangeEvent{
+		Seq:   seq,
+		Type:  typ,
+		Inode: ino,
+		Info:  mp.inodeChangeInfo(ino),
+	})
+}
+
+// txRollbackChange is a mutation of transaction in meta partition which
+// is reverted if the transaction is rolled back.
+type txRollbackChange struct {
+	rbDentry *TxRollbackDentry
+	rbInode  *TxRollbackInode
+	dentry   *Dentry // dentry after rollback, rbDentry is modified by rollback
+	oldIno   uint64  // inode of the dentry before rollback
+	exist    bool    // whether the inode exists before rollback
+}
+
+// txRollbackChanges returns the mutations of the transaction in this
+// partition, it's called before the transaction is rolled back.
+func (mp *metaPartition) txRollbackChanges(txInfo *proto.TransactionInfo) (changes []txRollbackChange) {
+	if mp.changeLog == nil {
+		return
+	}
+	tr := mp.txProcessor.txResource
+	tr.Lock()
+	defer tr.Unlock()
+	for _, ifo := range txInfo.TxInodeInfos {
+		if ifo.MpID != mp.config.PartitionId {
+			continue
+		}
+		if rbInode := tr.getTxRbInode(ifo.Ino); rbInode != nil && rbInode.txInodeInfo.TxID == ifo.TxID {
+			changes = append(changes, txRollbackChange{
+				rbInode: rbInode,
+				exist:   mp.inodeTree.Get(NewInode(ifo.Ino, 0)) != nil,
+			})
+		}
+	}
+	for _, ifo := range txInfo.TxDentryInfos {
+		if ifo.MpID != mp.config.PartitionId {
+			continue
+		}
+		if rbDentry := tr.getTxRbDentry(ifo.ParentId, ifo.Name); rbDentry != nil && rbDentry.txDentryInfo.TxID == ifo.TxID {
+			den := rbDentry.dentry
+			change := txRollbackChange{
+				rbDentry: rbDentry,
+				dentry:   &Dentry{ParentId: den.ParentId, Name: den.Name, Inode: den.Inode, Type: den.Type},
+			}
+			if item := mp.dentryTree.Get(&Dentry{ParentId: ifo.ParentId, Name: ifo.Name}); item != nil {
+				change.oldIno = item.(*Dentry).Inode
+			}
+			changes = append(changes, change)
+		}
+	}
+	return
+}
+
+// recordTxRollbackChange records the reverted mutations of the rolled
+// back transaction, the ones failed to roll back are skipped.
+func (mp *metaPartition) recordTxRollbackChange(seq uint64, changes []txRollbackChange) {
+	tr := mp.txProcessor.txResource
+	for _, change := range changes {
+		if rbDentry := change.rbDentry; rbDentry != nil {
+			den := change.dentry
+			if tr.getTxRbDentry(den.ParentId, den.Name) == rbDentry {
+				continue
+			}
+			switch rbDentry.rbType {
+			case TxAdd:
+				mp.recordDentryChange(seq, proto.ChangeEventCreate, den, 0)
+			case TxDelete:
+				mp.recordDentryChange(seq, proto.ChangeEventDelete, den, 0)
+			case TxUpdate:
+				mp.recordDentryChange(seq, proto.ChangeEventRename, den, change.oldIno)
+			}
+			continue
+		}
+
+		rbInode := change.rbInode
+		if tr.getTxRbInode(rbInode.inode.Inode) == rbInode {
+			continue
+		}
+		switch rbInode.rbType {
+		case TxAdd:
+			if change.exist {
+				mp.recordInodeChange(seq, proto.ChangeEventAttr, rbInode.inode.Inode)
+			} else {
+				mp.recordInodeChange(seq, proto.ChangeEventInodeCreate, rbInode.inode.Inode)
+			}
+		case TxDelete:
+			mp.recordInodeChange(seq, proto.ChangeEventInodeEvict, rbInode.inode.Inode)
+		}
+	}
+}
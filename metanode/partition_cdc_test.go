@@ -0,0 +1,229 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeLog(t *testing.T) {
+	var nilLog *changeLog
+	nilLog.append(proto.ChangeEvent{Seq: 1})
+	events, next, truncated := nilLog.read(5, 0)
+	require.Empty(t, events)
+	require.Equal(t, uint64(5), next)
+	require.False(t, truncated)
+
+	l := newChangeLog(4)
+	l.reset(10)
+	for seq := uint64(11); seq <= 13; seq++ {
+		l.append(proto.ChangeEvent{Seq: seq, Type: proto.ChangeEventCreate, Inode: seq})
+	}
+	// events are readable after committed
+	events, next, _ = l.read(10, 0)
+	require.Empty(t, events)
+	require.Equal(t, uint64(10), next)
+	l.commit(13)
+	_, _, truncated = l.read(0, 0)
+	require.True(t, truncated)
+	events, next, truncated = l.read(10, 0)
+	require.False(t, truncated)
+	require.Len(t, events, 3)
+	require.Equal(t, uint64(13), next)
+	require.NotZero(t, events[0].Timestamp)
+
+	events, next, _ = l.read(11, 1)
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(12), events[0].Seq)
+	require.Equal(t, uint64(12), next)
+
+	// ring evicts the oldest events
+	for seq := uint64(14); seq <= 16; seq++ {
+		l.append(proto.ChangeEvent{Seq: seq, Type: proto.ChangeEventDelete, Inode: seq})
+	}
+	l.commit(16)
+	_, _, truncated = l.read(11, 0)
+	require.True(t, truncated)
+	events, next, truncated = l.read(12, 0)
+	require.False(t, truncated)
+	require.Len(t, events, 4)
+	for i, event := range events {
+		require.Equal(t, uint64(13+i), event.Seq)
+	}
+	require.Equal(t, uint64(16), next)
+
+	events, next, truncated = l.read(16, 0)
+	require.Empty(t, events)
+	require.Equal(t, uint64(16), next)
+	require.False(t, truncated)
+
+	// events before snapshot are lost
+	l.reset(20)
+	_, _, truncated = l.read(16, 0)
+	require.True(t, truncated)
+	l.append(proto.ChangeEvent{Seq: 21, Type: proto.ChangeEventAttr, Inode: 1})
+	l.commit(21)
+	events, next, truncated = l.read(20, 0)
+	require.False(t, truncated)
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(21), next)
+
+	// events of one batch mutation are read together
+	l.append(proto.ChangeEvent{Seq: 22, Type: proto.ChangeEventDelete, Inode: 2})
+	l.append(proto.ChangeEvent{Seq: 22, Type: proto.ChangeEventDelete, Inode: 3})
+	l.append(proto.ChangeEvent{Seq: 23, Type: proto.ChangeEventDelete, Inode: 4})
+	l.commit(23)
+	events, next, _ = l.read(21, 1)
+	require.Len(t, events, 2)
+	require.Equal(t, uint64(22), next)
+}
+
+func TestChangeLogSegments(t *testing.T) {
+	dir := path.Join(t.TempDir(), changeLogDir)
+	appendEvents := func(l *changeLog, from, to uint64) {
+		for seq := from; seq <= to; seq++ {
+			l.append(proto.ChangeEvent{Seq: seq, Type: proto.ChangeEventCreate, Inode: seq})
+			l.commit(seq)
+		}
+	}
+	requireEvents := func(l *changeLog, from, first, last uint64, truncated bool) {
+		events, next, trunc := l.read(from, 0)
+		require.Equal(t, truncated, trunc)
+		require.Len(t, events, int(last-first+1))
+		for i, event := range events {
+			require.Equal(t, first+uint64(i), event.Seq)
+			require.Equal(t, first+uint64(i), event.Inode)
+		}
+		require.Equal(t, last, next)
+	}
+
+	// events of the upgraded partition before the applied index are unknown
+	l := newChangeLog(4)
+	require.NoError(t, l.open(dir, 10))
+	appendEvents(l, 11, 30)
+	require.Len(t, l.segments, 5)
+	// evicted from cache, read from segments
+	requireEvents(l, 10, 11, 30, false)
+	_, _, truncated := l.read(9, 0)
+	require.True(t, truncated)
+
+	// the oldest segments are removed
+	l.maxSegments = 3
+	appendEvents(l, 31, 33)
+	require.Len(t, l.segments, 3)
+	requireEvents(l, 21, 23, 33, true)
+	requireEvents(l, 22, 23, 33, false)
+	l.close()
+
+	// events after the applied index are replayed from raft log
+	l = newChangeLog(4)
+	require.NoError(t, l.open(dir, 32))
+	requireEvents(l, 23, 24, 32, false)
+	appendEvents(l, 20, 32)
+	requireEvents(l, 30, 31, 32, false)
+	appendEvents(l, 33, 34)
+	requireEvents(l, 23, 24, 34, false)
+
+	// the broken tail is dropped
+	fp, err := os.OpenFile(path.Join(dir, l.segments[len(l.segments)-1].name()), os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = fp.WriteString(`{"seq":35,"ty`)
+	require.NoError(t, err)
+	require.NoError(t, fp.Close())
+	l.close()
+	l = newChangeLog(4)
+	require.NoError(t, l.open(dir, 34))
+	appendEvents(l, 35, 36)
+	requireEvents(l, 23, 24, 36, false)
+
+	// events before the received snapshot are lost
+	l.reset(40)
+	_, _, truncated = l.read(36, 0)
+	require.True(t, truncated)
+	appendEvents(l, 41, 41)
+	l.close()
+	l = newChangeLog(4)
+	require.NoError(t, l.open(dir, 41))
+	require.Len(t, l.segments, 1)
+	requireEvents(l, 40, 41, 41, false)
+	_, _, truncated = l.read(36, 0)
+	require.True(t, truncated)
+}
+
+func TestTxChangeEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mp := mockPartitionRaftForQuotaTest(ctrl)
+	mp.uidManager = NewUidMgr(VolNameForTest, PartitionIdForTest)
+	dirMode := proto.Mode(os.ModeDir | 0o755)
+	require.Equal(t, proto.OpOk, mp.fsmCreateInode(NewInode(1, dirMode)))
+	require.Equal(t, proto.OpOk, mp.fsmCreateDentry(&Dentry{ParentId: 1, Name: "a", Inode: 2, Type: 0o644}, false))
+
+	newTx := func(name string) *proto.TransactionInfo {
+		txInfo := proto.NewTransactionInfo(5, proto.TxTypeCreate)
+		txInfo.TmID = int64(mp.config.PartitionId)
+		ifo := proto.NewTxDentryInfo("", 1, name, mp.config.PartitionId)
+		txInfo.TxDentryInfos[ifo.GetKey()] = ifo
+		require.NoError(t, mp.initTxInfo(txInfo))
+		require.Equal(t, proto.OpOk, mp.fsmTxInit(txInfo))
+		return txInfo
+	}
+	submit := func(op uint32, item interface{ Marshal() ([]byte, error) }) {
+		val, err := item.Marshal()
+		require.NoError(t, err)
+		_, err = mp.submit(op, val)
+		require.NoError(t, err)
+	}
+	lastSeq := uint64(0)
+	requireEvents := func(expected ...proto.ChangeEvent) {
+		events := mp.GetChangeEvents(lastSeq, 0)
+		require.Len(t, events.Events, len(expected))
+		for i, event := range events.Events {
+			require.Equal(t, expected[i].Type, event.Type)
+			require.Equal(t, expected[i].Name, event.Name)
+			require.Equal(t, expected[i].Inode, event.Inode)
+			require.Equal(t, expected[i].OldInode, event.OldInode)
+		}
+		lastSeq = events.NextSeq
+	}
+
+	txInfo := newTx("b")
+	submit(opFSMTxCreateDentry, NewTxDentry(1, "b", 3, 0o644, nil, txInfo))
+	requireEvents(proto.ChangeEvent{Type: proto.ChangeEventCreate, Name: "b", Inode: 3})
+	submit(opFSMTxRollbackRM, txInfo)
+	requireEvents(proto.ChangeEvent{Type: proto.ChangeEventDelete, Name: "b", Inode: 3})
+
+	txInfo = newTx("a")
+	submit(opFSMTxUpdateDentry, NewTxUpdateDentry(&Dentry{ParentId: 1, Name: "a", Inode: 2},
+		&Dentry{ParentId: 1, Name: "a", Inode: 4}, txInfo))
+	requireEvents(proto.ChangeEvent{Type: proto.ChangeEventRename, Name: "a", Inode: 4, OldInode: 2})
+	submit(opFSMTxRollbackRM, txInfo)
+	requireEvents(proto.ChangeEvent{Type: proto.ChangeEventRename, Name: "a", Inode: 2, OldInode: 4})
+
+	txInfo = newTx("a")
+	submit(opFSMTxDeleteDentry, NewTxDentry(1, "a", 2, 0o644, nil, txInfo))
+	requireEvents(proto.ChangeEvent{Type: proto.ChangeEventDelete, Name: "a", Inode: 2})
+	// rolled back only once
+	submit(opFSMTxRollbackRM, txInfo)
+	submit(opFSMTxRollbackRM, txInfo)
+	requireEvents(proto.ChangeEvent{Type: proto.ChangeEventCreate, Name: "a", Inode: 2})
+	require.NotNil(t, mp.dentryTree.Get(&Dentry{ParentId: 1, Name: "a"}))
+}
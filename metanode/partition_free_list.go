@@ -83,6 +83,9 @@ func (mp *metaPartition) updateVolView(convert func(view *proto.DataPartitionsVi
 		return
 	}
 	mp.vol.volDeleteLockTime = volView.DeleteLockTime
+	mp.vol.inlineDirThreshold = volView.InlineDirThreshold
+	mp.vol.clientQpsLimit = volView.ClientQpsLimit
+	mp.vol.trashInterval = volView.TrashInterval
 	return nil
 }
 
@@ -315,6 +318,13 @@ func (mp *metaPartition) deleteMarkedInodes(inoSlice []uint64) {
 			mp.freeList.Push(inode.Inode)
 		}
 		allInodes = shouldCommit
+	} else {
+		// the data of the inodes transitioned by the lifecycle is in the blobstore
+		shouldCommit, shouldRePushToFreeList = mp.deleteTransitionedObjExtents(allInodes)
+		for _, inode := range shouldRePushToFreeList {
+			mp.freeList.Push(inode.Inode)
+		}
+		allInodes = shouldCommit
 	}
 	log.LogInfof("[deleteMarkedInodes] metaPartition(%v) deleteExtentsByPartition(%v) allInodes(%v)",
 		mp.config.PartitionId, deleteExtentsByPartition, allInodes)
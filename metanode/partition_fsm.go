@@ -38,6 +38,7 @@ import (
 func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{}, err error) {
 	msg := &MetaItem{}
 	defer func() {
+		mp.changeLog.commit(index)
 		if err == nil {
 			mp.uploadApplyID(index)
 		}
@@ -58,7 +59,11 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 		if mp.config.Cursor < ino.Inode {
 			mp.config.Cursor = ino.Inode
 		}
-		resp = mp.fsmCreateInode(ino)
+		status := mp.fsmCreateInode(ino)
+		if status == proto.OpOk {
+			mp.recordInodeChange(index, proto.ChangeEventInodeCreate, ino.Inode)
+		}
+		resp = status
 	case opFSMCreateInodeQuota:
 		qinode := &MetaQuotaInode{}
 		if err = qinode.Unmarshal(msg.V); err != nil {
@@ -76,6 +81,7 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			for _, quotaId := range qinode.quotaIds {
 				mp.mqMgr.updateUsedInfo(0, 1, quotaId)
 			}
+			mp.recordInodeChange(index, proto.ChangeEventInodeCreate, ino.Inode)
 		}
 	case opFSMUnlinkInode:
 		ino := NewInode(0, 0)
@@ -88,7 +94,11 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			resp = &InodeResponse{Status: status}
 			return
 		}
-		resp = mp.fsmUnlinkInode(ino, 0)
+		inoResp := mp.fsmUnlinkInode(ino, 0)
+		if inoResp.Status == proto.OpOk {
+			mp.recordInodeChange(index, proto.ChangeEventInodeUnlink, ino.Inode)
+		}
+		resp = inoResp
 	case opFSMUnlinkInodeOnce:
 		var inoOnce *InodeOnce
 		if inoOnce, err = InodeOnceUnmarshal(msg.V); err != nil {
@@ -96,13 +106,23 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 		}
 		ino := NewInode(inoOnce.Inode, 0)
 		ino.setVer(inoOnce.VerSeq)
-		resp = mp.fsmUnlinkInode(ino, inoOnce.UniqID)
+		inoResp := mp.fsmUnlinkInode(ino, inoOnce.UniqID)
+		if inoResp.Status == proto.OpOk {
+			mp.recordInodeChange(index, proto.ChangeEventInodeUnlink, ino.Inode)
+		}
+		resp = inoResp
 	case opFSMUnlinkInodeBatch:
 		inodes, err := InodeBatchUnmarshal(msg.V)
 		if err != nil {
 			return nil, err
 		}
-		resp = mp.fsmUnlinkInodeBatch(inodes)
+		inoResps := mp.fsmUnlinkInodeBatch(inodes)
+		for i, inoResp := range inoResps {
+			if inoResp.Status == proto.OpOk {
+				mp.recordInodeChange(index, proto.ChangeEventInodeUnlink, inodes[i].Inode)
+			}
+		}
+		resp = inoResps
 	case opFSMExtentTruncate:
 		ino := NewInode(0, 0)
 		if err = ino.Unmarshal(msg.V); err != nil {
@@ -137,20 +157,32 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			resp = &InodeResponse{Status: status}
 			return
 		}
-		resp = mp.fsmEvictInode(ino)
+		inoResp := mp.fsmEvictInode(ino)
+		if inoResp.Status == proto.OpOk {
+			mp.recordInodeChange(index, proto.ChangeEventInodeEvict, ino.Inode)
+		}
+		resp = inoResp
 	case opFSMEvictInodeBatch:
 		inodes, err := InodeBatchUnmarshal(msg.V)
 		if err != nil {
 			return nil, err
 		}
-		resp = mp.fsmBatchEvictInode(inodes)
+		inoResps := mp.fsmBatchEvictInode(inodes)
+		for i, inoResp := range inoResps {
+			if inoResp.Status == proto.OpOk {
+				mp.recordInodeChange(index, proto.ChangeEventInodeEvict, inodes[i].Inode)
+			}
+		}
+		resp = inoResps
 	case opFSMSetAttr:
 		req := &SetattrRequest{}
 		err = json.Unmarshal(msg.V, req)
 		if err != nil {
 			return
 		}
-		err = mp.fsmSetAttr(req)
+		if err = mp.fsmSetAttr(req); err == nil {
+			mp.recordInodeChange(index, proto.ChangeEventAttr, req.Inode)
+		}
 	case opFSMCreateDentry:
 		den := &Dentry{}
 		if err = den.Unmarshal(msg.V); err != nil {
@@ -163,7 +195,11 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			return
 		}
 
-		resp = mp.fsmCreateDentry(den, false)
+		status = mp.fsmCreateDentry(den, false)
+		if status == proto.OpOk {
+			mp.recordDentryChange(index, proto.ChangeEventCreate, den, 0)
+		}
+		resp = status
 	case opFSMDeleteDentry:
 		den := &Dentry{}
 		if err = den.Unmarshal(msg.V); err != nil {
@@ -176,13 +212,23 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			return
 		}
 
-		resp = mp.fsmDeleteDentry(den, false)
+		denResp := mp.fsmDeleteDentry(den, false)
+		if denResp.Status == proto.OpOk {
+			mp.recordDentryChange(index, proto.ChangeEventDelete, den, 0)
+		}
+		resp = denResp
 	case opFSMDeleteDentryBatch:
 		db, err := DentryBatchUnmarshal(msg.V)
 		if err != nil {
 			return nil, err
 		}
-		resp = mp.fsmBatchDeleteDentry(db)
+		denResps := mp.fsmBatchDeleteDentry(db)
+		for i, denResp := range denResps {
+			if denResp.Status == proto.OpOk {
+				mp.recordDentryChange(index, proto.ChangeEventDelete, db[i], 0)
+			}
+		}
+		resp = denResps
 	case opFSMUpdateDentry:
 		den := &Dentry{}
 		if err = den.Unmarshal(msg.V); err != nil {
@@ -195,7 +241,14 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			return
 		}
 
-		resp = mp.fsmUpdateDentry(den)
+		newIno := den.Inode
+		denResp := mp.fsmUpdateDentry(den)
+		if denResp.Status == proto.OpOk && denResp.Msg != nil {
+			// den.Inode is swapped to the old inode after updated
+			mp.recordDentryChange(index, proto.ChangeEventRename,
+				&Dentry{ParentId: den.ParentId, Name: den.Name, Inode: newIno, Type: den.Type}, den.Inode)
+		}
+		resp = denResp
 	case opFSMUpdatePartition:
 		req := &UpdatePartitionReq{}
 		if err = json.Unmarshal(msg.V, req); err != nil {
@@ -270,8 +323,60 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 		}
 		log.LogDebugf("opFSMStoreTick: quotaRebuild [%v] uidRebuild [%v]", quotaRebuild, uidRebuild)
 		mp.storeChan <- msg
+		mp.compactInlineDirs()
 	case opFSMInternalDeleteInode:
 		err = mp.internalDelete(msg.V)
+	case opFSMImportSnapshotBatch:
+		err = mp.fsmImportSnapshotBatch(msg.V)
+	case opFSMUpdateAccessTimeBatch:
+		err = mp.fsmUpdateAccessTimeBatch(msg.V)
+	case opFSMTrashDentry:
+		req := &trashDentryReq{}
+		if err = json.Unmarshal(msg.V, req); err != nil {
+			return
+		}
+		status := mp.dentryInTx(req.ParentId, req.Name)
+		if status != proto.OpOk {
+			resp = status
+			return
+		}
+		denResp := mp.fsmTrashDentry(req)
+		if denResp.Status == proto.OpOk {
+			mp.recordDentryChange(index, proto.ChangeEventDelete, denResp.Msg, 0)
+		}
+		resp = denResp
+	case opFSMRestoreTrashDentry:
+		req := &restoreTrashReq{}
+		if err = json.Unmarshal(msg.V, req); err != nil {
+			return
+		}
+		den, status := mp.fsmRestoreTrashDentry(req)
+		if status == proto.OpOk {
+			mp.recordDentryChange(index, proto.ChangeEventCreate, den, 0)
+		}
+		resp = status
+	case opFSMMarkTrashPurge:
+		req := &markTrashPurgeReq{}
+		if err = json.Unmarshal(msg.V, req); err != nil {
+			return
+		}
+		resp = mp.fsmMarkTrashPurge(req)
+	case opFSMPurgeTrash:
+		req := &purgeTrashReq{}
+		if err = json.Unmarshal(msg.V, req); err != nil {
+			return
+		}
+		den, status := mp.fsmPurgeTrash(req)
+		if status == proto.OpOk {
+			mp.recordDentryChange(index, proto.ChangeEventTrashPurge, den, 0)
+		}
+		resp = status
+	case opFSMInodeTransition:
+		req := &proto.InodeTransitionRequest{}
+		if err = json.Unmarshal(msg.V, req); err != nil {
+			return
+		}
+		resp = mp.fsmInodeTransition(req)
 	case opFSMInternalDeleteInodeBatch:
 		err = mp.internalDeleteBatch(msg.V)
 	case opFSMInternalDelExtentFile:
@@ -334,7 +439,11 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 		if mp.config.Cursor < txIno.Inode.Inode {
 			mp.config.Cursor = txIno.Inode.Inode
 		}
-		resp = mp.fsmTxCreateInode(txIno, []uint32{})
+		status := mp.fsmTxCreateInode(txIno, []uint32{})
+		if status == proto.OpOk {
+			mp.recordInodeChange(index, proto.ChangeEventInodeCreate, txIno.Inode.Inode)
+		}
+		resp = status
 	case opFSMTxCreateInodeQuota:
 		qinode := &TxMetaQuotaInode{}
 		if err = qinode.Unmarshal(msg.V); err != nil {
@@ -352,13 +461,18 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 			for _, quotaId := range qinode.quotaIds {
 				mp.mqMgr.updateUsedInfo(0, 1, quotaId)
 			}
+			mp.recordInodeChange(index, proto.ChangeEventInodeCreate, txIno.Inode.Inode)
 		}
 	case opFSMTxCreateDentry:
 		txDen := NewTxDentry(0, "", 0, 0, nil, nil)
 		if err = txDen.Unmarshal(msg.V); err != nil {
 			return
 		}
-		resp = mp.fsmTxCreateDentry(txDen)
+		status := mp.fsmTxCreateDentry(txDen)
+		if status == proto.OpOk {
+			mp.recordDentryChange(index, proto.ChangeEventCreate, txDen.Dentry, 0)
+		}
+		resp = status
 	case opFSMTxSetState:
 		req := &proto.TxSetStateRequest{}
 		if err = json.Unmarshal(msg.V, req); err != nil {
@@ -376,7 +490,9 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 		if err = req.Unmarshal(msg.V); err != nil {
 			return
 		}
+		changes := mp.txRollbackChanges(req)
 		resp = mp.fsmTxRollbackRM(req)
+		mp.recordTxRollbackChange(index, changes)
 	case opFSMTxCommit:
 		req := &proto.TxApplyRequest{}
 		if err = json.Unmarshal(msg.V, req); err != nil {
@@ -400,20 +516,37 @@ func (mp *metaPartition) Apply(command []byte, index uint64) (resp interface{},
 		if err = txDen.Unmarshal(msg.V); err != nil {
 			return
 		}
-		resp = mp.fsmTxDeleteDentry(txDen)
+		denResp := mp.fsmTxDeleteDentry(txDen)
+		// Msg is empty if the dentry is deleted by the retried request before
+		if denResp.Status == proto.OpOk && denResp.Msg.Inode != 0 {
+			mp.recordDentryChange(index, proto.ChangeEventDelete, denResp.Msg, 0)
+		}
+		resp = denResp
 	case opFSMTxUnlinkInode:
 		txIno := NewTxInode(0, 0, nil)
 		if err = txIno.Unmarshal(msg.V); err != nil {
 			return
 		}
-		resp = mp.fsmTxUnlinkInode(txIno)
+		inoResp := mp.fsmTxUnlinkInode(txIno)
+		if inoResp.Status == proto.OpOk {
+			mp.recordInodeChange(index, proto.ChangeEventInodeUnlink, txIno.Inode.Inode)
+		}
+		resp = inoResp
 	case opFSMTxUpdateDentry:
 		// txDen := NewTxDentry(0, "", 0, 0, nil)
 		txUpdateDen := NewTxUpdateDentry(nil, nil, nil)
 		if err = txUpdateDen.Unmarshal(msg.V); err != nil {
 			return
 		}
-		resp = mp.fsmTxUpdateDentry(txUpdateDen)
+		newIno := txUpdateDen.NewDentry.Inode
+		denResp := mp.fsmTxUpdateDentry(txUpdateDen)
+		if denResp.Status == proto.OpOk && denResp.Msg.Inode != 0 {
+			// Msg is swapped to the old inode after updated
+			oldDen := txUpdateDen.OldDentry
+			mp.recordDentryChange(index, proto.ChangeEventRename,
+				&Dentry{ParentId: oldDen.ParentId, Name: oldDen.Name, Inode: newIno, Type: oldDen.Type}, denResp.Msg.Inode)
+		}
+		resp = denResp
 	case opFSMTxCreateLinkInode:
 		txIno := NewTxInode(0, 0, nil)
 		if err = txIno.Unmarshal(msg.V); err != nil {
@@ -614,7 +747,7 @@ func (mp *metaPartition) ApplySnapshot(peers []raftproto.Peer, iter raftproto.Sn
 		uniqID         uint64
 		cursor         uint64
 		inodeTree      = NewBtree()
-		dentryTree     = NewBtree()
+		dentryTree     = NewDentryTree()
 		extendTree     = NewBtree()
 		multipartTree  = NewBtree()
 		txTree         = NewBtree()
@@ -670,6 +803,7 @@ func (mp *metaPartition) ApplySnapshot(peers []raftproto.Peer, iter raftproto.Sn
 			mp.inodeTree = inodeTree
 			mp.dentryTree = dentryTree
 			mp.extendTree = extendTree
+			mp.rebuildXAttrIndex()
 			mp.multipartTree = multipartTree
 			mp.config.Cursor = cursor
 			mp.txProcessor.txManager.txTree = txTree
@@ -679,6 +813,7 @@ func (mp *metaPartition) ApplySnapshot(peers []raftproto.Peer, iter raftproto.Sn
 			mp.multiVersionList.VerList = make([]*proto.VolVersionInfo, len(verList))
 			copy(mp.multiVersionList.VerList, verList)
 			mp.verSeq = mp.multiVersionList.GetLastVer()
+			mp.changeLog.reset(appIndexID)
 			log.LogInfof("mp[%v] updateVerList (%v) seq [%v]", mp.config.PartitionId, mp.multiVersionList.VerList, mp.verSeq)
 			err = nil
 			// store message
@@ -228,6 +228,8 @@ func (mp *metaPartition) fsmDeleteDentry(denParm *Dentry, checkInode bool) (resp
 		doMore   = true
 		clean    bool
 	)
+	// dentries of the directory are modified in place below
+	mp.dentryTree.expand(denParm.ParentId)
 	if checkInode {
 		log.LogDebugf("action[fsmDeleteDentry] mp[%v] delete param %v", mp.config.PartitionId, denParm)
 		item = mp.dentryTree.Execute(func(tree *btree.BTree) interface{} {
@@ -242,7 +244,7 @@ func (mp *metaPartition) fsmDeleteDentry(denParm *Dentry, checkInode bool) (resp
 			if mp.verSeq == 0 {
 				log.LogDebugf("action[fsmDeleteDentry] mp[%v] volume snapshot not enabled,delete directly", mp.config.PartitionId)
 				denFound = den
-				return mp.dentryTree.tree.Delete(den)
+				return tree.Delete(den)
 			}
 			denFound, doMore, clean = den.deleteVerSnapshot(denParm.getSeqFiled(), mp.verSeq, mp.GetVerList())
 			return den
@@ -381,7 +383,7 @@ func (mp *metaPartition) fsmUpdateDentry(dentry *Dentry) (resp *DentryResponse)
 	return
 }
 
-func (mp *metaPartition) getDentryTree() *BTree {
+func (mp *metaPartition) getDentryTree() *DentryTree {
 	return mp.dentryTree.GetTree()
 }
 
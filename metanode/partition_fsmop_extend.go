@@ -27,6 +27,7 @@ type ExtendOpResult struct {
 }
 
 func (mp *metaPartition) fsmSetXAttr(extend *Extend) (err error) {
+	defer mp.updateXAttrIndex(extend.inode)
 	extend.verSeq = mp.GetVerSeq()
 	treeItem := mp.extendTree.CopyGet(extend)
 	var e *Extend
@@ -50,6 +51,7 @@ func (mp *metaPartition) fsmSetXAttr(extend *Extend) (err error) {
 
 // todo(leon chang):check snapshot delete relation with attr
 func (mp *metaPartition) fsmRemoveXAttr(reqExtend *Extend) (err error) {
+	defer mp.updateXAttrIndex(reqExtend.inode)
 	treeItem := mp.extendTree.CopyGet(reqExtend)
 	if treeItem == nil {
 		return
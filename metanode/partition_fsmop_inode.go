@@ -416,6 +416,7 @@ func (mp *metaPartition) internalDeleteInode(ino *Inode) {
 	mp.inodeTree.Delete(ino)
 	mp.freeList.Remove(ino.Inode)
 	mp.extendTree.Delete(&Extend{inode: ino.Inode}) // Also delete extend attribute.
+	mp.updateXAttrIndex(ino.Inode)
 }
 
 func (mp *metaPartition) fsmAppendExtents(ino *Inode) (status uint8) {
@@ -429,6 +430,10 @@ func (mp *metaPartition) fsmAppendExtents(ino *Inode) (status uint8) {
 		status = proto.OpNotExistErr
 		return
 	}
+	if mp.isTransitioned(ino2) {
+		status = proto.OpNotPerm
+		return
+	}
 	oldSize := int64(ino2.Size)
 	eks := ino.Extents.CopyExtents()
 	if status = mp.uidManager.addUidSpace(ino2.Uid, ino2.Inode, eks); status != proto.OpOk {
@@ -469,6 +474,10 @@ func (mp *metaPartition) fsmAppendExtentsWithCheck(ino *Inode, isSplit bool) (st
 		status = proto.OpNotExistErr
 		return
 	}
+	if mp.isTransitioned(fsmIno) {
+		status = proto.OpNotPerm
+		return
+	}
 
 	oldSize := int64(fsmIno.Size)
 	eks := ino.Extents.CopyExtents()
@@ -581,6 +590,10 @@ func (mp *metaPartition) fsmExtentsTruncate(ino *Inode) (resp *InodeResponse) {
 		resp.Status = proto.OpArgMismatchErr
 		return
 	}
+	if mp.isTransitioned(i) {
+		resp.Status = proto.OpNotPerm
+		return
+	}
 
 	doOnLastKey := func(lastKey *proto.ExtentKey) {
 		var eks []proto.ExtentKey
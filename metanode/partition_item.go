@@ -142,7 +142,7 @@ type MetaItemIterator struct {
 	txId              uint64
 	cursor            uint64
 	inodeTree         *BTree
-	dentryTree        *BTree
+	dentryTree        *DentryTree
 	extendTree        *BTree
 	multipartTree     *BTree
 	txTree            *BTree
@@ -297,6 +297,9 @@ func (mp *metaPartition) DeleteDentry(req *DeleteDentryReq, p *Packet, remoteAdd
 		p.PacketErrorWithBody(proto.OpErr, []byte(err.Error()))
 		return
 	}
+	if req.Trash && dentry.getSeqFiled() == 0 && mp.trashEnabled() {
+		return mp.moveDentryToTrash(req, p)
+	}
 	log.LogDebugf("action[DeleteDentry] submit!")
 	r, err := mp.submit(opFSMDeleteDentry, val)
 	if err != nil {
@@ -581,7 +584,7 @@ func (mp *metaPartition) Lookup(req *LookupReq, p *Packet) (err error) {
 }
 
 // GetDentryTree returns the dentry tree stored in the meta partition.
-func (mp *metaPartition) GetDentryTree() *BTree {
+func (mp *metaPartition) GetDentryTree() *DentryTree {
 	return mp.dentryTree.GetTree()
 }
 
@@ -242,6 +242,27 @@ func (mp *metaPartition) ListXAttr(req *proto.ListXAttrRequest, p *Packet) (err
 	return
 }
 
+func (mp *metaPartition) SearchXAttr(req *proto.SearchXAttrRequest, p *Packet) (err error) {
+	if mp.xattrIndex == nil {
+		p.PacketErrorWithBody(proto.OpNotPerm, []byte("xattr index is disabled"))
+		return
+	}
+	if !strings.HasPrefix(req.Key, xattrIndexKeyPrefix) {
+		p.PacketErrorWithBody(proto.OpArgMismatchErr, []byte("only xattrs with prefix "+xattrIndexKeyPrefix+" are indexed"))
+		return
+	}
+	response := &proto.SearchXAttrResponse{}
+	response.Items, response.Truncated = mp.xattrIndex.search(req)
+	var encoded []byte
+	encoded, err = json.Marshal(response)
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpErr, []byte(err.Error()))
+		return
+	}
+	p.PacketOkWithBody(encoded)
+	return
+}
+
 func (mp *metaPartition) putExtend(op uint32, extend *Extend) (resp interface{}, err error) {
 	var marshaled []byte
 	if marshaled, err = extend.Bytes(); err != nil {
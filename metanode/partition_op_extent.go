@@ -68,10 +68,16 @@ func (mp *metaPartition) ExtentAppend(req *proto.AppendExtentKeyRequest, p *Pack
 		return
 	}
 	ino := NewInode(req.Inode, 0)
-	if _, _, err = mp.CheckQuota(req.Inode, p); err != nil {
+	var i *Inode
+	if _, i, err = mp.CheckQuota(req.Inode, p); err != nil {
 		log.LogErrorf("ExtentAppend fail status [%v]", err)
 		return
 	}
+	if mp.isTransitioned(i) {
+		err = fmt.Errorf("inode[%v] is transitioned to the blobstore", req.Inode)
+		p.PacketErrorWithBody(proto.OpNotPerm, []byte(err.Error()))
+		return
+	}
 	ext := req.Extent
 	ino.Extents.Append(ext)
 	val, err := ino.Marshal()
@@ -107,6 +113,11 @@ func (mp *metaPartition) ExtentAppendWithCheck(req *proto.AppendExtentKeyWithChe
 		log.LogErrorf("ExtentAppendWithCheck CheckQuota fail err [%v]", err)
 		return
 	}
+	if mp.isTransitioned(i) {
+		err = fmt.Errorf("inode[%v] is transitioned to the blobstore", req.Inode)
+		p.PacketErrorWithBody(proto.OpNotPerm, []byte(err.Error()))
+		return
+	}
 
 	// check volume's Type: if volume's type is cold, cbfs' extent can be modify/add only when objextent exist
 	if proto.IsCold(mp.volType) {
@@ -390,6 +401,11 @@ func (mp *metaPartition) ExtentsList(req *proto.GetExtentsRequest, p *Packet) (e
 		status = retMsg.Status
 	)
 
+	if status == proto.OpOk && mp.isTransitioned(ino) {
+		// the client reads the extents of a hot volume from the data nodes only
+		status = proto.OpErr
+		reply = []byte(fmt.Sprintf("inode[%v] is transitioned to the blobstore", req.Inode))
+	}
 	if status == proto.OpOk {
 		resp := &proto.GetExtentsResponse{}
 		log.LogInfof("action[ExtentsList] inode[%v] request verseq [%v] ino ver [%v] extent size %v ino.Size %v ino[%v] hist len %v",
@@ -484,6 +500,11 @@ func (mp *metaPartition) ExtentsTruncate(req *ExtentsTruncateReq, p *Packet, rem
 		return
 	}
 	i := item.(*Inode)
+	if mp.isTransitioned(i) {
+		err = fmt.Errorf("inode[%v] is transitioned to the blobstore", req.Inode)
+		p.PacketErrorWithBody(proto.OpNotPerm, []byte(err.Error()))
+		return
+	}
 	status := mp.isOverQuota(req.Inode, req.Size > i.Size, false)
 	if status != 0 {
 		log.LogErrorf("ExtentsTruncate fail status [%v]", status)
@@ -518,11 +539,16 @@ func (mp *metaPartition) BatchExtentAppend(req *proto.AppendExtentKeysRequest, p
 		return
 	}
 
-	var ino *Inode
-	if ino, _, err = mp.CheckQuota(req.Inode, p); err != nil {
+	var ino, i *Inode
+	if ino, i, err = mp.CheckQuota(req.Inode, p); err != nil {
 		log.LogErrorf("BatchExtentAppend fail err [%v]", err)
 		return
 	}
+	if mp.isTransitioned(i) {
+		err = fmt.Errorf("inode[%v] is transitioned to the blobstore", req.Inode)
+		p.PacketErrorWithBody(proto.OpNotPerm, []byte(err.Error()))
+		return
+	}
 
 	extents := req.Extents
 	for _, extent := range extents {
@@ -77,6 +77,10 @@ func (mp *metaPartition) getQuotaReportInfos() (infos []*proto.QuotaReportInfo)
 	return mp.mqMgr.getQuotaReportInfos()
 }
 
+func (mp *metaPartition) getQuotaUsedInfos() (infos []*proto.QuotaReportInfo) {
+	return mp.mqMgr.getQuotaUsedInfos()
+}
+
 func (mp *metaPartition) statisticExtendByLoad(extend *Extend) {
 	mqMgr := mp.mqMgr
 	ino := NewInode(extend.GetInode(), 0)
@@ -0,0 +1,525 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cubefs/cubefs/util/errors"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	snapshotExportDir      = ".export"
+	snapshotImportDir      = ".import"
+	snapshotManifestFile   = "manifest.json"
+	snapshotImportMaxItems = 1024
+	snapshotImportMaxBytes = 4 * MB
+)
+
+// States of snapshot import.
+const (
+	SnapshotImportRunning = "running"
+	SnapshotImportDone    = "done"
+	SnapshotImportFailed  = "failed"
+)
+
+// snapshotStoreConfig is the object store that snapshots are exported to and imported from,
+// it is used by paths like s3://bucket/prefix.
+type snapshotStoreConfig struct {
+	endpoint  string
+	region    string
+	accessKey string
+	secretKey string
+}
+
+var snapshotStoreCfg snapshotStoreConfig
+
+// SnapshotManifest describes an exported snapshot of meta partition, it is written after all
+// snapshot files are uploaded, so an export without manifest is incomplete.
+type SnapshotManifest struct {
+	PartitionID uint64   `json:"pid"`
+	VolName     string   `json:"vol"`
+	Start       uint64   `json:"start"`
+	End         uint64   `json:"end"`
+	Files       []string `json:"files"`
+	ExportTime  int64    `json:"exportTime"`
+}
+
+// SnapshotImportStatus is the progress of importing a snapshot into the partition.
+type SnapshotImportStatus struct {
+	Path       string `json:"path"`
+	State      string `json:"state"`
+	Inodes     uint64 `json:"inodes"`
+	Dentries   uint64 `json:"dentries"`
+	Extends    uint64 `json:"extends"`
+	Multiparts uint64 `json:"multiparts"`
+	Msg        string `json:"msg,omitempty"`
+	StartTime  int64  `json:"startTime"`
+	EndTime    int64  `json:"endTime,omitempty"`
+}
+
+// snapshotImportBatch is a batch of snapshot items replicated to all replicas of the partition.
+type snapshotImportBatch struct {
+	Inodes     [][]byte `json:"inodes,omitempty"`
+	Dentries   [][]byte `json:"dentries,omitempty"`
+	Extends    [][]byte `json:"extends,omitempty"`
+	Multiparts [][]byte `json:"multiparts,omitempty"`
+	size       int
+}
+
+func (b *snapshotImportBatch) count() int {
+	return len(b.Inodes) + len(b.Dentries) + len(b.Extends) + len(b.Multiparts)
+}
+
+type snapshotStore interface {
+	put(name string, r io.ReadSeeker) error
+	get(name string) (io.ReadCloser, error)
+}
+
+// newSnapshotStore returns the store of path, which is s3://bucket/prefix for object store,
+// or file:///dir for local directory.
+func newSnapshotStore(rawPath string) (store snapshotStore, err error) {
+	u, err := url.Parse(rawPath)
+	if err != nil {
+		return
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("empty directory of path %v", rawPath)
+		}
+		return &localSnapshotStore{dir: u.Path}, nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("empty bucket of path %v", rawPath)
+		}
+		if snapshotStoreCfg.endpoint == "" {
+			return nil, fmt.Errorf("snapshot store endpoint is not configured")
+		}
+		ac := aws.NewConfig()
+		ac.Endpoint = aws.String(snapshotStoreCfg.endpoint)
+		ac.Region = aws.String(snapshotStoreCfg.region)
+		ac.Credentials = credentials.NewStaticCredentials(snapshotStoreCfg.accessKey, snapshotStoreCfg.secretKey, "")
+		ac.S3ForcePathStyle = aws.Bool(true)
+		var sess *session.Session
+		if sess, err = session.NewSession(ac); err != nil {
+			return
+		}
+		return &s3SnapshotStore{client: s3.New(sess), bucket: u.Host, prefix: strings.Trim(u.Path, "/")}, nil
+	default:
+		return nil, fmt.Errorf("unsupported snapshot path %v", rawPath)
+	}
+}
+
+type localSnapshotStore struct {
+	dir string
+}
+
+func (s *localSnapshotStore) put(name string, r io.ReadSeeker) (err error) {
+	if err = os.MkdirAll(s.dir, 0o755); err != nil {
+		return
+	}
+	fp, err := os.OpenFile(path.Join(s.dir, name), os.O_RDWR|os.O_TRUNC|os.O_CREATE, 0o644)
+	if err != nil {
+		return
+	}
+	defer fp.Close()
+	if _, err = io.Copy(fp, r); err != nil {
+		return
+	}
+	return fp.Sync()
+}
+
+func (s *localSnapshotStore) get(name string) (io.ReadCloser, error) {
+	return os.Open(path.Join(s.dir, name))
+}
+
+type s3SnapshotStore struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func (s *s3SnapshotStore) key(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
+
+func (s *s3SnapshotStore) put(name string, r io.ReadSeeker) (err error) {
+	_, err = s.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+		Body:   r,
+	})
+	return
+}
+
+func (s *s3SnapshotStore) get(name string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// ExportSnapshot uploads the latest persisted snapshot of the partition to path, the exported
+// files are in the same format as the snapshot directory, and can be loaded by offline tools.
+func (mp *metaPartition) ExportSnapshot(rawPath string) (manifest *SnapshotManifest, err error) {
+	store, err := newSnapshotStore(rawPath)
+	if err != nil {
+		return
+	}
+	exportDir := path.Join(mp.config.RootDir, snapshotExportDir)
+	if err = os.RemoveAll(exportDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(exportDir, 0o755); err != nil {
+		return
+	}
+	defer os.RemoveAll(exportDir)
+
+	manifest = &SnapshotManifest{
+		PartitionID: mp.config.PartitionId,
+		VolName:     mp.config.VolName,
+		Start:       mp.config.Start,
+		End:         mp.config.End,
+		ExportTime:  time.Now().Unix(),
+	}
+	// link the files so that the snapshot is consistent even if it is replaced during uploading
+	mp.snapshotLock.RLock()
+	entries, err := os.ReadDir(path.Join(mp.config.RootDir, snapshotDir))
+	if err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if err = os.Link(path.Join(mp.config.RootDir, snapshotDir, entry.Name()), path.Join(exportDir, entry.Name())); err != nil {
+				break
+			}
+			manifest.Files = append(manifest.Files, entry.Name())
+		}
+	}
+	mp.snapshotLock.RUnlock()
+	if err != nil {
+		err = errors.NewErrorf("[ExportSnapshot] link snapshot: %s", err.Error())
+		return
+	}
+
+	for _, name := range manifest.Files {
+		if err = putSnapshotFile(store, path.Join(exportDir, name), name); err != nil {
+			err = errors.NewErrorf("[ExportSnapshot] upload %v: %s", name, err.Error())
+			return
+		}
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return
+	}
+	if err = store.put(snapshotManifestFile, strings.NewReader(string(data))); err != nil {
+		err = errors.NewErrorf("[ExportSnapshot] upload manifest: %s", err.Error())
+		return
+	}
+	log.LogInfof("[ExportSnapshot] partition(%v) exported to %v, files(%v)", mp.config.PartitionId, rawPath, manifest.Files)
+	return
+}
+
+func putSnapshotFile(store snapshotStore, filename, name string) (err error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer fp.Close()
+	return store.put(name, fp)
+}
+
+func getSnapshotFile(store snapshotStore, name, filename string) (err error) {
+	r, err := store.get(name)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	fp, err := os.OpenFile(filename, os.O_RDWR|os.O_TRUNC|os.O_CREATE, 0o644)
+	if err != nil {
+		return
+	}
+	defer fp.Close()
+	_, err = io.Copy(fp, r)
+	return
+}
+
+func (mp *metaPartition) GetSnapshotImportStatus() *SnapshotImportStatus {
+	mp.snapshotImportLock.Lock()
+	defer mp.snapshotImportLock.Unlock()
+	if mp.snapshotImport == nil {
+		return nil
+	}
+	status := *mp.snapshotImport
+	return &status
+}
+
+func (mp *metaPartition) updateSnapshotImport(fn func(status *SnapshotImportStatus)) {
+	mp.snapshotImportLock.Lock()
+	defer mp.snapshotImportLock.Unlock()
+	fn(mp.snapshotImport)
+}
+
+// ImportSnapshot imports an exported snapshot into the partition in background, items of the
+// snapshot are replicated through raft, so the partition must be empty and be the leader.
+func (mp *metaPartition) ImportSnapshot(rawPath string) (err error) {
+	if _, ok := mp.IsLeader(); !ok {
+		return ErrNotALeader
+	}
+	if mp.inodeTree.Len() != 0 || mp.dentryTree.Len() != 0 {
+		return fmt.Errorf("partition(%v) is not empty, inodes(%v) dentries(%v)",
+			mp.config.PartitionId, mp.inodeTree.Len(), mp.dentryTree.Len())
+	}
+	store, err := newSnapshotStore(rawPath)
+	if err != nil {
+		return
+	}
+	mp.snapshotImportLock.Lock()
+	if mp.snapshotImport != nil && mp.snapshotImport.State == SnapshotImportRunning {
+		mp.snapshotImportLock.Unlock()
+		return fmt.Errorf("snapshot %v is being imported", mp.snapshotImport.Path)
+	}
+	mp.snapshotImport = &SnapshotImportStatus{Path: rawPath, State: SnapshotImportRunning, StartTime: time.Now().Unix()}
+	mp.snapshotImportLock.Unlock()
+
+	go func() {
+		importErr := mp.importSnapshot(store)
+		mp.updateSnapshotImport(func(status *SnapshotImportStatus) {
+			status.EndTime = time.Now().Unix()
+			if importErr != nil {
+				status.State = SnapshotImportFailed
+				status.Msg = importErr.Error()
+				return
+			}
+			status.State = SnapshotImportDone
+		})
+		if importErr != nil {
+			log.LogErrorf("[ImportSnapshot] partition(%v) import %v failed: %v", mp.config.PartitionId, rawPath, importErr)
+			return
+		}
+		log.LogInfof("[ImportSnapshot] partition(%v) import %v done", mp.config.PartitionId, rawPath)
+	}()
+	return
+}
+
+func (mp *metaPartition) importSnapshot(store snapshotStore) (err error) {
+	importDir := path.Join(mp.config.RootDir, snapshotImportDir)
+	if err = os.RemoveAll(importDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(path.Join(importDir, snapshotDir), 0o755); err != nil {
+		return
+	}
+	defer os.RemoveAll(importDir)
+
+	manifestFile := path.Join(importDir, snapshotManifestFile)
+	if err = getSnapshotFile(store, snapshotManifestFile, manifestFile); err != nil {
+		return errors.NewErrorf("[importSnapshot] download manifest: %s", err.Error())
+	}
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return
+	}
+	manifest := &SnapshotManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return
+	}
+	if manifest.VolName != mp.config.VolName {
+		return fmt.Errorf("snapshot of volume %v can not be imported into volume %v", manifest.VolName, mp.config.VolName)
+	}
+	for _, name := range manifest.Files {
+		if err = getSnapshotFile(store, name, path.Join(importDir, snapshotDir, name)); err != nil {
+			return errors.NewErrorf("[importSnapshot] download %v: %s", name, err.Error())
+		}
+	}
+
+	// load the snapshot with the same code as startup, the crc of files are checked
+	src := NewMetaPartition(&MetaPartitionConfig{
+		PartitionId: manifest.PartitionID,
+		VolName:     manifest.VolName,
+		Start:       manifest.Start,
+		End:         manifest.End,
+		RootDir:     importDir,
+	}, mp.manager).(*metaPartition)
+	src.uidManager = NewUidMgr(manifest.VolName, manifest.PartitionID)
+	src.mqMgr = NewQuotaManager(manifest.VolName, manifest.PartitionID)
+	if err = src.LoadSnapshot(path.Join(importDir, snapshotDir)); err != nil {
+		return errors.NewErrorf("[importSnapshot] load snapshot: %s", err.Error())
+	}
+	if err = mp.checkSnapshotInodeRange(src); err != nil {
+		return
+	}
+	return mp.replicateSnapshot(src)
+}
+
+func (mp *metaPartition) checkSnapshotInodeRange(src *metaPartition) (err error) {
+	src.inodeTree.Ascend(func(i BtreeItem) bool {
+		ino := i.(*Inode)
+		if ino.Inode < mp.config.Start || ino.Inode > mp.config.End {
+			err = fmt.Errorf("inode(%v) of snapshot is out of range [%v, %v] of partition(%v)",
+				ino.Inode, mp.config.Start, mp.config.End, mp.config.PartitionId)
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// replicateSnapshot submits items of src in batches, inodes first so that dentries and
+// extends refer to existing inodes.
+func (mp *metaPartition) replicateSnapshot(src *metaPartition) (err error) {
+	batch := &snapshotImportBatch{}
+	flush := func() bool {
+		if batch.count() == 0 {
+			return true
+		}
+		if err = mp.submitSnapshotBatch(batch); err != nil {
+			return false
+		}
+		batch = &snapshotImportBatch{}
+		return true
+	}
+	add := func(items *[][]byte, data []byte) bool {
+		*items = append(*items, data)
+		batch.size += len(data)
+		if batch.count() >= snapshotImportMaxItems || batch.size >= snapshotImportMaxBytes {
+			return flush()
+		}
+		return true
+	}
+	src.inodeTree.GetTree().Ascend(func(i BtreeItem) bool {
+		var data []byte
+		if data, err = i.(*Inode).Marshal(); err != nil {
+			return false
+		}
+		return add(&batch.Inodes, data)
+	})
+	if err != nil || !flush() {
+		return
+	}
+	src.dentryTree.GetTree().Ascend(func(i BtreeItem) bool {
+		var data []byte
+		if data, err = i.(*Dentry).Marshal(); err != nil {
+			return false
+		}
+		return add(&batch.Dentries, data)
+	})
+	if err != nil || !flush() {
+		return
+	}
+	src.extendTree.GetTree().Ascend(func(i BtreeItem) bool {
+		var data []byte
+		if data, err = i.(*Extend).Bytes(); err != nil {
+			return false
+		}
+		return add(&batch.Extends, data)
+	})
+	if err != nil || !flush() {
+		return
+	}
+	src.multipartTree.GetTree().Ascend(func(i BtreeItem) bool {
+		var data []byte
+		if data, err = i.(*Multipart).Bytes(); err != nil {
+			return false
+		}
+		return add(&batch.Multiparts, data)
+	})
+	if err != nil || !flush() {
+		return
+	}
+
+	// inodes allocated but not persisted by the source partition must not be reused
+	cursor := make([]byte, 8)
+	binary.BigEndian.PutUint64(cursor, src.GetCursor())
+	_, err = mp.submit(opFSMSyncCursor, cursor)
+	return
+}
+
+func (mp *metaPartition) submitSnapshotBatch(batch *snapshotImportBatch) (err error) {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return
+	}
+	if _, err = mp.submit(opFSMImportSnapshotBatch, data); err != nil {
+		return
+	}
+	mp.updateSnapshotImport(func(status *SnapshotImportStatus) {
+		status.Inodes += uint64(len(batch.Inodes))
+		status.Dentries += uint64(len(batch.Dentries))
+		status.Extends += uint64(len(batch.Extends))
+		status.Multiparts += uint64(len(batch.Multiparts))
+	})
+	return
+}
+
+func (mp *metaPartition) fsmImportSnapshotBatch(data []byte) (err error) {
+	batch := &snapshotImportBatch{}
+	if err = json.Unmarshal(data, batch); err != nil {
+		return
+	}
+	for _, raw := range batch.Inodes {
+		ino := NewInode(0, 0)
+		if err = ino.Unmarshal(raw); err != nil {
+			return
+		}
+		if mp.config.Cursor < ino.Inode {
+			mp.config.Cursor = ino.Inode
+		}
+		mp.fsmCreateInode(ino)
+		mp.checkAndInsertFreeList(ino)
+	}
+	for _, raw := range batch.Dentries {
+		dentry := &Dentry{}
+		if err = dentry.Unmarshal(raw); err != nil {
+			return
+		}
+		// the parent may belong to another partition
+		mp.fsmCreateDentry(dentry, true)
+	}
+	for _, raw := range batch.Extends {
+		var extend *Extend
+		if extend, err = NewExtendFromBytes(raw); err != nil {
+			return
+		}
+		if err = mp.fsmSetXAttr(extend); err != nil {
+			return
+		}
+	}
+	for _, raw := range batch.Multiparts {
+		mp.fsmCreateMultipart(MultipartFromBytes(raw))
+	}
+	return
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportSnapshot(t *testing.T) {
+	src := NewMetaPartitionForQuotaTest()
+	src.uidManager = NewUidMgr(VolNameForTest, PartitionIdForTest)
+	src.manager = &metadataManager{}
+	src.config.RootDir = t.TempDir()
+	src.config.Start, src.config.End = 1, 1000
+	for ino := uint64(1); ino <= 3; ino++ {
+		mode := uint32(0o644)
+		if ino == 1 {
+			mode = proto.Mode(os.ModeDir | 0o755)
+		}
+		require.Equal(t, proto.OpOk, src.fsmCreateInode(NewInode(ino, mode)))
+	}
+	require.Equal(t, proto.OpOk, src.fsmCreateDentry(&Dentry{ParentId: 1, Name: "a", Inode: 2}, true))
+	require.Equal(t, proto.OpOk, src.fsmCreateDentry(&Dentry{ParentId: 1, Name: "b", Inode: 3}, true))
+	extend := NewExtend(2)
+	extend.Put([]byte("user.tag"), []byte("photo"), 0)
+	require.NoError(t, src.fsmSetXAttr(extend))
+	src.config.Cursor = 10
+	require.NoError(t, src.store(&storeMsg{
+		applyIndex:     100,
+		inodeTree:      src.inodeTree.GetTree(),
+		dentryTree:     src.dentryTree.GetTree(),
+		extendTree:     src.extendTree.GetTree(),
+		multipartTree:  src.multipartTree.GetTree(),
+		txTree:         NewBtree(),
+		txRbInodeTree:  NewBtree(),
+		txRbDentryTree: NewBtree(),
+		uniqChecker:    newUniqChecker(),
+	}))
+
+	exportPath := "file://" + t.TempDir()
+	manifest, err := src.ExportSnapshot(exportPath)
+	require.NoError(t, err)
+	require.Contains(t, manifest.Files, inodeFile)
+	require.Contains(t, manifest.Files, SnapshotSign)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	dst := mockPartitionRaftForQuotaTest(ctrl)
+	dst.uidManager = NewUidMgr(VolNameForTest, PartitionIdForTest)
+	dst.manager = &metadataManager{}
+	dst.config.RootDir = t.TempDir()
+	dst.config.NodeId = 1
+
+	// inodes must be in range of the partition
+	dst.config.Start, dst.config.End = 1, 2
+	require.NoError(t, dst.ImportSnapshot(exportPath))
+	status := waitSnapshotImport(t, dst)
+	require.Equal(t, SnapshotImportFailed, status.State)
+	require.Equal(t, 0, dst.inodeTree.Len())
+
+	dst.config.Start, dst.config.End = 1, 1000
+	require.NoError(t, dst.ImportSnapshot(exportPath))
+	status = waitSnapshotImport(t, dst)
+	require.Equal(t, SnapshotImportDone, status.State, status.Msg)
+	require.Equal(t, uint64(3), status.Inodes)
+	require.Equal(t, uint64(2), status.Dentries)
+	require.Equal(t, 3, dst.inodeTree.Len())
+	require.Equal(t, 2, dst.dentryTree.Len())
+	require.NotNil(t, dst.extendTree.Get(NewExtend(2)))
+	require.Equal(t, uint64(10), dst.GetCursor())
+
+	// only empty partitions can be imported into
+	require.Error(t, dst.ImportSnapshot(exportPath))
+}
+
+func waitSnapshotImport(t *testing.T, mp *metaPartition) *SnapshotImportStatus {
+	for i := 0; i < 100; i++ {
+		status := mp.GetSnapshotImportStatus()
+		require.NotNil(t, status)
+		if status.State != SnapshotImportRunning {
+			return status
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("snapshot import is not finished")
+	return nil
+}
@@ -31,7 +31,7 @@ type storeMsg struct {
 	applyIndex     uint64
 	txId           uint64
 	inodeTree      *BTree
-	dentryTree     *BTree
+	dentryTree     *DentryTree
 	extendTree     *BTree
 	multipartTree  *BTree
 	txTree         *BTree
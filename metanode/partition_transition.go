@@ -0,0 +1,156 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/cubefs/cubefs/blobstore/api/access"
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/data/blobstore"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// InodeTransition moves the data of the inode of a hot volume to the blobstore, the data is copied
+// to the blobstore by the lifecycle node, and the extents on the data nodes are deleted here.
+func (mp *metaPartition) InodeTransition(req *proto.InodeTransitionRequest, p *Packet) (err error) {
+	if proto.IsCold(mp.volType) || len(req.ObjExtents) == 0 {
+		p.PacketErrorWithBody(proto.OpArgMismatchErr, []byte("no data to transition"))
+		return
+	}
+	val, err := json.Marshal(req)
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpErr, []byte(err.Error()))
+		return
+	}
+	r, err := mp.submit(opFSMInodeTransition, val)
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpAgain, []byte(err.Error()))
+		return
+	}
+	status := r.(uint8)
+	if status != proto.OpOk {
+		p.PacketErrorWithBody(status, nil)
+		return
+	}
+	p.PacketOkReply()
+	return
+}
+
+func (mp *metaPartition) fsmInodeTransition(req *proto.InodeTransitionRequest) (status uint8) {
+	item := mp.inodeTree.CopyGet(NewInode(req.Inode, 0))
+	if item == nil {
+		return proto.OpNotExistErr
+	}
+	i := item.(*Inode)
+	if i.ShouldDelete() {
+		return proto.OpNotExistErr
+	}
+	if !proto.IsRegular(i.Type) {
+		return proto.OpArgMismatchErr
+	}
+
+	objExtents := NewSortedObjExtents()
+	for _, ek := range req.ObjExtents {
+		if err := objExtents.Append(ek); err != nil {
+			log.LogWarnf("fsmInodeTransition: mp(%v) ino(%v) err(%v)", mp.config.PartitionId, req.Inode, err)
+			return proto.OpArgMismatchErr
+		}
+	}
+
+	i.Lock()
+	// the inode is modified after its data is copied, or is in a snapshot
+	if i.Size != req.Size || i.Generation != req.Generation || objExtents.Size() != i.Size ||
+		len(i.ObjExtents.eks) > 0 || i.getLayerLen() > 0 {
+		i.Unlock()
+		log.LogWarnf("fsmInodeTransition: mp(%v) ino(%v) size(%v) gen(%v) mismatch with req size(%v) gen(%v)",
+			mp.config.PartitionId, req.Inode, i.Size, i.Generation, req.Size, req.Generation)
+		return proto.OpArgMismatchErr
+	}
+	delExtents := i.Extents.CopyExtents()
+	i.Extents = NewSortedExtents()
+	i.ObjExtents = objExtents
+	i.Generation++
+	i.Unlock()
+
+	extend := NewExtend(req.Inode)
+	extend.Put([]byte(proto.XAttrKeyStorageClass), []byte(req.StorageClass), mp.verSeq)
+	if err := mp.fsmSetXAttr(extend); err != nil {
+		log.LogErrorf("fsmInodeTransition: mp(%v) ino(%v) set storage class err(%v)", mp.config.PartitionId, req.Inode, err)
+	}
+
+	if len(delExtents) > 0 {
+		i.DecSplitExts(mp.config.PartitionId, delExtents)
+		mp.extDelCh <- delExtents
+	}
+	log.LogInfof("fsmInodeTransition: mp(%v) ino(%v) storage class(%v) objExtents(%v) delExtents(%v)",
+		mp.config.PartitionId, req.Inode, req.StorageClass, len(req.ObjExtents), len(delExtents))
+	return proto.OpOk
+}
+
+// isTransitioned returns whether the data of the inode of a hot volume is transitioned to the blobstore,
+// such an inode has no extents on the data nodes, so it can't be read or written by the extents.
+func (mp *metaPartition) isTransitioned(ino *Inode) (transitioned bool) {
+	if !proto.IsHot(mp.volType) {
+		return false
+	}
+	ino.DoReadFunc(func() {
+		transitioned = len(ino.ObjExtents.eks) > 0
+	})
+	return
+}
+
+// deleteTransitionedObjExtents deletes the data of the transitioned inodes of a hot volume from the blobstore.
+func (mp *metaPartition) deleteTransitionedObjExtents(allInodes []*Inode) (shouldCommit []*Inode, shouldPushToFreeList []*Inode) {
+	transitioned := make([]*Inode, 0)
+	for _, inode := range allInodes {
+		if len(inode.ObjExtents.CopyExtents()) > 0 {
+			transitioned = append(transitioned, inode)
+		} else {
+			shouldCommit = append(shouldCommit, inode)
+		}
+	}
+	if len(transitioned) == 0 {
+		return
+	}
+	if mp.ebsClient == nil {
+		if err := mp.newEbsClient(); err != nil {
+			log.LogErrorf("[deleteTransitionedObjExtents] mp(%v) new ebs client err(%v)", mp.config.PartitionId, err)
+			return shouldCommit, append(shouldPushToFreeList, transitioned...)
+		}
+	}
+	committed, rePushed := mp.doBatchDeleteObjExtentsInEBS(transitioned)
+	return append(shouldCommit, committed...), append(shouldPushToFreeList, rePushed...)
+}
+
+func (mp *metaPartition) newEbsClient() (err error) {
+	clusterInfo, err := masterClient.AdminAPI().GetClusterInfo()
+	if err != nil {
+		return
+	}
+	if clusterInfo.EbsAddr == "" {
+		return fmt.Errorf("no blobstore of the cluster")
+	}
+	mp.ebsClient, err = blobstore.NewEbsClient(access.Config{
+		ConnMode: access.NoLimitConnMode,
+		Consul: access.ConsulConfig{
+			Address: clusterInfo.EbsAddr,
+		},
+		Logger: &access.Logger{Filename: path.Join(log.LogDir, "ebs.log")},
+	})
+	return
+}
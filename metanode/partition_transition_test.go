@@ -0,0 +1,65 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitionedInodeRejectsExtentOps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mp := mockPartitionRaftForQuotaTest(ctrl)
+	mp.uidManager = NewUidMgr(VolNameForTest, PartitionIdForTest)
+	mp.config.Start, mp.config.End = 1, 1000
+	mp.extDelCh = make(chan []proto.ExtentKey, 10)
+
+	inode := NewInode(1, 0o644)
+	require.Equal(t, proto.OpOk, mp.fsmCreateInode(inode))
+	ino := NewInode(1, 0)
+	ino.Extents.Append(proto.ExtentKey{PartitionId: 1, ExtentId: 1, Size: 100})
+	require.Equal(t, proto.OpOk, mp.fsmAppendExtents(ino))
+
+	p := &Packet{}
+	require.NoError(t, mp.ExtentsList(&proto.GetExtentsRequest{Inode: 1}, p))
+	require.Equal(t, proto.OpOk, p.ResultCode)
+
+	req := &proto.InodeTransitionRequest{
+		Inode:        1,
+		Size:         100,
+		Generation:   inode.Generation,
+		StorageClass: proto.StorageClassStandardIA,
+		ObjExtents:   []proto.ObjExtentKey{{Size: 100}},
+	}
+	require.Equal(t, proto.OpOk, mp.fsmInodeTransition(req))
+	require.True(t, mp.isTransitioned(inode))
+
+	// the data isn't on the data nodes any more
+	p = &Packet{}
+	require.NoError(t, mp.ExtentsList(&proto.GetExtentsRequest{Inode: 1}, p))
+	require.Equal(t, proto.OpErr, p.ResultCode)
+
+	ino = NewInode(1, 0)
+	ino.Extents.Append(proto.ExtentKey{FileOffset: 100, PartitionId: 1, ExtentId: 2, Size: 100})
+	require.Equal(t, proto.OpNotPerm, mp.fsmAppendExtents(ino))
+	p = &Packet{}
+	require.Error(t, mp.ExtentsTruncate(&ExtentsTruncateReq{Inode: 1, Size: 0}, p, ""))
+	require.Equal(t, proto.OpNotPerm, p.ResultCode)
+	require.Equal(t, proto.OpNotPerm, mp.fsmExtentsTruncate(NewInode(1, 0)).Status)
+}
@@ -0,0 +1,507 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	// dentries in trash are kept under the parent id 0, which is never a valid inode,
+	// in the partition of their original parent.
+	trashParentId         = 0
+	trashPurgePeriod      = time.Minute
+	defaultListTrashLimit = 1000
+	maxListTrashLimit     = 10000
+
+	// items being purged are moved to purgeKey in trash, which sorts after all items
+	// since their keys begin with digits.
+	trashPurgePrefix = "purge/"
+)
+
+// trashKey is the name of the dentry in trash, which is parentId/deleteTime/inode/name,
+// '/' never appears in the name of a dentry.
+func trashKey(den *Dentry, deleteTime int64) string {
+	return fmt.Sprintf("%d/%d/%d/%s", den.ParentId, deleteTime, den.Inode, den.Name)
+}
+
+func parseTrashItem(den *Dentry) (item *proto.TrashItem, err error) {
+	parts := strings.SplitN(den.Name, "/", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid trash key(%v)", den.Name)
+	}
+	item = &proto.TrashItem{Key: den.Name, Name: parts[3], Inode: den.Inode, Type: den.Type}
+	if item.ParentID, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid trash key(%v): %v", den.Name, err)
+	}
+	if item.DeleteTime, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid trash key(%v): %v", den.Name, err)
+	}
+	return
+}
+
+type trashDentryReq struct {
+	ParentId   uint64 `json:"pino"`
+	Name       string `json:"name"`
+	DeleteTime int64  `json:"deleteTime"`
+}
+
+type restoreTrashReq struct {
+	Key     string `json:"key"`
+	NewName string `json:"newName"`
+}
+
+type markTrashPurgeReq struct {
+	Key      string `json:"key"`
+	UniqID   uint64 `json:"uniqID"`
+	MarkTime int64  `json:"markTime"`
+}
+
+type purgeTrashReq struct {
+	PurgeKey string `json:"purgeKey"`
+}
+
+// trashPurge is an item being purged, its inode is unlinked once with UniqID.
+type trashPurge struct {
+	*proto.TrashItem
+	PurgeKey string
+	UniqID   uint64
+	MarkTime int64
+}
+
+// purgeKey is the name of the item being purged in trash, which is purge/uniqID/markTime/key.
+func purgeKey(req *markTrashPurgeReq) string {
+	return fmt.Sprintf("%s%d/%d/%s", trashPurgePrefix, req.UniqID, req.MarkTime, req.Key)
+}
+
+func parseTrashPurge(den *Dentry) (purge *trashPurge, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(den.Name, trashPurgePrefix), "/", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid purge key(%v)", den.Name)
+	}
+	purge = &trashPurge{PurgeKey: den.Name}
+	if purge.UniqID, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid purge key(%v): %v", den.Name, err)
+	}
+	if purge.MarkTime, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid purge key(%v): %v", den.Name, err)
+	}
+	if purge.TrashItem, err = parseTrashItem(&Dentry{Name: parts[2], Inode: den.Inode, Type: den.Type}); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (mp *metaPartition) trashEnabled() bool {
+	// the dentries are protected by snapshot if it is enabled
+	return mp.vol.trashInterval > 0 && mp.verSeq == 0
+}
+
+// moveDentryToTrash deletes the dentry by moving it into trash, directories are deleted directly.
+func (mp *metaPartition) moveDentryToTrash(req *DeleteDentryReq, p *Packet) (err error) {
+	val, err := json.Marshal(&trashDentryReq{ParentId: req.ParentID, Name: req.Name, DeleteTime: time.Now().Unix()})
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpErr, []byte(err.Error()))
+		return
+	}
+	r, err := mp.submit(opFSMTrashDentry, val)
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpAgain, []byte(err.Error()))
+		return
+	}
+	if status, ok := r.(uint8); ok {
+		p.PacketErrorWithBody(status, nil)
+		return
+	}
+	retMsg := r.(*DentryResponse)
+	p.ResultCode = retMsg.Status
+	if p.ResultCode == proto.OpOk {
+		var reply []byte
+		resp := &DeleteDentryResp{
+			Inode:   retMsg.Msg.Inode,
+			Trashed: !proto.IsDir(retMsg.Msg.Type),
+		}
+		reply, err = json.Marshal(resp)
+		p.PacketOkWithBody(reply)
+	}
+	return
+}
+
+func (mp *metaPartition) fsmTrashDentry(req *trashDentryReq) (resp *DentryResponse) {
+	resp = mp.fsmDeleteDentry(&Dentry{ParentId: req.ParentId, Name: req.Name}, false)
+	if resp.Status != proto.OpOk || proto.IsDir(resp.Msg.Type) {
+		return
+	}
+	den := resp.Msg
+	mp.dentryTree.ReplaceOrInsert(&Dentry{
+		ParentId: trashParentId,
+		Name:     trashKey(den, req.DeleteTime),
+		Inode:    den.Inode,
+		Type:     den.Type,
+	}, true)
+	return
+}
+
+// ListTrash lists the items in trash of the partition in order of the original parent.
+func (mp *metaPartition) ListTrash(req *proto.ListTrashRequest, p *Packet) (err error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxListTrashLimit {
+		limit = defaultListTrashLimit
+	}
+	begin := &Dentry{ParentId: trashParentId, Name: req.Marker}
+	if req.ParentID != 0 && req.Marker == "" {
+		begin.Name = strconv.FormatUint(req.ParentID, 10) + "/"
+	}
+	resp := &proto.ListTrashResponse{Items: make([]*proto.TrashItem, 0)}
+	mp.dentryTree.AscendRange(begin, &Dentry{ParentId: trashParentId + 1}, func(i BtreeItem) bool {
+		den := i.(*Dentry)
+		if den.Name == req.Marker {
+			return true
+		}
+		if strings.HasPrefix(den.Name, trashPurgePrefix) {
+			return false
+		}
+		item, parseErr := parseTrashItem(den)
+		if parseErr != nil {
+			log.LogWarnf("[ListTrash] mp(%v) %v", mp.config.PartitionId, parseErr)
+			return true
+		}
+		if req.ParentID != 0 && item.ParentID != req.ParentID {
+			return false
+		}
+		if len(resp.Items) >= limit {
+			resp.NextMarker = resp.Items[len(resp.Items)-1].Key
+			return false
+		}
+		resp.Items = append(resp.Items, item)
+		return true
+	})
+	reply, err := json.Marshal(resp)
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpErr, []byte(err.Error()))
+		return
+	}
+	p.PacketOkWithBody(reply)
+	return
+}
+
+// RestoreTrash moves the item in trash back to its parent, with its original name or a new one.
+func (mp *metaPartition) RestoreTrash(req *proto.RestoreTrashRequest, p *Packet) (err error) {
+	if strings.Contains(req.NewName, "/") {
+		p.PacketErrorWithBody(proto.OpArgMismatchErr, []byte("invalid name "+req.NewName))
+		return
+	}
+	val, err := json.Marshal(&restoreTrashReq{Key: req.Key, NewName: req.NewName})
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpErr, []byte(err.Error()))
+		return
+	}
+	r, err := mp.submit(opFSMRestoreTrashDentry, val)
+	if err != nil {
+		p.PacketErrorWithBody(proto.OpAgain, []byte(err.Error()))
+		return
+	}
+	status := r.(uint8)
+	if status != proto.OpOk {
+		p.PacketErrorWithBody(status, nil)
+		return
+	}
+	p.PacketOkReply()
+	return
+}
+
+func (mp *metaPartition) fsmRestoreTrashDentry(req *restoreTrashReq) (den *Dentry, status uint8) {
+	item := mp.dentryTree.Get(&Dentry{ParentId: trashParentId, Name: req.Key})
+	if item == nil || strings.HasPrefix(req.Key, trashPurgePrefix) {
+		return nil, proto.OpNotExistErr
+	}
+	trashItem, err := parseTrashItem(item.(*Dentry))
+	if err != nil {
+		log.LogErrorf("[fsmRestoreTrashDentry] mp(%v) %v", mp.config.PartitionId, err)
+		return nil, proto.OpArgMismatchErr
+	}
+	den = &Dentry{ParentId: trashItem.ParentID, Name: trashItem.Name, Inode: trashItem.Inode, Type: trashItem.Type}
+	if req.NewName != "" {
+		den.Name = req.NewName
+	}
+	if status = mp.fsmCreateDentry(den, false); status != proto.OpOk {
+		return nil, status
+	}
+	mp.dentryTree.Delete(item.(*Dentry))
+	return den, proto.OpOk
+}
+
+func (mp *metaPartition) startTrashPurge() {
+	ticker := time.NewTicker(trashPurgePeriod)
+	go func(stopC chan bool) {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopC:
+				return
+			case <-ticker.C:
+				if _, ok := mp.IsLeader(); !ok {
+					continue
+				}
+				if err := mp.purgeTrash(time.Now().Unix()); err != nil {
+					log.LogWarnf("[purgeTrash] mp(%v) purge trash failed: %v", mp.config.PartitionId, err)
+				}
+			}
+		}
+	}(mp.stopC)
+}
+
+func (mp *metaPartition) fsmMarkTrashPurge(req *markTrashPurgeReq) (status uint8) {
+	item := mp.dentryTree.Get(&Dentry{ParentId: trashParentId, Name: req.Key})
+	if item == nil {
+		return proto.OpNotExistErr
+	}
+	den := item.(*Dentry)
+	mp.dentryTree.Delete(den)
+	mp.dentryTree.ReplaceOrInsert(&Dentry{
+		ParentId: trashParentId,
+		Name:     purgeKey(req),
+		Inode:    den.Inode,
+		Type:     den.Type,
+	}, true)
+	return proto.OpOk
+}
+
+func (mp *metaPartition) fsmPurgeTrash(req *purgeTrashReq) (den *Dentry, status uint8) {
+	item := mp.dentryTree.Delete(&Dentry{ParentId: trashParentId, Name: req.PurgeKey})
+	if item == nil {
+		return nil, proto.OpNotExistErr
+	}
+	// the original dentry of the item, it is the key of change event
+	den = &Dentry{Name: req.PurgeKey, Inode: item.(*Dentry).Inode, Type: item.(*Dentry).Type}
+	if purge, err := parseTrashPurge(den); err == nil {
+		den.ParentId, den.Name = purge.ParentID, purge.Name
+	}
+	return den, proto.OpOk
+}
+
+// purgeTrash purges the expired items in trash, all the items are expired once the trash is disabled.
+// An expired item is marked with an uniq id allocated by the partition of its inode first, then the inode
+// is unlinked once with the uniq id and evicted before the item is deleted, so the purge is retried on
+// failure without unlinking the inode twice. The inode is left as an orphan rather than unlinked after
+// the uniq id may be evicted by the uniq checker.
+func (mp *metaPartition) purgeTrash(now int64) (err error) {
+	expireTime := now - mp.vol.trashInterval*60
+	expired := make([]*Dentry, 0)
+	purges := make([]*Dentry, 0)
+	mp.dentryTree.AscendRange(&Dentry{ParentId: trashParentId}, &Dentry{ParentId: trashParentId + 1}, func(i BtreeItem) bool {
+		den := i.(*Dentry)
+		if strings.HasPrefix(den.Name, trashPurgePrefix) {
+			purges = append(purges, den)
+			return true
+		}
+		item, parseErr := parseTrashItem(den)
+		if parseErr != nil || item.DeleteTime <= expireTime {
+			expired = append(expired, den)
+		}
+		return true
+	})
+	if len(expired) == 0 && len(purges) == 0 {
+		return
+	}
+
+	router := &trashInodeRouter{mp: mp}
+	for _, den := range expired {
+		if _, ok := mp.IsLeader(); !ok {
+			return
+		}
+		var uniqID uint64
+		if uniqID, err = router.getUniqID(den.Inode); err != nil {
+			return
+		}
+		req := &markTrashPurgeReq{Key: den.Name, UniqID: uniqID, MarkTime: now}
+		var val []byte
+		if val, err = json.Marshal(req); err != nil {
+			return
+		}
+		var r interface{}
+		if r, err = mp.submit(opFSMMarkTrashPurge, val); err != nil {
+			return
+		}
+		if status, _ := r.(uint8); status != proto.OpOk {
+			log.LogWarnf("[purgeTrash] mp(%v) mark trash item(%v) failed: %v", mp.config.PartitionId, den.Name, r)
+			continue
+		}
+		purges = append(purges, &Dentry{ParentId: trashParentId, Name: purgeKey(req), Inode: den.Inode, Type: den.Type})
+	}
+
+	for _, den := range purges {
+		if _, ok := mp.IsLeader(); !ok {
+			return
+		}
+		purge, parseErr := parseTrashPurge(den)
+		if parseErr != nil {
+			log.LogErrorf("[purgeTrash] mp(%v) %v, leave inode(%v) as orphan", mp.config.PartitionId, parseErr, den.Inode)
+		} else if now-purge.MarkTime > opKeepTime {
+			log.LogErrorf("[purgeTrash] mp(%v) uniq id of trash item(%v) may be evicted, leave inode(%v) as orphan",
+				mp.config.PartitionId, den.Name, den.Inode)
+		} else {
+			if err = router.unlinkInode(den.Inode, purge.UniqID); err != nil {
+				return
+			}
+			if err = router.evictInode(den.Inode); err != nil {
+				return
+			}
+		}
+		var val []byte
+		if val, err = json.Marshal(&purgeTrashReq{PurgeKey: den.Name}); err != nil {
+			return
+		}
+		if _, err = mp.submit(opFSMPurgeTrash, val); err != nil {
+			return
+		}
+		log.LogDebugf("[purgeTrash] mp(%v) purge trash item(%v)", mp.config.PartitionId, den.Name)
+	}
+	return nil
+}
+
+// trashInodeRouter sends requests to the leader of the partition of the inode, the partition
+// in this node is called directly.
+type trashInodeRouter struct {
+	mp    *metaPartition
+	views []*proto.MetaPartitionView
+}
+
+func (r *trashInodeRouter) getUniqID(ino uint64) (uniqID uint64, err error) {
+	p, err := r.send(ino, proto.OpMetaGetUniqID, &proto.GetUniqIDRequest{Num: 1})
+	if err != nil {
+		return
+	}
+	resp := &GetUniqIDResp{}
+	if err = json.Unmarshal(p.Data, resp); err != nil {
+		return
+	}
+	return resp.Start, nil
+}
+
+// unlinkInode unlinks the inode once, it's ok if the inode does not exist.
+func (r *trashInodeRouter) unlinkInode(ino, uniqID uint64) (err error) {
+	_, err = r.send(ino, proto.OpMetaUnlinkInode, &UnlinkInoReq{Inode: ino, UniqID: uniqID})
+	return
+}
+
+// evictInode evicts the inode, it's ok if the inode does not exist.
+func (r *trashInodeRouter) evictInode(ino uint64) (err error) {
+	_, err = r.send(ino, proto.OpMetaEvictInode, &EvictInodeReq{Inode: ino})
+	return
+}
+
+func (r *trashInodeRouter) send(ino uint64, op uint8, req interface{}) (p *Packet, err error) {
+	view, err := r.getView(ino)
+	if err != nil {
+		return
+	}
+	pkt, err := buildTxPacket(req, view.PartitionID, op)
+	if err != nil {
+		return
+	}
+	p = &Packet{*pkt}
+
+	var target MetaPartition
+	if view.PartitionID == r.mp.config.PartitionId {
+		target = r.mp
+	} else if r.mp.manager != nil {
+		if local, getErr := r.mp.manager.getPartition(view.PartitionID); getErr == nil {
+			if _, ok := local.IsLeader(); ok {
+				target = local
+			}
+		}
+	}
+	if target != nil {
+		err = callTrashInodeOp(target, p, req)
+	} else {
+		err = r.sendToMembers(view, p)
+	}
+	if err != nil {
+		return
+	}
+	if p.ResultCode != proto.OpOk && !(p.ResultCode == proto.OpNotExistErr && op != proto.OpMetaGetUniqID) {
+		err = fmt.Errorf("mp(%v) op(%v) inode(%v) failed: %v",
+			view.PartitionID, p.GetOpMsg(), ino, p.GetResultMsg())
+	}
+	return
+}
+
+func callTrashInodeOp(target MetaPartition, p *Packet, req interface{}) error {
+	switch req := req.(type) {
+	case *proto.GetUniqIDRequest:
+		return target.GetUniqID(p, req.Num)
+	case *UnlinkInoReq:
+		return target.UnlinkInode(req, p, "")
+	case *EvictInodeReq:
+		return target.EvictInode(req, p, "")
+	default:
+		return fmt.Errorf("unknown trash inode request %T", req)
+	}
+}
+
+// sendToMembers sends the packet to the leader first, then the other members.
+func (r *trashInodeRouter) sendToMembers(view *proto.MetaPartitionView, p *Packet) (err error) {
+	addrs := make([]string, 0, len(view.Members)+1)
+	if view.LeaderAddr != "" {
+		addrs = append(addrs, view.LeaderAddr)
+	}
+	for _, addr := range view.Members {
+		if addr != view.LeaderAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+	tm := r.mp.txProcessor.txManager
+	err = fmt.Errorf("no member of mp(%v)", view.PartitionID)
+	for _, addr := range addrs {
+		pkt := p.GetCopy()
+		if err = tm.sendPacketToMP(addr, pkt); err != nil {
+			continue
+		}
+		p.Packet = *pkt
+		if p.ResultCode == proto.OpErr || p.ResultCode == proto.OpAgain {
+			err = fmt.Errorf("mp(%v) addr(%v) op(%v) failed: %v", view.PartitionID, addr, p.GetOpMsg(), p.GetResultMsg())
+			continue
+		}
+		return nil
+	}
+	return
+}
+
+func (r *trashInodeRouter) getView(ino uint64) (*proto.MetaPartitionView, error) {
+	if ino >= r.mp.config.Start && ino <= r.mp.config.End {
+		return &proto.MetaPartitionView{PartitionID: r.mp.config.PartitionId}, nil
+	}
+	if r.views == nil {
+		views, err := masterClient.ClientAPI().GetMetaPartitions(r.mp.config.VolName)
+		if err != nil {
+			return nil, err
+		}
+		r.views = views
+	}
+	for _, view := range r.views {
+		if ino >= view.Start && ino <= view.End {
+			return view, nil
+		}
+	}
+	return nil, fmt.Errorf("no meta partition of inode(%v) in vol(%v)", ino, r.mp.config.VolName)
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashDentry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mp := mockPartitionRaftForQuotaTest(ctrl)
+	mp.uidManager = NewUidMgr(VolNameForTest, PartitionIdForTest)
+	mp.manager = &metadataManager{}
+	mp.config.NodeId = 1
+	mp.config.Start, mp.config.End = 1, 1000
+	mp.vol.trashInterval = 60
+
+	dirMode := proto.Mode(os.ModeDir | 0o755)
+	require.Equal(t, proto.OpOk, mp.fsmCreateInode(NewInode(1, dirMode)))
+	require.Equal(t, proto.OpOk, mp.fsmCreateInode(NewInode(2, 0o644)))
+	require.Equal(t, proto.OpOk, mp.fsmCreateInode(NewInode(3, dirMode)))
+	require.Equal(t, proto.OpOk, mp.fsmCreateDentry(&Dentry{ParentId: 1, Name: "f", Inode: 2, Type: 0o644}, false))
+	require.Equal(t, proto.OpOk, mp.fsmCreateDentry(&Dentry{ParentId: 1, Name: "d", Inode: 3, Type: dirMode}, false))
+
+	deleteDentry := func(name string) *DeleteDentryResp {
+		p := &Packet{}
+		require.NoError(t, mp.DeleteDentry(&DeleteDentryReq{ParentID: 1, Name: name, Trash: true}, p, ""))
+		require.Equal(t, proto.OpOk, p.ResultCode)
+		resp := &DeleteDentryResp{}
+		require.NoError(t, json.Unmarshal(p.Data, resp))
+		return resp
+	}
+	listTrash := func(req *proto.ListTrashRequest) *proto.ListTrashResponse {
+		p := &Packet{}
+		require.NoError(t, mp.ListTrash(req, p))
+		require.Equal(t, proto.OpOk, p.ResultCode)
+		resp := &proto.ListTrashResponse{}
+		require.NoError(t, json.Unmarshal(p.Data, resp))
+		return resp
+	}
+
+	// directories are deleted directly
+	resp := deleteDentry("d")
+	require.False(t, resp.Trashed)
+	require.Equal(t, uint64(3), resp.Inode)
+
+	resp = deleteDentry("f")
+	require.True(t, resp.Trashed)
+	require.Nil(t, mp.dentryTree.Get(&Dentry{ParentId: 1, Name: "f"}))
+	list := listTrash(&proto.ListTrashRequest{ParentID: 1})
+	require.Len(t, list.Items, 1)
+	item := list.Items[0]
+	require.Equal(t, uint64(1), item.ParentID)
+	require.Equal(t, "f", item.Name)
+	require.Equal(t, uint64(2), item.Inode)
+	require.Len(t, listTrash(&proto.ListTrashRequest{ParentID: 4}).Items, 0)
+
+	// restored with a new name
+	p := &Packet{}
+	require.NoError(t, mp.RestoreTrash(&proto.RestoreTrashRequest{Key: item.Key, NewName: "g"}, p))
+	require.Equal(t, proto.OpOk, p.ResultCode)
+	den := mp.dentryTree.Get(&Dentry{ParentId: 1, Name: "g"})
+	require.NotNil(t, den)
+	require.Equal(t, uint64(2), den.(*Dentry).Inode)
+	require.Len(t, listTrash(&proto.ListTrashRequest{}).Items, 0)
+	p = &Packet{}
+	require.NoError(t, mp.RestoreTrash(&proto.RestoreTrashRequest{Key: item.Key}, p))
+	require.Equal(t, proto.OpNotExistErr, p.ResultCode)
+
+	// expired items are purged with their inodes unlinked
+	require.True(t, deleteDentry("g").Trashed)
+	inodeNlink := func(ino uint64) uint32 {
+		return mp.inodeTree.Get(NewInode(ino, 0)).(*Inode).GetNLink()
+	}
+	require.Equal(t, uint32(1), inodeNlink(2))
+	require.NoError(t, mp.purgeTrash(time.Now().Unix()))
+	require.Equal(t, uint32(1), inodeNlink(2))
+	require.Len(t, listTrash(&proto.ListTrashRequest{}).Items, 1)
+
+	// failed after the item is marked, it's retried without unlinking twice
+	require.Equal(t, proto.OpOk, mp.fsmCreateDentry(&Dentry{ParentId: 1, Name: "h", Inode: 2, Type: 0o644}, false))
+	mp.inodeTree.Get(NewInode(2, 0)).(*Inode).IncNLink(0)
+	require.True(t, deleteDentry("h").Trashed)
+	list = listTrash(&proto.ListTrashRequest{})
+	require.Len(t, list.Items, 2)
+	now := time.Now().Unix() + 3600
+	uniqID := mp.config.UniqId + 1
+	require.Equal(t, proto.OpOk, mp.fsmMarkTrashPurge(&markTrashPurgeReq{Key: list.Items[0].Key, UniqID: uniqID, MarkTime: now}))
+	require.Equal(t, proto.OpOk, mp.fsmUnlinkInode(NewInode(2, 0), uniqID).Status)
+	mp.config.UniqId = uniqID
+	require.Equal(t, uint32(1), inodeNlink(2))
+	// the item being purged is neither listed nor restored
+	require.Len(t, listTrash(&proto.ListTrashRequest{}).Items, 1)
+	p = &Packet{}
+	require.NoError(t, mp.RestoreTrash(&proto.RestoreTrashRequest{Key: list.Items[0].Key}, p))
+	require.Equal(t, proto.OpNotExistErr, p.ResultCode)
+
+	events := mp.GetChangeEvents(0, 0).Events
+	require.NoError(t, mp.purgeTrash(now))
+	require.Len(t, listTrash(&proto.ListTrashRequest{}).Items, 0)
+	require.Nil(t, mp.dentryTree.Get(&Dentry{ParentId: trashParentId + 1}))
+	require.True(t, mp.inodeTree.Get(NewInode(2, 0)).(*Inode).ShouldDelete())
+	count := 0
+	mp.dentryTree.AscendRange(&Dentry{ParentId: trashParentId}, &Dentry{ParentId: trashParentId + 1}, func(i BtreeItem) bool {
+		count++
+		return true
+	})
+	require.Equal(t, 0, count)
+	purged := 0
+	for _, event := range mp.GetChangeEvents(0, 0).Events[len(events):] {
+		if event.Type == proto.ChangeEventTrashPurge {
+			require.Equal(t, uint64(1), event.ParentID)
+			require.Equal(t, uint64(2), event.Inode)
+			purged++
+		}
+	}
+	require.Equal(t, 2, purged)
+}
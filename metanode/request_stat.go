@@ -0,0 +1,57 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// requestStatWindow is the minimal window to compute qps of a partition.
+const requestStatWindow = 10 * time.Second
+
+// requestStat counts the requests served by the leader of a partition, the qps is reported
+// to master with heartbeats, so that hot partitions can be split.
+type requestStat struct {
+	count uint64
+
+	sync.Mutex
+	lastCount uint64
+	lastTime  time.Time
+	qps       uint64
+}
+
+func (s *requestStat) add() {
+	atomic.AddUint64(&s.count, 1)
+}
+
+// Qps returns the qps in the latest window, which is at least requestStatWindow long.
+func (s *requestStat) Qps(now time.Time) uint64 {
+	s.Lock()
+	defer s.Unlock()
+	if s.lastTime.IsZero() {
+		s.lastTime, s.lastCount = now, atomic.LoadUint64(&s.count)
+		return 0
+	}
+	elapsed := now.Sub(s.lastTime)
+	if elapsed < requestStatWindow {
+		return s.qps
+	}
+	count := atomic.LoadUint64(&s.count)
+	s.qps = uint64(float64(count-s.lastCount) / elapsed.Seconds())
+	s.lastTime, s.lastCount = now, count
+	return s.qps
+}
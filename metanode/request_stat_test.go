@@ -0,0 +1,37 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestStatQps(t *testing.T) {
+	s := &requestStat{}
+	now := time.Now()
+	require.Equal(t, uint64(0), s.Qps(now))
+
+	for i := 0; i < 500; i++ {
+		s.add()
+	}
+	// the qps is kept until the window is long enough
+	require.Equal(t, uint64(0), s.Qps(now.Add(time.Second)))
+	require.Equal(t, uint64(50), s.Qps(now.Add(requestStatWindow)))
+	require.Equal(t, uint64(50), s.Qps(now.Add(requestStatWindow+time.Second)))
+	require.Equal(t, uint64(0), s.Qps(now.Add(3*requestStatWindow)))
+}
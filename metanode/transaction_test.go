@@ -57,7 +57,7 @@ func newMetaPartition(PartitionId uint64, manager *metadataManager) (mp *metaPar
 
 	mp = &metaPartition{
 		config:        metaConf,
-		dentryTree:    NewBtree(),
+		dentryTree:    NewDentryTree(),
 		inodeTree:     NewBtree(),
 		extendTree:    NewBtree(),
 		multipartTree: NewBtree(),
@@ -0,0 +1,160 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/btree"
+)
+
+const (
+	xattrIndexKeyPrefix     = "user."
+	defaultXAttrSearchLimit = 1000
+	maxXAttrSearchLimit     = 10000
+)
+
+type xattrIndexItem struct {
+	key   string
+	value string
+	inode uint64
+}
+
+func (i *xattrIndexItem) Less(than BtreeItem) bool {
+	o := than.(*xattrIndexItem)
+	if i.key != o.key {
+		return i.key < o.key
+	}
+	if i.value != o.value {
+		return i.value < o.value
+	}
+	return i.inode < o.inode
+}
+
+func (i *xattrIndexItem) Copy() BtreeItem {
+	item := *i
+	return &item
+}
+
+// xattrIndex is the secondary index from user xattrs to inodes of a meta partition.
+// It is kept in memory only, and rebuilt from the extends when the partition is loaded.
+type xattrIndex struct {
+	sync.RWMutex
+	tree *btree.BTree
+	// items indexed for each inode, used to drop them when the xattrs change
+	inodes map[uint64][]*xattrIndexItem
+}
+
+func newXAttrIndex() *xattrIndex {
+	return &xattrIndex{
+		tree:   btree.New(defaultBTreeDegree),
+		inodes: make(map[uint64][]*xattrIndexItem),
+	}
+}
+
+// update replaces the indexed xattrs of the inode with the user xattrs of the extend,
+// a nil extend drops them.
+func (x *xattrIndex) update(inode uint64, extend *Extend) {
+	items := make([]*xattrIndexItem, 0)
+	if extend != nil {
+		extend.Range(func(key, value []byte) bool {
+			if strings.HasPrefix(string(key), xattrIndexKeyPrefix) {
+				items = append(items, &xattrIndexItem{key: string(key), value: string(value), inode: inode})
+			}
+			return true
+		})
+	}
+	x.Lock()
+	defer x.Unlock()
+	for _, item := range x.inodes[inode] {
+		x.tree.Delete(item)
+	}
+	if len(items) == 0 {
+		delete(x.inodes, inode)
+		return
+	}
+	for _, item := range items {
+		x.tree.ReplaceOrInsert(item)
+	}
+	x.inodes[inode] = items
+}
+
+// rebuild indexes the extends of the tree from scratch.
+func (x *xattrIndex) rebuild(extendTree *BTree) {
+	x.Lock()
+	x.tree = btree.New(defaultBTreeDegree)
+	x.inodes = make(map[uint64][]*xattrIndexItem)
+	x.Unlock()
+	extendTree.GetTree().Ascend(func(i BtreeItem) bool {
+		extend := i.(*Extend)
+		x.update(extend.inode, extend)
+		return true
+	})
+}
+
+func (x *xattrIndex) search(req *proto.SearchXAttrRequest) (items []*proto.XAttrSearchItem, truncated bool) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxXAttrSearchLimit {
+		limit = defaultXAttrSearchLimit
+	}
+	pivot := &xattrIndexItem{key: req.Key, value: req.Value}
+	if req.MarkerValue != "" || req.MarkerInode != 0 {
+		// start after the marker
+		marker := &xattrIndexItem{key: req.Key, value: req.MarkerValue, inode: req.MarkerInode + 1}
+		if pivot.Less(marker) {
+			pivot = marker
+		}
+	}
+	items = make([]*proto.XAttrSearchItem, 0)
+	x.RLock()
+	defer x.RUnlock()
+	x.tree.AscendGreaterOrEqual(pivot, func(i BtreeItem) bool {
+		item := i.(*xattrIndexItem)
+		if item.key != req.Key {
+			return false
+		}
+		if req.Prefix && !strings.HasPrefix(item.value, req.Value) || !req.Prefix && item.value != req.Value {
+			return false
+		}
+		if len(items) >= limit {
+			truncated = true
+			return false
+		}
+		items = append(items, &proto.XAttrSearchItem{Inode: item.inode, Value: item.value})
+		return true
+	})
+	return
+}
+
+// updateXAttrIndex refreshes the index of user xattrs of the inode with its current extend.
+func (mp *metaPartition) updateXAttrIndex(inode uint64) {
+	if mp.xattrIndex == nil {
+		return
+	}
+	var extend *Extend
+	if item := mp.extendTree.Get(NewExtend(inode)); item != nil {
+		extend = item.(*Extend)
+	}
+	mp.xattrIndex.update(inode, extend)
+}
+
+func (mp *metaPartition) rebuildXAttrIndex() {
+	if mp.xattrIndex == nil {
+		return
+	}
+	mp.xattrIndex.rebuild(mp.extendTree)
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metanode
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func searchXAttrForTest(t *testing.T, mp *metaPartition, req *proto.SearchXAttrRequest) *proto.SearchXAttrResponse {
+	p := &Packet{}
+	require.NoError(t, mp.SearchXAttr(req, p))
+	require.Equal(t, proto.OpOk, p.ResultCode, string(p.Data))
+	resp := &proto.SearchXAttrResponse{}
+	require.NoError(t, json.Unmarshal(p.Data, resp))
+	return resp
+}
+
+func TestSearchXAttr(t *testing.T) {
+	mp := NewMetaPartition(&MetaPartitionConfig{PartitionId: PartitionIdForTest, VolName: VolNameForTest}, nil).(*metaPartition)
+	p := &Packet{}
+	require.NoError(t, mp.SearchXAttr(&proto.SearchXAttrRequest{Key: "user.tag"}, p))
+	require.Equal(t, proto.OpNotPerm, p.ResultCode)
+
+	mp.xattrIndex = newXAttrIndex()
+	setXAttr := func(ino uint64, key, value string) {
+		extend := NewExtend(ino)
+		extend.Put([]byte(key), []byte(value), 0)
+		require.NoError(t, mp.fsmSetXAttr(extend))
+	}
+	setXAttr(3, "user.tag", "photo")
+	setXAttr(1, "user.tag", "photo-2023")
+	setXAttr(2, "user.tag", "photo")
+	setXAttr(2, "oss:tagging", "photo")
+	setXAttr(4, "user.owner", "photo")
+
+	resp := searchXAttrForTest(t, mp, &proto.SearchXAttrRequest{Key: "user.tag", Value: "photo"})
+	require.Equal(t, []*proto.XAttrSearchItem{{Inode: 2, Value: "photo"}, {Inode: 3, Value: "photo"}}, resp.Items)
+
+	resp = searchXAttrForTest(t, mp, &proto.SearchXAttrRequest{Key: "user.tag", Value: "photo", Prefix: true, Limit: 2})
+	require.True(t, resp.Truncated)
+	require.Len(t, resp.Items, 2)
+	resp = searchXAttrForTest(t, mp, &proto.SearchXAttrRequest{Key: "user.tag", Value: "photo", Prefix: true,
+		MarkerValue: "photo", MarkerInode: 3})
+	require.False(t, resp.Truncated)
+	require.Equal(t, []*proto.XAttrSearchItem{{Inode: 1, Value: "photo-2023"}}, resp.Items)
+
+	// changed, removed and deleted xattrs are dropped from the index
+	setXAttr(3, "user.tag", "video")
+	extend := NewExtend(1)
+	extend.Put([]byte("user.tag"), nil, 0)
+	require.NoError(t, mp.fsmRemoveXAttr(extend))
+	mp.internalDeleteInode(NewInode(2, 0))
+	resp = searchXAttrForTest(t, mp, &proto.SearchXAttrRequest{Key: "user.tag", Value: "photo", Prefix: true})
+	require.Len(t, resp.Items, 0)
+
+	// the index is rebuilt from extends
+	mp.xattrIndex = newXAttrIndex()
+	mp.rebuildXAttrIndex()
+	resp = searchXAttrForTest(t, mp, &proto.SearchXAttrRequest{Key: "user.tag", Value: "video"})
+	require.Equal(t, []*proto.XAttrSearchItem{{Inode: 3, Value: "video"}}, resp.Items)
+
+	require.NoError(t, mp.SearchXAttr(&proto.SearchXAttrRequest{Key: "oss:tagging", Value: "photo"}, p))
+	require.Equal(t, proto.OpArgMismatchErr, p.ResultCode)
+}
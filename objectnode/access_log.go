@@ -0,0 +1,311 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/common/rpc/auditlog"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	accessLogModule = "S3ACCESS"
+	accessLogTime   = "02/Jan/2006:15:04:05 -0700"
+	// the time in the key of the delivered log object, e.g. 2023-06-01-08-21-25
+	accessLogKeyTime = "2006-01-02-15-04-05"
+
+	defaultAccessLogDeliveryInterval = 300
+	defaultAccessLogDeliverySize     = 16 << 20
+)
+
+// the sub-resources which are taken as the resource of the operation in the access log
+var accessLogSubResources = []string{
+	"acl", "cors", "delete", "lifecycle", "legal-hold", "logging", "location", "object-lock",
+	"policy", "retention", "tagging", "uploads", "versioning", "website",
+}
+
+// AccessLogConfig is the S3 server access log, the entries are in the format of the AWS server
+// access log, written to the local log file or the sink of the audit log, and delivered to the
+// target bucket as log objects periodically if the target bucket is configured.
+type AccessLogConfig struct {
+	Local        *auditlog.Config `json:"local,omitempty"`
+	TargetBucket string           `json:"targetBucket,omitempty"`
+	TargetPrefix string           `json:"targetPrefix,omitempty"`
+	// DeliveryInterval is the interval in seconds to deliver the entries to the target bucket
+	DeliveryInterval int `json:"deliveryInterval,omitempty"`
+	// DeliverySize is the size in bytes of the buffered entries to be delivered immediately
+	DeliverySize int `json:"deliverySize,omitempty"`
+}
+
+// accessLogDeliverFunc puts the log object to the target bucket.
+type accessLogDeliverFunc func(bucket, key string, data []byte) error
+
+type AccessLogger struct {
+	conf    AccessLogConfig
+	logFile auditlog.LogCloser
+	deliver accessLogDeliverFunc
+
+	mu      sync.Mutex
+	buf     *bytes.Buffer
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewAccessLogger(conf AccessLogConfig, deliver accessLogDeliverFunc) (l *AccessLogger, err error) {
+	if conf.Local == nil && conf.TargetBucket == "" {
+		return nil, errors.New("access log: neither local log nor target bucket is configured")
+	}
+	if conf.DeliveryInterval <= 0 {
+		conf.DeliveryInterval = defaultAccessLogDeliveryInterval
+	}
+	if conf.DeliverySize <= 0 {
+		conf.DeliverySize = defaultAccessLogDeliverySize
+	}
+	l = &AccessLogger{
+		conf:    conf,
+		deliver: deliver,
+		buf:     new(bytes.Buffer),
+		flushCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+	}
+	if conf.Local != nil {
+		if _, l.logFile, err = auditlog.Open(accessLogModule, conf.Local); err != nil {
+			return nil, err
+		}
+	}
+	if conf.TargetBucket != "" {
+		l.wg.Add(1)
+		go l.deliveryLoop()
+	}
+	return l, nil
+}
+
+// Log writes the access log entry of the request after the response is served.
+func (l *AccessLogger) Log(w http.ResponseWriter, r *http.Request) {
+	line := formatAccessLog(w, r, time.Now().UTC())
+	if l.logFile != nil {
+		if err := l.logFile.Log(line); err != nil {
+			log.LogErrorf("access log: write local log fail: %v", err)
+		}
+	}
+	if l.conf.TargetBucket == "" {
+		return
+	}
+	l.mu.Lock()
+	l.buf.Write(line)
+	full := l.buf.Len() >= l.conf.DeliverySize
+	l.mu.Unlock()
+	if full {
+		select {
+		case l.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (l *AccessLogger) deliveryLoop() {
+	defer l.wg.Done()
+	ticker := time.NewTicker(time.Duration(l.conf.DeliveryInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.flushCh:
+		case <-l.stopCh:
+			l.flush()
+			return
+		}
+		l.flush()
+	}
+}
+
+// flush delivers the buffered entries to the target bucket as a log object, the entries
+// are dropped if failed to deliver as they have been written to the local log.
+func (l *AccessLogger) flush() {
+	l.mu.Lock()
+	if l.buf.Len() == 0 {
+		l.mu.Unlock()
+		return
+	}
+	data := l.buf.Bytes()
+	l.buf = new(bytes.Buffer)
+	l.mu.Unlock()
+
+	key := accessLogObjectKey(l.conf.TargetPrefix, time.Now().UTC())
+	if err := l.deliver(l.conf.TargetBucket, key, data); err != nil {
+		log.LogErrorf("access log: deliver fail: bucket(%v) key(%v) size(%v) err(%v)",
+			l.conf.TargetBucket, key, len(data), err)
+		return
+	}
+	log.LogDebugf("access log: delivered: bucket(%v) key(%v) size(%v)", l.conf.TargetBucket, key, len(data))
+}
+
+func (l *AccessLogger) Close() error {
+	close(l.stopCh)
+	l.wg.Wait()
+	if l.logFile != nil {
+		return l.logFile.Close()
+	}
+	return nil
+}
+
+// accessLogObjectKey returns the key of the log object, which is TargetPrefixYYYY-mm-DD-HH-MM-SS-UniqueString.
+func accessLogObjectKey(prefix string, t time.Time) string {
+	unique := make([]byte, 8)
+	_, _ = rand.Read(unique)
+	return prefix + t.Format(accessLogKeyTime) + "-" + strings.ToUpper(hex.EncodeToString(unique))
+}
+
+// formatAccessLog formats the entry of the request in the format of the AWS server access log:
+// BucketOwner Bucket Time RemoteIP Requester RequestID Operation Key Request-URI HTTPStatus ErrorCode
+// BytesSent ObjectSize TotalTime TurnAroundTime Referer User-Agent VersionId HostId SignatureVersion
+// CipherSuite AuthenticationType HostHeader TLSVersion
+func formatAccessLog(w http.ResponseWriter, r *http.Request, now time.Time) []byte {
+	var (
+		statusCode = http.StatusOK
+		bytesSent  int64
+		startTime  = now
+	)
+	if rs, ok := w.(*ResponseStater); ok {
+		statusCode = rs.StatusCode
+		bytesSent = rs.Written
+		startTime = rs.StartTime
+	}
+	param := ParseRequestParam(r)
+	sigVersion, authType := accessLogAuthInfo(r)
+	var objectSize string
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		objectSize = strconv.FormatInt(r.ContentLength, 10)
+	}
+	var cipherSuite, tlsVersion string
+	if r.TLS != nil {
+		cipherSuite = tls.CipherSuiteName(r.TLS.CipherSuite)
+		tlsVersion = accessLogTLSVersion(r.TLS.Version)
+	}
+
+	buf := new(bytes.Buffer)
+	fields := []string{
+		accessLogField(param.Owner()),
+		accessLogField(param.Bucket()),
+		"[" + startTime.Format(accessLogTime) + "]",
+		accessLogField(getRequestIP(r)),
+		accessLogField(param.Requester()),
+		accessLogField(param.RequestID()),
+		accessLogOperation(r, param),
+		accessLogField(strings.TrimPrefix(param.Object(), "/")),
+		strconv.Quote(r.Method + " " + r.URL.RequestURI() + " " + r.Proto),
+		strconv.Itoa(statusCode),
+		accessLogField(getResponseErrorCode(r)),
+		strconv.FormatInt(bytesSent, 10),
+		accessLogField(objectSize),
+		strconv.FormatInt(now.Sub(startTime).Milliseconds(), 10),
+		"-",
+		accessLogQuotedField(r.Referer()),
+		accessLogQuotedField(r.UserAgent()),
+		"-",
+		"-",
+		accessLogField(sigVersion),
+		accessLogField(cipherSuite),
+		accessLogField(authType),
+		accessLogField(r.Host),
+		accessLogField(tlsVersion),
+	}
+	buf.WriteString(strings.Join(fields, " "))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func accessLogField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, " ", "%20")
+}
+
+func accessLogQuotedField(s string) string {
+	if s == "" {
+		return "\"-\""
+	}
+	return strconv.Quote(s)
+}
+
+// accessLogOperation returns the operation in the form of REST.HTTP_method.resource_type,
+// e.g. REST.GET.OBJECT, REST.PUT.ACL, REST.COPY.PART.
+func accessLogOperation(r *http.Request, param *RequestParam) string {
+	method := r.Method
+	if r.Header.Get(XAmzCopySource) != "" && method == http.MethodPut {
+		method = "COPY"
+	}
+	query := r.URL.Query()
+	resource := "OBJECT"
+	switch {
+	case param.Bucket() == "":
+		resource = "SERVICE"
+	case query.Get(ParamUploadId) != "" && query.Get(ParamPartNumber) != "":
+		resource = "PART"
+	case query.Get(ParamUploadId) != "":
+		resource = "UPLOAD"
+	case param.Object() == "":
+		resource = "BUCKET"
+	}
+	for _, sub := range accessLogSubResources {
+		if _, ok := query[sub]; ok {
+			resource = strings.ToUpper(strings.ReplaceAll(sub, "-", "_"))
+			break
+		}
+	}
+	return "REST." + method + "." + resource
+}
+
+// accessLogAuthInfo returns the signature version and the authentication type of the request.
+func accessLogAuthInfo(r *http.Request) (sigVersion, authType string) {
+	query := r.URL.Query()
+	switch {
+	case strings.HasPrefix(r.Header.Get(Authorization), signV4Algorithm):
+		return "SigV4", "AuthHeader"
+	case r.Header.Get(Authorization) != "":
+		return "SigV2", "AuthHeader"
+	case query.Get(XAmzSignature) != "":
+		return "SigV4", "QueryString"
+	case query.Get(Signature) != "":
+		return "SigV2", "QueryString"
+	}
+	return "", ""
+}
+
+func accessLogTLSVersion(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLSv1"
+	case tls.VersionTLS11:
+		return "TLSv1.1"
+	case tls.VersionTLS12:
+		return "TLSv1.2"
+	case tls.VersionTLS13:
+		return "TLSv1.3"
+	}
+	return ""
+}
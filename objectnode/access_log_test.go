@@ -0,0 +1,126 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccessLogTestRequest(t *testing.T, method, url, bucket, object string) *http.Request {
+	r, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	r = mux.SetURLVars(r, map[string]string{
+		ContextKeyBucket:    bucket,
+		ContextKeyObject:    object,
+		ContextKeyRequestID: "7ab2d0f1",
+		ContextKeyRequester: "user1",
+		ContextKeyOwner:     "owner1",
+	})
+	SetRequestAction(r, proto.OSSGetObjectAction)
+	r.RemoteAddr = "10.1.1.1:3456"
+	return r
+}
+
+func TestFormatAccessLog(t *testing.T) {
+	r := newAccessLogTestRequest(t, http.MethodGet, "http://bucket.cfs.local/dir/a%20b.txt", "bucket", "dir/a b.txt")
+	r.Header.Set(Authorization, signV4Algorithm+" Credential=ak/20230601/cfs/s3/aws4_request")
+	r.Header.Set("User-Agent", "aws-cli/2.0")
+	w := NewResponseStater(httptest.NewRecorder())
+	w.StartTime = time.Date(2023, 6, 1, 8, 21, 25, 0, time.UTC)
+	NoSuchKey.ServeResponse(w, r)
+
+	line := string(formatAccessLog(w, r, w.StartTime.Add(15*time.Millisecond)))
+	require.True(t, strings.HasSuffix(line, "\n"))
+	require.Equal(t, `owner1 bucket [01/Jun/2023:08:21:25 +0000] 10.1.1.1 user1 7ab2d0f1 REST.GET.OBJECT dir/a%20b.txt `+
+		`"GET /dir/a%20b.txt HTTP/1.1" 404 NoSuchKey `+strconv.FormatInt(w.Written, 10)+` - 15 - "-" "aws-cli/2.0" - - `+
+		`SigV4 - AuthHeader bucket.cfs.local -`+"\n", line)
+}
+
+func TestAccessLogOperation(t *testing.T) {
+	testCases := []struct {
+		method, url, bucket, object string
+		copy                        bool
+		expect                      string
+	}{
+		{http.MethodGet, "http://cfs.local/", "", "", false, "REST.GET.SERVICE"},
+		{http.MethodGet, "http://bucket.cfs.local/?prefix=a", "bucket", "", false, "REST.GET.BUCKET"},
+		{http.MethodPut, "http://bucket.cfs.local/?acl", "bucket", "", false, "REST.PUT.ACL"},
+		{http.MethodPut, "http://bucket.cfs.local/key", "bucket", "key", false, "REST.PUT.OBJECT"},
+		{http.MethodPut, "http://bucket.cfs.local/key", "bucket", "key", true, "REST.COPY.OBJECT"},
+		{http.MethodPost, "http://bucket.cfs.local/key?uploads", "bucket", "key", false, "REST.POST.UPLOADS"},
+		{http.MethodPut, "http://bucket.cfs.local/key?uploadId=1&partNumber=2", "bucket", "key", true, "REST.COPY.PART"},
+		{http.MethodPost, "http://bucket.cfs.local/key?uploadId=1", "bucket", "key", false, "REST.POST.UPLOAD"},
+		{http.MethodGet, "http://bucket.cfs.local/key?object-lock", "bucket", "key", false, "REST.GET.OBJECT_LOCK"},
+	}
+	for _, tc := range testCases {
+		r := newAccessLogTestRequest(t, tc.method, tc.url, tc.bucket, tc.object)
+		if tc.copy {
+			r.Header.Set(XAmzCopySource, "/src/key")
+		}
+		require.Equal(t, tc.expect, accessLogOperation(r, ParseRequestParam(r)), tc.url)
+	}
+}
+
+func TestAccessLoggerDelivery(t *testing.T) {
+	_, err := NewAccessLogger(AccessLogConfig{}, nil)
+	require.Error(t, err)
+
+	var (
+		lock      sync.Mutex
+		delivered = make(map[string]string)
+	)
+	deliver := func(bucket, key string, data []byte) error {
+		lock.Lock()
+		defer lock.Unlock()
+		require.Equal(t, "logs", bucket)
+		require.True(t, strings.HasPrefix(key, "access/"))
+		delivered[key] = string(data)
+		return nil
+	}
+	l, err := NewAccessLogger(AccessLogConfig{TargetBucket: "logs", TargetPrefix: "access/", DeliverySize: 1}, deliver)
+	require.NoError(t, err)
+
+	r := newAccessLogTestRequest(t, http.MethodGet, "http://bucket.cfs.local/key", "bucket", "key")
+	l.Log(NewResponseStater(httptest.NewRecorder()), r)
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(delivered) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the buffered entries are delivered at close
+	l.conf.DeliverySize = 1 << 20
+	l.Log(NewResponseStater(httptest.NewRecorder()), r)
+	l.Log(NewResponseStater(httptest.NewRecorder()), r)
+	require.NoError(t, l.Close())
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, delivered, 2)
+	for _, data := range delivered {
+		for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+			require.Contains(t, line, " REST.GET.OBJECT key ")
+		}
+	}
+}
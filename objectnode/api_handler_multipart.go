@@ -116,6 +116,11 @@ func (o *ObjectNode) createMultipleUploadHandler(w http.ResponseWriter, r *http.
 			GetRequestID(r), acl, err)
 		return
 	}
+	// Check 'x-amz-server-side-encryption' header
+	sse, errorCode := ParseSSEOption(r.Header)
+	if errorCode != nil {
+		return
+	}
 	opt := &PutFileOption{
 		MIMEType:     contentType,
 		Disposition:  contentDisposition,
@@ -124,6 +129,7 @@ func (o *ObjectNode) createMultipleUploadHandler(w http.ResponseWriter, r *http.
 		CacheControl: cacheControl,
 		Expires:      expires,
 		ACL:          acl,
+		SSE:          sse,
 	}
 
 	var uploadID string
@@ -145,6 +151,9 @@ func (o *ObjectNode) createMultipleUploadHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	if sse != nil {
+		setSSEResponseHeader(w, sse.Algorithm, sse.KeyID)
+	}
 	writeSuccessResponseXML(w, response)
 }
 
@@ -271,6 +280,7 @@ func (o *ObjectNode) uploadPartHandler(w http.ResponseWriter, r *http.Request) {
 
 	// write header to response
 	w.Header()[ETag] = []string{"\"" + fsFileInfo.ETag + "\""}
+	setSSEResponseHeader(w, fsFileInfo.SSEAlgorithm, fsFileInfo.SSEKeyID)
 }
 
 // Upload part copy
@@ -346,7 +356,7 @@ func (o *ObjectNode) uploadPartCopyHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	start := time.Now()
-	srcFileInfo, _, err := srcVol.ObjectMeta(srcObject)
+	srcFileInfo, srcXAttr, err := srcVol.ObjectMeta(srcObject)
 	span.AppendTrackLog("meta.r", start, err)
 	if err != nil {
 		log.LogErrorf("uploadPartCopyHandler: get fileMeta fail: requestId(%v) srcVol(%v) path(%v) err(%v)",
@@ -361,6 +371,12 @@ func (o *ObjectNode) uploadPartCopyHandler(w http.ResponseWriter, r *http.Reques
 	if errorCode != nil {
 		return
 	}
+	srcEncryption, err := LoadObjectEncryption(srcXAttr.XAttrs)
+	if err != nil {
+		log.LogErrorf("uploadPartCopyHandler: load src encryption fail: requestId(%v) srcVol(%v) path(%v) err(%v)",
+			GetRequestID(r), srcBucket, srcObject, err)
+		return
+	}
 
 	// step4: extract range params
 	copyRange := r.Header.Get(XAmzCopySourceRange)
@@ -382,7 +398,12 @@ func (o *ObjectNode) uploadPartCopyHandler(w http.ResponseWriter, r *http.Reques
 	}
 	reader, writer := io.Pipe()
 	go func() {
-		err = srcVol.readFile(srcFileInfo.Inode, size, srcObject, writer, fb, cl)
+		// the data of the source is decrypted, and encrypted again if the part is encrypted
+		var dst io.Writer = writer
+		if srcEncryption != nil {
+			dst = srcEncryption.DecryptWriter(writer, fb)
+		}
+		err = srcVol.readFile(srcFileInfo.Inode, size, srcObject, dst, fb, cl)
 		if err != nil {
 			log.LogErrorf("uploadPartCopyHandler: read srcObj err(%v): requestId(%v) srcVol(%v) path(%v)",
 				err, GetRequestID(r), srcBucket, srcObject)
@@ -419,6 +440,7 @@ func (o *ObjectNode) uploadPartCopyHandler(w http.ResponseWriter, r *http.Reques
 
 	Etag := "\"" + fsFileInfo.ETag + "\""
 	w.Header()[ETag] = []string{Etag}
+	setSSEResponseHeader(w, fsFileInfo.SSEAlgorithm, fsFileInfo.SSEKeyID)
 	response := NewS3CopyPartResult(Etag, fsFileInfo.CreateTime.UTC().Format(time.RFC3339)).String()
 
 	writeSuccessResponseXML(w, []byte(response))
@@ -781,7 +803,9 @@ func (o *ObjectNode) completeMultipartUploadHandler(w http.ResponseWriter, r *ht
 			GetRequestID(r), completeResult, ierr)
 	}
 
+	setSSEResponseHeader(w, fsFileInfo.SSEAlgorithm, fsFileInfo.SSEKeyID)
 	writeSuccessResponseXML(w, response)
+	o.notifyEvent(r, vol, EventObjectCreatedCompleteMultipartUpload, param.Object(), fsFileInfo.Size, fsFileInfo.ETag)
 }
 
 // Abort multipart
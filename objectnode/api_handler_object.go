@@ -172,6 +172,14 @@ func (o *ObjectNode) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// load the data key of the encrypted object
+	encryption, err := LoadObjectEncryption(xattr.XAttrs)
+	if err != nil {
+		log.LogErrorf("getObjectHandler: load object encryption fail: requestId(%v) volume(%v) path(%v) err(%v)",
+			GetRequestID(r), vol.Name(), param.Object(), err)
+		return
+	}
+
 	// validate and fix range
 	if isRangeRead && rangeUpper > uint64(fileInfo.Size)-1 {
 		rangeUpper = uint64(fileInfo.Size) - 1
@@ -222,6 +230,10 @@ func (o *ObjectNode) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set(XAmzObjectLockMode, ComplianceMode)
 		w.Header().Set(XAmzObjectLockRetainUntilDate, fileInfo.RetainUntilDate)
 	}
+	if len(fileInfo.StorageClass) > 0 {
+		w.Header().Set(XAmzStorageClass, fileInfo.StorageClass)
+	}
+	setSSEResponseHeader(w, fileInfo.SSEAlgorithm, fileInfo.SSEKeyID)
 
 	// check request is whether contain param : partNumber
 	partNumber := r.URL.Query().Get(ParamPartNumber)
@@ -287,6 +299,9 @@ func (o *ObjectNode) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		writer = w
 	}
+	if encryption != nil {
+		writer = encryption.DecryptWriter(writer, offset)
+	}
 
 	// read file
 	start = time.Now()
@@ -490,6 +505,10 @@ func (o *ObjectNode) headObjectHandler(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set(XAmzObjectLockMode, ComplianceMode)
 		w.Header().Set(XAmzObjectLockRetainUntilDate, fileInfo.RetainUntilDate)
 	}
+	if len(fileInfo.StorageClass) > 0 {
+		w.Header().Set(XAmzStorageClass, fileInfo.StorageClass)
+	}
+	setSSEResponseHeader(w, fileInfo.SSEAlgorithm, fileInfo.SSEKeyID)
 
 	// check request is whether contain param : partNumber
 	partNumber := r.URL.Query().Get(ParamPartNumber)
@@ -624,16 +643,13 @@ func (o *ObjectNode) deleteObjectsHandler(w http.ResponseWriter, r *http.Request
 
 	deletedObjects := make([]Deleted, 0, len(deleteReq.Objects))
 	deletedErrors := make([]Error, 0)
+	allowedKeys := make([]string, 0, len(deleteReq.Objects))
 	start := time.Now()
 	for _, object := range deleteReq.Objects {
 		result := POLICY_UNKNOW
 		if policy != nil && !policy.IsEmpty() {
-			conditionCheck := map[string]string{
-				SOURCEIP: param.sourceIP,
-				REFERER:  param.r.Referer(),
-				KEYNAME:  object.Key,
-				HOST:     param.r.Host,
-			}
+			conditionCheck := newConditionCheck(param)
+			conditionCheck[KEYNAME] = object.Key
 			result = policy.IsAllowed(param, userInfo.UserID, vol.owner, conditionCheck)
 		}
 		if result == POLICY_DENY || (result == POLICY_UNKNOW && !allowByAcl) {
@@ -646,23 +662,35 @@ func (o *ObjectNode) deleteObjectsHandler(w http.ResponseWriter, r *http.Request
 		}
 		log.LogWarnf("deleteObjectsHandler: delete path: requestID(%v) remote(%v) volume(%v) path(%v)",
 			GetRequestID(r), getRequestIP(r), vol.Name(), object.Key)
+		allowedKeys = append(allowedKeys, object.Key)
+	}
+
+	if len(allowedKeys) > 0 {
 		// QPS and Concurrency Limit
 		rateLimit := o.AcquireRateLimiter()
-		if err = rateLimit.AcquireLimitResource(vol.owner, DELETE_OBJECT); err != nil {
+		if err = rateLimit.AcquireLimitResource(vol.owner, param.apiName); err != nil {
 			return
 		}
-		if err1 := vol.DeletePath(object.Key); err1 != nil {
+		errs := vol.DeletePaths(allowedKeys)
+		rateLimit.ReleaseLimitResource(vol.owner, param.apiName)
+		for idx, key := range allowedKeys {
+			err1 := errs[idx]
+			if err1 == nil {
+				o.notifyEvent(r, vol, EventObjectRemovedDelete, key, 0, "")
+				// the successfully deleted objects are not returned in quiet mode
+				if !deleteReq.Quiet {
+					deletedObjects = append(deletedObjects, Deleted{Key: key})
+				}
+				continue
+			}
 			log.LogErrorf("deleteObjectsHandler: delete object failed: requestID(%v) volume(%v) path(%v) err(%v)",
-				GetRequestID(r), vol.Name(), object.Key, err1)
+				GetRequestID(r), vol.Name(), key, err1)
 			if !strings.Contains(err1.Error(), AccessDenied.ErrorMessage) {
-				deletedErrors = append(deletedErrors, Error{Key: object.Key, Code: "InternalError", Message: err1.Error()})
+				deletedErrors = append(deletedErrors, Error{Key: key, Code: "InternalError", Message: err1.Error()})
 			} else {
-				deletedErrors = append(deletedErrors, Error{Key: object.Key, Code: "AccessDenied", Message: err1.Error()})
+				deletedErrors = append(deletedErrors, Error{Key: key, Code: "AccessDenied", Message: err1.Error()})
 			}
-		} else {
-			deletedObjects = append(deletedObjects, Deleted{Key: object.Key})
 		}
-		rateLimit.ReleaseLimitResource(vol.owner, param.apiName)
 	}
 	span.AppendTrackLog("files.d", start, err)
 
@@ -877,6 +905,11 @@ func (o *ObjectNode) copyObjectHandler(w http.ResponseWriter, r *http.Request) {
 
 	// parse user-defined metadata
 	metadata := ParseUserDefinedMetadata(r.Header)
+	// parse server-side encryption of the target
+	sse, errorCode := ParseSSEOption(r.Header)
+	if errorCode != nil {
+		return
+	}
 
 	// copy file
 	opt := &PutFileOption{
@@ -887,6 +920,7 @@ func (o *ObjectNode) copyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		Expires:      expires,
 		ACL:          acl,
 		ObjectLock:   objetLock,
+		SSE:          sse,
 	}
 	start = time.Now()
 	fsFileInfo, err := vol.CopyFile(sourceVol, sourceObject, param.Object(), metadataDirective, opt)
@@ -919,7 +953,9 @@ func (o *ObjectNode) copyObjectHandler(w http.ResponseWriter, r *http.Request) {
 			GetRequestID(r), copyResult, ierr)
 	}
 
+	setSSEResponseHeader(w, fsFileInfo.SSEAlgorithm, fsFileInfo.SSEKeyID)
 	writeSuccessResponseXML(w, response)
+	o.notifyEvent(r, vol, EventObjectCreatedCopy, param.Object(), fsFileInfo.Size, fsFileInfo.ETag)
 }
 
 // List objects v1
@@ -1026,7 +1062,7 @@ func (o *ObjectNode) getBucketV1Handler(w http.ResponseWriter, r *http.Request)
 			LastModified: formatTimeISO(file.ModifyTime),
 			ETag:         wrapUnescapedQuot(file.ETag),
 			Size:         int(file.Size),
-			StorageClass: StorageClassStandard,
+			StorageClass: file.GetStorageClass(),
 			Owner:        bucketOwner,
 		}
 		contents = append(contents, content)
@@ -1192,7 +1228,7 @@ func (o *ObjectNode) getBucketV2Handler(w http.ResponseWriter, r *http.Request)
 				LastModified: formatTimeISO(file.ModifyTime),
 				ETag:         wrapUnescapedQuot(file.ETag),
 				Size:         int(file.Size),
-				StorageClass: StorageClassStandard,
+				StorageClass: file.GetStorageClass(),
 				Owner:        bucketOwner,
 			}
 			contents = append(contents, content)
@@ -1353,6 +1389,11 @@ func (o *ObjectNode) putObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	// Checking user-defined metadata
 	metadata := ParseUserDefinedMetadata(r.Header)
+	// Check 'x-amz-server-side-encryption' header
+	sse, errorCode := ParseSSEOption(r.Header)
+	if errorCode != nil {
+		return
+	}
 	// Audit file write
 	log.LogInfof("Audit: put object: requestID(%v) remote(%v) volume(%v) path(%v) type(%v)",
 		GetRequestID(r), getRequestIP(r), vol.Name(), param.Object(), contentType)
@@ -1375,6 +1416,7 @@ func (o *ObjectNode) putObjectHandler(w http.ResponseWriter, r *http.Request) {
 		Expires:      expires,
 		ACL:          acl,
 		ObjectLock:   objetLock,
+		SSE:          sse,
 	}
 	start := time.Now()
 	fsFileInfo, err := vol.PutObject(param.Object(), reader, opt)
@@ -1396,6 +1438,8 @@ func (o *ObjectNode) putObjectHandler(w http.ResponseWriter, r *http.Request) {
 
 	// set response header
 	w.Header()[ETag] = []string{wrapUnescapedQuot(fsFileInfo.ETag)}
+	setSSEResponseHeader(w, fsFileInfo.SSEAlgorithm, fsFileInfo.SSEKeyID)
+	o.notifyEvent(r, vol, EventObjectCreatedPut, param.Object(), fsFileInfo.Size, fsFileInfo.ETag)
 }
 
 // Post object
@@ -1585,6 +1629,18 @@ func (o *ObjectNode) postObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// server-side encryption fields
+	sseHeader := make(http.Header)
+	for _, name := range []string{XAmzServerSideEncryption, XAmzServerSideEncryptionKeyID} {
+		if value := formReq.MultipartFormValue(name); value != "" {
+			sseHeader.Set(name, value)
+		}
+	}
+	sse, errorCode := ParseSSEOption(sseHeader)
+	if errorCode != nil {
+		return
+	}
+
 	// flow control
 	var reader io.Reader
 	if size > DefaultFlowLimitSize {
@@ -1603,6 +1659,7 @@ func (o *ObjectNode) postObjectHandler(w http.ResponseWriter, r *http.Request) {
 		Expires:      expires,
 		ACL:          aclInfo,
 		ObjectLock:   objetLock,
+		SSE:          sse,
 	}
 	start := time.Now()
 	fsFileInfo, err := vol.PutObject(key, reader, putOpt)
@@ -1625,6 +1682,8 @@ func (o *ObjectNode) postObjectHandler(w http.ResponseWriter, r *http.Request) {
 	// set response header
 	etag := wrapUnescapedQuot(fsFileInfo.ETag)
 	w.Header()[ETag] = []string{etag}
+	setSSEResponseHeader(w, fsFileInfo.SSEAlgorithm, fsFileInfo.SSEKeyID)
+	o.notifyEvent(r, vol, EventObjectCreatedPost, key, fsFileInfo.Size, fsFileInfo.ETag)
 
 	// return response depending on success_action_xxx parameter
 	if successRedirectURL != nil {
@@ -1720,6 +1779,7 @@ func (o *ObjectNode) deleteObjectHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	w.WriteHeader(http.StatusNoContent)
+	o.notifyEvent(r, vol, EventObjectRemovedDelete, param.Object(), 0, "")
 }
 
 // Get object tagging
@@ -97,6 +97,9 @@ func (o *ObjectNode) traceMiddleware(next http.Handler) http.Handler {
 			if o.externalAudit != nil {
 				o.externalAudit.Logger(w, r)
 			}
+			if o.accessLogger != nil {
+				o.accessLogger.Log(w, r)
+			}
 		}()
 
 		requestID, err := generateRequestID()
@@ -75,6 +75,8 @@ type AuditLogConfig struct {
 	// The key of map is a unique identifier of audit
 	Kafka   map[string]KafkaAuditConfig   `json:"kafka,omitempty"`
 	Webhook map[string]WebhookAuditConfig `json:"webhook,omitempty"`
+	// The S3 server access log
+	AccessLog *AccessLogConfig `json:"accessLog,omitempty"`
 }
 
 type ExternalAudit struct {
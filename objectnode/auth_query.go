@@ -17,6 +17,7 @@ package objectnode
 import (
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -126,6 +127,21 @@ func (auth *QueryAuth) parseSignV4() error {
 			// do nothing
 		}
 	}
+	// the date of the credential scope must be the date of the signing time
+	if !strings.HasPrefix(auth.credential.TimeStamp, auth.credential.Date) {
+		return ErrMalformedCredential
+	}
+	// the host header must be signed so that the presigned url is only valid for the endpoint
+	hostSigned := false
+	for _, header := range auth.signedHeaders {
+		if strings.EqualFold(header, Host) {
+			hostSigned = true
+			break
+		}
+	}
+	if !hostSigned {
+		return ErrMissingSignedHeaders
+	}
 
 	return nil
 }
@@ -218,3 +234,51 @@ func (auth *QueryAuth) buildSignatureV4(secretKey string) string {
 
 	return calculateSignature(signingKey, auth.stringToSign)
 }
+
+// PresignV4 presigns the request by the AWS signature V4 in the query parameters and returns the
+// presigned url, which grants the access of the request to anyone until it expires. The host header
+// is always signed, and the other signed headers must be sent with the same values by the requester.
+func PresignV4(r *http.Request, accessKey, secretKey, region string, expires time.Duration, signedHeaders ...string) (string, error) {
+	seconds := int64(expires / time.Second)
+	if seconds < MinSignatureExpires || seconds > MaxSignatureExpires {
+		return "", ErrInvalidRangeExpires
+	}
+	if r.Host == "" {
+		r.Host = r.URL.Host
+	}
+	headers := []string{strings.ToLower(Host)}
+	for _, header := range signedHeaders {
+		if header = strings.ToLower(header); header != headers[0] {
+			headers = append(headers, header)
+		}
+	}
+	sort.Strings(headers)
+
+	now := time.Now().UTC()
+	auth := &QueryAuth{request: r}
+	auth.version = signatureV4
+	auth.algorithm = signV4Algorithm
+	auth.signedHeaders = headers
+	auth.credential = &Credential{
+		Region:    region,
+		Service:   "s3",
+		Request:   "aws4_request",
+		Date:      now.Format(DateLayout),
+		TimeStamp: now.Format(ISO8601Format),
+		Expires:   seconds,
+		AccessKey: accessKey,
+	}
+	cred := auth.credential
+	query := r.URL.Query()
+	query.Set(XAmzAlgorithm, auth.algorithm)
+	query.Set(XAmzCredential, accessKey+"/"+buildScope(cred.Date, cred.Region, cred.Service, cred.Request))
+	query.Set(XAmzDate, cred.TimeStamp)
+	query.Set(XAmzExpires, strconv.FormatInt(seconds, 10))
+	query.Set(XAmzSignedHeaders, strings.Join(headers, ";"))
+	query.Del(XAmzSignature)
+	r.URL.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	query.Set(XAmzSignature, auth.buildSignatureV4(secretKey))
+	r.URL.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	return r.URL.String(), nil
+}
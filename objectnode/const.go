@@ -85,6 +85,8 @@ const (
 	XAmzSecurityToken               = "X-Amz-Security-Token" // #nosec G101
 	XAmzObjectLockMode              = "X-Amz-Object-Lock-Mode"
 	XAmzObjectLockRetainUntilDate   = "X-Amz-Object-Lock-Retain-Until-Date"
+	XAmzServerSideEncryption        = "x-amz-server-side-encryption"
+	XAmzServerSideEncryptionKeyID   = "x-amz-server-side-encryption-aws-kms-key-id"
 
 	HeaderNameXAmzDecodedContentLength = "x-amz-decoded-content-length"
 )
@@ -95,6 +97,7 @@ const (
 	ValueContentTypeStream    = "application/octet-stream"
 	ValueContentTypeXML       = "application/xml"
 	ValueContentTypeJSON      = "application/json"
+	ValueContentTypeText      = "text/plain"
 	ValueContentTypeDirectory = "application/directory"
 	ValueMultipartFormData    = "multipart/form-data"
 )
@@ -154,6 +157,12 @@ const (
 	XAttrKeyOSSLock         = "oss:lock"
 	XAttrKeyOSSCacheControl = "oss:cache"
 	XAttrKeyOSSExpires      = "oss:expires"
+	XAttrKeyOSSSSE          = "oss:sse"
+	XAttrKeyOSSSSEKeyID     = "oss:sse-key-id"
+	XAttrKeyOSSSSEKey       = "oss:sse-key"
+	XAttrKeyOSSSSEIV        = "oss:sse-iv"
+	XAttrKeyOSSSSEParts     = "oss:sse-parts"
+	XAttrKeyOSSNotification = "oss:notification"
 
 	// Deprecated
 	XAttrKeyOSSETagDeprecated = "oss:tag"
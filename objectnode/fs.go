@@ -34,6 +34,18 @@ type FSFileInfo struct {
 	Expires         string
 	Metadata        map[string]string `graphql:"-"` // User-defined metadata
 	RetainUntilDate string
+	StorageClass    string
+	SSEAlgorithm    string
+	SSEKeyID        string
+}
+
+// GetStorageClass returns the storage class of the object, the objects which are not
+// transitioned by the lifecycle are of the standard storage class.
+func (info *FSFileInfo) GetStorageClass() string {
+	if info.StorageClass == "" {
+		return StorageClassStandard
+	}
+	return info.StorageClass
 }
 
 type Prefixes []string
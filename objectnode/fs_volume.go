@@ -92,6 +92,7 @@ type PutFileOption struct {
 	CacheControl string
 	Expires      string
 	ObjectLock   *ObjectLockConfig
+	SSE          *SSEOption
 }
 
 type ListFilesV1Option struct {
@@ -198,6 +199,12 @@ func (v *Volume) loadOSSMeta() {
 		return
 	}
 	v.metaLoader.storeObjectLock(objectlock)
+
+	var notification *NotificationConfiguration
+	if notification, err = v.loadBucketNotification(); err != nil {
+		return
+	}
+	v.metaLoader.storeNotification(notification)
 	v.metaLoader.setSynced()
 }
 
@@ -277,6 +284,21 @@ func (v *Volume) loadObjectLock() (configuration *ObjectLockConfig, err error) {
 	return configuration, nil
 }
 
+func (v *Volume) loadBucketNotification() (configuration *NotificationConfiguration, err error) {
+	var raw []byte
+	if raw, err = v.store.Get(v.name, bucketRootPath, XAttrKeyOSSNotification); err != nil {
+		return
+	}
+	if len(raw) == 0 {
+		return
+	}
+	configuration = &NotificationConfiguration{}
+	if err = xml.Unmarshal(raw, configuration); err != nil {
+		return
+	}
+	return configuration, nil
+}
+
 func (v *Volume) getInodeFromPath(path string) (inode uint64, err error) {
 	if path == "/" {
 		return volumeRootInode, nil
@@ -679,6 +701,15 @@ func (v *Volume) PutObject(path string, reader io.Reader, opt *PutFileOption) (f
 		}
 	}
 
+	var encryption *ObjectEncryption
+	if opt != nil && opt.SSE != nil {
+		if encryption, err = NewObjectEncryption(opt.SSE); err != nil {
+			log.LogErrorf("PutObject: new object encryption fail: volume(%v) path(%v) sse(%v) err(%v)",
+				v.name, path, opt.SSE.Algorithm, err)
+			return
+		}
+	}
+
 	// Intermediate data during the writing of new versions is managed through invisible files.
 	// This file has only inode but no dentry. In this way, this temporary file can be made invisible
 	// in the true sense. In order to avoid the adverse impact of other user operations on temporary data.
@@ -700,6 +731,13 @@ func (v *Volume) PutObject(path string, reader io.Reader, opt *PutFileOption) (f
 	}()
 
 	md5Hash := md5.New()
+	// the etag of the encrypted object is the md5 of the plain data
+	writeHash := hash.Hash(md5Hash)
+	if encryption != nil {
+		reader = encryption.EncryptReader(io.TeeReader(reader, md5Hash))
+		writeHash = nil
+	}
+
 	if err = v.ec.OpenStream(invisibleTempDataInode.Inode); err != nil {
 		log.LogErrorf("PutObject: open stream fail: volume(%v) path(%v) inode(%v) err(%v)",
 			v.name, path, invisibleTempDataInode.Inode, err)
@@ -713,13 +751,13 @@ func (v *Volume) PutObject(path string, reader io.Reader, opt *PutFileOption) (f
 	}()
 
 	if proto.IsCold(v.volType) {
-		if _, err = v.ebsWrite(invisibleTempDataInode.Inode, reader, md5Hash); err != nil {
+		if _, err = v.ebsWrite(invisibleTempDataInode.Inode, reader, writeHash); err != nil {
 			log.LogErrorf("PutObject: ebs write fail: volume(%v) path(%v) inode(%v) err(%v)",
 				v.name, path, invisibleTempDataInode.Inode, err)
 			return
 		}
 	} else {
-		if _, err = v.streamWrite(invisibleTempDataInode.Inode, reader, md5Hash); err != nil {
+		if _, err = v.streamWrite(invisibleTempDataInode.Inode, reader, writeHash); err != nil {
 			log.LogErrorf("PutObject: stream write fail: volume(%v) path(%v) inode(%v) err(%v)",
 				v.name, path, invisibleTempDataInode.Inode, err)
 			return
@@ -774,6 +812,11 @@ func (v *Volume) PutObject(path string, reader io.Reader, opt *PutFileOption) (f
 	if opt != nil && opt.ObjectLock != nil && opt.ObjectLock.ToRetention() != nil {
 		attr.XAttrs[XAttrKeyOSSLock] = formatRetentionDateStr(finalInode.ModifyTime, opt.ObjectLock.ToRetention())
 	}
+	if encryption != nil {
+		for key, value := range encryption.XAttrs() {
+			attr.XAttrs[key] = value
+		}
+	}
 
 	// If user-defined metadata have been specified, use extend attributes for storage.
 	if opt != nil && len(opt.Metadata) > 0 {
@@ -802,6 +845,10 @@ func (v *Volume) PutObject(path string, reader io.Reader, opt *PutFileOption) (f
 		ETag:       etagValue.ETag(),
 		Inode:      finalInode.Inode,
 	}
+	if encryption != nil {
+		fsInfo.SSEAlgorithm = encryption.Algorithm
+		fsInfo.SSEKeyID = encryption.KeyID
+	}
 
 	// apply new inode to dentry
 	err = v.applyInodeToDEntry(parentId, lastPathItem.Name, invisibleTempDataInode.Inode, false, fixedPath)
@@ -926,6 +973,127 @@ func (v *Volume) DeletePath(path string) (err error) {
 	return
 }
 
+// DeletePaths deletes objects in batch and returns error of each path in the same order.
+// Files of the same parent directory are deleted by one batch request to metanode,
+// directories and files not supported by batch deletion are deleted one by one after files.
+func (v *Volume) DeletePaths(paths []string) []error {
+	errs := make([]error, len(paths))
+	objetLock, err := v.metaLoader.loadObjectLock()
+	if err != nil {
+		log.LogErrorf("DeletePaths: load volume objetLock: volume(%v) err(%v)", v.name, err)
+		for idx := range errs {
+			errs[idx] = err
+		}
+		return errs
+	}
+	if objetLock != nil {
+		// delete with condition one by one when objectlock is open
+		for idx := range paths {
+			errs[idx] = v.DeletePath(paths[idx])
+		}
+		return errs
+	}
+
+	deleter := &batchPathDeleter{
+		lookup: func(path string) (uint64, uint64, string, os.FileMode, error) {
+			return v.recursiveLookupTarget(path, false)
+		},
+		batchDelete: func(parent uint64, dentries []proto.Dentry, paths []string) ([]error, error) {
+			errs, err := v.mw.BatchDelete_ll(parent, dentries, paths)
+			if err != nil {
+				log.LogWarnf("DeletePaths: batch delete failed and delete one by one: volume(%v) parentID(%v) count(%v) err(%v)",
+					v.name, parent, len(dentries), err)
+			}
+			return errs, err
+		},
+		deleteOne: v.DeletePath,
+		deleted: func(parent uint64, dentry proto.Dentry, path string) {
+			log.LogInfof("Audit: DeletePath: volume(%v) path(%v) inode(%v) batch(true)", v.name, path, dentry.Inode)
+			if err := v.ec.EvictStream(dentry.Inode); err != nil {
+				log.LogWarnf("DeletePaths EvictStream: path(%v) inode(%v)", path, dentry.Inode)
+			}
+			deleteDentryCache(parent, dentry.Name, v.name)
+		},
+		batchDeleted: func(parent uint64) {
+			deleteAttrCache(parent, v.name)
+		},
+	}
+	return deleter.deletePaths(paths)
+}
+
+// batchPathDeleter groups files by parent directory to delete them in batch,
+// the operations on volume are replaceable in tests.
+type batchPathDeleter struct {
+	lookup       func(path string) (parent, ino uint64, name string, mode os.FileMode, err error)
+	batchDelete  func(parent uint64, dentries []proto.Dentry, paths []string) ([]error, error)
+	deleteOne    func(path string) error
+	deleted      func(parent uint64, dentry proto.Dentry, path string)
+	batchDeleted func(parent uint64)
+}
+
+func (d *batchPathDeleter) deletePaths(paths []string) []error {
+	errs := make([]error, len(paths))
+	deleteOneByOne := func(indexes []int) {
+		for _, idx := range indexes {
+			errs[idx] = d.deleteOne(paths[idx])
+		}
+	}
+
+	type fileBatch struct {
+		indexes  []int
+		dentries []proto.Dentry
+		paths    []string
+	}
+	batches := make(map[uint64]*fileBatch)
+	parents := make([]uint64, 0)
+	dirs := make([]int, 0)
+	for idx, path := range paths {
+		parent, ino, name, mode, err := d.lookup(path)
+		if err != nil {
+			if err != syscall.ENOENT {
+				errs[idx] = err
+			}
+			continue
+		}
+		if mode.IsDir() {
+			dirs = append(dirs, idx)
+			continue
+		}
+		batch, ok := batches[parent]
+		if !ok {
+			batch = &fileBatch{}
+			batches[parent] = batch
+			parents = append(parents, parent)
+		}
+		batch.indexes = append(batch.indexes, idx)
+		batch.dentries = append(batch.dentries, proto.Dentry{Name: name, Inode: ino, Type: proto.Mode(mode)})
+		batch.paths = append(batch.paths, path)
+	}
+
+	for _, parent := range parents {
+		batch := batches[parent]
+		batchErrs, err := d.batchDelete(parent, batch.dentries, batch.paths)
+		if err != nil {
+			deleteOneByOne(batch.indexes)
+			continue
+		}
+		retries := make([]int, 0)
+		for i, idx := range batch.indexes {
+			if batchErrs[i] != nil {
+				// the cached dentry may be stale, retry with lookup from metanode
+				retries = append(retries, idx)
+				continue
+			}
+			d.deleted(parent, batch.dentries[i], paths[idx])
+		}
+		d.batchDeleted(parent)
+		deleteOneByOne(retries)
+	}
+
+	deleteOneByOne(dirs)
+	return errs
+}
+
 func (v *Volume) InitMultipart(path string, opt *PutFileOption) (multipartID string, err error) {
 	defer func() {
 		log.LogInfof("Audit: InitMultipart: volume(%v) path(%v) multipartID(%v) err(%v)", v.name, path, multipartID, err)
@@ -963,6 +1131,18 @@ func (v *Volume) InitMultipart(path string, opt *PutFileOption) (multipartID str
 	if opt != nil && opt.ACL != nil {
 		extend[XAttrKeyOSSACL] = opt.ACL.Encode()
 	}
+	// If encryption have been specified, the data key of the object is stored with the session.
+	if opt != nil && opt.SSE != nil {
+		var encryption *ObjectEncryption
+		if encryption, err = NewObjectEncryption(opt.SSE); err != nil {
+			log.LogErrorf("InitMultipart: new object encryption fail: volume(%v) path(%v) sse(%v) err(%v)",
+				v.name, path, opt.SSE.Algorithm, err)
+			return
+		}
+		for key, value := range encryption.XAttrs() {
+			extend[key] = value
+		}
+	}
 
 	if v.mw.EnableQuota {
 		var parentId uint64
@@ -998,6 +1178,22 @@ func (v *Volume) WritePart(path string, multipartId string, partId uint16, reade
 	var fInfo *FSFileInfo
 	_, fileName := splitPath(path)
 
+	// the part of the encrypted object is encrypted with the data key of the session
+	var encryption *ObjectEncryption
+	if sseKMS != nil {
+		var multipartInfo *proto.MultipartInfo
+		if multipartInfo, err = v.mw.GetMultipart_ll(path, multipartId); err != nil {
+			log.LogErrorf("WritePart: meta get multipart fail: volume(%v) path(%v) multipartID(%v) err(%v)",
+				v.name, path, multipartId, err)
+			return nil, err
+		}
+		if encryption, err = LoadObjectEncryption(multipartInfo.Extend); err != nil {
+			log.LogErrorf("WritePart: load object encryption fail: volume(%v) path(%v) multipartID(%v) err(%v)",
+				v.name, path, multipartId, err)
+			return nil, err
+		}
+	}
+
 	// create temp file (inode only, invisible for user)
 	var tempInodeInfo *proto.InodeInfo
 	if tempInodeInfo, err = v.mw.InodeCreate_ll(0, DefaultFileMode, 0, 0, nil, make([]uint64, 0), path); err != nil {
@@ -1031,10 +1227,16 @@ func (v *Volume) WritePart(path string, multipartId string, partId uint16, reade
 	}()
 
 	var (
-		size    uint64
-		etag    string
-		md5Hash = md5.New()
+		size      uint64
+		etag      string
+		md5Hash   = md5.New()
+		writeHash = hash.Hash(md5Hash)
 	)
+	if encryption != nil {
+		encryption = encryption.ForPart(partId)
+		reader = encryption.EncryptReader(io.TeeReader(reader, md5Hash))
+		writeHash = nil
+	}
 	if err = v.ec.OpenStream(tempInodeInfo.Inode); err != nil {
 		log.LogErrorf("WritePart: data open stream fail: volume(%v) path(%v) multipartID(%v) partID(%v) inode(%v) err(%v)",
 			v.name, path, multipartId, partId, tempInodeInfo.Inode, err)
@@ -1047,14 +1249,14 @@ func (v *Volume) WritePart(path string, multipartId string, partId uint16, reade
 		}
 	}()
 	if proto.IsCold(v.volType) {
-		if size, err = v.ebsWrite(tempInodeInfo.Inode, reader, md5Hash); err != nil {
+		if size, err = v.ebsWrite(tempInodeInfo.Inode, reader, writeHash); err != nil {
 			log.LogErrorf("WritePart: ebs write fail: volume(%v) inode(%v) multipartID(%v) partID(%v) err(%v)",
 				v.name, tempInodeInfo.Inode, multipartId, partId, err)
 			return nil, err
 		}
 	} else {
 		// Write data to data node
-		if size, err = v.streamWrite(tempInodeInfo.Inode, reader, md5Hash); err != nil {
+		if size, err = v.streamWrite(tempInodeInfo.Inode, reader, writeHash); err != nil {
 			log.LogErrorf("WritePart: stream write fail: volume(%v) inode(%v) multipartID(%v) partID(%v) err(%v)",
 				v.name, tempInodeInfo.Inode, multipartId, partId, err)
 			return nil, err
@@ -1088,6 +1290,10 @@ func (v *Volume) WritePart(path string, multipartId string, partId uint16, reade
 		ETag:       etag,
 		Inode:      tempInodeInfo.Inode,
 	}
+	if encryption != nil {
+		fInfo.SSEAlgorithm = encryption.Algorithm
+		fInfo.SSEKeyID = encryption.KeyID
+	}
 	return fInfo, nil
 }
 
@@ -1281,6 +1487,9 @@ func (v *Volume) CompleteMultipart(path, multipartID string, multipartInfo *prot
 			attrs[key] = value
 		}
 	}
+	if len(extend[XAttrKeyOSSSSE]) > 0 {
+		attrs[XAttrKeyOSSSSEParts] = formatMultipartSSEParts(parts)
+	}
 	if objectLock != nil && objectLock.ToRetention() != nil {
 		attrs[XAttrKeyOSSLock] = formatRetentionDateStr(finalInode.ModifyTime, objectLock.ToRetention())
 	}
@@ -1342,6 +1551,8 @@ func (v *Volume) CompleteMultipart(path, multipartID string, multipartInfo *prot
 		ETag:       etagValue.ETag(),
 		Inode:      finalInode.Inode,
 	}
+	fInfo.SSEAlgorithm = extend[XAttrKeyOSSSSE]
+	fInfo.SSEKeyID = extend[XAttrKeyOSSSSEKeyID]
 
 	return fInfo, nil
 }
@@ -1541,13 +1752,27 @@ func (v *Volume) readFile(inode, inodeSize uint64, path string, writer io.Writer
 		}
 	}()
 
-	if proto.IsHot(v.volType) {
+	if proto.IsHot(v.volType) && !v.isTransitioned(inode) {
 		return v.read(inode, inodeSize, path, writer, offset, size)
 	} else {
 		return v.readEbs(inode, inodeSize, path, writer, offset, size)
 	}
 }
 
+// isTransitioned returns whether the data of the inode of the hot volume is transitioned
+// to the blobstore by the lifecycle.
+func (v *Volume) isTransitioned(inode uint64) bool {
+	if ebsClient == nil {
+		return false
+	}
+	xattr, err := v.mw.XAttrGet_ll(inode, proto.XAttrKeyStorageClass)
+	if err != nil {
+		log.LogWarnf("isTransitioned: get xattr fail: volume(%v) inode(%v) err(%v)", v.name, inode, err)
+		return false
+	}
+	return string(xattr.Get(proto.XAttrKeyStorageClass)) == proto.StorageClassStandardIA
+}
+
 func (v *Volume) readEbs(inode, inodeSize uint64, path string, writer io.Writer, offset, size uint64) error {
 	upper := size + offset
 	if upper > inodeSize {
@@ -1785,6 +2010,9 @@ func (v *Volume) ObjectMeta(path string) (info *FSFileInfo, xattr *proto.XAttrIn
 		Expires:         expires,
 		Metadata:        metadata,
 		RetainUntilDate: retainUntilDate,
+		StorageClass:    string(xattr.Get(proto.XAttrKeyStorageClass)),
+		SSEAlgorithm:    string(xattr.Get(XAttrKeyOSSSSE)),
+		SSEKeyID:        string(xattr.Get(XAttrKeyOSSSSEKeyID)),
 	}
 	return
 }
@@ -2410,8 +2638,8 @@ func (v *Volume) supplyListFileInfo(fileInfos []*FSFileInfo) (err error) {
 		}
 	}
 
-	// Get MD5 information in batches, then update to fileInfos
-	keys := []string{XAttrKeyOSSETag, XAttrKeyOSSETagDeprecated}
+	// Get MD5 information and storage class in batches, then update to fileInfos
+	keys := []string{XAttrKeyOSSETag, XAttrKeyOSSETagDeprecated, proto.XAttrKeyStorageClass}
 	xattrs, err := v.mw.BatchGetXAttr(inodes, keys)
 	if err != nil {
 		log.LogErrorf("supplyListFileInfo: batch get xattr fail, inodes(%v), err(%v)", inodes, err)
@@ -2438,6 +2666,7 @@ func (v *Volume) supplyListFileInfo(fileInfos []*FSFileInfo) (err error) {
 			if len(rawETag) > 0 {
 				etagValue = ParseETagValue(rawETag)
 			}
+			fileInfo.StorageClass = string(xattr.Get(proto.XAttrKeyStorageClass))
 		}
 		if !etagValue.Valid() || etagValue.TS.Before(fileInfo.ModifyTime) {
 			// The ETag is invalid or outdated then generate a new ETag and make update.
@@ -2614,7 +2843,8 @@ func (v *Volume) CopyFile(sv *Volume, sourcePath, targetPath, metaDirective stri
 	var xattr *proto.XAttrInfo
 	// if source path is same with target path, just reset file metadata
 	// source path is same with target path, and metadata directive is not 'REPLACE', objectNode does nothing
-	if targetPath == sourcePath && v.name == sv.name {
+	// the data is rewritten if the object is required to be encrypted
+	if targetPath == sourcePath && v.name == sv.name && (opt == nil || opt.SSE == nil) {
 		if metaDirective != MetadataDirectiveReplace {
 			log.LogInfof("CopyFile: targetPath(%v) is equal with sourcePath(%v),but metaDirective(%v) is not REPLACE",
 				targetPath, sourcePath, metaDirective)
@@ -2746,6 +2976,26 @@ func (v *Volume) CopyFile(sv *Volume, sourcePath, targetPath, metaDirective stri
 		}
 	}
 
+	// the data of the source is decrypted, and encrypted with the new data key of the target if required
+	if xattr, err = sv.mw.XAttrGetAll_ll(sInode); xattr == nil || err != nil {
+		log.LogErrorf("CopyFile: get source xattr fail: volume(%v) source path(%v) inode(%v) err(%v)",
+			sv.name, sourcePath, sInode, err)
+		return
+	}
+	var sEncryption, tEncryption *ObjectEncryption
+	if sEncryption, err = LoadObjectEncryption(xattr.XAttrs); err != nil {
+		log.LogErrorf("CopyFile: load source encryption fail: volume(%v) source path(%v) inode(%v) err(%v)",
+			sv.name, sourcePath, sInode, err)
+		return
+	}
+	if opt != nil && opt.SSE != nil {
+		if tEncryption, err = NewObjectEncryption(opt.SSE); err != nil {
+			log.LogErrorf("CopyFile: new target encryption fail: volume(%v) target path(%v) sse(%v) err(%v)",
+				v.name, targetPath, opt.SSE.Algorithm, err)
+			return
+		}
+	}
+
 	// create target file inode and set target inode to be source file inode
 	if tInodeInfo, err = v.mw.InodeCreate_ll(tParentId, uint32(sMode), 0, 0, nil, make([]uint64, 0), targetPath); err != nil {
 		return
@@ -2771,52 +3021,47 @@ func (v *Volume) CopyFile(sv *Volume, sourcePath, targetPath, metaDirective stri
 		}
 	}()
 
-	// write data to invisibleTempDataInode from source object
+	// write data to invisibleTempDataInode from source object, the source is read by another
+	// goroutine so that reading the source volume is overlapped with writing the target volume
 	var (
 		fileSize    = sInodeInfo.Size
 		md5Hash     = md5.New()
 		md5Value    string
 		readN       int
 		writeN      int
-		readOffset  int
 		writeOffset int
-		readSize    int
-		rest        int
 		buf         = make([]byte, 2*util.BlockSize)
-		hashBuf     = make([]byte, 2*util.BlockSize)
 	)
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	go func() {
+		// the data of the source is decrypted before written to the pipe
+		var dst io.Writer = writer
+		if sEncryption != nil {
+			dst = sEncryption.DecryptWriter(writer, 0)
+		}
+		writer.CloseWithError(sv.readFile(sInode, fileSize, sourcePath, dst, 0, fileSize))
+	}()
 
-	var sctx context.Context
-	var ebsReader *blobstore.Reader
 	var tctx context.Context
 	var ebsWriter *blobstore.Writer
-	if proto.IsCold(sv.volType) {
-		sctx = context.Background()
-		ebsReader = v.getEbsReader(sInode)
-	}
 	if proto.IsCold(v.volType) {
 		tctx = context.Background()
 		ebsWriter = v.getEbsWriter(tInodeInfo.Inode)
 	}
 
 	for {
-		if rest = int(fileSize) - readOffset; rest <= 0 {
-			break
-		}
-		readSize = len(buf)
-		if rest < len(buf) {
-			readSize = rest
-		}
-		buf = buf[:readSize]
-		if proto.IsCold(sv.volType) {
-			readN, err = ebsReader.Read(sctx, buf, readOffset, readSize)
-		} else {
-			readN, err = sv.ec.Read(sInode, buf, readOffset, readSize)
-		}
-		if err != nil && err != io.EOF {
+		readN, err = io.ReadFull(reader, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			log.LogErrorf("CopyFile: read source path fail, volume(%v) path(%v) inode(%v) offset(%v) err(%v)",
+				sv.name, sourcePath, sInode, writeOffset, err)
 			return
 		}
 		if readN > 0 {
+			md5Hash.Write(buf[:readN])
+			if tEncryption != nil {
+				tEncryption.XORKeyStream(buf[:readN], uint64(writeOffset))
+			}
 			if proto.IsCold(v.volType) {
 				writeN, err = ebsWriter.WriteWithoutPool(tctx, writeOffset, buf[:readN])
 			} else {
@@ -2827,13 +3072,10 @@ func (v *Volume) CopyFile(sv *Volume, sourcePath, targetPath, metaDirective stri
 					v.name, targetPath, tInodeInfo.Inode, writeOffset, err)
 				return
 			}
-			readOffset += readN
 			writeOffset += writeN
-			// copy to md5 buffer, and then write to md5
-			copy(hashBuf, buf[:readN])
-			md5Hash.Write(hashBuf[:readN])
 		}
-		if err == io.EOF {
+		if err != nil {
+			// the source has been read to the end
 			err = nil
 			break
 		}
@@ -2871,15 +3113,17 @@ func (v *Volume) CopyFile(sv *Volume, sourcePath, targetPath, metaDirective stri
 		},
 	}
 	targetAttr.XAttrs[XAttrKeyOSSETag] = etagValue.Encode()
+	if tEncryption != nil {
+		for key, val := range tEncryption.XAttrs() {
+			targetAttr.XAttrs[key] = val
+		}
+	}
 
 	// copy source file metadata to write target file metadata
 	if metaDirective != MetadataDirectiveReplace {
-		xattr, err = sv.mw.XAttrGetAll_ll(sInode)
-		if xattr == nil || err != nil {
-			return
-		}
 		for key, val := range xattr.XAttrs {
-			if key == XAttrKeyOSSETag {
+			// the data of the target is not transitioned, and is encrypted with its own data key
+			if key == XAttrKeyOSSETag || key == proto.XAttrKeyStorageClass || isSSEXAttrKey(key) {
 				continue
 			}
 			targetAttr.XAttrs[key] = val
@@ -2951,6 +3195,10 @@ func (v *Volume) CopyFile(sv *Volume, sourcePath, targetPath, metaDirective stri
 		ETag:       md5Value,
 		Inode:      tInodeInfo.Inode,
 	}
+	if tEncryption != nil {
+		info.SSEAlgorithm = tEncryption.Algorithm
+		info.SSEKeyID = tEncryption.KeyID
+	}
 
 	// apply new inode to dentry
 	err = v.applyInodeToDEntry(tParentId, tLastName, tInodeInfo.Inode, false, targetPath)
@@ -13,3 +13,78 @@
 // permissions and limitations under the License.
 
 package objectnode
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchPathDeleter(t *testing.T) {
+	type target struct {
+		parent, ino uint64
+		mode        os.FileMode
+		err         error
+	}
+	targets := map[string]target{
+		"a/1":  {parent: 10, ino: 101},
+		"a/2":  {parent: 10, ino: 102},
+		"b/1":  {parent: 20, ino: 201},
+		"a/3":  {parent: 10, ino: 103},
+		"dir/": {parent: 10, ino: 104, mode: os.ModeDir},
+		"gone": {err: syscall.ENOENT},
+		"bad":  {err: syscall.EIO},
+	}
+
+	var batched, deleted, deletedOne []string
+	var batchedParents []uint64
+	deleter := &batchPathDeleter{
+		lookup: func(path string) (uint64, uint64, string, os.FileMode, error) {
+			tg := targets[path]
+			return tg.parent, tg.ino, path, tg.mode, tg.err
+		},
+		batchDelete: func(parent uint64, dentries []proto.Dentry, paths []string) ([]error, error) {
+			batched = append(batched, paths...)
+			if parent == 20 {
+				return nil, syscall.ENOTSUP
+			}
+			errs := make([]error, len(dentries))
+			for i, den := range dentries {
+				require.Equal(t, targets[paths[i]].ino, den.Inode)
+				if den.Inode == 102 {
+					errs[i] = syscall.ENOENT
+				}
+			}
+			return errs, nil
+		},
+		deleteOne: func(path string) error {
+			deletedOne = append(deletedOne, path)
+			if path == "b/1" {
+				return syscall.EPERM
+			}
+			return nil
+		},
+		deleted: func(parent uint64, dentry proto.Dentry, path string) {
+			require.Equal(t, uint64(10), parent)
+			deleted = append(deleted, path)
+		},
+		batchDeleted: func(parent uint64) {
+			batchedParents = append(batchedParents, parent)
+		},
+	}
+
+	errs := deleter.deletePaths([]string{"a/1", "dir/", "a/2", "gone", "b/1", "bad", "a/3"})
+	// not found is deleted, errors are returned in the order of paths
+	require.Equal(t, []error{nil, nil, nil, nil, syscall.EPERM, syscall.EIO, nil}, errs)
+	// files of the same parent are deleted in one batch
+	require.Equal(t, []string{"a/1", "a/2", "a/3", "b/1"}, batched)
+	require.Equal(t, []string{"a/1", "a/3"}, deleted)
+	require.Equal(t, []uint64{10}, batchedParents)
+	// failed items and failed batch are deleted one by one, directories at last
+	require.Equal(t, []string{"a/2", "b/1", "dir/"}, deletedOne)
+
+	require.Empty(t, deleter.deletePaths(nil))
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	KMSTypeStatic   = "static"
+	KMSTypeExternal = "external"
+
+	dataKeySize = 32
+)
+
+var ErrKMSKeyNotFound = errors.New("kms: master key not found")
+
+// KMS generates the data keys for the objects and seals them with the master keys,
+// only the sealed data keys are stored with the objects.
+type KMS interface {
+	// DefaultKeyID returns the master key which is used if no key is specified.
+	DefaultKeyID() string
+	// GenerateKey returns a new data key, and the data key sealed by the master key.
+	GenerateKey(keyID string) (key, sealedKey []byte, err error)
+	// DecryptKey unseals the data key sealed by the master key.
+	DecryptKey(keyID string, sealedKey []byte) (key []byte, err error)
+}
+
+type KMSConfig struct {
+	Type         string `json:"type"`
+	DefaultKeyID string `json:"defaultKeyID"`
+	// StaticKeys maps the key ids to the base64 encoded 256-bit master keys of the static KMS
+	StaticKeys map[string]string `json:"staticKeys"`
+	// the external KMS which is compatible with the API of the MinIO KES
+	WebhookConfig
+}
+
+func NewKMS(conf KMSConfig) (KMS, error) {
+	if conf.DefaultKeyID == "" {
+		return nil, errors.New("kms: no default key id")
+	}
+	switch conf.Type {
+	case KMSTypeStatic, "":
+		return newStaticKMS(conf.DefaultKeyID, conf.StaticKeys)
+	case KMSTypeExternal:
+		return newExternalKMS(conf.DefaultKeyID, conf.WebhookConfig)
+	default:
+		return nil, fmt.Errorf("kms: unsupported type '%s'", conf.Type)
+	}
+}
+
+type staticKMS struct {
+	defaultKeyID string
+	masterKeys   map[string]cipher.AEAD
+}
+
+func newStaticKMS(defaultKeyID string, keys map[string]string) (*staticKMS, error) {
+	k := &staticKMS{
+		defaultKeyID: defaultKeyID,
+		masterKeys:   make(map[string]cipher.AEAD),
+	}
+	for id, encoded := range keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("kms: master key '%s' is not a base64 encoded 256-bit key", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if k.masterKeys[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := k.masterKeys[defaultKeyID]; !ok {
+		return nil, fmt.Errorf("kms: default key '%s' not found", defaultKeyID)
+	}
+	return k, nil
+}
+
+func (k *staticKMS) DefaultKeyID() string {
+	return k.defaultKeyID
+}
+
+// GenerateKey seals the data key by AES-256-GCM with the key id as the additional data,
+// the sealed key is the nonce followed by the cipher text.
+func (k *staticKMS) GenerateKey(keyID string) (key, sealedKey []byte, err error) {
+	aead, ok := k.masterKeys[keyID]
+	if !ok {
+		return nil, nil, ErrKMSKeyNotFound
+	}
+	key = make([]byte, dataKeySize)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, key); err != nil {
+		return
+	}
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return
+	}
+	sealedKey = aead.Seal(nonce, nonce, key, []byte(keyID))
+	return
+}
+
+func (k *staticKMS) DecryptKey(keyID string, sealedKey []byte) ([]byte, error) {
+	aead, ok := k.masterKeys[keyID]
+	if !ok {
+		return nil, ErrKMSKeyNotFound
+	}
+	if len(sealedKey) < aead.NonceSize() {
+		return nil, errors.New("kms: invalid sealed key")
+	}
+	nonce, sealed := sealedKey[:aead.NonceSize()], sealedKey[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, []byte(keyID))
+}
+
+type externalKMS struct {
+	defaultKeyID string
+	client       *http.Client
+
+	WebhookConfig
+}
+
+func newExternalKMS(defaultKeyID string, conf WebhookConfig) (*externalKMS, error) {
+	if err := conf.FixConfig(); err != nil {
+		return nil, err
+	}
+	client, err := conf.BuildClient()
+	if err != nil {
+		return nil, err
+	}
+	return &externalKMS{
+		defaultKeyID:  defaultKeyID,
+		client:        client,
+		WebhookConfig: conf,
+	}, nil
+}
+
+func (k *externalKMS) DefaultKeyID() string {
+	return k.defaultKeyID
+}
+
+type kmsKeyRequest struct {
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+}
+
+type kmsKeyResponse struct {
+	Plaintext  []byte `json:"plaintext"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (k *externalKMS) GenerateKey(keyID string) (key, sealedKey []byte, err error) {
+	resp := new(kmsKeyResponse)
+	if err = k.call("/v1/key/generate/"+url.PathEscape(keyID), &kmsKeyRequest{}, resp); err != nil {
+		return
+	}
+	if len(resp.Plaintext) != dataKeySize || len(resp.Ciphertext) == 0 {
+		return nil, nil, errors.New("kms: invalid generated key")
+	}
+	return resp.Plaintext, resp.Ciphertext, nil
+}
+
+func (k *externalKMS) DecryptKey(keyID string, sealedKey []byte) ([]byte, error) {
+	resp := new(kmsKeyResponse)
+	if err := k.call("/v1/key/decrypt/"+url.PathEscape(keyID), &kmsKeyRequest{Ciphertext: sealedKey}, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Plaintext) != dataKeySize {
+		return nil, errors.New("kms: invalid decrypted key")
+	}
+	return resp.Plaintext, nil
+}
+
+func (k *externalKMS) call(path string, request, response interface{}) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(k.Endpoint, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(ContentType, ValueContentTypeJSON)
+	if k.Authorization != "" {
+		req.Header.Set(Authorization, k.Authorization)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := k.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrKMSKeyNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("kms: %s returns '%s' statuscode", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
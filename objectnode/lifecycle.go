@@ -18,6 +18,8 @@ import (
 	"encoding/xml"
 	"net/http"
 	"time"
+
+	"github.com/cubefs/cubefs/proto"
 )
 
 const (
@@ -39,6 +41,11 @@ var (
 	LifeCycleErrSameRuleID       = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "Rule ID must be unique. Found same ID for more than one rule.", StatusCode: http.StatusBadRequest}
 	LifeCycleErrDateType         = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "'Date' must be at midnight GMT.", StatusCode: http.StatusBadRequest}
 	LifeCycleErrDaysType         = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "'Days' for Expiration action must be a positive integer.", StatusCode: http.StatusBadRequest}
+	LifeCycleErrTransitionDays   = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "'Days' for Transition action must be a positive integer.", StatusCode: http.StatusBadRequest}
+	LifeCycleErrAbortDays        = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "'DaysAfterInitiation' for AbortIncompleteMultipartUpload action must be a positive integer.", StatusCode: http.StatusBadRequest}
+	LifeCycleErrStorageClass     = &ErrorCode{ErrorCode: "InvalidStorageClass", ErrorMessage: "The storage class you specified is not valid.", StatusCode: http.StatusBadRequest}
+	LifeCycleErrSameStorageClass = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "'StorageClass' must be different for each Transition action in a rule.", StatusCode: http.StatusBadRequest}
+	LifeCycleErrTransitionAfter  = &ErrorCode{ErrorCode: "InvalidArgument", ErrorMessage: "'Days' or 'Date' in the Expiration action must be greater than that in the Transition action.", StatusCode: http.StatusBadRequest}
 	LifeCycleErrMalformedXML     = &ErrorCode{ErrorCode: "MalformedXML", ErrorMessage: "The XML you provided was not well-formed or did not validate against our published schema.", StatusCode: http.StatusBadRequest}
 	NoSuchLifecycleConfiguration = &ErrorCode{ErrorCode: "NoSuchLifecycleConfiguration", ErrorMessage: "The lifecycle configuration does not exist.", StatusCode: http.StatusNotFound}
 )
@@ -50,11 +57,13 @@ type LifeCycle struct {
 }
 
 type Rule struct {
-	XMLName xml.Name    `xml:"Rule"`
-	Expire  *Expiration `xml:"Expiration"`
-	Filter  *Filter     `xml:"Filter"`
-	ID      string      `xml:"ID"`
-	Status  string      `xml:"Status"`
+	XMLName                        xml.Name                        `xml:"Rule"`
+	Expire                         *Expiration                     `xml:"Expiration"`
+	Transitions                    []*Transition                   `xml:"Transition,omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+	Filter                         *Filter                         `xml:"Filter"`
+	ID                             string                          `xml:"ID"`
+	Status                         string                          `xml:"Status"`
 }
 
 type Expiration struct {
@@ -63,6 +72,18 @@ type Expiration struct {
 	Days    *int       `xml:"Days,omitempty"`
 }
 
+type Transition struct {
+	XMLName      xml.Name   `xml:"Transition"`
+	Date         *time.Time `xml:"Date,omitempty"`
+	Days         *int       `xml:"Days,omitempty"`
+	StorageClass string     `xml:"StorageClass"`
+}
+
+type AbortIncompleteMultipartUpload struct {
+	XMLName             xml.Name `xml:"AbortIncompleteMultipartUpload"`
+	DaysAfterInitiation *int     `xml:"DaysAfterInitiation"`
+}
+
 type Filter struct {
 	XMLName xml.Name `xml:"Filter"`
 	Prefix  string   `xml:"Prefix,omitempty"`
@@ -109,17 +130,66 @@ func (r *Rule) valid() *ErrorCode {
 		return LifeCycleErrMalformedXML
 	}
 
-	if r.Expire == nil {
+	if r.Expire == nil && len(r.Transitions) == 0 && r.AbortIncompleteMultipartUpload == nil {
 		return LifeCycleErrMissingActions
 	}
 
-	if err := r.Expire.validExpiration(); err != nil {
-		return err
+	if r.AbortIncompleteMultipartUpload != nil {
+		if days := r.AbortIncompleteMultipartUpload.DaysAfterInitiation; days == nil || *days <= 0 {
+			return LifeCycleErrAbortDays
+		}
+	}
+
+	if r.Expire != nil {
+		if err := r.Expire.validExpiration(); err != nil {
+			return err
+		}
+	}
+
+	storageClasses := make(map[string]bool)
+	for _, t := range r.Transitions {
+		if err := t.validTransition(); err != nil {
+			return err
+		}
+		if storageClasses[t.StorageClass] {
+			return LifeCycleErrSameStorageClass
+		}
+		storageClasses[t.StorageClass] = true
+		// objects are transitioned before they expire
+		if r.Expire != nil {
+			if (r.Expire.Days != nil) != (t.Days != nil) {
+				return LifeCycleErrMalformedXML
+			}
+			if r.Expire.Days != nil && *r.Expire.Days <= *t.Days {
+				return LifeCycleErrTransitionAfter
+			}
+			if r.Expire.Date != nil && !r.Expire.Date.After(*t.Date) {
+				return LifeCycleErrTransitionAfter
+			}
+		}
 	}
 
 	return nil
 }
 
+func (t *Transition) validTransition() *ErrorCode {
+	if t.StorageClass != proto.StorageClassStandardIA {
+		return LifeCycleErrStorageClass
+	}
+	if (t.Date != nil) == (t.Days != nil) {
+		return LifeCycleErrMalformedXML
+	}
+	if t.Date != nil {
+		date := t.Date.In(time.UTC)
+		if !(date.Hour() == 0 && date.Minute() == 0 && date.Second() == 0 && date.Nanosecond() == 0) {
+			return LifeCycleErrDateType
+		}
+	} else if *t.Days <= 0 {
+		return LifeCycleErrTransitionDays
+	}
+	return nil
+}
+
 func (e *Expiration) validExpiration() *ErrorCode {
 	// Date and Days cannot be set at the same time
 	if e.Date != nil && e.Days != nil {
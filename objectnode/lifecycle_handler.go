@@ -65,6 +65,21 @@ func (o *ObjectNode) getBucketLifecycleConfigurationHandler(w http.ResponseWrite
 				rule.Expire.Days = &lc.Expire.Days
 			}
 		}
+		for _, t := range lc.Transitions {
+			transition := &Transition{
+				Date:         t.Date,
+				StorageClass: t.StorageClass,
+			}
+			if t.Days != 0 {
+				transition.Days = &t.Days
+			}
+			rule.Transitions = append(rule.Transitions, transition)
+		}
+		if lc.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteMultipartUpload = &AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: &lc.AbortIncompleteMultipartUpload.DaysAfterInitiation,
+			}
+		}
 		if lc.Filter != nil {
 			rule.Filter = &Filter{
 				Prefix: lc.Filter.Prefix,
@@ -150,6 +165,21 @@ func (o *ObjectNode) putBucketLifecycleConfigurationHandler(w http.ResponseWrite
 				rule.Expire.Days = *lr.Expire.Days
 			}
 		}
+		for _, lt := range lr.Transitions {
+			transition := &proto.TransitionConfig{
+				Date:         lt.Date,
+				StorageClass: lt.StorageClass,
+			}
+			if lt.Days != nil {
+				transition.Days = *lt.Days
+			}
+			rule.Transitions = append(rule.Transitions, transition)
+		}
+		if lr.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteMultipartUpload = &proto.AbortIncompleteMultipartUploadConfig{
+				DaysAfterInitiation: *lr.AbortIncompleteMultipartUpload.DaysAfterInitiation,
+			}
+		}
 		if lr.Filter != nil {
 			rule.Filter = &proto.FilterConfig{
 				Prefix: lr.Filter.Prefix,
@@ -124,3 +124,124 @@ func TestLifecycleConfiguration(t *testing.T) {
 	_, err = l1.Validate()
 	require.Equal(t, err, LifeCycleErrMissingRules)
 }
+
+func TestLifecycleTransition(t *testing.T) {
+	LifecycleXml := `
+<LifecycleConfiguration>
+    <Rule>
+        <Filter>
+           <Prefix>logs/</Prefix>
+        </Filter>
+        <ID>id1</ID>
+        <Status>Enabled</Status>
+        <Transition>
+           <Days>30</Days>
+           <StorageClass>STANDARD_IA</StorageClass>
+        </Transition>
+        <Expiration>
+           <Days>365</Days>
+        </Expiration>
+    </Rule>
+</LifecycleConfiguration>
+`
+
+	l1 := NewLifeCycle()
+	err := xml.Unmarshal([]byte(LifecycleXml), l1)
+	require.NoError(t, err)
+	require.Len(t, l1.Rules[0].Transitions, 1)
+	require.Equal(t, 30, *l1.Rules[0].Transitions[0].Days)
+	ok, _ := l1.Validate()
+	require.Equal(t, true, ok)
+
+	// transition only
+	expire := l1.Rules[0].Expire
+	l1.Rules[0].Expire = nil
+	ok, _ = l1.Validate()
+	require.Equal(t, true, ok)
+	l1.Rules[0].Expire = expire
+
+	// transition after expiration
+	day := 365
+	l1.Rules[0].Transitions[0].Days = &day
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrTransitionAfter)
+
+	// days <= 0
+	day = 0
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrTransitionDays)
+	day = 30
+
+	// unsupported storage class
+	l1.Rules[0].Transitions[0].StorageClass = "GLACIER"
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrStorageClass)
+	l1.Rules[0].Transitions[0].StorageClass = "STANDARD_IA"
+
+	// same storage class
+	l1.Rules[0].Transitions = append(l1.Rules[0].Transitions, l1.Rules[0].Transitions[0])
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrSameStorageClass)
+	l1.Rules[0].Transitions = l1.Rules[0].Transitions[:1]
+
+	// date not at midnight
+	now := time.Now().In(time.UTC)
+	ti := time.Date(now.Year(), now.Month(), now.Day(), 1, 0, 0, 0, time.UTC)
+	l1.Rules[0].Transitions[0].Days = nil
+	l1.Rules[0].Transitions[0].Date = &ti
+	l1.Rules[0].Expire = nil
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrDateType)
+
+	// days and date of expiration and transition are mixed
+	ti = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	l1.Rules[0].Expire = expire
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrMalformedXML)
+
+	data, err := xml.Marshal(l1)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "<StorageClass>STANDARD_IA</StorageClass>")
+}
+
+func TestLifecycleAbortIncompleteMultipartUpload(t *testing.T) {
+	LifecycleXml := `
+<LifecycleConfiguration>
+    <Rule>
+        <Filter>
+           <Prefix>uploads/</Prefix>
+        </Filter>
+        <ID>id1</ID>
+        <Status>Enabled</Status>
+        <AbortIncompleteMultipartUpload>
+           <DaysAfterInitiation>7</DaysAfterInitiation>
+        </AbortIncompleteMultipartUpload>
+    </Rule>
+</LifecycleConfiguration>
+`
+
+	l1 := NewLifeCycle()
+	err := xml.Unmarshal([]byte(LifecycleXml), l1)
+	require.NoError(t, err)
+	require.NotNil(t, l1.Rules[0].AbortIncompleteMultipartUpload)
+	require.Equal(t, 7, *l1.Rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation)
+	ok, _ := l1.Validate()
+	require.Equal(t, true, ok)
+
+	// days <= 0
+	day := 0
+	l1.Rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation = &day
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrAbortDays)
+
+	// days missing
+	l1.Rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation = nil
+	_, err = l1.Validate()
+	require.Equal(t, err, LifeCycleErrAbortDays)
+
+	day = 7
+	l1.Rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation = &day
+	data, err := xml.Marshal(l1)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "<DaysAfterInitiation>7</DaysAfterInitiation>")
+}
@@ -0,0 +1,426 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/EventNotifications.html
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/blobstore/util/retry"
+	"github.com/cubefs/cubefs/util/log"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxNotificationSize = 1 << 16 // 64KB
+
+	// the ARN of the notification target is arn:cubefs:sqs::<id>:<kafka|webhook>
+	notificationARNPrefix = "arn:cubefs:sqs::"
+	notificationKafka     = "kafka"
+	notificationWebhook   = "webhook"
+
+	notificationEventVersion  = "2.1"
+	notificationEventSource   = "cubefs:s3"
+	notificationSchemaVersion = "1.0"
+
+	defaultNotificationQueueSize = 10000
+	defaultNotificationWorkers   = 4
+	defaultNotificationRetries   = 5
+)
+
+// the supported event types of the bucket notification
+const (
+	EventObjectCreatedAll                     = "s3:ObjectCreated:*"
+	EventObjectCreatedPut                     = "s3:ObjectCreated:Put"
+	EventObjectCreatedPost                    = "s3:ObjectCreated:Post"
+	EventObjectCreatedCopy                    = "s3:ObjectCreated:Copy"
+	EventObjectCreatedCompleteMultipartUpload = "s3:ObjectCreated:CompleteMultipartUpload"
+	EventObjectRemovedAll                     = "s3:ObjectRemoved:*"
+	EventObjectRemovedDelete                  = "s3:ObjectRemoved:Delete"
+)
+
+var supportedNotificationEvents = []string{
+	EventObjectCreatedAll, EventObjectCreatedPut, EventObjectCreatedPost, EventObjectCreatedCopy,
+	EventObjectCreatedCompleteMultipartUpload, EventObjectRemovedAll, EventObjectRemovedDelete,
+}
+
+type NotificationConfiguration struct {
+	XMLName             xml.Name             `xml:"NotificationConfiguration"`
+	XMLNS               string               `xml:"xmlns,attr,omitempty"`
+	QueueConfigurations []QueueConfiguration `xml:"QueueConfiguration,omitempty"`
+	// the topic and lambda function destinations are not supported
+	TopicConfigurations         []struct{} `xml:"TopicConfiguration,omitempty"`
+	CloudFunctionConfigurations []struct{} `xml:"CloudFunctionConfiguration,omitempty"`
+}
+
+// QueueConfiguration publishes the events of the objects whose key matches the filter to
+// the kafka topic or the webhook identified by the Queue ARN.
+type QueueConfiguration struct {
+	ID     string              `xml:"Id,omitempty"`
+	Queue  string              `xml:"Queue"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type NotificationFilter struct {
+	S3Key struct {
+		FilterRules []NotificationFilterRule `xml:"FilterRule"`
+	} `xml:"S3Key"`
+}
+
+type NotificationFilterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+func (c *NotificationConfiguration) IsEmpty() bool {
+	return c == nil || len(c.QueueConfigurations) == 0
+}
+
+func (c *NotificationConfiguration) validate(n *Notifier) *ErrorCode {
+	if len(c.TopicConfigurations) > 0 || len(c.CloudFunctionConfigurations) > 0 {
+		return NewError("InvalidArgument", "Only the QueueConfiguration is supported.", 400)
+	}
+	ids := make(map[string]struct{}, len(c.QueueConfigurations))
+	for i := range c.QueueConfigurations {
+		qc := &c.QueueConfigurations[i]
+		if qc.ID == "" {
+			qc.ID = uuid.New().String()
+		}
+		if _, ok := ids[qc.ID]; ok {
+			return NewError("InvalidArgument", "Duplicate notification configuration id: "+qc.ID, 400)
+		}
+		ids[qc.ID] = struct{}{}
+		if n == nil || !n.HasTarget(qc.Queue) {
+			return NewError("InvalidArgument", "Unable to validate the destination configuration: "+qc.Queue, 400)
+		}
+		if len(qc.Events) == 0 {
+			return NewError("InvalidArgument", "Missing Event in the notification configuration.", 400)
+		}
+		for _, event := range qc.Events {
+			if !StringListContain(supportedNotificationEvents, event) {
+				return NewError("InvalidArgument", "The event is not supported for notifications: "+event, 400)
+			}
+		}
+		if qc.Filter == nil {
+			continue
+		}
+		var prefix, suffix bool
+		for _, rule := range qc.Filter.S3Key.FilterRules {
+			switch strings.ToLower(rule.Name) {
+			case "prefix":
+				if prefix {
+					return NewError("InvalidArgument", "Cannot specify more than one prefix rule in a filter.", 400)
+				}
+				prefix = true
+			case "suffix":
+				if suffix {
+					return NewError("InvalidArgument", "Cannot specify more than one suffix rule in a filter.", 400)
+				}
+				suffix = true
+			default:
+				return NewError("InvalidArgument", "The filter rule name must be either prefix or suffix.", 400)
+			}
+		}
+	}
+	return nil
+}
+
+// match returns whether the event of the object key is published by the configuration.
+func (qc *QueueConfiguration) match(event, key string) bool {
+	matched := false
+	for _, e := range qc.Events {
+		if e == event || strings.HasSuffix(e, ":*") && strings.HasPrefix(event, strings.TrimSuffix(e, "*")) {
+			matched = true
+			break
+		}
+	}
+	if !matched || qc.Filter == nil {
+		return matched
+	}
+	for _, rule := range qc.Filter.S3Key.FilterRules {
+		switch strings.ToLower(rule.Name) {
+		case "prefix":
+			matched = matched && strings.HasPrefix(key, rule.Value)
+		case "suffix":
+			matched = matched && strings.HasSuffix(key, rule.Value)
+		}
+	}
+	return matched
+}
+
+func parseNotificationConfig(bytes []byte, n *Notifier) (config *NotificationConfiguration, errCode *ErrorCode) {
+	config = &NotificationConfiguration{}
+	if err := xml.Unmarshal(bytes, config); err != nil {
+		return nil, MalformedXML
+	}
+	if errCode = config.validate(n); errCode != nil {
+		return nil, errCode
+	}
+	return config, nil
+}
+
+func storeBucketNotification(bytes []byte, vol *Volume) (err error) {
+	return vol.store.Put(vol.name, bucketRootPath, XAttrKeyOSSNotification, bytes)
+}
+
+func deleteBucketNotification(vol *Volume) (err error) {
+	return vol.store.Delete(vol.name, bucketRootPath, XAttrKeyOSSNotification)
+}
+
+// NotificationEvent is the message published to the targets, which is compatible with
+// the event message structure of AWS S3.
+type NotificationEvent struct {
+	Records []NotificationRecord `json:"Records"`
+}
+
+type NotificationIdentity struct {
+	PrincipalID string `json:"principalId"`
+}
+
+type NotificationRecord struct {
+	EventVersion      string               `json:"eventVersion"`
+	EventSource       string               `json:"eventSource"`
+	AwsRegion         string               `json:"awsRegion"`
+	EventTime         string               `json:"eventTime"`
+	EventName         string               `json:"eventName"`
+	UserIdentity      NotificationIdentity `json:"userIdentity"`
+	RequestParameters struct {
+		SourceIPAddress string `json:"sourceIPAddress"`
+	} `json:"requestParameters"`
+	ResponseElements struct {
+		RequestID string `json:"x-amz-request-id"`
+	} `json:"responseElements"`
+	S3 struct {
+		SchemaVersion   string `json:"s3SchemaVersion"`
+		ConfigurationID string `json:"configurationId"`
+		Bucket          struct {
+			Name          string               `json:"name"`
+			OwnerIdentity NotificationIdentity `json:"ownerIdentity"`
+			ARN           string               `json:"arn"`
+		} `json:"bucket"`
+		Object struct {
+			Key       string `json:"key"`
+			Size      int64  `json:"size,omitempty"`
+			ETag      string `json:"eTag,omitempty"`
+			Sequencer string `json:"sequencer"`
+		} `json:"object"`
+	} `json:"s3"`
+}
+
+// NotificationConfig is the configuration of the notification targets, the key of the map is
+// the id in the ARN of the target.
+type NotificationConfig struct {
+	Kafka   map[string]KafkaAuditConfig   `json:"kafka,omitempty"`
+	Webhook map[string]WebhookAuditConfig `json:"webhook,omitempty"`
+	// QueueSize is the max number of the events waiting to be delivered, the new events
+	// are dropped if the queue is full.
+	QueueSize int `json:"queueSize,omitempty"`
+	// Workers is the number of the goroutines delivering the events.
+	Workers int `json:"workers,omitempty"`
+	// Retries is the max times of sending an event to the target.
+	Retries int `json:"retries,omitempty"`
+}
+
+func notificationARN(id, typ string) string {
+	return notificationARNPrefix + id + ":" + typ
+}
+
+// webhookNotificationTarget sends the event without the retries of the webhook audit,
+// as the retries are done by the notifier.
+type webhookNotificationTarget struct {
+	*WebhookAudit
+}
+
+func (t webhookNotificationTarget) Send(data []byte) error {
+	return t.send(data)
+}
+
+type notificationTask struct {
+	arn  string
+	data []byte
+}
+
+// Notifier delivers the events to the targets asynchronously.
+type Notifier struct {
+	conf    NotificationConfig
+	targets map[string]AuditLogger // ARN -> target
+	queue   chan notificationTask
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewNotifier(conf NotificationConfig) (n *Notifier, err error) {
+	targets := make(map[string]AuditLogger)
+	defer func() {
+		if err != nil {
+			for _, target := range targets {
+				target.Close()
+			}
+		}
+	}()
+	for id, cfg := range conf.Kafka {
+		if !cfg.Enable {
+			continue
+		}
+		var ka *KafkaAudit
+		if ka, err = NewKafkaAudit(id, cfg); err != nil {
+			return nil, fmt.Errorf("kafka target '%s': %v", id, err)
+		}
+		targets[notificationARN(id, notificationKafka)] = ka
+	}
+	for id, cfg := range conf.Webhook {
+		if !cfg.Enable {
+			continue
+		}
+		var wa *WebhookAudit
+		if wa, err = NewWebhookAudit(id, cfg); err != nil {
+			return nil, fmt.Errorf("webhook target '%s': %v", id, err)
+		}
+		targets[notificationARN(id, notificationWebhook)] = webhookNotificationTarget{wa}
+	}
+	return newNotifier(conf, targets), nil
+}
+
+func newNotifier(conf NotificationConfig, targets map[string]AuditLogger) *Notifier {
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = defaultNotificationQueueSize
+	}
+	if conf.Workers <= 0 {
+		conf.Workers = defaultNotificationWorkers
+	}
+	if conf.Retries <= 0 {
+		conf.Retries = defaultNotificationRetries
+	}
+	n := &Notifier{
+		conf:    conf,
+		targets: targets,
+		queue:   make(chan notificationTask, conf.QueueSize),
+		stopCh:  make(chan struct{}),
+	}
+	for i := 0; i < conf.Workers; i++ {
+		n.wg.Add(1)
+		go n.worker()
+	}
+	return n
+}
+
+func (n *Notifier) HasTarget(arn string) bool {
+	_, ok := n.targets[arn]
+	return ok
+}
+
+// Notify publishes the event of the object key to the targets of the matched configurations.
+func (n *Notifier) Notify(config *NotificationConfiguration, key string, record NotificationRecord) {
+	if config.IsEmpty() {
+		return
+	}
+	eventName := "s3:" + record.EventName
+	for _, qc := range config.QueueConfigurations {
+		if !qc.match(eventName, key) || !n.HasTarget(qc.Queue) {
+			continue
+		}
+		record.S3.ConfigurationID = qc.ID
+		data, err := json.Marshal(NotificationEvent{Records: []NotificationRecord{record}})
+		if err != nil {
+			log.LogErrorf("notification: json marshal event fail: event(%+v) err(%v)", record, err)
+			return
+		}
+		select {
+		case n.queue <- notificationTask{arn: qc.Queue, data: data}:
+		default:
+			log.LogErrorf("notification: queue is full, drop event: target(%v) event(%v)", qc.Queue, string(data))
+		}
+	}
+}
+
+func (n *Notifier) worker() {
+	defer n.wg.Done()
+	for {
+		select {
+		case task := <-n.queue:
+			n.send(task)
+		case <-n.stopCh:
+			// deliver the remaining events before exit
+			for {
+				select {
+				case task := <-n.queue:
+					n.send(task)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (n *Notifier) send(task notificationTask) {
+	target := n.targets[task.arn]
+	err := retry.ExponentialBackoff(n.conf.Retries, 100).On(func() error {
+		return target.Send(task.data)
+	})
+	if err != nil {
+		log.LogErrorf("notification: send event to '%s' fail: event(%v) err(%v)", target.Name(), string(task.data), err)
+	}
+}
+
+func (n *Notifier) Close() {
+	close(n.stopCh)
+	n.wg.Wait()
+	for _, target := range n.targets {
+		target.Close()
+	}
+}
+
+// newNotificationRecord returns the event record of the object in the bucket.
+func newNotificationRecord(param *RequestParam, region, event, key string, size int64, etag string) NotificationRecord {
+	now := time.Now().UTC()
+	record := NotificationRecord{
+		EventVersion: notificationEventVersion,
+		EventSource:  notificationEventSource,
+		AwsRegion:    region,
+		EventTime:    formatTimeISO(now),
+		EventName:    strings.TrimPrefix(event, "s3:"),
+		UserIdentity: NotificationIdentity{PrincipalID: param.Requester()},
+	}
+	record.RequestParameters.SourceIPAddress = param.sourceIP
+	record.ResponseElements.RequestID = param.RequestID()
+	record.S3.SchemaVersion = notificationSchemaVersion
+	record.S3.Bucket.Name = param.Bucket()
+	record.S3.Bucket.OwnerIdentity = NotificationIdentity{PrincipalID: param.Owner()}
+	record.S3.Bucket.ARN = "arn:aws:s3:::" + param.Bucket()
+	record.S3.Object.Key = notificationObjectKey(key)
+	record.S3.Object.Size = size
+	record.S3.Object.ETag = etag
+	record.S3.Object.Sequencer = strings.ToUpper(strconv.FormatInt(now.UnixNano(), 16))
+	return record
+}
+
+// notificationObjectKey returns the URL encoded key in the event, in which the slashes are kept.
+func notificationObjectKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.QueryEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
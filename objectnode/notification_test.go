@@ -0,0 +1,199 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNotificationTarget struct {
+	name  string
+	fails int
+
+	sync.Mutex
+	sent [][]byte
+}
+
+func (m *mockNotificationTarget) Name() string {
+	return m.name
+}
+
+func (m *mockNotificationTarget) Send(data []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.fails > 0 {
+		m.fails--
+		return errors.New("mock send error")
+	}
+	m.sent = append(m.sent, data)
+	return nil
+}
+
+func (m *mockNotificationTarget) Close() error {
+	return nil
+}
+
+func (m *mockNotificationTarget) events(t *testing.T) []NotificationRecord {
+	m.Lock()
+	defer m.Unlock()
+	records := make([]NotificationRecord, 0, len(m.sent))
+	for _, data := range m.sent {
+		event := new(NotificationEvent)
+		require.NoError(t, json.Unmarshal(data, event))
+		records = append(records, event.Records...)
+	}
+	return records
+}
+
+const testNotificationConfig = `<NotificationConfiguration>
+	<QueueConfiguration>
+		<Id>images</Id>
+		<Queue>arn:cubefs:sqs::events:kafka</Queue>
+		<Event>s3:ObjectCreated:*</Event>
+		<Filter>
+			<S3Key>
+				<FilterRule><Name>prefix</Name><Value>images/</Value></FilterRule>
+				<FilterRule><Name>Suffix</Name><Value>.jpg</Value></FilterRule>
+			</S3Key>
+		</Filter>
+	</QueueConfiguration>
+	<QueueConfiguration>
+		<Queue>arn:cubefs:sqs::pipeline:webhook</Queue>
+		<Event>s3:ObjectRemoved:Delete</Event>
+		<Event>s3:ObjectCreated:Copy</Event>
+	</QueueConfiguration>
+</NotificationConfiguration>`
+
+func TestParseNotificationConfig(t *testing.T) {
+	n := newNotifier(NotificationConfig{}, map[string]AuditLogger{
+		notificationARN("events", notificationKafka):     &mockNotificationTarget{},
+		notificationARN("pipeline", notificationWebhook): &mockNotificationTarget{},
+	})
+	defer n.Close()
+
+	config, errCode := parseNotificationConfig([]byte(testNotificationConfig), n)
+	require.Nil(t, errCode)
+	require.Len(t, config.QueueConfigurations, 2)
+	require.Equal(t, "images", config.QueueConfigurations[0].ID)
+	require.NotEmpty(t, config.QueueConfigurations[1].ID)
+
+	config, errCode = parseNotificationConfig([]byte(`<NotificationConfiguration/>`), n)
+	require.Nil(t, errCode)
+	require.True(t, config.IsEmpty())
+
+	for _, invalid := range []string{
+		`<NotificationConfiguration>`,
+		`<NotificationConfiguration><TopicConfiguration><Topic>arn:aws:sns:::t</Topic></TopicConfiguration></NotificationConfiguration>`,
+		`<NotificationConfiguration><QueueConfiguration><Queue>arn:cubefs:sqs::unknown:kafka</Queue>` +
+			`<Event>s3:ObjectCreated:*</Event></QueueConfiguration></NotificationConfiguration>`,
+		`<NotificationConfiguration><QueueConfiguration><Queue>arn:cubefs:sqs::events:kafka</Queue>` +
+			`<Event>s3:ObjectRestore:Post</Event></QueueConfiguration></NotificationConfiguration>`,
+		`<NotificationConfiguration><QueueConfiguration><Queue>arn:cubefs:sqs::events:kafka</Queue>` +
+			`</QueueConfiguration></NotificationConfiguration>`,
+		`<NotificationConfiguration><QueueConfiguration><Id>a</Id><Queue>arn:cubefs:sqs::events:kafka</Queue>` +
+			`<Event>s3:ObjectCreated:*</Event></QueueConfiguration><QueueConfiguration><Id>a</Id>` +
+			`<Queue>arn:cubefs:sqs::events:kafka</Queue><Event>s3:ObjectCreated:*</Event></QueueConfiguration>` +
+			`</NotificationConfiguration>`,
+		`<NotificationConfiguration><QueueConfiguration><Queue>arn:cubefs:sqs::events:kafka</Queue>` +
+			`<Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>regex</Name><Value>.*</Value>` +
+			`</FilterRule></S3Key></Filter></QueueConfiguration></NotificationConfiguration>`,
+	} {
+		_, errCode = parseNotificationConfig([]byte(invalid), n)
+		require.NotNil(t, errCode, invalid)
+	}
+
+	// no destination is valid if the notification is not configured
+	_, errCode = parseNotificationConfig([]byte(testNotificationConfig), nil)
+	require.NotNil(t, errCode)
+}
+
+func TestQueueConfigurationMatch(t *testing.T) {
+	config := &NotificationConfiguration{}
+	require.NoError(t, xml.Unmarshal([]byte(testNotificationConfig), config))
+	images, pipeline := config.QueueConfigurations[0], config.QueueConfigurations[1]
+
+	require.True(t, images.match(EventObjectCreatedPut, "images/a.jpg"))
+	require.True(t, images.match(EventObjectCreatedCompleteMultipartUpload, "images/b/c.jpg"))
+	require.False(t, images.match(EventObjectCreatedPut, "images/a.png"))
+	require.False(t, images.match(EventObjectCreatedPut, "docs/a.jpg"))
+	require.False(t, images.match(EventObjectRemovedDelete, "images/a.jpg"))
+
+	require.True(t, pipeline.match(EventObjectRemovedDelete, "docs/a.txt"))
+	require.True(t, pipeline.match(EventObjectCreatedCopy, "docs/a.txt"))
+	require.False(t, pipeline.match(EventObjectCreatedPut, "docs/a.txt"))
+}
+
+func TestNotifierNotify(t *testing.T) {
+	kafka := &mockNotificationTarget{name: "kafka", fails: 2}
+	webhook := &mockNotificationTarget{name: "webhook"}
+	n := newNotifier(NotificationConfig{Retries: 3}, map[string]AuditLogger{
+		notificationARN("events", notificationKafka):     kafka,
+		notificationARN("pipeline", notificationWebhook): webhook,
+	})
+	config, errCode := parseNotificationConfig([]byte(testNotificationConfig), n)
+	require.Nil(t, errCode)
+
+	r, err := http.NewRequest(http.MethodPut, "http://bucket.cfs.local/images/a%20b.jpg", nil)
+	require.NoError(t, err)
+	r = mux.SetURLVars(r, map[string]string{
+		ContextKeyBucket:    "bucket",
+		ContextKeyObject:    "images/a b.jpg",
+		ContextKeyRequestID: "7ab2d0f1",
+		ContextKeyRequester: "user1",
+		ContextKeyOwner:     "owner1",
+	})
+	SetRequestAction(r, proto.OSSPutObjectAction)
+	r.RemoteAddr = "10.1.1.1:3456"
+	param := ParseRequestParam(r)
+
+	key := param.Object()
+	n.Notify(config, key, newNotificationRecord(param, "cfs", EventObjectCreatedPut, key, 1024, "etag"))
+	n.Notify(config, key, newNotificationRecord(param, "cfs", EventObjectRemovedDelete, key, 0, ""))
+	n.Notify(config, "docs/a.txt", newNotificationRecord(param, "cfs", EventObjectCreatedPut, "docs/a.txt", 1, "etag"))
+	// the remaining events are delivered at close
+	n.Close()
+
+	// the event is delivered after the failed retries
+	records := kafka.events(t)
+	require.Len(t, records, 1)
+	record := records[0]
+	require.Equal(t, "ObjectCreated:Put", record.EventName)
+	require.Equal(t, notificationEventSource, record.EventSource)
+	require.Equal(t, "cfs", record.AwsRegion)
+	require.Equal(t, "user1", record.UserIdentity.PrincipalID)
+	require.Equal(t, "10.1.1.1", record.RequestParameters.SourceIPAddress)
+	require.Equal(t, "7ab2d0f1", record.ResponseElements.RequestID)
+	require.Equal(t, "images", record.S3.ConfigurationID)
+	require.Equal(t, "bucket", record.S3.Bucket.Name)
+	require.Equal(t, "owner1", record.S3.Bucket.OwnerIdentity.PrincipalID)
+	require.Equal(t, "images/a+b.jpg", record.S3.Object.Key)
+	require.Equal(t, int64(1024), record.S3.Object.Size)
+	require.Equal(t, "etag", record.S3.Object.ETag)
+	require.NotEmpty(t, record.S3.Object.Sequencer)
+
+	records = webhook.events(t)
+	require.Len(t, records, 1)
+	require.Equal(t, "ObjectRemoved:Delete", records[0].EventName)
+	require.Equal(t, config.QueueConfigurations[1].ID, records[0].S3.ConfigurationID)
+}
@@ -211,19 +211,28 @@ func (o *ObjectNode) policyCheck(f http.HandlerFunc) http.HandlerFunc {
 		}
 		userPolicy = userInfo.Policy
 		isOwner = userPolicy.IsOwn(param.Bucket())
+		// copy api should check srcBucket policy additionally, the source may be in another bucket
+		if param.apiName == COPY_OBJECT || param.apiName == UPLOAD_PART_COPY {
+			err = o.allowedBySrcBucketPolicy(param, userInfo.UserID, userPolicy)
+			if err != nil {
+				allowed = false
+				return
+			}
+		}
 		// The bucket is not owned by request user who has not been authorized, so bucket policy should be checked.
 		if !isOwner && userPolicy.IsAuthorizedS3(param.Bucket(), param.apiName) {
 			log.LogInfof("user policy check:  permission url(%v) requestID(%v) userID(%v) accessKey(%v) volume(%v) object(%v) action(%v) authorizedVols(%v)",
 				r.URL, GetRequestID(r), userInfo.UserID, param.AccessKey(), param.Bucket(), param.Object(), param.Action(), userPolicy.AuthorizedVols)
-			allowed = true
-			return
-		}
-		// copy api should check srcBucket policy additionally
-		if param.apiName == COPY_OBJECT || param.apiName == UPLOAD_PART_COPY {
-			err = o.allowedBySrcBucketPolicy(param, userInfo.UserID)
-			if err != nil {
+			// an explicit deny in the bucket policy overrides the authorization of the user policy
+			var denied bool
+			if denied, err = o.deniedByBucketPolicy(param, userInfo.UserID); err != nil || denied {
+				log.LogWarnf("bucket policy check: authorized user denied: requestID(%v) userID(%v) volume(%v) denied(%v) err(%v)",
+					GetRequestID(r), userInfo.UserID, param.Bucket(), denied, err)
+				allowed = false
 				return
 			}
+			allowed = true
+			return
 		}
 		// batch delete will delay to check just before delete for each key
 		if param.apiName == BATCH_DELETE {
@@ -245,15 +254,7 @@ func (o *ObjectNode) policyCheck(f http.HandlerFunc) http.HandlerFunc {
 			GetRequestID(r), userInfo.UserID, userInfo.Policy, vol.Name(), vol.GetOwner(), acl, policy)
 		if vol != nil && policy != nil && !policy.IsEmpty() {
 			log.LogDebugf("bucket policy check: requestID(%v) policy(%v)", GetRequestID(r), policy)
-			conditionCheck := map[string]string{
-				SOURCEIP: param.sourceIP,
-				REFERER:  param.r.Referer(),
-				HOST:     param.r.Host,
-			}
-			if !IsBucketApi(param.apiName) {
-				conditionCheck[KEYNAME] = param.object
-			}
-			pcr := policy.IsAllowed(param, userInfo.UserID, vol.owner, conditionCheck)
+			pcr := policy.IsAllowed(param, userInfo.UserID, vol.owner, newConditionCheck(param))
 			switch pcr {
 			case POLICY_ALLOW:
 				allowed = true
@@ -312,6 +313,37 @@ func (o *ObjectNode) policyCheck(f http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// newConditionCheck returns the values of the condition keys of the request, which are
+// evaluated by the conditions of the bucket policy.
+func newConditionCheck(param *RequestParam) map[string]string {
+	conditionCheck := map[string]string{
+		SOURCEIP:  param.sourceIP,
+		REFERER:   param.r.Referer(),
+		HOST:      param.r.Host,
+		USERAGENT: param.r.UserAgent(),
+	}
+	if IsBucketApi(param.apiName) {
+		query := param.r.URL.Query()
+		conditionCheck[PREFIX] = query.Get(ParamPrefix)
+		conditionCheck[DELIMITER] = query.Get(ParamPartDelimiter)
+	} else {
+		conditionCheck[KEYNAME] = param.object
+	}
+	return conditionCheck
+}
+
+// deniedByBucketPolicy returns whether the request is denied explicitly by the bucket policy.
+func (o *ObjectNode) deniedByBucketPolicy(param *RequestParam, reqUid string) (bool, error) {
+	vol, _, policy, err := o.loadBucketMeta(param.Bucket())
+	if err != nil {
+		return false, err
+	}
+	if policy == nil || policy.IsEmpty() {
+		return false, nil
+	}
+	return policy.IsAllowed(param, reqUid, vol.owner, newConditionCheck(param)) == POLICY_DENY, nil
+}
+
 func (o *ObjectNode) loadBucketMeta(bucket string) (vol *Volume, acl *AccessControlPolicy, policy *Policy, err error) {
 	if vol, err = o.getVol(bucket); err != nil {
 		return
@@ -325,7 +357,7 @@ func (o *ObjectNode) loadBucketMeta(bucket string) (vol *Volume, acl *AccessCont
 	return
 }
 
-func (o *ObjectNode) allowedBySrcBucketPolicy(param *RequestParam, reqUid string) (err error) {
+func (o *ObjectNode) allowedBySrcBucketPolicy(param *RequestParam, reqUid string, userPolicy *proto.UserPolicy) (err error) {
 	paramCopy := *param
 	srcBucketId, srcKey, _, err := extractSrcBucketKey(paramCopy.r)
 	if err != nil {
@@ -340,12 +372,8 @@ func (o *ObjectNode) allowedBySrcBucketPolicy(param *RequestParam, reqUid string
 	paramCopy.apiName = GET_OBJECT
 	paramCopy.action = proto.OSSGetObjectAction
 	if vol != nil && policy != nil && !policy.IsEmpty() {
-		conditionCheck := map[string]string{
-			SOURCEIP: paramCopy.sourceIP,
-			KEYNAME:  srcKey,
-			REFERER:  paramCopy.r.Referer(),
-			HOST:     paramCopy.r.Host,
-		}
+		conditionCheck := newConditionCheck(&paramCopy)
+		conditionCheck[KEYNAME] = srcKey
 		pcr := policy.IsAllowed(&paramCopy, reqUid, vol.owner, conditionCheck)
 		switch pcr {
 		case POLICY_ALLOW:
@@ -362,6 +390,13 @@ func (o *ObjectNode) allowedBySrcBucketPolicy(param *RequestParam, reqUid string
 		}
 	}
 
+	// the source bucket is owned by or authorized to the request user by the user policy
+	if userPolicy != nil && (userPolicy.IsOwn(srcBucketId) || userPolicy.IsAuthorizedS3(srcBucketId, paramCopy.apiName)) {
+		log.LogDebugf("srcBucket user policy check: action allowed: requestID(%v) reqUid(%v) volume(%v)",
+			GetRequestID(paramCopy.r), reqUid, srcBucketId)
+		return
+	}
+
 	isOwner := reqUid == vol.owner
 	var acl *AccessControlPolicy
 	if acl, err = getObjectACL(vol, srcKey, true); err != nil && err != syscall.ENOENT {
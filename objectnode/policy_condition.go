@@ -42,15 +42,19 @@ var (
 type operator string
 
 const (
-	stringLike    = "StringLike"
-	stringNotLike = "StringNotLike"
-	ipAddress     = "IpAddress"
-	notIPAddress  = "NotIpAddress"
+	stringLike      = "StringLike"
+	stringNotLike   = "StringNotLike"
+	stringEquals    = "StringEquals"
+	stringNotEquals = "StringNotEquals"
+	ipAddress       = "IpAddress"
+	notIPAddress    = "NotIpAddress"
 )
 
 var supportedOperators = []operator{
 	stringLike,
 	stringNotLike,
+	stringEquals,
+	stringNotEquals,
 	ipAddress,
 	notIPAddress,
 	// Add new conditions here.
@@ -166,10 +170,12 @@ func (operations Condition) String() string {
 }
 
 var conditionOpMap = map[operator]func(map[Key]ValueSet) (Operation, error){
-	stringLike:    newStringLikeOp,
-	stringNotLike: newStringNotLikeOp,
-	ipAddress:     newIPAddressOp,
-	notIPAddress:  newNotIPAddressOp,
+	stringLike:      newStringLikeOp,
+	stringNotLike:   newStringNotLikeOp,
+	stringEquals:    newStringEqualsOp,
+	stringNotEquals: newStringNotEqualsOp,
+	ipAddress:       newIPAddressOp,
+	notIPAddress:    newNotIPAddressOp,
 	// Add new conditions here.
 }
 
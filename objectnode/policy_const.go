@@ -25,10 +25,13 @@ const (
 type ConditionEnum int
 
 const (
-	KEYNAME  = "KeyName"
-	SOURCEIP = "SourceIp"
-	REFERER  = "Referer"
-	HOST     = "Host"
+	KEYNAME   = "KeyName"
+	SOURCEIP  = "SourceIp"
+	REFERER   = "Referer"
+	HOST      = "Host"
+	USERAGENT = "UserAgent"
+	PREFIX    = "prefix"
+	DELIMITER = "delimiter"
 )
 
 const (
@@ -34,12 +34,24 @@ const (
 
 	// AWSHost - key representing client's request host of any API, this is not standard AWS key
 	AWSHost Key = "aws:Host"
+
+	// AWSUserAgent - key representing UserAgent header of any API.
+	AWSUserAgent Key = "aws:UserAgent"
+
+	// S3Prefix - key representing prefix query parameter of ListObjects/ListObjectsV2 API.
+	S3Prefix Key = "s3:prefix"
+
+	// S3Delimiter - key representing delimiter query parameter of ListObjects/ListObjectsV2 API.
+	S3Delimiter Key = "s3:delimiter"
 )
 
 var AllSupportedKeys = []Key{
 	AWSReferer,
 	AWSSourceIP,
 	AWSHost,
+	AWSUserAgent,
+	S3Prefix,
+	S3Delimiter,
 	// Add new supported condition keys.
 }
 
@@ -0,0 +1,118 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"net/http"
+)
+
+// String equals operation. It checks whether value by Key in given
+// values map is in condition values.
+// For example,
+//   - if values = ["photos/", "docs/"], at evaluate() it returns whether string
+//     in value map for Key is one of the values.
+type stringEqualsOp struct {
+	m map[Key]StringSet
+}
+
+// evaluates to check whether value by Key in given values is in condition values.
+func (op stringEqualsOp) evaluate(values map[string]string) bool {
+	for k, v := range op.m {
+		requestValue, ok := values[http.CanonicalHeaderKey(k.Name())]
+		if !ok {
+			requestValue = values[k.Name()]
+		}
+		if !v.Contains(requestValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// returns condition key which is used by this condition operation.
+func (op stringEqualsOp) keys() KeySet {
+	keys := make(KeySet)
+	for key := range op.m {
+		keys.Add(key)
+	}
+	return keys
+}
+
+// returns "StringEquals" operator.
+func (op stringEqualsOp) operator() operator {
+	return stringEquals
+}
+
+// returns map representation of this operation.
+func (op stringEqualsOp) toMap() map[Key]ValueSet {
+	resultMap := make(map[Key]ValueSet)
+	for k, v := range op.m {
+		if !k.IsValid() {
+			return nil
+		}
+		values := NewValueSet()
+		for _, value := range v.ToSlice() {
+			values.Add(NewStringValue(value))
+		}
+		resultMap[k] = values
+	}
+
+	return resultMap
+}
+
+// returns new StringEquals operation.
+func newStringEqualsOp(m map[Key]ValueSet) (Operation, error) {
+	newMap, err := parseMap(m, stringEquals)
+	if err != nil {
+		return nil, err
+	}
+	return NewStringEqualsOp(newMap)
+}
+
+// NewStringEqualsOp - returns new StringEquals operation.
+func NewStringEqualsOp(m map[Key]StringSet) (Operation, error) {
+	return &stringEqualsOp{m: m}, nil
+}
+
+// String not equals operation. It checks whether value by Key in given
+// values map is NOT in condition values.
+type stringNotEqualsOp struct {
+	stringEqualsOp
+}
+
+// evaluates to check whether value by Key in given values is NOT in condition values.
+func (op stringNotEqualsOp) evaluate(values map[string]string) bool {
+	return !op.stringEqualsOp.evaluate(values)
+}
+
+// returns "StringNotEquals" operator.
+func (op stringNotEqualsOp) operator() operator {
+	return stringNotEquals
+}
+
+// returns new StringNotEquals operation.
+func newStringNotEqualsOp(m map[Key]ValueSet) (Operation, error) {
+	newMap, err := parseMap(m, stringNotEquals)
+	if err != nil {
+		return nil, err
+	}
+	return NewStringNotEqualsOp(newMap)
+}
+
+// NewStringNotEqualsOp - returns new StringNotEquals operation.
+func NewStringNotEqualsOp(m map[Key]StringSet) (Operation, error) {
+	return &stringNotEqualsOp{stringEqualsOp{m}}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringEqualsOpEvaluate(t *testing.T) {
+	case1Operation, err := newStringEqualsOp(map[Key]ValueSet{S3Prefix: NewValueSet(NewStringValue("photos/"), NewStringValue(""))})
+	require.NoError(t, err)
+	case2Operation, err := newStringEqualsOp(map[Key]ValueSet{S3Delimiter: NewValueSet(NewStringValue("/"))})
+	require.NoError(t, err)
+	case3Operation, err := newStringNotEqualsOp(map[Key]ValueSet{AWSUserAgent: NewValueSet(NewStringValue("curl/7.29.0"))})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		operation      Operation
+		values         map[string]string
+		expectedResult bool
+	}{
+		{case1Operation, map[string]string{PREFIX: "photos/"}, true},
+		{case1Operation, map[string]string{PREFIX: ""}, true},
+		{case1Operation, map[string]string{PREFIX: "photos/2023/"}, false},
+		{case1Operation, map[string]string{PREFIX: "docs/"}, false},
+
+		{case2Operation, map[string]string{DELIMITER: "/"}, true},
+		{case2Operation, map[string]string{DELIMITER: ""}, false},
+
+		{case3Operation, map[string]string{USERAGENT: "curl/7.29.0"}, false},
+		{case3Operation, map[string]string{USERAGENT: "aws-cli/2.0"}, true},
+	}
+
+	for i, testCase := range testCases {
+		require.Equal(t, testCase.expectedResult, testCase.operation.evaluate(testCase.values), "case %v", i+1)
+	}
+}
+
+func TestPolicyConditionKeys(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": ["s3:ListBucket"],
+			"Resource": ["arn:aws:s3:::bucket"],
+			"Condition": {
+				"StringLike": {"s3:prefix": ["public/*"]},
+				"IpAddress": {"aws:SourceIp": ["10.0.0.0/8"]}
+			}
+		}, {
+			"Effect": "Deny",
+			"Principal": "*",
+			"Action": ["s3:GetObject"],
+			"Resource": ["arn:aws:s3:::bucket/*"],
+			"Condition": {"StringNotEquals": {"aws:UserAgent": ["cubefs"]}}
+		}]
+	}`))
+	require.NoError(t, err)
+	ok, err := policy.Validate("bucket")
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = json.Marshal(policy)
+	require.NoError(t, err)
+
+	newParam := func(apiName, url, sourceIP, userAgent string) *RequestParam {
+		r, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		r.Header.Set(UserAgent, userAgent)
+		return &RequestParam{r: r, apiName: apiName, sourceIP: sourceIP, object: "key"}
+	}
+	testCases := []struct {
+		param  *RequestParam
+		expect PolicyCheckResult
+	}{
+		{newParam(LIST_OBJECTS, "http://bucket.cfs.local/?prefix=public/a", "10.1.1.1", ""), POLICY_ALLOW},
+		{newParam(LIST_OBJECTS_V2, "http://bucket.cfs.local/?list-type=2&prefix=private/", "10.1.1.1", ""), POLICY_UNKNOW},
+		{newParam(LIST_OBJECTS, "http://bucket.cfs.local/?prefix=public/a", "192.168.1.1", ""), POLICY_UNKNOW},
+		{newParam(GET_OBJECT, "http://bucket.cfs.local/key", "10.1.1.1", "cubefs"), POLICY_UNKNOW},
+		{newParam(GET_OBJECT, "http://bucket.cfs.local/key", "10.1.1.1", "curl"), POLICY_DENY},
+	}
+	for i, testCase := range testCases {
+		result := policy.IsAllowed(testCase.param, "user", "owner", newConditionCheck(testCase.param))
+		require.Equal(t, testCase.expect, result, "case %v", i+1)
+	}
+}
@@ -245,6 +245,7 @@ type Object struct {
 type DeleteRequest struct {
 	XMLName xml.Name `xml:"Delete"`
 	Objects []Object `xml:"Object"`
+	Quiet   bool     `xml:"Quiet,omitempty"`
 }
 
 type CopyResult struct {
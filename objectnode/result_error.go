@@ -90,6 +90,9 @@ var (
 	ObjectLockConfigurationNotFound     = &ErrorCode{"ObjectLockConfigurationNotFoundError", "Object Lock configuration does not exist for this bucket", http.StatusNotFound}
 	TooManyRequests                     = &ErrorCode{"TooManyRequests", "too many requests, please retry later", http.StatusTooManyRequests}
 	MalformedPOSTRequest                = &ErrorCode{ErrorCode: "MalformedPOSTRequest", ErrorMessage: "The body of your POST request is not well-formed multipart/form-data.", StatusCode: http.StatusBadRequest}
+	InvalidEncryptionAlgorithm          = &ErrorCode{ErrorCode: "InvalidEncryptionAlgorithmError", ErrorMessage: "The encryption request you specified is not valid. The valid value is AES256 or aws:kms.", StatusCode: http.StatusBadRequest}
+	ServerSideEncryptionNotConfigured   = &ErrorCode{ErrorCode: "NotImplemented", ErrorMessage: "Server side encryption is not configured.", StatusCode: http.StatusNotImplemented}
+	KMSKeyNotFound                      = &ErrorCode{ErrorCode: "KMS.NotFoundException", ErrorMessage: "The specified KMS key does not exist.", StatusCode: http.StatusBadRequest}
 )
 
 type ErrorCode struct {
@@ -119,6 +122,7 @@ func (ec *ErrorCode) ServeResponse(w http.ResponseWriter, r *http.Request) {
 	// traceMiddleWare send exception request to prometheus via status code
 	SetResponseStatusCode(r, strconv.Itoa(ec.StatusCode))
 	SetResponseErrorMessage(r, ec.ErrorMessage)
+	SetResponseErrorCode(r, ec.ErrorCode)
 
 	errorResponse := ErrorResponse{
 		Code:      ec.ErrorCode,
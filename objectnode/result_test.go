@@ -300,3 +300,14 @@ func TestRequest_CompleteMultipartUploadRequest_Unmarshal(t *testing.T) {
 	bytes, _ := json.Marshal(request)
 	fmt.Printf("request : %s\n", string(bytes))
 }
+
+func TestXmlUnmarshal_DeleteRequestQuiet(t *testing.T) {
+	var deleteRequest DeleteRequest
+	data := []byte("<Delete><Quiet>true</Quiet><Object><Key>a</Key></Object></Delete>")
+	if err := xml.Unmarshal(data, &deleteRequest); err != nil {
+		t.Fatalf("unmarshal fail cause: %v", err)
+	}
+	if !deleteRequest.Quiet || len(deleteRequest.Objects) != 1 || deleteRequest.Objects[0].Key != "a" {
+		t.Fatalf("unexpected delete request: %+v", deleteRequest)
+	}
+}
@@ -55,6 +55,13 @@ func (o *ObjectNode) registerApiRouters(router *mux.Router) {
 			Queries("object-lock", "").
 			HandlerFunc(o.getObjectLockConfigurationHandler)
 
+		// Get bucket notification configuration
+		// API reference: https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketNotificationConfiguration.html
+		r.NewRoute().Name(ActionToUniqueRouteName(proto.OSSGetBucketNotificationConfigurationAction)).
+			Methods(http.MethodGet).
+			Queries("notification", "").
+			HandlerFunc(o.getBucketNotificationHandler)
+
 		// List parts
 		// API reference: https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListParts.html
 		r.NewRoute().Name(ActionToUniqueRouteName(proto.OSSListPartsAction)).
@@ -311,6 +318,13 @@ func (o *ObjectNode) registerApiRouters(router *mux.Router) {
 			Queries("object-lock", "").
 			HandlerFunc(o.putObjectLockConfigurationHandler)
 
+		// Put bucket notification configuration
+		// API reference: https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketNotificationConfiguration.html
+		r.NewRoute().Name(ActionToUniqueRouteName(proto.OSSPutBucketNotificationConfigurationAction)).
+			Methods(http.MethodPut).
+			Queries("notification", "").
+			HandlerFunc(o.putBucketNotificationHandler)
+
 		// Upload part copy
 		// API reference: https://docs.aws.amazon.com/AmazonS3/latest/API/API_UploadPartCopy.html
 		r.NewRoute().Name(ActionToUniqueRouteName(proto.OSSUploadPartCopyAction)).
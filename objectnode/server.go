@@ -15,6 +15,7 @@
 package objectnode
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -70,6 +71,16 @@ const (
 	//		}
 	configMasterAddr = proto.MasterAddr
 
+	// String configuration items, the keys of the user signing the admin requests to the master such as
+	// creating and deleting buckets, which are required if RBAC is enabled by the master.
+	// Example:
+	//		{
+	//			"masterAccessKey": "39bEF4RrAQgMj6RV",
+	//			"masterSecretKey": "TRL6o3JL16YOqvZGIohBDFTHZDEcFsyd"
+	//		}
+	configMasterAccessKey = "masterAccessKey"
+	configMasterSecretKey = "masterSecretKey"
+
 	// A bool type configuration is used to ensure that the topology information is consistent with the cluster
 	// in real time during the compatibility test. If true, the object node will not cache user information and
 	// volume topology. This configuration will cause a drastic decrease in performance after being turned on,
@@ -123,11 +134,52 @@ const (
 	// 						"brokers": "192.168.80.130:9095,192.168.80.131:9095,192.168.80.132:9095"
 	// 					}
 	// 				},
+	// 				"accessLog": {
+	// 					"targetBucket": "logs",
+	// 					"targetPrefix": "access/"
+	// 				},
 	//				...
 	// 			}
 	// 		}
 	configAuditLog = "auditLog"
 
+	// Map type configuration item, used to configure the KMS of the server-side encryption of the objects.
+	// For detailed parameters, see the KMSConfig structure.
+	// Example:
+	//		{
+	//			"sseKMS": {
+	//				"type": "static",
+	//				"defaultKeyID": "key-1",
+	//				"staticKeys": {
+	//					"key-1": "base64 encoded 256-bit key"
+	//				}
+	//			}
+	//		}
+	configSSEKMS = "sseKMS"
+
+	// Map type configuration item, used to configure the targets of the bucket notification, the events
+	// are published to the target by the ARN arn:cubefs:sqs::<id>:<kafka|webhook>. For detailed parameters,
+	// see the NotificationConfig structure.
+	// Example:
+	//		{
+	//			"notification": {
+	//				"kafka": {
+	//					"events": {
+	//						"enable": true,
+	//						"topic": "s3_event_topic",
+	//						"brokers": "192.168.80.130:9095,192.168.80.131:9095"
+	//					}
+	//				},
+	//				"webhook": {
+	//					"pipeline": {
+	//						"enable": true,
+	//						"endpoint": "http://192.168.80.140:8080/events"
+	//					}
+	//				}
+	//			}
+	//		}
+	configNotification = "notification"
+
 	// ObjMetaCache takes each path hierarchy of the path-like S3 object key as the cache key,
 	// and map it to the corresponding posix-compatible inode
 	// when enabled, the maxDentryCacheNum must at least be the minimum of defaultMaxDentryCacheNum
@@ -178,6 +230,7 @@ var (
 	writeThreads     = 4
 	readThreads      = 4
 	enableBlockcache bool
+	sseKMS           KMS
 )
 
 type ObjectNode struct {
@@ -194,6 +247,8 @@ type ObjectNode struct {
 
 	localAuditHandler rpc.ProgressHandler
 	externalAudit     *ExternalAudit
+	accessLogger      *AccessLogger
+	notifier          *Notifier
 
 	closes []func() // close other resources after http server closed
 
@@ -286,12 +341,45 @@ func (o *ObjectNode) loadConfig(cfg *config.Config) (err error) {
 		log.LogInfof("loadConfig: setup config: %v(%v)", configAuditLog, rawAuditLog)
 	}
 
+	// parse sse kms config
+	if rawKMS := cfg.GetValue(configSSEKMS); rawKMS != nil {
+		var conf KMSConfig
+		if err = ParseJSONEntity(rawKMS, &conf); err != nil {
+			err = fmt.Errorf("invalid %v configuration: %v", configSSEKMS, err)
+			return
+		}
+		if sseKMS, err = NewKMS(conf); err != nil {
+			err = fmt.Errorf("invalid %v configuration: %v", configSSEKMS, err)
+			return
+		}
+		log.LogInfof("loadConfig: setup config: %v type(%v) defaultKeyID(%v)", configSSEKMS, conf.Type, conf.DefaultKeyID)
+	}
+
+	// parse bucket notification config
+	if rawNotification := cfg.GetValue(configNotification); rawNotification != nil {
+		var conf NotificationConfig
+		if err = ParseJSONEntity(rawNotification, &conf); err != nil {
+			err = fmt.Errorf("invalid %v configuration: %v", configNotification, err)
+			return
+		}
+		if o.notifier, err = NewNotifier(conf); err != nil {
+			err = fmt.Errorf("invalid %v configuration: %v", configNotification, err)
+			return
+		}
+		o.closes = append(o.closes, o.notifier.Close)
+		log.LogInfof("loadConfig: setup config: %v targets(%v)", configNotification, len(o.notifier.targets))
+	}
+
 	// parse strict config
 	strict := cfg.GetBool(configStrict)
 	log.LogInfof("loadConfig: strict: %v", strict)
 	o.disableCreateBucketByS3 = cfg.GetBool(disableCreateBucketByS3)
 
 	o.mc = master.NewMasterClient(masters, false)
+	if accessKey := cfg.GetString(configMasterAccessKey); accessKey != "" {
+		o.mc.SetCredential(accessKey, cfg.GetString(configMasterSecretKey))
+		log.LogInfof("loadConfig: admin requests to master are signed by %v(%v)", configMasterAccessKey, accessKey)
+	}
 	o.vm = NewVolumeManager(masters, strict)
 	o.userStore = NewUserInfoStore(masters, strict)
 
@@ -366,10 +454,29 @@ func (o *ObjectNode) setAuditLog(raw interface{}) error {
 		}
 	}
 	o.closes = append(o.closes, func() { o.externalAudit.Close() })
+	// set the s3 server access log
+	if conf.AccessLog != nil {
+		al, err := NewAccessLogger(*conf.AccessLog, o.deliverAccessLog)
+		if err != nil {
+			return err
+		}
+		o.accessLogger = al
+		o.closes = append(o.closes, func() { al.Close() })
+	}
 
 	return nil
 }
 
+// deliverAccessLog puts the entries of the access log to the target bucket as a log object.
+func (o *ObjectNode) deliverAccessLog(bucket, key string, data []byte) error {
+	vol, err := o.getVol(bucket)
+	if err != nil {
+		return err
+	}
+	_, err = vol.PutObject(key, bytes.NewReader(data), &PutFileOption{MIMEType: ValueContentTypeText})
+	return err
+}
+
 func handleStart(s common.Server, cfg *config.Config) (err error) {
 	o, ok := s.(*ObjectNode)
 	if !ok {
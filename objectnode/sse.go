@@ -0,0 +1,324 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+const (
+	SSEAlgorithmAES256 = "AES256"
+	SSEAlgorithmKMS    = "aws:kms"
+)
+
+// SSEOption is the server-side encryption requested by the x-amz-server-side-encryption headers.
+type SSEOption struct {
+	Algorithm string
+	KeyID     string
+}
+
+// ParseSSEOption parses the server-side encryption headers of the request, nil is returned
+// if the object is not required to be encrypted.
+func ParseSSEOption(header http.Header) (*SSEOption, *ErrorCode) {
+	algorithm := header.Get(XAmzServerSideEncryption)
+	keyID := header.Get(XAmzServerSideEncryptionKeyID)
+	switch algorithm {
+	case "":
+		if keyID != "" {
+			return nil, InvalidArgument
+		}
+		return nil, nil
+	case SSEAlgorithmAES256:
+		if keyID != "" {
+			return nil, InvalidArgument
+		}
+	case SSEAlgorithmKMS:
+	default:
+		return nil, InvalidEncryptionAlgorithm
+	}
+	if sseKMS == nil {
+		return nil, ServerSideEncryptionNotConfigured
+	}
+	if keyID == "" {
+		keyID = sseKMS.DefaultKeyID()
+	}
+	return &SSEOption{Algorithm: algorithm, KeyID: keyID}, nil
+}
+
+// setSSEResponseHeader sets the server-side encryption headers of the response for the object.
+func setSSEResponseHeader(w http.ResponseWriter, algorithm, keyID string) {
+	if algorithm == "" {
+		return
+	}
+	w.Header().Set(XAmzServerSideEncryption, algorithm)
+	if algorithm == SSEAlgorithmKMS {
+		w.Header().Set(XAmzServerSideEncryptionKeyID, keyID)
+	}
+}
+
+func isSSEXAttrKey(key string) bool {
+	return strings.HasPrefix(key, XAttrKeyOSSSSE)
+}
+
+type ssePart struct {
+	id   uint16
+	size uint64
+}
+
+// ObjectEncryption is the envelope encryption of an object, the data is encrypted by AES-256-CTR
+// with a data key of the object, and the data key is sealed by the master key of the KMS.
+// The parts of a multipart upload are encrypted with the IVs derived from the part numbers.
+type ObjectEncryption struct {
+	Algorithm string
+	KeyID     string
+	key       []byte
+	sealedKey []byte
+	iv        []byte
+	parts     []ssePart
+	block     cipher.Block
+}
+
+func NewObjectEncryption(opt *SSEOption) (*ObjectEncryption, error) {
+	if sseKMS == nil {
+		return nil, ServerSideEncryptionNotConfigured
+	}
+	e := &ObjectEncryption{
+		Algorithm: opt.Algorithm,
+		KeyID:     opt.KeyID,
+		iv:        make([]byte, aes.BlockSize),
+	}
+	if e.KeyID == "" {
+		e.KeyID = sseKMS.DefaultKeyID()
+	}
+	var err error
+	if e.key, e.sealedKey, err = sseKMS.GenerateKey(e.KeyID); err != nil {
+		if err == ErrKMSKeyNotFound {
+			return nil, KMSKeyNotFound
+		}
+		return nil, err
+	}
+	if _, err = io.ReadFull(rand.Reader, e.iv); err != nil {
+		return nil, err
+	}
+	if e.block, err = aes.NewCipher(e.key); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// LoadObjectEncryption loads the encryption from the xattrs of the object, nil is returned
+// if the object is not encrypted.
+func LoadObjectEncryption(xattrs map[string]string) (e *ObjectEncryption, err error) {
+	algorithm := xattrs[XAttrKeyOSSSSE]
+	if algorithm == "" {
+		return nil, nil
+	}
+	if sseKMS == nil {
+		return nil, ServerSideEncryptionNotConfigured
+	}
+	e = &ObjectEncryption{
+		Algorithm: algorithm,
+		KeyID:     xattrs[XAttrKeyOSSSSEKeyID],
+	}
+	if e.sealedKey, err = base64.StdEncoding.DecodeString(xattrs[XAttrKeyOSSSSEKey]); err != nil {
+		return nil, fmt.Errorf("invalid sealed key: %v", err)
+	}
+	if e.iv, err = base64.StdEncoding.DecodeString(xattrs[XAttrKeyOSSSSEIV]); err != nil || len(e.iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv: %v", err)
+	}
+	if e.parts, err = parseSSEParts(xattrs[XAttrKeyOSSSSEParts]); err != nil {
+		return nil, err
+	}
+	if e.key, err = sseKMS.DecryptKey(e.KeyID, e.sealedKey); err != nil {
+		return nil, err
+	}
+	if e.block, err = aes.NewCipher(e.key); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// XAttrs returns the xattrs to be stored with the object.
+func (e *ObjectEncryption) XAttrs() map[string]string {
+	attrs := map[string]string{
+		XAttrKeyOSSSSE:      e.Algorithm,
+		XAttrKeyOSSSSEKeyID: e.KeyID,
+		XAttrKeyOSSSSEKey:   base64.StdEncoding.EncodeToString(e.sealedKey),
+		XAttrKeyOSSSSEIV:    base64.StdEncoding.EncodeToString(e.iv),
+	}
+	if len(e.parts) > 0 {
+		attrs[XAttrKeyOSSSSEParts] = formatSSEParts(e.parts)
+	}
+	return attrs
+}
+
+// ForPart returns the encryption of the part of the multipart upload.
+func (e *ObjectEncryption) ForPart(partID uint16) *ObjectEncryption {
+	part := *e
+	part.iv = e.partIV(partID)
+	part.parts = nil
+	return &part
+}
+
+func (e *ObjectEncryption) partIV(partID uint16) []byte {
+	buf := make([]byte, len(e.iv)+2)
+	copy(buf, e.iv)
+	binary.BigEndian.PutUint16(buf[len(e.iv):], partID)
+	sum := sha256.Sum256(buf)
+	return sum[:aes.BlockSize]
+}
+
+// XORKeyStream encrypts or decrypts the data at the offset of the object in place.
+func (e *ObjectEncryption) XORKeyStream(data []byte, offset uint64) {
+	if len(e.parts) == 0 {
+		e.xorKeyStream(e.iv, data, offset)
+		return
+	}
+	var partOffset uint64
+	for _, part := range e.parts {
+		if len(data) == 0 {
+			return
+		}
+		if offset >= partOffset+part.size {
+			partOffset += part.size
+			continue
+		}
+		n := partOffset + part.size - offset
+		if n > uint64(len(data)) {
+			n = uint64(len(data))
+		}
+		e.xorKeyStream(e.partIV(part.id), data[:n], offset-partOffset)
+		data = data[n:]
+		offset += n
+		partOffset += part.size
+	}
+}
+
+func (e *ObjectEncryption) xorKeyStream(iv, data []byte, offset uint64) {
+	// the counter of the block at the offset, which wraps around as the counter of CTR mode
+	ctr := make([]byte, aes.BlockSize)
+	lo := binary.BigEndian.Uint64(iv[8:])
+	hi := binary.BigEndian.Uint64(iv[:8])
+	blocks := offset / aes.BlockSize
+	if lo+blocks < lo {
+		hi++
+	}
+	binary.BigEndian.PutUint64(ctr[:8], hi)
+	binary.BigEndian.PutUint64(ctr[8:], lo+blocks)
+
+	stream := cipher.NewCTR(e.block, ctr)
+	if skip := offset % aes.BlockSize; skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+	stream.XORKeyStream(data, data)
+}
+
+// EncryptReader returns the reader of the encrypted data of the reader.
+func (e *ObjectEncryption) EncryptReader(r io.Reader) io.Reader {
+	return &sseReader{r: r, e: e}
+}
+
+// DecryptWriter returns the writer which decrypts the data of the object from the offset.
+func (e *ObjectEncryption) DecryptWriter(w io.Writer, offset uint64) io.Writer {
+	return &sseWriter{w: w, e: e, offset: offset}
+}
+
+type sseReader struct {
+	r      io.Reader
+	e      *ObjectEncryption
+	offset uint64
+}
+
+func (r *sseReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if n > 0 {
+		r.e.XORKeyStream(p[:n], r.offset)
+		r.offset += uint64(n)
+	}
+	return
+}
+
+type sseWriter struct {
+	w      io.Writer
+	e      *ObjectEncryption
+	offset uint64
+	buf    []byte
+}
+
+func (w *sseWriter) Write(p []byte) (n int, err error) {
+	if cap(w.buf) < len(p) {
+		w.buf = make([]byte, len(p))
+	}
+	buf := w.buf[:len(p)]
+	copy(buf, p)
+	w.e.XORKeyStream(buf, w.offset)
+	n, err = w.w.Write(buf)
+	w.offset += uint64(n)
+	return
+}
+
+// formatMultipartSSEParts records the sizes of the parts of the completed multipart upload,
+// which are required to decrypt the object as the parts are encrypted with their own IVs.
+func formatMultipartSSEParts(parts []*proto.MultipartPartInfo) string {
+	sseParts := make([]ssePart, 0, len(parts))
+	for _, part := range parts {
+		sseParts = append(sseParts, ssePart{id: part.ID, size: part.Size})
+	}
+	return formatSSEParts(sseParts)
+}
+
+func formatSSEParts(parts []ssePart) string {
+	items := make([]string, 0, len(parts))
+	for _, part := range parts {
+		items = append(items, fmt.Sprintf("%d:%d", part.id, part.size))
+	}
+	return strings.Join(items, ",")
+}
+
+func parseSSEParts(s string) (parts []ssePart, err error) {
+	if s == "" {
+		return
+	}
+	for _, item := range strings.Split(s, ",") {
+		fields := strings.Split(item, ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid sse part '%s'", item)
+		}
+		var id, size uint64
+		if id, err = strconv.ParseUint(fields[0], 10, 16); err != nil {
+			return nil, err
+		}
+		if size, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return nil, err
+		}
+		parts = append(parts, ssePart{id: uint16(id), size: size})
+	}
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].id < parts[j].id })
+	return
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package objectnode
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStaticKMS(t *testing.T) KMS {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	kms, err := NewKMS(KMSConfig{
+		DefaultKeyID: "key-1",
+		StaticKeys:   map[string]string{"key-1": base64.StdEncoding.EncodeToString(key)},
+	})
+	require.NoError(t, err)
+	return kms
+}
+
+func TestStaticKMS(t *testing.T) {
+	_, err := NewKMS(KMSConfig{StaticKeys: map[string]string{}})
+	require.Error(t, err)
+	_, err = NewKMS(KMSConfig{DefaultKeyID: "key-1", StaticKeys: map[string]string{"key-1": "short"}})
+	require.Error(t, err)
+	_, err = NewKMS(KMSConfig{DefaultKeyID: "key-1", StaticKeys: map[string]string{}})
+	require.Error(t, err)
+
+	kms := newTestStaticKMS(t)
+	require.Equal(t, "key-1", kms.DefaultKeyID())
+	key, sealedKey, err := kms.GenerateKey("key-1")
+	require.NoError(t, err)
+	require.Len(t, key, dataKeySize)
+	require.NotContains(t, string(sealedKey), string(key))
+
+	decrypted, err := kms.DecryptKey("key-1", sealedKey)
+	require.NoError(t, err)
+	require.Equal(t, key, decrypted)
+
+	_, _, err = kms.GenerateKey("key-2")
+	require.Equal(t, ErrKMSKeyNotFound, err)
+	sealedKey[len(sealedKey)-1] ^= 0xff
+	_, err = kms.DecryptKey("key-1", sealedKey)
+	require.Error(t, err)
+}
+
+func TestExternalKMS(t *testing.T) {
+	// the sealed key is the reversed data key in the test server
+	reverse := func(b []byte) []byte {
+		r := make([]byte, len(b))
+		for i := range b {
+			r[len(b)-1-i] = b[i]
+		}
+		return r
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/key-1") || r.Header.Get(Authorization) != "token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		req := new(kmsKeyRequest)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		resp := new(kmsKeyResponse)
+		if strings.HasPrefix(r.URL.Path, "/v1/key/generate/") {
+			resp.Plaintext = make([]byte, dataKeySize)
+			_, _ = rand.Read(resp.Plaintext)
+			resp.Ciphertext = reverse(resp.Plaintext)
+		} else {
+			resp.Plaintext = reverse(req.Ciphertext)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	conf := KMSConfig{Type: KMSTypeExternal, DefaultKeyID: "key-1"}
+	_, err := NewKMS(conf)
+	require.Error(t, err)
+	conf.Endpoint = server.URL
+	conf.Authorization = "token"
+	kms, err := NewKMS(conf)
+	require.NoError(t, err)
+
+	key, sealedKey, err := kms.GenerateKey("key-1")
+	require.NoError(t, err)
+	require.Equal(t, reverse(key), sealedKey)
+	decrypted, err := kms.DecryptKey("key-1", sealedKey)
+	require.NoError(t, err)
+	require.Equal(t, key, decrypted)
+	_, _, err = kms.GenerateKey("key-2")
+	require.Equal(t, ErrKMSKeyNotFound, err)
+}
+
+func TestParseSSEOption(t *testing.T) {
+	defer func(kms KMS) { sseKMS = kms }(sseKMS)
+	sseKMS = nil
+
+	header := make(http.Header)
+	opt, errorCode := ParseSSEOption(header)
+	require.Nil(t, errorCode)
+	require.Nil(t, opt)
+	header.Set(XAmzServerSideEncryption, SSEAlgorithmAES256)
+	_, errorCode = ParseSSEOption(header)
+	require.Equal(t, ServerSideEncryptionNotConfigured, errorCode)
+
+	sseKMS = newTestStaticKMS(t)
+	opt, errorCode = ParseSSEOption(header)
+	require.Nil(t, errorCode)
+	require.Equal(t, &SSEOption{Algorithm: SSEAlgorithmAES256, KeyID: "key-1"}, opt)
+	header.Set(XAmzServerSideEncryptionKeyID, "key-2")
+	_, errorCode = ParseSSEOption(header)
+	require.Equal(t, InvalidArgument, errorCode)
+	header.Set(XAmzServerSideEncryption, SSEAlgorithmKMS)
+	opt, errorCode = ParseSSEOption(header)
+	require.Nil(t, errorCode)
+	require.Equal(t, &SSEOption{Algorithm: SSEAlgorithmKMS, KeyID: "key-2"}, opt)
+	header.Set(XAmzServerSideEncryption, "AES128")
+	_, errorCode = ParseSSEOption(header)
+	require.Equal(t, InvalidEncryptionAlgorithm, errorCode)
+
+	_, err := NewObjectEncryption(opt)
+	require.Equal(t, KMSKeyNotFound, err)
+}
+
+func TestObjectEncryption(t *testing.T) {
+	defer func(kms KMS) { sseKMS = kms }(sseKMS)
+	sseKMS = newTestStaticKMS(t)
+
+	plain := make([]byte, 1<<20+7)
+	_, err := rand.Read(plain)
+	require.NoError(t, err)
+
+	e, err := NewObjectEncryption(&SSEOption{Algorithm: SSEAlgorithmAES256})
+	require.NoError(t, err)
+	require.Equal(t, "key-1", e.KeyID)
+	cipherText, err := io.ReadAll(io.LimitReader(e.EncryptReader(bytes.NewReader(plain)), int64(len(plain))))
+	require.NoError(t, err)
+	require.NotEqual(t, plain, cipherText)
+
+	// decrypt the ranges with the data key loaded from the xattrs
+	loaded, err := LoadObjectEncryption(e.XAttrs())
+	require.NoError(t, err)
+	for _, r := range [][2]int{{0, len(plain)}, {1, 15}, {15, 33}, {4097, 70000}, {len(plain) - 3, len(plain)}} {
+		buf := new(bytes.Buffer)
+		w := loaded.DecryptWriter(buf, uint64(r[0]))
+		_, err = w.Write(cipherText[r[0]:r[1]])
+		require.NoError(t, err)
+		require.Equal(t, plain[r[0]:r[1]], buf.Bytes(), r)
+	}
+
+	e, err = LoadObjectEncryption(map[string]string{})
+	require.NoError(t, err)
+	require.Nil(t, e)
+}
+
+func TestMultipartObjectEncryption(t *testing.T) {
+	defer func(kms KMS) { sseKMS = kms }(sseKMS)
+	sseKMS = newTestStaticKMS(t)
+
+	session, err := NewObjectEncryption(&SSEOption{Algorithm: SSEAlgorithmKMS, KeyID: "key-1"})
+	require.NoError(t, err)
+	extend := session.XAttrs()
+
+	// the parts are encrypted with the session loaded from the extend of the multipart
+	sizes := []int{5000, 17, 1 << 16}
+	plain := make([]byte, 0)
+	cipherText := make([]byte, 0)
+	parts := make([]*proto.MultipartPartInfo, 0)
+	for i, size := range sizes {
+		e, err := LoadObjectEncryption(extend)
+		require.NoError(t, err)
+		part := make([]byte, size)
+		_, _ = rand.Read(part)
+		encrypted, err := io.ReadAll(e.ForPart(uint16(i + 1)).EncryptReader(bytes.NewReader(part)))
+		require.NoError(t, err)
+		plain = append(plain, part...)
+		cipherText = append(cipherText, encrypted...)
+		parts = append(parts, &proto.MultipartPartInfo{ID: uint16(i + 1), Size: uint64(size)})
+	}
+
+	xattrs := make(map[string]string)
+	for key, value := range extend {
+		xattrs[key] = value
+	}
+	xattrs[XAttrKeyOSSSSEParts] = formatMultipartSSEParts(parts)
+	e, err := LoadObjectEncryption(xattrs)
+	require.NoError(t, err)
+	for _, r := range [][2]int{{0, len(plain)}, {4990, 5020}, {5010, 5017}, {5016, 6000}} {
+		data := append([]byte{}, cipherText[r[0]:r[1]]...)
+		e.XORKeyStream(data, uint64(r[0]))
+		require.Equal(t, plain[r[0]:r[1]], data, r)
+	}
+
+	_, err = parseSSEParts("1:10,2")
+	require.Error(t, err)
+}
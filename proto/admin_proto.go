@@ -33,6 +33,7 @@ const (
 	AdminGetApiQpsLimit                       = "/admin/getApiQpsLimit"
 	AdminRemoveApiQpsLimit                    = "/admin/rmApiQpsLimit"
 	AdminGetCluster                           = "/admin/getCluster"
+	AdminGetClusterEvents                     = "/admin/getClusterEvents"
 	AdminSetClusterInfo                       = "/admin/setClusterInfo"
 	AdminGetMonitorPushAddr                   = "/admin/getMonitorPushAddr"
 	AdminGetDataPartition                     = "/dataPartition/get"
@@ -43,6 +44,8 @@ const (
 	AdminDiagnoseDataPartition                = "/dataPartition/diagnose"
 	AdminResetDataPartitionDecommissionStatus = "/dataPartition/resetDecommissionStatus"
 	AdminQueryDataPartitionDecommissionStatus = "/dataPartition/queryDecommissionStatus"
+	AdminSetReplicaRepair                     = "/dataPartition/replicaRepair/set"
+	AdminGetReplicaRepairBacklog              = "/dataPartition/replicaRepair/backlog"
 	AdminDeleteDataReplica                    = "/dataReplica/delete"
 	AdminAddDataReplica                       = "/dataReplica/add"
 	AdminDeleteVol                            = "/vol/delete"
@@ -51,11 +54,19 @@ const (
 	AdminVolExpand                            = "/vol/expand"
 	AdminVolForbidden                         = "/vol/forbidden"
 	AdminVolEnableAuditLog                    = "/vol/auditlog"
+	AdminVolSetTrashInterval                  = "/vol/setTrashInterval"
+	AdminVolSetAutoScale                      = "/vol/autoScale/set"
+	AdminVolGetAutoScale                      = "/vol/autoScale/get"
+	AdminVolSetPlacement                      = "/vol/placement/set"
+	AdminVolGetPlacement                      = "/vol/placement/get"
+	AdminVolSetQosLimit                       = "/vol/qosLimit/set"
+	AdminVolGetQosLimit                       = "/vol/qosLimit/get"
 	AdminCreateVol                            = "/admin/createVol"
 	AdminGetVol                               = "/admin/getVol"
 	AdminClusterFreeze                        = "/cluster/freeze"
 	AdminClusterForbidMpDecommission          = "/cluster/forbidMetaPartitionDecommission"
 	AdminClusterStat                          = "/cluster/stat"
+	AdminClusterStatHistory                   = "/cluster/statHistory"
 	AdminSetCheckDataReplicasEnable           = "/cluster/setCheckDataReplicasEnable"
 	AdminGetIP                                = "/admin/getIp"
 	AdminCreateMetaPartition                  = "/metaPartition/create"
@@ -74,6 +85,8 @@ const (
 	AdminUpdateZoneExcludeRatio               = "/admin/updateZoneExcludeRatio"
 	AdminSetNodeRdOnly                        = "/admin/setNodeRdOnly"
 	AdminSetDpRdOnly                          = "/admin/setDpRdOnly"
+	AdminEnterNodeMaintenance                 = "/admin/enterNodeMaintenance"
+	AdminExitNodeMaintenance                  = "/admin/exitNodeMaintenance"
 	AdminSetConfig                            = "/admin/setConfig"
 	AdminGetConfig                            = "/admin/getConfig"
 	AdminDataPartitionChangeLeader            = "/dataPartition/changeleader"
@@ -121,6 +134,7 @@ const (
 	ClientMetaPartition      = "/metaPartition/get"
 	ClientVolStat            = "/client/volStat"
 	ClientMetaPartitions     = "/client/metaPartitions"
+	ClientTopologyEvents     = "/client/topologyEvents"
 
 	// qos api
 	QosGetStatus           = "/qos/getStatus"
@@ -151,16 +165,21 @@ const (
 	QueryDataNodeDecoFailedDps         = "/dataNode/queryDecommissionFailedDps"
 	MigrateDataNode                    = "/dataNode/migrate"
 	PauseDecommissionDataNode          = "/dataNode/pauseDecommission"
+	ResumeDecommissionDataNode         = "/dataNode/resumeDecommission"
+	QueryDataNodeDecoDetail            = "/dataNode/queryDecommissionDetail"
 	CancelDecommissionDataNode         = "/dataNode/cancelDecommission"
 	DecommissionDisk                   = "/disk/decommission"
 	RecommissionDisk                   = "/disk/recommission"
 	QueryDiskDecoProgress              = "/disk/queryDecommissionProgress"
 	MarkDecoDiskFixed                  = "/disk/MarkDecommissionDiskFixed"
 	PauseDecommissionDisk              = "/disk/pauseDecommission"
+	ResumeDecommissionDisk             = "/disk/resumeDecommission"
+	QueryDiskDecoDetail                = "/disk/queryDecommissionDetail"
 	QueryDecommissionDiskDecoFailedDps = "/disk/queryDecommissionFailedDps"
 	QueryBadDisks                      = "/disk/queryBadDisks"
 	QueryDisks                         = "/disk/queryDisks"
 	QueryDiskDetail                    = "/disk/detail"
+	QueryPredictedFailureDisks         = "/disk/queryPredictedFailures"
 	RestoreStoppedAutoDecommissionDisk = "/disk/restoreStoppedAutoDecommissionDisk"
 	QueryAllDecommissionDisk           = "/disk/queryAllDecommissionDisk"
 	GetDataNode                        = "/dataNode/get"
@@ -174,6 +193,7 @@ const (
 	AdminLoadMetaPartition             = "/metaPartition/load"
 	AdminDiagnoseMetaPartition         = "/metaPartition/diagnose"
 	AdminDecommissionMetaPartition     = "/metaPartition/decommission"
+	AdminSplitMetaPartition            = "/metaPartition/split"
 	AdminChangeMetaPartitionLeader     = "/metaPartition/changeleader"
 	AdminBalanceMetaPartitionLeader    = "/metaPartition/balanceLeader"
 	AdminAddMetaReplica                = "/metaReplica/add"
@@ -208,6 +228,14 @@ const (
 	GetNodeSet      = "/nodeSet/get"
 	UpdateNodeSet   = "/nodeSet/update"
 
+	// rebalance data partitions among nodes of each zone
+	SetNodeSetRebalance = "/nodeSet/rebalance/set"
+	GetNodeSetRebalance = "/nodeSet/rebalance/status"
+
+	// reserve capacity of zones which new partitions of volumes can not use
+	SetZoneReservation = "/zone/setReservation"
+	GetZoneReservation = "/zone/getReservation"
+
 	// Header keys
 	SkipOwnerValidation = "Skip-Owner-Validation"
 	ForceDelete         = "Force-Delete"
@@ -242,12 +270,33 @@ const (
 	S3QoSSet    = "/s3/qos/set"
 	S3QoSGet    = "/s3/qos/get"
 	S3QoSDelete = "/s3/qos/delete"
+
+	// APIs for tenant management
+	TenantCreate     = "/tenant/create"
+	TenantDelete     = "/tenant/delete"
+	TenantUpdate     = "/tenant/update"
+	TenantGet        = "/tenant/get"
+	TenantList       = "/tenant/list"
+	TenantAddUser    = "/tenant/addUser"
+	TenantRemoveUser = "/tenant/removeUser"
+	TenantAddVol     = "/tenant/addVol"
+	TenantRemoveVol  = "/tenant/removeVol"
+
+	// APIs for role management
+	RoleCreate = "/role/create"
+	RoleDelete = "/role/delete"
+	RoleUpdate = "/role/update"
+	RoleGet    = "/role/get"
+	RoleList   = "/role/list"
+	RoleGrant  = "/role/grant"
+	RoleRevoke = "/role/revoke"
 )
 
 var GApiInfo map[string]string = map[string]string{
 	"admingetmasterapilist":              AdminGetMasterApiList,
 	"adminsetapiqpslimit":                AdminSetApiQpsLimit,
 	"admingetcluster":                    AdminGetCluster,
+	"admingetclusterevents":              AdminGetClusterEvents,
 	"adminsetclusterinfo":                AdminSetClusterInfo,
 	"admingetdatapartition":              AdminGetDataPartition,
 	"adminloaddatapartition":             AdminLoadDataPartition,
@@ -255,10 +304,19 @@ var GApiInfo map[string]string = map[string]string{
 	"admincreatepreloaddatapartition":    AdminCreatePreLoadDataPartition,
 	"admindecommissiondatapartition":     AdminDecommissionDataPartition,
 	"admindiagnosedatapartition":         AdminDiagnoseDataPartition,
+	"adminsetreplicarepair":              AdminSetReplicaRepair,
+	"admingetreplicarepairbacklog":       AdminGetReplicaRepairBacklog,
 	"admindeletedatareplica":             AdminDeleteDataReplica,
 	"adminadddatareplica":                AdminAddDataReplica,
 	"admindeletevol":                     AdminDeleteVol,
 	"adminupdatevol":                     AdminUpdateVol,
+	"adminvolsetautoscale":               AdminVolSetAutoScale,
+	"adminvolgetautoscale":               AdminVolGetAutoScale,
+	"adminvolsetplacement":               AdminVolSetPlacement,
+	"adminvolgetplacement":               AdminVolGetPlacement,
+	"adminvolsettrashinterval":           AdminVolSetTrashInterval,
+	"adminvolsetqoslimit":                AdminVolSetQosLimit,
+	"adminvolgetqoslimit":                AdminVolGetQosLimit,
 	"adminvolshrink":                     AdminVolShrink,
 	"adminvolexpand":                     AdminVolExpand,
 	"admincreatevol":                     AdminCreateVol,
@@ -266,6 +324,7 @@ var GApiInfo map[string]string = map[string]string{
 	"adminclusterfreeze":                 AdminClusterFreeze,
 	"adminclusterforbidmpdecommission":   AdminClusterForbidMpDecommission,
 	"adminclusterstat":                   AdminClusterStat,
+	"adminclusterstathistory":            AdminClusterStatHistory,
 	"admingetip":                         AdminGetIP,
 	"admincreatemetapartition":           AdminCreateMetaPartition,
 	"adminsetmetanodethreshold":          AdminSetMetaNodeThreshold,
@@ -282,6 +341,8 @@ var GApiInfo map[string]string = map[string]string{
 	"adminupdatezoneexcluderatio":        AdminUpdateZoneExcludeRatio,
 	"adminsetnoderdonly":                 AdminSetNodeRdOnly,
 	"adminsetdprdonly":                   AdminSetDpRdOnly,
+	"adminenternodemaintenance":          AdminEnterNodeMaintenance,
+	"adminexitnodemaintenance":           AdminExitNodeMaintenance,
 	"admindatapartitionchangeleader":     AdminDataPartitionChangeLeader,
 	"adminsetdpdiscard":                  AdminSetDpDiscard,
 	"admingetdiscarddp":                  AdminGetDiscardDp,
@@ -300,6 +361,7 @@ var GApiInfo map[string]string = map[string]string{
 	"clientmetapartition":    ClientMetaPartition,
 	"clientvolstat":          ClientVolStat,
 	"clientmetapartitions":   ClientMetaPartitions,
+	"clienttopologyevents":   ClientTopologyEvents,
 	"qosgetstatus":           QosGetStatus,
 	"qosgetclientslimitinfo": QosGetClientsLimitInfo,
 	"qosgetzonelimitinfo":    QosGetZoneLimitInfo,
@@ -316,6 +378,10 @@ var GApiInfo map[string]string = map[string]string{
 	"decommissiondatanode":            DecommissionDataNode,
 	"migratedatanode":                 MigrateDataNode,
 	"canceldecommissiondatanode":      PauseDecommissionDataNode,
+	"resumedecommissiondatanode":      ResumeDecommissionDataNode,
+	"querydatanodedecodetail":         QueryDataNodeDecoDetail,
+	"resumedecommissiondisk":          ResumeDecommissionDisk,
+	"querydiskdecodetail":             QueryDiskDecoDetail,
 	"decommissiondisk":                DecommissionDisk,
 	"getdatanode":                     GetDataNode,
 	"addmetanode":                     AddMetaNode,
@@ -328,6 +394,7 @@ var GApiInfo map[string]string = map[string]string{
 	"adminloadmetapartition":          AdminLoadMetaPartition,
 	"admindiagnosemetapartition":      AdminDiagnoseMetaPartition,
 	"admindecommissionmetapartition":  AdminDecommissionMetaPartition,
+	"adminsplitmetapartition":         AdminSplitMetaPartition,
 	"adminchangemetapartitionleader":  AdminChangeMetaPartitionLeader,
 	"adminbalancemetapartitionleader": AdminBalanceMetaPartitionLeader,
 	"adminaddmetareplica":             AdminAddMetaReplica,
@@ -337,6 +404,10 @@ var GApiInfo map[string]string = map[string]string{
 	"gettopologyview":                 GetTopologyView,
 	"updatezone":                      UpdateZone,
 	"getallzones":                     GetAllZones,
+	"setzonereservation":              SetZoneReservation,
+	"getzonereservation":              GetZoneReservation,
+	"setnodesetrebalance":             SetNodeSetRebalance,
+	"getnodesetrebalance":             GetNodeSetRebalance,
 	"usercreate":                      UserCreate,
 	"userdelete":                      UserDelete,
 	"userupdate":                      UserUpdate,
@@ -348,6 +419,22 @@ var GApiInfo map[string]string = map[string]string{
 	"usertransfervol":                 UserTransferVol,
 	"userlist":                        UserList,
 	"usersofvol":                      UsersOfVol,
+	"tenantcreate":                    TenantCreate,
+	"tenantdelete":                    TenantDelete,
+	"tenantupdate":                    TenantUpdate,
+	"tenantget":                       TenantGet,
+	"tenantlist":                      TenantList,
+	"tenantadduser":                   TenantAddUser,
+	"tenantremoveuser":                TenantRemoveUser,
+	"tenantaddvol":                    TenantAddVol,
+	"tenantremovevol":                 TenantRemoveVol,
+	"rolecreate":                      RoleCreate,
+	"roledelete":                      RoleDelete,
+	"roleupdate":                      RoleUpdate,
+	"roleget":                         RoleGet,
+	"rolelist":                        RoleList,
+	"rolegrant":                       RoleGrant,
+	"rolerevoke":                      RoleRevoke,
 }
 
 // const TimeFormat = "2006-01-02 15:04:05"
@@ -356,6 +443,8 @@ const (
 	TimeFormat                 = "2006-01-02 15:04:05"
 	DefaultDirChildrenNumLimit = 20000000
 	MinDirChildrenNumLimit     = 1000000
+	// MaxInlineDirThreshold is the max number of children of a directory stored inline in meta nodes
+	MaxInlineDirThreshold = 256
 )
 
 // HTTPReply uniform response structure
@@ -690,7 +779,8 @@ type HeartBeatRequest struct {
 	TxInfos
 	ForbiddenVols     []string
 	DisableAuditVols  []string
-	DecommissionDisks []string // NOTE: for datanode
+	DecommissionDisks []string                // NOTE: for datanode
+	VolQosLimits      map[string]*VolQosLimit // NOTE: for datanode, the share of the node of volume qos limits
 }
 
 // DataPartitionReport defines the partition report.
@@ -705,6 +795,17 @@ type DataPartitionReport struct {
 	ExtentCount                int
 	NeedCompare                bool
 	DecommissionRepairProgress float64
+	LastScrubTime              int64
+	ScrubErrors                []*ExtentScrubError
+}
+
+// ExtentScrubError is a mismatch of extent crc found by the scrubber of data nodes.
+type ExtentScrubError struct {
+	ExtentID uint64
+	Addr     string // the replica holding the mismatched data
+	Reason   string
+	Repaired bool
+	Time     int64
 }
 
 type DataNodeQosResponse struct {
@@ -728,6 +829,31 @@ type DiskStat struct {
 	TotalPartitionCnt int
 
 	DiskErrPartitionList []uint64
+
+	Health *DiskHealth `json:",omitempty"`
+}
+
+// DiskHealth holds the health indicators of a disk collected from the SMART data and /proc/diskstats.
+type DiskHealth struct {
+	Device         string
+	SmartAvailable bool // false if smartctl is not installed or the device does not support SMART
+	SmartPassed    bool // overall-health self-assessment
+
+	ReallocatedSectors   uint64 // ATA attribute 5
+	PendingSectors       uint64 // ATA attribute 197
+	UncorrectableSectors uint64 // ATA attribute 198
+	MediaErrors          uint64 // NVMe media and data integrity errors
+	CriticalWarning      uint64 // NVMe critical warning bits
+	PercentageUsed       uint64 // NVMe percentage of the endurance used
+	Temperature          uint64 // celsius
+	PowerOnHours         uint64
+
+	ReadErrCnt     uint64 // io errors seen by the data node
+	WriteErrCnt    uint64
+	ReadLatencyMs  float64 // average latency of the reads since the last collection
+	WriteLatencyMs float64 // average latency of the writes since the last collection
+
+	UpdateTime int64
 }
 
 // DataNodeHeartbeatResponse defines the response to the data node heartbeat.
@@ -768,6 +894,7 @@ type MetaPartitionReport struct {
 	FreeListLen      uint64
 	UidInfo          []*UidReportSpaceInfo
 	QuotaReportInfos []*QuotaReportInfo
+	Qps              uint64 // requests per second served by the leader
 }
 
 // MetaNodeHeartbeatResponse defines the response to the meta node heartbeat request.
@@ -936,6 +1063,7 @@ type VolView struct {
 	DeleteLockTime int64
 	CacheTTL       int
 	VolType        int
+	TrashInterval  int64
 }
 
 func (v *VolView) SetOwner(owner string) {
@@ -1083,6 +1211,9 @@ type SimpleVolView struct {
 	TxConflictRetryNum      int64
 	TxConflictRetryInterval int64
 	TxOpLimit               int
+	InlineDirThreshold      int
+	ClientQpsLimit          int
+	TrashInterval           int64
 	Description             string
 	DpSelectorName          string
 	DpSelectorParm          string
@@ -1106,6 +1237,7 @@ type SimpleVolView struct {
 	Forbidden      bool
 	EnableAuditLog bool
 	DeleteExecTime time.Time
+	QosLimit       *VolQosLimit
 }
 
 type NodeSetInfo struct {
@@ -1178,6 +1310,34 @@ type ZoneView struct {
 	DataNodesetSelector string
 	MetaNodesetSelector string
 	NodeSet             map[uint64]*NodeSetView
+	Usage               *ZoneUsageView `json:",omitempty"`
+}
+
+// ZoneUsageView define the node counts, capacity and writable partitions of zone,
+// a partition is counted in every zone holding one of its replicas.
+type ZoneUsageView struct {
+	DataNodeCount          int
+	MetaNodeCount          int
+	DataTotal              uint64
+	DataUsed               uint64
+	MetaTotal              uint64
+	MetaUsed               uint64
+	WritableDataPartitions int
+	WritableMetaPartitions int
+}
+
+// ZoneReservationView define the capacity reserved in a zone for repairs and emergency expansion,
+// new partitions of volumes are not allocated in the zone when its used capacity reaches the unreserved.
+type ZoneReservationView struct {
+	Name              string
+	DataReservedRatio float64
+	MetaReservedRatio float64
+	DataTotal         uint64
+	DataUsed          uint64
+	MetaTotal         uint64
+	MetaUsed          uint64
+	DataUsedUp        bool
+	MetaUsedUp        bool
 }
 
 type NodeSetView struct {
@@ -1244,3 +1404,131 @@ const (
 const (
 	LFClient = 1 // low frequency client
 )
+
+// severity of cluster events
+const (
+	EventSeverityInfo    = "info"
+	EventSeverityWarning = "warning"
+	EventSeverityError   = "error"
+)
+
+// VolAutoScalePolicy expands data partitions of the volume automatically when its writable space (GB) or
+// count of writable data partitions drops below the thresholds, a zero threshold is not checked.
+type VolAutoScalePolicy struct {
+	Enable           bool
+	MinWritableSpace uint64
+	MinWritableDp    int
+	ExpandCount      int
+	CooldownSec      int64
+}
+
+// VolAutoScaleRecord is an automatic expansion of the volume
+type VolAutoScaleRecord struct {
+	Time          int64
+	Reason        string
+	WritableSpace uint64
+	WritableDp    int
+	ExpandCount   int
+	Created       int
+	Error         string `json:",omitempty"`
+}
+
+// VolAutoScaleView is the auto-scaling policy of the volume and its latest automatic expansions
+type VolAutoScaleView struct {
+	Name          string
+	Policy        *VolAutoScalePolicy
+	WritableSpace uint64
+	WritableDp    int
+	Records       []*VolAutoScaleRecord
+}
+
+// VolPlacement constraints of replica placement of the volume, master enforces them when it creates
+// partitions of the volume or chooses new replicas of them.
+type VolPlacement struct {
+	// MinZones replicas of a partition span at least MinZones zones
+	MinZones int
+	// ExcludeNodeSets replicas are not placed in these node sets
+	ExcludeNodeSets []uint64
+}
+
+// VolPlacementField describes a field of VolPlacement
+type VolPlacementField struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// VolPlacementSchema the schema of placement constraints
+var VolPlacementSchema = []VolPlacementField{
+	{Name: "minZones", Type: "int", Description: "replicas of a partition span at least minZones zones, 0 means not constrained"},
+	{Name: "excludeNodeSets", Type: "[]uint64", Description: "comma separated ids of node sets in which replicas are not placed"},
+}
+
+type VolPlacementView struct {
+	Name      string
+	Placement *VolPlacement
+	Schema    []VolPlacementField
+}
+
+// VolQosLimit the read/write iops and flow(bytes per second) limits of the volume, 0 means no limit.
+// Master shares the limits among data nodes of the volume and datanodes enforce them.
+type VolQosLimit struct {
+	IopsReadLimit  uint64
+	IopsWriteLimit uint64
+	FlowReadLimit  uint64
+	FlowWriteLimit uint64
+}
+
+func (l *VolQosLimit) IsEmpty() bool {
+	return l == nil || (l.IopsReadLimit == 0 && l.IopsWriteLimit == 0 && l.FlowReadLimit == 0 && l.FlowWriteLimit == 0)
+}
+
+// ClusterEvent is an event of the cluster recorded by master, such as node down and partition repair
+type ClusterEvent struct {
+	Seq      uint64
+	Time     int64
+	Severity string
+	Module   string
+	Message  string
+}
+
+// ClusterEventsView is the events of the cluster since a sequence, NextSeq is the sequence to get the following events
+type ClusterEventsView struct {
+	Events  []*ClusterEvent
+	NextSeq uint64
+}
+
+// types of topology events
+const (
+	TopologyEventDataNodeAdded              = "DataNodeAdded"
+	TopologyEventDataNodeRemoved            = "DataNodeRemoved"
+	TopologyEventMetaNodeAdded              = "MetaNodeAdded"
+	TopologyEventMetaNodeRemoved            = "MetaNodeRemoved"
+	TopologyEventDataPartitionLeaderChanged = "DataPartitionLeaderChanged"
+	TopologyEventMetaPartitionLeaderChanged = "MetaPartitionLeaderChanged"
+	// TopologyEventResync is delivered by the sdk when events may have been lost,
+	// subscribers should reload the topology they care about.
+	TopologyEventResync = "Resync"
+)
+
+// TopologyEvent is a change of the cluster topology, Addr is the node added or removed,
+// or the new leader of the partition.
+type TopologyEvent struct {
+	Seq         uint64
+	Time        int64
+	Type        string
+	Addr        string
+	ZoneName    string
+	VolName     string
+	PartitionID uint64
+}
+
+// TopologyEventsView is the topology events since a sequence. Epoch changes when the leader of master
+// changes, Truncated is set if events since the sequence have been dropped, subscribers should reload
+// the topology and continue from NextSeq in both cases.
+type TopologyEventsView struct {
+	Epoch     int64
+	Events    []*TopologyEvent
+	NextSeq   uint64
+	Truncated bool
+}
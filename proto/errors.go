@@ -100,6 +100,10 @@ var (
 	ErrNodeSetNotExists                        = errors.New("node set not exists")
 	ErrCompressFailed                          = errors.New("compress data failed")
 	ErrDecompressFailed                        = errors.New("decompress data failed")
+	ErrTenantNotExists                         = errors.New("tenant not exists")
+	ErrDuplicateTenant                         = errors.New("duplicate tenant")
+	ErrRoleNotExists                           = errors.New("role not exists")
+	ErrDuplicateRole                           = errors.New("duplicate role")
 )
 
 // http response error code and error message definitions
@@ -169,6 +173,10 @@ const (
 	ErrCodeZoneNumError
 	ErrCodeVersionOpError
 	ErrCodeNodeSetNotExists
+	ErrCodeTenantNotExists
+	ErrCodeDuplicateTenant
+	ErrCodeRoleNotExists
+	ErrCodeDuplicateRole
 )
 
 // Err2CodeMap error map to code
@@ -234,6 +242,10 @@ var Err2CodeMap = map[error]int32{
 	ErrZoneNum:                         ErrCodeZoneNumError,
 	ErrCodeVersionOp:                   ErrCodeVersionOpError,
 	ErrNodeSetNotExists:                ErrCodeNodeSetNotExists,
+	ErrTenantNotExists:                 ErrCodeTenantNotExists,
+	ErrDuplicateTenant:                 ErrCodeDuplicateTenant,
+	ErrRoleNotExists:                   ErrCodeRoleNotExists,
+	ErrDuplicateRole:                   ErrCodeDuplicateRole,
 }
 
 func ParseErrorCode(code int32) error {
@@ -308,6 +320,10 @@ var code2ErrMap = map[int32]error{
 	ErrCodeNodeSetNotExists:                ErrNodeSetNotExists,
 	ErrCodeVolNotDelete:                    ErrVolNotDelete,
 	ErrCodeVolHasDeleted:                   ErrVolHasDeleted,
+	ErrCodeTenantNotExists:                 ErrTenantNotExists,
+	ErrCodeDuplicateTenant:                 ErrDuplicateTenant,
+	ErrCodeRoleNotExists:                   ErrRoleNotExists,
+	ErrCodeDuplicateRole:                   ErrDuplicateRole,
 }
 
 type GeneralResp struct {
@@ -407,6 +407,15 @@ type BatchEvictInodeRequest struct {
 	FullPaths   []string `json:"fullPaths"`
 }
 
+// BatchRecordAccessRequest reports the access time of inodes, which is tracked by meta
+// partitions and persisted lazily.
+type BatchRecordAccessRequest struct {
+	VolName     string   `json:"vol"`
+	PartitionID uint64   `json:"pid"`
+	Inodes      []uint64 `json:"inos"`
+	AccessTime  int64    `json:"at"`
+}
+
 // CreateDentryRequest defines the request to create a dentry.
 type QuotaCreateDentryRequest struct {
 	VolName     string   `json:"vol"`
@@ -515,6 +524,8 @@ type DeleteDentryRequest struct {
 	Name            string `json:"name"`
 	InodeCreateTime int64  `json:"inodeCreateTime"`
 	Verseq          uint64 `json:"ver"`
+	// move the dentry into trash if trash of the volume is enabled, directories are deleted directly
+	Trash bool `json:"trash"`
 	RequestExtend
 }
 
@@ -529,6 +540,8 @@ type BatchDeleteDentryRequest struct {
 // DeleteDentryResponse defines the response to the request of deleting a dentry.
 type DeleteDentryResponse struct {
 	Inode uint64 `json:"ino"`
+	// the dentry is moved into trash, the inode must not be unlinked
+	Trashed bool `json:"trashed"`
 }
 
 // BatchDeleteDentryResponse defines the response to the request of deleting a dentry.
@@ -1011,6 +1024,118 @@ type GetInodeQuotaResponse struct {
 	MetaQuotaInfoMap map[uint32]*MetaQuotaInfo
 }
 
+// Change event types of namespace mutations.
+const (
+	ChangeEventCreate      = "create"       // dentry created
+	ChangeEventDelete      = "delete"       // dentry deleted
+	ChangeEventRename      = "rename"       // dentry points to another inode, eg: rename with overwrite
+	ChangeEventAttr        = "attr"         // inode attributes changed
+	ChangeEventInodeCreate = "inode_create" // inode created
+	ChangeEventInodeUnlink = "inode_unlink" // inode nlink decreased
+	ChangeEventInodeEvict  = "inode_evict"  // inode marked deleted
+	ChangeEventTrashPurge  = "trash_purge"  // dentry deleted into trash is purged
+)
+
+// ChangeEvent is one namespace mutation of meta partition.
+// Seq is the raft apply index of the mutation, it is ordered and
+// consistent on all replicas of the partition. Events of one batch
+// mutation share the same seq.
+type ChangeEvent struct {
+	PartitionID uint64     `json:"pid,omitempty"`
+	Seq         uint64     `json:"seq"`
+	Type        string     `json:"type"`
+	Timestamp   int64      `json:"ts"`
+	ParentID    uint64     `json:"pino,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Inode       uint64     `json:"ino"`
+	OldInode    uint64     `json:"oldIno,omitempty"`
+	Info        *InodeInfo `json:"info,omitempty"`
+}
+
+// GetChangeEventsRequest reads change events of the partition with seq greater than From.
+type GetChangeEventsRequest struct {
+	VolName     string `json:"vol"`
+	PartitionId uint64 `json:"pid"`
+	From        uint64 `json:"from"`
+	Limit       int    `json:"limit"`
+}
+
+// ChangeEvents is the result of reading change log of meta partition.
+// Truncated means some events after the requested seq are evicted or
+// lost, the consumer should resync with full scan.
+type ChangeEvents struct {
+	PartitionID uint64        `json:"pid"`
+	Events      []ChangeEvent `json:"events"`
+	NextSeq     uint64        `json:"nextSeq"`
+	Truncated   bool          `json:"truncated"`
+}
+
+// SearchXAttrRequest looks up inodes of the partition by the secondary index of user xattrs,
+// the value is matched exactly or as a prefix. Results are ordered by value and inode, and
+// start after the marker if it is set.
+type SearchXAttrRequest struct {
+	VolName     string `json:"vol"`
+	PartitionId uint64 `json:"pid"`
+	Key         string `json:"key"`
+	Value       string `json:"val"`
+	Prefix      bool   `json:"prefix"`
+	MarkerValue string `json:"mval"`
+	MarkerInode uint64 `json:"mino"`
+	Limit       int    `json:"limit"`
+}
+
+type XAttrSearchItem struct {
+	Inode uint64 `json:"ino"`
+	Value string `json:"val"`
+}
+
+type SearchXAttrResponse struct {
+	Items     []*XAttrSearchItem `json:"items"`
+	Truncated bool               `json:"truncated"`
+}
+
+// TrashItem is a deleted dentry kept in trash of the partition of its parent.
+type TrashItem struct {
+	Key        string `json:"key"`
+	ParentID   uint64 `json:"pino"`
+	Name       string `json:"name"`
+	Inode      uint64 `json:"ino"`
+	Type       uint32 `json:"type"`
+	DeleteTime int64  `json:"deleteTime"`
+}
+
+type ListTrashRequest struct {
+	VolName     string `json:"vol"`
+	PartitionID uint64 `json:"pid"`
+	ParentID    uint64 `json:"pino"` // 0 lists all the items of the partition
+	Marker      string `json:"marker"`
+	Limit       int    `json:"limit"`
+}
+
+type ListTrashResponse struct {
+	Items      []*TrashItem `json:"items"`
+	NextMarker string       `json:"nextMarker"`
+}
+
+type RestoreTrashRequest struct {
+	VolName     string `json:"vol"`
+	PartitionID uint64 `json:"pid"`
+	Key         string `json:"key"`
+	NewName     string `json:"newName"` // restore with the original name if empty
+}
+
+// InodeTransitionRequest replaces the extents of the inode with the obj extents in the blobstore,
+// it fails if the size or modify time of the inode is not the same as the request.
+type InodeTransitionRequest struct {
+	VolName      string         `json:"vol"`
+	PartitionID  uint64         `json:"pid"`
+	Inode        uint64         `json:"ino"`
+	Size         uint64         `json:"size"`
+	Generation   uint64         `json:"gen"`
+	ObjExtents   []ObjExtentKey `json:"oeks"`
+	StorageClass string         `json:"sc"`
+}
+
 type AppendMultipartResponse struct {
 	Status   uint8  `json:"status"`
 	Update   bool   `json:"update"`
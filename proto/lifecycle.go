@@ -28,10 +28,12 @@ type LcConfiguration struct {
 }
 
 type Rule struct {
-	Expire *ExpirationConfig
-	Filter *FilterConfig
-	ID     string
-	Status string
+	Expire                         *ExpirationConfig
+	Transitions                    []*TransitionConfig                   `json:",omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUploadConfig `json:",omitempty"`
+	Filter                         *FilterConfig
+	ID                             string
+	Status                         string
 }
 
 type ExpirationConfig struct {
@@ -39,6 +41,18 @@ type ExpirationConfig struct {
 	Days int
 }
 
+type TransitionConfig struct {
+	Date         *time.Time
+	Days         int
+	StorageClass string
+}
+
+// AbortIncompleteMultipartUploadConfig aborts the multipart uploads which are not completed
+// within the days after they are initiated, and removes their parts.
+type AbortIncompleteMultipartUploadConfig struct {
+	DaysAfterInitiation int
+}
+
 type FilterConfig struct {
 	Prefix string
 }
@@ -48,6 +62,16 @@ const (
 	RuleDisabled string = "Disabled"
 )
 
+// storage classes of the objects, the data of StorageClassStandardIA is stored in the blobstore
+// instead of the data nodes.
+const (
+	StorageClassStandard   = "STANDARD"
+	StorageClassStandardIA = "STANDARD_IA"
+
+	// XAttrKeyStorageClass is the xattr recording the storage class of the transitioned inodes
+	XAttrKeyStorageClass = "oss:storage-class"
+)
+
 func (lcConf *LcConfiguration) GenEnabledRuleTasks() []*RuleTask {
 	tasks := make([]*RuleTask, 0)
 	for _, r := range lcConf.Rules {
@@ -101,6 +125,8 @@ type LcNodeRuleTaskStatistics struct {
 	FileScannedNum       int64
 	DirScannedNum        int64
 	ExpiredNum           int64
+	TransitionedNum      int64
+	AbortedMultipartNum  int64
 	ErrorSkippedNum      int64
 }
 
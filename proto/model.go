@@ -45,6 +45,7 @@ type MetaNodeInfo struct {
 	NodeSetID                 uint64
 	PersistenceMetaPartitions []uint64
 	RdOnly                    bool
+	MaintenanceExpireTime     int64   `json:"maintenanceExpireTime"`
 	CpuUtil                   float64 `json:"cpuUtil"`
 }
 
@@ -68,6 +69,7 @@ type DataNodeInfo struct {
 	PersistenceDataPartitions []uint64
 	BadDisks                  []string
 	RdOnly                    bool
+	MaintenanceExpireTime     int64              `json:"maintenanceExpireTime"`
 	MaxDpCntLimit             uint32             `json:"maxDpCntLimit"`
 	CpuUtil                   float64            `json:"cpuUtil"`
 	IoUtils                   map[string]float64 `json:"ioUtil"`
@@ -94,6 +96,7 @@ type MetaPartitionInfo struct {
 	MissNodes     map[string]int64
 	LoadResponse  []*MetaPartitionLoadResponse
 	Forbidden     bool
+	Qps           uint64
 }
 
 // MetaReplica defines the replica of a meta partition
@@ -107,6 +110,7 @@ type MetaReplicaInfo struct {
 	InodeCount  uint64
 	MaxInode    uint64
 	DentryCount uint64
+	Qps         uint64
 }
 
 // ClusterView provides the view of a cluster.
@@ -182,6 +186,89 @@ type ClusterStatInfo struct {
 	ZoneStatInfo     map[string]*ZoneStat
 }
 
+// ClusterStatSnapshot is a snapshot of cluster stats which master takes and persists periodically
+type ClusterStatSnapshot struct {
+	Time                      int64
+	DataTotalGB               uint64
+	DataUsedGB                uint64
+	MetaTotalGB               uint64
+	MetaUsedGB                uint64
+	DataNodeCount             int
+	InactiveDataNodeCount     int
+	MetaNodeCount             int
+	InactiveMetaNodeCount     int
+	VolCount                  int
+	DataPartitionCount        int
+	UnavailableDataPartitions int
+	BadDataPartitions         int
+	MetaPartitionCount        int
+	UnavailableMetaPartitions int
+	BadMetaPartitions         int
+}
+
+// ClusterStatHistory snapshots of cluster stats in a time range, in ascending order of time
+type ClusterStatHistory struct {
+	IntervalSec   int64
+	RetentionHour int64
+	Snapshots     []*ClusterStatSnapshot
+}
+
+// ReplicaRepairTask is a data partition replica to be repaired by the repair planner of master
+type ReplicaRepairTask struct {
+	PartitionID  uint64
+	VolName      string
+	Addr         string // the corrupt replica, empty for a missing replica
+	ZoneName     string
+	Reason       string
+	Status       string
+	CreateTime   int64
+	ScheduleTime int64
+	ErrMsg       string
+}
+
+type ReplicaRepairBacklog struct {
+	Enable       bool
+	LimitPerZone uint64 // repairs scheduled in each zone per round
+	Tasks        []*ReplicaRepairTask
+}
+
+// NodeSetRebalanceMove is a partition replica migrated by the rebalance of nodesets
+type NodeSetRebalanceMove struct {
+	PartitionID   uint64
+	PartitionType string // data or meta
+	VolName       string
+	ZoneName      string
+	NodeSetID     uint64
+	SrcAddr       string
+	DstAddr       string
+	Status        string
+	ScheduleTime  int64
+}
+
+// NodeSetRebalanceNodeSet is the rebalance progress of a type of partitions in a nodeset, replicas are balanced
+// among nodes of the nodeset proportional to their total space.
+type NodeSetRebalanceNodeSet struct {
+	ZoneName      string
+	NodeSetID     uint64
+	PartitionType string // data or meta
+	NodeCount     int
+	MinCount      int // min replicas on a node
+	MaxCount      int // max replicas on a node
+	PendingMoves  int // moves still needed to balance the nodeset
+	RunningMoves  int
+	DoneMoves     uint64
+	FailedMoves   uint64
+	Progress      string
+}
+
+type NodeSetRebalanceStatus struct {
+	Enable    bool
+	Rate      uint64 // max migrating replicas of each type in each nodeset
+	StartTime int64
+	NodeSets  []*NodeSetRebalanceNodeSet
+	Moves     []*NodeSetRebalanceMove // migrating replicas
+}
+
 type ZoneStat struct {
 	DataNodeStat *ZoneNodesStat
 	MetaNodeStat *ZoneNodesStat
@@ -306,6 +393,8 @@ type DataReplica struct {
 	NeedsToCompare             bool
 	DiskPath                   string
 	DecommissionRepairProgress float64
+	LastScrubTime              int64
+	ScrubErrors                []*ExtentScrubError
 }
 
 // data partition diagnosis represents the inactive data nodes, corrupt data partitions, and data partitions lack of replicas
@@ -319,6 +408,7 @@ type DataPartitionDiagnosis struct {
 	// BadDataPartitionIDs         []BadPartitionView
 	BadDataPartitionInfos      []BadPartitionRepairView
 	BadReplicaDataPartitionIDs []uint64
+	ScrubErrorDataPartitionIDs []uint64
 }
 
 // meta partition diagnosis represents the inactive meta nodes, corrupt meta partitions, and meta partitions lack of replicas
@@ -341,6 +431,43 @@ type DecommissionProgress struct {
 	StatusMessage string
 }
 
+// DecommissionDpDetail is the progress of migrating a data partition in a decommission
+type DecommissionDpDetail struct {
+	PartitionID    uint64
+	Status         string
+	SrcAddr        string
+	DstAddr        string
+	RepairProgress float64 // repair progress of the new replica, in [0, 1]
+	Retry          int
+	ErrorMessage   string
+}
+
+// DecommissionDetail is the progress of a decommission of a data node or a disk with its data partitions
+type DecommissionDetail struct {
+	DecommissionProgress
+	StartTime             int64
+	EstimatedCompleteTime int64 // 0 if it cannot be estimated
+	Partitions            []*DecommissionDpDetail
+}
+
+// DryRunPartition is a partition affected by a planned change, DstAddr is empty if the change does not move it
+type DryRunPartition struct {
+	PartitionID   uint64
+	PartitionType string // "data" or "meta"
+	VolName       string
+	SrcAddr       string
+	DstAddr       string
+	ErrorMessage  string // why no target can be chosen for the partition
+}
+
+// DryRunPlan is the plan computed by a cluster-changing admin API called with dryrun=true, nothing of it is applied
+type DryRunPlan struct {
+	Operation  string
+	Target     string
+	Changes    map[string]string // changed settings, the value is formatted as "old -> new"
+	Partitions []*DryRunPartition
+}
+
 type DiskInfo struct {
 	NodeId  uint64
 	Address string
@@ -354,6 +481,9 @@ type DiskInfo struct {
 
 	TotalPartitionCnt    int
 	DiskErrPartitionList []uint64
+
+	Health         *DiskHealth `json:",omitempty"`
+	FailureReasons []string    `json:",omitempty"` // why the disk is predicted to fail
 }
 
 type DiskInfos struct {
@@ -26,6 +26,8 @@ const (
 	AttrValid
 	ReadRate
 	WriteRate
+	ReadBandwidth
+	WriteBandwidth
 	EnSyncWrite
 	AutoInvalData
 	Rdonly
@@ -67,6 +69,9 @@ const (
 	BuffersTotalLimit
 	MaxStreamerLimit
 	EnableAudit
+	ReportAccessTime
+	ReadCacheDir
+	ReadCacheSize
 
 	LocallyProf
 	MinWriteAbleDataPartitionCnt
@@ -118,6 +123,8 @@ func InitMountOptions(opts []MountOption) {
 	opts[AttrValid] = MountOption{"attrValid", "Attr Valid Duration", "", int64(-1)}
 	opts[ReadRate] = MountOption{"readRate", "Read Rate Limit", "", int64(-1)}
 	opts[WriteRate] = MountOption{"writeRate", "Write Rate Limit", "", int64(-1)}
+	opts[ReadBandwidth] = MountOption{"readBandwidth", "Read Bandwidth Limit in MB/s", "", int64(-1)}
+	opts[WriteBandwidth] = MountOption{"writeBandwidth", "Write Bandwidth Limit in MB/s", "", int64(-1)}
 	opts[EnSyncWrite] = MountOption{"enSyncWrite", "Enable Sync Write", "", int64(-1)}
 	opts[AutoInvalData] = MountOption{"autoInvalData", "Auto Invalidate Data", "", int64(-1)}
 	opts[Rdonly] = MountOption{"rdonly", "Mount as readonly", "", false}
@@ -160,6 +167,9 @@ func InitMountOptions(opts []MountOption) {
 	opts[BcacheBatchCnt] = MountOption{"bcacheBatchCnt", "The block cache get meta count", "", int64(100000)}
 	opts[BcacheCheckIntervalS] = MountOption{"bcacheCheckIntervalS", "The block cache check interval", "", int64(300)}
 	opts[EnableAudit] = MountOption{"enableAudit", "enable client audit logging", "", false}
+	opts[ReportAccessTime] = MountOption{"reportAtime", "report access time of files to meta nodes", "", false}
+	opts[ReadCacheDir] = MountOption{"readCacheDir", "Local read cache dir", "", ""}
+	opts[ReadCacheSize] = MountOption{"readCacheSize", "Local read cache size in bytes", "", int64(0)} // default 10G
 	opts[RequestTimeout] = MountOption{"requestTimeout", "The Request Expiration Time", "", int64(0)}
 	opts[MinWriteAbleDataPartitionCnt] = MountOption{
 		"minWriteAbleDataPartitionCnt",
@@ -281,6 +291,8 @@ type MountOptions struct {
 	AttrValid                    int64
 	ReadRate                     int64
 	WriteRate                    int64
+	ReadBandwidth                int64
+	WriteBandwidth               int64
 	EnSyncWrite                  int64
 	AutoInvalData                int64
 	UmpDatadir                   string
@@ -325,6 +337,9 @@ type MountOptions struct {
 	BuffersTotalLimit            int64
 	MaxStreamerLimit             int64
 	EnableAudit                  bool
+	ReportAccessTime             bool
+	ReadCacheDir                 string
+	ReadCacheSize                int64
 	RequestTimeout               int64
 	MinWriteAbleDataPartitionCnt int
 	FileSystemName               string
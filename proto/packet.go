@@ -104,6 +104,8 @@ const (
 	OpMetaBatchGetXAttr      uint8 = 0x39
 	OpMetaExtentAddWithCheck uint8 = 0x3A // Append extent key with discard extents check
 	OpMetaReadDirLimit       uint8 = 0x3D
+	OpMetaGetChangeEvents    uint8 = 0x3E
+	OpMetaSearchXAttr        uint8 = 0x3F
 
 	// Operations: Master -> MetaNode
 	OpCreateMetaPartition           uint8 = 0x40
@@ -187,9 +189,10 @@ const (
 	OpVersionOp             uint8 = 0xB8
 
 	// Commons
-	OpNoSpaceErr uint8 = 0xEE
-	OpForbidErr  uint8 = 0xEF
-	OpDirQuota   uint8 = 0xF1
+	OpNoSpaceErr   uint8 = 0xEE
+	OpForbidErr    uint8 = 0xEF
+	OpDirQuota     uint8 = 0xF1
+	OpThrottledErr uint8 = 0xDB
 
 	// Commons
 
@@ -221,8 +224,12 @@ const (
 	OpMetaBatchObjExtentsAdd uint8 = 0xD0
 	OpMetaClearInodeCache    uint8 = 0xD1
 
-	OpMetaBatchSetXAttr uint8 = 0xD2
-	OpMetaGetAllXAttr   uint8 = 0xD3
+	OpMetaBatchSetXAttr     uint8 = 0xD2
+	OpMetaGetAllXAttr       uint8 = 0xD3
+	OpMetaBatchRecordAccess uint8 = 0xD4
+	OpMetaListTrash         uint8 = 0xD8
+	OpMetaRestoreTrash      uint8 = 0xD9
+	OpMetaInodeTransition   uint8 = 0xDA
 
 	// transaction error
 
@@ -594,6 +601,18 @@ func (p *Packet) GetOpMsg() (m string) {
 		m = "OpMetaBatchDeleteInodeQuota"
 	case OpMetaGetInodeQuota:
 		m = "OpMetaGetInodeQuota"
+	case OpMetaGetChangeEvents:
+		m = "OpMetaGetChangeEvents"
+	case OpMetaSearchXAttr:
+		m = "OpMetaSearchXAttr"
+	case OpMetaBatchRecordAccess:
+		m = "OpMetaBatchRecordAccess"
+	case OpMetaListTrash:
+		m = "OpMetaListTrash"
+	case OpMetaRestoreTrash:
+		m = "OpMetaRestoreTrash"
+	case OpMetaInodeTransition:
+		m = "OpMetaInodeTransition"
 	case OpStopDataPartitionRepair:
 		m = "OpStopDataPartitionRepair"
 	case OpLcNodeHeartbeat:
@@ -655,6 +674,8 @@ func (p *Packet) GetResultMsg() (m string) {
 		m = "OpDirQuota"
 	case OpNoSpaceErr:
 		m = "NoSpaceErr"
+	case OpThrottledErr:
+		m = "ThrottledErr: " + string(p.Data)
 	case OpTxInodeInfoNotExistErr:
 		m = "OpTxInodeInfoNotExistErr"
 	case OpTxConflictErr:
@@ -174,6 +174,10 @@ const (
 	OSSPutObjectLockConfigurationAction Action = OSSActionPrefix + "PutObjectLockConfiguration"
 	OSSGetObjectLockConfigurationAction Action = OSSActionPrefix + "GetObjectLockConfiguration"
 
+	// Bucket notification actions
+	OSSGetBucketNotificationConfigurationAction Action = OSSActionPrefix + "GetBucketNotificationConfiguration"
+	OSSPutBucketNotificationConfigurationAction Action = OSSActionPrefix + "PutBucketNotificationConfiguration"
+
 	NoneAction Action = ""
 )
 
@@ -258,6 +262,9 @@ var AllActions = []Action{
 
 	OSSPutObjectLockConfigurationAction,
 	OSSGetObjectLockConfigurationAction,
+
+	OSSGetBucketNotificationConfigurationAction,
+	OSSPutBucketNotificationConfigurationAction,
 }
 
 func ParseAction(str string) Action {
@@ -0,0 +1,87 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// admin actions granted to users by roles
+const (
+	AdminActionAll          = "*"
+	AdminActionCreateVolume = "createVolume"
+	AdminActionDeleteVolume = "deleteVolume"
+	AdminActionUpdateVolume = "updateVolume"
+	AdminActionDecommission = "decommission"
+	AdminActionUpdateConfig = "updateConfig"
+	AdminActionManageUser   = "manageUser"
+	AdminActionManageRole   = "manageRole"
+)
+
+var AdminActions = []string{
+	AdminActionAll, AdminActionCreateVolume, AdminActionDeleteVolume, AdminActionUpdateVolume,
+	AdminActionDecommission, AdminActionUpdateConfig, AdminActionManageUser, AdminActionManageRole,
+}
+
+func IsValidAdminAction(action string) bool {
+	for _, a := range AdminActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// headers of the signature of the request checked against roles of the user by master, the request is
+// signed by the secret key of the user so that no password is sent.
+const (
+	RBACAccessKeyHeader = "Cfs-Access-Key"
+	RBACDateHeader      = "Cfs-Date"
+	RBACSignatureHeader = "Cfs-Signature"
+
+	// requests signed earlier or later than it are rejected
+	RBACSignatureExpiration = 15 * time.Minute
+)
+
+// RBACSignature signs the method, path, raw query, unix time in seconds and body of the request by the
+// secret key of the user.
+func RBACSignature(secretKey, method, path, rawQuery, date string, body []byte) string {
+	digest := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(strings.Join([]string{method, path, rawQuery, date, hex.EncodeToString(digest[:])}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RoleInfo grants admin actions to users
+type RoleInfo struct {
+	Name        string   `json:"name"`
+	Actions     []string `json:"actions"`
+	Users       []string `json:"users"`
+	CreateTime  string   `json:"create_time"`
+	Description string   `json:"description"`
+}
+
+func (r *RoleInfo) HasAction(action string) bool {
+	for _, a := range r.Actions {
+		if a == action || a == AdminActionAll {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proto
+
+// TenantInfo groups volumes and users, a zero limit means no limit
+type TenantInfo struct {
+	Name           string   `json:"name"`
+	CapacityLimit  uint64   `json:"capacity_limit"` // total capacity of volumes in GB
+	VolCountLimit  int      `json:"vol_count_limit"`
+	UserCountLimit int      `json:"user_count_limit"`
+	Vols           []string `json:"vols"`
+	Users          []string `json:"users"`
+	CreateTime     string   `json:"create_time"`
+	Description    string   `json:"description"`
+}
+
+// TenantView is the tenant with the capacity allocated to its volumes
+type TenantView struct {
+	TenantInfo
+	UsedCapacity uint64 `json:"used_capacity"` // GB
+}
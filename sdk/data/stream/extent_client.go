@@ -18,6 +18,7 @@ import (
 	"container/list"
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -42,6 +43,7 @@ type (
 	AppendExtentKeyFunc func(parentInode, inode uint64, key proto.ExtentKey, discard []proto.ExtentKey) (int, error)
 	GetExtentsFunc      func(inode uint64) (uint64, uint64, []proto.ExtentKey, error)
 	TruncateFunc        func(inode, size uint64, fullPath string) error
+	SetModifyTimeFunc   func(inode uint64, mtime int64) error
 	EvictIcacheFunc     func(inode uint64)
 	LoadBcacheFunc      func(key string, buf []byte, offset uint64, size uint32) (int, error)
 	CacheBcacheFunc     func(key string, buf []byte) error
@@ -106,14 +108,19 @@ type ExtentConfig struct {
 	Preload           bool
 	ReadRate          int64
 	WriteRate         int64
+	ReadBandwidth     int64 // MB/s
+	WriteBandwidth    int64 // MB/s
 	BcacheEnable      bool
 	BcacheDir         string
+	ReadCacheDir      string
+	ReadCacheSize     int64
 	MaxStreamerLimit  int64
 	VerReadSeq        uint64
 	OnAppendExtentKey AppendExtentKeyFunc
 	OnSplitExtentKey  SplitExtentKeyFunc
 	OnGetExtents      GetExtentsFunc
 	OnTruncate        TruncateFunc
+	OnSetModifyTime   SetModifyTimeFunc // May be null, the generation is not increased by overwrites then
 	OnEvictIcache     EvictIcacheFunc
 	OnLoadBcache      LoadBcacheFunc
 	OnCacheBcache     CacheBcacheFunc
@@ -138,6 +145,8 @@ type ExtentClient struct {
 	maxStreamerLimit   int
 	readLimiter        *rate.Limiter
 	writeLimiter       *rate.Limiter
+	readBwLimiter      *rate.Limiter // bytes per second
+	writeBwLimiter     *rate.Limiter // bytes per second
 	disableMetaCache   bool
 	volumeType         int
 	volumeName         string
@@ -151,10 +160,12 @@ type ExtentClient struct {
 	splitExtentKey     SplitExtentKeyFunc
 	getExtents         GetExtentsFunc
 	truncate           TruncateFunc
+	setModifyTime      SetModifyTimeFunc
 	evictIcache        EvictIcacheFunc // May be null, must check before using
 	loadBcache         LoadBcacheFunc
 	cacheBcache        CacheBcacheFunc
 	evictBcache        EvictBacheFunc
+	readCache          *ReadCache // local read cache on disk, nil if disabled
 	inflightL1cache    sync.Map
 	inflightL1BigBlock int32
 	multiVerMgr        *MultiVerMgr
@@ -260,6 +271,7 @@ retry:
 	client.splitExtentKey = config.OnSplitExtentKey
 	client.getExtents = config.OnGetExtents
 	client.truncate = config.OnTruncate
+	client.setModifyTime = config.OnSetModifyTime
 	client.evictIcache = config.OnEvictIcache
 	client.dataWrapper.InitFollowerRead(config.FollowerRead)
 	client.dataWrapper.SetNearRead(config.NearRead)
@@ -274,6 +286,11 @@ retry:
 	client.BcacheHealth = true
 	client.preload = config.Preload
 	client.disableMetaCache = config.DisableMetaCache
+	if config.ReadCacheDir != "" {
+		if client.readCache, err = NewReadCache(filepath.Join(config.ReadCacheDir, config.Volume), config.ReadCacheSize); err != nil {
+			return nil, errors.Trace(err, "Init read cache failed!")
+		}
+	}
 
 	var readLimit, writeLimit rate.Limit
 	if config.ReadRate <= 0 {
@@ -288,6 +305,10 @@ retry:
 	}
 	client.readLimiter = rate.NewLimiter(readLimit, defaultReadLimitBurst)
 	client.writeLimiter = rate.NewLimiter(writeLimit, defaultWriteLimitBurst)
+	client.readBwLimiter = rate.NewLimiter(rate.Inf, 0)
+	client.writeBwLimiter = rate.NewLimiter(rate.Inf, 0)
+	setBandwidth(client.readBwLimiter, int(config.ReadBandwidth))
+	setBandwidth(client.writeBwLimiter, int(config.WriteBandwidth))
 
 	if config.MaxStreamerLimit <= 0 {
 		client.disableMetaCache = true
@@ -564,6 +585,11 @@ func (client *ExtentClient) Flush(inode uint64) error {
 }
 
 func (client *ExtentClient) Read(inode uint64, data []byte, offset int, size int) (read int, err error) {
+	return client.ReadContext(context.Background(), inode, data, offset, size)
+}
+
+// ReadContext is Read which stops once ctx is done, ctx.Err() is returned in that case.
+func (client *ExtentClient) ReadContext(ctx context.Context, inode uint64, data []byte, offset int, size int) (read int, err error) {
 	// log.LogErrorf("======> ExtentClient Read Enter, inode(%v), len(data)=(%v), offset(%v), size(%v).", inode, len(data), offset, size)
 	// t1 := time.Now()
 	if size == 0 {
@@ -585,7 +611,11 @@ func (client *ExtentClient) Read(inode uint64, data []byte, offset int, size int
 		return
 	}
 
-	read, err = s.read(data, offset, size)
+	if client.readCache != nil {
+		read, err = s.readCached(ctx, data, offset, size)
+	} else {
+		read, err = s.read(ctx, data, offset, size)
+	}
 	// log.LogErrorf("======> ExtentClient Read Exit, inode(%v), time[%v us].", inode, time.Since(t1).Microseconds())
 	return
 }
@@ -652,6 +682,7 @@ func (client *ExtentClient) ReadExtent(inode uint64, ek *proto.ExtentKey, data [
 		req = NewExtentRequest(int(ek.FileOffset)+offset, size, data, ek)
 		ctx := context.Background()
 		s.client.readLimiter.Wait(ctx)
+		waitBandwidth(ctx, s.client.readBwLimiter, size)
 		s.client.LimitManager.ReadAlloc(ctx, size)
 		isStream = true
 
@@ -681,7 +712,9 @@ func (client *ExtentClient) GetStreamer(inode uint64) *Streamer {
 }
 
 func (client *ExtentClient) GetRate() string {
-	return fmt.Sprintf("read: %v\nwrite: %v\n", getRate(client.readLimiter), getRate(client.writeLimiter))
+	return fmt.Sprintf("read: %v\nwrite: %v\nreadBandwidth: %v\nwriteBandwidth: %v\n",
+		getRate(client.readLimiter), getRate(client.writeLimiter),
+		getBandwidth(client.readBwLimiter), getBandwidth(client.writeBwLimiter))
 }
 
 func (client *ExtentClient) shouldBcache() bool {
@@ -713,6 +746,48 @@ func setRate(lim *rate.Limiter, val int) string {
 	return "unlimited"
 }
 
+func getBandwidth(lim *rate.Limiter) string {
+	val := int(lim.Limit()) / util.MB
+	if lim.Limit() != rate.Inf && val > 0 {
+		return fmt.Sprintf("%vMB/s", val)
+	}
+	return "unlimited"
+}
+
+func (client *ExtentClient) SetReadBandwidth(val int) string {
+	return setBandwidth(client.readBwLimiter, val)
+}
+
+func (client *ExtentClient) SetWriteBandwidth(val int) string {
+	return setBandwidth(client.writeBwLimiter, val)
+}
+
+// setBandwidth sets the limit of the bandwidth in MB/s, the burst is the bytes of one second.
+func setBandwidth(lim *rate.Limiter, val int) string {
+	if val > 0 {
+		lim.SetBurst(val * util.MB)
+		lim.SetLimit(rate.Limit(val * util.MB))
+		return fmt.Sprintf("%vMB/s", val)
+	}
+	lim.SetLimit(rate.Inf)
+	return "unlimited"
+}
+
+// waitBandwidth waits for the bytes in the pieces of the burst, as the request may be larger
+// than the burst of a low bandwidth limit.
+func waitBandwidth(ctx context.Context, lim *rate.Limiter, n int) {
+	for n > 0 {
+		piece := n
+		if burst := lim.Burst(); lim.Limit() != rate.Inf && piece > burst {
+			piece = burst
+		}
+		if err := lim.WaitN(ctx, piece); err != nil {
+			return
+		}
+		n -= piece
+	}
+}
+
 func (client *ExtentClient) Close() error {
 	// release streamers
 	var inodes []uint64
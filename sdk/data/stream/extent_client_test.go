@@ -0,0 +1,46 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/util"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestBandwidthLimit(t *testing.T) {
+	lim := rate.NewLimiter(rate.Inf, 0)
+	require.Equal(t, "unlimited", getBandwidth(lim))
+	// no limit by default
+	start := time.Now()
+	waitBandwidth(context.Background(), lim, 100*util.MB)
+	require.Less(t, time.Since(start), time.Second)
+
+	require.Equal(t, "1MB/s", setBandwidth(lim, 1))
+	require.Equal(t, "1MB/s", getBandwidth(lim))
+	require.Equal(t, util.MB, lim.Burst())
+
+	// the request larger than the burst is waited in pieces
+	start = time.Now()
+	waitBandwidth(context.Background(), lim, util.MB+util.MB/2)
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	require.Equal(t, "unlimited", setBandwidth(lim, -1))
+	require.Equal(t, "unlimited", getBandwidth(lim))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cubefs/cubefs/sdk/meta"
 	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/errors"
+	"github.com/cubefs/cubefs/util/exporter"
 	"github.com/cubefs/cubefs/util/log"
 	"github.com/cubefs/cubefs/util/stat"
 )
@@ -528,6 +529,9 @@ func (eh *ExtentHandler) recoverPacket(packet *Packet) error {
 		return errors.New(fmt.Sprintf("recoverPacket failed: reach max error limit, eh(%v) packet(%v)", eh, packet))
 	}
 
+	retryMetric := exporter.NewCounter("dataWriteRecover")
+	retryMetric.AddWithLabels(1, map[string]string{exporter.Vol: eh.stream.client.volumeName})
+
 	handler := eh.recoverHandler
 	if handler == nil {
 		// Always use normal extent store mode for recovery.
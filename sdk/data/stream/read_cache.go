@@ -0,0 +1,376 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stream
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cubefs/cubefs/util"
+	"github.com/cubefs/cubefs/util/exporter"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	readCacheBlockSize    = util.MB
+	readCacheDirNum       = 256
+	readCacheTmpSuffix    = ".tmp"
+	defaultReadCacheSize  = 10 * util.GB
+	readCacheFileNameSize = 3
+)
+
+type readCacheBlock struct {
+	ino   uint64
+	gen   uint64
+	block uint64
+	size  int64
+}
+
+type readCacheInode struct {
+	gen    uint64
+	blocks map[uint64]*list.Element
+}
+
+// ReadCache caches the data of the files in the blocks on the local disk. The blocks of an inode
+// are cached with the generation of the extents as the consistency token, the generation is
+// increased by the meta node on each modification of the extents, so that the blocks of the
+// older generation are invalidated once the newer extents are loaded from the meta node.
+type ReadCache struct {
+	dir      string
+	capacity int64
+
+	sync.Mutex
+	used     int64
+	lru      *list.List
+	inodes   map[uint64]*readCacheInode
+	evictSeq uint64 // increased on each eviction by the local modification
+}
+
+// NewReadCache returns the read cache in the directory, the blocks cached by the previous
+// mount are loaded and validated by the generation on reading.
+func NewReadCache(dir string, capacity int64) (c *ReadCache, err error) {
+	if capacity <= 0 {
+		capacity = defaultReadCacheSize
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	c = &ReadCache{
+		dir:      dir,
+		capacity: capacity,
+		lru:      list.New(),
+		inodes:   make(map[uint64]*readCacheInode),
+	}
+	if err = c.load(); err != nil {
+		return nil, err
+	}
+	log.LogInfof("NewReadCache: dir(%v) capacity(%v) used(%v) blocks(%v)", dir, capacity, c.used, c.lru.Len())
+	return
+}
+
+func (c *ReadCache) blockPath(ino, gen, block uint64) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%02x", ino%readCacheDirNum), fmt.Sprintf("%d_%d_%d", ino, gen, block))
+}
+
+func parseReadCacheFileName(name string) (ino, gen, block uint64, err error) {
+	parts := strings.Split(name, "_")
+	if len(parts) != readCacheFileNameSize {
+		err = fmt.Errorf("invalid read cache file name %v", name)
+		return
+	}
+	if ino, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return
+	}
+	if gen, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+		return
+	}
+	block, err = strconv.ParseUint(parts[2], 10, 64)
+	return
+}
+
+// load rebuilds the index of the cached blocks, only the blocks of the newest generation
+// of each inode are kept.
+func (c *ReadCache) load() error {
+	var obsolete []string
+	err := filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		ino, gen, block, err := parseReadCacheFileName(info.Name())
+		// the temporary files of the interrupted writing are removed too
+		if err != nil {
+			obsolete = append(obsolete, path)
+			return nil
+		}
+		ci := c.inodes[ino]
+		if ci != nil && ci.gen > gen {
+			obsolete = append(obsolete, path)
+			return nil
+		}
+		if ci != nil && ci.gen < gen {
+			obsolete = append(obsolete, c.removeInode(ino)...)
+		}
+		c.insert(&readCacheBlock{ino: ino, gen: gen, block: block, size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	obsolete = append(obsolete, c.shrink()...)
+	removeReadCacheFiles(obsolete)
+	return nil
+}
+
+// insert adds the block to the index, the caller must hold the lock.
+func (c *ReadCache) insert(b *readCacheBlock) {
+	ci := c.inodes[b.ino]
+	if ci == nil {
+		ci = &readCacheInode{gen: b.gen, blocks: make(map[uint64]*list.Element)}
+		c.inodes[b.ino] = ci
+	}
+	if e, ok := ci.blocks[b.block]; ok {
+		c.lru.Remove(e)
+		c.used -= e.Value.(*readCacheBlock).size
+	}
+	ci.blocks[b.block] = c.lru.PushFront(b)
+	c.used += b.size
+}
+
+// removeInode removes the blocks of the inode from the index and returns the paths of the
+// blocks, the caller must hold the lock.
+func (c *ReadCache) removeInode(ino uint64) (paths []string) {
+	ci := c.inodes[ino]
+	if ci == nil {
+		return
+	}
+	for block, e := range ci.blocks {
+		c.lru.Remove(e)
+		c.used -= e.Value.(*readCacheBlock).size
+		paths = append(paths, c.blockPath(ino, ci.gen, block))
+	}
+	delete(c.inodes, ino)
+	return
+}
+
+// shrink removes the least recently used blocks from the index until the used size is within
+// the capacity, and returns the paths of the blocks, the caller must hold the lock.
+func (c *ReadCache) shrink() (paths []string) {
+	for c.used > c.capacity {
+		e := c.lru.Back()
+		if e == nil {
+			break
+		}
+		b := e.Value.(*readCacheBlock)
+		c.lru.Remove(e)
+		c.used -= b.size
+		if ci := c.inodes[b.ino]; ci != nil {
+			delete(ci.blocks, b.block)
+			if len(ci.blocks) == 0 {
+				delete(c.inodes, b.ino)
+			}
+		}
+		paths = append(paths, c.blockPath(b.ino, b.gen, b.block))
+	}
+	return
+}
+
+func removeReadCacheFiles(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.LogWarnf("ReadCache: remove block file(%v) err(%v)", path, err)
+		}
+	}
+}
+
+// Get reads the data of the block of the generation from the offset in the block, it returns
+// false if the block is not cached or shorter than the data.
+func (c *ReadCache) Get(ino, gen, block uint64, data []byte, offset int) bool {
+	c.Lock()
+	ci := c.inodes[ino]
+	if ci == nil || ci.gen != gen {
+		var obsolete []string
+		// the blocks of the older generation are invalid
+		if ci != nil && ci.gen < gen {
+			obsolete = c.removeInode(ino)
+		}
+		c.Unlock()
+		removeReadCacheFiles(obsolete)
+		return false
+	}
+	e, ok := ci.blocks[block]
+	if !ok || e.Value.(*readCacheBlock).size < int64(offset+len(data)) {
+		c.Unlock()
+		return false
+	}
+	c.lru.MoveToFront(e)
+	c.Unlock()
+
+	path := c.blockPath(ino, gen, block)
+	f, err := os.Open(path)
+	if err != nil {
+		log.LogWarnf("ReadCache Get: open block file(%v) err(%v)", path, err)
+		c.remove(ino, gen, block)
+		return false
+	}
+	defer f.Close()
+	if _, err = f.ReadAt(data, int64(offset)); err != nil {
+		log.LogWarnf("ReadCache Get: read block file(%v) offset(%v) size(%v) err(%v)", path, offset, len(data), err)
+		c.remove(ino, gen, block)
+		return false
+	}
+	return true
+}
+
+// Put caches the data of the block of the generation. The block is dropped if the inode is
+// evicted since the evictSeq is taken before reading the data, or a newer generation of the
+// inode is cached.
+func (c *ReadCache) Put(ino, gen, block uint64, data []byte, evictSeq uint64) {
+	if int64(len(data)) > c.capacity {
+		return
+	}
+	c.Lock()
+	if c.evictSeq != evictSeq {
+		c.Unlock()
+		return
+	}
+	if ci := c.inodes[ino]; ci != nil && ci.gen > gen {
+		c.Unlock()
+		return
+	}
+	c.Unlock()
+
+	path := c.blockPath(ino, gen, block)
+	if err := writeReadCacheFile(path, data); err != nil {
+		log.LogWarnf("ReadCache Put: write block file(%v) size(%v) err(%v)", path, len(data), err)
+		return
+	}
+
+	var obsolete []string
+	c.Lock()
+	ci := c.inodes[ino]
+	if c.evictSeq != evictSeq || (ci != nil && ci.gen > gen) {
+		c.Unlock()
+		removeReadCacheFiles([]string{path})
+		return
+	}
+	if ci != nil && ci.gen < gen {
+		obsolete = c.removeInode(ino)
+	}
+	c.insert(&readCacheBlock{ino: ino, gen: gen, block: block, size: int64(len(data))})
+	obsolete = append(obsolete, c.shrink()...)
+	c.Unlock()
+	removeReadCacheFiles(obsolete)
+}
+
+func writeReadCacheFile(path string, data []byte) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp := path + readCacheTmpSuffix
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		os.Remove(tmp)
+		return
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+	return
+}
+
+func (c *ReadCache) remove(ino, gen, block uint64) {
+	c.Lock()
+	ci := c.inodes[ino]
+	if ci == nil || ci.gen != gen {
+		c.Unlock()
+		return
+	}
+	if e, ok := ci.blocks[block]; ok {
+		c.lru.Remove(e)
+		c.used -= e.Value.(*readCacheBlock).size
+		delete(ci.blocks, block)
+		if len(ci.blocks) == 0 {
+			delete(c.inodes, ino)
+		}
+	}
+	c.Unlock()
+	removeReadCacheFiles([]string{c.blockPath(ino, gen, block)})
+}
+
+// Evict removes the blocks of the inode which is modified by the local client, as the
+// generation is not increased until the extents are reloaded from the meta node.
+func (c *ReadCache) Evict(ino uint64) {
+	c.Lock()
+	c.evictSeq++
+	obsolete := c.removeInode(ino)
+	c.Unlock()
+	removeReadCacheFiles(obsolete)
+}
+
+// EvictSeq returns the sequence of the evictions, which is taken before reading the data to
+// be cached.
+func (c *ReadCache) EvictSeq() uint64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.evictSeq
+}
+
+// readCached reads the data through the local read cache in blocks, the missed block is read
+// from the data nodes and cached with the generation of the extents.
+func (s *Streamer) readCached(ctx context.Context, data []byte, offset int, size int) (total int, err error) {
+	filesize, gen := s.extents.Size()
+	// the data beyond the file size and the files never synced from the meta node are not cached
+	if offset+size > filesize || gen == 0 {
+		return s.read(ctx, data, offset, size)
+	}
+	cache := s.client.readCache
+	for total < size {
+		pos := offset + total
+		block := uint64(pos / readCacheBlockSize)
+		blockOffset := int(block) * readCacheBlockSize
+		n := util.Min(size-total, blockOffset+readCacheBlockSize-pos)
+		if cache.Get(s.inode, gen, block, data[total:total+n], pos-blockOffset) {
+			hitMetric := exporter.NewCounter("fileReadLocalCacheHit")
+			hitMetric.AddWithLabels(1, map[string]string{exporter.Vol: s.client.volumeName})
+			total += n
+			continue
+		}
+		missMetric := exporter.NewCounter("fileReadLocalCacheMiss")
+		missMetric.AddWithLabels(1, map[string]string{exporter.Vol: s.client.volumeName})
+
+		blockSize := util.Min(readCacheBlockSize, filesize-blockOffset)
+		evictSeq := cache.EvictSeq()
+		buf := make([]byte, blockSize)
+		var read int
+		read, err = s.read(ctx, buf, blockOffset, blockSize)
+		if (err != nil && err != io.EOF) || read < blockSize {
+			log.LogWarnf("readCached: ino(%v) block(%v) read(%v) blockSize(%v) err(%v), read without cache",
+				s.inode, block, read, blockSize, err)
+			read, err = s.read(ctx, data[total:size], pos, size-total)
+			total += read
+			return
+		}
+		cache.Put(s.inode, gen, block, buf, evictSeq)
+		copy(data[total:total+n], buf[pos-blockOffset:])
+		total += n
+	}
+	return total, nil
+}
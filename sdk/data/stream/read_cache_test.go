@@ -0,0 +1,88 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stream
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewReadCache(dir, 10)
+	require.NoError(t, err)
+
+	data := make([]byte, 4)
+	require.False(t, c.Get(1, 1, 0, data, 0))
+
+	c.Put(1, 1, 0, []byte("abcd"), c.EvictSeq())
+	require.True(t, c.Get(1, 1, 0, data[:2], 1))
+	require.Equal(t, []byte("bc"), data[:2])
+	// beyond the cached data
+	require.False(t, c.Get(1, 1, 0, data, 1))
+
+	// the blocks of the older generation are invalidated by the newer generation
+	require.False(t, c.Get(1, 2, 0, data, 0))
+	require.False(t, c.Get(1, 1, 0, data, 0))
+	_, err = os.Stat(c.blockPath(1, 1, 0))
+	require.True(t, os.IsNotExist(err))
+
+	c.Put(1, 2, 0, []byte("efgh"), c.EvictSeq())
+	// the older generation read before the invalidation is not cached
+	c.Put(1, 1, 1, []byte("ijkl"), c.EvictSeq())
+	require.False(t, c.Get(1, 1, 1, data, 0))
+
+	// the block read before the local modification is not cached
+	seq := c.EvictSeq()
+	c.Evict(1)
+	require.False(t, c.Get(1, 2, 0, data, 0))
+	c.Put(1, 2, 0, []byte("efgh"), seq)
+	require.False(t, c.Get(1, 2, 0, data, 0))
+
+	// the least recently used blocks are removed beyond the capacity
+	c.Put(2, 1, 0, []byte("1234"), c.EvictSeq())
+	c.Put(2, 1, 1, []byte("5678"), c.EvictSeq())
+	require.True(t, c.Get(2, 1, 0, data, 0))
+	c.Put(3, 1, 0, []byte("9012"), c.EvictSeq())
+	require.True(t, c.Get(2, 1, 0, data, 0))
+	require.False(t, c.Get(2, 1, 1, data, 0))
+	require.Equal(t, int64(8), c.used)
+
+	// the cached blocks are loaded on restart
+	c, err = NewReadCache(dir, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), c.used)
+	require.True(t, c.Get(3, 1, 0, data, 0))
+	require.True(t, bytes.Equal([]byte("9012"), data))
+}
+
+func TestStreamerUpdateModifyTime(t *testing.T) {
+	var updated []uint64
+	s := &Streamer{inode: 1, client: &ExtentClient{setModifyTime: func(ino uint64, mtime int64) error {
+		updated = append(updated, ino)
+		return nil
+	}}}
+	require.NoError(t, s.updateModifyTime())
+	require.Empty(t, updated)
+
+	// the overwrites increase the generation on the meta node once
+	s.overwritten = true
+	require.NoError(t, s.updateModifyTime())
+	require.NoError(t, s.updateModifyTime())
+	require.Equal(t, []uint64{1}, updated)
+}
@@ -15,6 +15,7 @@
 package stream
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/cubefs/cubefs/sdk/data/wrapper"
 	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/errors"
+	"github.com/cubefs/cubefs/util/exporter"
 	"github.com/cubefs/cubefs/util/log"
 )
 
@@ -105,22 +107,40 @@ func (sc *StreamConn) String() string {
 // Send send the given packet over the network through the stream connection until success
 // or the maximum number of retries is reached.
 func (sc *StreamConn) Send(retry *bool, req *Packet, getReply GetReplyFunc) (err error) {
+	return sc.SendContext(context.Background(), retry, req, getReply)
+}
+
+// SendContext is Send which stops retrying and interrupts the request in flight once ctx is done,
+// ctx.Err() is returned in that case.
+func (sc *StreamConn) SendContext(ctx context.Context, retry *bool, req *Packet, getReply GetReplyFunc) (err error) {
 	for i := 0; i < StreamSendMaxRetry; i++ {
-		err = sc.sendToDataPartition(req, retry, getReply)
+		err = sc.sendToDataPartition(ctx, req, retry, getReply)
+		if ctx.Err() != nil {
+			log.LogWarnf("StreamConn Send: sc(%v) reqPacket(%v) cancelled, err(%v)", sc, req, err)
+			return ctx.Err()
+		}
 		if err == nil || err == proto.ErrCodeVersionOp || !*retry || err == TryOtherAddrError || strings.Contains(err.Error(), "OpForbidErr") {
 			return
 		}
 		log.LogWarnf("StreamConn Send: err(%v)", err)
-		time.Sleep(StreamSendSleepInterval)
+		if sc.dp.ClientWrapper != nil {
+			retryMetric := exporter.NewCounter("dataSendRetry")
+			retryMetric.AddWithLabels(1, map[string]string{exporter.Vol: sc.dp.ClientWrapper.VolName()})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(StreamSendSleepInterval):
+		}
 	}
 	return errors.New(fmt.Sprintf("StreamConn Send: retried %v times and still failed, sc(%v) reqPacket(%v)", StreamSendMaxRetry, sc, req))
 }
 
-func (sc *StreamConn) sendToDataPartition(req *Packet, retry *bool, getReply GetReplyFunc) (err error) {
+func (sc *StreamConn) sendToDataPartition(ctx context.Context, req *Packet, retry *bool, getReply GetReplyFunc) (err error) {
 	conn, err := StreamConnPool.GetConnect(sc.currAddr)
 	if err == nil {
 		log.LogDebugf("req opcode %v, conn %v", req.Opcode, conn)
-		err = sc.sendToConn(conn, req, getReply)
+		err = sc.sendToConn(ctx, conn, req, getReply)
 		if err == nil {
 			StreamConnPool.PutConnect(conn, false)
 			return
@@ -134,9 +154,17 @@ func (sc *StreamConn) sendToDataPartition(req *Packet, retry *bool, getReply Get
 		log.LogWarnf("sendToDataPartition: get connection to curr addr failed, addr(%v) reqPacket(%v) err(%v)", sc.currAddr, req, err)
 	}
 
+	// leader of the partition may be changed, refresh the view while trying other hosts
+	if req.Opcode != proto.OpStreamFollowerRead && sc.dp.ClientWrapper != nil {
+		sc.dp.ClientWrapper.RefreshDataPartition(sc.dp.PartitionID)
+	}
+
 	hosts := sortByStatus(sc.dp, true)
 
 	for _, addr := range hosts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		log.LogWarnf("sendToDataPartition: try addr(%v) reqPacket(%v)", addr, req)
 		conn, err = StreamConnPool.GetConnect(addr)
 		if err != nil {
@@ -144,9 +172,10 @@ func (sc *StreamConn) sendToDataPartition(req *Packet, retry *bool, getReply Get
 			continue
 		}
 		sc.currAddr = addr
-		sc.dp.LeaderAddr = addr
-		err = sc.sendToConn(conn, req, getReply)
+		err = sc.sendToConn(ctx, conn, req, getReply)
 		if err == nil {
+			// only the host which serves the request successfully is taken as new leader
+			sc.dp.LeaderAddr = addr
 			StreamConnPool.PutConnect(conn, false)
 			return
 		}
@@ -159,7 +188,13 @@ func (sc *StreamConn) sendToDataPartition(req *Packet, retry *bool, getReply Get
 	return errors.New(fmt.Sprintf("sendToPatition Failed: sc(%v) reqPacket(%v)", sc, req))
 }
 
-func (sc *StreamConn) sendToConn(conn *net.TCPConn, req *Packet, getReply GetReplyFunc) (err error) {
+func (sc *StreamConn) sendToConn(ctx context.Context, conn *net.TCPConn, req *Packet, getReply GetReplyFunc) (err error) {
+	stop := util.CloseOnDone(ctx, conn)
+	defer func() {
+		if stop() {
+			err = ctx.Err()
+		}
+	}()
 	for i := 0; i < StreamSendMaxRetry; i++ {
 		log.LogDebugf("sendToConn: send to addr(%v), reqPacket(%v)", sc.currAddr, req)
 		err = req.WriteToConn(conn)
@@ -179,7 +214,11 @@ func (sc *StreamConn) sendToConn(conn *net.TCPConn, req *Packet, getReply GetRep
 		}
 
 		log.LogWarnf("sendToConn: getReply error and will RETRY, sc(%v) err(%v)", sc, err)
-		time.Sleep(StreamSendSleepInterval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(StreamSendSleepInterval):
+		}
 	}
 
 	log.LogDebugf("sendToConn exit: send to addr(%v) reqPacket(%v) err(%v)", sc.currAddr, req, err)
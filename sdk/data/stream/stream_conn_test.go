@@ -0,0 +1,85 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/data/wrapper"
+	"github.com/stretchr/testify/require"
+)
+
+// newDiscardServer returns address of a server which discards all requests
+func newDiscardServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestStreamConnNotLeader(t *testing.T) {
+	proto.InitBufferPool(32768)
+	leader, follower, other := newDiscardServer(t), newDiscardServer(t), newDiscardServer(t)
+	dp := &wrapper.DataPartition{
+		DataPartitionResponse: proto.DataPartitionResponse{
+			PartitionID: 1,
+			Hosts:       []string{follower, other, leader},
+			LeaderAddr:  follower,
+		},
+		ClientWrapper: &wrapper.Wrapper{HostsStatus: map[string]bool{leader: true, follower: true, other: true}},
+	}
+	req := new(Packet)
+	req.Opcode = proto.OpStreamRead
+	retry := true
+	errMock := errors.New("fake error")
+
+	// the host replying try other addr is not taken as leader
+	sc := NewStreamConn(dp, false)
+	err := sc.sendToDataPartition(context.Background(), req, &retry, func(conn *net.TCPConn) (error, bool) {
+		if conn.RemoteAddr().String() == leader {
+			return nil, false
+		}
+		return TryOtherAddrError, false
+	})
+	require.NoError(t, err)
+	require.Equal(t, leader, dp.LeaderAddr)
+	require.Equal(t, leader, sc.currAddr)
+
+	// the host failed with other error is not taken as leader
+	dp.LeaderAddr = follower
+	sc = NewStreamConn(dp, false)
+	err = sc.sendToDataPartition(context.Background(), req, &retry, func(conn *net.TCPConn) (error, bool) {
+		if conn.RemoteAddr().String() == follower {
+			return TryOtherAddrError, false
+		}
+		return errMock, false
+	})
+	require.ErrorIs(t, err, errMock)
+	require.Equal(t, follower, dp.LeaderAddr)
+}
@@ -54,6 +54,7 @@ type Streamer struct {
 	pendingCache         chan bcacheKey
 	verSeq               uint64
 	needUpdateVer        int32
+	overwritten          bool // the extents are overwritten in place since the last flush
 }
 
 type bcacheKey struct {
@@ -124,7 +125,7 @@ func (s *Streamer) GetExtentReader(ek *proto.ExtentKey) (*ExtentReader, error) {
 	return reader, nil
 }
 
-func (s *Streamer) read(data []byte, offset int, size int) (total int, err error) {
+func (s *Streamer) read(ctx context.Context, data []byte, offset int, size int) (total int, err error) {
 	var (
 		readBytes       int
 		reader          *ExtentReader
@@ -132,8 +133,8 @@ func (s *Streamer) read(data []byte, offset int, size int) (total int, err error
 		revisedRequests []*ExtentRequest
 	)
 	log.LogDebugf("action[streamer.read] offset %v size %v", offset, size)
-	ctx := context.Background()
 	s.client.readLimiter.Wait(ctx)
+	waitBandwidth(ctx, s.client.readBwLimiter, size)
 	s.client.LimitManager.ReadAlloc(ctx, size)
 	requests = s.extents.PrepareReadRequests(offset, size, data)
 	for _, req := range requests {
@@ -159,6 +160,10 @@ func (s *Streamer) read(data []byte, offset int, size int) (total int, err error
 	filesize, _ := s.extents.Size()
 	log.LogDebugf("read: ino(%v) requests(%v) filesize(%v)", s.inode, requests, filesize)
 	for _, req := range requests {
+		if err = ctx.Err(); err != nil {
+			log.LogWarnf("Stream read: ino(%v) req(%v) total(%v) cancelled", s.inode, req, total)
+			break
+		}
 		log.LogDebugf("action[streamer.read] req %v", req)
 		if req.ExtentKey == nil {
 			zeros := make([]byte, len(req.Data))
@@ -229,7 +234,7 @@ func (s *Streamer) read(data []byte, offset int, size int) (total int, err error
 				}
 			}
 
-			readBytes, err = reader.Read(req)
+			readBytes, err = reader.ReadContext(ctx, req)
 			log.LogDebugf("TRACE Stream read: ino(%v) req(%v) readBytes(%v) err(%v)", s.inode, req, readBytes, err)
 
 			total += readBytes
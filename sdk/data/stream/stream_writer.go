@@ -286,7 +286,9 @@ func (s *Streamer) handleRequest(request interface{}) {
 		request.err = s.truncate(request.size, request.fullPath)
 		request.done <- struct{}{}
 	case *FlushRequest:
-		request.err = s.flush()
+		if request.err = s.flush(); request.err == nil {
+			request.err = s.updateModifyTime()
+		}
 		request.done <- struct{}{}
 	case *ReleaseRequest:
 		request.err = s.release()
@@ -310,6 +312,10 @@ func (s *Streamer) write(data []byte, offset, size, flags int, checkFunc func()
 	if flags&proto.FlagsSyncWrite != 0 {
 		direct = true
 	}
+	// the generation of the extents is not increased until they are reloaded from the meta node
+	if s.client.readCache != nil {
+		s.client.readCache.Evict(s.inode)
+	}
 begin:
 	if flags&proto.FlagsAppend != 0 {
 		filesize, _ := s.extents.Size()
@@ -320,6 +326,7 @@ begin:
 
 	ctx := context.Background()
 	s.client.writeLimiter.Wait(ctx)
+	waitBandwidth(ctx, s.client.writeBwLimiter, size)
 	s.client.LimitManager.WriteAlloc(ctx, size)
 
 	requests := s.extents.PrepareWriteRequests(offset, size, data)
@@ -621,6 +628,8 @@ func (s *Streamer) doOverwrite(req *ExtentRequest, direct bool) (total int, err
 
 	sc := NewStreamConn(dp, false)
 
+	// the modify time is updated on flush even if the overwrite fails, as it may be partially done
+	s.overwritten = true
 	for total < size {
 		reqPacket := NewOverwritePacket(dp, req.ExtentKey.ExtentId, offset-ekFileOffset+total+ekExtOffset, s.inode, offset)
 		reqPacket.VerSeq = s.client.multiVerMgr.latestVerSeq
@@ -979,11 +988,28 @@ func (s *Streamer) release() error {
 	err := s.flush()
 	if err != nil {
 		s.abort()
+	} else {
+		err = s.updateModifyTime()
 	}
 	log.LogDebugf("release: streamer(%v) refcnt(%v)", s, s.refcnt)
 	return err
 }
 
+// updateModifyTime sets the modify time of the inode on the meta node once the extents are overwritten in
+// place, which does not reach the meta node otherwise. The generation of the extents is increased with it,
+// so that the data cached by the read cache of the other clients is invalidated.
+func (s *Streamer) updateModifyTime() (err error) {
+	if !s.overwritten || s.client.setModifyTime == nil {
+		return
+	}
+	if err = s.client.setModifyTime(s.inode, time.Now().Unix()); err != nil {
+		log.LogErrorf("updateModifyTime: ino(%v) err(%v)", s.inode, err)
+		return
+	}
+	s.overwritten = false
+	return
+}
+
 func (s *Streamer) evict() error {
 	s.client.streamerLock.Lock()
 	if s.refcnt > 0 || len(s.request) != 0 {
@@ -1021,6 +1047,9 @@ func (s *Streamer) truncate(size int, fullPath string) error {
 	if err != nil {
 		return err
 	}
+	if s.client.readCache != nil {
+		s.client.readCache.Evict(s.inode)
+	}
 
 	oldsize, _ := s.extents.Size()
 	if oldsize <= size {
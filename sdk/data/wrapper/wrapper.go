@@ -37,6 +37,9 @@ var (
 	DefaultMinWritableDataPartitionCnt = 10
 )
 
+// MinRefreshDataPartitionInterval minimum interval in seconds to refresh one data partition from master.
+const MinRefreshDataPartitionInterval = 5
+
 type DataPartitionView struct {
 	DataPartitions []*DataPartition
 }
@@ -82,6 +85,8 @@ type Wrapper struct {
 	verConfReadSeq              uint64
 	verReadSeq                  uint64
 	SimpleClient                SimpleClientInfo
+
+	refreshTime sync.Map // partition id => unix time of last refresh
 }
 
 func (w *Wrapper) GetMasterClient() *masterSDK.MasterClient {
@@ -166,6 +171,10 @@ func (w *Wrapper) FollowerRead() bool {
 	return w.followerRead
 }
 
+func (w *Wrapper) VolName() string {
+	return w.volName
+}
+
 func (w *Wrapper) tryGetPartition(index uint64) (partition *DataPartition, ok bool) {
 	w.Lock.RLock()
 	defer w.Lock.RUnlock()
@@ -451,6 +460,59 @@ func (w *Wrapper) getDataPartitionFromMaster(isInit bool, dpId uint64) (err erro
 	return w.updateDataPartitionByRsp(isInit, DataPartitions)
 }
 
+// RefreshDataPartition refreshes hosts and leader of the cached data partition from master asynchronously,
+// it's called when the leader of partition is changed and the cached view may be stale.
+func (w *Wrapper) RefreshDataPartition(dpId uint64) {
+	// not connected to master
+	if w.mc == nil {
+		return
+	}
+	now := time.Now().Unix()
+	if last, ok := w.refreshTime.Load(dpId); ok && now-last.(int64) < MinRefreshDataPartitionInterval {
+		return
+	}
+	w.refreshTime.Store(dpId, now)
+
+	go func() {
+		dpInfo, err := w.mc.AdminAPI().GetDataPartition(w.volName, dpId)
+		if err != nil {
+			log.LogWarnf("RefreshDataPartition: get data partition fail: volume(%v) dpId(%v) err(%v)", w.volName, dpId, err)
+			return
+		}
+		w.refreshDataPartition(dpInfo)
+	}()
+}
+
+// refreshDataPartition replaces the cached data partition with a new one of the hosts and leader in dpInfo,
+// the old one is not modified as it may be in use by others.
+func (w *Wrapper) refreshDataPartition(dpInfo *proto.DataPartitionInfo) {
+	var leaderAddr string
+	for _, replica := range dpInfo.Replicas {
+		if replica.IsLeader {
+			leaderAddr = replica.Addr
+		}
+	}
+	hosts := make([]string, len(dpInfo.Hosts))
+	copy(hosts, dpInfo.Hosts)
+
+	w.Lock.Lock()
+	old, ok := w.partitions[dpInfo.PartitionID]
+	if ok {
+		dp := *old
+		dp.Hosts = hosts
+		if w.followerRead && w.nearRead {
+			dp.NearHosts = w.sortHostsByDistance(hosts)
+		}
+		if leaderAddr != "" {
+			dp.LeaderAddr = leaderAddr
+		}
+		w.partitions[dp.PartitionID] = &dp
+	}
+	w.Lock.Unlock()
+	log.LogInfof("RefreshDataPartition: volume(%v) dpId(%v) hosts(%v) leader(%v) cached(%v)",
+		w.volName, dpInfo.PartitionID, hosts, leaderAddr, ok)
+}
+
 func (w *Wrapper) clearPartitions() {
 	w.Lock.Lock()
 	defer w.Lock.Unlock()
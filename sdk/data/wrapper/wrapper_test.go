@@ -0,0 +1,60 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshDataPartition(t *testing.T) {
+	old := &DataPartition{
+		DataPartitionResponse: proto.DataPartitionResponse{
+			PartitionID: 1,
+			Hosts:       []string{"a", "b", "c"},
+			LeaderAddr:  "a",
+		},
+		Metrics: NewDataPartitionMetrics(),
+	}
+	w := &Wrapper{partitions: map[uint64]*DataPartition{1: old}}
+
+	w.refreshDataPartition(&proto.DataPartitionInfo{
+		PartitionID: 1,
+		Hosts:       []string{"b", "c", "d"},
+		Replicas:    []*proto.DataReplica{{Addr: "b"}, {Addr: "d", IsLeader: true}},
+	})
+	dp, ok := w.tryGetPartition(1)
+	require.True(t, ok)
+	require.Equal(t, []string{"b", "c", "d"}, dp.Hosts)
+	require.Equal(t, "d", dp.LeaderAddr)
+	require.True(t, old.Metrics == dp.Metrics)
+	// the old one in use is not modified
+	require.False(t, old == dp)
+	require.Equal(t, []string{"a", "b", "c"}, old.Hosts)
+	require.Equal(t, "a", old.LeaderAddr)
+
+	// keep the leader if master does not know
+	w.refreshDataPartition(&proto.DataPartitionInfo{PartitionID: 1, Hosts: []string{"c", "d"}})
+	dp, _ = w.tryGetPartition(1)
+	require.Equal(t, []string{"c", "d"}, dp.Hosts)
+	require.Equal(t, "d", dp.LeaderAddr)
+
+	// not cached partition is ignored
+	w.refreshDataPartition(&proto.DataPartitionInfo{PartitionID: 2, Hosts: []string{"c", "d"}})
+	_, ok = w.tryGetPartition(2)
+	require.False(t, ok)
+}
@@ -0,0 +1,436 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	gopath "path"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/sdk/data/stream"
+	masterSDK "github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/sdk/meta"
+	"github.com/cubefs/cubefs/util/errors"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	DefaultRetries       = 3
+	DefaultRetryInterval = 100 * time.Millisecond
+	DefaultBlockSize     = 4 * 1024 * 1024
+)
+
+// Config is the configuration of a client.
+type Config struct {
+	Volume  string
+	Masters []string
+	// Owner is the owner of the volume, the owner is not validated if it is empty.
+	Owner string
+
+	FollowerRead bool
+	NearRead     bool
+	// ReadRate and WriteRate limit the read and write requests per second.
+	ReadRate  int64
+	WriteRate int64
+	// ReadBandwidth and WriteBandwidth limit the read and write bandwidth in MB/s.
+	ReadBandwidth  int64
+	WriteBandwidth int64
+
+	// Retries is the number of times an idempotent metadata request is retried
+	// on the transient errors, RetryInterval is the backoff between two retries
+	// which doubles after each retry.
+	Retries       int
+	RetryInterval time.Duration
+	// BlockSize is the maximum size of a single read or write request, the
+	// context is checked between two requests.
+	BlockSize int
+}
+
+func (cfg *Config) checkAndSetDefault() error {
+	if cfg.Volume == "" {
+		return errors.New("volume is empty")
+	}
+	if len(cfg.Masters) == 0 {
+		return errors.New("masters are empty")
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = DefaultRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.BlockSize <= 0 {
+		cfg.BlockSize = DefaultBlockSize
+	}
+	return nil
+}
+
+// Client accesses the files of a volume. It is safe for concurrent use.
+type Client struct {
+	cfg    *Config
+	mw     *meta.MetaWrapper
+	ec     *stream.ExtentClient
+	opened *openInodes
+}
+
+// NewClient connects to the volume.
+func NewClient(ctx context.Context, cfg *Config) (c *Client, err error) {
+	conf := *cfg
+	if err = conf.checkAndSetDefault(); err != nil {
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	mc := masterSDK.NewMasterClient(conf.Masters, false)
+	var volumeInfo *proto.SimpleVolView
+	if volumeInfo, err = mc.AdminAPI().GetVolumeSimpleInfo(conf.Volume); err != nil {
+		return nil, errors.Trace(err, "get volume info failed")
+	}
+	if !proto.IsHot(volumeInfo.VolType) {
+		return nil, fmt.Errorf("volume(%v) type(%v) is not supported", conf.Volume, volumeInfo.VolType)
+	}
+
+	var mw *meta.MetaWrapper
+	if mw, err = meta.NewMetaWrapper(&meta.MetaConfig{
+		Volume:        conf.Volume,
+		Owner:         conf.Owner,
+		Masters:       conf.Masters,
+		ValidateOwner: conf.Owner != "",
+	}); err != nil {
+		log.LogErrorf("NewClient: NewMetaWrapper failed, vol(%v) err(%v)", conf.Volume, err)
+		return nil, errors.Trace(err, "NewMetaWrapper failed")
+	}
+	var ec *stream.ExtentClient
+	if ec, err = stream.NewExtentClient(&stream.ExtentConfig{
+		Volume:            conf.Volume,
+		VolumeType:        volumeInfo.VolType,
+		Masters:           conf.Masters,
+		FollowerRead:      conf.FollowerRead,
+		NearRead:          conf.NearRead,
+		ReadRate:          conf.ReadRate,
+		WriteRate:         conf.WriteRate,
+		ReadBandwidth:     conf.ReadBandwidth,
+		WriteBandwidth:    conf.WriteBandwidth,
+		OnAppendExtentKey: mw.AppendExtentKey,
+		OnSplitExtentKey:  mw.SplitExtentKey,
+		OnGetExtents:      mw.GetExtents,
+		OnTruncate:        mw.Truncate,
+		OnSetModifyTime:   mw.SetModifyTime,
+		DisableMetaCache:  true,
+	}); err != nil {
+		log.LogErrorf("NewClient: NewExtentClient failed, vol(%v) err(%v)", conf.Volume, err)
+		mw.Close()
+		return nil, errors.Trace(err, "NewExtentClient failed")
+	}
+
+	c = &Client{cfg: &conf, mw: mw, ec: ec, opened: newOpenInodes()}
+	return c, nil
+}
+
+// Close releases the connections of the client, the opened files must be
+// closed before.
+func (c *Client) Close() error {
+	c.ec.Close()
+	return c.mw.Close()
+}
+
+// Stat returns the file info of the path.
+func (c *Client) Stat(ctx context.Context, path string) (os.FileInfo, error) {
+	path = cleanPath(path)
+	info, err := c.lookup(ctx, path)
+	if err != nil {
+		return nil, pathError("stat", path, err)
+	}
+	return newFileInfo(gopath.Base(path), info), nil
+}
+
+// ReadDir returns the file infos of the children of the directory.
+func (c *Client) ReadDir(ctx context.Context, path string) ([]os.FileInfo, error) {
+	path = cleanPath(path)
+	info, err := c.lookup(ctx, path)
+	if err != nil {
+		return nil, pathError("readdir", path, err)
+	}
+	if !proto.IsDir(info.Mode) {
+		return nil, pathError("readdir", path, syscall.ENOTDIR)
+	}
+	var dentries []proto.Dentry
+	err = c.retry(ctx, func() (e error) {
+		dentries, e = c.mw.ReadDir_ll(info.Inode)
+		return
+	})
+	if err != nil {
+		return nil, pathError("readdir", path, err)
+	}
+
+	names := make(map[uint64]string, len(dentries))
+	inodes := make([]uint64, 0, len(dentries))
+	for _, dentry := range dentries {
+		names[dentry.Inode] = dentry.Name
+		inodes = append(inodes, dentry.Inode)
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	// the children removed after the listing are skipped
+	infos := c.mw.BatchInodeGet(inodes)
+	result := make([]os.FileInfo, 0, len(infos))
+	for _, child := range infos {
+		result = append(result, newFileInfo(names[child.Inode], child))
+	}
+	return result, nil
+}
+
+// Mkdir creates the directory, the parent directory must exist.
+func (c *Client) Mkdir(ctx context.Context, path string, perm os.FileMode) error {
+	path = cleanPath(path)
+	if _, err := c.create(ctx, path, uint32(perm.Perm())|uint32(os.ModeDir)); err != nil {
+		return pathError("mkdir", path, err)
+	}
+	return nil
+}
+
+// Remove removes the file or the empty directory.
+func (c *Client) Remove(ctx context.Context, path string) error {
+	path = cleanPath(path)
+	dir, name := gopath.Split(path)
+	if name == "" {
+		return pathError("remove", path, syscall.EPERM)
+	}
+	parent, err := c.lookup(ctx, dir)
+	if err != nil {
+		return pathError("remove", path, err)
+	}
+	var mode uint32
+	err = c.retry(ctx, func() (e error) {
+		_, mode, e = c.mw.Lookup_ll(parent.Inode, name)
+		return
+	})
+	if err != nil {
+		return pathError("remove", path, err)
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	info, err := c.mw.Delete_ll(parent.Inode, name, proto.IsDir(mode), path)
+	if err != nil {
+		return pathError("remove", path, err)
+	}
+	// the removed file is evicted after it is closed by all
+	if info != nil && !proto.IsDir(mode) && c.opened.orphan(info.Inode, path) {
+		_ = c.mw.Evict(info.Inode, path)
+	}
+	return nil
+}
+
+// Rename renames the file or the directory, the destination is replaced if
+// it exists.
+func (c *Client) Rename(ctx context.Context, oldPath, newPath string) error {
+	oldPath, newPath = cleanPath(oldPath), cleanPath(newPath)
+	oldDir, oldName := gopath.Split(oldPath)
+	newDir, newName := gopath.Split(newPath)
+	if oldName == "" || newName == "" {
+		return pathError("rename", oldPath, syscall.EPERM)
+	}
+	src, err := c.lookup(ctx, oldDir)
+	if err != nil {
+		return pathError("rename", oldPath, err)
+	}
+	dst, err := c.lookup(ctx, newDir)
+	if err != nil {
+		return pathError("rename", newPath, err)
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	if err = c.mw.Rename_ll(src.Inode, oldName, dst.Inode, newName, oldPath, newPath, true); err != nil {
+		return pathError("rename", oldPath, err)
+	}
+	return nil
+}
+
+// Open opens the file for reading.
+func (c *Client) Open(ctx context.Context, path string) (*File, error) {
+	return c.OpenFile(ctx, path, os.O_RDONLY, 0)
+}
+
+// OpenFile opens the file with the flags of os.OpenFile, perm is used if the
+// file is created.
+func (c *Client) OpenFile(ctx context.Context, path string, flag int, perm os.FileMode) (*File, error) {
+	path = cleanPath(path)
+	info, err := c.lookup(ctx, path)
+	switch {
+	case err == nil:
+		if flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL {
+			return nil, pathError("open", path, syscall.EEXIST)
+		}
+	case err == syscall.ENOENT && flag&os.O_CREATE != 0:
+		if info, err = c.create(ctx, path, uint32(perm.Perm())); err != nil {
+			return nil, pathError("open", path, err)
+		}
+	default:
+		return nil, pathError("open", path, err)
+	}
+	if proto.IsDir(info.Mode) && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		return nil, pathError("open", path, syscall.EISDIR)
+	}
+
+	parent, err := c.lookup(ctx, gopath.Dir(path))
+	if err != nil {
+		return nil, pathError("open", path, err)
+	}
+	if err = c.ec.OpenStream(info.Inode); err != nil {
+		return nil, pathError("open", path, err)
+	}
+	c.opened.open(info.Inode)
+	f := &File{c: c, path: path, ino: info.Inode, pino: parent.Inode, flag: flag}
+	if flag&os.O_TRUNC != 0 && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		if err = f.Truncate(ctx, 0); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// release is called when a file is closed, the inode is evicted if it is
+// removed and closed by all.
+func (c *Client) release(ino uint64) {
+	if path, evict := c.opened.release(ino); evict {
+		_ = c.mw.Evict(ino, path)
+	}
+}
+
+func (c *Client) lookup(ctx context.Context, path string) (info *proto.InodeInfo, err error) {
+	var ino uint64
+	if err = c.retry(ctx, func() (e error) {
+		ino, e = c.mw.LookupPath(path)
+		return
+	}); err != nil {
+		return
+	}
+	err = c.retry(ctx, func() (e error) {
+		info, e = c.mw.InodeGetContext_ll(ctx, ino)
+		return
+	})
+	return
+}
+
+func (c *Client) create(ctx context.Context, path string, mode uint32) (*proto.InodeInfo, error) {
+	dir, name := gopath.Split(path)
+	if name == "" {
+		return nil, syscall.EEXIST
+	}
+	parent, err := c.lookup(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !proto.IsDir(parent.Mode) {
+		return nil, syscall.ENOTDIR
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.mw.Create_ll(parent.Inode, name, mode, 0, 0, nil, path)
+}
+
+// retry runs the idempotent op until it succeeds, fails with a permanent error
+// or the retries are exhausted.
+func (c *Client) retry(ctx context.Context, op func() error) (err error) {
+	interval := c.cfg.RetryInterval
+	for i := 0; ; i++ {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if err = op(); err == nil || !isTransient(err) || i >= c.cfg.Retries {
+			return
+		}
+		log.LogWarnf("retry: retry(%v) after %v, err(%v)", i+1, interval, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+func isTransient(err error) bool {
+	switch err {
+	case syscall.EAGAIN, syscall.EIO, syscall.ETIMEDOUT:
+		return true
+	}
+	return false
+}
+
+func cleanPath(path string) string {
+	return gopath.Clean("/" + path)
+}
+
+func pathError(op, path string, err error) error {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
+
+// openInodes counts the opened files of inodes, and keeps the removed inodes
+// which are still opened, so that they are evicted after the last close.
+type openInodes struct {
+	sync.Mutex
+	counts  map[uint64]int
+	orphans map[uint64]string
+}
+
+func newOpenInodes() *openInodes {
+	return &openInodes{counts: make(map[uint64]int), orphans: make(map[uint64]string)}
+}
+
+func (o *openInodes) open(ino uint64) {
+	o.Lock()
+	o.counts[ino]++
+	o.Unlock()
+}
+
+// release returns true if the inode is removed and closed by all.
+func (o *openInodes) release(ino uint64) (path string, evict bool) {
+	o.Lock()
+	defer o.Unlock()
+	if o.counts[ino]--; o.counts[ino] > 0 {
+		return
+	}
+	delete(o.counts, ino)
+	path, evict = o.orphans[ino]
+	delete(o.orphans, ino)
+	return
+}
+
+// orphan returns true if the removed inode is not opened and can be evicted now.
+func (o *openInodes) orphan(ino uint64, path string) bool {
+	o.Lock()
+	defer o.Unlock()
+	if o.counts[ino] > 0 {
+		o.orphans[ino] = path
+		return false
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigCheckAndSetDefault(t *testing.T) {
+	require.Error(t, (&Config{Masters: []string{"m1"}}).checkAndSetDefault())
+	require.Error(t, (&Config{Volume: "vol"}).checkAndSetDefault())
+
+	cfg := &Config{Volume: "vol", Masters: []string{"m1"}}
+	require.NoError(t, cfg.checkAndSetDefault())
+	require.Equal(t, DefaultRetries, cfg.Retries)
+	require.Equal(t, DefaultRetryInterval, cfg.RetryInterval)
+	require.Equal(t, DefaultBlockSize, cfg.BlockSize)
+}
+
+func TestCleanPath(t *testing.T) {
+	for path, expect := range map[string]string{
+		"":           "/",
+		"/":          "/",
+		"a/b":        "/a/b",
+		"/a//b/":     "/a/b",
+		"/a/../b":    "/b",
+		" a ":        "/ a ",
+		"/../../a/b": "/a/b",
+	} {
+		require.Equal(t, expect, cleanPath(path), path)
+	}
+}
+
+func TestOpenInodes(t *testing.T) {
+	o := newOpenInodes()
+	// the removed inode which is not opened is evicted now
+	require.True(t, o.orphan(1, "/a"))
+
+	// the removed inode is evicted after the last close
+	o.open(2)
+	o.open(2)
+	require.False(t, o.orphan(2, "/b"))
+	_, evict := o.release(2)
+	require.False(t, evict)
+	path, evict := o.release(2)
+	require.True(t, evict)
+	require.Equal(t, "/b", path)
+	require.Empty(t, o.counts)
+	require.Empty(t, o.orphans)
+
+	// the inode which is not removed is not evicted
+	o.open(3)
+	_, evict = o.release(3)
+	require.False(t, evict)
+}
+
+func TestClientRetry(t *testing.T) {
+	c := &Client{cfg: &Config{Retries: 2, RetryInterval: time.Millisecond}}
+
+	// the transient errors are retried
+	calls := 0
+	err := c.retry(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return syscall.EAGAIN
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+
+	// the retries are exhausted
+	calls = 0
+	err = c.retry(context.Background(), func() error {
+		calls++
+		return syscall.EIO
+	})
+	require.Equal(t, syscall.EIO, err)
+	require.Equal(t, 3, calls)
+
+	// the permanent errors are not retried
+	calls = 0
+	err = c.retry(context.Background(), func() error {
+		calls++
+		return syscall.ENOENT
+	})
+	require.Equal(t, syscall.ENOENT, err)
+	require.Equal(t, 1, calls)
+
+	// the cancelled context stops the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = c.retry(ctx, func() error {
+		calls++
+		cancel()
+		return syscall.EAGAIN
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestFileInfo(t *testing.T) {
+	now := time.Now()
+	fi := newFileInfo("dir", &proto.InodeInfo{Inode: 2, Mode: uint32(os.ModeDir | 0o755), ModifyTime: now})
+	require.Equal(t, "dir", fi.Name())
+	require.True(t, fi.IsDir())
+	require.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+	require.Equal(t, now, fi.ModTime())
+	require.Equal(t, uint64(2), fi.Sys().(*proto.InodeInfo).Inode)
+
+	fi = newFileInfo("file", &proto.InodeInfo{Inode: 3, Mode: 0o644, Size: 1024})
+	require.False(t, fi.IsDir())
+	require.True(t, fi.Mode().IsRegular())
+	require.Equal(t, int64(1024), fi.Size())
+
+	err := pathError("open", "/file", syscall.ENOENT)
+	require.True(t, os.IsNotExist(err))
+	require.Equal(t, context.Canceled, pathError("open", "/file", context.Canceled))
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fs is a native Go client of the CubeFS file system. It wraps the
+// meta and data SDKs so that applications can access a volume directly
+// without mounting it through FUSE.
+//
+//	c, err := fs.NewClient(ctx, &fs.Config{
+//		Volume:  "ltptest",
+//		Masters: []string{"192.168.0.11:17010", "192.168.0.12:17010"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	f, err := c.OpenFile(ctx, "/dir/file", os.O_CREATE|os.O_RDWR, 0o644)
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	_, err = f.WriteAt(ctx, []byte("hello"), 0)
+//
+// Every call takes a context, which is checked before each request sent to
+// the cluster, so a cancelled context stops a large read or write between
+// two blocks.
+//
+// The connections to the meta and data nodes are pooled and shared by all
+// the files of a client. The client fails over to the other masters, to the
+// new leader of a meta partition and to the other replicas of a data
+// partition. The idempotent metadata requests are also retried with a
+// backoff on the transient errors, see Config.Retries.
+//
+// Only the volumes of the replica type are supported.
+package fs
@@ -0,0 +1,260 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"io"
+	"os"
+	gopath "path"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+// File is an opened file of the volume. The ReadAt and WriteAt methods are
+// safe for concurrent use, Read and Write share the offset of the file.
+type File struct {
+	c    *Client
+	path string
+	ino  uint64
+	pino uint64
+	flag int
+
+	sync.Mutex
+	offset int64
+	closed bool
+}
+
+// Name returns the path of the file.
+func (f *File) Name() string {
+	return f.path
+}
+
+// Stat returns the file info of the file.
+func (f *File) Stat(ctx context.Context) (os.FileInfo, error) {
+	var info *proto.InodeInfo
+	err := f.c.retry(ctx, func() (e error) {
+		info, e = f.c.mw.InodeGetContext_ll(ctx, f.ino)
+		return
+	})
+	if err != nil {
+		return nil, pathError("stat", f.path, err)
+	}
+	fi := newFileInfo(gopath.Base(f.path), info)
+	// the size of the data not flushed yet is not known by the meta node
+	if size, _, valid := f.c.ec.FileSize(f.ino); valid {
+		fi.size = int64(size)
+	}
+	return fi, nil
+}
+
+// ReadAt reads len(p) bytes from the offset off, io.EOF is returned if less
+// bytes are read.
+func (f *File) ReadAt(ctx context.Context, p []byte, off int64) (n int, err error) {
+	if err = f.checkReadable(); err != nil {
+		return
+	}
+	for n < len(p) {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		size := len(p) - n
+		if size > f.c.cfg.BlockSize {
+			size = f.c.cfg.BlockSize
+		}
+		var read int
+		read, err = f.c.ec.ReadContext(ctx, f.ino, p[n:n+size], int(off)+n, size)
+		n += read
+		if err != nil && err != io.EOF {
+			return n, pathError("read", f.path, err)
+		}
+		if read < size {
+			return n, io.EOF
+		}
+	}
+	return n, nil
+}
+
+// Read reads up to len(p) bytes from the offset of the file.
+func (f *File) Read(ctx context.Context, p []byte) (n int, err error) {
+	f.Lock()
+	defer f.Unlock()
+	n, err = f.ReadAt(ctx, p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return
+}
+
+// WriteAt writes p at the offset off.
+func (f *File) WriteAt(ctx context.Context, p []byte, off int64) (n int, err error) {
+	if err = f.checkWritable(); err != nil {
+		return
+	}
+	if f.flag&os.O_APPEND != 0 {
+		return 0, pathError("write", f.path, syscall.EINVAL)
+	}
+	return f.writeAt(ctx, p, off)
+}
+
+// Write writes p at the offset of the file, or at the end of the file if it is
+// opened with os.O_APPEND.
+func (f *File) Write(ctx context.Context, p []byte) (n int, err error) {
+	if err = f.checkWritable(); err != nil {
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+	if f.flag&os.O_APPEND != 0 {
+		size, _, _ := f.c.ec.FileSize(f.ino)
+		f.offset = int64(size)
+	}
+	n, err = f.writeAt(ctx, p, f.offset)
+	f.offset += int64(n)
+	return
+}
+
+func (f *File) writeAt(ctx context.Context, p []byte, off int64) (n int, err error) {
+	for n < len(p) {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		size := len(p) - n
+		if size > f.c.cfg.BlockSize {
+			size = f.c.cfg.BlockSize
+		}
+		var written int
+		written, err = f.c.ec.Write(f.ino, int(off)+n, p[n:n+size], 0, nil)
+		n += written
+		if err != nil {
+			return n, pathError("write", f.path, err)
+		}
+	}
+	return n, nil
+}
+
+// Seek sets the offset of the next Read or Write, whence is one of io.SeekStart,
+// io.SeekCurrent and io.SeekEnd.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	f.Lock()
+	defer f.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		size, _, _ := f.c.ec.FileSize(f.ino)
+		offset += int64(size)
+	default:
+		return 0, pathError("seek", f.path, syscall.EINVAL)
+	}
+	if offset < 0 {
+		return 0, pathError("seek", f.path, syscall.EINVAL)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Truncate changes the size of the file.
+func (f *File) Truncate(ctx context.Context, size int64) error {
+	if err := f.checkWritable(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := f.c.ec.Truncate(f.c.mw, f.pino, f.ino, int(size), f.path); err != nil {
+		return pathError("truncate", f.path, err)
+	}
+	return nil
+}
+
+// Sync flushes the written data to the data nodes and the extent keys to the
+// meta nodes.
+func (f *File) Sync(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := f.c.ec.Flush(f.ino); err != nil {
+		return pathError("sync", f.path, err)
+	}
+	return nil
+}
+
+// Close flushes the written data and closes the file.
+func (f *File) Close() error {
+	f.Lock()
+	if f.closed {
+		f.Unlock()
+		return pathError("close", f.path, os.ErrClosed)
+	}
+	f.closed = true
+	f.Unlock()
+
+	err := f.c.ec.Flush(f.ino)
+	_ = f.c.ec.CloseStream(f.ino)
+	_ = f.c.ec.EvictStream(f.ino)
+	f.c.release(f.ino)
+	if err != nil {
+		return pathError("close", f.path, err)
+	}
+	return nil
+}
+
+func (f *File) checkReadable() error {
+	if f.flag&os.O_WRONLY != 0 {
+		return pathError("read", f.path, syscall.EBADF)
+	}
+	return nil
+}
+
+func (f *File) checkWritable() error {
+	if f.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return pathError("write", f.path, syscall.EBADF)
+	}
+	return nil
+}
+
+// fileInfo implements os.FileInfo by the inode info.
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+	info    *proto.InodeInfo
+}
+
+func newFileInfo(name string, info *proto.InodeInfo) *fileInfo {
+	return &fileInfo{
+		name:    name,
+		size:    int64(info.Size),
+		mode:    proto.OsMode(info.Mode),
+		modTime: info.ModifyTime,
+		info:    info,
+	}
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.mode.IsDir() }
+
+// Sys returns the *proto.InodeInfo of the file.
+func (fi *fileInfo) Sys() interface{} { return fi.info }
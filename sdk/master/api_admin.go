@@ -15,8 +15,11 @@
 package master
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/util"
@@ -24,12 +27,22 @@ import (
 )
 
 type AdminAPI struct {
-	mc *MasterClient
-	h  map[string]string // extra headers
+	mc  *MasterClient
+	h   map[string]string // extra headers
+	ctx context.Context
 }
 
 func (api *AdminAPI) WithHeader(key, val string) *AdminAPI {
-	return &AdminAPI{mc: api.mc, h: mergeHeader(api.h, key, val)}
+	return &AdminAPI{mc: api.mc, h: mergeHeader(api.h, key, val), ctx: api.ctx}
+}
+
+// WithContext returns an api whose requests are cancelled once ctx is done.
+func (api *AdminAPI) WithContext(ctx context.Context) *AdminAPI {
+	return &AdminAPI{mc: api.mc, h: api.h, ctx: ctx}
+}
+
+func (api *AdminAPI) newRequest(method string, path string) *request {
+	return newRequest(method, path).WithContext(api.ctx)
 }
 
 func (api *AdminAPI) EncodingWith(encoding string) *AdminAPI {
@@ -42,31 +55,66 @@ func (api *AdminAPI) EncodingGzip() *AdminAPI {
 
 func (api *AdminAPI) GetCluster() (cv *proto.ClusterView, err error) {
 	cv = &proto.ClusterView{}
-	err = api.mc.requestWith(cv, newRequest(get, proto.AdminGetCluster).Header(api.h))
+	err = api.mc.requestWith(cv, api.newRequest(get, proto.AdminGetCluster).Header(api.h))
+	return
+}
+
+// GetClusterEvents returns cluster events since seq, master waits at most wait for new events.
+// Events are filtered by the minimum severity and the comma separated modules if they are not empty.
+func (api *AdminAPI) GetClusterEvents(seq uint64, wait time.Duration, severity, module string) (view *proto.ClusterEventsView, err error) {
+	view = &proto.ClusterEventsView{}
+	err = api.mc.requestWith(view, api.newRequest(get, proto.AdminGetClusterEvents).Header(api.h).NoTimeout().
+		addParamAny("seq", seq).
+		addParamAny("wait", int64(wait/time.Second)).
+		addParam("severity", severity).
+		addParam("module", module))
 	return
 }
 
 func (api *AdminAPI) GetClusterNodeInfo() (cn *proto.ClusterNodeInfo, err error) {
 	cn = &proto.ClusterNodeInfo{}
-	err = api.mc.requestWith(cn, newRequest(get, proto.AdminGetNodeInfo).Header(api.h))
+	err = api.mc.requestWith(cn, api.newRequest(get, proto.AdminGetNodeInfo).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) GetClusterIP() (cp *proto.ClusterIP, err error) {
 	cp = &proto.ClusterIP{}
-	err = api.mc.requestWith(cp, newRequest(get, proto.AdminGetIP).Header(api.h))
+	err = api.mc.requestWith(cp, api.newRequest(get, proto.AdminGetIP).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) GetClusterStat() (cs *proto.ClusterStatInfo, err error) {
 	cs = &proto.ClusterStatInfo{}
-	err = api.mc.requestWith(cs, newRequest(get, proto.AdminClusterStat).Header(api.h).NoTimeout())
+	err = api.mc.requestWith(cs, api.newRequest(get, proto.AdminClusterStat).Header(api.h).NoTimeout())
+	return
+}
+
+// GetClusterStatHistory returns snapshots of cluster stats taken in [start, end], both are unix seconds,
+// master uses its defaults for them if they are 0.
+func (api *AdminAPI) GetClusterStatHistory(start, end int64) (history *proto.ClusterStatHistory, err error) {
+	history = &proto.ClusterStatHistory{}
+	request := api.newRequest(get, proto.AdminClusterStatHistory).Header(api.h)
+	if start > 0 {
+		request.addParamAny("start", start)
+	}
+	if end > 0 {
+		request.addParamAny("end", end)
+	}
+	err = api.mc.requestWith(history, request)
 	return
 }
 
 func (api *AdminAPI) ListZones() (zoneViews []*proto.ZoneView, err error) {
 	zoneViews = make([]*proto.ZoneView, 0)
-	err = api.mc.requestWith(&zoneViews, newRequest(get, proto.GetAllZones).Header(api.h))
+	err = api.mc.requestWith(&zoneViews, api.newRequest(get, proto.GetAllZones).Header(api.h))
+	return
+}
+
+// ListZonesWithUsage list zones with node counts, capacity and writable partitions
+func (api *AdminAPI) ListZonesWithUsage() (zoneViews []*proto.ZoneView, err error) {
+	zoneViews = make([]*proto.ZoneView, 0)
+	err = api.mc.requestWith(&zoneViews, api.newRequest(get, proto.GetAllZones).Header(api.h).
+		Param(anyParam{"usage", true}).NoTimeout())
 	return
 }
 
@@ -76,19 +124,19 @@ func (api *AdminAPI) ListNodeSets(zoneName string) (nodeSetStats []*proto.NodeSe
 		params = append(params, anyParam{"zoneName", zoneName})
 	}
 	nodeSetStats = make([]*proto.NodeSetStat, 0)
-	err = api.mc.requestWith(&nodeSetStats, newRequest(get, proto.GetAllNodeSets).Header(api.h).Param(params...))
+	err = api.mc.requestWith(&nodeSetStats, api.newRequest(get, proto.GetAllNodeSets).Header(api.h).Param(params...))
 	return
 }
 
 func (api *AdminAPI) GetNodeSet(nodeSetId string) (nodeSetStatInfo *proto.NodeSetStatInfo, err error) {
 	nodeSetStatInfo = &proto.NodeSetStatInfo{}
-	err = api.mc.requestWith(nodeSetStatInfo, newRequest(get, proto.GetNodeSet).
+	err = api.mc.requestWith(nodeSetStatInfo, api.newRequest(get, proto.GetNodeSet).
 		Header(api.h).addParam("nodesetId", nodeSetId))
 	return
 }
 
 func (api *AdminAPI) UpdateNodeSet(nodeSetId string, dataNodeSelector string, metaNodeSelector string) (err error) {
-	return api.mc.request(newRequest(get, proto.UpdateNodeSet).Header(api.h).Param(
+	return api.mc.request(api.newRequest(get, proto.UpdateNodeSet).Header(api.h).Param(
 		anyParam{"nodesetId", nodeSetId},
 		anyParam{"dataNodeSelector", dataNodeSelector},
 		anyParam{"metaNodeSelector", metaNodeSelector},
@@ -96,7 +144,7 @@ func (api *AdminAPI) UpdateNodeSet(nodeSetId string, dataNodeSelector string, me
 }
 
 func (api *AdminAPI) UpdateZone(name string, enable bool, dataNodesetSelector string, metaNodesetSelector string, dataNodeSelector string, metaNodeSelector string) (err error) {
-	return api.mc.request(newRequest(post, proto.UpdateZone).Header(api.h).Param(
+	return api.mc.request(api.newRequest(post, proto.UpdateZone).Header(api.h).Param(
 		anyParam{"name", name},
 		anyParam{"enable", enable},
 		anyParam{"dataNodesetSelector", dataNodesetSelector},
@@ -106,41 +154,61 @@ func (api *AdminAPI) UpdateZone(name string, enable bool, dataNodesetSelector st
 	))
 }
 
+// SetZoneReservation reserves ratio of data and meta capacity of the zone which new partitions of volumes
+// can not use, a negative ratio is not changed.
+func (api *AdminAPI) SetZoneReservation(name string, dataRatio, metaRatio float64) (err error) {
+	request := api.newRequest(post, proto.SetZoneReservation).Header(api.h).addParam("name", name)
+	if dataRatio >= 0 {
+		request.addParamAny("dataReservedRatio", dataRatio)
+	}
+	if metaRatio >= 0 {
+		request.addParamAny("metaReservedRatio", metaRatio)
+	}
+	return api.mc.request(request)
+}
+
+// GetZoneReservation returns reservation of the zone, or all zones if name is empty
+func (api *AdminAPI) GetZoneReservation(name string) (views []*proto.ZoneReservationView, err error) {
+	views = make([]*proto.ZoneReservationView, 0)
+	err = api.mc.requestWith(&views, api.newRequest(get, proto.GetZoneReservation).Header(api.h).addParam("name", name))
+	return
+}
+
 func (api *AdminAPI) Topo() (topo *proto.TopologyView, err error) {
 	topo = &proto.TopologyView{}
-	err = api.mc.requestWith(topo, newRequest(get, proto.GetTopologyView).Header(api.h))
+	err = api.mc.requestWith(topo, api.newRequest(get, proto.GetTopologyView).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) GetDataPartition(volName string, partitionID uint64) (partition *proto.DataPartitionInfo, err error) {
 	partition = &proto.DataPartitionInfo{}
-	err = api.mc.requestWith(partition, newRequest(get, proto.AdminGetDataPartition).
+	err = api.mc.requestWith(partition, api.newRequest(get, proto.AdminGetDataPartition).
 		Header(api.h).Param(anyParam{"id", partitionID}, anyParam{"name", volName}))
 	return
 }
 
 func (api *AdminAPI) GetDataPartitionById(partitionID uint64) (partition *proto.DataPartitionInfo, err error) {
 	partition = &proto.DataPartitionInfo{}
-	err = api.mc.requestWith(partition, newRequest(get, proto.AdminGetDataPartition).
+	err = api.mc.requestWith(partition, api.newRequest(get, proto.AdminGetDataPartition).
 		Header(api.h).addParamAny("id", partitionID))
 	return
 }
 
 func (api *AdminAPI) DiagnoseDataPartition(ignoreDiscardDp bool) (diagnosis *proto.DataPartitionDiagnosis, err error) {
 	diagnosis = &proto.DataPartitionDiagnosis{}
-	err = api.mc.requestWith(diagnosis, newRequest(get, proto.AdminDiagnoseDataPartition).
+	err = api.mc.requestWith(diagnosis, api.newRequest(get, proto.AdminDiagnoseDataPartition).
 		Header(api.h).addParamAny("ignoreDiscard", ignoreDiscardDp))
 	return
 }
 
 func (api *AdminAPI) DiagnoseMetaPartition() (diagnosis *proto.MetaPartitionDiagnosis, err error) {
 	diagnosis = &proto.MetaPartitionDiagnosis{}
-	err = api.mc.requestWith(diagnosis, newRequest(get, proto.AdminDiagnoseMetaPartition).Header(api.h))
+	err = api.mc.requestWith(diagnosis, api.newRequest(get, proto.AdminDiagnoseMetaPartition).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) LoadDataPartition(volName string, partitionID uint64, clientIDKey string) (err error) {
-	return api.mc.request(newRequest(get, proto.AdminLoadDataPartition).Header(api.h).Param(
+	return api.mc.request(api.newRequest(get, proto.AdminLoadDataPartition).Header(api.h).Param(
 		anyParam{"id", partitionID},
 		anyParam{"name", volName},
 		anyParam{"clientIDKey", clientIDKey},
@@ -148,7 +216,7 @@ func (api *AdminAPI) LoadDataPartition(volName string, partitionID uint64, clien
 }
 
 func (api *AdminAPI) CreateDataPartition(volName string, count int, clientIDKey string) (err error) {
-	return api.mc.request(newRequest(get, proto.AdminCreateDataPartition).Header(api.h).Param(
+	return api.mc.request(api.newRequest(get, proto.AdminCreateDataPartition).Header(api.h).Param(
 		anyParam{"name", volName},
 		anyParam{"count", count},
 		anyParam{"clientIDKey", clientIDKey},
@@ -156,7 +224,7 @@ func (api *AdminAPI) CreateDataPartition(volName string, count int, clientIDKey
 }
 
 func (api *AdminAPI) DecommissionDataPartition(dataPartitionID uint64, nodeAddr string, raftForce bool, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminDecommissionDataPartition).Header(api.h)
+	request := api.newRequest(get, proto.AdminDecommissionDataPartition).Header(api.h)
 	request.addParam("id", strconv.FormatUint(dataPartitionID, 10))
 	request.addParam("addr", nodeAddr)
 	request.addParam("raftForceDel", strconv.FormatBool(raftForce))
@@ -165,8 +233,46 @@ func (api *AdminAPI) DecommissionDataPartition(dataPartitionID uint64, nodeAddr
 	return
 }
 
+// SetReplicaRepair enables or disables the automatic repair of bad replicas, nil arguments are not changed
+func (api *AdminAPI) SetReplicaRepair(enable *bool, limitPerZone *uint64) (err error) {
+	request := api.newRequest(post, proto.AdminSetReplicaRepair).Header(api.h)
+	if enable != nil {
+		request.addParamAny("enable", *enable)
+	}
+	if limitPerZone != nil {
+		request.addParamAny("limitPerZone", *limitPerZone)
+	}
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+func (api *AdminAPI) GetReplicaRepairBacklog() (backlog *proto.ReplicaRepairBacklog, err error) {
+	backlog = &proto.ReplicaRepairBacklog{}
+	err = api.mc.requestWith(backlog, api.newRequest(get, proto.AdminGetReplicaRepairBacklog).Header(api.h))
+	return
+}
+
+// SetNodeSetRebalance enables or disables the rebalance of nodesets, nil arguments are not changed
+func (api *AdminAPI) SetNodeSetRebalance(enable *bool, rate *uint64) (err error) {
+	request := api.newRequest(post, proto.SetNodeSetRebalance).Header(api.h)
+	if enable != nil {
+		request.addParamAny("enable", *enable)
+	}
+	if rate != nil {
+		request.addParamAny("rate", *rate)
+	}
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+func (api *AdminAPI) GetNodeSetRebalanceStatus() (status *proto.NodeSetRebalanceStatus, err error) {
+	status = &proto.NodeSetRebalanceStatus{}
+	err = api.mc.requestWith(status, api.newRequest(get, proto.GetNodeSetRebalance).Header(api.h))
+	return
+}
+
 func (api *AdminAPI) DecommissionMetaPartition(metaPartitionID uint64, nodeAddr, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminDecommissionMetaPartition).Header(api.h)
+	request := api.newRequest(get, proto.AdminDecommissionMetaPartition).Header(api.h)
 	request.addParam("id", strconv.FormatUint(metaPartitionID, 10))
 	request.addParam("addr", nodeAddr)
 	request.addParam("clientIDKey", clientIDKey)
@@ -174,8 +280,21 @@ func (api *AdminAPI) DecommissionMetaPartition(metaPartitionID uint64, nodeAddr,
 	return
 }
 
+// SplitMetaPartition splits the inode range of a meta partition of the volume after end
+// into a new meta partition, master decides the end if it is 0.
+func (api *AdminAPI) SplitMetaPartition(volName string, metaPartitionID, end uint64) (msg string, err error) {
+	request := api.newRequest(get, proto.AdminSplitMetaPartition).Header(api.h)
+	request.addParam("name", volName)
+	request.addParam("id", strconv.FormatUint(metaPartitionID, 10))
+	if end > 0 {
+		request.addParam("end", strconv.FormatUint(end, 10))
+	}
+	err = api.mc.requestWith(&msg, request)
+	return
+}
+
 func (api *AdminAPI) DeleteDataReplica(dataPartitionID uint64, nodeAddr, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminDeleteDataReplica).Header(api.h)
+	request := api.newRequest(get, proto.AdminDeleteDataReplica).Header(api.h)
 	request.addParam("id", strconv.FormatUint(dataPartitionID, 10))
 	request.addParam("addr", nodeAddr)
 	request.addParam("clientIDKey", clientIDKey)
@@ -184,7 +303,7 @@ func (api *AdminAPI) DeleteDataReplica(dataPartitionID uint64, nodeAddr, clientI
 }
 
 func (api *AdminAPI) AddDataReplica(dataPartitionID uint64, nodeAddr, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminAddDataReplica).Header(api.h)
+	request := api.newRequest(get, proto.AdminAddDataReplica).Header(api.h)
 	request.addParam("id", strconv.FormatUint(dataPartitionID, 10))
 	request.addParam("addr", nodeAddr)
 	request.addParam("clientIDKey", clientIDKey)
@@ -193,7 +312,7 @@ func (api *AdminAPI) AddDataReplica(dataPartitionID uint64, nodeAddr, clientIDKe
 }
 
 func (api *AdminAPI) DeleteMetaReplica(metaPartitionID uint64, nodeAddr string, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminDeleteMetaReplica).Header(api.h)
+	request := api.newRequest(get, proto.AdminDeleteMetaReplica).Header(api.h)
 	request.addParam("id", strconv.FormatUint(metaPartitionID, 10))
 	request.addParam("addr", nodeAddr)
 	request.addParam("clientIDKey", clientIDKey)
@@ -202,7 +321,7 @@ func (api *AdminAPI) DeleteMetaReplica(metaPartitionID uint64, nodeAddr string,
 }
 
 func (api *AdminAPI) AddMetaReplica(metaPartitionID uint64, nodeAddr string, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminAddMetaReplica).Header(api.h)
+	request := api.newRequest(get, proto.AdminAddMetaReplica).Header(api.h)
 	request.addParam("id", strconv.FormatUint(metaPartitionID, 10))
 	request.addParam("addr", nodeAddr)
 	request.addParam("clientIDKey", clientIDKey)
@@ -211,7 +330,7 @@ func (api *AdminAPI) AddMetaReplica(metaPartitionID uint64, nodeAddr string, cli
 }
 
 func (api *AdminAPI) QueryDataPartitionDecommissionStatus(partitionId uint64) (info *proto.DecommissionDataPartitionInfo, err error) {
-	request := newRequest(get, proto.AdminQueryDataPartitionDecommissionStatus).Header(api.h)
+	request := api.newRequest(get, proto.AdminQueryDataPartitionDecommissionStatus).Header(api.h)
 	request.addParam("id", strconv.FormatUint(partitionId, 10))
 	info = &proto.DecommissionDataPartitionInfo{}
 	err = api.mc.requestWith(info, request)
@@ -219,7 +338,7 @@ func (api *AdminAPI) QueryDataPartitionDecommissionStatus(partitionId uint64) (i
 }
 
 func (api *AdminAPI) DeleteVolume(volName, authKey string) (err error) {
-	request := newRequest(get, proto.AdminDeleteVol).Header(api.h)
+	request := api.newRequest(get, proto.AdminDeleteVol).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("authKey", authKey)
 	_, err = api.mc.serveRequest(request)
@@ -227,7 +346,7 @@ func (api *AdminAPI) DeleteVolume(volName, authKey string) (err error) {
 }
 
 func (api *AdminAPI) DeleteVolumeWithAuthNode(volName, authKey, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminDeleteVol).Header(api.h)
+	request := api.newRequest(get, proto.AdminDeleteVol).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("authKey", authKey)
 	request.addParam("clientIDKey", clientIDKey)
@@ -236,7 +355,7 @@ func (api *AdminAPI) DeleteVolumeWithAuthNode(volName, authKey, clientIDKey stri
 }
 
 func (api *AdminAPI) UnDeleteVolume(volName, authKey string, status bool) (err error) {
-	request := newRequest(get, proto.AdminDeleteVol)
+	request := api.newRequest(get, proto.AdminDeleteVol)
 	request.addParam("name", volName)
 	request.addParam("authKey", authKey)
 	request.addParam("delete", strconv.FormatBool(false))
@@ -254,7 +373,7 @@ func (api *AdminAPI) UpdateVolume(
 	txOpLimit int,
 	clientIDKey string,
 ) (err error) {
-	request := newRequest(get, proto.AdminUpdateVol).Header(api.h)
+	request := api.newRequest(get, proto.AdminUpdateVol).Header(api.h)
 	request.addParam("name", vv.Name)
 	request.addParam("description", vv.Description)
 	request.addParam("authKey", util.CalcAuthKey(vv.Owner))
@@ -274,6 +393,8 @@ func (api *AdminAPI) UpdateVolume(
 	request.addParam("replicaNum", strconv.FormatUint(uint64(vv.DpReplicaNum), 10))
 	request.addParam("enableQuota", strconv.FormatBool(vv.EnableQuota))
 	request.addParam("deleteLockTime", strconv.FormatInt(vv.DeleteLockTime, 10))
+	request.addParam("inlineDirThreshold", strconv.Itoa(vv.InlineDirThreshold))
+	request.addParam("clientQpsLimit", strconv.Itoa(vv.ClientQpsLimit))
 	request.addParam("clientIDKey", clientIDKey)
 	if txMask != "" {
 		request.addParam("enableTxMask", txMask)
@@ -296,12 +417,12 @@ func (api *AdminAPI) UpdateVolume(
 }
 
 func (api *AdminAPI) PutDataPartitions(volName string, dpsView []byte) (err error) {
-	return api.mc.request(newRequest(post, proto.AdminPutDataPartitions).
+	return api.mc.request(api.newRequest(post, proto.AdminPutDataPartitions).
 		Header(api.h).addParam("name", volName).Body(dpsView))
 }
 
 func (api *AdminAPI) VolShrink(volName string, capacity uint64, authKey, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminVolShrink).Header(api.h)
+	request := api.newRequest(get, proto.AdminVolShrink).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("authKey", authKey)
 	request.addParam("capacity", strconv.FormatUint(capacity, 10))
@@ -311,7 +432,7 @@ func (api *AdminAPI) VolShrink(volName string, capacity uint64, authKey, clientI
 }
 
 func (api *AdminAPI) VolExpand(volName string, capacity uint64, authKey, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminVolExpand).Header(api.h)
+	request := api.newRequest(get, proto.AdminVolExpand).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("authKey", authKey)
 	request.addParam("capacity", strconv.FormatUint(capacity, 10))
@@ -320,13 +441,22 @@ func (api *AdminAPI) VolExpand(volName string, capacity uint64, authKey, clientI
 	return
 }
 
+// VolCapacityPlan returns the planned change if the capacity of the volume is set by the expand or shrink api
+func (api *AdminAPI) VolCapacityPlan(path, volName string, capacity uint64, authKey string) (plan *proto.DryRunPlan, err error) {
+	plan = &proto.DryRunPlan{}
+	err = api.mc.requestWith(plan, api.newRequest(get, path).Header(api.h).
+		addParam("name", volName).addParam("authKey", authKey).
+		addParam("capacity", strconv.FormatUint(capacity, 10)).addParam("dryrun", "true"))
+	return
+}
+
 func (api *AdminAPI) CreateVolName(volName, owner string, capacity uint64, deleteLockTime int64, crossZone, normalZonesFirst bool, business string,
 	mpCount, dpCount, replicaNum, dpSize, volType int, followerRead bool, zoneName, cacheRuleKey string, ebsBlkSize,
 	cacheCapacity, cacheAction, cacheThreshold, cacheTTL, cacheHighWater, cacheLowWater, cacheLRUInterval int,
 	dpReadOnlyWhenVolFull bool, txMask string, txTimeout uint32, txConflictRetryNum int64, txConflictRetryInterval int64, optEnableQuota string,
 	clientIDKey string,
 ) (err error) {
-	request := newRequest(get, proto.AdminCreateVol).Header(api.h)
+	request := api.newRequest(get, proto.AdminCreateVol).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("owner", owner)
 	request.addParam("capacity", strconv.FormatUint(capacity, 10))
@@ -370,7 +500,7 @@ func (api *AdminAPI) CreateVolName(volName, owner string, capacity uint64, delet
 }
 
 func (api *AdminAPI) CreateDefaultVolume(volName, owner string) (err error) {
-	request := newRequest(get, proto.AdminCreateVol).Header(api.h)
+	request := api.newRequest(get, proto.AdminCreateVol).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("owner", owner)
 	request.addParam("capacity", "10")
@@ -380,12 +510,12 @@ func (api *AdminAPI) CreateDefaultVolume(volName, owner string) (err error) {
 
 func (api *AdminAPI) GetVolumeSimpleInfo(volName string) (vv *proto.SimpleVolView, err error) {
 	vv = &proto.SimpleVolView{}
-	err = api.mc.requestWith(vv, newRequest(get, proto.AdminGetVol).Header(api.h).addParam("name", volName))
+	err = api.mc.requestWith(vv, api.newRequest(get, proto.AdminGetVol).Header(api.h).addParam("name", volName))
 	return
 }
 
 func (api *AdminAPI) SetVolumeForbidden(volName string, forbidden bool) (err error) {
-	request := newRequest(post, proto.AdminVolForbidden).Header(api.h)
+	request := api.newRequest(post, proto.AdminVolForbidden).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("forbidden", strconv.FormatBool(forbidden))
 	_, err = api.mc.serveRequest(request)
@@ -393,15 +523,92 @@ func (api *AdminAPI) SetVolumeForbidden(volName string, forbidden bool) (err err
 }
 
 func (api *AdminAPI) SetVolumeAuditLog(volName string, enable bool) (err error) {
-	request := newRequest(post, proto.AdminVolEnableAuditLog).Header(api.h)
+	request := api.newRequest(post, proto.AdminVolEnableAuditLog).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("enable", strconv.FormatBool(enable))
 	_, err = api.mc.serveRequest(request)
 	return
 }
 
+// SetVolumeTrashInterval sets the retention of trash of the volume in minutes, 0 disables the trash.
+func (api *AdminAPI) SetVolumeTrashInterval(volName, authKey string, interval int64) (err error) {
+	request := api.newRequest(post, proto.AdminVolSetTrashInterval).Header(api.h)
+	request.addParam("name", volName)
+	request.addParam("authKey", authKey)
+	request.addParam("trashInterval", strconv.FormatInt(interval, 10))
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+// SetVolumeAutoScale sets the auto-scaling policy of the volume, which expands data partitions automatically
+func (api *AdminAPI) SetVolumeAutoScale(volName string, policy *proto.VolAutoScalePolicy) (err error) {
+	request := api.newRequest(post, proto.AdminVolSetAutoScale).Header(api.h)
+	request.addParam("name", volName)
+	request.addParam("enable", strconv.FormatBool(policy.Enable))
+	request.addParamAny("minWritableSpace", policy.MinWritableSpace)
+	request.addParamAny("minWritableDp", policy.MinWritableDp)
+	request.addParamAny("expandCount", policy.ExpandCount)
+	request.addParamAny("cooldown", policy.CooldownSec)
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+// GetVolumeAutoScale returns the auto-scaling policy of the volume and its latest automatic expansions
+func (api *AdminAPI) GetVolumeAutoScale(volName string) (view *proto.VolAutoScaleView, err error) {
+	view = &proto.VolAutoScaleView{}
+	err = api.mc.requestWith(view, api.newRequest(get, proto.AdminVolGetAutoScale).Header(api.h).addParam("name", volName))
+	return
+}
+
+// SetVolumePlacement sets placement constraints of replicas of the volume
+func (api *AdminAPI) SetVolumePlacement(volName string, placement *proto.VolPlacement) (err error) {
+	nodeSets := make([]string, 0, len(placement.ExcludeNodeSets))
+	for _, id := range placement.ExcludeNodeSets {
+		nodeSets = append(nodeSets, strconv.FormatUint(id, 10))
+	}
+	request := api.newRequest(post, proto.AdminVolSetPlacement).Header(api.h)
+	request.addParam("name", volName)
+	request.addParamAny("minZones", placement.MinZones)
+	request.addParam("excludeNodeSets", strings.Join(nodeSets, ","))
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+// GetVolumePlacement returns placement constraints of the volume and the schema of them
+func (api *AdminAPI) GetVolumePlacement(volName string) (view *proto.VolPlacementView, err error) {
+	view = &proto.VolPlacementView{}
+	err = api.mc.requestWith(view, api.newRequest(get, proto.AdminVolGetPlacement).Header(api.h).addParam("name", volName))
+	return
+}
+
+// SetVolumeQosLimit sets the read/write iops and flow limits of the volume, flow limits take MB as unit
+// and 0 means no limit. Negative limits are not sent and keep unchanged.
+func (api *AdminAPI) SetVolumeQosLimit(volName string, iopsRLimit, iopsWLimit, flowRLimitMB, flowWLimitMB int64) (err error) {
+	request := api.newRequest(post, proto.AdminVolSetQosLimit).Header(api.h)
+	request.addParam("name", volName)
+	for key, limit := range map[string]int64{
+		"iopsReadLimit":  iopsRLimit,
+		"iopsWriteLimit": iopsWLimit,
+		"flowReadLimit":  flowRLimitMB,
+		"flowWriteLimit": flowWLimitMB,
+	} {
+		if limit >= 0 {
+			request.addParamAny(key, limit)
+		}
+	}
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+// GetVolumeQosLimit returns the qos limit of the volume, flow limits take byte as unit
+func (api *AdminAPI) GetVolumeQosLimit(volName string) (limit *proto.VolQosLimit, err error) {
+	limit = &proto.VolQosLimit{}
+	err = api.mc.requestWith(limit, api.newRequest(get, proto.AdminVolGetQosLimit).Header(api.h).addParam("name", volName))
+	return
+}
+
 func (api *AdminAPI) GetMonitorPushAddr() (addr string, err error) {
-	err = api.mc.requestWith(&addr, newRequest(get, proto.AdminGetMonitorPushAddr).Header(api.h))
+	err = api.mc.requestWith(&addr, api.newRequest(get, proto.AdminGetMonitorPushAddr).Header(api.h))
 	return
 }
 
@@ -410,7 +617,7 @@ func (api *AdminAPI) UploadFlowInfo(volName string, flowInfo *proto.ClientReport
 		return nil, fmt.Errorf("flowinfo is nil")
 	}
 	vv = &proto.LimitRsp2Client{}
-	err = api.mc.requestWith(vv, newRequest(get, proto.QosUpload).Header(api.h).Body(flowInfo).
+	err = api.mc.requestWith(vv, api.newRequest(get, proto.QosUpload).Header(api.h).Body(flowInfo).
 		Param(anyParam{"name", volName}, anyParam{"qosEnable", "true"}))
 	log.LogInfof("action[UploadFlowInfo] enable %v", vv.Enable)
 	return
@@ -418,7 +625,7 @@ func (api *AdminAPI) UploadFlowInfo(volName string, flowInfo *proto.ClientReport
 
 func (api *AdminAPI) GetVolumeSimpleInfoWithFlowInfo(volName string) (vv *proto.SimpleVolView, err error) {
 	vv = &proto.SimpleVolView{}
-	err = api.mc.requestWith(vv, newRequest(get, proto.AdminGetVol).
+	err = api.mc.requestWith(vv, api.newRequest(get, proto.AdminGetVol).
 		Header(api.h).Param(anyParam{"name", volName}, anyParam{"init", "true"}))
 	return
 }
@@ -426,25 +633,25 @@ func (api *AdminAPI) GetVolumeSimpleInfoWithFlowInfo(volName string) (vv *proto.
 // access control list
 func (api *AdminAPI) CheckACL() (ci *proto.ClusterInfo, err error) {
 	ci = &proto.ClusterInfo{}
-	err = api.mc.requestWith(ci, newRequest(get, proto.AdminACL).Header(api.h))
+	err = api.mc.requestWith(ci, api.newRequest(get, proto.AdminACL).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) GetClusterInfo() (ci *proto.ClusterInfo, err error) {
 	ci = &proto.ClusterInfo{}
-	err = api.mc.requestWith(ci, newRequest(get, proto.AdminGetIP).Header(api.h))
+	err = api.mc.requestWith(ci, api.newRequest(get, proto.AdminGetIP).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) GetVerInfo(volName string) (ci *proto.VolumeVerInfo, err error) {
 	ci = &proto.VolumeVerInfo{}
-	err = api.mc.requestWith(ci, newRequest(get, proto.AdminGetVolVer).
+	err = api.mc.requestWith(ci, api.newRequest(get, proto.AdminGetVolVer).
 		Header(api.h).addParam("name", volName))
 	return
 }
 
 func (api *AdminAPI) CreateMetaPartition(volName string, count int, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminCreateMetaPartition).Header(api.h)
+	request := api.newRequest(get, proto.AdminCreateMetaPartition).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("count", strconv.Itoa(count))
 	request.addParam("clientIDKey", clientIDKey)
@@ -454,13 +661,13 @@ func (api *AdminAPI) CreateMetaPartition(volName string, count int, clientIDKey
 
 func (api *AdminAPI) ListVols(keywords string) (volsInfo []*proto.VolInfo, err error) {
 	volsInfo = make([]*proto.VolInfo, 0)
-	err = api.mc.requestWith(&volsInfo, newRequest(get, proto.AdminListVols).
+	err = api.mc.requestWith(&volsInfo, api.newRequest(get, proto.AdminListVols).
 		Header(api.h).addParam("keywords", keywords))
 	return
 }
 
 func (api *AdminAPI) IsFreezeCluster(isFreeze bool, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminClusterFreeze).Header(api.h)
+	request := api.newRequest(get, proto.AdminClusterFreeze).Header(api.h)
 	request.addParam("enable", strconv.FormatBool(isFreeze))
 	request.addParam("clientIDKey", clientIDKey)
 	_, err = api.mc.serveRequest(request)
@@ -468,14 +675,14 @@ func (api *AdminAPI) IsFreezeCluster(isFreeze bool, clientIDKey string) (err err
 }
 
 func (api *AdminAPI) SetForbidMpDecommission(disable bool) (err error) {
-	request := newRequest(get, proto.AdminClusterForbidMpDecommission).Header(api.h)
+	request := api.newRequest(get, proto.AdminClusterForbidMpDecommission).Header(api.h)
 	request.addParam("enable", strconv.FormatBool(disable))
 	_, err = api.mc.serveRequest(request)
 	return
 }
 
 func (api *AdminAPI) SetMetaNodeThreshold(threshold float64, clientIDKey string) (err error) {
-	request := newRequest(get, proto.AdminSetMetaNodeThreshold).Header(api.h)
+	request := api.newRequest(get, proto.AdminSetMetaNodeThreshold).Header(api.h)
 	request.addParam("threshold", strconv.FormatFloat(threshold, 'f', 6, 64))
 	request.addParam("clientIDKey", clientIDKey)
 	_, err = api.mc.serveRequest(request)
@@ -483,7 +690,7 @@ func (api *AdminAPI) SetMetaNodeThreshold(threshold float64, clientIDKey string)
 }
 
 func (api *AdminAPI) SetMasterVolDeletionDelayTime(volDeletionDelayTimeHour int) (err error) {
-	request := newRequest(get, proto.AdminSetMasterVolDeletionDelayTime)
+	request := api.newRequest(get, proto.AdminSetMasterVolDeletionDelayTime)
 	request.addParam("volDeletionDelayTime", strconv.FormatInt(int64(volDeletionDelayTimeHour), 10))
 	_, err = api.mc.serveRequest(request)
 	return
@@ -492,7 +699,7 @@ func (api *AdminAPI) SetMasterVolDeletionDelayTime(volDeletionDelayTimeHour int)
 func (api *AdminAPI) SetClusterParas(batchCount, markDeleteRate, deleteWorkerSleepMs, autoRepairRate, loadFactor, maxDpCntLimit, clientIDKey string,
 	dataNodesetSelector, metaNodesetSelector, dataNodeSelector, metaNodeSelector string,
 ) (err error) {
-	request := newRequest(get, proto.AdminSetNodeInfo).Header(api.h)
+	request := api.newRequest(get, proto.AdminSetNodeInfo).Header(api.h)
 	request.addParam("batchCount", batchCount)
 	request.addParam("markDeleteRate", markDeleteRate)
 	request.addParam("deleteWorkerSleepMs", deleteWorkerSleepMs)
@@ -510,18 +717,18 @@ func (api *AdminAPI) SetClusterParas(batchCount, markDeleteRate, deleteWorkerSle
 }
 
 func (api *AdminAPI) GetClusterParas() (delParas map[string]string, err error) {
-	request := newRequest(get, proto.AdminGetNodeInfo).Header(api.h)
+	request := api.newRequest(get, proto.AdminGetNodeInfo).Header(api.h)
 	if _, err = api.mc.serveRequest(request); err != nil {
 		return
 	}
 	delParas = make(map[string]string)
-	err = api.mc.requestWith(&delParas, newRequest(get, proto.AdminGetNodeInfo).Header(api.h))
+	err = api.mc.requestWith(&delParas, api.newRequest(get, proto.AdminGetNodeInfo).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) CreatePreLoadDataPartition(volName string, count int, capacity, ttl uint64, zongs string) (view *proto.DataPartitionsView, err error) {
 	view = &proto.DataPartitionsView{}
-	err = api.mc.requestWith(view, newRequest(get, proto.AdminCreatePreLoadDataPartition).Header(api.h).Param(
+	err = api.mc.requestWith(view, api.newRequest(get, proto.AdminCreatePreLoadDataPartition).Header(api.h).Param(
 		anyParam{"name", volName},
 		anyParam{"replicaNum", count},
 		anyParam{"capacity", capacity},
@@ -533,7 +740,7 @@ func (api *AdminAPI) CreatePreLoadDataPartition(volName string, count int, capac
 
 func (api *AdminAPI) ListQuota(volName string) (quotaInfo []*proto.QuotaInfo, err error) {
 	resp := &proto.ListMasterQuotaResponse{}
-	if err = api.mc.requestWith(resp, newRequest(get, proto.QuotaList).
+	if err = api.mc.requestWith(resp, api.newRequest(get, proto.QuotaList).
 		Header(api.h).addParam("name", volName)); err != nil {
 		log.LogErrorf("action[ListQuota] fail. %v", err)
 		return
@@ -544,7 +751,7 @@ func (api *AdminAPI) ListQuota(volName string) (quotaInfo []*proto.QuotaInfo, er
 }
 
 func (api *AdminAPI) CreateQuota(volName string, quotaPathInfos []proto.QuotaPathInfo, maxFiles uint64, maxBytes uint64) (quotaId uint32, err error) {
-	if err = api.mc.requestWith(&quotaId, newRequest(get, proto.QuotaCreate).
+	if err = api.mc.requestWith(&quotaId, api.newRequest(get, proto.QuotaCreate).
 		Header(api.h).Body(&quotaPathInfos).Param(
 		anyParam{"name", volName},
 		anyParam{"maxFiles", maxFiles},
@@ -557,7 +764,7 @@ func (api *AdminAPI) CreateQuota(volName string, quotaPathInfos []proto.QuotaPat
 }
 
 func (api *AdminAPI) UpdateQuota(volName string, quotaId string, maxFiles uint64, maxBytes uint64) (err error) {
-	request := newRequest(get, proto.QuotaUpdate).Header(api.h)
+	request := api.newRequest(get, proto.QuotaUpdate).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("quotaId", quotaId)
 	request.addParam("maxFiles", strconv.FormatUint(maxFiles, 10))
@@ -571,7 +778,7 @@ func (api *AdminAPI) UpdateQuota(volName string, quotaId string, maxFiles uint64
 }
 
 func (api *AdminAPI) DeleteQuota(volName string, quotaId string) (err error) {
-	request := newRequest(get, proto.QuotaDelete).Header(api.h)
+	request := api.newRequest(get, proto.QuotaDelete).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("quotaId", quotaId)
 	if _, err = api.mc.serveRequest(request); err != nil {
@@ -584,7 +791,7 @@ func (api *AdminAPI) DeleteQuota(volName string, quotaId string) (err error) {
 
 func (api *AdminAPI) GetQuota(volName string, quotaId string) (quotaInfo *proto.QuotaInfo, err error) {
 	info := &proto.QuotaInfo{}
-	if err = api.mc.requestWith(info, newRequest(get, proto.QuotaGet).Header(api.h).
+	if err = api.mc.requestWith(info, api.newRequest(get, proto.QuotaGet).Header(api.h).
 		Param(anyParam{"name", volName}, anyParam{"quotaId", quotaId})); err != nil {
 		log.LogErrorf("action[GetQuota] fail. %v", err)
 		return
@@ -596,55 +803,87 @@ func (api *AdminAPI) GetQuota(volName string, quotaId string) (quotaInfo *proto.
 
 func (api *AdminAPI) QueryBadDisks() (badDisks *proto.DiskInfos, err error) {
 	badDisks = &proto.DiskInfos{}
-	err = api.mc.requestWith(badDisks, newRequest(get, proto.QueryBadDisks).Header(api.h))
+	err = api.mc.requestWith(badDisks, api.newRequest(get, proto.QueryBadDisks).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) QueryDisks(addr string) (disks *proto.DiskInfos, err error) {
 	disks = &proto.DiskInfos{}
-	err = api.mc.requestWith(disks, newRequest(get, proto.QueryDisks).Header(api.h).
+	err = api.mc.requestWith(disks, api.newRequest(get, proto.QueryDisks).Header(api.h).
+		addParam("addr", addr))
+	return
+}
+
+func (api *AdminAPI) QueryPredictedFailureDisks(addr string) (disks *proto.DiskInfos, err error) {
+	disks = &proto.DiskInfos{}
+	err = api.mc.requestWith(disks, api.newRequest(get, proto.QueryPredictedFailureDisks).Header(api.h).
 		addParam("addr", addr))
 	return
 }
 
 func (api *AdminAPI) DiskDetail(addr string, diskPath string) (disk *proto.DiskInfo, err error) {
 	disk = &proto.DiskInfo{}
-	err = api.mc.requestWith(disk, newRequest(get, proto.QueryDiskDetail).Header(api.h).
+	err = api.mc.requestWith(disk, api.newRequest(get, proto.QueryDiskDetail).Header(api.h).
 		addParam("addr", addr).addParam("disk", diskPath))
 	return
 }
 
 func (api *AdminAPI) DecommissionDisk(addr string, disk string) (err error) {
-	return api.mc.request(newRequest(post, proto.DecommissionDisk).Header(api.h).
+	return api.mc.request(api.newRequest(post, proto.DecommissionDisk).Header(api.h).
 		addParam("addr", addr).addParam("disk", disk))
 }
 
 func (api *AdminAPI) RecommissionDisk(addr string, disk string) (err error) {
-	return api.mc.request(newRequest(post, proto.RecommissionDisk).Header(api.h).
+	return api.mc.request(api.newRequest(post, proto.RecommissionDisk).Header(api.h).
 		addParam("addr", addr).addParam("disk", disk))
 }
 
 func (api *AdminAPI) QueryDecommissionDiskProgress(addr string, disk string) (progress *proto.DecommissionProgress, err error) {
 	progress = &proto.DecommissionProgress{}
-	err = api.mc.requestWith(progress, newRequest(post, proto.QueryDiskDecoProgress).
+	err = api.mc.requestWith(progress, api.newRequest(post, proto.QueryDiskDecoProgress).
 		Header(api.h).Param(anyParam{"addr", addr}, anyParam{"disk", disk}))
 	return
 }
 
+func (api *AdminAPI) QueryDecommissionDiskDetail(addr string, disk string) (detail *proto.DecommissionDetail, err error) {
+	detail = &proto.DecommissionDetail{}
+	err = api.mc.requestWith(detail, api.newRequest(get, proto.QueryDiskDecoDetail).
+		Header(api.h).addParam("addr", addr).addParam("disk", disk))
+	return
+}
+
+// DecommissionDiskPlan returns the partitions to migrate and their targets if the disk is decommissioned
+func (api *AdminAPI) DecommissionDiskPlan(addr string, disk string) (plan *proto.DryRunPlan, err error) {
+	plan = &proto.DryRunPlan{}
+	err = api.mc.requestWith(plan, api.newRequest(post, proto.DecommissionDisk).Header(api.h).
+		addParam("addr", addr).addParam("disk", disk).addParam("dryrun", "true"))
+	return
+}
+
+func (api *AdminAPI) PauseDecommissionDisk(addr string, disk string) (err error) {
+	return api.mc.request(api.newRequest(post, proto.PauseDecommissionDisk).Header(api.h).
+		addParam("addr", addr).addParam("disk", disk))
+}
+
+func (api *AdminAPI) ResumeDecommissionDisk(addr string, disk string) (err error) {
+	return api.mc.request(api.newRequest(post, proto.ResumeDecommissionDisk).Header(api.h).
+		addParam("addr", addr).addParam("disk", disk))
+}
+
 func (api *AdminAPI) ListQuotaAll() (volsInfo []*proto.VolInfo, err error) {
 	volsInfo = make([]*proto.VolInfo, 0)
-	err = api.mc.requestWith(&volsInfo, newRequest(get, proto.QuotaListAll).Header(api.h))
+	err = api.mc.requestWith(&volsInfo, api.newRequest(get, proto.QuotaListAll).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) GetDiscardDataPartition() (discardDpInfos *proto.DiscardDataPartitionInfos, err error) {
 	discardDpInfos = &proto.DiscardDataPartitionInfos{}
-	err = api.mc.requestWith(&discardDpInfos, newRequest(get, proto.AdminGetDiscardDp).Header(api.h))
+	err = api.mc.requestWith(&discardDpInfos, api.newRequest(get, proto.AdminGetDiscardDp).Header(api.h))
 	return
 }
 
 func (api *AdminAPI) SetDataPartitionDiscard(partitionId uint64, discard bool) (err error) {
-	request := newRequest(post, proto.AdminSetDpDiscard).
+	request := api.newRequest(post, proto.AdminSetDpDiscard).
 		Header(api.h).
 		addParam("id", strconv.FormatUint(partitionId, 10)).
 		addParam("dpDiscard", strconv.FormatBool(discard))
@@ -655,7 +894,7 @@ func (api *AdminAPI) SetDataPartitionDiscard(partitionId uint64, discard bool) (
 }
 
 func (api *AdminAPI) DeleteVersion(volName string, verSeq string) (err error) {
-	request := newRequest(get, proto.AdminDelVersion).Header(api.h)
+	request := api.newRequest(get, proto.AdminDelVersion).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("verSeq", verSeq)
 	_, err = api.mc.serveRequest(request)
@@ -663,7 +902,7 @@ func (api *AdminAPI) DeleteVersion(volName string, verSeq string) (err error) {
 }
 
 func (api *AdminAPI) SetStrategy(volName string, periodic string, count string, enable string, force string) (err error) {
-	request := newRequest(get, proto.AdminSetVerStrategy).Header(api.h)
+	request := api.newRequest(get, proto.AdminSetVerStrategy).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("periodic", periodic)
 	request.addParam("count", count)
@@ -675,21 +914,21 @@ func (api *AdminAPI) SetStrategy(volName string, periodic string, count string,
 
 func (api *AdminAPI) CreateVersion(volName string) (ver *proto.VolVersionInfo, err error) {
 	ver = &proto.VolVersionInfo{}
-	err = api.mc.requestWith(ver, newRequest(get, proto.AdminCreateVersion).
+	err = api.mc.requestWith(ver, api.newRequest(get, proto.AdminCreateVersion).
 		Header(api.h).addParam("name", volName))
 	return
 }
 
 func (api *AdminAPI) GetLatestVer(volName string) (ver *proto.VolVersionInfo, err error) {
 	ver = &proto.VolVersionInfo{}
-	err = api.mc.requestWith(ver, newRequest(get, proto.AdminGetVersionInfo).
+	err = api.mc.requestWith(ver, api.newRequest(get, proto.AdminGetVersionInfo).
 		Header(api.h).addParam("name", volName))
 	return
 }
 
 func (api *AdminAPI) GetVerList(volName string) (verList *proto.VolVersionInfoList, err error) {
 	verList = &proto.VolVersionInfoList{}
-	err = api.mc.requestWith(verList, newRequest(get, proto.AdminGetAllVersionInfo).
+	err = api.mc.requestWith(verList, api.newRequest(get, proto.AdminGetAllVersionInfo).
 		Header(api.h).addParam("name", volName))
 	log.LogDebugf("GetVerList. vol %v verList %v", volName, verList)
 	for _, info := range verList.VerList {
@@ -699,23 +938,92 @@ func (api *AdminAPI) GetVerList(volName string) (verList *proto.VolVersionInfoLi
 }
 
 func (api *AdminAPI) SetBucketLifecycle(req *proto.LcConfiguration) (err error) {
-	return api.mc.request(newRequest(post, proto.SetBucketLifecycle).Header(api.h).Body(req))
+	return api.mc.request(api.newRequest(post, proto.SetBucketLifecycle).Header(api.h).Body(req))
 }
 
 func (api *AdminAPI) GetBucketLifecycle(volume string) (lcConf *proto.LcConfiguration, err error) {
 	lcConf = &proto.LcConfiguration{}
-	err = api.mc.requestWith(lcConf, newRequest(get, proto.GetBucketLifecycle).
+	err = api.mc.requestWith(lcConf, api.newRequest(get, proto.GetBucketLifecycle).
 		Header(api.h).addParam("name", volume))
 	return
 }
 
 func (api *AdminAPI) DelBucketLifecycle(volume string) (err error) {
-	request := newRequest(get, proto.DeleteBucketLifecycle).Header(api.h)
+	request := api.newRequest(get, proto.DeleteBucketLifecycle).Header(api.h)
 	request.addParam("name", volume)
 	_, err = api.mc.serveRequest(request)
 	return
 }
 
 func (api *AdminAPI) GetS3QoSInfo() (data []byte, err error) {
-	return api.mc.serveRequest(newRequest(get, proto.S3QoSGet).Header(api.h))
+	return api.mc.serveRequest(api.newRequest(get, proto.S3QoSGet).Header(api.h))
+}
+
+func (api *AdminAPI) CreateTenant(name string, capacityLimit uint64, volCountLimit, userCountLimit int, description string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(post, proto.TenantCreate).Header(api.h).Param(
+		anyParam{"tenant", name},
+		anyParam{"capacityLimit", capacityLimit},
+		anyParam{"volCountLimit", volCountLimit},
+		anyParam{"userCountLimit", userCountLimit},
+		anyParam{"description", description},
+	))
+	return
+}
+
+// UpdateTenant replaces the limits and description of the tenant, a zero limit means no limit
+func (api *AdminAPI) UpdateTenant(name string, capacityLimit uint64, volCountLimit, userCountLimit int, description string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(post, proto.TenantUpdate).Header(api.h).Param(
+		anyParam{"tenant", name},
+		anyParam{"capacityLimit", capacityLimit},
+		anyParam{"volCountLimit", volCountLimit},
+		anyParam{"userCountLimit", userCountLimit},
+		anyParam{"description", description},
+	))
+	return
+}
+
+func (api *AdminAPI) DeleteTenant(name string) (err error) {
+	return api.mc.request(api.newRequest(post, proto.TenantDelete).Header(api.h).addParam("tenant", name))
+}
+
+func (api *AdminAPI) GetTenant(name string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(get, proto.TenantGet).Header(api.h).addParam("tenant", name))
+	return
+}
+
+func (api *AdminAPI) ListTenants() (tenants []*proto.TenantView, err error) {
+	tenants = make([]*proto.TenantView, 0)
+	err = api.mc.requestWith(&tenants, api.newRequest(get, proto.TenantList).Header(api.h))
+	return
+}
+
+func (api *AdminAPI) AddTenantUser(name, userID string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(post, proto.TenantAddUser).Header(api.h).
+		Param(anyParam{"tenant", name}, anyParam{"user", userID}))
+	return
+}
+
+func (api *AdminAPI) RemoveTenantUser(name, userID string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(post, proto.TenantRemoveUser).Header(api.h).
+		Param(anyParam{"tenant", name}, anyParam{"user", userID}))
+	return
+}
+
+func (api *AdminAPI) AddTenantVol(name, volName string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(post, proto.TenantAddVol).Header(api.h).
+		Param(anyParam{"tenant", name}, anyParam{"name", volName}))
+	return
+}
+
+func (api *AdminAPI) RemoveTenantVol(name, volName string) (tenant *proto.TenantView, err error) {
+	tenant = &proto.TenantView{}
+	err = api.mc.requestWith(tenant, api.newRequest(post, proto.TenantRemoveVol).Header(api.h).
+		Param(anyParam{"tenant", name}, anyParam{"name", volName}))
+	return
 }
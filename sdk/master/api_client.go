@@ -15,9 +15,11 @@
 package master
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/cubefs/cubefs/proto"
 )
@@ -29,12 +31,22 @@ func (d Decoder) Decode(raw []byte) ([]byte, error) {
 }
 
 type ClientAPI struct {
-	mc *MasterClient
-	h  map[string]string // extra headers
+	mc  *MasterClient
+	h   map[string]string // extra headers
+	ctx context.Context
 }
 
 func (api *ClientAPI) WithHeader(key, val string) *ClientAPI {
-	return &ClientAPI{mc: api.mc, h: mergeHeader(api.h, key, val)}
+	return &ClientAPI{mc: api.mc, h: mergeHeader(api.h, key, val), ctx: api.ctx}
+}
+
+// WithContext returns an api whose requests are cancelled once ctx is done.
+func (api *ClientAPI) WithContext(ctx context.Context) *ClientAPI {
+	return &ClientAPI{mc: api.mc, h: api.h, ctx: ctx}
+}
+
+func (api *ClientAPI) newRequest(method string, path string) *request {
+	return newRequest(method, path).WithContext(api.ctx)
 }
 
 func (api *ClientAPI) EncodingWith(encoding string) *ClientAPI {
@@ -47,21 +59,21 @@ func (api *ClientAPI) EncodingGzip() *ClientAPI {
 
 func (api *ClientAPI) GetVolume(volName string, authKey string) (vv *proto.VolView, err error) {
 	vv = &proto.VolView{}
-	err = api.mc.requestWith(vv, newRequest(post, proto.ClientVol).
+	err = api.mc.requestWith(vv, api.newRequest(post, proto.ClientVol).
 		Header(api.h).Param(anyParam{"name", volName}, anyParam{"authKey", authKey}))
 	return
 }
 
 func (api *ClientAPI) GetVolumeWithoutAuthKey(volName string) (vv *proto.VolView, err error) {
 	vv = &proto.VolView{}
-	err = api.mc.requestWith(vv, newRequest(post, proto.ClientVol).
+	err = api.mc.requestWith(vv, api.newRequest(post, proto.ClientVol).
 		Header(api.h, proto.SkipOwnerValidation, "true").addParam("name", volName))
 	return
 }
 
 func (api *ClientAPI) GetVolumeWithAuthnode(volName string, authKey string, token string, decoder Decoder) (vv *proto.VolView, err error) {
 	var body []byte
-	request := newRequest(post, proto.ClientVol).Header(api.h)
+	request := api.newRequest(post, proto.ClientVol).Header(api.h)
 	request.addParam("name", volName)
 	request.addParam("authKey", authKey)
 	request.addParam(proto.ClientMessage, token)
@@ -82,27 +94,27 @@ func (api *ClientAPI) GetVolumeWithAuthnode(volName string, authKey string, toke
 
 func (api *ClientAPI) GetVolumeStat(volName string) (info *proto.VolStatInfo, err error) {
 	info = &proto.VolStatInfo{}
-	err = api.mc.requestWith(info, newRequest(get, proto.ClientVolStat).
+	err = api.mc.requestWith(info, api.newRequest(get, proto.ClientVolStat).
 		Header(api.h).Param(anyParam{"name", volName}, anyParam{"version", proto.LFClient}))
 	return
 }
 
 func (api *ClientAPI) GetMetaPartition(partitionID uint64) (partition *proto.MetaPartitionInfo, err error) {
 	partition = &proto.MetaPartitionInfo{}
-	err = api.mc.requestWith(partition, newRequest(get, proto.ClientMetaPartition).
+	err = api.mc.requestWith(partition, api.newRequest(get, proto.ClientMetaPartition).
 		Header(api.h).addParamAny("id", partitionID))
 	return
 }
 
 func (api *ClientAPI) GetMetaPartitions(volName string) (views []*proto.MetaPartitionView, err error) {
 	views = make([]*proto.MetaPartitionView, 0)
-	err = api.mc.requestWith(&views, newRequest(get, proto.ClientMetaPartitions).
+	err = api.mc.requestWith(&views, api.newRequest(get, proto.ClientMetaPartitions).
 		Header(api.h).addParam("name", volName))
 	return
 }
 
 func (api *ClientAPI) GetDataPartitionsFromLeader(volName string) (view *proto.DataPartitionsView, err error) {
-	request := newRequest(get, proto.ClientDataPartitions).Header(api.h).addParam("name", volName)
+	request := api.newRequest(get, proto.ClientDataPartitions).Header(api.h).addParam("name", volName)
 	var data []byte
 	if data, err = api.mc.serveRequest(request); err != nil {
 		return
@@ -129,7 +141,7 @@ func (api *ClientAPI) GetDataPartitions(volName string) (view *proto.DataPartiti
 
 func (api *ClientAPI) GetDiskDataPartitions(addr string, diskPath string) (view *proto.DiskDataPartitionsView, err error) {
 	view = &proto.DiskDataPartitionsView{}
-	err = api.mc.requestWith(view, newRequest(get, proto.ClientDiskDataPartitions).
+	err = api.mc.requestWith(view, api.newRequest(get, proto.ClientDiskDataPartitions).
 		Header(api.h).
 		addParam("addr", addr).
 		addParam("disk", diskPath))
@@ -138,7 +150,17 @@ func (api *ClientAPI) GetDiskDataPartitions(addr string, diskPath string) (view
 
 func (api *ClientAPI) GetPreLoadDataPartitions(volName string) (view *proto.DataPartitionsView, err error) {
 	view = &proto.DataPartitionsView{}
-	err = api.mc.requestWith(view, newRequest(get, proto.ClientDataPartitions).
+	err = api.mc.requestWith(view, api.newRequest(get, proto.ClientDataPartitions).
 		Header(api.h).addParam("name", volName))
 	return
 }
+
+// WatchTopology returns topology events since seq, master waits at most wait for new events.
+// A zero seq returns the sequence to watch from without events.
+func (api *ClientAPI) WatchTopology(seq uint64, wait time.Duration) (view *proto.TopologyEventsView, err error) {
+	view = &proto.TopologyEventsView{}
+	err = api.mc.requestWith(view, api.newRequest(get, proto.ClientTopologyEvents).Header(api.h).NoTimeout().
+		addParamAny("seq", seq).
+		addParamAny("wait", int64(wait/time.Second)))
+	return
+}
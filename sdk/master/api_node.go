@@ -15,18 +15,36 @@
 package master
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	"github.com/cubefs/cubefs/proto"
 )
 
+// node types of the maintenance API, the same as partition types of master
+const (
+	maintenanceNodeTypeMeta = 1
+	maintenanceNodeTypeData = 2
+)
+
 type NodeAPI struct {
-	mc *MasterClient
-	h  map[string]string // extra headers
+	mc  *MasterClient
+	h   map[string]string // extra headers
+	ctx context.Context
 }
 
 func (api *NodeAPI) WithHeader(key, val string) *NodeAPI {
-	return &NodeAPI{mc: api.mc, h: mergeHeader(api.h, key, val)}
+	return &NodeAPI{mc: api.mc, h: mergeHeader(api.h, key, val), ctx: api.ctx}
+}
+
+// WithContext returns an api whose requests are cancelled once ctx is done.
+func (api *NodeAPI) WithContext(ctx context.Context) *NodeAPI {
+	return &NodeAPI{mc: api.mc, h: api.h, ctx: ctx}
+}
+
+func (api *NodeAPI) newRequest(method string, path string) *request {
+	return newRequest(method, path).WithContext(api.ctx)
 }
 
 func (api *NodeAPI) EncodingWith(encoding string) *NodeAPI {
@@ -38,7 +56,7 @@ func (api *NodeAPI) EncodingGzip() *NodeAPI {
 }
 
 func (api *NodeAPI) AddDataNode(serverAddr, zoneName string) (id uint64, err error) {
-	request := newRequest(get, proto.AddDataNode).Header(api.h)
+	request := api.newRequest(get, proto.AddDataNode).Header(api.h)
 	request.addParam("addr", serverAddr)
 	request.addParam("zoneName", zoneName)
 	var data []byte
@@ -50,7 +68,7 @@ func (api *NodeAPI) AddDataNode(serverAddr, zoneName string) (id uint64, err err
 }
 
 func (api *NodeAPI) AddDataNodeWithAuthNode(serverAddr, zoneName, clientIDKey string) (id uint64, err error) {
-	request := newRequest(get, proto.AddDataNode).Header(api.h)
+	request := api.newRequest(get, proto.AddDataNode).Header(api.h)
 	request.addParam("addr", serverAddr)
 	request.addParam("zoneName", zoneName)
 	request.addParam("clientIDKey", clientIDKey)
@@ -63,7 +81,7 @@ func (api *NodeAPI) AddDataNodeWithAuthNode(serverAddr, zoneName, clientIDKey st
 }
 
 func (api *NodeAPI) AddMetaNode(serverAddr, zoneName string) (id uint64, err error) {
-	request := newRequest(get, proto.AddMetaNode).Header(api.h)
+	request := api.newRequest(get, proto.AddMetaNode).Header(api.h)
 	request.addParam("addr", serverAddr)
 	request.addParam("zoneName", zoneName)
 	var data []byte
@@ -75,7 +93,7 @@ func (api *NodeAPI) AddMetaNode(serverAddr, zoneName string) (id uint64, err err
 }
 
 func (api *NodeAPI) AddMetaNodeWithAuthNode(serverAddr, zoneName, clientIDKey string) (id uint64, err error) {
-	request := newRequest(get, proto.AddMetaNode).Header(api.h)
+	request := api.newRequest(get, proto.AddMetaNode).Header(api.h)
 	request.addParam("addr", serverAddr)
 	request.addParam("zoneName", zoneName)
 	request.addParam("clientIDKey", clientIDKey)
@@ -89,26 +107,26 @@ func (api *NodeAPI) AddMetaNodeWithAuthNode(serverAddr, zoneName, clientIDKey st
 
 func (api *NodeAPI) GetDataNode(serverHost string) (node *proto.DataNodeInfo, err error) {
 	node = &proto.DataNodeInfo{}
-	err = api.mc.requestWith(node, newRequest(get, proto.GetDataNode).Header(api.h).addParam("addr", serverHost))
+	err = api.mc.requestWith(node, api.newRequest(get, proto.GetDataNode).Header(api.h).addParam("addr", serverHost))
 	return
 }
 
 func (api *NodeAPI) GetMetaNode(serverHost string) (node *proto.MetaNodeInfo, err error) {
 	node = &proto.MetaNodeInfo{}
-	err = api.mc.requestWith(node, newRequest(get, proto.GetMetaNode).Header(api.h).addParam("addr", serverHost))
+	err = api.mc.requestWith(node, api.newRequest(get, proto.GetMetaNode).Header(api.h).addParam("addr", serverHost))
 	return
 }
 
 func (api *NodeAPI) ResponseMetaNodeTask(task *proto.AdminTask) (err error) {
-	return api.mc.request(newRequest(post, proto.GetMetaNodeTaskResponse).Header(api.h).Body(task))
+	return api.mc.request(api.newRequest(post, proto.GetMetaNodeTaskResponse).Header(api.h).Body(task))
 }
 
 func (api *NodeAPI) ResponseDataNodeTask(task *proto.AdminTask) (err error) {
-	return api.mc.request(newRequest(post, proto.GetDataNodeTaskResponse).Header(api.h).Body(task))
+	return api.mc.request(api.newRequest(post, proto.GetDataNodeTaskResponse).Header(api.h).Body(task))
 }
 
 func (api *NodeAPI) DataNodeDecommission(nodeAddr string, count int, clientIDKey string) (err error) {
-	request := newRequest(get, proto.DecommissionDataNode).Header(api.h).NoTimeout()
+	request := api.newRequest(get, proto.DecommissionDataNode).Header(api.h).NoTimeout()
 	request.addParam("addr", nodeAddr)
 	request.addParam("count", strconv.Itoa(count))
 	request.addParam("clientIDKey", clientIDKey)
@@ -118,8 +136,38 @@ func (api *NodeAPI) DataNodeDecommission(nodeAddr string, count int, clientIDKey
 	return
 }
 
+// DataNodeDecommissionPlan returns the partitions to migrate and their targets if the data node is decommissioned
+func (api *NodeAPI) DataNodeDecommissionPlan(nodeAddr string, count int) (plan *proto.DryRunPlan, err error) {
+	plan = &proto.DryRunPlan{}
+	err = api.mc.requestWith(plan, api.newRequest(get, proto.DecommissionDataNode).Header(api.h).
+		addParam("addr", nodeAddr).addParam("count", strconv.Itoa(count)).addParam("dryrun", "true"))
+	return
+}
+
+func (api *NodeAPI) QueryDataNodeDecommissionProgress(nodeAddr string) (progress *proto.DecommissionProgress, err error) {
+	progress = &proto.DecommissionProgress{}
+	err = api.mc.requestWith(progress, api.newRequest(get, proto.QueryDataNodeDecoProgress).
+		Header(api.h).addParam("addr", nodeAddr))
+	return
+}
+
+func (api *NodeAPI) QueryDataNodeDecommissionDetail(nodeAddr string) (detail *proto.DecommissionDetail, err error) {
+	detail = &proto.DecommissionDetail{}
+	err = api.mc.requestWith(detail, api.newRequest(get, proto.QueryDataNodeDecoDetail).
+		Header(api.h).addParam("addr", nodeAddr))
+	return
+}
+
+func (api *NodeAPI) PauseDataNodeDecommission(nodeAddr string) (err error) {
+	return api.mc.request(api.newRequest(post, proto.PauseDecommissionDataNode).Header(api.h).addParam("addr", nodeAddr))
+}
+
+func (api *NodeAPI) ResumeDataNodeDecommission(nodeAddr string) (err error) {
+	return api.mc.request(api.newRequest(post, proto.ResumeDecommissionDataNode).Header(api.h).addParam("addr", nodeAddr))
+}
+
 func (api *NodeAPI) MetaNodeDecommission(nodeAddr string, count int, clientIDKey string) (err error) {
-	request := newRequest(get, proto.DecommissionMetaNode).Header(api.h).NoTimeout()
+	request := api.newRequest(get, proto.DecommissionMetaNode).Header(api.h).NoTimeout()
 	request.addParam("addr", nodeAddr)
 	request.addParam("count", strconv.Itoa(count))
 	request.addParam("clientIDKey", clientIDKey)
@@ -129,8 +177,16 @@ func (api *NodeAPI) MetaNodeDecommission(nodeAddr string, count int, clientIDKey
 	return
 }
 
+// MetaNodeDecommissionPlan returns the partitions to migrate and their targets if the meta node is decommissioned
+func (api *NodeAPI) MetaNodeDecommissionPlan(nodeAddr string, count int) (plan *proto.DryRunPlan, err error) {
+	plan = &proto.DryRunPlan{}
+	err = api.mc.requestWith(plan, api.newRequest(get, proto.DecommissionMetaNode).Header(api.h).
+		addParam("addr", nodeAddr).addParam("count", strconv.Itoa(count)).addParam("dryrun", "true"))
+	return
+}
+
 func (api *NodeAPI) MetaNodeMigrate(srcAddr, targetAddr string, count int, clientIDKey string) (err error) {
-	request := newRequest(get, proto.MigrateMetaNode).Header(api.h).NoTimeout()
+	request := api.newRequest(get, proto.MigrateMetaNode).Header(api.h).NoTimeout()
 	request.addParam("srcAddr", srcAddr)
 	request.addParam("targetAddr", targetAddr)
 	request.addParam("count", strconv.Itoa(count))
@@ -142,7 +198,7 @@ func (api *NodeAPI) MetaNodeMigrate(srcAddr, targetAddr string, count int, clien
 }
 
 func (api *NodeAPI) DataNodeMigrate(srcAddr, targetAddr string, count int, clientIDKey string) (err error) {
-	request := newRequest(get, proto.MigrateDataNode).Header(api.h).NoTimeout()
+	request := api.newRequest(get, proto.MigrateDataNode).Header(api.h).NoTimeout()
 	request.addParam("srcAddr", srcAddr)
 	request.addParam("targetAddr", targetAddr)
 	request.addParam("count", strconv.Itoa(count))
@@ -154,7 +210,7 @@ func (api *NodeAPI) DataNodeMigrate(srcAddr, targetAddr string, count int, clien
 }
 
 func (api *NodeAPI) AddLcNode(serverAddr string) (id uint64, err error) {
-	request := newRequest(get, proto.AddLcNode).Header(api.h).addParam("addr", serverAddr)
+	request := api.newRequest(get, proto.AddLcNode).Header(api.h).addParam("addr", serverAddr)
 	var data []byte
 	if data, err = api.mc.serveRequest(request); err != nil {
 		return
@@ -164,5 +220,42 @@ func (api *NodeAPI) AddLcNode(serverAddr string) (id uint64, err error) {
 }
 
 func (api *NodeAPI) ResponseLcNodeTask(task *proto.AdminTask) (err error) {
-	return api.mc.request(newRequest(post, proto.GetLcNodeTaskResponse).Header(api.h).Body(task))
+	return api.mc.request(api.newRequest(post, proto.GetLcNodeTaskResponse).Header(api.h).Body(task))
+}
+
+func (api *NodeAPI) enterMaintenance(nodeAddr string, nodeType int, ttl time.Duration) (err error) {
+	request := api.newRequest(post, proto.AdminEnterNodeMaintenance).Header(api.h)
+	request.addParam("addr", nodeAddr)
+	request.addParam("nodeType", strconv.Itoa(nodeType))
+	request.addParam("ttl", strconv.FormatInt(int64(ttl/time.Second), 10))
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+func (api *NodeAPI) exitMaintenance(nodeAddr string, nodeType int) (err error) {
+	request := api.newRequest(post, proto.AdminExitNodeMaintenance).Header(api.h)
+	request.addParam("addr", nodeAddr)
+	request.addParam("nodeType", strconv.Itoa(nodeType))
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+// DataNodeEnterMaintenance stops placing new partitions on the data node and transfers its leaders away,
+// the data node exits maintenance automatically after ttl.
+func (api *NodeAPI) DataNodeEnterMaintenance(nodeAddr string, ttl time.Duration) (err error) {
+	return api.enterMaintenance(nodeAddr, maintenanceNodeTypeData, ttl)
+}
+
+func (api *NodeAPI) DataNodeExitMaintenance(nodeAddr string) (err error) {
+	return api.exitMaintenance(nodeAddr, maintenanceNodeTypeData)
+}
+
+// MetaNodeEnterMaintenance stops placing new partitions on the meta node and transfers its leaders away,
+// the meta node exits maintenance automatically after ttl.
+func (api *NodeAPI) MetaNodeEnterMaintenance(nodeAddr string, ttl time.Duration) (err error) {
+	return api.enterMaintenance(nodeAddr, maintenanceNodeTypeMeta, ttl)
+}
+
+func (api *NodeAPI) MetaNodeExitMaintenance(nodeAddr string) (err error) {
+	return api.exitMaintenance(nodeAddr, maintenanceNodeTypeMeta)
 }
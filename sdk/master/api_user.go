@@ -1,20 +1,32 @@
 package master
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/util/ump"
 )
 
 type UserAPI struct {
-	mc *MasterClient
-	h  map[string]string // extra headers
+	mc  *MasterClient
+	h   map[string]string // extra headers
+	ctx context.Context
 }
 
 func (api *UserAPI) WithHeader(key, val string) *UserAPI {
-	return &UserAPI{mc: api.mc, h: mergeHeader(api.h, key, val)}
+	return &UserAPI{mc: api.mc, h: mergeHeader(api.h, key, val), ctx: api.ctx}
+}
+
+// WithContext returns an api whose requests are cancelled once ctx is done.
+func (api *UserAPI) WithContext(ctx context.Context) *UserAPI {
+	return &UserAPI{mc: api.mc, h: api.h, ctx: ctx}
+}
+
+func (api *UserAPI) newRequest(method string, path string) *request {
+	return newRequest(method, path).WithContext(api.ctx)
 }
 
 func (api *UserAPI) EncodingWith(encoding string) *UserAPI {
@@ -27,13 +39,13 @@ func (api *UserAPI) EncodingGzip() *UserAPI {
 
 func (api *UserAPI) CreateUser(param *proto.UserCreateParam, clientIDKey string) (userInfo *proto.UserInfo, err error) {
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(post, proto.UserCreate).
+	err = api.mc.requestWith(userInfo, api.newRequest(post, proto.UserCreate).
 		Header(api.h).Body(param).addParam("clientIDKey", clientIDKey))
 	return
 }
 
 func (api *UserAPI) DeleteUser(userID string, clientIDKey string) (err error) {
-	request := newRequest(post, proto.UserDelete).Header(api.h)
+	request := api.newRequest(post, proto.UserDelete).Header(api.h)
 	request.addParam("user", userID)
 	request.addParam("clientIDKey", clientIDKey)
 	if _, err = api.mc.serveRequest(request); err != nil {
@@ -44,7 +56,7 @@ func (api *UserAPI) DeleteUser(userID string, clientIDKey string) (err error) {
 
 func (api *UserAPI) UpdateUser(param *proto.UserUpdateParam, clientIDKey string) (userInfo *proto.UserInfo, err error) {
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(post, proto.UserUpdate).
+	err = api.mc.requestWith(userInfo, api.newRequest(post, proto.UserUpdate).
 		Header(api.h).Body(param).addParam("clientIDKey", clientIDKey))
 	return
 }
@@ -52,14 +64,14 @@ func (api *UserAPI) UpdateUser(param *proto.UserUpdateParam, clientIDKey string)
 func (api *UserAPI) GetAKInfo(accesskey string) (userInfo *proto.UserInfo, err error) {
 	localIP, _ := ump.GetLocalIpAddr()
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(get, proto.UserGetAKInfo).
+	err = api.mc.requestWith(userInfo, api.newRequest(get, proto.UserGetAKInfo).
 		Header(api.h).Param(anyParam{"ak", accesskey}, anyParam{"ip", localIP}))
 	return
 }
 
 func (api *UserAPI) AclOperation(volName string, localIP string, op uint32) (aclInfo *proto.AclRsp, err error) {
 	aclInfo = &proto.AclRsp{}
-	if err = api.mc.requestWith(aclInfo, newRequest(get, proto.AdminACL).Header(api.h).Param(
+	if err = api.mc.requestWith(aclInfo, api.newRequest(get, proto.AdminACL).Header(api.h).Param(
 		anyParam{"name", volName},
 		anyParam{"ip", localIP},
 		anyParam{"op", op},
@@ -72,7 +84,7 @@ func (api *UserAPI) AclOperation(volName string, localIP string, op uint32) (acl
 
 func (api *UserAPI) UidOperation(volName string, uid string, op uint32, val string) (uidInfo *proto.UidSpaceRsp, err error) {
 	uidInfo = &proto.UidSpaceRsp{}
-	if err = api.mc.requestWith(uidInfo, newRequest(get, proto.AdminUid).Header(api.h).Param(
+	if err = api.mc.requestWith(uidInfo, api.newRequest(get, proto.AdminUid).Header(api.h).Param(
 		anyParam{"name", volName},
 		anyParam{"uid", uid},
 		anyParam{"op", op},
@@ -86,44 +98,96 @@ func (api *UserAPI) UidOperation(volName string, uid string, op uint32, val stri
 
 func (api *UserAPI) GetUserInfo(userID string) (userInfo *proto.UserInfo, err error) {
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(get, proto.UserGetInfo).Header(api.h).addParam("user", userID))
+	err = api.mc.requestWith(userInfo, api.newRequest(get, proto.UserGetInfo).Header(api.h).addParam("user", userID))
 	return
 }
 
 func (api *UserAPI) UpdatePolicy(param *proto.UserPermUpdateParam, clientIDKey string) (userInfo *proto.UserInfo, err error) {
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(post, proto.UserUpdatePolicy).
+	err = api.mc.requestWith(userInfo, api.newRequest(post, proto.UserUpdatePolicy).
 		Header(api.h).Body(param).addParam("clientIDKey", clientIDKey))
 	return
 }
 
 func (api *UserAPI) RemovePolicy(param *proto.UserPermRemoveParam, clientIDKey string) (userInfo *proto.UserInfo, err error) {
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(post, proto.UserRemovePolicy).
+	err = api.mc.requestWith(userInfo, api.newRequest(post, proto.UserRemovePolicy).
 		Header(api.h).Body(param).addParam("clientIDKey", clientIDKey))
 	return
 }
 
 func (api *UserAPI) DeleteVolPolicy(vol, clientIDKey string) (err error) {
-	return api.mc.request(newRequest(post, proto.UserDeleteVolPolicy).Header(api.h).
+	return api.mc.request(api.newRequest(post, proto.UserDeleteVolPolicy).Header(api.h).
 		addParam("name", vol).addParam("clientIDKey", clientIDKey))
 }
 
 func (api *UserAPI) TransferVol(param *proto.UserTransferVolParam, clientIDKey string) (userInfo *proto.UserInfo, err error) {
 	userInfo = &proto.UserInfo{}
-	err = api.mc.requestWith(userInfo, newRequest(post, proto.UserTransferVol).
+	err = api.mc.requestWith(userInfo, api.newRequest(post, proto.UserTransferVol).
 		Header(api.h).Body(param).addParam("clientIDKey", clientIDKey))
 	return
 }
 
 func (api *UserAPI) ListUsers(keywords string) (users []*proto.UserInfo, err error) {
 	users = make([]*proto.UserInfo, 0)
-	err = api.mc.requestWith(&users, newRequest(get, proto.UserList).Header(api.h).addParam("keywords", keywords))
+	err = api.mc.requestWith(&users, api.newRequest(get, proto.UserList).Header(api.h).addParam("keywords", keywords))
 	return
 }
 
 func (api *UserAPI) ListUsersOfVol(vol string) (users []string, err error) {
 	users = make([]string, 0)
-	err = api.mc.requestWith(&users, newRequest(get, proto.UsersOfVol).Header(api.h).addParam("name", vol))
+	err = api.mc.requestWith(&users, api.newRequest(get, proto.UsersOfVol).Header(api.h).addParam("name", vol))
+	return
+}
+
+// CreateRole creates a role allowed to do the admin actions, such as proto.AdminActionCreateVolume
+func (api *UserAPI) CreateRole(name string, actions []string, description string) (role *proto.RoleInfo, err error) {
+	role = &proto.RoleInfo{}
+	err = api.mc.requestWith(role, api.newRequest(post, proto.RoleCreate).Header(api.h).Param(
+		anyParam{"role", name},
+		anyParam{"actions", strings.Join(actions, ",")},
+		anyParam{"description", description},
+	))
+	return
+}
+
+// UpdateRole replaces the actions of the role, actions are not changed if empty
+func (api *UserAPI) UpdateRole(name string, actions []string, description string) (role *proto.RoleInfo, err error) {
+	role = &proto.RoleInfo{}
+	err = api.mc.requestWith(role, api.newRequest(post, proto.RoleUpdate).Header(api.h).Param(
+		anyParam{"role", name},
+		anyParam{"actions", strings.Join(actions, ",")},
+		anyParam{"description", description},
+	))
+	return
+}
+
+func (api *UserAPI) DeleteRole(name string) (err error) {
+	return api.mc.request(api.newRequest(post, proto.RoleDelete).Header(api.h).addParam("role", name))
+}
+
+func (api *UserAPI) GetRole(name string) (role *proto.RoleInfo, err error) {
+	role = &proto.RoleInfo{}
+	err = api.mc.requestWith(role, api.newRequest(get, proto.RoleGet).Header(api.h).addParam("role", name))
+	return
+}
+
+func (api *UserAPI) ListRoles() (roles []*proto.RoleInfo, err error) {
+	roles = make([]*proto.RoleInfo, 0)
+	err = api.mc.requestWith(&roles, api.newRequest(get, proto.RoleList).Header(api.h))
+	return
+}
+
+func (api *UserAPI) GrantRole(name, userID string) (role *proto.RoleInfo, err error) {
+	role = &proto.RoleInfo{}
+	err = api.mc.requestWith(role, api.newRequest(post, proto.RoleGrant).Header(api.h).
+		Param(anyParam{"role", name}, anyParam{"user", userID}))
+	return
+}
+
+func (api *UserAPI) RevokeRole(name, userID string) (role *proto.RoleInfo, err error) {
+	role = &proto.RoleInfo{}
+	err = api.mc.requestWith(role, api.newRequest(post, proto.RoleRevoke).Header(api.h).
+		Param(anyParam{"role", name}, anyParam{"user", userID}))
 	return
 }
@@ -16,11 +16,13 @@ package master
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -59,6 +61,10 @@ type MasterClient struct {
 	leaderAddr  string
 	timeout     time.Duration
 	clientIDKey string
+	accessKey   string
+	secretKey   string
+	retryPolicy RetryPolicy
+	breaker     hostBreaker
 
 	adminAPI  *AdminAPI
 	clientAPI *ClientAPI
@@ -130,32 +136,80 @@ func (c *MasterClient) SetTimeout(timeout uint16) {
 	c.Unlock()
 }
 
+// SetRetryPolicy changes how requests are retried when no master could serve them,
+// requests are not retried unless a policy is set.
+func (c *MasterClient) SetRetryPolicy(policy RetryPolicy) {
+	c.Lock()
+	c.retryPolicy = policy
+	c.Unlock()
+}
+
+func (c *MasterClient) getRetryPolicy() (policy RetryPolicy) {
+	c.RLock()
+	policy = c.retryPolicy
+	c.RUnlock()
+	return
+}
+
 func (c *MasterClient) SetClientIDKey(clientIDKey string) {
 	c.Lock()
 	c.clientIDKey = clientIDKey
 	c.Unlock()
 }
 
+// SetCredential signs the requests by the keys of the user, which is checked against roles of the user
+// if RBAC is enabled by master.
+func (c *MasterClient) SetCredential(accessKey, secretKey string) {
+	c.Lock()
+	c.accessKey = accessKey
+	c.secretKey = secretKey
+	c.Unlock()
+}
+
 func (c *MasterClient) serveRequest(r *request) (repsData []byte, err error) {
-	leaderAddr, nodes := c.prepareRequest()
-	host := leaderAddr
-	for i := -1; i < len(nodes); i++ {
-		if i == -1 {
-			if host == "" {
-				continue
-			}
-		} else {
-			host = nodes[i]
+	ctx := r.Context()
+	policy := c.getRetryPolicy()
+	for retry := 0; ; retry++ {
+		var retryable bool
+		repsData, retryable, err = c.serveRequestOnce(ctx, r, policy)
+		if err == nil || !retryable || r.method != get || retry >= policy.MaxRetries {
+			return
+		}
+		backoff := policy.backoff(retry)
+		log.LogWarnf("serveRequest: retry after %v: method(%v) path(%v) retry(%v) err(%v)",
+			backoff, r.method, r.path, retry+1, err)
+		if ctxErr := sleepContext(ctx, backoff); ctxErr != nil {
+			return nil, ctxErr
+		}
+	}
+}
+
+// serveRequestOnce sends the request to the leader and then to the other masters until one serves it,
+// the leader address replied by a follower is cached and tried next.
+func (c *MasterClient) serveRequestOnce(ctx context.Context, r *request, policy RetryPolicy) (repsData []byte, retryable bool, err error) {
+	leaderAddr, hosts := c.candidateHosts()
+	if len(hosts) == 0 {
+		return nil, false, ErrNoValidMaster
+	}
+	redirects := 0
+	for i := 0; i < len(hosts); i++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, false, ctxErr
 		}
+		host := hosts[i]
 		var resp *http.Response
 		schema := "http"
 		if c.useSSL {
 			schema = "https"
 		}
 		url := fmt.Sprintf("%s://%s%s", schema, host, r.path)
-		resp, err = c.httpRequest(r.method, url, r)
+		resp, err = c.httpRequest(ctx, r.method, url, r)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, false, ctxErr
+			}
 			log.LogErrorf("serveRequest: send http request fail: method(%v) url(%v) err(%v)", r.method, url, err)
+			c.breaker.onFailure(host, policy)
 			continue
 		}
 		stateCode := resp.StatusCode
@@ -163,22 +217,32 @@ func (c *MasterClient) serveRequest(r *request) (repsData []byte, err error) {
 		_ = resp.Body.Close()
 		if err != nil {
 			log.LogErrorf("serveRequest: read http response body fail: err(%v)", err)
+			c.breaker.onFailure(host, policy)
 			continue
 		}
 
-		switch stateCode {
-		case http.StatusForbidden:
-			curMasterAddr := strings.TrimSpace(string(repsData))
-			curMasterAddr = strings.Replace(curMasterAddr, "\n", "", -1)
-			if len(curMasterAddr) == 0 {
+		if stateCode == http.StatusForbidden || stateCode == http.StatusBadRequest {
+			// the follower replies the leader address if it could not proxy the request.
+			newLeader := parseLeaderAddr(repsData)
+			if newLeader != "" && newLeader != host && redirects < maxLeaderRedirects {
+				log.LogDebugf("serveRequest: redirect to leader(%v) replied by host(%v)", newLeader, host)
+				redirects++
+				c.SetLeader(newLeader)
+				leaderAddr = newLeader
+				hosts = append([]string{newLeader}, hosts[i+1:]...)
+				i = -1
+				continue
+			}
+			if stateCode == http.StatusForbidden && newLeader == "" {
 				log.LogWarnf("serveRequest: server response status 403: request(%s) status"+
 					"(403), body is empty", host)
-				err = ErrNoValidMaster
-				return
+				return nil, true, ErrNoValidMaster
 			}
-			repsData, err = c.serveRequest(r)
-			return
+		}
+
+		switch stateCode {
 		case http.StatusOK:
+			c.breaker.onSuccess(host)
 			if leaderAddr != host {
 				log.LogDebugf("server Request resp new master[%v] old [%v]", host, leaderAddr)
 				c.SetLeader(host)
@@ -186,28 +250,29 @@ func (c *MasterClient) serveRequest(r *request) (repsData []byte, err error) {
 			repsData, err = compressor.New(resp.Header.Get(headerContentEncoding)).Decompress(repsData)
 			if err != nil {
 				log.LogErrorf("serveRequest: decompress response body fail: err(%v)", err)
-				return nil, fmt.Errorf("decompress response body err:%v", err)
+				return nil, false, fmt.Errorf("decompress response body err:%v", err)
 			}
 			body := new(proto.HTTPReplyRaw)
 			if err := body.Unmarshal(repsData); err != nil {
 				log.LogErrorf("unmarshal response body err:%v", err)
-				return nil, fmt.Errorf("unmarshal response body err:%v", err)
+				return nil, false, fmt.Errorf("unmarshal response body err:%v", err)
 
 			}
 			if body.Code != proto.ErrCodeSuccess {
 				log.LogWarnf("serveRequest: code[%v], msg[%v], data[%v] ", body.Code, body.Msg, body.Data)
-				return []byte(body.Data), errors.New(body.Msg)
+				return []byte(body.Data), false, errors.New(body.Msg)
 			}
-			return body.Bytes(), nil
+			return body.Bytes(), false, nil
 		default:
 			msg := fmt.Sprintf("serveRequest: unknown status: host(%v) uri(%v) status(%v) body(%s).",
 				resp.Request.URL.String(), host, stateCode, strings.Replace(string(repsData), "\n", "", -1))
 			err = errors.New(msg)
 			log.LogErrorf(msg)
+			c.breaker.onFailure(host, policy)
 			continue
 		}
 	}
-	return
+	return repsData, true, err
 }
 
 func (c *MasterClient) requestWith(rst interface{}, r *request) error {
@@ -237,6 +302,30 @@ func (c *MasterClient) Nodes() (nodes []string) {
 	return
 }
 
+// candidateHosts returns the leader and the other masters in the order to try, masters whose
+// circuit breaker is open are left out unless all of them are.
+func (c *MasterClient) candidateHosts() (leaderAddr string, hosts []string) {
+	leaderAddr, nodes := c.prepareRequest()
+	all := make([]string, 0, len(nodes)+1)
+	if leaderAddr != "" {
+		all = append(all, leaderAddr)
+	}
+	for _, node := range nodes {
+		if node != leaderAddr {
+			all = append(all, node)
+		}
+	}
+	for _, host := range all {
+		if !c.breaker.isOpen(host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		hosts = all
+	}
+	return
+}
+
 // prepareRequest returns the leader address and all master addresses.
 func (c *MasterClient) prepareRequest() (addr string, nodes []string) {
 	c.RLock()
@@ -246,7 +335,7 @@ func (c *MasterClient) prepareRequest() (addr string, nodes []string) {
 	return
 }
 
-func (c *MasterClient) httpRequest(method, url string, r *request) (resp *http.Response, err error) {
+func (c *MasterClient) httpRequest(ctx context.Context, method, url string, r *request) (resp *http.Response, err error) {
 	client := http.DefaultClient
 	if !r.noTimeout {
 		client.Timeout = c.timeout
@@ -255,7 +344,7 @@ func (c *MasterClient) httpRequest(method, url string, r *request) (resp *http.R
 	var req *http.Request
 	fullUrl := c.mergeRequestUrl(url, r.params)
 	log.LogDebugf("httpRequest: method(%v) url(%v) bodyLength[%v].", method, fullUrl, len(r.body))
-	if req, err = http.NewRequest(method, fullUrl, reader); err != nil {
+	if req, err = http.NewRequestWithContext(ctx, method, fullUrl, reader); err != nil {
 		return
 	}
 	req.Header.Set("Content-Type", "application/json")
@@ -263,6 +352,15 @@ func (c *MasterClient) httpRequest(method, url string, r *request) (resp *http.R
 	for k, v := range r.header {
 		req.Header.Set(k, v)
 	}
+	c.RLock()
+	accessKey, secretKey := c.accessKey, c.secretKey
+	c.RUnlock()
+	if accessKey != "" {
+		date := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(proto.RBACAccessKeyHeader, accessKey)
+		req.Header.Set(proto.RBACDateHeader, date)
+		req.Header.Set(proto.RBACSignatureHeader, proto.RBACSignature(secretKey, method, req.URL.Path, req.URL.RawQuery, date, r.body))
+	}
 	resp, err = client.Do(req)
 	return
 }
@@ -0,0 +1,171 @@
+// Copyright 2018 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxRetries:       3,
+	MinBackoff:       time.Millisecond,
+	MaxBackoff:       5 * time.Millisecond,
+	BreakerThreshold: 2,
+	BreakerCooldown:  time.Minute,
+}
+
+func newTestMaster(handler http.HandlerFunc) (*httptest.Server, string) {
+	server := httptest.NewServer(handler)
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+func replySuccess(w http.ResponseWriter, data interface{}) {
+	reply, _ := json.Marshal(&proto.HTTPReply{Code: proto.ErrCodeSuccess, Msg: "success", Data: data})
+	w.Write(reply)
+}
+
+func TestServeRequestRedirectToLeader(t *testing.T) {
+	leader, leaderAddr := newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		replySuccess(w, "leader")
+	})
+	defer leader.Close()
+	follower, followerAddr := newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, leaderAddr, http.StatusBadRequest)
+	})
+	defer follower.Close()
+
+	mc := NewMasterClient([]string{followerAddr}, false)
+	mc.SetRetryPolicy(testRetryPolicy)
+	var data string
+	require.NoError(t, mc.requestWith(&data, newRequest(get, "/test")))
+	require.Equal(t, "leader", data)
+	require.Equal(t, leaderAddr, mc.Leader())
+}
+
+func TestServeRequestRetry(t *testing.T) {
+	var count int32
+	master, addr := newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= 2 {
+			http.Error(w, "no leader", http.StatusBadRequest)
+			return
+		}
+		replySuccess(w, nil)
+	})
+	defer master.Close()
+
+	mc := NewMasterClient([]string{addr}, false)
+	mc.SetRetryPolicy(testRetryPolicy)
+	require.NoError(t, mc.request(newRequest(get, "/test")))
+	require.EqualValues(t, 3, atomic.LoadInt32(&count))
+
+	// business errors are not retried.
+	atomic.StoreInt32(&count, 0)
+	master, addr = newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		reply, _ := json.Marshal(&proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: "param error"})
+		w.Write(reply)
+	})
+	defer master.Close()
+	mc.ReplaceMasterAddresses([]string{addr})
+	require.EqualError(t, mc.request(newRequest(get, "/test")), "param error")
+	require.EqualValues(t, 1, atomic.LoadInt32(&count))
+
+	// post requests and clients without a policy are not retried.
+	master, addr = newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		http.Error(w, "no leader", http.StatusBadRequest)
+	})
+	defer master.Close()
+	mc.ReplaceMasterAddresses([]string{addr})
+	atomic.StoreInt32(&count, 0)
+	require.Error(t, mc.request(newRequest(post, "/test")))
+	require.EqualValues(t, 1, atomic.LoadInt32(&count))
+
+	mc = NewMasterClient([]string{addr}, false)
+	atomic.StoreInt32(&count, 0)
+	require.Error(t, mc.request(newRequest(get, "/test")))
+	require.EqualValues(t, 1, atomic.LoadInt32(&count))
+}
+
+func TestServeRequestWithContext(t *testing.T) {
+	master, addr := newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		http.Error(w, "no leader", http.StatusBadRequest)
+	})
+	defer master.Close()
+
+	mc := NewMasterClient([]string{addr}, false)
+	mc.SetRetryPolicy(testRetryPolicy)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := mc.AdminAPI().WithContext(ctx).GetClusterInfo()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHostBreaker(t *testing.T) {
+	mc := NewMasterClient([]string{"a:1", "b:1"}, false)
+	mc.breaker.onFailure("a:1", testRetryPolicy)
+	_, hosts := mc.candidateHosts()
+	require.Equal(t, []string{"a:1", "b:1"}, hosts)
+
+	mc.breaker.onFailure("a:1", testRetryPolicy)
+	_, hosts = mc.candidateHosts()
+	require.Equal(t, []string{"b:1"}, hosts)
+
+	// all masters are tried if all breakers are open.
+	mc.breaker.onFailure("b:1", testRetryPolicy)
+	mc.breaker.onFailure("b:1", testRetryPolicy)
+	_, hosts = mc.candidateHosts()
+	require.Equal(t, []string{"a:1", "b:1"}, hosts)
+
+	mc.breaker.onSuccess("a:1")
+	_, hosts = mc.candidateHosts()
+	require.Equal(t, []string{"a:1"}, hosts)
+}
+
+func TestServeRequestWithCredential(t *testing.T) {
+	var signed int32
+	master, addr := newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		date := r.Header.Get(proto.RBACDateHeader)
+		if r.Header.Get(proto.RBACAccessKeyHeader) == "ak" &&
+			r.Header.Get(proto.RBACSignatureHeader) == proto.RBACSignature("sk", r.Method, r.URL.Path, r.URL.RawQuery, date, body) {
+			atomic.AddInt32(&signed, 1)
+		}
+		replySuccess(w, nil)
+	})
+	defer master.Close()
+
+	mc := NewMasterClient([]string{addr}, false)
+	require.NoError(t, mc.request(newRequest(post, "/test").addParam("name", "vol").Body(map[string]string{"k": "v"})))
+	require.EqualValues(t, 0, atomic.LoadInt32(&signed))
+	mc.SetCredential("ak", "sk")
+	require.NoError(t, mc.request(newRequest(post, "/test").addParam("name", "vol").Body(map[string]string{"k": "v"})))
+	require.EqualValues(t, 1, atomic.LoadInt32(&signed))
+}
@@ -15,6 +15,7 @@
 package master
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -29,6 +30,7 @@ type request struct {
 	header map[string]string
 	body   []byte
 	err    error
+	ctx    context.Context
 
 	noTimeout bool
 }
@@ -88,6 +90,19 @@ func (r *request) NoTimeout() *request {
 	return r
 }
 
+// WithContext binds ctx to the request, a nil ctx means the request can not be cancelled.
+func (r *request) WithContext(ctx context.Context) *request {
+	r.ctx = ctx
+	return r
+}
+
+func (r *request) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 func newRequest(method string, path string) *request {
 	req := &request{
 		method: method,
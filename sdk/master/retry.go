@@ -0,0 +1,127 @@
+// Copyright 2018 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxLeaderRedirects = 3
+
+// RetryPolicy controls how a request is retried when no master could serve it,
+// e.g. while the masters are electing a new leader. Only GET requests are retried,
+// since a POST request may have been applied by a master which failed to reply.
+type RetryPolicy struct {
+	// MaxRetries is the number of extra rounds over all masters, 0 disables retrying.
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, it is doubled by each retry up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failures after which a master is skipped
+	// for BreakerCooldown, 0 disables the circuit breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultRetryPolicy is a policy for the clients which opt in to retrying by SetRetryPolicy,
+// the requests are not retried by default.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:       2,
+	MinBackoff:       200 * time.Millisecond,
+	MaxBackoff:       3 * time.Second,
+	BreakerThreshold: 3,
+	BreakerCooldown:  10 * time.Second,
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// sleepContext waits for d, it returns the error of ctx if ctx is done before.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// hostBreaker records consecutive failures of each master, a master is not
+// tried before its cooldown ends once the failures reach the threshold.
+type hostBreaker struct {
+	sync.Mutex
+	failures  map[string]int
+	openUntil map[string]time.Time
+}
+
+func (b *hostBreaker) isOpen(host string) bool {
+	b.Lock()
+	defer b.Unlock()
+	until, ok := b.openUntil[host]
+	return ok && time.Now().Before(until)
+}
+
+func (b *hostBreaker) onSuccess(host string) {
+	b.Lock()
+	delete(b.failures, host)
+	delete(b.openUntil, host)
+	b.Unlock()
+}
+
+func (b *hostBreaker) onFailure(host string, policy RetryPolicy) {
+	if policy.BreakerThreshold <= 0 {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	if b.failures == nil {
+		b.failures = make(map[string]int)
+		b.openUntil = make(map[string]time.Time)
+	}
+	b.failures[host]++
+	if b.failures[host] >= policy.BreakerThreshold {
+		b.openUntil[host] = time.Now().Add(policy.BreakerCooldown)
+	}
+}
+
+// parseLeaderAddr returns the leader address carried by the body of a rejected
+// request, or an empty string if the body is not an address.
+func parseLeaderAddr(body []byte) string {
+	addr := strings.TrimSpace(strings.Replace(string(body), "\n", "", -1))
+	if addr == "" || strings.ContainsAny(addr, " \t") {
+		return ""
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return ""
+	}
+	return addr
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"context"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	topologyWatchWait        = 20 * time.Second
+	topologyEventChannelSize = 128
+)
+
+// SubscribeTopology long polls master for topology changes happened after the call, and delivers
+// them in order through the returned channel, which is closed when ctx is done.
+// An event of type proto.TopologyEventResync is delivered if events may have been lost, e.g. when
+// the leader of master changes, subscribers should reload the topology they care about.
+func (api *ClientAPI) SubscribeTopology(ctx context.Context) <-chan *proto.TopologyEvent {
+	eventC := make(chan *proto.TopologyEvent, topologyEventChannelSize)
+	go api.subscribeTopology(ctx, eventC)
+	return eventC
+}
+
+func (api *ClientAPI) subscribeTopology(ctx context.Context, eventC chan<- *proto.TopologyEvent) {
+	defer close(eventC)
+	watchAPI := api.WithContext(ctx)
+	var (
+		epoch    int64
+		seq      uint64
+		failures int
+	)
+	send := func(event *proto.TopologyEvent) bool {
+		select {
+		case eventC <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for ctx.Err() == nil {
+		view, err := watchAPI.WatchTopology(seq, topologyWatchWait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.LogWarnf("SubscribeTopology: watch topology from seq(%v) failed: %v", seq, err)
+			if sleepContext(ctx, api.mc.getRetryPolicy().backoff(failures)) != nil {
+				return
+			}
+			failures++
+			continue
+		}
+		failures = 0
+		if seq == 0 {
+			epoch, seq = view.Epoch, view.NextSeq
+			continue
+		}
+		if view.Epoch != epoch || view.Truncated {
+			log.LogWarnf("SubscribeTopology: events since seq(%v) are lost, epoch(%v) new epoch(%v) truncated(%v)",
+				seq, epoch, view.Epoch, view.Truncated)
+			epoch, seq = view.Epoch, view.NextSeq
+			if !send(&proto.TopologyEvent{Type: proto.TopologyEventResync, Seq: seq, Time: time.Now().Unix()}) {
+				return
+			}
+			continue
+		}
+		for _, event := range view.Events {
+			if !send(event) {
+				return
+			}
+		}
+		seq = view.NextSeq
+	}
+}
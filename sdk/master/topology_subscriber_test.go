@@ -0,0 +1,73 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeTopology(t *testing.T) {
+	master, addr := newTestMaster(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, proto.ClientTopologyEvents, r.URL.Path)
+		view := &proto.TopologyEventsView{Epoch: 1, Events: make([]*proto.TopologyEvent, 0)}
+		switch r.FormValue("seq") {
+		case "0":
+			view.NextSeq = 1
+		case "1":
+			view.NextSeq = 3
+			view.Events = append(view.Events,
+				&proto.TopologyEvent{Seq: 1, Type: proto.TopologyEventDataNodeAdded, Addr: "node"},
+				&proto.TopologyEvent{Seq: 2, Type: proto.TopologyEventDataPartitionLeaderChanged, PartitionID: 1})
+		case "3":
+			// leader of master changed
+			view.Epoch, view.NextSeq = 2, 5
+		default:
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			view.Epoch, view.NextSeq = 2, 5
+		}
+		replySuccess(w, view)
+	})
+	defer master.Close()
+
+	mc := NewMasterClient([]string{addr}, false)
+	mc.SetRetryPolicy(testRetryPolicy)
+	ctx, cancel := context.WithCancel(context.Background())
+	eventC := mc.ClientAPI().SubscribeTopology(ctx)
+
+	types := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-eventC:
+			types = append(types, event.Type)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no topology event received")
+		}
+	}
+	require.Equal(t, []string{proto.TopologyEventDataNodeAdded, proto.TopologyEventDataPartitionLeaderChanged,
+		proto.TopologyEventResync}, types)
+
+	cancel()
+	for range eventC {
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package meta
+
+import (
+	"time"
+
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const ReportAccessTimeInterval = time.Minute
+
+// RecordAccess records the access of an inode, it is reported to meta nodes periodically
+// if access time reporting is enabled.
+func (mw *MetaWrapper) RecordAccess(ino uint64) {
+	mw.accessLock.Lock()
+	if mw.accessedInodes != nil {
+		mw.accessedInodes[ino] = time.Now().Unix()
+	}
+	mw.accessLock.Unlock()
+}
+
+func (mw *MetaWrapper) reportAccessTimeTick() {
+	ticker := time.NewTicker(ReportAccessTimeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mw.reportAccessTime()
+		case <-mw.closeCh:
+			return
+		}
+	}
+}
+
+func (mw *MetaWrapper) reportAccessTime() {
+	mw.accessLock.Lock()
+	accessed := mw.accessedInodes
+	mw.accessedInodes = make(map[uint64]int64)
+	mw.accessLock.Unlock()
+	if len(accessed) == 0 {
+		return
+	}
+
+	// inodes of a partition are reported in one request with the latest access time
+	type batch struct {
+		mp     *MetaPartition
+		inodes []uint64
+		atime  int64
+	}
+	batches := make(map[uint64]*batch)
+	for ino, atime := range accessed {
+		mp := mw.getPartitionByInode(ino)
+		if mp == nil {
+			continue
+		}
+		b, ok := batches[mp.PartitionID]
+		if !ok {
+			b = &batch{mp: mp}
+			batches[mp.PartitionID] = b
+		}
+		b.inodes = append(b.inodes, ino)
+		if atime > b.atime {
+			b.atime = atime
+		}
+	}
+	for _, b := range batches {
+		if err := mw.batchRecordAccess(b.mp, b.inodes, b.atime); err != nil {
+			log.LogWarnf("reportAccessTime: mp(%v) inodes(%v) err(%v)", b.mp.PartitionID, len(b.inodes), err)
+		}
+	}
+}
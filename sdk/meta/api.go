@@ -15,6 +15,7 @@
 package meta
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	syslog "log"
@@ -46,6 +47,9 @@ const (
 	BatchGetBufLen         = 500
 	UpdateSummaryRetry     = 3
 	SummaryKey             = "DirStat"
+	RecursiveSummaryKey    = "DirStat.Recursive"
+	SummaryParentKey       = "DirStat.Parent"
+	MaxSummaryDepth        = 256
 	ChannelLen             = 100
 	BatchSize              = 200
 	MaxGoroutineNum        = 5
@@ -201,6 +205,9 @@ create_dentry:
 		}
 		// go mw.UpdateSummary_ll(parentID, filesInc, dirsInc, 0)
 		job := func() {
+			if proto.IsDir(mode) {
+				mw.setSummaryParent(info.Inode, parentID)
+			}
 			mw.UpdateSummary_ll(parentID, filesInc, dirsInc, 0)
 		}
 		tx.SetOnCommit(job)
@@ -321,6 +328,7 @@ create_dentry:
 		var filesInc, dirsInc int64
 		if proto.IsDir(mode) {
 			dirsInc = 1
+			mw.setSummaryParent(info.Inode, parentID)
 		} else {
 			filesInc = 1
 		}
@@ -330,15 +338,20 @@ create_dentry:
 }
 
 func (mw *MetaWrapper) Lookup_ll(parentID uint64, name string) (inode uint64, mode uint32, err error) {
+	return mw.LookupContext_ll(context.Background(), parentID, name)
+}
+
+// LookupContext_ll is Lookup_ll which returns EINTR once ctx is done.
+func (mw *MetaWrapper) LookupContext_ll(ctx context.Context, parentID uint64, name string) (inode uint64, mode uint32, err error) {
 	parentMP := mw.getPartitionByInode(parentID)
 	if parentMP == nil {
 		log.LogErrorf("Lookup_ll: No parent partition, parentID(%v) name(%v)", parentID, name)
 		return 0, 0, syscall.ENOENT
 	}
 
-	status, inode, mode, err := mw.lookup(parentMP, parentID, name, mw.VerReadSeq)
+	status, inode, mode, err := mw.lookupContext(ctx, parentMP, parentID, name, mw.VerReadSeq)
 	if err != nil || status != statusOK {
-		return 0, 0, statusToErrno(status)
+		return 0, 0, contextErrno(ctx, status)
 	}
 	return inode, mode, nil
 }
@@ -377,21 +390,26 @@ func (mw *MetaWrapper) BatchGetExpiredMultipart(prefix string, days int) (expire
 }
 
 func (mw *MetaWrapper) InodeGet_ll(inode uint64) (*proto.InodeInfo, error) {
+	return mw.InodeGetContext_ll(context.Background(), inode)
+}
+
+// InodeGetContext_ll is InodeGet_ll which returns EINTR once ctx is done.
+func (mw *MetaWrapper) InodeGetContext_ll(ctx context.Context, inode uint64) (*proto.InodeInfo, error) {
 	mp := mw.getPartitionByInode(inode)
 	if mp == nil {
 		log.LogErrorf("InodeGet_ll: No such partition, ino(%v)", inode)
 		return nil, syscall.ENOENT
 	}
 
-	status, info, err := mw.iget(mp, inode, mw.VerReadSeq)
+	status, info, err := mw.igetContext(ctx, mp, inode, mw.VerReadSeq)
 	if err != nil || status != statusOK {
 		if status == statusNoent {
 			// For NOENT error, pull the latest mp and give it another try,
 			// in case the mp view is outdated.
 			mw.triggerAndWaitForceUpdate()
-			return mw.doInodeGet(inode)
+			return mw.doInodeGet(ctx, inode)
 		}
-		return nil, statusToErrno(status)
+		return nil, contextErrno(ctx, status)
 	}
 	if mw.EnableQuota {
 		if len(info.QuotaInfos) != 0 && proto.IsDir(info.Mode) {
@@ -407,16 +425,16 @@ func (mw *MetaWrapper) InodeGet_ll(inode uint64) (*proto.InodeInfo, error) {
 }
 
 // Just like InodeGet but without retry
-func (mw *MetaWrapper) doInodeGet(inode uint64) (*proto.InodeInfo, error) {
+func (mw *MetaWrapper) doInodeGet(ctx context.Context, inode uint64) (*proto.InodeInfo, error) {
 	mp := mw.getPartitionByInode(inode)
 	if mp == nil {
 		log.LogErrorf("InodeGet_ll: No such partition, ino(%v)", inode)
 		return nil, syscall.ENOENT
 	}
 
-	status, info, err := mw.iget(mp, inode, mw.VerReadSeq)
+	status, info, err := mw.igetContext(ctx, mp, inode, mw.VerReadSeq)
 	if err != nil || status != statusOK {
-		return nil, statusToErrno(status)
+		return nil, contextErrno(ctx, status)
 	}
 	log.LogDebugf("doInodeGet: info(%v)", info)
 	return info, nil
@@ -760,7 +778,9 @@ func (mw *MetaWrapper) Delete_ll_EX(parentID uint64, name string, isDir bool, ve
 
 	log.LogDebugf("action[Delete_ll] parentID %v name %v verSeq %v", parentID, name, verSeq)
 
-	status, inode, _, err = mw.ddelete(parentMP, parentID, name, inodeCreateTime, verSeq, fullPath)
+	// files are moved into trash by the meta node if it is enabled, directories are deleted directly
+	trash := !isDir && verSeq == 0 && mw.volTrashInterval > 0
+	status, inode, _, trashed, err := mw.ddelete(parentMP, parentID, name, inodeCreateTime, verSeq, fullPath, trash)
 	if err != nil || status != statusOK {
 		if status == statusNoent {
 			log.LogDebugf("action[Delete_ll] parentID %v name %v verSeq %v", parentID, name, verSeq)
@@ -769,6 +789,11 @@ func (mw *MetaWrapper) Delete_ll_EX(parentID uint64, name string, isDir bool, ve
 		log.LogDebugf("action[Delete_ll] parentID %v name %v verSeq %v", parentID, name, verSeq)
 		return nil, statusToErrno(status)
 	}
+	if trashed {
+		// the inode is unlinked by the meta node once the item in trash expires
+		log.LogDebugf("action[Delete_ll] parentID %v name %v ino %v moved into trash", parentID, name, inode)
+		return nil, nil
+	}
 	log.LogDebugf("action[Delete_ll] parentID %v name %v verSeq %v", parentID, name, verSeq)
 	// dentry is deleted successfully but inode is not, still returns success.
 	mp = mw.getPartitionByInode(inode)
@@ -796,6 +821,124 @@ func (mw *MetaWrapper) Delete_ll_EX(parentID uint64, name string, isDir bool, ve
 	return info, nil
 }
 
+// ListTrash_ll lists the files in trash which were deleted from the directory parentID,
+// at most limit items after marker, it returns the marker of the next page.
+func (mw *MetaWrapper) ListTrash_ll(parentID uint64, marker string, limit int) ([]*proto.TrashItem, string, error) {
+	mp := mw.getPartitionByInode(parentID)
+	if mp == nil {
+		log.LogErrorf("ListTrash_ll: No parent partition, parentID(%v)", parentID)
+		return nil, "", syscall.ENOENT
+	}
+	status, resp, err := mw.listTrash(mp, parentID, marker, limit)
+	if err != nil || status != statusOK {
+		return nil, "", statusErrToErrno(status, err)
+	}
+	return resp.Items, resp.NextMarker, nil
+}
+
+// RestoreTrash_ll moves the file in trash back to its directory, with its original name
+// if newName is empty. It returns EEXIST if the name is taken.
+func (mw *MetaWrapper) RestoreTrash_ll(item *proto.TrashItem, newName string) error {
+	mp := mw.getPartitionByInode(item.ParentID)
+	if mp == nil {
+		log.LogErrorf("RestoreTrash_ll: No parent partition, parentID(%v)", item.ParentID)
+		return syscall.ENOENT
+	}
+	status, err := mw.restoreTrash(mp, item.Key, newName)
+	if err != nil || status != statusOK {
+		return statusErrToErrno(status, err)
+	}
+	return nil
+}
+
+// BatchDelete_ll deletes files of the same parent directory in batch, the dentries are deleted
+// by one request, and the inodes are unlinked and evicted by one request of each meta partition.
+// It returns error of each dentry in the same order, nil means deleted, ENOENT means the dentry
+// is not found with the inode.
+// Directories, transaction, snapshot, trash and delete lock of volume are not supported, returns ENOTSUP.
+func (mw *MetaWrapper) BatchDelete_ll(parentID uint64, dentries []proto.Dentry, fullPaths []string) ([]error, error) {
+	if mw.enableTx(proto.TxOpMaskRemove) || mw.LastVerSeq != 0 || mw.volDeleteLockTime > 0 ||
+		mw.volTrashInterval > 0 {
+		return nil, syscall.ENOTSUP
+	}
+	for _, den := range dentries {
+		if proto.IsDir(den.Type) {
+			return nil, syscall.ENOTSUP
+		}
+	}
+	if len(dentries) == 0 {
+		return nil, nil
+	}
+
+	parentMP := mw.getPartitionByInode(parentID)
+	if parentMP == nil {
+		log.LogErrorf("BatchDelete_ll: No parent partition, parentID(%v)", parentID)
+		return nil, syscall.ENOENT
+	}
+	status, resp, err := mw.ddeletes(parentMP, parentID, dentries, fullPaths)
+	if err != nil || status != statusOK {
+		return nil, statusErrToErrno(status, err)
+	}
+	if len(resp.Items) != len(dentries) {
+		log.LogErrorf("BatchDelete_ll: mismatched items, parentID(%v) dentries(%v) items(%v)",
+			parentID, len(dentries), len(resp.Items))
+		return nil, syscall.EIO
+	}
+
+	type inodeBatch struct {
+		mp        *MetaPartition
+		inodes    []uint64
+		fullPaths []string
+	}
+	batches := make(map[uint64]*inodeBatch)
+	errs := make([]error, len(dentries))
+	for idx, item := range resp.Items {
+		if status = parseStatus(item.Status); status != statusOK {
+			errs[idx] = statusToErrno(status)
+			continue
+		}
+		inode := dentries[idx].Inode
+		mp := mw.getPartitionByInode(inode)
+		if mp == nil {
+			// dentry is deleted successfully but inode is not, still returns success.
+			log.LogErrorf("BatchDelete_ll: No inode partition, parentID(%v) ino(%v)", parentID, inode)
+			continue
+		}
+		batch, ok := batches[mp.PartitionID]
+		if !ok {
+			batch = &inodeBatch{mp: mp}
+			batches[mp.PartitionID] = batch
+		}
+		batch.inodes = append(batch.inodes, inode)
+		if len(fullPaths) > idx {
+			batch.fullPaths = append(batch.fullPaths, fullPaths[idx])
+		}
+	}
+
+	var files, size int64
+	for _, batch := range batches {
+		status, unlinkResp, err := mw.iunlinks(batch.mp, batch.inodes, batch.fullPaths)
+		if err != nil || status != statusOK {
+			log.LogWarnf("BatchDelete_ll: unlink inodes failed, mp(%v) inodes(%v) err(%v)", batch.mp, batch.inodes, err)
+			continue
+		}
+		for _, item := range unlinkResp.Items {
+			if item.Status == proto.OpOk && item.Info != nil {
+				files++
+				size += int64(item.Info.Size)
+			}
+		}
+		if _, err = mw.ievicts(batch.mp, batch.inodes, batch.fullPaths); err != nil {
+			log.LogWarnf("BatchDelete_ll: evict inodes failed, mp(%v) inodes(%v) err(%v)", batch.mp, batch.inodes, err)
+		}
+	}
+
+	if mw.EnableSummary && files > 0 {
+		go mw.UpdateSummary_ll(parentID, -files, 0, -size)
+	}
+	return errs, nil
+}
+
 func isObjectLocked(mw *MetaWrapper, inode uint64, name string) error {
 	xattrInfo, err := mw.XAttrGet_ll(inode, "oss:lock")
 	if err != nil {
@@ -1053,6 +1196,9 @@ func (mw *MetaWrapper) txRename_ll(srcParentID uint64, srcName string, dstParent
 			job = func() {
 				mw.UpdateSummary_ll(srcParentID, -1, 0, -int64(srcInodeInfo.Size))
 				mw.UpdateSummary_ll(dstParentID, 0, 0, int64(sizeInc))
+				if proto.IsDir(srcMode) {
+					mw.moveRecursiveSummary(srcInode, srcParentID, dstParentID)
+				}
 			}
 			tx.SetOnCommit(job)
 			return
@@ -1069,6 +1215,7 @@ func (mw *MetaWrapper) txRename_ll(srcParentID uint64, srcName string, dstParent
 				job = func() {
 					mw.UpdateSummary_ll(srcParentID, 0, -1, 0)
 					mw.UpdateSummary_ll(dstParentID, 0, 1, 0)
+					mw.moveRecursiveSummary(srcInode, srcParentID, dstParentID)
 				}
 			}
 			tx.SetOnCommit(job)
@@ -1184,7 +1331,7 @@ func (mw *MetaWrapper) rename_ll(srcParentID uint64, srcName string, dstParentID
 	var denVer uint64
 	// delete dentry from src parent
 
-	status, _, denVer, err = mw.ddelete(srcParentMP, srcParentID, srcName, 0, lastVerSeq, srcFullPath)
+	status, _, denVer, _, err = mw.ddelete(srcParentMP, srcParentID, srcName, 0, lastVerSeq, srcFullPath, false)
 
 	if err != nil {
 		log.LogErrorf("mw.ddelete(srcParentMP, srcParentID, %s) failed.", srcName)
@@ -1195,7 +1342,7 @@ func (mw *MetaWrapper) rename_ll(srcParentID uint64, srcName string, dstParentID
 			e   error
 		)
 		if oldInode == 0 {
-			sts, inode, denVer, e = mw.ddelete(dstParentMP, dstParentID, dstName, 0, lastVerSeq, dstFullPath)
+			sts, inode, denVer, _, e = mw.ddelete(dstParentMP, dstParentID, dstName, 0, lastVerSeq, dstFullPath, false)
 		} else {
 			sts, denVer, e = mw.dupdate(dstParentMP, dstParentID, dstName, oldInode, dstFullPath)
 		}
@@ -1220,6 +1367,9 @@ func (mw *MetaWrapper) rename_ll(srcParentID uint64, srcName string, dstParentID
 			go func() {
 				mw.UpdateSummary_ll(srcParentID, -1, 0, -int64(srcInodeInfo.Size))
 				mw.UpdateSummary_ll(dstParentID, 0, 0, int64(sizeInc))
+				if proto.IsDir(mode) {
+					mw.moveRecursiveSummary(inode, srcParentID, dstParentID)
+				}
 			}()
 		}
 	} else {
@@ -1236,6 +1386,7 @@ func (mw *MetaWrapper) rename_ll(srcParentID uint64, srcName string, dstParentID
 				go func() {
 					mw.UpdateSummary_ll(srcParentID, 0, -1, 0)
 					mw.UpdateSummary_ll(dstParentID, 0, 1, 0)
+					mw.moveRecursiveSummary(inode, srcParentID, dstParentID)
 				}()
 			}
 		}
@@ -1310,15 +1461,20 @@ func (mw *MetaWrapper) ReadDirLimitForSnapShotClean(parentID uint64, from string
 
 // Read limit count dentries with parentID, start from string
 func (mw *MetaWrapper) ReadDirLimit_ll(parentID uint64, from string, limit uint64) ([]proto.Dentry, error) {
+	return mw.ReadDirLimitContext_ll(context.Background(), parentID, from, limit)
+}
+
+// ReadDirLimitContext_ll is ReadDirLimit_ll which returns EINTR once ctx is done.
+func (mw *MetaWrapper) ReadDirLimitContext_ll(ctx context.Context, parentID uint64, from string, limit uint64) ([]proto.Dentry, error) {
 	log.LogDebugf("action[ReadDirLimit_ll] parentID %v from %v limit %v", parentID, from, limit)
 	parentMP := mw.getPartitionByInode(parentID)
 	if parentMP == nil {
 		return nil, syscall.ENOENT
 	}
 
-	status, children, err := mw.readDirLimit(parentMP, parentID, from, limit, mw.VerReadSeq, 0)
+	status, children, err := mw.readDirLimitContext(ctx, parentMP, parentID, from, limit, mw.VerReadSeq, 0)
 	if err != nil || status != statusOK {
-		return nil, statusToErrno(status)
+		return nil, contextErrno(ctx, status)
 	}
 	return children, nil
 }
@@ -1446,6 +1602,30 @@ func (mw *MetaWrapper) AppendObjExtentKeys(inode uint64, eks []proto.ObjExtentKe
 	return nil
 }
 
+// InodeTransition replaces the extents of the inode with the obj extents of the data copied to the blobstore,
+// it fails with EINVAL if the inode is modified since the size and generation are read.
+func (mw *MetaWrapper) InodeTransition(inode, size, gen uint64, eks []proto.ObjExtentKey, storageClass string) error {
+	mp := mw.getPartitionByInode(inode)
+	if mp == nil {
+		return syscall.ENOENT
+	}
+
+	req := &proto.InodeTransitionRequest{
+		VolName:      mw.volname,
+		PartitionID:  mp.PartitionID,
+		Inode:        inode,
+		Size:         size,
+		Generation:   gen,
+		ObjExtents:   eks,
+		StorageClass: storageClass,
+	}
+	status, err := mw.inodeTransition(mp, req)
+	if err != nil || status != statusOK {
+		return statusErrToErrno(status, err)
+	}
+	return nil
+}
+
 func (mw *MetaWrapper) GetExtents(inode uint64) (gen uint64, size uint64, extents []proto.ExtentKey, err error) {
 	mp := mw.getPartitionByInode(inode)
 	if mp == nil {
@@ -1684,6 +1864,11 @@ func (mw *MetaWrapper) Setattr(inode uint64, valid, mode, uid, gid uint32, atime
 	return nil
 }
 
+// SetModifyTime sets the modify time of the inode, which increases the generation of its extents.
+func (mw *MetaWrapper) SetModifyTime(inode uint64, mtime int64) error {
+	return mw.Setattr(inode, proto.AttrModifyTime, 0, 0, 0, 0, mtime)
+}
+
 func (mw *MetaWrapper) InodeCreate_ll(parentID uint64, mode, uid, gid uint32, target []byte, quotaIds []uint64, fullPath string) (*proto.InodeInfo, error) {
 	var (
 		status       int
@@ -1925,6 +2110,18 @@ func (mw *MetaWrapper) RemoveMultipart_ll(path, multipartID string) (err error)
 	return
 }
 
+// AbortExpiredMultipart_ll releases the parts of the expired multipart upload and removes the upload.
+func (mw *MetaWrapper) AbortExpiredMultipart_ll(info *proto.ExpiredMultipartInfo) (err error) {
+	// the part inodes are released once they are unlinked
+	for _, ino := range info.Inodes {
+		if _, err = mw.InodeUnlink_ll(ino, info.Path); err != nil {
+			log.LogWarnf("AbortExpiredMultipart_ll: unlink part fail: volume(%v) path(%v) multipartID(%v) inode(%v) err(%v)",
+				mw.volname, info.Path, info.MultipartId, ino, err)
+		}
+	}
+	return mw.RemoveMultipart_ll(info.Path, info.MultipartId)
+}
+
 func (mw *MetaWrapper) broadcastGetMultipart(path, multipartId string) (info *proto.MultipartInfo, mpID uint64, err error) {
 	log.LogInfof("broadcastGetMultipart: find meta partition broadcast multipartId(%v)", multipartId)
 	partitions := mw.partitions
@@ -2108,19 +2305,96 @@ func (mw *MetaWrapper) UpdateSummary_ll(parentIno uint64, filesInc int64, dirsIn
 	if filesInc == 0 && dirsInc == 0 && bytesInc == 0 {
 		return
 	}
+	mw.updateSummary(parentIno, filesInc, dirsInc, bytesInc)
+	mw.updateRecursiveSummary(parentIno, filesInc, dirsInc, bytesInc)
+}
+
+// updateSummary adds the increments to the summary of the direct children of the directory
+func (mw *MetaWrapper) updateSummary(parentIno uint64, filesInc int64, dirsInc int64, bytesInc int64) {
 	mp := mw.getPartitionByInode(parentIno)
 	if mp == nil {
 		log.LogErrorf("UpdateSummary_ll: no such partition, inode(%v)", parentIno)
 		return
 	}
 	for cnt := 0; cnt < UpdateSummaryRetry; cnt++ {
-		err := mw.updateXAttrs(mp, parentIno, filesInc, dirsInc, bytesInc)
+		err := mw.updateXAttrs(mp, parentIno, SummaryKey, filesInc, dirsInc, bytesInc)
 		if err == nil {
 			return
 		}
 	}
 }
 
+// updateRecursiveSummary adds the increments to the recursive summary of the directory and its ancestors,
+// which are found by the parent recorded on each directory. Ancestors above a directory without the
+// parent recorded, such as one created before summary was enabled, are not updated.
+func (mw *MetaWrapper) updateRecursiveSummary(ino uint64, filesInc int64, dirsInc int64, bytesInc int64) {
+	if filesInc == 0 && dirsInc == 0 && bytesInc == 0 {
+		return
+	}
+	for depth := 0; ino != 0 && depth < MaxSummaryDepth; depth++ {
+		mp := mw.getPartitionByInode(ino)
+		if mp == nil {
+			log.LogErrorf("updateRecursiveSummary: no such partition, inode(%v)", ino)
+			return
+		}
+		for cnt := 0; cnt < UpdateSummaryRetry; cnt++ {
+			err := mw.updateXAttrs(mp, ino, RecursiveSummaryKey, filesInc, dirsInc, bytesInc)
+			if err == nil {
+				break
+			}
+		}
+		if ino == proto.RootIno {
+			return
+		}
+		ino = mw.getSummaryParent(ino)
+	}
+}
+
+// moveRecursiveSummary moves the recursive summary of the renamed directory from the ancestors of
+// srcParentID to the ones of dstParentID, and records dstParentID as its parent.
+func (mw *MetaWrapper) moveRecursiveSummary(ino uint64, srcParentID uint64, dstParentID uint64) {
+	if srcParentID == dstParentID {
+		return
+	}
+	mw.setSummaryParent(ino, dstParentID)
+	summaryInfo, err := mw.GetDirStat_ll(ino)
+	if err != nil {
+		log.LogErrorf("moveRecursiveSummary: get recursive summary of inode(%v) failed: %v", ino, err)
+		return
+	}
+	mw.updateRecursiveSummary(srcParentID, -summaryInfo.Files, -summaryInfo.Subdirs, -summaryInfo.Fbytes)
+	mw.updateRecursiveSummary(dstParentID, summaryInfo.Files, summaryInfo.Subdirs, summaryInfo.Fbytes)
+}
+
+// getSummaryParent returns the parent recorded on the directory, 0 means not recorded
+func (mw *MetaWrapper) getSummaryParent(ino uint64) uint64 {
+	xattrInfo, err := mw.XAttrGet_ll(ino, SummaryParentKey)
+	if err != nil {
+		log.LogErrorf("getSummaryParent: get parent of inode(%v) failed: %v", ino, err)
+		return 0
+	}
+	parentIno, _ := strconv.ParseUint(xattrInfo.XAttrs[SummaryParentKey], 10, 64)
+	return parentIno
+}
+
+func (mw *MetaWrapper) setSummaryParent(ino uint64, parentIno uint64) {
+	if err := mw.XAttrSet_ll(ino, []byte(SummaryParentKey), []byte(strconv.FormatUint(parentIno, 10))); err != nil {
+		log.LogErrorf("setSummaryParent: set parent(%v) of inode(%v) failed: %v", parentIno, ino, err)
+	}
+}
+
+// parseSummaryInfo parses the summary xattr value of files, subdirs and bytes separated by comma
+func parseSummaryInfo(value string) (summaryInfo SummaryInfo) {
+	summaryList := strings.Split(value, ",")
+	if len(summaryList) < 3 {
+		return
+	}
+	summaryInfo.Files, _ = strconv.ParseInt(summaryList[0], 10, 64)
+	summaryInfo.Subdirs, _ = strconv.ParseInt(summaryList[1], 10, 64)
+	summaryInfo.Fbytes, _ = strconv.ParseInt(summaryList[2], 10, 64)
+	return
+}
+
 func (mw *MetaWrapper) ReadDirOnly_ll(parentID uint64) ([]proto.Dentry, error) {
 	parentMP := mw.getPartitionByInode(parentID)
 	if parentMP == nil {
@@ -2326,9 +2600,53 @@ func (mw *MetaWrapper) RefreshSummary_ll(parentIno uint64, goroutineNum int32) e
 	for err := range errch {
 		return err
 	}
+
+	oldSummaryInfo, err := mw.GetDirStat_ll(parentIno)
+	if err != nil {
+		return err
+	}
+	newSummaryInfo, err := mw.refreshRecursiveSummary(parentIno)
+	if err != nil {
+		return err
+	}
+	if parentIno != proto.RootIno {
+		mw.updateRecursiveSummary(mw.getSummaryParent(parentIno),
+			newSummaryInfo.Files-oldSummaryInfo.Files,
+			newSummaryInfo.Subdirs-oldSummaryInfo.Subdirs,
+			newSummaryInfo.Fbytes-oldSummaryInfo.Fbytes,
+		)
+	}
 	return nil
 }
 
+// refreshRecursiveSummary rebuilds the recursive summary of the directory from the summaries of it and its
+// subdirectories, and records the parent of each subdirectory.
+func (mw *MetaWrapper) refreshRecursiveSummary(parentIno uint64) (summaryInfo SummaryInfo, err error) {
+	xattrInfo, err := mw.XAttrGet_ll(parentIno, SummaryKey)
+	if err != nil {
+		return
+	}
+	summaryInfo = parseSummaryInfo(xattrInfo.XAttrs[SummaryKey])
+	children, err := mw.ReadDirOnly_ll(parentIno)
+	if err != nil {
+		return
+	}
+	for _, dentry := range children {
+		mw.setSummaryParent(dentry.Inode, parentIno)
+		var subdirSummaryInfo SummaryInfo
+		if subdirSummaryInfo, err = mw.refreshRecursiveSummary(dentry.Inode); err != nil {
+			return
+		}
+		summaryInfo.Files += subdirSummaryInfo.Files
+		summaryInfo.Subdirs += subdirSummaryInfo.Subdirs
+		summaryInfo.Fbytes += subdirSummaryInfo.Fbytes
+	}
+	value := strconv.FormatInt(summaryInfo.Files, 10) + "," + strconv.FormatInt(summaryInfo.Subdirs, 10) + "," +
+		strconv.FormatInt(summaryInfo.Fbytes, 10)
+	err = mw.XAttrSet_ll(parentIno, []byte(RecursiveSummaryKey), []byte(value))
+	return
+}
+
 func (mw *MetaWrapper) refreshSummary(parentIno uint64, errCh chan<- error, wg *sync.WaitGroup, currentGoroutineNum *int32, newGoroutine bool, goroutineNum int32) {
 	defer func() {
 		if newGoroutine {
@@ -2378,7 +2696,8 @@ func (mw *MetaWrapper) refreshSummary(parentIno uint64, errCh chan<- error, wg *
 			newSummaryInfo.Fbytes += int64(fileInfo.Size)
 		}
 	}
-	go mw.UpdateSummary_ll(
+	// the recursive summary is rebuilt after all summaries are refreshed
+	mw.updateSummary(
 		parentIno,
 		newSummaryInfo.Files-oldSummaryInfo.Files,
 		newSummaryInfo.Subdirs-oldSummaryInfo.Subdirs,
@@ -2475,6 +2794,65 @@ func (mw *MetaWrapper) GetInodeQuota_ll(inode uint64) (quotaInfos map[uint32]*pr
 	return
 }
 
+// GetDirStat_ll returns the files, subdirectories and bytes under the directory recursively. They are
+// counted incrementally in the recursive summary of the directory when summary is enabled, so the
+// directory tree is not walked.
+func (mw *MetaWrapper) GetDirStat_ll(ino uint64) (summaryInfo SummaryInfo, err error) {
+	xattrInfo, err := mw.XAttrGet_ll(ino, RecursiveSummaryKey)
+	if err != nil {
+		return
+	}
+	return parseSummaryInfo(xattrInfo.XAttrs[RecursiveSummaryKey]), nil
+}
+
+// SearchXAttr_ll looks up inodes by a user xattr through the xattr index of meta partitions, the
+// value is matched exactly or as a prefix. At most limit items ordered by value and inode are returned,
+// truncated is set if there are more.
+func (mw *MetaWrapper) SearchXAttr_ll(key, value string, prefix bool, limit int) (items []*proto.XAttrSearchItem, truncated bool, err error) {
+	mw.RLock()
+	partitions := make([]*MetaPartition, 0, len(mw.partitions))
+	for _, mp := range mw.partitions {
+		partitions = append(partitions, mp)
+	}
+	mw.RUnlock()
+
+	var (
+		wg       sync.WaitGroup
+		resultMu sync.Mutex
+	)
+	items = make([]*proto.XAttrSearchItem, 0)
+	for _, mp := range partitions {
+		wg.Add(1)
+		go func(mp *MetaPartition) {
+			defer wg.Done()
+			resp, searchErr := mw.searchXAttr(mp, key, value, prefix, limit)
+			resultMu.Lock()
+			defer resultMu.Unlock()
+			if searchErr != nil {
+				log.LogErrorf("SearchXAttr_ll: search key(%v) value(%v) in partition[%v] failed: %v", key, value, mp.PartitionID, searchErr)
+				err = searchErr
+				return
+			}
+			items = append(items, resp.Items...)
+			truncated = truncated || resp.Truncated
+		}(mp)
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, false, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Value != items[j].Value {
+			return items[i].Value < items[j].Value
+		}
+		return items[i].Inode < items[j].Inode
+	})
+	if limit > 0 && len(items) > limit {
+		items, truncated = items[:limit], true
+	}
+	return
+}
+
 func (mw *MetaWrapper) ApplyQuota_ll(parentIno uint64, quotaId uint32, maxConcurrencyInode uint64) (numInodes uint64, err error) {
 	inodes := make([]uint64, 0, maxConcurrencyInode)
 	var curInodeCount uint64
@@ -0,0 +1,162 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package meta
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const defaultChangeEventsLimit = 1000
+
+// VolChangeEvents is a batch of change events of the volume.
+// Cursor is passed to the next read to resume after the batch, it keeps
+// the seq of every meta partition and is valid across leader changes and
+// restarts of meta nodes. Truncated is the partitions whose events after
+// the previous cursor are lost, the consumer should resync them with full
+// scan.
+type VolChangeEvents struct {
+	Events    []proto.ChangeEvent
+	Cursor    string
+	Truncated []uint64
+}
+
+func decodeChangeCursor(cursor string) (seqs map[uint64]uint64, err error) {
+	seqs = make(map[uint64]uint64)
+	if cursor == "" {
+		return
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &seqs)
+	return
+}
+
+func encodeChangeCursor(seqs map[uint64]uint64) string {
+	data, _ := json.Marshal(seqs)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// GetChangeEvents_ll reads at most limit change events of the volume after the cursor, an empty
+// cursor reads from the beginning. Events of all meta partitions are merged by timestamp, events
+// of one partition are always in seq order and events of one seq are never split.
+func (mw *MetaWrapper) GetChangeEvents_ll(cursor string, limit int) (result *VolChangeEvents, err error) {
+	seqs, err := decodeChangeCursor(cursor)
+	if err != nil {
+		log.LogErrorf("GetChangeEvents_ll: invalid cursor(%v) err(%v)", cursor, err)
+		return nil, err
+	}
+	if limit <= 0 || limit > defaultChangeEventsLimit {
+		limit = defaultChangeEventsLimit
+	}
+
+	mw.RLock()
+	partitions := make([]*MetaPartition, 0, len(mw.partitions))
+	for _, mp := range mw.partitions {
+		partitions = append(partitions, mp)
+	}
+	mw.RUnlock()
+
+	var (
+		wg       sync.WaitGroup
+		resultMu sync.Mutex
+	)
+	results := make([]*proto.ChangeEvents, 0, len(partitions))
+	for _, mp := range partitions {
+		wg.Add(1)
+		go func(mp *MetaPartition, from uint64) {
+			defer wg.Done()
+			resp, getErr := mw.getChangeEvents(mp, from, limit)
+			resultMu.Lock()
+			defer resultMu.Unlock()
+			if getErr != nil {
+				log.LogErrorf("GetChangeEvents_ll: partition[%v] from(%v) failed: %v", mp.PartitionID, from, getErr)
+				err = getErr
+				return
+			}
+			resp.PartitionID = mp.PartitionID
+			results = append(results, resp)
+		}(mp, seqs[mp.PartitionID])
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	result = &VolChangeEvents{Truncated: make([]uint64, 0)}
+	for _, resp := range results {
+		if resp.Truncated {
+			result.Truncated = append(result.Truncated, resp.PartitionID)
+		}
+	}
+	sort.Slice(result.Truncated, func(i, j int) bool { return result.Truncated[i] < result.Truncated[j] })
+	result.Events = mergeChangeEvents(results, seqs, limit)
+	result.Cursor = encodeChangeCursor(seqs)
+	return
+}
+
+// mergeChangeEvents merges events of partitions by the timestamp of each seq, and moves seqs to the
+// last merged seq of every partition. Merging stops at limit, or when a partition which may have
+// more events runs out, since its following events may be earlier than the ones of others.
+func mergeChangeEvents(results []*proto.ChangeEvents, seqs map[uint64]uint64, limit int) (events []proto.ChangeEvent) {
+	events = make([]proto.ChangeEvent, 0)
+	heads := make([]int, len(results))
+	for {
+		pick := -1
+		for i, resp := range results {
+			if heads[i] >= len(resp.Events) {
+				if len(resp.Events) >= limit {
+					pick = -1
+					break
+				}
+				continue
+			}
+			if pick < 0 {
+				pick = i
+				continue
+			}
+			head, picked := resp.Events[heads[i]], results[pick].Events[heads[pick]]
+			if head.Timestamp < picked.Timestamp ||
+				(head.Timestamp == picked.Timestamp && resp.PartitionID < results[pick].PartitionID) {
+				pick = i
+			}
+		}
+		if pick < 0 || len(events) >= limit {
+			break
+		}
+		resp := results[pick]
+		seq := resp.Events[heads[pick]].Seq
+		for ; heads[pick] < len(resp.Events) && resp.Events[heads[pick]].Seq == seq; heads[pick]++ {
+			event := resp.Events[heads[pick]]
+			event.PartitionID = resp.PartitionID
+			events = append(events, event)
+		}
+		seqs[resp.PartitionID] = seq
+	}
+	for i, resp := range results {
+		if heads[i] >= len(resp.Events) {
+			// the lost events are skipped if nothing is read
+			seqs[resp.PartitionID] = resp.NextSeq
+		}
+	}
+	return
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeChangeEvents(t *testing.T) {
+	seqs, err := decodeChangeCursor("")
+	require.NoError(t, err)
+	require.Empty(t, seqs)
+	_, err = decodeChangeCursor("not a cursor")
+	require.Error(t, err)
+
+	event := func(seq uint64, ts int64) proto.ChangeEvent {
+		return proto.ChangeEvent{Seq: seq, Timestamp: ts}
+	}
+	results := []*proto.ChangeEvents{
+		{PartitionID: 1, Events: []proto.ChangeEvent{event(5, 100), event(5, 101), event(8, 103)}, NextSeq: 8},
+		{PartitionID: 2, Events: []proto.ChangeEvent{event(3, 100), event(4, 102)}, NextSeq: 4},
+		// the lost events are skipped
+		{PartitionID: 3, Events: []proto.ChangeEvent{}, NextSeq: 20, Truncated: true},
+	}
+	seqs[1], seqs[2], seqs[3] = 4, 2, 10
+	events := mergeChangeEvents(results, seqs, 10)
+	require.Len(t, events, 5)
+	expected := []struct{ pid, seq uint64 }{{1, 5}, {1, 5}, {2, 3}, {2, 4}, {1, 8}}
+	for i, e := range expected {
+		require.Equal(t, e.pid, events[i].PartitionID)
+		require.Equal(t, e.seq, events[i].Seq)
+	}
+	require.Equal(t, map[uint64]uint64{1: 8, 2: 4, 3: 20}, seqs)
+
+	// events of one seq are never split by limit
+	seqs = map[uint64]uint64{1: 4, 2: 2}
+	events = mergeChangeEvents(results[:2], seqs, 1)
+	require.Len(t, events, 2)
+	require.Equal(t, map[uint64]uint64{1: 5, 2: 2}, seqs)
+
+	// stops when a partition with more events runs out
+	results = []*proto.ChangeEvents{
+		{PartitionID: 1, Events: []proto.ChangeEvent{event(5, 100), event(6, 200)}, NextSeq: 6},
+		{PartitionID: 2, Events: []proto.ChangeEvent{event(3, 150)}, NextSeq: 3},
+	}
+	seqs = map[uint64]uint64{}
+	events = mergeChangeEvents(results, seqs, 1)
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(5), events[0].Seq)
+	require.Equal(t, map[uint64]uint64{1: 5}, seqs)
+	events = mergeChangeEvents(results, seqs, 2)
+	require.Len(t, events, 2)
+	require.Equal(t, uint64(3), events[1].Seq)
+	require.Equal(t, map[uint64]uint64{1: 5, 2: 3}, seqs)
+
+	cursor := encodeChangeCursor(seqs)
+	decoded, err := decodeChangeCursor(cursor)
+	require.NoError(t, err)
+	require.Equal(t, seqs, decoded)
+}
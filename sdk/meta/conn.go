@@ -15,19 +15,24 @@
 package meta
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"syscall"
 	"time"
 
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/errors"
+	"github.com/cubefs/cubefs/util/exporter"
 	"github.com/cubefs/cubefs/util/log"
 )
 
 const (
 	SendRetryLimit    = 200 // times
 	SendRetryInterval = 100 // ms
+
+	MaxThrottledRetryInterval = 1000 // ms
 )
 
 type MetaConn struct {
@@ -57,6 +62,11 @@ func (mw *MetaWrapper) putConn(mc *MetaConn, err error) {
 }
 
 func (mw *MetaWrapper) sendToMetaPartition(mp *MetaPartition, req *proto.Packet) (*proto.Packet, error) {
+	return mw.sendToMetaPartitionContext(context.Background(), mp, req)
+}
+
+// sendToMetaPartitionContext stops retrying and interrupts the request in flight once ctx is done.
+func (mw *MetaWrapper) sendToMetaPartitionContext(ctx context.Context, mp *MetaPartition, req *proto.Packet) (*proto.Packet, error) {
 	var (
 		resp    *proto.Packet
 		err     error
@@ -96,7 +106,10 @@ func (mw *MetaWrapper) sendToMetaPartition(mp *MetaPartition, req *proto.Packet)
 	}
 
 sendWithList:
-	resp, err = mc.send(req, lastSeq)
+	resp, err = mc.send(ctx, req, lastSeq)
+	if err == nil && resp.ResultCode == proto.OpThrottledErr {
+		resp, err = mw.sendThrottled(ctx, mc, req, lastSeq, resp, sendTimeLimit)
+	}
 	if err == nil && !resp.ShouldRetry() && !resp.ShouldRetryWithVersionList() {
 		mw.putConn(mc, err)
 		goto out
@@ -115,8 +128,19 @@ sendWithList:
 	}
 	mw.putConn(mc, err)
 retry:
+	// leader of the partition may be changed, refresh partition views while retrying
+	mw.triggerForceUpdate(mp.PartitionID)
 	start = time.Now()
 	for i := 0; i <= SendRetryLimit; i++ {
+		if ctx.Err() != nil {
+			log.LogWarnf("sendToMetaPartition: req(%v) mp(%v) cancelled, err(%v)", req, mp, ctx.Err())
+			break
+		}
+		retryMetric := exporter.NewCounter("metaSendRetry")
+		retryMetric.AddWithLabels(1, map[string]string{exporter.Vol: mw.volname})
+		if latest := mw.getPartitionByID(mp.PartitionID); latest != nil {
+			mp = latest
+		}
 		for j, addr = range mp.Members {
 			mc, err = mw.getConn(mp.PartitionID, addr)
 			errs[j] = err
@@ -124,7 +148,10 @@ retry:
 				log.LogWarnf("sendToMetaPartition: getConn failed and continue to retry, req(%v) mp(%v) addr(%v) err(%v)", req, mp, addr, err)
 				continue
 			}
-			resp, err = mc.send(req, lastSeq)
+			resp, err = mc.send(ctx, req, lastSeq)
+			if err == nil && resp.ResultCode == proto.OpThrottledErr {
+				resp, err = mw.sendThrottled(ctx, mc, req, lastSeq, resp, sendTimeLimit)
+			}
 			mw.putConn(mc, err)
 			if err == nil && !resp.ShouldRetry() {
 				goto out
@@ -143,7 +170,10 @@ retry:
 		sendRetryInterval := time.Duration(SendRetryInterval+i*delta) * time.Millisecond
 		log.LogWarnf("sendToMetaPartition: req(%v) mp(%v) retry in (%v), retry_iteration (%v), retry_totalTime (%v)", req, mp,
 			sendRetryInterval, i+1, time.Since(start))
-		time.Sleep(sendRetryInterval)
+		select {
+		case <-ctx.Done():
+		case <-time.After(sendRetryInterval):
+		}
 	}
 
 out:
@@ -157,10 +187,41 @@ out:
 	return resp, nil
 }
 
-func (mc *MetaConn) send(req *proto.Packet, verSeq uint64) (resp *proto.Packet, err error) {
+// sendThrottled resends the request to the same meta node with backoff while it is throttled,
+// trying the other replicas instead would multiply the qps limit of the client.
+func (mw *MetaWrapper) sendThrottled(ctx context.Context, mc *MetaConn, req *proto.Packet, lastSeq uint64,
+	resp *proto.Packet, sendTimeLimit int) (*proto.Packet, error) {
+	var err error
+	deadline := time.Now().Add(time.Duration(sendTimeLimit) * time.Millisecond)
+	for i := 1; resp.ResultCode == proto.OpThrottledErr; i++ {
+		interval := time.Duration(util.Min(SendRetryInterval*i, MaxThrottledRetryInterval)) * time.Millisecond
+		if time.Now().Add(interval).After(deadline) {
+			break
+		}
+		log.LogWarnf("sendThrottled: req(%v) mc(%v) throttled, retry in (%v)", req, mc, interval)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		if resp, err = mc.send(ctx, req, lastSeq); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (mc *MetaConn) send(ctx context.Context, req *proto.Packet, verSeq uint64) (resp *proto.Packet, err error) {
 	req.ExtentType |= proto.MultiVersionFlag
 	req.VerSeq = verSeq
 
+	stop := util.CloseOnDone(ctx, mc.conn)
+	defer func() {
+		if stop() {
+			resp, err = nil, ctx.Err()
+		}
+	}()
+
 	err = req.WriteToConn(mc.conn)
 	if err != nil {
 		return nil, errors.Trace(err, "Failed to write to conn, req(%v)", req)
@@ -0,0 +1,88 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// newThrottledServer returns a meta node which throttles the first n requests
+func newThrottledServer(t *testing.T, n int) *net.TCPConn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for i := 0; ; i++ {
+			p := proto.NewPacket()
+			if err = p.ReadFromConnWithVer(conn, proto.NoReadDeadlineTime); err != nil {
+				return
+			}
+			if i < n {
+				p.PacketErrorWithBody(proto.OpThrottledErr, []byte("throttled"))
+			} else {
+				p.PacketOkReply()
+			}
+			if err = p.WriteToConn(conn); err != nil {
+				return
+			}
+		}
+	}()
+	conn, err := net.DialTCP("tcp", nil, ln.Addr().(*net.TCPAddr))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSendThrottled(t *testing.T) {
+	proto.InitBufferPool(32768)
+	mw := &MetaWrapper{}
+	ctx := context.Background()
+
+	// succeed on the same meta node after backoff
+	mc := &MetaConn{conn: newThrottledServer(t, 3), id: 1}
+	req := proto.NewPacketReqID()
+	req.Opcode = proto.OpMetaInodeGet
+	resp, err := mc.send(ctx, req, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, proto.OpThrottledErr, resp.ResultCode)
+	resp, err = mw.sendThrottled(ctx, mc, req, 0, resp, 10000)
+	assert.NoError(t, err)
+	assert.Equal(t, proto.OpOk, resp.ResultCode)
+
+	// still throttled until time limit
+	mc = &MetaConn{conn: newThrottledServer(t, 100), id: 1}
+	resp, err = mc.send(ctx, req, 0)
+	assert.NoError(t, err)
+	resp, err = mw.sendThrottled(ctx, mc, req, 0, resp, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, proto.OpThrottledErr, resp.ResultCode)
+	assert.Equal(t, statusAgain, parseStatus(resp.ResultCode))
+
+	// stopped if cancelled
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = mw.sendThrottled(cctx, mc, req, 0, resp, 10000)
+	assert.ErrorIs(t, err, context.Canceled)
+}
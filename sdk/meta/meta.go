@@ -15,6 +15,7 @@
 package meta
 
 import (
+	"context"
 	gerrors "errors"
 	"sync"
 	"syscall"
@@ -91,6 +92,7 @@ type MetaConfig struct {
 	OnAsyncTaskError AsyncTaskErrorFunc
 	EnableSummary    bool
 	MetaSendTimeout  int64
+	ReportAccessTime bool
 
 	// EnableTransaction uint8
 	// EnableTransaction bool
@@ -105,6 +107,7 @@ type MetaWrapper struct {
 	ossSecure         *OSSSecure
 	volCreateTime     int64
 	volDeleteLockTime int64
+	volTrashInterval  int64
 	owner             string
 	ownerValidation   bool
 	mc                *masterSDK.MasterClient
@@ -147,6 +150,7 @@ type MetaWrapper struct {
 	// Allocated to trigger and throttle instant partition updates
 	forceUpdate             chan struct{}
 	forceUpdateLimit        *rate.Limiter
+	forceUpdateTime         sync.Map // partition id => unix time of last trigger
 	singleflight            singleflight.Group
 	EnableSummary           bool
 	metaSendTimeout         int64
@@ -168,6 +172,10 @@ type MetaWrapper struct {
 	VerReadSeq uint64
 	LastVerSeq uint64
 	Client     wrapper.SimpleClientInfo
+
+	// access time of inodes waiting to be reported to meta nodes, nil if disabled
+	accessedInodes map[uint64]int64
+	accessLock     sync.Mutex
 }
 
 type uniqidRange struct {
@@ -243,6 +251,10 @@ func NewMetaWrapper(config *MetaConfig) (*MetaWrapper, error) {
 
 	go mw.updateQuotaInfoTick()
 	go mw.refresh()
+	if config.ReportAccessTime {
+		mw.accessedInodes = make(map[uint64]int64)
+		go mw.reportAccessTimeTick()
+	}
 	return mw, nil
 }
 
@@ -309,7 +321,7 @@ func parseStatus(result uint8) (status int) {
 		status = statusNoent
 	case proto.OpInodeFullErr:
 		status = statusFull
-	case proto.OpAgain:
+	case proto.OpAgain, proto.OpThrottledErr:
 		status = statusAgain
 	case proto.OpArgMismatchErr:
 		status = statusInval
@@ -347,6 +359,14 @@ func statusErrToErrno(status int, err error) error {
 	return statusToErrno(status)
 }
 
+// contextErrno returns EINTR for the request interrupted by ctx, or the errno of the status.
+func contextErrno(ctx context.Context, status int) error {
+	if ctx.Err() != nil {
+		return syscall.EINTR
+	}
+	return statusToErrno(status)
+}
+
 func statusToErrno(status int) error {
 	switch status {
 	case statusOK:
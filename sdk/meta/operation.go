@@ -15,6 +15,7 @@
 package meta
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"sync"
@@ -474,6 +475,98 @@ func (mw *MetaWrapper) ievict(mp *MetaPartition, inode uint64, fullPath string)
 	return statusOK, nil
 }
 
+func (mw *MetaWrapper) iunlinks(mp *MetaPartition, inodes []uint64, fullPaths []string,
+) (status int, resp *proto.BatchUnlinkInodeResponse, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("iunlinks", err, bgTime, 1)
+	}()
+
+	req := &proto.BatchUnlinkInodeRequest{
+		VolName:     mw.volname,
+		PartitionID: mp.PartitionID,
+		Inodes:      inodes,
+		FullPaths:   fullPaths,
+	}
+
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaBatchUnlinkInode
+	packet.PartitionID = mp.PartitionID
+	err = packet.MarshalData(req)
+	if err != nil {
+		log.LogErrorf("iunlinks: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("iunlinks: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+
+	// status of packet is the last failed item, items are replied anyway
+	status = parseStatus(packet.ResultCode)
+	resp = new(proto.BatchUnlinkInodeResponse)
+	if err = packet.UnmarshalData(resp); err != nil {
+		if status != statusOK {
+			err = errors.New(packet.GetResultMsg())
+		}
+		log.LogErrorf("iunlinks: packet(%v) mp(%v) err(%v) PacketData(%v)", packet, mp, err, string(packet.Data))
+		return
+	}
+	log.LogDebugf("iunlinks: packet(%v) mp(%v) req(%v) status(%v)", packet, mp, *req, status)
+	return statusOK, resp, nil
+}
+
+func (mw *MetaWrapper) ievicts(mp *MetaPartition, inodes []uint64, fullPaths []string) (status int, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("ievicts", err, bgTime, 1)
+	}()
+
+	req := &proto.BatchEvictInodeRequest{
+		VolName:     mw.volname,
+		PartitionID: mp.PartitionID,
+		Inodes:      inodes,
+		FullPaths:   fullPaths,
+	}
+
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaBatchEvictInode
+	packet.PartitionID = mp.PartitionID
+	err = packet.MarshalData(req)
+	if err != nil {
+		log.LogWarnf("ievicts: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogWarnf("ievicts: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+
+	status = parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogWarnf("ievicts: packet(%v) mp(%v) req(%v) result(%v)", packet, mp, *req, packet.GetResultMsg())
+		return
+	}
+
+	log.LogDebugf("ievicts exit: packet(%v) mp(%v) req(%v)", packet, mp, *req)
+	return statusOK, nil
+}
+
 func (mw *MetaWrapper) txDcreate(tx *Transaction, mp *MetaPartition, parentID uint64, name string, inode uint64, mode uint32, quotaIds []uint32, fullPath string) (status int, err error) {
 	bgTime := stat.BeginStat()
 	defer func() {
@@ -825,7 +918,9 @@ func (mw *MetaWrapper) txDdelete(tx *Transaction, mp *MetaPartition, parentID, i
 	return statusOK, resp.Inode, nil
 }
 
-func (mw *MetaWrapper) ddelete(mp *MetaPartition, parentID uint64, name string, inodeCreateTime int64, verSeq uint64, fullPath string) (status int, inode uint64, denVer uint64, err error) {
+func (mw *MetaWrapper) ddelete(mp *MetaPartition, parentID uint64, name string, inodeCreateTime int64, verSeq uint64, fullPath string,
+	trash bool,
+) (status int, inode uint64, denVer uint64, trashed bool, err error) {
 	bgTime := stat.BeginStat()
 	defer func() {
 		stat.EndStat("ddelete", err, bgTime, 1)
@@ -838,6 +933,7 @@ func (mw *MetaWrapper) ddelete(mp *MetaPartition, parentID uint64, name string,
 		Name:            name,
 		InodeCreateTime: inodeCreateTime,
 		Verseq:          verSeq,
+		Trash:           trash,
 	}
 	req.FullPaths = []string{fullPath}
 	log.LogDebugf("action[ddelete] %v", req)
@@ -874,8 +970,8 @@ func (mw *MetaWrapper) ddelete(mp *MetaPartition, parentID uint64, name string,
 		log.LogErrorf("ddelete: packet(%v) mp(%v) err(%v) PacketData(%v)", packet, mp, err, string(packet.Data))
 		return
 	}
-	log.LogDebugf("ddelete: packet(%v) mp(%v) req(%v) ino(%v)", packet, mp, *req, resp.Inode)
-	return statusOK, resp.Inode, packet.VerSeq, nil
+	log.LogDebugf("ddelete: packet(%v) mp(%v) req(%v) ino(%v) trashed(%v)", packet, mp, *req, resp.Inode, resp.Trashed)
+	return statusOK, resp.Inode, packet.VerSeq, resp.Trashed, nil
 }
 
 func (mw *MetaWrapper) canDeleteInode(mp *MetaPartition, info *proto.InodeInfo, ino uint64) (can bool, err error) {
@@ -949,6 +1045,11 @@ func (mw *MetaWrapper) ddeletes(mp *MetaPartition, parentID uint64, dentries []p
 }
 
 func (mw *MetaWrapper) lookup(mp *MetaPartition, parentID uint64, name string, verSeq uint64) (status int, inode uint64, mode uint32, err error) {
+	return mw.lookupContext(context.Background(), mp, parentID, name, verSeq)
+}
+
+// lookupContext is lookup which is cancelled once ctx is done.
+func (mw *MetaWrapper) lookupContext(ctx context.Context, mp *MetaPartition, parentID uint64, name string, verSeq uint64) (status int, inode uint64, mode uint32, err error) {
 	bgTime := stat.BeginStat()
 	defer func() {
 		stat.EndStat("lookup", err, bgTime, 1)
@@ -977,7 +1078,7 @@ func (mw *MetaWrapper) lookup(mp *MetaPartition, parentID uint64, name string, v
 		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
 	}()
 
-	packet, err = mw.sendToMetaPartition(mp, packet)
+	packet, err = mw.sendToMetaPartitionContext(ctx, mp, packet)
 	if err != nil {
 		log.LogErrorf("lookup: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
 		errMetric := exporter.NewCounter("fileOpenFailed")
@@ -1011,6 +1112,11 @@ func (mw *MetaWrapper) lookup(mp *MetaPartition, parentID uint64, name string, v
 }
 
 func (mw *MetaWrapper) iget(mp *MetaPartition, inode uint64, verSeq uint64) (status int, info *proto.InodeInfo, err error) {
+	return mw.igetContext(context.Background(), mp, inode, verSeq)
+}
+
+// igetContext is iget which is cancelled once ctx is done.
+func (mw *MetaWrapper) igetContext(ctx context.Context, mp *MetaPartition, inode uint64, verSeq uint64) (status int, info *proto.InodeInfo, err error) {
 	bgTime := stat.BeginStat()
 	defer func() {
 		stat.EndStat("iget", err, bgTime, 1)
@@ -1040,7 +1146,7 @@ func (mw *MetaWrapper) iget(mp *MetaPartition, inode uint64, verSeq uint64) (sta
 		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
 	}()
 
-	packet, err = mw.sendToMetaPartition(mp, packet)
+	packet, err = mw.sendToMetaPartitionContext(ctx, mp, packet)
 	if err != nil {
 		log.LogErrorf("iget: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
 		return
@@ -1173,6 +1279,11 @@ func (mw *MetaWrapper) readDir(mp *MetaPartition, parentID uint64) (status int,
 
 // read limit dentries start from
 func (mw *MetaWrapper) readDirLimit(mp *MetaPartition, parentID uint64, from string, limit uint64, verSeq uint64, verOpt uint8) (status int, children []proto.Dentry, err error) {
+	return mw.readDirLimitContext(context.Background(), mp, parentID, from, limit, verSeq, verOpt)
+}
+
+// readDirLimitContext is readDirLimit which is cancelled once ctx is done.
+func (mw *MetaWrapper) readDirLimitContext(ctx context.Context, mp *MetaPartition, parentID uint64, from string, limit uint64, verSeq uint64, verOpt uint8) (status int, children []proto.Dentry, err error) {
 	req := &proto.ReadDirLimitRequest{
 		VolName:     mw.volname,
 		PartitionID: mp.PartitionID,
@@ -1197,7 +1308,7 @@ func (mw *MetaWrapper) readDirLimit(mp *MetaPartition, parentID uint64, from str
 		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
 	}()
 
-	packet, err = mw.sendToMetaPartition(mp, packet)
+	packet, err = mw.sendToMetaPartitionContext(ctx, mp, packet)
 	if err != nil {
 		log.LogErrorf("readDirLimit: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
 		return
@@ -2436,7 +2547,7 @@ func (mw *MetaWrapper) readdironly(mp *MetaPartition, parentID uint64) (status i
 	return statusOK, resp.Children, nil
 }
 
-func (mw *MetaWrapper) updateXAttrs(mp *MetaPartition, inode uint64, filesInc int64, dirsInc int64, bytesInc int64) error {
+func (mw *MetaWrapper) updateXAttrs(mp *MetaPartition, inode uint64, key string, filesInc int64, dirsInc int64, bytesInc int64) error {
 	var err error
 
 	bgTime := stat.BeginStat()
@@ -2449,7 +2560,7 @@ func (mw *MetaWrapper) updateXAttrs(mp *MetaPartition, inode uint64, filesInc in
 		VolName:     mw.volname,
 		PartitionId: mp.PartitionID,
 		Inode:       inode,
-		Key:         SummaryKey,
+		Key:         key,
 		Value:       value,
 	}
 	packet := proto.NewPacketReqID()
@@ -2638,6 +2749,101 @@ func (mw *MetaWrapper) getInodeQuota(mp *MetaPartition, inode uint64) (quotaInfo
 	return
 }
 
+func (mw *MetaWrapper) searchXAttr(mp *MetaPartition, key, value string, prefix bool, limit int) (resp *proto.SearchXAttrResponse, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("searchXAttr", err, bgTime, 1)
+	}()
+
+	req := &proto.SearchXAttrRequest{
+		VolName:     mw.volname,
+		PartitionId: mp.PartitionID,
+		Key:         key,
+		Value:       value,
+		Prefix:      prefix,
+		Limit:       limit,
+	}
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaSearchXAttr
+	packet.PartitionID = mp.PartitionID
+	if err = packet.MarshalData(req); err != nil {
+		log.LogErrorf("searchXAttr: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("searchXAttr: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+
+	status := parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogErrorf("searchXAttr: packet(%v) mp(%v) req(%v) result(%v)", packet, mp, *req, packet.GetResultMsg())
+		return
+	}
+
+	resp = new(proto.SearchXAttrResponse)
+	if err = packet.UnmarshalData(resp); err != nil {
+		log.LogErrorf("searchXAttr: packet(%v) mp(%v) req(%v) err(%v) PacketData(%v)", packet, mp, *req, err, string(packet.Data))
+		return
+	}
+	log.LogDebugf("searchXAttr: req(%v) items(%v) truncated(%v)", *req, len(resp.Items), resp.Truncated)
+	return
+}
+
+func (mw *MetaWrapper) getChangeEvents(mp *MetaPartition, from uint64, limit int) (resp *proto.ChangeEvents, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("getChangeEvents", err, bgTime, 1)
+	}()
+
+	req := &proto.GetChangeEventsRequest{
+		VolName:     mw.volname,
+		PartitionId: mp.PartitionID,
+		From:        from,
+		Limit:       limit,
+	}
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaGetChangeEvents
+	packet.PartitionID = mp.PartitionID
+	if err = packet.MarshalData(req); err != nil {
+		log.LogErrorf("getChangeEvents: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("getChangeEvents: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+
+	status := parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogErrorf("getChangeEvents: packet(%v) mp(%v) req(%v) result(%v)", packet, mp, *req, packet.GetResultMsg())
+		return
+	}
+
+	resp = new(proto.ChangeEvents)
+	if err = packet.UnmarshalData(resp); err != nil {
+		log.LogErrorf("getChangeEvents: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+	log.LogDebugf("getChangeEvents: req(%v) events(%v) nextSeq(%v) truncated(%v)", *req, len(resp.Events), resp.NextSeq, resp.Truncated)
+	return
+}
 func (mw *MetaWrapper) applyQuota(parentIno uint64, quotaId uint32, totalInodeCount *uint64, curInodeCount *uint64, inodes *[]uint64,
 	maxInodes uint64, first bool,
 ) (err error) {
@@ -2843,3 +3049,168 @@ func (mw *MetaWrapper) checkVerFromMeta(packet *proto.Packet) {
 	log.LogDebugf("checkVerFromMeta.UpdateLatestVer.try update meta wrapper verSeq from %v to %v verlist[%v]", mw.Client.GetLatestVer(), packet.VerSeq, packet.VerList)
 	mw.Client.UpdateLatestVer(&proto.VolVersionInfoList{VerList: packet.VerList})
 }
+
+func (mw *MetaWrapper) batchRecordAccess(mp *MetaPartition, inodes []uint64, atime int64) (err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("batchRecordAccess", err, bgTime, 1)
+	}()
+
+	req := &proto.BatchRecordAccessRequest{
+		VolName:     mw.volname,
+		PartitionID: mp.PartitionID,
+		Inodes:      inodes,
+		AccessTime:  atime,
+	}
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaBatchRecordAccess
+	packet.PartitionID = mp.PartitionID
+	if err = packet.MarshalData(req); err != nil {
+		log.LogErrorf("batchRecordAccess: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("batchRecordAccess: packet(%v) mp(%v) inodes(%v) err(%v)", packet, mp, len(inodes), err)
+		return
+	}
+
+	status := parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogErrorf("batchRecordAccess: packet(%v) mp(%v) inodes(%v) result(%v)", packet, mp, len(inodes), packet.GetResultMsg())
+		return
+	}
+	log.LogDebugf("batchRecordAccess: mp(%v) inodes(%v) atime(%v)", mp.PartitionID, len(inodes), atime)
+	return
+}
+
+func (mw *MetaWrapper) listTrash(mp *MetaPartition, parentID uint64, marker string, limit int) (status int, resp *proto.ListTrashResponse, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("listTrash", err, bgTime, 1)
+	}()
+
+	req := &proto.ListTrashRequest{
+		VolName:     mw.volname,
+		PartitionID: mp.PartitionID,
+		ParentID:    parentID,
+		Marker:      marker,
+		Limit:       limit,
+	}
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaListTrash
+	packet.PartitionID = mp.PartitionID
+	if err = packet.MarshalData(req); err != nil {
+		log.LogErrorf("listTrash: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("listTrash: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+
+	status = parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogErrorf("listTrash: packet(%v) mp(%v) req(%v) result(%v)", packet, mp, *req, packet.GetResultMsg())
+		return
+	}
+
+	resp = new(proto.ListTrashResponse)
+	if err = packet.UnmarshalData(resp); err != nil {
+		log.LogErrorf("listTrash: packet(%v) mp(%v) err(%v) PacketData(%v)", packet, mp, err, string(packet.Data))
+		return
+	}
+	log.LogDebugf("listTrash: mp(%v) req(%v) items(%v)", mp.PartitionID, *req, len(resp.Items))
+	return
+}
+
+func (mw *MetaWrapper) restoreTrash(mp *MetaPartition, key, newName string) (status int, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("restoreTrash", err, bgTime, 1)
+	}()
+
+	req := &proto.RestoreTrashRequest{
+		VolName:     mw.volname,
+		PartitionID: mp.PartitionID,
+		Key:         key,
+		NewName:     newName,
+	}
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaRestoreTrash
+	packet.PartitionID = mp.PartitionID
+	if err = packet.MarshalData(req); err != nil {
+		log.LogErrorf("restoreTrash: req(%v) err(%v)", *req, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("restoreTrash: packet(%v) mp(%v) req(%v) err(%v)", packet, mp, *req, err)
+		return
+	}
+
+	status = parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogErrorf("restoreTrash: packet(%v) mp(%v) req(%v) result(%v)", packet, mp, *req, packet.GetResultMsg())
+		return
+	}
+	log.LogDebugf("restoreTrash: mp(%v) req(%v)", mp.PartitionID, *req)
+	return
+}
+
+func (mw *MetaWrapper) inodeTransition(mp *MetaPartition, req *proto.InodeTransitionRequest) (status int, err error) {
+	bgTime := stat.BeginStat()
+	defer func() {
+		stat.EndStat("inodeTransition", err, bgTime, 1)
+	}()
+
+	packet := proto.NewPacketReqID()
+	packet.Opcode = proto.OpMetaInodeTransition
+	packet.PartitionID = mp.PartitionID
+	if err = packet.MarshalData(req); err != nil {
+		log.LogErrorf("inodeTransition: ino(%v) err(%v)", req.Inode, err)
+		return
+	}
+
+	metric := exporter.NewTPCnt(packet.GetOpMsg())
+	defer func() {
+		metric.SetWithLabels(err, map[string]string{exporter.Vol: mw.volname})
+	}()
+
+	packet, err = mw.sendToMetaPartition(mp, packet)
+	if err != nil {
+		log.LogErrorf("inodeTransition: packet(%v) mp(%v) ino(%v) err(%v)", packet, mp, req.Inode, err)
+		return
+	}
+
+	status = parseStatus(packet.ResultCode)
+	if status != statusOK {
+		err = errors.New(packet.GetResultMsg())
+		log.LogErrorf("inodeTransition: packet(%v) mp(%v) ino(%v) result(%v)", packet, mp, req.Inode, packet.GetResultMsg())
+		return
+	}
+	log.LogDebugf("inodeTransition: mp(%v) ino(%v) storageClass(%v)", mp.PartitionID, req.Inode, req.StorageClass)
+	return
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSummaryInfo(t *testing.T) {
+	require.Equal(t, SummaryInfo{Files: 3, Subdirs: 2, Fbytes: 1024}, parseSummaryInfo("3,2,1024"))
+	// decrements applied before increments may leave negative counts for a while
+	require.Equal(t, SummaryInfo{Files: -1, Subdirs: 0, Fbytes: -10}, parseSummaryInfo("-1,0,-10"))
+	// directories not counted yet have no summary
+	require.Equal(t, SummaryInfo{}, parseSummaryInfo(""))
+	require.Equal(t, SummaryInfo{}, parseSummaryInfo("1,2"))
+}
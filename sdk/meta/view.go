@@ -44,6 +44,7 @@ type VolumeView struct {
 	OSSSecure      *OSSSecure
 	CreateTime     int64
 	DeleteLockTime int64
+	TrashInterval  int64
 }
 
 type OSSSecure struct {
@@ -99,6 +100,7 @@ func (mw *MetaWrapper) fetchVolumeView() (view *VolumeView, err error) {
 			OSSSecure:      &OSSSecure{},
 			CreateTime:     volView.CreateTime,
 			DeleteLockTime: volView.DeleteLockTime,
+			TrashInterval:  volView.TrashInterval,
 		}
 		if volView.OSSSecure != nil {
 			result.OSSSecure.AccessKey = volView.OSSSecure.AccessKey
@@ -208,6 +210,7 @@ func (mw *MetaWrapper) updateMetaPartitions() error {
 	mw.ossSecure = view.OSSSecure
 	mw.volCreateTime = view.CreateTime
 	mw.volDeleteLockTime = view.DeleteLockTime
+	mw.volTrashInterval = view.TrashInterval
 
 	if len(rwPartitions) == 0 {
 		log.LogInfof("updateMetaPartition: no rw partitions")
@@ -240,6 +243,20 @@ func (mw *MetaWrapper) triggerAndWaitForceUpdate() {
 	mw.partMutex.Unlock()
 }
 
+// triggerForceUpdate triggers force update of meta partitions without waiting,
+// it's triggered at most once in MinForceUpdateMetaPartitionsInterval seconds for one partition.
+func (mw *MetaWrapper) triggerForceUpdate(mpID uint64) {
+	now := time.Now().Unix()
+	if last, ok := mw.forceUpdateTime.Load(mpID); ok && now-last.(int64) < MinForceUpdateMetaPartitionsInterval {
+		return
+	}
+	mw.forceUpdateTime.Store(mpID, now)
+	select {
+	case mw.forceUpdate <- struct{}{}:
+	default:
+	}
+}
+
 func (mw *MetaWrapper) refresh() {
 	var err error
 
@@ -0,0 +1,40 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerForceUpdate(t *testing.T) {
+	mw := &MetaWrapper{forceUpdate: make(chan struct{}, 1)}
+
+	mw.triggerForceUpdate(1)
+	assert.Equal(t, 1, len(mw.forceUpdate))
+	<-mw.forceUpdate
+
+	// debounced for the same partition
+	mw.triggerForceUpdate(1)
+	assert.Equal(t, 0, len(mw.forceUpdate))
+
+	// not debounced for other partitions
+	mw.triggerForceUpdate(2)
+	assert.Equal(t, 1, len(mw.forceUpdate))
+	// not blocked if an update is pending
+	mw.triggerForceUpdate(3)
+	assert.Equal(t, 1, len(mw.forceUpdate))
+}
@@ -451,6 +451,31 @@ func (e *Extent) GetCrc(blockNo int64) uint32 {
 	return binary.BigEndian.Uint32(e.header[blockNo*util.PerBlockCrcSize : (blockNo+1)*util.PerBlockCrcSize])
 }
 
+// RepairBlock overwrites the block with data whose crc is the expected one. It's done under the lock
+// of the extent, and only if the block crc is still expected and the local data of the block still mismatches it.
+func (e *Extent) RepairBlock(blockNo int, data []byte, expected uint32) (repaired bool, err error) {
+	e.Lock()
+	defer e.Unlock()
+	offset := int64(blockNo) * util.BlockSize
+	if e.GetCrc(int64(blockNo)) != expected || offset+int64(len(data)) > e.dataSize {
+		return false, nil
+	}
+	local := make([]byte, len(data))
+	if _, err = e.file.ReadAt(local, offset); err != nil {
+		return false, err
+	}
+	if crc32.ChecksumIEEE(local) == expected {
+		return false, nil
+	}
+	if _, err = e.file.WriteAt(data, offset); err != nil {
+		return false, err
+	}
+	if err = e.file.Sync(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (e *Extent) autoComputeExtentCrc(crcFunc UpdateCrcFunc) (crc uint32, err error) {
 	var blockCnt int
 	extSize := e.Size()
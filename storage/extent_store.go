@@ -136,6 +136,7 @@ type ExtentStore struct {
 	partitionType                     int
 	ApplyId                           uint64
 	ApplyIdMutex                      sync.RWMutex
+	writeCache                        *storeWriteCache
 }
 
 func MkdirAll(name string) (err error) {
@@ -395,6 +396,16 @@ func (s *ExtentStore) Write(extentID uint64, offset, size int64, data []byte, cr
 		return status, err
 	}
 
+	if s.writeCache != nil && !IsTinyExtent(extentID) {
+		// the writes beyond the extent size are not cached, since the reads of the range
+		// fail on the extent before the cached data is overlaid
+		if writeType == RandomWriteType && !isHole && offset+size <= e.Size() && s.writeCache.write(extentID, offset, data[:size], isSync) {
+			atomic.StoreUint32(&ei.Crc, 0)
+			return status, nil
+		}
+		s.writeCache.invalidate(extentID, offset, size)
+	}
+
 	status, err = e.Write(data, offset, size, crc, writeType, isSync, s.PersistenceBlockCrc, ei, isHole)
 	if err != nil {
 		log.LogInfof("action[Write] path %v err %v", e.filePath, err)
@@ -453,7 +464,17 @@ func (s *ExtentStore) Read(extentID uint64, offset, size int64, nbuf []byte, isR
 	//if err = s.checkOffsetAndSize(extentID, offset, size); err != nil {
 	//	return
 	//}
+	if s.writeCache != nil && !IsTinyExtent(extentID) {
+		s.writeCache.RLock()
+		defer s.writeCache.RUnlock()
+	}
 	crc, err = e.Read(nbuf, offset, size, isRepairRead)
+	if err == nil && s.writeCache != nil && !IsTinyExtent(extentID) {
+		var overlaid bool
+		if overlaid, err = s.writeCache.readLocked(extentID, offset, size, nbuf); overlaid && err == nil {
+			crc = crc32.ChecksumIEEE(nbuf)
+		}
+	}
 
 	return
 }
@@ -506,11 +527,17 @@ func (s *ExtentStore) MarkDelete(extentID uint64, offset, size int64) (err error
 	}
 
 	if IsTinyExtent(extentID) || funcNeedPunchDel() {
+		if s.writeCache != nil && !IsTinyExtent(extentID) {
+			s.writeCache.invalidate(extentID, offset, size)
+		}
 		log.LogDebugf("action[MarkDelete] extentID %v offset %v size %v ei(size %v snapshotSize %v)",
 			extentID, offset, size, ei.Size, ei.SnapshotDataOff)
 		return s.punchDelete(extentID, offset, size)
 	}
 
+	if s.writeCache != nil {
+		s.writeCache.drop(extentID)
+	}
 	extentFilePath := path.Join(s.dataPath, strconv.FormatUint(extentID, 10))
 	log.LogDebugf("action[MarkDelete] extentID %v offset %v size %v ei(size %v extentFilePath %v)",
 		extentID, offset, size, ei.Size, extentFilePath)
@@ -557,6 +584,11 @@ func (s *ExtentStore) Close() {
 		return
 	}
 
+	if s.writeCache != nil {
+		if err := s.writeCache.close(); err != nil {
+			log.LogErrorf("action[Close] dp(%v) close write cache err(%v)", s.partitionID, err)
+		}
+	}
 	// Release cache
 	s.cache.Flush()
 	s.cache.Clear()
@@ -1073,6 +1105,107 @@ func (s *ExtentStore) ScanBlocks(extentID uint64) (bcs []*BlockCrc, err error) {
 	return
 }
 
+// BlockCrc returns the crc of the block of the normal extent recorded in its header,
+// 0 means the crc is not computed yet.
+func (s *ExtentStore) BlockCrc(extentID uint64, blockNo int) (crc uint32, err error) {
+	s.eiMutex.RLock()
+	ei := s.extentInfoMap[extentID]
+	s.eiMutex.RUnlock()
+	if ei == nil || ei.IsDeleted {
+		return 0, errors.Trace(ExtentHasBeenDeletedError, "[BlockCrc] extent[%d] is already been deleted", extentID)
+	}
+	e, err := s.extentWithHeader(ei)
+	if err != nil {
+		return
+	}
+	return e.GetCrc(int64(blockNo)), nil
+}
+
+// VerifyBlock reads the block of the normal extent into buf and checks it against the block crc
+// recorded in the header, blocks whose crc is not computed or changed by writes during the check
+// are taken as verified, so are extents with data in the write cache. The access time of the extent is not updated.
+func (s *ExtentStore) VerifyBlock(extentID uint64, blockNo int, buf []byte) (ok bool, err error) {
+	if IsTinyExtent(extentID) {
+		return true, nil
+	}
+	s.eiMutex.RLock()
+	ei := s.extentInfoMap[extentID]
+	s.eiMutex.RUnlock()
+	if ei == nil || ei.IsDeleted {
+		return true, nil
+	}
+	e, err := s.extentWithHeader(ei)
+	if err != nil {
+		return
+	}
+	expected := e.GetCrc(int64(blockNo))
+	offset := int64(blockNo) * util.BlockSize
+	size := e.Size() - offset
+	if size > util.BlockSize {
+		size = util.BlockSize
+	}
+	if expected == 0 || size <= 0 || (s.writeCache != nil && s.writeCache.isDirty(extentID)) {
+		return true, nil
+	}
+	if _, err = e.file.ReadAt(buf[:size], offset); err != nil {
+		return
+	}
+	if crc32.ChecksumIEEE(buf[:size]) == expected {
+		return true, nil
+	}
+	return e.GetCrc(int64(blockNo)) != expected, nil
+}
+
+// RepairBlock overwrites the corrupted block of the normal extent with data whose crc is expected,
+// it's skipped if the block has been written since the data is checked, or the extent has data in
+// the write cache.
+func (s *ExtentStore) RepairBlock(extentID uint64, blockNo int, data []byte, expected uint32) (repaired bool, err error) {
+	s.eiMutex.RLock()
+	ei := s.extentInfoMap[extentID]
+	s.eiMutex.RUnlock()
+	if ei == nil || ei.IsDeleted {
+		return false, errors.Trace(ExtentHasBeenDeletedError, "[RepairBlock] extent[%d] is already been deleted", extentID)
+	}
+	if crc32.ChecksumIEEE(data) != expected {
+		return false, newParameterError("block(%d) of extent(%d) mismatches crc", blockNo, extentID)
+	}
+	e, err := s.extentWithHeader(ei)
+	if err != nil {
+		return
+	}
+	if s.writeCache != nil && s.writeCache.isDirty(extentID) {
+		return false, nil
+	}
+	return e.RepairBlock(blockNo, data, expected)
+}
+
+// BlockFile returns the file of the normal extent and the crc of the block, so that the whole
+// block can be sent from the file without copying it to user space. ok is false if the block is
+// not whole, its crc is not computed or it has data in the write cache.
+func (s *ExtentStore) BlockFile(extentID uint64, blockNo int) (file *os.File, crc uint32, ok bool, err error) {
+	if IsTinyExtent(extentID) || (s.writeCache != nil && s.writeCache.isDirty(extentID)) {
+		return
+	}
+	s.eiMutex.RLock()
+	ei := s.extentInfoMap[extentID]
+	s.eiMutex.RUnlock()
+	if ei == nil || ei.IsDeleted {
+		return nil, 0, false, errors.Trace(ExtentHasBeenDeletedError, "[BlockFile] extent[%d] is already been deleted", extentID)
+	}
+	atomic.StoreInt64(&ei.AccessTime, time.Now().Unix())
+	e, err := s.extentWithHeader(ei)
+	if err != nil {
+		return
+	}
+	if int64(blockNo+1)*util.BlockSize > e.Size() {
+		return
+	}
+	if crc = e.GetCrc(int64(blockNo)); crc == 0 {
+		return
+	}
+	return e.file, crc, true, nil
+}
+
 type ExtentInfoArr []*ExtentInfo
 
 func (arr ExtentInfoArr) Len() int           { return len(arr) }
@@ -1136,6 +1269,11 @@ func (s *ExtentStore) autoComputeExtentCrc() {
 
 		if !IsTinyExtent(ei.FileID) && time.Now().Unix()-ei.ModifyTime > UpdateCrcInterval &&
 			!ei.IsDeleted && ei.Size > 0 && ei.Crc == 0 {
+			// the crc is computed after the cached data is flushed
+			if s.writeCache != nil && s.writeCache.isDirty(ei.FileID) {
+				s.ApplyIdMutex.RUnlock()
+				continue
+			}
 
 			e, err := s.extentWithHeader(ei)
 			if err != nil {
@@ -256,3 +256,101 @@ func TestExtentStores(t *testing.T) {
 		ExtentStoreTest(t, ty)
 	}
 }
+
+func TestExtentStoreVerifyBlock(t *testing.T) {
+	path, clean, err := getTestPathExtentStore()
+	require.NoError(t, err)
+	defer clean()
+	s, err := storage.NewExtentStore(path, 0, 1*util.GB, proto.PartitionTypeNormal, true)
+	require.NoError(t, err)
+	defer s.Close()
+	id, err := s.NextExtentID()
+	require.NoError(t, err)
+	require.NoError(t, s.Create(id))
+
+	data := []byte(strings.Repeat("a", util.BlockSize))
+	crc := crc32.ChecksumIEEE(data)
+	_, err = s.Write(id, 0, int64(len(data)), data, crc, storage.AppendWriteType, true, false)
+	require.NoError(t, err)
+	blockCrc, err := s.BlockCrc(id, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, crc, blockCrc)
+
+	buf := make([]byte, util.BlockSize)
+	ok, err := s.VerifyBlock(id, 0, buf)
+	require.NoError(t, err)
+	require.True(t, ok)
+	// blocks beyond the extent are taken as verified
+	ok, err = s.VerifyBlock(id, 1, buf)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// corrupt the data on disk
+	f, err := os.OpenFile(fmt.Sprintf("%s/%d", path, id), os.O_RDWR, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte("b"), 100)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	ok, err = s.VerifyBlock(id, 0, buf)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// data mismatching the crc is refused
+	_, err = s.RepairBlock(id, 0, []byte(strings.Repeat("c", util.BlockSize)), crc)
+	require.Error(t, err)
+	// not repaired if the block crc is changed
+	repaired, err := s.RepairBlock(id, 0, []byte(strings.Repeat("c", util.BlockSize)),
+		crc32.ChecksumIEEE([]byte(strings.Repeat("c", util.BlockSize))))
+	require.NoError(t, err)
+	require.False(t, repaired)
+
+	// repaired with the data of the block
+	repaired, err = s.RepairBlock(id, 0, data, crc)
+	require.NoError(t, err)
+	require.True(t, repaired)
+	ok, err = s.VerifyBlock(id, 0, buf)
+	require.NoError(t, err)
+	require.True(t, ok)
+	// not repaired again if the local data matches
+	repaired, err = s.RepairBlock(id, 0, data, crc)
+	require.NoError(t, err)
+	require.False(t, repaired)
+}
+
+func TestExtentStoreBlockFile(t *testing.T) {
+	path, clean, err := getTestPathExtentStore()
+	require.NoError(t, err)
+	defer clean()
+	s, err := storage.NewExtentStore(path, 0, 1*util.GB, proto.PartitionTypeNormal, true)
+	require.NoError(t, err)
+	defer s.Close()
+	id, err := s.NextExtentID()
+	require.NoError(t, err)
+	require.NoError(t, s.Create(id))
+
+	data := []byte(strings.Repeat("a", util.BlockSize))
+	crc := crc32.ChecksumIEEE(data)
+	_, err = s.Write(id, 0, int64(len(data)), data, crc, storage.AppendWriteType, true, false)
+	require.NoError(t, err)
+	_, err = s.Write(id, util.BlockSize, 100, data, crc32.ChecksumIEEE(data[:100]), storage.AppendWriteType, true, false)
+	require.NoError(t, err)
+
+	file, blockCrc, ok, err := s.BlockFile(id, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, crc, blockCrc)
+	buf := make([]byte, util.BlockSize)
+	_, err = file.ReadAt(buf, 0)
+	require.NoError(t, err)
+	require.Equal(t, data, buf)
+	// the last block is not whole
+	_, _, ok, err = s.BlockFile(id, 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+	// the crc is reset by random writes
+	_, err = s.Write(id, 10, 10, data, crc32.ChecksumIEEE(data[:10]), storage.RandomWriteType, true, false)
+	require.NoError(t, err)
+	_, _, ok, err = s.BlockFile(id, 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
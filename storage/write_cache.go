@@ -0,0 +1,664 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	WriteCacheJournalPrefix        = "journal_"
+	DefaultWriteCacheFlushInterval = 60 * time.Second
+
+	writeCacheRecordMagic      uint32 = 0x5743524a
+	writeCacheRecordHeaderSize        = 32
+	// the cache flushes the stores with the oldest dirty data once its usage reaches the
+	// high watermark, until the usage drops below the low watermark.
+	writeCacheHighWatermark = 0.8
+	writeCacheLowWatermark  = 0.5
+	writeCacheCheckInterval = time.Second
+)
+
+// WriteCacheConfig defines the write cache of a data disk.
+type WriteCacheConfig struct {
+	Path          string        // directory of the journals on the fast disk
+	Capacity      int64         // total size of the journals
+	MaxWriteSize  int64         // only the random writes not larger than it are cached
+	FlushInterval time.Duration // dirty data older than it is flushed to the extents
+	SyncJournal   bool          // sync the journal on every write, otherwise only on sync writes
+}
+
+// WriteCache is a write back cache on a fast disk (SSD/NVMe) shared by the extent stores of a
+// data disk. Small random writes are appended to the journal of their store on the fast disk
+// and flushed to the extents sorted by extent and offset later. The data is evicted from the
+// cache once it is flushed, and the journals left by a crash are replayed when the stores load.
+type WriteCache struct {
+	config WriteCacheConfig
+	used   int64
+	stores sync.Map // partition id -> *storeWriteCache
+	flushC chan struct{}
+	stopC  chan struct{}
+	once   sync.Once
+}
+
+// NewWriteCache creates the write cache and starts flushing it in the background.
+func NewWriteCache(config WriteCacheConfig) (c *WriteCache, err error) {
+	if config.Capacity <= 0 {
+		return nil, fmt.Errorf("invalid write cache capacity %v", config.Capacity)
+	}
+	if config.MaxWriteSize <= 0 {
+		config.MaxWriteSize = util.BlockSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultWriteCacheFlushInterval
+	}
+	if err = os.MkdirAll(config.Path, 0o755); err != nil {
+		return
+	}
+	c = &WriteCache{
+		config: config,
+		flushC: make(chan struct{}, 1),
+		stopC:  make(chan struct{}),
+	}
+	go c.flushScheduler()
+	return
+}
+
+func (c *WriteCache) Config() WriteCacheConfig {
+	return c.config
+}
+
+// Used returns the size of the journals on the fast disk.
+func (c *WriteCache) Used() int64 {
+	return atomic.LoadInt64(&c.used)
+}
+
+// Stop stops flushing the cache in the background, the stores flush their data on close.
+func (c *WriteCache) Stop() {
+	c.once.Do(func() {
+		close(c.stopC)
+	})
+}
+
+func (c *WriteCache) flushScheduler() {
+	ticker := time.NewTicker(writeCacheCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopC:
+			return
+		case <-c.flushC:
+			c.evict()
+		case <-ticker.C:
+			c.evict()
+			c.flushExpired()
+		}
+	}
+}
+
+func (c *WriteCache) notifyFlush() {
+	select {
+	case c.flushC <- struct{}{}:
+	default:
+	}
+}
+
+// evict flushes the stores with the oldest dirty data first once the usage reaches the high watermark.
+func (c *WriteCache) evict() {
+	if float64(c.Used()) < float64(c.config.Capacity)*writeCacheHighWatermark {
+		return
+	}
+	stores := make([]*storeWriteCache, 0)
+	c.stores.Range(func(_, val interface{}) bool {
+		if wc := val.(*storeWriteCache); wc.getDirtyTime() > 0 {
+			stores = append(stores, wc)
+		}
+		return true
+	})
+	sort.Slice(stores, func(i, j int) bool { return stores[i].getDirtyTime() < stores[j].getDirtyTime() })
+	for _, wc := range stores {
+		if float64(c.Used()) < float64(c.config.Capacity)*writeCacheLowWatermark {
+			return
+		}
+		if err := wc.flush(); err != nil {
+			log.LogErrorf("action[WriteCache.evict] flush dp(%v) err(%v)", wc.store.partitionID, err)
+		}
+	}
+}
+
+func (c *WriteCache) flushExpired() {
+	now := time.Now().Unix()
+	c.stores.Range(func(_, val interface{}) bool {
+		wc := val.(*storeWriteCache)
+		if dirtyTime := wc.getDirtyTime(); dirtyTime > 0 && now-dirtyTime >= int64(c.config.FlushInterval/time.Second) {
+			if err := wc.flush(); err != nil {
+				log.LogErrorf("action[WriteCache.flushExpired] flush dp(%v) err(%v)", wc.store.partitionID, err)
+			}
+		}
+		return true
+	})
+}
+
+// listJournals returns the sorted generations of the journals of the partition.
+func (c *WriteCache) listJournals(partitionID uint64) (gens []uint64, err error) {
+	dir, err := os.ReadDir(c.config.Path)
+	if err != nil {
+		return
+	}
+	prefix := fmt.Sprintf("%v%v_", WriteCacheJournalPrefix, partitionID)
+	gens = make([]uint64, 0)
+	for _, entry := range dir {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		gen, parseErr := strconv.ParseUint(strings.TrimPrefix(entry.Name(), prefix), 10, 64)
+		if parseErr != nil {
+			continue
+		}
+		gens = append(gens, gen)
+	}
+	sort.Slice(gens, func(i, j int) bool { return gens[i] < gens[j] })
+	return
+}
+
+// RemoveJournals removes the journals left by a partition which is deleted while the node is
+// down, since they are never replayed.
+func (c *WriteCache) RemoveJournals(partitionID uint64) (err error) {
+	if _, ok := c.stores.Load(partitionID); ok {
+		return fmt.Errorf("write cache of dp(%v) is in use", partitionID)
+	}
+	gens, err := c.listJournals(partitionID)
+	if err != nil {
+		return
+	}
+	for _, gen := range gens {
+		name := path.Join(c.config.Path, journalName(partitionID, gen))
+		if err = os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return
+		}
+		log.LogInfof("action[WriteCache.RemoveJournals] journal(%v) of deleted dp is removed", name)
+	}
+	return nil
+}
+
+type writeCacheJournal struct {
+	gen  uint64
+	file *os.File
+	size int64
+}
+
+func journalName(partitionID, gen uint64) string {
+	return fmt.Sprintf("%v%v_%v", WriteCacheJournalPrefix, partitionID, gen)
+}
+
+// append appends a record of the write to the journal and returns the position of the data.
+func (j *writeCacheJournal) append(extentID uint64, offset int64, data []byte) (pos int64, err error) {
+	buf := make([]byte, writeCacheRecordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(buf[0:4], writeCacheRecordMagic)
+	binary.BigEndian.PutUint64(buf[4:12], extentID)
+	binary.BigEndian.PutUint64(buf[12:20], uint64(offset))
+	binary.BigEndian.PutUint32(buf[20:24], uint32(len(data)))
+	binary.BigEndian.PutUint32(buf[24:28], crc32.ChecksumIEEE(data))
+	binary.BigEndian.PutUint32(buf[28:32], crc32.ChecksumIEEE(buf[:28]))
+	copy(buf[writeCacheRecordHeaderSize:], data)
+	if _, err = j.file.WriteAt(buf, j.size); err != nil {
+		return
+	}
+	pos = j.size + writeCacheRecordHeaderSize
+	j.size += int64(len(buf))
+	return
+}
+
+// replay applies the records of the journal in order, the records after a torn one are dropped.
+func (j *writeCacheJournal) replay(apply func(extentID uint64, offset int64, data []byte) error) (err error) {
+	header := make([]byte, writeCacheRecordHeaderSize)
+	var pos int64
+	for {
+		if _, err = j.file.ReadAt(header, pos); err != nil {
+			break
+		}
+		if binary.BigEndian.Uint32(header[0:4]) != writeCacheRecordMagic ||
+			binary.BigEndian.Uint32(header[28:32]) != crc32.ChecksumIEEE(header[:28]) {
+			err = fmt.Errorf("invalid record header at %v", pos)
+			break
+		}
+		extentID := binary.BigEndian.Uint64(header[4:12])
+		offset := int64(binary.BigEndian.Uint64(header[12:20]))
+		data := make([]byte, binary.BigEndian.Uint32(header[20:24]))
+		if _, err = j.file.ReadAt(data, pos+writeCacheRecordHeaderSize); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[24:28]) {
+			err = fmt.Errorf("invalid record data at %v", pos)
+			break
+		}
+		if err = apply(extentID, offset, data); err != nil {
+			return
+		}
+		pos += writeCacheRecordHeaderSize + int64(len(data))
+	}
+	if err != io.EOF {
+		log.LogWarnf("action[writeCacheJournal.replay] journal(%v) stops at %v: %v", j.file.Name(), pos, err)
+	}
+	return nil
+}
+
+// writeCacheSegment is a range of an extent whose latest data is in a journal.
+type writeCacheSegment struct {
+	offset  int64
+	size    int64
+	journal *writeCacheJournal
+	pos     int64 // position of the data in the journal
+}
+
+func (seg *writeCacheSegment) end() int64 {
+	return seg.offset + seg.size
+}
+
+// cutSegments removes the range from the sorted segments.
+func cutSegments(segs []*writeCacheSegment, offset, size int64) []*writeCacheSegment {
+	end := offset + size
+	result := make([]*writeCacheSegment, 0, len(segs)+1)
+	for _, seg := range segs {
+		if seg.end() <= offset || seg.offset >= end {
+			result = append(result, seg)
+			continue
+		}
+		if seg.offset < offset {
+			result = append(result, &writeCacheSegment{offset: seg.offset, size: offset - seg.offset, journal: seg.journal, pos: seg.pos})
+		}
+		if seg.end() > end {
+			result = append(result, &writeCacheSegment{offset: end, size: seg.end() - end, journal: seg.journal, pos: seg.pos + end - seg.offset})
+		}
+	}
+	return result
+}
+
+func insertSegment(segs []*writeCacheSegment, seg *writeCacheSegment) []*writeCacheSegment {
+	segs = cutSegments(segs, seg.offset, seg.size)
+	i := sort.Search(len(segs), func(i int) bool { return segs[i].offset >= seg.offset })
+	segs = append(segs, nil)
+	copy(segs[i+1:], segs[i:])
+	segs[i] = seg
+	return segs
+}
+
+// storeWriteCache is the part of the write cache used by an extent store. The writes are appended
+// to the current journal, a flush switches to a new journal and removes the old ones after their
+// data is written to the extents.
+type storeWriteCache struct {
+	sync.RWMutex
+	cache     *WriteCache
+	store     *ExtentStore
+	journal   *writeCacheJournal
+	journals  []*writeCacheJournal
+	extents   map[uint64][]*writeCacheSegment // extent id -> sorted segments
+	dirtyTime int64                           // unix time of the oldest dirty data
+	flushLock sync.Mutex
+	flushing  uint64     // id of the extent whose cached data is being written to it
+	flushDone *sync.Cond // signaled once the extent is flushed
+}
+
+func (wc *storeWriteCache) getDirtyTime() int64 {
+	return atomic.LoadInt64(&wc.dirtyTime)
+}
+
+func (wc *storeWriteCache) openJournal(gen uint64) (err error) {
+	file, err := os.OpenFile(path.Join(wc.cache.config.Path, journalName(wc.store.partitionID, gen)), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o666)
+	if err != nil {
+		return
+	}
+	wc.journal = &writeCacheJournal{gen: gen, file: file}
+	wc.journals = append(wc.journals, wc.journal)
+	return
+}
+
+// waitFlushLocked waits until the extent is not being flushed, so that the data written to the
+// extent directly is not overwritten by the older data in the journal. The lock must be held.
+func (wc *storeWriteCache) waitFlushLocked(extentID uint64) {
+	for wc.flushing == extentID {
+		wc.flushDone.Wait()
+	}
+}
+
+// write caches the random write, false means the data is not cached and should be written to
+// the extent.
+func (wc *storeWriteCache) write(extentID uint64, offset int64, data []byte, isSync bool) (cached bool) {
+	size := int64(len(data))
+	if size > wc.cache.config.MaxWriteSize {
+		return false
+	}
+	recordSize := int64(writeCacheRecordHeaderSize) + size
+	wc.Lock()
+	defer wc.Unlock()
+	if atomic.LoadInt64(&wc.cache.used)+recordSize > wc.cache.config.Capacity {
+		wc.cache.notifyFlush()
+		return false
+	}
+	pos, err := wc.journal.append(extentID, offset, data)
+	if err == nil && (isSync || wc.cache.config.SyncJournal) {
+		err = wc.journal.file.Sync()
+	}
+	if err != nil {
+		log.LogErrorf("action[storeWriteCache.write] dp(%v) journal(%v) err(%v)", wc.store.partitionID, wc.journal.file.Name(), err)
+		wc.extents[extentID] = cutSegments(wc.extents[extentID], offset, size)
+		return false
+	}
+	wc.extents[extentID] = insertSegment(wc.extents[extentID], &writeCacheSegment{offset: offset, size: size, journal: wc.journal, pos: pos})
+	atomic.CompareAndSwapInt64(&wc.dirtyTime, 0, time.Now().Unix())
+	if float64(atomic.AddInt64(&wc.cache.used, recordSize)) >= float64(wc.cache.config.Capacity)*writeCacheHighWatermark {
+		wc.cache.notifyFlush()
+	}
+	return true
+}
+
+// invalidate drops the cached data of the range which is overwritten in the extent.
+func (wc *storeWriteCache) invalidate(extentID uint64, offset, size int64) {
+	wc.Lock()
+	defer wc.Unlock()
+	wc.waitFlushLocked(extentID)
+	if segs, ok := wc.extents[extentID]; ok {
+		if segs = cutSegments(segs, offset, size); len(segs) == 0 {
+			delete(wc.extents, extentID)
+		} else {
+			wc.extents[extentID] = segs
+		}
+	}
+}
+
+func (wc *storeWriteCache) drop(extentID uint64) {
+	wc.Lock()
+	defer wc.Unlock()
+	wc.waitFlushLocked(extentID)
+	delete(wc.extents, extentID)
+}
+
+func (wc *storeWriteCache) isDirty(extentID uint64) bool {
+	wc.RLock()
+	defer wc.RUnlock()
+	return len(wc.extents[extentID]) > 0
+}
+
+// readLocked overlays the cached data of the range onto the data read from the extent,
+// the read lock must be held since the extent is read.
+func (wc *storeWriteCache) readLocked(extentID uint64, offset, size int64, nbuf []byte) (overlaid bool, err error) {
+	end := offset + size
+	for _, seg := range wc.extents[extentID] {
+		if seg.offset >= end {
+			break
+		}
+		if seg.end() <= offset {
+			continue
+		}
+		from, to := seg.offset, seg.end()
+		if from < offset {
+			from = offset
+		}
+		if to > end {
+			to = end
+		}
+		if _, err = seg.journal.file.ReadAt(nbuf[from-offset:to-offset], seg.pos+from-seg.offset); err != nil {
+			return
+		}
+		overlaid = true
+	}
+	return
+}
+
+// flush writes the cached data to the extents sorted by extent and offset, and removes the
+// journals once all of their data is persisted.
+func (wc *storeWriteCache) flush() (err error) {
+	wc.flushLock.Lock()
+	defer wc.flushLock.Unlock()
+
+	wc.Lock()
+	if wc.journal.size > 0 {
+		if err = wc.openJournal(wc.journal.gen + 1); err != nil {
+			wc.Unlock()
+			return
+		}
+	}
+	atomic.StoreInt64(&wc.dirtyTime, 0)
+	current := wc.journal
+	extentIDs := make([]uint64, 0, len(wc.extents))
+	for extentID := range wc.extents {
+		extentIDs = append(extentIDs, extentID)
+	}
+	wc.Unlock()
+
+	sort.Slice(extentIDs, func(i, j int) bool { return extentIDs[i] < extentIDs[j] })
+	flushed := make([]*Extent, 0, len(extentIDs))
+	for _, extentID := range extentIDs {
+		var e *Extent
+		if e, err = wc.flushExtent(extentID, current); err != nil {
+			atomic.CompareAndSwapInt64(&wc.dirtyTime, 0, time.Now().Unix())
+			return
+		}
+		if e != nil {
+			flushed = append(flushed, e)
+		}
+	}
+	for _, e := range flushed {
+		if err = e.Flush(); err != nil {
+			atomic.CompareAndSwapInt64(&wc.dirtyTime, 0, time.Now().Unix())
+			return
+		}
+	}
+
+	wc.Lock()
+	defer wc.Unlock()
+	journals := wc.journals[:0]
+	for _, j := range wc.journals {
+		if j == current {
+			journals = append(journals, j)
+			continue
+		}
+		j.file.Close()
+		if err = os.Remove(j.file.Name()); err != nil {
+			log.LogWarnf("action[storeWriteCache.flush] remove journal(%v) err(%v)", j.file.Name(), err)
+			err = nil
+		}
+		atomic.AddInt64(&wc.cache.used, -j.size)
+	}
+	wc.journals = journals
+	return
+}
+
+// flushExtent writes the segments of the extent in the old journals to the extent. The segments
+// are written from a snapshot without the lock, so the reads and the cached writes are not blocked,
+// while the direct writes to the extent wait until it is flushed.
+func (wc *storeWriteCache) flushExtent(extentID uint64, current *writeCacheJournal) (e *Extent, err error) {
+	wc.Lock()
+	segs := make([]*writeCacheSegment, 0, len(wc.extents[extentID]))
+	for _, seg := range wc.extents[extentID] {
+		if seg.journal != current {
+			segs = append(segs, seg)
+		}
+	}
+	if len(segs) == 0 {
+		wc.Unlock()
+		return
+	}
+	wc.flushing = extentID
+	wc.Unlock()
+
+	for _, seg := range segs {
+		data := make([]byte, seg.size)
+		if _, err = seg.journal.file.ReadAt(data, seg.pos); err != nil {
+			break
+		}
+		if e, err = wc.store.writeExtentBlocks(extentID, seg.offset, data); err != nil {
+			break
+		}
+	}
+
+	wc.Lock()
+	defer wc.Unlock()
+	wc.flushing = 0
+	wc.flushDone.Broadcast()
+	if err != nil {
+		return
+	}
+	// the segments cut by the cached writes during the flush are in the old journals as well
+	remains := make([]*writeCacheSegment, 0)
+	for _, seg := range wc.extents[extentID] {
+		if seg.journal == current {
+			remains = append(remains, seg)
+		}
+	}
+	if len(remains) == 0 {
+		delete(wc.extents, extentID)
+	} else {
+		wc.extents[extentID] = remains
+	}
+	return
+}
+
+// replay applies the journals left by the last run of the store in order.
+func (wc *storeWriteCache) replay() (maxGen uint64, err error) {
+	gens, err := wc.cache.listJournals(wc.store.partitionID)
+	if err != nil || len(gens) == 0 {
+		return
+	}
+
+	extents := make(map[uint64]*Extent)
+	apply := func(extentID uint64, offset int64, data []byte) (err error) {
+		e, err := wc.store.writeExtentBlocks(extentID, offset, data)
+		if err != nil {
+			return
+		}
+		if e != nil {
+			extents[extentID] = e
+		}
+		return
+	}
+	for _, gen := range gens {
+		var file *os.File
+		if file, err = os.Open(path.Join(wc.cache.config.Path, journalName(wc.store.partitionID, gen))); err != nil {
+			return
+		}
+		err = (&writeCacheJournal{gen: gen, file: file}).replay(apply)
+		file.Close()
+		if err != nil {
+			return
+		}
+		log.LogInfof("action[storeWriteCache.replay] dp(%v) journal(%v) is replayed", wc.store.partitionID, gen)
+	}
+	for _, e := range extents {
+		if err = e.Flush(); err != nil {
+			return
+		}
+	}
+	for _, gen := range gens {
+		if err = os.Remove(path.Join(wc.cache.config.Path, journalName(wc.store.partitionID, gen))); err != nil {
+			return
+		}
+	}
+	return gens[len(gens)-1], nil
+}
+
+// close flushes the cached data and removes the journal of the store.
+func (wc *storeWriteCache) close() (err error) {
+	if err = wc.flush(); err != nil {
+		return
+	}
+	wc.Lock()
+	defer wc.Unlock()
+	if len(wc.extents) > 0 {
+		return fmt.Errorf("dp(%v) has unflushed data", wc.store.partitionID)
+	}
+	for _, j := range wc.journals {
+		j.file.Close()
+		os.Remove(j.file.Name())
+		atomic.AddInt64(&wc.cache.used, -j.size)
+	}
+	wc.journals = nil
+	wc.cache.stores.Delete(wc.store.partitionID)
+	return
+}
+
+// SetWriteCache replays the journals left in the cache and caches the small random writes of
+// the store in it.
+func (s *ExtentStore) SetWriteCache(c *WriteCache) (err error) {
+	if !proto.IsNormalDp(s.partitionType) {
+		return
+	}
+	wc := &storeWriteCache{
+		cache:   c,
+		store:   s,
+		extents: make(map[uint64][]*writeCacheSegment),
+	}
+	wc.flushDone = sync.NewCond(wc)
+	maxGen, err := wc.replay()
+	if err != nil {
+		return fmt.Errorf("replay write cache of dp(%v) err(%v)", s.partitionID, err)
+	}
+	if err = wc.openJournal(maxGen + 1); err != nil {
+		return
+	}
+	c.stores.Store(s.partitionID, wc)
+	s.writeCache = wc
+	return
+}
+
+// FlushWriteCache writes the data in the write cache to the extents.
+func (s *ExtentStore) FlushWriteCache() (err error) {
+	if s.writeCache == nil {
+		return
+	}
+	return s.writeCache.flush()
+}
+
+// writeExtentBlocks writes the data to the normal extent split by blocks, so that the crc of
+// the whole blocks is kept. A deleted extent is skipped.
+func (s *ExtentStore) writeExtentBlocks(extentID uint64, offset int64, data []byte) (e *Extent, err error) {
+	s.eiMutex.RLock()
+	ei := s.extentInfoMap[extentID]
+	s.eiMutex.RUnlock()
+	if ei == nil || ei.IsDeleted {
+		return
+	}
+	if e, err = s.extentWithHeader(ei); err != nil {
+		return
+	}
+	for len(data) > 0 {
+		size := util.BlockSize - offset%util.BlockSize
+		if size > int64(len(data)) {
+			size = int64(len(data))
+		}
+		if _, err = e.Write(data[:size], offset, size, crc32.ChecksumIEEE(data[:size]), RandomWriteType, false, s.PersistenceBlockCrc, ei, false); err != nil {
+			return
+		}
+		offset += size
+		data = data[size:]
+	}
+	ei.UpdateExtentInfo(e, 0)
+	return
+}
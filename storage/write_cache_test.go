@@ -0,0 +1,173 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage_test
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/storage"
+	"github.com/cubefs/cubefs/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtentStoreWriteCache(t *testing.T) {
+	path, clean, err := getTestPathExtentStore()
+	require.NoError(t, err)
+	defer clean()
+	cacheConfig := storage.WriteCacheConfig{
+		Path:          filepath.Join(filepath.Dir(path), "cache"),
+		Capacity:      util.MB,
+		MaxWriteSize:  4 * util.KB,
+		FlushInterval: time.Hour,
+	}
+	cache, err := storage.NewWriteCache(cacheConfig)
+	require.NoError(t, err)
+	defer cache.Stop()
+
+	s, err := storage.NewExtentStore(path, 1, 1*util.GB, proto.PartitionTypeNormal, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetWriteCache(cache))
+	id, err := s.NextExtentID()
+	require.NoError(t, err)
+	require.NoError(t, s.Create(id))
+
+	expected := bytes.Repeat([]byte("a"), 2*util.BlockSize)
+	_, err = s.Write(id, 0, util.BlockSize, expected[:util.BlockSize], crc32.ChecksumIEEE(expected[:util.BlockSize]), storage.AppendWriteType, true, false)
+	require.NoError(t, err)
+	_, err = s.Write(id, util.BlockSize, util.BlockSize, expected[util.BlockSize:], crc32.ChecksumIEEE(expected[util.BlockSize:]), storage.AppendWriteType, true, false)
+	require.NoError(t, err)
+
+	randomWrite := func(offset int, data []byte, isSync bool) {
+		_, err := s.Write(id, int64(offset), int64(len(data)), data, crc32.ChecksumIEEE(data), storage.RandomWriteType, isSync, false)
+		require.NoError(t, err)
+		copy(expected[offset:], data)
+	}
+	readAndCheck := func() {
+		buf := make([]byte, len(expected))
+		crc, err := s.Read(id, 0, int64(len(buf)), buf, false)
+		require.NoError(t, err)
+		require.Equal(t, expected, buf)
+		require.EqualValues(t, crc32.ChecksumIEEE(expected), crc)
+	}
+	extentData := func() []byte {
+		data, err := os.ReadFile(fmt.Sprintf("%s/%d", path, id))
+		require.NoError(t, err)
+		return data
+	}
+
+	// small random writes are cached and overlaid on reads
+	randomWrite(100, bytes.Repeat([]byte("b"), 1000), false)
+	randomWrite(util.BlockSize-10, bytes.Repeat([]byte("c"), 20), false)
+	randomWrite(500, bytes.Repeat([]byte("d"), 100), false)
+	require.NotZero(t, cache.Used())
+	require.NotEqual(t, expected, extentData())
+	readAndCheck()
+
+	// large writes go to the extent and drop the overlapped cached data
+	randomWrite(0, bytes.Repeat([]byte("e"), 8*util.KB), true)
+	readAndCheck()
+
+	// flushed to the extent with the block crc kept
+	require.NoError(t, s.FlushWriteCache())
+	require.Equal(t, expected, extentData())
+	require.Zero(t, cache.Used())
+	readAndCheck()
+
+	// the journal is replayed after a crash
+	randomWrite(3000, bytes.Repeat([]byte("f"), 3000), true)
+	randomWrite(util.BlockSize+1, bytes.Repeat([]byte("g"), 10), true)
+	require.NotEqual(t, expected, extentData())
+	cache.Stop()
+	cache, err = storage.NewWriteCache(cacheConfig)
+	require.NoError(t, err)
+	defer cache.Stop()
+	s, err = storage.NewExtentStore(path, 1, 1*util.GB, proto.PartitionTypeNormal, false)
+	require.NoError(t, err)
+	require.NoError(t, s.SetWriteCache(cache))
+	require.Equal(t, expected, extentData())
+	readAndCheck()
+
+	// writes beyond the capacity are not cached
+	for i := 0; i < 300; i++ {
+		randomWrite(i*10, bytes.Repeat([]byte{byte(i)}, 4*util.KB), false)
+	}
+	require.LessOrEqual(t, cache.Used(), int64(util.MB))
+	readAndCheck()
+
+	// the cached data is flushed on close
+	s.Close()
+	require.Equal(t, expected, extentData())
+	require.Zero(t, cache.Used())
+	entries, err := os.ReadDir(cacheConfig.Path)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestWriteCacheBeyondExtentSize(t *testing.T) {
+	path, clean, err := getTestPathExtentStore()
+	require.NoError(t, err)
+	defer clean()
+	cacheConfig := storage.WriteCacheConfig{
+		Path:          filepath.Join(filepath.Dir(path), "cache"),
+		Capacity:      util.MB,
+		MaxWriteSize:  4 * util.KB,
+		FlushInterval: time.Hour,
+	}
+	cache, err := storage.NewWriteCache(cacheConfig)
+	require.NoError(t, err)
+	defer cache.Stop()
+
+	s, err := storage.NewExtentStore(path, 2, 1*util.GB, proto.PartitionTypeNormal, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetWriteCache(cache))
+	id, err := s.NextExtentID()
+	require.NoError(t, err)
+	require.NoError(t, s.Create(id))
+	data := bytes.Repeat([]byte("a"), util.KB)
+	_, err = s.Write(id, 0, int64(len(data)), data, crc32.ChecksumIEEE(data), storage.AppendWriteType, true, false)
+	require.NoError(t, err)
+
+	// the write past the extent end goes to the extent, so the range can be read
+	data = bytes.Repeat([]byte("b"), util.KB)
+	_, err = s.Write(id, 512, int64(len(data)), data, crc32.ChecksumIEEE(data), storage.RandomWriteType, false, false)
+	require.NoError(t, err)
+	require.Zero(t, cache.Used())
+	buf := make([]byte, len(data))
+	_, err = s.Read(id, 512, int64(len(buf)), buf, false)
+	require.NoError(t, err)
+	require.Equal(t, data, buf)
+
+	// the journals left by a partition deleted while the node is down are removed
+	data = bytes.Repeat([]byte("c"), 100)
+	_, err = s.Write(id, 0, int64(len(data)), data, crc32.ChecksumIEEE(data), storage.RandomWriteType, true, false)
+	require.NoError(t, err)
+	require.NotZero(t, cache.Used())
+	require.Error(t, cache.RemoveJournals(2))
+	cache.Stop()
+	cache, err = storage.NewWriteCache(cacheConfig)
+	require.NoError(t, err)
+	defer cache.Stop()
+	require.NoError(t, cache.RemoveJournals(2))
+	entries, err := os.ReadDir(cacheConfig.Path)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -15,6 +15,7 @@
 package util
 
 import (
+	"context"
 	"net"
 	"sync"
 	"time"
@@ -167,6 +168,30 @@ func (cp *ConnectPool) Close() {
 	})
 }
 
+// CloseOnDone closes the connection once ctx is done to interrupt the blocking io on it.
+// The returned stop must be called after the io is finished, it reports whether the
+// connection is closed by ctx, in which case the connection can not be reused.
+func CloseOnDone(ctx context.Context, c net.Conn) (stop func() bool) {
+	if ctx.Done() == nil {
+		return func() bool { return false }
+	}
+	stopC := make(chan struct{})
+	closedC := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+			closedC <- true
+		case <-stopC:
+			closedC <- false
+		}
+	}()
+	return func() bool {
+		close(stopC)
+		return <-closedC
+	}
+}
+
 type Pool struct {
 	objects        chan *Object
 	mincap         int
@@ -0,0 +1,51 @@
+// Copyright 2023 The CubeFS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cubefs/cubefs/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseOnDone(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	// the connection is kept if the io is finished before ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := util.CloseOnDone(ctx, client)
+	go server.Write([]byte("a"))
+	buf := make([]byte, 1)
+	_, err := client.Read(buf)
+	require.NoError(t, err)
+	require.False(t, stop())
+	cancel()
+
+	// the blocking read is interrupted once ctx is done
+	ctx, cancel = context.WithCancel(context.Background())
+	stop = util.CloseOnDone(ctx, client)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = client.Read(buf)
+	require.Error(t, err)
+	require.True(t, stop())
+
+	// the context which is never done is not watched
+	require.False(t, util.CloseOnDone(context.Background(), client)())
+}